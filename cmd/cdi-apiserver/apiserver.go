@@ -24,16 +24,20 @@ import (
 	"fmt"
 	"os"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"kubevirt.io/containerized-data-importer/pkg/apiserver"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
 	certwatcher "kubevirt.io/containerized-data-importer/pkg/util/cert/watcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 	"kubevirt.io/containerized-data-importer/pkg/version/verflag"
 )
 
@@ -95,6 +99,15 @@ func main() {
 
 	cdiClient := cdiclient.NewForConfigOrDie(cfg)
 
+	scheme := runtime.NewScheme()
+	if err := snapshotv1.AddToScheme(scheme); err != nil {
+		klog.Fatalf("Unable to add the snapshot types to the scheme: %v\n", errors.WithStack(err))
+	}
+	controllerRuntimeClient, err := runtimeclient.New(cfg, runtimeclient.Options{Scheme: scheme})
+	if err != nil {
+		klog.Fatalf("Unable to get controller runtime client: %v\n", errors.WithStack(err))
+	}
+
 	ch := signals.SetupSignalHandler()
 
 	authConfigWatcher := apiserver.NewAuthConfigWatcher(client, ch)
@@ -114,9 +127,11 @@ func main() {
 		client,
 		aggregatorClient,
 		cdiClient,
+		controllerRuntimeClient,
 		authorizor,
 		authConfigWatcher,
-		certWatcher)
+		certWatcher,
+		tlsconfig.NewWatcher(cdiClient, ch))
 	if err != nil {
 		klog.Fatalf("Upload api failed to initialize: %v\n", errors.WithStack(err))
 	}
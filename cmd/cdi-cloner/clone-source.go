@@ -2,15 +2,21 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"flag"
+	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 
+	"github.com/containers/image/v5/pkg/compression"
 	"github.com/golang/snappy"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
@@ -18,6 +24,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 var (
@@ -47,6 +54,52 @@ func (er *execReader) Close() error {
 	return er.stdout.Close()
 }
 
+// checksumReader computes the sha256 checksum of the clone stream, sent to the target in the trailer of the request once
+// the stream is read to the end
+type checksumReader struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+func (r *checksumReader) Sum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// trailerReader sets the trailer of the request when its body is read to the end
+type trailerReader struct {
+	io.ReadCloser
+	req    *http.Request
+	key    string
+	valueF func() string
+}
+
+func (r *trailerReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		r.req.Trailer.Set(r.key, r.valueF())
+	}
+	return n, err
+}
+
+// newUploadRequest returns the request posting the stream to the target, along with the checksum in its trailer. The
+// compression is done reading the whole clone stream by the time its output ends.
+func newUploadRequest(url string, body io.ReadCloser, checksum *checksumReader) (*http.Request, error) {
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = &trailerReader{ReadCloser: body, req: req, key: common.UploadContentSHA256Trailer, valueF: checksum.Sum}
+	req.Trailer = http.Header{}
+	req.Trailer.Set(common.UploadContentSHA256Trailer, "")
+	return req, nil
+}
+
 func init() {
 	flag.StringVar(&contentType, "content-type", "", "filesystem-clone|blockdevice-clone")
 	flag.StringVar(&mountPoint, "mount", "", "pvc mount point")
@@ -62,6 +115,18 @@ func getEnvVarOrDie(name string) string {
 	return value
 }
 
+// readTrustedCA returns the cluster-wide trusted CA bundle, trusted in addition to the CA of the upload server. The
+// bundle is only read once, the clone is a single request.
+func readTrustedCA(trustedCADir string) []byte {
+	bundle, err := ioutil.ReadFile(filepath.Join(trustedCADir, common.TrustedCABundleKey))
+	if err != nil {
+		// The copy of the bundle is optional, it may have been removed since the pod was created
+		klog.Warningf("Unable to read the trusted CA bundle: %v", err)
+		return nil
+	}
+	return append([]byte("\n"), bundle...)
+}
+
 func createHTTPClient(clientKey, clientCert, serverCert []byte) *http.Client {
 	clientKeyPair, err := tls.X509KeyPair(clientCert, clientKey)
 	if err != nil {
@@ -77,6 +142,12 @@ func createHTTPClient(clientKey, clientCert, serverCert []byte) *http.Client {
 	}
 	tlsConfig.BuildNameToCertificate()
 
+	tlsOptions, err := tlsconfig.FromEnv()
+	if err != nil {
+		klog.Fatalf("Error %s parsing TLS config", err)
+	}
+	tlsOptions.Apply(tlsConfig)
+
 	transport := &http.Transport{TLSClientConfig: tlsConfig}
 	client := &http.Client{Transport: transport}
 
@@ -103,6 +174,7 @@ func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes
 	prometheus.MustRegister(progress)
 
 	promReader := prometheusutil.NewProgressReader(readCloser, totalBytes, progress, ownerUID)
+	promReader.SetTransferMetrics(prometheusutil.NewTransferMetrics("clone"))
 	promReader.StartTimedUpdate()
 
 	return promReader
@@ -129,6 +201,38 @@ func pipeToSnappy(reader io.ReadCloser) io.ReadCloser {
 	return pr
 }
 
+func pipeToZstd(reader io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	zw, err := compression.CompressStream(pw, compression.Zstd, nil)
+	if err != nil {
+		klog.Fatalf("Error creating zstd writer %+v", err)
+	}
+
+	go func() {
+		n, err := io.Copy(zw, reader)
+		if err != nil {
+			klog.Fatalf("Error %s piping to zstd", err)
+		}
+		if err = zw.Close(); err != nil {
+			klog.Fatalf("Error closing zstd writer %+v", err)
+		}
+		if err = pw.Close(); err != nil {
+			klog.Fatalf("Error closing pipe writer %+v", err)
+		}
+		klog.Infof("Wrote %d bytes\n", n)
+	}()
+
+	return pr
+}
+
+// compressStream compresses the stream with the compression requested for the clone, snappy by default
+func compressStream(reader io.ReadCloser, cloneCompression string) io.ReadCloser {
+	if cloneCompression == common.CloneCompressionZstd {
+		return pipeToZstd(reader)
+	}
+	return pipeToSnappy(reader)
+}
+
 func validateContentType() {
 	switch contentType {
 	case "filesystem-clone", "blockdevice-clone":
@@ -197,39 +301,54 @@ func main() {
 	clientKey := []byte(getEnvVarOrDie("CLIENT_KEY"))
 	clientCert := []byte(getEnvVarOrDie("CLIENT_CERT"))
 	serverCert := []byte(getEnvVarOrDie("SERVER_CA_CERT"))
+	if trustedCADir := os.Getenv(common.ImporterTrustedCADirVar); trustedCADir != "" {
+		serverCert = append(serverCert, readTrustedCA(trustedCADir)...)
+	}
 
 	url := getEnvVarOrDie("UPLOAD_URL")
 
+	cloneCompression := os.Getenv(common.CloneCompressionVar)
+	klog.Infof("compression is %q", cloneCompression)
+
 	klog.V(1).Infoln("Starting cloner target")
 
-	reader := pipeToSnappy(createProgressReader(getInputStream(), ownerUID, uploadBytes))
+	checksum := &checksumReader{ReadCloser: getInputStream(), hash: sha256.New()}
+	reader := compressStream(createProgressReader(checksum, ownerUID, uploadBytes), cloneCompression)
 
 	startPrometheus()
 
 	client := createHTTPClient(clientKey, clientCert, serverCert)
 
-	req, _ := http.NewRequest("POST", url, reader)
+	req, _ := newUploadRequest(url, reader, checksum)
 
 	if contentType != "" {
 		req.Header.Set("x-cdi-content-type", contentType)
 		klog.Infof("Set header to %s", contentType)
 	}
+	if cloneCompression == common.CloneCompressionZstd {
+		req.Header.Set(common.UploadContentEncodingHeader, cloneCompression)
+	}
 
 	response, err := client.Do(req)
 	if err != nil {
 		klog.Fatalf("Error %s POSTing to %s", err, url)
 	}
 
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		klog.Fatalf("Unexpected status code %d", response.StatusCode)
-	}
-
 	var buf bytes.Buffer
 	_, err = io.Copy(&buf, response.Body)
 	if err != nil {
 		klog.Fatalf("Error %s copying response body", err)
 	}
 
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		// The target reports the checksum mismatches in the body, surfaced in the conditions of the DataVolume
+		message := fmt.Sprintf("Unexpected status code %d: %s", response.StatusCode, buf.String())
+		if err := util.WriteTerminationMessage(message); err != nil {
+			klog.Errorf("%+v", err)
+		}
+		klog.Fatal(message)
+	}
+
 	klog.V(1).Infof("Response body:\n%s", buf.String())
 
 	klog.V(1).Infoln("clone complete")
@@ -1,17 +1,61 @@
 package main
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
+var _ = Describe("Upload request", func() {
+	It("Should send the checksum of the stream in the trailer", func() {
+		var body, trailer string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			content, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			body, trailer = string(content), r.Trailer.Get(common.UploadContentSHA256Trailer)
+		}))
+		defer server.Close()
+
+		checksum := &checksumReader{ReadCloser: ioutil.NopCloser(strings.NewReader("disk content")), hash: sha256.New()}
+		req, err := newUploadRequest(server.URL, checksum, checksum)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := server.Client().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(body).To(Equal("disk content"))
+		Expect(trailer).To(Equal(fmt.Sprintf("%x", sha256.Sum256([]byte("disk content")))))
+	})
+})
+
+var _ = Describe("Trusted CA bundle", func() {
+	It("Should read the bundle from the trusted CA dir", func() {
+		dir, err := ioutil.TempDir("", "trusted-ca")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		err = ioutil.WriteFile(filepath.Join(dir, common.TrustedCABundleKey), []byte("bundle"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(readTrustedCA(dir))).To(Equal("\nbundle"))
+	})
+
+	It("Should ignore a missing bundle", func() {
+		Expect(readTrustedCA("/nonexistent")).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Prometheus Endpoint", func() {
 	It("Should start prometheus endpoint", func() {
 		By("Creating cert directory, we can store self signed CAs")
@@ -142,7 +142,7 @@ func start(cfg *rest.Config, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 	// TODO: Current DV controller had threadiness 3, should we do the same here, defaults to one thread.
-	if _, err := controller.NewDatavolumeController(mgr, extClient, log); err != nil {
+	if _, err := controller.NewDatavolumeController(mgr, extClient, log, importerImage, pullPolicy, verbose); err != nil {
 		klog.Errorf("Unable to setup datavolume controller: %v", err)
 		os.Exit(1)
 	}
@@ -152,6 +152,28 @@ func start(cfg *rest.Config, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 
+	// The exporter is shipped in the importer image, next to qemu-img
+	if _, err := controller.NewExportController(mgr, log, importerImage, pullPolicy, verbose); err != nil {
+		klog.Errorf("Unable to setup export controller: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewPopulatorController(mgr, extClient, log); err != nil {
+		klog.Errorf("Unable to setup populator controller: %v", err)
+		os.Exit(1)
+	}
+
+	// The poll pods of the DataImportCrons run the importer
+	if _, err := controller.NewDataImportCronController(mgr, log, importerImage, pullPolicy, verbose); err != nil {
+		klog.Errorf("Unable to setup dataimportcron controller: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewDataSourceController(mgr, log); err != nil {
+		klog.Errorf("Unable to setup datasource controller: %v", err)
+		os.Exit(1)
+	}
+
 	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, getAPIServerPublicKey()); err != nil {
 		klog.Errorf("Unable to setup clone controller: %v", err)
 		os.Exit(1)
@@ -162,6 +184,16 @@ func start(cfg *rest.Config, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 
+	if _, err := controller.NewStorageProfileController(mgr, log); err != nil {
+		klog.Errorf("Unable to setup storage profile controller: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewTrustedCAController(mgr, log, namespace); err != nil {
+		klog.Errorf("Unable to setup trusted CA controller: %v", err)
+		os.Exit(1)
+	}
+
 	klog.V(1).Infoln("created cdi controllers")
 
 	go crdInformerFactory.Start(stopCh)
@@ -0,0 +1,111 @@
+package main
+
+// exporter.go writes the disk image of a PVC to a target outside of the cluster. It runs in the
+// export pods created for DataExports, with the PVC mounted read only.
+// This process expects several environmental variables:
+//    ExporterSourcePath    The disk image file or block device to export.
+//    ExporterFormat        The format of the exported image, qcow2 or raw.
+//    ExporterCompression   Optional. The compression of the clusters of a qcow2 image, zlib, zstd or none.
+//    ExporterTarget        The type of target, s3, http, registry or cdi.
+//    ExporterEndpoint      The url the image is written to.
+//    ExporterAccessKeyID   Optional. The access key or user name of the target.
+//    ExporterSecretKey     Optional. The secret key or password of the target.
+//    ExporterCertDirVar    Optional. The directory of the certificate authorities of an http or registry target.
+//    ExporterAuthFile      Optional. The docker config json file with the credentials of a registry target.
+//    ExporterTokenFile     Optional. The file of the upload token of a cdi target.
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
+	"kubevirt.io/containerized-data-importer/pkg/exporter"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+func init() {
+	klog.InitFlags(nil)
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	certsDirectory, err := ioutil.TempDir("", "certsdir")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(certsDirectory)
+	prometheusutil.StartPrometheusEndpoint(certsDirectory)
+
+	klog.V(1).Infoln("Starting exporter")
+	source, _ := util.ParseEnvVar(common.ExporterSourcePath, false)
+	format, _ := util.ParseEnvVar(common.ExporterFormat, false)
+	compression, _ := util.ParseEnvVar(common.ExporterCompression, false)
+	ownerUID, _ := util.ParseEnvVar(common.OwnerUID, false)
+
+	target, err := newTarget(cdiv1.DataExportFormat(format))
+	if err == nil {
+		err = exporter.Export(source, cdiv1.DataExportFormat(format), cdiv1.DataExportCompression(compression), common.ScratchDataDir, target, ownerUID)
+	}
+	if err != nil {
+		klog.Errorf("%+v", err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to export disk: %+v", errors.Cause(err)))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		os.Exit(1)
+	}
+
+	klog.V(1).Infoln("Export complete")
+	if err := util.WriteTerminationMessage(common.ExportComplete); err != nil {
+		klog.Errorf("%+v", err)
+		os.Exit(1)
+	}
+}
+
+func newTarget(format cdiv1.DataExportFormat) (exporter.Target, error) {
+	targetType, _ := util.ParseEnvVar(common.ExporterTarget, false)
+	ep, _ := util.ParseEnvVar(common.ExporterEndpoint, false)
+	acc, _ := util.ParseEnvVar(common.ExporterAccessKeyID, false)
+	sec, _ := util.ParseEnvVar(common.ExporterSecretKey, false)
+
+	switch targetType {
+	case controller.ExportTargetS3:
+		region, _ := util.ParseEnvVar(common.ExporterS3Region, false)
+		var partSize int64
+		if value, _ := util.ParseEnvVar(common.ExporterS3PartSize, false); value != "" {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid part size %q", value)
+			}
+			partSize = quantity.Value()
+		}
+		return exporter.NewS3Target(ep, acc, sec, region, partSize)
+	case controller.ExportTargetHTTP:
+		certDir, _ := util.ParseEnvVar(common.ExporterCertDirVar, false)
+		return exporter.NewHTTPTarget(ep, acc, sec, certDir)
+	case controller.ExportTargetRegistry:
+		authFile, _ := util.ParseEnvVar(common.ExporterAuthFile, false)
+		certDir, _ := util.ParseEnvVar(common.ExporterCertDirVar, false)
+		diskName := "disk.qcow2"
+		if format == cdiv1.DataExportFormatRaw {
+			diskName = "disk.img"
+		}
+		return exporter.NewRegistryTarget(ep, authFile, certDir, diskName)
+	case controller.ExportTargetCDI:
+		tokenFile, _ := util.ParseEnvVar(common.ExporterTokenFile, false)
+		certDir, _ := util.ParseEnvVar(common.ExporterCertDirVar, false)
+		return exporter.NewCDITarget(ep, tokenFile, certDir)
+	}
+	return nil, errors.Errorf("unknown export target %q", targetType)
+}
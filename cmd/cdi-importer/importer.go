@@ -13,13 +13,23 @@ package main
 //    ImporterSecretKey     Optional. Secret key is the password to your account.
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -28,10 +38,12 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 func init() {
@@ -53,10 +65,17 @@ func main() {
 	ep, _ := util.ParseEnvVar(common.ImporterEndpoint, false)
 	acc, _ := util.ParseEnvVar(common.ImporterAccessKeyID, false)
 	sec, _ := util.ParseEnvVar(common.ImporterSecretKey, false)
+	token, _ := util.ParseEnvVar(common.ImporterBearerToken, false)
+	oauth2TokenURL, _ := util.ParseEnvVar(common.ImporterOAuth2TokenURL, false)
+	oauth2ClientID, _ := util.ParseEnvVar(common.ImporterOAuth2ClientID, false)
+	oauth2ClientSecret, _ := util.ParseEnvVar(common.ImporterOAuth2ClientSecret, false)
+	oauth2Scopes, _ := util.ParseEnvVar(common.ImporterOAuth2Scopes, false)
 	source, _ := util.ParseEnvVar(common.ImporterSource, false)
 	contentType, _ := util.ParseEnvVar(common.ImporterContentType, false)
 	imageSize, _ := util.ParseEnvVar(common.ImporterImageSize, false)
 	certDir, _ := util.ParseEnvVar(common.ImporterCertDirVar, false)
+	clientCertDir, _ := util.ParseEnvVar(common.ImporterClientCertDirVar, false)
+	trustedCADir, _ := util.ParseEnvVar(common.ImporterTrustedCADirVar, false)
 	filesystemOverhead, _ := strconv.ParseFloat(os.Getenv(common.FilesystemOverheadVar), 64)
 	insecureTLS, _ := strconv.ParseBool(os.Getenv(common.InsecureTLSVar))
 	diskID, _ := util.ParseEnvVar(common.ImporterDiskID, false)
@@ -67,12 +86,117 @@ func main() {
 	previousCheckpoint, _ := util.ParseEnvVar(common.ImporterPreviousCheckpoint, false)
 	finalCheckpoint, _ := util.ParseEnvVar(common.ImporterFinalCheckpoint, false)
 	preallocation, err := strconv.ParseBool(os.Getenv(common.Preallocation))
+	httpSegments, _ := strconv.Atoi(os.Getenv(common.ImporterHTTPSegments))
+	httpSegmentSize, _ := strconv.ParseInt(os.Getenv(common.ImporterHTTPSegmentSize), 10, 64)
+	sourceETag, _ := util.ParseEnvVar(common.ImporterSourceETag, false)
+	sourceLastModified, _ := util.ParseEnvVar(common.ImporterSourceLastModified, false)
+	sourceVersionID, _ := util.ParseEnvVar(common.ImporterSourceVersionID, false)
+	s3Region, _ := util.ParseEnvVar(common.ImporterS3Region, false)
+	s3SSECustomerKey, _ := util.ParseEnvVar(common.ImporterS3SSECustomerKey, false)
+	s3ObjectVersionID, _ := util.ParseEnvVar(common.ImporterS3ObjectVersionID, false)
+	gcsServiceAccountKey, _ := util.ParseEnvVar(common.ImporterGCSServiceAccountKey, false)
+	azureSASToken, _ := util.ParseEnvVar(common.ImporterAzureSASToken, false)
+	azureAccountKey, _ := util.ParseEnvVar(common.ImporterAzureAccountKey, false)
+	azureTenantID, _ := util.ParseEnvVar(common.ImporterAzureTenantID, false)
+	azureClientID, _ := util.ParseEnvVar(common.ImporterAzureClientID, false)
+	azureClientSecret, _ := util.ParseEnvVar(common.ImporterAzureClientSecret, false)
+	glanceProject, _ := util.ParseEnvVar(common.ImporterGlanceProject, false)
+	glanceDomain, _ := util.ParseEnvVar(common.ImporterGlanceDomain, false)
+	glanceRegion, _ := util.ParseEnvVar(common.ImporterGlanceRegion, false)
+	proxmoxNode, _ := util.ParseEnvVar(common.ImporterProxmoxNode, false)
+	proxmoxVMID, _ := strconv.ParseInt(os.Getenv(common.ImporterProxmoxVMID), 10, 64)
+	smbDomain, _ := util.ParseEnvVar(common.ImporterSMBDomain, false)
+	rsyncModule, _ := util.ParseEnvVar(common.ImporterRsyncModule, false)
+	rsyncHostKey, _ := util.ParseEnvVar(common.ImporterRsyncHostKey, false)
+	iscsiInitiator, _ := util.ParseEnvVar(common.ImporterISCSIInitiator, false)
+	rbdMonitors, _ := util.ParseEnvVar(common.ImporterRBDMonitors, false)
+	libvirtDomain, _ := util.ParseEnvVar(common.ImporterLibvirtDomain, false)
+	libvirtDisk, _ := util.ParseEnvVar(common.ImporterLibvirtDisk, false)
+	libvirtHostKey, _ := util.ParseEnvVar(common.ImporterLibvirtHostKey, false)
+	registriesConfig, _ := util.ParseEnvVar(common.ImporterRegistriesConfig, false)
+	registryPlatform, _ := util.ParseEnvVar(common.ImporterRegistryPlatform, false)
+	signatureDir, _ := util.ParseEnvVar(common.ImporterSignatureDirVar, false)
+	signatureIdentity, _ := util.ParseEnvVar(common.ImporterSignatureIdentity, false)
+	signatureIssuer, _ := util.ParseEnvVar(common.ImporterSignatureIssuer, false)
+	featureGates, _ := util.ParseEnvVar(common.ImporterFeatureGates, false)
+	probe, _ := strconv.ParseBool(os.Getenv(common.ImporterProbe))
+	poll, _ := strconv.ParseBool(os.Getenv(common.ImporterPoll))
+	notification, _ := util.ParseEnvVar(common.ImporterNotification, false)
+	ftpPassive, err := strconv.ParseBool(os.Getenv(common.ImporterFTPPassive))
+	if err != nil {
+		// Passive mode works through firewalls and NAT
+		ftpPassive = true
+	}
+	s3ForcePathStyle, err := strconv.ParseBool(os.Getenv(common.ImporterS3ForcePathStyle))
+	if err != nil {
+		// Path-style addressing works with most S3 compatible stores
+		s3ForcePathStyle = true
+	}
 	var preallocationApplied common.PreallocationStatus
+	var sourceInfo importer.SourceInfo
+	var phaseDurations map[string]time.Duration
 
-	//Registry import currently support kubevirt content type only
-	if contentType != string(cdiv1.DataVolumeKubeVirt) && (source == controller.SourceRegistry || source == controller.SourceImageio) {
+	// Only the sources of disk images support the archive content type
+	if contentType != string(cdiv1.DataVolumeKubeVirt) && controller.IsKubeVirtOnlySource(source) {
 		klog.Errorf("Unsupported content type %s when importing from %s", contentType, source)
-		os.Exit(1)
+		exit(1)
+	}
+
+	tlsOptions, err := tlsconfig.FromEnv()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to parse TLS config: %+v", err))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		exit(1)
+	}
+	importer.SetTLSOptions(tlsOptions)
+
+	gates := featuregates.NewStaticFeatureGates(featureGates)
+	if enabled, _ := gates.EnabledFeatureGates(); len(enabled) > 0 {
+		klog.V(1).Infof("Enabled feature gates: %s", strings.Join(enabled, ", "))
+	}
+	importer.SetFeatureGates(gates)
+
+	if trustedCADir != "" {
+		// Trust the cluster-wide CA bundle together with the certs of the DataVolume, picking up rotations as they happen
+		certDir, err = importer.MergeCertDirs(make(chan struct{}), certDir, trustedCADir)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to load trusted CA bundle: %+v", err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
+	}
+
+	if registriesConfig != "" {
+		var registries []cdiv1.RegistryConfig
+		err = json.Unmarshal([]byte(registriesConfig), &registries)
+		if err == nil {
+			err = importer.SetRegistriesConfig(registries)
+		}
+		if err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to configure the registry mirrors: %+v", err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
+	}
+
+	if signatureDir != "" {
+		if err := importer.SetSignaturePolicy(signatureDir, signatureIdentity, signatureIssuer); err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("%s, unable to load the signature policy: %+v", common.ImageSignatureNotVerified, err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
 	}
 
 	volumeMode := v1.PersistentVolumeBlock
@@ -80,6 +204,8 @@ func main() {
 		volumeMode = v1.PersistentVolumeFilesystem
 	}
 
+	go abortOnTermination(volumeMode)
+
 	dest := common.ImporterWritePath
 	if contentType == string(cdiv1.DataVolumeArchive) {
 		dest = common.ImporterVolumePath
@@ -90,10 +216,68 @@ func main() {
 	}
 
 	dataDir := common.ImporterDataDir
+	var tokenSource oauth2.TokenSource
+	if source == controller.SourceHTTP && oauth2TokenURL != "" {
+		tokenSource, token, err = newOAuth2TokenSource(oauth2TokenURL, oauth2ClientID, oauth2ClientSecret, oauth2Scopes, certDir)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to retrieve OAuth2 token: %+v", err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
+	}
+
+	if source == controller.SourceGCS {
+		// GCS objects are read with the http data source, using the XML API and the tokens of the service account
+		ep, err = importer.GCSEndpoint(ep)
+		if err == nil {
+			tokenSource, token, err = newGCSTokenSource(gcsServiceAccountKey, certDir)
+		}
+		if err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to retrieve GCS token: %+v", err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
+	}
+
+	if source == controller.SourceAzureBlob {
+		// Azure blobs are read with the http data source, using a read only SAS
+		ep, err = importer.AzureBlobEndpoint(ep, importer.AzureBlobCredentials{SASToken: azureSASToken, AccountKey: azureAccountKey}, certDir)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to authorize access to azure blob: %+v", err))
+			if err != nil {
+				klog.Errorf("%+v", err)
+			}
+			exit(1)
+		}
+	}
+
+	s3Options := importer.S3Options{
+		Region:         s3Region,
+		ForcePathStyle: s3ForcePathStyle,
+		SSECustomerKey: s3SSECustomerKey,
+		Segments:       httpSegments,
+		SegmentSize:    httpSegmentSize,
+		VersionID:      s3ObjectVersionID,
+	}
+
+	if notification != "" {
+		notifyWebhook(ep, notification, certDir)
+	}
+	if poll {
+		pollSource(source, ep, acc, sec, token, certDir, clientCertDir, insecureTLS, s3Options, importer.HTTPSourceValidators{ETag: sourceETag, LastModified: sourceLastModified}, importer.S3SourceValidators{ETag: sourceETag, VersionID: sourceVersionID})
+	}
+
 	availableDestSpace, err := util.GetAvailableSpaceByVolumeMode(volumeMode)
-	if err != nil {
+	if err != nil && !probe {
 		klog.Errorf("%+v", err)
-		os.Exit(1)
+		exit(1)
 	}
 	if source == controller.SourceNone && contentType == string(cdiv1.DataVolumeKubeVirt) {
 		requestImageSizeQuantity := resource.MustParse(imageSize)
@@ -126,7 +310,7 @@ func main() {
 			if err != nil {
 				klog.Errorf("%+v", err)
 			}
-			os.Exit(1)
+			exit(1)
 		}
 	} else if source == controller.SourceNone && contentType == string(cdiv1.DataVolumeArchive) {
 		klog.Errorf("%+v", errors.New("Cannot create empty disk with content type archive"))
@@ -134,52 +318,231 @@ func main() {
 		if err != nil {
 			klog.Errorf("%+v", err)
 		}
-		os.Exit(1)
+		exit(1)
 	} else {
 		klog.V(1).Infoln("begin import process")
 		var dp importer.DataSourceInterface
 		switch source {
 		case controller.SourceHTTP:
-			dp, err = importer.NewHTTPDataSource(ep, acc, sec, certDir, cdiv1.DataVolumeContentType(contentType))
+			if importer.IsFTPEndpoint(ep) {
+				dp, err = importer.NewFTPDataSource(ep, acc, sec, certDir, ftpPassive)
+				if err != nil {
+					klog.Errorf("%+v", err)
+					err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to ftp data source: %+v", err))
+					if err != nil {
+						klog.Errorf("%+v", err)
+					}
+					exit(1)
+				}
+				break
+			}
+			hs, err := importer.NewHTTPDataSource(ep, acc, sec, token, certDir, clientCertDir, cdiv1.DataVolumeContentType(contentType))
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to http data source: %+v", err))
 				if err != nil {
 					klog.Errorf("%+v", err)
 				}
-				os.Exit(1)
+				exit(1)
+			}
+			hs.SetSegmentedDownload(httpSegments, httpSegmentSize)
+			if tokenSource != nil {
+				hs.SetTokenSource(tokenSource)
+			}
+			dp = hs
+		case controller.SourceGCS:
+			hs, err := importer.NewHTTPDataSource(ep, "", "", token, certDir, "", cdiv1.DataVolumeContentType(contentType))
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to gcs data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+			hs.SetTokenSource(tokenSource)
+			dp = hs
+		case controller.SourceGCEImage:
+			dp, err = importer.NewGCEImageDataSource(ep, gcsServiceAccountKey, certDir)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to gce image data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceAzureBlob:
+			hs, err := importer.NewHTTPDataSource(ep, "", "", "", certDir, "", cdiv1.DataVolumeContentType(contentType))
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to azure blob data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+			hs.SetSegmentedDownload(httpSegments, httpSegmentSize)
+			dp = hs
+		case controller.SourceAWSSnapshot:
+			dp, err = importer.NewAWSSnapshotDataSource(ep, s3Region, acc, sec, currentCheckpoint, previousCheckpoint, certDir)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to aws snapshot data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceAzureDisk:
+			dp, err = importer.NewAzureDiskDataSource(ep, importer.AzureADCredentials{TenantID: azureTenantID, ClientID: azureClientID, ClientSecret: azureClientSecret}, certDir)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to azure disk data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceGlance:
+			dp, err = importer.NewGlanceDataSource(ep, importer.GlanceCredentials{Username: acc, Password: sec, Project: glanceProject, Domain: glanceDomain}, glanceRegion, diskID, certDir)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to glance data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceProxmox:
+			dp, err = importer.NewProxmoxDataSource(ep, acc, sec, proxmoxNode, proxmoxVMID, diskID, certDir)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to proxmox data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceNFS:
+			// The directory of the file is mounted
+			var nfsURL *url.URL
+			if nfsURL, err = url.Parse(ep); err == nil {
+				dp, err = importer.NewNFSDataSource(filepath.Join(common.ImporterNFSDir, path.Base(nfsURL.Path)))
+			}
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to open nfs data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceFile:
+			// The host path or PVC containing the file is mounted
+			var fileURL *url.URL
+			if fileURL, err = url.Parse(ep); err == nil {
+				dp, err = importer.NewNFSDataSource(filepath.Join(common.ImporterFileDir, fileURL.Path))
+			}
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to open file data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceSMB:
+			dp, err = importer.NewSMBDataSource(ep, acc, sec, smbDomain)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to smb data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceRsync:
+			dp, err = importer.NewRsyncDataSource(ep, acc, sec, rsyncModule, rsyncHostKey)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to rsync data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceISCSI:
+			dp, err = importer.NewISCSIDataSource(ep, acc, sec, iscsiInitiator)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to iscsi data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceRBD:
+			dp, err = importer.NewRBDDataSource(ep, acc, sec, rbdMonitors)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to rbd data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceLibvirt:
+			dp, err = importer.NewLibvirtDataSource(ep, acc, sec, libvirtDomain, libvirtDisk, libvirtHostKey)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to libvirt data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		case controller.SourceHyperV:
+			dp, err = importer.NewHyperVDataSource(ep, acc, sec, smbDomain)
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to hyperv data source: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
 			}
 		case controller.SourceImageio:
-			dp, err = importer.NewImageioDataSource(ep, acc, sec, certDir, diskID)
+			dp, err = importer.NewImageioDataSource(ep, acc, sec, certDir, diskID, currentCheckpoint, previousCheckpoint)
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to imageio data source: %+v", err))
 				if err != nil {
 					klog.Errorf("%+v", err)
 				}
-				os.Exit(1)
+				exit(1)
 			}
 		case controller.SourceRegistry:
-			dp = importer.NewRegistryDataSource(ep, acc, sec, certDir, insecureTLS)
+			dp = importer.NewRegistryDataSource(ep, acc, sec, certDir, registryPlatform, insecureTLS)
 		case controller.SourceS3:
-			dp, err = importer.NewS3DataSource(ep, acc, sec)
+			dp, err = importer.NewS3DataSource(ep, acc, sec, s3Options)
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to s3 data source: %+v", err))
 				if err != nil {
 					klog.Errorf("%+v", err)
 				}
-				os.Exit(1)
+				exit(1)
 			}
 		case controller.SourceVDDK:
-			dp, err = importer.NewVDDKDataSource(ep, acc, sec, thumbprint, uuid, backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, volumeMode)
+			dp, err = importer.NewVDDKDataSource(ep, acc, sec, thumbprint, uuid, backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, volumeMode, httpSegments)
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to vddk data source: %+v", err))
 				if err != nil {
 					klog.Errorf("%+v", err)
 				}
-				os.Exit(1)
+				exit(1)
 			}
 		default:
 			klog.Errorf("Unknown source type %s\n", source)
@@ -187,35 +550,230 @@ func main() {
 			if err != nil {
 				klog.Errorf("%+v", err)
 			}
-			os.Exit(1)
+			exit(1)
 		}
 		defer dp.Close()
 		processor := importer.NewDataProcessor(dp, dest, dataDir, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
-		err = processor.ProcessData()
-		if err != nil {
+		if probe {
+			// The probe pod has no volume, it only reports the information of the source
+			sourceInfo, err = processor.ProbeData()
+			if err != nil {
+				klog.Errorf("%+v", err)
+				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to probe data: %+v", err))
+				if err != nil {
+					klog.Errorf("%+v", err)
+				}
+				exit(1)
+			}
+		} else if err = processor.ProcessData(); err != nil {
 			klog.Errorf("%+v", err)
 			if err == importer.ErrRequiresScratchSpace {
-				os.Exit(common.ScratchSpaceNeededExitCode)
+				exit(common.ScratchSpaceNeededExitCode)
 			}
 			err = util.WriteTerminationMessage(fmt.Sprintf("Unable to process data: %+v", err))
 			if err != nil {
 				klog.Errorf("%+v", err)
 			}
-			os.Exit(1)
+			exit(1)
+		}
+		if !probe {
+			preallocationApplied = processor.PreallocationApplied()
+			sourceInfo = processor.SourceInfo()
+			phaseDurations = processor.PhaseDurations()
 		}
-		preallocationApplied = processor.PreallocationApplied()
+	}
+	if !atomic.CompareAndSwapInt32(&importState, importRunning, importDone) {
+		// Aborted as the import completed
+		select {}
 	}
 	message := "Import Complete"
+	if probe {
+		message = "Probe Complete"
+	}
 	switch preallocationApplied {
 	case common.PreallocationApplied:
 		message += ", " + controller.PreallocationApplied
 	case common.PreallocationSkipped:
 		message += ", " + controller.PreallocationSkipped
 	}
+	if sourceInfo.Format != "" {
+		message += "\n" + controller.SourceFormatMessagePrefix + sourceInfo.Format
+	}
+	if sourceInfo.Compression != "" {
+		message += "\n" + controller.SourceCompressionMessagePrefix + sourceInfo.Compression
+	}
+	if sourceInfo.VirtualSize > 0 {
+		message += "\n" + controller.SourceVirtualSizeMessagePrefix + strconv.FormatInt(sourceInfo.VirtualSize, 10)
+	}
+	if sourceInfo.DownloadedSize > 0 {
+		message += "\n" + controller.SourceDownloadedSizeMessagePrefix + strconv.FormatInt(sourceInfo.DownloadedSize, 10)
+	}
+	if len(phaseDurations) > 0 {
+		message += "\n" + controller.PhaseDurationsMessagePrefix + importer.FormatPhaseDurations(phaseDurations)
+	}
 	err = util.WriteTerminationMessage(message)
 	if err != nil {
 		klog.Errorf("%+v", err)
-		os.Exit(1)
+		exit(1)
+	}
+	klog.V(1).Infoln(message)
+}
+
+const (
+	importRunning int32 = iota
+	importAborting
+	importDone
+)
+
+// importState tells whether the import is cancelled or done, a done import is not aborted anymore and the failures of
+// an aborted transfer are not reported
+var importState = importRunning
+
+// abortOnTermination cancels the import when the importer pod is deleted. The transfer processes like nbdkit and
+// qemu-img are stopped, and the partial data written to a block volume is wiped so it is not mistaken for an image.
+func abortOnTermination(volumeMode v1.PersistentVolumeMode) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	<-signals
+	if !atomic.CompareAndSwapInt32(&importState, importRunning, importAborting) {
+		return
+	}
+	klog.Infoln("Import cancelled, stopping the transfer")
+	signal.Ignore(syscall.SIGTERM, syscall.SIGINT)
+	// The transfer processes run in the process group of the importer
+	if err := syscall.Kill(0, syscall.SIGTERM); err != nil {
+		klog.Errorf("Unable to stop the transfer processes: %v", err)
+	}
+	message := "Import cancelled"
+	if volumeMode == v1.PersistentVolumeBlock {
+		if err := util.WipeBlockDevice(common.WriteBlockPath); err != nil {
+			klog.Errorf("%+v", err)
+			message += ", unable to wipe the partial data"
+		} else {
+			message += ", partial data wiped"
+		}
+	}
+	if err := util.WriteTerminationMessage(message); err != nil {
+		klog.Errorf("%+v", err)
+	}
+	klog.Flush()
+	os.Exit(1)
+}
+
+// exit exits the importer, unless the import is being aborted which exits once the abort is done
+func exit(code int) {
+	if !atomic.CompareAndSwapInt32(&importState, importRunning, importDone) && atomic.LoadInt32(&importState) == importAborting {
+		select {}
+	}
+	os.Exit(code)
+}
+
+func newOAuth2TokenSource(tokenURL, clientID, clientSecret, scopes, certDir string) (oauth2.TokenSource, string, error) {
+	tokenSource, err := importer.NewClientCredentialsTokenSource(tokenURL, clientID, clientSecret, strings.Fields(scopes), certDir)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return nil, "", err
+	}
+	return tokenSource, token.AccessToken, nil
+}
+
+func newGCSTokenSource(key, certDir string) (oauth2.TokenSource, string, error) {
+	tokenSource, err := importer.NewGCSTokenSource(key, certDir)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return nil, "", err
+	}
+	return tokenSource, token.AccessToken, nil
+}
+
+// notifyWebhook posts the notification of a new golden image to the webhook of a DataImportCron
+func notifyWebhook(ep, notification, certDir string) {
+	if err := importer.PostNotification(ep, notification, certDir); err != nil {
+		klog.Errorf("%+v", err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to notify the webhook: %+v", err))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		exit(1)
+	}
+	message := "Notification Complete"
+	if err := util.WriteTerminationMessage(message); err != nil {
+		klog.Errorf("%+v", err)
+		exit(1)
+	}
+	klog.V(1).Infoln(message)
+	exit(0)
+}
+
+// pollSource reports the version of the source of a DataImportCron: the digest of a registry image, or the validators
+// of an http or s3 object, read with a conditional request using the validators of the latest import
+func pollSource(source, ep, acc, sec, token, certDir, clientCertDir string, insecureTLS bool, s3Options importer.S3Options, httpValidators importer.HTTPSourceValidators, s3Validators importer.S3SourceValidators) {
+	message := "Poll Complete"
+	var err error
+	switch {
+	case source == controller.SourceRegistry:
+		var digest string
+		digest, err = importer.GetRegistryImageDigest(ep, acc, sec, certDir, insecureTLS)
+		if err == nil {
+			message += "\n" + controller.SourceDigestMessagePrefix + digest
+		}
+	case source == controller.SourceHTTP && !importer.IsFTPEndpoint(ep):
+		var modified bool
+		modified, httpValidators, err = importer.CheckHTTPSourceModified(ep, acc, sec, token, certDir, clientCertDir, httpValidators)
+		if err == nil && httpValidators.ETag == "" && httpValidators.LastModified == "" {
+			err = errors.New("the server reports neither an ETag nor a Last-Modified time, the changes of the source cannot be detected")
+		}
+		if err == nil {
+			klog.V(1).Infof("Source modified since the latest import: %t", modified)
+			// The last modified time contains commas, so the validators are reported on separate lines.
+			if httpValidators.ETag != "" {
+				message += "\n" + controller.SourceETagMessagePrefix + httpValidators.ETag
+			}
+			if httpValidators.LastModified != "" {
+				message += "\n" + controller.SourceLastModifiedMessagePrefix + httpValidators.LastModified
+			}
+		}
+	case source == controller.SourceS3:
+		var modified bool
+		modified, s3Validators, err = importer.CheckS3SourceModified(ep, acc, sec, s3Options, s3Validators)
+		if err == nil && s3Validators.ETag == "" && s3Validators.VersionID == "" {
+			err = errors.New("the object has neither an ETag nor a version, the changes of the source cannot be detected")
+		}
+		if err == nil {
+			klog.V(1).Infof("Source modified since the latest import: %t", modified)
+			if s3Validators.ETag != "" {
+				message += "\n" + controller.SourceETagMessagePrefix + s3Validators.ETag
+			}
+			if s3Validators.VersionID != "" {
+				message += "\n" + controller.SourceVersionIDMessagePrefix + s3Validators.VersionID
+			}
+		}
+	default:
+		klog.Errorf("Polling %s sources is not supported", source)
+		err := util.WriteTerminationMessage(fmt.Sprintf("Polling %s sources is not supported", source))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		exit(1)
+	}
+	if err != nil {
+		klog.Errorf("%+v", err)
+		err = util.WriteTerminationMessage(fmt.Sprintf("Unable to poll the source: %+v", err))
+		if err != nil {
+			klog.Errorf("%+v", err)
+		}
+		exit(1)
+	}
+	if err := util.WriteTerminationMessage(message); err != nil {
+		klog.Errorf("%+v", err)
+		exit(1)
 	}
 	klog.V(1).Infoln(message)
+	exit(0)
 }
@@ -11,10 +11,12 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
 	"kubevirt.io/containerized-data-importer/pkg/uploadproxy"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	certfetcher "kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	certwatcher "kubevirt.io/containerized-data-importer/pkg/util/cert/watcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -69,6 +71,8 @@ func main() {
 	if err != nil {
 		klog.Fatalf("Unable to get kube client: %v\n", errors.WithStack(err))
 	}
+	cdiClient := cdiclient.NewForConfigOrDie(cfg)
+	ch := signals.SetupSignalHandler()
 	apiServerPublicKey, err := getAPIServerPublicKey()
 	if err != nil {
 		klog.Fatalf("Unable to get apiserver public key %v\n", errors.WithStack(err))
@@ -90,12 +94,14 @@ func main() {
 		certWatcher,
 		clientCertFetcher,
 		serverCAFetcher,
-		client)
+		client,
+		tlsconfig.NewWatcher(cdiClient, ch),
+		uploadproxy.NewRateLimitWatcher(cdiClient, ch))
 	if err != nil {
 		klog.Fatalf("UploadProxy failed to initialize: %v\n", errors.WithStack(err))
 	}
 
-	go certWatcher.Start(signals.SetupSignalHandler())
+	go certWatcher.Start(ch)
 
 	err = uploadProxy.Start()
 	if err != nil {
@@ -30,6 +30,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/controller"
 	"kubevirt.io/containerized-data-importer/pkg/uploadserver"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -53,6 +54,16 @@ func main() {
 
 	filesystemOverhead, _ := strconv.ParseFloat(os.Getenv(common.FilesystemOverheadVar), 64)
 	preallocation, _ := strconv.ParseBool(os.Getenv(common.Preallocation))
+	tlsOptions, err := tlsconfig.FromEnv()
+	if err != nil {
+		klog.Errorf("Invalid TLS config: %v", err)
+		os.Exit(1)
+	}
+
+	if source := os.Getenv(common.DownloadSource); source != "" {
+		runDownloadServer(listenAddress, listenPort, source, tlsOptions)
+		return
+	}
 
 	server := uploadserver.NewUploadServer(
 		listenAddress,
@@ -65,13 +76,14 @@ func main() {
 		os.Getenv(common.UploadImageSize),
 		filesystemOverhead,
 		preallocation,
+		tlsOptions,
 	)
 
 	klog.Infof("Upload destination: %s", destination)
 
 	klog.Infof("Running server on %s:%d", listenAddress, listenPort)
 
-	err := server.Run()
+	err = server.Run()
 	if err != nil {
 		klog.Errorf("UploadServer failed: %s", err)
 		os.Exit(1)
@@ -104,6 +116,29 @@ func main() {
 	klog.Info("UploadServer successfully exited")
 }
 
+// runDownloadServer serves the disk image of the source until the pod is deleted
+func runDownloadServer(listenAddress string, listenPort int, source string, tlsOptions *tlsconfig.Options) {
+	server := uploadserver.NewDownloadServer(
+		listenAddress,
+		listenPort,
+		source,
+		os.Getenv("TLS_KEY"),
+		os.Getenv("TLS_CERT"),
+		os.Getenv("CLIENT_CERT"),
+		os.Getenv("CLIENT_NAME"),
+		tlsOptions,
+	)
+
+	klog.Infof("Download source: %s", source)
+
+	klog.Infof("Running server on %s:%d", listenAddress, listenPort)
+
+	if err := server.Run(); err != nil {
+		klog.Errorf("DownloadServer failed: %s", err)
+		os.Exit(1)
+	}
+}
+
 func getListenAddressAndPort() (string, int) {
 	addr, port := defaultListenAddress, defaultListenPort
 
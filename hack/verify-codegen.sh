@@ -54,4 +54,12 @@ curl https://github.com/mikefarah/yq/releases/download/3.3.2/yq_linux_amd64 -L -
 chmod +x ./bin/yq
 ./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_cdiconfigs.yaml _out/manifests/code_schema/cdiconfigs.cdi.kubevirt.io spec || (echo "CDIConfg crd schema does not match" && exit 1)
 ./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_cdis.yaml _out/manifests/code_schema/cdis.cdi.kubevirt.io spec || (echo "CDI crd schema does not match" && exit 1)
-./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_datavolumes.yaml _out/manifests/code_schema/datavolumes.cdi.kubevirt.io spec || (echo "Datavolume crd schema does not match" && exit 1)
\ No newline at end of file
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_datavolumes.yaml _out/manifests/code_schema/datavolumes.cdi.kubevirt.io spec || (echo "Datavolume crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_dataexports.yaml _out/manifests/code_schema/dataexports.cdi.kubevirt.io spec || (echo "DataExport crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_clonegrants.yaml _out/manifests/code_schema/clonegrants.cdi.kubevirt.io spec || (echo "CloneGrant crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_volumeimportsources.yaml _out/manifests/code_schema/volumeimportsources.cdi.kubevirt.io spec || (echo "VolumeImportSource crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_volumeuploadsources.yaml _out/manifests/code_schema/volumeuploadsources.cdi.kubevirt.io spec || (echo "VolumeUploadSource crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_volumeclonesources.yaml _out/manifests/code_schema/volumeclonesources.cdi.kubevirt.io spec || (echo "VolumeCloneSource crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_storageprofiles.yaml _out/manifests/code_schema/storageprofiles.cdi.kubevirt.io spec || (echo "StorageProfile crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_dataimportcrons.yaml _out/manifests/code_schema/dataimportcrons.cdi.kubevirt.io spec || (echo "DataImportCron crd schema does not match" && exit 1)
+./bin/yq compare _out/manifests/schema/cdi.kubevirt.io_datasources.yaml _out/manifests/code_schema/datasources.cdi.kubevirt.io spec || (echo "DataSource crd schema does not match" && exit 1)
\ No newline at end of file
@@ -40,6 +40,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CDIConfigList{},
 		&CDI{},
 		&CDIList{},
+		&DataExport{},
+		&DataExportList{},
+		&CloneGrant{},
+		&CloneGrantList{},
+		&VolumeImportSource{},
+		&VolumeImportSourceList{},
+		&VolumeUploadSource{},
+		&VolumeUploadSourceList{},
+		&VolumeCloneSource{},
+		&VolumeCloneSourceList{},
+		&DataImportCron{},
+		&DataImportCronList{},
+		&DataSource{},
+		&DataSourceList{},
+		&StorageProfile{},
+		&StorageProfileList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
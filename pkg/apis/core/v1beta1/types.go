@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 )
@@ -43,7 +44,11 @@ type DataVolume struct {
 // DataVolumeSpec defines the DataVolume type specification
 type DataVolumeSpec struct {
 	//Source is the src of the data for the requested DataVolume
-	Source DataVolumeSource `json:"source"`
+	// +optional
+	Source DataVolumeSource `json:"source,omitempty"`
+	// SourceRef is a reference to a DataSource, resolved into the source of the DataVolume when it is created
+	// +optional
+	SourceRef *DataVolumeSourceRef `json:"sourceRef,omitempty"`
 	//PVC is the PVC specification
 	PVC *corev1.PersistentVolumeClaimSpec `json:"pvc"`
 	//DataVolumeContentType options: "kubevirt", "archive"
@@ -55,8 +60,38 @@ type DataVolumeSpec struct {
 	FinalCheckpoint bool `json:"finalCheckpoint,omitempty"`
 	// Preallocation controls whether storage for DataVolumes should be allocated in advance.
 	Preallocation *bool `json:"preallocation,omitempty"`
+	// NodePlacement restricts on which nodes the pods populating the DataVolume are scheduled, in addition to the workload placement of the CDI resource
+	NodePlacement *sdkapi.NodePlacement `json:"nodePlacement,omitempty"`
+	// PriorityClassName of the pods populating the DataVolume, overrides the default pod priority class of the CDIConfig
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// PodResourceRequirements of the pods populating the DataVolume, overrides the resources of the podResourceRequirements of the CDIConfig
+	PodResourceRequirements *corev1.ResourceRequirements `json:"podResourceRequirements,omitempty"`
+	// RetryPolicy of the failed imports of the DataVolume, overrides the importRetryPolicy of the CDIConfig
+	RetryPolicy *ImportRetryPolicy `json:"retryPolicy,omitempty"`
+	// ImportTimeout is the maximum duration of the import from the start of its first importer pod, after which the import is cancelled and the DataVolume fails
+	ImportTimeout *metav1.Duration `json:"importTimeout,omitempty"`
+	// FilesystemOverhead of the PVC of the DataVolume when using a Filesystem volume, a value between 0 and 1 overriding the filesystemOverhead of the CDIConfig
+	FilesystemOverhead *Percent `json:"filesystemOverhead,omitempty"`
+	// ScratchSpace of the pods populating the DataVolume, overrides the scratchSpaceStorageClass of the CDIConfig
+	ScratchSpace *DataVolumeScratchSpace `json:"scratchSpace,omitempty"`
+}
+
+// DataVolumeSourceRef is a reference to the source of a DataVolume
+type DataVolumeSourceRef struct {
+	// Kind of the source, only DataSource is supported
+	Kind string `json:"kind"`
+	// Namespace of the source, the namespace of the DataVolume when unset
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+	// Name of the source
+	Name string `json:"name"`
 }
 
+const (
+	// DataVolumeDataSource is the kind of the DataSource references of the DataVolumes
+	DataVolumeDataSource = "DataSource"
+)
+
 // DataVolumeCheckpoint defines a stage in a warm migration.
 type DataVolumeCheckpoint struct {
 	// Previous is the identifier of the snapshot from the previous checkpoint.
@@ -75,16 +110,33 @@ const (
 	DataVolumeArchive DataVolumeContentType = "archive"
 )
 
-// DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry or an existing PVC
+// DataVolumeSource represents the source for our Data Volume, this can be HTTP, NFS, SMB, Rsync, iSCSI, Ceph RBD, a file on a host path or PVC, libvirt, Imageio, Glance, Proxmox, Hyper-V, S3, AWS snapshot, GCS, GCE image, Azure Blob, Azure disk, Registry, a VolumeSnapshot or an existing PVC, cloned or copied over the network
 type DataVolumeSource struct {
-	HTTP     *DataVolumeSourceHTTP     `json:"http,omitempty"`
-	S3       *DataVolumeSourceS3       `json:"s3,omitempty"`
-	Registry *DataVolumeSourceRegistry `json:"registry,omitempty"`
-	PVC      *DataVolumeSourcePVC      `json:"pvc,omitempty"`
-	Upload   *DataVolumeSourceUpload   `json:"upload,omitempty"`
-	Blank    *DataVolumeBlankImage     `json:"blank,omitempty"`
-	Imageio  *DataVolumeSourceImageIO  `json:"imageio,omitempty"`
-	VDDK     *DataVolumeSourceVDDK     `json:"vddk,omitempty"`
+	HTTP        *DataVolumeSourceHTTP        `json:"http,omitempty"`
+	S3          *DataVolumeSourceS3          `json:"s3,omitempty"`
+	AWSSnapshot *DataVolumeSourceAWSSnapshot `json:"awsSnapshot,omitempty"`
+	GCS         *DataVolumeSourceGCS         `json:"gcs,omitempty"`
+	GCEImage    *DataVolumeSourceGCEImage    `json:"gceImage,omitempty"`
+	AzureBlob   *DataVolumeSourceAzureBlob   `json:"azureBlob,omitempty"`
+	AzureDisk   *DataVolumeSourceAzureDisk   `json:"azureDisk,omitempty"`
+	Registry    *DataVolumeSourceRegistry    `json:"registry,omitempty"`
+	PVC         *DataVolumeSourcePVC         `json:"pvc,omitempty"`
+	PVCNetwork  *DataVolumeSourcePVC         `json:"pvcNetwork,omitempty"`
+	Snapshot    *DataVolumeSourceSnapshot    `json:"snapshot,omitempty"`
+	Upload      *DataVolumeSourceUpload      `json:"upload,omitempty"`
+	Blank       *DataVolumeBlankImage        `json:"blank,omitempty"`
+	Imageio     *DataVolumeSourceImageIO     `json:"imageio,omitempty"`
+	Glance      *DataVolumeSourceGlance      `json:"glance,omitempty"`
+	Proxmox     *DataVolumeSourceProxmox     `json:"proxmox,omitempty"`
+	HyperV      *DataVolumeSourceHyperV      `json:"hyperV,omitempty"`
+	NFS         *DataVolumeSourceNFS         `json:"nfs,omitempty"`
+	SMB         *DataVolumeSourceSMB         `json:"smb,omitempty"`
+	Rsync       *DataVolumeSourceRsync       `json:"rsync,omitempty"`
+	ISCSI       *DataVolumeSourceISCSI       `json:"iscsi,omitempty"`
+	RBD         *DataVolumeSourceRBD         `json:"rbd,omitempty"`
+	File        *DataVolumeSourceFile        `json:"file,omitempty"`
+	Libvirt     *DataVolumeSourceLibvirt     `json:"libvirt,omitempty"`
+	VDDK        *DataVolumeSourceVDDK        `json:"vddk,omitempty"`
 }
 
 // DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC
@@ -95,6 +147,14 @@ type DataVolumeSourcePVC struct {
 	Name string `json:"name"`
 }
 
+// DataVolumeSourceSnapshot provides the parameters to create a Data Volume from a VolumeSnapshot
+type DataVolumeSourceSnapshot struct {
+	// The namespace of the source VolumeSnapshot
+	Namespace string `json:"namespace"`
+	// The name of the source VolumeSnapshot
+	Name string `json:"name"`
+}
+
 // DataVolumeBlankImage provides the parameters to create a new raw blank image for the PVC
 type DataVolumeBlankImage struct{}
 
@@ -108,6 +168,98 @@ type DataVolumeSourceS3 struct {
 	URL string `json:"url"`
 	//SecretRef provides the secret reference needed to access the S3 source
 	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	//Region is the region of the bucket, by default the region is derived from the host of the URL
+	// +optional
+	Region string `json:"region,omitempty"`
+	//ForcePathStyle addresses the bucket in the path of the request instead of the host name, defaults to true
+	// +optional
+	ForcePathStyle *bool `json:"forcePathStyle,omitempty"`
+	//SSECustomerKeySecretRef is the secret containing the base64 encoded 256-bit AES key in the sseCustomerKey key, used to read objects encrypted with SSE-C
+	// +optional
+	SSECustomerKeySecretRef string `json:"sseCustomerKeySecretRef,omitempty"`
+	// Segments is the number of concurrent ranged requests used to download the object
+	// +optional
+	Segments *int32 `json:"segments,omitempty"`
+	// SegmentSize is the size of each ranged request, if not set the object is split evenly between the segments
+	// +optional
+	SegmentSize *resource.Quantity `json:"segmentSize,omitempty"`
+	//ObjectVersionID is the version of the object to import from a versioned bucket, by default the latest version is imported
+	// +optional
+	ObjectVersionID string `json:"objectVersionId,omitempty"`
+}
+
+// DataVolumeSourceAWSSnapshot provides the parameters to create a Data Volume from an EBS snapshot or AMI, using the EBS direct APIs
+type DataVolumeSourceAWSSnapshot struct {
+	//SnapshotID is the ID of the EBS snapshot, or the ID of an AMI whose root device snapshot is imported. With checkpoints the snapshot of the current checkpoint is imported instead
+	SnapshotID string `json:"snapshotId"`
+	//Region is the region of the snapshot
+	Region string `json:"region"`
+	//SecretRef provides the secret containing the accessKeyId and secretKey. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// DataVolumeSourceGCS provides the parameters to create a Data Volume from a Google Cloud Storage object
+type DataVolumeSourceGCS struct {
+	//URL is the url of the GCS object, gs://bucket/object
+	URL string `json:"url"`
+	//SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// DataVolumeSourceGCEImage provides the parameters to create a Data Volume from a Google Compute Engine image
+type DataVolumeSourceGCEImage struct {
+	//Image is the image to import, projects/<project>/global/images/<name> or projects/<project>/global/images/family/<family>
+	Image string `json:"image"`
+	//ExportURL is the gs:// url of the image exported with gcloud compute images export, required if the image was not created from a tar.gz in GCS
+	// +optional
+	ExportURL string `json:"exportUrl,omitempty"`
+	//SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// DataVolumeSourceAzureBlob provides the parameters to create a Data Volume from an Azure Blob Storage blob
+type DataVolumeSourceAzureBlob struct {
+	//URL is the url of the blob, https://account.blob.core.windows.net/container/blob
+	URL string `json:"url"`
+	//SecretRef is the secret containing either a SAS token in the sasToken key or the storage account key in the accountKey key. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Segments is the number of concurrent ranged requests used to download the blob
+	// +optional
+	Segments *int32 `json:"segments,omitempty"`
+	// SegmentSize is the size of each ranged request, if not set the blob is split evenly between the segments
+	// +optional
+	SegmentSize *resource.Quantity `json:"segmentSize,omitempty"`
+}
+
+// DataVolumeSourceAzureDisk provides the parameters to create a Data Volume from an Azure managed disk or snapshot
+type DataVolumeSourceAzureDisk struct {
+	//ResourceID is the resource ID of the managed disk or snapshot, /subscriptions/<subscription>/resourceGroups/<group>/providers/Microsoft.Compute/snapshots/<name>
+	ResourceID string `json:"resourceId"`
+	//SecretRef is the secret containing the tenantId, clientId and clientSecret of a service principal. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 }
 
 // DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source
@@ -118,18 +270,65 @@ type DataVolumeSourceRegistry struct {
 	SecretRef string `json:"secretRef,omitempty"`
 	//CertConfigMap provides a reference to the Registry certs
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// Platform selects the image of a manifest list as os/arch[/variant], for instance linux/arm64. If not set the image of the platform of the node running the import is selected
+	// +optional
+	Platform string `json:"platform,omitempty"`
+	// Signature verifies the cosign signature of the image before it is imported, the import fails if no signature verifies
+	// +optional
+	Signature *DataVolumeSourceRegistrySignature `json:"signature,omitempty"`
+}
+
+// DataVolumeSourceRegistrySignature verifies the cosign signatures of a registry image, with a public key, or keyless with the certificate Fulcio issued to the identity that signed the image
+type DataVolumeSourceRegistrySignature struct {
+	// ConfigMap is the name of a ConfigMap with the PEM public key verifying the signatures in its cosign.pub key, or for keyless signatures with the PEM Fulcio root certificates in its fulcio.crt.pem key and the PEM Rekor public key in its rekor.pub key
+	ConfigMap string `json:"configMap"`
+	// Identity is the email or URI of the signer of keyless signatures
+	// +optional
+	Identity string `json:"identity,omitempty"`
+	// Issuer is the URL of the OIDC issuer that authenticated the signer of keyless signatures
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
 }
 
-// DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs
+// DataVolumeSourceHTTP can be either an http, https, ftp or ftps endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs
 type DataVolumeSourceHTTP struct {
-	// URL is the URL of the http(s) endpoint
+	// URL is the URL of the http(s) or ftp(s) endpoint
 	URL string `json:"url"`
 	// SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded
 	// +optional
 	SecretRef string `json:"secretRef,omitempty"`
+	// FTPPassive selects passive mode for ftp and ftps URLs, the default, set it to false to use active mode where the server connects back to the importer pod
+	// +optional
+	FTPPassive *bool `json:"ftpPassive,omitempty"`
+	// TokenSecretRef A Secret reference, the secret should contain a bearer token (token) base64 encoded, that is sent in the Authorization header
+	// +optional
+	TokenSecretRef string `json:"tokenSecretRef,omitempty"`
+	// OAuth2 provides the parameters to retrieve bearer tokens using the OAuth2 client credentials flow
+	// +optional
+	OAuth2 *DataVolumeSourceHTTPOAuth2 `json:"oauth2,omitempty"`
 	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
 	// +optional
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// ClientCertSecretRef A Secret reference of type kubernetes.io/tls, the client certificate (tls.crt) and key (tls.key) are presented to the endpoint
+	// +optional
+	ClientCertSecretRef string `json:"clientCertSecretRef,omitempty"`
+	// Segments is the number of concurrent ranged requests used to download the image into scratch space before conversion, requires the server to support ranges
+	// +optional
+	Segments *int32 `json:"segments,omitempty"`
+	// SegmentSize is the size of each ranged request, if not set the image is split evenly between the segments
+	// +optional
+	SegmentSize *resource.Quantity `json:"segmentSize,omitempty"`
+}
+
+// DataVolumeSourceHTTPOAuth2 provides the parameters to retrieve bearer tokens from an OAuth2 token endpoint using the client credentials flow
+type DataVolumeSourceHTTPOAuth2 struct {
+	// TokenURL is the URL of the OAuth2 token endpoint
+	TokenURL string `json:"tokenURL"`
+	// SecretRef A Secret reference, the secret should contain clientId and clientSecret base64 encoded
+	SecretRef string `json:"secretRef"`
+	// Scopes is the list of scopes to request
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // DataVolumeSourceImageIO provides the parameters to create a Data Volume from an imageio source
@@ -144,6 +343,163 @@ type DataVolumeSourceImageIO struct {
 	CertConfigMap string `json:"certConfigMap,omitempty"`
 }
 
+// DataVolumeSourceGlance provides the parameters to create a Data Volume from an OpenStack Glance image
+type DataVolumeSourceGlance struct {
+	// AuthURL is the URL of the Keystone v3 identity service, for instance https://keystone.example.com:5000/v3
+	AuthURL string `json:"authUrl"`
+	// Project is the name of the project the image is visible to
+	Project string `json:"project"`
+	// Domain is the name of the domain of the user and the project, defaults to Default
+	// +optional
+	Domain string `json:"domain,omitempty"`
+	// Region is the region of the image service in the service catalog, if not set the first public image endpoint is used
+	// +optional
+	Region string `json:"region,omitempty"`
+	// ImageID is the UUID of the image
+	ImageID string `json:"imageId"`
+	// SecretRef provides the secret reference needed to access Keystone, the secret should contain accessKeyId (user name) and secretKey (password)
+	SecretRef string `json:"secretRef"`
+	// CertConfigMap provides a reference to the CA cert of Keystone and Glance
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataVolumeSourceProxmox provides the parameters to create a Data Volume from the disk of a Proxmox VE virtual machine
+type DataVolumeSourceProxmox struct {
+	// URL is the URL of the API of a node of the Proxmox VE cluster, for instance https://pve.example.com:8006
+	URL string `json:"url"`
+	// Node is the name of the node of the VM, if not set the node is looked up in the cluster resources
+	// +optional
+	Node string `json:"node,omitempty"`
+	// VMID is the ID of the VM, the VM must be stopped
+	VMID int32 `json:"vmId"`
+	// Disk is the key of the disk in the configuration of the VM, for instance scsi0 or virtio1
+	Disk string `json:"disk"`
+	// SecretRef provides the secret reference needed to access the API, the secret should contain accessKeyId (API token ID, user@realm!token) and secretKey (API token secret)
+	SecretRef string `json:"secretRef"`
+	// CertConfigMap provides a reference to the CA cert of the API
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataVolumeSourceHyperV provides the parameters to create a Data Volume from a VHDX or VHD disk of a Hyper-V virtual machine on an SMB share
+type DataVolumeSourceHyperV struct {
+	// Server is the host name or address of the SMB server
+	Server string `json:"server"`
+	// Share is the name of the SMB share
+	Share string `json:"share"`
+	// Path is the path of the disk in the share, for instance VMs/fedora/Virtual Hard Disks/fedora.vhdx
+	Path string `json:"path"`
+	// SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// Domain is the domain or workgroup of the user
+	// +optional
+	Domain string `json:"domain,omitempty"`
+}
+
+// DataVolumeSourceNFS provides the parameters to create a Data Volume from an image file on an NFS export
+type DataVolumeSourceNFS struct {
+	// Server is the host name or address of the NFS server
+	Server string `json:"server"`
+	// Path is the absolute path of the image file on the server, for instance /export/templates/fedora.qcow2, the importer mounts its directory read-only
+	Path string `json:"path"`
+}
+
+// DataVolumeSourceSMB provides the parameters to create a Data Volume from an image file on an SMB/CIFS share
+type DataVolumeSourceSMB struct {
+	// Server is the host name or address of the SMB server
+	Server string `json:"server"`
+	// Share is the name of the SMB share
+	Share string `json:"share"`
+	// Path is the path of the image file in the share, for instance Images/fedora.qcow2
+	Path string `json:"path"`
+	// SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// Domain is the domain or workgroup of the user
+	// +optional
+	Domain string `json:"domain,omitempty"`
+}
+
+// DataVolumeSourceRsync provides the parameters to create a Data Volume from an image file pulled with rsync over SSH
+type DataVolumeSourceRsync struct {
+	// Host is the host name or address of the SSH server, optionally followed by :port
+	Host string `json:"host"`
+	// Module is the rsync module containing the image, read through a single-use rsync daemon started over SSH. The path is absolute on the server if not set
+	// +optional
+	Module string `json:"module,omitempty"`
+	// Path is the path of the image file, relative to the module if one is set, for instance templates/fedora.qcow2
+	Path string `json:"path"`
+	// SecretRef provides the secret reference needed to log in, the secret should contain accessKeyId (user name) and secretKey (SSH private key)
+	SecretRef string `json:"secretRef"`
+	// HostKey is the public key of the SSH server in known_hosts format, for instance "ssh-ed25519 AAAAC3...", the host key is not verified if not set
+	// +optional
+	HostKey string `json:"hostKey,omitempty"`
+}
+
+// DataVolumeSourceISCSI provides the parameters to create a Data Volume from a LUN of an iSCSI target
+type DataVolumeSourceISCSI struct {
+	// Portal is the address of the iSCSI portal, optionally followed by :port, the default port is 3260
+	Portal string `json:"portal"`
+	// IQN is the qualified name of the iSCSI target, for instance iqn.2003-01.org.linux-iscsi.san:p2v
+	IQN string `json:"iqn"`
+	// LUN is the number of the logical unit to import, 0 by default
+	// +optional
+	LUN int32 `json:"lun,omitempty"`
+	// SecretRef provides the secret reference needed for CHAP authentication, the secret should contain accessKeyId (user name) and secretKey (password), no authentication is used if not set
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// InitiatorName is the qualified name of the initiator the importer logs in with, for targets restricting access by initiator
+	// +optional
+	InitiatorName string `json:"initiatorName,omitempty"`
+}
+
+// DataVolumeSourceRBD provides the parameters to create a Data Volume from an RBD image of an external Ceph cluster
+type DataVolumeSourceRBD struct {
+	// Monitors are the addresses of the Ceph monitors, optionally followed by :port
+	Monitors []string `json:"monitors"`
+	// Pool is the name of the pool containing the image
+	Pool string `json:"pool"`
+	// Image is the name of the RBD image
+	Image string `json:"image"`
+	// Snapshot is the name of a snapshot of the image to import instead of the image itself, recommended if the image is in use
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+	// SecretRef provides the secret reference needed to access the cluster, the secret should contain accessKeyId (Ceph user id, for instance admin) and secretKey (key of the user from its keyring)
+	SecretRef string `json:"secretRef"`
+}
+
+// DataVolumeSourceFile provides the parameters to create a Data Volume from an image file on a directory of a node or on an existing PVC, which the importer pod mounts read-only
+type DataVolumeSourceFile struct {
+	// HostPath is the absolute path of a directory of the node containing the image file, it requires the FileSourceHostPath feature gate
+	// +optional
+	HostPath string `json:"hostPath,omitempty"`
+	// NodeName is the name of the node with the host path, the importer pod runs on this node
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+	// ClaimName is the name of an existing PVC in the namespace of the Data Volume containing the image file
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// Path is the path of the image file relative to the host path or to the root of the PVC, for instance images/fedora.qcow2
+	Path string `json:"path"`
+}
+
+// DataVolumeSourceLibvirt provides the parameters to create a Data Volume from a disk of a domain of a remote libvirt host
+type DataVolumeSourceLibvirt struct {
+	// URI is the libvirt connection URI of the host, for instance qemu+ssh://kvm01.example.com/system
+	URI string `json:"uri"`
+	// Domain is the name of the domain
+	Domain string `json:"domain"`
+	// Disk is the target device of the disk in the domain, for instance vda
+	Disk string `json:"disk"`
+	// SecretRef provides the secret reference needed to log in to the host, the secret should contain accessKeyId (user name) and secretKey (SSH private key)
+	SecretRef string `json:"secretRef"`
+	// HostKey is the public key of the host in known_hosts format, for instance ssh-ed25519 AAAA..., the host key is not verified if empty
+	// +optional
+	HostKey string `json:"hostKey,omitempty"`
+}
+
 // DataVolumeSourceVDDK provides the parameters to create a Data Volume from a Vmware source
 type DataVolumeSourceVDDK struct {
 	// URL is the URL of the vCenter or ESXi host with the VM to migrate
@@ -156,6 +512,9 @@ type DataVolumeSourceVDDK struct {
 	Thumbprint string `json:"thumbprint,omitempty"`
 	// SecretRef provides a reference to a secret containing the username and password needed to access the vCenter or ESXi host
 	SecretRef string `json:"secretRef,omitempty"`
+	// Connections is the number of concurrent NBD connections used to copy the disk, each connection opens a VDDK session on the host
+	// +optional
+	Connections *int32 `json:"connections,omitempty"`
 }
 
 // DataVolumeStatus contains the current status of the DataVolume
@@ -163,9 +522,64 @@ type DataVolumeStatus struct {
 	//Phase is the current phase of the data volume
 	Phase    DataVolumePhase    `json:"phase,omitempty"`
 	Progress DataVolumeProgress `json:"progress,omitempty"`
+	// Transfer is the amount transferred, the throughput and the estimated completion of the import, when the importer reports them
+	// +optional
+	Transfer *DataVolumeTransferStatus `json:"transfer,omitempty"`
+	// SourceInfo is the format, compression and sizes of the imported source, as detected by the importer
+	// +optional
+	SourceInfo *DataVolumeSourceInfo `json:"sourceInfo,omitempty"`
 	// RestartCount is the number of times the pod populating the DataVolume has restarted
 	RestartCount int32                 `json:"restartCount,omitempty"`
 	Conditions   []DataVolumeCondition `json:"conditions,omitempty" optional:"true"`
+	// Checkpoints is the status of the copy of each checkpoint of a multi-stage import
+	// +optional
+	Checkpoints []DataVolumeCheckpointStatus `json:"checkpoints,omitempty"`
+}
+
+// DataVolumeTransferStatus is the rate and the estimated completion of the transfer of a DataVolume.
+type DataVolumeTransferStatus struct {
+	// BytesTransferred is the number of bytes read from the source so far.
+	BytesTransferred int64 `json:"bytesTransferred"`
+	// BytesPerSecond is the current transfer rate, 0 when the transfer is stalled.
+	BytesPerSecond int64 `json:"bytesPerSecond"`
+	// EstimatedCompletionTime is the time the transfer is expected to complete at the current rate, unset when unknown.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+}
+
+// DataVolumeSourceInfo describes the source of a completed import, as detected by the importer.
+type DataVolumeSourceInfo struct {
+	// Format is the disk image format of the source, like raw, qcow2, vmdk or vhdx.
+	// +optional
+	Format string `json:"format,omitempty"`
+	// Compression is the compression of the source, gz or xz, empty if not compressed.
+	// +optional
+	Compression string `json:"compression,omitempty"`
+	// VirtualSize is the size of the disk in the source image.
+	// +optional
+	VirtualSize *resource.Quantity `json:"virtualSize,omitempty"`
+	// DownloadedSize is the size of the source as downloaded.
+	// +optional
+	DownloadedSize *resource.Quantity `json:"downloadedSize,omitempty"`
+}
+
+// DataVolumeCheckpointStatus is the status of the copy of one checkpoint of a multi-stage import.
+type DataVolumeCheckpointStatus struct {
+	// Previous is the identifier of the checkpoint the changes are copied from, empty for the base copy.
+	// +optional
+	Previous string `json:"previous,omitempty"`
+	// Current is the identifier of the copied checkpoint.
+	Current string `json:"current"`
+	// Phase is the phase of the copy of the checkpoint, Pending, ImportInProgress or Succeeded.
+	Phase DataVolumePhase `json:"phase,omitempty"`
+	// Progress is the progress of the copy of the checkpoint.
+	Progress DataVolumeProgress `json:"progress,omitempty"`
+	// StartTime is the time the copy of the checkpoint started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the copy of the checkpoint completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 //DataVolumeList provides the needed parameters to do request a list of Data Volumes from the system
@@ -248,11 +662,519 @@ const (
 	DataVolumeBound DataVolumeConditionType = "Bound"
 	// DataVolumeRunning is the condition that indicates if the import/upload/clone container is running.
 	DataVolumeRunning DataVolumeConditionType = "Running"
+	// DataVolumeSmartCloneFallback is the condition that indicates why a clone is a host-assisted copy instead of a smart-clone.
+	DataVolumeSmartCloneFallback DataVolumeConditionType = "SmartCloneFallback"
 )
 
 // DataVolumeCloneSourceSubresource is the subresource checked for permission to clone
 const DataVolumeCloneSourceSubresource = "source"
 
+// DataExport writes the disk of a PVC or DataVolume to a target outside of the cluster
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=dx;dxs
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase the export is in"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.progress",description="Export progress in percentage if known, N/A otherwise"
+// +kubebuilder:printcolumn:name="Restarts",type="integer",JSONPath=".status.restartCount",description="The number of times the export has been restarted."
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DataExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataExportSpec   `json:"spec"`
+	Status DataExportStatus `json:"status,omitempty"`
+}
+
+// DataExportSpec defines the DataExport type specification
+type DataExportSpec struct {
+	// Source is the PVC, DataVolume or VolumeSnapshot whose disk is exported
+	Source DataExportSource `json:"source"`
+	// Target is where the disk is written to
+	Target DataExportTarget `json:"target"`
+	// Format is the format of the exported disk image, qcow2 or raw, defaults to qcow2
+	// +kubebuilder:validation:Enum="qcow2";"raw"
+	// +optional
+	Format DataExportFormat `json:"format,omitempty"`
+	// Compression is the compression of the clusters of qcow2 images, zlib, zstd or none, defaults to zlib
+	// +kubebuilder:validation:Enum="zlib";"zstd";"none"
+	// +optional
+	Compression DataExportCompression `json:"compression,omitempty"`
+	// TTLSecondsAfterFinished is the time the DataExport is kept once it succeeded before it is deleted, it is kept until deleted when unset
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// DataExportSource is the PVC, DataVolume or VolumeSnapshot, in the namespace of the DataExport, whose disk is exported
+type DataExportSource struct {
+	// PVC is the name of the exported PVC
+	// +optional
+	PVC string `json:"pvc,omitempty"`
+	// DataVolume is the name of the exported DataVolume, the export starts once the DataVolume succeeded
+	// +optional
+	DataVolume string `json:"dataVolume,omitempty"`
+	// VolumeSnapshot is the name of the exported VolumeSnapshot, the disk is read from a temporary PVC restored from the snapshot
+	// +optional
+	VolumeSnapshot string `json:"volumeSnapshot,omitempty"`
+}
+
+// DataExportTarget is the target of a DataExport
+type DataExportTarget struct {
+	// S3 writes the disk to an object of an S3 bucket
+	// +optional
+	S3 *DataExportTargetS3 `json:"s3,omitempty"`
+	// HTTP writes the disk with an HTTP PUT, to a WebDAV server or an artifact store
+	// +optional
+	HTTP *DataExportTargetHTTP `json:"http,omitempty"`
+	// Registry pushes the disk as a containerDisk image to a container registry
+	// +optional
+	Registry *DataExportTargetRegistry `json:"registry,omitempty"`
+	// CDI uploads the disk to a DataVolume of another cluster, resuming after network interruptions
+	// +optional
+	CDI *DataExportTargetCDI `json:"cdi,omitempty"`
+}
+
+// DataExportTargetS3 provides the parameters to write the exported disk to an S3 object, with a multipart upload
+type DataExportTargetS3 struct {
+	//URL is the url of the S3 object, for instance https://s3.us-east-1.amazonaws.com/bucket/fedora.qcow2
+	URL string `json:"url"`
+	//SecretRef is the secret containing the accessKeyId and secretKey used to write the object. Without a secretRef the credentials of the pod are used
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//Region is the region of the bucket, by default the region is derived from the host of the URL
+	// +optional
+	Region string `json:"region,omitempty"`
+	//PartSize is the size of the parts of the multipart upload, defaults to 64Mi
+	// +optional
+	PartSize *resource.Quantity `json:"partSize,omitempty"`
+}
+
+// DataExportTargetHTTP provides the parameters to write the exported disk with an HTTP PUT
+type DataExportTargetHTTP struct {
+	//URL is the url the disk is written to, for instance https://nexus.example.com/repository/disks/fedora.qcow2
+	URL string `json:"url"`
+	//SecretRef is the secret containing the accessKeyId (user name) and secretKey (password) of the basic authentication of the server
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//CertConfigMap is a configmap containing the Certificate Authorities of the server
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataExportTargetRegistry provides the parameters to push the exported disk as a containerDisk image
+type DataExportTargetRegistry struct {
+	//URL is the image the disk is pushed to, for instance docker://quay.io/kubevirt/fedora:35
+	URL string `json:"url"`
+	//SecretRef is a secret of type kubernetes.io/dockerconfigjson with the credentials of the registry
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//CertConfigMap is a configmap containing the Certificate Authorities of the registry
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataExportTargetCDI provides the parameters to upload the exported disk to an upload DataVolume of another cluster,
+// through its upload proxy with the tus resumable upload protocol
+type DataExportTargetCDI struct {
+	//URL is the url of the upload proxy of the other cluster, for instance https://cdi-uploadproxy.dr.example.com
+	URL string `json:"url"`
+	//TokenSecretRef is the secret containing the upload token of the target DataVolume in its token key. The token is read again before each request, so it can be renewed in the secret during long exports
+	TokenSecretRef string `json:"tokenSecretRef"`
+	//CertConfigMap is a configmap containing the Certificate Authorities of the upload proxy
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataExportFormat is the format of the exported disk image
+type DataExportFormat string
+
+const (
+	// DataExportFormatQcow2 exports the disk as a compressed qcow2 image
+	DataExportFormatQcow2 DataExportFormat = "qcow2"
+	// DataExportFormatRaw exports the disk as is
+	DataExportFormatRaw DataExportFormat = "raw"
+)
+
+// DataExportCompression is the compression of the clusters of exported qcow2 images
+type DataExportCompression string
+
+const (
+	// DataExportCompressionZlib compresses the clusters with zlib, which all qemu versions read
+	DataExportCompressionZlib DataExportCompression = "zlib"
+	// DataExportCompressionZstd compresses the clusters with zstd, faster than zlib but readable by qemu 5.1 and later only
+	DataExportCompressionZstd DataExportCompression = "zstd"
+	// DataExportCompressionNone does not compress the clusters
+	DataExportCompressionNone DataExportCompression = "none"
+)
+
+// DataExportStatus is the status of a DataExport
+type DataExportStatus struct {
+	// Phase is the current phase of the export
+	Phase DataExportPhase `json:"phase,omitempty"`
+	// Progress is the percentage of the disk written to the target
+	Progress DataVolumeProgress `json:"progress,omitempty"`
+	// RestartCount is the number of times the export pod has restarted
+	RestartCount int32 `json:"restartCount,omitempty"`
+	// Message explains why the export is pending, or the last failure of the export pod
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is the time the export completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Conditions are the SourceReady, Running and Ready conditions of the export
+	// +optional
+	Conditions []DataVolumeCondition `json:"conditions,omitempty" optional:"true"`
+}
+
+// DataExportPhase is the current phase of the DataExport
+type DataExportPhase string
+
+const (
+	// ExportPending represents a DataExport waiting for its source to be populated and not in use
+	ExportPending DataExportPhase = "Pending"
+	// ExportInProgress represents a DataExport whose export pod is running
+	ExportInProgress DataExportPhase = "ExportInProgress"
+	// ExportSucceeded represents a DataExport whose disk was written to the target
+	ExportSucceeded DataExportPhase = "Succeeded"
+)
+
+// DataExportSourceReady is the condition of the source of a DataExport, true once it is available for the export
+const DataExportSourceReady DataVolumeConditionType = "SourceReady"
+
+//DataExportList provides the needed parameters to do request a list of DataExports from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DataExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of DataExports
+	Items []DataExport `json:"items"`
+}
+
+// CloneGrant allows the DataVolumes of a target namespace to clone the PVCs of the namespace of the grant, in place of
+// the short-lived clone token of the DataVolumes
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=cg;cgs
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetNamespace",description="The namespace allowed to clone"
+// +kubebuilder:printcolumn:name="Expiration",type="string",JSONPath=".spec.expirationTime",description="The time the grant expires"
+// +kubebuilder:printcolumn:name="Clones",type="integer",JSONPath=".status.cloneCount",description="The number of clones the grant allowed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type CloneGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloneGrantSpec   `json:"spec"`
+	Status CloneGrantStatus `json:"status,omitempty"`
+}
+
+// CloneGrantSpec defines the CloneGrant type specification
+type CloneGrantSpec struct {
+	// TargetNamespace is the namespace whose DataVolumes may clone the PVCs of the namespace of the grant
+	TargetNamespace string `json:"targetNamespace"`
+	// Selector selects the PVCs that may be cloned, all the PVCs of the namespace may be cloned when unset
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// ExpirationTime is the time after which the grant no longer allows clones, the grant does not expire when unset
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// CloneGrantStatus is the status of a CloneGrant, recording the clones it allowed
+type CloneGrantStatus struct {
+	// CloneCount is the number of clones the grant allowed
+	CloneCount int64 `json:"cloneCount,omitempty"`
+	// LastCloneTime is the time the grant last allowed a clone
+	// +optional
+	LastCloneTime *metav1.Time `json:"lastCloneTime,omitempty"`
+	// LastSource is the name of the source PVC of the last clone the grant allowed
+	// +optional
+	LastSource string `json:"lastSource,omitempty"`
+	// LastTarget is the namespace/name of the target PVC of the last clone the grant allowed
+	// +optional
+	LastTarget string `json:"lastTarget,omitempty"`
+}
+
+//CloneGrantList provides the needed parameters to do request a list of CloneGrants from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CloneGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of CloneGrants
+	Items []CloneGrant `json:"items"`
+}
+
+// VolumeImportSource populates the PVCs referencing it in their dataSource with an import, without a DataVolume
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=vis
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VolumeImportSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VolumeImportSourceSpec `json:"spec"`
+}
+
+// VolumeImportSourceSpec defines the VolumeImportSource type specification
+type VolumeImportSourceSpec struct {
+	// Source is the source of the data imported into the PVCs, any DataVolume source but pvc, pvcNetwork, snapshot and upload
+	Source DataVolumeSource `json:"source"`
+	// ContentType is the content type of the imported data, kubevirt or archive, defaults to kubevirt
+	// +kubebuilder:validation:Enum="kubevirt";"archive"
+	// +optional
+	ContentType DataVolumeContentType `json:"contentType,omitempty"`
+	// Preallocation preallocates the space of the PVCs when true
+	// +optional
+	Preallocation *bool `json:"preallocation,omitempty"`
+}
+
+//VolumeImportSourceList provides the needed parameters to do request a list of VolumeImportSources from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VolumeImportSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of VolumeImportSources
+	Items []VolumeImportSource `json:"items"`
+}
+
+// VolumeUploadSource populates the PVCs referencing it in their dataSource with an upload, without a DataVolume
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=vus
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VolumeUploadSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VolumeUploadSourceSpec `json:"spec"`
+}
+
+// VolumeUploadSourceSpec defines the VolumeUploadSource type specification
+type VolumeUploadSourceSpec struct {
+	// Preallocation preallocates the space of the PVCs when true
+	// +optional
+	Preallocation *bool `json:"preallocation,omitempty"`
+}
+
+//VolumeUploadSourceList provides the needed parameters to do request a list of VolumeUploadSources from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VolumeUploadSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of VolumeUploadSources
+	Items []VolumeUploadSource `json:"items"`
+}
+
+// VolumeCloneSource populates the PVCs referencing it in their dataSource with a clone, without a DataVolume
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=vcs
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source.name"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VolumeCloneSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VolumeCloneSourceSpec `json:"spec"`
+}
+
+// VolumeCloneSourceSpec defines the VolumeCloneSource type specification
+type VolumeCloneSourceSpec struct {
+	// Source is the PVC cloned into the PVCs, in their namespace
+	Source corev1.TypedLocalObjectReference `json:"source"`
+	// Preallocation preallocates the space of the PVCs of host-assisted clones when true
+	// +optional
+	Preallocation *bool `json:"preallocation,omitempty"`
+}
+
+//VolumeCloneSourceList provides the needed parameters to do request a list of VolumeCloneSources from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VolumeCloneSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of VolumeCloneSources
+	Items []VolumeCloneSource `json:"items"`
+}
+
+// DataImportCron polls the registry, http or s3 source of a DataVolume template on a schedule and imports each new image as a
+// golden image, keeping the latest imports and pointing a DataSource at the PVC of the latest one
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=dic;dics
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="The schedule of the polls of the source"
+// +kubebuilder:printcolumn:name="DataSource",type="string",JSONPath=".spec.managedDataSource",description="The DataSource pointing at the latest import"
+// +kubebuilder:printcolumn:name="Last Import",type="date",JSONPath=".status.lastImportTimestamp",description="The time of the last import"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DataImportCron struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataImportCronSpec   `json:"spec"`
+	Status DataImportCronStatus `json:"status,omitempty"`
+}
+
+// DataImportCronSpec defines the DataImportCron type specification
+type DataImportCronSpec struct {
+	// Template is the DataVolume of the imports, its source is polled for new images
+	Template DataVolume `json:"template"`
+	// Schedule is when the source is polled, in the cron format of CronJobs
+	Schedule string `json:"schedule"`
+	// GarbageCollect is Outdated, the default, to delete the imports which are not retained anymore, or Never to keep all the imports
+	// +kubebuilder:validation:Enum="Outdated";"Never"
+	// +optional
+	GarbageCollect *DataImportCronGarbageCollect `json:"garbageCollect,omitempty"`
+	// ImportsToKeep is the number of the latest imports retained, 3 by default
+	// +optional
+	ImportsToKeep *int32 `json:"importsToKeep,omitempty"`
+	// RetentionPeriod retains the imports newer than the period as well, whatever their number
+	// +optional
+	RetentionPeriod *metav1.Duration `json:"retentionPeriod,omitempty"`
+	// ManagedDataSource is the name of the DataSource pointing at the PVC of the latest import, in the namespace of the DataImportCron
+	ManagedDataSource string `json:"managedDataSource"`
+	// PinnedDigest pins the DataSource to the import of the digest, which is imported if it is not retained. New images are still imported, the DataSource points at the latest one again once the pin is removed
+	// +optional
+	PinnedDigest string `json:"pinnedDigest,omitempty"`
+	// Notification is the webhook notified when the DataSource points at a new golden image
+	// +optional
+	Notification *DataImportCronNotification `json:"notification,omitempty"`
+}
+
+// DataImportCronNotification is the webhook notified of the new golden images of a DataImportCron
+type DataImportCronNotification struct {
+	// URL is the http(s) endpoint the new golden images are posted to
+	URL string `json:"url"`
+	// CertConfigMap is a ConfigMap, in the namespace of the DataImportCron, with the CA bundle verifying the certificate of the endpoint
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// DataImportCronGarbageCollect tells whether the imports which are not retained anymore are deleted
+type DataImportCronGarbageCollect string
+
+const (
+	// DataImportCronGarbageCollectOutdated deletes the PVCs, and their snapshots, of the imports which are not retained anymore
+	DataImportCronGarbageCollectOutdated DataImportCronGarbageCollect = "Outdated"
+	// DataImportCronGarbageCollectNever keeps all the imports
+	DataImportCronGarbageCollectNever DataImportCronGarbageCollect = "Never"
+)
+
+// DataImportCronStatus is the status of a DataImportCron
+type DataImportCronStatus struct {
+	// Imports are the imports of the DataImportCron which are not garbage collected yet, the latest first
+	// +optional
+	Imports []DataImportCronImport `json:"imports,omitempty"`
+	// LastExecutionTimestamp is the time the source was last polled
+	// +optional
+	LastExecutionTimestamp *metav1.Time `json:"lastExecutionTimestamp,omitempty"`
+	// LastImportTimestamp is the time the latest import succeeded
+	// +optional
+	LastImportTimestamp *metav1.Time `json:"lastImportTimestamp,omitempty"`
+	// CurrentDigest is the digest of the import the DataSource points at
+	// +optional
+	CurrentDigest string `json:"currentDigest,omitempty"`
+	// NotifiedDigest is the digest of the last golden image the DataImportCron notified of
+	// +optional
+	NotifiedDigest string `json:"notifiedDigest,omitempty"`
+}
+
+// DataImportCronImport is an import of a new image of the source of a DataImportCron
+type DataImportCronImport struct {
+	// DataVolumeName is the name of the DataVolume of the import, and of its PVC
+	DataVolumeName string `json:"dataVolumeName"`
+	// Digest is the digest of the imported image, or of the validators of the imported http or s3 object
+	Digest string `json:"digest"`
+	// Timestamp is the time the import started, the retention period of the import starts then
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+//DataImportCronList provides the needed parameters to do request a list of DataImportCrons from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DataImportCronList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of DataImportCrons
+	Items []DataImportCron `json:"items"`
+}
+
+// DataSource references the PVC of a golden image, so its consumers keep the same reference when the PVC is replaced
+// by a newer import
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=das
+// +kubebuilder:printcolumn:name="PVC",type="string",JSONPath=".status.source.pvc.name",description="The PVC of the ready source"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",description="Whether a source is ready"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",description="Why the source is ready or not"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DataSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DataSourceSpec `json:"spec"`
+	// +optional
+	Status DataSourceStatus `json:"status,omitempty"`
+}
+
+// DataSourceSpec defines the DataSource type specification
+type DataSourceSpec struct {
+	// Source is the source of the data referenced by the DataSource
+	Source DataSourceSource `json:"source"`
+	// Fallbacks are the sources used, in their order, while the source is not ready, like when its golden image is missing or being replaced
+	// +optional
+	Fallbacks []DataSourceSource `json:"fallbacks,omitempty"`
+}
+
+// DataSourceStatus is the status of a DataSource
+type DataSourceStatus struct {
+	// Source is the first ready source among the source and the fallbacks, the one its consumers clone
+	// +optional
+	Source *DataSourceSource `json:"source,omitempty"`
+	// Conditions holds the Ready condition of the DataSource, its reason tells whether the source or a fallback is used
+	// +optional
+	Conditions []DataVolumeCondition `json:"conditions,omitempty" optional:"true"`
+}
+
+const (
+	// DataSourceReady is the reason of the Ready condition when the source is ready
+	DataSourceReady = "Ready"
+	// DataSourceFallback is the reason of the Ready condition when a fallback is used as the source is not ready
+	DataSourceFallback = "Fallback"
+	// DataSourceNotFound is the reason of the Ready condition when the PVC of the source is not found
+	DataSourceNotFound = "NotFound"
+	// DataSourceNotPopulated is the reason of the Ready condition when the PVC of the source is still being populated
+	DataSourceNotPopulated = "NotPopulated"
+	// DataSourceNoSource is the reason of the Ready condition when the DataSource has no source
+	DataSourceNoSource = "NoSource"
+)
+
+// DataSourceSource is the source of the data referenced by a DataSource
+type DataSourceSource struct {
+	// PVC is the PVC of the golden image
+	// +optional
+	PVC *DataVolumeSourcePVC `json:"pvc,omitempty"`
+}
+
+//DataSourceList provides the needed parameters to do request a list of DataSources from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DataSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of DataSources
+	Items []DataSource `json:"items"`
+}
+
 // this has to be here otherwise informer-gen doesn't recognize it
 // see https://github.com/kubernetes/code-generator/issues/59
 // +genclient:nonNamespaced
@@ -293,6 +1215,28 @@ type CDICertConfig struct {
 	// Server configuration
 	// Certs are rotated and discarded
 	Server *CertConfig `json:"server,omitempty"`
+
+	// CertManager delegates the serving certificates of the apiserver and of the upload proxy to cert-manager,
+	// which then rotates them and their CA instead of CDI
+	CertManager *CertManagerConfig `json:"certManager,omitempty"`
+}
+
+// CertManagerConfig defines the cert-manager issuer of the serving certificates of CDI
+type CertManagerConfig struct {
+	// IssuerRef is the cert-manager Issuer of the CDI namespace or the ClusterIssuer issuing the certificates.
+	// The issuer must provide the CA of the certificates, like the CA and Vault issuers
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerReference references a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// Name is the name of the issuer
+	Name string `json:"name"`
+	// Kind is the kind of the issuer, Issuer if not set
+	// +kubebuilder:validation:Enum="Issuer";"ClusterIssuer"
+	Kind string `json:"kind,omitempty"`
+	// Group is the API group of the issuer, cert-manager.io if not set
+	Group string `json:"group,omitempty"`
 }
 
 // CDISpec defines our specification for the CDI installation
@@ -308,14 +1252,75 @@ type CDISpec struct {
 	// Restrict on which nodes CDI workload pods will be scheduled
 	Workloads sdkapi.NodePlacement `json:"workload,omitempty"`
 	// Clone strategy override: should we use a host-assisted copy even if snapshots are available?
-	// +kubebuilder:validation:Enum="copy";"snapshot"
+	// +kubebuilder:validation:Enum="copy";"snapshot";"csi-clone"
 	CloneStrategyOverride *CDICloneStrategy `json:"cloneStrategyOverride,omitempty"`
 	// CDIConfig at CDI level
 	Config *CDIConfigSpec `json:"config,omitempty"`
 	// certificate configuration
 	CertConfig *CDICertConfig `json:"certConfig,omitempty"`
+	// UploadProxyExposure makes the operator expose the upload proxy outside of the cluster
+	UploadProxyExposure *UploadProxyExposure `json:"uploadProxyExposure,omitempty"`
+	// ComponentResources overrides the resource requirements of the CDI control-plane components
+	ComponentResources *CDIComponentResources `json:"componentResources,omitempty"`
+	// ImagePullSecrets are the secrets used to pull the images of the CDI pods. The control-plane pods use the secrets of
+	// the CDI namespace, the importer, upload and clone pods use the secrets of the same names in their own namespace
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageOverrides replaces the images of the importer, upload and clone pods, for instance with mirrored digests
+	ImageOverrides *CDIImageOverrides `json:"imageOverrides,omitempty"`
+	// PriorityClass is the priority class of the control-plane pods, which keeps them from being evicted before the
+	// transfer pods. The cdi-cluster-critical priority class created by the operator is used if not set
+	PriorityClass *CDIPriorityClass `json:"priorityClass,omitempty"`
+}
+
+// CDIImageOverrides defines the images of the pods created by the CDI controller, the image of the CDI release is used
+// if the image of a pod is not set
+type CDIImageOverrides struct {
+	// Importer is the image of the importer and export pods
+	Importer string `json:"importer,omitempty"`
+	// Cloner is the image of the clone source pods
+	Cloner string `json:"cloner,omitempty"`
+	// UploadServer is the image of the upload, download and clone target pods
+	UploadServer string `json:"uploadServer,omitempty"`
 }
 
+// CDIComponentResources defines the resource requirements of the containers of the CDI control-plane deployments,
+// the defaults of a component are used if its requirements are not set
+type CDIComponentResources struct {
+	// Controller is the resource requirements of cdi-deployment
+	Controller *corev1.ResourceRequirements `json:"controller,omitempty"`
+	// APIServer is the resource requirements of cdi-apiserver
+	APIServer *corev1.ResourceRequirements `json:"apiServer,omitempty"`
+	// UploadProxy is the resource requirements of cdi-uploadproxy
+	UploadProxy *corev1.ResourceRequirements `json:"uploadProxy,omitempty"`
+}
+
+// UploadProxyExposure defines the Ingress or the Route created by the operator to expose the upload proxy
+type UploadProxyExposure struct {
+	// +kubebuilder:validation:Enum=Ingress;Route
+	// Type is the kind of object exposing the upload proxy
+	Type UploadProxyExposureType `json:"type"`
+	// Hostname is the external host name of the upload proxy
+	Hostname string `json:"hostname"`
+	// TLSSecretName is the name of a kubernetes.io/tls Secret of the CDI namespace, holding the certificate of the host name.
+	// The default certificate of the ingress controller or of the router is used if not set
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// IngressClassName is the class of the Ingress, the default class is used if not set
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Annotations are added to the Ingress or to the Route, for the settings of the ingress controller or of the router
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// UploadProxyExposureType is the kind of object exposing the upload proxy
+type UploadProxyExposureType string
+
+const (
+	// UploadProxyExposureIngress exposes the upload proxy with an Ingress
+	UploadProxyExposureIngress UploadProxyExposureType = "Ingress"
+
+	// UploadProxyExposureRoute exposes the upload proxy with an OpenShift Route
+	UploadProxyExposureRoute UploadProxyExposureType = "Route"
+)
+
 // CDICloneStrategy defines the preferred method for performing a CDI clone (override snapshot?)
 type CDICloneStrategy string
 
@@ -325,6 +1330,9 @@ const (
 
 	// CloneStrategySnapshot specifies snapshot-based copying
 	CloneStrategySnapshot = "snapshot"
+
+	// CloneStrategyCsiClone specifies copying with the volume cloning of the CSI driver
+	CloneStrategyCsiClone = "csi-clone"
 )
 
 // CDIUninstallStrategy defines the state to leave CDI on uninstall
@@ -338,6 +1346,9 @@ const (
 	CDIUninstallStrategyBlockUninstallIfWorkloadsExist CDIUninstallStrategy = "BlockUninstallIfWorkloadsExist"
 )
 
+// CDIPriorityClass defines the priority class of the CDI control plane
+type CDIPriorityClass string
+
 // CDIPhase is the current phase of the CDI deployment
 type CDIPhase string
 
@@ -391,16 +1402,142 @@ type FilesystemOverhead struct {
 type CDIConfigSpec struct {
 	// Override the URL used when uploading to a DataVolume
 	UploadProxyURLOverride *string `json:"uploadProxyURLOverride,omitempty"`
+	// UploadProxyClientCAConfigMap is the name of a ConfigMap in the CDI namespace holding, in its ca-bundle.crt key, the CA bundle of the client certificates the upload proxy requires in addition to the upload token. If not set client certificates are not required
+	// +optional
+	UploadProxyClientCAConfigMap *string `json:"uploadProxyClientCAConfigMap,omitempty"`
+	// UploadProxyRateLimits limits the concurrent uploads and the bandwidth of the uploads through the upload proxy, per namespace and per PVC
+	// +optional
+	UploadProxyRateLimits *UploadProxyRateLimits `json:"uploadProxyRateLimits,omitempty"`
+	// UploadTokenTTL is the lifetime of the upload and download tokens issued by the CDI API server, 5 minutes if not set. Tokens can be renewed before they expire
+	// +optional
+	UploadTokenTTL *metav1.Duration `json:"uploadTokenTTL,omitempty"`
 	// Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space
 	ScratchSpaceStorageClass *string `json:"scratchSpaceStorageClass,omitempty"`
 	// ResourceRequirements describes the compute resource requirements.
 	PodResourceRequirements *corev1.ResourceRequirements `json:"podResourceRequirements,omitempty"`
+	// PodPriorityClassName is the default priority class of the importer, upload and clone pods
+	PodPriorityClassName *string `json:"podPriorityClassName,omitempty"`
+	// MaxParallelImports is the maximum number of importer pods running at the same time in the cluster, the other imports wait in the Pending phase
+	MaxParallelImports *int32 `json:"maxParallelImports,omitempty"`
+	// ImportRetryPolicy is the default retry policy of the failed imports, the importer pods restart with the backoff of the kubelet without it
+	ImportRetryPolicy *ImportRetryPolicy `json:"importRetryPolicy,omitempty"`
+	// DataVolumeTTLSeconds is the time in seconds after the completion of a DataVolume before it is deleted, its PVC is kept. DataVolumes whose PVC is used by a pod or which are referenced by a VirtualMachine are kept until they are not anymore. The DataVolumes are kept if not set
+	// +optional
+	DataVolumeTTLSeconds *int32 `json:"dataVolumeTTLSeconds,omitempty"`
 	// FeatureGates are a list of specific enabled feature gates
 	FeatureGates []string `json:"featureGates,omitempty"`
 	// FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)
 	FilesystemOverhead *FilesystemOverhead `json:"filesystemOverhead,omitempty"`
 	// Preallocation controls whether storage for DataVolumes should be allocated in advance.
 	Preallocation *bool `json:"preallocation,omitempty"`
+	// TLSConfig controls the minimum TLS version and the cipher suites used by the CDI servers and the http clients of the importer
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// Registries configures the mirrors and the insecure registries consulted by registry imports
+	// +optional
+	Registries []RegistryConfig `json:"registries,omitempty"`
+	// PodSecurity overrides the security context of the importer, upload server and clone source pods, which comply with the restricted Pod Security Standard by default
+	// +optional
+	PodSecurity *PodSecurityConfig `json:"podSecurity,omitempty"`
+	// FileSourceHostPathPrefixes are the directories of the nodes the host paths of File sources must be in, host paths are rejected if it is empty
+	// +optional
+	FileSourceHostPathPrefixes []string `json:"fileSourceHostPathPrefixes,omitempty"`
+}
+
+// PodSecurityConfig overrides the security context of the importer, upload server and clone source pods, for the clusters whose policies require custom settings
+type PodSecurityConfig struct {
+	// RunAsUser is the user the pods run as, 107 (qemu) if not set
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// SELinuxOptions are the SELinux options of the pods, those of the container runtime if not set
+	// +optional
+	SELinuxOptions *corev1.SELinuxOptions `json:"seLinuxOptions,omitempty"`
+	// SeccompProfile is the seccomp profile of the pods, runtime/default if not set
+	// +optional
+	SeccompProfile *string `json:"seccompProfile,omitempty"`
+}
+
+// RegistryConfig defines the mirrors of a registry, or of a namespace of a registry, tried before the registry itself
+type RegistryConfig struct {
+	// Registry is the host[:port] of the registry, optionally followed by a namespace, for instance quay.io/kubevirt. The configuration applies to the images under it
+	Registry string `json:"registry"`
+	// Insecure allows plain HTTP and TLS connections without certificate verification to the registry
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+	// Mirrors are tried in order before the registry, the images are pulled from the registry if none of them has the image
+	// +optional
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+}
+
+// RegistryMirror defines a mirror of a registry
+type RegistryMirror struct {
+	// Location is the host[:port] of the mirror, optionally followed by a namespace replacing the one of the registry, for instance mirror.example.com:5000/quay
+	Location string `json:"location"`
+	// Insecure allows plain HTTP and TLS connections without certificate verification to the mirror
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// ImportRetryPolicy defines how the failed imports are retried. The importer pod of a failed attempt is deleted, and a new one is created after a backoff doubling at every attempt
+type ImportRetryPolicy struct {
+	// MaxAttempts is the number of attempts of the import before the DataVolume fails for good, unlimited when unset
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+	// InitialBackoff is the delay before the first retry, 10s when unset
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// MaxBackoff caps the delay between two attempts, 5m when unset
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// DataVolumeScratchSpace defines the scratch space PVC of the pods populating a DataVolume
+type DataVolumeScratchSpace struct {
+	// StorageClassName of the scratch space PVC, the scratchSpaceStorageClass of the CDIConfig when unset
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// Size of the scratch space PVC, the size of the PVC of the DataVolume when unset
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// UploadProxyRateLimits defines the limits enforced by the upload proxy, so a tenant can't saturate the bandwidth of the cluster. Uploads over the concurrent limits are rejected with a 429 status, and the bandwidth limits are shared by the concurrent uploads
+type UploadProxyRateLimits struct {
+	// MaxConcurrentUploadsPerNamespace is the maximum number of uploads proxied at the same time to the PVCs of a namespace
+	// +optional
+	MaxConcurrentUploadsPerNamespace *int32 `json:"maxConcurrentUploadsPerNamespace,omitempty"`
+	// MaxConcurrentUploadsPerPVC is the maximum number of uploads proxied at the same time to a PVC, with any of its upload tokens
+	// +optional
+	MaxConcurrentUploadsPerPVC *int32 `json:"maxConcurrentUploadsPerPVC,omitempty"`
+	// MaxBandwidthPerNamespace is the maximum number of bytes per second uploaded to the PVCs of a namespace
+	// +optional
+	MaxBandwidthPerNamespace *resource.Quantity `json:"maxBandwidthPerNamespace,omitempty"`
+	// MaxBandwidthPerPVC is the maximum number of bytes per second uploaded to a PVC
+	// +optional
+	MaxBandwidthPerPVC *resource.Quantity `json:"maxBandwidthPerPVC,omitempty"`
+}
+
+// TLSProtocolVersion is a version of the TLS protocol
+type TLSProtocolVersion string
+
+const (
+	// VersionTLS10 is version 1.0 of the TLS protocol
+	VersionTLS10 TLSProtocolVersion = "VersionTLS10"
+	// VersionTLS11 is version 1.1 of the TLS protocol
+	VersionTLS11 TLSProtocolVersion = "VersionTLS11"
+	// VersionTLS12 is version 1.2 of the TLS protocol
+	VersionTLS12 TLSProtocolVersion = "VersionTLS12"
+	// VersionTLS13 is version 1.3 of the TLS protocol
+	VersionTLS13 TLSProtocolVersion = "VersionTLS13"
+)
+
+// TLSConfig defines the TLS settings of the CDI components
+type TLSConfig struct {
+	// MinVersion is the minimum TLS version, one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13. If not set the default of the component is used
+	// +optional
+	MinVersion TLSProtocolVersion `json:"minVersion,omitempty"`
+	// Ciphers is the list of allowed cipher suites using the IANA names, for instance TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The cipher suites of TLS 1.3 are not configurable. If not set the default of the component is used
+	// +optional
+	Ciphers []string `json:"ciphers,omitempty"`
 }
 
 //CDIConfigStatus provides the most recently observed status of the CDI Config resource
@@ -426,3 +1563,73 @@ type CDIConfigList struct {
 	// Items provides a list of CDIConfigs
 	Items []CDIConfig `json:"items"`
 }
+
+// this has to be here otherwise informer-gen doesn't recognize it
+// see https://github.com/kubernetes/code-generator/issues/59
+// +genclient:nonNamespaced
+
+// StorageProfile provides a CDI specific recommendation for storage parameters, one per StorageClass
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Provisioner",type="string",JSONPath=".status.provisioner",description="The provisioner of the StorageClass"
+// +kubebuilder:printcolumn:name="Clone Strategy",type="string",JSONPath=".status.cloneStrategy",description="The clone strategy of the StorageClass"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type StorageProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageProfileSpec   `json:"spec"`
+	Status StorageProfileStatus `json:"status,omitempty"`
+}
+
+//StorageProfileSpec defines specification for StorageProfile, the settings overriding the recommendations for the provisioner
+type StorageProfileSpec struct {
+	// CloneStrategy defines the preferred method for performing a CDI clone
+	// +kubebuilder:validation:Enum="copy";"snapshot";"csi-clone"
+	// +optional
+	CloneStrategy *CDICloneStrategy `json:"cloneStrategy,omitempty"`
+	// ClaimPropertySets is a provided set of properties applicable to PVC
+	// +optional
+	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass
+	// +optional
+	SnapshotClass *string `json:"snapshotClass,omitempty"`
+}
+
+//StorageProfileStatus provides the most recently observed status of the StorageProfile
+type StorageProfileStatus struct {
+	// The StorageClass name for which capabilities are defined
+	StorageClass *string `json:"storageClass,omitempty"`
+	// The Storage class provisioner plugin name
+	Provisioner *string `json:"provisioner,omitempty"`
+	// CloneStrategy defines the preferred method for performing a CDI clone
+	CloneStrategy *CDICloneStrategy `json:"cloneStrategy,omitempty"`
+	// ClaimPropertySets computed from the spec and detected in the system
+	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass
+	SnapshotClass *string `json:"snapshotClass,omitempty"`
+}
+
+// ClaimPropertySet is a set of properties applicable to PVC
+type ClaimPropertySet struct {
+	// AccessModes contains the desired access modes the volume should have.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// VolumeMode defines what type of volume is required by the claim.
+	// Value of Filesystem is implied when not included in claim spec.
+	// +optional
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+}
+
+//StorageProfileList provides the needed parameters to request a list of StorageProfile from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type StorageProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of StorageProfile
+	Items []StorageProfile `json:"items"`
+}
@@ -10,13 +10,30 @@ func (DataVolume) SwaggerDoc() map[string]string {
 
 func (DataVolumeSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                "DataVolumeSpec defines the DataVolume type specification",
-		"source":          "Source is the src of the data for the requested DataVolume",
-		"pvc":             "PVC is the PVC specification",
-		"contentType":     "DataVolumeContentType options: \"kubevirt\", \"archive\"\n+kubebuilder:validation:Enum=\"kubevirt\";\"archive\"",
-		"checkpoints":     "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
-		"finalCheckpoint": "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
-		"preallocation":   "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"":                        "DataVolumeSpec defines the DataVolume type specification",
+		"source":                  "Source is the src of the data for the requested DataVolume\n+optional",
+		"sourceRef":               "SourceRef is a reference to a DataSource, resolved into the source of the DataVolume when it is created\n+optional",
+		"pvc":                     "PVC is the PVC specification",
+		"contentType":             "DataVolumeContentType options: \"kubevirt\", \"archive\"\n+kubebuilder:validation:Enum=\"kubevirt\";\"archive\"",
+		"checkpoints":             "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
+		"finalCheckpoint":         "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
+		"preallocation":           "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"nodePlacement":           "NodePlacement restricts on which nodes the pods populating the DataVolume are scheduled, in addition to the workload placement of the CDI resource",
+		"priorityClassName":       "PriorityClassName of the pods populating the DataVolume, overrides the default pod priority class of the CDIConfig",
+		"podResourceRequirements": "PodResourceRequirements of the pods populating the DataVolume, overrides the resources of the podResourceRequirements of the CDIConfig",
+		"retryPolicy":             "RetryPolicy of the failed imports of the DataVolume, overrides the importRetryPolicy of the CDIConfig",
+		"importTimeout":           "ImportTimeout is the maximum duration of the import from the start of its first importer pod, after which the import is cancelled and the DataVolume fails",
+		"filesystemOverhead":      "FilesystemOverhead of the PVC of the DataVolume when using a Filesystem volume, a value between 0 and 1 overriding the filesystemOverhead of the CDIConfig",
+		"scratchSpace":            "ScratchSpace of the pods populating the DataVolume, overrides the scratchSpaceStorageClass of the CDIConfig",
+	}
+}
+
+func (DataVolumeSourceRef) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceRef is a reference to the source of a DataVolume",
+		"kind":      "Kind of the source, only DataSource is supported",
+		"namespace": "Namespace of the source, the namespace of the DataVolume when unset\n+optional",
+		"name":      "Name of the source",
 	}
 }
 
@@ -30,7 +47,7 @@ func (DataVolumeCheckpoint) SwaggerDoc() map[string]string {
 
 func (DataVolumeSource) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry or an existing PVC",
+		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, NFS, SMB, Rsync, iSCSI, Ceph RBD, a file on a host path or PVC, libvirt, Imageio, Glance, Proxmox, Hyper-V, S3, AWS snapshot, GCS, GCE image, Azure Blob, Azure disk, Registry, a VolumeSnapshot or an existing PVC, cloned or copied over the network",
 	}
 }
 
@@ -42,6 +59,14 @@ func (DataVolumeSourcePVC) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataVolumeSourceSnapshot) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceSnapshot provides the parameters to create a Data Volume from a VolumeSnapshot",
+		"namespace": "The namespace of the source VolumeSnapshot",
+		"name":      "The name of the source VolumeSnapshot",
+	}
+}
+
 func (DataVolumeBlankImage) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "DataVolumeBlankImage provides the parameters to create a new raw blank image for the PVC",
@@ -56,9 +81,65 @@ func (DataVolumeSourceUpload) SwaggerDoc() map[string]string {
 
 func (DataVolumeSourceS3) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":          "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
-		"url":       "URL is the url of the S3 source",
-		"secretRef": "SecretRef provides the secret reference needed to access the S3 source",
+		"":                        "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
+		"url":                     "URL is the url of the S3 source",
+		"secretRef":               "SecretRef provides the secret reference needed to access the S3 source",
+		"serviceAccountName":      "ServiceAccountName is the service account the importer pod runs with. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account\n+optional",
+		"region":                  "Region is the region of the bucket, by default the region is derived from the host of the URL\n+optional",
+		"forcePathStyle":          "ForcePathStyle addresses the bucket in the path of the request instead of the host name, defaults to true\n+optional",
+		"sseCustomerKeySecretRef": "SSECustomerKeySecretRef is the secret containing the base64 encoded 256-bit AES key in the sseCustomerKey key, used to read objects encrypted with SSE-C\n+optional",
+		"segments":                "Segments is the number of concurrent ranged requests used to download the object\n+optional",
+		"segmentSize":             "SegmentSize is the size of each ranged request, if not set the object is split evenly between the segments\n+optional",
+		"objectVersionId":         "ObjectVersionID is the version of the object to import from a versioned bucket, by default the latest version is imported\n+optional",
+	}
+}
+
+func (DataVolumeSourceAWSSnapshot) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "DataVolumeSourceAWSSnapshot provides the parameters to create a Data Volume from an EBS snapshot or AMI, using the EBS direct APIs",
+		"snapshotId":         "SnapshotID is the ID of the EBS snapshot, or the ID of an AMI whose root device snapshot is imported. With checkpoints the snapshot of the current checkpoint is imported instead",
+		"region":             "Region is the region of the snapshot",
+		"secretRef":          "SecretRef provides the secret containing the accessKeyId and secretKey. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account\n+optional",
+		"serviceAccountName": "ServiceAccountName is the service account the importer pod runs with\n+optional",
+	}
+}
+
+func (DataVolumeSourceGCS) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "DataVolumeSourceGCS provides the parameters to create a Data Volume from a Google Cloud Storage object",
+		"url":                "URL is the url of the GCS object, gs://bucket/object",
+		"secretRef":          "SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity\n+optional",
+		"serviceAccountName": "ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity\n+optional",
+	}
+}
+
+func (DataVolumeSourceGCEImage) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "DataVolumeSourceGCEImage provides the parameters to create a Data Volume from a Google Compute Engine image",
+		"image":              "Image is the image to import, projects/<project>/global/images/<name> or projects/<project>/global/images/family/<family>",
+		"exportUrl":          "ExportURL is the gs:// url of the image exported with gcloud compute images export, required if the image was not created from a tar.gz in GCS\n+optional",
+		"secretRef":          "SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity\n+optional",
+		"serviceAccountName": "ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity\n+optional",
+	}
+}
+
+func (DataVolumeSourceAzureBlob) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "DataVolumeSourceAzureBlob provides the parameters to create a Data Volume from an Azure Blob Storage blob",
+		"url":                "URL is the url of the blob, https://account.blob.core.windows.net/container/blob",
+		"secretRef":          "SecretRef is the secret containing either a SAS token in the sasToken key or the storage account key in the accountKey key. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity\n+optional",
+		"serviceAccountName": "ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity\n+optional",
+		"segments":           "Segments is the number of concurrent ranged requests used to download the blob\n+optional",
+		"segmentSize":        "SegmentSize is the size of each ranged request, if not set the blob is split evenly between the segments\n+optional",
+	}
+}
+
+func (DataVolumeSourceAzureDisk) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "DataVolumeSourceAzureDisk provides the parameters to create a Data Volume from an Azure managed disk or snapshot",
+		"resourceId":         "ResourceID is the resource ID of the managed disk or snapshot, /subscriptions/<subscription>/resourceGroups/<group>/providers/Microsoft.Compute/snapshots/<name>",
+		"secretRef":          "SecretRef is the secret containing the tenantId, clientId and clientSecret of a service principal. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity\n+optional",
+		"serviceAccountName": "ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity\n+optional",
 	}
 }
 
@@ -68,15 +149,41 @@ func (DataVolumeSourceRegistry) SwaggerDoc() map[string]string {
 		"url":           "URL is the url of the Docker registry source",
 		"secretRef":     "SecretRef provides the secret reference needed to access the Registry source",
 		"certConfigMap": "CertConfigMap provides a reference to the Registry certs",
+		"platform":      "Platform selects the image of a manifest list as os/arch[/variant], for instance linux/arm64. If not set the image of the platform of the node running the import is selected\n+optional",
+		"signature":     "Signature verifies the cosign signature of the image before it is imported, the import fails if no signature verifies\n+optional",
+	}
+}
+
+func (DataVolumeSourceRegistrySignature) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceRegistrySignature verifies the cosign signatures of a registry image, with a public key, or keyless with the certificate Fulcio issued to the identity that signed the image",
+		"configMap": "ConfigMap is the name of a ConfigMap with the PEM public key verifying the signatures in its cosign.pub key, or for keyless signatures with the PEM Fulcio root certificates in its fulcio.crt.pem key and the PEM Rekor public key in its rekor.pub key",
+		"identity":  "Identity is the email or URI of the signer of keyless signatures\n+optional",
+		"issuer":    "Issuer is the URL of the OIDC issuer that authenticated the signer of keyless signatures\n+optional",
 	}
 }
 
 func (DataVolumeSourceHTTP) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
-		"url":           "URL is the URL of the http(s) endpoint",
-		"secretRef":     "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded\n+optional",
-		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"":                    "DataVolumeSourceHTTP can be either an http, https, ftp or ftps endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
+		"url":                 "URL is the URL of the http(s) or ftp(s) endpoint",
+		"secretRef":           "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded\n+optional",
+		"ftpPassive":          "FTPPassive selects passive mode for ftp and ftps URLs, the default, set it to false to use active mode where the server connects back to the importer pod\n+optional",
+		"tokenSecretRef":      "TokenSecretRef A Secret reference, the secret should contain a bearer token (token) base64 encoded, that is sent in the Authorization header\n+optional",
+		"oauth2":              "OAuth2 provides the parameters to retrieve bearer tokens using the OAuth2 client credentials flow\n+optional",
+		"certConfigMap":       "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"clientCertSecretRef": "ClientCertSecretRef A Secret reference of type kubernetes.io/tls, the client certificate (tls.crt) and key (tls.key) are presented to the endpoint\n+optional",
+		"segments":            "Segments is the number of concurrent ranged requests used to download the image into scratch space before conversion, requires the server to support ranges\n+optional",
+		"segmentSize":         "SegmentSize is the size of each ranged request, if not set the image is split evenly between the segments\n+optional",
+	}
+}
+
+func (DataVolumeSourceHTTPOAuth2) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceHTTPOAuth2 provides the parameters to retrieve bearer tokens from an OAuth2 token endpoint using the client credentials flow",
+		"tokenURL":  "TokenURL is the URL of the OAuth2 token endpoint",
+		"secretRef": "SecretRef A Secret reference, the secret should contain clientId and clientSecret base64 encoded",
+		"scopes":    "Scopes is the list of scopes to request\n+optional",
 	}
 }
 
@@ -90,6 +197,115 @@ func (DataVolumeSourceImageIO) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataVolumeSourceGlance) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceGlance provides the parameters to create a Data Volume from an OpenStack Glance image",
+		"authUrl":       "AuthURL is the URL of the Keystone v3 identity service, for instance https://keystone.example.com:5000/v3",
+		"project":       "Project is the name of the project the image is visible to",
+		"domain":        "Domain is the name of the domain of the user and the project, defaults to Default\n+optional",
+		"region":        "Region is the region of the image service in the service catalog, if not set the first public image endpoint is used\n+optional",
+		"imageId":       "ImageID is the UUID of the image",
+		"secretRef":     "SecretRef provides the secret reference needed to access Keystone, the secret should contain accessKeyId (user name) and secretKey (password)",
+		"certConfigMap": "CertConfigMap provides a reference to the CA cert of Keystone and Glance\n+optional",
+	}
+}
+
+func (DataVolumeSourceProxmox) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceProxmox provides the parameters to create a Data Volume from the disk of a Proxmox VE virtual machine",
+		"url":           "URL is the URL of the API of a node of the Proxmox VE cluster, for instance https://pve.example.com:8006",
+		"node":          "Node is the name of the node of the VM, if not set the node is looked up in the cluster resources\n+optional",
+		"vmId":          "VMID is the ID of the VM, the VM must be stopped",
+		"disk":          "Disk is the key of the disk in the configuration of the VM, for instance scsi0 or virtio1",
+		"secretRef":     "SecretRef provides the secret reference needed to access the API, the secret should contain accessKeyId (API token ID, user@realm!token) and secretKey (API token secret)",
+		"certConfigMap": "CertConfigMap provides a reference to the CA cert of the API\n+optional",
+	}
+}
+
+func (DataVolumeSourceHyperV) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceHyperV provides the parameters to create a Data Volume from a VHDX or VHD disk of a Hyper-V virtual machine on an SMB share",
+		"server":    "Server is the host name or address of the SMB server",
+		"share":     "Share is the name of the SMB share",
+		"path":      "Path is the path of the disk in the share, for instance VMs/fedora/Virtual Hard Disks/fedora.vhdx",
+		"secretRef": "SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set\n+optional",
+		"domain":    "Domain is the domain or workgroup of the user\n+optional",
+	}
+}
+
+func (DataVolumeSourceNFS) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "DataVolumeSourceNFS provides the parameters to create a Data Volume from an image file on an NFS export",
+		"server": "Server is the host name or address of the NFS server",
+		"path":   "Path is the absolute path of the image file on the server, for instance /export/templates/fedora.qcow2, the importer mounts its directory read-only",
+	}
+}
+
+func (DataVolumeSourceSMB) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceSMB provides the parameters to create a Data Volume from an image file on an SMB/CIFS share",
+		"server":    "Server is the host name or address of the SMB server",
+		"share":     "Share is the name of the SMB share",
+		"path":      "Path is the path of the image file in the share, for instance Images/fedora.qcow2",
+		"secretRef": "SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set\n+optional",
+		"domain":    "Domain is the domain or workgroup of the user\n+optional",
+	}
+}
+
+func (DataVolumeSourceRsync) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceRsync provides the parameters to create a Data Volume from an image file pulled with rsync over SSH",
+		"host":      "Host is the host name or address of the SSH server, optionally followed by :port",
+		"module":    "Module is the rsync module containing the image, read through a single-use rsync daemon started over SSH. The path is absolute on the server if not set\n+optional",
+		"path":      "Path is the path of the image file, relative to the module if one is set, for instance templates/fedora.qcow2",
+		"secretRef": "SecretRef provides the secret reference needed to log in, the secret should contain accessKeyId (user name) and secretKey (SSH private key)",
+		"hostKey":   "HostKey is the public key of the SSH server in known_hosts format, for instance \"ssh-ed25519 AAAAC3...\", the host key is not verified if not set\n+optional",
+	}
+}
+
+func (DataVolumeSourceISCSI) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataVolumeSourceISCSI provides the parameters to create a Data Volume from a LUN of an iSCSI target",
+		"portal":        "Portal is the address of the iSCSI portal, optionally followed by :port, the default port is 3260",
+		"iqn":           "IQN is the qualified name of the iSCSI target, for instance iqn.2003-01.org.linux-iscsi.san:p2v",
+		"lun":           "LUN is the number of the logical unit to import, 0 by default\n+optional",
+		"secretRef":     "SecretRef provides the secret reference needed for CHAP authentication, the secret should contain accessKeyId (user name) and secretKey (password), no authentication is used if not set\n+optional",
+		"initiatorName": "InitiatorName is the qualified name of the initiator the importer logs in with, for targets restricting access by initiator\n+optional",
+	}
+}
+
+func (DataVolumeSourceRBD) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceRBD provides the parameters to create a Data Volume from an RBD image of an external Ceph cluster",
+		"monitors":  "Monitors are the addresses of the Ceph monitors, optionally followed by :port",
+		"pool":      "Pool is the name of the pool containing the image",
+		"image":     "Image is the name of the RBD image",
+		"snapshot":  "Snapshot is the name of a snapshot of the image to import instead of the image itself, recommended if the image is in use\n+optional",
+		"secretRef": "SecretRef provides the secret reference needed to access the cluster, the secret should contain accessKeyId (Ceph user id, for instance admin) and secretKey (key of the user from its keyring)",
+	}
+}
+
+func (DataVolumeSourceFile) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceFile provides the parameters to create a Data Volume from an image file on a directory of a node or on an existing PVC, which the importer pod mounts read-only",
+		"hostPath":  "HostPath is the absolute path of a directory of the node containing the image file, it requires the FileSourceHostPath feature gate\n+optional",
+		"nodeName":  "NodeName is the name of the node with the host path, the importer pod runs on this node\n+optional",
+		"claimName": "ClaimName is the name of an existing PVC in the namespace of the Data Volume containing the image file\n+optional",
+		"path":      "Path is the path of the image file relative to the host path or to the root of the PVC, for instance images/fedora.qcow2",
+	}
+}
+
+func (DataVolumeSourceLibvirt) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceLibvirt provides the parameters to create a Data Volume from a disk of a domain of a remote libvirt host",
+		"uri":       "URI is the libvirt connection URI of the host, for instance qemu+ssh://kvm01.example.com/system",
+		"domain":    "Domain is the name of the domain",
+		"disk":      "Disk is the target device of the disk in the domain, for instance vda",
+		"secretRef": "SecretRef provides the secret reference needed to log in to the host, the secret should contain accessKeyId (user name) and secretKey (SSH private key)",
+		"hostKey":   "HostKey is the public key of the host in known_hosts format, for instance ssh-ed25519 AAAA..., the host key is not verified if empty\n+optional",
+	}
+}
+
 func (DataVolumeSourceVDDK) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":            "DataVolumeSourceVDDK provides the parameters to create a Data Volume from a Vmware source",
@@ -98,6 +314,7 @@ func (DataVolumeSourceVDDK) SwaggerDoc() map[string]string {
 		"backingFile": "BackingFile is the path to the virtual hard disk to migrate from vCenter/ESXi",
 		"thumbprint":  "Thumbprint is the certificate thumbprint of the vCenter or ESXi host",
 		"secretRef":   "SecretRef provides a reference to a secret containing the username and password needed to access the vCenter or ESXi host",
+		"connections": "Connections is the number of concurrent NBD connections used to copy the disk, each connection opens a VDDK session on the host\n+optional",
 	}
 }
 
@@ -105,7 +322,41 @@ func (DataVolumeStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":             "DataVolumeStatus contains the current status of the DataVolume",
 		"phase":        "Phase is the current phase of the data volume",
+		"transfer":     "Transfer is the amount transferred, the throughput and the estimated completion of the import, when the importer reports them\n+optional",
+		"sourceInfo":   "SourceInfo is the format, compression and sizes of the imported source, as detected by the importer\n+optional",
 		"restartCount": "RestartCount is the number of times the pod populating the DataVolume has restarted",
+		"checkpoints":  "Checkpoints is the status of the copy of each checkpoint of a multi-stage import\n+optional",
+	}
+}
+
+func (DataVolumeTransferStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "DataVolumeTransferStatus is the rate and the estimated completion of the transfer of a DataVolume.",
+		"bytesTransferred":        "BytesTransferred is the number of bytes read from the source so far.",
+		"bytesPerSecond":          "BytesPerSecond is the current transfer rate, 0 when the transfer is stalled.",
+		"estimatedCompletionTime": "EstimatedCompletionTime is the time the transfer is expected to complete at the current rate, unset when unknown.\n+optional",
+	}
+}
+
+func (DataVolumeSourceInfo) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataVolumeSourceInfo describes the source of a completed import, as detected by the importer.",
+		"format":         "Format is the disk image format of the source, like raw, qcow2, vmdk or vhdx.\n+optional",
+		"compression":    "Compression is the compression of the source, gz or xz, empty if not compressed.\n+optional",
+		"virtualSize":    "VirtualSize is the size of the disk in the source image.\n+optional",
+		"downloadedSize": "DownloadedSize is the size of the source as downloaded.\n+optional",
+	}
+}
+
+func (DataVolumeCheckpointStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataVolumeCheckpointStatus is the status of the copy of one checkpoint of a multi-stage import.",
+		"previous":       "Previous is the identifier of the checkpoint the changes are copied from, empty for the base copy.\n+optional",
+		"current":        "Current is the identifier of the copied checkpoint.",
+		"phase":          "Phase is the phase of the copy of the checkpoint, Pending, ImportInProgress or Succeeded.",
+		"progress":       "Progress is the progress of the copy of the checkpoint.",
+		"startTime":      "StartTime is the time the copy of the checkpoint started.\n+optional",
+		"completionTime": "CompletionTime is the time the copy of the checkpoint completed.\n+optional",
 	}
 }
 
@@ -122,6 +373,285 @@ func (DataVolumeCondition) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataExport) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "DataExport writes the disk of a PVC or DataVolume to a target outside of the cluster\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=dx;dxs\n+kubebuilder:printcolumn:name=\"Phase\",type=\"string\",JSONPath=\".status.phase\",description=\"The phase the export is in\"\n+kubebuilder:printcolumn:name=\"Progress\",type=\"string\",JSONPath=\".status.progress\",description=\"Export progress in percentage if known, N/A otherwise\"\n+kubebuilder:printcolumn:name=\"Restarts\",type=\"integer\",JSONPath=\".status.restartCount\",description=\"The number of times the export has been restarted.\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (DataExportSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "DataExportSpec defines the DataExport type specification",
+		"source":                  "Source is the PVC, DataVolume or VolumeSnapshot whose disk is exported",
+		"target":                  "Target is where the disk is written to",
+		"format":                  "Format is the format of the exported disk image, qcow2 or raw, defaults to qcow2\n+kubebuilder:validation:Enum=\"qcow2\";\"raw\"\n+optional",
+		"compression":             "Compression is the compression of the clusters of qcow2 images, zlib, zstd or none, defaults to zlib\n+kubebuilder:validation:Enum=\"zlib\";\"zstd\";\"none\"\n+optional",
+		"ttlSecondsAfterFinished": "TTLSecondsAfterFinished is the time the DataExport is kept once it succeeded before it is deleted, it is kept until deleted when unset\n+optional",
+	}
+}
+
+func (DataExportSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataExportSource is the PVC, DataVolume or VolumeSnapshot, in the namespace of the DataExport, whose disk is exported",
+		"pvc":            "PVC is the name of the exported PVC\n+optional",
+		"dataVolume":     "DataVolume is the name of the exported DataVolume, the export starts once the DataVolume succeeded\n+optional",
+		"volumeSnapshot": "VolumeSnapshot is the name of the exported VolumeSnapshot, the disk is read from a temporary PVC restored from the snapshot\n+optional",
+	}
+}
+
+func (DataExportTarget) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "DataExportTarget is the target of a DataExport",
+		"s3":       "S3 writes the disk to an object of an S3 bucket\n+optional",
+		"http":     "HTTP writes the disk with an HTTP PUT, to a WebDAV server or an artifact store\n+optional",
+		"registry": "Registry pushes the disk as a containerDisk image to a container registry\n+optional",
+		"cdi":      "CDI uploads the disk to a DataVolume of another cluster, resuming after network interruptions\n+optional",
+	}
+}
+
+func (DataExportTargetS3) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataExportTargetS3 provides the parameters to write the exported disk to an S3 object, with a multipart upload",
+		"url":       "URL is the url of the S3 object, for instance https://s3.us-east-1.amazonaws.com/bucket/fedora.qcow2",
+		"secretRef": "SecretRef is the secret containing the accessKeyId and secretKey used to write the object. Without a secretRef the credentials of the pod are used\n+optional",
+		"region":    "Region is the region of the bucket, by default the region is derived from the host of the URL\n+optional",
+		"partSize":  "PartSize is the size of the parts of the multipart upload, defaults to 64Mi\n+optional",
+	}
+}
+
+func (DataExportTargetHTTP) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataExportTargetHTTP provides the parameters to write the exported disk with an HTTP PUT",
+		"url":           "URL is the url the disk is written to, for instance https://nexus.example.com/repository/disks/fedora.qcow2",
+		"secretRef":     "SecretRef is the secret containing the accessKeyId (user name) and secretKey (password) of the basic authentication of the server\n+optional",
+		"certConfigMap": "CertConfigMap is a configmap containing the Certificate Authorities of the server\n+optional",
+	}
+}
+
+func (DataExportTargetRegistry) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataExportTargetRegistry provides the parameters to push the exported disk as a containerDisk image",
+		"url":           "URL is the image the disk is pushed to, for instance docker://quay.io/kubevirt/fedora:35",
+		"secretRef":     "SecretRef is a secret of type kubernetes.io/dockerconfigjson with the credentials of the registry\n+optional",
+		"certConfigMap": "CertConfigMap is a configmap containing the Certificate Authorities of the registry\n+optional",
+	}
+}
+
+func (DataExportTargetCDI) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataExportTargetCDI provides the parameters to upload the exported disk to an upload DataVolume of another cluster,\nthrough its upload proxy with the tus resumable upload protocol",
+		"url":            "URL is the url of the upload proxy of the other cluster, for instance https://cdi-uploadproxy.dr.example.com",
+		"tokenSecretRef": "TokenSecretRef is the secret containing the upload token of the target DataVolume in its token key. The token is read again before each request, so it can be renewed in the secret during long exports",
+		"certConfigMap":  "CertConfigMap is a configmap containing the Certificate Authorities of the upload proxy\n+optional",
+	}
+}
+
+func (DataExportStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataExportStatus is the status of a DataExport",
+		"phase":          "Phase is the current phase of the export",
+		"progress":       "Progress is the percentage of the disk written to the target",
+		"restartCount":   "RestartCount is the number of times the export pod has restarted",
+		"message":        "Message explains why the export is pending, or the last failure of the export pod\n+optional",
+		"completionTime": "CompletionTime is the time the export completed\n+optional",
+		"conditions":     "Conditions are the SourceReady, Running and Ready conditions of the export\n+optional",
+	}
+}
+
+func (DataExportList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DataExportList provides the needed parameters to do request a list of DataExports from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of DataExports",
+	}
+}
+
+func (CloneGrant) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "CloneGrant allows the DataVolumes of a target namespace to clone the PVCs of the namespace of the grant, in place of\nthe short-lived clone token of the DataVolumes\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=cg;cgs\n+kubebuilder:printcolumn:name=\"Target\",type=\"string\",JSONPath=\".spec.targetNamespace\",description=\"The namespace allowed to clone\"\n+kubebuilder:printcolumn:name=\"Expiration\",type=\"string\",JSONPath=\".spec.expirationTime\",description=\"The time the grant expires\"\n+kubebuilder:printcolumn:name=\"Clones\",type=\"integer\",JSONPath=\".status.cloneCount\",description=\"The number of clones the grant allowed\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (CloneGrantSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                "CloneGrantSpec defines the CloneGrant type specification",
+		"targetNamespace": "TargetNamespace is the namespace whose DataVolumes may clone the PVCs of the namespace of the grant",
+		"selector":        "Selector selects the PVCs that may be cloned, all the PVCs of the namespace may be cloned when unset\n+optional",
+		"expirationTime":  "ExpirationTime is the time after which the grant no longer allows clones, the grant does not expire when unset\n+optional",
+	}
+}
+
+func (CloneGrantStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "CloneGrantStatus is the status of a CloneGrant, recording the clones it allowed",
+		"cloneCount":    "CloneCount is the number of clones the grant allowed",
+		"lastCloneTime": "LastCloneTime is the time the grant last allowed a clone\n+optional",
+		"lastSource":    "LastSource is the name of the source PVC of the last clone the grant allowed\n+optional",
+		"lastTarget":    "LastTarget is the namespace/name of the target PVC of the last clone the grant allowed\n+optional",
+	}
+}
+
+func (CloneGrantList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "CloneGrantList provides the needed parameters to do request a list of CloneGrants from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of CloneGrants",
+	}
+}
+
+func (VolumeImportSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VolumeImportSource populates the PVCs referencing it in their dataSource with an import, without a DataVolume\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=vis\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (VolumeImportSourceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VolumeImportSourceSpec defines the VolumeImportSource type specification",
+		"source":        "Source is the source of the data imported into the PVCs, any DataVolume source but pvc, pvcNetwork, snapshot and upload",
+		"contentType":   "ContentType is the content type of the imported data, kubevirt or archive, defaults to kubevirt\n+kubebuilder:validation:Enum=\"kubevirt\";\"archive\"\n+optional",
+		"preallocation": "Preallocation preallocates the space of the PVCs when true\n+optional",
+	}
+}
+
+func (VolumeImportSourceList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "VolumeImportSourceList provides the needed parameters to do request a list of VolumeImportSources from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of VolumeImportSources",
+	}
+}
+
+func (VolumeUploadSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VolumeUploadSource populates the PVCs referencing it in their dataSource with an upload, without a DataVolume\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=vus\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (VolumeUploadSourceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VolumeUploadSourceSpec defines the VolumeUploadSource type specification",
+		"preallocation": "Preallocation preallocates the space of the PVCs when true\n+optional",
+	}
+}
+
+func (VolumeUploadSourceList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "VolumeUploadSourceList provides the needed parameters to do request a list of VolumeUploadSources from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of VolumeUploadSources",
+	}
+}
+
+func (VolumeCloneSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VolumeCloneSource populates the PVCs referencing it in their dataSource with a clone, without a DataVolume\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=vcs\n+kubebuilder:printcolumn:name=\"Source\",type=\"string\",JSONPath=\".spec.source.name\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (VolumeCloneSourceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VolumeCloneSourceSpec defines the VolumeCloneSource type specification",
+		"source":        "Source is the PVC cloned into the PVCs, in their namespace",
+		"preallocation": "Preallocation preallocates the space of the PVCs of host-assisted clones when true\n+optional",
+	}
+}
+
+func (VolumeCloneSourceList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "VolumeCloneSourceList provides the needed parameters to do request a list of VolumeCloneSources from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of VolumeCloneSources",
+	}
+}
+
+func (DataImportCron) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "DataImportCron polls the registry, http or s3 source of a DataVolume template on a schedule and imports each new image as a\ngolden image, keeping the latest imports and pointing a DataSource at the PVC of the latest one\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=dic;dics\n+kubebuilder:printcolumn:name=\"Schedule\",type=\"string\",JSONPath=\".spec.schedule\",description=\"The schedule of the polls of the source\"\n+kubebuilder:printcolumn:name=\"DataSource\",type=\"string\",JSONPath=\".spec.managedDataSource\",description=\"The DataSource pointing at the latest import\"\n+kubebuilder:printcolumn:name=\"Last Import\",type=\"date\",JSONPath=\".status.lastImportTimestamp\",description=\"The time of the last import\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (DataImportCronSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "DataImportCronSpec defines the DataImportCron type specification",
+		"template":          "Template is the DataVolume of the imports, its source is polled for new images",
+		"schedule":          "Schedule is when the source is polled, in the cron format of CronJobs",
+		"garbageCollect":    "GarbageCollect is Outdated, the default, to delete the imports which are not retained anymore, or Never to keep all the imports\n+kubebuilder:validation:Enum=\"Outdated\";\"Never\"\n+optional",
+		"importsToKeep":     "ImportsToKeep is the number of the latest imports retained, 3 by default\n+optional",
+		"retentionPeriod":   "RetentionPeriod retains the imports newer than the period as well, whatever their number\n+optional",
+		"managedDataSource": "ManagedDataSource is the name of the DataSource pointing at the PVC of the latest import, in the namespace of the DataImportCron",
+		"pinnedDigest":      "PinnedDigest pins the DataSource to the import of the digest, which is imported if it is not retained. New images are still imported, the DataSource points at the latest one again once the pin is removed\n+optional",
+		"notification":      "Notification is the webhook notified when the DataSource points at a new golden image\n+optional",
+	}
+}
+
+func (DataImportCronNotification) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DataImportCronNotification is the webhook notified of the new golden images of a DataImportCron",
+		"url":           "URL is the http(s) endpoint the new golden images are posted to",
+		"certConfigMap": "CertConfigMap is a ConfigMap, in the namespace of the DataImportCron, with the CA bundle verifying the certificate of the endpoint\n+optional",
+	}
+}
+
+func (DataImportCronStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                       "DataImportCronStatus is the status of a DataImportCron",
+		"imports":                "Imports are the imports of the DataImportCron which are not garbage collected yet, the latest first\n+optional",
+		"lastExecutionTimestamp": "LastExecutionTimestamp is the time the source was last polled\n+optional",
+		"lastImportTimestamp":    "LastImportTimestamp is the time the latest import succeeded\n+optional",
+		"currentDigest":          "CurrentDigest is the digest of the import the DataSource points at\n+optional",
+		"notifiedDigest":         "NotifiedDigest is the digest of the last golden image the DataImportCron notified of\n+optional",
+	}
+}
+
+func (DataImportCronImport) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DataImportCronImport is an import of a new image of the source of a DataImportCron",
+		"dataVolumeName": "DataVolumeName is the name of the DataVolume of the import, and of its PVC",
+		"digest":         "Digest is the digest of the imported image, or of the validators of the imported http or s3 object",
+		"timestamp":      "Timestamp is the time the import started, the retention period of the import starts then",
+	}
+}
+
+func (DataImportCronList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DataImportCronList provides the needed parameters to do request a list of DataImportCrons from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of DataImportCrons",
+	}
+}
+
+func (DataSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "DataSource references the PVC of a golden image, so its consumers keep the same reference when the PVC is replaced\nby a newer import\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:shortName=das\n+kubebuilder:printcolumn:name=\"PVC\",type=\"string\",JSONPath=\".status.source.pvc.name\",description=\"The PVC of the ready source\"\n+kubebuilder:printcolumn:name=\"Ready\",type=\"string\",JSONPath=\".status.conditions[?(@.type=='Ready')].status\",description=\"Whether a source is ready\"\n+kubebuilder:printcolumn:name=\"Reason\",type=\"string\",JSONPath=\".status.conditions[?(@.type=='Ready')].reason\",description=\"Why the source is ready or not\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+		"status": "+optional",
+	}
+}
+
+func (DataSourceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataSourceSpec defines the DataSource type specification",
+		"source":    "Source is the source of the data referenced by the DataSource",
+		"fallbacks": "Fallbacks are the sources used, in their order, while the source is not ready, like when its golden image is missing or being replaced\n+optional",
+	}
+}
+
+func (DataSourceStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "DataSourceStatus is the status of a DataSource",
+		"source":     "Source is the first ready source among the source and the fallbacks, the one its consumers clone\n+optional",
+		"conditions": "Conditions holds the Ready condition of the DataSource, its reason tells whether the source or a fallback is used\n+optional",
+	}
+}
+
+func (DataSourceSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":    "DataSourceSource is the source of the data referenced by a DataSource",
+		"pvc": "PVC is the PVC of the golden image\n+optional",
+	}
+}
+
+func (DataSourceList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DataSourceList provides the needed parameters to do request a list of DataSources from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of DataSources",
+	}
+}
+
 func (CDI) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":       "CDI is the CDI Operator CRD\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:storageversion\n+kubebuilder:resource:shortName=cdi;cdis,scope=Cluster\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"\n+kubebuilder:printcolumn:name=\"Phase\",type=\"string\",JSONPath=\".status.phase\"",
@@ -139,9 +669,26 @@ func (CertConfig) SwaggerDoc() map[string]string {
 
 func (CDICertConfig) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":       "CDICertConfig has the CertConfigs for CDI",
-		"ca":     "CA configuration\nCA certs are kept in the CA bundle as long as they are valid",
-		"server": "Server configuration\nCerts are rotated and discarded",
+		"":            "CDICertConfig has the CertConfigs for CDI",
+		"ca":          "CA configuration\nCA certs are kept in the CA bundle as long as they are valid",
+		"server":      "Server configuration\nCerts are rotated and discarded",
+		"certManager": "CertManager delegates the serving certificates of the apiserver and of the upload proxy to cert-manager,\nwhich then rotates them and their CA instead of CDI",
+	}
+}
+
+func (CertManagerConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "CertManagerConfig defines the cert-manager issuer of the serving certificates of CDI",
+		"issuerRef": "IssuerRef is the cert-manager Issuer of the CDI namespace or the ClusterIssuer issuing the certificates.\nThe issuer must provide the CA of the certificates, like the CA and Vault issuers",
+	}
+}
+
+func (CertManagerIssuerReference) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "CertManagerIssuerReference references a cert-manager issuer",
+		"name":  "Name is the name of the issuer",
+		"kind":  "Kind is the kind of the issuer, Issuer if not set\n+kubebuilder:validation:Enum=\"Issuer\";\"ClusterIssuer\"",
+		"group": "Group is the API group of the issuer, cert-manager.io if not set",
 	}
 }
 
@@ -152,9 +699,43 @@ func (CDISpec) SwaggerDoc() map[string]string {
 		"uninstallStrategy":     "+kubebuilder:validation:Enum=RemoveWorkloads;BlockUninstallIfWorkloadsExist\nCDIUninstallStrategy defines the state to leave CDI on uninstall",
 		"infra":                 "Rules on which nodes CDI infrastructure pods will be scheduled",
 		"workload":              "Restrict on which nodes CDI workload pods will be scheduled",
-		"cloneStrategyOverride": "Clone strategy override: should we use a host-assisted copy even if snapshots are available?\n+kubebuilder:validation:Enum=\"copy\";\"snapshot\"",
+		"cloneStrategyOverride": "Clone strategy override: should we use a host-assisted copy even if snapshots are available?\n+kubebuilder:validation:Enum=\"copy\";\"snapshot\";\"csi-clone\"",
 		"config":                "CDIConfig at CDI level",
 		"certConfig":            "certificate configuration",
+		"uploadProxyExposure":   "UploadProxyExposure makes the operator expose the upload proxy outside of the cluster",
+		"componentResources":    "ComponentResources overrides the resource requirements of the CDI control-plane components",
+		"imagePullSecrets":      "ImagePullSecrets are the secrets used to pull the images of the CDI pods. The control-plane pods use the secrets of\nthe CDI namespace, the importer, upload and clone pods use the secrets of the same names in their own namespace",
+		"imageOverrides":        "ImageOverrides replaces the images of the importer, upload and clone pods, for instance with mirrored digests",
+		"priorityClass":         "PriorityClass is the priority class of the control-plane pods, which keeps them from being evicted before the\ntransfer pods. The cdi-cluster-critical priority class created by the operator is used if not set",
+	}
+}
+
+func (CDIImageOverrides) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "CDIImageOverrides defines the images of the pods created by the CDI controller, the image of the CDI release is used\nif the image of a pod is not set",
+		"importer":     "Importer is the image of the importer and export pods",
+		"cloner":       "Cloner is the image of the clone source pods",
+		"uploadServer": "UploadServer is the image of the upload, download and clone target pods",
+	}
+}
+
+func (CDIComponentResources) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "CDIComponentResources defines the resource requirements of the containers of the CDI control-plane deployments,\nthe defaults of a component are used if its requirements are not set",
+		"controller":  "Controller is the resource requirements of cdi-deployment",
+		"apiServer":   "APIServer is the resource requirements of cdi-apiserver",
+		"uploadProxy": "UploadProxy is the resource requirements of cdi-uploadproxy",
+	}
+}
+
+func (UploadProxyExposure) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "UploadProxyExposure defines the Ingress or the Route created by the operator to expose the upload proxy",
+		"type":             "+kubebuilder:validation:Enum=Ingress;Route\nType is the kind of object exposing the upload proxy",
+		"hostname":         "Hostname is the external host name of the upload proxy",
+		"tlsSecretName":    "TLSSecretName is the name of a kubernetes.io/tls Secret of the CDI namespace, holding the certificate of the host name.\nThe default certificate of the ingress controller or of the router is used if not set",
+		"ingressClassName": "IngressClassName is the class of the Ingress, the default class is used if not set",
+		"annotations":      "Annotations are added to the Ingress or to the Route, for the settings of the ingress controller or of the router",
 	}
 }
 
@@ -187,13 +768,85 @@ func (FilesystemOverhead) SwaggerDoc() map[string]string {
 
 func (CDIConfigSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                         "CDIConfigSpec defines specification for user configuration",
-		"uploadProxyURLOverride":   "Override the URL used when uploading to a DataVolume",
-		"scratchSpaceStorageClass": "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
-		"podResourceRequirements":  "ResourceRequirements describes the compute resource requirements.",
-		"featureGates":             "FeatureGates are a list of specific enabled feature gates",
-		"filesystemOverhead":       "FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)",
-		"preallocation":            "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"":                             "CDIConfigSpec defines specification for user configuration",
+		"uploadProxyURLOverride":       "Override the URL used when uploading to a DataVolume",
+		"uploadProxyClientCAConfigMap": "UploadProxyClientCAConfigMap is the name of a ConfigMap in the CDI namespace holding, in its ca-bundle.crt key, the CA bundle of the client certificates the upload proxy requires in addition to the upload token. If not set client certificates are not required\n+optional",
+		"uploadProxyRateLimits":        "UploadProxyRateLimits limits the concurrent uploads and the bandwidth of the uploads through the upload proxy, per namespace and per PVC\n+optional",
+		"uploadTokenTTL":               "UploadTokenTTL is the lifetime of the upload and download tokens issued by the CDI API server, 5 minutes if not set. Tokens can be renewed before they expire\n+optional",
+		"scratchSpaceStorageClass":     "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
+		"podResourceRequirements":      "ResourceRequirements describes the compute resource requirements.",
+		"podPriorityClassName":         "PodPriorityClassName is the default priority class of the importer, upload and clone pods",
+		"maxParallelImports":           "MaxParallelImports is the maximum number of importer pods running at the same time in the cluster, the other imports wait in the Pending phase",
+		"importRetryPolicy":            "ImportRetryPolicy is the default retry policy of the failed imports, the importer pods restart with the backoff of the kubelet without it",
+		"dataVolumeTTLSeconds":         "DataVolumeTTLSeconds is the time in seconds after the completion of a DataVolume before it is deleted, its PVC is kept. DataVolumes whose PVC is used by a pod or which are referenced by a VirtualMachine are kept until they are not anymore. The DataVolumes are kept if not set\n+optional",
+		"featureGates":                 "FeatureGates are a list of specific enabled feature gates",
+		"filesystemOverhead":           "FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)",
+		"preallocation":                "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"tlsConfig":                    "TLSConfig controls the minimum TLS version and the cipher suites used by the CDI servers and the http clients of the importer",
+		"registries":                   "Registries configures the mirrors and the insecure registries consulted by registry imports\n+optional",
+		"podSecurity":                  "PodSecurity overrides the security context of the importer, upload server and clone source pods, which comply with the restricted Pod Security Standard by default\n+optional",
+		"fileSourceHostPathPrefixes":   "FileSourceHostPathPrefixes are the directories of the nodes the host paths of File sources must be in, host paths are rejected if it is empty\n+optional",
+	}
+}
+
+func (RegistryConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "RegistryConfig defines the mirrors of a registry, or of a namespace of a registry, tried before the registry itself",
+		"registry": "Registry is the host[:port] of the registry, optionally followed by a namespace, for instance quay.io/kubevirt. The configuration applies to the images under it",
+		"insecure": "Insecure allows plain HTTP and TLS connections without certificate verification to the registry\n+optional",
+		"mirrors":  "Mirrors are tried in order before the registry, the images are pulled from the registry if none of them has the image\n+optional",
+	}
+}
+
+func (RegistryMirror) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "RegistryMirror defines a mirror of a registry",
+		"location": "Location is the host[:port] of the mirror, optionally followed by a namespace replacing the one of the registry, for instance mirror.example.com:5000/quay",
+		"insecure": "Insecure allows plain HTTP and TLS connections without certificate verification to the mirror\n+optional",
+	}
+}
+
+func (PodSecurityConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "PodSecurityConfig overrides the security context of the importer, upload server and clone source pods, for the clusters whose policies require custom settings",
+		"runAsUser":      "RunAsUser is the user the pods run as, 107 (qemu) if not set\n+optional",
+		"seLinuxOptions": "SELinuxOptions are the SELinux options of the pods, those of the container runtime if not set\n+optional",
+		"seccompProfile": "SeccompProfile is the seccomp profile of the pods, runtime/default if not set\n+optional",
+	}
+}
+
+func (ImportRetryPolicy) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "ImportRetryPolicy defines how the failed imports are retried. The importer pod of a failed attempt is deleted, and a new one is created after a backoff doubling at every attempt",
+		"maxAttempts":    "MaxAttempts is the number of attempts of the import before the DataVolume fails for good, unlimited when unset\n+optional",
+		"initialBackoff": "InitialBackoff is the delay before the first retry, 10s when unset\n+optional",
+		"maxBackoff":     "MaxBackoff caps the delay between two attempts, 5m when unset\n+optional",
+	}
+}
+
+func (DataVolumeScratchSpace) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "DataVolumeScratchSpace defines the scratch space PVC of the pods populating a DataVolume",
+		"storageClassName": "StorageClassName of the scratch space PVC, the scratchSpaceStorageClass of the CDIConfig when unset\n+optional",
+		"size":             "Size of the scratch space PVC, the size of the PVC of the DataVolume when unset\n+optional",
+	}
+}
+
+func (UploadProxyRateLimits) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                                 "UploadProxyRateLimits defines the limits enforced by the upload proxy, so a tenant can't saturate the bandwidth of the cluster. Uploads over the concurrent limits are rejected with a 429 status, and the bandwidth limits are shared by the concurrent uploads",
+		"maxConcurrentUploadsPerNamespace": "MaxConcurrentUploadsPerNamespace is the maximum number of uploads proxied at the same time to the PVCs of a namespace\n+optional",
+		"maxConcurrentUploadsPerPVC":       "MaxConcurrentUploadsPerPVC is the maximum number of uploads proxied at the same time to a PVC, with any of its upload tokens\n+optional",
+		"maxBandwidthPerNamespace":         "MaxBandwidthPerNamespace is the maximum number of bytes per second uploaded to the PVCs of a namespace\n+optional",
+		"maxBandwidthPerPVC":               "MaxBandwidthPerPVC is the maximum number of bytes per second uploaded to a PVC\n+optional",
+	}
+}
+
+func (TLSConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "TLSConfig defines the TLS settings of the CDI components",
+		"minVersion": "MinVersion is the minimum TLS version, one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13. If not set the default of the component is used\n+optional",
+		"ciphers":    "Ciphers is the list of allowed cipher suites using the IANA names, for instance TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The cipher suites of TLS 1.3 are not configurable. If not set the default of the component is used\n+optional",
 	}
 }
 
@@ -214,3 +867,44 @@ func (CDIConfigList) SwaggerDoc() map[string]string {
 		"items": "Items provides a list of CDIConfigs",
 	}
 }
+
+func (StorageProfile) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "StorageProfile provides a CDI specific recommendation for storage parameters, one per StorageClass\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:resource:scope=Cluster\n+kubebuilder:printcolumn:name=\"Provisioner\",type=\"string\",JSONPath=\".status.provisioner\",description=\"The provisioner of the StorageClass\"\n+kubebuilder:printcolumn:name=\"Clone Strategy\",type=\"string\",JSONPath=\".status.cloneStrategy\",description=\"The clone strategy of the StorageClass\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"",
+	}
+}
+
+func (StorageProfileSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "StorageProfileSpec defines specification for StorageProfile, the settings overriding the recommendations for the provisioner",
+		"cloneStrategy":     "CloneStrategy defines the preferred method for performing a CDI clone\n+kubebuilder:validation:Enum=\"copy\";\"snapshot\";\"csi-clone\"\n+optional",
+		"claimPropertySets": "ClaimPropertySets is a provided set of properties applicable to PVC\n+optional",
+		"snapshotClass":     "SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass\n+optional",
+	}
+}
+
+func (StorageProfileStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "StorageProfileStatus provides the most recently observed status of the StorageProfile",
+		"storageClass":      "The StorageClass name for which capabilities are defined",
+		"provisioner":       "The Storage class provisioner plugin name",
+		"cloneStrategy":     "CloneStrategy defines the preferred method for performing a CDI clone",
+		"claimPropertySets": "ClaimPropertySets computed from the spec and detected in the system",
+		"snapshotClass":     "SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass",
+	}
+}
+
+func (ClaimPropertySet) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "ClaimPropertySet is a set of properties applicable to PVC",
+		"accessModes": "AccessModes contains the desired access modes the volume should have.\nMore info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1\n+optional",
+		"volumeMode":  "VolumeMode defines what type of volume is required by the claim.\nValue of Filesystem is implied when not included in claim spec.\n+optional",
+	}
+}
+
+func (StorageProfileList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "StorageProfileList provides the needed parameters to request a list of StorageProfile from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of StorageProfile",
+	}
+}
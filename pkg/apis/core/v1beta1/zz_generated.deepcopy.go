@@ -67,6 +67,11 @@ func (in *CDICertConfig) DeepCopyInto(out *CDICertConfig) {
 		*out = new(CertConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -80,6 +85,37 @@ func (in *CDICertConfig) DeepCopy() *CDICertConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CDIComponentResources) DeepCopyInto(out *CDIComponentResources) {
+	*out = *in
+	if in.Controller != nil {
+		in, out := &in.Controller, &out.Controller
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServer != nil {
+		in, out := &in.APIServer, &out.APIServer
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UploadProxy != nil {
+		in, out := &in.UploadProxy, &out.UploadProxy
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CDIComponentResources.
+func (in *CDIComponentResources) DeepCopy() *CDIComponentResources {
+	if in == nil {
+		return nil
+	}
+	out := new(CDIComponentResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CDIConfig) DeepCopyInto(out *CDIConfig) {
 	*out = *in
@@ -149,6 +185,21 @@ func (in *CDIConfigSpec) DeepCopyInto(out *CDIConfigSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.UploadProxyClientCAConfigMap != nil {
+		in, out := &in.UploadProxyClientCAConfigMap, &out.UploadProxyClientCAConfigMap
+		*out = new(string)
+		**out = **in
+	}
+	if in.UploadProxyRateLimits != nil {
+		in, out := &in.UploadProxyRateLimits, &out.UploadProxyRateLimits
+		*out = new(UploadProxyRateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UploadTokenTTL != nil {
+		in, out := &in.UploadTokenTTL, &out.UploadTokenTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ScratchSpaceStorageClass != nil {
 		in, out := &in.ScratchSpaceStorageClass, &out.ScratchSpaceStorageClass
 		*out = new(string)
@@ -159,6 +210,26 @@ func (in *CDIConfigSpec) DeepCopyInto(out *CDIConfigSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodPriorityClassName != nil {
+		in, out := &in.PodPriorityClassName, &out.PodPriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxParallelImports != nil {
+		in, out := &in.MaxParallelImports, &out.MaxParallelImports
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImportRetryPolicy != nil {
+		in, out := &in.ImportRetryPolicy, &out.ImportRetryPolicy
+		*out = new(ImportRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataVolumeTTLSeconds != nil {
+		in, out := &in.DataVolumeTTLSeconds, &out.DataVolumeTTLSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	if in.FeatureGates != nil {
 		in, out := &in.FeatureGates, &out.FeatureGates
 		*out = make([]string, len(*in))
@@ -174,6 +245,28 @@ func (in *CDIConfigSpec) DeepCopyInto(out *CDIConfigSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]RegistryConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecurityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FileSourceHostPathPrefixes != nil {
+		in, out := &in.FileSourceHostPathPrefixes, &out.FileSourceHostPathPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -218,6 +311,22 @@ func (in *CDIConfigStatus) DeepCopy() *CDIConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CDIImageOverrides) DeepCopyInto(out *CDIImageOverrides) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CDIImageOverrides.
+func (in *CDIImageOverrides) DeepCopy() *CDIImageOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(CDIImageOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CDIList) DeepCopyInto(out *CDIList) {
 	*out = *in
@@ -276,6 +385,31 @@ func (in *CDISpec) DeepCopyInto(out *CDISpec) {
 		*out = new(CDICertConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UploadProxyExposure != nil {
+		in, out := &in.UploadProxyExposure, &out.UploadProxyExposure
+		*out = new(UploadProxyExposure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentResources != nil {
+		in, out := &in.ComponentResources, &out.ComponentResources
+		*out = new(CDIComponentResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(CDIImageOverrides)
+		**out = **in
+	}
+	if in.PriorityClass != nil {
+		in, out := &in.PriorityClass, &out.PriorityClass
+		*out = new(CDIPriorityClass)
+		**out = **in
+	}
 	return
 }
 
@@ -333,91 +467,100 @@ func (in *CertConfig) DeepCopy() *CertConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolume) DeepCopyInto(out *DataVolume) {
+func (in *CertManagerConfig) DeepCopyInto(out *CertManagerConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.IssuerRef = in.IssuerRef
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolume.
-func (in *DataVolume) DeepCopy() *DataVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerConfig.
+func (in *CertManagerConfig) DeepCopy() *CertManagerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolume)
+	out := new(CertManagerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataVolume) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeBlankImage) DeepCopyInto(out *DataVolumeBlankImage) {
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeBlankImage.
-func (in *DataVolumeBlankImage) DeepCopy() *DataVolumeBlankImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeBlankImage)
+	out := new(CertManagerIssuerReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeCheckpoint) DeepCopyInto(out *DataVolumeCheckpoint) {
+func (in *ClaimPropertySet) DeepCopyInto(out *ClaimPropertySet) {
 	*out = *in
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeCheckpoint.
-func (in *DataVolumeCheckpoint) DeepCopy() *DataVolumeCheckpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimPropertySet.
+func (in *ClaimPropertySet) DeepCopy() *ClaimPropertySet {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeCheckpoint)
+	out := new(ClaimPropertySet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeCondition) DeepCopyInto(out *DataVolumeCondition) {
+func (in *CloneGrant) DeepCopyInto(out *CloneGrant) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeCondition.
-func (in *DataVolumeCondition) DeepCopy() *DataVolumeCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrant.
+func (in *CloneGrant) DeepCopy() *CloneGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeCondition)
+	out := new(CloneGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloneGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeList) DeepCopyInto(out *DataVolumeList) {
+func (in *CloneGrantList) DeepCopyInto(out *CloneGrantList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DataVolume, len(*in))
+		*out = make([]CloneGrant, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -425,18 +568,18 @@ func (in *DataVolumeList) DeepCopyInto(out *DataVolumeList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeList.
-func (in *DataVolumeList) DeepCopy() *DataVolumeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantList.
+func (in *CloneGrantList) DeepCopy() *CloneGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeList)
+	out := new(CloneGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataVolumeList) DeepCopyObject() runtime.Object {
+func (in *CloneGrantList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -444,187 +587,1921 @@ func (in *DataVolumeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
+func (in *CloneGrantSpec) DeepCopyInto(out *CloneGrantSpec) {
 	*out = *in
-	if in.HTTP != nil {
-		in, out := &in.HTTP, &out.HTTP
-		*out = new(DataVolumeSourceHTTP)
-		**out = **in
-	}
-	if in.S3 != nil {
-		in, out := &in.S3, &out.S3
-		*out = new(DataVolumeSourceS3)
-		**out = **in
-	}
-	if in.Registry != nil {
-		in, out := &in.Registry, &out.Registry
-		*out = new(DataVolumeSourceRegistry)
-		**out = **in
-	}
-	if in.PVC != nil {
-		in, out := &in.PVC, &out.PVC
-		*out = new(DataVolumeSourcePVC)
-		**out = **in
-	}
-	if in.Upload != nil {
-		in, out := &in.Upload, &out.Upload
-		*out = new(DataVolumeSourceUpload)
-		**out = **in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Blank != nil {
-		in, out := &in.Blank, &out.Blank
-		*out = new(DataVolumeBlankImage)
-		**out = **in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Imageio != nil {
-		in, out := &in.Imageio, &out.Imageio
-		*out = new(DataVolumeSourceImageIO)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantSpec.
+func (in *CloneGrantSpec) DeepCopy() *CloneGrantSpec {
+	if in == nil {
+		return nil
 	}
-	if in.VDDK != nil {
-		in, out := &in.VDDK, &out.VDDK
-		*out = new(DataVolumeSourceVDDK)
-		**out = **in
+	out := new(CloneGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGrantStatus) DeepCopyInto(out *CloneGrantStatus) {
+	*out = *in
+	if in.LastCloneTime != nil {
+		in, out := &in.LastCloneTime, &out.LastCloneTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSource.
-func (in *DataVolumeSource) DeepCopy() *DataVolumeSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGrantStatus.
+func (in *CloneGrantStatus) DeepCopy() *CloneGrantStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSource)
+	out := new(CloneGrantStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceHTTP) DeepCopyInto(out *DataVolumeSourceHTTP) {
+func (in *DataExport) DeepCopyInto(out *DataExport) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceHTTP.
-func (in *DataVolumeSourceHTTP) DeepCopy() *DataVolumeSourceHTTP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExport.
+func (in *DataExport) DeepCopy() *DataExport {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceHTTP)
+	out := new(DataExport)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceImageIO) DeepCopyInto(out *DataVolumeSourceImageIO) {
+func (in *DataExportList) DeepCopyInto(out *DataExportList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceImageIO.
-func (in *DataVolumeSourceImageIO) DeepCopy() *DataVolumeSourceImageIO {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportList.
+func (in *DataExportList) DeepCopy() *DataExportList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceImageIO)
+	out := new(DataExportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourcePVC) DeepCopyInto(out *DataVolumeSourcePVC) {
+func (in *DataExportSource) DeepCopyInto(out *DataExportSource) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourcePVC.
-func (in *DataVolumeSourcePVC) DeepCopy() *DataVolumeSourcePVC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportSource.
+func (in *DataExportSource) DeepCopy() *DataExportSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourcePVC)
+	out := new(DataExportSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceRegistry) DeepCopyInto(out *DataVolumeSourceRegistry) {
+func (in *DataExportSpec) DeepCopyInto(out *DataExportSpec) {
 	*out = *in
+	out.Source = in.Source
+	in.Target.DeepCopyInto(&out.Target)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRegistry.
-func (in *DataVolumeSourceRegistry) DeepCopy() *DataVolumeSourceRegistry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportSpec.
+func (in *DataExportSpec) DeepCopy() *DataExportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceRegistry)
+	out := new(DataExportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceS3) DeepCopyInto(out *DataVolumeSourceS3) {
+func (in *DataExportStatus) DeepCopyInto(out *DataExportStatus) {
 	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]DataVolumeCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceS3.
-func (in *DataVolumeSourceS3) DeepCopy() *DataVolumeSourceS3 {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportStatus.
+func (in *DataExportStatus) DeepCopy() *DataExportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceS3)
+	out := new(DataExportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceUpload) DeepCopyInto(out *DataVolumeSourceUpload) {
+func (in *DataExportTarget) DeepCopyInto(out *DataExportTarget) {
 	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(DataExportTargetS3)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(DataExportTargetHTTP)
+		**out = **in
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(DataExportTargetRegistry)
+		**out = **in
+	}
+	if in.CDI != nil {
+		in, out := &in.CDI, &out.CDI
+		*out = new(DataExportTargetCDI)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceUpload.
-func (in *DataVolumeSourceUpload) DeepCopy() *DataVolumeSourceUpload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportTarget.
+func (in *DataExportTarget) DeepCopy() *DataExportTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceUpload)
+	out := new(DataExportTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSourceVDDK) DeepCopyInto(out *DataVolumeSourceVDDK) {
+func (in *DataExportTargetCDI) DeepCopyInto(out *DataExportTargetCDI) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceVDDK.
-func (in *DataVolumeSourceVDDK) DeepCopy() *DataVolumeSourceVDDK {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportTargetCDI.
+func (in *DataExportTargetCDI) DeepCopy() *DataExportTargetCDI {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSourceVDDK)
+	out := new(DataExportTargetCDI)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSpec) DeepCopyInto(out *DataVolumeSpec) {
+func (in *DataExportTargetHTTP) DeepCopyInto(out *DataExportTargetHTTP) {
 	*out = *in
-	in.Source.DeepCopyInto(&out.Source)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportTargetHTTP.
+func (in *DataExportTargetHTTP) DeepCopy() *DataExportTargetHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(DataExportTargetHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataExportTargetRegistry) DeepCopyInto(out *DataExportTargetRegistry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportTargetRegistry.
+func (in *DataExportTargetRegistry) DeepCopy() *DataExportTargetRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DataExportTargetRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataExportTargetS3) DeepCopyInto(out *DataExportTargetS3) {
+	*out = *in
+	if in.PartSize != nil {
+		in, out := &in.PartSize, &out.PartSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataExportTargetS3.
+func (in *DataExportTargetS3) DeepCopy() *DataExportTargetS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(DataExportTargetS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCron) DeepCopyInto(out *DataImportCron) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCron.
+func (in *DataImportCron) DeepCopy() *DataImportCron {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCron)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataImportCron) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronImport) DeepCopyInto(out *DataImportCronImport) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronImport.
+func (in *DataImportCronImport) DeepCopy() *DataImportCronImport {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronList) DeepCopyInto(out *DataImportCronList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataImportCron, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronList.
+func (in *DataImportCronList) DeepCopy() *DataImportCronList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataImportCronList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronNotification) DeepCopyInto(out *DataImportCronNotification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronNotification.
+func (in *DataImportCronNotification) DeepCopy() *DataImportCronNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronSpec) DeepCopyInto(out *DataImportCronSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.GarbageCollect != nil {
+		in, out := &in.GarbageCollect, &out.GarbageCollect
+		*out = new(DataImportCronGarbageCollect)
+		**out = **in
+	}
+	if in.ImportsToKeep != nil {
+		in, out := &in.ImportsToKeep, &out.ImportsToKeep
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Notification != nil {
+		in, out := &in.Notification, &out.Notification
+		*out = new(DataImportCronNotification)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronSpec.
+func (in *DataImportCronSpec) DeepCopy() *DataImportCronSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportCronStatus) DeepCopyInto(out *DataImportCronStatus) {
+	*out = *in
+	if in.Imports != nil {
+		in, out := &in.Imports, &out.Imports
+		*out = make([]DataImportCronImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastExecutionTimestamp != nil {
+		in, out := &in.LastExecutionTimestamp, &out.LastExecutionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastImportTimestamp != nil {
+		in, out := &in.LastImportTimestamp, &out.LastImportTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCronStatus.
+func (in *DataImportCronStatus) DeepCopy() *DataImportCronStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCronStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceList) DeepCopyInto(out *DataSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceList.
+func (in *DataSourceList) DeepCopy() *DataSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceSource) DeepCopyInto(out *DataSourceSource) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(DataVolumeSourcePVC)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceSource.
+func (in *DataSourceSource) DeepCopy() *DataSourceSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceSpec) DeepCopyInto(out *DataSourceSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]DataSourceSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceSpec.
+func (in *DataSourceSpec) DeepCopy() *DataSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceStatus) DeepCopyInto(out *DataSourceStatus) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(DataSourceSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]DataVolumeCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceStatus.
+func (in *DataSourceStatus) DeepCopy() *DataSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolume) DeepCopyInto(out *DataVolume) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolume.
+func (in *DataVolume) DeepCopy() *DataVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataVolume) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeBlankImage) DeepCopyInto(out *DataVolumeBlankImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeBlankImage.
+func (in *DataVolumeBlankImage) DeepCopy() *DataVolumeBlankImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeBlankImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeCheckpoint) DeepCopyInto(out *DataVolumeCheckpoint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeCheckpoint.
+func (in *DataVolumeCheckpoint) DeepCopy() *DataVolumeCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeCheckpointStatus) DeepCopyInto(out *DataVolumeCheckpointStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeCheckpointStatus.
+func (in *DataVolumeCheckpointStatus) DeepCopy() *DataVolumeCheckpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeCheckpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeCondition) DeepCopyInto(out *DataVolumeCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeCondition.
+func (in *DataVolumeCondition) DeepCopy() *DataVolumeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeList) DeepCopyInto(out *DataVolumeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeList.
+func (in *DataVolumeList) DeepCopy() *DataVolumeList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataVolumeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeScratchSpace) DeepCopyInto(out *DataVolumeScratchSpace) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeScratchSpace.
+func (in *DataVolumeScratchSpace) DeepCopy() *DataVolumeScratchSpace {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeScratchSpace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(DataVolumeSourceHTTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(DataVolumeSourceS3)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AWSSnapshot != nil {
+		in, out := &in.AWSSnapshot, &out.AWSSnapshot
+		*out = new(DataVolumeSourceAWSSnapshot)
+		**out = **in
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(DataVolumeSourceGCS)
+		**out = **in
+	}
+	if in.GCEImage != nil {
+		in, out := &in.GCEImage, &out.GCEImage
+		*out = new(DataVolumeSourceGCEImage)
+		**out = **in
+	}
+	if in.AzureBlob != nil {
+		in, out := &in.AzureBlob, &out.AzureBlob
+		*out = new(DataVolumeSourceAzureBlob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureDisk != nil {
+		in, out := &in.AzureDisk, &out.AzureDisk
+		*out = new(DataVolumeSourceAzureDisk)
+		**out = **in
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(DataVolumeSourceRegistry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(DataVolumeSourcePVC)
+		**out = **in
+	}
+	if in.PVCNetwork != nil {
+		in, out := &in.PVCNetwork, &out.PVCNetwork
+		*out = new(DataVolumeSourcePVC)
+		**out = **in
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(DataVolumeSourceSnapshot)
+		**out = **in
+	}
+	if in.Upload != nil {
+		in, out := &in.Upload, &out.Upload
+		*out = new(DataVolumeSourceUpload)
+		**out = **in
+	}
+	if in.Blank != nil {
+		in, out := &in.Blank, &out.Blank
+		*out = new(DataVolumeBlankImage)
+		**out = **in
+	}
+	if in.Imageio != nil {
+		in, out := &in.Imageio, &out.Imageio
+		*out = new(DataVolumeSourceImageIO)
+		**out = **in
+	}
+	if in.Glance != nil {
+		in, out := &in.Glance, &out.Glance
+		*out = new(DataVolumeSourceGlance)
+		**out = **in
+	}
+	if in.Proxmox != nil {
+		in, out := &in.Proxmox, &out.Proxmox
+		*out = new(DataVolumeSourceProxmox)
+		**out = **in
+	}
+	if in.HyperV != nil {
+		in, out := &in.HyperV, &out.HyperV
+		*out = new(DataVolumeSourceHyperV)
+		**out = **in
+	}
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(DataVolumeSourceNFS)
+		**out = **in
+	}
+	if in.SMB != nil {
+		in, out := &in.SMB, &out.SMB
+		*out = new(DataVolumeSourceSMB)
+		**out = **in
+	}
+	if in.Rsync != nil {
+		in, out := &in.Rsync, &out.Rsync
+		*out = new(DataVolumeSourceRsync)
+		**out = **in
+	}
+	if in.ISCSI != nil {
+		in, out := &in.ISCSI, &out.ISCSI
+		*out = new(DataVolumeSourceISCSI)
+		**out = **in
+	}
+	if in.RBD != nil {
+		in, out := &in.RBD, &out.RBD
+		*out = new(DataVolumeSourceRBD)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(DataVolumeSourceFile)
+		**out = **in
+	}
+	if in.Libvirt != nil {
+		in, out := &in.Libvirt, &out.Libvirt
+		*out = new(DataVolumeSourceLibvirt)
+		**out = **in
+	}
+	if in.VDDK != nil {
+		in, out := &in.VDDK, &out.VDDK
+		*out = new(DataVolumeSourceVDDK)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSource.
+func (in *DataVolumeSource) DeepCopy() *DataVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceAWSSnapshot) DeepCopyInto(out *DataVolumeSourceAWSSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceAWSSnapshot.
+func (in *DataVolumeSourceAWSSnapshot) DeepCopy() *DataVolumeSourceAWSSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceAWSSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceAzureBlob) DeepCopyInto(out *DataVolumeSourceAzureBlob) {
+	*out = *in
+	if in.Segments != nil {
+		in, out := &in.Segments, &out.Segments
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SegmentSize != nil {
+		in, out := &in.SegmentSize, &out.SegmentSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceAzureBlob.
+func (in *DataVolumeSourceAzureBlob) DeepCopy() *DataVolumeSourceAzureBlob {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceAzureBlob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceAzureDisk) DeepCopyInto(out *DataVolumeSourceAzureDisk) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceAzureDisk.
+func (in *DataVolumeSourceAzureDisk) DeepCopy() *DataVolumeSourceAzureDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceAzureDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceFile) DeepCopyInto(out *DataVolumeSourceFile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceFile.
+func (in *DataVolumeSourceFile) DeepCopy() *DataVolumeSourceFile {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceGCEImage) DeepCopyInto(out *DataVolumeSourceGCEImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceGCEImage.
+func (in *DataVolumeSourceGCEImage) DeepCopy() *DataVolumeSourceGCEImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceGCEImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceGCS) DeepCopyInto(out *DataVolumeSourceGCS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceGCS.
+func (in *DataVolumeSourceGCS) DeepCopy() *DataVolumeSourceGCS {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceGCS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceGlance) DeepCopyInto(out *DataVolumeSourceGlance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceGlance.
+func (in *DataVolumeSourceGlance) DeepCopy() *DataVolumeSourceGlance {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceGlance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceHTTP) DeepCopyInto(out *DataVolumeSourceHTTP) {
+	*out = *in
+	if in.FTPPassive != nil {
+		in, out := &in.FTPPassive, &out.FTPPassive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OAuth2 != nil {
+		in, out := &in.OAuth2, &out.OAuth2
+		*out = new(DataVolumeSourceHTTPOAuth2)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Segments != nil {
+		in, out := &in.Segments, &out.Segments
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SegmentSize != nil {
+		in, out := &in.SegmentSize, &out.SegmentSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceHTTP.
+func (in *DataVolumeSourceHTTP) DeepCopy() *DataVolumeSourceHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceHTTPOAuth2) DeepCopyInto(out *DataVolumeSourceHTTPOAuth2) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceHTTPOAuth2.
+func (in *DataVolumeSourceHTTPOAuth2) DeepCopy() *DataVolumeSourceHTTPOAuth2 {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceHTTPOAuth2)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceHyperV) DeepCopyInto(out *DataVolumeSourceHyperV) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceHyperV.
+func (in *DataVolumeSourceHyperV) DeepCopy() *DataVolumeSourceHyperV {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceHyperV)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceISCSI) DeepCopyInto(out *DataVolumeSourceISCSI) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceISCSI.
+func (in *DataVolumeSourceISCSI) DeepCopy() *DataVolumeSourceISCSI {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceISCSI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceImageIO) DeepCopyInto(out *DataVolumeSourceImageIO) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceImageIO.
+func (in *DataVolumeSourceImageIO) DeepCopy() *DataVolumeSourceImageIO {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceImageIO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceInfo) DeepCopyInto(out *DataVolumeSourceInfo) {
+	*out = *in
+	if in.VirtualSize != nil {
+		in, out := &in.VirtualSize, &out.VirtualSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DownloadedSize != nil {
+		in, out := &in.DownloadedSize, &out.DownloadedSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceInfo.
+func (in *DataVolumeSourceInfo) DeepCopy() *DataVolumeSourceInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceLibvirt) DeepCopyInto(out *DataVolumeSourceLibvirt) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceLibvirt.
+func (in *DataVolumeSourceLibvirt) DeepCopy() *DataVolumeSourceLibvirt {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceLibvirt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceNFS) DeepCopyInto(out *DataVolumeSourceNFS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceNFS.
+func (in *DataVolumeSourceNFS) DeepCopy() *DataVolumeSourceNFS {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceNFS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourcePVC) DeepCopyInto(out *DataVolumeSourcePVC) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourcePVC.
+func (in *DataVolumeSourcePVC) DeepCopy() *DataVolumeSourcePVC {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourcePVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceProxmox) DeepCopyInto(out *DataVolumeSourceProxmox) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceProxmox.
+func (in *DataVolumeSourceProxmox) DeepCopy() *DataVolumeSourceProxmox {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceProxmox)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRBD) DeepCopyInto(out *DataVolumeSourceRBD) {
+	*out = *in
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRBD.
+func (in *DataVolumeSourceRBD) DeepCopy() *DataVolumeSourceRBD {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRBD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRef) DeepCopyInto(out *DataVolumeSourceRef) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRef.
+func (in *DataVolumeSourceRef) DeepCopy() *DataVolumeSourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRegistry) DeepCopyInto(out *DataVolumeSourceRegistry) {
+	*out = *in
+	if in.Signature != nil {
+		in, out := &in.Signature, &out.Signature
+		*out = new(DataVolumeSourceRegistrySignature)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRegistry.
+func (in *DataVolumeSourceRegistry) DeepCopy() *DataVolumeSourceRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRegistrySignature) DeepCopyInto(out *DataVolumeSourceRegistrySignature) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRegistrySignature.
+func (in *DataVolumeSourceRegistrySignature) DeepCopy() *DataVolumeSourceRegistrySignature {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRegistrySignature)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceRsync) DeepCopyInto(out *DataVolumeSourceRsync) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceRsync.
+func (in *DataVolumeSourceRsync) DeepCopy() *DataVolumeSourceRsync {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceRsync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceS3) DeepCopyInto(out *DataVolumeSourceS3) {
+	*out = *in
+	if in.ForcePathStyle != nil {
+		in, out := &in.ForcePathStyle, &out.ForcePathStyle
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Segments != nil {
+		in, out := &in.Segments, &out.Segments
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SegmentSize != nil {
+		in, out := &in.SegmentSize, &out.SegmentSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceS3.
+func (in *DataVolumeSourceS3) DeepCopy() *DataVolumeSourceS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSMB) DeepCopyInto(out *DataVolumeSourceSMB) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSMB.
+func (in *DataVolumeSourceSMB) DeepCopy() *DataVolumeSourceSMB {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSMB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSnapshot) DeepCopyInto(out *DataVolumeSourceSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSnapshot.
+func (in *DataVolumeSourceSnapshot) DeepCopy() *DataVolumeSourceSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceUpload) DeepCopyInto(out *DataVolumeSourceUpload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceUpload.
+func (in *DataVolumeSourceUpload) DeepCopy() *DataVolumeSourceUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceVDDK) DeepCopyInto(out *DataVolumeSourceVDDK) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceVDDK.
+func (in *DataVolumeSourceVDDK) DeepCopy() *DataVolumeSourceVDDK {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceVDDK)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSpec) DeepCopyInto(out *DataVolumeSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	if in.SourceRef != nil {
+		in, out := &in.SourceRef, &out.SourceRef
+		*out = new(DataVolumeSourceRef)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PVC != nil {
 		in, out := &in.PVC, &out.PVC
 		*out = new(v1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Checkpoints != nil {
-		in, out := &in.Checkpoints, &out.Checkpoints
-		*out = make([]DataVolumeCheckpoint, len(*in))
+	if in.Checkpoints != nil {
+		in, out := &in.Checkpoints, &out.Checkpoints
+		*out = make([]DataVolumeCheckpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodePlacement != nil {
+		in, out := &in.NodePlacement, &out.NodePlacement
+		*out = (*in).DeepCopy()
+	}
+	if in.PodResourceRequirements != nil {
+		in, out := &in.PodResourceRequirements, &out.PodResourceRequirements
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(ImportRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImportTimeout != nil {
+		in, out := &in.ImportTimeout, &out.ImportTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FilesystemOverhead != nil {
+		in, out := &in.FilesystemOverhead, &out.FilesystemOverhead
+		*out = new(Percent)
+		**out = **in
+	}
+	if in.ScratchSpace != nil {
+		in, out := &in.ScratchSpace, &out.ScratchSpace
+		*out = new(DataVolumeScratchSpace)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSpec.
+func (in *DataVolumeSpec) DeepCopy() *DataVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeStatus) DeepCopyInto(out *DataVolumeStatus) {
+	*out = *in
+	if in.Transfer != nil {
+		in, out := &in.Transfer, &out.Transfer
+		*out = new(DataVolumeTransferStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceInfo != nil {
+		in, out := &in.SourceInfo, &out.SourceInfo
+		*out = new(DataVolumeSourceInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]DataVolumeCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Checkpoints != nil {
+		in, out := &in.Checkpoints, &out.Checkpoints
+		*out = make([]DataVolumeCheckpointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeStatus.
+func (in *DataVolumeStatus) DeepCopy() *DataVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeTransferStatus) DeepCopyInto(out *DataVolumeTransferStatus) {
+	*out = *in
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeTransferStatus.
+func (in *DataVolumeTransferStatus) DeepCopy() *DataVolumeTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemOverhead) DeepCopyInto(out *FilesystemOverhead) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = make(map[string]Percent, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemOverhead.
+func (in *FilesystemOverhead) DeepCopy() *FilesystemOverhead {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemOverhead)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportRetryPolicy) DeepCopyInto(out *ImportRetryPolicy) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportRetryPolicy.
+func (in *ImportRetryPolicy) DeepCopy() *ImportRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityConfig) DeepCopyInto(out *PodSecurityConfig) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SELinuxOptions != nil {
+		in, out := &in.SELinuxOptions, &out.SELinuxOptions
+		*out = new(v1.SELinuxOptions)
+		**out = **in
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityConfig.
+func (in *PodSecurityConfig) DeepCopy() *PodSecurityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]RegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfig.
+func (in *RegistryConfig) DeepCopy() *RegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProfile) DeepCopyInto(out *StorageProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProfile.
+func (in *StorageProfile) DeepCopy() *StorageProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProfileList) DeepCopyInto(out *StorageProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StorageProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProfileList.
+func (in *StorageProfileList) DeepCopy() *StorageProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProfileSpec) DeepCopyInto(out *StorageProfileSpec) {
+	*out = *in
+	if in.CloneStrategy != nil {
+		in, out := &in.CloneStrategy, &out.CloneStrategy
+		*out = new(CDICloneStrategy)
+		**out = **in
+	}
+	if in.ClaimPropertySets != nil {
+		in, out := &in.ClaimPropertySets, &out.ClaimPropertySets
+		*out = make([]ClaimPropertySet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotClass != nil {
+		in, out := &in.SnapshotClass, &out.SnapshotClass
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProfileSpec.
+func (in *StorageProfileSpec) DeepCopy() *StorageProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProfileStatus) DeepCopyInto(out *StorageProfileStatus) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.Provisioner != nil {
+		in, out := &in.Provisioner, &out.Provisioner
+		*out = new(string)
+		**out = **in
+	}
+	if in.CloneStrategy != nil {
+		in, out := &in.CloneStrategy, &out.CloneStrategy
+		*out = new(CDICloneStrategy)
+		**out = **in
+	}
+	if in.ClaimPropertySets != nil {
+		in, out := &in.ClaimPropertySets, &out.ClaimPropertySets
+		*out = make([]ClaimPropertySet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotClass != nil {
+		in, out := &in.SnapshotClass, &out.SnapshotClass
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProfileStatus.
+func (in *StorageProfileStatus) DeepCopy() *StorageProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UploadProxyExposure) DeepCopyInto(out *UploadProxyExposure) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UploadProxyExposure.
+func (in *UploadProxyExposure) DeepCopy() *UploadProxyExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(UploadProxyExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UploadProxyRateLimits) DeepCopyInto(out *UploadProxyRateLimits) {
+	*out = *in
+	if in.MaxConcurrentUploadsPerNamespace != nil {
+		in, out := &in.MaxConcurrentUploadsPerNamespace, &out.MaxConcurrentUploadsPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentUploadsPerPVC != nil {
+		in, out := &in.MaxConcurrentUploadsPerPVC, &out.MaxConcurrentUploadsPerPVC
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBandwidthPerNamespace != nil {
+		in, out := &in.MaxBandwidthPerNamespace, &out.MaxBandwidthPerNamespace
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxBandwidthPerPVC != nil {
+		in, out := &in.MaxBandwidthPerPVC, &out.MaxBandwidthPerPVC
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UploadProxyRateLimits.
+func (in *UploadProxyRateLimits) DeepCopy() *UploadProxyRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(UploadProxyRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCloneSource) DeepCopyInto(out *VolumeCloneSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCloneSource.
+func (in *VolumeCloneSource) DeepCopy() *VolumeCloneSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeCloneSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeCloneSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCloneSourceList) DeepCopyInto(out *VolumeCloneSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VolumeCloneSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCloneSourceList.
+func (in *VolumeCloneSourceList) DeepCopy() *VolumeCloneSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeCloneSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeCloneSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCloneSourceSpec) DeepCopyInto(out *VolumeCloneSourceSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
 	if in.Preallocation != nil {
 		in, out := &in.Preallocation, &out.Preallocation
 		*out = new(bool)
@@ -633,22 +2510,51 @@ func (in *DataVolumeSpec) DeepCopyInto(out *DataVolumeSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSpec.
-func (in *DataVolumeSpec) DeepCopy() *DataVolumeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCloneSourceSpec.
+func (in *VolumeCloneSourceSpec) DeepCopy() *VolumeCloneSourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSpec)
+	out := new(VolumeCloneSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeStatus) DeepCopyInto(out *DataVolumeStatus) {
+func (in *VolumeImportSource) DeepCopyInto(out *VolumeImportSource) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]DataVolumeCondition, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeImportSource.
+func (in *VolumeImportSource) DeepCopy() *VolumeImportSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeImportSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeImportSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeImportSourceList) DeepCopyInto(out *VolumeImportSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VolumeImportSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -656,35 +2562,123 @@ func (in *DataVolumeStatus) DeepCopyInto(out *DataVolumeStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeStatus.
-func (in *DataVolumeStatus) DeepCopy() *DataVolumeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeImportSourceList.
+func (in *VolumeImportSourceList) DeepCopy() *VolumeImportSourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeStatus)
+	out := new(VolumeImportSourceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeImportSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemOverhead) DeepCopyInto(out *FilesystemOverhead) {
+func (in *VolumeImportSourceSpec) DeepCopyInto(out *VolumeImportSourceSpec) {
 	*out = *in
-	if in.StorageClass != nil {
-		in, out := &in.StorageClass, &out.StorageClass
-		*out = make(map[string]Percent, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeImportSourceSpec.
+func (in *VolumeImportSourceSpec) DeepCopy() *VolumeImportSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeImportSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeUploadSource) DeepCopyInto(out *VolumeUploadSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeUploadSource.
+func (in *VolumeUploadSource) DeepCopy() *VolumeUploadSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeUploadSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeUploadSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeUploadSourceList) DeepCopyInto(out *VolumeUploadSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VolumeUploadSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemOverhead.
-func (in *FilesystemOverhead) DeepCopy() *FilesystemOverhead {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeUploadSourceList.
+func (in *VolumeUploadSourceList) DeepCopy() *VolumeUploadSourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(FilesystemOverhead)
+	out := new(VolumeUploadSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeUploadSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeUploadSourceSpec) DeepCopyInto(out *VolumeUploadSourceSpec) {
+	*out = *in
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeUploadSourceSpec.
+func (in *VolumeUploadSourceSpec) DeepCopy() *VolumeUploadSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeUploadSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -280,6 +280,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                            schema_pkg_apis_meta_v1_TypeMeta(ref),
 		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                       schema_pkg_apis_meta_v1_UpdateOptions(ref),
 		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                          schema_pkg_apis_meta_v1_WatchEvent(ref),
+		"kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadStatus":             schema_pkg_apis_upload_v1beta1_UploadStatus(ref),
 		"kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadTokenRequest":       schema_pkg_apis_upload_v1beta1_UploadTokenRequest(ref),
 		"kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadTokenRequestList":   schema_pkg_apis_upload_v1beta1_UploadTokenRequestList(ref),
 		"kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadTokenRequestSpec":   schema_pkg_apis_upload_v1beta1_UploadTokenRequestSpec(ref),
@@ -13254,6 +13255,47 @@ func schema_pkg_apis_meta_v1_WatchEvent(ref common.ReferenceCallback) common.Ope
 	}
 }
 
+func schema_pkg_apis_upload_v1beta1_UploadStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UploadStatus is the progress of an upload, returned by the upload status endpoint of the upload proxy",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the phase of the upload",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bytesReceived": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BytesReceived is the number of bytes of the image received by the upload server",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"totalBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TotalBytes is the size of the image, when the client sent it",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"progress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress is the progress in percentage of the conversion of the received image",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_upload_v1beta1_UploadTokenRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -13363,6 +13405,20 @@ func schema_pkg_apis_upload_v1beta1_UploadTokenRequestSpec(ref common.ReferenceC
 							Format:      "",
 						},
 					},
+					"download": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Download requests a token to download the disk of the PVC instead of uploading to it",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"token": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Token is the token to renew, in requests to the renewal endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"pvcName"},
 			},
@@ -13384,8 +13440,16 @@ func schema_pkg_apis_upload_v1beta1_UploadTokenRequestStatus(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"uploadStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UploadStatus is the status of the upload to the PVC when the token was issued",
+							Ref:         ref("kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadStatus"),
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1.UploadStatus"},
 	}
 }
@@ -38,12 +38,52 @@ type UploadTokenRequest struct {
 type UploadTokenRequestSpec struct {
 	// PvcName is the name of the PVC to upload to
 	PvcName string `json:"pvcName"`
+	// Download requests a token to download the disk of the PVC instead of uploading to it
+	// +optional
+	Download bool `json:"download,omitempty"`
+	// Token is the token to renew, in requests to the renewal endpoint
+	// +optional
+	Token string `json:"token,omitempty"`
 }
 
 // UploadTokenRequestStatus stores the status of a token request
 type UploadTokenRequestStatus struct {
 	// Token is a JWT token to be inserted in "Authentication Bearer header"
 	Token string `json:"token,omitempty"`
+	// UploadStatus is the status of the upload to the PVC when the token was issued
+	// +optional
+	UploadStatus *UploadStatus `json:"uploadStatus,omitempty"`
+}
+
+// UploadPhase is the phase of an upload
+type UploadPhase string
+
+const (
+	// UploadPhasePending means the upload server is not ready or did not receive data yet
+	UploadPhasePending UploadPhase = "Pending"
+	// UploadPhaseUploading means the upload server is receiving the image
+	UploadPhaseUploading UploadPhase = "Uploading"
+	// UploadPhaseProcessing means the image was received and is being converted and written to the PVC
+	UploadPhaseProcessing UploadPhase = "Processing"
+	// UploadPhaseSucceeded means the image was written to the PVC
+	UploadPhaseSucceeded UploadPhase = "Succeeded"
+	// UploadPhaseFailed means the upload server failed
+	UploadPhaseFailed UploadPhase = "Failed"
+)
+
+// UploadStatus is the progress of an upload, returned by the upload status endpoint of the upload proxy
+type UploadStatus struct {
+	// Phase is the phase of the upload
+	Phase UploadPhase `json:"phase,omitempty"`
+	// BytesReceived is the number of bytes of the image received by the upload server
+	// +optional
+	BytesReceived int64 `json:"bytesReceived,omitempty"`
+	// TotalBytes is the size of the image, when the client sent it
+	// +optional
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// Progress is the progress in percentage of the conversion of the received image
+	// +optional
+	Progress string `json:"progress,omitempty"`
 }
 
 // UploadTokenRequestList contains a list of UploadTokenRequests
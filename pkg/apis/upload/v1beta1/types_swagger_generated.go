@@ -12,15 +12,28 @@ func (UploadTokenRequest) SwaggerDoc() map[string]string {
 
 func (UploadTokenRequestSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":        "UploadTokenRequestSpec defines the parameters of the token request",
-		"pvcName": "PvcName is the name of the PVC to upload to",
+		"":         "UploadTokenRequestSpec defines the parameters of the token request",
+		"pvcName":  "PvcName is the name of the PVC to upload to",
+		"download": "Download requests a token to download the disk of the PVC instead of uploading to it\n+optional",
+		"token":    "Token is the token to renew, in requests to the renewal endpoint\n+optional",
 	}
 }
 
 func (UploadTokenRequestStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":      "UploadTokenRequestStatus stores the status of a token request",
-		"token": "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+		"":             "UploadTokenRequestStatus stores the status of a token request",
+		"token":        "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+		"uploadStatus": "UploadStatus is the status of the upload to the PVC when the token was issued\n+optional",
+	}
+}
+
+func (UploadStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "UploadStatus is the progress of an upload, returned by the upload status endpoint of the upload proxy",
+		"phase":         "Phase is the phase of the upload",
+		"bytesReceived": "BytesReceived is the number of bytes of the image received by the upload server\n+optional",
+		"totalBytes":    "TotalBytes is the size of the image, when the client sent it\n+optional",
+		"progress":      "Progress is the progress in percentage of the conversion of the received image\n+optional",
 	}
 }
 
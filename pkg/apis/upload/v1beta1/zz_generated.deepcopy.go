@@ -24,13 +24,29 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UploadStatus) DeepCopyInto(out *UploadStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UploadStatus.
+func (in *UploadStatus) DeepCopy() *UploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UploadTokenRequest) DeepCopyInto(out *UploadTokenRequest) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -104,6 +120,11 @@ func (in *UploadTokenRequestSpec) DeepCopy() *UploadTokenRequestSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UploadTokenRequestStatus) DeepCopyInto(out *UploadTokenRequestStatus) {
 	*out = *in
+	if in.UploadStatus != nil {
+		in, out := &in.UploadStatus, &out.UploadStatus
+		*out = new(UploadStatus)
+		**out = **in
+	}
 	return
 }
 
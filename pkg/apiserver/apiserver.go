@@ -38,19 +38,23 @@ import (
 	restful "github.com/emicklei/go-restful"
 	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdiuploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/apiserver/webhooks"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
 	"kubevirt.io/containerized-data-importer/pkg/keys"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/openapi"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -65,7 +69,18 @@ const (
 
 	cdiValidatePath = "/cdi-validate"
 
+	storageProfileValidatePath = "/storageprofile-validate"
+
+	dataImportCronValidatePath = "/dataimportcron-validate"
+
+	volumeImportSourceValidatePath = "/volumeimportsource-validate"
+
+	uploadTokenRenewalResource = "uploadtokenrenewals"
+
 	healthzPath = "/healthz"
+
+	// defaultUploadTokenTTL is the lifetime of upload tokens when the CDIConfig does not set one
+	defaultUploadTokenTTL = 5 * time.Minute
 )
 
 var uploadTokenVersions = []string{"v1beta1", "v1alpha1"}
@@ -87,6 +102,8 @@ type cdiAPIApp struct {
 	client           kubernetes.Interface
 	aggregatorClient aggregatorclient.Interface
 	cdiClient        cdiclient.Interface
+	// controllerRuntimeClient gets the resources without a typed client, like the VolumeSnapshotClasses
+	controllerRuntimeClient client.Client
 
 	privateSigningKey *rsa.PrivateKey
 
@@ -97,7 +114,7 @@ type cdiAPIApp struct {
 
 	certWarcher CertWatcher
 
-	tokenGenerator token.Generator
+	tlsWatcher tlsconfig.Watcher
 }
 
 // UploadTokenRequestAPI returns web service for swagger generation
@@ -113,19 +130,23 @@ func NewCdiAPIServer(bindAddress string,
 	client kubernetes.Interface,
 	aggregatorClient aggregatorclient.Interface,
 	cdiClient cdiclient.Interface,
+	controllerRuntimeClient client.Client,
 	authorizor CdiAPIAuthorizer,
 	authConfigWatcher AuthConfigWatcher,
-	certWatcher CertWatcher) (CdiAPIServer, error) {
+	certWatcher CertWatcher,
+	tlsWatcher tlsconfig.Watcher) (CdiAPIServer, error) {
 	var err error
 	app := &cdiAPIApp{
-		bindAddress:       bindAddress,
-		bindPort:          bindPort,
-		client:            client,
-		aggregatorClient:  aggregatorClient,
-		cdiClient:         cdiClient,
-		authorizer:        authorizor,
-		authConfigWatcher: authConfigWatcher,
-		certWarcher:       certWatcher,
+		bindAddress:             bindAddress,
+		bindPort:                bindPort,
+		client:                  client,
+		aggregatorClient:        aggregatorClient,
+		cdiClient:               cdiClient,
+		controllerRuntimeClient: controllerRuntimeClient,
+		authorizer:              authorizor,
+		authConfigWatcher:       authConfigWatcher,
+		certWarcher:             certWatcher,
+		tlsWatcher:              tlsWatcher,
 	}
 
 	err = app.getKeysAndCerts()
@@ -171,11 +192,41 @@ func NewCdiAPIServer(bindAddress string,
 		return nil, errors.Errorf("failed to create CDI validating webhook: %s", err)
 	}
 
+	err = app.createStorageProfileValidatingWebhook()
+	if err != nil {
+		return nil, errors.Errorf("failed to create StorageProfile validating webhook: %s", err)
+	}
+
+	err = app.createDataImportCronValidatingWebhook()
+	if err != nil {
+		return nil, errors.Errorf("failed to create DataImportCron validating webhook: %s", err)
+	}
+
+	err = app.createVolumeImportSourceValidatingWebhook()
+	if err != nil {
+		return nil, errors.Errorf("failed to create VolumeImportSource validating webhook: %s", err)
+	}
+
 	return app, nil
 }
 
-func newUploadTokenGenerator(key *rsa.PrivateKey) token.Generator {
-	return token.NewGenerator(common.UploadTokenIssuer, key, 5*time.Minute)
+func newUploadTokenGenerator(key *rsa.PrivateKey, lifetime time.Duration) token.Generator {
+	return token.NewGenerator(common.UploadTokenIssuer, key, lifetime)
+}
+
+// getUploadTokenTTL returns the lifetime of upload tokens configured in the CDIConfig
+func (app *cdiAPIApp) getUploadTokenTTL() time.Duration {
+	config, err := app.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			klog.Errorf("Unable to get the CDI config, using the default upload token TTL: %v", err)
+		}
+		return defaultUploadTokenTTL
+	}
+	if ttl := config.Spec.UploadTokenTTL; ttl != nil && ttl.Duration > 0 {
+		return ttl.Duration
+	}
+	return defaultUploadTokenTTL
 }
 
 func (app *cdiAPIApp) Start(ch <-chan struct{}) error {
@@ -192,8 +243,6 @@ func (app *cdiAPIApp) getKeysAndCerts() error {
 
 	app.privateSigningKey = privateKey
 
-	app.tokenGenerator = newUploadTokenGenerator(privateKey)
-
 	return nil
 }
 
@@ -221,6 +270,9 @@ func (app *cdiAPIApp) getTLSConfig() (*tls.Config, error) {
 			return fmt.Errorf("no valid subject specified")
 		},
 	}
+	if app.tlsWatcher != nil {
+		app.tlsWatcher.GetOptions().Apply(tlsConfig)
+	}
 	tlsConfig.BuildNameToCertificate()
 
 	return tlsConfig, nil
@@ -291,8 +343,13 @@ func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.
 		return
 	}
 
+	operation := token.OperationUpload
+	if uploadToken.Spec.Download {
+		operation = token.OperationDownload
+	}
+
 	tokenData := &token.Payload{
-		Operation: token.OperationUpload,
+		Operation: operation,
 		Name:      uploadToken.Spec.PvcName,
 		Namespace: namespace,
 		Resource: metav1.GroupVersionResource{
@@ -302,7 +359,7 @@ func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.
 		},
 	}
 
-	token, err := app.tokenGenerator.Generate(tokenData)
+	token, err := newUploadTokenGenerator(app.privateSigningKey, app.getUploadTokenTTL()).Generate(tokenData)
 	if err != nil {
 		klog.Error(err)
 		response.WriteError(http.StatusInternalServerError, err)
@@ -310,10 +367,100 @@ func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.
 	}
 
 	uploadToken.Status.Token = token
+	if !uploadToken.Spec.Download {
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), uploadToken.Spec.PvcName, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			klog.Error(err)
+			response.WriteError(http.StatusInternalServerError, err)
+			return
+		}
+		if err == nil {
+			uploadToken.Status.UploadStatus = controller.GetUploadStatus(pvc)
+		}
+	}
 	response.WriteAsJson(uploadToken)
 
 }
 
+// renewalHandler issues a new token for the PVC and the operation of the valid token of the request, so clients can
+// keep uploading past the token lifetime. Renewing only needs the permission to create uploadtokenrenewals, which can
+// be granted without the permission to request tokens for any PVC. Tokens of completed uploads are not renewed.
+func (app *cdiAPIApp) renewalHandler(request *restful.Request, response *restful.Response) {
+	allowed, reason, err := app.authorizer.Authorize(request)
+	if err != nil {
+		klog.Error(err)
+		response.WriteHeader(http.StatusInternalServerError)
+		return
+	} else if !allowed {
+		klog.Infof("Rejected Request: %s", reason)
+		response.WriteErrorString(http.StatusUnauthorized, reason)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	defer request.Request.Body.Close()
+	body, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	uploadToken := &cdiuploadv1.UploadTokenRequest{}
+	err = json.Unmarshal(body, uploadToken)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	validator := token.NewValidator(common.UploadTokenIssuer, &app.privateSigningKey.PublicKey, 0)
+	tokenData, err := validator.Validate(uploadToken.Spec.Token)
+	if err != nil {
+		klog.Infof("Rejected token renewal: %v", err)
+		response.WriteErrorString(http.StatusUnauthorized, "invalid token")
+		return
+	}
+	if tokenData.Namespace != namespace ||
+		tokenData.Resource.Resource != "persistentvolumeclaims" ||
+		(tokenData.Operation != token.OperationUpload && tokenData.Operation != token.OperationDownload) {
+		klog.Infof("Rejected token renewal: token of %s %s/%s", tokenData.Operation, tokenData.Namespace, tokenData.Name)
+		response.WriteErrorString(http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	pvc, err := app.client.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), tokenData.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			response.WriteErrorString(http.StatusNotFound, fmt.Sprintf("PVC %s/%s not found", namespace, tokenData.Name))
+			return
+		}
+		klog.Error(err)
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	if tokenData.Operation == token.OperationUpload {
+		uploadToken.Status.UploadStatus = controller.GetUploadStatus(pvc)
+		if uploadToken.Status.UploadStatus.Phase == cdiuploadv1.UploadPhaseSucceeded {
+			response.WriteErrorString(http.StatusConflict, "upload already completed")
+			return
+		}
+	}
+
+	renewed, err := newUploadTokenGenerator(app.privateSigningKey, app.getUploadTokenTTL()).Generate(tokenData)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	uploadToken.Spec.PvcName = tokenData.Name
+	uploadToken.Spec.Download = tokenData.Operation == token.OperationDownload
+	uploadToken.Spec.Token = ""
+	uploadToken.Status.Token = renewed
+	response.WriteAsJson(uploadToken)
+}
+
 func uploadTokenAPIGroup() metav1.APIGroup {
 	apiGroup := metav1.APIGroup{
 		Name: uploadTokenGroup,
@@ -346,6 +493,8 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 
 	groupPath := fmt.Sprintf("/apis/%s", uploadTokenGroup)
 	createPath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", resource)
+	// Not listed in the API resources since the renewals are UploadTokenRequests too
+	renewalPath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", uploadTokenRenewalResource)
 
 	app.container = restful.NewContainer()
 
@@ -372,6 +521,18 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 			Returns(http.StatusUnauthorized, "Unauthorized", "").
 			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
 
+		uploadTokenWs.Route(uploadTokenWs.POST(renewalPath).
+			Produces("application/json").
+			Consumes("application/json").
+			Operation("renewNamespaced"+objKind+"-"+v).
+			To(app.renewalHandler).Reads(objExample).Writes(objExample).
+			Doc("Renew the token of an UploadTokenRequest object.").
+			Returns(http.StatusOK, "OK", objExample).
+			Returns(http.StatusUnauthorized, "Unauthorized", "").
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusConflict, "Conflict", "").
+			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
+
 		uploadTokenWs.Route(uploadTokenWs.GET("/").
 			Produces("application/json").Writes(metav1.APIResourceList{}).
 			To(func(request *restful.Request, response *restful.Response) {
@@ -472,16 +633,31 @@ func (app *cdiAPIApp) healthzHandler(req *restful.Request, resp *restful.Respons
 }
 
 func (app *cdiAPIApp) createDataVolumeValidatingWebhook() error {
-	app.container.ServeMux.Handle(dvValidatePath, webhooks.NewDataVolumeValidatingWebhook(app.client))
+	app.container.ServeMux.Handle(dvValidatePath, webhooks.NewDataVolumeValidatingWebhook(app.client, app.cdiClient))
 	return nil
 }
 
 func (app *cdiAPIApp) createDataVolumeMutatingWebhook() error {
-	app.container.ServeMux.Handle(dvMutatePath, webhooks.NewDataVolumeMutatingWebhook(app.client, app.privateSigningKey))
+	app.container.ServeMux.Handle(dvMutatePath, webhooks.NewDataVolumeMutatingWebhook(app.client, app.cdiClient, app.privateSigningKey))
 	return nil
 }
 
 func (app *cdiAPIApp) createCDIValidatingWebhook() error {
-	app.container.ServeMux.Handle(cdiValidatePath, webhooks.NewCDIValidatingWebhook(app.cdiClient))
+	app.container.ServeMux.Handle(cdiValidatePath, webhooks.NewCDIValidatingWebhook(app.client, app.cdiClient))
+	return nil
+}
+
+func (app *cdiAPIApp) createStorageProfileValidatingWebhook() error {
+	app.container.ServeMux.Handle(storageProfileValidatePath, webhooks.NewStorageProfileValidatingWebhook(app.client, app.controllerRuntimeClient))
+	return nil
+}
+
+func (app *cdiAPIApp) createDataImportCronValidatingWebhook() error {
+	app.container.ServeMux.Handle(dataImportCronValidatePath, webhooks.NewDataImportCronValidatingWebhook(app.client, app.cdiClient))
+	return nil
+}
+
+func (app *cdiAPIApp) createVolumeImportSourceValidatingWebhook() error {
+	app.container.ServeMux.Handle(volumeImportSourceValidatePath, webhooks.NewVolumeImportSourceValidatingWebhook(app.client, app.cdiClient))
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -34,6 +35,7 @@ import (
 
 	"github.com/appscode/jsonpatch"
 	restful "github.com/emicklei/go-restful"
+	"gopkg.in/square/go-jose.v2/jwt"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,8 +43,13 @@ import (
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
 
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiuploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
+	cdifake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
 	"kubevirt.io/containerized-data-importer/pkg/keys/keystest"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
 type testAuthorizer struct {
@@ -187,6 +194,27 @@ func getExpectedAPIGroup() metav1.APIGroup {
 	}
 }
 
+func postTokenRequest(app *cdiAPIApp, resource string, request *cdiuploadv1.UploadTokenRequest) *httptest.ResponseRecorder {
+	body, err := json.Marshal(request)
+	Expect(err).ToNot(HaveOccurred())
+	req, err := http.NewRequest("POST",
+		"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/"+resource,
+		bytes.NewReader(body))
+	Expect(err).ToNot(HaveOccurred())
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	app.container.ServeHTTP(rr, req)
+	return rr
+}
+
+func getTokenClaims(t string, key *rsa.PrivateKey) *jwt.Claims {
+	tok, err := jwt.ParseSigned(t)
+	Expect(err).ToNot(HaveOccurred())
+	claims := &jwt.Claims{}
+	Expect(tok.Claims(&key.PublicKey, claims)).To(Succeed())
+	return claims
+}
+
 var _ = Describe("API server tests", func() {
 	It("Get self-signed cert", func() {
 		signingKey, err := generateTestKey()
@@ -369,7 +397,7 @@ var _ = Describe("API server tests", func() {
 		app := &cdiAPIApp{client: client,
 			privateSigningKey: signingKey,
 			authorizer:        args.authorizer,
-			tokenGenerator:    newUploadTokenGenerator(signingKey)}
+			cdiClient:         cdifake.NewSimpleClientset()}
 		app.composeUploadTokenAPI()
 
 		req, err := http.NewRequest("POST",
@@ -389,6 +417,8 @@ var _ = Describe("API server tests", func() {
 			err := json.Unmarshal(rr.Body.Bytes(), &uploadTokenRequest)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(uploadTokenRequest.Status.Token).To(Not(Equal("")))
+			Expect(uploadTokenRequest.Status.UploadStatus).ToNot(BeNil())
+			Expect(uploadTokenRequest.Status.UploadStatus.Phase).To(Equal(cdiuploadv1.UploadPhasePending))
 		}
 	},
 		table.Entry("authoriser error",
@@ -420,4 +450,159 @@ var _ = Describe("API server tests", func() {
 			http.StatusOK,
 			true),
 	)
+
+	It("Should mint download tokens", func() {
+		downloadRequest := request.DeepCopy()
+		downloadRequest.Spec.Download = true
+		body, err := json.Marshal(downloadRequest)
+		Expect(err).ToNot(HaveOccurred())
+
+		app := &cdiAPIApp{client: k8sfake.NewSimpleClientset(pvc),
+			privateSigningKey: signingKey,
+			authorizer:        authorizeSuccess,
+			cdiClient:         cdifake.NewSimpleClientset()}
+		app.composeUploadTokenAPI()
+
+		req, err := http.NewRequest("POST",
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/uploadtokenrequests",
+			bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		app.container.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+		validator := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, 0)
+		payload, err := validator.Validate(uploadTokenRequest.Status.Token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.Operation).To(Equal(token.OperationDownload))
+		Expect(payload.Name).To(Equal("test-pvc"))
+		Expect(uploadTokenRequest.Status.UploadStatus).To(BeNil())
+	})
+
+	It("Should return the status of the upload", func() {
+		uploadingPvc := pvc.DeepCopy()
+		uploadingPvc.Annotations = map[string]string{
+			controller.AnnPodPhase:     string(v1.PodRunning),
+			controller.AnnUploadStatus: `{"phase":"Uploading","bytesReceived":512,"totalBytes":1024}`,
+		}
+		app := &cdiAPIApp{client: k8sfake.NewSimpleClientset(uploadingPvc),
+			privateSigningKey: signingKey,
+			authorizer:        authorizeSuccess,
+			cdiClient:         cdifake.NewSimpleClientset()}
+		app.composeUploadTokenAPI()
+
+		req, err := http.NewRequest("POST",
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/uploadtokenrequests",
+			bytes.NewReader(serializedRequest))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		app.container.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+		Expect(uploadTokenRequest.Status.UploadStatus).To(Equal(&cdiuploadv1.UploadStatus{
+			Phase:         cdiuploadv1.UploadPhaseUploading,
+			BytesReceived: 512,
+			TotalBytes:    1024,
+		}))
+	})
+
+	It("Should issue tokens with the lifetime of the CDIConfig", func() {
+		config := &cdiv1.CDIConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: common.ConfigName},
+			Spec: cdiv1.CDIConfigSpec{
+				UploadTokenTTL: &metav1.Duration{Duration: 2 * time.Hour},
+			},
+		}
+		app := &cdiAPIApp{client: k8sfake.NewSimpleClientset(pvc),
+			privateSigningKey: signingKey,
+			authorizer:        authorizeSuccess,
+			cdiClient:         cdifake.NewSimpleClientset(config)}
+		app.composeUploadTokenAPI()
+
+		rr := postTokenRequest(app, "uploadtokenrequests", request)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+		claims := getTokenClaims(uploadTokenRequest.Status.Token, signingKey)
+		Expect(claims.Expiry.Time().Sub(claims.IssuedAt.Time())).To(Equal(2 * time.Hour))
+	})
+
+	Context("Token renewal", func() {
+		generateToken := func(operation token.Operation, namespace string, lifetime time.Duration) string {
+			t, err := newUploadTokenGenerator(signingKey, lifetime).Generate(&token.Payload{
+				Operation: operation,
+				Name:      "test-pvc",
+				Namespace: namespace,
+				Resource: metav1.GroupVersionResource{
+					Version:  "v1",
+					Resource: "persistentvolumeclaims",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			return t
+		}
+
+		renewalRequest := func(t string) *cdiuploadv1.UploadTokenRequest {
+			return &cdiuploadv1.UploadTokenRequest{
+				Spec: cdiuploadv1.UploadTokenRequestSpec{
+					Token: t,
+				},
+			}
+		}
+
+		It("Should renew a valid token", func() {
+			app := &cdiAPIApp{client: k8sfake.NewSimpleClientset(pvc),
+				privateSigningKey: signingKey,
+				authorizer:        authorizeSuccess,
+				cdiClient:         cdifake.NewSimpleClientset()}
+			app.composeUploadTokenAPI()
+
+			rr := postTokenRequest(app, "uploadtokenrenewals", renewalRequest(generateToken(token.OperationDownload, "default", time.Minute)))
+			Expect(rr.Code).To(Equal(http.StatusOK))
+
+			uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+			Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+			Expect(uploadTokenRequest.Spec.PvcName).To(Equal("test-pvc"))
+			Expect(uploadTokenRequest.Spec.Download).To(BeTrue())
+			Expect(uploadTokenRequest.Spec.Token).To(BeEmpty())
+			claims := getTokenClaims(uploadTokenRequest.Status.Token, signingKey)
+			Expect(claims.Expiry.Time().Sub(claims.IssuedAt.Time())).To(Equal(defaultUploadTokenTTL))
+			validator := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, 0)
+			payload, err := validator.Validate(uploadTokenRequest.Status.Token)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(payload.Operation).To(Equal(token.OperationDownload))
+			Expect(payload.Name).To(Equal("test-pvc"))
+		})
+
+		table.DescribeTable("Should not renew", func(t func() string, authorizer CdiAPIAuthorizer, annotations map[string]string, expectedStatus int) {
+			targetPvc := pvc.DeepCopy()
+			targetPvc.Annotations = annotations
+			app := &cdiAPIApp{client: k8sfake.NewSimpleClientset(targetPvc),
+				privateSigningKey: signingKey,
+				authorizer:        authorizer,
+				cdiClient:         cdifake.NewSimpleClientset()}
+			app.composeUploadTokenAPI()
+
+			rr := postTokenRequest(app, "uploadtokenrenewals", renewalRequest(t()))
+			Expect(rr.Code).To(Equal(expectedStatus))
+		},
+			table.Entry("without the permission", func() string { return generateToken(token.OperationUpload, "default", time.Minute) },
+				&testAuthorizer{allowed: false, reason: "bad person"}, nil, http.StatusUnauthorized),
+			table.Entry("an invalid token", func() string { return "invalid" },
+				authorizeSuccess, nil, http.StatusUnauthorized),
+			table.Entry("an expired token", func() string { return generateToken(token.OperationUpload, "default", -time.Minute) },
+				authorizeSuccess, nil, http.StatusUnauthorized),
+			table.Entry("a token of another namespace", func() string { return generateToken(token.OperationUpload, "other", time.Minute) },
+				authorizeSuccess, nil, http.StatusUnauthorized),
+			table.Entry("a token of a completed upload", func() string { return generateToken(token.OperationUpload, "default", time.Minute) },
+				authorizeSuccess, map[string]string{controller.AnnPodPhase: string(v1.PodSucceeded)}, http.StatusConflict),
+		)
+	})
 })
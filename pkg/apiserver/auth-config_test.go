@@ -43,8 +43,21 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
+type fakeTLSWatcher struct {
+	options *tlsconfig.Options
+}
+
+func (w *fakeTLSWatcher) GetOptions() *tlsconfig.Options {
+	return w.options
+}
+
+func (w *fakeTLSWatcher) GetUploadProxyClientCAConfigMap() string {
+	return ""
+}
+
 func generateCACert() string {
 	keyPair, err := triple.NewCA(util.RandAlphaNum(10))
 	Expect(err).ToNot(HaveOccurred())
@@ -122,7 +135,7 @@ var _ = Describe("Auth config tests", func() {
 		authorizer := &testAuthorizer{}
 		authConfigWatcher := NewAuthConfigWatcher(client, ch)
 
-		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, authorizer, authConfigWatcher, nil)
+		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, nil, authorizer, authConfigWatcher, nil, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		app := server.(*cdiAPIApp)
@@ -149,7 +162,7 @@ var _ = Describe("Auth config tests", func() {
 		authorizer := &testAuthorizer{}
 		acw := NewAuthConfigWatcher(client, ch).(*authConfigWatcher)
 
-		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, authorizer, acw, nil)
+		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, nil, authorizer, acw, nil, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		app := server.(*cdiAPIApp)
@@ -181,7 +194,7 @@ var _ = Describe("Auth config tests", func() {
 		acw := NewAuthConfigWatcher(client, ch).(*authConfigWatcher)
 		certWatcher := NewFakeCertWatcher()
 
-		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, authorizer, acw, certWatcher)
+		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, nil, authorizer, acw, certWatcher, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		app := server.(*cdiAPIApp)
@@ -194,6 +207,32 @@ var _ = Describe("Auth config tests", func() {
 		}
 	})
 
+	It("Get TLS config with TLS options", func() {
+		ch := make(chan struct{})
+		client := k8sfake.NewSimpleClientset(getAPIServerConfigMap())
+		aggregatorClient := aggregatorapifake.NewSimpleClientset()
+		cdiClient := cdiclientfake.NewSimpleClientset()
+		authorizer := &testAuthorizer{}
+		acw := NewAuthConfigWatcher(client, ch).(*authConfigWatcher)
+		certWatcher := NewFakeCertWatcher()
+		tlsWatcher := &fakeTLSWatcher{
+			options: &tlsconfig.Options{
+				MinVersion:   tls.VersionTLS12,
+				CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+			},
+		}
+
+		server, err := NewCdiAPIServer("0.0.0.0", 0, client, aggregatorClient, cdiClient, nil, authorizer, acw, certWatcher, tlsWatcher)
+		Expect(err).ToNot(HaveOccurred())
+
+		app := server.(*cdiAPIApp)
+
+		tlsConfig, err := app.getTLSConfig()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(tlsConfig.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}))
+	})
+
 	DescribeTable("Validate client CN", func(f func() *corev1.ConfigMap, name string, allowed bool) {
 		ch := make(chan struct{})
 		kubeobjects := []runtime.Object{}
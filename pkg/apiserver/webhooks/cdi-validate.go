@@ -27,17 +27,24 @@ import (
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
 )
 
-const uninstallErrorMsg = "Rejecting the uninstall request, since there are still DataVolumes present. Either delete all DataVolumes or change the uninstall strategy before uninstalling CDI."
+const (
+	uninstallErrorMsg         = "Rejecting the uninstall request, since there are still DataVolumes present. Either delete all DataVolumes or change the uninstall strategy before uninstalling CDI."
+	uninstallTransferErrorMsg = "Rejecting the uninstall request, since there are still PVCs being populated by CDI. Either wait for the transfers to complete, delete the PVCs or change the uninstall strategy before uninstalling CDI."
+)
 
 type cdiValidatingWebhook struct {
-	client cdiclient.Interface
+	client    kubernetes.Interface
+	cdiClient cdiclient.Interface
 }
 
 func (wh *cdiValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
@@ -64,7 +71,7 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admi
 	}
 
 	if cdi.Spec.UninstallStrategy != nil && *cdi.Spec.UninstallStrategy == cdiv1.CDIUninstallStrategyBlockUninstallIfWorkloadsExist {
-		dvs, err := wh.client.CdiV1beta1().DataVolumes(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{Limit: 2})
+		dvs, err := wh.cdiClient.CdiV1beta1().DataVolumes(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{Limit: 2})
 		if err != nil {
 			return toAdmissionResponseError(err)
 		}
@@ -72,11 +79,63 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admi
 		if len(dvs.Items) > 0 {
 			return toAdmissionResponseError(fmt.Errorf(uninstallErrorMsg))
 		}
+
+		inFlight, err := wh.hasTransfersInFlight()
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+
+		if inFlight {
+			return toAdmissionResponseError(fmt.Errorf(uninstallTransferErrorMsg))
+		}
 	}
 
 	return allowedAdmissionResponse()
 }
 
+// hasTransfersInFlight returns true if a PVC requested an import, an upload or a clone without a DataVolume,
+// and its transfer neither completed nor failed for good
+func (wh *cdiValidatingWebhook) hasTransfersInFlight() (bool, error) {
+	pvcs, err := wh.client.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, pvc := range pvcs.Items {
+		if !isTransferTarget(&pvc) {
+			continue
+		}
+
+		if !isTransferDone(&pvc) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func isTransferTarget(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, ann := range []string{controller.AnnEndpoint, controller.AnnUploadRequest, controller.AnnCloneRequest} {
+		if _, ok := pvc.Annotations[ann]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransferDone returns true if the transfer of the PVC succeeded, was completed by a smart clone, which runs no pod,
+// or failed for good: a failed import waiting for its next attempt is pending again
+func isTransferDone(pvc *corev1.PersistentVolumeClaim) bool {
+	if pvc.Annotations[controller.AnnCloneOf] == "true" {
+		return true
+	}
+	switch corev1.PodPhase(pvc.Annotations[controller.AnnPodPhase]) {
+	case corev1.PodSucceeded, corev1.PodFailed:
+		return true
+	}
+	return false
+}
+
 func (wh *cdiValidatingWebhook) getResource(ar admissionv1beta1.AdmissionReview) (*cdiv1.CDI, error) {
 	var cdi *cdiv1.CDI
 
@@ -88,7 +147,7 @@ func (wh *cdiValidatingWebhook) getResource(ar admissionv1beta1.AdmissionReview)
 		}
 	} else if len(ar.Request.Name) > 0 {
 		var err error
-		cdi, err = wh.client.CdiV1beta1().CDIs().Get(context.TODO(), ar.Request.Name, metav1.GetOptions{})
+		cdi, err = wh.cdiClient.CdiV1beta1().CDIs().Get(context.TODO(), ar.Request.Name, metav1.GetOptions{})
 		if err != nil {
 			return nil, err
 		}
@@ -29,11 +29,14 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/api/admission/v1beta1"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
 )
 
 var (
@@ -227,6 +230,55 @@ var _ = Describe("CDI Delete Webhook", func() {
 			Expect(resp.Allowed).To(BeTrue())
 		})
 
+		DescribeTable("should check the PVCs populated without DataVolumes", func(strategy *cdiv1.CDIUninstallStrategy, pvc *corev1.PersistentVolumeClaim, allowed bool) {
+			cdi := &cdiv1.CDI{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cdi",
+				},
+				Spec: cdiv1.CDISpec{
+					UninstallStrategy: strategy,
+				},
+				Status: cdiv1.CDIStatus{
+					Status: sdkapi.Status{
+						Phase: sdkapi.PhaseDeployed,
+					},
+				},
+			}
+
+			bytes, _ := json.Marshal(cdi)
+
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Delete,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "cdis",
+					},
+					OldObject: runtime.RawExtension{
+						Raw: bytes,
+					},
+				},
+			}
+
+			resp := validateCDIs(ar, pvc)
+			Expect(resp.Allowed).To(Equal(allowed))
+			if !allowed {
+				Expect(resp.Result.Message).To(ContainSubstring("Rejecting the uninstall request, since there are still PVCs being populated by CDI."))
+			}
+		},
+			Entry("BLOCK upload in progress", &block, newPVCWithAnnotations(controller.AnnUploadRequest, "", controller.AnnPodPhase, string(corev1.PodRunning)), false),
+			Entry("BLOCK import not started", &block, newPVCWithAnnotations(controller.AnnEndpoint, "http://example.com/disk.img"), false),
+			Entry("BLOCK clone in progress", &block, newPVCWithAnnotations(controller.AnnCloneRequest, "ns/source", controller.AnnPodPhase, string(corev1.PodPending)), false),
+			Entry("BLOCK import completed", &block, newPVCWithAnnotations(controller.AnnEndpoint, "http://example.com/disk.img", controller.AnnPodPhase, string(corev1.PodSucceeded)), true),
+			Entry("BLOCK import failed for good", &block, newPVCWithAnnotations(controller.AnnEndpoint, "http://example.com/disk.img", controller.AnnPodPhase, string(corev1.PodFailed)), true),
+			Entry("BLOCK import waiting for its next attempt", &block, newPVCWithAnnotations(controller.AnnEndpoint, "http://example.com/disk.img", controller.AnnPodPhase, string(corev1.PodPending), controller.AnnImportAttempts, "1"), false),
+			Entry("BLOCK smart clone completed", &block, newPVCWithAnnotations(controller.AnnCloneRequest, "ns/source", controller.AnnSmartCloneRequest, "true", controller.AnnCloneOf, "true"), true),
+			Entry("BLOCK PVC not populated by CDI", &block, newPVCWithAnnotations(), true),
+			Entry("NO BLOCK upload in progress", &noBlock, newPVCWithAnnotations(controller.AnnUploadRequest, "", controller.AnnPodPhase, string(corev1.PodRunning)), true),
+			Entry("EMPTY upload in progress", nil, newPVCWithAnnotations(controller.AnnUploadRequest, "", controller.AnnPodPhase, string(corev1.PodRunning)), true),
+		)
+
 		It("should reject weird resource", func() {
 			bytes, _ := json.Marshal(newDataVolumeWithName("foo"))
 
@@ -259,8 +311,31 @@ func newDataVolumeWithName(name string) *cdiv1.DataVolume {
 	}
 }
 
-func validateCDIs(ar *admissionv1beta1.AdmissionReview, cdiObjects ...runtime.Object) *v1beta1.AdmissionResponse {
-	client := cdiclient.NewSimpleClientset(cdiObjects...)
-	wh := NewCDIValidatingWebhook(client)
+func newPVCWithAnnotations(keysAndValues ...string) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pvc",
+			Namespace:   "default",
+			Annotations: map[string]string{},
+		},
+	}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		pvc.Annotations[keysAndValues[i]] = keysAndValues[i+1]
+	}
+	return pvc
+}
+
+func validateCDIs(ar *admissionv1beta1.AdmissionReview, objects ...runtime.Object) *v1beta1.AdmissionResponse {
+	var k8sObjects, cdiObjects []runtime.Object
+	for _, obj := range objects {
+		if _, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+			k8sObjects = append(k8sObjects, obj)
+		} else {
+			cdiObjects = append(cdiObjects, obj)
+		}
+	}
+	client := fakeclient.NewSimpleClientset(k8sObjects...)
+	cdiClient := cdiclient.NewSimpleClientset(cdiObjects...)
+	wh := NewCDIValidatingWebhook(client, cdiClient)
 	return serve(ar, wh)
 }
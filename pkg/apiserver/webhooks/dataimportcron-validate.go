@@ -0,0 +1,190 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kvalidation "k8s.io/apimachinery/pkg/util/validation"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// digestRegexp matches the sha256 digests of the registry images
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+type dataImportCronValidatingWebhook struct {
+	dataVolumeValidatingWebhook
+}
+
+func (wh *dataImportCronValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
+	klog.V(3).Infof("Got AdmissionReview %+v", ar)
+
+	if ar.Request.Resource.Group != cdiv1.SchemeGroupVersion.Group || ar.Request.Resource.Resource != "dataimportcrons" {
+		klog.V(3).Infof("Got unexpected resource type %s", ar.Request.Resource.Resource)
+		return toAdmissionResponseError(fmt.Errorf("unexpected resource: %s", ar.Request.Resource.Resource))
+	}
+
+	dataImportCron := &cdiv1.DataImportCron{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, dataImportCron); err != nil {
+		return toAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1beta1.Update {
+		oldDataImportCron := &cdiv1.DataImportCron{}
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldDataImportCron); err != nil {
+			return toAdmissionResponseError(err)
+		}
+
+		// The controller updates the status, the unchanged spec was validated already
+		if reflect.DeepEqual(dataImportCron.Spec, oldDataImportCron.Spec) {
+			return allowedAdmissionResponse()
+		}
+	}
+
+	causes := wh.validateDataImportCronSpec(ar.Request, k8sfield.NewPath("spec"), &dataImportCron.Spec)
+	if len(causes) > 0 {
+		klog.Infof("rejected DataImportCron admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+
+	return allowedAdmissionResponse()
+}
+
+// validateDataImportCronSpec validates the DataVolume template like a DataVolume, with the credentials of the user
+// creating or updating the DataImportCron, and checks its source can be polled
+func (wh *dataImportCronValidatingWebhook) validateDataImportCronSpec(request *admissionv1beta1.AdmissionRequest, field *k8sfield.Path, spec *cdiv1.DataImportCronSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	// The controller creates the DataVolumes of the template on behalf of the user, so the template is validated like
+	// a DataVolume being created, checking the user may use its service account, on updates as well
+	templateRequest := request.DeepCopy()
+	templateRequest.Operation = admissionv1beta1.Create
+	templateField := field.Child("template", "spec")
+	causes = wh.validateDataVolumeSpec(templateRequest, templateField, &spec.Template.Spec)
+	if len(causes) > 0 {
+		return causes
+	}
+
+	source := &spec.Template.Spec.Source
+	switch {
+	case source.Registry != nil:
+		if !strings.HasPrefix(source.Registry.URL, "docker://") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be a docker:// url, image archives are not polled", templateField.Child("source", "registry", "url").String()),
+				Field:   templateField.Child("source", "registry", "url").String(),
+			})
+			return causes
+		}
+	case source.HTTP != nil:
+		if u, err := url.Parse(source.HTTP.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be an http(s) url, ftp sources are not polled", templateField.Child("source", "http", "url").String()),
+				Field:   templateField.Child("source", "http", "url").String(),
+			})
+			return causes
+		}
+	case source.S3 != nil:
+		if source.S3.ObjectVersionID != "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must not be set, the version of a polled object changes", templateField.Child("source", "s3", "objectVersionId").String()),
+				Field:   templateField.Child("source", "s3", "objectVersionId").String(),
+			})
+			return causes
+		}
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be a registry, http or s3 source, only those sources are polled", templateField.Child("source").String()),
+			Field:   templateField.Child("source").String(),
+		})
+		return causes
+	}
+
+	if fields := strings.Fields(spec.Schedule); len(fields) != 5 && (len(fields) != 1 || !strings.HasPrefix(fields[0], "@")) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Schedule %q must have the 5 fields of the cron format, or be a predefined schedule like @daily", spec.Schedule),
+			Field:   field.Child("schedule").String(),
+		})
+		return causes
+	}
+
+	if errs := kvalidation.IsDNS1123Subdomain(spec.ManagedDataSource); len(errs) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid DataSource name %q: %s", spec.ManagedDataSource, strings.Join(errs, ", ")),
+			Field:   field.Child("managedDataSource").String(),
+		})
+		return causes
+	}
+
+	if spec.ImportsToKeep != nil && *spec.ImportsToKeep < 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Imports to keep must be at least 1"),
+			Field:   field.Child("importsToKeep").String(),
+		})
+		return causes
+	}
+
+	if spec.RetentionPeriod != nil && spec.RetentionPeriod.Duration <= 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Retention period must be positive"),
+			Field:   field.Child("retentionPeriod").String(),
+		})
+		return causes
+	}
+
+	if spec.PinnedDigest != "" && !digestRegexp.MatchString(spec.PinnedDigest) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Pinned digest %q must be a sha256 digest, like sha256:<64 hex characters>", spec.PinnedDigest),
+			Field:   field.Child("pinnedDigest").String(),
+		})
+		return causes
+	}
+
+	if notification := spec.Notification; notification != nil {
+		if u, err := url.Parse(notification.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Notification url %q must be an http(s) url", notification.URL),
+				Field:   field.Child("notification", "url").String(),
+			})
+			return causes
+		}
+	}
+
+	return causes
+}
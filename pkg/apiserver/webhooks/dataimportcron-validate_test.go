@@ -0,0 +1,181 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+)
+
+var _ = Describe("DataImportCron Webhook", func() {
+	Context("with DataImportCron admission review", func() {
+		newDataImportCron := func(mutate func(*cdiv1.DataImportCron)) *cdiv1.DataImportCron {
+			dataImportCron := &cdiv1.DataImportCron{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fedora",
+					Namespace: "golden-images",
+				},
+				Spec: cdiv1.DataImportCronSpec{
+					Template:          *newRegistryDataVolume("fedora", "docker://quay.io/containerdisks/fedora:latest"),
+					Schedule:          "0 */12 * * *",
+					ManagedDataSource: "fedora",
+				},
+			}
+			if mutate != nil {
+				mutate(dataImportCron)
+			}
+			return dataImportCron
+		}
+
+		admissionReview := func(operation admissionv1beta1.Operation, dataImportCron, old *cdiv1.DataImportCron) *admissionv1beta1.AdmissionReview {
+			bytes, _ := json.Marshal(dataImportCron)
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: operation,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "dataimportcrons",
+					},
+					Object: runtime.RawExtension{
+						Raw: bytes,
+					},
+				},
+			}
+			if old != nil {
+				oldBytes, _ := json.Marshal(old)
+				ar.Request.OldObject = runtime.RawExtension{Raw: oldBytes}
+			}
+			return ar
+		}
+
+		DescribeTable("should", func(mutate func(*cdiv1.DataImportCron), allowed bool) {
+			resp := validateDataImportCron(admissionReview(admissionv1beta1.Create, newDataImportCron(mutate), nil))
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a registry DataImportCron", nil, true),
+			Entry("accept a predefined schedule", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Schedule = "@daily"
+			}, true),
+			Entry("accept the retention of the imports", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+				dataImportCron.Spec.RetentionPeriod = &metav1.Duration{Duration: 7 * 24 * time.Hour}
+			}, true),
+			Entry("accept a pinned digest", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.PinnedDigest = "sha256:" + strings.Repeat("4a", 32)
+			}, true),
+			Entry("reject a pinned digest which is not a sha256 digest", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.PinnedDigest = "latest"
+			}, false),
+			Entry("accept a notification webhook", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Notification = &cdiv1.DataImportCronNotification{URL: "https://ci.example.com/hooks/golden-images", CertConfigMap: "ci-ca"}
+			}, true),
+			Entry("reject a notification webhook which is not an http(s) url", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Notification = &cdiv1.DataImportCronNotification{URL: "ci.example.com/hooks/golden-images"}
+			}, false),
+			Entry("accept an http source", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template = *newHTTPDataVolume("fedora", "https://www.example.com/fedora.qcow2")
+			}, true),
+			Entry("reject an ftp source", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template = *newHTTPDataVolume("fedora", "ftp://ftp.example.com/fedora.qcow2")
+			}, false),
+			Entry("accept an s3 source", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2"}}
+			}, true),
+			Entry("reject an s3 source pinned to an object version", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2", ObjectVersionID: "3HL4kqtJlcpXroDTDmJ"}}
+			}, false),
+			Entry("reject a source which is not polled", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{Blank: &cdiv1.DataVolumeBlankImage{}}
+			}, false),
+			Entry("reject an image archive", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.Source.Registry.URL = "oci-archive:///images/fedora.tar"
+			}, false),
+			Entry("reject a template the DataVolume webhook rejects", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.PVC = nil
+			}, false),
+			Entry("reject a schedule which is not in the cron format", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Schedule = "every day"
+			}, false),
+			Entry("reject an invalid DataSource name", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.ManagedDataSource = "Fedora_Latest"
+			}, false),
+			Entry("reject keeping no import", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.ImportsToKeep = &[]int32{0}[0]
+			}, false),
+			Entry("reject a negative retention period", func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.RetentionPeriod = &metav1.Duration{Duration: -time.Hour}
+			}, false),
+		)
+
+		It("should accept an update of the status", func() {
+			old := newDataImportCron(nil)
+			dataImportCron := old.DeepCopy()
+			dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+			resp := validateDataImportCron(admissionReview(admissionv1beta1.Update, dataImportCron, old))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject an update of the spec with an invalid schedule", func() {
+			old := newDataImportCron(nil)
+			dataImportCron := old.DeepCopy()
+			dataImportCron.Spec.Schedule = "weekly"
+			resp := validateDataImportCron(admissionReview(admissionv1beta1.Update, dataImportCron, old))
+			Expect(resp.Allowed).To(BeFalse())
+		})
+
+		It("should check the user may use the service account of the template on update", func() {
+			old := newDataImportCron(func(dataImportCron *cdiv1.DataImportCron) {
+				dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2"}}
+			})
+			dataImportCron := old.DeepCopy()
+			dataImportCron.Spec.Template.Spec.Source.S3.ServiceAccountName = "importer"
+			// The fake client does not allow the subject access reviews
+			resp := validateDataImportCron(admissionReview(admissionv1beta1.Update, dataImportCron, old))
+			Expect(resp.Allowed).To(BeFalse())
+		})
+
+		It("should reject another resource", func() {
+			ar := admissionReview(admissionv1beta1.Create, newDataImportCron(nil), nil)
+			ar.Request.Resource.Resource = "datavolumes"
+			resp := validateDataImportCron(ar)
+			Expect(resp.Allowed).To(BeFalse())
+		})
+	})
+})
+
+func validateDataImportCron(ar *admissionv1beta1.AdmissionReview, objects ...runtime.Object) *admissionv1beta1.AdmissionResponse {
+	client := fakeclient.NewSimpleClientset(objects...)
+	wh := NewDataImportCronValidatingWebhook(client, cdiclient.NewSimpleClientset())
+	return serve(ar, wh)
+}
@@ -22,15 +22,21 @@ package webhooks
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
 	"kubevirt.io/containerized-data-importer/pkg/clone"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
 	"kubevirt.io/containerized-data-importer/pkg/token"
@@ -38,6 +44,7 @@ import (
 
 type dataVolumeMutatingWebhook struct {
 	client         kubernetes.Interface
+	cdiClient      cdiclient.Interface
 	tokenGenerator token.Generator
 	proxy          clone.SubjectAccessReviewsProxy
 }
@@ -52,6 +59,12 @@ var (
 		Version:  "v1",
 		Resource: "persistentvolumeclaims",
 	}
+
+	snapshotTokenResource = metav1.GroupVersionResource{
+		Group:    "snapshot.storage.k8s.io",
+		Version:  "v1beta1",
+		Resource: "volumesnapshots",
+	}
 )
 
 func (p *sarProxy) Create(sar *authv1.SubjectAccessReview) (*authv1.SubjectAccessReview, error) {
@@ -71,7 +84,6 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 		return toAdmissionResponseError(err)
 	}
 
-	pvcSource := dataVolume.Spec.Source.PVC
 	targetNamespace, targetName := dataVolume.Namespace, dataVolume.Name
 	if targetNamespace == "" {
 		targetNamespace = ar.Request.Namespace
@@ -81,9 +93,42 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 		targetName = ar.Request.Name
 	}
 
+	modifiedDataVolume := dataVolume.DeepCopy()
+	if ar.Request.Operation == admissionv1beta1.Create {
+		if dataVolume.Spec.SourceRef != nil {
+			causes, err := wh.resolveDataSourceRef(modifiedDataVolume, targetNamespace)
+			if err != nil {
+				return toAdmissionResponseError(err)
+			}
+			if len(causes) > 0 {
+				klog.Infof("rejected DataVolume admission %s", causes)
+				return toRejectedAdmissionResponse(causes)
+			}
+		}
+		if err := wh.applyStorageProfile(modifiedDataVolume); err != nil {
+			return toAdmissionResponseError(err)
+		}
+	}
+
+	// The source resolved from a DataSource is cloned like any PVC source
+	pvcSource, pvcSourceField := modifiedDataVolume.Spec.Source.PVC, "PVC"
+	if modifiedDataVolume.Spec.Source.PVCNetwork != nil {
+		// Copying over the network needs the same permissions on the source as cloning
+		pvcSource, pvcSourceField = modifiedDataVolume.Spec.Source.PVCNetwork, "PVCNetwork"
+	}
+	sourceResource := tokenResource
+	if snapshot := modifiedDataVolume.Spec.Source.Snapshot; snapshot != nil {
+		// Copying a snapshot to another namespace or storage class needs the same permissions on the source as cloning
+		pvcSource, pvcSourceField = &cdiv1.DataVolumeSourcePVC{Namespace: snapshot.Namespace, Name: snapshot.Name}, "Snapshot"
+		sourceResource = snapshotTokenResource
+	}
+
 	if pvcSource == nil {
 		klog.V(3).Infof("DataVolume %s/%s not cloning", targetNamespace, targetName)
-		return allowedAdmissionResponse()
+		if reflect.DeepEqual(dataVolume.Spec, modifiedDataVolume.Spec) {
+			return allowedAdmissionResponse()
+		}
+		return toPatchResponse(dataVolume, modifiedDataVolume)
 	}
 
 	sourceNamespace, sourceName := pvcSource.Namespace, pvcSource.Name
@@ -101,6 +146,29 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 			klog.V(3).Infof("DataVolume %s/%s already has clone token", targetNamespace, targetName)
 			return allowedAdmissionResponse()
 		}
+
+		_, ok = oldDataVolume.Annotations[controller.AnnCloneGrant]
+		if ok {
+			klog.V(3).Infof("DataVolume %s/%s already has clone grant", targetNamespace, targetName)
+			return allowedAdmissionResponse()
+		}
+	}
+
+	if modifiedDataVolume.Annotations == nil {
+		modifiedDataVolume.Annotations = make(map[string]string)
+	}
+
+	if sourceResource == tokenResource && sourceNamespace != targetNamespace {
+		grant, err := wh.findCloneGrant(sourceNamespace, sourceName, targetNamespace)
+		if err != nil {
+			return toAdmissionResponseError(err)
+		}
+
+		if grant != nil {
+			klog.V(3).Infof("DataVolume %s/%s allowed to clone by CloneGrant %s/%s", targetNamespace, targetName, grant.Namespace, grant.Name)
+			modifiedDataVolume.Annotations[controller.AnnCloneGrant] = grant.Name
+			return toPatchResponse(dataVolume, modifiedDataVolume)
+		}
 	}
 
 	ok, reason, err := clone.CanUserClonePVC(wh.proxy, sourceNamespace, sourceName, targetNamespace, ar.Request.UserInfo)
@@ -113,7 +181,7 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 			{
 				Type:    metav1.CauseTypeFieldValueInvalid,
 				Message: reason,
-				Field:   k8sfield.NewPath("spec", "source", "PVC", "namespace").String(),
+				Field:   k8sfield.NewPath("spec", "source", pvcSourceField, "namespace").String(),
 			},
 		}
 		return toRejectedAdmissionResponse(causes)
@@ -123,7 +191,7 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 		Operation: token.OperationClone,
 		Name:      sourceName,
 		Namespace: sourceNamespace,
-		Resource:  tokenResource,
+		Resource:  sourceResource,
 		Params: map[string]string{
 			"targetNamespace": targetNamespace,
 			"targetName":      targetName,
@@ -135,14 +203,165 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1beta1.AdmissionReview)
 		return toAdmissionResponseError(err)
 	}
 
-	modifiedDataVolume := dataVolume.DeepCopy()
-	if modifiedDataVolume.Annotations == nil {
-		modifiedDataVolume.Annotations = make(map[string]string)
-	}
-
 	modifiedDataVolume.Annotations[controller.AnnCloneToken] = token
 
 	klog.V(3).Infof("Sending patch response...")
 
 	return toPatchResponse(dataVolume, modifiedDataVolume)
 }
+
+// resolveDataSourceRef sets the source of the DataVolume to the ready source of the DataSource it references, the
+// DataVolume is rejected when the DataSource does not exist or is not ready
+func (wh *dataVolumeMutatingWebhook) resolveDataSourceRef(dataVolume *cdiv1.DataVolume, targetNamespace string) ([]metav1.StatusCause, error) {
+	field := k8sfield.NewPath("spec", "sourceRef")
+	sourceRef := dataVolume.Spec.SourceRef
+	if sourceRef.Kind != cdiv1.DataVolumeDataSource {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("Unsupported sourceRef kind %q, only %s is supported", sourceRef.Kind, cdiv1.DataVolumeDataSource),
+			Field:   field.Child("kind").String(),
+		}}, nil
+	}
+	if !reflect.DeepEqual(dataVolume.Spec.Source, cdiv1.DataVolumeSource{}) {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Data volume source and sourceRef are mutually exclusive",
+			Field:   field.String(),
+		}}, nil
+	}
+
+	namespace := targetNamespace
+	if sourceRef.Namespace != nil && *sourceRef.Namespace != "" {
+		namespace = *sourceRef.Namespace
+	}
+	dataSource, err := wh.cdiClient.CdiV1beta1().DataSources(namespace).Get(context.TODO(), sourceRef.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueNotFound,
+				Message: fmt.Sprintf("DataSource %s/%s not found", namespace, sourceRef.Name),
+				Field:   field.Child("name").String(),
+			}}, nil
+		}
+		return nil, err
+	}
+
+	if dataSource.Status.Source == nil || dataSource.Status.Source.PVC == nil {
+		message := fmt.Sprintf("DataSource %s/%s is not ready", namespace, sourceRef.Name)
+		for _, condition := range dataSource.Status.Conditions {
+			if condition.Type == cdiv1.DataVolumeReady {
+				message = fmt.Sprintf("%s: %s, %s", message, condition.Reason, condition.Message)
+			}
+		}
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: message,
+			Field:   field.Child("name").String(),
+		}}, nil
+	}
+
+	klog.V(3).Infof("DataVolume %s/%s source resolved from DataSource %s/%s to PVC %s/%s", targetNamespace, dataVolume.Name,
+		namespace, sourceRef.Name, dataSource.Status.Source.PVC.Namespace, dataSource.Status.Source.PVC.Name)
+	dataVolume.Spec.Source.PVC = dataSource.Status.Source.PVC.DeepCopy()
+	return nil, nil
+}
+
+// findCloneGrant returns a CloneGrant of the source namespace allowing the target namespace to clone the source PVC,
+// nil if there is none or the source PVC does not exist yet
+func (wh *dataVolumeMutatingWebhook) findCloneGrant(sourceNamespace, sourceName, targetNamespace string) (*cdiv1.CloneGrant, error) {
+	grants, err := wh.cdiClient.CdiV1beta1().CloneGrants(sourceNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(grants.Items) == 0 {
+		return nil, nil
+	}
+
+	source, err := wh.client.CoreV1().PersistentVolumeClaims(sourceNamespace).Get(context.TODO(), sourceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		ok, reason, err := controller.CloneGrantAllows(grant, source, targetNamespace, now)
+		if err != nil {
+			klog.Warningf("Ignoring CloneGrant %s/%s: %v", grant.Namespace, grant.Name, err)
+			continue
+		}
+
+		if ok {
+			return grant, nil
+		}
+
+		klog.V(3).Infof("%s", reason)
+	}
+
+	return nil, nil
+}
+
+// applyStorageProfile fills the access mode and the volume mode of the PVC of the DataVolume from the claim property
+// sets of the StorageProfile of its storage class, when the PVC has no access mode
+func (wh *dataVolumeMutatingWebhook) applyStorageProfile(dataVolume *cdiv1.DataVolume) error {
+	pvcSpec := dataVolume.Spec.PVC
+	if pvcSpec == nil || len(pvcSpec.AccessModes) > 0 {
+		return nil
+	}
+
+	storageClassName, err := wh.getStorageClassName(pvcSpec.StorageClassName)
+	if err != nil || storageClassName == "" {
+		return err
+	}
+
+	storageProfile, err := wh.cdiClient.CdiV1beta1().StorageProfiles().Get(context.TODO(), storageClassName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, claimPropertySet := range storageProfile.Status.ClaimPropertySets {
+		if len(claimPropertySet.AccessModes) == 0 {
+			continue
+		}
+		if pvcSpec.VolumeMode != nil && claimPropertySet.VolumeMode != nil && *pvcSpec.VolumeMode != *claimPropertySet.VolumeMode {
+			continue
+		}
+		klog.V(3).Infof("DataVolume %s/%s uses the claim properties of StorageProfile %s", dataVolume.Namespace, dataVolume.Name, storageProfile.Name)
+		// DataVolumes support a single access mode
+		pvcSpec.AccessModes = []corev1.PersistentVolumeAccessMode{claimPropertySet.AccessModes[0]}
+		if pvcSpec.VolumeMode == nil && claimPropertySet.VolumeMode != nil {
+			volumeMode := *claimPropertySet.VolumeMode
+			pvcSpec.VolumeMode = &volumeMode
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// getStorageClassName returns the name of the storage class, or of the default storage class when unset
+func (wh *dataVolumeMutatingWebhook) getStorageClassName(storageClassName *string) (string, error) {
+	if storageClassName != nil {
+		return *storageClassName, nil
+	}
+
+	storageClasses, err := wh.client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[controller.AnnDefaultStorageClass] == "true" {
+			return storageClass.Name, nil
+		}
+	}
+
+	return "", nil
+}
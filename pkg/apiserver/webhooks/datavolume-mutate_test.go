@@ -24,6 +24,7 @@ import (
 	"crypto/rsa"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -32,12 +33,15 @@ import (
 	"github.com/appscode/jsonpatch"
 	"k8s.io/api/admission/v1beta1"
 	authorization "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 
 	cdicorev1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
 )
 
@@ -129,8 +133,36 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Expect(resp.Patch).To(BeNil())
 		})
 
-		DescribeTable("should", func(srcNamespace string) {
+		It("should reject a network copy DataVolume", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVCNetwork = dataVolume.Spec.Source.PVC
+			dataVolume.Spec.Source.PVC = nil
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			resp := mutateDVs(key, ar, false)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.source.PVCNetwork.namespace"))
+		})
+
+		DescribeTable("should", func(srcNamespace string, network bool) {
 			dataVolume := newPVCDataVolume("testDV", srcNamespace, "test")
+			if network {
+				dataVolume.Spec.Source.PVCNetwork = dataVolume.Spec.Source.PVC
+				dataVolume.Spec.Source.PVC = nil
+			}
 			dvBytes, _ := json.Marshal(&dataVolume)
 
 			ar := &v1beta1.AdmissionReview{
@@ -158,15 +190,245 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Expect(patchObjs[0].Path).Should(Equal("/metadata/annotations"))
 
 		},
-			Entry("succeed with explicit namespace", "testNamespace"),
-			Entry("succeed with same (default) namespace", "default"),
-			Entry("succeed with empty namespace", ""),
+			Entry("succeed with explicit namespace", "testNamespace", false),
+			Entry("succeed with same (default) namespace", "default", false),
+			Entry("succeed with empty namespace", "", false),
+			Entry("succeed with explicit namespace over the network", "testNamespace", true),
+		)
+
+		DescribeTable("with a CloneGrant in the source namespace", func(modifyGrant func(*cdicorev1.CloneGrant), sourceExists, allowed bool) {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Namespace: "default",
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			grant := &cdicorev1.CloneGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "grant",
+					Namespace: "testNamespace",
+				},
+				Spec: cdicorev1.CloneGrantSpec{
+					TargetNamespace: "default",
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "golden"},
+					},
+				},
+			}
+			modifyGrant(grant)
+			objs := []runtime.Object{grant}
+			if sourceExists {
+				objs = append(objs, &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "testNamespace",
+						Labels:    map[string]string{"app": "golden"},
+					},
+				})
+			}
+
+			resp := mutateDVs(key, ar, false, objs...)
+			Expect(resp.Allowed).To(Equal(allowed))
+			if !allowed {
+				Expect(resp.Patch).To(BeNil())
+				return
+			}
+
+			var patchObjs []jsonpatch.Operation
+			err := json.Unmarshal(resp.Patch, &patchObjs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(patchObjs).Should(HaveLen(1))
+			Expect(patchObjs[0].Path).Should(Equal("/metadata/annotations"))
+			Expect(patchObjs[0].Value).To(HaveKeyWithValue(controller.AnnCloneGrant, "grant"))
+			Expect(patchObjs[0].Value).ToNot(HaveKey(controller.AnnCloneToken))
+		},
+			Entry("allow the clone without a token", func(grant *cdicorev1.CloneGrant) {}, true, true),
+			Entry("allow the clone before the grant expires", func(grant *cdicorev1.CloneGrant) {
+				grant.Spec.ExpirationTime = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			}, true, true),
+			Entry("reject the clone once the grant expired", func(grant *cdicorev1.CloneGrant) {
+				grant.Spec.ExpirationTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			}, true, false),
+			Entry("reject the clone to another namespace", func(grant *cdicorev1.CloneGrant) {
+				grant.Spec.TargetNamespace = "other"
+			}, true, false),
+			Entry("reject the clone of a PVC not selected", func(grant *cdicorev1.CloneGrant) {
+				grant.Spec.Selector.MatchLabels["app"] = "other"
+			}, true, false),
+			Entry("reject the clone of a PVC that does not exist", func(grant *cdicorev1.CloneGrant) {}, false, false),
+		)
+
+		DescribeTable("with a DataSource sourceRef", func(modifyDataSource func(*cdicorev1.DataSource), modifyDataVolume func(*cdicorev1.DataVolume), message string) {
+			dataVolume := newPVCDataVolume("testDV", "", "")
+			dataVolume.Spec.Source = cdicorev1.DataVolumeSource{}
+			dataVolume.Spec.SourceRef = &cdicorev1.DataVolumeSourceRef{Kind: cdicorev1.DataVolumeDataSource, Namespace: &[]string{"golden-images"}[0], Name: "fedora"}
+			modifyDataVolume(dataVolume)
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Create,
+					Namespace: "default",
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			dataSource := &cdicorev1.DataSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fedora",
+					Namespace: "golden-images",
+				},
+				Spec: cdicorev1.DataSourceSpec{
+					Source: cdicorev1.DataSourceSource{PVC: &cdicorev1.DataVolumeSourcePVC{Namespace: "golden-images", Name: "fedora-2"}},
+				},
+				Status: cdicorev1.DataSourceStatus{
+					Source: &cdicorev1.DataSourceSource{PVC: &cdicorev1.DataVolumeSourcePVC{Namespace: "golden-images", Name: "fedora-1"}},
+					Conditions: []cdicorev1.DataVolumeCondition{{
+						Type:    cdicorev1.DataVolumeReady,
+						Status:  corev1.ConditionTrue,
+						Reason:  cdicorev1.DataSourceFallback,
+						Message: "Fallback PVC golden-images/fedora-1 is used: PVC golden-images/fedora-2 not found",
+					}},
+				},
+			}
+			modifyDataSource(dataSource)
+
+			resp := mutateDVs(key, ar, true, dataSource)
+			if message != "" {
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes[0].Message).To(Equal(message))
+				return
+			}
+			Expect(resp.Allowed).To(BeTrue())
+
+			var patchObjs []jsonpatch.Operation
+			err := json.Unmarshal(resp.Patch, &patchObjs)
+			Expect(err).ToNot(HaveOccurred())
+			patchedValues := map[string]interface{}{}
+			for _, patchObj := range patchObjs {
+				patchedValues[patchObj.Path] = patchObj.Value
+			}
+			Expect(patchedValues).To(HaveKeyWithValue("/spec/source/pvc", map[string]interface{}{"namespace": "golden-images", "name": "fedora-1"}))
+			Expect(patchedValues).To(HaveKey("/metadata/annotations"))
+			Expect(patchedValues["/metadata/annotations"]).To(HaveKey(controller.AnnCloneToken))
+		},
+			Entry("resolve the ready source of the DataSource and clone it", func(dataSource *cdicorev1.DataSource) {}, func(dataVolume *cdicorev1.DataVolume) {}, ""),
+			Entry("reject a DataSource which is not ready", func(dataSource *cdicorev1.DataSource) {
+				dataSource.Status.Source = nil
+				dataSource.Status.Conditions[0].Status = corev1.ConditionFalse
+				dataSource.Status.Conditions[0].Reason = cdicorev1.DataSourceNotFound
+				dataSource.Status.Conditions[0].Message = "PVC golden-images/fedora-2 not found"
+			}, func(dataVolume *cdicorev1.DataVolume) {}, "DataSource golden-images/fedora is not ready: NotFound, PVC golden-images/fedora-2 not found"),
+			Entry("reject a DataSource which does not exist", func(dataSource *cdicorev1.DataSource) {
+				dataSource.Namespace = "default"
+			}, func(dataVolume *cdicorev1.DataVolume) {}, "DataSource golden-images/fedora not found"),
+			Entry("reject a sourceRef along with a source", func(dataSource *cdicorev1.DataSource) {}, func(dataVolume *cdicorev1.DataVolume) {
+				dataVolume.Spec.Source.HTTP = &cdicorev1.DataVolumeSourceHTTP{URL: "http://www.example.com"}
+			}, "Data volume source and sourceRef are mutually exclusive"),
+			Entry("reject another kind", func(dataSource *cdicorev1.DataSource) {}, func(dataVolume *cdicorev1.DataVolume) {
+				dataVolume.Spec.SourceRef.Kind = "PersistentVolumeClaim"
+			}, `Unsupported sourceRef kind "PersistentVolumeClaim", only DataSource is supported`),
+		)
+
+		DescribeTable("with a StorageProfile", func(storageClassName *string, volumeMode *corev1.PersistentVolumeMode, expectedAccessMode corev1.PersistentVolumeAccessMode, expectedVolumeMode corev1.PersistentVolumeMode) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.PVC.AccessModes = nil
+			dataVolume.Spec.PVC.StorageClassName = storageClassName
+			dataVolume.Spec.PVC.VolumeMode = volumeMode
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			blockMode := corev1.PersistentVolumeBlock
+			filesystemMode := corev1.PersistentVolumeFilesystem
+			storageProfile := &cdicorev1.StorageProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "default-sc",
+				},
+				Status: cdicorev1.StorageProfileStatus{
+					ClaimPropertySets: []cdicorev1.ClaimPropertySet{
+						{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany, corev1.ReadWriteOnce}, VolumeMode: &blockMode},
+						{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: &filesystemMode},
+					},
+				},
+			}
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default-sc",
+					Annotations: map[string]string{controller.AnnDefaultStorageClass: "true"},
+				},
+			}
+
+			resp := mutateDVs(key, ar, true, storageProfile, storageClass)
+			Expect(resp.Allowed).To(BeTrue())
+			if expectedAccessMode == "" {
+				Expect(resp.Patch).To(BeNil())
+				return
+			}
+
+			var patchObjs []jsonpatch.Operation
+			err := json.Unmarshal(resp.Patch, &patchObjs)
+			Expect(err).ToNot(HaveOccurred())
+			patchedValues := map[string]interface{}{}
+			for _, patchObj := range patchObjs {
+				patchedValues[patchObj.Path] = patchObj.Value
+			}
+			Expect(patchedValues).To(HaveKeyWithValue("/spec/pvc/accessModes", []interface{}{string(expectedAccessMode)}))
+			if volumeMode == nil {
+				Expect(patchedValues).To(HaveKeyWithValue("/spec/pvc/volumeMode", string(expectedVolumeMode)))
+			} else {
+				Expect(patchedValues).ToNot(HaveKey("/spec/pvc/volumeMode"))
+			}
+		},
+			Entry("fill the access and volume modes of the default storage class", nil, nil, corev1.ReadWriteMany, corev1.PersistentVolumeBlock),
+			Entry("fill the access mode of the requested volume mode", nil, &[]corev1.PersistentVolumeMode{corev1.PersistentVolumeFilesystem}[0], corev1.ReadWriteOnce, corev1.PersistentVolumeFilesystem),
+			Entry("fill the access and volume modes of the named storage class", &[]string{"default-sc"}[0], nil, corev1.ReadWriteMany, corev1.PersistentVolumeBlock),
+			Entry("not patch a storage class without StorageProfile", &[]string{"other-sc"}[0], nil, corev1.PersistentVolumeAccessMode(""), corev1.PersistentVolumeMode("")),
 		)
 	})
 })
 
-func mutateDVs(key *rsa.PrivateKey, ar *v1beta1.AdmissionReview, isAuthorized bool) *v1beta1.AdmissionResponse {
-	client := fakeclient.NewSimpleClientset()
+func mutateDVs(key *rsa.PrivateKey, ar *v1beta1.AdmissionReview, isAuthorized bool, objs ...runtime.Object) *v1beta1.AdmissionResponse {
+	var k8sObjs, cdiObjs []runtime.Object
+	for _, obj := range objs {
+		switch obj.(type) {
+		case *cdicorev1.CloneGrant, *cdicorev1.StorageProfile, *cdicorev1.DataSource:
+			cdiObjs = append(cdiObjs, obj)
+		default:
+			k8sObjs = append(k8sObjs, obj)
+		}
+	}
+	client := fakeclient.NewSimpleClientset(k8sObjs...)
 	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
 		if action.GetResource().Resource != "subjectaccessreviews" {
 			return false, nil, nil
@@ -180,6 +442,6 @@ func mutateDVs(key *rsa.PrivateKey, ar *v1beta1.AdmissionReview, isAuthorized bo
 		}
 		return true, sar, nil
 	})
-	wh := NewDataVolumeMutatingWebhook(client, key)
+	wh := NewDataVolumeMutatingWebhook(client, cdiclient.NewSimpleClientset(cdiObjs...), key)
 	return serve(ar, wh)
 }
@@ -24,9 +24,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"k8s.io/api/admission/v1beta1"
+	authv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,11 +42,28 @@ import (
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+var (
+	azureDiskResourceID = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/(disks|snapshots)/[^/]+$`)
+	awsSnapshotID       = regexp.MustCompile(`^snap-[0-9a-f]+$`)
+	awsImageID          = regexp.MustCompile(`^ami-[0-9a-f]+$`)
+	iscsiQualifiedName  = regexp.MustCompile(`^(iqn\.[0-9]{4}-[0-9]{2}\.[^\s/]+|eui\.[0-9A-Fa-f]{16}|naa\.[0-9A-Fa-f]{16}([0-9A-Fa-f]{16})?)$`)
+	gceImagePath        = regexp.MustCompile(`^projects/[^/]+/global/images/(family/)?[^/]+$`)
+	glanceImageID       = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	proxmoxDisk         = regexp.MustCompile(`^(ide|sata|scsi|virtio)[0-9]+$`)
+	libvirtDiskTarget   = regexp.MustCompile(`^[a-z]+[0-9a-z]*$`)
+	registryPlatform    = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+	filesystemOverhead  = regexp.MustCompile(`^0(\.\d{1,3})?$`)
 )
 
 type dataVolumeValidatingWebhook struct {
-	client kubernetes.Interface
+	client    kubernetes.Interface
+	cdiClient cdiclient.Interface
 }
 
 func validateSourceURL(sourceURL string) string {
@@ -58,6 +80,147 @@ func validateSourceURL(sourceURL string) string {
 	return ""
 }
 
+// validateHTTPSourceURL validates the URL of the http source, which also accepts ftp and ftps URLs.
+func validateHTTPSourceURL(sourceURL string) string {
+	if isFTPURL(sourceURL) {
+		return ""
+	}
+	return validateSourceURL(sourceURL)
+}
+
+func isFTPURL(sourceURL string) bool {
+	url, err := url.ParseRequestURI(sourceURL)
+	return err == nil && (url.Scheme == "ftp" || url.Scheme == "ftps") && url.Host != ""
+}
+
+// isLibvirtSSHURI returns true for qemu+ssh:// URIs with a host, the user name is taken from the secret.
+func isLibvirtSSHURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme == "qemu+ssh" && u.Hostname() != "" && u.User == nil
+}
+
+func validateGCSURL(sourceURL string) string {
+	if sourceURL == "" {
+		return "source URL is empty"
+	}
+	url, err := url.Parse(sourceURL)
+	if err != nil {
+		return fmt.Sprintf("Invalid source URL: %s", sourceURL)
+	}
+	if url.Scheme != "gs" || url.Host == "" || strings.TrimPrefix(url.Path, "/") == "" {
+		return fmt.Sprintf("Invalid source URL, expected gs://bucket/object: %s", sourceURL)
+	}
+	return ""
+}
+
+func validateAWSSnapshot(field *k8sfield.Path, spec *cdiv1.DataVolumeSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	source := spec.Source.AWSSnapshot
+	if !awsSnapshotID.MatchString(source.SnapshotID) && !awsImageID.MatchString(source.SnapshotID) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is not the ID of an EBS snapshot or AMI", field.Child("snapshotId").String()),
+			Field:   field.Child("snapshotId").String(),
+		})
+	}
+	if source.Region == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s is required", field.Child("region").String()),
+			Field:   field.Child("region").String(),
+		})
+	}
+	// changed blocks can only be listed between snapshots of the same volume, the checkpoints are snapshot IDs
+	for i, checkpoint := range spec.Checkpoints {
+		if !awsSnapshotID.MatchString(checkpoint.Current) || (checkpoint.Previous != "" && !awsSnapshotID.MatchString(checkpoint.Previous)) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("checkpoint %d of %s must reference EBS snapshot IDs", i, field.String()),
+				Field:   k8sfield.NewPath("spec", "checkpoints").Index(i).String(),
+			})
+		}
+	}
+	return causes
+}
+
+// validateFileSourceHostPath checks the host path of a File source is under the fileSourceHostPathPrefixes of the CDIConfig
+func (wh *dataVolumeValidatingWebhook) validateFileSourceHostPath(field *k8sfield.Path, hostPath string) *metav1.StatusCause {
+	var prefixes []string
+	config, err := wh.cdiClient.CdiV1beta1().CDIConfigs().Get(context.TODO(), common.ConfigName, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: err.Error(),
+			Field:   field.String(),
+		}
+	}
+	if err == nil {
+		prefixes = config.Spec.FileSourceHostPathPrefixes
+	}
+	if !util.HostPathAllowed(hostPath, prefixes) {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s is not under the fileSourceHostPathPrefixes of the CDIConfig", field.String()),
+			Field:   field.String(),
+		}
+	}
+	return nil
+}
+
+// importServiceAccount returns the service account the importer pod of the source runs with, and its field.
+func importServiceAccount(field *k8sfield.Path, source *cdiv1.DataVolumeSource) (string, *k8sfield.Path) {
+	switch {
+	case source.S3 != nil:
+		return source.S3.ServiceAccountName, field.Child("S3", "serviceAccountName")
+	case source.AWSSnapshot != nil:
+		return source.AWSSnapshot.ServiceAccountName, field.Child("AWSSnapshot", "serviceAccountName")
+	case source.GCS != nil:
+		return source.GCS.ServiceAccountName, field.Child("GCS", "serviceAccountName")
+	case source.GCEImage != nil:
+		return source.GCEImage.ServiceAccountName, field.Child("GCEImage", "serviceAccountName")
+	case source.AzureBlob != nil:
+		return source.AzureBlob.ServiceAccountName, field.Child("AzureBlob", "serviceAccountName")
+	case source.AzureDisk != nil:
+		return source.AzureDisk.ServiceAccountName, field.Child("AzureDisk", "serviceAccountName")
+	}
+	return "", nil
+}
+
+// canUseServiceAccount checks the user creating the DataVolume may impersonate the service account, the importer pod
+// gets the cloud credentials of the service account. The CDI controller creates the DataVolumes of the DataImportCrons,
+// whose users were checked by the DataImportCron webhook.
+func (wh *dataVolumeValidatingWebhook) canUseServiceAccount(request *v1beta1.AdmissionRequest, serviceAccountName string) (bool, error) {
+	if request.UserInfo.Username == fmt.Sprintf("system:serviceaccount:%s:%s", util.GetNamespace(), common.ControllerServiceAccountName) {
+		return true, nil
+	}
+	var extra map[string]authv1.ExtraValue
+	if len(request.UserInfo.Extra) > 0 {
+		extra = make(map[string]authv1.ExtraValue)
+		for k, v := range request.UserInfo.Extra {
+			extra[k] = authv1.ExtraValue(v)
+		}
+	}
+	sar := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   request.UserInfo.Username,
+			Groups: request.UserInfo.Groups,
+			UID:    request.UserInfo.UID,
+			Extra:  extra,
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: request.Namespace,
+				Verb:      "impersonate",
+				Resource:  "serviceaccounts",
+				Name:      serviceAccountName,
+			},
+		},
+	}
+	response, err := wh.client.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return response.Status.Allowed, nil
+}
+
 func validateDataVolumeName(name string) []metav1.StatusCause {
 	var causes []metav1.StatusCause
 	if len(name) > kvalidation.DNS1123SubdomainMaxLength {
@@ -84,6 +247,157 @@ func validateContentTypes(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataV
 
 func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.AdmissionRequest, field *k8sfield.Path, spec *cdiv1.DataVolumeSpec) []metav1.StatusCause {
 	var causes []metav1.StatusCause
+
+	if sourceRef := spec.SourceRef; sourceRef != nil {
+		if sourceRef.Kind != cdiv1.DataVolumeDataSource {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Unsupported sourceRef kind %q, only %s is supported", sourceRef.Kind, cdiv1.DataVolumeDataSource),
+				Field:   field.Child("sourceRef", "kind").String(),
+			})
+			return causes
+		}
+		if sourceRef.Name == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("Missing sourceRef name"),
+				Field:   field.Child("sourceRef", "name").String(),
+			})
+			return causes
+		}
+	}
+
+	if causes := wh.validateDataVolumeSource(request, field, spec); len(causes) > 0 {
+		return causes
+	}
+	if spec.PodResourceRequirements != nil {
+		for name, request := range spec.PodResourceRequirements.Requests {
+			if limit, ok := spec.PodResourceRequirements.Limits[name]; ok && request.Cmp(limit) > 0 {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("Pod %s request must be less than or equal to the %s limit", name, name),
+					Field:   field.Child("podResourceRequirements", "requests", string(name)).String(),
+				})
+				return causes
+			}
+		}
+	}
+	if spec.RetryPolicy != nil {
+		if spec.RetryPolicy.MaxAttempts != nil && *spec.RetryPolicy.MaxAttempts < 1 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Retry policy max attempts must be at least 1"),
+				Field:   field.Child("retryPolicy", "maxAttempts").String(),
+			})
+			return causes
+		}
+		for name, backoff := range map[string]*metav1.Duration{"initialBackoff": spec.RetryPolicy.InitialBackoff, "maxBackoff": spec.RetryPolicy.MaxBackoff} {
+			if backoff != nil && backoff.Duration <= 0 {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("Retry policy %s must be positive", name),
+					Field:   field.Child("retryPolicy", name).String(),
+				})
+				return causes
+			}
+		}
+	}
+	if spec.ImportTimeout != nil && spec.ImportTimeout.Duration <= 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Import timeout must be positive"),
+			Field:   field.Child("importTimeout").String(),
+		})
+		return causes
+	}
+	if spec.FilesystemOverhead != nil && !filesystemOverhead.MatchString(string(*spec.FilesystemOverhead)) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Filesystem overhead %s must be a value between 0 and 1 with up to 3 decimals, such as 0.055", *spec.FilesystemOverhead),
+			Field:   field.Child("filesystemOverhead").String(),
+		})
+		return causes
+	}
+	if scratchSpace := spec.ScratchSpace; scratchSpace != nil {
+		if scratchSpace.StorageClassName != "" {
+			if errs := kvalidation.IsDNS1123Subdomain(scratchSpace.StorageClassName); len(errs) > 0 {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("Invalid scratch space storage class %s: %s", scratchSpace.StorageClassName, strings.Join(errs, ", ")),
+					Field:   field.Child("scratchSpace", "storageClassName").String(),
+				})
+				return causes
+			}
+		}
+		if scratchSpace.Size != nil && scratchSpace.Size.Sign() <= 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Scratch space size must be positive"),
+				Field:   field.Child("scratchSpace", "size").String(),
+			})
+			return causes
+		}
+	}
+	if spec.PVC == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Missing Data volume PVC"),
+			Field:   field.Child("PVC").String(),
+		})
+		return causes
+	}
+	if pvcSize, ok := spec.PVC.Resources.Requests["storage"]; ok {
+		if pvcSize.IsZero() || pvcSize.Value() < 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("PVC size can't be equal or less than zero"),
+				Field:   field.Child("PVC", "resources", "requests", "size").String(),
+			})
+			return causes
+		}
+	} else if (spec.Source.HTTP == nil && spec.Source.Registry == nil) || spec.ContentType == cdiv1.DataVolumeArchive {
+		// The size of the PVC is detected from the http and registry sources of disk images
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("PVC size is missing"),
+			Field:   field.Child("PVC", "resources", "requests", "size").String(),
+		})
+		return causes
+	}
+
+	accessModes := spec.PVC.AccessModes
+	if len(accessModes) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Required value: at least 1 access mode is required, in the PVC or in the claimPropertySets of the StorageProfile of the storage class"),
+			Field:   field.Child("PVC", "accessModes").String(),
+		})
+		return causes
+	}
+	if len(accessModes) > 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("PVC multiple accessModes"),
+			Field:   field.Child("PVC", "accessModes").String(),
+		})
+		return causes
+	}
+	// We know we have one access mode
+	if accessModes[0] != v1.ReadWriteOnce && accessModes[0] != v1.ReadOnlyMany && accessModes[0] != v1.ReadWriteMany {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Unsupported value: \"%s\": supported values: \"ReadOnlyMany\", \"ReadWriteMany\", \"ReadWriteOnce\"", string(accessModes[0])),
+			Field:   field.Child("PVC", "accessModes").String(),
+		})
+		return causes
+	}
+	return causes
+}
+
+// validateDataVolumeSource validates the source of the DataVolume spec, along with the content type and checkpoints
+// the source supports, and checks the user may use the service account of the source on create
+func (wh *dataVolumeValidatingWebhook) validateDataVolumeSource(request *v1beta1.AdmissionRequest, field *k8sfield.Path, spec *cdiv1.DataVolumeSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
 	var url string
 	var sourceType string
 
@@ -110,22 +424,44 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 		})
 		return causes
 	}
-	// if source types are HTTP, Imageio, S3 or VDDK, check if URL is valid
-	if spec.Source.HTTP != nil || spec.Source.S3 != nil || spec.Source.Imageio != nil || spec.Source.VDDK != nil {
+	if len(spec.Checkpoints) > 0 && !controller.IsMultiStageImportSource(&spec.Source) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Data volume source does not support multi-stage imports"),
+			Field:   field.Child("checkpoints").String(),
+		})
+		return causes
+	}
+	// if source types are HTTP, Imageio, Glance, Proxmox, S3, Azure Blob or VDDK, check if URL is valid
+	if spec.Source.HTTP != nil || spec.Source.S3 != nil || spec.Source.AzureBlob != nil || spec.Source.Imageio != nil || spec.Source.Glance != nil || spec.Source.Proxmox != nil || spec.Source.VDDK != nil {
 		if spec.Source.HTTP != nil {
 			url = spec.Source.HTTP.URL
 			sourceType = field.Child("source", "HTTP", "url").String()
 		} else if spec.Source.S3 != nil {
 			url = spec.Source.S3.URL
 			sourceType = field.Child("source", "S3", "url").String()
+		} else if spec.Source.AzureBlob != nil {
+			url = spec.Source.AzureBlob.URL
+			sourceType = field.Child("source", "AzureBlob", "url").String()
 		} else if spec.Source.Imageio != nil {
 			url = spec.Source.Imageio.URL
 			sourceType = field.Child("source", "Imageio", "url").String()
+		} else if spec.Source.Glance != nil {
+			url = spec.Source.Glance.AuthURL
+			sourceType = field.Child("source", "Glance", "authUrl").String()
+		} else if spec.Source.Proxmox != nil {
+			url = spec.Source.Proxmox.URL
+			sourceType = field.Child("source", "Proxmox", "url").String()
 		} else if spec.Source.VDDK != nil {
 			url = spec.Source.VDDK.URL
 			sourceType = field.Child("source", "VDDK", "url").String()
 		}
-		err := validateSourceURL(url)
+		var err string
+		if spec.Source.HTTP != nil {
+			err = validateHTTPSourceURL(url)
+		} else {
+			err = validateSourceURL(url)
+		}
 		if err != "" {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
@@ -167,6 +503,151 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 		return causes
 	}
 
+	if spec.Source.Registry != nil && spec.Source.Registry.Platform != "" && !registryPlatform.MatchString(spec.Source.Registry.Platform) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is not a platform of the form os/arch[/variant]", field.Child("source", "Registry", "platform").String()),
+			Field:   field.Child("source", "Registry", "platform").String(),
+		})
+		return causes
+	}
+
+	if spec.Source.Registry != nil && spec.Source.Registry.Signature != nil {
+		signature := spec.Source.Registry.Signature
+		signatureField := field.Child("source", "Registry", "signature")
+		if signature.ConfigMap == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is required to verify signatures", signatureField.Child("configMap").String()),
+				Field:   signatureField.Child("configMap").String(),
+			})
+			return causes
+		}
+		if (signature.Identity == "") != (signature.Issuer == "") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s and %s are both required to verify keyless signatures", signatureField.Child("identity").String(), signatureField.Child("issuer").String()),
+				Field:   signatureField.String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.HTTP != nil {
+		if isFTPURL(spec.Source.HTTP.URL) && (spec.Source.HTTP.TokenSecretRef != "" || spec.Source.HTTP.OAuth2 != nil || spec.Source.HTTP.ClientCertSecretRef != "" ||
+			spec.Source.HTTP.Segments != nil || spec.Source.HTTP.SegmentSize != nil || spec.ContentType == cdiv1.DataVolumeArchive) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s ftp(s) URLs do not support tokens, client certificates, segments or archive content", field.Child("source", "HTTP").String()),
+				Field:   field.Child("source", "HTTP").String(),
+			})
+			return causes
+		}
+		if (spec.Source.HTTP.Segments != nil && *spec.Source.HTTP.Segments < 1) || (spec.Source.HTTP.SegmentSize != nil && spec.Source.HTTP.SegmentSize.Sign() <= 0) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s segments and segmentSize must be positive", field.Child("source", "HTTP").String()),
+				Field:   field.Child("source", "HTTP").String(),
+			})
+			return causes
+		}
+		if oauth2 := spec.Source.HTTP.OAuth2; oauth2 != nil {
+			if spec.Source.HTTP.TokenSecretRef != "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s tokenSecretRef and oauth2 are mutually exclusive", field.Child("source", "HTTP").String()),
+					Field:   field.Child("source", "HTTP").String(),
+				})
+				return causes
+			}
+			if oauth2.SecretRef == "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s secretRef is required", field.Child("source", "HTTP", "oauth2").String()),
+					Field:   field.Child("source", "HTTP", "oauth2", "secretRef").String(),
+				})
+				return causes
+			}
+			if err := validateSourceURL(oauth2.TokenURL); err != "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s %s", field.Child("source", "HTTP", "oauth2").String(), err),
+					Field:   field.Child("source", "HTTP", "oauth2", "tokenURL").String(),
+				})
+				return causes
+			}
+		}
+	}
+
+	if spec.Source.S3 != nil {
+		if (spec.Source.S3.Segments != nil && *spec.Source.S3.Segments < 1) || (spec.Source.S3.SegmentSize != nil && spec.Source.S3.SegmentSize.Sign() <= 0) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s segments and segmentSize must be positive", field.Child("source", "S3").String()),
+				Field:   field.Child("source", "S3").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.AzureBlob != nil {
+		if (spec.Source.AzureBlob.Segments != nil && *spec.Source.AzureBlob.Segments < 1) || (spec.Source.AzureBlob.SegmentSize != nil && spec.Source.AzureBlob.SegmentSize.Sign() <= 0) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s segments and segmentSize must be positive", field.Child("source", "AzureBlob").String()),
+				Field:   field.Child("source", "AzureBlob").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.AzureDisk != nil && !azureDiskResourceID.MatchString(spec.Source.AzureDisk.ResourceID) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is not the resource ID of a managed disk or snapshot", field.Child("source", "AzureDisk", "resourceId").String()),
+			Field:   field.Child("source", "AzureDisk", "resourceId").String(),
+		})
+		return causes
+	}
+
+	if spec.Source.AWSSnapshot != nil {
+		if causes := validateAWSSnapshot(field.Child("source", "AWSSnapshot"), spec); len(causes) > 0 {
+			return causes
+		}
+	}
+
+	if spec.Source.GCS != nil {
+		if err := validateGCSURL(spec.Source.GCS.URL); err != "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s %s", field.Child("source").String(), err),
+				Field:   field.Child("source", "GCS", "url").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.GCEImage != nil {
+		if !gceImagePath.MatchString(spec.Source.GCEImage.Image) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not an image, expected projects/<project>/global/images/<name>", field.Child("source", "GCEImage", "image").String()),
+				Field:   field.Child("source", "GCEImage", "image").String(),
+			})
+			return causes
+		}
+		if spec.Source.GCEImage.ExportURL != "" {
+			if err := validateGCSURL(spec.Source.GCEImage.ExportURL); err != "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s %s", field.Child("source", "GCEImage", "exportUrl").String(), err),
+					Field:   field.Child("source", "GCEImage", "exportUrl").String(),
+				})
+				return causes
+			}
+		}
+	}
+
 	if spec.Source.Imageio != nil {
 		if spec.Source.Imageio.SecretRef == "" || spec.Source.Imageio.CertConfigMap == "" || spec.Source.Imageio.DiskID == "" {
 			causes = append(causes, metav1.StatusCause{
@@ -178,6 +659,249 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 		}
 	}
 
+	if spec.Source.Glance != nil {
+		if spec.Source.Glance.SecretRef == "" || spec.Source.Glance.Project == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source Glance is not valid", field.Child("source", "Glance").String()),
+				Field:   field.Child("source", "Glance").String(),
+			})
+			return causes
+		}
+		if !glanceImageID.MatchString(spec.Source.Glance.ImageID) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not an image UUID", field.Child("source", "Glance", "imageId").String()),
+				Field:   field.Child("source", "Glance", "imageId").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.Proxmox != nil {
+		// VM IDs of Proxmox VE start at 100
+		if spec.Source.Proxmox.SecretRef == "" || spec.Source.Proxmox.VMID < 100 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source Proxmox is not valid", field.Child("source", "Proxmox").String()),
+				Field:   field.Child("source", "Proxmox").String(),
+			})
+			return causes
+		}
+		if !proxmoxDisk.MatchString(spec.Source.Proxmox.Disk) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not a disk key like scsi0", field.Child("source", "Proxmox", "disk").String()),
+				Field:   field.Child("source", "Proxmox", "disk").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.NFS != nil {
+		if spec.Source.NFS.Server == "" || strings.Contains(spec.Source.NFS.Server, "/") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source NFS is not valid", field.Child("source", "NFS").String()),
+				Field:   field.Child("source", "NFS").String(),
+			})
+			return causes
+		}
+		nfsPath := spec.Source.NFS.Path
+		if !path.IsAbs(nfsPath) || path.Clean(nfsPath) != nfsPath || nfsPath == "/" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the absolute path of a file", field.Child("source", "NFS", "path").String()),
+				Field:   field.Child("source", "NFS", "path").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.SMB != nil {
+		if spec.Source.SMB.Server == "" || spec.Source.SMB.Share == "" || strings.ContainsAny(spec.Source.SMB.Server+spec.Source.SMB.Share, "/\\") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source SMB is not valid", field.Child("source", "SMB").String()),
+				Field:   field.Child("source", "SMB").String(),
+			})
+			return causes
+		}
+		smbPath := strings.Trim(strings.ReplaceAll(spec.Source.SMB.Path, "\\", "/"), "/")
+		if smbPath == "" || strings.ContainsAny(smbPath, "\"\n") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the path of a file", field.Child("source", "SMB", "path").String()),
+				Field:   field.Child("source", "SMB", "path").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.Rsync != nil {
+		rsync := spec.Source.Rsync
+		if rsync.SecretRef == "" || rsync.Host == "" || strings.ContainsAny(rsync.Host, "/@?# \\\n") ||
+			strings.ContainsAny(rsync.Module, "/:\n") || strings.Contains(rsync.HostKey, "\n") || (rsync.HostKey != "" && len(strings.Fields(rsync.HostKey)) < 2) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source Rsync is not valid", field.Child("source", "Rsync").String()),
+				Field:   field.Child("source", "Rsync").String(),
+			})
+			return causes
+		}
+		rsyncPath := path.Clean(rsync.Path)
+		if rsync.Path == "" || strings.HasSuffix(rsync.Path, "/") || strings.Contains(rsync.Path, "\n") ||
+			(rsync.Module == "" && !path.IsAbs(rsyncPath)) || (rsync.Module != "" && (rsyncPath == "." || strings.HasPrefix(rsyncPath, ".."))) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the path of a file", field.Child("source", "Rsync", "path").String()),
+				Field:   field.Child("source", "Rsync", "path").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.ISCSI != nil {
+		iscsi := spec.Source.ISCSI
+		if iscsi.Portal == "" || strings.ContainsAny(iscsi.Portal, "/@?# \\\n") || iscsi.LUN < 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source ISCSI is not valid", field.Child("source", "ISCSI").String()),
+				Field:   field.Child("source", "ISCSI").String(),
+			})
+			return causes
+		}
+		if !iscsiQualifiedName.MatchString(iscsi.IQN) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not an iSCSI qualified name", field.Child("source", "ISCSI", "iqn").String()),
+				Field:   field.Child("source", "ISCSI", "iqn").String(),
+			})
+			return causes
+		}
+		if iscsi.InitiatorName != "" && !iscsiQualifiedName.MatchString(iscsi.InitiatorName) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not an iSCSI qualified name", field.Child("source", "ISCSI", "initiatorName").String()),
+				Field:   field.Child("source", "ISCSI", "initiatorName").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.RBD != nil {
+		rbd := spec.Source.RBD
+		if len(rbd.Monitors) == 0 || rbd.SecretRef == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source RBD is not valid", field.Child("source", "RBD").String()),
+				Field:   field.Child("source", "RBD").String(),
+			})
+			return causes
+		}
+		for _, monitor := range rbd.Monitors {
+			if monitor == "" || strings.ContainsAny(monitor, "/@,;= \n") {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s %q is not the address of a monitor", field.Child("source", "RBD", "monitors").String(), monitor),
+					Field:   field.Child("source", "RBD", "monitors").String(),
+				})
+				return causes
+			}
+		}
+		if rbd.Pool == "" || rbd.Image == "" || strings.ContainsAny(rbd.Pool+rbd.Image+rbd.Snapshot, "/@\n") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the name of an RBD image", field.Child("source", "RBD", "image").String()),
+				Field:   field.Child("source", "RBD", "image").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.File != nil {
+		file := spec.Source.File
+		if (file.HostPath == "") == (file.ClaimName == "") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source File requires either a host path or a claim name", field.Child("source", "File").String()),
+				Field:   field.Child("source", "File").String(),
+			})
+			return causes
+		}
+		if file.HostPath != "" && (!path.IsAbs(file.HostPath) || path.Clean(file.HostPath) != file.HostPath || file.NodeName == "") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the absolute path of a directory of a node", field.Child("source", "File", "hostPath").String()),
+				Field:   field.Child("source", "File", "hostPath").String(),
+			})
+			return causes
+		}
+		if file.HostPath != "" {
+			if cause := wh.validateFileSourceHostPath(field.Child("source", "File", "hostPath"), file.HostPath); cause != nil {
+				causes = append(causes, *cause)
+				return causes
+			}
+		}
+		if file.ClaimName != "" && len(kvalidation.IsDNS1123Subdomain(file.ClaimName)) != 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the name of a PVC", field.Child("source", "File", "claimName").String()),
+				Field:   field.Child("source", "File", "claimName").String(),
+			})
+			return causes
+		}
+		if file.Path == "" || path.IsAbs(file.Path) || path.Clean(file.Path) != file.Path || strings.HasPrefix(file.Path, "../") || file.Path == ".." || file.Path == "." {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the relative path of a file", field.Child("source", "File", "path").String()),
+				Field:   field.Child("source", "File", "path").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.Libvirt != nil {
+		libvirt := spec.Source.Libvirt
+		if !isLibvirtSSHURI(libvirt.URI) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not a qemu+ssh:// URI without user", field.Child("source", "Libvirt", "uri").String()),
+				Field:   field.Child("source", "Libvirt", "uri").String(),
+			})
+			return causes
+		}
+		if libvirt.Domain == "" || strings.Contains(libvirt.Domain, "\n") || !libvirtDiskTarget.MatchString(libvirt.Disk) || libvirt.SecretRef == "" ||
+			strings.Contains(libvirt.HostKey, "\n") || (libvirt.HostKey != "" && len(strings.Fields(libvirt.HostKey)) < 2) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source Libvirt is not valid", field.Child("source", "Libvirt").String()),
+				Field:   field.Child("source", "Libvirt").String(),
+			})
+			return causes
+		}
+	}
+
+	if spec.Source.HyperV != nil {
+		if spec.Source.HyperV.Server == "" || spec.Source.HyperV.Share == "" || strings.ContainsAny(spec.Source.HyperV.Server+spec.Source.HyperV.Share, "/\\") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source HyperV is not valid", field.Child("source", "HyperV").String()),
+				Field:   field.Child("source", "HyperV").String(),
+			})
+			return causes
+		}
+		ext := strings.ToLower(filepath.Ext(spec.Source.HyperV.Path))
+		if (ext != ".vhdx" && ext != ".vhd") || strings.ContainsAny(spec.Source.HyperV.Path, "\"\n") {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not the path of a VHDX or VHD disk", field.Child("source", "HyperV", "path").String()),
+				Field:   field.Child("source", "HyperV", "path").String(),
+			})
+			return causes
+		}
+	}
+
 	if spec.Source.VDDK != nil {
 		if spec.Source.VDDK.SecretRef == "" || spec.Source.VDDK.UUID == "" || spec.Source.VDDK.BackingFile == "" || spec.Source.VDDK.Thumbprint == "" {
 			causes = append(causes, metav1.StatusCause{
@@ -187,31 +911,52 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 			})
 			return causes
 		}
+		if spec.Source.VDDK.Connections != nil && *spec.Source.VDDK.Connections < 1 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s connections must be positive", field.Child("source", "VDDK").String()),
+				Field:   field.Child("source", "VDDK", "connections").String(),
+			})
+			return causes
+		}
+	}
+
+	if snapshot := spec.Source.Snapshot; snapshot != nil && (snapshot.Namespace == "" || snapshot.Name == "") {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s source snapshot is not valid", field.Child("source", "Snapshot").String()),
+			Field:   field.Child("source", "Snapshot").String(),
+		})
+		return causes
 	}
 
-	if spec.Source.PVC != nil {
-		if spec.Source.PVC.Namespace == "" || spec.Source.PVC.Name == "" {
+	sourcePVC, sourcePVCField := spec.Source.PVC, field.Child("source", "PVC")
+	if spec.Source.PVCNetwork != nil {
+		sourcePVC, sourcePVCField = spec.Source.PVCNetwork, field.Child("source", "PVCNetwork")
+	}
+	if sourcePVC != nil {
+		if sourcePVC.Namespace == "" || sourcePVC.Name == "" {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("%s source PVC is not valid", field.Child("source", "PVC").String()),
-				Field:   field.Child("source", "PVC").String(),
+				Message: fmt.Sprintf("%s source PVC is not valid", sourcePVCField.String()),
+				Field:   sourcePVCField.String(),
 			})
 			return causes
 		}
 
 		if request.Operation == v1beta1.Create {
-			sourcePVC, err := wh.client.CoreV1().PersistentVolumeClaims(spec.Source.PVC.Namespace).Get(context.TODO(), spec.Source.PVC.Name, metav1.GetOptions{})
+			pvc, err := wh.client.CoreV1().PersistentVolumeClaims(sourcePVC.Namespace).Get(context.TODO(), sourcePVC.Name, metav1.GetOptions{})
 			if err != nil {
 				if k8serrors.IsNotFound(err) {
 					causes = append(causes, metav1.StatusCause{
 						Type:    metav1.CauseTypeFieldValueNotFound,
-						Message: fmt.Sprintf("Source PVC %s/%s doesn't exist", spec.Source.PVC.Namespace, spec.Source.PVC.Name),
-						Field:   field.Child("source", "PVC").String(),
+						Message: fmt.Sprintf("Source PVC %s/%s doesn't exist", sourcePVC.Namespace, sourcePVC.Name),
+						Field:   sourcePVCField.String(),
 					})
 					return causes
 				}
 			}
-			valid, sourceContentType, targetContentType := validateContentTypes(sourcePVC, spec)
+			valid, sourceContentType, targetContentType := validateContentTypes(pvc, spec)
 			if !valid {
 				causes = append(causes, metav1.StatusCause{
 					Type:    metav1.CauseTypeFieldValueInvalid,
@@ -220,7 +965,7 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 				})
 				return causes
 			}
-			err = controller.ValidateCanCloneSourceAndTargetSpec(&sourcePVC.Spec, spec.PVC, targetContentType)
+			err = controller.ValidateCanCloneSourceAndTargetSpec(&pvc.Spec, spec.PVC, targetContentType)
 			if err != nil {
 				causes = append(causes, metav1.StatusCause{
 					Type:    metav1.CauseTypeFieldValueInvalid,
@@ -232,58 +977,26 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *v1beta1.A
 		}
 	}
 
-	if spec.PVC == nil {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("Missing Data volume PVC"),
-			Field:   field.Child("PVC").String(),
-		})
-		return causes
-	}
-	if pvcSize, ok := spec.PVC.Resources.Requests["storage"]; ok {
-		if pvcSize.IsZero() || pvcSize.Value() < 0 {
+	if serviceAccountName, serviceAccountField := importServiceAccount(field.Child("source"), &spec.Source); serviceAccountName != "" && request.Operation == v1beta1.Create {
+		allowed, err := wh.canUseServiceAccount(request, serviceAccountName)
+		if err != nil {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("PVC size can't be equal or less than zero"),
-				Field:   field.Child("PVC", "resources", "requests", "size").String(),
+				Message: fmt.Sprintf("Unable to check the permissions on the service account %s: %v", serviceAccountName, err),
+				Field:   serviceAccountField.String(),
+			})
+			return causes
+		}
+		if !allowed {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("User %s is not allowed to impersonate the service account %s", request.UserInfo.Username, serviceAccountName),
+				Field:   serviceAccountField.String(),
 			})
 			return causes
 		}
-	} else {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("PVC size is missing"),
-			Field:   field.Child("PVC", "resources", "requests", "size").String(),
-		})
-		return causes
 	}
 
-	accessModes := spec.PVC.AccessModes
-	if len(accessModes) == 0 {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("Required value: at least 1 access mode is required"),
-			Field:   field.Child("PVC", "accessModes").String(),
-		})
-		return causes
-	}
-	if len(accessModes) > 1 {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("PVC multiple accessModes"),
-			Field:   field.Child("PVC", "accessModes").String(),
-		})
-		return causes
-	}
-	// We know we have one access mode
-	if accessModes[0] != v1.ReadWriteOnce && accessModes[0] != v1.ReadOnlyMany && accessModes[0] != v1.ReadWriteMany {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("Unsupported value: \"%s\": supported values: \"ReadOnlyMany\", \"ReadWriteMany\", \"ReadWriteOnce\"", string(accessModes[0])),
-			Field:   field.Child("PVC", "accessModes").String(),
-		})
-		return causes
-	}
 	return causes
 }
 
@@ -309,7 +1022,7 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar v1beta1.AdmissionReview) *v1beta
 
 		// Always admit checkpoint updates for multi-stage migrations.
 		multiStageAdmitted := false
-		isMultiStage := dv.Spec.Source.VDDK != nil && len(dv.Spec.Checkpoints) > 0
+		isMultiStage := controller.IsMultiStageImportSource(&dv.Spec.Source) && len(dv.Spec.Checkpoints) > 0
 		if isMultiStage {
 			oldSpec := oldDV.Spec.DeepCopy()
 			oldSpec.FinalCheckpoint = false
@@ -352,8 +1065,8 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar v1beta1.AdmissionReview) *v1beta
 				pvcOwner := metav1.GetControllerOf(pvc)
 				// We should reject the DV if a PVC with the same name exists, and that PVC has no ownerRef, or that
 				// PVC has an ownerRef that is not a DataVolume. Because that means that PVC is not managed by the
-				// datavolume controller, and we can't use it.
-				if (pvcOwner == nil) || (pvcOwner.Kind != "DataVolume") {
+				// datavolume controller, and we can't use it. Unless the DV asks to adopt it.
+				if ((pvcOwner == nil) || (pvcOwner.Kind != "DataVolume")) && !controller.ClaimAdoptionAllowed(&dv, pvc) {
 					klog.Errorf("destination PVC %s/%s already exists", pvc.GetNamespace(), pvc.GetName())
 					var causes []metav1.StatusCause
 					causes = append(causes, metav1.StatusCause{
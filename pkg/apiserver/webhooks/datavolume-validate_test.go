@@ -25,20 +25,26 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 )
 
 var _ = Describe("Validating Webhook", func() {
@@ -49,6 +55,463 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(true))
 		})
 
+		It("should accept DataVolume with HTTP source and segmented download on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			segments := int32(4)
+			segmentSize := resource.MustParse("64Mi")
+			dataVolume.Spec.Source.HTTP.Segments = &segments
+			dataVolume.Spec.Source.HTTP.SegmentSize = &segmentSize
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should reject DataVolume with HTTP source and invalid segments on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			segments := int32(0)
+			dataVolume.Spec.Source.HTTP.Segments = &segments
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
+		It("should accept DataVolume with pod resource requirements on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.PodResourceRequirements = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should reject DataVolume with pod resource requests over the limits on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.PodResourceRequirements = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
+		DescribeTable("should validate the retry policy on create", func(maxAttempts int32, backoff time.Duration, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.RetryPolicy = &cdiv1.ImportRetryPolicy{
+				MaxAttempts:    &maxAttempts,
+				InitialBackoff: &metav1.Duration{Duration: backoff},
+				MaxBackoff:     &metav1.Duration{Duration: time.Minute},
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a retry policy", int32(3), time.Second, true),
+			Entry("reject no attempts", int32(0), time.Second, false),
+			Entry("reject a negative backoff", int32(3), -time.Second, false),
+		)
+
+		DescribeTable("should validate the import timeout on create", func(timeout time.Duration, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.ImportTimeout = &metav1.Duration{Duration: timeout}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a timeout", time.Hour, true),
+			Entry("reject a zero timeout", time.Duration(0), false),
+		)
+
+		DescribeTable("should validate the filesystem overhead on create", func(overhead cdiv1.Percent, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.FilesystemOverhead = &overhead
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an overhead", cdiv1.Percent("0.02"), true),
+			Entry("accept no overhead", cdiv1.Percent("0"), true),
+			Entry("reject a full overhead", cdiv1.Percent("1"), false),
+			Entry("reject an overhead over 1", cdiv1.Percent("1.5"), false),
+			Entry("reject a percentage", cdiv1.Percent("5%"), false),
+		)
+
+		DescribeTable("should validate the scratch space on create", func(storageClassName, size string, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.ScratchSpace = &cdiv1.DataVolumeScratchSpace{StorageClassName: storageClassName}
+			if size != "" {
+				quantity := resource.MustParse(size)
+				dataVolume.Spec.ScratchSpace.Size = &quantity
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a storage class and a size", "local-nvme", "20Gi", true),
+			Entry("accept a storage class", "local-nvme", "", true),
+			Entry("reject an invalid storage class", "Local_NVMe", "", false),
+			Entry("reject a zero size", "", "0", false),
+		)
+
+		DescribeTable("should validate DataVolume with HTTP source and ftp URL on create", func(url, tokenSecretRef string, contentType cdiv1.DataVolumeContentType, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", url)
+			dataVolume.Spec.Source.HTTP.TokenSecretRef = tokenSecretRef
+			dataVolume.Spec.ContentType = contentType
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an ftp URL", "ftp://ftp.example.com/images/appliance.qcow2", "", cdiv1.DataVolumeKubeVirt, true),
+			Entry("accept an ftps URL", "ftps://ftp.example.com/images/appliance.qcow2", "", cdiv1.DataVolumeKubeVirt, true),
+			Entry("reject an ftp URL without host", "ftp:///images/appliance.qcow2", "", cdiv1.DataVolumeKubeVirt, false),
+			Entry("reject an ftp URL with a token", "ftp://ftp.example.com/images/appliance.qcow2", "tokensecret", cdiv1.DataVolumeKubeVirt, false),
+			Entry("reject an ftp URL with archive content", "ftp://ftp.example.com/images/appliance.tar", "", cdiv1.DataVolumeArchive, false),
+			Entry("reject other schemes", "sftp://ftp.example.com/images/appliance.qcow2", "", cdiv1.DataVolumeKubeVirt, false),
+		)
+
+		DescribeTable("should validate the VDDK connections on create", func(connections int32, allowed bool) {
+			dataVolume := newMultistageDataVolume("testDV", false, nil)
+			dataVolume.Spec.Source.VDDK.Connections = &connections
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept multiple connections", int32(4), true),
+			Entry("reject no connections", int32(0), false),
+		)
+
+		It("should reject DataVolume with S3 source and invalid segment size on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			segmentSize := resource.MustParse("0")
+			dataVolume.Spec.Source.HTTP = nil
+			dataVolume.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{URL: "http://s3.example.com/bucket/disk.img", SegmentSize: &segmentSize}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
+		DescribeTable("should check the user may impersonate the service account of the importer on create", func(source cdiv1.DataVolumeSource, impersonate bool) {
+			dataVolume := newDataVolume("testDV", source, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			dvBytes, _ := json.Marshal(dataVolume)
+			client := fakeclient.NewSimpleClientset()
+			client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				sar := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+				Expect(sar.Spec.User).To(Equal("user"))
+				Expect(*sar.Spec.ResourceAttributes).To(Equal(authv1.ResourceAttributes{
+					Namespace: "default",
+					Verb:      "impersonate",
+					Resource:  "serviceaccounts",
+					Name:      "importer",
+				}))
+				sar.Status.Allowed = impersonate
+				return true, sar, nil
+			})
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Create,
+					Namespace: "default",
+					UserInfo:  authenticationv1.UserInfo{Username: "user"},
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+			resp := serve(ar, NewDataVolumeValidatingWebhook(client, cdiclient.NewSimpleClientset()))
+			Expect(resp.Allowed).To(Equal(impersonate))
+		},
+			Entry("accept S3 with an allowed service account", cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.example.com/bucket/disk.img", ServiceAccountName: "importer"}}, true),
+			Entry("reject S3 with a forbidden service account", cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.example.com/bucket/disk.img", ServiceAccountName: "importer"}}, false),
+			Entry("reject AWS snapshot with a forbidden service account", cdiv1.DataVolumeSource{AWSSnapshot: &cdiv1.DataVolumeSourceAWSSnapshot{SnapshotID: "snap-01", Region: "us-east-1", ServiceAccountName: "importer"}}, false),
+			Entry("accept GCS with an allowed service account", cdiv1.DataVolumeSource{GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", ServiceAccountName: "importer"}}, true),
+			Entry("reject GCS with a forbidden service account", cdiv1.DataVolumeSource{GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", ServiceAccountName: "importer"}}, false),
+			Entry("reject GCE image with a forbidden service account", cdiv1.DataVolumeSource{GCEImage: &cdiv1.DataVolumeSourceGCEImage{Image: "projects/p/global/images/disk", ServiceAccountName: "importer"}}, false),
+			Entry("reject Azure blob with a forbidden service account", cdiv1.DataVolumeSource{AzureBlob: &cdiv1.DataVolumeSourceAzureBlob{URL: "https://account.blob.core.windows.net/container/disk.vhd", ServiceAccountName: "importer"}}, false),
+		)
+
+		It("should accept the service account of a DataVolume created by the CDI controller", func() {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.example.com/bucket/disk.img", ServiceAccountName: "importer"}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			dvBytes, _ := json.Marshal(dataVolume)
+			client := fakeclient.NewSimpleClientset()
+			client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				Fail("the permissions of the CDI controller should not be checked")
+				return true, nil, nil
+			})
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Create,
+					Namespace: "default",
+					UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:cdi:" + common.ControllerServiceAccountName},
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+			resp := serve(ar, NewDataVolumeValidatingWebhook(client, cdiclient.NewSimpleClientset()))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		DescribeTable("should validate DataVolume with Azure Blob source on create", func(url string, segments int32, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Source.HTTP = nil
+			dataVolume.Spec.Source.AzureBlob = &cdiv1.DataVolumeSourceAzureBlob{URL: url, Segments: &segments}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a blob url", "https://account.blob.core.windows.net/images/disk.img", int32(4), true),
+			Entry("reject an empty url", "", int32(4), false),
+			Entry("reject invalid segments", "https://account.blob.core.windows.net/images/disk.img", int32(0), false),
+		)
+
+		DescribeTable("should validate DataVolume with Azure disk source on create", func(resourceID string, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Source.HTTP = nil
+			dataVolume.Spec.Source.AzureDisk = &cdiv1.DataVolumeSourceAzureDisk{ResourceID: resourceID}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a snapshot", "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/snapshots/fedora", true),
+			Entry("accept a disk", "/subscriptions/sub/resourceGroups/group/providers/microsoft.compute/disks/fedora", true),
+			Entry("reject a managed image", "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/images/fedora", false),
+			Entry("reject an empty resource ID", "", false),
+		)
+
+		DescribeTable("should validate DataVolume with AWS snapshot source on create", func(snapshotID, region string, checkpoints []cdiv1.DataVolumeCheckpoint, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{AWSSnapshot: &cdiv1.DataVolumeSourceAWSSnapshot{SnapshotID: snapshotID, Region: region}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			dataVolume.Spec.Checkpoints = checkpoints
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a snapshot", "snap-0123456789abcdef0", "us-east-1", nil, true),
+			Entry("accept an AMI", "ami-0123456789abcdef0", "us-east-1", nil, true),
+			Entry("accept snapshot checkpoints", "snap-01", "us-east-1", []cdiv1.DataVolumeCheckpoint{{Current: "snap-01"}, {Previous: "snap-01", Current: "snap-02"}}, true),
+			Entry("reject a volume ID", "vol-0123456789abcdef0", "us-east-1", nil, false),
+			Entry("reject a missing region", "snap-0123456789abcdef0", "", nil, false),
+			Entry("reject checkpoints that are not snapshots", "snap-01", "us-east-1", []cdiv1.DataVolumeCheckpoint{{Current: "stage-1"}}, false),
+		)
+
+		DescribeTable("should validate the sourceRef on create", func(sourceRef *cdiv1.DataVolumeSourceRef, resolved, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.SourceRef = sourceRef
+			if !resolved {
+				dataVolume.Spec.Source = cdiv1.DataVolumeSource{}
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a DataSource resolved by the mutating webhook", &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "fedora"}, true, true),
+			Entry("reject a DataSource which was not resolved", &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "fedora"}, false, false),
+			Entry("reject another kind", &cdiv1.DataVolumeSourceRef{Kind: "PersistentVolumeClaim", Name: "fedora"}, true, false),
+			Entry("reject a missing name", &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource}, true, false),
+		)
+
+		It("should reject checkpoints of a source that does not support multi-stage imports", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{{Current: "stage-1"}}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.checkpoints"))
+		})
+
+		DescribeTable("should validate DataVolume with Proxmox source on create", func(vmID int32, disk, secretRef string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Proxmox: &cdiv1.DataVolumeSourceProxmox{URL: "https://pve.example.com:8006", VMID: vmID, Disk: disk, SecretRef: secretRef}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a disk", int32(100), "scsi0", "proxmox", true),
+			Entry("accept a virtio disk", int32(4242), "virtio12", "proxmox", true),
+			Entry("reject a VM ID below 100", int32(99), "scsi0", "proxmox", false),
+			Entry("reject an invalid disk key", int32(100), "local-lvm:vm-100-disk-0", "proxmox", false),
+			Entry("reject a missing secret", int32(100), "scsi0", "", false),
+		)
+
+		DescribeTable("should validate DataVolume with NFS source on create", func(server, path string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{Server: server, Path: path}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an image file", "nfs.example.com", "/export/templates/fedora.qcow2", true),
+			Entry("reject a relative path", "nfs.example.com", "templates/fedora.qcow2", false),
+			Entry("reject a path with a trailing slash", "nfs.example.com", "/export/templates/", false),
+			Entry("reject a path with parent references", "nfs.example.com", "/export/../etc/fedora.qcow2", false),
+			Entry("reject the root of the export", "nfs.example.com", "/", false),
+			Entry("reject a missing server", "", "/export/templates/fedora.qcow2", false),
+		)
+
+		DescribeTable("should validate DataVolume with VolumeSnapshot source on create", func(namespace, name string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Snapshot: &cdiv1.DataVolumeSourceSnapshot{Namespace: namespace, Name: name}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a snapshot", "default", "snapshot", true),
+			Entry("reject a missing namespace", "", "snapshot", false),
+			Entry("reject a missing name", "default", "", false),
+		)
+
+		DescribeTable("should validate DataVolume with Hyper-V source on create", func(server, share, path string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{HyperV: &cdiv1.DataVolumeSourceHyperV{Server: server, Share: share, Path: path, SecretRef: "smb"}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a VHDX disk", "fileserver", "vms", "fedora/Virtual Hard Disks/fedora.vhdx", true),
+			Entry("accept a VHD disk with backslashes", "fileserver", "vms", "fedora\\fedora.VHD", true),
+			Entry("reject a differencing disk", "fileserver", "vms", "fedora/fedora_1234.avhdx", false),
+			Entry("reject a path with quotes", "fileserver", "vms", "\"fedora.vhdx", false),
+			Entry("reject a missing share", "fileserver", "", "fedora.vhdx", false),
+			Entry("reject a share with a path", "fileserver", "vms/fedora", "fedora.vhdx", false),
+		)
+
+		DescribeTable("should validate DataVolume with SMB source on create", func(server, share, path string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{SMB: &cdiv1.DataVolumeSourceSMB{Server: server, Share: share, Path: path, SecretRef: "smb"}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a file", "fileserver", "images", "templates/fedora.qcow2", true),
+			Entry("accept a file with backslashes", "fileserver", "images", "ISO\\Windows Server.iso", true),
+			Entry("reject a missing path", "fileserver", "images", "/", false),
+			Entry("reject a path with quotes", "fileserver", "images", "\"fedora.qcow2", false),
+			Entry("reject a missing server", "", "images", "fedora.qcow2", false),
+			Entry("reject a share with a path", "fileserver", "images\\templates", "fedora.qcow2", false),
+		)
+
+		DescribeTable("should validate DataVolume with rsync source on create", func(host, module, path, hostKey string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Rsync: &cdiv1.DataVolumeSourceRsync{Host: host, Module: module, Path: path, SecretRef: "rsync", HostKey: hostKey}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an absolute path", "backup.example.com", "", "/srv/images/fedora.qcow2", "", true),
+			Entry("accept a path in a module with a port and host key", "backup.example.com:2222", "images", "templates/fedora.qcow2", "ssh-ed25519 AAAAC3Nza", true),
+			Entry("reject a relative path without module", "backup.example.com", "", "images/fedora.qcow2", "", false),
+			Entry("reject a path out of the module", "backup.example.com", "images", "../etc/shadow", "", false),
+			Entry("reject a directory", "backup.example.com", "", "/srv/images/", "", false),
+			Entry("reject a user in the host", "root@backup.example.com", "", "/srv/images/fedora.qcow2", "", false),
+			Entry("reject a module with a path", "backup.example.com", "images/templates", "fedora.qcow2", "", false),
+			Entry("reject a host key without type", "backup.example.com", "", "/srv/images/fedora.qcow2", "AAAAC3Nza", false),
+		)
+
+		DescribeTable("should validate DataVolume with iSCSI source on create", func(portal, iqn string, lun int32, initiator string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{ISCSI: &cdiv1.DataVolumeSourceISCSI{Portal: portal, IQN: iqn, LUN: lun, InitiatorName: initiator}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an iqn target", "san.example.com:3260", "iqn.2003-01.org.linux-iscsi.san:p2v", int32(1), "iqn.2021-01.io.kubevirt:importer", true),
+			Entry("accept an eui target", "10.0.0.5", "eui.02004567A425678D", int32(0), "", true),
+			Entry("reject a missing portal", "", "iqn.2003-01.org.linux-iscsi.san:p2v", int32(0), "", false),
+			Entry("reject a portal with a path", "san.example.com/iscsi", "iqn.2003-01.org.linux-iscsi.san:p2v", int32(0), "", false),
+			Entry("reject a negative LUN", "san.example.com", "iqn.2003-01.org.linux-iscsi.san:p2v", int32(-1), "", false),
+			Entry("reject an invalid target", "san.example.com", "p2v", int32(0), "", false),
+			Entry("reject an invalid initiator", "san.example.com", "iqn.2003-01.org.linux-iscsi.san:p2v", int32(0), "importer", false),
+		)
+
+		DescribeTable("should validate DataVolume with RBD source on create", func(monitors []string, pool, image, snapshot string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{RBD: &cdiv1.DataVolumeSourceRBD{Monitors: monitors, Pool: pool, Image: image, Snapshot: snapshot, SecretRef: "ceph"}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an image", []string{"10.0.0.1:6789", "10.0.0.2"}, "vms", "disk-1", "", true),
+			Entry("accept a snapshot", []string{"[fd00::1]:3300"}, "vms", "disk-1", "migration", true),
+			Entry("reject missing monitors", nil, "vms", "disk-1", "", false),
+			Entry("reject a list of monitors in a single entry", []string{"10.0.0.1,10.0.0.2"}, "vms", "disk-1", "", false),
+			Entry("reject a missing pool", []string{"10.0.0.1"}, "", "disk-1", "", false),
+			Entry("reject an image with a snapshot", []string{"10.0.0.1"}, "vms", "disk-1@migration", "", false),
+		)
+
+		DescribeTable("should validate DataVolume with file source on create", func(hostPath, nodeName, claimName, filePath string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{File: &cdiv1.DataVolumeSourceFile{HostPath: hostPath, NodeName: nodeName, ClaimName: claimName, Path: filePath}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			config := &cdiv1.CDIConfig{ObjectMeta: metav1.ObjectMeta{Name: common.ConfigName}}
+			config.Spec.FileSourceHostPathPrefixes = []string{"/mnt"}
+			resp := validateDataVolumeCreate(dataVolume, config)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a file on a host path", "/mnt/usb", "node01", "", "images/fedora.qcow2", true),
+			Entry("reject a host path out of the prefixes of the CDIConfig", "/etc", "node01", "", "shadow", false),
+			Entry("accept a file on a PVC", "", "", "images", "fedora.qcow2", true),
+			Entry("reject both a host path and a PVC", "/mnt/usb", "node01", "images", "fedora.qcow2", false),
+			Entry("reject neither a host path nor a PVC", "", "", "", "fedora.qcow2", false),
+			Entry("reject a host path without node", "/mnt/usb", "", "", "fedora.qcow2", false),
+			Entry("reject a relative host path", "mnt/usb", "node01", "", "fedora.qcow2", false),
+			Entry("reject an invalid claim name", "", "", "Images", "fedora.qcow2", false),
+			Entry("reject an absolute path", "", "", "images", "/fedora.qcow2", false),
+			Entry("reject a path out of the volume", "/mnt/usb", "node01", "", "../etc/shadow", false),
+			Entry("reject a missing path", "", "", "images", "", false),
+		)
+
+		DescribeTable("should validate DataVolume with libvirt source on create", func(uri, domain, disk, hostKey string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Libvirt: &cdiv1.DataVolumeSourceLibvirt{URI: uri, Domain: domain, Disk: disk, SecretRef: "kvm01", HostKey: hostKey}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a domain disk", "qemu+ssh://kvm01.example.com/system", "vm01", "vda", "", true),
+			Entry("accept a port and a host key", "qemu+ssh://kvm01.example.com:2222/system", "vm01", "sdb", "ssh-ed25519 AAAAC3Nza", true),
+			Entry("reject a local URI", "qemu:///system", "vm01", "vda", "", false),
+			Entry("reject a URI without host", "qemu+ssh:///system", "vm01", "vda", "", false),
+			Entry("reject a user in the URI", "qemu+ssh://root@kvm01.example.com/system", "vm01", "vda", "", false),
+			Entry("reject a missing domain", "qemu+ssh://kvm01.example.com/system", "", "vda", "", false),
+			Entry("reject a disk path", "qemu+ssh://kvm01.example.com/system", "vm01", "/var/lib/libvirt/images/vm01.qcow2", "", false),
+			Entry("reject a host key without type", "qemu+ssh://kvm01.example.com/system", "vm01", "vda", "AAAAC3Nza", false),
+		)
+
+		DescribeTable("should validate DataVolume with Glance source on create", func(authURL, project, imageID, secretRef string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Glance: &cdiv1.DataVolumeSourceGlance{AuthURL: authURL, Project: project, ImageID: imageID, SecretRef: secretRef}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an image", "https://keystone.example.com:5000/v3", "admin", "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", "openstack", true),
+			Entry("reject an invalid auth url", "keystone", "admin", "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", "openstack", false),
+			Entry("reject a missing project", "https://keystone.example.com:5000/v3", "", "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", "openstack", false),
+			Entry("reject a missing secret", "https://keystone.example.com:5000/v3", "admin", "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", "", false),
+			Entry("reject an image name", "https://keystone.example.com:5000/v3", "admin", "cirros", "openstack", false),
+		)
+
+		DescribeTable("should validate DataVolume with GCS source on create", func(url string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{GCS: &cdiv1.DataVolumeSourceGCS{URL: url}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a gs url", "gs://bucket/images/disk.img", true),
+			Entry("reject an empty url", "", false),
+			Entry("reject a https url", "https://storage.googleapis.com/bucket/disk.img", false),
+			Entry("reject a url without object", "gs://bucket/", false),
+		)
+
+		DescribeTable("should validate DataVolume with GCE image source on create", func(image, exportURL string, allowed bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{GCEImage: &cdiv1.DataVolumeSourceGCEImage{Image: image, ExportURL: exportURL}}, newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an image", "projects/debian-cloud/global/images/debian-10-buster-v20210316", "", true),
+			Entry("accept an image family", "projects/debian-cloud/global/images/family/debian-10", "", true),
+			Entry("accept an export url", "projects/project/global/images/fedora", "gs://bucket/fedora.tar.gz", true),
+			Entry("reject an image name without project", "fedora", "", false),
+			Entry("reject a disk", "projects/project/zones/us-central1-a/disks/fedora", "", false),
+			Entry("reject a https export url", "projects/project/global/images/fedora", "https://storage.googleapis.com/bucket/fedora.tar.gz", false),
+		)
+
+		It("should accept DataVolume with HTTP source and oauth2 on create", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Source.HTTP.OAuth2 = &cdiv1.DataVolumeSourceHTTPOAuth2{
+				TokenURL:  "https://sso.example.com/token",
+				SecretRef: "oauth2secret",
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		DescribeTable("should reject DataVolume with HTTP source and invalid oauth2 on create", func(tokenURL, secretRef, tokenSecretRef string) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Source.HTTP.TokenSecretRef = tokenSecretRef
+			dataVolume.Spec.Source.HTTP.OAuth2 = &cdiv1.DataVolumeSourceHTTPOAuth2{
+				TokenURL:  tokenURL,
+				SecretRef: secretRef,
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		},
+			Entry("missing secretRef", "https://sso.example.com/token", "", ""),
+			Entry("invalid tokenURL", "ftp://sso.example.com/token", "oauth2secret", ""),
+			Entry("tokenSecretRef also set", "https://sso.example.com/token", "oauth2secret", "tokensecret"),
+		)
+
 		It("should reject DataVolume when target pvc exists", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			pvc := &corev1.PersistentVolumeClaim{
@@ -68,6 +531,32 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(true))
 		})
 
+		DescribeTable("should validate the platform of a Registry source on create", func(platform string, allowed bool) {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.Platform = platform
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an os and an architecture", "linux/arm64", true),
+			Entry("accept a variant", "linux/arm/v7", true),
+			Entry("reject an architecture alone", "arm64", false),
+			Entry("reject an empty architecture", "linux/", false),
+			Entry("reject upper case", "Linux/AMD64", false),
+		)
+
+		DescribeTable("should validate the signature policy of a Registry source on create", func(signature *cdiv1.DataVolumeSourceRegistrySignature, allowed bool) {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.Signature = signature
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a public key", &cdiv1.DataVolumeSourceRegistrySignature{ConfigMap: "cosign-key"}, true),
+			Entry("accept a keyless identity", &cdiv1.DataVolumeSourceRegistrySignature{ConfigMap: "sigstore-roots", Identity: "builder@example.com", Issuer: "https://accounts.example.com"}, true),
+			Entry("reject a missing configmap", &cdiv1.DataVolumeSourceRegistrySignature{Identity: "builder@example.com", Issuer: "https://accounts.example.com"}, false),
+			Entry("reject an identity without issuer", &cdiv1.DataVolumeSourceRegistrySignature{ConfigMap: "sigstore-roots", Identity: "builder@example.com"}, false),
+			Entry("reject an issuer without identity", &cdiv1.DataVolumeSourceRegistrySignature{ConfigMap: "sigstore-roots", Issuer: "https://accounts.example.com"}, false),
+		)
+
 		It("should accept DataVolume with PVC source on create", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			pvc := &corev1.PersistentVolumeClaim{
@@ -97,12 +586,60 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(true))
 		})
 
+		DescribeTable("should validate the adoption of an existing PVC on create", func(allowAdoption bool, ownerKind string, allowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			if allowAdoption {
+				dataVolume.Annotations = map[string]string{"cdi.kubevirt.io/storage.allowClaimAdoption": "true"}
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      dataVolume.Name,
+					Namespace: dataVolume.Namespace,
+				},
+				Spec: *dataVolume.Spec.PVC,
+			}
+			if ownerKind != "" {
+				isController := true
+				pvc.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: "owner", Controller: &isController}}
+			}
+			resp := validateDataVolumeCreate(dataVolume, pvc)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("reject an existing PVC without adoption", false, "", false),
+			Entry("accept an existing PVC with adoption", true, "", true),
+			Entry("reject a PVC controlled by another object with adoption", true, "VirtualMachine", false),
+		)
+
 		It("should reject DataVolume with PVC source on create if PVC does not exist", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			resp := validateDataVolumeCreate(dataVolume)
 			Expect(resp.Allowed).To(Equal(false))
 		})
 
+		It("should accept DataVolume with pvcNetwork source on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVCNetwork = dataVolume.Spec.Source.PVC
+			dataVolume.Spec.Source.PVC = nil
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      dataVolume.Spec.Source.PVCNetwork.Name,
+					Namespace: dataVolume.Spec.Source.PVCNetwork.Namespace,
+				},
+				Spec: *dataVolume.Spec.PVC,
+			}
+			resp := validateDataVolumeCreate(dataVolume, pvc)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should reject DataVolume with pvcNetwork source on create if PVC does not exist", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVCNetwork = dataVolume.Spec.Source.PVC
+			dataVolume.Spec.Source.PVC = nil
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.source.PVCNetwork"))
+		})
+
 		It("should reject invalid DataVolume source PVC namespace on create", func() {
 			dataVolume := newPVCDataVolume("testDV", "", "test")
 			resp := validateDataVolumeCreate(dataVolume)
@@ -150,6 +687,35 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(false))
 		})
 
+		It("should accept DataVolume with http source and no PVC size", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			delete(dataVolume.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should reject DataVolume with http archive source and no PVC size", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.ContentType = cdiv1.DataVolumeArchive
+			delete(dataVolume.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
+		It("should accept DataVolume with registry source and no PVC size", func() {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			delete(dataVolume.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should reject DataVolume with Blank source and no PVC size", func() {
+			dataVolume := newBlankDataVolume("blank")
+			delete(dataVolume.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
 		It("should accept DataVolume with Blank source and no content type", func() {
 			dataVolume := newBlankDataVolume("blank")
 			resp := validateDataVolumeCreate(dataVolume)
@@ -353,6 +919,44 @@ var _ = Describe("Validating Webhook", func() {
 
 			Entry("reject a spec change on un-approved fields, even with identical non-empty multi-stage fields", false, []string{"stage-1"}, false, []string{"stage-1"}, func(newDV *cdiv1.DataVolume) { newDV.Spec.Source.VDDK.URL = "tesing123" }, false),
 		)
+
+		DescribeTable("should accept checkpoint changes of a multi-stage import", func(setSource func(*cdiv1.DataVolume), checkpoints []string) {
+			oldDV := newMultistageDataVolume("multi-stage", false, checkpoints[:1])
+			setSource(oldDV)
+			oldBytes, _ := json.Marshal(&oldDV)
+			newDV := newMultistageDataVolume("multi-stage", true, checkpoints)
+			setSource(newDV)
+			newBytes, _ := json.Marshal(&newDV)
+
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Update,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: newBytes,
+					},
+					OldObject: runtime.RawExtension{
+						Raw: oldBytes,
+					},
+				},
+			}
+
+			resp := validateAdmissionReview(ar)
+			Expect(resp.Allowed).To(BeTrue())
+		},
+			Entry("of an AWS snapshot", func(dv *cdiv1.DataVolume) {
+				dv.Spec.Source.VDDK = nil
+				dv.Spec.Source.AWSSnapshot = &cdiv1.DataVolumeSourceAWSSnapshot{SnapshotID: "snap-01", Region: "us-east-1"}
+			}, []string{"snap-01", "snap-02"}),
+			Entry("of an imageio disk", func(dv *cdiv1.DataVolume) {
+				dv.Spec.Source.VDDK = nil
+				dv.Spec.Source.Imageio = &cdiv1.DataVolumeSourceImageIO{URL: "http://www.example.com/ovirt-engine/api", SecretRef: "secret", CertConfigMap: "tls-certs", DiskID: "disk-1"}
+			}, []string{"backup-1", "backup-2"}),
+		)
 	})
 })
 
@@ -504,8 +1108,7 @@ func newPVCSpec(sizeValue int64) *corev1.PersistentVolumeClaimSpec {
 }
 
 func validateDataVolumeCreate(dv *cdiv1.DataVolume, objects ...runtime.Object) *v1beta1.AdmissionResponse {
-	client := fakeclient.NewSimpleClientset(objects...)
-	wh := NewDataVolumeValidatingWebhook(client)
+	wh := newDataVolumeValidatingWebhook(objects...)
 
 	dvBytes, _ := json.Marshal(dv)
 	ar := &v1beta1.AdmissionReview{
@@ -526,11 +1129,24 @@ func validateDataVolumeCreate(dv *cdiv1.DataVolume, objects ...runtime.Object) *
 }
 
 func validateAdmissionReview(ar *v1beta1.AdmissionReview, objects ...runtime.Object) *v1beta1.AdmissionResponse {
-	client := fakeclient.NewSimpleClientset(objects...)
-	wh := NewDataVolumeValidatingWebhook(client)
+	wh := newDataVolumeValidatingWebhook(objects...)
 	return serve(ar, wh)
 }
 
+func newDataVolumeValidatingWebhook(objects ...runtime.Object) http.Handler {
+	k8sObjs := []runtime.Object{}
+	cdiObjs := []runtime.Object{}
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *cdiv1.CDIConfig:
+			cdiObjs = append(cdiObjs, obj)
+		default:
+			k8sObjs = append(k8sObjs, obj)
+		}
+	}
+	return NewDataVolumeValidatingWebhook(fakeclient.NewSimpleClientset(k8sObjs...), cdiclient.NewSimpleClientset(cdiObjs...))
+}
+
 func serve(ar *v1beta1.AdmissionReview, handler http.Handler) *v1beta1.AdmissionResponse {
 	reqBytes, _ := json.Marshal(ar)
 	req, err := http.NewRequest("POST", "/foobar", bytes.NewReader(reqBytes))
@@ -34,6 +34,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdiv1alpha1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
@@ -52,19 +53,34 @@ type admissionHandler struct {
 }
 
 // NewDataVolumeValidatingWebhook creates a new DataVolumeValidation webhook
-func NewDataVolumeValidatingWebhook(client kubernetes.Interface) http.Handler {
-	return newAdmissionHandler(&dataVolumeValidatingWebhook{client: client})
+func NewDataVolumeValidatingWebhook(client kubernetes.Interface, cdiClient cdiclient.Interface) http.Handler {
+	return newAdmissionHandler(&dataVolumeValidatingWebhook{client: client, cdiClient: cdiClient})
 }
 
 // NewDataVolumeMutatingWebhook creates a new DataVolumeMutation webhook
-func NewDataVolumeMutatingWebhook(client kubernetes.Interface, key *rsa.PrivateKey) http.Handler {
+func NewDataVolumeMutatingWebhook(client kubernetes.Interface, cdiClient cdiclient.Interface, key *rsa.PrivateKey) http.Handler {
 	generator := newCloneTokenGenerator(key)
-	return newAdmissionHandler(&dataVolumeMutatingWebhook{client: client, tokenGenerator: generator, proxy: &sarProxy{client: client}})
+	return newAdmissionHandler(&dataVolumeMutatingWebhook{client: client, cdiClient: cdiClient, tokenGenerator: generator, proxy: &sarProxy{client: client}})
 }
 
 // NewCDIValidatingWebhook creates a new CDI validating webhook
-func NewCDIValidatingWebhook(client cdiclient.Interface) http.Handler {
-	return newAdmissionHandler(&cdiValidatingWebhook{client: client})
+func NewCDIValidatingWebhook(client kubernetes.Interface, cdiClient cdiclient.Interface) http.Handler {
+	return newAdmissionHandler(&cdiValidatingWebhook{client: client, cdiClient: cdiClient})
+}
+
+// NewStorageProfileValidatingWebhook creates a new StorageProfile validating webhook
+func NewStorageProfileValidatingWebhook(client kubernetes.Interface, controllerRuntimeClient runtimeclient.Client) http.Handler {
+	return newAdmissionHandler(&storageProfileValidatingWebhook{client: client, controllerRuntimeClient: controllerRuntimeClient})
+}
+
+// NewDataImportCronValidatingWebhook creates a new DataImportCron validating webhook
+func NewDataImportCronValidatingWebhook(client kubernetes.Interface, cdiClient cdiclient.Interface) http.Handler {
+	return newAdmissionHandler(&dataImportCronValidatingWebhook{dataVolumeValidatingWebhook{client: client, cdiClient: cdiClient}})
+}
+
+// NewVolumeImportSourceValidatingWebhook creates a new VolumeImportSource validating webhook
+func NewVolumeImportSourceValidatingWebhook(client kubernetes.Interface, cdiClient cdiclient.Interface) http.Handler {
+	return newAdmissionHandler(&volumeImportSourceValidatingWebhook{dataVolumeValidatingWebhook{client: client, cdiClient: cdiClient}})
 }
 
 func newCloneTokenGenerator(key *rsa.PrivateKey) token.Generator {
@@ -0,0 +1,273 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/storagecapabilities"
+)
+
+type storageProfileValidatingWebhook struct {
+	client kubernetes.Interface
+	// controllerRuntimeClient gets the resources without a typed client, like the VolumeSnapshotClasses
+	controllerRuntimeClient client.Client
+}
+
+func (wh *storageProfileValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
+	klog.V(3).Infof("Got AdmissionReview %+v", ar)
+
+	if ar.Request.Resource.Group != cdiv1.SchemeGroupVersion.Group || ar.Request.Resource.Resource != "storageprofiles" {
+		klog.V(3).Infof("Got unexpected resource type %s", ar.Request.Resource.Resource)
+		return toAdmissionResponseError(fmt.Errorf("unexpected resource: %s", ar.Request.Resource.Resource))
+	}
+
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, storageProfile); err != nil {
+		return toAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1beta1.Update {
+		oldStorageProfile := &cdiv1.StorageProfile{}
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldStorageProfile); err != nil {
+			return toAdmissionResponseError(err)
+		}
+
+		// The controller updates the status, validating the unchanged spec again would block it when the driver changed
+		if reflect.DeepEqual(storageProfile.Spec, oldStorageProfile.Spec) {
+			return allowedAdmissionResponse()
+		}
+	}
+
+	causes, err := wh.validateStorageProfileSpec(storageProfile)
+	if err != nil {
+		return toAdmissionResponseError(err)
+	}
+
+	if len(causes) > 0 {
+		klog.Infof("rejected StorageProfile admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+
+	return allowedAdmissionResponse()
+}
+
+// validateStorageProfileSpec validates the overrides of the spec against the capabilities of the provisioner of the
+// StorageClass of the same name
+func (wh *storageProfileValidatingWebhook) validateStorageProfileSpec(storageProfile *cdiv1.StorageProfile) ([]metav1.StatusCause, error) {
+	var causes []metav1.StatusCause
+	field := k8sfield.NewPath("spec")
+	spec := &storageProfile.Spec
+
+	provisioner, err := wh.getProvisioner(storageProfile.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, claimPropertySet := range spec.ClaimPropertySets {
+		causes = append(causes, validateClaimPropertySet(provisioner, &claimPropertySet, field.Child("claimPropertySets").Index(i))...)
+	}
+
+	if spec.CloneStrategy != nil {
+		cause, err := wh.validateCloneStrategy(provisioner, *spec.CloneStrategy, spec.SnapshotClass, field.Child("cloneStrategy"))
+		if err != nil {
+			return nil, err
+		}
+		if cause != nil {
+			causes = append(causes, *cause)
+		}
+	}
+
+	if spec.SnapshotClass != nil {
+		cause, err := wh.validateSnapshotClass(provisioner, *spec.SnapshotClass, field.Child("snapshotClass"))
+		if err != nil {
+			return nil, err
+		}
+		if cause != nil {
+			causes = append(causes, *cause)
+		}
+	}
+
+	return causes, nil
+}
+
+// validateClaimPropertySet checks the access and volume modes are valid and, when its capabilities are known,
+// supported by the provisioner
+func validateClaimPropertySet(provisioner string, claimPropertySet *cdiv1.ClaimPropertySet, field *k8sfield.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	volumeMode := corev1.PersistentVolumeFilesystem
+	if claimPropertySet.VolumeMode != nil {
+		volumeMode = *claimPropertySet.VolumeMode
+		if volumeMode != corev1.PersistentVolumeFilesystem && volumeMode != corev1.PersistentVolumeBlock {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Unsupported value: \"%s\": supported values: \"Block\", \"Filesystem\"", volumeMode),
+				Field:   field.Child("volumeMode").String(),
+			})
+			return causes
+		}
+	}
+
+	if len(claimPropertySet.AccessModes) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "Required value: at least 1 access mode is required",
+			Field:   field.Child("accessModes").String(),
+		})
+		return causes
+	}
+
+	for _, accessMode := range claimPropertySet.AccessModes {
+		if accessMode != corev1.ReadWriteOnce && accessMode != corev1.ReadOnlyMany && accessMode != corev1.ReadWriteMany {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Unsupported value: \"%s\": supported values: \"ReadOnlyMany\", \"ReadWriteMany\", \"ReadWriteOnce\"", accessMode),
+				Field:   field.Child("accessModes").String(),
+			})
+			continue
+		}
+		if supported, known := storagecapabilities.Supports(provisioner, accessMode, volumeMode); known && !supported {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("provisioner %s does not support access mode %s in volume mode %s", provisioner, accessMode, volumeMode),
+				Field:   field.Child("accessModes").String(),
+			})
+		}
+	}
+
+	return causes
+}
+
+// validateCloneStrategy checks the provisioner has the CSIDriver needed by csi-clone, or the VolumeSnapshotClass needed
+// by snapshot
+func (wh *storageProfileValidatingWebhook) validateCloneStrategy(provisioner string, cloneStrategy cdiv1.CDICloneStrategy, snapshotClass *string, field *k8sfield.Path) (*metav1.StatusCause, error) {
+	switch cloneStrategy {
+	case cdiv1.CloneStrategyHostAssisted:
+		return nil, nil
+	case cdiv1.CloneStrategyCsiClone:
+		if provisioner == "" {
+			return nil, nil
+		}
+		_, err := wh.client.StorageV1().CSIDrivers().Get(context.TODO(), provisioner, metav1.GetOptions{})
+		if err == nil {
+			return nil, nil
+		}
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("clone strategy %s needs a CSI driver, provisioner %s has no CSIDriver", cloneStrategy, provisioner),
+			Field:   field.String(),
+		}, nil
+	case cdiv1.CloneStrategySnapshot:
+		if provisioner == "" || snapshotClass != nil {
+			// The snapshot class is validated on its own
+			return nil, nil
+		}
+		snapshotClasses, err := wh.listVolumeSnapshotClasses()
+		if err != nil {
+			return nil, err
+		}
+		for _, vsc := range snapshotClasses {
+			if vsc.Driver == provisioner {
+				return nil, nil
+			}
+		}
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("clone strategy %s needs a VolumeSnapshotClass, provisioner %s has none", cloneStrategy, provisioner),
+			Field:   field.String(),
+		}, nil
+	}
+
+	return &metav1.StatusCause{
+		Type:    metav1.CauseTypeFieldValueNotSupported,
+		Message: fmt.Sprintf("Unsupported value: \"%s\": supported values: \"copy\", \"csi-clone\", \"snapshot\"", cloneStrategy),
+		Field:   field.String(),
+	}, nil
+}
+
+// validateSnapshotClass checks the VolumeSnapshotClass exists and snapshots the volumes of the provisioner
+func (wh *storageProfileValidatingWebhook) validateSnapshotClass(provisioner, snapshotClass string, field *k8sfield.Path) (*metav1.StatusCause, error) {
+	vsc := &snapshotv1.VolumeSnapshotClass{}
+	if err := wh.controllerRuntimeClient.Get(context.TODO(), types.NamespacedName{Name: snapshotClass}, vsc); err != nil {
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotFound,
+			Message: fmt.Sprintf("VolumeSnapshotClass %s not found", snapshotClass),
+			Field:   field.String(),
+		}, nil
+	}
+
+	if provisioner != "" && vsc.Driver != provisioner {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("VolumeSnapshotClass %s has driver %s, not provisioner %s", snapshotClass, vsc.Driver, provisioner),
+			Field:   field.String(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// listVolumeSnapshotClasses returns the VolumeSnapshotClasses, none when the snapshot API is not installed
+func (wh *storageProfileValidatingWebhook) listVolumeSnapshotClasses() ([]snapshotv1.VolumeSnapshotClass, error) {
+	snapshotClasses := &snapshotv1.VolumeSnapshotClassList{}
+	if err := wh.controllerRuntimeClient.List(context.TODO(), snapshotClasses); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return snapshotClasses.Items, nil
+}
+
+// getProvisioner returns the provisioner of the StorageClass of the StorageProfile, empty if the StorageClass does not
+// exist
+func (wh *storageProfileValidatingWebhook) getProvisioner(storageClassName string) (string, error) {
+	storageClass, err := wh.client.StorageV1().StorageClasses().Get(context.TODO(), storageClassName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return storageClass.Provisioner, nil
+}
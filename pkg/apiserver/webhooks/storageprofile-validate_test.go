@@ -0,0 +1,210 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("StorageProfile Webhook", func() {
+	Context("with StorageProfile admission review", func() {
+		const (
+			cephProvisioner = "rbd.csi.ceph.com"
+			nfsProvisioner  = "example.com/nfs"
+		)
+
+		strategy := func(cloneStrategy cdiv1.CDICloneStrategy) *cdiv1.CDICloneStrategy {
+			return &cloneStrategy
+		}
+
+		volumeMode := func(mode corev1.PersistentVolumeMode) *corev1.PersistentVolumeMode {
+			return &mode
+		}
+
+		newStorageProfile := func(provisioner string, spec cdiv1.StorageProfileSpec) *cdiv1.StorageProfile {
+			return &cdiv1.StorageProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: provisioner + "-sc",
+				},
+				Spec: spec,
+				Status: cdiv1.StorageProfileStatus{
+					Provisioner: &provisioner,
+				},
+			}
+		}
+
+		objects := func() []runtime.Object {
+			return []runtime.Object{
+				&storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: cephProvisioner + "-sc"},
+					Provisioner: cephProvisioner,
+				},
+				&storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: nfsProvisioner + "-sc"},
+					Provisioner: nfsProvisioner,
+				},
+				&storagev1.CSIDriver{
+					ObjectMeta: metav1.ObjectMeta{Name: cephProvisioner},
+				},
+				&snapshotv1.VolumeSnapshotClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "ceph-snapshots"},
+					Driver:     cephProvisioner,
+				},
+			}
+		}
+
+		DescribeTable("should", func(provisioner string, spec cdiv1.StorageProfileSpec, allowed bool) {
+			storageProfile := newStorageProfile(provisioner, spec)
+			bytes, _ := json.Marshal(storageProfile)
+
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "storageprofiles",
+					},
+					Object: runtime.RawExtension{
+						Raw: bytes,
+					},
+				},
+			}
+
+			resp := validateStorageProfiles(ar, objects()...)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an empty spec", cephProvisioner, cdiv1.StorageProfileSpec{}, true),
+			Entry("accept a supported claim property set", cephProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: volumeMode(corev1.PersistentVolumeBlock)},
+				},
+			}, true),
+			Entry("reject an access mode the provisioner does not support", cephProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: volumeMode(corev1.PersistentVolumeFilesystem)},
+				},
+			}, false),
+			Entry("accept any valid claim property set of an unknown provisioner", nfsProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}},
+				},
+			}, true),
+			Entry("reject a claim property set without access mode", nfsProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{VolumeMode: volumeMode(corev1.PersistentVolumeBlock)},
+				},
+			}, false),
+			Entry("reject an invalid access mode", nfsProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{AccessModes: []corev1.PersistentVolumeAccessMode{"ReadWriteSometimes"}},
+				},
+			}, false),
+			Entry("reject an invalid volume mode", nfsProvisioner, cdiv1.StorageProfileSpec{
+				ClaimPropertySets: []cdiv1.ClaimPropertySet{
+					{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: volumeMode("Tape")},
+				},
+			}, false),
+			Entry("reject an invalid clone strategy", cephProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy("teleport")}, false),
+			Entry("accept copy for any provisioner", nfsProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategyHostAssisted)}, true),
+			Entry("accept csi-clone for a CSI driver", cephProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategyCsiClone)}, true),
+			Entry("reject csi-clone without CSI driver", nfsProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategyCsiClone)}, false),
+			Entry("accept snapshot with a VolumeSnapshotClass of the driver", cephProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategySnapshot)}, true),
+			Entry("reject snapshot without VolumeSnapshotClass of the driver", nfsProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategySnapshot)}, false),
+			Entry("accept the VolumeSnapshotClass of the driver", cephProvisioner, cdiv1.StorageProfileSpec{SnapshotClass: &[]string{"ceph-snapshots"}[0]}, true),
+			Entry("reject the VolumeSnapshotClass of another driver", nfsProvisioner, cdiv1.StorageProfileSpec{SnapshotClass: &[]string{"ceph-snapshots"}[0]}, false),
+			Entry("reject a VolumeSnapshotClass that does not exist", cephProvisioner, cdiv1.StorageProfileSpec{SnapshotClass: &[]string{"missing"}[0]}, false),
+		)
+
+		It("should accept an update of the status with an invalid spec", func() {
+			storageProfile := newStorageProfile(nfsProvisioner, cdiv1.StorageProfileSpec{CloneStrategy: strategy(cdiv1.CloneStrategyCsiClone)})
+			oldBytes, _ := json.Marshal(storageProfile)
+			storageProfile.Status.CloneStrategy = strategy(cdiv1.CloneStrategyCsiClone)
+			bytes, _ := json.Marshal(storageProfile)
+
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Update,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "storageprofiles",
+					},
+					Object: runtime.RawExtension{
+						Raw: bytes,
+					},
+					OldObject: runtime.RawExtension{
+						Raw: oldBytes,
+					},
+				},
+			}
+
+			resp := validateStorageProfiles(ar, objects()...)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject another resource", func() {
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+				},
+			}
+
+			resp := validateStorageProfiles(ar)
+			Expect(resp.Allowed).To(BeFalse())
+		})
+	})
+})
+
+func validateStorageProfiles(ar *admissionv1beta1.AdmissionReview, objects ...runtime.Object) *admissionv1beta1.AdmissionResponse {
+	var k8sObjects, snapshotObjects []runtime.Object
+	for _, obj := range objects {
+		if _, ok := obj.(*snapshotv1.VolumeSnapshotClass); ok {
+			snapshotObjects = append(snapshotObjects, obj)
+		} else {
+			k8sObjects = append(k8sObjects, obj)
+		}
+	}
+	s := runtime.NewScheme()
+	Expect(snapshotv1.AddToScheme(s)).To(Succeed())
+	client := fakeclient.NewSimpleClientset(k8sObjects...)
+	wh := NewStorageProfileValidatingWebhook(client, fake.NewFakeClientWithScheme(s, snapshotObjects...))
+	return serve(ar, wh)
+}
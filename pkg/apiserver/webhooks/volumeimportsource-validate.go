@@ -0,0 +1,97 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+type volumeImportSourceValidatingWebhook struct {
+	dataVolumeValidatingWebhook
+}
+
+func (wh *volumeImportSourceValidatingWebhook) Admit(ar admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
+	klog.V(3).Infof("Got AdmissionReview %+v", ar)
+
+	if ar.Request.Resource.Group != cdiv1.SchemeGroupVersion.Group || ar.Request.Resource.Resource != "volumeimportsources" {
+		klog.V(3).Infof("Got unexpected resource type %s", ar.Request.Resource.Resource)
+		return toAdmissionResponseError(fmt.Errorf("unexpected resource: %s", ar.Request.Resource.Resource))
+	}
+
+	importSource := &cdiv1.VolumeImportSource{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, importSource); err != nil {
+		return toAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1beta1.Update {
+		oldImportSource := &cdiv1.VolumeImportSource{}
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldImportSource); err != nil {
+			return toAdmissionResponseError(err)
+		}
+
+		if reflect.DeepEqual(importSource.Spec, oldImportSource.Spec) {
+			return allowedAdmissionResponse()
+		}
+	}
+
+	causes := wh.validateVolumeImportSourceSpec(ar.Request, k8sfield.NewPath("spec"), &importSource.Spec)
+	if len(causes) > 0 {
+		klog.Infof("rejected VolumeImportSource admission %s", causes)
+		return toRejectedAdmissionResponse(causes)
+	}
+
+	return allowedAdmissionResponse()
+}
+
+// validateVolumeImportSourceSpec validates the source like the source of a DataVolume, the populator controller
+// imports it into the PVCs referencing the VolumeImportSource like the DataVolumes do
+func (wh *volumeImportSourceValidatingWebhook) validateVolumeImportSourceSpec(request *admissionv1beta1.AdmissionRequest, field *k8sfield.Path, spec *cdiv1.VolumeImportSourceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	source := &spec.Source
+	if source.PVC != nil || source.PVCNetwork != nil || source.Snapshot != nil || source.Upload != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be an import source, pvc, pvcNetwork, snapshot and upload sources are not imported", field.Child("source").String()),
+			Field:   field.Child("source").String(),
+		})
+		return causes
+	}
+
+	// The populator controller imports the source on behalf of the user, so the user must be allowed to use its
+	// service account on updates as well
+	sourceRequest := request.DeepCopy()
+	sourceRequest.Operation = admissionv1beta1.Create
+	dataVolumeSpec := &cdiv1.DataVolumeSpec{
+		Source:        spec.Source,
+		ContentType:   spec.ContentType,
+		Preallocation: spec.Preallocation,
+	}
+	return wh.validateDataVolumeSource(sourceRequest, field, dataVolumeSpec)
+}
@@ -0,0 +1,148 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+)
+
+var _ = Describe("VolumeImportSource Webhook", func() {
+	Context("with VolumeImportSource admission review", func() {
+		newVolumeImportSource := func(mutate func(*cdiv1.VolumeImportSource)) *cdiv1.VolumeImportSource {
+			importSource := &cdiv1.VolumeImportSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fedora",
+					Namespace: "default",
+				},
+				Spec: cdiv1.VolumeImportSourceSpec{
+					Source: cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "https://www.example.com/fedora.qcow2"}},
+				},
+			}
+			if mutate != nil {
+				mutate(importSource)
+			}
+			return importSource
+		}
+
+		admissionReview := func(operation admissionv1beta1.Operation, importSource, old *cdiv1.VolumeImportSource) *admissionv1beta1.AdmissionReview {
+			bytes, _ := json.Marshal(importSource)
+			ar := &admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					Operation: operation,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "volumeimportsources",
+					},
+					Object: runtime.RawExtension{
+						Raw: bytes,
+					},
+				},
+			}
+			if old != nil {
+				oldBytes, _ := json.Marshal(old)
+				ar.Request.OldObject = runtime.RawExtension{Raw: oldBytes}
+			}
+			return ar
+		}
+
+		DescribeTable("should", func(mutate func(*cdiv1.VolumeImportSource), allowed bool) {
+			resp := validateVolumeImportSource(admissionReview(admissionv1beta1.Create, newVolumeImportSource(mutate), nil))
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept an http source", nil, true),
+			Entry("accept a registry source", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{URL: "docker://quay.io/containerdisks/fedora:latest"}}
+			}, true),
+			Entry("accept a blank source", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{Blank: &cdiv1.DataVolumeBlankImage{}}
+			}, true),
+			Entry("reject a missing source", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{}
+			}, false),
+			Entry("reject multiple sources", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2"}
+			}, false),
+			Entry("reject an invalid url", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source.HTTP.URL = "www.example.com/fedora.qcow2"
+			}, false),
+			Entry("reject an invalid content type", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.ContentType = "iso"
+			}, false),
+			Entry("reject an archive of a registry image", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{URL: "docker://quay.io/containerdisks/fedora:latest"}}
+				importSource.Spec.ContentType = cdiv1.DataVolumeArchive
+			}, false),
+			Entry("reject a PVC source", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "default", Name: "fedora"}}
+			}, false),
+			Entry("reject an upload source", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{}}
+			}, false),
+			Entry("reject a service account the user may not impersonate", func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2", ServiceAccountName: "importer"}}
+			}, false),
+		)
+
+		It("should accept an update without change of the spec", func() {
+			old := newVolumeImportSource(nil)
+			importSource := old.DeepCopy()
+			importSource.Labels = map[string]string{"app": "fedora"}
+			resp := validateVolumeImportSource(admissionReview(admissionv1beta1.Update, importSource, old))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should check the user may use the service account of the source on update", func() {
+			old := newVolumeImportSource(func(importSource *cdiv1.VolumeImportSource) {
+				importSource.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2"}}
+			})
+			importSource := old.DeepCopy()
+			importSource.Spec.Source.S3.ServiceAccountName = "importer"
+			// The fake client does not allow the subject access reviews
+			resp := validateVolumeImportSource(admissionReview(admissionv1beta1.Update, importSource, old))
+			Expect(resp.Allowed).To(BeFalse())
+		})
+
+		It("should reject another resource", func() {
+			ar := admissionReview(admissionv1beta1.Create, newVolumeImportSource(nil), nil)
+			ar.Request.Resource.Resource = "datavolumes"
+			resp := validateVolumeImportSource(ar)
+			Expect(resp.Allowed).To(BeFalse())
+		})
+	})
+})
+
+func validateVolumeImportSource(ar *admissionv1beta1.AdmissionReview, objects ...runtime.Object) *admissionv1beta1.AdmissionResponse {
+	client := fakeclient.NewSimpleClientset(objects...)
+	wh := NewVolumeImportSourceValidatingWebhook(client, cdiclient.NewSimpleClientset())
+	return serve(ar, wh)
+}
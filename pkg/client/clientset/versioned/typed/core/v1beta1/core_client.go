@@ -28,7 +28,15 @@ type CdiV1beta1Interface interface {
 	RESTClient() rest.Interface
 	CDIsGetter
 	CDIConfigsGetter
+	CloneGrantsGetter
+	DataExportsGetter
+	DataImportCronsGetter
+	DataSourcesGetter
 	DataVolumesGetter
+	StorageProfilesGetter
+	VolumeCloneSourcesGetter
+	VolumeImportSourcesGetter
+	VolumeUploadSourcesGetter
 }
 
 // CdiV1beta1Client is used to interact with features provided by the cdi.kubevirt.io group.
@@ -44,10 +52,42 @@ func (c *CdiV1beta1Client) CDIConfigs() CDIConfigInterface {
 	return newCDIConfigs(c)
 }
 
+func (c *CdiV1beta1Client) CloneGrants(namespace string) CloneGrantInterface {
+	return newCloneGrants(c, namespace)
+}
+
+func (c *CdiV1beta1Client) DataExports(namespace string) DataExportInterface {
+	return newDataExports(c, namespace)
+}
+
+func (c *CdiV1beta1Client) DataImportCrons(namespace string) DataImportCronInterface {
+	return newDataImportCrons(c, namespace)
+}
+
+func (c *CdiV1beta1Client) DataSources(namespace string) DataSourceInterface {
+	return newDataSources(c, namespace)
+}
+
 func (c *CdiV1beta1Client) DataVolumes(namespace string) DataVolumeInterface {
 	return newDataVolumes(c, namespace)
 }
 
+func (c *CdiV1beta1Client) StorageProfiles() StorageProfileInterface {
+	return newStorageProfiles(c)
+}
+
+func (c *CdiV1beta1Client) VolumeCloneSources(namespace string) VolumeCloneSourceInterface {
+	return newVolumeCloneSources(c, namespace)
+}
+
+func (c *CdiV1beta1Client) VolumeImportSources(namespace string) VolumeImportSourceInterface {
+	return newVolumeImportSources(c, namespace)
+}
+
+func (c *CdiV1beta1Client) VolumeUploadSources(namespace string) VolumeUploadSourceInterface {
+	return newVolumeUploadSources(c, namespace)
+}
+
 // NewForConfig creates a new CdiV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*CdiV1beta1Client, error) {
 	config := *c
@@ -22,4 +22,20 @@ type CDIExpansion interface{}
 
 type CDIConfigExpansion interface{}
 
+type CloneGrantExpansion interface{}
+
+type DataExportExpansion interface{}
+
+type DataImportCronExpansion interface{}
+
+type DataSourceExpansion interface{}
+
 type DataVolumeExpansion interface{}
+
+type StorageProfileExpansion interface{}
+
+type VolumeCloneSourceExpansion interface{}
+
+type VolumeImportSourceExpansion interface{}
+
+type VolumeUploadSourceExpansion interface{}
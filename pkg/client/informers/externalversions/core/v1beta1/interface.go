@@ -28,8 +28,24 @@ type Interface interface {
 	CDIs() CDIInformer
 	// CDIConfigs returns a CDIConfigInformer.
 	CDIConfigs() CDIConfigInformer
+	// CloneGrants returns a CloneGrantInformer.
+	CloneGrants() CloneGrantInformer
+	// DataExports returns a DataExportInformer.
+	DataExports() DataExportInformer
+	// DataImportCrons returns a DataImportCronInformer.
+	DataImportCrons() DataImportCronInformer
+	// DataSources returns a DataSourceInformer.
+	DataSources() DataSourceInformer
 	// DataVolumes returns a DataVolumeInformer.
 	DataVolumes() DataVolumeInformer
+	// StorageProfiles returns a StorageProfileInformer.
+	StorageProfiles() StorageProfileInformer
+	// VolumeCloneSources returns a VolumeCloneSourceInformer.
+	VolumeCloneSources() VolumeCloneSourceInformer
+	// VolumeImportSources returns a VolumeImportSourceInformer.
+	VolumeImportSources() VolumeImportSourceInformer
+	// VolumeUploadSources returns a VolumeUploadSourceInformer.
+	VolumeUploadSources() VolumeUploadSourceInformer
 }
 
 type version struct {
@@ -53,7 +69,47 @@ func (v *version) CDIConfigs() CDIConfigInformer {
 	return &cDIConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// CloneGrants returns a CloneGrantInformer.
+func (v *version) CloneGrants() CloneGrantInformer {
+	return &cloneGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DataExports returns a DataExportInformer.
+func (v *version) DataExports() DataExportInformer {
+	return &dataExportInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DataImportCrons returns a DataImportCronInformer.
+func (v *version) DataImportCrons() DataImportCronInformer {
+	return &dataImportCronInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DataSources returns a DataSourceInformer.
+func (v *version) DataSources() DataSourceInformer {
+	return &dataSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DataVolumes returns a DataVolumeInformer.
 func (v *version) DataVolumes() DataVolumeInformer {
 	return &dataVolumeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// StorageProfiles returns a StorageProfileInformer.
+func (v *version) StorageProfiles() StorageProfileInformer {
+	return &storageProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// VolumeCloneSources returns a VolumeCloneSourceInformer.
+func (v *version) VolumeCloneSources() VolumeCloneSourceInformer {
+	return &volumeCloneSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// VolumeImportSources returns a VolumeImportSourceInformer.
+func (v *version) VolumeImportSources() VolumeImportSourceInformer {
+	return &volumeImportSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// VolumeUploadSources returns a VolumeUploadSourceInformer.
+func (v *version) VolumeUploadSources() VolumeUploadSourceInformer {
+	return &volumeUploadSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
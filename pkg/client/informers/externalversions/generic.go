@@ -68,8 +68,24 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CDIs().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("cdiconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CDIConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clonegrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().CloneGrants().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("dataexports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataExports().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("dataimportcrons"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataImportCrons().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("datasources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataSources().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("datavolumes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataVolumes().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("storageprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().StorageProfiles().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("volumeclonesources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().VolumeCloneSources().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("volumeimportsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().VolumeImportSources().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("volumeuploadsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().VolumeUploadSources().Informer()}, nil
 
 		// Group=upload.cdi.kubevirt.io, Version=v1alpha1
 	case uploadv1alpha1.SchemeGroupVersion.WithResource("uploadtokenrequests"):
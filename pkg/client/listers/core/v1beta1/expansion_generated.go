@@ -26,6 +26,38 @@ type CDIListerExpansion interface{}
 // CDIConfigLister.
 type CDIConfigListerExpansion interface{}
 
+// CloneGrantListerExpansion allows custom methods to be added to
+// CloneGrantLister.
+type CloneGrantListerExpansion interface{}
+
+// CloneGrantNamespaceListerExpansion allows custom methods to be added to
+// CloneGrantNamespaceLister.
+type CloneGrantNamespaceListerExpansion interface{}
+
+// DataExportListerExpansion allows custom methods to be added to
+// DataExportLister.
+type DataExportListerExpansion interface{}
+
+// DataExportNamespaceListerExpansion allows custom methods to be added to
+// DataExportNamespaceLister.
+type DataExportNamespaceListerExpansion interface{}
+
+// DataImportCronListerExpansion allows custom methods to be added to
+// DataImportCronLister.
+type DataImportCronListerExpansion interface{}
+
+// DataImportCronNamespaceListerExpansion allows custom methods to be added to
+// DataImportCronNamespaceLister.
+type DataImportCronNamespaceListerExpansion interface{}
+
+// DataSourceListerExpansion allows custom methods to be added to
+// DataSourceLister.
+type DataSourceListerExpansion interface{}
+
+// DataSourceNamespaceListerExpansion allows custom methods to be added to
+// DataSourceNamespaceLister.
+type DataSourceNamespaceListerExpansion interface{}
+
 // DataVolumeListerExpansion allows custom methods to be added to
 // DataVolumeLister.
 type DataVolumeListerExpansion interface{}
@@ -33,3 +65,31 @@ type DataVolumeListerExpansion interface{}
 // DataVolumeNamespaceListerExpansion allows custom methods to be added to
 // DataVolumeNamespaceLister.
 type DataVolumeNamespaceListerExpansion interface{}
+
+// StorageProfileListerExpansion allows custom methods to be added to
+// StorageProfileLister.
+type StorageProfileListerExpansion interface{}
+
+// VolumeCloneSourceListerExpansion allows custom methods to be added to
+// VolumeCloneSourceLister.
+type VolumeCloneSourceListerExpansion interface{}
+
+// VolumeCloneSourceNamespaceListerExpansion allows custom methods to be added to
+// VolumeCloneSourceNamespaceLister.
+type VolumeCloneSourceNamespaceListerExpansion interface{}
+
+// VolumeImportSourceListerExpansion allows custom methods to be added to
+// VolumeImportSourceLister.
+type VolumeImportSourceListerExpansion interface{}
+
+// VolumeImportSourceNamespaceListerExpansion allows custom methods to be added to
+// VolumeImportSourceNamespaceLister.
+type VolumeImportSourceNamespaceListerExpansion interface{}
+
+// VolumeUploadSourceListerExpansion allows custom methods to be added to
+// VolumeUploadSourceLister.
+type VolumeUploadSourceListerExpansion interface{}
+
+// VolumeUploadSourceNamespaceListerExpansion allows custom methods to be added to
+// VolumeUploadSourceNamespaceLister.
+type VolumeUploadSourceNamespaceListerExpansion interface{}
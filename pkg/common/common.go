@@ -48,6 +48,20 @@ const (
 	ImporterS3Host = "s3.amazonaws.com"
 	// ImporterCertDir is where the configmap containing certs will be mounted
 	ImporterCertDir = "/certs"
+	// ImporterNFSDir is where the directory of the image file on the NFS export will be mounted
+	ImporterNFSDir = "/nfs"
+	// ImporterFileDir is where the host path or PVC containing the image file of the file source will be mounted
+	ImporterFileDir = "/source"
+	// ImporterClientCertDir is where the secret containing the client certificate will be mounted
+	ImporterClientCertDir = "/client-certs"
+	// ImporterTrustedCADir is where the configmap containing the cluster-wide trusted CA bundle will be mounted
+	ImporterTrustedCADir = "/trusted-ca"
+	// ImporterSignatureDir is where the configmap containing the public key or the trusted roots verifying image signatures will be mounted
+	ImporterSignatureDir = "/signature"
+	// TrustedCAConfigMap is the name of the configmap containing the cluster-wide trusted CA bundle
+	TrustedCAConfigMap = "cdi-trusted-ca"
+	// TrustedCABundleKey is the key of the CA bundle in the trusted CA configmap
+	TrustedCABundleKey = "ca-bundle.crt"
 	// DefaultPullPolicy imports k8s "IfNotPresent" string for the import_controller_gingko_test and the cdi-controller executable
 	DefaultPullPolicy = string(v1.PullIfNotPresent)
 
@@ -63,10 +77,95 @@ const (
 	ImporterAccessKeyID = "IMPORTER_ACCESS_KEY_ID"
 	// ImporterSecretKey provides a constant to capture our env variable "IMPORTER_SECRET_KEY"
 	ImporterSecretKey = "IMPORTER_SECRET_KEY"
+	// ImporterBearerToken provides a constant to capture our env variable "IMPORTER_BEARER_TOKEN"
+	ImporterBearerToken = "IMPORTER_BEARER_TOKEN"
+	// ImporterOAuth2TokenURL provides a constant to capture our env variable "IMPORTER_OAUTH2_TOKEN_URL"
+	ImporterOAuth2TokenURL = "IMPORTER_OAUTH2_TOKEN_URL"
+	// ImporterOAuth2ClientID provides a constant to capture our env variable "IMPORTER_OAUTH2_CLIENT_ID"
+	ImporterOAuth2ClientID = "IMPORTER_OAUTH2_CLIENT_ID"
+	// ImporterOAuth2ClientSecret provides a constant to capture our env variable "IMPORTER_OAUTH2_CLIENT_SECRET"
+	ImporterOAuth2ClientSecret = "IMPORTER_OAUTH2_CLIENT_SECRET"
+	// ImporterOAuth2Scopes provides a constant to capture our env variable "IMPORTER_OAUTH2_SCOPES"
+	ImporterOAuth2Scopes = "IMPORTER_OAUTH2_SCOPES"
+	// ImporterS3Region provides a constant to capture our env variable "IMPORTER_S3_REGION"
+	ImporterS3Region = "IMPORTER_S3_REGION"
+	// ImporterS3ForcePathStyle provides a constant to capture our env variable "IMPORTER_S3_FORCE_PATH_STYLE"
+	ImporterS3ForcePathStyle = "IMPORTER_S3_FORCE_PATH_STYLE"
+	// ImporterS3SSECustomerKey provides a constant to capture our env variable "IMPORTER_S3_SSE_CUSTOMER_KEY"
+	ImporterS3SSECustomerKey = "IMPORTER_S3_SSE_CUSTOMER_KEY"
+	// ImporterS3ObjectVersionID provides a constant to capture our env variable "IMPORTER_S3_OBJECT_VERSION_ID"
+	ImporterS3ObjectVersionID = "IMPORTER_S3_OBJECT_VERSION_ID"
+	// ImporterGCSServiceAccountKey provides a constant to capture our env variable "IMPORTER_GCS_SERVICE_ACCOUNT_KEY"
+	ImporterGCSServiceAccountKey = "IMPORTER_GCS_SERVICE_ACCOUNT_KEY"
+	// ImporterGlanceProject provides a constant to capture our env variable "IMPORTER_GLANCE_PROJECT"
+	ImporterGlanceProject = "IMPORTER_GLANCE_PROJECT"
+	// ImporterGlanceDomain provides a constant to capture our env variable "IMPORTER_GLANCE_DOMAIN"
+	ImporterGlanceDomain = "IMPORTER_GLANCE_DOMAIN"
+	// ImporterGlanceRegion provides a constant to capture our env variable "IMPORTER_GLANCE_REGION"
+	ImporterGlanceRegion = "IMPORTER_GLANCE_REGION"
+	// ImporterProxmoxNode provides a constant to capture our env variable "IMPORTER_PROXMOX_NODE"
+	ImporterProxmoxNode = "IMPORTER_PROXMOX_NODE"
+	// ImporterProxmoxVMID provides a constant to capture our env variable "IMPORTER_PROXMOX_VMID"
+	ImporterProxmoxVMID = "IMPORTER_PROXMOX_VMID"
+	// ImporterFTPPassive provides a constant to capture our env variable "IMPORTER_FTP_PASSIVE"
+	ImporterFTPPassive = "IMPORTER_FTP_PASSIVE"
+	// ImporterSMBDomain provides a constant to capture our env variable "IMPORTER_SMB_DOMAIN"
+	ImporterSMBDomain = "IMPORTER_SMB_DOMAIN"
+	// ImporterRsyncModule provides a constant to capture our env variable "IMPORTER_RSYNC_MODULE"
+	ImporterRsyncModule = "IMPORTER_RSYNC_MODULE"
+	// ImporterRsyncHostKey provides a constant to capture our env variable "IMPORTER_RSYNC_HOST_KEY"
+	ImporterRsyncHostKey = "IMPORTER_RSYNC_HOST_KEY"
+	// ImporterISCSIInitiator provides a constant to capture our env variable "IMPORTER_ISCSI_INITIATOR"
+	ImporterISCSIInitiator = "IMPORTER_ISCSI_INITIATOR"
+	// ImporterRBDMonitors provides a constant to capture our env variable "IMPORTER_RBD_MONITORS"
+	ImporterRBDMonitors = "IMPORTER_RBD_MONITORS"
+	// ImporterLibvirtDomain provides a constant to capture our env variable "IMPORTER_LIBVIRT_DOMAIN"
+	ImporterLibvirtDomain = "IMPORTER_LIBVIRT_DOMAIN"
+	// ImporterLibvirtDisk provides a constant to capture our env variable "IMPORTER_LIBVIRT_DISK"
+	ImporterLibvirtDisk = "IMPORTER_LIBVIRT_DISK"
+	// ImporterLibvirtHostKey provides a constant to capture our env variable "IMPORTER_LIBVIRT_HOST_KEY"
+	ImporterLibvirtHostKey = "IMPORTER_LIBVIRT_HOST_KEY"
+	// ImporterRegistriesConfig provides a constant to capture our env variable "IMPORTER_REGISTRIES_CONFIG"
+	ImporterRegistriesConfig = "IMPORTER_REGISTRIES_CONFIG"
+	// ImporterRegistryPlatform provides a constant to capture our env variable "IMPORTER_REGISTRY_PLATFORM"
+	ImporterRegistryPlatform = "IMPORTER_REGISTRY_PLATFORM"
+	// ImageSignatureNotVerified is inserted into the importer's exit message when no signature of a registry image verifies
+	ImageSignatureNotVerified = "Image signature not verified"
+	// ImporterSignatureDirVar provides a constant to capture our env variable "IMPORTER_SIGNATURE_DIR"
+	ImporterSignatureDirVar = "IMPORTER_SIGNATURE_DIR"
+	// ImporterSignatureIdentity provides a constant to capture our env variable "IMPORTER_SIGNATURE_IDENTITY"
+	ImporterSignatureIdentity = "IMPORTER_SIGNATURE_IDENTITY"
+	// ImporterSignatureIssuer provides a constant to capture our env variable "IMPORTER_SIGNATURE_ISSUER"
+	ImporterSignatureIssuer = "IMPORTER_SIGNATURE_ISSUER"
+	// ImporterAzureSASToken provides a constant to capture our env variable "IMPORTER_AZURE_SAS_TOKEN"
+	ImporterAzureSASToken = "IMPORTER_AZURE_SAS_TOKEN"
+	// ImporterAzureAccountKey provides a constant to capture our env variable "IMPORTER_AZURE_ACCOUNT_KEY"
+	ImporterAzureAccountKey = "IMPORTER_AZURE_ACCOUNT_KEY"
+	// ImporterAzureTenantID provides a constant to capture our env variable "IMPORTER_AZURE_TENANT_ID"
+	ImporterAzureTenantID = "IMPORTER_AZURE_TENANT_ID"
+	// ImporterAzureClientID provides a constant to capture our env variable "IMPORTER_AZURE_CLIENT_ID"
+	ImporterAzureClientID = "IMPORTER_AZURE_CLIENT_ID"
+	// ImporterAzureClientSecret provides a constant to capture our env variable "IMPORTER_AZURE_CLIENT_SECRET"
+	ImporterAzureClientSecret = "IMPORTER_AZURE_CLIENT_SECRET"
+	// ImporterFeatureGates provides a constant to capture our env variable "IMPORTER_FEATURE_GATES"
+	ImporterFeatureGates = "IMPORTER_FEATURE_GATES"
+	// ImporterProbe provides a constant to capture our env variable "IMPORTER_PROBE", the importer only reports the
+	// information of the source when it is true
+	ImporterProbe = "IMPORTER_PROBE"
+	// ImporterPoll provides a constant to capture our env variable "IMPORTER_POLL", the importer only reports the
+	// version of the source of a DataImportCron when it is true
+	ImporterPoll = "IMPORTER_POLL"
+	// ImporterNotification provides a constant to capture our env variable "IMPORTER_NOTIFICATION", the importer only
+	// posts its JSON value to the endpoint, the webhook of a DataImportCron, when it is set
+	ImporterNotification = "IMPORTER_NOTIFICATION"
 	// ImporterImageSize provides a constant to capture our env variable "IMPORTER_IMAGE_SIZE"
 	ImporterImageSize = "IMPORTER_IMAGE_SIZE"
 	// ImporterCertDirVar provides a constant to capture our env variable "IMPORTER_CERT_DIR"
 	ImporterCertDirVar = "IMPORTER_CERT_DIR"
+	// ImporterClientCertDirVar provides a constant to capture our env variable "IMPORTER_CLIENT_CERT_DIR"
+	ImporterClientCertDirVar = "IMPORTER_CLIENT_CERT_DIR"
+	// ImporterTrustedCADirVar provides a constant to capture our env variable "IMPORTER_TRUSTED_CA_DIR"
+	ImporterTrustedCADirVar = "IMPORTER_TRUSTED_CA_DIR"
 	// InsecureTLSVar provides a constant to capture our env variable "INSECURE_TLS"
 	InsecureTLSVar = "INSECURE_TLS"
 	// ImporterDiskID provides a constant to capture our env variable "IMPORTER_DISK_ID"
@@ -85,6 +184,16 @@ const (
 	ImporterFinalCheckpoint = "IMPORTER_FINAL_CHECKPOINT"
 	// Preallocation provides a constant to capture out env variable "PREALLOCATION"
 	Preallocation = "PREALLOCATION"
+	// ImporterHTTPSegments provides a constant to capture our env variable "IMPORTER_HTTP_SEGMENTS", used by http and s3 imports, and as the NBD connections of vddk imports
+	ImporterHTTPSegments = "IMPORTER_HTTP_SEGMENTS"
+	// ImporterHTTPSegmentSize provides a constant to capture our env variable "IMPORTER_HTTP_SEGMENT_SIZE", used by http and s3 imports
+	ImporterHTTPSegmentSize = "IMPORTER_HTTP_SEGMENT_SIZE"
+	// ImporterSourceETag provides a constant to capture our env variable "IMPORTER_SOURCE_ETAG"
+	ImporterSourceETag = "IMPORTER_SOURCE_ETAG"
+	// ImporterSourceLastModified provides a constant to capture our env variable "IMPORTER_SOURCE_LAST_MODIFIED"
+	ImporterSourceLastModified = "IMPORTER_SOURCE_LAST_MODIFIED"
+	// ImporterSourceVersionID provides a constant to capture our env variable "IMPORTER_SOURCE_VERSION_ID"
+	ImporterSourceVersionID = "IMPORTER_SOURCE_VERSION_ID"
 
 	// CloningLabelValue provides a constant to use as a label value for pod affinity (controller pkg only)
 	CloningLabelValue = "host-assisted-cloning"
@@ -115,15 +224,53 @@ const (
 	UploadServerServiceLabel = "service"
 	// UploadImageSize provides a constant to capture our env variable "UPLOAD_IMAGE_SIZE"
 	UploadImageSize = "UPLOAD_IMAGE_SIZE"
+	// DownloadSource provides a constant to capture our env variable "DOWNLOAD_SOURCE", the disk image file or block device served by download servers
+	DownloadSource = "DOWNLOAD_SOURCE"
 
 	// FilesystemOverheadVar provides a constant to capture our env variable "FILESYSTEM_OVERHEAD"
 	FilesystemOverheadVar = "FILESYSTEM_OVERHEAD"
 	// DefaultGlobalOverhead is the amount of space reserved on Filesystem volumes by default
 	DefaultGlobalOverhead = "0.055"
 
+	// TLSMinVersionVar provides a constant to capture our env variable "TLS_MIN_VERSION"
+	TLSMinVersionVar = "TLS_MIN_VERSION"
+	// TLSCiphersVar provides a constant to capture our env variable "TLS_CIPHERS", the cipher suites are separated by commas
+	TLSCiphersVar = "TLS_CIPHERS"
+
 	// ConfigName is the name of default CDI Config
 	ConfigName = "config"
 
+	// ExporterPodName provides a constant to use as a prefix for the export pods created by CDI (controller only)
+	ExporterPodName = "exporter"
+	// ExporterSourcePath provides a constant to capture our env variable "EXPORTER_SOURCE_PATH", the disk image file or block device to export
+	ExporterSourcePath = "EXPORTER_SOURCE_PATH"
+	// ExporterFormat provides a constant to capture our env variable "EXPORTER_FORMAT"
+	ExporterFormat = "EXPORTER_FORMAT"
+	// ExporterCompression provides a constant to capture our env variable "EXPORTER_COMPRESSION"
+	ExporterCompression = "EXPORTER_COMPRESSION"
+	// ExporterTarget provides a constant to capture our env variable "EXPORTER_TARGET", the type of target written to
+	ExporterTarget = "EXPORTER_TARGET"
+	// ExporterEndpoint provides a constant to capture our env variable "EXPORTER_ENDPOINT"
+	ExporterEndpoint = "EXPORTER_ENDPOINT"
+	// ExporterAccessKeyID provides a constant to capture our env variable "EXPORTER_ACCESS_KEY_ID"
+	ExporterAccessKeyID = "EXPORTER_ACCESS_KEY_ID"
+	// ExporterSecretKey provides a constant to capture our env variable "EXPORTER_SECRET_KEY"
+	ExporterSecretKey = "EXPORTER_SECRET_KEY"
+	// ExporterS3Region provides a constant to capture our env variable "EXPORTER_S3_REGION"
+	ExporterS3Region = "EXPORTER_S3_REGION"
+	// ExporterS3PartSize provides a constant to capture our env variable "EXPORTER_S3_PART_SIZE"
+	ExporterS3PartSize = "EXPORTER_S3_PART_SIZE"
+	// ExporterCertDirVar provides a constant to capture our env variable "EXPORTER_CERT_DIR"
+	ExporterCertDirVar = "EXPORTER_CERT_DIR"
+	// ExporterAuthFile provides a constant to capture our env variable "EXPORTER_AUTH_FILE", the docker config json file of a registry target
+	ExporterAuthFile = "EXPORTER_AUTH_FILE"
+	// ExporterTokenFile provides a constant to capture our env variable "EXPORTER_TOKEN_FILE", the upload token file of a cdi target
+	ExporterTokenFile = "EXPORTER_TOKEN_FILE"
+	// ExporterAuthDir is where the docker config secret of a registry target, or the token secret of a cdi target, is mounted
+	ExporterAuthDir = "/auth"
+	// ExportComplete is the termination message of a successful export pod
+	ExportComplete = "Export Complete"
+
 	// OwnerUID provides the UID of the owner entity (either PVC or DV)
 	OwnerUID = "OWNER_UID"
 
@@ -131,6 +278,22 @@ const (
 	KeyAccess = "accessKeyId"
 	// KeySecret provides a constant to the secretKey label using in controller pkg and transport_test.go
 	KeySecret = "secretKey"
+	// KeyToken provides a constant to the bearer token label used in controller pkg
+	KeyToken = "token"
+	// KeyClientID provides a constant to the OAuth2 and Azure service principal client id label used in controller pkg
+	KeyClientID = "clientId"
+	// KeyClientSecret provides a constant to the OAuth2 and Azure service principal client secret label used in controller pkg
+	KeyClientSecret = "clientSecret"
+	// KeySSECustomerKey provides a constant to the S3 SSE-C customer key label used in controller pkg
+	KeySSECustomerKey = "sseCustomerKey"
+	// KeyServiceAccountKey provides a constant to the GCS service account key label used in controller pkg
+	KeyServiceAccountKey = "serviceAccountKey"
+	// KeySASToken provides a constant to the Azure SAS token label used in controller pkg
+	KeySASToken = "sasToken"
+	// KeyAccountKey provides a constant to the Azure storage account key label used in controller pkg
+	KeyAccountKey = "accountKey"
+	// KeyTenantID provides a constant to the Azure service principal tenant label used in controller pkg
+	KeyTenantID = "tenantId"
 
 	// DefaultResyncPeriod sets a 10 minute resync period, used in the controller pkg and the controller cmd executable
 	DefaultResyncPeriod = 10 * time.Minute
@@ -152,6 +315,9 @@ const (
 	// QemuSubGid is the gid used as the qemu group in fsGroup
 	QemuSubGid = int64(107)
 
+	// QemuSubUID is the uid of the qemu user the importer, upload server and clone source pods run as
+	QemuSubUID = int64(107)
+
 	// ControllerServiceAccountName is the name of the CDI controller service account
 	ControllerServiceAccountName = "cdi-sa"
 
@@ -163,6 +329,24 @@ const (
 	// UploadContentTypeHeader is the header upload clients may use to set the content type explicitly
 	UploadContentTypeHeader = "x-cdi-content-type"
 
+	// UploadContentEncodingHeader is the header the clone source uses to set the compression of the clone stream
+	UploadContentEncodingHeader = "x-cdi-content-encoding"
+
+	// UploadContentSHA256Trailer is the trailer the clone source uses to send the sha256 checksum of the clone stream
+	UploadContentSHA256Trailer = "x-cdi-content-sha256"
+
+	// CloneChecksumMismatchMessage is the message of the clone stream verification failures
+	CloneChecksumMismatchMessage = "clone stream checksum mismatch"
+
+	// CloneCompressionVar is the environment variable setting the compression of the clone stream in the clone source
+	CloneCompressionVar = "CLONE_COMPRESSION"
+
+	// CloneCompressionSnappy is the default compression of the clone stream
+	CloneCompressionSnappy = "snappy"
+
+	// CloneCompressionZstd is the zstd compression of the clone stream
+	CloneCompressionZstd = "zstd"
+
 	// FilesystemCloneContentType is the content type when cloning a filesystem
 	FilesystemCloneContentType = "filesystem-clone"
 
@@ -180,6 +364,40 @@ const (
 
 	// UploadFormAsync is the path to POST CDI uploads as form data in async mode
 	UploadFormAsync = "/v1beta1/upload-form-async"
+
+	// UploadPathTus is the path to create CDI uploads with the tus resumable upload protocol
+	UploadPathTus = "/v1beta1/upload-tus"
+	// TusUploadID is the id of the upload resource created by tus uploads, there is a single one per upload server
+	TusUploadID = "disk"
+	// TusVersion is the supported version of the tus resumable upload protocol
+	TusVersion = "1.0.0"
+	// TusResumableHeader is the header of the tus protocol version of requests and responses
+	TusResumableHeader = "Tus-Resumable"
+	// TusUploadOffsetHeader is the header of the offset of tus uploads
+	TusUploadOffsetHeader = "Upload-Offset"
+	// TusUploadLengthHeader is the header of the total length of tus uploads
+	TusUploadLengthHeader = "Upload-Length"
+
+	// UploadPathChunked is the path of CDI uploads sent in chunks
+	UploadPathChunked = "/v1beta1/upload-chunked"
+	// ChunkedUploadComplete is the path, relative to UploadPathChunked, to POST once all chunks are uploaded
+	ChunkedUploadComplete = "complete"
+	// UploadLengthHeader is the header of the total length of chunked uploads
+	UploadLengthHeader = "x-cdi-upload-length"
+	// UploadChunkSizeHeader is the header of the size of the chunks of chunked uploads
+	UploadChunkSizeHeader = "x-cdi-chunk-size"
+	// UploadChunkChecksumHeader is the header of the hex encoded sha256 checksum of a chunk
+	UploadChunkChecksumHeader = "x-cdi-chunk-sha256"
+
+	// UploadPathWebSocket is the path to stream CDI uploads through a websocket, the upload proxy forwards them to
+	// the upload server as async uploads
+	UploadPathWebSocket = "/v1beta1/upload-websocket"
+
+	// UploadStatusPath is the path to GET the progress of CDI uploads
+	UploadStatusPath = "/v1beta1/upload-status"
+
+	// DownloadPath is the path to GET the disk of a PVC, the format query parameter is raw (the default) or qcow2
+	DownloadPath = "/v1beta1/download"
 )
 
 // PreallocationStatus is used to mark result of preallocation in importer and uploader
@@ -205,10 +423,22 @@ func PreallocationStatusFromBool(preallocation bool) PreallocationStatus {
 
 // ProxyPaths are all supported paths
 var ProxyPaths = append(
-	append(SyncUploadPaths, AsyncUploadPaths...),
+	append(append(append(SyncUploadPaths, AsyncUploadPaths...), TusUploadPaths...), ChunkedUploadPaths...),
 	append(SyncUploadFormPaths, AsyncUploadFormPaths...)...,
 )
 
+// ChunkedUploadPaths are the paths of chunked uploads, the upload path and the paths of its chunks
+var ChunkedUploadPaths = []string{
+	UploadPathChunked,
+	UploadPathChunked + "/",
+}
+
+// TusUploadPaths are the paths of tus resumable uploads, the creation path and the path of the upload resources
+var TusUploadPaths = []string{
+	UploadPathTus,
+	UploadPathTus + "/",
+}
+
 // SyncUploadPaths are paths to POST CDI uploads
 var SyncUploadPaths = []string{
 	UploadPathSync,
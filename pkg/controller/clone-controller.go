@@ -33,6 +33,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -49,6 +50,8 @@ const (
 	CloneUniqueID = "cdi.kubevirt.io/storage.clone.cloneUniqeId"
 	// AnnCloneSourcePod name of the source clone pod
 	AnnCloneSourcePod = "cdi.kubevirt.io/storage.sourceClonePodName"
+	// AnnCloneCompression is the compression of the host assisted clone stream, snappy unless set to zstd
+	AnnCloneCompression = "cdi.kubevirt.io/storage.clone.compression"
 
 	// ErrIncompatiblePVC provides a const to indicate a clone is not possible due to an incompatible PVC
 	ErrIncompatiblePVC = "ErrIncompatiblePVC"
@@ -65,6 +68,9 @@ const (
 	// CloneSourceInUse is reason for event created when clone source pvc is in use
 	CloneSourceInUse = "CloneSourceInUse"
 
+	// CloneChecksumMismatch provides a const to indicate the target received a clone stream not matching the source
+	CloneChecksumMismatch = "CloneChecksumMismatch"
+
 	cloneSourcePodFinalizer = "cdi.kubevirt.io/cloneSource"
 
 	cloneTokenLeeway = 10 * time.Second
@@ -77,6 +83,7 @@ const (
 // CloneReconciler members
 type CloneReconciler struct {
 	client              client.Client
+	uncachedClient      client.Client
 	scheme              *runtime.Scheme
 	recorder            record.EventRecorder
 	clientCertGenerator generator.CertGenerator
@@ -96,8 +103,16 @@ func NewCloneController(mgr manager.Manager,
 	clientCertGenerator generator.CertGenerator,
 	serverCAFetcher fetcher.CertBundleFetcher,
 	apiServerKey *rsa.PublicKey) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
 	reconciler := &CloneReconciler{
 		client:              mgr.GetClient(),
+		uncachedClient:      uncachedClient,
 		scheme:              mgr.GetScheme(),
 		log:                 log.WithName("clone-controller"),
 		tokenValidator:      newCloneTokenValidator(apiServerKey),
@@ -278,6 +293,12 @@ func (r *CloneReconciler) reconcileSourcePod(sourcePod *corev1.Pod, targetPvc *c
 			return false, err
 		}
 		log.V(3).Info("Created source pod ", "sourcePod.Namespace", sourcePod.Namespace, "sourcePod.Name", sourcePod.Name)
+
+		if _, ok := targetPvc.Annotations[AnnCloneGrant]; ok {
+			if err := r.recordCloneGrantUse(sourcePvc, targetPvc); err != nil {
+				return false, err
+			}
+		}
 	}
 	return false, nil
 }
@@ -301,7 +322,15 @@ func (r *CloneReconciler) updatePvcFromPod(sourcePod *corev1.Pod, pvc *corev1.Pe
 		if podRestarts > annPodRestarts {
 			pvc.Annotations[AnnPodRestarts] = strconv.Itoa(podRestarts)
 		}
+		previousReason := pvc.Annotations[AnnSourceRunningConditionReason]
 		setConditionFromPodWithPrefix(pvc.Annotations, AnnSourceRunningCondition, sourcePod)
+		if message, mismatch := cloneChecksumMismatch(sourcePod); mismatch {
+			pvc.Annotations[AnnSourceRunningConditionMessage] = message
+			pvc.Annotations[AnnSourceRunningConditionReason] = CloneChecksumMismatch
+			if previousReason != CloneChecksumMismatch {
+				r.recorder.Event(pvc, corev1.EventTypeWarning, CloneChecksumMismatch, message)
+			}
+		}
 	}
 
 	if !reflect.DeepEqual(currentPvcCopy, pvc) {
@@ -310,6 +339,20 @@ func (r *CloneReconciler) updatePvcFromPod(sourcePod *corev1.Pod, pvc *corev1.Pe
 	return nil
 }
 
+// cloneChecksumMismatch returns the termination message of the source pod when its last run failed as the stream
+// received by the target did not match the checksum of the source, the pod is restarted to copy it again
+func cloneChecksumMismatch(sourcePod *corev1.Pod) (string, bool) {
+	status := sourcePod.Status.ContainerStatuses[0]
+	terminated := status.State.Terminated
+	if terminated == nil && status.State.Running == nil {
+		terminated = status.LastTerminationState.Terminated
+	}
+	if terminated == nil || !strings.Contains(terminated.Message, common.CloneChecksumMismatchMessage) {
+		return "", false
+	}
+	return terminated.Message, true
+}
+
 func (r *CloneReconciler) updatePVC(pvc *corev1.PersistentVolumeClaim) error {
 	if err := r.client.Update(context.TODO(), pvc); err != nil {
 		return err
@@ -369,7 +412,14 @@ func (r *CloneReconciler) findCloneSourcePod(pvc *corev1.PersistentVolumeClaim)
 }
 
 func (r *CloneReconciler) validateSourceAndTarget(sourcePvc, targetPvc *corev1.PersistentVolumeClaim) error {
-	if err := validateCloneToken(r.tokenValidator, sourcePvc, targetPvc); err != nil {
+	if _, ok := targetPvc.Annotations[AnnCloneGrant]; ok {
+		if _, err := r.validateCloneGrant(sourcePvc, targetPvc); err != nil {
+			return err
+		}
+	} else if isPopulatorClone(sourcePvc, targetPvc) {
+		// The prime PVCs of the PVCs populated by a VolumeCloneSource clone the PVCs of their own namespace
+		r.log.V(3).Info("Cloning for a VolumeCloneSource", "target.Name", targetPvc.Name)
+	} else if err := validateCloneToken(r.tokenValidator, sourcePvc, targetPvc); err != nil {
 		return err
 	}
 	contentType, err := ValidateCanCloneSourceAndTargetContentType(sourcePvc, targetPvc)
@@ -453,9 +503,25 @@ func (r *CloneReconciler) cleanup(pvc *corev1.PersistentVolumeClaim, log logr.Lo
 		}
 	}
 
+	if err := r.deleteSnapshotRestorePvc(pvc); err != nil {
+		return err
+	}
+
 	return r.updatePVC(r.removeFinalizer(pvc, cloneSourcePodFinalizer))
 }
 
+// deleteSnapshotRestorePvc deletes the source of the copy of a VolumeSnapshot to the PVC, the PVC restored from it
+func (r *CloneReconciler) deleteSnapshotRestorePvc(pvc *corev1.PersistentVolumeClaim) error {
+	sourcePvc, err := r.getCloneRequestSourcePVC(pvc)
+	if err != nil || !isSnapshotRestoreFor(sourcePvc, pvc) {
+		return nil
+	}
+	if err := r.client.Delete(context.TODO(), sourcePvc); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "error deleting the PVC restored from the snapshot")
+	}
+	return nil
+}
+
 // CreateCloneSourcePod creates our cloning src pod which will be used for out of band cloning to read the contents of the src PVC
 func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy, clientName string, pvc *corev1.PersistentVolumeClaim, log logr.Logger) (*corev1.Pod, error) {
 	exists, sourcePvcNamespace, sourcePvcName := ParseCloneRequestAnnotation(pvc)
@@ -478,12 +544,22 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy, clientName str
 		return nil, err
 	}
 
-	podResourceRequirements, err := GetDefaultPodResourceRequirements(r.client)
+	podResourceRequirements, err := GetPodResourceRequirements(r.client, pvc)
 	if err != nil {
 		return nil, err
 	}
 
-	workloadNodePlacement, err := GetWorkloadNodePlacement(r.client)
+	workloadNodePlacement, err := GetPodNodePlacement(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityClassName, err := GetPriorityClassName(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := GetTLSConfig(r.client)
 	if err != nil {
 		return nil, err
 	}
@@ -500,7 +576,31 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy, clientName str
 		sourceVolumeMode = corev1.PersistentVolumeFilesystem
 	}
 
-	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, sourcePvcName, sourcePvcNamespace, ownerKey, clientKey, clientCert, serverCABundle, pvc, podResourceRequirements, workloadNodePlacement)
+	podSecurity, err := GetPodSecurityConfig(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePullSecrets, err := GetImagePullSecrets(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedCAConfigMap, err := SyncTrustedCA(r.uncachedClient, sourcePvcNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, sourcePvcName, sourcePvcNamespace, ownerKey, clientKey, clientCert, serverCABundle, pvc, podResourceRequirements, workloadNodePlacement, priorityClassName, tlsConfig)
+	if trustedCAConfigMap != "" {
+		addTrustedCAVolume(pod, trustedCAConfigMap)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		})
+	}
+	SetRestrictedSecurityContext(pod, podSecurity)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
 
 	if err := r.client.Create(context.TODO(), pod); err != nil {
 		return nil, errors.Wrap(err, "source pod API create errored")
@@ -518,7 +618,7 @@ func createCloneSourcePodName(targetPvc *corev1.PersistentVolumeClaim) string {
 // MakeCloneSourcePodSpec creates and returns the clone source pod spec based on the target pvc.
 func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image, pullPolicy, sourcePvcName, sourcePvcNamespace, ownerRefAnno string,
 	clientKey, clientCert, serverCACert []byte, targetPvc *corev1.PersistentVolumeClaim, resourceRequirements *corev1.ResourceRequirements,
-	workloadNodePlacement *sdkapi.NodePlacement) *corev1.Pod {
+	workloadNodePlacement *sdkapi.NodePlacement, priorityClassName string, tlsConfig *cdiv1.TLSConfig) *corev1.Pod {
 
 	var ownerID string
 	cloneSourcePodName, _ := targetPvc.Annotations[AnnCloneSourcePod]
@@ -545,15 +645,6 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 			},
 		},
 		Spec: corev1.PodSpec{
-			SecurityContext: &corev1.PodSecurityContext{
-				RunAsUser: &[]int64{0}[0],
-				SELinuxOptions: &corev1.SELinuxOptions{
-					User:  "system_u",
-					Role:  "system_r",
-					Type:  "spc_t",
-					Level: "s0",
-				},
-			},
 			Containers: []corev1.Container{
 				{
 					Name:            common.ClonerSourcePodName,
@@ -606,9 +697,10 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 					},
 				},
 			},
-			NodeSelector: workloadNodePlacement.NodeSelector,
-			Tolerations:  workloadNodePlacement.Tolerations,
-			Affinity:     workloadNodePlacement.Affinity,
+			NodeSelector:      workloadNodePlacement.NodeSelector,
+			Tolerations:       workloadNodePlacement.Tolerations,
+			Affinity:          workloadNodePlacement.Affinity,
+			PriorityClassName: priorityClassName,
 		},
 	}
 
@@ -677,6 +769,14 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 		}
 	}
 
+	if targetPvc.Annotations[AnnCloneCompression] == common.CloneCompressionZstd {
+		addVars = append(addVars, corev1.EnvVar{
+			Name:  common.CloneCompressionVar,
+			Value: common.CloneCompressionZstd,
+		})
+	}
+
+	addVars = append(addVars, tlsconfig.EnvVars(tlsConfig)...)
 	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, addVars...)
 	SetPodPvcAnnotations(pod, targetPvc)
 	return pod
@@ -693,10 +793,17 @@ func validateCloneToken(validator token.Validator, source, target *corev1.Persis
 		return errors.Wrap(err, "error verifying token")
 	}
 
+	sourceName := source.Name
+	if tokenData.Resource.Resource == "volumesnapshots" && isSnapshotRestoreFor(source, target) {
+		// Copies of a VolumeSnapshot read the PVC restored from it for the target
+		sourceName = source.Spec.DataSource.Name
+	} else if tokenData.Resource.Resource != "persistentvolumeclaims" {
+		return errors.New("invalid token")
+	}
+
 	if tokenData.Operation != token.OperationClone ||
-		tokenData.Name != source.Name ||
+		tokenData.Name != sourceName ||
 		tokenData.Namespace != source.Namespace ||
-		tokenData.Resource.Resource != "persistentvolumeclaims" ||
 		tokenData.Params["targetNamespace"] != target.Namespace ||
 		tokenData.Params["targetName"] != target.Name {
 		return errors.New("invalid token")
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -43,8 +43,10 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 )
 
 var (
@@ -149,6 +151,49 @@ var _ = Describe("Clone controller reconcile loop", func() {
 		Expect(reconciler.hasFinalizer(testPvc, cloneSourcePodFinalizer)).To(BeTrue())
 	})
 
+	It("Should mount the trusted CA bundle in the source pod", func() {
+		testPvc := createPvc("testPvc1", "default", map[string]string{
+			AnnCloneRequest:     "default/source",
+			AnnPodReady:         "true",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}, nil)
+		reconciler = createCloneReconciler(testPvc, createPvc("source", "default", map[string]string{}, nil), createTrustedCAConfigMap(util.GetNamespace(), "bundle", nil))
+		sourcePod, err := reconciler.CreateCloneSourcePod(testImage, testPullPolicy, "uploadclient", testPvc, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      TrustedCAVolName,
+			MountPath: common.ImporterTrustedCADir,
+			ReadOnly:  true,
+		}))
+		Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		}))
+	})
+
+	It("Should run the source pod with the SELinux options of the CDIConfig", func() {
+		testPvc := createPvc("testPvc1", "default", map[string]string{
+			AnnCloneRequest:     "default/source",
+			AnnPodReady:         "true",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-testPvc1-source-pod"}, nil)
+		reconciler = createCloneReconciler(testPvc, createPvc("source", "default", map[string]string{}, nil))
+		sourcePod, err := reconciler.CreateCloneSourcePod(testImage, testPullPolicy, "uploadclient", testPvc, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod.Spec.SecurityContext.SELinuxOptions).To(BeNil())
+		Expect(*sourcePod.Spec.SecurityContext.RunAsUser).To(Equal(common.QemuSubUID))
+		Expect(*sourcePod.Spec.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+
+		config := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config)).To(Succeed())
+		config.Spec.PodSecurity = &cdiv1.PodSecurityConfig{SELinuxOptions: &corev1.SELinuxOptions{Type: "container_t", Level: "s0:c1,c2"}}
+		Expect(reconciler.client.Update(context.TODO(), config)).To(Succeed())
+		Expect(reconciler.client.Delete(context.TODO(), sourcePod)).To(Succeed())
+		sourcePod, err = reconciler.CreateCloneSourcePod(testImage, testPullPolicy, "uploadclient", testPvc, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod.Spec.SecurityContext.SELinuxOptions).To(Equal(config.Spec.PodSecurity.SELinuxOptions))
+	})
+
 	DescribeTable("Should NOT create new source pod if source PVC is in use", func(podFunc func(*corev1.PersistentVolumeClaim) *corev1.Pod) {
 		testPvc := createPvc("testPvc1", "default", map[string]string{
 			AnnCloneRequest:     "default/source",
@@ -259,6 +304,90 @@ var _ = Describe("Clone controller reconcile loop", func() {
 		}),
 	)
 
+	DescribeTable("Should validate the CloneGrant of a clone without token and", func(modifyGrant func(*cdiv1.CloneGrant), expectedError string) {
+		testPvc := createPvc("testPvc1", "target", map[string]string{
+			AnnCloneRequest:     "source/source",
+			AnnPodReady:         "true",
+			AnnCloneGrant:       "grant",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "target-testPvc1-source-pod"}, nil)
+		sourcePvc := createPvc("source", "source", map[string]string{}, map[string]string{"app": "golden"})
+		grant := &cdiv1.CloneGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "grant",
+				Namespace: "source",
+			},
+			Spec: cdiv1.CloneGrantSpec{
+				TargetNamespace: "target",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "golden"},
+				},
+				ExpirationTime: &metav1.Time{Time: time.Now().Add(time.Hour)},
+			},
+		}
+		modifyGrant(grant)
+		reconciler = createCloneReconciler(testPvc, sourcePvc, grant)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "target"}})
+		sourcePod, podErr := reconciler.findCloneSourcePod(testPvc)
+		Expect(podErr).ToNot(HaveOccurred())
+		if expectedError != "" {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(expectedError))
+			Expect(sourcePod).To(BeNil())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		By("Verifying the use of the grant is recorded")
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "grant", Namespace: "source"}, grant)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(grant.Status.CloneCount).To(Equal(int64(1)))
+		Expect(grant.Status.LastCloneTime).ToNot(BeNil())
+		Expect(grant.Status.LastSource).To(Equal("source"))
+		Expect(grant.Status.LastTarget).To(Equal("target/testPvc1"))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(CloneGrantUsed))
+	},
+		Entry("create the source pod when it allows the clone", func(grant *cdiv1.CloneGrant) {}, ""),
+		Entry("fail when it expired", func(grant *cdiv1.CloneGrant) {
+			grant.Spec.ExpirationTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		}, "expired"),
+		Entry("fail when it allows another namespace", func(grant *cdiv1.CloneGrant) {
+			grant.Spec.TargetNamespace = "other"
+		}, "does not allow namespace target"),
+		Entry("fail when it does not select the source", func(grant *cdiv1.CloneGrant) {
+			grant.Spec.Selector.MatchLabels["app"] = "other"
+		}, "does not select PVC source"),
+		Entry("fail when it does not exist", func(grant *cdiv1.CloneGrant) {
+			grant.Name = "other"
+		}, "error getting clone grant"),
+	)
+
+	It("Should clone without token into the prime PVC of a PVC populated by a VolumeCloneSource", func() {
+		pvc := createPvc("testPvc1", "default", nil, nil)
+		testPvc := createPvc(GetPrimePvcName(pvc), "default", map[string]string{
+			AnnCloneRequest:     "default/source",
+			AnnPodReady:         "true",
+			AnnUploadClientName: "uploadclient",
+			AnnCloneSourcePod:   "default-prime-source-pod"}, nil)
+		testPvc.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(pvc, corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")),
+		}
+		reconciler = createCloneReconciler(testPvc, createPvc("source", "default", map[string]string{}, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testPvc.Name, Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+
+		By("Requiring a token when the target is not a prime PVC")
+		testPvc.OwnerReferences = nil
+		reconciler = createCloneReconciler(testPvc, createPvc("source", "default", map[string]string{}, nil))
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testPvc.Name, Namespace: "default"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("clone token missing"))
+	})
+
 	It("Should error with missing upload client name annotation if none provided", func() {
 		testPvc := createPvc("testPvc1", "default", map[string]string{
 			AnnCloneRequest: "default/source", AnnPodReady: "true", AnnCloneToken: "foobaz", AnnCloneSourcePod: "default-testPvc1-source-pod"}, nil)
@@ -573,6 +702,65 @@ var _ = Describe("Update PVC", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(actualPvc.Annotations[AnnPodRestarts]).To(Equal("3"))
 	})
+
+	DescribeTable("Should surface a checksum mismatch of the source pod", func(state, lastState corev1.ContainerState) {
+		testPvc := createPvc("testPvc1", "default", map[string]string{
+			AnnCloneRequest: "default/test"},
+			nil)
+		pod := createSourcePod(testPvc, string(testPvc.GetUID()))
+		pod.Status = corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					RestartCount:         1,
+					State:                state,
+					LastTerminationState: lastState,
+				},
+			},
+		}
+		reconciler = createCloneReconciler(testPvc, createPvc("source", "default", map[string]string{}, nil))
+
+		err := reconciler.updatePvcFromPod(pod, testPvc, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+
+		actualPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, actualPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actualPvc.Annotations[AnnSourceRunningCondition]).To(Equal("false"))
+		Expect(actualPvc.Annotations[AnnSourceRunningConditionReason]).To(Equal(CloneChecksumMismatch))
+		Expect(actualPvc.Annotations[AnnSourceRunningConditionMessage]).To(ContainSubstring(common.CloneChecksumMismatchMessage))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(CloneChecksumMismatch))
+	},
+		Entry("when terminated", corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 255, Reason: "Error", Message: "Unexpected status code 500: Saving stream failed: " + common.CloneChecksumMismatchMessage},
+		}, corev1.ContainerState{}),
+		Entry("when waiting to restart", corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}, corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 255, Reason: "Error", Message: "Unexpected status code 500: Saving stream failed: " + common.CloneChecksumMismatchMessage},
+		}),
+	)
+})
+
+var _ = Describe("Clone source pod compression", func() {
+	DescribeTable("should", func(compression string, expected string) {
+		targetPvc := createPvc("target", "default", map[string]string{AnnCloneSourcePod: "source-pod"}, nil)
+		if compression != "" {
+			targetPvc.Annotations[AnnCloneCompression] = compression
+		}
+		pod := MakeCloneSourcePodSpec(corev1.PersistentVolumeFilesystem, "image", "Always", "source", "default", "", nil, nil, nil, targetPvc, nil, &sdkapi.NodePlacement{}, "", nil)
+		value := ""
+		for _, env := range pod.Spec.Containers[0].Env {
+			if env.Name == common.CloneCompressionVar {
+				value = env.Value
+			}
+		}
+		Expect(value).To(Equal(expected))
+	},
+		Entry("compress the stream with zstd when requested", common.CloneCompressionZstd, common.CloneCompressionZstd),
+		Entry("keep the default compression without annotation", "", ""),
+		Entry("keep the default compression with an unknown compression", "lz4", ""),
+	)
 })
 
 var _ = Describe("TokenValidation", func() {
@@ -655,6 +843,47 @@ var _ = Describe("TokenValidation", func() {
 		Entry("fail on bad targetNamespace", badTargetNamespace, false),
 		Entry("fail on bad missing parameters", missingParams, false),
 	)
+
+	DescribeTable("validate volumesnapshots tokens", func(annotations map[string]string, expectedSuccess bool) {
+		p := goodTokenData()
+		p.Name = "snapshot"
+		p.Resource.Resource = "volumesnapshots"
+		tokenString, err := g.Generate(p)
+		Expect(err).ToNot(HaveOccurred())
+
+		restorePvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cdi-snapshot-restore-uid",
+				Namespace:   "sourcens",
+				Annotations: annotations,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				DataSource: &corev1.TypedLocalObjectReference{
+					Name: "snapshot",
+					Kind: "VolumeSnapshot",
+				},
+			},
+		}
+		target := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "target",
+				Namespace: "targetns",
+				Annotations: map[string]string{
+					AnnCloneToken: tokenString,
+				},
+			},
+		}
+		err = validateCloneToken(v, restorePvc, target)
+		if expectedSuccess {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+		Entry("succeed on the PVC restored for the target", map[string]string{AnnSnapshotRestoreFor: "targetns/target"}, true),
+		Entry("fail on a PVC restored for another target", map[string]string{AnnSnapshotRestoreFor: "targetns/foo"}, false),
+		Entry("fail on a PVC not restored from the snapshot", nil, false),
+	)
 })
 
 func createCloneReconciler(objects ...runtime.Object) *CloneReconciler {
@@ -676,10 +905,11 @@ func createCloneReconciler(objects ...runtime.Object) *CloneReconciler {
 
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	return &CloneReconciler{
-		client:   cl,
-		scheme:   s,
-		log:      cloneLog,
-		recorder: rec,
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            cloneLog,
+		recorder:       rec,
 		tokenValidator: &FakeValidator{
 			Params: make(map[string]string, 0),
 		},
@@ -797,7 +1027,7 @@ func createSourcePod(pvc *corev1.PersistentVolumeClaim, pvcUID string) *corev1.P
 		},
 		Spec: corev1.PodSpec{
 			SecurityContext: &corev1.PodSecurityContext{
-				RunAsUser: &[]int64{0}[0],
+				RunAsUser: &[]int64{common.QemuSubUID}[0],
 			},
 			Containers: []corev1.Container{
 				{
@@ -872,7 +1102,7 @@ func createSourcePod(pvc *corev1.PersistentVolumeClaim, pvcUID string) *corev1.P
 			},
 		}
 		pod.Spec.SecurityContext = &corev1.PodSecurityContext{
-			RunAsUser: &[]int64{0}[0],
+			RunAsUser: &[]int64{common.QemuSubUID}[0],
 		}
 	} else {
 		pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// AnnCloneGrant is the annotation holding the name of the CloneGrant of the source namespace that allows the clone,
+	// in place of a clone token
+	AnnCloneGrant = AnnAPIGroup + "/storage.clone.grant"
+
+	// CloneGrantUsed provides a const to indicate a CloneGrant allowed a clone
+	CloneGrantUsed = "CloneGrantUsed"
+)
+
+// CloneGrantAllows checks if the CloneGrant allows the DataVolumes of the target namespace to clone the source PVC at
+// the given time, and returns why it does not otherwise
+func CloneGrantAllows(grant *cdiv1.CloneGrant, source *corev1.PersistentVolumeClaim, targetNamespace string, now time.Time) (bool, string, error) {
+	if grant.Namespace != source.Namespace || grant.Spec.TargetNamespace != targetNamespace {
+		return false, fmt.Sprintf("CloneGrant %s/%s does not allow namespace %s to clone", grant.Namespace, grant.Name, targetNamespace), nil
+	}
+	if grant.Spec.ExpirationTime != nil && !now.Before(grant.Spec.ExpirationTime.Time) {
+		return false, fmt.Sprintf("CloneGrant %s/%s expired at %s", grant.Namespace, grant.Name, grant.Spec.ExpirationTime.UTC().Format(time.RFC3339)), nil
+	}
+	if grant.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(grant.Spec.Selector)
+		if err != nil {
+			return false, "", errors.Wrapf(err, "invalid selector of CloneGrant %s/%s", grant.Namespace, grant.Name)
+		}
+		if !selector.Matches(labels.Set(source.Labels)) {
+			return false, fmt.Sprintf("CloneGrant %s/%s does not select PVC %s", grant.Namespace, grant.Name, source.Name), nil
+		}
+	}
+	return true, "", nil
+}
+
+// validateCloneGrant returns the CloneGrant of the target PVC if it allows the clone of the source PVC, an error otherwise
+func (r *CloneReconciler) validateCloneGrant(source, target *corev1.PersistentVolumeClaim) (*cdiv1.CloneGrant, error) {
+	grant := &cdiv1.CloneGrant{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: source.Namespace, Name: target.Annotations[AnnCloneGrant]}, grant); err != nil {
+		return nil, errors.Wrap(err, "error getting clone grant")
+	}
+	ok, reason, err := CloneGrantAllows(grant, source, target.Namespace, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(reason)
+	}
+	return grant, nil
+}
+
+// recordCloneGrantUse records on the CloneGrant of the target PVC that it allowed the clone of the source PVC
+func (r *CloneReconciler) recordCloneGrantUse(source, target *corev1.PersistentVolumeClaim) error {
+	grant, err := r.validateCloneGrant(source, target)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	grant.Status.CloneCount++
+	grant.Status.LastCloneTime = &now
+	grant.Status.LastSource = source.Name
+	grant.Status.LastTarget = target.Namespace + "/" + target.Name
+	if err := r.client.Update(context.TODO(), grant); err != nil {
+		return err
+	}
+	r.recorder.Eventf(grant, corev1.EventTypeNormal, CloneGrantUsed, "Allowed the clone of PVC %s into %s/%s", source.Name, target.Namespace, target.Name)
+	return nil
+}
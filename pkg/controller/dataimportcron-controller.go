@@ -0,0 +1,1055 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	osnaming "github.com/openshift/library-go/pkg/build/naming"
+	"github.com/pkg/errors"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	dataImportCronControllerAgentName = "dataimportcron-controller"
+
+	// AnnDataImportCron is the annotation holding the name of the DataImportCron of its poll pods and DataVolumes
+	AnnDataImportCron = AnnAPIGroup + "/storage.import.cron"
+	// AnnDataImportCronSpecHash is the annotation holding the hash of the spec of the CronJob polling the source of a
+	// DataImportCron, the CronJob is only updated when the hash changes
+	AnnDataImportCronSpecHash = AnnAPIGroup + "/storage.import.cron.specHash"
+	// AnnDataImportCronRollback is the annotation requesting a DataImportCron to pin its DataSource to the import
+	// preceding the one it points at, the annotation is removed once handled
+	AnnDataImportCronRollback = AnnAPIGroup + "/storage.import.cron.rollback"
+	// AnnDataImportCronDigest is the annotation holding the digest of the golden image the DataSource of a
+	// DataImportCron points at
+	AnnDataImportCronDigest = AnnAPIGroup + "/storage.import.cron.digest"
+	// LabelDataImportCron is the label selecting the poll pods, DataVolumes and DataSource of a DataImportCron
+	LabelDataImportCron = AnnAPIGroup + "/dataImportCron"
+
+	// DataImportCronPollFailed provides a const to indicate the poll of the source of a DataImportCron failed
+	DataImportCronPollFailed = "PollFailed"
+	// DataImportCronImportStarted provides a const to indicate a DataImportCron started the import of a new image
+	DataImportCronImportStarted = "ImportStarted"
+	// DataImportCronGarbageCollected provides a const to indicate a DataImportCron deleted an import it does not retain
+	DataImportCronGarbageCollected = "GarbageCollected"
+	// DataImportCronDataSourceConflict provides a const to indicate the DataSource of a DataImportCron is managed by
+	// another object
+	DataImportCronDataSourceConflict = "DataSourceConflict"
+	// DataImportCronRolledBack provides a const to indicate a DataImportCron pinned its DataSource to the previous import
+	DataImportCronRolledBack = "RolledBack"
+	// DataImportCronRollbackFailed provides a const to indicate a DataImportCron has no previous import to roll back to
+	DataImportCronRollbackFailed = "RollbackFailed"
+	// DataImportCronGoldenImageUpdated provides a const to indicate the DataSource of a DataImportCron points at a new
+	// golden image
+	DataImportCronGoldenImageUpdated = "GoldenImageUpdated"
+	// DataImportCronPinnedDigestNotRetained provides a const to indicate the pinned digest of a DataImportCron cannot be
+	// imported again
+	DataImportCronPinnedDigestNotRetained = "PinnedDigestNotRetained"
+	// DataImportCronNotificationFailed provides a const to indicate the webhook of a DataImportCron was not notified
+	DataImportCronNotificationFailed = "NotificationFailed"
+
+	// MessageDataImportCronImportStarted provides a const to form the message of the start of an import
+	MessageDataImportCronImportStarted = "Importing %s into DataVolume %s"
+	// MessageDataImportCronGarbageCollected provides a const to form the message of the deletion of an import
+	MessageDataImportCronGarbageCollected = "Deleted DataVolume %s of the import of %s"
+	// MessageDataImportCronDataSourceConflict provides a const to form the message of a DataSource managed by another object
+	MessageDataImportCronDataSourceConflict = "DataSource %s is managed by %s %s"
+	// MessageDataImportCronRolledBack provides a const to form the message of a rollback
+	MessageDataImportCronRolledBack = "Rolled back from %s to %s, the DataSource is pinned to %s"
+	// MessageDataImportCronRollbackFailed provides a const to form the message of a rollback without a previous import
+	MessageDataImportCronRollbackFailed = "No import preceding %s succeeded, there is nothing to roll back to"
+	// MessageDataImportCronGoldenImageUpdated provides a const to form the message of a new golden image
+	MessageDataImportCronGoldenImageUpdated = "DataSource %s points at the golden image %s in PVC %s"
+	// MessageDataImportCronPinnedDigestNotRetained provides a const to form the message of a pinned digest which cannot
+	// be imported again
+	MessageDataImportCronPinnedDigestNotRetained = "The import of the pinned digest %s is not retained, only registry images are imported again"
+	// MessageDataImportCronNotificationFailed provides a const to form the message of a failed notification
+	MessageDataImportCronNotificationFailed = "Failed to notify %s of the golden image %s: %v"
+
+	// dataImportCronPollPrefix is the prefix of the names of the poll pods and CronJobs of the DataImportCrons
+	dataImportCronPollPrefix = "cdi-poll"
+	// dataImportCronNotificationPrefix is the prefix of the names of the notification pods of the DataImportCrons
+	dataImportCronNotificationPrefix = "cdi-notify"
+	// cronJobNameMaxLength is the longest name of a CronJob, the names of its Jobs get a suffix
+	cronJobNameMaxLength = 52
+	// defaultImportsToKeep is the number of imports retained when the DataImportCron does not set it
+	defaultImportsToKeep = 3
+	// dataImportCronDigestLength is the length of the prefix of the digest in the names of the DataVolumes
+	dataImportCronDigestLength = 12
+	// notificationRetryInterval is the delay before a failed notification is sent again
+	notificationRetryInterval = time.Minute
+)
+
+// DataImportCronReconciler members
+type DataImportCronReconciler struct {
+	client         client.Client
+	uncachedClient client.Client
+	recorder       record.EventRecorder
+	scheme         *runtime.Scheme
+	log            logr.Logger
+	featureGates   featuregates.FeatureGates
+	image          string
+	verbose        string
+	pullPolicy     string
+}
+
+// dataImportCronPollResult is the version of the source reported by a poll pod
+type dataImportCronPollResult struct {
+	// digest is the digest of the registry image, or of the validators of the http or s3 object
+	digest string
+	// validators are the ETag, Last-Modified time and version of the http or s3 object, as source validator annotations
+	validators map[string]string
+	// err is the reason the poll failed, nil if it succeeded
+	err error
+	// finishedAt is the time the poll pod terminated
+	finishedAt metav1.Time
+}
+
+// dataImportCronNotification is the body of the requests notifying the webhook of a DataImportCron of a new golden
+// image
+type dataImportCronNotification struct {
+	DataImportCron string `json:"dataImportCron"`
+	Namespace      string `json:"namespace"`
+	DataSource     string `json:"dataSource"`
+	PVC            string `json:"pvc"`
+	Digest         string `json:"digest"`
+}
+
+// NewDataImportCronController creates a new instance of the DataImportCron controller.
+func NewDataImportCronController(mgr manager.Manager, log logr.Logger, importerImage, pullPolicy, verbose string) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reconciler := &DataImportCronReconciler{
+		client:         mgr.GetClient(),
+		uncachedClient: uncachedClient,
+		scheme:         mgr.GetScheme(),
+		log:            log.WithName(dataImportCronControllerAgentName),
+		featureGates:   featuregates.NewFeatureGates(mgr.GetClient()),
+		image:          importerImage,
+		verbose:        verbose,
+		pullPolicy:     pullPolicy,
+		recorder:       mgr.GetEventRecorderFor(dataImportCronControllerAgentName),
+	}
+	dataImportCronController, err := controller.New(dataImportCronControllerAgentName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDataImportCronControllerWatches(mgr, dataImportCronController); err != nil {
+		return nil, err
+	}
+	return dataImportCronController, nil
+}
+
+func addDataImportCronControllerWatches(mgr manager.Manager, dataImportCronController controller.Controller) error {
+	if err := cdiv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := batchv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := dataImportCronController.Watch(&source.Kind{Type: &cdiv1.DataImportCron{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	for _, ownedType := range []runtime.Object{&cdiv1.DataVolume{}, &cdiv1.DataSource{}, &batchv1beta1.CronJob{}} {
+		if err := dataImportCronController.Watch(&source.Kind{Type: ownedType}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &cdiv1.DataImportCron{},
+			IsController: true,
+		}); err != nil {
+			return err
+		}
+	}
+	// The pods of the CronJob are owned by its Jobs, they are mapped to the DataImportCron by their annotation
+	if err := dataImportCronController.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			name, ok := obj.Meta.GetAnnotations()[AnnDataImportCron]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: name}}}
+		}),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reconcile the reconcile loop for the DataImportCron object.
+func (r *DataImportCronReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataImportCron", req.NamespacedName)
+	dataImportCron := &cdiv1.DataImportCron{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, dataImportCron); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if dataImportCron.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcilePollCronJob(log, dataImportCron); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	dataImportCronCopy := dataImportCron.DeepCopy()
+	if err := r.reconcilePolls(log, dataImportCronCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.reconcileRollback(log, dataImportCronCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.reconcilePinnedDigest(log, dataImportCronCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+	retained, err := r.reconcileImports(log, dataImportCronCopy)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	result, err := r.garbageCollect(log, dataImportCronCopy, retained)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	notified, err := r.notifyGoldenImage(log, dataImportCronCopy)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !notified && (result.RequeueAfter == 0 || result.RequeueAfter > notificationRetryInterval) {
+		result.RequeueAfter = notificationRetryInterval
+	}
+
+	if !reflect.DeepEqual(dataImportCron.Status, dataImportCronCopy.Status) ||
+		!reflect.DeepEqual(dataImportCron.Spec, dataImportCronCopy.Spec) ||
+		!reflect.DeepEqual(dataImportCron.Annotations, dataImportCronCopy.Annotations) {
+		if err := r.client.Update(context.TODO(), dataImportCronCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// dataImportCronLabel returns the value of the label of the objects of the DataImportCron
+func dataImportCronLabel(dataImportCron *cdiv1.DataImportCron) string {
+	return naming.GetLabelNameFromResourceName(dataImportCron.Name)
+}
+
+// dataImportCronPollName returns the name of the initial poll pod and of the CronJob of the DataImportCron
+func dataImportCronPollName(dataImportCron *cdiv1.DataImportCron) string {
+	return osnaming.GetName(dataImportCronPollPrefix, dataImportCron.Name, cronJobNameMaxLength)
+}
+
+// newPollPod returns the poll pod of the DataImportCron, the importer pod of its template reporting the version of the
+// source instead of importing it
+func (r *DataImportCronReconciler) newPollPod(dataImportCron *cdiv1.DataImportCron) (*corev1.Pod, error) {
+	dataVolume := dataImportCron.Spec.Template.DeepCopy()
+	dataVolume.Name = dataImportCron.Name
+	dataVolume.Namespace = dataImportCron.Namespace
+	if len(dataImportCron.Status.Imports) > 0 {
+		// An http or s3 source is polled with conditional requests, using the validators of the latest import
+		latest, err := r.getImportDataVolume(dataImportCron.Namespace, dataImportCron.Status.Imports[0].DataVolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil {
+			for _, ann := range []string{AnnSourceETag, AnnSourceLastModified, AnnSourceVersionID} {
+				if value, ok := latest.Annotations[ann]; ok {
+					if dataVolume.Annotations == nil {
+						dataVolume.Annotations = map[string]string{}
+					}
+					dataVolume.Annotations[ann] = value
+				}
+			}
+		}
+	}
+	pod, err := newSourceReaderPod(r.client, r.uncachedClient, r.log, r.featureGates, dataVolume, r.image, r.verbose, r.pullPolicy)
+	if err != nil {
+		return nil, err
+	}
+	pod.Name = dataImportCronPollName(dataImportCron)
+	pod.Labels[LabelDataImportCron] = dataImportCronLabel(dataImportCron)
+	pod.Annotations[AnnDataImportCron] = dataImportCron.Name
+	pod.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(dataImportCron, cdiv1.SchemeGroupVersion.WithKind("DataImportCron")),
+	}
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: common.ImporterPoll, Value: "true"})
+	return pod, nil
+}
+
+// reconcilePollCronJob creates the CronJob polling the source of the DataImportCron on its schedule, and updates it
+// when the schedule or the poll pod changes
+func (r *DataImportCronReconciler) reconcilePollCronJob(log logr.Logger, dataImportCron *cdiv1.DataImportCron) error {
+	pod, err := r.newPollPod(dataImportCron)
+	if err != nil {
+		return err
+	}
+	one := int32(1)
+	backoffLimit := int32(2)
+	spec := batchv1beta1.CronJobSpec{
+		Schedule:                   dataImportCron.Spec.Schedule,
+		ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+		SuccessfulJobsHistoryLimit: &one,
+		FailedJobsHistoryLimit:     &one,
+	}
+	spec.JobTemplate.Spec.BackoffLimit = &backoffLimit
+	spec.JobTemplate.Spec.Template.ObjectMeta = metav1.ObjectMeta{
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+	}
+	spec.JobTemplate.Spec.Template.Spec = pod.Spec
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(specJSON)
+	specHash := hex.EncodeToString(hash[:])
+
+	cronJob := &batchv1beta1.CronJob{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: dataImportCronPollName(dataImportCron)}, cronJob); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		cronJob = &batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dataImportCronPollName(dataImportCron),
+				Namespace: dataImportCron.Namespace,
+				Labels: map[string]string{
+					common.CDILabelKey:  common.CDILabelValue,
+					LabelDataImportCron: dataImportCronLabel(dataImportCron),
+				},
+				Annotations: map[string]string{
+					AnnDataImportCronSpecHash: specHash,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(dataImportCron, cdiv1.SchemeGroupVersion.WithKind("DataImportCron")),
+				},
+			},
+			Spec: spec,
+		}
+		if err := r.client.Create(context.TODO(), cronJob); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		log.V(1).Info("Created the poll CronJob", "CronJob", cronJob.Name)
+		return nil
+	}
+	if !metav1.IsControlledBy(cronJob, dataImportCron) {
+		return errors.Errorf("CronJob %s/%s is not the poll CronJob of the DataImportCron", cronJob.Namespace, cronJob.Name)
+	}
+	// The defaults set by the API server make the stored spec differ from the desired one, only the hash is compared
+	if cronJob.Annotations[AnnDataImportCronSpecHash] == specHash {
+		return nil
+	}
+	cronJobCopy := cronJob.DeepCopy()
+	if cronJobCopy.Annotations == nil {
+		cronJobCopy.Annotations = make(map[string]string)
+	}
+	cronJobCopy.Annotations[AnnDataImportCronSpecHash] = specHash
+	cronJobCopy.Spec = spec
+	log.V(1).Info("Updating the poll CronJob", "CronJob", cronJob.Name)
+	return r.client.Update(context.TODO(), cronJobCopy)
+}
+
+// reconcilePolls reads the result of the poll pods which terminated since the last execution, and starts the import of
+// the digest reported by the latest one. The initial poll pod of a new DataImportCron polls the source right away
+// instead of waiting for the schedule, it is deleted once its result is read.
+func (r *DataImportCronReconciler) reconcilePolls(log logr.Logger, dataImportCron *cdiv1.DataImportCron) error {
+	pods := &corev1.PodList{}
+	if err := r.client.List(context.TODO(), pods, client.InNamespace(dataImportCron.Namespace), client.MatchingLabels{LabelDataImportCron: dataImportCronLabel(dataImportCron)}); err != nil {
+		return err
+	}
+	var latest *dataImportCronPollResult
+	polls := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[AnnDataImportCron] != dataImportCron.Name {
+			continue
+		}
+		polls++
+		result := pollPodResult(pod)
+		if result == nil {
+			continue
+		}
+		if metav1.IsControlledBy(pod, dataImportCron) {
+			// The pods of the CronJob are deleted with their Jobs
+			if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+		if last := dataImportCron.Status.LastExecutionTimestamp; last != nil && !result.finishedAt.After(last.Time) {
+			continue
+		}
+		if latest == nil || result.finishedAt.After(latest.finishedAt.Time) {
+			latest = result
+		}
+	}
+
+	if latest == nil {
+		if polls > 0 || dataImportCron.Status.LastExecutionTimestamp != nil {
+			return nil
+		}
+		pod, err := r.newPollPod(dataImportCron)
+		if err != nil {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		log.V(1).Info("Created the initial poll pod", "pod.Name", pod.Name)
+		return nil
+	}
+
+	dataImportCron.Status.LastExecutionTimestamp = &latest.finishedAt
+	if latest.err != nil {
+		log.Info("The poll of the source failed", "error", latest.err.Error())
+		r.recorder.Event(dataImportCron, corev1.EventTypeWarning, DataImportCronPollFailed, latest.err.Error())
+		return nil
+	}
+	return r.startImport(log, dataImportCron, latest.digest, latest.validators)
+}
+
+// pollPodResult returns the version of the source reported by the poll pod, nil while the pod runs
+func pollPodResult(pod *corev1.Pod) *dataImportCronPollResult {
+	var terminated *corev1.ContainerStateTerminated
+	if len(pod.Status.ContainerStatuses) > 0 {
+		terminated = pod.Status.ContainerStatuses[0].State.Terminated
+	}
+	if terminated == nil {
+		if pod.Status.Phase == corev1.PodFailed {
+			// The pod failed before the importer ran, like when the deadline passes while the image is pulled
+			return &dataImportCronPollResult{
+				err:        errors.Errorf("the poll pod failed: %s %s", pod.Status.Reason, pod.Status.Message),
+				finishedAt: pod.CreationTimestamp,
+			}
+		}
+		return nil
+	}
+	result := &dataImportCronPollResult{finishedAt: terminated.FinishedAt}
+	message := strings.TrimSpace(terminated.Message)
+	if terminated.ExitCode != 0 {
+		if message == "" {
+			message = "the poll pod failed: " + terminated.Reason
+		}
+		result.err = errors.New(message)
+		return result
+	}
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, SourceDigestMessagePrefix) {
+			result.digest = strings.TrimPrefix(line, SourceDigestMessagePrefix)
+		}
+	}
+	validators := map[string]string{}
+	updateSourceValidatorsFromMessage(validators, message)
+	if result.digest == "" && len(validators) > 0 {
+		result.digest = sourceValidatorsDigest(validators)
+		result.validators = validators
+	}
+	if result.digest == "" {
+		result.err = errors.New("the poll pod did not report the digest of the source")
+	}
+	return result
+}
+
+// sourceValidatorsDigest returns a sha256 digest of the validators of an http or s3 object, which identifies the
+// version of the object like the digest of a registry image
+func sourceValidatorsDigest(validators map[string]string) string {
+	hash := sha256.New()
+	for _, ann := range []string{AnnSourceETag, AnnSourceLastModified, AnnSourceVersionID} {
+		hash.Write([]byte(ann + "=" + validators[ann] + "\n"))
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// startImport makes the import of the digest the latest one, the DataVolume importing it is created unless an import
+// of the digest is still retained
+func (r *DataImportCronReconciler) startImport(log logr.Logger, dataImportCron *cdiv1.DataImportCron, digest string, validators map[string]string) error {
+	now := metav1.Now()
+	for i, dataImportCronImport := range dataImportCron.Status.Imports {
+		if dataImportCronImport.Digest != digest {
+			continue
+		}
+		if i > 0 {
+			// The source is back to a previous image
+			dataImportCronImport.Timestamp = now
+			imports := append([]cdiv1.DataImportCronImport{dataImportCronImport}, dataImportCron.Status.Imports[:i]...)
+			dataImportCron.Status.Imports = append(imports, dataImportCron.Status.Imports[i+1:]...)
+		}
+		return nil
+	}
+
+	dataImportCronImport, err := r.createImport(log, dataImportCron, digest, validators)
+	if err != nil {
+		return err
+	}
+	dataImportCron.Status.Imports = append([]cdiv1.DataImportCronImport{*dataImportCronImport}, dataImportCron.Status.Imports...)
+	return nil
+}
+
+// createImport creates the DataVolume importing the image of the digest
+func (r *DataImportCronReconciler) createImport(log logr.Logger, dataImportCron *cdiv1.DataImportCron, digest string, validators map[string]string) (*cdiv1.DataImportCronImport, error) {
+	dataVolume, err := newDataImportCronDataVolume(dataImportCron, digest, validators)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.client.Create(context.TODO(), dataVolume); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	log.Info("Importing a new image", "digest", digest, "DataVolume", dataVolume.Name)
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronImportStarted, MessageDataImportCronImportStarted, digest, dataVolume.Name)
+	return &cdiv1.DataImportCronImport{
+		DataVolumeName: dataVolume.Name,
+		Digest:         digest,
+		Timestamp:      metav1.Now(),
+	}, nil
+}
+
+// findImport returns the import of the digest, nil if it is not retained
+func findImport(dataImportCron *cdiv1.DataImportCron, digest string) *cdiv1.DataImportCronImport {
+	for i := range dataImportCron.Status.Imports {
+		if dataImportCron.Status.Imports[i].Digest == digest {
+			return &dataImportCron.Status.Imports[i]
+		}
+	}
+	return nil
+}
+
+// reconcilePinnedDigest imports the pinned digest again when its import is not retained anymore. The import is
+// appended after the newer imports, so it does not become the latest one. Only a registry image can be imported again
+// by its digest, the previous versions of an http or s3 object are gone.
+func (r *DataImportCronReconciler) reconcilePinnedDigest(log logr.Logger, dataImportCron *cdiv1.DataImportCron) error {
+	digest := dataImportCron.Spec.PinnedDigest
+	if digest == "" || findImport(dataImportCron, digest) != nil {
+		return nil
+	}
+	if dataImportCron.Spec.Template.Spec.Source.Registry == nil {
+		r.recorder.Eventf(dataImportCron, corev1.EventTypeWarning, DataImportCronPinnedDigestNotRetained, MessageDataImportCronPinnedDigestNotRetained, digest)
+		return nil
+	}
+	dataImportCronImport, err := r.createImport(log, dataImportCron, digest, nil)
+	if err != nil {
+		return err
+	}
+	dataImportCron.Status.Imports = append(dataImportCron.Status.Imports, *dataImportCronImport)
+	return nil
+}
+
+// reconcileRollback handles the rollback annotation: the DataSource is pinned to the latest import preceding the
+// current one which succeeded, so a broken golden image is replaced in one step. The pin stays until it is removed
+// from the spec, newer images are still imported meanwhile.
+func (r *DataImportCronReconciler) reconcileRollback(log logr.Logger, dataImportCron *cdiv1.DataImportCron) error {
+	if _, ok := dataImportCron.Annotations[AnnDataImportCronRollback]; !ok {
+		return nil
+	}
+	delete(dataImportCron.Annotations, AnnDataImportCronRollback)
+
+	current := dataImportCron.Status.CurrentDigest
+	previous := -1
+	for i, dataImportCronImport := range dataImportCron.Status.Imports {
+		if dataImportCronImport.Digest == current {
+			previous = i + 1
+			break
+		}
+	}
+	if previous > 0 {
+		for _, dataImportCronImport := range dataImportCron.Status.Imports[previous:] {
+			dataVolume, err := r.getImportDataVolume(dataImportCron.Namespace, dataImportCronImport.DataVolumeName)
+			if err != nil {
+				return err
+			}
+			if dataVolume == nil || dataVolume.Status.Phase != cdiv1.Succeeded {
+				continue
+			}
+			dataImportCron.Spec.PinnedDigest = dataImportCronImport.Digest
+			log.Info("Rolled back to the previous import", "from", current, "to", dataImportCronImport.Digest)
+			r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronRolledBack, MessageDataImportCronRolledBack, current, dataImportCronImport.Digest, dataImportCronImport.DataVolumeName)
+			return nil
+		}
+	}
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeWarning, DataImportCronRollbackFailed, MessageDataImportCronRollbackFailed, current)
+	return nil
+}
+
+// newDataImportCronDataVolume returns the DataVolume of the template of the DataImportCron importing the image of the
+// digest, named after the DataImportCron and the digest and controlled by the DataImportCron. A registry image is
+// imported by its digest and an s3 object by its version when the bucket is versioned, an http object is imported as
+// it is when the DataVolume runs. The validators of an http or s3 object are kept on the DataVolume, the next polls
+// compare the source with them.
+func newDataImportCronDataVolume(dataImportCron *cdiv1.DataImportCron, digest string, validators map[string]string) (*cdiv1.DataVolume, error) {
+	template := &dataImportCron.Spec.Template
+	if template.Spec.Source.Registry == nil && template.Spec.Source.HTTP == nil && template.Spec.Source.S3 == nil {
+		return nil, errors.New("only registry, http and s3 sources are polled")
+	}
+	labels := map[string]string{}
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+	labels[LabelDataImportCron] = dataImportCronLabel(dataImportCron)
+	annotations := map[string]string{}
+	for key, value := range template.Annotations {
+		annotations[key] = value
+	}
+	for key, value := range validators {
+		annotations[key] = value
+	}
+	annotations[AnnDataImportCron] = dataImportCron.Name
+
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dataImportCronDataVolumeName(dataImportCron, digest),
+			Namespace:   dataImportCron.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(dataImportCron, cdiv1.SchemeGroupVersion.WithKind("DataImportCron")),
+			},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	switch source := &dataVolume.Spec.Source; {
+	case source.Registry != nil:
+		url, err := pinRegistryURL(source.Registry.URL, digest)
+		if err != nil {
+			return nil, err
+		}
+		source.Registry.URL = url
+	case source.S3 != nil:
+		if versionID := validators[AnnSourceVersionID]; versionID != "" {
+			source.S3.ObjectVersionID = versionID
+		}
+	}
+	return dataVolume, nil
+}
+
+// dataImportCronDataVolumeName returns the name of the DataVolume importing the digest
+func dataImportCronDataVolumeName(dataImportCron *cdiv1.DataImportCron, digest string) string {
+	encoded := digest[strings.Index(digest, ":")+1:]
+	if len(encoded) > dataImportCronDigestLength {
+		encoded = encoded[:dataImportCronDigestLength]
+	}
+	return naming.GetResourceName(dataImportCron.Name, encoded)
+}
+
+// pinRegistryURL returns the registry url of the image of the digest, in place of the tag of the url
+func pinRegistryURL(url, digest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(url, "docker://"))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid registry url %s", url)
+	}
+	return "docker://" + named.Name() + "@" + digest, nil
+}
+
+// getImportDataVolume returns the DataVolume of the import, nil if it was deleted
+func (r *DataImportCronReconciler) getImportDataVolume(namespace, name string) (*cdiv1.DataVolume, error) {
+	dataVolume := &cdiv1.DataVolume{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	err := r.client.Get(context.TODO(), key, dataVolume)
+	if k8serrors.IsNotFound(err) {
+		// The cache may not have seen the DataVolume created by the previous reconcile yet
+		err = r.uncachedClient.Get(context.TODO(), key, dataVolume)
+	}
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return dataVolume, nil
+}
+
+// reconcileImports drops the imports whose DataVolume was deleted, and points the DataSource at the PVC of the pinned
+// import, or of the latest import that succeeded when no digest is pinned. The DataSource is left alone while the
+// pinned import is in progress. It returns the names of the PVCs retained whatever their age: the one the DataSource
+// points at and the one of the pinned import.
+func (r *DataImportCronReconciler) reconcileImports(log logr.Logger, dataImportCron *cdiv1.DataImportCron) (sets.String, error) {
+	retained := sets.NewString()
+	pinnedDigest := dataImportCron.Spec.PinnedDigest
+	imports := []cdiv1.DataImportCronImport{}
+	var latest, pinned *cdiv1.DataVolume
+	latestDigest := ""
+	for _, dataImportCronImport := range dataImportCron.Status.Imports {
+		dataVolume, err := r.getImportDataVolume(dataImportCron.Namespace, dataImportCronImport.DataVolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if dataVolume == nil {
+			log.V(1).Info("The DataVolume of the import was deleted", "DataVolume", dataImportCronImport.DataVolumeName)
+			continue
+		}
+		imports = append(imports, dataImportCronImport)
+		if dataImportCronImport.Digest == pinnedDigest {
+			retained.Insert(dataVolume.Name)
+		}
+		if dataVolume.Status.Phase != cdiv1.Succeeded {
+			continue
+		}
+		if latest == nil {
+			latest = dataVolume
+			latestDigest = dataImportCronImport.Digest
+		}
+		if dataImportCronImport.Digest == pinnedDigest {
+			pinned = dataVolume
+		}
+	}
+	if len(imports) == 0 {
+		imports = nil
+	}
+	dataImportCron.Status.Imports = imports
+
+	if latest != nil {
+		if ready := findConditionByType(cdiv1.DataVolumeReady, latest.Status.Conditions); ready != nil && ready.Status == corev1.ConditionTrue {
+			lastImportTimestamp := ready.LastTransitionTime
+			dataImportCron.Status.LastImportTimestamp = &lastImportTimestamp
+		}
+	}
+	target, digest := latest, latestDigest
+	if pinnedDigest != "" {
+		target, digest = pinned, pinnedDigest
+	}
+	pvcName := ""
+	if target != nil {
+		pvcName = target.Name
+	}
+	current, err := r.reconcileDataSource(log, dataImportCron, pvcName, digest)
+	if err != nil {
+		return nil, err
+	}
+	dataImportCron.Status.CurrentDigest = ""
+	if current != "" {
+		retained.Insert(current)
+		for _, dataImportCronImport := range imports {
+			if dataImportCronImport.DataVolumeName == current {
+				dataImportCron.Status.CurrentDigest = dataImportCronImport.Digest
+			}
+		}
+	}
+	return retained, nil
+}
+
+// reconcileDataSource points the DataSource of the DataImportCron at the PVC of the import of the digest, the
+// DataSource is created and controlled by the DataImportCron if it does not exist. A DataSource controlled by another
+// object is left alone, and so is the DataSource when the PVC is empty. It returns the name of the PVC of the
+// DataImportCron the DataSource points at.
+func (r *DataImportCronReconciler) reconcileDataSource(log logr.Logger, dataImportCron *cdiv1.DataImportCron, pvcName, digest string) (string, error) {
+	source := cdiv1.DataSourceSource{
+		PVC: &cdiv1.DataVolumeSourcePVC{
+			Namespace: dataImportCron.Namespace,
+			Name:      pvcName,
+		},
+	}
+	dataSource := &cdiv1.DataSource{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: dataImportCron.Spec.ManagedDataSource}, dataSource); err != nil {
+		if !k8serrors.IsNotFound(err) || pvcName == "" {
+			return "", IgnoreNotFound(err)
+		}
+		dataSource = &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dataImportCron.Spec.ManagedDataSource,
+				Namespace: dataImportCron.Namespace,
+				Labels: map[string]string{
+					LabelDataImportCron: dataImportCronLabel(dataImportCron),
+				},
+				Annotations: map[string]string{
+					AnnDataImportCronDigest: digest,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(dataImportCron, cdiv1.SchemeGroupVersion.WithKind("DataImportCron")),
+				},
+			},
+			Spec: cdiv1.DataSourceSpec{
+				Source: source,
+			},
+		}
+		if err := r.client.Create(context.TODO(), dataSource); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return "", err
+		}
+		log.Info("Created the DataSource", "DataSource", dataSource.Name, "PVC", pvcName)
+		return pvcName, nil
+	}
+	if owner := metav1.GetControllerOf(dataSource); owner != nil && owner.UID != dataImportCron.UID {
+		r.recorder.Eventf(dataImportCron, corev1.EventTypeWarning, DataImportCronDataSourceConflict, MessageDataImportCronDataSourceConflict, dataSource.Name, owner.Kind, owner.Name)
+		return "", nil
+	}
+	if pvcName == "" || (reflect.DeepEqual(dataSource.Spec.Source, source) && dataSource.Annotations[AnnDataImportCronDigest] == digest) {
+		if pvc := dataSource.Spec.Source.PVC; pvc != nil && pvc.Namespace == dataImportCron.Namespace {
+			return pvc.Name, nil
+		}
+		return "", nil
+	}
+	dataSourceCopy := dataSource.DeepCopy()
+	dataSourceCopy.Spec.Source = source
+	if dataSourceCopy.Annotations == nil {
+		dataSourceCopy.Annotations = map[string]string{}
+	}
+	dataSourceCopy.Annotations[AnnDataImportCronDigest] = digest
+	log.Info("Pointing the DataSource at the import", "DataSource", dataSource.Name, "PVC", pvcName)
+	if err := r.client.Update(context.TODO(), dataSourceCopy); err != nil {
+		return "", err
+	}
+	return pvcName, nil
+}
+
+// notifyGoldenImage reports the golden image the DataSource points at once it changes: by an event, and by a request
+// to the webhook of the DataImportCron when it has one. The request is sent by a notification pod in the namespace of
+// the DataImportCron, with the network and the certificates of its imports. It returns false when the webhook was not
+// notified, the notification is sent again after notificationRetryInterval then.
+func (r *DataImportCronReconciler) notifyGoldenImage(log logr.Logger, dataImportCron *cdiv1.DataImportCron) (bool, error) {
+	digest := dataImportCron.Status.CurrentDigest
+	if digest == "" || digest == dataImportCron.Status.NotifiedDigest {
+		return true, nil
+	}
+	pvcName := ""
+	if dataImportCronImport := findImport(dataImportCron, digest); dataImportCronImport != nil {
+		pvcName = dataImportCronImport.DataVolumeName
+	}
+	if notification := dataImportCron.Spec.Notification; notification != nil {
+		pod := &corev1.Pod{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: dataImportCronNotificationName(dataImportCron)}, pod); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return false, err
+			}
+			pod, err = r.newNotificationPod(dataImportCron, digest, pvcName)
+			if err != nil {
+				return false, err
+			}
+			if err := r.client.Create(context.TODO(), pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+				return false, err
+			}
+			log.V(1).Info("Created the notification pod", "pod.Name", pod.Name)
+			// The pod is watched, its result is read on the reconcile of its termination
+			return true, nil
+		}
+		if !metav1.IsControlledBy(pod, dataImportCron) {
+			return false, errors.Errorf("pod %s is not controlled by DataImportCron %s", pod.Name, dataImportCron.Name)
+		}
+		if pod.Annotations[AnnDataImportCronDigest] != digest {
+			// The pod notifies a former golden image, it is replaced on the next reconcile
+			return true, IgnoreNotFound(r.client.Delete(context.TODO(), pod))
+		}
+		notified, err := notificationPodResult(pod)
+		if !notified && err == nil {
+			return true, nil
+		}
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return false, err
+		}
+		if err != nil {
+			log.Info("Failed to notify the webhook of the golden image", "url", notification.URL, "error", err.Error())
+			r.recorder.Eventf(dataImportCron, corev1.EventTypeWarning, DataImportCronNotificationFailed, MessageDataImportCronNotificationFailed, notification.URL, digest, err)
+			return false, nil
+		}
+	}
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronGoldenImageUpdated, MessageDataImportCronGoldenImageUpdated, dataImportCron.Spec.ManagedDataSource, digest, pvcName)
+	dataImportCron.Status.NotifiedDigest = digest
+	return true, nil
+}
+
+// dataImportCronNotificationName returns the name of the notification pod of the DataImportCron
+func dataImportCronNotificationName(dataImportCron *cdiv1.DataImportCron) string {
+	return naming.GetResourceName(dataImportCronNotificationPrefix, dataImportCron.Name)
+}
+
+// newNotificationPod returns the notification pod of the DataImportCron, the importer pod of its template posting the
+// notification of the golden image to its webhook instead of importing its source
+func (r *DataImportCronReconciler) newNotificationPod(dataImportCron *cdiv1.DataImportCron, digest, pvcName string) (*corev1.Pod, error) {
+	notification := dataImportCron.Spec.Notification
+	payload, err := json.Marshal(&dataImportCronNotification{
+		DataImportCron: dataImportCron.Name,
+		Namespace:      dataImportCron.Namespace,
+		DataSource:     dataImportCron.Spec.ManagedDataSource,
+		PVC:            pvcName,
+		Digest:         digest,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dataVolume := dataImportCron.Spec.Template.DeepCopy()
+	dataVolume.Name = dataImportCron.Name
+	dataVolume.Namespace = dataImportCron.Namespace
+	dataVolume.Annotations = nil
+	dataVolume.Spec.Source = cdiv1.DataVolumeSource{
+		HTTP: &cdiv1.DataVolumeSourceHTTP{
+			URL:           notification.URL,
+			CertConfigMap: notification.CertConfigMap,
+		},
+	}
+	pod, err := newSourceReaderPod(r.client, r.uncachedClient, r.log, r.featureGates, dataVolume, r.image, r.verbose, r.pullPolicy)
+	if err != nil {
+		return nil, err
+	}
+	// The pod is not labeled as a poll pod, its result is not a version of the source
+	pod.Name = dataImportCronNotificationName(dataImportCron)
+	pod.Annotations[AnnDataImportCron] = dataImportCron.Name
+	pod.Annotations[AnnDataImportCronDigest] = digest
+	pod.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(dataImportCron, cdiv1.SchemeGroupVersion.WithKind("DataImportCron")),
+	}
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: common.ImporterNotification, Value: string(payload)})
+	return pod, nil
+}
+
+// notificationPodResult returns whether the notification pod notified the webhook, false and no error while the pod
+// runs
+func notificationPodResult(pod *corev1.Pod) (bool, error) {
+	var terminated *corev1.ContainerStateTerminated
+	if len(pod.Status.ContainerStatuses) > 0 {
+		terminated = pod.Status.ContainerStatuses[0].State.Terminated
+	}
+	if terminated == nil {
+		if pod.Status.Phase == corev1.PodFailed {
+			// The pod failed before the importer ran, like when the deadline passes while the image is pulled
+			return false, errors.Errorf("the notification pod failed: %s %s", pod.Status.Reason, pod.Status.Message)
+		}
+		return false, nil
+	}
+	if terminated.ExitCode != 0 {
+		message := strings.TrimSpace(terminated.Message)
+		if message == "" {
+			message = "the notification pod failed: " + terminated.Reason
+		}
+		return false, errors.New(message)
+	}
+	return true, nil
+}
+
+// garbageCollect deletes the imports which are not retained anymore: the imports beyond the importsToKeep latest ones
+// and older than the retention period. The imports of the retained PVCs are always retained, and so are the imports
+// whose PVC is in use. It returns when to garbage collect again, once the retention period of the next import expires.
+func (r *DataImportCronReconciler) garbageCollect(log logr.Logger, dataImportCron *cdiv1.DataImportCron, retained sets.String) (reconcile.Result, error) {
+	result := reconcile.Result{}
+	if dataImportCron.Spec.GarbageCollect != nil && *dataImportCron.Spec.GarbageCollect == cdiv1.DataImportCronGarbageCollectNever {
+		return result, nil
+	}
+	importsToKeep := defaultImportsToKeep
+	if dataImportCron.Spec.ImportsToKeep != nil {
+		importsToKeep = int(*dataImportCron.Spec.ImportsToKeep)
+	}
+	requeueAfter := func(after time.Duration) {
+		if result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+
+	imports := []cdiv1.DataImportCronImport{}
+	for i, dataImportCronImport := range dataImportCron.Status.Imports {
+		if i < importsToKeep || retained.Has(dataImportCronImport.DataVolumeName) {
+			imports = append(imports, dataImportCronImport)
+			continue
+		}
+		if retentionPeriod := dataImportCron.Spec.RetentionPeriod; retentionPeriod != nil {
+			if left := time.Until(dataImportCronImport.Timestamp.Add(retentionPeriod.Duration)); left > 0 {
+				imports = append(imports, dataImportCronImport)
+				requeueAfter(left)
+				continue
+			}
+		}
+		deleted, err := r.deleteImport(log, dataImportCron, dataImportCronImport)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !deleted {
+			imports = append(imports, dataImportCronImport)
+			requeueAfter(dataVolumeInUseRetryInterval)
+		}
+	}
+	if len(imports) == 0 {
+		imports = nil
+	}
+	dataImportCron.Status.Imports = imports
+	return result, nil
+}
+
+// deleteImport deletes the DataVolume of the import with its PVC, and the VolumeSnapshots of the PVC. The import is
+// kept while its PVC is in use, or while a smart clone is taking a snapshot of it, and false is returned then.
+func (r *DataImportCronReconciler) deleteImport(log logr.Logger, dataImportCron *cdiv1.DataImportCron, dataImportCronImport cdiv1.DataImportCronImport) (bool, error) {
+	name := dataImportCronImport.DataVolumeName
+	inUse, err := dataVolumeInUse(r.client, r.uncachedClient, dataImportCron.Namespace, name)
+	if err != nil {
+		return false, err
+	}
+	if inUse {
+		log.V(1).Info("Keeping the import, it is in use", "DataVolume", name)
+		return false, nil
+	}
+
+	snapshots := &snapshotv1.VolumeSnapshotList{}
+	if err := r.client.List(context.TODO(), snapshots, client.InNamespace(dataImportCron.Namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return false, err
+	}
+	snapshotsOfImport := []*snapshotv1.VolumeSnapshot{}
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if claimName := snapshot.Spec.Source.PersistentVolumeClaimName; claimName == nil || *claimName != name {
+			continue
+		}
+		if _, ok := snapshot.Annotations[AnnSmartCloneRequest]; ok {
+			log.V(1).Info("Keeping the import, it is being cloned", "DataVolume", name, "VolumeSnapshot", snapshot.Name)
+			return false, nil
+		}
+		snapshotsOfImport = append(snapshotsOfImport, snapshot)
+	}
+	for _, snapshot := range snapshotsOfImport {
+		if err := r.client.Delete(context.TODO(), snapshot); IgnoreNotFound(err) != nil {
+			return false, err
+		}
+	}
+
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dataImportCron.Namespace,
+		},
+	}
+	if err := r.client.Delete(context.TODO(), dataVolume); IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	log.Info("Deleted the import", "DataVolume", name, "digest", dataImportCronImport.Digest)
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronGarbageCollected, MessageDataImportCronGarbageCollected, name, dataImportCronImport.Digest)
+	return true, nil
+}
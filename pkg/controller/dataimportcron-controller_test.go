@@ -0,0 +1,789 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+)
+
+var (
+	dataImportCronLog = logf.Log.WithName("dataimportcron-controller-test")
+)
+
+const (
+	testDigest1 = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	testDigest2 = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	testDigest3 = "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	testDigest4 = "sha256:4444444444444444444444444444444444444444444444444444444444444444"
+)
+
+var _ = Describe("DataImportCron reconcile", func() {
+	It("Should do nothing and return nil when no DataImportCron exists", func() {
+		reconciler := createDataImportCronReconciler()
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "no-cron", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should create the poll CronJob and poll the source right away", func() {
+		dataImportCron := createDataImportCron("fedora")
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+
+		cronJob := &batchv1beta1.CronJob{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronPollName(dataImportCron), Namespace: "default"}, cronJob)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(cronJob, dataImportCron)).To(BeTrue())
+		Expect(cronJob.Spec.Schedule).To(Equal("0 */12 * * *"))
+		Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1beta1.ForbidConcurrent))
+		template := cronJob.Spec.JobTemplate.Spec.Template
+		Expect(template.Annotations[AnnDataImportCron]).To(Equal("fedora"))
+		Expect(pollEnv(template.Spec)[common.ImporterPoll]).To(Equal("true"))
+		Expect(pollEnv(template.Spec)[common.ImporterEndpoint]).To(Equal("docker://quay.io/containerdisks/fedora:latest"))
+		for _, volume := range template.Spec.Volumes {
+			Expect(volume.PersistentVolumeClaim).To(BeNil())
+		}
+
+		pod := getInitialPollPod(reconciler, dataImportCron)
+		Expect(pod).ToNot(BeNil())
+		Expect(metav1.IsControlledBy(pod, dataImportCron)).To(BeTrue())
+		Expect(pollEnv(pod.Spec)[common.ImporterPoll]).To(Equal("true"))
+	})
+
+	It("Should only update the CronJob when its spec changes", func() {
+		dataImportCron := createDataImportCron("fedora")
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		cronJob := &batchv1beta1.CronJob{}
+		key := types.NamespacedName{Name: dataImportCronPollName(dataImportCron), Namespace: "default"}
+		Expect(reconciler.client.Get(context.TODO(), key, cronJob)).To(Succeed())
+		resourceVersion := cronJob.ResourceVersion
+
+		reconcileDataImportCron(reconciler)
+		Expect(reconciler.client.Get(context.TODO(), key, cronJob)).To(Succeed())
+		Expect(cronJob.ResourceVersion).To(Equal(resourceVersion))
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		dataImportCron.Spec.Schedule = "@daily"
+		Expect(reconciler.client.Update(context.TODO(), dataImportCron)).To(Succeed())
+		reconcileDataImportCron(reconciler)
+		Expect(reconciler.client.Get(context.TODO(), key, cronJob)).To(Succeed())
+		Expect(cronJob.Spec.Schedule).To(Equal("@daily"))
+	})
+
+	It("Should import the digest reported by the poll pod pinned to the digest", func() {
+		dataImportCron := createDataImportCron("fedora")
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		finishedAt := completePollPod(reconciler, getInitialPollPod(reconciler, dataImportCron), 0, "Poll Complete\nDigest: "+testDigest1)
+		reconcileDataImportCron(reconciler)
+
+		Expect(getInitialPollPod(reconciler, dataImportCron)).To(BeNil())
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.LastExecutionTimestamp.Unix()).To(Equal(finishedAt.Unix()))
+		Expect(dataImportCron.Status.Imports).To(HaveLen(1))
+		Expect(dataImportCron.Status.Imports[0].Digest).To(Equal(testDigest1))
+		Expect(dataImportCron.Status.Imports[0].DataVolumeName).To(Equal("fedora-111111111111"))
+
+		dataVolume := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "fedora-111111111111", Namespace: "default"}, dataVolume)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(dataVolume, dataImportCron)).To(BeTrue())
+		Expect(dataVolume.Spec.Source.Registry.URL).To(Equal("docker://quay.io/containerdisks/fedora@" + testDigest1))
+		Expect(dataVolume.Annotations[AnnDataImportCron]).To(Equal("fedora"))
+
+		// The poll pod is not created again once the source was polled
+		reconcileDataImportCron(reconciler)
+		Expect(getInitialPollPod(reconciler, dataImportCron)).To(BeNil())
+	})
+
+	It("Should not import again the digest of the latest import", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Status.Imports = []cdiv1.DataImportCronImport{{DataVolumeName: "fedora-111111111111", Digest: testDigest1, Timestamp: metav1.Now()}}
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		reconciler := createDataImportCronReconciler(dataImportCron, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		createCronJobPollPod(reconciler, dataImportCron, 0, "Poll Complete\nDigest: "+testDigest1)
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.Imports).To(HaveLen(1))
+		dataVolumes := &cdiv1.DataVolumeList{}
+		Expect(reconciler.client.List(context.TODO(), dataVolumes, client.InNamespace("default"))).To(Succeed())
+		Expect(dataVolumes.Items).To(HaveLen(1))
+	})
+
+	It("Should record the failure of a poll", func() {
+		dataImportCron := createDataImportCron("fedora")
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		completePollPod(reconciler, getInitialPollPod(reconciler, dataImportCron), 1, "Unable to poll the registry image: unauthorized")
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.LastExecutionTimestamp).ToNot(BeNil())
+		Expect(dataImportCron.Status.Imports).To(BeEmpty())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronPollFailed))
+		Expect(event).To(ContainSubstring("unauthorized"))
+	})
+
+	It("Should point the DataSource at the latest import that succeeded", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Status.Imports = []cdiv1.DataImportCronImport{
+			{DataVolumeName: "fedora-222222222222", Digest: testDigest2, Timestamp: metav1.Now()},
+			{DataVolumeName: "fedora-111111111111", Digest: testDigest1, Timestamp: metav1.Now()},
+		}
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		reconciler := createDataImportCronReconciler(dataImportCron,
+			createDataImportCronDataVolume(dataImportCron, testDigest2, cdiv1.ImportInProgress),
+			createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+
+		dataSource := getDataSource(reconciler, "fedora")
+		Expect(metav1.IsControlledBy(dataSource, dataImportCron)).To(BeTrue())
+		Expect(dataSource.Spec.Source.PVC.Name).To(Equal("fedora-111111111111"))
+		Expect(dataSource.Spec.Source.PVC.Namespace).To(Equal("default"))
+		Expect(getDataImportCron(reconciler, "fedora").Status.LastImportTimestamp).ToNot(BeNil())
+		Expect(getDataImportCron(reconciler, "fedora").Status.CurrentDigest).To(Equal(testDigest1))
+
+		dataVolume := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "fedora-222222222222", Namespace: "default"}, dataVolume)
+		Expect(err).ToNot(HaveOccurred())
+		dataVolume.Status.Phase = cdiv1.Succeeded
+		Expect(reconciler.client.Update(context.TODO(), dataVolume)).To(Succeed())
+		reconcileDataImportCron(reconciler)
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-222222222222"))
+	})
+
+	It("Should drop the imports whose DataVolume was deleted", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Status.Imports = []cdiv1.DataImportCronImport{{DataVolumeName: "fedora-111111111111", Digest: testDigest1, Timestamp: metav1.Now()}}
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		Expect(getDataImportCron(reconciler, "fedora").Status.Imports).To(BeEmpty())
+	})
+
+	It("Should not take over a DataSource controlled by another object", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Status.Imports = []cdiv1.DataImportCronImport{{DataVolumeName: "fedora-111111111111", Digest: testDigest1, Timestamp: metav1.Now()}}
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		other := createDataImportCron("other")
+		dataSource := &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "fedora",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(other, cdiv1.SchemeGroupVersion.WithKind("DataImportCron"))},
+			},
+			Spec: cdiv1.DataSourceSpec{Source: cdiv1.DataSourceSource{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "default", Name: "other-pvc"}}},
+		}
+		reconciler := createDataImportCronReconciler(dataImportCron, dataSource, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("other-pvc"))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronDataSourceConflict))
+	})
+})
+
+var _ = Describe("DataImportCron pinning and rollback", func() {
+	var dataImportCron *cdiv1.DataImportCron
+
+	// The imports of the digests 3 to 1, the latest first, the DataSource pointing at the latest
+	BeforeEach(func() {
+		dataImportCron = createDataImportCron("fedora")
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		dataImportCron.Status.CurrentDigest = testDigest3
+		for _, digest := range []string{testDigest3, testDigest2, testDigest1} {
+			dataImportCron.Status.Imports = append(dataImportCron.Status.Imports, cdiv1.DataImportCronImport{
+				DataVolumeName: dataImportCronDataVolumeName(dataImportCron, digest),
+				Digest:         digest,
+				Timestamp:      metav1.Now(),
+			})
+		}
+	})
+
+	createReconciler := func(phases ...cdiv1.DataVolumePhase) *DataImportCronReconciler {
+		objects := []runtime.Object{dataImportCron}
+		for i, phase := range phases {
+			objects = append(objects, createDataImportCronDataVolume(dataImportCron, dataImportCron.Status.Imports[i].Digest, phase))
+		}
+		return createDataImportCronReconciler(objects...)
+	}
+
+	It("Should point the DataSource at the pinned import", func() {
+		dataImportCron.Spec.PinnedDigest = testDigest1
+		dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+		reconciler := createReconciler(cdiv1.Succeeded, cdiv1.Succeeded, cdiv1.Succeeded)
+		reconcileDataImportCron(reconciler)
+
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-111111111111"))
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.CurrentDigest).To(Equal(testDigest1))
+		// The latest import and the pinned one are retained
+		Expect(dataImportCron.Status.Imports).To(HaveLen(2))
+		Expect(dataImportCron.Status.Imports[0].Digest).To(Equal(testDigest3))
+		Expect(dataImportCron.Status.Imports[1].Digest).To(Equal(testDigest1))
+	})
+
+	It("Should import the pinned digest again when its import is not retained", func() {
+		dataImportCron.Status.Imports = dataImportCron.Status.Imports[:1]
+		reconciler := createReconciler(cdiv1.Succeeded)
+		reconcileDataImportCron(reconciler)
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		dataImportCron.Spec.PinnedDigest = testDigest1
+		Expect(reconciler.client.Update(context.TODO(), dataImportCron)).To(Succeed())
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.Imports).To(HaveLen(2))
+		Expect(dataImportCron.Status.Imports[1].Digest).To(Equal(testDigest1))
+		dataVolume := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "fedora-111111111111", Namespace: "default"}, dataVolume)
+		Expect(err).ToNot(HaveOccurred())
+		// The DataSource stays on the latest import while the pinned one is in progress
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-333333333333"))
+		Expect(dataImportCron.Status.CurrentDigest).To(Equal(testDigest3))
+
+		dataVolume.Status.Phase = cdiv1.Succeeded
+		Expect(reconciler.client.Update(context.TODO(), dataVolume)).To(Succeed())
+		reconcileDataImportCron(reconciler)
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-111111111111"))
+	})
+
+	It("Should roll back to the previous import that succeeded", func() {
+		dataImportCron.Annotations = map[string]string{AnnDataImportCronRollback: "true"}
+		reconciler := createReconciler(cdiv1.Succeeded, cdiv1.Failed, cdiv1.Succeeded)
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Annotations).ToNot(HaveKey(AnnDataImportCronRollback))
+		Expect(dataImportCron.Spec.PinnedDigest).To(Equal(testDigest1))
+		Expect(dataImportCron.Status.CurrentDigest).To(Equal(testDigest1))
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-111111111111"))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronRolledBack))
+	})
+
+	It("Should report a rollback without a previous import", func() {
+		dataImportCron.Status.Imports = dataImportCron.Status.Imports[:1]
+		dataImportCron.Annotations = map[string]string{AnnDataImportCronRollback: "true"}
+		reconciler := createReconciler(cdiv1.Succeeded)
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Annotations).ToNot(HaveKey(AnnDataImportCronRollback))
+		Expect(dataImportCron.Spec.PinnedDigest).To(BeEmpty())
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal("fedora-333333333333"))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronRollbackFailed))
+	})
+})
+
+var _ = Describe("DataImportCron notification", func() {
+	var dataImportCron *cdiv1.DataImportCron
+
+	BeforeEach(func() {
+		dataImportCron = createDataImportCron("fedora")
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		dataImportCron.Status.Imports = []cdiv1.DataImportCronImport{{DataVolumeName: "fedora-111111111111", Digest: testDigest1, Timestamp: metav1.Now()}}
+	})
+
+	getNotificationPod := func(reconciler *DataImportCronReconciler) *corev1.Pod {
+		pod := &corev1.Pod{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronNotificationName(dataImportCron), Namespace: "default"}, pod)).To(Succeed())
+		return pod
+	}
+
+	notificationPodExists := func(reconciler *DataImportCronReconciler) bool {
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronNotificationName(dataImportCron), Namespace: "default"}, &corev1.Pod{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("Should notify the webhook once of a new golden image from a notification pod", func() {
+		dataImportCron.Spec.Notification = &cdiv1.DataImportCronNotification{URL: "https://webhook.example.com/notify", CertConfigMap: "webhook-ca"}
+		reconciler := createDataImportCronReconciler(dataImportCron, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+
+		Expect(getDataImportCron(reconciler, "fedora").Status.NotifiedDigest).To(BeEmpty())
+		pod := getNotificationPod(reconciler)
+		Expect(pod.Labels).ToNot(HaveKey(LabelDataImportCron))
+		Expect(pod.Annotations[AnnDataImportCron]).To(Equal("fedora"))
+		Expect(pod.Annotations[AnnDataImportCronDigest]).To(Equal(testDigest1))
+		env := pollEnv(pod.Spec)
+		Expect(env[common.ImporterEndpoint]).To(Equal("https://webhook.example.com/notify"))
+		notification := dataImportCronNotification{}
+		Expect(json.Unmarshal([]byte(env[common.ImporterNotification]), &notification)).To(Succeed())
+		Expect(notification).To(Equal(dataImportCronNotification{
+			DataImportCron: "fedora",
+			Namespace:      "default",
+			DataSource:     "fedora",
+			PVC:            "fedora-111111111111",
+			Digest:         testDigest1,
+		}))
+
+		completePollPod(reconciler, pod, 0, "Notification Complete")
+		reconcileDataImportCron(reconciler)
+		Expect(getDataImportCron(reconciler, "fedora").Status.NotifiedDigest).To(Equal(testDigest1))
+		Expect(getDataSource(reconciler, "fedora").Annotations[AnnDataImportCronDigest]).To(Equal(testDigest1))
+		Expect(notificationPodExists(reconciler)).To(BeFalse())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronGoldenImageUpdated))
+
+		reconcileDataImportCron(reconciler)
+		Expect(notificationPodExists(reconciler)).To(BeFalse())
+	})
+
+	It("Should notify the webhook again when the notification failed", func() {
+		dataImportCron.Spec.Notification = &cdiv1.DataImportCronNotification{URL: "https://webhook.example.com/notify"}
+		reconciler := createDataImportCronReconciler(dataImportCron, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+
+		completePollPod(reconciler, getNotificationPod(reconciler), 1, "Unable to notify the webhook: the webhook replied 503 Service Unavailable")
+		result := reconcileDataImportCron(reconciler)
+		Expect(result.RequeueAfter).To(Equal(notificationRetryInterval))
+		Expect(getDataImportCron(reconciler, "fedora").Status.NotifiedDigest).To(BeEmpty())
+		Expect(notificationPodExists(reconciler)).To(BeFalse())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronNotificationFailed))
+		Expect(event).To(ContainSubstring("503 Service Unavailable"))
+
+		reconcileDataImportCron(reconciler)
+		Expect(notificationPodExists(reconciler)).To(BeTrue())
+	})
+
+	It("Should replace the notification pod of a former golden image", func() {
+		dataImportCron.Spec.Notification = &cdiv1.DataImportCronNotification{URL: "https://webhook.example.com/notify"}
+		reconciler := createDataImportCronReconciler(dataImportCron, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+		pod := getNotificationPod(reconciler)
+		pod.Annotations[AnnDataImportCronDigest] = testDigest2
+		Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+
+		reconcileDataImportCron(reconciler)
+		Expect(notificationPodExists(reconciler)).To(BeFalse())
+		reconcileDataImportCron(reconciler)
+		Expect(getNotificationPod(reconciler).Annotations[AnnDataImportCronDigest]).To(Equal(testDigest1))
+	})
+
+	It("Should report a new golden image by an event without a webhook", func() {
+		reconciler := createDataImportCronReconciler(dataImportCron, createDataImportCronDataVolume(dataImportCron, testDigest1, cdiv1.Succeeded))
+		reconcileDataImportCron(reconciler)
+
+		Expect(getDataImportCron(reconciler, "fedora").Status.NotifiedDigest).To(Equal(testDigest1))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronGoldenImageUpdated))
+	})
+})
+
+var _ = Describe("DataImportCron garbage collection", func() {
+	var (
+		dataImportCron *cdiv1.DataImportCron
+		dataVolumes    []runtime.Object
+	)
+
+	// The imports of the digests 4 to 1, the latest first, one hour apart
+	BeforeEach(func() {
+		dataImportCron = createDataImportCron("fedora")
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		dataVolumes = nil
+		for i, digest := range []string{testDigest4, testDigest3, testDigest2, testDigest1} {
+			dataVolume := createDataImportCronDataVolume(dataImportCron, digest, cdiv1.Succeeded)
+			dataVolumes = append(dataVolumes, dataVolume)
+			dataImportCron.Status.Imports = append(dataImportCron.Status.Imports, cdiv1.DataImportCronImport{
+				DataVolumeName: dataVolume.Name,
+				Digest:         digest,
+				Timestamp:      metav1.Time{Time: time.Now().Add(-time.Duration(i) * time.Hour)},
+			})
+		}
+	})
+
+	importDigests := func(reconciler *DataImportCronReconciler) []string {
+		digests := []string{}
+		for _, dataImportCronImport := range getDataImportCron(reconciler, "fedora").Status.Imports {
+			digests = append(digests, dataImportCronImport.Digest)
+		}
+		return digests
+	}
+
+	dataVolumeExists := func(reconciler *DataImportCronReconciler, digest string) bool {
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronDataVolumeName(dataImportCron, digest), Namespace: "default"}, &cdiv1.DataVolume{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("Should keep the 3 latest imports by default", func() {
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron)...)
+		reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(Equal([]string{testDigest4, testDigest3, testDigest2}))
+		Expect(dataVolumeExists(reconciler, testDigest1)).To(BeFalse())
+		Expect(dataVolumeExists(reconciler, testDigest2)).To(BeTrue())
+	})
+
+	It("Should keep the number of imports to keep", func() {
+		dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron)...)
+		reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(Equal([]string{testDigest4}))
+		for _, digest := range []string{testDigest3, testDigest2, testDigest1} {
+			Expect(dataVolumeExists(reconciler, digest)).To(BeFalse())
+		}
+	})
+
+	It("Should keep the imports newer than the retention period", func() {
+		dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+		dataImportCron.Spec.RetentionPeriod = &metav1.Duration{Duration: 90 * time.Minute}
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron)...)
+		result := reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(Equal([]string{testDigest4, testDigest3}))
+		Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+	})
+
+	It("Should keep every import when the garbage collection is disabled", func() {
+		never := cdiv1.DataImportCronGarbageCollectNever
+		dataImportCron.Spec.GarbageCollect = &never
+		dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron)...)
+		reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(HaveLen(4))
+	})
+
+	It("Should keep the import the DataSource points at", func() {
+		dataImportCron.Spec.ImportsToKeep = &[]int32{1}[0]
+		for _, obj := range dataVolumes[:3] {
+			obj.(*cdiv1.DataVolume).Status.Phase = cdiv1.ImportInProgress
+		}
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron)...)
+		reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(Equal([]string{testDigest4, testDigest1}))
+		Expect(getDataSource(reconciler, "fedora").Spec.Source.PVC.Name).To(Equal(dataImportCronDataVolumeName(dataImportCron, testDigest1)))
+	})
+
+	It("Should keep an import whose PVC is in use", func() {
+		dataImportCron.Spec.ImportsToKeep = &[]int32{3}[0]
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone-source", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{
+					Name: "disk",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: dataImportCronDataVolumeName(dataImportCron, testDigest1)},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron, pod)...)
+		result := reconcileDataImportCron(reconciler)
+		Expect(importDigests(reconciler)).To(HaveLen(4))
+		Expect(dataVolumeExists(reconciler, testDigest1)).To(BeTrue())
+		Expect(result.RequeueAfter).To(Equal(dataVolumeInUseRetryInterval))
+	})
+
+	It("Should delete the VolumeSnapshots of the PVC of a deleted import", func() {
+		pvcName := dataImportCronDataVolumeName(dataImportCron, testDigest1)
+		snapshot := createDataImportCronSnapshot("fedora-snapshot", pvcName)
+		otherSnapshot := createDataImportCronSnapshot("other-snapshot", "other-pvc")
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron, snapshot, otherSnapshot)...)
+		reconcileDataImportCron(reconciler)
+
+		Expect(dataVolumeExists(reconciler, testDigest1)).To(BeFalse())
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "fedora-snapshot", Namespace: "default"}, &snapshotv1.VolumeSnapshot{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "other-snapshot", Namespace: "default"}, &snapshotv1.VolumeSnapshot{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should keep an import while a smart clone takes a snapshot of its PVC", func() {
+		snapshot := createDataImportCronSnapshot("smart-clone", dataImportCronDataVolumeName(dataImportCron, testDigest1))
+		snapshot.Annotations = map[string]string{AnnSmartCloneRequest: "true"}
+		reconciler := createDataImportCronReconciler(append(dataVolumes, dataImportCron, snapshot)...)
+		reconcileDataImportCron(reconciler)
+
+		Expect(importDigests(reconciler)).To(HaveLen(4))
+		Expect(dataVolumeExists(reconciler, testDigest1)).To(BeTrue())
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "smart-clone", Namespace: "default"}, &snapshotv1.VolumeSnapshot{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("DataImportCron http and s3 sources", func() {
+	const (
+		httpPoll = "Poll Complete\nETag: \"5d8c72a5edda8\"\nLast-Modified: Tue, 13 Oct 2026 08:00:00 GMT"
+		s3Poll   = "Poll Complete\nETag: \"9b2cf535f27731c974343645a3985328\"\nVersionId: 3HL4kqtJlcpXroDTDmJ"
+	)
+
+	It("Should import the new version of an http source and poll it with its validators", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "https://www.example.com/fedora.qcow2"}}
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		completePollPod(reconciler, getInitialPollPod(reconciler, dataImportCron), 0, httpPoll)
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.Imports).To(HaveLen(1))
+		digest := dataImportCron.Status.Imports[0].Digest
+		Expect(digest).To(HavePrefix("sha256:"))
+		dataVolume := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronDataVolumeName(dataImportCron, digest), Namespace: "default"}, dataVolume)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataVolume.Spec.Source.HTTP.URL).To(Equal("https://www.example.com/fedora.qcow2"))
+		Expect(dataVolume.Annotations[AnnSourceETag]).To(Equal(`"5d8c72a5edda8"`))
+		Expect(dataVolume.Annotations[AnnSourceLastModified]).To(Equal("Tue, 13 Oct 2026 08:00:00 GMT"))
+
+		// The CronJob polls the source with conditional requests
+		reconcileDataImportCron(reconciler)
+		cronJob := &batchv1beta1.CronJob{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronPollName(dataImportCron), Namespace: "default"}, cronJob)
+		Expect(err).ToNot(HaveOccurred())
+		env := pollEnv(cronJob.Spec.JobTemplate.Spec.Template.Spec)
+		Expect(env[common.ImporterSourceETag]).To(Equal(`"5d8c72a5edda8"`))
+		Expect(env[common.ImporterSourceLastModified]).To(Equal("Tue, 13 Oct 2026 08:00:00 GMT"))
+
+		// An unchanged source is not imported again
+		createCronJobPollPod(reconciler, dataImportCron, 0, httpPoll)
+		reconcileDataImportCron(reconciler)
+		Expect(getDataImportCron(reconciler, "fedora").Status.Imports).To(HaveLen(1))
+	})
+
+	It("Should import the version of an s3 object reported by the poll", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "https://s3.example.com/images/fedora.qcow2"}}
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+		completePollPod(reconciler, getInitialPollPod(reconciler, dataImportCron), 0, s3Poll)
+		reconcileDataImportCron(reconciler)
+
+		dataImportCron = getDataImportCron(reconciler, "fedora")
+		Expect(dataImportCron.Status.Imports).To(HaveLen(1))
+		dataVolume := &cdiv1.DataVolume{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCron.Status.Imports[0].DataVolumeName, Namespace: "default"}, dataVolume)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataVolume.Spec.Source.S3.ObjectVersionID).To(Equal("3HL4kqtJlcpXroDTDmJ"))
+		Expect(dataVolume.Annotations[AnnSourceVersionID]).To(Equal("3HL4kqtJlcpXroDTDmJ"))
+	})
+
+	It("Should not import again a pinned http version which is not retained", func() {
+		dataImportCron := createDataImportCron("fedora")
+		dataImportCron.Spec.Template.Spec.Source = cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "https://www.example.com/fedora.qcow2"}}
+		dataImportCron.Spec.PinnedDigest = testDigest1
+		dataImportCron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now()}
+		reconciler := createDataImportCronReconciler(dataImportCron)
+		reconcileDataImportCron(reconciler)
+
+		Expect(getDataImportCron(reconciler, "fedora").Status.Imports).To(BeEmpty())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(DataImportCronPinnedDigestNotRetained))
+	})
+})
+
+var _ = Describe("DataImportCron poll pod", func() {
+	It("Should not report a result while the poll pod runs", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+		Expect(pollPodResult(pod)).To(BeNil())
+	})
+
+	It("Should report a poll without digest as failed", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "Poll Complete"}}},
+		}}}
+		result := pollPodResult(pod)
+		Expect(result).ToNot(BeNil())
+		Expect(result.err).To(HaveOccurred())
+	})
+
+	It("Should report the digest of the validators of an http source", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "Poll Complete\nETag: \"abc\""}}},
+		}}}
+		result := pollPodResult(pod)
+		Expect(result.err).ToNot(HaveOccurred())
+		Expect(result.digest).To(Equal(sourceValidatorsDigest(map[string]string{AnnSourceETag: `"abc"`})))
+		Expect(result.validators).To(Equal(map[string]string{AnnSourceETag: `"abc"`}))
+
+		pod.Status.ContainerStatuses[0].State.Terminated.Message = "Poll Complete\nETag: \"def\""
+		Expect(pollPodResult(pod).digest).ToNot(Equal(result.digest))
+	})
+
+	It("Should pin the registry url to the digest", func() {
+		url, err := pinRegistryURL("docker://fedora:33", testDigest1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).To(Equal("docker://docker.io/library/fedora@" + testDigest1))
+		url, err = pinRegistryURL("docker://registry.example.com:5000/disks/fedora@"+testDigest2, testDigest1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).To(Equal("docker://registry.example.com:5000/disks/fedora@" + testDigest1))
+	})
+})
+
+func createDataImportCronReconciler(objects ...runtime.Object) *DataImportCronReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
+	batchv1beta1.AddToScheme(s)
+	s.AddKnownTypeWithName(virtualMachineListKind.GroupVersion().WithKind("VirtualMachine"), &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(virtualMachineListKind, &unstructured.UnstructuredList{})
+
+	objs = append(objs, MakeEmptyCDICR())
+	objs = append(objs, MakeEmptyCDIConfigSpec(common.ConfigName))
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	rec := record.NewFakeRecorder(10)
+	return &DataImportCronReconciler{
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            dataImportCronLog,
+		recorder:       rec,
+		featureGates:   featuregates.NewFeatureGates(cl),
+		image:          "test/importer",
+		verbose:        "1",
+		pullPolicy:     string(corev1.PullIfNotPresent),
+	}
+}
+
+func createDataImportCron(name string) *cdiv1.DataImportCron {
+	return &cdiv1.DataImportCron{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("default-" + name),
+		},
+		Spec: cdiv1.DataImportCronSpec{
+			Template: cdiv1.DataVolume{
+				Spec: cdiv1.DataVolumeSpec{
+					Source: cdiv1.DataVolumeSource{
+						Registry: &cdiv1.DataVolumeSourceRegistry{
+							URL: "docker://quay.io/containerdisks/fedora:latest",
+						},
+					},
+					PVC: &corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("5Gi"),
+							},
+						},
+					},
+				},
+			},
+			Schedule:          "0 */12 * * *",
+			ManagedDataSource: name,
+		},
+	}
+}
+
+// createDataImportCronDataVolume returns the DataVolume of the import of the digest, in the phase
+func createDataImportCronDataVolume(dataImportCron *cdiv1.DataImportCron, digest string, phase cdiv1.DataVolumePhase) *cdiv1.DataVolume {
+	dataVolume, err := newDataImportCronDataVolume(dataImportCron, digest, nil)
+	Expect(err).ToNot(HaveOccurred())
+	dataVolume.Status.Phase = phase
+	if phase == cdiv1.Succeeded {
+		dataVolume.Status.Conditions = updateCondition(dataVolume.Status.Conditions, cdiv1.DataVolumeReady, corev1.ConditionTrue, "", "")
+	}
+	return dataVolume
+}
+
+func createDataImportCronSnapshot(name, pvcName string) *snapshotv1.VolumeSnapshot {
+	return &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+		},
+	}
+}
+
+func reconcileDataImportCron(reconciler *DataImportCronReconciler) reconcile.Result {
+	result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "fedora", Namespace: "default"}})
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+func getDataImportCron(reconciler *DataImportCronReconciler, name string) *cdiv1.DataImportCron {
+	dataImportCron := &cdiv1.DataImportCron{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, dataImportCron)
+	Expect(err).ToNot(HaveOccurred())
+	return dataImportCron
+}
+
+func getDataSource(reconciler *DataImportCronReconciler, name string) *cdiv1.DataSource {
+	dataSource := &cdiv1.DataSource{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, dataSource)
+	Expect(err).ToNot(HaveOccurred())
+	return dataSource
+}
+
+func getInitialPollPod(reconciler *DataImportCronReconciler, dataImportCron *cdiv1.DataImportCron) *corev1.Pod {
+	pod := &corev1.Pod{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: dataImportCronPollName(dataImportCron), Namespace: "default"}, pod)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	Expect(err).ToNot(HaveOccurred())
+	return pod
+}
+
+// createCronJobPollPod creates a terminated poll pod of the CronJob of the DataImportCron
+func createCronJobPollPod(reconciler *DataImportCronReconciler, dataImportCron *cdiv1.DataImportCron, exitCode int32, message string) {
+	pod, err := reconciler.newPollPod(dataImportCron)
+	Expect(err).ToNot(HaveOccurred())
+	pod.Name = dataImportCronPollName(dataImportCron) + "-1234567890-abcde"
+	pod.OwnerReferences = nil
+	Expect(reconciler.client.Create(context.TODO(), pod)).To(Succeed())
+	completePollPod(reconciler, pod, exitCode, message)
+}
+
+// completePollPod terminates the poll pod with the exit code and message, and returns the time it finished
+func completePollPod(reconciler *DataImportCronReconciler, pod *corev1.Pod, exitCode int32, message string) metav1.Time {
+	finishedAt := metav1.Now()
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message, FinishedAt: finishedAt}}},
+	}
+	Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+	return finishedAt
+}
+
+func pollEnv(podSpec corev1.PodSpec) map[string]string {
+	env := map[string]string{}
+	for _, envVar := range podSpec.Containers[0].Env {
+		env[envVar.Name] = envVar.Value
+	}
+	return env
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	dataSourceControllerAgentName = "datasource-controller"
+
+	// MessageDataSourceReady provides a const to form the message of a DataSource whose source is ready
+	MessageDataSourceReady = "PVC %s/%s is ready"
+	// MessageDataSourceFallback provides a const to form the message of a DataSource using a fallback
+	MessageDataSourceFallback = "Fallback PVC %s/%s is used: %s"
+	// MessageDataSourceNotFound provides a const to form the message of a DataSource whose PVC does not exist
+	MessageDataSourceNotFound = "PVC %s/%s not found"
+	// MessageDataSourceNotPopulated provides a const to form the message of a DataSource whose PVC is not populated yet
+	MessageDataSourceNotPopulated = "PVC %s/%s is not populated yet"
+	// MessageDataSourceNoSource provides a const to form the message of a DataSource without a PVC source
+	MessageDataSourceNoSource = "No PVC source"
+)
+
+// DataSourceReconciler members
+type DataSourceReconciler struct {
+	client client.Client
+	log    logr.Logger
+}
+
+// NewDataSourceController creates a new instance of the DataSource controller, which reports whether the source or
+// a fallback of the DataSources is ready
+func NewDataSourceController(mgr manager.Manager, log logr.Logger) (controller.Controller, error) {
+	reconciler := &DataSourceReconciler{
+		client: mgr.GetClient(),
+		log:    log.WithName(dataSourceControllerAgentName),
+	}
+	dataSourceController, err := controller.New(dataSourceControllerAgentName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDataSourceControllerWatches(mgr, dataSourceController); err != nil {
+		return nil, err
+	}
+	return dataSourceController, nil
+}
+
+func addDataSourceControllerWatches(mgr manager.Manager, dataSourceController controller.Controller) error {
+	if err := dataSourceController.Watch(&source.Kind{Type: &cdiv1.DataSource{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	// The PVCs of the sources, and the DataVolumes populating them, may be in another namespace than the DataSources
+	if err := dataSourceController.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return mapPvcToDataSources(mgr.GetClient(), obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}); err != nil {
+		return err
+	}
+	if err := dataSourceController.Watch(&source.Kind{Type: &cdiv1.DataVolume{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return mapPvcToDataSources(mgr.GetClient(), obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mapPvcToDataSources returns the requests of the DataSources whose source or fallbacks reference the PVC
+func mapPvcToDataSources(c client.Client, namespace, name string) []reconcile.Request {
+	dataSources := &cdiv1.DataSourceList{}
+	if err := c.List(context.TODO(), dataSources); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, dataSource := range dataSources.Items {
+		for _, source := range getDataSourceSources(&dataSource) {
+			if source.PVC != nil && source.PVC.Namespace == namespace && source.PVC.Name == name {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dataSource.Namespace, Name: dataSource.Name}})
+				break
+			}
+		}
+	}
+	return reqs
+}
+
+// getDataSourceSources returns the source of the DataSource followed by its fallbacks
+func getDataSourceSources(dataSource *cdiv1.DataSource) []cdiv1.DataSourceSource {
+	return append([]cdiv1.DataSourceSource{dataSource.Spec.Source}, dataSource.Spec.Fallbacks...)
+}
+
+// Reconcile the reconcile loop for the DataSource object.
+func (r *DataSourceReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataSource", req.NamespacedName)
+	dataSource := &cdiv1.DataSource{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, dataSource); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if dataSource.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	dataSourceCopy := dataSource.DeepCopy()
+	var readySource *cdiv1.DataSourceSource
+	var reason, message string
+	for i, source := range getDataSourceSources(dataSource) {
+		sourceReason, sourceMessage, err := r.getSourceReadiness(&source)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if i == 0 {
+			reason, message = sourceReason, sourceMessage
+		}
+		if sourceReason != cdiv1.DataSourceReady {
+			continue
+		}
+		readySource = source.DeepCopy()
+		if i > 0 {
+			reason = cdiv1.DataSourceFallback
+			message = fmt.Sprintf(MessageDataSourceFallback, source.PVC.Namespace, source.PVC.Name, message)
+		}
+		break
+	}
+
+	status := corev1.ConditionFalse
+	if readySource != nil {
+		status = corev1.ConditionTrue
+	}
+	dataSourceCopy.Status.Source = readySource
+	dataSourceCopy.Status.Conditions = updateCondition(dataSourceCopy.Status.Conditions, cdiv1.DataVolumeReady, status, message, reason)
+	if !reflect.DeepEqual(dataSource.Status, dataSourceCopy.Status) {
+		log.V(1).Info("Updating DataSource status", "reason", reason, "message", message)
+		if err := r.client.Update(context.TODO(), dataSourceCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// getSourceReadiness returns the reason and message of the Ready condition of the source, its PVC is ready once it is
+// bound and the DataVolume populating it succeeded
+func (r *DataSourceReconciler) getSourceReadiness(source *cdiv1.DataSourceSource) (string, string, error) {
+	if source.PVC == nil {
+		return cdiv1.DataSourceNoSource, MessageDataSourceNoSource, nil
+	}
+	namespace, name := source.PVC.Namespace, source.PVC.Name
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return cdiv1.DataSourceNotFound, fmt.Sprintf(MessageDataSourceNotFound, namespace, name), nil
+		}
+		return "", "", err
+	}
+	if pvc.DeletionTimestamp != nil {
+		return cdiv1.DataSourceNotFound, fmt.Sprintf(MessageDataSourceNotFound, namespace, name), nil
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return cdiv1.DataSourceNotPopulated, fmt.Sprintf(MessageDataSourceNotPopulated, namespace, name), nil
+	}
+	populated, err := IsPopulated(pvc, r.client)
+	if err != nil {
+		// The DataVolume was deleted, its PVC is being garbage collected
+		if k8serrors.IsNotFound(err) {
+			return cdiv1.DataSourceNotFound, fmt.Sprintf(MessageDataSourceNotFound, namespace, name), nil
+		}
+		return "", "", err
+	}
+	if !populated {
+		return cdiv1.DataSourceNotPopulated, fmt.Sprintf(MessageDataSourceNotPopulated, namespace, name), nil
+	}
+	return cdiv1.DataSourceReady, fmt.Sprintf(MessageDataSourceReady, namespace, name), nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var (
+	dataSourceLog = logf.Log.WithName("datasource-controller-test")
+)
+
+var _ = Describe("DataSource reconcile", func() {
+	It("Should do nothing and return nil when no DataSource exists", func() {
+		reconciler := createDataSourceReconciler()
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "no-datasource", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should be ready when the PVC of the source is bound", func() {
+		reconciler := createDataSourceReconciler(createDataSource("fedora", "fedora-1"), createPvc("fedora-1", "default", nil, nil))
+		dataSource := reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(Equal(&dataSource.Spec.Source))
+		expectDataSourceReady(dataSource, corev1.ConditionTrue, cdiv1.DataSourceReady, fmt.Sprintf(MessageDataSourceReady, "default", "fedora-1"))
+	})
+
+	It("Should not be ready while the DataVolume of the source is in progress", func() {
+		dv := newImportDataVolume("fedora-1")
+		dv.Status.Phase = cdiv1.ImportInProgress
+		pvc := createPvc("fedora-1", "default", nil, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		reconciler := createDataSourceReconciler(createDataSource("fedora", "fedora-1"), dv, pvc)
+		dataSource := reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(BeNil())
+		expectDataSourceReady(dataSource, corev1.ConditionFalse, cdiv1.DataSourceNotPopulated, fmt.Sprintf(MessageDataSourceNotPopulated, "default", "fedora-1"))
+
+		dv.Status.Phase = cdiv1.Succeeded
+		Expect(reconciler.client.Update(context.TODO(), dv)).To(Succeed())
+		dataSource = reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(Equal(&dataSource.Spec.Source))
+		expectDataSourceReady(dataSource, corev1.ConditionTrue, cdiv1.DataSourceReady, fmt.Sprintf(MessageDataSourceReady, "default", "fedora-1"))
+	})
+
+	It("Should use the first ready fallback when the PVC of the source is missing", func() {
+		dataSource := createDataSource("fedora", "fedora-2")
+		dataSource.Spec.Fallbacks = []cdiv1.DataSourceSource{
+			{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "default", Name: "fedora-1"}},
+			{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "golden-images", Name: "fedora"}},
+		}
+		reconciler := createDataSourceReconciler(dataSource, createPvc("fedora", "golden-images", nil, nil))
+		dataSource = reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(Equal(&dataSource.Spec.Fallbacks[1]))
+		expectDataSourceReady(dataSource, corev1.ConditionTrue, cdiv1.DataSourceFallback,
+			fmt.Sprintf(MessageDataSourceFallback, "golden-images", "fedora", fmt.Sprintf(MessageDataSourceNotFound, "default", "fedora-2")))
+
+		// The source is used again once it is ready
+		Expect(reconciler.client.Create(context.TODO(), createPvc("fedora-2", "default", nil, nil))).To(Succeed())
+		dataSource = reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(Equal(&dataSource.Spec.Source))
+		expectDataSourceReady(dataSource, corev1.ConditionTrue, cdiv1.DataSourceReady, fmt.Sprintf(MessageDataSourceReady, "default", "fedora-2"))
+	})
+
+	It("Should not be ready when neither the source nor the fallbacks are ready", func() {
+		dataSource := createDataSource("fedora", "fedora-2")
+		dataSource.Spec.Fallbacks = []cdiv1.DataSourceSource{{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "default", Name: "fedora-1"}}}
+		reconciler := createDataSourceReconciler(dataSource, createPendingPvc("fedora-1", "default", nil, nil))
+		dataSource = reconcileDataSource(reconciler, "fedora")
+		Expect(dataSource.Status.Source).To(BeNil())
+		expectDataSourceReady(dataSource, corev1.ConditionFalse, cdiv1.DataSourceNotFound, fmt.Sprintf(MessageDataSourceNotFound, "default", "fedora-2"))
+	})
+
+	It("Should not be ready without a PVC source", func() {
+		dataSource := createDataSource("fedora", "")
+		dataSource.Spec.Source = cdiv1.DataSourceSource{}
+		reconciler := createDataSourceReconciler(dataSource)
+		dataSource = reconcileDataSource(reconciler, "fedora")
+		expectDataSourceReady(dataSource, corev1.ConditionFalse, cdiv1.DataSourceNoSource, MessageDataSourceNoSource)
+	})
+
+	It("Should map the PVCs to the DataSources using them as source or fallback", func() {
+		dataSource := createDataSource("fedora", "fedora-2")
+		dataSource.Spec.Fallbacks = []cdiv1.DataSourceSource{{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "golden-images", Name: "fedora"}}}
+		reconciler := createDataSourceReconciler(dataSource, createDataSource("centos", "centos-1"))
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "fedora", Namespace: "default"}}
+		Expect(mapPvcToDataSources(reconciler.client, "default", "fedora-2")).To(ConsistOf(request))
+		Expect(mapPvcToDataSources(reconciler.client, "golden-images", "fedora")).To(ConsistOf(request))
+		Expect(mapPvcToDataSources(reconciler.client, "default", "fedora")).To(BeEmpty())
+	})
+})
+
+func createDataSourceReconciler(objects ...runtime.Object) *DataSourceReconciler {
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	return &DataSourceReconciler{
+		client: fake.NewFakeClientWithScheme(s, objects...),
+		log:    dataSourceLog,
+	}
+}
+
+func createDataSource(name, pvcName string) *cdiv1.DataSource {
+	return &cdiv1.DataSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: cdiv1.DataSourceSpec{
+			Source: cdiv1.DataSourceSource{
+				PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "default", Name: pvcName},
+			},
+		},
+	}
+}
+
+func reconcileDataSource(reconciler *DataSourceReconciler, name string) *cdiv1.DataSource {
+	_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}})
+	Expect(err).ToNot(HaveOccurred())
+	dataSource := &cdiv1.DataSource{}
+	Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, dataSource)).To(Succeed())
+	return dataSource
+}
+
+func expectDataSourceReady(dataSource *cdiv1.DataSource, status corev1.ConditionStatus, reason, message string) {
+	condition := findConditionByType(cdiv1.DataVolumeReady, dataSource.Status.Conditions)
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+	Expect(condition.Message).To(Equal(message))
+}
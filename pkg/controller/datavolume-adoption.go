@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// AnnAllowClaimAdoption is a DataVolume annotation adopting the existing PVC of the same name as already populated
+	// when set to "true"
+	AnnAllowClaimAdoption = AnnAPIGroup + "/storage.allowClaimAdoption"
+)
+
+// ClaimAdoptionAllowed returns true if the DataVolume asks to adopt its existing PVC, and the PVC is not controlled by
+// another object than a former DataVolume of the same name, as restored PVCs are
+func ClaimAdoptionAllowed(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) bool {
+	if dataVolume.GetAnnotations()[AnnAllowClaimAdoption] != "true" {
+		return false
+	}
+	owner := metav1.GetControllerOf(pvc)
+	return owner == nil || (owner.Kind == "DataVolume" && owner.Name == dataVolume.Name)
+}
+
+// adoptClaim marks the PVC populated for the DataVolume and makes the DataVolume its controller, in place of the
+// former DataVolume
+func (r *DatavolumeReconciler) adoptClaim(pvc *corev1.PersistentVolumeClaim, dataVolume *cdiv1.DataVolume) error {
+	pvcCopy := pvc.DeepCopy()
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ownerRef := range pvcCopy.OwnerReferences {
+		if ownerRef.Controller == nil || !*ownerRef.Controller {
+			ownerRefs = append(ownerRefs, ownerRef)
+		}
+	}
+	pvcCopy.OwnerReferences = ownerRefs
+	if pvcCopy.Annotations == nil {
+		pvcCopy.Annotations = make(map[string]string)
+	}
+	pvcCopy.Annotations[AnnPopulatedFor] = dataVolume.Name
+	if err := controllerutil.SetControllerReference(dataVolume, pvcCopy, r.scheme); err != nil {
+		return err
+	}
+	if err := r.client.Update(context.TODO(), pvcCopy); err != nil {
+		return err
+	}
+	pvcCopy.DeepCopyInto(pvc)
+	return nil
+}
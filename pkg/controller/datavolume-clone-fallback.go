@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// AnnSmartCloneFallbackReason is the PVC annotation holding the reason a clone falls back to a host-assisted copy
+	AnnSmartCloneFallbackReason = AnnAPIGroup + "/storage.clone.fallback.reason"
+	// AnnSmartCloneFallbackMessage is the PVC annotation explaining why a clone falls back to a host-assisted copy
+	AnnSmartCloneFallbackMessage = AnnAPIGroup + "/storage.clone.fallback.message"
+
+	// SmartCloneNoSnapshotCRDs provides a const to indicate the VolumeSnapshot CRDs are not installed
+	SmartCloneNoSnapshotCRDs = "NoSnapshotCRDs"
+	// SmartCloneNoVolumeSnapshotClass provides a const to indicate no VolumeSnapshotClass matches the source storage class
+	SmartCloneNoVolumeSnapshotClass = "NoVolumeSnapshotClass"
+	// SmartCloneStorageClassMismatch provides a const to indicate the source and target storage classes differ
+	SmartCloneStorageClassMismatch = "StorageClassMismatch"
+	// SmartCloneVolumeModeMismatch provides a const to indicate the source and target volume modes differ
+	SmartCloneVolumeModeMismatch = "VolumeModeMismatch"
+	// SmartCloneCrossNamespace provides a const to indicate the source and target are in different namespaces
+	SmartCloneCrossNamespace = "CrossNamespace"
+	// SmartCloneSizeMismatch provides a const to indicate the target is smaller than the source
+	SmartCloneSizeMismatch = "SizeMismatch"
+)
+
+// smartCloneFallbackError is returned when the cluster lacks the capability to smart-clone a DataVolume, its reason
+// and message are recorded in the SmartCloneFallback condition of the DataVolume
+type smartCloneFallbackError struct {
+	reason  string
+	message string
+}
+
+func (e *smartCloneFallbackError) Error() string { return e.message }
+
+func newSmartCloneFallbackError(reason, format string, args ...interface{}) error {
+	return &smartCloneFallbackError{reason: reason, message: fmt.Sprintf(format, args...)}
+}
+
+// setSmartCloneFallback records on the new PVC of the DataVolume why it is not smart-cloned
+func setSmartCloneFallback(pvc *corev1.PersistentVolumeClaim, fallback *smartCloneFallbackError) {
+	pvc.Annotations[AnnSmartCloneFallbackReason] = fallback.reason
+	pvc.Annotations[AnnSmartCloneFallbackMessage] = fallback.message
+}
+
+func updateSmartCloneFallbackCondition(conditions []cdiv1.DataVolumeCondition, anno map[string]string) []cdiv1.DataVolumeCondition {
+	if reason, ok := anno[AnnSmartCloneFallbackReason]; ok {
+		conditions = updateCondition(conditions, cdiv1.DataVolumeSmartCloneFallback, corev1.ConditionTrue, anno[AnnSmartCloneFallbackMessage], reason)
+	}
+	return conditions
+}
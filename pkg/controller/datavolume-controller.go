@@ -19,9 +19,12 @@ package controller
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -37,6 +40,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	extclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -117,6 +121,8 @@ const (
 	MessageErrClaimLost = "PVC %s lost"
 	// MessageImportScheduled provides a const to form import is scheduled message
 	MessageImportScheduled = "Import into %s scheduled"
+	// MessageImportQueued provides a const to form import is queued message
+	MessageImportQueued = "Import into %s queued, the maximum number of parallel imports is reached"
 	// MessageImportInProgress provides a const to form import is in progress message
 	MessageImportInProgress = "Import into %s in progress"
 	// MessageImportFailed provides a const to form import has failed message
@@ -125,6 +131,8 @@ const (
 	MessageImportSucceeded = "Successfully imported into PVC %s"
 	// MessageImportPaused provides a const for a "multistage import paused" message
 	MessageImportPaused = "Multistage import into PVC %s is paused"
+	// MessageImportPausedByAnnotation provides a const for a "import paused by annotation" message
+	MessageImportPausedByAnnotation = "Import into PVC %s is paused"
 	// MessageCloneScheduled provides a const to form clone is scheduled message
 	MessageCloneScheduled = "Cloning from %s/%s into %s/%s scheduled"
 	// MessageCloneInProgress provides a const to form clone is in progress message
@@ -133,6 +141,14 @@ const (
 	MessageCloneFailed = "Cloning from %s/%s into %s/%s failed"
 	// MessageCloneSucceeded provides a const to form clone has succeeded message
 	MessageCloneSucceeded = "Successfully cloned from %s/%s into %s/%s"
+	// MessageNetworkCopyScheduled provides a const to form network copy is scheduled message
+	MessageNetworkCopyScheduled = "Copying from %s/%s into %s/%s over the network scheduled"
+	// MessageNetworkCopyInProgress provides a const to form network copy is in progress message
+	MessageNetworkCopyInProgress = "Copying from %s/%s into %s/%s over the network in progress"
+	// MessageNetworkCopyFailed provides a const to form network copy has failed message
+	MessageNetworkCopyFailed = "Copying from %s/%s into %s/%s over the network failed"
+	// MessageNetworkCopySucceeded provides a const to form network copy has succeeded message
+	MessageNetworkCopySucceeded = "Successfully copied from %s/%s into %s/%s over the network"
 	// MessageSmartCloneInProgress provides a const to form snapshot for smart-clone is in progress message
 	MessageSmartCloneInProgress = "Creating snapshot for smart-clone is in progress (for pvc %s/%s)"
 	// MessageSmartClonePVCInProgress provides a const to form snapshot for smart-clone is in progress message
@@ -158,12 +174,17 @@ type DataVolumeEvent struct {
 
 // DatavolumeReconciler members
 type DatavolumeReconciler struct {
-	client       client.Client
-	extClientSet extclientset.Interface
-	recorder     record.EventRecorder
-	scheme       *runtime.Scheme
-	log          logr.Logger
-	featureGates featuregates.FeatureGates
+	client         client.Client
+	uncachedClient client.Client
+	extClientSet   extclientset.Interface
+	recorder       record.EventRecorder
+	scheme         *runtime.Scheme
+	log            logr.Logger
+	featureGates   featuregates.FeatureGates
+	// importerImage, pullPolicy and verbose configure the probe pods of the import sources
+	importerImage string
+	pullPolicy    string
+	verbose       string
 }
 
 func pvcIsPopulated(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) bool {
@@ -172,15 +193,26 @@ func pvcIsPopulated(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) boo
 }
 
 // NewDatavolumeController creates a new instance of the datavolume controller.
-func NewDatavolumeController(mgr manager.Manager, extClientSet extclientset.Interface, log logr.Logger) (controller.Controller, error) {
+func NewDatavolumeController(mgr manager.Manager, extClientSet extclientset.Interface, log logr.Logger, importerImage, pullPolicy, verbose string) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
 	client := mgr.GetClient()
 	reconciler := &DatavolumeReconciler{
-		client:       client,
-		scheme:       mgr.GetScheme(),
-		extClientSet: extClientSet,
-		log:          log.WithName("datavolume-controller"),
-		recorder:     mgr.GetEventRecorderFor("datavolume-controller"),
-		featureGates: featuregates.NewFeatureGates(client),
+		client:         client,
+		uncachedClient: uncachedClient,
+		scheme:         mgr.GetScheme(),
+		extClientSet:   extClientSet,
+		log:            log.WithName("datavolume-controller"),
+		recorder:       mgr.GetEventRecorderFor("datavolume-controller"),
+		featureGates:   featuregates.NewFeatureGates(client),
+		importerImage:  importerImage,
+		pullPolicy:     pullPolicy,
+		verbose:        verbose,
 	}
 	datavolumeController, err := controller.New("datavolume-controller", mgr, controller.Options{
 		Reconciler: reconciler,
@@ -216,6 +248,13 @@ func addDatavolumeControllerWatches(mgr manager.Manager, datavolumeController co
 	}); err != nil {
 		return err
 	}
+	// The probe pods of the import sources
+	if err := datavolumeController.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.DataVolume{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -257,6 +296,11 @@ func (r *DatavolumeReconciler) Reconcile(req reconcile.Request) (reconcile.Resul
 				if err := r.addOwnerRef(pvc, datavolume); err != nil {
 					return reconcile.Result{}, err
 				}
+			} else if ClaimAdoptionAllowed(datavolume, pvc) {
+				log.Info("Adopting the existing PVC as populated")
+				if err := r.adoptClaim(pvc, datavolume); err != nil {
+					return reconcile.Result{}, err
+				}
 			} else {
 				msg := fmt.Sprintf(MessageResourceExists, pvc.Name)
 				r.recorder.Event(datavolume, corev1.EventTypeWarning, ErrResourceExists, msg)
@@ -272,11 +316,16 @@ func (r *DatavolumeReconciler) Reconcile(req reconcile.Request) (reconcile.Resul
 	}
 
 	if !pvcExists {
-		cloneStrategy, err := r.getCloneStrategy()
+		cloneStrategy, err := r.getCloneStrategy(datavolume)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		snapshotClassName, err := r.getSnapshotClassForSmartClone(datavolume)
+		var snapshotClassName string
+		if cloneStrategy == cdiv1.CloneStrategyCsiClone {
+			err = r.validateCsiClone(datavolume)
+		} else {
+			snapshotClassName, err = r.getSnapshotClassForSmartClone(datavolume)
+		}
 		if err == nil && cloneStrategy == cdiv1.CloneStrategySnapshot {
 			r.log.V(3).Info("Smart-Clone via Snapshot is available with Volume Snapshot Class", "snapshotClassName", snapshotClassName)
 			if requeue, err := r.sourceInUse(datavolume); requeue || err != nil {
@@ -294,11 +343,79 @@ func (r *DatavolumeReconciler) Reconcile(req reconcile.Request) (reconcile.Resul
 			}
 			return reconcile.Result{}, r.updateSmartCloneStatusPhase(cdiv1.SnapshotForSmartCloneInProgress, datavolume)
 		}
+		csiClone := err == nil && cloneStrategy == cdiv1.CloneStrategyCsiClone
+		if csiClone {
+			if requeue, err := r.sourceInUse(datavolume); requeue || err != nil {
+				return reconcile.Result{Requeue: requeue}, err
+			}
+			if populated, err := r.isSourcePVCPopulated(datavolume); !populated || err != nil {
+				return reconcile.Result{Requeue: !populated}, err
+			}
+		}
+		// Record why the clone is not a smart-clone when the cluster lacks the capability
+		var fallback *smartCloneFallbackError
+		if cloneStrategy != cdiv1.CloneStrategyHostAssisted {
+			errors.As(err, &fallback)
+		}
+		var probe *importProbeResult
+		if importProbeRequired(datavolume) {
+			// The PVC is provisioned once the probe pod reported on the source, its completion requeues the DataVolume
+			if probe, err = r.probeImportSource(datavolume); probe == nil || err != nil {
+				return reconcile.Result{}, err
+			}
+			if probe.err != nil && importPreflightRequired(datavolume) {
+				r.recorder.Event(datavolume, corev1.EventTypeWarning, ImportPreflightFailed, fmt.Sprintf(MessageImportPreflightFailed, probe.err))
+				return reconcile.Result{RequeueAfter: importPreflightRetryInterval}, nil
+			}
+		}
+		var snapshot *snapshotv1.VolumeSnapshot
+		if datavolume.Spec.Source.Snapshot != nil {
+			if snapshot, err = r.getReadySourceSnapshot(datavolume); snapshot == nil || err != nil {
+				if err == nil {
+					source := datavolume.Spec.Source.Snapshot
+					r.recorder.Event(datavolume, corev1.EventTypeNormal, SnapshotSourceNotReady, fmt.Sprintf(MessageSnapshotSourceNotReady, source.Namespace, source.Name))
+				}
+				return reconcile.Result{RequeueAfter: snapshotSourceRetryInterval}, err
+			}
+		}
+		pvcSource := datavolume
+		var size *resource.Quantity
+		if importSizeDetectionRequired(datavolume) {
+			if size, err = r.detectImportSize(datavolume, probe); err != nil {
+				r.recorder.Event(datavolume, corev1.EventTypeWarning, ImportSizeDetectionFailed, fmt.Sprintf(MessageImportSizeDetectionFailed, err))
+				return reconcile.Result{RequeueAfter: importSizeDetectionRetryInterval}, nil
+			}
+			log.Info("Detected the size of the import source", "size", size.String())
+		} else if size, err = r.blockToFilesystemCloneSize(datavolume); err != nil {
+			return reconcile.Result{}, err
+		} else if size != nil {
+			log.Info("Growing the PVC to hold the disk image of the block source", "size", size.String())
+		}
+		if size != nil {
+			// The size only applies to the PVC, the DataVolume spec is left as is
+			pvcSource = datavolume.DeepCopy()
+			if pvcSource.Spec.PVC.Resources.Requests == nil {
+				pvcSource.Spec.PVC.Resources.Requests = corev1.ResourceList{}
+			}
+			pvcSource.Spec.PVC.Resources.Requests[corev1.ResourceStorage] = *size
+		}
 		log.Info("Creating PVC for datavolume")
-		newPvc, err := r.newPersistentVolumeClaim(datavolume)
+		newPvc, err := newPersistentVolumeClaim(r.client, pvcSource)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if snapshot != nil {
+			if err := r.setSnapshotSource(datavolume, newPvc, snapshot); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if csiClone {
+			log.Info("Cloning the source PVC with the CSI driver")
+			setCsiCloneSource(datavolume, newPvc)
+		} else if fallback != nil {
+			log.Info("Falling back to a host-assisted clone", "reason", fallback.reason, "message", fallback.message)
+			setSmartCloneFallback(newPvc, fallback)
+		}
 		checkpoint := r.getNextCheckpoint(datavolume, newPvc)
 		if checkpoint != nil { // Initialize new warm import annotations before creating PVC
 			newPvc.ObjectMeta.Annotations[AnnCurrentCheckpoint] = checkpoint.Current
@@ -323,11 +440,21 @@ func (r *DatavolumeReconciler) Reconcile(req reconcile.Request) (reconcile.Resul
 				}
 			}
 		}
+		if err := r.syncImportPaused(datavolume, pvc); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if err := r.reconcileSnapshotRestorePvc(datavolume, pvc); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// Finally, we update the status block of the DataVolume resource to reflect the
 	// current state of the world
-	return r.reconcileDataVolumeStatus(datavolume, pvc)
+	result, err := r.reconcileDataVolumeStatus(datavolume, pvc)
+	if err != nil || datavolume.Status.Phase != cdiv1.Succeeded {
+		return result, err
+	}
+	return r.garbageCollect(datavolume, pvc, log)
 }
 
 // Set the PVC annotations related to multi-stage imports so that they point to the next checkpoint to copy.
@@ -372,8 +499,8 @@ func (r *DatavolumeReconciler) setMultistageImportAnnotations(dataVolume *cdiv1.
 		// more checkpoints to copy but the PVC is stopped in Succeeded,
 		// reset the phase to get another pod started for the next checkpoint.
 		var podNamespace string
-		if dataVolume.Spec.Source.PVC != nil {
-			podNamespace = dataVolume.Spec.Source.PVC.Namespace
+		if sourcePVC := getCloneSourcePVC(dataVolume); sourcePVC != nil {
+			podNamespace = sourcePVC.Namespace
 		} else {
 			podNamespace = dataVolume.Namespace
 		}
@@ -394,6 +521,21 @@ func (r *DatavolumeReconciler) setMultistageImportAnnotations(dataVolume *cdiv1.
 	return nil
 }
 
+// syncImportPaused pauses or resumes the import to the PVC along with the DataVolume
+func (r *DatavolumeReconciler) syncImportPaused(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	paused := dataVolume.GetAnnotations()[AnnImportPaused] == "true"
+	if paused == isImportPaused(pvc) {
+		return nil
+	}
+	pvcCopy := pvc.DeepCopy()
+	if paused {
+		pvcCopy.Annotations[AnnImportPaused] = "true"
+	} else {
+		delete(pvcCopy.Annotations, AnnImportPaused)
+	}
+	return r.client.Update(context.TODO(), pvcCopy)
+}
+
 // Clean up PVC annotations after a multi-stage import.
 func (r *DatavolumeReconciler) deleteMultistageImportAnnotations(pvc *corev1.PersistentVolumeClaim) error {
 	pvcCopy := pvc.DeepCopy()
@@ -527,8 +669,8 @@ func (r *DatavolumeReconciler) reconcileProgressUpdate(datavolume *cdiv1.DataVol
 		datavolume.Status.Progress = "N/A"
 	}
 
-	if datavolume.Spec.Source.PVC != nil {
-		podNamespace = datavolume.Spec.Source.PVC.Namespace
+	if sourcePVC := getCloneSourcePVC(datavolume); sourcePVC != nil {
+		podNamespace = sourcePVC.Namespace
 	} else {
 		podNamespace = datavolume.Namespace
 	}
@@ -558,9 +700,65 @@ func (r *DatavolumeReconciler) getSnapshotClassForSmartClone(dataVolume *cdiv1.D
 	// Check if relevant CRDs are available
 	if !IsCsiCrdsDeployed(r.extClientSet) {
 		r.log.V(3).Info("Missing CSI snapshotter CRDs, falling back to host assisted clone")
-		return "", errors.New("CSI snapshot CRDs not found")
+		return "", newSmartCloneFallbackError(SmartCloneNoSnapshotCRDs, "CSI snapshot CRDs not found")
 	}
 
+	pvc, err := r.getSmartCloneSourcePVC(dataVolume)
+	if err != nil {
+		return "", err
+	}
+	sourcePvcStorageClassName := pvc.Spec.StorageClassName
+
+	// Fetch the source storage class
+	srcStorageClass := &storagev1.StorageClass{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *sourcePvcStorageClassName}, srcStorageClass); err != nil {
+		r.log.V(3).Info("Unable to retrieve storage class, falling back to host assisted clone", "storage class", *sourcePvcStorageClassName)
+		return "", errors.New("unable to retrieve storage class, falling back to host assisted clone")
+	}
+
+	// Use the snapshot class of the storage profile of the source storage class if set
+	storageProfile, err := getStorageProfile(r.client, srcStorageClass.Name)
+	if err != nil {
+		return "", err
+	}
+	if storageProfile != nil && storageProfile.Status.SnapshotClass != nil {
+		snapshotClass := &snapshotv1.VolumeSnapshotClass{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *storageProfile.Status.SnapshotClass}, snapshotClass); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return "", err
+			}
+		} else if snapshotClass.Driver == srcStorageClass.Provisioner {
+			r.log.V(3).Info("smart-clone is applicable for datavolume", "datavolume",
+				dataVolume.Name, "snapshot class", snapshotClass.Name)
+			return snapshotClass.Name, nil
+		}
+		r.log.V(3).Info("The snapshot class of the storage profile does not match the storage class, falling back to host assisted clone", "snapshot class", *storageProfile.Status.SnapshotClass)
+		return "", newSmartCloneFallbackError(SmartCloneNoVolumeSnapshotClass, "the snapshot class %s of the storage profile does not exist or does not match the storage class, falling back to host assisted clone", *storageProfile.Status.SnapshotClass)
+	}
+
+	// List the snapshot classes
+	scs := &snapshotv1.VolumeSnapshotClassList{}
+	if err := r.client.List(context.TODO(), scs); err != nil {
+		r.log.V(3).Info("Cannot list snapshot classes, falling back to host assisted clone")
+		return "", errors.New("cannot list snapshot classes, falling back to host assisted clone")
+	}
+	for _, snapshotClass := range scs.Items {
+		// Validate association between snapshot class and storage class
+		if snapshotClass.Driver == srcStorageClass.Provisioner {
+			r.log.V(3).Info("smart-clone is applicable for datavolume", "datavolume",
+				dataVolume.Name, "snapshot class", snapshotClass.Name)
+			return snapshotClass.Name, nil
+		}
+	}
+
+	r.log.V(3).Info("Could not match snapshotter with storage class, falling back to host assisted clone")
+	return "", newSmartCloneFallbackError(SmartCloneNoVolumeSnapshotClass, "could not match snapshotter with storage class, falling back to host assisted clone")
+}
+
+// getSmartCloneSourcePVC returns the source PVC of the DataVolume if the storage can clone it into the target PVC
+// without copying the data, which requires the same namespace, storage class and volume mode, and a target at least
+// as large as the source.
+func (r *DatavolumeReconciler) getSmartCloneSourcePVC(dataVolume *cdiv1.DataVolume) (*corev1.PersistentVolumeClaim, error) {
 	// Find source PVC
 	sourcePvcNs := dataVolume.Spec.Source.PVC.Namespace
 	if sourcePvcNs == "" {
@@ -572,7 +770,7 @@ func (r *DatavolumeReconciler) getSnapshotClassForSmartClone(dataVolume *cdiv1.D
 		if k8serrors.IsNotFound(err) {
 			r.log.V(3).Info("Source PVC is missing", "source namespace", dataVolume.Spec.Source.PVC.Namespace, "source name", dataVolume.Spec.Source.PVC.Name)
 		}
-		return "", errors.New("source PVC not found")
+		return nil, errors.New("source PVC not found")
 	}
 
 	sourceVolumeMode := GetVolumeMode(pvc.Spec.VolumeMode)
@@ -580,62 +778,67 @@ func (r *DatavolumeReconciler) getSnapshotClassForSmartClone(dataVolume *cdiv1.D
 	if sourceVolumeMode != targetVolumeMode {
 		r.log.V(3).Info("Source PVC and target PVC have different volume modes, falling back to host assisted clone", "source volume mode",
 			sourceVolumeMode, "target volume mode", targetVolumeMode)
-		return "", errors.New("Source PVC and target PVC have different volume modes, falling back to host assisted clone")
+		return nil, newSmartCloneFallbackError(SmartCloneVolumeModeMismatch, "Source PVC and target PVC have different volume modes, falling back to host assisted clone")
 	}
 
 	targetPvcStorageClassName := dataVolume.Spec.PVC.StorageClassName
 	targetStorageClass, err := GetStorageClassByName(r.client, targetPvcStorageClassName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if targetStorageClass == nil {
 		r.log.V(3).Info("Target PVC's Storage Class not found")
-		return "", errors.New("Target PVC storage class not found")
+		return nil, newSmartCloneFallbackError(SmartCloneStorageClassMismatch, "Target PVC storage class not found")
 	}
 	targetPvcStorageClassName = &targetStorageClass.Name
 	sourcePvcStorageClassName := pvc.Spec.StorageClassName
 
 	// Compare source and target storage classess
-	if *sourcePvcStorageClassName != *targetPvcStorageClassName {
+	if sourcePvcStorageClassName == nil || *sourcePvcStorageClassName != *targetPvcStorageClassName {
 		r.log.V(3).Info("Source PVC and target PVC belong to different storage classes", "source storage class",
-			*sourcePvcStorageClassName, "target storage class", *targetPvcStorageClassName)
-		return "", errors.New("source PVC and target PVC belong to different storage classes")
+			sourcePvcStorageClassName, "target storage class", *targetPvcStorageClassName)
+		return nil, newSmartCloneFallbackError(SmartCloneStorageClassMismatch, "source PVC and target PVC belong to different storage classes")
 	}
 
 	// Compare source and target namespaces
 	if pvc.Namespace != dataVolume.Namespace {
 		r.log.V(3).Info("Source PVC and target PVC belong to different namespaces", "source namespace",
 			pvc.Namespace, "target namespace", dataVolume.Namespace)
-		return "", errors.New("source PVC and target PVC belong to different namespaces")
+		return nil, newSmartCloneFallbackError(SmartCloneCrossNamespace, "source PVC and target PVC belong to different namespaces")
 	}
 
-	// Fetch the source storage class
-	srcStorageClass := &storagev1.StorageClass{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *sourcePvcStorageClassName}, srcStorageClass); err != nil {
-		r.log.V(3).Info("Unable to retrieve storage class, falling back to host assisted clone", "storage class", *sourcePvcStorageClassName)
-		return "", errors.New("unable to retrieve storage class, falling back to host assisted clone")
+	// Neither a restored snapshot nor a CSI volume clone can be smaller than its source
+	sourceSize, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		sourceSize = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	if targetSize, ok := dataVolume.Spec.PVC.Resources.Requests[corev1.ResourceStorage]; ok && targetSize.Cmp(sourceSize) < 0 {
+		r.log.V(3).Info("Target PVC is smaller than the source PVC", "source size", sourceSize.String(), "target size", targetSize.String())
+		return nil, newSmartCloneFallbackError(SmartCloneSizeMismatch, "target PVC size %s is smaller than the source PVC size %s", targetSize.String(), sourceSize.String())
 	}
 
-	// List the snapshot classes
-	scs := &snapshotv1.VolumeSnapshotClassList{}
-	if err := r.client.List(context.TODO(), scs); err != nil {
-		r.log.V(3).Info("Cannot list snapshot classes, falling back to host assisted clone")
-		return "", errors.New("cannot list snapshot classes, falling back to host assisted clone")
+	return pvc, nil
+}
+
+// getCloneStrategy returns the clone strategy of the DataVolume: the clone strategy of the StorageProfile of the target
+// storage class if set, otherwise the override of the CDI resource, snapshot by default
+func (r *DatavolumeReconciler) getCloneStrategy(dataVolume *cdiv1.DataVolume) (cdiv1.CDICloneStrategy, error) {
+	var storageClassName *string
+	if dataVolume.Spec.PVC != nil {
+		storageClassName = dataVolume.Spec.PVC.StorageClassName
 	}
-	for _, snapshotClass := range scs.Items {
-		// Validate association between snapshot class and storage class
-		if snapshotClass.Driver == srcStorageClass.Provisioner {
-			r.log.V(3).Info("smart-clone is applicable for datavolume", "datavolume",
-				dataVolume.Name, "snapshot class", snapshotClass.Name)
-			return snapshotClass.Name, nil
+	storageClass, err := GetStorageClassByName(r.client, storageClassName)
+	if err == nil && storageClass != nil {
+		storageProfile, err := getStorageProfile(r.client, storageClass.Name)
+		if err != nil {
+			return cdiv1.CloneStrategySnapshot, err
+		}
+		if storageProfile != nil && storageProfile.Status.CloneStrategy != nil {
+			r.log.V(3).Info(fmt.Sprintf("Using the clone strategy %s of the storage profile %s", *storageProfile.Status.CloneStrategy, storageProfile.Name))
+			return *storageProfile.Status.CloneStrategy, nil
 		}
 	}
 
-	r.log.V(3).Info("Could not match snapshotter with storage class, falling back to host assisted clone")
-	return "", errors.New("could not match snapshotter with storage class, falling back to host assisted clone")
-}
-
-func (r *DatavolumeReconciler) getCloneStrategy() (cdiv1.CDICloneStrategy, error) {
 	cr, err := GetActiveCDI(r.client)
 	if err != nil {
 		return cdiv1.CloneStrategySnapshot, err
@@ -645,12 +848,11 @@ func (r *DatavolumeReconciler) getCloneStrategy() (cdiv1.CDICloneStrategy, error
 		return cdiv1.CloneStrategySnapshot, fmt.Errorf("no active CDI")
 	}
 
-	if cr.Spec.CloneStrategyOverride == nil {
-		return cdiv1.CloneStrategySnapshot, nil
+	if cr.Spec.CloneStrategyOverride != nil {
+		r.log.V(3).Info(fmt.Sprintf("Overriding default clone strategy with %s", *cr.Spec.CloneStrategyOverride))
+		return *cr.Spec.CloneStrategyOverride, nil
 	}
-
-	r.log.V(3).Info(fmt.Sprintf("Overriding default clone strategy with %s", *cr.Spec.CloneStrategyOverride))
-	return *cr.Spec.CloneStrategyOverride, nil
+	return cdiv1.CloneStrategySnapshot, nil
 }
 
 func newSnapshot(dataVolume *cdiv1.DataVolume, snapshotClassName string) *snapshotv1.VolumeSnapshot {
@@ -687,6 +889,13 @@ func newSnapshot(dataVolume *cdiv1.DataVolume, snapshotClassName string) *snapsh
 
 func (r *DatavolumeReconciler) updateImportStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *DataVolumeEvent) {
 	phase, ok := pvc.Annotations[AnnPodPhase]
+	if ok && isImportPaused(pvc) && phase != string(corev1.PodSucceeded) && phase != string(corev1.PodFailed) {
+		dataVolumeCopy.Status.Phase = cdiv1.Paused
+		event.eventType = corev1.EventTypeNormal
+		event.reason = ImportPausedByUser
+		event.message = fmt.Sprintf(MessageImportPausedByAnnotation, pvc.Name)
+		return
+	}
 	if ok {
 		switch phase {
 		case string(corev1.PodPending):
@@ -717,6 +926,10 @@ func (r *DatavolumeReconciler) updateImportStatusPhase(pvc *corev1.PersistentVol
 			} else {
 				dataVolumeCopy.Status.Phase = cdiv1.Succeeded
 				dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress("100.0%")
+				if transfer := dataVolumeCopy.Status.Transfer; transfer != nil {
+					transfer.BytesPerSecond = 0
+					transfer.EstimatedCompletionTime = nil
+				}
 				event.eventType = corev1.EventTypeNormal
 				event.reason = ImportSucceeded
 				event.message = fmt.Sprintf(MessageImportSucceeded, pvc.Name)
@@ -743,6 +956,15 @@ func (r *DatavolumeReconciler) updateSmartCloneStatusPhase(phase cdiv1.DataVolum
 }
 
 func (r *DatavolumeReconciler) updateCloneStatusPhase(pvc *corev1.PersistentVolumeClaim, dataVolumeCopy *cdiv1.DataVolume, event *DataVolumeEvent) {
+	sourcePVC := getCloneSourcePVC(dataVolumeCopy)
+	scheduled, inProgress, failed, succeeded := MessageCloneScheduled, MessageCloneInProgress, MessageCloneFailed, MessageCloneSucceeded
+	if dataVolumeCopy.Spec.Source.PVCNetwork != nil {
+		scheduled, inProgress, failed, succeeded = MessageNetworkCopyScheduled, MessageNetworkCopyInProgress, MessageNetworkCopyFailed, MessageNetworkCopySucceeded
+	}
+	if snapshot := dataVolumeCopy.Spec.Source.Snapshot; snapshot != nil {
+		sourcePVC = &cdiv1.DataVolumeSourcePVC{Namespace: snapshot.Namespace, Name: snapshot.Name}
+		scheduled, inProgress, failed, succeeded = MessageSnapshotCopyScheduled, MessageSnapshotCopyInProgress, MessageSnapshotCopyFailed, MessageSnapshotCopySucceeded
+	}
 	phase, ok := pvc.Annotations[AnnPodPhase]
 	if ok {
 		switch phase {
@@ -751,24 +973,28 @@ func (r *DatavolumeReconciler) updateCloneStatusPhase(pvc *corev1.PersistentVolu
 			dataVolumeCopy.Status.Phase = cdiv1.CloneScheduled
 			event.eventType = corev1.EventTypeNormal
 			event.reason = CloneScheduled
-			event.message = fmt.Sprintf(MessageCloneScheduled, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+			event.message = fmt.Sprintf(scheduled, sourcePVC.Namespace, sourcePVC.Name, pvc.Namespace, pvc.Name)
 		case string(corev1.PodRunning):
 			// TODO: Use a more generic In Progess, like maybe TransferInProgress.
 			dataVolumeCopy.Status.Phase = cdiv1.CloneInProgress
 			event.eventType = corev1.EventTypeNormal
 			event.reason = CloneInProgress
-			event.message = fmt.Sprintf(MessageCloneInProgress, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+			event.message = fmt.Sprintf(inProgress, sourcePVC.Namespace, sourcePVC.Name, pvc.Namespace, pvc.Name)
 		case string(corev1.PodFailed):
 			dataVolumeCopy.Status.Phase = cdiv1.Failed
 			event.eventType = corev1.EventTypeWarning
 			event.reason = CloneFailed
-			event.message = fmt.Sprintf(MessageCloneFailed, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+			event.message = fmt.Sprintf(failed, sourcePVC.Namespace, sourcePVC.Name, pvc.Namespace, pvc.Name)
 		case string(corev1.PodSucceeded):
 			dataVolumeCopy.Status.Phase = cdiv1.Succeeded
 			dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress("100.0%")
+			if transfer := dataVolumeCopy.Status.Transfer; transfer != nil {
+				transfer.BytesPerSecond = 0
+				transfer.EstimatedCompletionTime = nil
+			}
 			event.eventType = corev1.EventTypeNormal
 			event.reason = CloneSucceeded
-			event.message = fmt.Sprintf(MessageCloneSucceeded, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+			event.message = fmt.Sprintf(succeeded, sourcePVC.Namespace, sourcePVC.Name, pvc.Namespace, pvc.Name)
 		}
 
 	}
@@ -869,7 +1095,7 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 					}
 				} else {
 					dataVolumeCopy.Status.Phase = cdiv1.Succeeded
-
+					dataVolumeCopy.Status.SourceInfo = getImportSourceInfo(pvc)
 				}
 				r.updateImportStatusPhase(pvc, dataVolumeCopy, &event)
 			}
@@ -907,6 +1133,12 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 					if ok {
 						dataVolumeCopy.Status.Phase = cdiv1.ImportScheduled
 						r.updateImportStatusPhase(pvc, dataVolumeCopy, &event)
+						if _, queued := pvc.Annotations[AnnImportQueued]; queued {
+							dataVolumeCopy.Status.Phase = cdiv1.Pending
+							event.eventType = corev1.EventTypeNormal
+							event.reason = ImportQueued
+							event.message = fmt.Sprintf(MessageImportQueued, pvc.Name)
+						}
 					}
 					_, ok = pvc.Annotations[AnnCloneRequest]
 					if ok {
@@ -938,6 +1170,9 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 		if err != nil {
 			return result, err
 		}
+		if len(dataVolumeCopy.Spec.Checkpoints) > 0 {
+			r.updateCheckpointStatus(dataVolumeCopy, pvc)
+		}
 	}
 
 	currentCond := make([]cdiv1.DataVolumeCondition, len(dataVolumeCopy.Status.Conditions))
@@ -946,6 +1181,128 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 	return result, r.emitEvent(dataVolume, dataVolumeCopy, curPhase, currentCond, &event)
 }
 
+// getImportSourceInfo returns the format, compression and sizes of the source the importer recorded on the PVC, nil if
+// it recorded none.
+func getImportSourceInfo(pvc *corev1.PersistentVolumeClaim) *cdiv1.DataVolumeSourceInfo {
+	info := &cdiv1.DataVolumeSourceInfo{
+		Format:      pvc.Annotations[AnnSourceFormat],
+		Compression: pvc.Annotations[AnnSourceCompression],
+	}
+	if size, err := strconv.ParseInt(pvc.Annotations[AnnSourceVirtualSize], 10, 64); err == nil {
+		info.VirtualSize = resource.NewQuantity(size, resource.BinarySI)
+	}
+	if size, err := strconv.ParseInt(pvc.Annotations[AnnSourceDownloadedSize], 10, 64); err == nil {
+		info.DownloadedSize = resource.NewQuantity(size, resource.BinarySI)
+	}
+	if *info == (cdiv1.DataVolumeSourceInfo{}) {
+		return nil
+	}
+	return info
+}
+
+// getCloneSourcePVC returns the source PVC of a DataVolume cloning a PVC or copying it over the network, nil otherwise.
+func getCloneSourcePVC(dataVolume *cdiv1.DataVolume) *cdiv1.DataVolumeSourcePVC {
+	if dataVolume.Spec.Source.PVCNetwork != nil {
+		return dataVolume.Spec.Source.PVCNetwork
+	}
+	return dataVolume.Spec.Source.PVC
+}
+
+// blockToFilesystemCloneSize returns the size of the PVC of a DataVolume cloning a block PVC to a file system, large
+// enough for the disk image of the device along with the filesystem overhead. It returns nil when the clone does not
+// convert the volume mode or the requested size is large enough.
+func (r *DatavolumeReconciler) blockToFilesystemCloneSize(dataVolume *cdiv1.DataVolume) (*resource.Quantity, error) {
+	sourcePVC := getCloneSourcePVC(dataVolume)
+	if sourcePVC == nil || GetVolumeMode(dataVolume.Spec.PVC.VolumeMode) != corev1.PersistentVolumeFilesystem {
+		return nil, nil
+	}
+	sourceNamespace := sourcePVC.Namespace
+	if sourceNamespace == "" {
+		sourceNamespace = dataVolume.Namespace
+	}
+	source := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: sourceNamespace, Name: sourcePVC.Name}, source); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if GetVolumeMode(source.Spec.VolumeMode) != corev1.PersistentVolumeBlock {
+		return nil, nil
+	}
+	// The whole device is copied, which may be larger than requested
+	sourceSize, ok := source.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		sourceSize = source.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: dataVolume.Namespace},
+		Spec:       *dataVolume.Spec.PVC,
+	}
+	if dataVolume.Spec.FilesystemOverhead != nil {
+		pvc.Annotations = map[string]string{AnnFilesystemOverhead: string(*dataVolume.Spec.FilesystemOverhead)}
+	}
+	overhead, err := GetFilesystemOverhead(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+	overheadValue, err := strconv.ParseFloat(string(overhead), 64)
+	if err != nil {
+		return nil, err
+	}
+	size := importPvcSize(sourceSize.Value(), overheadValue)
+	if requested, ok := dataVolume.Spec.PVC.Resources.Requests[corev1.ResourceStorage]; ok && requested.Cmp(*size) >= 0 {
+		return nil, nil
+	}
+	return size, nil
+}
+
+// updateCheckpointStatus records the phase, progress and duration of the copy of each checkpoint of a multi-stage
+// import in the DataVolume status. A checkpoint is copied once the PVC has its copied annotation, or once the whole
+// import is done, as the annotations are removed then.
+func (r *DatavolumeReconciler) updateCheckpointStatus(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) {
+	previousStatus := make(map[string]cdiv1.DataVolumeCheckpointStatus)
+	for _, status := range dataVolume.Status.Checkpoints {
+		previousStatus[status.Current] = status
+	}
+	multiStageDone := metav1.HasAnnotation(pvc.ObjectMeta, AnnMultiStageImportDone)
+	currentCheckpoint := pvc.Annotations[AnnCurrentCheckpoint]
+	podRunning := pvc.Annotations[AnnPodPhase] == string(corev1.PodRunning)
+	now := metav1.Now()
+
+	var checkpoints []cdiv1.DataVolumeCheckpointStatus
+	for _, checkpoint := range dataVolume.Spec.Checkpoints {
+		status, ok := previousStatus[checkpoint.Current]
+		if !ok {
+			status = cdiv1.DataVolumeCheckpointStatus{
+				Current: checkpoint.Current,
+				Phase:   cdiv1.Pending,
+			}
+		}
+		status.Previous = checkpoint.Previous
+		switch {
+		case status.Phase == cdiv1.Succeeded:
+			// Copied checkpoints stay copied
+		case multiStageDone || r.checkpointAlreadyCopied(pvc, checkpoint.Current):
+			status.Phase = cdiv1.Succeeded
+			status.Progress = "100.0%"
+			if status.StartTime == nil {
+				status.StartTime = &now
+			}
+			status.CompletionTime = &now
+		case checkpoint.Current == currentCheckpoint && podRunning:
+			status.Phase = cdiv1.ImportInProgress
+			status.Progress = dataVolume.Status.Progress
+			if status.StartTime == nil {
+				status.StartTime = &now
+			}
+		}
+		checkpoints = append(checkpoints, status)
+	}
+	dataVolume.Status.Checkpoints = checkpoints
+}
+
 func (r *DatavolumeReconciler) updateConditions(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) {
 	var anno map[string]string
 
@@ -972,6 +1329,7 @@ func (r *DatavolumeReconciler) updateConditions(dataVolume *cdiv1.DataVolume, pv
 	dataVolume.Status.Conditions = updateBoundCondition(dataVolume.Status.Conditions, pvc)
 	dataVolume.Status.Conditions = updateReadyCondition(dataVolume.Status.Conditions, readyStatus, "", "")
 	dataVolume.Status.Conditions = updateRunningCondition(dataVolume.Status.Conditions, anno)
+	dataVolume.Status.Conditions = updateSmartCloneFallbackCondition(dataVolume.Status.Conditions, anno)
 }
 
 func (r *DatavolumeReconciler) emitConditionEvent(dataVolume *cdiv1.DataVolume, originalCond []cdiv1.DataVolumeCondition) {
@@ -1055,37 +1413,93 @@ func (r *DatavolumeReconciler) addOwnerRef(pvc *corev1.PersistentVolumeClaim, dv
 }
 
 func updateProgressUsingPod(dataVolumeCopy *cdiv1.DataVolume, pod *corev1.Pod) error {
-	httpClient := buildHTTPClient()
-	// Example value: import_progress{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} 13.45
-	var importRegExp = regexp.MustCompile("progress\\{ownerUID\\=\"" + string(dataVolumeCopy.UID) + "\"\\} (\\d{1,3}\\.?\\d*)")
+	metrics, err := getMetricsFromPod(pod)
+	if progress := parseProgress(metrics, dataVolumeCopy.UID); progress != "" {
+		dataVolumeCopy.Status.Progress = progress
+	}
+	if transfer := parseTransferStatus(metrics, dataVolumeCopy.UID, time.Now()); transfer != nil {
+		dataVolumeCopy.Status.Transfer = transfer
+	}
+	return err
+}
 
+// getProgressFromPod returns the progress the pod reports for the owner uid on its metrics endpoint, "" if the pod
+// does not report it yet.
+func getProgressFromPod(pod *corev1.Pod, ownerUID types.UID) (cdiv1.DataVolumeProgress, error) {
+	metrics, err := getMetricsFromPod(pod)
+	return parseProgress(metrics, ownerUID), err
+}
+
+// getMetricsFromPod returns the metrics of the pod, "" if its metrics endpoint is not up yet.
+func getMetricsFromPod(pod *corev1.Pod) (string, error) {
+	httpClient := buildHTTPClient()
 	port, err := getPodMetricsPort(pod)
 	if err == nil && pod.Status.PodIP != "" {
 		url := fmt.Sprintf("https://%s:%d/metrics", pod.Status.PodIP, port)
 		resp, err := httpClient.Get(url)
 		if err != nil {
 			if errConnectionRefused(err) {
-				return nil
+				return "", nil
 			}
-			return err
+			return "", err
 		}
 		defer resp.Body.Close()
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return err
+			return "", err
 		}
+		return string(body), nil
+	}
+	return "", err
+}
 
-		match := importRegExp.FindStringSubmatch(string(body))
-		if match == nil {
-			// No match
-			return nil
-		}
-		if f, err := strconv.ParseFloat(match[1], 64); err == nil {
-			dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress(fmt.Sprintf("%.2f%%", f))
-		}
+func parseProgress(metrics string, ownerUID types.UID) cdiv1.DataVolumeProgress {
+	// Example value: import_progress{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} 13.45
+	var importRegExp = regexp.MustCompile("progress\\{ownerUID\\=\"" + string(ownerUID) + "\"\\} (\\d{1,3}\\.?\\d*)")
+	match := importRegExp.FindStringSubmatch(metrics)
+	if match == nil {
+		// No match
+		return ""
+	}
+	if f, err := strconv.ParseFloat(match[1], 64); err == nil {
+		return cdiv1.DataVolumeProgress(fmt.Sprintf("%.2f%%", f))
+	}
+	return ""
+}
+
+// maxEstimatedRemainingTime is the longest remaining time of a transfer reported as an estimated completion time
+const maxEstimatedRemainingTime = 365 * 24 * time.Hour
+
+// parseTransferStatus returns the transfer status reported in the metrics for the owner uid, nil if the pod does not
+// report it. The estimated completion time is left unset while the remaining time is unknown.
+func parseTransferStatus(metrics string, ownerUID types.UID, now time.Time) *cdiv1.DataVolumeTransferStatus {
+	bytesTransferred, ok := parseOwnerMetric(metrics, "bytes_transferred", ownerUID)
+	if !ok {
 		return nil
 	}
-	return err
+	transfer := &cdiv1.DataVolumeTransferStatus{BytesTransferred: int64(bytesTransferred)}
+	if throughput, ok := parseOwnerMetric(metrics, "throughput_bytes", ownerUID); ok {
+		transfer.BytesPerSecond = int64(throughput)
+	}
+	remaining, ok := parseOwnerMetric(metrics, "remaining_seconds", ownerUID)
+	// A stalled transfer has no meaningful estimate
+	if ok && remaining >= 0 && remaining <= maxEstimatedRemainingTime.Seconds() {
+		completion := metav1.NewTime(now.Add(time.Duration(remaining) * time.Second).Truncate(time.Second))
+		transfer.EstimatedCompletionTime = &completion
+	}
+	return transfer
+}
+
+// parseOwnerMetric returns the value of the metric with the name suffix for the owner uid
+func parseOwnerMetric(metrics, suffix string, ownerUID types.UID) (float64, bool) {
+	// Example value: import_bytes_transferred{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} 1.048576e+06
+	metricRegExp := regexp.MustCompile(regexp.QuoteMeta(suffix+"{ownerUID=\""+string(ownerUID)+"\"} ") + "(\\S+)")
+	match := metricRegExp.FindStringSubmatch(metrics)
+	if match == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(match[1], 64)
+	return f, err == nil
 }
 
 func errConnectionRefused(err error) bool {
@@ -1130,7 +1544,7 @@ func buildHTTPClient() *http.Client {
 // It also sets the appropriate OwnerReferences on the resource
 // which allows handleObject to discover the DataVolume resource
 // that 'owns' it.
-func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVolume) (*corev1.PersistentVolumeClaim, error) {
+func newPersistentVolumeClaim(c client.Client, dataVolume *cdiv1.DataVolume) (*corev1.PersistentVolumeClaim, error) {
 	labels := map[string]string{
 		"app": "containerized-data-importer",
 	}
@@ -1146,6 +1560,10 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 	for k, v := range dataVolume.ObjectMeta.Annotations {
 		annotations[k] = v
 	}
+	// The source validators of the DataVolumes of DataImportCrons are only used to poll their source, the import always reads it.
+	delete(annotations, AnnSourceETag)
+	delete(annotations, AnnSourceLastModified)
+	delete(annotations, AnnSourceVersionID)
 
 	annotations[AnnPodRestarts] = "0"
 	if dataVolume.Spec.Source.HTTP != nil {
@@ -1159,15 +1577,114 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		if dataVolume.Spec.Source.HTTP.SecretRef != "" {
 			annotations[AnnSecret] = dataVolume.Spec.Source.HTTP.SecretRef
 		}
+		if dataVolume.Spec.Source.HTTP.ClientCertSecretRef != "" {
+			annotations[AnnClientCertSecret] = dataVolume.Spec.Source.HTTP.ClientCertSecretRef
+		}
+		if dataVolume.Spec.Source.HTTP.TokenSecretRef != "" {
+			annotations[AnnTokenSecret] = dataVolume.Spec.Source.HTTP.TokenSecretRef
+		}
+		if oauth2 := dataVolume.Spec.Source.HTTP.OAuth2; oauth2 != nil {
+			annotations[AnnOAuth2TokenURL] = oauth2.TokenURL
+			annotations[AnnOAuth2Secret] = oauth2.SecretRef
+			if len(oauth2.Scopes) > 0 {
+				annotations[AnnOAuth2Scopes] = strings.Join(oauth2.Scopes, " ")
+			}
+		}
 		if dataVolume.Spec.Source.HTTP.CertConfigMap != "" {
 			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.HTTP.CertConfigMap
 		}
+		if dataVolume.Spec.Source.HTTP.FTPPassive != nil {
+			annotations[AnnFTPPassive] = strconv.FormatBool(*dataVolume.Spec.Source.HTTP.FTPPassive)
+		}
+		if dataVolume.Spec.Source.HTTP.Segments != nil {
+			annotations[AnnHTTPSegments] = strconv.Itoa(int(*dataVolume.Spec.Source.HTTP.Segments))
+		}
+		if dataVolume.Spec.Source.HTTP.SegmentSize != nil {
+			annotations[AnnHTTPSegmentSize] = dataVolume.Spec.Source.HTTP.SegmentSize.String()
+		}
 	} else if dataVolume.Spec.Source.S3 != nil {
 		annotations[AnnEndpoint] = dataVolume.Spec.Source.S3.URL
 		annotations[AnnSource] = SourceS3
 		if dataVolume.Spec.Source.S3.SecretRef != "" {
 			annotations[AnnSecret] = dataVolume.Spec.Source.S3.SecretRef
 		}
+		if dataVolume.Spec.Source.S3.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.S3.ServiceAccountName
+		}
+		if dataVolume.Spec.Source.S3.Region != "" {
+			annotations[AnnS3Region] = dataVolume.Spec.Source.S3.Region
+		}
+		if dataVolume.Spec.Source.S3.ForcePathStyle != nil {
+			annotations[AnnS3ForcePathStyle] = strconv.FormatBool(*dataVolume.Spec.Source.S3.ForcePathStyle)
+		}
+		if dataVolume.Spec.Source.S3.SSECustomerKeySecretRef != "" {
+			annotations[AnnS3SSECustomerKeySecret] = dataVolume.Spec.Source.S3.SSECustomerKeySecretRef
+		}
+		if dataVolume.Spec.Source.S3.Segments != nil {
+			annotations[AnnS3Segments] = strconv.Itoa(int(*dataVolume.Spec.Source.S3.Segments))
+		}
+		if dataVolume.Spec.Source.S3.SegmentSize != nil {
+			annotations[AnnS3SegmentSize] = dataVolume.Spec.Source.S3.SegmentSize.String()
+		}
+		if dataVolume.Spec.Source.S3.ObjectVersionID != "" {
+			annotations[AnnS3ObjectVersionID] = dataVolume.Spec.Source.S3.ObjectVersionID
+		}
+	} else if dataVolume.Spec.Source.AWSSnapshot != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.AWSSnapshot.SnapshotID
+		annotations[AnnSource] = SourceAWSSnapshot
+		annotations[AnnAWSSnapshotRegion] = dataVolume.Spec.Source.AWSSnapshot.Region
+		if dataVolume.Spec.Source.AWSSnapshot.SecretRef != "" {
+			annotations[AnnSecret] = dataVolume.Spec.Source.AWSSnapshot.SecretRef
+		}
+		if dataVolume.Spec.Source.AWSSnapshot.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.AWSSnapshot.ServiceAccountName
+		}
+	} else if dataVolume.Spec.Source.GCS != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.GCS.URL
+		annotations[AnnSource] = SourceGCS
+		if dataVolume.Spec.Source.GCS.SecretRef != "" {
+			annotations[AnnGCSSecret] = dataVolume.Spec.Source.GCS.SecretRef
+		}
+		if dataVolume.Spec.Source.GCS.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.GCS.ServiceAccountName
+		}
+	} else if dataVolume.Spec.Source.GCEImage != nil {
+		// The images that were not created from a tar.gz in GCS must be exported first
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.GCEImage.Image
+		if dataVolume.Spec.Source.GCEImage.ExportURL != "" {
+			annotations[AnnEndpoint] = dataVolume.Spec.Source.GCEImage.ExportURL
+		}
+		annotations[AnnSource] = SourceGCEImage
+		if dataVolume.Spec.Source.GCEImage.SecretRef != "" {
+			annotations[AnnGCEImageSecret] = dataVolume.Spec.Source.GCEImage.SecretRef
+		}
+		if dataVolume.Spec.Source.GCEImage.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.GCEImage.ServiceAccountName
+		}
+	} else if dataVolume.Spec.Source.AzureBlob != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.AzureBlob.URL
+		annotations[AnnSource] = SourceAzureBlob
+		if dataVolume.Spec.Source.AzureBlob.SecretRef != "" {
+			annotations[AnnAzureBlobSecret] = dataVolume.Spec.Source.AzureBlob.SecretRef
+		}
+		if dataVolume.Spec.Source.AzureBlob.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.AzureBlob.ServiceAccountName
+		}
+		if dataVolume.Spec.Source.AzureBlob.Segments != nil {
+			annotations[AnnAzureBlobSegments] = strconv.Itoa(int(*dataVolume.Spec.Source.AzureBlob.Segments))
+		}
+		if dataVolume.Spec.Source.AzureBlob.SegmentSize != nil {
+			annotations[AnnAzureBlobSegmentSize] = dataVolume.Spec.Source.AzureBlob.SegmentSize.String()
+		}
+	} else if dataVolume.Spec.Source.AzureDisk != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.AzureDisk.ResourceID
+		annotations[AnnSource] = SourceAzureDisk
+		if dataVolume.Spec.Source.AzureDisk.SecretRef != "" {
+			annotations[AnnAzureDiskSecret] = dataVolume.Spec.Source.AzureDisk.SecretRef
+		}
+		if dataVolume.Spec.Source.AzureDisk.ServiceAccountName != "" {
+			annotations[AnnImportServiceAccount] = dataVolume.Spec.Source.AzureDisk.ServiceAccountName
+		}
 	} else if dataVolume.Spec.Source.Registry != nil {
 		annotations[AnnSource] = SourceRegistry
 		annotations[AnnEndpoint] = dataVolume.Spec.Source.Registry.URL
@@ -1178,17 +1695,34 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		if dataVolume.Spec.Source.Registry.CertConfigMap != "" {
 			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.Registry.CertConfigMap
 		}
-	} else if dataVolume.Spec.Source.PVC != nil {
-		sourceNamespace := dataVolume.Spec.Source.PVC.Namespace
+		if dataVolume.Spec.Source.Registry.Platform != "" {
+			annotations[AnnRegistryPlatform] = dataVolume.Spec.Source.Registry.Platform
+		}
+		if signature := dataVolume.Spec.Source.Registry.Signature; signature != nil {
+			annotations[AnnRegistrySignatureConfigMap] = signature.ConfigMap
+			if signature.Identity != "" {
+				annotations[AnnRegistrySignatureIdentity] = signature.Identity
+			}
+			if signature.Issuer != "" {
+				annotations[AnnRegistrySignatureIssuer] = signature.Issuer
+			}
+		}
+	} else if sourcePVC := getCloneSourcePVC(dataVolume); sourcePVC != nil {
+		// Only the pvc source is considered for smart clones, a network copy always goes through the host assisted clone
+		sourceNamespace := sourcePVC.Namespace
 		if sourceNamespace == "" {
 			sourceNamespace = dataVolume.Namespace
 		}
-		token, ok := dataVolume.Annotations[AnnCloneToken]
-		if !ok {
+		if grant, ok := dataVolume.Annotations[AnnCloneGrant]; ok {
+			annotations[AnnCloneGrant] = grant
+		} else if token, ok := dataVolume.Annotations[AnnCloneToken]; ok {
+			annotations[AnnCloneToken] = token
+		} else {
 			return nil, errors.Errorf("no clone token")
 		}
-		annotations[AnnCloneToken] = token
-		annotations[AnnCloneRequest] = sourceNamespace + "/" + dataVolume.Spec.Source.PVC.Name
+		annotations[AnnCloneRequest] = sourceNamespace + "/" + sourcePVC.Name
+	} else if dataVolume.Spec.Source.Snapshot != nil {
+		// Set by setSnapshotSource once the snapshot is ready, either restored or copied
 	} else if dataVolume.Spec.Source.Upload != nil {
 		annotations[AnnUploadRequest] = ""
 	} else if dataVolume.Spec.Source.Blank != nil {
@@ -1200,6 +1734,137 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		annotations[AnnSecret] = dataVolume.Spec.Source.Imageio.SecretRef
 		annotations[AnnCertConfigMap] = dataVolume.Spec.Source.Imageio.CertConfigMap
 		annotations[AnnDiskID] = dataVolume.Spec.Source.Imageio.DiskID
+	} else if dataVolume.Spec.Source.Glance != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.Glance.AuthURL
+		annotations[AnnSource] = SourceGlance
+		annotations[AnnSecret] = dataVolume.Spec.Source.Glance.SecretRef
+		annotations[AnnDiskID] = dataVolume.Spec.Source.Glance.ImageID
+		annotations[AnnGlanceProject] = dataVolume.Spec.Source.Glance.Project
+		if dataVolume.Spec.Source.Glance.Domain != "" {
+			annotations[AnnGlanceDomain] = dataVolume.Spec.Source.Glance.Domain
+		}
+		if dataVolume.Spec.Source.Glance.Region != "" {
+			annotations[AnnGlanceRegion] = dataVolume.Spec.Source.Glance.Region
+		}
+		if dataVolume.Spec.Source.Glance.CertConfigMap != "" {
+			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.Glance.CertConfigMap
+		}
+	} else if dataVolume.Spec.Source.Proxmox != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.Proxmox.URL
+		annotations[AnnSource] = SourceProxmox
+		annotations[AnnSecret] = dataVolume.Spec.Source.Proxmox.SecretRef
+		annotations[AnnDiskID] = dataVolume.Spec.Source.Proxmox.Disk
+		annotations[AnnProxmoxVMID] = strconv.Itoa(int(dataVolume.Spec.Source.Proxmox.VMID))
+		if dataVolume.Spec.Source.Proxmox.Node != "" {
+			annotations[AnnProxmoxNode] = dataVolume.Spec.Source.Proxmox.Node
+		}
+		if dataVolume.Spec.Source.Proxmox.CertConfigMap != "" {
+			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.Proxmox.CertConfigMap
+		}
+	} else if dataVolume.Spec.Source.NFS != nil {
+		endpoint := url.URL{
+			Scheme: "nfs",
+			Host:   dataVolume.Spec.Source.NFS.Server,
+			Path:   dataVolume.Spec.Source.NFS.Path,
+		}
+		if strings.Contains(endpoint.Host, ":") {
+			// IPv6 address
+			endpoint.Host = "[" + endpoint.Host + "]"
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceNFS
+	} else if dataVolume.Spec.Source.SMB != nil {
+		endpoint := url.URL{
+			Scheme: "smb",
+			Host:   dataVolume.Spec.Source.SMB.Server,
+			Path:   path.Join("/", dataVolume.Spec.Source.SMB.Share, strings.ReplaceAll(dataVolume.Spec.Source.SMB.Path, "\\", "/")),
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceSMB
+		if dataVolume.Spec.Source.SMB.SecretRef != "" {
+			annotations[AnnSecret] = dataVolume.Spec.Source.SMB.SecretRef
+		}
+		if dataVolume.Spec.Source.SMB.Domain != "" {
+			annotations[AnnSMBDomain] = dataVolume.Spec.Source.SMB.Domain
+		}
+	} else if dataVolume.Spec.Source.Rsync != nil {
+		endpoint := url.URL{
+			Scheme: "ssh",
+			Host:   dataVolume.Spec.Source.Rsync.Host,
+			Path:   path.Join("/", dataVolume.Spec.Source.Rsync.Path),
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceRsync
+		annotations[AnnSecret] = dataVolume.Spec.Source.Rsync.SecretRef
+		if dataVolume.Spec.Source.Rsync.Module != "" {
+			annotations[AnnRsyncModule] = dataVolume.Spec.Source.Rsync.Module
+		}
+		if dataVolume.Spec.Source.Rsync.HostKey != "" {
+			annotations[AnnRsyncHostKey] = dataVolume.Spec.Source.Rsync.HostKey
+		}
+	} else if dataVolume.Spec.Source.ISCSI != nil {
+		endpoint := url.URL{
+			Scheme: "iscsi",
+			Host:   dataVolume.Spec.Source.ISCSI.Portal,
+			Path:   fmt.Sprintf("/%s/%d", dataVolume.Spec.Source.ISCSI.IQN, dataVolume.Spec.Source.ISCSI.LUN),
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceISCSI
+		if dataVolume.Spec.Source.ISCSI.SecretRef != "" {
+			annotations[AnnSecret] = dataVolume.Spec.Source.ISCSI.SecretRef
+		}
+		if dataVolume.Spec.Source.ISCSI.InitiatorName != "" {
+			annotations[AnnISCSIInitiator] = dataVolume.Spec.Source.ISCSI.InitiatorName
+		}
+	} else if dataVolume.Spec.Source.RBD != nil {
+		image := path.Join("/", dataVolume.Spec.Source.RBD.Pool, dataVolume.Spec.Source.RBD.Image)
+		if dataVolume.Spec.Source.RBD.Snapshot != "" {
+			image += "@" + dataVolume.Spec.Source.RBD.Snapshot
+		}
+		endpoint := url.URL{
+			Scheme: "rbd",
+			Path:   image,
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceRBD
+		annotations[AnnSecret] = dataVolume.Spec.Source.RBD.SecretRef
+		annotations[AnnRBDMonitors] = strings.Join(dataVolume.Spec.Source.RBD.Monitors, ",")
+	} else if dataVolume.Spec.Source.File != nil {
+		endpoint := url.URL{
+			Scheme: "file",
+			Path:   path.Join("/", dataVolume.Spec.Source.File.Path),
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceFile
+		if dataVolume.Spec.Source.File.HostPath != "" {
+			annotations[AnnFileHostPath] = dataVolume.Spec.Source.File.HostPath
+			annotations[AnnFileNodeName] = dataVolume.Spec.Source.File.NodeName
+		} else {
+			annotations[AnnFileClaimName] = dataVolume.Spec.Source.File.ClaimName
+		}
+	} else if dataVolume.Spec.Source.Libvirt != nil {
+		annotations[AnnEndpoint] = dataVolume.Spec.Source.Libvirt.URI
+		annotations[AnnSource] = SourceLibvirt
+		annotations[AnnSecret] = dataVolume.Spec.Source.Libvirt.SecretRef
+		annotations[AnnLibvirtDomain] = dataVolume.Spec.Source.Libvirt.Domain
+		annotations[AnnLibvirtDisk] = dataVolume.Spec.Source.Libvirt.Disk
+		if dataVolume.Spec.Source.Libvirt.HostKey != "" {
+			annotations[AnnLibvirtHostKey] = dataVolume.Spec.Source.Libvirt.HostKey
+		}
+	} else if dataVolume.Spec.Source.HyperV != nil {
+		endpoint := url.URL{
+			Scheme: "smb",
+			Host:   dataVolume.Spec.Source.HyperV.Server,
+			Path:   path.Join("/", dataVolume.Spec.Source.HyperV.Share, strings.ReplaceAll(dataVolume.Spec.Source.HyperV.Path, "\\", "/")),
+		}
+		annotations[AnnEndpoint] = endpoint.String()
+		annotations[AnnSource] = SourceHyperV
+		if dataVolume.Spec.Source.HyperV.SecretRef != "" {
+			annotations[AnnSecret] = dataVolume.Spec.Source.HyperV.SecretRef
+		}
+		if dataVolume.Spec.Source.HyperV.Domain != "" {
+			annotations[AnnSMBDomain] = dataVolume.Spec.Source.HyperV.Domain
+		}
 	} else if dataVolume.Spec.Source.VDDK != nil {
 		annotations[AnnEndpoint] = dataVolume.Spec.Source.VDDK.URL
 		annotations[AnnSource] = SourceVDDK
@@ -1207,11 +1872,52 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		annotations[AnnBackingFile] = dataVolume.Spec.Source.VDDK.BackingFile
 		annotations[AnnUUID] = dataVolume.Spec.Source.VDDK.UUID
 		annotations[AnnThumbprint] = dataVolume.Spec.Source.VDDK.Thumbprint
+		if dataVolume.Spec.Source.VDDK.Connections != nil {
+			annotations[AnnVDDKConnections] = strconv.Itoa(int(*dataVolume.Spec.Source.VDDK.Connections))
+		}
 	} else {
 		return nil, errors.Errorf("no source set for datavolume")
 	}
 
-	annotations[AnnPreallocationRequested] = strconv.FormatBool(GetPreallocation(r.client, dataVolume))
+	annotations[AnnPreallocationRequested] = strconv.FormatBool(GetPreallocation(c, dataVolume))
+	if dataVolume.Spec.NodePlacement != nil {
+		nodePlacement, err := json.Marshal(dataVolume.Spec.NodePlacement)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnPodNodePlacement] = string(nodePlacement)
+	}
+	if dataVolume.Spec.PriorityClassName != "" {
+		annotations[AnnPriorityClassName] = dataVolume.Spec.PriorityClassName
+	}
+	if dataVolume.Spec.PodResourceRequirements != nil {
+		resourceRequirements, err := json.Marshal(dataVolume.Spec.PodResourceRequirements)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnPodResourceRequirements] = string(resourceRequirements)
+	}
+	if dataVolume.Spec.RetryPolicy != nil {
+		retryPolicy, err := json.Marshal(dataVolume.Spec.RetryPolicy)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnImportRetryPolicy] = string(retryPolicy)
+	}
+	if dataVolume.Spec.ImportTimeout != nil {
+		annotations[AnnImportTimeout] = dataVolume.Spec.ImportTimeout.Duration.String()
+	}
+	if dataVolume.Spec.FilesystemOverhead != nil {
+		annotations[AnnFilesystemOverhead] = string(*dataVolume.Spec.FilesystemOverhead)
+	}
+	if scratchSpace := dataVolume.Spec.ScratchSpace; scratchSpace != nil {
+		if scratchSpace.StorageClassName != "" {
+			annotations[AnnScratchStorageClass] = scratchSpace.StorageClassName
+		}
+		if scratchSpace.Size != nil {
+			annotations[AnnScratchSize] = scratchSpace.Size.String()
+		}
+	}
 
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -38,8 +38,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
@@ -49,6 +52,7 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 )
 
 var (
@@ -90,6 +94,630 @@ var _ = Describe("Datavolume controller reconcile loop", func() {
 		Expect(pvc.Name).To(Equal("test-dv"))
 	})
 
+	It("Should pass the http segmented download settings to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		segments := int32(4)
+		segmentSize := resource.MustParse("64Mi")
+		dv.Spec.Source.HTTP.Segments = &segments
+		dv.Spec.Source.HTTP.SegmentSize = &segmentSize
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnHTTPSegments]).To(Equal("4"))
+		Expect(pvc.GetAnnotations()[AnnHTTPSegmentSize]).To(Equal("64Mi"))
+	})
+
+	It("Should pass the node placement to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.NodePlacement = &sdkapi.NodePlacement{
+			NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"},
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnPodNodePlacement]).To(Equal(`{"nodeSelector":{"kubernetes.io/arch":"amd64"}}`))
+	})
+
+	It("Should pass the priority class to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.PriorityClassName = "p0"
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnPriorityClassName]).To(Equal("p0"))
+	})
+
+	It("Should pass the pod resource requirements to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.PodResourceRequirements = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnPodResourceRequirements]).To(Equal(`{"limits":{"cpu":"4"}}`))
+	})
+
+	It("Should pass the retry policy to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		maxAttempts := int32(3)
+		dv.Spec.RetryPolicy = &cdiv1.ImportRetryPolicy{MaxAttempts: &maxAttempts}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnImportRetryPolicy]).To(Equal(`{"maxAttempts":3}`))
+	})
+
+	It("Should pass the import timeout to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.ImportTimeout = &metav1.Duration{Duration: 90 * time.Minute}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnImportTimeout]).To(Equal("1h30m0s"))
+	})
+
+	It("Should pass the filesystem overhead to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		overhead := cdiv1.Percent("0.1")
+		dv.Spec.FilesystemOverhead = &overhead
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnFilesystemOverhead]).To(Equal("0.1"))
+	})
+
+	It("Should pass the scratch space to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		size := resource.MustParse("20Gi")
+		dv.Spec.ScratchSpace = &cdiv1.DataVolumeScratchSpace{StorageClassName: "local-nvme", Size: &size}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnScratchStorageClass]).To(Equal("local-nvme"))
+		Expect(pvc.GetAnnotations()[AnnScratchSize]).To(Equal("20Gi"))
+	})
+
+	It("Should pause and resume the import to the PVC along with the DataVolume", func() {
+		dv := newImportDataVolume("test-dv")
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Pausing the DataVolume")
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		dv.SetAnnotations(map[string]string{AnnImportPaused: "true"})
+		err = reconciler.client.Update(context.TODO(), dv)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()).To(HaveKeyWithValue(AnnImportPaused, "true"))
+
+		By("Resuming the DataVolume")
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		dv.SetAnnotations(nil)
+		err = reconciler.client.Update(context.TODO(), dv)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnImportPaused))
+	})
+
+	It("Should pass the ftp passive mode to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		passive := false
+		dv.Spec.Source.HTTP.URL = "ftp://ftp.example.com/images/appliance.qcow2"
+		dv.Spec.Source.HTTP.FTPPassive = &passive
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceHTTP))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("ftp://ftp.example.com/images/appliance.qcow2"))
+		Expect(pvc.GetAnnotations()[AnnFTPPassive]).To(Equal("false"))
+	})
+
+	It("Should pass the number of VDDK connections to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		connections := int32(4)
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.VDDK = &cdiv1.DataVolumeSourceVDDK{
+			URL:         "https://vcenter.example.com",
+			UUID:        "12345",
+			BackingFile: "[datastore] vm/vm.vmdk",
+			Thumbprint:  "aa:bb:cc",
+			SecretRef:   "secret",
+			Connections: &connections,
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceVDDK))
+		Expect(pvc.GetAnnotations()[AnnVDDKConnections]).To(Equal("4"))
+	})
+
+	It("Should pass the http token secret to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP.TokenSecretRef = "token-secret"
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnTokenSecret]).To(Equal("token-secret"))
+	})
+
+	It("Should pass the http client certificate secret to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP.ClientCertSecretRef = "client-cert"
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnClientCertSecret]).To(Equal("client-cert"))
+	})
+
+	It("Should pass the S3 service account to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{URL: "http://s3.amazonaws.com/bucket/disk.img", ServiceAccountName: "s3-reader"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnImportServiceAccount]).To(Equal("s3-reader"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(BeEmpty())
+	})
+
+	It("Should pass the GCS source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.GCS = &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", SecretRef: "gcs-key", ServiceAccountName: "gcs-reader"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceGCS))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("gs://bucket/disk.img"))
+		Expect(pvc.GetAnnotations()[AnnGCSSecret]).To(Equal("gcs-key"))
+		Expect(pvc.GetAnnotations()[AnnImportServiceAccount]).To(Equal("gcs-reader"))
+	})
+
+	It("Should pass the GCE image source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.GCEImage = &cdiv1.DataVolumeSourceGCEImage{Image: "projects/project/global/images/fedora", SecretRef: "gce-key"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceGCEImage))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("projects/project/global/images/fedora"))
+		Expect(pvc.GetAnnotations()[AnnGCEImageSecret]).To(Equal("gce-key"))
+	})
+
+	It("Should import the export url of the GCE image when set", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.GCEImage = &cdiv1.DataVolumeSourceGCEImage{Image: "projects/project/global/images/fedora", ExportURL: "gs://bucket/fedora.tar.gz"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("gs://bucket/fedora.tar.gz"))
+	})
+
+	It("Should pass the Glance source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Glance = &cdiv1.DataVolumeSourceGlance{AuthURL: "https://keystone.example.com:5000/v3", Project: "admin", Region: "RegionOne", ImageID: "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", SecretRef: "openstack"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceGlance))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("https://keystone.example.com:5000/v3"))
+		Expect(pvc.GetAnnotations()[AnnDiskID]).To(Equal("0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f"))
+		Expect(pvc.GetAnnotations()[AnnGlanceProject]).To(Equal("admin"))
+		Expect(pvc.GetAnnotations()[AnnGlanceRegion]).To(Equal("RegionOne"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("openstack"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnGlanceDomain))
+	})
+
+	It("Should pass the Proxmox source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Proxmox = &cdiv1.DataVolumeSourceProxmox{URL: "https://pve.example.com:8006", VMID: 100, Disk: "scsi0", SecretRef: "proxmox"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceProxmox))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("https://pve.example.com:8006"))
+		Expect(pvc.GetAnnotations()[AnnDiskID]).To(Equal("scsi0"))
+		Expect(pvc.GetAnnotations()[AnnProxmoxVMID]).To(Equal("100"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("proxmox"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnProxmoxNode))
+	})
+
+	It("Should pass the NFS source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.NFS = &cdiv1.DataVolumeSourceNFS{Server: "nfs.example.com", Path: "/export/templates/fedora.qcow2"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceNFS))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("nfs://nfs.example.com/export/templates/fedora.qcow2"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnSecret))
+	})
+
+	It("Should pass the SMB source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.SMB = &cdiv1.DataVolumeSourceSMB{Server: "fileserver", Share: "images", Path: "ISO\\Windows Server.iso", SecretRef: "smb", Domain: "CORP"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceSMB))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("smb://fileserver/images/ISO/Windows%20Server.iso"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("smb"))
+		Expect(pvc.GetAnnotations()[AnnSMBDomain]).To(Equal("CORP"))
+	})
+
+	It("Should pass the rsync source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Rsync = &cdiv1.DataVolumeSourceRsync{Host: "backup.example.com:2222", Module: "images", Path: "templates/fedora.qcow2", SecretRef: "rsync", HostKey: "ssh-ed25519 AAAA"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceRsync))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("ssh://backup.example.com:2222/templates/fedora.qcow2"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("rsync"))
+		Expect(pvc.GetAnnotations()[AnnRsyncModule]).To(Equal("images"))
+		Expect(pvc.GetAnnotations()[AnnRsyncHostKey]).To(Equal("ssh-ed25519 AAAA"))
+	})
+
+	It("Should pass the iSCSI source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.ISCSI = &cdiv1.DataVolumeSourceISCSI{Portal: "san.example.com:3260", IQN: "iqn.2003-01.org.linux-iscsi.san:p2v", LUN: 2, SecretRef: "chap", InitiatorName: "iqn.2021-01.io.kubevirt:importer"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceISCSI))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("iscsi://san.example.com:3260/iqn.2003-01.org.linux-iscsi.san:p2v/2"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("chap"))
+		Expect(pvc.GetAnnotations()[AnnISCSIInitiator]).To(Equal("iqn.2021-01.io.kubevirt:importer"))
+	})
+
+	It("Should pass the registry platform to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Registry = &cdiv1.DataVolumeSourceRegistry{URL: "docker://quay.io/kubevirt/fedora-cloud-container-disk-demo", Platform: "linux/arm64"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceRegistry))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("docker://quay.io/kubevirt/fedora-cloud-container-disk-demo"))
+		Expect(pvc.GetAnnotations()[AnnRegistryPlatform]).To(Equal("linux/arm64"))
+	})
+
+	It("Should pass the registry signature policy to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Registry = &cdiv1.DataVolumeSourceRegistry{
+			URL:       "docker://registry.example.com/images/fedora",
+			Signature: &cdiv1.DataVolumeSourceRegistrySignature{ConfigMap: "sigstore-roots", Identity: "builder@example.com", Issuer: "https://accounts.example.com"},
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnRegistrySignatureConfigMap]).To(Equal("sigstore-roots"))
+		Expect(pvc.GetAnnotations()[AnnRegistrySignatureIdentity]).To(Equal("builder@example.com"))
+		Expect(pvc.GetAnnotations()[AnnRegistrySignatureIssuer]).To(Equal("https://accounts.example.com"))
+	})
+
+	It("Should pass the RBD source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.RBD = &cdiv1.DataVolumeSourceRBD{Monitors: []string{"10.0.0.1:6789", "10.0.0.2"}, Pool: "vms", Image: "disk-1", Snapshot: "migration", SecretRef: "ceph"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceRBD))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("rbd:///vms/disk-1@migration"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("ceph"))
+		Expect(pvc.GetAnnotations()[AnnRBDMonitors]).To(Equal("10.0.0.1:6789,10.0.0.2"))
+	})
+
+	It("Should pass the file source on a host path to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.File = &cdiv1.DataVolumeSourceFile{HostPath: "/mnt/usb", NodeName: "node01", Path: "images/fedora.qcow2"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceFile))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("file:///images/fedora.qcow2"))
+		Expect(pvc.GetAnnotations()[AnnFileHostPath]).To(Equal("/mnt/usb"))
+		Expect(pvc.GetAnnotations()[AnnFileNodeName]).To(Equal("node01"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnFileClaimName))
+	})
+
+	It("Should pass the file source on a PVC to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.File = &cdiv1.DataVolumeSourceFile{ClaimName: "images", Path: "fedora.qcow2"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceFile))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("file:///fedora.qcow2"))
+		Expect(pvc.GetAnnotations()[AnnFileClaimName]).To(Equal("images"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnFileHostPath))
+	})
+
+	It("Should pass the libvirt source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.Libvirt = &cdiv1.DataVolumeSourceLibvirt{URI: "qemu+ssh://kvm01.example.com/system", Domain: "vm01", Disk: "vda", SecretRef: "kvm01", HostKey: "ssh-ed25519 AAAAC3Nza"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceLibvirt))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("qemu+ssh://kvm01.example.com/system"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("kvm01"))
+		Expect(pvc.GetAnnotations()[AnnLibvirtDomain]).To(Equal("vm01"))
+		Expect(pvc.GetAnnotations()[AnnLibvirtDisk]).To(Equal("vda"))
+		Expect(pvc.GetAnnotations()[AnnLibvirtHostKey]).To(Equal("ssh-ed25519 AAAAC3Nza"))
+	})
+
+	It("Should pass the Hyper-V source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.HyperV = &cdiv1.DataVolumeSourceHyperV{Server: "fileserver", Share: "vms", Path: "fedora\\Virtual Hard Disks\\fedora.vhdx", SecretRef: "smb", Domain: "CORP"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceHyperV))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("smb://fileserver/vms/fedora/Virtual%20Hard%20Disks/fedora.vhdx"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("smb"))
+		Expect(pvc.GetAnnotations()[AnnSMBDomain]).To(Equal("CORP"))
+	})
+
+	It("Should pass the Azure blob source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		segments := int32(8)
+		segmentSize := resource.MustParse("64Mi")
+		dv.Spec.Source.AzureBlob = &cdiv1.DataVolumeSourceAzureBlob{
+			URL:                "https://account.blob.core.windows.net/images/disk.img",
+			SecretRef:          "azure-key",
+			ServiceAccountName: "blob-reader",
+			Segments:           &segments,
+			SegmentSize:        &segmentSize,
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceAzureBlob))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("https://account.blob.core.windows.net/images/disk.img"))
+		Expect(pvc.GetAnnotations()[AnnAzureBlobSecret]).To(Equal("azure-key"))
+		Expect(pvc.GetAnnotations()[AnnImportServiceAccount]).To(Equal("blob-reader"))
+		Expect(pvc.GetAnnotations()[AnnAzureBlobSegments]).To(Equal("8"))
+		Expect(pvc.GetAnnotations()[AnnAzureBlobSegmentSize]).To(Equal("64Mi"))
+	})
+
+	It("Should pass the AWS snapshot source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.AWSSnapshot = &cdiv1.DataVolumeSourceAWSSnapshot{SnapshotID: "snap-0123456789abcdef0", Region: "us-east-1", SecretRef: "aws-creds"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceAWSSnapshot))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal("snap-0123456789abcdef0"))
+		Expect(pvc.GetAnnotations()[AnnAWSSnapshotRegion]).To(Equal("us-east-1"))
+		Expect(pvc.GetAnnotations()[AnnSecret]).To(Equal("aws-creds"))
+	})
+
+	It("Should pass the Azure disk source to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		resourceID := "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/snapshots/fedora"
+		dv.Spec.Source.AzureDisk = &cdiv1.DataVolumeSourceAzureDisk{ResourceID: resourceID, SecretRef: "azure-sp"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnSource]).To(Equal(SourceAzureDisk))
+		Expect(pvc.GetAnnotations()[AnnEndpoint]).To(Equal(resourceID))
+		Expect(pvc.GetAnnotations()[AnnAzureDiskSecret]).To(Equal("azure-sp"))
+	})
+
+	It("Should pass the S3 region, addressing style and SSE-C key to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		forcePathStyle := false
+		dv.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{
+			URL:                     "https://bucket.s3.example.com/bucket/disk.img",
+			Region:                  "eu-west-1",
+			ForcePathStyle:          &forcePathStyle,
+			SSECustomerKeySecretRef: "sse-key",
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnS3Region]).To(Equal("eu-west-1"))
+		Expect(pvc.GetAnnotations()[AnnS3ForcePathStyle]).To(Equal("false"))
+		Expect(pvc.GetAnnotations()[AnnS3SSECustomerKeySecret]).To(Equal("sse-key"))
+	})
+
+	It("Should pass the S3 object version to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		dv.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{URL: "http://s3.amazonaws.com/bucket/disk.img", ObjectVersionID: "3HL4kqtJlcpXroDTDmJ"}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnS3ObjectVersionID]).To(Equal("3HL4kqtJlcpXroDTDmJ"))
+	})
+
+	It("Should pass the S3 segmented download settings to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP = nil
+		segments := int32(8)
+		segmentSize := resource.MustParse("128Mi")
+		dv.Spec.Source.S3 = &cdiv1.DataVolumeSourceS3{URL: "http://s3.amazonaws.com/bucket/disk.img", Segments: &segments, SegmentSize: &segmentSize}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnS3Segments]).To(Equal("8"))
+		Expect(pvc.GetAnnotations()[AnnS3SegmentSize]).To(Equal("128Mi"))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnHTTPSegments))
+	})
+
+	It("Should pass the http OAuth2 settings to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Spec.Source.HTTP.OAuth2 = &cdiv1.DataVolumeSourceHTTPOAuth2{
+			TokenURL:  "https://sso.example.com/token",
+			SecretRef: "oauth2-secret",
+			Scopes:    []string{"read", "write"},
+		}
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()[AnnOAuth2TokenURL]).To(Equal("https://sso.example.com/token"))
+		Expect(pvc.GetAnnotations()[AnnOAuth2Secret]).To(Equal("oauth2-secret"))
+		Expect(pvc.GetAnnotations()[AnnOAuth2Scopes]).To(Equal("read write"))
+	})
+
+	It("Should not pass the source validators from DV to the created PVC", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.SetAnnotations(map[string]string{
+			AnnSourceETag:         "\"v1\"",
+			AnnSourceLastModified: "Wed, 01 Jan 2020 00:00:00 GMT",
+			AnnSourceVersionID:    "3HL4kqtJlcpXroDTDmJ",
+		})
+		reconciler = createDatavolumeReconciler(dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnSourceETag))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnSourceLastModified))
+		Expect(pvc.GetAnnotations()).ToNot(HaveKey(AnnSourceVersionID))
+	})
+
 	It("Should pass annotation from DV to created a PVC on a DV", func() {
 		dv := newImportDataVolume("test-dv")
 		dv.SetAnnotations(make(map[string]string))
@@ -217,6 +845,58 @@ var _ = Describe("Datavolume controller reconcile loop", func() {
 		Expect(string(dv.Status.Progress)).To(Equal("N/A"))
 	})
 
+	It("Should adopt an existing PVC when the DV allows it", func() {
+		pvc := createPvc("test-dv", metav1.NamespaceDefault, nil, nil)
+		pvc.Status.Phase = corev1.ClaimBound
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnAllowClaimAdoption: "true"}
+		reconciler = createDatavolumeReconciler(pvc, dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(pvc, dv)).To(BeTrue())
+		Expect(pvc.Annotations[AnnPopulatedFor]).To(Equal("test-dv"))
+
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Annotations[AnnPrePopulated]).To(Equal("test-dv"))
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+	})
+
+	It("Should adopt a PVC controlled by a former DV of the same name", func() {
+		former := newImportDataVolume("test-dv")
+		former.UID = "former-uid"
+		pvc := createPvc("test-dv", metav1.NamespaceDefault, nil, nil)
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(former, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnAllowClaimAdoption: "true"}
+		reconciler = createDatavolumeReconciler(pvc, dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.OwnerReferences).To(HaveLen(1))
+		Expect(pvc.OwnerReferences[0].UID).To(Equal(dv.UID))
+	})
+
+	It("Should not adopt a PVC controlled by another object", func() {
+		owner := createPvc("owner", metav1.NamespaceDefault, nil, nil)
+		pvc := createPvc("test-dv", metav1.NamespaceDefault, nil, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))}
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnAllowClaimAdoption: "true"}
+		reconciler = createDatavolumeReconciler(pvc, dv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).To(HaveOccurred())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring("Resource \"test-dv\" already exists and is not managed by DataVolume"))
+	})
+
 	It("Should create a snapshot if cloning and the PVC doesn't exist, and the snapshot class can be found", func() {
 		dv := newCloneDataVolume("test-dv")
 		scName := "testsc"
@@ -238,6 +918,132 @@ var _ = Describe("Datavolume controller reconcile loop", func() {
 		Expect(dv.Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
 	})
 
+	It("Should copy over the network instead of creating a snapshot if the source is pvcNetwork", func() {
+		dv := newNetworkCopyDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		dv.Spec.PVC.StorageClassName = &scName
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+		snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+		reconciler := createDatavolumeReconciler(sc, dv, pvc, snapClass)
+		reconciler.extClientSet = extfake.NewSimpleClientset(createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying that the target PVC requests a host assisted clone")
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.GetAnnotations()[AnnCloneRequest]).To(Equal("default/test"))
+		Expect(targetPvc.GetAnnotations()[AnnCloneToken]).To(Equal("foobar"))
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Status.Phase).ToNot(Equal(cdiv1.SnapshotForSmartCloneInProgress))
+	})
+
+	It("Should pass the CloneGrant of a clone to the target PVC in place of a clone token", func() {
+		dv := newCloneDataVolumeWithPVCNS("test-dv", "source")
+		dv.Annotations = map[string]string{AnnCloneGrant: "grant"}
+		scName := "testsc"
+		sc := createStorageClass(scName, map[string]string{AnnDefaultStorageClass: "true"})
+		dv.Spec.PVC.StorageClassName = &scName
+		pvc := createPvcInStorageClass("test", "source", &scName, nil, nil, corev1.ClaimBound)
+		reconciler := createDatavolumeReconciler(sc, dv, pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("source/test"))
+		Expect(targetPvc.Annotations[AnnCloneGrant]).To(Equal("grant"))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(AnnCloneToken))
+	})
+
+	DescribeTable("Should size the PVC of a clone", func(sourceVolumeMode corev1.PersistentVolumeMode, requested string, grown bool) {
+		dv := newCloneDataVolume("test-dv")
+		dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)}
+		source := createPvc("test", metav1.NamespaceDefault, nil, nil)
+		source.Spec.VolumeMode = &sourceVolumeMode
+		reconciler = createDatavolumeReconciler(dv, source)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if grown {
+			Expect(size.Cmp(resource.MustParse(requested))).To(Equal(1))
+		} else {
+			Expect(size.Cmp(resource.MustParse(requested))).To(Equal(0))
+		}
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse(requested)))
+	},
+		Entry("growing it for the disk image of a block source", corev1.PersistentVolumeBlock, "1G", true),
+		Entry("keeping a size large enough for the disk image of a block source", corev1.PersistentVolumeBlock, "2G", false),
+		Entry("keeping the size for a filesystem source", corev1.PersistentVolumeFilesystem, "1G", false),
+	)
+
+	It("Should wait for the source snapshot to be ready", func() {
+		snapshot := newSourceSnapshot("snapshot", metav1.NamespaceDefault, false)
+		reconciler = createDatavolumeReconciler(newSnapshotDataVolume("test-dv", metav1.NamespaceDefault), snapshot)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(snapshotSourceRetryInterval))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(SnapshotSourceNotReady))
+	})
+
+	It("Should restore the source snapshot when its driver provisions the storage class of the DV", func() {
+		snapshot := newSourceSnapshot("snapshot", metav1.NamespaceDefault, true)
+		sc := createStorageClassWithProvisioner("testsc", map[string]string{AnnDefaultStorageClass: "true"}, "csi-plugin")
+		reconciler = createDatavolumeReconciler(newSnapshotDataVolume("test-dv", metav1.NamespaceDefault), snapshot, newSnapshotContent("content", "csi-plugin"), sc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.DataSource).ToNot(BeNil())
+		Expect(pvc.Spec.DataSource.Kind).To(Equal("VolumeSnapshot"))
+		Expect(pvc.Spec.DataSource.Name).To(Equal("snapshot"))
+		Expect(pvc.Annotations[AnnPopulatedFor]).To(Equal("test-dv"))
+		Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneRequest))
+	})
+
+	DescribeTable("Should copy the source snapshot restored to a temporary PVC", func(snapshotNamespace, provisioner, restoreStorageClass string) {
+		snapshot := newSourceSnapshot("snapshot", snapshotNamespace, true)
+		sc := createStorageClassWithProvisioner("testsc", map[string]string{AnnDefaultStorageClass: "true"}, provisioner)
+		snapshotSc := createStorageClassWithProvisioner("snapshotsc", nil, "csi-plugin")
+		dv := newSnapshotDataVolume("test-dv", snapshotNamespace)
+		reconciler = createDatavolumeReconciler(dv, snapshot, newSnapshotContent("content", "csi-plugin"), sc, snapshotSc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.DataSource).To(BeNil())
+		restorePvcName := snapshotRestorePvcPrefix + string(dv.UID)
+		Expect(pvc.Annotations[AnnCloneRequest]).To(Equal(snapshotNamespace + "/" + restorePvcName))
+		Expect(pvc.Annotations[AnnCloneToken]).To(Equal("foobar"))
+
+		restorePvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: restorePvcName, Namespace: snapshotNamespace}, restorePvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restorePvc.Spec.DataSource.Name).To(Equal("snapshot"))
+		Expect(*restorePvc.Spec.StorageClassName).To(Equal(restoreStorageClass))
+		Expect(restorePvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1Gi")))
+		Expect(isSnapshotRestoreFor(restorePvc, pvc)).To(BeTrue())
+	},
+		Entry("when the driver of the snapshot does not provision the storage class of the DV", metav1.NamespaceDefault, "other-plugin", "snapshotsc"),
+		Entry("when the snapshot is in another namespace", "snapshotns", "csi-plugin", "testsc"),
+	)
+
 	DescribeTable("Should NOT create a snapshot if source PVC mounted", func(podFunc func(*cdiv1.DataVolume) *corev1.Pod) {
 		dv := newCloneDataVolume("test-dv")
 		scName := "testsc"
@@ -443,7 +1249,154 @@ var _ = Describe("Datavolume controller reconcile loop", func() {
 		}, func(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) {
 			Expect(pvc.GetAnnotations()[AnnCurrentCheckpoint]).To(Equal("current"))
 		}),
+		Entry("should report the copy of each checkpoint in the status", false, func(annotations map[string]string) {
+			delete(annotations, AnnCheckpointsCopied+"."+"current")
+			annotations[AnnPodPhase] = string(corev1.PodRunning)
+		}, func(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) {
+			Expect(dv.Status.Checkpoints).To(HaveLen(4))
+			for _, status := range dv.Status.Checkpoints[:3] {
+				Expect(status.Phase).To(Equal(cdiv1.Succeeded))
+				Expect(status.Progress).To(BeEquivalentTo("100.0%"))
+				Expect(status.CompletionTime).ToNot(BeNil())
+			}
+			status := dv.Status.Checkpoints[3]
+			Expect(status.Current).To(Equal("current"))
+			Expect(status.Previous).To(Equal("previous"))
+			Expect(status.Phase).To(Equal(cdiv1.ImportInProgress))
+			Expect(status.StartTime).ToNot(BeNil())
+			Expect(status.CompletionTime).To(BeNil())
+		}),
+		Entry("should keep the checkpoints copied in the status once the multi-stage import is done", true, nil, func(pvc *corev1.PersistentVolumeClaim, dv *cdiv1.DataVolume) {
+			// Extra reconcile once the multistage migration annotations are cleared
+			reconciler = createDatavolumeReconciler(dv, pvc)
+			_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			newDv := &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, newDv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newDv.Status.Checkpoints).To(HaveLen(4))
+			for _, status := range newDv.Status.Checkpoints {
+				Expect(status.Phase).To(Equal(cdiv1.Succeeded))
+			}
+		}),
+	)
+})
+
+var _ = Describe("DataVolume garbage collection", func() {
+	var (
+		reconciler *DatavolumeReconciler
 	)
+	AfterEach(func() {
+		if reconciler != nil {
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+			reconciler = nil
+		}
+	})
+
+	succeededDataVolume := func(completed time.Time) (*cdiv1.DataVolume, *corev1.PersistentVolumeClaim) {
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{"test-annotation": "dv", "shared-annotation": "dv"}
+		dv.Status.Phase = cdiv1.Succeeded
+		dv.Status.Conditions = []cdiv1.DataVolumeCondition{{
+			Type:               cdiv1.DataVolumeReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(completed),
+		}}
+		pvc := createPvc("test-dv", metav1.NamespaceDefault, map[string]string{AnnPodPhase: string(corev1.PodSucceeded), "shared-annotation": "pvc"}, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		return dv, pvc
+	}
+
+	setDataVolumeTTL := func(ttlSeconds int32) {
+		cdiConfig := &cdiv1.CDIConfig{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+		cdiConfig.Spec.DataVolumeTTLSeconds = &ttlSeconds
+		err = reconciler.client.Update(context.TODO(), cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("Should keep the Succeeded DataVolumes without TTL", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Hour))
+		reconciler = createDatavolumeReconciler(dv, pvc)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should requeue the Succeeded DataVolumes until their TTL expires", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Minute))
+		reconciler = createDatavolumeReconciler(dv, pvc)
+		setDataVolumeTTL(3600)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 59*time.Minute, time.Minute))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should delete the DataVolume once its TTL expired and keep the PVC", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Hour))
+		reconciler = createDatavolumeReconciler(dv, pvc)
+		setDataVolumeTTL(60)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, &cdiv1.DataVolume{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		pvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.OwnerReferences).To(BeEmpty())
+		Expect(pvc.Annotations["test-annotation"]).To(Equal("dv"))
+		Expect(pvc.Annotations["shared-annotation"]).To(Equal("pvc"))
+		Expect(pvc.Annotations[AnnPopulatedFor]).To(Equal("test-dv"))
+	})
+
+	It("Should keep the DataVolumes whose PVC is used by a pod after their TTL", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Hour))
+		pod := podUsingPVC(pvc, false)
+		reconciler = createDatavolumeReconciler(dv, pvc, pod)
+		setDataVolumeTTL(60)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(dataVolumeInUseRetryInterval))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should keep the DataVolumes referenced by a VirtualMachine after their TTL", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Hour))
+		vm := &unstructured.Unstructured{}
+		vm.SetGroupVersionKind(schema.GroupVersionKind{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachine"})
+		vm.SetName("test-vm")
+		vm.SetNamespace(metav1.NamespaceDefault)
+		err := unstructured.SetNestedSlice(vm.Object, []interface{}{
+			map[string]interface{}{"name": "rootdisk", "dataVolume": map[string]interface{}{"name": "test-dv"}},
+		}, "spec", "template", "spec", "volumes")
+		Expect(err).ToNot(HaveOccurred())
+		reconciler = createDatavolumeReconciler(dv, pvc, vm)
+		setDataVolumeTTL(60)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(dataVolumeInUseRetryInterval))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should keep the DataVolumes controlled by another object", func() {
+		dv, pvc := succeededDataVolume(time.Now().Add(-time.Hour))
+		owner := createPvc("owner", metav1.NamespaceDefault, nil, nil)
+		dv.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))}
+		reconciler = createDatavolumeReconciler(dv, pvc)
+		setDataVolumeTTL(60)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+	})
 })
 
 var _ = Describe("Reconcile Datavolume status", func() {
@@ -736,7 +1689,15 @@ var _ = Describe("Reconcile Datavolume status", func() {
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(dv.Status.Phase).To(Equal(expected))
-		Expect(len(dv.Status.Conditions)).To(Equal(3))
+		if dv.Spec.Source.PVC != nil {
+			// Without the snapshot CRDs the clone falls back to a host-assisted copy
+			Expect(len(dv.Status.Conditions)).To(Equal(4))
+			fallbackCondition := findConditionByType(cdiv1.DataVolumeSmartCloneFallback, dv.Status.Conditions)
+			Expect(fallbackCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(fallbackCondition.Reason).To(Equal(SmartCloneNoSnapshotCRDs))
+		} else {
+			Expect(len(dv.Status.Conditions)).To(Equal(3))
+		}
 		boundCondition := findConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
 		Expect(boundCondition.Status).To(Equal(boundStatusByPVCPhase(pvcPhase)))
 		Expect(boundCondition.Message).To(Equal(boundMessageByPVCPhase(pvcPhase, "test-dv")))
@@ -757,7 +1718,9 @@ var _ = Describe("Reconcile Datavolume status", func() {
 		Entry("should switch to bound for import", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.PVCBound, corev1.ClaimBound, corev1.PodPending, "invalid", "PVC test-dv Bound"),
 		Entry("should switch to bound for import", newImportDataVolume("test-dv"), cdiv1.Unknown, cdiv1.PVCBound, corev1.ClaimBound, corev1.PodPending, "invalid", "PVC test-dv Bound"),
 		Entry("should switch to scheduled for import", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportScheduled, corev1.ClaimBound, corev1.PodPending, AnnImportPod, "Import into test-dv scheduled"),
+		Entry("should switch to pending for queued import", newImportDataVolume("test-dv"), cdiv1.ImportScheduled, cdiv1.Pending, corev1.ClaimBound, corev1.PodPending, AnnImportPod, "Import into test-dv queued", AnnImportQueued, "true"),
 		Entry("should switch to inprogress for import", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.ImportInProgress, corev1.ClaimBound, corev1.PodRunning, AnnImportPod, "Import into test-dv in progress"),
+		Entry("should switch to paused for paused import", newImportDataVolume("test-dv"), cdiv1.ImportInProgress, cdiv1.Paused, corev1.ClaimBound, corev1.PodPending, AnnImportPod, "Import into PVC test-dv is paused", AnnImportPaused, "true"),
 		Entry("should switch to failed for import", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimBound, corev1.PodFailed, AnnImportPod, "Failed to import into PVC test-dv"),
 		Entry("should switch to failed on claim lost for impot", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimLost, corev1.PodFailed, AnnImportPod, "PVC test-dv lost"),
 		Entry("should switch to succeeded for import", newImportDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnImportPod, "Successfully imported into PVC test-dv"),
@@ -766,6 +1729,10 @@ var _ = Describe("Reconcile Datavolume status", func() {
 		Entry("should switch to failed for clone", newCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimBound, corev1.PodFailed, AnnCloneRequest, "Cloning from default/test into default/test-dv failed"),
 		Entry("should switch to failed on claim lost for clone", newCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimLost, corev1.PodFailed, AnnCloneRequest, "PVC test-dv lost"),
 		Entry("should switch to succeeded for clone", newCloneDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnCloneRequest, "Successfully cloned from default/test into default/test-dv"),
+		Entry("should switch to scheduled for network copy", newNetworkCopyDataVolume("test-dv"), cdiv1.Pending, cdiv1.CloneScheduled, corev1.ClaimBound, corev1.PodPending, AnnCloneRequest, "Copying from default/test into default/test-dv over the network scheduled"),
+		Entry("should switch to clone in progress for network copy", newNetworkCopyDataVolume("test-dv"), cdiv1.Pending, cdiv1.CloneInProgress, corev1.ClaimBound, corev1.PodRunning, AnnCloneRequest, "Copying from default/test into default/test-dv over the network in progress"),
+		Entry("should switch to failed for network copy", newNetworkCopyDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimBound, corev1.PodFailed, AnnCloneRequest, "Copying from default/test into default/test-dv over the network failed"),
+		Entry("should switch to succeeded for network copy", newNetworkCopyDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnCloneRequest, "Successfully copied from default/test into default/test-dv over the network"),
 		Entry("should switch to scheduled for upload", newUploadDataVolume("test-dv"), cdiv1.Pending, cdiv1.UploadScheduled, corev1.ClaimBound, corev1.PodPending, AnnUploadRequest, "Upload into test-dv scheduled"),
 		Entry("should switch to uploadready for upload", newUploadDataVolume("test-dv"), cdiv1.Pending, cdiv1.UploadReady, corev1.ClaimBound, corev1.PodRunning, AnnUploadRequest, "Upload into test-dv ready", AnnPodReady, "true"),
 		Entry("should switch to failed for upload", newUploadDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimBound, corev1.PodFailed, AnnUploadRequest, "Upload into test-dv failed"),
@@ -777,6 +1744,41 @@ var _ = Describe("Reconcile Datavolume status", func() {
 		Entry("should switch to failed on claim lost for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.Failed, corev1.ClaimLost, corev1.PodFailed, AnnImportPod, "PVC test-dv lost"),
 		Entry("should switch to succeeded for blank", newBlankImageDataVolume("test-dv"), cdiv1.Pending, cdiv1.Succeeded, corev1.ClaimBound, corev1.PodSucceeded, AnnImportPod, "Successfully imported into PVC test-dv"),
 	)
+
+	DescribeTable("Should keep the bytes transferred but no estimate once succeeded", func(testDv runtime.Object, ann string) {
+		reconciler = createDatavolumeReconciler(testDv)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		dv := &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		completion := metav1.NewTime(time.Now().Add(time.Minute))
+		dv.Status.Transfer = &cdiv1.DataVolumeTransferStatus{BytesTransferred: 4096, BytesPerSecond: 1024, EstimatedCompletionTime: &completion}
+		err = reconciler.client.Update(context.TODO(), dv)
+		Expect(err).ToNot(HaveOccurred())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.SetAnnotations(map[string]string{ann: "something", AnnPodPhase: string(corev1.PodSucceeded)})
+
+		_, err = reconciler.reconcileDataVolumeStatus(dv, pvc)
+		Expect(err).ToNot(HaveOccurred())
+
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+		Expect(dv.Status.Progress).To(BeEquivalentTo("100.0%"))
+		Expect(dv.Status.Transfer).ToNot(BeNil())
+		Expect(dv.Status.Transfer.BytesTransferred).To(BeEquivalentTo(4096))
+		Expect(dv.Status.Transfer.BytesPerSecond).To(BeZero())
+		Expect(dv.Status.Transfer.EstimatedCompletionTime).To(BeNil())
+	},
+		Entry("for import", newImportDataVolume("test-dv"), AnnImportPod),
+		Entry("for clone", newCloneDataVolume("test-dv"), AnnCloneRequest),
+	)
 })
 
 var _ = Describe("sourcePVCPopulated", func() {
@@ -918,6 +1920,7 @@ var _ = Describe("Smart clone", func() {
 		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("CSI snapshot CRDs not found"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneNoSnapshotCRDs))
 		Expect(snapclass).To(BeEmpty())
 	})
 
@@ -943,6 +1946,7 @@ var _ = Describe("Smart clone", func() {
 		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("Target PVC storage class not found"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneStorageClassMismatch))
 		Expect(snapclass).To(BeEmpty())
 	})
 
@@ -963,6 +1967,24 @@ var _ = Describe("Smart clone", func() {
 		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("source PVC and target PVC belong to different storage classes"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneStorageClassMismatch))
+		Expect(snapclass).To(BeEmpty())
+	})
+
+	It("Should not return storage class, if source PVC has no storage class", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClass(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		})
+		dv.Spec.PVC.StorageClassName = &scName
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, nil, nil, nil, corev1.ClaimBound)
+		reconciler := createDatavolumeReconciler(sc, dv, pvc)
+		reconciler.extClientSet = extfake.NewSimpleClientset(createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("source PVC and target PVC belong to different storage classes"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneStorageClassMismatch))
 		Expect(snapclass).To(BeEmpty())
 	})
 
@@ -980,6 +2002,7 @@ var _ = Describe("Smart clone", func() {
 		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("source PVC and target PVC belong to different namespaces"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneCrossNamespace))
 		Expect(snapclass).To(BeEmpty())
 	})
 
@@ -996,6 +2019,25 @@ var _ = Describe("Smart clone", func() {
 		Expect(snapclass).To(BeEmpty())
 	})
 
+	It("Should not return storage class, if the target PVC is smaller than the source PVC", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		dv.Spec.PVC.StorageClassName = &scName
+		dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("500M")}
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+		snapClass := createSnapshotClass("snap-class", nil, "csi-plugin")
+		reconciler := createDatavolumeReconciler(sc, dv, pvc, snapClass)
+		reconciler.extClientSet = extfake.NewSimpleClientset(createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("target PVC size 500M is smaller than the source PVC size 1G"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneSizeMismatch))
+		Expect(snapclass).To(BeEmpty())
+	})
+
 	It("Should not return storage class, if storage class does not exist", func() {
 		dv := newCloneDataVolume("test-dv")
 		scName := "testsc"
@@ -1009,6 +2051,7 @@ var _ = Describe("Smart clone", func() {
 		snapclass, err := reconciler.getSnapshotClassForSmartClone(dv)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("could not match snapshotter with storage class, falling back to host assisted clone"))
+		Expect(err.(*smartCloneFallbackError).reason).To(Equal(SmartCloneNoVolumeSnapshotClass))
 		Expect(snapclass).To(BeEmpty())
 	})
 
@@ -1029,11 +2072,60 @@ var _ = Describe("Smart clone", func() {
 		Expect(snapclass).To(Equal(expectedSnapshotClass))
 	})
 
+	It("Should record why the clone falls back to a host-assisted copy", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		dv.Spec.PVC.StorageClassName = &scName
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+		reconciler := createDatavolumeReconciler(sc, dv, pvc)
+		reconciler.extClientSet = extfake.NewSimpleClientset(createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+		Expect(targetPvc.Annotations[AnnSmartCloneFallbackReason]).To(Equal(SmartCloneNoVolumeSnapshotClass))
+
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		condition := findConditionByType(cdiv1.DataVolumeSmartCloneFallback, dv.Status.Conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(SmartCloneNoVolumeSnapshotClass))
+		Expect(condition.Message).To(ContainSubstring("could not match snapshotter with storage class"))
+	})
+
+	It("Should not record a fallback when the clone strategy is copy", func() {
+		dv := newCloneDataVolume("test-dv")
+		pvc := createPvc("test", metav1.NamespaceDefault, nil, nil)
+		reconciler := createDatavolumeReconciler(dv, pvc)
+		cr := &cdiv1.CDI{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)
+		Expect(err).ToNot(HaveOccurred())
+		copyStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)
+		cr.Spec.CloneStrategyOverride = &copyStrategy
+		err = reconciler.client.Update(context.TODO(), cr)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findConditionByType(cdiv1.DataVolumeSmartCloneFallback, dv.Status.Conditions)).To(BeNil())
+	})
+
 	It("Clone strategy should default to snapshot", func() {
 		dv := newImportDataVolume("test-dv")
 		reconciler := createDatavolumeReconciler(dv)
 		reconciler.extClientSet = extfake.NewSimpleClientset(createVolumeSnapshotContentCrd(), createVolumeSnapshotClassCrd(), createVolumeSnapshotCrd())
-		cloneStrategy, err := reconciler.getCloneStrategy()
+		cloneStrategy, err := reconciler.getCloneStrategy(dv)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(cloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)))
 	})
@@ -1051,14 +2143,145 @@ var _ = Describe("Smart clone", func() {
 		err = reconciler.client.Update(context.TODO(), cr)
 		Expect(err).ToNot(HaveOccurred())
 
-		cloneStrategy, err := reconciler.getCloneStrategy()
+		cloneStrategy, err := reconciler.getCloneStrategy(dv)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(cloneStrategy).To(Equal(expectedCloneStrategy))
 	},
 		Entry("snapshot", cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)),
 		Entry("copy", cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)),
+		Entry("csi-clone", cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)),
 	)
 
+	It("Clone strategy should come from the StorageProfile of the storage class", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+		storageProfile := MakeEmptyStorageProfileSpec(scName)
+		storageProfile.Status.CloneStrategy = &csiClone
+		reconciler := createDatavolumeReconciler(sc, storageProfile, dv)
+
+		cloneStrategy, err := reconciler.getCloneStrategy(dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+	})
+
+	It("Clone strategy of the StorageProfile should win over the override of the CDI", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+		storageProfile := MakeEmptyStorageProfileSpec(scName)
+		storageProfile.Status.CloneStrategy = &csiClone
+		reconciler := createDatavolumeReconciler(sc, storageProfile, dv)
+
+		cr := &cdiv1.CDI{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)
+		Expect(err).ToNot(HaveOccurred())
+		copyStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)
+		cr.Spec.CloneStrategyOverride = &copyStrategy
+		err = reconciler.client.Update(context.TODO(), cr)
+		Expect(err).ToNot(HaveOccurred())
+
+		cloneStrategy, err := reconciler.getCloneStrategy(dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+	})
+
+	It("Clone strategy override of the CDI should be used when the StorageProfile sets none", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		storageProfile := MakeEmptyStorageProfileSpec(scName)
+		reconciler := createDatavolumeReconciler(sc, storageProfile, dv)
+
+		cr := &cdiv1.CDI{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)
+		Expect(err).ToNot(HaveOccurred())
+		copyStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)
+		cr.Spec.CloneStrategyOverride = &copyStrategy
+		err = reconciler.client.Update(context.TODO(), cr)
+		Expect(err).ToNot(HaveOccurred())
+
+		cloneStrategy, err := reconciler.getCloneStrategy(dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)))
+	})
+
+	setCloneStrategy := func(reconciler *DatavolumeReconciler, strategy cdiv1.CDICloneStrategy) {
+		cr := &cdiv1.CDI{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)
+		Expect(err).ToNot(HaveOccurred())
+		cr.Spec.CloneStrategyOverride = &strategy
+		err = reconciler.client.Update(context.TODO(), cr)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("Should clone the source PVC with the CSI driver with the csi-clone strategy", func() {
+		dv := newCloneDataVolume("test-dv")
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		dv.Spec.PVC.StorageClassName = &scName
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+		reconciler := createDatavolumeReconciler(sc, dv, pvc)
+		setCloneStrategy(reconciler, cdiv1.CloneStrategyCsiClone)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.Spec.DataSource).ToNot(BeNil())
+		Expect(targetPvc.Spec.DataSource.Kind).To(Equal("PersistentVolumeClaim"))
+		Expect(targetPvc.Spec.DataSource.Name).To(Equal("test"))
+		Expect(targetPvc.Annotations[AnnPopulatedFor]).To(Equal("test-dv"))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(AnnCloneRequest))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(AnnCloneToken))
+		Expect(targetPvc.Annotations).ToNot(HaveKey(AnnSmartCloneFallbackReason))
+
+		By("Succeeding once the PVC is bound")
+		targetPvc.Status.Phase = corev1.ClaimBound
+		err = reconciler.client.Update(context.TODO(), targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+		dv = &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Status.Phase).To(Equal(cdiv1.Succeeded))
+	})
+
+	It("Should fall back to a host-assisted clone with the csi-clone strategy across storage classes", func() {
+		dv := newCloneDataVolume("test-dv")
+		targetSc := "testsc"
+		tsc := createStorageClassWithProvisioner(targetSc, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, "csi-plugin")
+		dv.Spec.PVC.StorageClassName = &targetSc
+		sourceSc := "testsc2"
+		ssc := createStorageClassWithProvisioner(sourceSc, nil, "csi-plugin")
+		pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &sourceSc, nil, nil, corev1.ClaimBound)
+		reconciler := createDatavolumeReconciler(ssc, tsc, dv, pvc)
+		setCloneStrategy(reconciler, cdiv1.CloneStrategyCsiClone)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+		Expect(err).ToNot(HaveOccurred())
+
+		targetPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, targetPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetPvc.Spec.DataSource).To(BeNil())
+		Expect(targetPvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+		Expect(targetPvc.Annotations[AnnSmartCloneFallbackReason]).To(Equal(SmartCloneStorageClassMismatch))
+	})
+
 })
 
 var _ = Describe("Get Pod from PVC", func() {
@@ -1119,6 +2342,198 @@ var _ = Describe("Get Pod from PVC", func() {
 	})
 })
 
+var _ = Describe("Import source info", func() {
+	It("Should return the source info recorded on the PVC", func() {
+		pvc := createPvc("test", metav1.NamespaceDefault, map[string]string{
+			AnnSourceFormat:         "qcow2",
+			AnnSourceVirtualSize:    "10737418240",
+			AnnSourceDownloadedSize: "524288000",
+		}, nil)
+		info := getImportSourceInfo(pvc)
+		Expect(info).ToNot(BeNil())
+		Expect(info.Format).To(Equal("qcow2"))
+		Expect(info.Compression).To(BeEmpty())
+		Expect(info.VirtualSize.String()).To(Equal("10Gi"))
+		Expect(info.DownloadedSize.String()).To(Equal("500Mi"))
+	})
+
+	It("Should return nil if the importer recorded no source info", func() {
+		pvc := createPvc("test", metav1.NamespaceDefault, nil, nil)
+		Expect(getImportSourceInfo(pvc)).To(BeNil())
+	})
+})
+
+var _ = Describe("Import size detection", func() {
+	newSizedImportDataVolume := func() *cdiv1.DataVolume {
+		dv := newImportDataVolume("test-dv")
+		delete(dv.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+		return dv
+	}
+
+	It("Should create the PVC with the virtual size reported by the probe pod", func() {
+		reconciler := createDatavolumeReconciler(newSizedImportDataVolume())
+		result, pvcErr := reconcileProbedDataVolume(reconciler)
+		Expect(result.Requeue).To(BeFalse())
+		Expect(k8serrors.IsNotFound(pvcErr)).To(BeTrue())
+
+		completeProbePod(reconciler, 0, "Probe Complete\n"+SourceFormatMessagePrefix+"qcow2\n"+SourceVirtualSizeMessagePrefix+"10737418240")
+		_, pvcErr = reconcileProbedDataVolume(reconciler)
+		Expect(pvcErr).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+		dv := &cdiv1.DataVolume{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Spec.PVC.Resources.Requests).ToNot(HaveKey(corev1.ResourceStorage))
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: importProbePodName(dv), Namespace: metav1.NamespaceDefault}, &corev1.Pod{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not create the PVC if the probe pod fails", func() {
+		reconciler := createDatavolumeReconciler(newSizedImportDataVolume())
+		reconcileProbedDataVolume(reconciler)
+		completeProbePod(reconciler, 1, "Unable to probe data: the header of the disk image does not hold its virtual size")
+		result, pvcErr := reconcileProbedDataVolume(reconciler)
+		Expect(result.RequeueAfter).To(Equal(importSizeDetectionRetryInterval))
+		Expect(k8serrors.IsNotFound(pvcErr)).To(BeTrue())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ImportSizeDetectionFailed))
+		Expect(event).To(ContainSubstring("does not hold its virtual size"))
+
+		// The next attempt probes the source again
+		reconcileProbedDataVolume(reconciler)
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: importProbePodName(newSizedImportDataVolume()), Namespace: metav1.NamespaceDefault}, &corev1.Pod{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should detect the size of the sources of disk images without a PVC size", func() {
+		Expect(importSizeDetectionRequired(newImportDataVolume("test-dv"))).To(BeFalse())
+		Expect(importSizeDetectionRequired(newSizedImportDataVolume())).To(BeTrue())
+		dv := newSizedImportDataVolume()
+		dv.Spec.Source = cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{URL: "docker://registry:5000/test"}}
+		Expect(importSizeDetectionRequired(dv)).To(BeTrue())
+		dv = newSizedImportDataVolume()
+		dv.Spec.ContentType = cdiv1.DataVolumeArchive
+		Expect(importSizeDetectionRequired(dv)).To(BeFalse())
+		dv = newUploadDataVolume("test-dv")
+		delete(dv.Spec.PVC.Resources.Requests, corev1.ResourceStorage)
+		Expect(importSizeDetectionRequired(dv)).To(BeFalse())
+	})
+
+	It("Should add the filesystem overhead to the virtual size", func() {
+		Expect(importPvcSize(1073741824, 0).String()).To(Equal("1Gi"))
+		size := importPvcSize(1073741824, 0.055)
+		Expect(size.Value() % importSizeAlignment).To(BeZero())
+		Expect(size.Value()).To(BeNumerically(">=", 1073741824/0.945))
+		Expect(size.Value()).To(BeNumerically("<", 1073741824/0.945+importSizeAlignment))
+	})
+})
+
+var _ = Describe("Import preflight", func() {
+	newPreflightDataVolume := func() *cdiv1.DataVolume {
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnImportPreflight: "true"}
+		return dv
+	}
+
+	It("Should create the PVC once the source passes the check of the probe pod", func() {
+		reconciler := createDatavolumeReconciler(newPreflightDataVolume())
+		_, pvcErr := reconcileProbedDataVolume(reconciler)
+		Expect(k8serrors.IsNotFound(pvcErr)).To(BeTrue())
+		completeProbePod(reconciler, 0, "Probe Complete")
+		_, pvcErr = reconcileProbedDataVolume(reconciler)
+		Expect(pvcErr).ToNot(HaveOccurred())
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1G"))
+	})
+
+	It("Should not create the PVC if the source fails the check", func() {
+		reconciler := createDatavolumeReconciler(newPreflightDataVolume())
+		reconcileProbedDataVolume(reconciler)
+		completeProbePod(reconciler, 1, "Unable to connect to http data source: expected status code 200, got 401. Status: 401 Unauthorized")
+		result, pvcErr := reconcileProbedDataVolume(reconciler)
+		Expect(k8serrors.IsNotFound(pvcErr)).To(BeTrue())
+		Expect(result.RequeueAfter).To(Equal(importPreflightRetryInterval))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ImportPreflightFailed))
+		Expect(event).To(ContainSubstring("401"))
+	})
+
+	It("Should only check the sources of the DataVolumes opting in", func() {
+		Expect(importPreflightRequired(newImportDataVolume("test-dv"))).To(BeFalse())
+		Expect(importPreflightRequired(newPreflightDataVolume())).To(BeTrue())
+		dv := newUploadDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnImportPreflight: "true"}
+		Expect(importPreflightRequired(dv)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Import probe pod", func() {
+	It("Should run the importer without the PVC, in the namespace of the DataVolume", func() {
+		dv := newImportDataVolume("test-dv")
+		dv.Annotations = map[string]string{AnnImportPreflight: "true"}
+		dv.Spec.Source.HTTP.SecretRef = "credentials"
+		dv.Spec.Source.HTTP.CertConfigMap = "server-ca"
+		reconciler := createDatavolumeReconciler(dv)
+		reconcileProbedDataVolume(reconciler)
+
+		pod := &corev1.Pod{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: importProbePodName(dv), Namespace: metav1.NamespaceDefault}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(pod, dv)).To(BeTrue())
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(*pod.Spec.ActiveDeadlineSeconds).To(Equal(importProbeDeadlineSeconds))
+		volumes := []string{}
+		for _, volume := range pod.Spec.Volumes {
+			Expect(volume.PersistentVolumeClaim).To(BeNil())
+			volumes = append(volumes, volume.Name)
+		}
+		Expect(volumes).To(ContainElement(CertVolName))
+		env := map[string]corev1.EnvVar{}
+		for _, envVar := range pod.Spec.Containers[0].Env {
+			env[envVar.Name] = envVar
+		}
+		Expect(env[common.ImporterProbe].Value).To(Equal("true"))
+		Expect(env[common.ImporterEndpoint].Value).To(Equal("http://example.com/data"))
+		Expect(env[common.ImporterAccessKeyID].ValueFrom.SecretKeyRef.Name).To(Equal("credentials"))
+	})
+
+	It("Should not report a result while the probe pod runs", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+		Expect(importProbePodResult(pod)).To(BeNil())
+	})
+
+	It("Should report the failure of a probe pod that did not run the importer", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "DeadlineExceeded"}}
+		result := importProbePodResult(pod)
+		Expect(result).ToNot(BeNil())
+		Expect(result.err).To(MatchError(ContainSubstring("DeadlineExceeded")))
+	})
+})
+
+// reconcileProbedDataVolume reconciles the test-dv DataVolume and returns the error getting its PVC
+func reconcileProbedDataVolume(reconciler *DatavolumeReconciler) (reconcile.Result, error) {
+	result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+	Expect(err).ToNot(HaveOccurred())
+	pvc := &corev1.PersistentVolumeClaim{}
+	return result, reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+}
+
+// completeProbePod terminates the probe pod of the test-dv DataVolume with the exit code and message
+func completeProbePod(reconciler *DatavolumeReconciler, exitCode int32, message string) {
+	pod := &corev1.Pod{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: importProbePodName(newImportDataVolume("test-dv")), Namespace: metav1.NamespaceDefault}, pod)
+	Expect(err).ToNot(HaveOccurred())
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}}},
+	}
+	Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+}
+
 var _ = Describe("Update Progress from pod", func() {
 	var (
 		pvc *corev1.PersistentVolumeClaim
@@ -1164,6 +2579,42 @@ var _ = Describe("Update Progress from pod", func() {
 		Expect(dv.Status.Progress).To(BeEquivalentTo("13.45%"))
 	})
 
+	It("Should update the transfer status if http endpoint reports it", func() {
+		dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(fmt.Sprintf("import_progress{ownerUID=\"%v\"} 13.45\n", dv.GetUID())))
+			w.Write([]byte(fmt.Sprintf("import_bytes_transferred{ownerUID=\"%v\"} 1.048576e+06\n", dv.GetUID())))
+			w.Write([]byte(fmt.Sprintf("import_throughput_bytes{ownerUID=\"%v\"} 2048.5\n", dv.GetUID())))
+			w.Write([]byte(fmt.Sprintf("import_remaining_seconds{ownerUID=\"%v\"} 3600\n", dv.GetUID())))
+			w.WriteHeader(200)
+		}))
+		defer ts.Close()
+		ep, err := url.Parse(ts.URL)
+		Expect(err).ToNot(HaveOccurred())
+		port, err := strconv.Atoi(ep.Port())
+		Expect(err).ToNot(HaveOccurred())
+		pod.Spec.Containers[0].Ports[0].ContainerPort = int32(port)
+		pod.Status.PodIP = ep.Hostname()
+		err = updateProgressUsingPod(dv, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dv.Status.Progress).To(BeEquivalentTo("13.45%"))
+		Expect(dv.Status.Transfer).ToNot(BeNil())
+		Expect(dv.Status.Transfer.BytesTransferred).To(BeEquivalentTo(1048576))
+		Expect(dv.Status.Transfer.BytesPerSecond).To(BeEquivalentTo(2048))
+		Expect(dv.Status.Transfer.EstimatedCompletionTime).ToNot(BeNil())
+		Expect(dv.Status.Transfer.EstimatedCompletionTime.Time).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+	})
+
+	It("Should not estimate the completion of a stalled transfer", func() {
+		metrics := "import_bytes_transferred{ownerUID=\"1234\"} 512\nimport_throughput_bytes{ownerUID=\"1234\"} 0\nimport_remaining_seconds{ownerUID=\"1234\"} -1\n"
+		transfer := parseTransferStatus(metrics, "1234", time.Now())
+		Expect(transfer).ToNot(BeNil())
+		Expect(transfer.BytesTransferred).To(BeEquivalentTo(512))
+		Expect(transfer.BytesPerSecond).To(BeZero())
+		Expect(transfer.EstimatedCompletionTime).To(BeNil())
+		Expect(parseTransferStatus(metrics, "5678", time.Now())).To(BeNil())
+	})
+
 	It("Should not change update progress if http endpoint returns no matching data", func() {
 		dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
 		dv.Status.Progress = cdiv1.DataVolumeProgress("2.3%")
@@ -1192,6 +2643,8 @@ func createDatavolumeReconciler(objects ...runtime.Object) *DatavolumeReconciler
 	s := scheme.Scheme
 	cdiv1.AddToScheme(s)
 	snapshotv1.AddToScheme(s)
+	s.AddKnownTypeWithName(virtualMachineListKind.GroupVersion().WithKind("VirtualMachine"), &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(virtualMachineListKind, &unstructured.UnstructuredList{})
 
 	objs = append(objs, MakeEmptyCDICR())
 
@@ -1209,12 +2662,13 @@ func createDatavolumeReconciler(objects ...runtime.Object) *DatavolumeReconciler
 	rec := record.NewFakeRecorder(10)
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	r := &DatavolumeReconciler{
-		client:       cl,
-		scheme:       s,
-		log:          dvLog,
-		recorder:     rec,
-		extClientSet: extfakeclientset,
-		featureGates: featuregates.NewFeatureGates(cl),
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            dvLog,
+		recorder:       rec,
+		extClientSet:   extfakeclientset,
+		featureGates:   featuregates.NewFeatureGates(cl),
 	}
 	return r
 }
@@ -1233,7 +2687,13 @@ func newImportDataVolume(name string) *cdiv1.DataVolume {
 					URL: "http://example.com/data",
 				},
 			},
-			PVC: &corev1.PersistentVolumeClaimSpec{},
+			PVC: &corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1G"),
+					},
+				},
+			},
 		},
 	}
 }
@@ -1283,6 +2743,49 @@ func newCloneDataVolumeWithPVCNS(name string, pvcNamespace string) *cdiv1.DataVo
 	}
 }
 
+func newSnapshotDataVolume(name, snapshotNamespace string) *cdiv1.DataVolume {
+	dv := newCloneDataVolume(name)
+	dv.UID = types.UID(metav1.NamespaceDefault + "-" + name)
+	dv.Spec.Source.PVC = nil
+	dv.Spec.Source.Snapshot = &cdiv1.DataVolumeSourceSnapshot{Namespace: snapshotNamespace, Name: "snapshot"}
+	dv.Spec.PVC.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+	return dv
+}
+
+func newSourceSnapshot(name, namespace string, ready bool) *snapshotv1.VolumeSnapshot {
+	contentName := "content"
+	restoreSize := resource.MustParse("1Gi")
+	return &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: &snapshotv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &contentName,
+			ReadyToUse:                     &ready,
+			RestoreSize:                    &restoreSize,
+		},
+	}
+}
+
+func newSnapshotContent(name, driver string) *snapshotv1.VolumeSnapshotContent {
+	return &snapshotv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: snapshotv1.VolumeSnapshotContentSpec{
+			Driver: driver,
+		},
+	}
+}
+
+func newNetworkCopyDataVolume(name string) *cdiv1.DataVolume {
+	dv := newCloneDataVolume(name)
+	dv.Spec.Source.PVCNetwork = dv.Spec.Source.PVC
+	dv.Spec.Source.PVC = nil
+	return dv
+}
+
 func newUploadDataVolume(name string) *cdiv1.DataVolume {
 	return &cdiv1.DataVolume{
 		TypeMeta: metav1.TypeMeta{APIVersion: cdiv1.SchemeGroupVersion.String()},
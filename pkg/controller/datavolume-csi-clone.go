@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// validateCsiClone returns nil if the CSI driver can clone the source PVC of the DataVolume into its PVC
+func (r *DatavolumeReconciler) validateCsiClone(dataVolume *cdiv1.DataVolume) error {
+	if dataVolume.Spec.Source.PVC == nil {
+		return errors.New("no source PVC provided")
+	}
+	if _, err := r.getSmartCloneSourcePVC(dataVolume); err != nil {
+		return err
+	}
+	r.log.V(3).Info("csi-clone is applicable for datavolume", "datavolume", dataVolume.Name)
+	return nil
+}
+
+// setCsiCloneSource makes the new PVC of the DataVolume a CSI volume clone of its source PVC instead of a host assisted
+// clone, the PVC is populated once bound then
+func setCsiCloneSource(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) {
+	delete(pvc.Annotations, AnnCloneRequest)
+	delete(pvc.Annotations, AnnCloneToken)
+	delete(pvc.Annotations, AnnCloneGrant)
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		Name: dataVolume.Spec.Source.PVC.Name,
+		Kind: "PersistentVolumeClaim",
+	}
+	pvc.Annotations[AnnPopulatedFor] = dataVolume.Name
+}
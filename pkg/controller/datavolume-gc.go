@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// dataVolumeInUseRetryInterval is how long to wait before collecting again an expired DataVolume that is in use
+const dataVolumeInUseRetryInterval = 5 * time.Minute
+
+// virtualMachineListKind is the list kind of the KubeVirt VirtualMachines, read as unstructured objects so CDI does
+// not depend on the KubeVirt API
+var virtualMachineListKind = schema.GroupVersionKind{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachineList"}
+
+// getDataVolumeTTL returns the time the DataVolumes are kept after their completion, a negative duration if they are
+// kept for good
+func (r *DatavolumeReconciler) getDataVolumeTTL() (time.Duration, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return -1, nil
+		}
+		return 0, err
+	}
+	if cdiconfig.Spec.DataVolumeTTLSeconds == nil || *cdiconfig.Spec.DataVolumeTTLSeconds < 0 {
+		return -1, nil
+	}
+	return time.Duration(*cdiconfig.Spec.DataVolumeTTLSeconds) * time.Second, nil
+}
+
+// garbageCollect deletes the Succeeded DataVolume once its TTL expired, and requeues it until then. The PVC is kept:
+// it is released from the DataVolume and given its annotations, along with the populated for annotation so a DataVolume
+// created again with the same name adopts it. DataVolumes controlled by another object are left to their owner, and
+// DataVolumes whose PVC is used by a pod or which are referenced by a VirtualMachine are kept until they are not anymore.
+func (r *DatavolumeReconciler) garbageCollect(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, log logr.Logger) (reconcile.Result, error) {
+	if metav1.GetControllerOf(dataVolume) != nil {
+		return reconcile.Result{}, nil
+	}
+	ready := findConditionByType(cdiv1.DataVolumeReady, dataVolume.Status.Conditions)
+	if ready == nil || ready.Status != corev1.ConditionTrue {
+		return reconcile.Result{}, nil
+	}
+	ttl, err := r.getDataVolumeTTL()
+	if err != nil || ttl < 0 {
+		return reconcile.Result{}, err
+	}
+	if left := time.Until(ready.LastTransitionTime.Add(ttl)); left > 0 {
+		return reconcile.Result{RequeueAfter: left}, nil
+	}
+	inUse, err := dataVolumeInUse(r.client, r.uncachedClient, dataVolume.Namespace, dataVolume.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if inUse {
+		log.V(1).Info("Keeping the DataVolume after its TTL, it is in use")
+		return reconcile.Result{RequeueAfter: dataVolumeInUseRetryInterval}, nil
+	}
+
+	pvcCopy := pvc.DeepCopy()
+	if pvcCopy.Annotations == nil {
+		pvcCopy.Annotations = make(map[string]string)
+	}
+	for key, value := range dataVolume.Annotations {
+		if _, ok := pvcCopy.Annotations[key]; !ok && key != corev1.LastAppliedConfigAnnotation {
+			pvcCopy.Annotations[key] = value
+		}
+	}
+	pvcCopy.Annotations[AnnPopulatedFor] = dataVolume.Name
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ownerRef := range pvcCopy.OwnerReferences {
+		if ownerRef.UID != dataVolume.UID {
+			ownerRefs = append(ownerRefs, ownerRef)
+		}
+	}
+	pvcCopy.OwnerReferences = ownerRefs
+	if err := r.client.Update(context.TODO(), pvcCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Deleting the DataVolume after its TTL, keeping the PVC", "ttl", ttl.String())
+	if err := r.client.Delete(context.TODO(), dataVolume); err != nil && !k8serrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// dataVolumeInUse returns true if the PVC of the DataVolume is used by a pod, or if a VirtualMachine references the
+// DataVolume in its volumes. The VirtualMachines are only looked up when KubeVirt is installed.
+func dataVolumeInUse(c, uncachedClient client.Client, namespace, name string) (bool, error) {
+	pods, err := getPodsUsingPVCs(c, namespace, sets.NewString(name), false)
+	if err != nil || len(pods) > 0 {
+		return len(pods) > 0, err
+	}
+
+	vms := &unstructured.UnstructuredList{}
+	vms.SetGroupVersionKind(virtualMachineListKind)
+	if err := uncachedClient.List(context.TODO(), vms, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, vm := range vms.Items {
+		volumes, _, err := unstructured.NestedSlice(vm.Object, "spec", "template", "spec", "volumes")
+		if err != nil {
+			continue
+		}
+		for _, volume := range volumes {
+			volumeMap, ok := volume.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if volumeName, _, _ := unstructured.NestedString(volumeMap, "dataVolume", "name"); volumeName == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
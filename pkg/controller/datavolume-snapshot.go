@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// AnnSnapshotRestoreFor is the annotation of the temporary PVCs restored from a VolumeSnapshot for a host assisted
+	// copy, holding the namespace/name of the target PVC
+	AnnSnapshotRestoreFor = AnnAPIGroup + "/storage.snapshot.restoreFor"
+
+	// SnapshotSourceNotReady provides a const to indicate the source VolumeSnapshot is missing or not ready to use
+	SnapshotSourceNotReady = "SnapshotSourceNotReady"
+	// MessageSnapshotSourceNotReady provides a const to form the source VolumeSnapshot not ready message
+	MessageSnapshotSourceNotReady = "Waiting for VolumeSnapshot %s/%s to be ready to use"
+	// MessageSnapshotCopyScheduled provides a const to form snapshot copy is scheduled message
+	MessageSnapshotCopyScheduled = "Copying snapshot %s/%s into %s/%s scheduled"
+	// MessageSnapshotCopyInProgress provides a const to form snapshot copy is in progress message
+	MessageSnapshotCopyInProgress = "Copying snapshot %s/%s into %s/%s in progress"
+	// MessageSnapshotCopyFailed provides a const to form snapshot copy has failed message
+	MessageSnapshotCopyFailed = "Copying snapshot %s/%s into %s/%s failed"
+	// MessageSnapshotCopySucceeded provides a const to form snapshot copy has succeeded message
+	MessageSnapshotCopySucceeded = "Successfully copied snapshot %s/%s into %s/%s"
+
+	// snapshotSourceRetryInterval is how long to wait before checking a source VolumeSnapshot that is not ready again
+	snapshotSourceRetryInterval = 10 * time.Second
+	// snapshotRestorePvcPrefix is the prefix of the names of the temporary PVCs restored from a VolumeSnapshot
+	snapshotRestorePvcPrefix = "cdi-snapshot-restore-"
+)
+
+// getReadySourceSnapshot returns the source VolumeSnapshot of the DataVolume, nil until it exists and is ready to use
+func (r *DatavolumeReconciler) getReadySourceSnapshot(dataVolume *cdiv1.DataVolume) (*snapshotv1.VolumeSnapshot, error) {
+	source := dataVolume.Spec.Source.Snapshot
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: source.Namespace, Name: source.Name}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		return nil, nil
+	}
+	return snapshot, nil
+}
+
+// snapshotDriver returns the CSI driver of the snapshot, from its VolumeSnapshotContent
+func (r *DatavolumeReconciler) snapshotDriver(snapshot *snapshotv1.VolumeSnapshot) (string, error) {
+	if snapshot.Status == nil || snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		return "", errors.Errorf("VolumeSnapshot %s/%s has no VolumeSnapshotContent", snapshot.Namespace, snapshot.Name)
+	}
+	content := &snapshotv1.VolumeSnapshotContent{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *snapshot.Status.BoundVolumeSnapshotContentName}, content); err != nil {
+		return "", err
+	}
+	return content.Spec.Driver, nil
+}
+
+// canRestoreSnapshot returns true if the PVC of the DataVolume can be restored from the snapshot by the CSI driver,
+// which requires the snapshot to be in the namespace of the DataVolume and the driver to provision its storage class
+func (r *DatavolumeReconciler) canRestoreSnapshot(dataVolume *cdiv1.DataVolume, snapshot *snapshotv1.VolumeSnapshot) (bool, error) {
+	if snapshot.Namespace != dataVolume.Namespace {
+		return false, nil
+	}
+	driver, err := r.snapshotDriver(snapshot)
+	if err != nil {
+		return false, err
+	}
+	storageClass, err := GetStorageClassByName(r.client, dataVolume.Spec.PVC.StorageClassName)
+	if err != nil {
+		return false, err
+	}
+	return storageClass != nil && storageClass.Provisioner == driver, nil
+}
+
+// setSnapshotSource makes the new PVC of the DataVolume a restore of the snapshot when possible, the PVC is populated
+// once bound then. Otherwise the PVC is a host assisted copy of a temporary PVC restored from the snapshot in the
+// namespace of the snapshot.
+func (r *DatavolumeReconciler) setSnapshotSource(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, snapshot *snapshotv1.VolumeSnapshot) error {
+	restore, err := r.canRestoreSnapshot(dataVolume, snapshot)
+	if err != nil {
+		return err
+	}
+	if restore {
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			Name:     snapshot.Name,
+			Kind:     "VolumeSnapshot",
+			APIGroup: &snapshotv1.SchemeGroupVersion.Group,
+		}
+		pvc.Annotations[AnnPopulatedFor] = dataVolume.Name
+		return nil
+	}
+	token, ok := dataVolume.Annotations[AnnCloneToken]
+	if !ok {
+		return errors.Errorf("no clone token")
+	}
+	pvc.Annotations[AnnCloneToken] = token
+	pvc.Annotations[AnnCloneRequest] = snapshot.Namespace + "/" + snapshotRestorePvcName(dataVolume)
+	return nil
+}
+
+func snapshotRestorePvcName(dataVolume *cdiv1.DataVolume) string {
+	return snapshotRestorePvcPrefix + string(dataVolume.UID)
+}
+
+// reconcileSnapshotRestorePvc creates the temporary PVC restored from the snapshot that is the source of the host
+// assisted copy to the PVC, until the copy is done. The clone controller deletes it along with the clone source pod.
+func (r *DatavolumeReconciler) reconcileSnapshotRestorePvc(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	exists, namespace, name := ParseCloneRequestAnnotation(pvc)
+	if dataVolume.Spec.Source.Snapshot == nil || !exists || metav1.HasAnnotation(pvc.ObjectMeta, AnnCloneOf) {
+		return nil
+	}
+	restorePvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, restorePvc); err == nil || !k8serrors.IsNotFound(err) {
+		return err
+	}
+	snapshot, err := r.getReadySourceSnapshot(dataVolume)
+	if snapshot == nil || err != nil {
+		return err
+	}
+	restorePvc, err = r.newSnapshotRestorePvc(snapshot, pvc, name)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Create(context.TODO(), restorePvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// newSnapshotRestorePvc returns the temporary PVC restoring the snapshot for the target PVC, in the storage class of the
+// snapshotted PVC when it still exists, otherwise in a storage class provisioned by the driver of the snapshot
+func (r *DatavolumeReconciler) newSnapshotRestorePvc(snapshot *snapshotv1.VolumeSnapshot, target *corev1.PersistentVolumeClaim, name string) (*corev1.PersistentVolumeClaim, error) {
+	var storageClassName *string
+	volumeMode := target.Spec.VolumeMode
+	if source := snapshot.Spec.Source.PersistentVolumeClaimName; source != nil {
+		sourcePvc := &corev1.PersistentVolumeClaim{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: snapshot.Namespace, Name: *source}, sourcePvc); err == nil {
+			storageClassName, volumeMode = sourcePvc.Spec.StorageClassName, sourcePvc.Spec.VolumeMode
+		}
+	}
+	if storageClassName == nil {
+		driver, err := r.snapshotDriver(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		storageClasses := &storagev1.StorageClassList{}
+		if err := r.client.List(context.TODO(), storageClasses); err != nil {
+			return nil, err
+		}
+		for i := range storageClasses.Items {
+			if storageClasses.Items[i].Provisioner == driver {
+				storageClassName = &storageClasses.Items[i].Name
+				break
+			}
+		}
+		if storageClassName == nil {
+			return nil, errors.Errorf("no storage class is provisioned by the driver %s of VolumeSnapshot %s/%s", driver, snapshot.Namespace, snapshot.Name)
+		}
+	}
+
+	size := target.Spec.Resources.Requests[corev1.ResourceStorage]
+	if snapshot.Status.RestoreSize != nil {
+		size = *snapshot.Status.RestoreSize
+	}
+	annotations := map[string]string{
+		AnnSnapshotRestoreFor: target.Namespace + "/" + target.Name,
+	}
+	if contentType, ok := target.Annotations[AnnContentType]; ok {
+		annotations[AnnContentType] = contentType
+	}
+	restorePvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   snapshot.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				common.CDILabelKey: common.CDILabelValue,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			DataSource: &corev1.TypedLocalObjectReference{
+				Name:     snapshot.Name,
+				Kind:     "VolumeSnapshot",
+				APIGroup: &snapshotv1.SchemeGroupVersion.Group,
+			},
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			VolumeMode:       volumeMode,
+			StorageClassName: storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	// Owner references cannot cross namespaces, the clone controller deletes the PVC in the other cases
+	if snapshot.Namespace == target.Namespace {
+		restorePvc.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+			Name:       target.Name,
+			UID:        target.UID,
+		}}
+	}
+	return restorePvc, nil
+}
+
+// isSnapshotRestoreFor returns true if the PVC is the temporary PVC restored from a snapshot for the target PVC
+func isSnapshotRestoreFor(pvc, target *corev1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[AnnSnapshotRestoreFor] == target.Namespace+"/"+target.Name &&
+		pvc.Spec.DataSource != nil && pvc.Spec.DataSource.Kind == "VolumeSnapshot"
+}
@@ -0,0 +1,757 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"time"
+
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	exportControllerAgentName = "export-controller"
+
+	// ExportTargetS3 is the target type of the exports written to S3
+	ExportTargetS3 = "s3"
+	// ExportTargetHTTP is the target type of the exports written with an HTTP PUT
+	ExportTargetHTTP = "http"
+	// ExportTargetRegistry is the target type of the exports pushed as containerDisk images
+	ExportTargetRegistry = "registry"
+	// ExportTargetCDI is the target type of the exports uploaded to a DataVolume of another cluster
+	ExportTargetCDI = "cdi"
+
+	exportAuthVolName = "cdi-export-auth-vol"
+
+	// ExportSourceInUse provides a const to indicate the source of an export is in use by a pod
+	ExportSourceInUse = "ExportSourceInUse"
+	// ExportFailed provides a const to indicate the export pod failed
+	ExportFailed = "ExportFailed"
+	// ExportSucceeded provides a const to indicate the disk was exported
+	ExportSucceeded = "ExportSucceeded"
+
+	// MessageExportSourceNotFound provides a const to form the message of an export whose source PVC does not exist
+	MessageExportSourceNotFound = "Source PVC %s not found"
+	// MessageExportSourceNotPopulated provides a const to form the message of an export whose source is not populated yet
+	MessageExportSourceNotPopulated = "Source PVC %s is not populated yet"
+	// MessageExportSourceInUse provides a const to form the message of an export whose source is in use
+	MessageExportSourceInUse = "Source PVC %s is in use by pod %s"
+	// MessageExportSnapshotNotFound provides a const to form the message of an export whose source VolumeSnapshot does not exist
+	MessageExportSnapshotNotFound = "Source VolumeSnapshot %s not found"
+	// MessageExportSnapshotNotReady provides a const to form the message of an export whose source VolumeSnapshot is not ready yet
+	MessageExportSnapshotNotReady = "Source VolumeSnapshot %s is not ready to use yet"
+	// MessageExportSucceeded provides a const to form the message of a successful export
+	MessageExportSucceeded = "Disk of %s exported"
+
+	exportSourceAvailable   = "Available"
+	exportSourceNotReady    = "NotReady"
+	exportSourceInvalidSpec = "InvalidSpec"
+	exportPodPending        = "Pending"
+	exportPodError          = "Error"
+	exportCompleted         = "Completed"
+)
+
+// ExportReconciler members
+type ExportReconciler struct {
+	client     client.Client
+	recorder   record.EventRecorder
+	scheme     *runtime.Scheme
+	log        logr.Logger
+	image      string
+	verbose    string
+	pullPolicy string
+}
+
+// NewExportController creates a new instance of the export controller.
+func NewExportController(mgr manager.Manager, log logr.Logger, exporterImage, pullPolicy, verbose string) (controller.Controller, error) {
+	reconciler := &ExportReconciler{
+		client:     mgr.GetClient(),
+		scheme:     mgr.GetScheme(),
+		log:        log.WithName(exportControllerAgentName),
+		image:      exporterImage,
+		verbose:    verbose,
+		pullPolicy: pullPolicy,
+		recorder:   mgr.GetEventRecorderFor(exportControllerAgentName),
+	}
+	exportController, err := controller.New(exportControllerAgentName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addExportControllerWatches(mgr, exportController); err != nil {
+		return nil, err
+	}
+	return exportController, nil
+}
+
+func addExportControllerWatches(mgr manager.Manager, exportController controller.Controller) error {
+	if err := exportController.Watch(&source.Kind{Type: &cdiv1.DataExport{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := exportController.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.DataExport{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
+	// The PVCs restored from VolumeSnapshot sources
+	if err := exportController.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.DataExport{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reconcile the reconcile loop for the DataExport object.
+func (r *ExportReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataExport", req.NamespacedName)
+	dataExport := &cdiv1.DataExport{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, dataExport); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if dataExport.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+	if dataExport.Status.Phase == cdiv1.ExportSucceeded {
+		return r.reconcileRetention(log, dataExport)
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: getExportPodName(dataExport), Namespace: dataExport.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		pod = nil
+	}
+
+	dataExportCopy := dataExport.DeepCopy()
+	result := reconcile.Result{}
+	if pod == nil {
+		pvc, reason, message, err := r.getExportSource(dataExport)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if pvc == nil {
+			log.V(3).Info("Export source not ready", "message", message)
+			dataExportCopy.Status.Phase = cdiv1.ExportPending
+			dataExportCopy.Status.Message = message
+			dataExportCopy.Status.Conditions = updateCondition(dataExportCopy.Status.Conditions, cdiv1.DataExportSourceReady, corev1.ConditionFalse, message, reason)
+			result = reconcile.Result{RequeueAfter: 2 * time.Second}
+		} else {
+			log.V(1).Info("Creating export pod", "PVC", pvc.Name)
+			if err := r.createExportPod(dataExport, pvc); err != nil {
+				return reconcile.Result{}, err
+			}
+			dataExportCopy.Status.Phase = cdiv1.ExportInProgress
+			dataExportCopy.Status.Message = ""
+			dataExportCopy.Status.Progress = "N/A"
+			dataExportCopy.Status.Conditions = updateCondition(dataExportCopy.Status.Conditions, cdiv1.DataExportSourceReady, corev1.ConditionTrue, "", exportSourceAvailable)
+		}
+		dataExportCopy.Status.Conditions = updateCondition(dataExportCopy.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, "", exportPodPending)
+		dataExportCopy.Status.Conditions = updateCondition(dataExportCopy.Status.Conditions, cdiv1.DataVolumeReady, corev1.ConditionFalse, "", exportPodPending)
+	} else {
+		if err := r.updateStatusFromPod(dataExportCopy, pod); err != nil {
+			return reconcile.Result{}, err
+		}
+		if dataExportCopy.Status.Phase == cdiv1.ExportInProgress {
+			// Poll the progress of the export pod
+			result = reconcile.Result{RequeueAfter: 2 * time.Second}
+		}
+	}
+
+	if !reflect.DeepEqual(dataExport.Status, dataExportCopy.Status) {
+		if err := r.client.Update(context.TODO(), dataExportCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if dataExportCopy.Status.Phase == cdiv1.ExportSucceeded {
+		if pod != nil {
+			log.V(1).Info("Deleting export pod", "pod.Name", pod.Name)
+			if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if err := r.deleteSnapshotPVC(log, dataExportCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+		return r.reconcileRetention(log, dataExportCopy)
+	}
+	return result, nil
+}
+
+// reconcileRetention deletes the succeeded DataExport once its TTLSecondsAfterFinished expired
+func (r *ExportReconciler) reconcileRetention(log logr.Logger, dataExport *cdiv1.DataExport) (reconcile.Result, error) {
+	ttl := dataExport.Spec.TTLSecondsAfterFinished
+	if ttl == nil || dataExport.Status.CompletionTime == nil {
+		return reconcile.Result{}, nil
+	}
+	expiry := dataExport.Status.CompletionTime.Add(time.Duration(*ttl) * time.Second)
+	if remaining := time.Until(expiry); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+	log.V(1).Info("Deleting DataExport, its retention expired")
+	if err := r.client.Delete(context.TODO(), dataExport); IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// getExportSource returns the source PVC of the export, or the reason the export cannot start yet
+func (r *ExportReconciler) getExportSource(dataExport *cdiv1.DataExport) (*corev1.PersistentVolumeClaim, string, string, error) {
+	source := dataExport.Spec.Source
+	if countExportSources(source) != 1 {
+		return nil, exportSourceInvalidSpec, "Exactly one of pvc, dataVolume and volumeSnapshot must be set in the source", nil
+	}
+	if countExportTargets(dataExport.Spec.Target) != 1 {
+		return nil, exportSourceInvalidSpec, "Exactly one of s3, http, registry and cdi must be set in the target", nil
+	}
+	if source.VolumeSnapshot != "" {
+		return r.getSnapshotSource(dataExport)
+	}
+	name := source.PVC
+	if name == "" {
+		name = source.DataVolume
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: dataExport.Namespace}, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, notFound, fmt.Sprintf(MessageExportSourceNotFound, name), nil
+		}
+		return nil, "", "", err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return nil, exportSourceNotReady, fmt.Sprintf(MessageExportSourceNotPopulated, name), nil
+	}
+	populated, err := IsPopulated(pvc, r.client)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !populated {
+		return nil, exportSourceNotReady, fmt.Sprintf(MessageExportSourceNotPopulated, name), nil
+	}
+
+	// Readers do not change the disk while it is exported
+	pods, err := getPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), true)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(pods) > 0 {
+		message := fmt.Sprintf(MessageExportSourceInUse, name, pods[0].Name)
+		r.recorder.Event(dataExport, corev1.EventTypeWarning, ExportSourceInUse, message)
+		return nil, ExportSourceInUse, message, nil
+	}
+	return pvc, "", "", nil
+}
+
+// getSnapshotSource returns the PVC restored from the VolumeSnapshot source of the export, creating it when the
+// snapshot is ready to use. The PVC is not required to be bound, it binds once the export pod is scheduled.
+func (r *ExportReconciler) getSnapshotSource(dataExport *cdiv1.DataExport) (*corev1.PersistentVolumeClaim, string, string, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: getExportSnapshotPVCName(dataExport), Namespace: dataExport.Namespace}, pvc); err == nil {
+		return pvc, "", "", nil
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, "", "", err
+	}
+
+	name := dataExport.Spec.Source.VolumeSnapshot
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: dataExport.Namespace}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, notFound, fmt.Sprintf(MessageExportSnapshotNotFound, name), nil
+		}
+		return nil, "", "", err
+	}
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		return nil, exportSourceNotReady, fmt.Sprintf(MessageExportSnapshotNotReady, name), nil
+	}
+
+	// The restored PVC has the storage class and mode of the PVC the snapshot was taken from, when it still exists
+	var sourcePVC *corev1.PersistentVolumeClaim
+	if claimName := snapshot.Spec.Source.PersistentVolumeClaimName; claimName != nil {
+		sourcePVC = &corev1.PersistentVolumeClaim{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *claimName, Namespace: dataExport.Namespace}, sourcePVC); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, "", "", err
+			}
+			sourcePVC = nil
+		}
+	}
+	pvc = newExportPvcFromSnapshot(dataExport, snapshot, sourcePVC)
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		return nil, exportSourceNotReady, fmt.Sprintf("Unable to determine the restore size of VolumeSnapshot %s", name), nil
+	}
+	if err := controllerutil.SetControllerReference(dataExport, pvc, r.scheme); err != nil {
+		return nil, "", "", err
+	}
+	if err := r.client.Create(context.TODO(), pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, "", "", errors.Wrap(err, "snapshot PVC API create errored")
+	}
+	return pvc, "", "", nil
+}
+
+// deleteSnapshotPVC deletes the PVC restored from the VolumeSnapshot source once the export is over
+func (r *ExportReconciler) deleteSnapshotPVC(log logr.Logger, dataExport *cdiv1.DataExport) error {
+	if dataExport.Spec.Source.VolumeSnapshot == "" {
+		return nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: getExportSnapshotPVCName(dataExport), Namespace: dataExport.Namespace}, pvc); err != nil {
+		return IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pvc, dataExport) {
+		return nil
+	}
+	log.V(1).Info("Deleting snapshot PVC", "PVC", pvc.Name)
+	return IgnoreNotFound(r.client.Delete(context.TODO(), pvc))
+}
+
+func (r *ExportReconciler) updateStatusFromPod(dataExport *cdiv1.DataExport, pod *corev1.Pod) error {
+	var terminationMessage string
+	if len(pod.Status.ContainerStatuses) > 0 {
+		status := pod.Status.ContainerStatuses[0]
+		dataExport.Status.RestartCount = status.RestartCount
+		if status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode > 0 {
+			terminationMessage = status.LastTerminationState.Terminated.Message
+			if terminationMessage != dataExport.Status.Message {
+				r.recorder.Event(dataExport, corev1.EventTypeWarning, ExportFailed, terminationMessage)
+			}
+			dataExport.Status.Message = terminationMessage
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded {
+		now := metav1.Now()
+		dataExport.Status.Phase = cdiv1.ExportSucceeded
+		dataExport.Status.Progress = "100.0%"
+		dataExport.Status.Message = ""
+		dataExport.Status.CompletionTime = &now
+		dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, "", exportCompleted)
+		dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeReady, corev1.ConditionTrue, "", exportCompleted)
+		r.recorder.Event(dataExport, corev1.EventTypeNormal, ExportSucceeded, fmt.Sprintf(MessageExportSucceeded, describeExportSource(dataExport.Spec.Source)))
+		return nil
+	}
+
+	dataExport.Status.Phase = cdiv1.ExportInProgress
+	switch {
+	case pod.Status.Phase == corev1.PodRunning:
+		dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionTrue, "", transferRunning)
+	case terminationMessage != "":
+		dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, terminationMessage, exportPodError)
+	default:
+		dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeRunning, corev1.ConditionFalse, "", exportPodPending)
+	}
+	dataExport.Status.Conditions = updateCondition(dataExport.Status.Conditions, cdiv1.DataVolumeReady, corev1.ConditionFalse, "", string(cdiv1.ExportInProgress))
+	if pod.Status.Phase == corev1.PodRunning {
+		progress, err := getProgressFromPod(pod, dataExport.UID)
+		if err != nil {
+			r.log.V(3).Info("Unable to get the export progress", "error", err)
+		}
+		if progress != "" {
+			dataExport.Status.Progress = progress
+		}
+	}
+	return nil
+}
+
+func (r *ExportReconciler) createExportPod(dataExport *cdiv1.DataExport, pvc *corev1.PersistentVolumeClaim) error {
+	podResourceRequirements, err := GetDefaultPodResourceRequirements(r.client)
+	if err != nil {
+		return err
+	}
+	workloadNodePlacement, err := GetWorkloadNodePlacement(r.client)
+	if err != nil {
+		return err
+	}
+	imagePullSecrets, err := GetImagePullSecrets(r.client)
+	if err != nil {
+		return err
+	}
+
+	pod := makeExporterPodSpec(dataExport, pvc, r.image, r.verbose, r.pullPolicy, podResourceRequirements, workloadNodePlacement)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
+	if err := controllerutil.SetControllerReference(dataExport, pod, r.scheme); err != nil {
+		return err
+	}
+	if err := r.client.Create(context.TODO(), pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "export pod API create errored")
+	}
+	return nil
+}
+
+func getExportPodName(dataExport *cdiv1.DataExport) string {
+	return naming.GetResourceName(common.ExporterPodName, dataExport.Name)
+}
+
+func getExportSnapshotPVCName(dataExport *cdiv1.DataExport) string {
+	return naming.GetResourceName("cdi-export-snapshot", dataExport.Name)
+}
+
+func newExportPvcFromSnapshot(dataExport *cdiv1.DataExport, snapshot *snapshotv1.VolumeSnapshot, sourcePVC *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getExportSnapshotPVCName(dataExport),
+			Namespace: dataExport.Namespace,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.ExporterPodName,
+			},
+			Annotations: map[string]string{
+				AnnCreatedBy: "yes",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			DataSource: &corev1.TypedLocalObjectReference{
+				Name:     snapshot.Name,
+				Kind:     "VolumeSnapshot",
+				APIGroup: &snapshotv1.SchemeGroupVersion.Group,
+			},
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{},
+			},
+		},
+	}
+	if sourcePVC != nil {
+		pvc.Spec.AccessModes = sourcePVC.Spec.AccessModes
+		pvc.Spec.VolumeMode = sourcePVC.Spec.VolumeMode
+		pvc.Spec.StorageClassName = sourcePVC.Spec.StorageClassName
+		if size, ok := sourcePVC.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		}
+	}
+	if snapshot.Status.RestoreSize != nil && !snapshot.Status.RestoreSize.IsZero() {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *snapshot.Status.RestoreSize
+	}
+	return pvc
+}
+
+func makeExporterPodSpec(dataExport *cdiv1.DataExport, pvc *corev1.PersistentVolumeClaim, image, verbose, pullPolicy string, podResourceRequirements *corev1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement) *corev1.Pod {
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getExportPodName(dataExport),
+			Namespace: dataExport.Namespace,
+			Annotations: map[string]string{
+				AnnCreatedBy: "yes",
+			},
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.ExporterPodName,
+				common.PrometheusLabel:   "",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            common.ExporterPodName,
+					Image:           image,
+					ImagePullPolicy: corev1.PullPolicy(pullPolicy),
+					Command:         []string{"/usr/bin/cdi-exporter"},
+					Args:            []string{"-v=" + verbose},
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "metrics",
+							ContainerPort: 8443,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      ScratchVolName,
+							MountPath: common.ScratchDataDir,
+						},
+					},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+			Volumes: []corev1.Volume{
+				{
+					Name: DataVolName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+							ReadOnly:  true,
+						},
+					},
+				},
+				{
+					// Holds the qcow2 image while it is written to the target
+					Name: ScratchVolName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+			NodeSelector: workloadNodePlacement.NodeSelector,
+			Tolerations:  workloadNodePlacement.Tolerations,
+			Affinity:     workloadNodePlacement.Affinity,
+		},
+	}
+
+	if podResourceRequirements != nil {
+		pod.Spec.Containers[0].Resources = *podResourceRequirements
+	}
+
+	sourcePath := common.ImporterWritePath
+	if getVolumeMode(pvc) == corev1.PersistentVolumeBlock {
+		sourcePath = common.WriteBlockPath
+		pod.Spec.Containers[0].VolumeDevices = addVolumeDevices()
+		pod.Spec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsUser: &[]int64{0}[0],
+		}
+	} else {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      DataVolName,
+			MountPath: common.ImporterDataDir,
+			ReadOnly:  true,
+		})
+	}
+
+	if certConfigMap := getExportCertConfigMap(dataExport); certConfigMap != "" {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      CertVolName,
+			MountPath: common.ImporterCertDir,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: CertVolName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: certConfigMap,
+					},
+				},
+			},
+		})
+	}
+
+	if authSecret := getExportAuthSecret(dataExport); authSecret != "" {
+		// Mounted rather than passed as env, so the renewed upload tokens of cdi targets are seen by the running pod
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      exportAuthVolName,
+			MountPath: common.ExporterAuthDir,
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: exportAuthVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: authSecret,
+				},
+			},
+		})
+	}
+
+	pod.Spec.Containers[0].Env = makeExportEnv(dataExport, sourcePath)
+	return pod
+}
+
+func makeExportEnv(dataExport *cdiv1.DataExport, sourcePath string) []corev1.EnvVar {
+	format := dataExport.Spec.Format
+	if format == "" {
+		format = cdiv1.DataExportFormatQcow2
+	}
+	env := []corev1.EnvVar{
+		{
+			Name:  common.OwnerUID,
+			Value: string(dataExport.UID),
+		},
+		{
+			Name:  common.ExporterSourcePath,
+			Value: sourcePath,
+		},
+		{
+			Name:  common.ExporterFormat,
+			Value: string(format),
+		},
+	}
+
+	if format != cdiv1.DataExportFormatRaw && dataExport.Spec.Compression != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterCompression,
+			Value: string(dataExport.Spec.Compression),
+		})
+	}
+
+	var secretRef string
+	if s3 := dataExport.Spec.Target.S3; s3 != nil {
+		secretRef = s3.SecretRef
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterTarget,
+			Value: ExportTargetS3,
+		}, corev1.EnvVar{
+			Name:  common.ExporterEndpoint,
+			Value: s3.URL,
+		}, corev1.EnvVar{
+			Name:  common.ExporterS3Region,
+			Value: s3.Region,
+		})
+		if s3.PartSize != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  common.ExporterS3PartSize,
+				Value: s3.PartSize.String(),
+			})
+		}
+	} else if http := dataExport.Spec.Target.HTTP; http != nil {
+		secretRef = http.SecretRef
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterTarget,
+			Value: ExportTargetHTTP,
+		}, corev1.EnvVar{
+			Name:  common.ExporterEndpoint,
+			Value: http.URL,
+		})
+	} else if registry := dataExport.Spec.Target.Registry; registry != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterTarget,
+			Value: ExportTargetRegistry,
+		}, corev1.EnvVar{
+			Name:  common.ExporterEndpoint,
+			Value: registry.URL,
+		})
+		if registry.SecretRef != "" {
+			env = append(env, corev1.EnvVar{
+				Name:  common.ExporterAuthFile,
+				Value: path.Join(common.ExporterAuthDir, corev1.DockerConfigJsonKey),
+			})
+		}
+	} else if cdi := dataExport.Spec.Target.CDI; cdi != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterTarget,
+			Value: ExportTargetCDI,
+		}, corev1.EnvVar{
+			Name:  common.ExporterEndpoint,
+			Value: cdi.URL,
+		}, corev1.EnvVar{
+			Name:  common.ExporterTokenFile,
+			Value: path.Join(common.ExporterAuthDir, common.KeyToken),
+		})
+	}
+
+	if getExportCertConfigMap(dataExport) != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ExporterCertDirVar,
+			Value: common.ImporterCertDir,
+		})
+	}
+
+	if secretRef != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ExporterAccessKeyID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretRef,
+					},
+					Key: common.KeyAccess,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ExporterSecretKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretRef,
+					},
+					Key: common.KeySecret,
+				},
+			},
+		})
+	}
+	return env
+}
+
+func countExportSources(source cdiv1.DataExportSource) int {
+	count := 0
+	for _, name := range []string{source.PVC, source.DataVolume, source.VolumeSnapshot} {
+		if name != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func describeExportSource(source cdiv1.DataExportSource) string {
+	switch {
+	case source.PVC != "":
+		return "PVC " + source.PVC
+	case source.DataVolume != "":
+		return "DataVolume " + source.DataVolume
+	}
+	return "VolumeSnapshot " + source.VolumeSnapshot
+}
+
+func countExportTargets(target cdiv1.DataExportTarget) int {
+	count := 0
+	if target.S3 != nil {
+		count++
+	}
+	if target.HTTP != nil {
+		count++
+	}
+	if target.Registry != nil {
+		count++
+	}
+	if target.CDI != nil {
+		count++
+	}
+	return count
+}
+
+func getExportCertConfigMap(dataExport *cdiv1.DataExport) string {
+	if http := dataExport.Spec.Target.HTTP; http != nil {
+		return http.CertConfigMap
+	}
+	if registry := dataExport.Spec.Target.Registry; registry != nil {
+		return registry.CertConfigMap
+	}
+	if cdi := dataExport.Spec.Target.CDI; cdi != nil {
+		return cdi.CertConfigMap
+	}
+	return ""
+}
+
+// getExportAuthSecret returns the secret mounted in the export pod, the docker config of a registry target or the
+// upload token of a cdi target
+func getExportAuthSecret(dataExport *cdiv1.DataExport) string {
+	if registry := dataExport.Spec.Target.Registry; registry != nil {
+		return registry.SecretRef
+	}
+	if cdi := dataExport.Spec.Target.CDI; cdi != nil {
+		return cdi.TokenSecretRef
+	}
+	return ""
+}
@@ -0,0 +1,490 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var (
+	exportLog = logf.Log.WithName("export-controller-test")
+)
+
+var _ = Describe("Export reconcile", func() {
+	It("Should do nothing and return nil when no DataExport exists", func() {
+		reconciler := createExportReconciler()
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "no-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should wait for the source PVC to exist", func() {
+		reconciler := createExportReconciler(createDataExport("test-export", "test-pvc"))
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		dataExport := getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportPending))
+		Expect(dataExport.Status.Message).To(Equal(fmt.Sprintf(MessageExportSourceNotFound, "test-pvc")))
+		Expect(getExportPod(reconciler, dataExport)).To(BeNil())
+		condition := findConditionByType(cdiv1.DataExportSourceReady, dataExport.Status.Conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(notFound))
+	})
+
+	It("Should wait for the source DataVolume to succeed", func() {
+		dataExport := createDataExport("test-export", "")
+		dataExport.Spec.Source = cdiv1.DataExportSource{DataVolume: "test-dv"}
+		dv := newImportDataVolume("test-dv")
+		pvc := createPvc("test-dv", "default", nil, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		reconciler := createExportReconciler(dataExport, dv, pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportPending))
+		Expect(dataExport.Status.Message).To(Equal(fmt.Sprintf(MessageExportSourceNotPopulated, "test-dv")))
+	})
+
+	It("Should wait for the pods using the source PVC to complete", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vm-pod",
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{
+						Name: "disk",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: "test-pvc",
+							},
+						},
+					},
+				},
+			},
+		}
+		reconciler := createExportReconciler(createDataExport("test-export", "test-pvc"), createPvc("test-pvc", "default", nil, nil), pod)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		dataExport := getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportPending))
+		Expect(dataExport.Status.Message).To(Equal(fmt.Sprintf(MessageExportSourceInUse, "test-pvc", "vm-pod")))
+		Expect(getExportPod(reconciler, dataExport)).To(BeNil())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ExportSourceInUse))
+	})
+
+	It("Should create an export pod reading the disk image of the source PVC", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		partSize := resource.MustParse("128Mi")
+		dataExport.Spec.Target.S3.SecretRef = "s3-secret"
+		dataExport.Spec.Target.S3.PartSize = &partSize
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportInProgress))
+		pod := getExportPod(reconciler, dataExport)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.OwnerReferences).To(HaveLen(1))
+		Expect(pod.OwnerReferences[0].UID).To(Equal(dataExport.UID))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("test-pvc"))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      DataVolName,
+			MountPath: common.ImporterDataDir,
+			ReadOnly:  true,
+		}))
+		env := pod.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterSourcePath, Value: common.ImporterWritePath}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterFormat, Value: string(cdiv1.DataExportFormatQcow2)}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterEndpoint, Value: "https://s3.us-east-1.amazonaws.com/bucket/disk.qcow2"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterS3PartSize, Value: "128Mi"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.OwnerUID, Value: string(dataExport.UID)}))
+		Expect(env).To(ContainElement(corev1.EnvVar{
+			Name: common.ExporterSecretKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "s3-secret"},
+					Key:                  common.KeySecret,
+				},
+			},
+		}))
+	})
+
+	It("Should create an export pod writing to an HTTP target with its certificate authorities", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.Target = cdiv1.DataExportTarget{
+			HTTP: &cdiv1.DataExportTargetHTTP{
+				URL:           "https://nexus.example.com/repository/disks/disk.qcow2",
+				SecretRef:     "nexus-secret",
+				CertConfigMap: "nexus-ca",
+			},
+		}
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := getExportPod(reconciler, getDataExport(reconciler, "test-export"))
+		Expect(pod).ToNot(BeNil())
+		env := pod.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterTarget, Value: ExportTargetHTTP}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterEndpoint, Value: "https://nexus.example.com/repository/disks/disk.qcow2"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterCertDirVar, Value: common.ImporterCertDir}))
+		Expect(env).To(ContainElement(corev1.EnvVar{
+			Name: common.ExporterAccessKeyID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "nexus-secret"},
+					Key:                  common.KeyAccess,
+				},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: CertVolName, MountPath: common.ImporterCertDir}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: CertVolName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "nexus-ca"},
+				},
+			},
+		}))
+	})
+
+	It("Should create an export pod pushing to a registry with a docker config secret", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.Target = cdiv1.DataExportTarget{
+			Registry: &cdiv1.DataExportTargetRegistry{
+				URL:       "docker://registry.example.com/disks/fedora:35",
+				SecretRef: "registry-secret",
+			},
+		}
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := getExportPod(reconciler, getDataExport(reconciler, "test-export"))
+		Expect(pod).ToNot(BeNil())
+		env := pod.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterTarget, Value: ExportTargetRegistry}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterAuthFile, Value: common.ExporterAuthDir + "/.dockerconfigjson"}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: exportAuthVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "registry-secret"},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: exportAuthVolName, MountPath: common.ExporterAuthDir, ReadOnly: true}))
+	})
+
+	It("Should create an export pod uploading to another cluster with a mounted upload token", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.Target = cdiv1.DataExportTarget{
+			CDI: &cdiv1.DataExportTargetCDI{
+				URL:            "https://cdi-uploadproxy.dr.example.com",
+				TokenSecretRef: "upload-token",
+				CertConfigMap:  "dr-ca",
+			},
+		}
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := getExportPod(reconciler, getDataExport(reconciler, "test-export"))
+		Expect(pod).ToNot(BeNil())
+		env := pod.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterTarget, Value: ExportTargetCDI}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterEndpoint, Value: "https://cdi-uploadproxy.dr.example.com"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterTokenFile, Value: common.ExporterAuthDir + "/token"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterCertDirVar, Value: common.ImporterCertDir}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: exportAuthVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "upload-token"},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: exportAuthVolName, MountPath: common.ExporterAuthDir, ReadOnly: true}))
+	})
+
+	It("Should not start an export without a single target", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.Target.HTTP = &cdiv1.DataExportTargetHTTP{URL: "https://nexus.example.com/disk.qcow2"}
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportPending))
+		Expect(getExportPod(reconciler, dataExport)).To(BeNil())
+	})
+
+	It("Should read the device of a block source PVC", func() {
+		reconciler := createExportReconciler(createDataExport("test-export", "test-pvc"), createBlockPvc("test-pvc", "default", nil, nil))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := getExportPod(reconciler, getDataExport(reconciler, "test-export"))
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Spec.Containers[0].VolumeDevices).To(Equal(addVolumeDevices()))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ExporterSourcePath, Value: common.WriteBlockPath}))
+	})
+
+	It("Should record the failures of the export pod", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		pod := createExportPod(dataExport, corev1.PodRunning)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				RestartCount: 2,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Message:  "Unable to export disk: access denied",
+					},
+				},
+			},
+		}
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil), pod)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportInProgress))
+		Expect(dataExport.Status.RestartCount).To(BeNumerically("==", 2))
+		Expect(dataExport.Status.Message).To(Equal("Unable to export disk: access denied"))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ExportFailed))
+	})
+
+	It("Should complete the export and delete the pod once the pod succeeded", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		pod := createExportPod(dataExport, corev1.PodSucceeded)
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil), pod)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportSucceeded))
+		Expect(dataExport.Status.Progress).To(BeEquivalentTo("100.0%"))
+		Expect(dataExport.Status.CompletionTime).ToNot(BeNil())
+		Expect(getExportPod(reconciler, dataExport)).To(BeNil())
+		Expect(findConditionByType(cdiv1.DataVolumeReady, dataExport.Status.Conditions).Status).To(Equal(corev1.ConditionTrue))
+		Expect(findConditionByType(cdiv1.DataVolumeRunning, dataExport.Status.Conditions).Status).To(Equal(corev1.ConditionFalse))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ExportSucceeded))
+	})
+
+	It("Should set the running condition while the export pod runs", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		reconciler := createExportReconciler(dataExport, createPvc("test-pvc", "default", nil, nil), createExportPod(dataExport, corev1.PodRunning))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		condition := findConditionByType(cdiv1.DataVolumeRunning, dataExport.Status.Conditions)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(transferRunning))
+		Expect(findConditionByType(cdiv1.DataVolumeReady, dataExport.Status.Conditions).Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("Should pass the compression of qcow2 images only", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.Compression = cdiv1.DataExportCompressionZstd
+		env := makeExportEnv(dataExport, common.ImporterWritePath)
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ExporterCompression, Value: string(cdiv1.DataExportCompressionZstd)}))
+
+		dataExport.Spec.Format = cdiv1.DataExportFormatRaw
+		env = makeExportEnv(dataExport, common.ImporterWritePath)
+		for _, e := range env {
+			Expect(e.Name).ToNot(Equal(common.ExporterCompression))
+		}
+	})
+
+	It("Should wait for the source VolumeSnapshot to be ready to use", func() {
+		dataExport := createDataExport("test-export", "")
+		dataExport.Spec.Source = cdiv1.DataExportSource{VolumeSnapshot: "test-snapshot"}
+		snapshot := createExportSnapshot("test-snapshot", false)
+		reconciler := createExportReconciler(dataExport, snapshot)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportPending))
+		Expect(dataExport.Status.Message).To(Equal(fmt.Sprintf(MessageExportSnapshotNotReady, "test-snapshot")))
+		Expect(findConditionByType(cdiv1.DataExportSourceReady, dataExport.Status.Conditions).Reason).To(Equal(exportSourceNotReady))
+		Expect(getExportPod(reconciler, dataExport)).To(BeNil())
+	})
+
+	It("Should export a PVC restored from the source VolumeSnapshot", func() {
+		dataExport := createDataExport("test-export", "")
+		dataExport.Spec.Source = cdiv1.DataExportSource{VolumeSnapshot: "test-snapshot"}
+		sourcePVC := createPvc("test-pvc", "default", nil, nil)
+		sourcePVC.Spec.StorageClassName = &[]string{"csi-rbd"}[0]
+		reconciler := createExportReconciler(dataExport, createExportSnapshot("test-snapshot", true), sourcePVC)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: getExportSnapshotPVCName(dataExport), Namespace: "default"}, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.DataSource.Kind).To(Equal("VolumeSnapshot"))
+		Expect(pvc.Spec.DataSource.Name).To(Equal("test-snapshot"))
+		Expect(*pvc.Spec.StorageClassName).To(Equal("csi-rbd"))
+		Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("2Gi")))
+		Expect(metav1.IsControlledBy(pvc, dataExport)).To(BeTrue())
+
+		dataExport = getDataExport(reconciler, "test-export")
+		Expect(dataExport.Status.Phase).To(Equal(cdiv1.ExportInProgress))
+		Expect(findConditionByType(cdiv1.DataExportSourceReady, dataExport.Status.Conditions).Status).To(Equal(corev1.ConditionTrue))
+		pod := getExportPod(reconciler, dataExport)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(pvc.Name))
+
+		By("Deleting the restored PVC once the export succeeded")
+		pod.Status.Phase = corev1.PodSucceeded
+		Expect(reconciler.client.Update(context.TODO(), pod)).To(Succeed())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pvc.Name, Namespace: "default"}, pvc)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring("VolumeSnapshot test-snapshot"))
+	})
+
+	It("Should keep a succeeded DataExport until its retention expires", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.TTLSecondsAfterFinished = &[]int32{3600}[0]
+		dataExport.Status.Phase = cdiv1.ExportSucceeded
+		dataExport.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		reconciler := createExportReconciler(dataExport)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 58*time.Minute))
+		getDataExport(reconciler, "test-export")
+	})
+
+	It("Should delete a succeeded DataExport once its retention expired", func() {
+		dataExport := createDataExport("test-export", "test-pvc")
+		dataExport.Spec.TTLSecondsAfterFinished = &[]int32{60}[0]
+		dataExport.Status.Phase = cdiv1.ExportSucceeded
+		dataExport.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+		reconciler := createExportReconciler(dataExport)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-export", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-export", Namespace: "default"}, &cdiv1.DataExport{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+func createExportReconciler(objects ...runtime.Object) *ExportReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
+
+	objs = append(objs, MakeEmptyCDICR())
+	objs = append(objs, MakeEmptyCDIConfigSpec(common.ConfigName))
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	rec := record.NewFakeRecorder(1)
+	return &ExportReconciler{
+		client:   cl,
+		scheme:   s,
+		log:      exportLog,
+		recorder: rec,
+	}
+}
+
+func createDataExport(name, pvcName string) *cdiv1.DataExport {
+	return &cdiv1.DataExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("default-" + name),
+		},
+		Spec: cdiv1.DataExportSpec{
+			Source: cdiv1.DataExportSource{
+				PVC: pvcName,
+			},
+			Target: cdiv1.DataExportTarget{
+				S3: &cdiv1.DataExportTargetS3{
+					URL: "https://s3.us-east-1.amazonaws.com/bucket/disk.qcow2",
+				},
+			},
+		},
+	}
+}
+
+func createExportPod(dataExport *cdiv1.DataExport, phase corev1.PodPhase) *corev1.Pod {
+	pod := makeExporterPodSpec(dataExport, createPvc(dataExport.Spec.Source.PVC, dataExport.Namespace, nil, nil), testImage, "5", testPullPolicy, nil, &sdkapi.NodePlacement{})
+	pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dataExport, cdiv1.SchemeGroupVersion.WithKind("DataExport"))}
+	pod.Status.Phase = phase
+	return pod
+}
+
+func createExportSnapshot(name string, readyToUse bool) *snapshotv1.VolumeSnapshot {
+	restoreSize := resource.MustParse("2Gi")
+	return &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &[]string{"test-pvc"}[0],
+			},
+		},
+		Status: &snapshotv1.VolumeSnapshotStatus{
+			ReadyToUse:  &readyToUse,
+			RestoreSize: &restoreSize,
+		},
+	}
+}
+
+func getDataExport(reconciler *ExportReconciler, name string) *cdiv1.DataExport {
+	dataExport := &cdiv1.DataExport{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, dataExport)
+	Expect(err).ToNot(HaveOccurred())
+	return dataExport
+}
+
+func getExportPod(reconciler *ExportReconciler, dataExport *cdiv1.DataExport) *corev1.Pod {
+	pod := &corev1.Pod{}
+	err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: getExportPodName(dataExport), Namespace: dataExport.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	Expect(err).ToNot(HaveOccurred())
+	return pod
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// importCleanupFinalizer keeps the PVC of an import in progress until its importer pod and scratch space are gone
+	importCleanupFinalizer = "cdi.kubevirt.io/importCleanup"
+
+	// importerBlockTerminationGracePeriod leaves the importer of a block volume the time to wipe the partial data of a
+	// cancelled import
+	importerBlockTerminationGracePeriod = int64(300)
+)
+
+// cleanupCancelledImport stops the import to a PVC being deleted. The importer pod aborts the transfer and wipes the
+// partial data of block volumes when it is deleted, the finalizer is removed once the pod and the scratch space are
+// gone.
+func (r *ImportReconciler) cleanupCancelledImport(pvc *corev1.PersistentVolumeClaim, log logr.Logger) (reconcile.Result, error) {
+	pod, err := r.findImporterPod(pvc, log)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod != nil {
+		if pod.DeletionTimestamp == nil {
+			log.V(1).Info("PVC being terminated, cancelling the import", "pod.Name", pod.Name)
+			if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		// Wait for the importer to abort the transfer
+		return reconcile.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: createScratchNameFromPvc(pvc)}, scratchPvc)
+	if IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, err
+	}
+	if err == nil && scratchPvc.DeletionTimestamp == nil && isScratchPvcOf(scratchPvc, pvc) {
+		log.V(1).Info("Deleting the scratch space of the cancelled import", "scratchPvc.Name", scratchPvc.Name)
+		if err := r.client.Delete(context.TODO(), scratchPvc); IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	log.V(1).Info("Import cleaned up, removing finalizer")
+	controllerutil.RemoveFinalizer(pvc, importCleanupFinalizer)
+	return reconcile.Result{}, r.updatePVC(pvc, log)
+}
+
+// isScratchPvcOf tells whether the scratch PVC belongs to the import to the PVC, owned by its importer pod or kept by
+// the PVC itself while the import was paused
+func isScratchPvcOf(scratchPvc, pvc *corev1.PersistentVolumeClaim) bool {
+	if metav1.IsControlledBy(scratchPvc, pvc) {
+		return true
+	}
+	owner := metav1.GetControllerOf(scratchPvc)
+	return owner != nil && owner.Kind == "Pod" && owner.Name == getImportPodNameFromPvc(pvc)
+}
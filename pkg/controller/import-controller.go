@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -22,6 +24,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -32,6 +35,7 @@ import (
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -41,8 +45,36 @@ const (
 	SourceHTTP = "http"
 	// SourceS3 is the source type S3
 	SourceS3 = "s3"
-	// SourceGlance is the source type of glance
+	// SourceAWSSnapshot is the source type EBS snapshot, read with the EBS direct APIs
+	SourceAWSSnapshot = "aws-snapshot"
+	// SourceGCS is the source type Google Cloud Storage
+	SourceGCS = "gcs"
+	// SourceGCEImage is the source type Google Compute Engine image
+	SourceGCEImage = "gce-image"
+	// SourceAzureBlob is the source type Azure Blob Storage
+	SourceAzureBlob = "azure-blob"
+	// SourceAzureDisk is the source type Azure managed disk or snapshot
+	SourceAzureDisk = "azure-disk"
+	// SourceGlance is the source type OpenStack Glance image
 	SourceGlance = "glance"
+	// SourceProxmox is the source type Proxmox VE VM disk
+	SourceProxmox = "proxmox"
+	// SourceHyperV is the source type Hyper-V disk on an SMB share
+	SourceHyperV = "hyperv"
+	// SourceNFS is the source type of image file on an NFS export
+	SourceNFS = "nfs"
+	// SourceSMB is the source type of image file on an SMB share
+	SourceSMB = "smb"
+	// SourceRsync is the source type of image file pulled with rsync over SSH
+	SourceRsync = "rsync"
+	// SourceISCSI is the source type of LUN of an iSCSI target
+	SourceISCSI = "iscsi"
+	// SourceRBD is the source type of RBD image of an external Ceph cluster
+	SourceRBD = "rbd"
+	// SourceFile is the source type of image file on a host path or an existing PVC
+	SourceFile = "file"
+	// SourceLibvirt is the source type of disk of a domain of a remote libvirt host
+	SourceLibvirt = "libvirt"
 	// SourceNone means there is no source.
 	SourceNone = "none"
 	// SourceRegistry is the source type of Registry
@@ -60,6 +92,84 @@ const (
 	AnnSecret = AnnAPIGroup + "/storage.import.secretName"
 	// AnnCertConfigMap is the name of a configmap containing tls certs
 	AnnCertConfigMap = AnnAPIGroup + "/storage.import.certConfigMap"
+	// AnnClientCertSecret provides a const for our PVC client certificate secretName annotation
+	AnnClientCertSecret = AnnAPIGroup + "/storage.import.clientCertSecretName"
+	// AnnImportServiceAccount provides a const for our PVC importer pod service account annotation
+	AnnImportServiceAccount = AnnAPIGroup + "/storage.import.serviceAccountName"
+	// AnnS3Region provides a const for our PVC S3 region annotation
+	AnnS3Region = AnnAPIGroup + "/storage.import.s3.region"
+	// AnnS3ForcePathStyle provides a const for our PVC S3 path-style addressing annotation
+	AnnS3ForcePathStyle = AnnAPIGroup + "/storage.import.s3.forcePathStyle"
+	// AnnS3SSECustomerKeySecret provides a const for our PVC S3 SSE-C customer key secretName annotation
+	AnnS3SSECustomerKeySecret = AnnAPIGroup + "/storage.import.s3.sseCustomerKeySecretName"
+	// AnnS3Segments provides a const for the number of concurrent ranged requests of a s3 import
+	AnnS3Segments = AnnAPIGroup + "/storage.import.s3.segments"
+	// AnnS3SegmentSize provides a const for the size of each ranged request of a s3 import
+	AnnS3SegmentSize = AnnAPIGroup + "/storage.import.s3.segmentSize"
+	// AnnS3ObjectVersionID provides a const for our PVC S3 object version annotation
+	AnnS3ObjectVersionID = AnnAPIGroup + "/storage.import.s3.objectVersionId"
+	// AnnAWSSnapshotRegion provides a const for our PVC EBS snapshot region annotation
+	AnnAWSSnapshotRegion = AnnAPIGroup + "/storage.import.awsSnapshot.region"
+	// AnnGCSSecret provides a const for our PVC GCS service account key secretName annotation
+	AnnGCSSecret = AnnAPIGroup + "/storage.import.gcs.secretName"
+	// AnnGCEImageSecret provides a const for our PVC GCE image service account key secretName annotation
+	AnnGCEImageSecret = AnnAPIGroup + "/storage.import.gceImage.secretName"
+	// AnnGlanceProject provides a const for our PVC OpenStack project annotation
+	AnnGlanceProject = AnnAPIGroup + "/storage.import.glance.project"
+	// AnnGlanceDomain provides a const for our PVC OpenStack domain annotation
+	AnnGlanceDomain = AnnAPIGroup + "/storage.import.glance.domain"
+	// AnnGlanceRegion provides a const for our PVC OpenStack image service region annotation
+	AnnGlanceRegion = AnnAPIGroup + "/storage.import.glance.region"
+	// AnnProxmoxNode provides a const for our PVC Proxmox VE node annotation
+	AnnProxmoxNode = AnnAPIGroup + "/storage.import.proxmox.node"
+	// AnnProxmoxVMID provides a const for our PVC Proxmox VE VM ID annotation
+	AnnProxmoxVMID = AnnAPIGroup + "/storage.import.proxmox.vmId"
+	// AnnSMBDomain provides a const for our PVC SMB user domain annotation
+	AnnSMBDomain = AnnAPIGroup + "/storage.import.smb.domain"
+	// AnnRsyncModule provides a const for our PVC rsync module annotation
+	AnnRsyncModule = AnnAPIGroup + "/storage.import.rsync.module"
+	// AnnRsyncHostKey provides a const for our PVC rsync SSH host key annotation
+	AnnRsyncHostKey = AnnAPIGroup + "/storage.import.rsync.hostKey"
+	// AnnISCSIInitiator provides a const for our PVC iSCSI initiator name annotation
+	AnnISCSIInitiator = AnnAPIGroup + "/storage.import.iscsi.initiatorName"
+	// AnnRBDMonitors provides a const for our PVC Ceph monitors annotation, the monitors are separated by commas
+	AnnRBDMonitors = AnnAPIGroup + "/storage.import.rbd.monitors"
+	// AnnFileHostPath provides a const for our PVC file source host path annotation
+	AnnFileHostPath = AnnAPIGroup + "/storage.import.file.hostPath"
+	// AnnFileNodeName provides a const for our PVC file source node name annotation
+	AnnFileNodeName = AnnAPIGroup + "/storage.import.file.nodeName"
+	// AnnFileClaimName provides a const for our PVC file source claim name annotation
+	AnnFileClaimName = AnnAPIGroup + "/storage.import.file.claimName"
+	// AnnLibvirtDomain provides a const for our PVC libvirt domain annotation
+	AnnLibvirtDomain = AnnAPIGroup + "/storage.import.libvirt.domain"
+	// AnnLibvirtDisk provides a const for our PVC libvirt disk target annotation
+	AnnLibvirtDisk = AnnAPIGroup + "/storage.import.libvirt.disk"
+	// AnnLibvirtHostKey provides a const for our PVC libvirt SSH host key annotation
+	AnnLibvirtHostKey = AnnAPIGroup + "/storage.import.libvirt.hostKey"
+	// AnnRegistryPlatform provides a const for our PVC registry platform annotation, selecting the image of a manifest list
+	AnnRegistryPlatform = AnnAPIGroup + "/storage.import.registry.platform"
+	// AnnRegistrySignatureConfigMap provides a const for our PVC registry signature configmap annotation, with the public key or the trusted roots verifying the signatures
+	AnnRegistrySignatureConfigMap = AnnAPIGroup + "/storage.import.registry.signature.configMap"
+	// AnnRegistrySignatureIdentity provides a const for our PVC registry signature identity annotation, the signer of keyless signatures
+	AnnRegistrySignatureIdentity = AnnAPIGroup + "/storage.import.registry.signature.identity"
+	// AnnRegistrySignatureIssuer provides a const for our PVC registry signature issuer annotation, the OIDC issuer of keyless signatures
+	AnnRegistrySignatureIssuer = AnnAPIGroup + "/storage.import.registry.signature.issuer"
+	// AnnAzureBlobSecret provides a const for our PVC Azure SAS token or account key secretName annotation
+	AnnAzureBlobSecret = AnnAPIGroup + "/storage.import.azureBlob.secretName"
+	// AnnAzureBlobSegments provides a const for the number of concurrent ranged requests of an Azure blob import
+	AnnAzureBlobSegments = AnnAPIGroup + "/storage.import.azureBlob.segments"
+	// AnnAzureBlobSegmentSize provides a const for the size of each ranged request of an Azure blob import
+	AnnAzureBlobSegmentSize = AnnAPIGroup + "/storage.import.azureBlob.segmentSize"
+	// AnnAzureDiskSecret provides a const for our PVC Azure service principal secretName annotation
+	AnnAzureDiskSecret = AnnAPIGroup + "/storage.import.azureDisk.secretName"
+	// AnnTokenSecret provides a const for our PVC bearer token secretName annotation
+	AnnTokenSecret = AnnAPIGroup + "/storage.import.tokenSecretName"
+	// AnnOAuth2TokenURL provides a const for our PVC OAuth2 token endpoint annotation
+	AnnOAuth2TokenURL = AnnAPIGroup + "/storage.import.oauth2.tokenURL"
+	// AnnOAuth2Secret provides a const for our PVC OAuth2 client credentials secretName annotation
+	AnnOAuth2Secret = AnnAPIGroup + "/storage.import.oauth2.secretName"
+	// AnnOAuth2Scopes provides a const for our PVC OAuth2 scopes annotation, the scopes are separated by spaces
+	AnnOAuth2Scopes = AnnAPIGroup + "/storage.import.oauth2.scopes"
 	// AnnContentType provides a const for the PVC content-type
 	AnnContentType = AnnAPIGroup + "/storage.contentType"
 	// AnnImportPod provides a const for our PVC importPodName annotation
@@ -74,8 +184,33 @@ const (
 	AnnBackingFile = AnnAPIGroup + "/storage.import.backingFile"
 	// AnnThumbprint provides a const for our PVC backing thumbprint annotation
 	AnnThumbprint = AnnAPIGroup + "/storage.import.vddk.thumbprint"
+	// AnnVDDKConnections provides a const for the number of concurrent NBD connections of a vddk import
+	AnnVDDKConnections = AnnAPIGroup + "/storage.import.vddk.connections"
 	// AnnPreallocationApplied provides a const for PVC preallocation annotation
 	AnnPreallocationApplied = AnnAPIGroup + "/storage.preallocation"
+	// AnnHTTPSegments provides a const for the number of concurrent ranged requests of a http import
+	AnnHTTPSegments = AnnAPIGroup + "/storage.import.http.segments"
+	// AnnHTTPSegmentSize provides a const for the size of each ranged request of a http import
+	AnnHTTPSegmentSize = AnnAPIGroup + "/storage.import.http.segmentSize"
+	// AnnFTPPassive provides a const for the passive mode of a ftp import
+	AnnFTPPassive = AnnAPIGroup + "/storage.import.ftp.passive"
+	// AnnSourceETag provides a const for the ETag of the http or s3 source imported by a DataImportCron, its next polls send conditional requests with it
+	AnnSourceETag = AnnAPIGroup + "/storage.import.source.etag"
+	// AnnSourceLastModified provides a const for the Last-Modified time of the http source imported by a DataImportCron
+	AnnSourceLastModified = AnnAPIGroup + "/storage.import.source.lastModified"
+	// AnnSourceVersionID provides a const for the version of the s3 object imported by a DataImportCron
+	AnnSourceVersionID = AnnAPIGroup + "/storage.import.source.versionId"
+	// AnnSourceFormat provides a const for the disk image format of the source detected by the importer
+	AnnSourceFormat = AnnAPIGroup + "/storage.import.source.format"
+	// AnnSourceCompression provides a const for the compression of the source detected by the importer
+	AnnSourceCompression = AnnAPIGroup + "/storage.import.source.compression"
+	// AnnSourceVirtualSize provides a const for the virtual size in bytes of the source image
+	AnnSourceVirtualSize = AnnAPIGroup + "/storage.import.source.virtualSize"
+	// AnnSourceDownloadedSize provides a const for the size in bytes of the source as downloaded
+	AnnSourceDownloadedSize = AnnAPIGroup + "/storage.import.source.downloadedSize"
+
+	// AnnImportQueued is a PVC annotation telling the import waits for the number of importer pods to go under the maximum number of parallel imports
+	AnnImportQueued = AnnAPIGroup + "/storage.import.queued"
 
 	//LabelImportPvc is a pod label used to find the import pod that was created by the relevant PVC
 	LabelImportPvc = AnnAPIGroup + "/storage.import.importPvcName"
@@ -93,14 +228,53 @@ const (
 	// awaitingVddk provides a const to indicate the PVC is waiting for a VDDK image
 	awaitingVddk = "AwaitingVDDK"
 
+	// fileSourceHostPathDisabled provides a const to indicate the PVC is waiting for the FileSourceHostPath feature gate
+	fileSourceHostPathDisabled = "FileSourceHostPathDisabled"
+	// fileSourceHostPathNotAllowed provides a const to indicate the host path of the PVC is not allowed by the CDIConfig
+	fileSourceHostPathNotAllowed = "FileSourceHostPathNotAllowed"
+
+	// signatureNotVerified provides a const to indicate the import failed because no signature of the image verifies
+	signatureNotVerified = "SignatureNotVerified"
+
 	// ImportTargetInUse is reason for event created when an import pvc is in use
 	ImportTargetInUse = "ImportTargetInUse"
 
+	// ImportQueued is reason for event created when an import waits for the number of parallel imports to go down
+	ImportQueued = "ImportQueued"
+
 	// PreallocationApplied is a string inserted into importer's/uploader's exit message
 	PreallocationApplied = "Preallocation applied"
 
 	// PreallocationSkipped is a string inserted into importer's/uploader's exit message
 	PreallocationSkipped = "Preallocation skipped"
+
+	// SourceETagMessagePrefix is the prefix of the line in the importer's exit message containing the ETag of the source
+	SourceETagMessagePrefix = "ETag: "
+
+	// SourceLastModifiedMessagePrefix is the prefix of the line in the importer's exit message containing the Last-Modified time of the source
+	SourceLastModifiedMessagePrefix = "Last-Modified: "
+
+	// SourceVersionIDMessagePrefix is the prefix of the line in the importer's exit message containing the version of the source
+	SourceVersionIDMessagePrefix = "VersionId: "
+
+	// SourceDigestMessagePrefix is the prefix of the line in the importer's exit message containing the digest of the
+	// registry image polled for a DataImportCron
+	SourceDigestMessagePrefix = "Digest: "
+
+	// SourceFormatMessagePrefix is the prefix of the line in the importer's exit message containing the format of the source
+	SourceFormatMessagePrefix = "Format: "
+
+	// SourceCompressionMessagePrefix is the prefix of the line in the importer's exit message containing the compression of the source
+	SourceCompressionMessagePrefix = "Compression: "
+
+	// SourceVirtualSizeMessagePrefix is the prefix of the line in the importer's exit message containing the virtual size of the source
+	SourceVirtualSizeMessagePrefix = "Virtual-Size: "
+
+	// SourceDownloadedSizeMessagePrefix is the prefix of the line in the importer's exit message containing the downloaded size of the source
+	SourceDownloadedSizeMessagePrefix = "Downloaded-Size: "
+
+	// label of the pods the Azure AD workload identity webhook injects the federated credentials into
+	azureWorkloadIdentityLabel = "azure.workload.identity/use"
 )
 
 // ImportReconciler members
@@ -134,6 +308,48 @@ type importPodEnvVar struct {
 	previousCheckpoint string
 	finalCheckpoint    string
 	preallocation      bool
+	httpSegments       string
+	httpSegmentSize    string
+	sourceETag         string
+	sourceLastModified string
+	tokenSecretName    string
+	oauth2TokenURL     string
+	oauth2SecretName   string
+	oauth2Scopes       string
+	clientCertSecret   string
+	tlsConfig          *cdiv1.TLSConfig
+	trustedCAConfigMap string
+	serviceAccount     string
+	s3Region           string
+	s3ForcePathStyle   string
+	s3SSECustomerKey   string
+	s3ObjectVersionID  string
+	sourceVersionID    string
+	gcsSecretName      string
+	azureSecretName    string
+	glanceProject      string
+	glanceDomain       string
+	glanceRegion       string
+	proxmoxNode        string
+	proxmoxVMID        string
+	smbDomain          string
+	ftpPassive         string
+	rsyncModule        string
+	rsyncHostKey       string
+	iscsiInitiator     string
+	rbdMonitors        string
+	fileHostPath       string
+	fileNodeName       string
+	fileClaimName      string
+	libvirtDomain      string
+	libvirtDisk        string
+	libvirtHostKey     string
+	registriesConfig   string
+	registryPlatform   string
+	signatureConfigMap string
+	signatureIdentity  string
+	signatureIssuer    string
+	featureGates       string
 }
 
 // NewImportController creates a new instance of the import controller.
@@ -189,9 +405,11 @@ func (r *ImportReconciler) shouldReconcilePVC(pvc *corev1.PersistentVolumeClaim,
 	if err != nil {
 		return false, err
 	}
+	// The import of a PVC waiting for its first consumer starts once the scheduler selected the node of the consumer
+	_, isNodeSelected := pvc.Annotations[AnnSelectedNode]
 	return !isPVCComplete(pvc) &&
 			(checkPVC(pvc, AnnEndpoint, log) || checkPVC(pvc, AnnSource, log)) &&
-			shouldHandlePvc(pvc, waitForFirstConsumerEnabled, log),
+			(shouldHandlePvc(pvc, waitForFirstConsumerEnabled, log) || isNodeSelected),
 		nil
 }
 
@@ -214,6 +432,10 @@ func (r *ImportReconciler) Reconcile(req reconcile.Request) (reconcile.Result, e
 		return reconcile.Result{}, err
 	}
 
+	if pvc.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(pvc, importCleanupFinalizer) {
+		return r.cleanupCancelledImport(pvc, log)
+	}
+
 	shouldReconcile, err := r.shouldReconcilePVC(pvc, log)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -269,11 +491,37 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 		return reconcile.Result{}, err
 	}
 
+	if importPauseRequested(pvc, pod) {
+		return reconcile.Result{}, r.pauseImport(pvc, pod, log)
+	}
+
 	if pod == nil {
 		if isPVCComplete(pvc) {
 			// Don't create the POD if the PVC is completed already
 			log.V(1).Info("PVC is already complete")
 		} else if pvc.DeletionTimestamp == nil {
+			if importRetriesExhausted(pvc) {
+				log.V(1).Info("Import failed at its last attempt, not retrying")
+				return reconcile.Result{}, nil
+			}
+			if importDeadlineExceeded(pvc) {
+				if pvc.GetAnnotations()[AnnRunningConditionReason] != ImportTimeout {
+					if err := r.deleteKeptScratchPvc(pvc); err != nil {
+						return reconcile.Result{}, err
+					}
+					r.failTimedOutImport(pvc, log)
+					return reconcile.Result{}, r.updatePVC(pvc, log)
+				}
+				return reconcile.Result{}, nil
+			}
+			if wait := importRetryWait(pvc); wait > 0 {
+				log.V(1).Info("Waiting for the backoff of the failed import attempt", "wait", wait)
+				if deadline, ok := importDeadline(pvc); ok && time.Until(deadline) < wait {
+					wait = time.Until(deadline)
+				}
+				return reconcile.Result{RequeueAfter: wait}, nil
+			}
+
 			podsUsingPVC, err := getPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), false)
 			if err != nil {
 				return reconcile.Result{}, err
@@ -291,10 +539,16 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 			}
 
 			if _, ok := pvc.Annotations[AnnImportPod]; ok {
-				// Create importer pod, make sure the PVC owns it.
-				if err := r.createImporterPod(pvc); err != nil {
+				admitted, err := r.admitImport(pvc, log)
+				if err != nil {
 					return reconcile.Result{}, err
 				}
+				// Create importer pod, make sure the PVC owns it.
+				if admitted {
+					if err := r.createImporterPod(pvc); err != nil {
+						return reconcile.Result{}, err
+					}
+				}
 			} else {
 				// Create importer pod Name and store in PVC?
 				if err := r.initPvcPodName(pvc, log); err != nil {
@@ -325,6 +579,38 @@ func (r *ImportReconciler) reconcilePvc(pvc *corev1.PersistentVolumeClaim, log l
 	return reconcile.Result{}, nil
 }
 
+// updateSourceValidatorsFromMessage records the validators of the source reported in the exit message of a poll pod.
+func updateSourceValidatorsFromMessage(anno map[string]string, message string) {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, SourceETagMessagePrefix) {
+			anno[AnnSourceETag] = strings.TrimPrefix(line, SourceETagMessagePrefix)
+		}
+		if strings.HasPrefix(line, SourceLastModifiedMessagePrefix) {
+			anno[AnnSourceLastModified] = strings.TrimPrefix(line, SourceLastModifiedMessagePrefix)
+		}
+		if strings.HasPrefix(line, SourceVersionIDMessagePrefix) {
+			anno[AnnSourceVersionID] = strings.TrimPrefix(line, SourceVersionIDMessagePrefix)
+		}
+	}
+}
+
+// updateSourceInfoFromMessage records the format, compression and sizes of the source reported in the importer's exit message.
+func updateSourceInfoFromMessage(anno map[string]string, message string) {
+	prefixes := map[string]string{
+		SourceFormatMessagePrefix:         AnnSourceFormat,
+		SourceCompressionMessagePrefix:    AnnSourceCompression,
+		SourceVirtualSizeMessagePrefix:    AnnSourceVirtualSize,
+		SourceDownloadedSizeMessagePrefix: AnnSourceDownloadedSize,
+	}
+	for _, line := range strings.Split(message, "\n") {
+		for prefix, ann := range prefixes {
+			if strings.HasPrefix(line, prefix) {
+				anno[ann] = strings.TrimPrefix(line, prefix)
+			}
+		}
+	}
+}
+
 func (r *ImportReconciler) initPvcPodName(pvc *corev1.PersistentVolumeClaim, log logr.Logger) error {
 	currentPvcCopy := pvc.DeepCopyObject()
 
@@ -354,20 +640,28 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 	log.V(1).Info("Updating PVC from pod")
 	anno := pvc.GetAnnotations()
 	setConditionFromPodWithPrefix(anno, AnnRunningCondition, pod)
+	if message, ok := signatureNotVerifiedMessage(pod); ok {
+		// The pod restarts after the failure, keep the reason on the running condition meanwhile
+		anno[AnnRunningConditionMessage] = message
+		anno[AnnRunningConditionReason] = signatureNotVerified
+	}
 
+	timedOut := isPodDeadlineExceeded(pod)
 	scratchExitCode := false
-	if pod.Status.ContainerStatuses != nil &&
-		pod.Status.ContainerStatuses[0].LastTerminationState.Terminated != nil &&
-		pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.ExitCode > 0 {
-		log.Info("Pod termination code", "pod.Name", pod.Name, "ExitCode", pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.ExitCode)
-		if pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.ExitCode == common.ScratchSpaceNeededExitCode {
+	restarts := anno[AnnPodRestarts]
+	terminated := podFailedTermination(pod)
+	if terminated != nil && !timedOut {
+		log.Info("Pod termination code", "pod.Name", pod.Name, "ExitCode", terminated.ExitCode)
+		if terminated.ExitCode == common.ScratchSpaceNeededExitCode {
 			log.V(1).Info("Pod requires scratch space, terminating pod, and restarting with scratch space", "pod.Name", pod.Name)
 			scratchExitCode = true
 			anno[AnnRequiresScratch] = "true"
 		} else {
-			r.recorder.Event(pvc, corev1.EventTypeWarning, ErrImportFailedPVC, pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.Message)
+			r.recorder.Event(pvc, corev1.EventTypeWarning, ErrImportFailedPVC, terminated.Message)
 		}
 	}
+	// Pods of imports with a retry policy don't restart, every failed pod is an attempt
+	attemptFailed := pod.Status.Phase == corev1.PodFailed && pod.Spec.RestartPolicy == corev1.RestartPolicyNever && !scratchExitCode && !timedOut
 	if pod.Status.ContainerStatuses != nil &&
 		pod.Status.ContainerStatuses[0].State.Terminated != nil &&
 		pod.Status.ContainerStatuses[0].State.Terminated.ExitCode == 0 {
@@ -377,6 +671,11 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		if strings.Contains(pod.Status.ContainerStatuses[0].State.Terminated.Message, PreallocationSkipped) {
 			anno[AnnPreallocationApplied] = "skipped"
 		}
+		updateSourceInfoFromMessage(anno, pod.Status.ContainerStatuses[0].State.Terminated.Message)
+		// The pod is seen again until it is deleted, the durations are observed once
+		if anno[AnnPodPhase] != string(corev1.PodSucceeded) {
+			observePhaseDurationsFromMessage(pvc, pod.Status.ContainerStatuses[0].State.Terminated.Message)
+		}
 	}
 
 	if anno[AnnCurrentCheckpoint] != "" {
@@ -384,7 +683,11 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 	}
 
 	if pod.Status.ContainerStatuses != nil {
-		anno[AnnPodRestarts] = strconv.Itoa(int(pod.Status.ContainerStatuses[0].RestartCount))
+		anno[AnnPodRestarts] = strconv.Itoa(int(pod.Status.ContainerStatuses[0].RestartCount) + getImportAttempts(pvc))
+	}
+	// The failure of a restarting pod is seen until its next restart, a failed pod of a retry policy is seen once
+	if terminated != nil && !timedOut && !scratchExitCode && (attemptFailed || anno[AnnPodRestarts] != restarts) {
+		countImportFailure(pvc, importFailureReason(terminated.Message))
 	}
 
 	anno[AnnImportPod] = string(pod.Name)
@@ -393,6 +696,24 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		// phase, because the pod might terminate cleanly and mistakenly mark the import complete.
 		anno[AnnPodPhase] = string(pod.Status.Phase)
 	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		delete(anno, AnnImportAttempts)
+		delete(anno, AnnImportRetryTime)
+	}
+	if attemptFailed {
+		if err := r.retryFailedImport(pvc, log); err != nil {
+			return err
+		}
+		if !importRetriesExhausted(pvc) {
+			// The next attempt resumes the download kept in the scratch space
+			if err := r.keepScratchPvc(pvc, pod); err != nil {
+				return err
+			}
+		}
+	}
+	if timedOut {
+		r.failTimedOutImport(pvc, log)
+	}
 
 	// Check if the POD is waiting for scratch space, if so create some.
 	if pod.Status.Phase == corev1.PodPending && r.requiresScratchSpace(pvc) {
@@ -415,6 +736,10 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		pvc.GetLabels()[common.CDILabelKey] = common.CDILabelValue
 	}
 
+	if isPVCComplete(pvc) {
+		controllerutil.RemoveFinalizer(pvc, importCleanupFinalizer)
+	}
+
 	if !reflect.DeepEqual(currentPvcCopy, pvc) {
 		if err := r.updatePVC(pvc, log); err != nil {
 			return err
@@ -422,8 +747,8 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 		log.V(1).Info("Updated PVC", "pvc.anno.Phase", anno[AnnPodPhase], "pvc.anno.Restarts", anno[AnnPodRestarts])
 	}
 
-	if isPVCComplete(pvc) || scratchExitCode {
-		if !scratchExitCode {
+	if isPVCComplete(pvc) || scratchExitCode || attemptFailed || timedOut {
+		if !scratchExitCode && !attemptFailed && !timedOut {
 			r.recorder.Event(pvc, corev1.EventTypeNormal, ImportSucceededPVC, "Import Successful")
 			log.V(1).Info("Completed successfully, deleting POD", "pod.Name", pod.Name)
 		}
@@ -469,11 +794,53 @@ func (r *ImportReconciler) createImporterPod(pvc *corev1.PersistentVolumeClaim)
 		}
 	}
 
+	if getSource(pvc) == SourceFile && getValueFromAnnotation(pvc, AnnFileHostPath) != "" {
+		// Mounting directories of the nodes has to be allowed by the administrator
+		enabled, err := r.featureGates.FileSourceHostPathEnabled()
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			anno := pvc.GetAnnotations()
+			anno[AnnBoundCondition] = "false"
+			anno[AnnBoundConditionMessage] = fmt.Sprintf("waiting for the %s feature gate to import from a host path", featuregates.FileSourceHostPath)
+			anno[AnnBoundConditionReason] = fileSourceHostPathDisabled
+			if updateErr := r.updatePVC(pvc, r.log); updateErr != nil {
+				return updateErr
+			}
+			return errors.Errorf("the %s feature gate is not enabled", featuregates.FileSourceHostPath)
+		}
+		// The webhook only checks the DataVolumes, PVCs may be annotated directly
+		prefixes, err := GetFileSourceHostPathPrefixes(r.client)
+		if err != nil {
+			return err
+		}
+		if hostPath := getValueFromAnnotation(pvc, AnnFileHostPath); !util.HostPathAllowed(hostPath, prefixes) {
+			anno := pvc.GetAnnotations()
+			anno[AnnBoundCondition] = "false"
+			anno[AnnBoundConditionMessage] = fmt.Sprintf("the host path %s is not under the fileSourceHostPathPrefixes of the CDIConfig", hostPath)
+			anno[AnnBoundConditionReason] = fileSourceHostPathNotAllowed
+			if updateErr := r.updatePVC(pvc, r.log); updateErr != nil {
+				return updateErr
+			}
+			return errors.Errorf("the host path %s is not allowed", hostPath)
+		}
+	}
+
 	podEnvVar, err := r.createImportEnvVar(pvc)
 	if err != nil {
 		return err
 	}
 
+	// The finalizer makes sure the import is cleaned up if the PVC is deleted in the middle of it
+	needsUpdate := !controllerutil.ContainsFinalizer(pvc, importCleanupFinalizer)
+	controllerutil.AddFinalizer(pvc, importCleanupFinalizer)
+	if startImportTimeout(pvc) || needsUpdate {
+		if err := r.updatePVC(pvc, r.log); err != nil {
+			return err
+		}
+	}
+
 	// all checks passed, let's create the importer pod!
 	pod, err := createImporterPod(r.log, r.client, r.image, r.verbose, r.pullPolicy, podEnvVar, pvc, scratchPvcName, vddkImageName)
 
@@ -524,6 +891,114 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		podEnvVar.previousCheckpoint = getValueFromAnnotation(pvc, AnnPreviousCheckpoint)
 		podEnvVar.currentCheckpoint = getValueFromAnnotation(pvc, AnnCurrentCheckpoint)
 		podEnvVar.finalCheckpoint = getValueFromAnnotation(pvc, AnnFinalCheckpoint)
+		segmentsAnn, segmentSizeAnn := AnnHTTPSegments, AnnHTTPSegmentSize
+		if podEnvVar.source == SourceS3 {
+			segmentsAnn, segmentSizeAnn = AnnS3Segments, AnnS3SegmentSize
+		} else if podEnvVar.source == SourceAzureBlob {
+			segmentsAnn, segmentSizeAnn = AnnAzureBlobSegments, AnnAzureBlobSegmentSize
+		} else if podEnvVar.source == SourceVDDK {
+			// The NBD connections of the vddk source are its segments, copied concurrently
+			segmentsAnn = AnnVDDKConnections
+		}
+		podEnvVar.httpSegments = getValueFromAnnotation(pvc, segmentsAnn)
+		if segmentSize := getValueFromAnnotation(pvc, segmentSizeAnn); segmentSize != "" {
+			q, err := resource.ParseQuantity(segmentSize)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid segment size %q", segmentSize)
+			}
+			podEnvVar.httpSegmentSize = strconv.FormatInt(q.Value(), 10)
+		}
+		if podEnvVar.source == SourceHTTP {
+			podEnvVar.sourceETag = getValueFromAnnotation(pvc, AnnSourceETag)
+			podEnvVar.sourceLastModified = getValueFromAnnotation(pvc, AnnSourceLastModified)
+			podEnvVar.tokenSecretName = getValueFromAnnotation(pvc, AnnTokenSecret)
+			podEnvVar.oauth2TokenURL = getValueFromAnnotation(pvc, AnnOAuth2TokenURL)
+			podEnvVar.oauth2SecretName = getValueFromAnnotation(pvc, AnnOAuth2Secret)
+			podEnvVar.oauth2Scopes = getValueFromAnnotation(pvc, AnnOAuth2Scopes)
+			podEnvVar.clientCertSecret = getValueFromAnnotation(pvc, AnnClientCertSecret)
+			podEnvVar.ftpPassive = getValueFromAnnotation(pvc, AnnFTPPassive)
+		}
+		podEnvVar.tlsConfig, err = GetTLSConfig(r.client)
+		if err != nil {
+			return nil, err
+		}
+		podEnvVar.trustedCAConfigMap, err = SyncTrustedCA(r.uncachedClient, pvc.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		podEnvVar.serviceAccount, err = r.getImportServiceAccount(pvc)
+		if err != nil {
+			return nil, err
+		}
+		if podEnvVar.source == SourceS3 {
+			podEnvVar.s3Region = getValueFromAnnotation(pvc, AnnS3Region)
+			podEnvVar.s3ForcePathStyle = getValueFromAnnotation(pvc, AnnS3ForcePathStyle)
+			podEnvVar.s3SSECustomerKey = getValueFromAnnotation(pvc, AnnS3SSECustomerKeySecret)
+			podEnvVar.s3ObjectVersionID = getValueFromAnnotation(pvc, AnnS3ObjectVersionID)
+			podEnvVar.sourceETag = getValueFromAnnotation(pvc, AnnSourceETag)
+			podEnvVar.sourceVersionID = getValueFromAnnotation(pvc, AnnSourceVersionID)
+		}
+		if podEnvVar.source == SourceAWSSnapshot {
+			podEnvVar.s3Region = getValueFromAnnotation(pvc, AnnAWSSnapshotRegion)
+		}
+		if podEnvVar.source == SourceGCS {
+			podEnvVar.gcsSecretName = getValueFromAnnotation(pvc, AnnGCSSecret)
+			podEnvVar.sourceETag = getValueFromAnnotation(pvc, AnnSourceETag)
+			podEnvVar.sourceLastModified = getValueFromAnnotation(pvc, AnnSourceLastModified)
+		}
+		if podEnvVar.source == SourceGCEImage {
+			podEnvVar.gcsSecretName = getValueFromAnnotation(pvc, AnnGCEImageSecret)
+		}
+		if podEnvVar.source == SourceGlance {
+			podEnvVar.glanceProject = getValueFromAnnotation(pvc, AnnGlanceProject)
+			podEnvVar.glanceDomain = getValueFromAnnotation(pvc, AnnGlanceDomain)
+			podEnvVar.glanceRegion = getValueFromAnnotation(pvc, AnnGlanceRegion)
+		}
+		if podEnvVar.source == SourceProxmox {
+			podEnvVar.proxmoxNode = getValueFromAnnotation(pvc, AnnProxmoxNode)
+			podEnvVar.proxmoxVMID = getValueFromAnnotation(pvc, AnnProxmoxVMID)
+		}
+		if podEnvVar.source == SourceHyperV || podEnvVar.source == SourceSMB {
+			podEnvVar.smbDomain = getValueFromAnnotation(pvc, AnnSMBDomain)
+		}
+		if podEnvVar.source == SourceRsync {
+			podEnvVar.rsyncModule = getValueFromAnnotation(pvc, AnnRsyncModule)
+			podEnvVar.rsyncHostKey = getValueFromAnnotation(pvc, AnnRsyncHostKey)
+		}
+		if podEnvVar.source == SourceISCSI {
+			podEnvVar.iscsiInitiator = getValueFromAnnotation(pvc, AnnISCSIInitiator)
+		}
+		if podEnvVar.source == SourceRBD {
+			podEnvVar.rbdMonitors = getValueFromAnnotation(pvc, AnnRBDMonitors)
+		}
+		if podEnvVar.source == SourceFile {
+			podEnvVar.fileHostPath = getValueFromAnnotation(pvc, AnnFileHostPath)
+			podEnvVar.fileNodeName = getValueFromAnnotation(pvc, AnnFileNodeName)
+			podEnvVar.fileClaimName = getValueFromAnnotation(pvc, AnnFileClaimName)
+		}
+		if podEnvVar.source == SourceLibvirt {
+			podEnvVar.libvirtDomain = getValueFromAnnotation(pvc, AnnLibvirtDomain)
+			podEnvVar.libvirtDisk = getValueFromAnnotation(pvc, AnnLibvirtDisk)
+			podEnvVar.libvirtHostKey = getValueFromAnnotation(pvc, AnnLibvirtHostKey)
+		}
+		if podEnvVar.source == SourceRegistry {
+			podEnvVar.registriesConfig, err = GetRegistriesConfig(r.client)
+			if err != nil {
+				return nil, err
+			}
+			podEnvVar.registryPlatform = getValueFromAnnotation(pvc, AnnRegistryPlatform)
+			podEnvVar.signatureConfigMap = getValueFromAnnotation(pvc, AnnRegistrySignatureConfigMap)
+			podEnvVar.signatureIdentity = getValueFromAnnotation(pvc, AnnRegistrySignatureIdentity)
+			podEnvVar.signatureIssuer = getValueFromAnnotation(pvc, AnnRegistrySignatureIssuer)
+		}
+		if podEnvVar.source == SourceAzureBlob {
+			podEnvVar.azureSecretName = getValueFromAnnotation(pvc, AnnAzureBlobSecret)
+			podEnvVar.sourceETag = getValueFromAnnotation(pvc, AnnSourceETag)
+			podEnvVar.sourceLastModified = getValueFromAnnotation(pvc, AnnSourceLastModified)
+		}
+		if podEnvVar.source == SourceAzureDisk {
+			podEnvVar.azureSecretName = getValueFromAnnotation(pvc, AnnAzureDiskSecret)
+		}
 	}
 
 	if preallocation, err := strconv.ParseBool(getValueFromAnnotation(pvc, AnnPreallocationRequested)); err == nil {
@@ -535,6 +1010,12 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 	if err != nil {
 		return nil, err
 	}
+
+	featureGates, err := r.featureGates.EnabledFeatureGates()
+	if err != nil {
+		return nil, err
+	}
+	podEnvVar.featureGates = featuregates.FormatFeatureGates(featureGates)
 	return podEnvVar, nil
 }
 
@@ -618,6 +1099,50 @@ func (r *ImportReconciler) getSecretName(pvc *corev1.PersistentVolumeClaim) stri
 	return name
 }
 
+// getImportServiceAccount returns the service account of the importer pod, taken from the spec of the DataVolume owning
+// the PVC. The webhook checked that the creator of the DataVolume may use it, so the annotation of PVCs not owned by a
+// DataVolume is ignored.
+func (r *ImportReconciler) getImportServiceAccount(pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if getValueFromAnnotation(pvc, AnnImportServiceAccount) == "" {
+		return "", nil
+	}
+	owner := metav1.GetControllerOf(pvc)
+	if owner == nil || owner.Kind != "DataVolume" {
+		r.log.V(1).Info("Ignoring the importer service account of a PVC not owned by a DataVolume", "pvc", pvc.Name)
+		return "", nil
+	}
+	dataVolume := &cdiv1.DataVolume{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: owner.Name}, dataVolume); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if dataVolume.UID != owner.UID {
+		return "", nil
+	}
+	return importSourceServiceAccount(&dataVolume.Spec.Source), nil
+}
+
+// importSourceServiceAccount returns the service account the importer pod of the source runs with
+func importSourceServiceAccount(source *cdiv1.DataVolumeSource) string {
+	switch {
+	case source.S3 != nil:
+		return source.S3.ServiceAccountName
+	case source.AWSSnapshot != nil:
+		return source.AWSSnapshot.ServiceAccountName
+	case source.GCS != nil:
+		return source.GCS.ServiceAccountName
+	case source.GCEImage != nil:
+		return source.GCEImage.ServiceAccountName
+	case source.AzureBlob != nil:
+		return source.AzureBlob.ServiceAccountName
+	case source.AzureDisk != nil:
+		return source.AzureDisk.ServiceAccountName
+	}
+	return ""
+}
+
 func (r *ImportReconciler) requiresScratchSpace(pvc *corev1.PersistentVolumeClaim) bool {
 	scratchRequired := false
 	contentType := getContentType(pvc)
@@ -626,7 +1151,7 @@ func (r *ImportReconciler) requiresScratchSpace(pvc *corev1.PersistentVolumeClai
 		scratchRequired = true
 	} else {
 		switch getSource(pvc) {
-		case SourceGlance:
+		case SourceGlance, SourceProxmox, SourceHyperV, SourceRsync:
 			scratchRequired = true
 		case SourceRegistry:
 			scratchRequired = true
@@ -664,6 +1189,13 @@ func (r *ImportReconciler) createScratchPvcForPod(pvc *corev1.PersistentVolumeCl
 		anno[AnnBoundConditionMessage] = "Creating scratch space"
 		anno[AnnBoundConditionReason] = creatingScratch
 	} else {
+		if metav1.IsControlledBy(scratchPvc, pvc) {
+			// Scratch space kept while the import was paused
+			scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(pod)}
+			if err := r.client.Update(context.TODO(), scratchPvc); err != nil {
+				return err
+			}
+		}
 		setBoundConditionFromPVC(anno, AnnBoundCondition, scratchPvc)
 	}
 	return nil
@@ -699,7 +1231,21 @@ func getSource(pvc *corev1.PersistentVolumeClaim) string {
 	case
 		SourceHTTP,
 		SourceS3,
+		SourceAWSSnapshot,
+		SourceGCS,
+		SourceGCEImage,
+		SourceAzureBlob,
+		SourceAzureDisk,
 		SourceGlance,
+		SourceProxmox,
+		SourceHyperV,
+		SourceNFS,
+		SourceSMB,
+		SourceRsync,
+		SourceISCSI,
+		SourceRBD,
+		SourceFile,
+		SourceLibvirt,
 		SourceNone,
 		SourceRegistry,
 		SourceImageio,
@@ -763,17 +1309,53 @@ func createImportPodNameFromPvc(pvc *corev1.PersistentVolumeClaim) string {
 // name, and pvc. A nil secret means the endpoint credentials are not passed to the
 // importer pod.
 func createImporterPod(log logr.Logger, client client.Client, image, verbose, pullPolicy string, podEnvVar *importPodEnvVar, pvc *corev1.PersistentVolumeClaim, scratchPvcName *string, vddkImageName *string) (*corev1.Pod, error) {
-	podResourceRequirements, err := GetDefaultPodResourceRequirements(client)
+	podResourceRequirements, err := GetPodResourceRequirements(client, pvc)
 	if err != nil {
 		return nil, err
 	}
 
-	workloadNodePlacement, err := GetWorkloadNodePlacement(client)
+	workloadNodePlacement, err := GetPodNodePlacement(client, pvc)
+	if err != nil {
+		return nil, err
+	}
+	consumerNodePlacement, err := GetConsumerNodePlacement(client, pvc)
 	if err != nil {
 		return nil, err
 	}
+	if consumerNodePlacement != nil {
+		log.V(3).Info("Scheduling the importer pod with the consumer of the PVC", "pvc.Name", pvc.Name)
+		workloadNodePlacement = mergeNodePlacement(workloadNodePlacement, consumerNodePlacement)
+	}
 
-	pod := makeImporterPodSpec(pvc.Namespace, image, verbose, pullPolicy, podEnvVar, pvc, scratchPvcName, podResourceRequirements, workloadNodePlacement, vddkImageName)
+	priorityClassName, err := GetPriorityClassName(client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := GetImportRetryPolicy(client, pvc)
+	if err != nil {
+		return nil, err
+	}
+	restartPolicy := corev1.RestartPolicyOnFailure
+	if retryPolicy != nil {
+		// The import controller retries the failed pods after the backoff of the retry policy
+		restartPolicy = corev1.RestartPolicyNever
+	}
+
+	podSecurity, err := GetPodSecurityConfig(client)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePullSecrets, err := GetImagePullSecrets(client)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := makeImporterPodSpec(pvc.Namespace, image, verbose, pullPolicy, podEnvVar, pvc, scratchPvcName, podResourceRequirements, workloadNodePlacement, priorityClassName, restartPolicy, vddkImageName)
+	SetRestrictedSecurityContext(pod, podSecurity)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
+	pod.Spec.ActiveDeadlineSeconds = importActiveDeadlineSeconds(pvc)
 
 	if err := client.Create(context.TODO(), pod); err != nil {
 		return nil, err
@@ -783,7 +1365,7 @@ func createImporterPod(log logr.Logger, client client.Client, image, verbose, pu
 }
 
 // makeImporterPodSpec creates and return the importer pod spec based on the passed-in endpoint, secret and pvc.
-func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar *importPodEnvVar, pvc *corev1.PersistentVolumeClaim, scratchPvcName *string, podResourceRequirements *corev1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement, vddkImageName *string) *corev1.Pod {
+func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar *importPodEnvVar, pvc *corev1.PersistentVolumeClaim, scratchPvcName *string, podResourceRequirements *corev1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement, priorityClassName string, restartPolicy corev1.RestartPolicy, vddkImageName *string) *corev1.Pod {
 	// importer pod name contains the pvc name
 	podName, _ := pvc.Annotations[AnnImportPod]
 
@@ -857,11 +1439,12 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 					},
 				},
 			},
-			RestartPolicy: corev1.RestartPolicyOnFailure,
-			Volumes:       volumes,
-			NodeSelector:  workloadNodePlacement.NodeSelector,
-			Tolerations:   workloadNodePlacement.Tolerations,
-			Affinity:      workloadNodePlacement.Affinity,
+			RestartPolicy:     restartPolicy,
+			Volumes:           volumes,
+			NodeSelector:      workloadNodePlacement.NodeSelector,
+			Tolerations:       workloadNodePlacement.Tolerations,
+			Affinity:          workloadNodePlacement.Affinity,
+			PriorityClassName: priorityClassName,
 		},
 	}
 
@@ -876,9 +1459,7 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 
 	if getVolumeMode(pvc) == corev1.PersistentVolumeBlock {
 		pod.Spec.Containers[0].VolumeDevices = addVolumeDevices()
-		pod.Spec.SecurityContext = &corev1.PodSecurityContext{
-			RunAsUser: &[]int64{0}[0],
-		}
+		pod.Spec.TerminationGracePeriodSeconds = &[]int64{importerBlockTerminationGracePeriod}[0]
 	} else {
 		pod.Spec.Containers[0].VolumeMounts = addImportVolumeMounts()
 	}
@@ -915,6 +1496,15 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 
 	pod.Spec.Containers[0].Env = makeImportEnv(podEnvVar, ownerUID)
 
+	if podEnvVar.serviceAccount != "" {
+		// Lets the importer use the cloud identity bound to the service account instead of static credentials
+		pod.Spec.ServiceAccountName = podEnvVar.serviceAccount
+		if podEnvVar.source == SourceAzureBlob || podEnvVar.source == SourceAzureDisk {
+			// The workload identity webhook only injects the Azure AD credentials into labeled pods
+			pod.Labels[azureWorkloadIdentityLabel] = "true"
+		}
+	}
+
 	if podEnvVar.certConfigMap != "" {
 		vm := corev1.VolumeMount{
 			Name:      CertVolName,
@@ -936,6 +1526,94 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 		pod.Spec.Volumes = append(pod.Spec.Volumes, vol)
 	}
 
+	if podEnvVar.trustedCAConfigMap != "" {
+		addTrustedCAVolume(pod, podEnvVar.trustedCAConfigMap)
+	}
+
+	if podEnvVar.signatureConfigMap != "" {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      SignatureVolName,
+			MountPath: common.ImporterSignatureDir,
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: SignatureVolName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.signatureConfigMap,
+					},
+				},
+			},
+		})
+	}
+
+	if podEnvVar.source == SourceNFS {
+		server, file := parseNFSEndpoint(podEnvVar.ep)
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      NFSVolName,
+			MountPath: common.ImporterNFSDir,
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: NFSVolName,
+			VolumeSource: corev1.VolumeSource{
+				NFS: &corev1.NFSVolumeSource{
+					Server:   server,
+					Path:     path.Dir(file),
+					ReadOnly: true,
+				},
+			},
+		})
+	}
+
+	if podEnvVar.source == SourceFile {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      FileVolName,
+			MountPath: common.ImporterFileDir,
+			ReadOnly:  true,
+		})
+		vol := corev1.Volume{
+			Name: FileVolName,
+		}
+		if podEnvVar.fileHostPath != "" {
+			hostPathType := corev1.HostPathDirectory
+			vol.VolumeSource.HostPath = &corev1.HostPathVolumeSource{
+				Path: podEnvVar.fileHostPath,
+				Type: &hostPathType,
+			}
+			// The directory only exists on the given node
+			nodeSelector := map[string]string{}
+			for k, v := range pod.Spec.NodeSelector {
+				nodeSelector[k] = v
+			}
+			nodeSelector[corev1.LabelHostname] = podEnvVar.fileNodeName
+			pod.Spec.NodeSelector = nodeSelector
+		} else {
+			vol.VolumeSource.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: podEnvVar.fileClaimName,
+				ReadOnly:  true,
+			}
+		}
+		pod.Spec.Volumes = append(pod.Spec.Volumes, vol)
+	}
+
+	if podEnvVar.clientCertSecret != "" {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      ClientCertVolName,
+			MountPath: common.ImporterClientCertDir,
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: ClientCertVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: podEnvVar.clientCertSecret,
+				},
+			},
+		})
+	}
+
 	if podEnvVar.contentType == string(cdiv1.DataVolumeKubeVirt) {
 		// Set the fsGroup on the security context to the QemuSubGid
 		if pod.Spec.SecurityContext == nil {
@@ -948,6 +1626,30 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 	return pod
 }
 
+// signatureNotVerifiedMessage returns the exit message of the importer if it is not running because no signature of the
+// image verifies.
+func signatureNotVerifiedMessage(pod *corev1.Pod) (string, bool) {
+	if len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Running != nil {
+		return "", false
+	}
+	status := pod.Status.ContainerStatuses[0]
+	for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+		if terminated != nil && terminated.ExitCode != 0 && strings.Contains(terminated.Message, common.ImageSignatureNotVerified) {
+			return terminated.Message, true
+		}
+	}
+	return "", false
+}
+
+// parseNFSEndpoint returns the server and the path of the file of an NFS endpoint like nfs://server/export/disk.qcow2.
+func parseNFSEndpoint(endpoint string) (string, string) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", endpoint
+	}
+	return u.Hostname(), u.Path
+}
+
 // this is being called for pods using PV with filesystem volume mode
 func addImportVolumeMounts() []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
@@ -1022,6 +1724,22 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 			Name:  common.Preallocation,
 			Value: strconv.FormatBool(podEnvVar.preallocation),
 		},
+		{
+			Name:  common.ImporterHTTPSegments,
+			Value: podEnvVar.httpSegments,
+		},
+		{
+			Name:  common.ImporterHTTPSegmentSize,
+			Value: podEnvVar.httpSegmentSize,
+		},
+		{
+			Name:  common.ImporterSourceETag,
+			Value: podEnvVar.sourceETag,
+		},
+		{
+			Name:  common.ImporterSourceLastModified,
+			Value: podEnvVar.sourceLastModified,
+		},
 	}
 	if podEnvVar.secretName != "" {
 		env = append(env, corev1.EnvVar{
@@ -1047,11 +1765,295 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 		})
 
 	}
+	if podEnvVar.tokenSecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterBearerToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.tokenSecretName,
+					},
+					Key: common.KeyToken,
+				},
+			},
+		})
+	}
+	if podEnvVar.oauth2TokenURL != "" && podEnvVar.oauth2SecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterOAuth2TokenURL,
+			Value: podEnvVar.oauth2TokenURL,
+		}, corev1.EnvVar{
+			Name:  common.ImporterOAuth2Scopes,
+			Value: podEnvVar.oauth2Scopes,
+		}, corev1.EnvVar{
+			Name: common.ImporterOAuth2ClientID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.oauth2SecretName,
+					},
+					Key: common.KeyClientID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterOAuth2ClientSecret,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.oauth2SecretName,
+					},
+					Key: common.KeyClientSecret,
+				},
+			},
+		})
+	}
+	if podEnvVar.s3Region != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3Region,
+			Value: podEnvVar.s3Region,
+		})
+	}
+	if podEnvVar.s3ForcePathStyle != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3ForcePathStyle,
+			Value: podEnvVar.s3ForcePathStyle,
+		})
+	}
+	if podEnvVar.s3ObjectVersionID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3ObjectVersionID,
+			Value: podEnvVar.s3ObjectVersionID,
+		})
+	}
+	if podEnvVar.sourceVersionID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSourceVersionID,
+			Value: podEnvVar.sourceVersionID,
+		})
+	}
+	if podEnvVar.s3SSECustomerKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterS3SSECustomerKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.s3SSECustomerKey,
+					},
+					Key: common.KeySSECustomerKey,
+				},
+			},
+		})
+	}
+	if podEnvVar.glanceProject != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceProject,
+			Value: podEnvVar.glanceProject,
+		})
+	}
+	if podEnvVar.glanceDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceDomain,
+			Value: podEnvVar.glanceDomain,
+		})
+	}
+	if podEnvVar.glanceRegion != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceRegion,
+			Value: podEnvVar.glanceRegion,
+		})
+	}
+	if podEnvVar.proxmoxNode != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterProxmoxNode,
+			Value: podEnvVar.proxmoxNode,
+		})
+	}
+	if podEnvVar.proxmoxVMID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterProxmoxVMID,
+			Value: podEnvVar.proxmoxVMID,
+		})
+	}
+	if podEnvVar.smbDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSMBDomain,
+			Value: podEnvVar.smbDomain,
+		})
+	}
+	if podEnvVar.rsyncModule != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRsyncModule,
+			Value: podEnvVar.rsyncModule,
+		})
+	}
+	if podEnvVar.rsyncHostKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRsyncHostKey,
+			Value: podEnvVar.rsyncHostKey,
+		})
+	}
+	if podEnvVar.iscsiInitiator != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterISCSIInitiator,
+			Value: podEnvVar.iscsiInitiator,
+		})
+	}
+	if podEnvVar.rbdMonitors != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRBDMonitors,
+			Value: podEnvVar.rbdMonitors,
+		})
+	}
+	if podEnvVar.libvirtDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtDomain,
+			Value: podEnvVar.libvirtDomain,
+		})
+	}
+	if podEnvVar.libvirtDisk != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtDisk,
+			Value: podEnvVar.libvirtDisk,
+		})
+	}
+	if podEnvVar.libvirtHostKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtHostKey,
+			Value: podEnvVar.libvirtHostKey,
+		})
+	}
+	if podEnvVar.registriesConfig != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistriesConfig,
+			Value: podEnvVar.registriesConfig,
+		})
+	}
+	if podEnvVar.registryPlatform != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistryPlatform,
+			Value: podEnvVar.registryPlatform,
+		})
+	}
+	if podEnvVar.featureGates != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterFeatureGates,
+			Value: podEnvVar.featureGates,
+		})
+	}
+	if podEnvVar.signatureConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureDirVar,
+			Value: common.ImporterSignatureDir,
+		})
+	}
+	if podEnvVar.signatureIdentity != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureIdentity,
+			Value: podEnvVar.signatureIdentity,
+		})
+	}
+	if podEnvVar.signatureIssuer != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureIssuer,
+			Value: podEnvVar.signatureIssuer,
+		})
+	}
+	if podEnvVar.ftpPassive != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterFTPPassive,
+			Value: podEnvVar.ftpPassive,
+		})
+	}
+	if podEnvVar.gcsSecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterGCSServiceAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.gcsSecretName,
+					},
+					Key: common.KeyServiceAccountKey,
+				},
+			},
+		})
+	}
+	if podEnvVar.azureSecretName != "" && podEnvVar.source == SourceAzureDisk {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterAzureTenantID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyTenantID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureClientID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyClientID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureClientSecret,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyClientSecret,
+				},
+			},
+		})
+	} else if podEnvVar.azureSecretName != "" {
+		// The secret contains either a SAS token or the account key
+		optional := true
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterAzureSASToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key:      common.KeySASToken,
+					Optional: &optional,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key:      common.KeyAccountKey,
+					Optional: &optional,
+				},
+			},
+		})
+	}
 	if podEnvVar.certConfigMap != "" {
 		env = append(env, corev1.EnvVar{
 			Name:  common.ImporterCertDirVar,
 			Value: common.ImporterCertDir,
 		})
 	}
+	if podEnvVar.clientCertSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterClientCertDirVar,
+			Value: common.ImporterClientCertDir,
+		})
+	}
+	if podEnvVar.trustedCAConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		})
+	}
+	env = append(env, tlsconfig.EnvVars(podEnvVar.tlsConfig)...)
 	return env
 }
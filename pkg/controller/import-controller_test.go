@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -31,15 +32,19 @@ import (
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -106,6 +111,18 @@ var _ = Describe("Test PVC annotations status", func() {
 		Expect(r.shouldReconcilePVC(testPvc, importLog)).To(BeFalse())
 	})
 
+	It("Should be interesting if NOT BOUND, and endpoint and source is set, and honorWaitForFirstConsumerEnabled and the node of the consumer is selected", func() {
+		r := createImportReconciler()
+		r.featureGates = &FakeFeatureGates{honorWaitForFirstConsumerEnabled: true}
+		testPvc := createPendingPvc("testPvc1", "default", map[string]string{
+			AnnPodPhase:     string(corev1.PodPending),
+			AnnEndpoint:     testEndPoint,
+			AnnSource:       SourceHTTP,
+			AnnSelectedNode: "node01",
+		}, nil)
+		Expect(r.shouldReconcilePVC(testPvc, importLog)).To(BeTrue())
+	})
+
 	It("Should be interesting if NOT BOUND, and endpoint and source is set, and honorWaitForFirstConsumerEnabled and isImmediateBindingRequested is requested", func() {
 		r := createImportReconciler()
 		r.featureGates = &FakeFeatureGates{honorWaitForFirstConsumerEnabled: true}
@@ -291,6 +308,375 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 		Expect(pod.Spec.Tolerations).To(Equal(dummyTolerations))
 	})
 
+	It("Should create a POD with the image pull secrets of the CDI CR", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+
+		cr := &cdiv1.CDI{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)
+		Expect(err).ToNot(HaveOccurred())
+		pullSecrets := []v1.LocalObjectReference{{Name: "mirror-pull-secret"}}
+		cr.Spec.ImagePullSecrets = pullSecrets
+		err = reconciler.client.Update(context.TODO(), cr)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.ImagePullSecrets).To(Equal(pullSecrets))
+	})
+
+	It("Should create a POD on the node selected for the consumer of a WaitForFirstConsumer PVC", func() {
+		storageClass := createStorageClassWithBindingMode("wffc", nil, storagev1.VolumeBindingWaitForFirstConsumer)
+		pvc := createPvcInStorageClass("testPvc1", "default", &storageClass.Name, map[string]string{
+			AnnEndpoint:     testEndPoint,
+			AnnImportPod:    "importer-testPvc1",
+			AnnSelectedNode: "node01",
+		}, nil, corev1.ClaimPending)
+		reconciler = createImportReconciler(pvc, storageClass)
+		reconciler.featureGates = &FakeFeatureGates{honorWaitForFirstConsumerEnabled: true}
+
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelHostname, "node01"))
+	})
+
+	It("Should queue the import when the maximum number of parallel imports is reached", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		runningPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "importer-other",
+				Namespace: "other",
+				Labels:    map[string]string{common.CDIComponentLabel: common.ImporterPodName},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		reconciler = createImportReconciler(pvc, runningPod)
+		setMaxParallelImports(reconciler, 1)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnImportQueued, "true"))
+
+		By("Completing the running import")
+		runningPod.Status.Phase = corev1.PodSucceeded
+		err = reconciler.client.Update(context.TODO(), runningPod)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).ToNot(HaveKey(AnnImportQueued))
+	})
+
+	It("Should admit the queued imports in the order of creation of their PVCs", func() {
+		older := createPvc("olderPvc", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-olderPvc", AnnImportQueued: "true"}, nil)
+		older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.CreationTimestamp = metav1.Now()
+		reconciler = createImportReconciler(older, pvc)
+		setMaxParallelImports(reconciler, 1)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "olderPvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-olderPvc", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should queue the import when the maximum number of parallel imports of the namespace is reached", func() {
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant1",
+				Annotations: map[string]string{AnnMaxParallelImports: "1"},
+			},
+		}
+		runningPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "importer-running",
+				Namespace: "tenant1",
+				Labels:    map[string]string{common.CDIComponentLabel: common.ImporterPodName},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		older := createPvc("olderPvc", "tenant1", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-olderPvc", AnnImportQueued: "true"}, nil)
+		older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		pvc := createPvc("testPvc1", "tenant1", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.CreationTimestamp = metav1.Now()
+		otherPvc := createPvc("testPvc1", "tenant2", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		otherPvc.CreationTimestamp = metav1.Now()
+		reconciler = createImportReconciler(namespace, runningPod, older, pvc, otherPvc)
+		setMaxParallelImports(reconciler, 3)
+
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "tenant1"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "tenant1"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "tenant1"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnImportQueued, "true"))
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningCondition, "false"))
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportQueued))
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionMessage, fmt.Sprintf(MessageImportQueuedNamespace, 2, 1)))
+
+		By("Admitting the import of the other namespace")
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "tenant2"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "tenant2"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should report the position of the import in the queue of the cluster", func() {
+		runningPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "importer-running",
+				Namespace: "other",
+				Labels:    map[string]string{common.CDIComponentLabel: common.ImporterPodName},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		older := createPvc("olderPvc", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-olderPvc", AnnImportQueued: "true"}, nil)
+		older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.CreationTimestamp = metav1.Now()
+		reconciler = createImportReconciler(runningPod, older, pvc)
+		setMaxParallelImports(reconciler, 1)
+
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionMessage, fmt.Sprintf(MessageImportQueuedCluster, 2, 1)))
+	})
+
+	It("Should create a POD with the priority class of the PVC", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnPriorityClassName: "p0"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.PriorityClassName).To(Equal("p0"))
+	})
+
+	It("Should create a POD that does not restart with the retry policy of the PVC", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportRetryPolicy: `{"maxAttempts":3}`}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	})
+
+	It("Should not create a POD before the backoff of the failed attempt", func() {
+		retryTime := time.Now().Add(time.Minute).Format(time.RFC3339)
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportAttempts: "1", AnnImportRetryTime: retryTime, AnnPodPhase: string(corev1.PodPending)}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not create a POD after the last failed attempt", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportAttempts: "3", AnnPodPhase: string(corev1.PodFailed)}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should create a POD with the time left before the import timeout as active deadline", func() {
+		startTime := time.Now().Add(-time.Minute).Format(time.RFC3339)
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportTimeout: "10m", AnnImportStartTime: startTime}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.ActiveDeadlineSeconds).ToNot(BeNil())
+		Expect(*pod.Spec.ActiveDeadlineSeconds).To(BeNumerically("~", 9*60, 5))
+	})
+
+	It("Should start the import timeout at the creation of the first POD", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportTimeout: "10m"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKey(AnnImportStartTime))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*pod.Spec.ActiveDeadlineSeconds).To(BeNumerically("~", 10*60, 5))
+	})
+
+	It("Should fail the import without creating a POD past its timeout", func() {
+		startTime := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		retryTime := time.Now().Add(time.Minute).Format(time.RFC3339)
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportTimeout: "10m", AnnImportStartTime: startTime, AnnImportRetryTime: retryTime, AnnPodPhase: string(corev1.PodPending)}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(fmt.Sprintf(MessageImportTimeout, "10m0s")))
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodFailed)))
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportTimeout))
+		Expect(resultPvc.GetAnnotations()).ToNot(HaveKey(AnnImportRetryTime))
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should delete the scratch space kept for the next attempt when failing the import past its timeout", func() {
+		startTime := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		retryTime := time.Now().Add(time.Minute).Format(time.RFC3339)
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportTimeout: "10m", AnnImportStartTime: startTime, AnnImportRetryTime: retryTime, AnnPodPhase: string(corev1.PodPending)}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		scratchPvc := createPvc(createScratchNameFromPvc(pvc), "default", nil, nil)
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePVCOwnerReference(pvc)}
+		reconciler = createImportReconciler(pvc, scratchPvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resScratchPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: "default"}, resScratchPvc)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should add the cleanup finalizer when creating the POD", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.Finalizers).To(ContainElement(importCleanupFinalizer))
+	})
+
+	It("Should cancel the import of a deleted PVC before removing the cleanup finalizer", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnPodPhase: string(corev1.PodRunning)}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		pvc.Finalizers = []string{importCleanupFinalizer}
+		now := metav1.Now()
+		pvc.DeletionTimestamp = &now
+		scratchPvc := createPvc(createScratchNameFromPvc(pvc), "default", nil, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Status.Phase = corev1.PodRunning
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(pod)}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		resPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.Finalizers).To(ContainElement(importCleanupFinalizer))
+
+		By("Cleaning up once the POD is gone")
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resScratchPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: "default"}, resScratchPvc)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		resultPvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.Finalizers).ToNot(ContainElement(importCleanupFinalizer))
+	})
+
+	It("Should delete the POD of a paused import and keep its scratch space", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnPodPhase: string(corev1.PodRunning), AnnImportPaused: "true"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		scratchPvcName := createScratchNameFromPvc(pvc)
+		scratchPvc := createPvc(scratchPvcName, "default", nil, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Status.Phase = corev1.PodRunning
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(pod)}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		resPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodPending)))
+		Expect(resultPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportPausedByUser))
+		resScratchPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvcName, Namespace: "default"}, resScratchPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(resScratchPvc, resultPvc)).To(BeTrue())
+
+		By("Resuming the import")
+		delete(resultPvc.Annotations, AnnImportPaused)
+		err = reconciler.client.Update(context.TODO(), resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should not create a POD for a paused import", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnImportPaused: "true"}, nil)
+		pvc.Status.Phase = v1.ClaimBound
+		reconciler = createImportReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, pod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("Should create a POD if a PVC with all needed annotations is passed", func() {
 		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnPodNetwork: "net1"}, nil)
 		pvc.Status.Phase = v1.ClaimBound
@@ -335,7 +721,7 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 		Expect(pod.GetAnnotations()["annot1"]).ToNot(Equal("value1"))
 	})
 
-	It("Should create a POD if a bound PVC with all needed annotations is passed, but not set fsgroup if not kubevirt contenttype", func() {
+	It("Should create a POD if a bound PVC with all needed annotations is passed, and set the non-root fsgroup if not kubevirt contenttype", func() {
 		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnImportPod: "importer-testPvc1", AnnContentType: string(cdiv1.DataVolumeArchive)}, nil)
 		pvc.Status.Phase = v1.ClaimBound
 		reconciler = createImportReconciler(pvc)
@@ -352,8 +738,10 @@ var _ = Describe("ImportConfig Controller reconcile loop", func() {
 			}
 		}
 		Expect(foundEndPoint).To(BeTrue())
-		By("Verifying the fsGroupis not set")
-		Expect(pod.Spec.SecurityContext).To(BeNil())
+		By("Verifying the fsGroup lets the non-root importer write the archive")
+		Expect(*pod.Spec.SecurityContext.FSGroup).To(Equal(common.QemuSubGid))
+		Expect(*pod.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(pod.Annotations[corev1.SeccompPodAnnotationKey]).To(Equal(corev1.SeccompProfileRuntimeDefault))
 	})
 
 	It("Should error if a POD with the same name exists, but is not owned by the PVC, if a PVC with all needed annotations is passed", func() {
@@ -425,47 +813,236 @@ var _ = Describe("Update PVC from POD", func() {
 		Expect(resPvc.GetAnnotations()[AnnRunningConditionReason]).To(Equal("Reason"))
 	})
 
-	It("Should update the PVC status to running, if pod is running", func() {
-		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending)}, nil)
+	It("Should retry the failed import after the backoff of the retry policy", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnImportRetryPolicy: `{"maxAttempts":3,"initialBackoff":"20s"}`}, nil)
 		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
 		pod.Status = corev1.PodStatus{
-			Phase: corev1.PodRunning,
+			Phase: corev1.PodFailed,
 			ContainerStatuses: []v1.ContainerStatus{
 				{
 					State: v1.ContainerState{
-						Running: &v1.ContainerStateRunning{},
+						Terminated: &v1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Unable to connect to http data source",
+							Reason:   "Error",
+						},
 					},
 				},
 			},
 		}
 		reconciler = createImportReconciler(pvc, pod)
-		resPod := &corev1.Pod{}
-		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
-		Expect(err).ToNot(HaveOccurred())
-		err = reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
 		Expect(err).ToNot(HaveOccurred())
-		By("Checking pvc phase has been updated")
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring("Unable to connect to http data source"))
 		resPvc := &corev1.PersistentVolumeClaim{}
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(resPvc.GetAnnotations()[AnnPodPhase]).To(BeEquivalentTo(corev1.PodRunning))
-		Expect(resPvc.GetAnnotations()[AnnImportPod]).To(Equal(pod.Name))
-		By("Checking pod has NOT been deleted")
-		resPod = &corev1.Pod{}
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodPending)))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnImportAttempts, "1"))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodRestarts, "1"))
+		Expect(resPvc.GetAnnotations()).To(HaveKey(AnnImportRetryTime))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportRetryBackoff))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionMessage, "Attempt 1 failed: Unable to connect to http data source, retrying in 20s"))
+		By("Checking pod has been deleted")
+		resPod := &corev1.Pod{}
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
-		Expect(err).ToNot(HaveOccurred())
-		By("Making sure the label has been added")
-		Expect(resPvc.GetLabels()[common.CDILabelKey]).To(Equal(common.CDILabelValue))
-		Expect(resPvc.GetAnnotations()[AnnRunningCondition]).To(Equal("true"))
-		Expect(resPvc.GetAnnotations()[AnnRunningConditionMessage]).To(Equal(""))
-		Expect(resPvc.GetAnnotations()[AnnRunningConditionReason]).To(Equal("Pod is running"))
+		Expect(errors.IsNotFound(err)).To(BeTrue())
 	})
 
-	It("Should create scratch PVC, if pod is pending and PVC is marked with scratch", func() {
-		scratchPvcName := &corev1.PersistentVolumeClaim{}
-		scratchPvcName.Name = "testPvc1-scratch"
-		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnRequiresScratch: "true"}, nil, corev1.ClaimBound)
-		pod := createImporterTestPod(pvc, "testPvc1", scratchPvcName)
+	It("Should keep the scratch space of the failed attempt for the next one", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnImportRetryPolicy: `{"maxAttempts":3}`}, nil)
+		scratchPvc := createPvc(createScratchNameFromPvc(pvc), "default", nil, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Unable to transfer source data to scratch space",
+							Reason:   "Error",
+						},
+					},
+				},
+			},
+		}
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(pod)}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resScratchPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: "default"}, resScratchPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(resScratchPvc, pvc)).To(BeTrue())
+
+		By("Handing the scratch space over to the POD of the next attempt")
+		pod.Status = corev1.PodStatus{Phase: corev1.PodPending}
+		err = reconciler.client.Create(context.TODO(), pod)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.createScratchPvcForPod(pvc, pod)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: "default"}, resScratchPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(resScratchPvc, pod)).To(BeTrue())
+	})
+
+	It("Should not keep the scratch space of the last attempt", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnImportAttempts: "2", AnnImportRetryPolicy: `{"maxAttempts":3}`}, nil)
+		scratchPvc := createPvc(createScratchNameFromPvc(pvc), "default", nil, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", scratchPvc)
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+		pod.Status = corev1.PodStatus{Phase: corev1.PodFailed}
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePodOwnerReference(pod)}
+		reconciler = createImportReconciler(pvc, pod, scratchPvc)
+		reconciler.recorder = record.NewFakeRecorder(2)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resScratchPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: scratchPvc.Name, Namespace: "default"}, resScratchPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(resScratchPvc, pvc)).To(BeFalse())
+	})
+
+	It("Should fail the import at the last attempt of the retry policy", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnImportAttempts: "2", AnnImportRetryPolicy: `{"maxAttempts":3}`}, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Unable to connect to http data source",
+							Reason:   "Error",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		reconciler.recorder = record.NewFakeRecorder(2)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		<-reconciler.recorder.(*record.FakeRecorder).Events
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ImportRetriesExhausted))
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodFailed)))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnImportAttempts, "3"))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportRetriesExhausted))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionMessage, "Import failed after 3 attempts: Unable to connect to http data source"))
+		resPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should fail the import when the pod reached the deadline of the import timeout", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnImportTimeout: "1h"}, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase:  corev1.PodFailed,
+			Reason: podDeadlineExceededReason,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							ExitCode: 137,
+							Reason:   "Error",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(ImportTimeout))
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnPodPhase, string(corev1.PodFailed)))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionReason, ImportTimeout))
+		Expect(resPvc.GetAnnotations()).To(HaveKeyWithValue(AnnRunningConditionMessage, "Import did not complete within 1h0m0s"))
+		resPod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should remove the cleanup finalizer once the import succeeded", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning)}, nil)
+		pvc.Finalizers = []string{importCleanupFinalizer}
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Message: "Import Complete",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.Finalizers).ToNot(ContainElement(importCleanupFinalizer))
+	})
+
+	It("Should update the PVC status to running, if pod is running", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending)}, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Running: &v1.ContainerStateRunning{},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		resPod := &corev1.Pod{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		By("Checking pvc phase has been updated")
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnPodPhase]).To(BeEquivalentTo(corev1.PodRunning))
+		Expect(resPvc.GetAnnotations()[AnnImportPod]).To(Equal(pod.Name))
+		By("Checking pod has NOT been deleted")
+		resPod = &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "importer-testPvc1", Namespace: "default"}, resPod)
+		Expect(err).ToNot(HaveOccurred())
+		By("Making sure the label has been added")
+		Expect(resPvc.GetLabels()[common.CDILabelKey]).To(Equal(common.CDILabelValue))
+		Expect(resPvc.GetAnnotations()[AnnRunningCondition]).To(Equal("true"))
+		Expect(resPvc.GetAnnotations()[AnnRunningConditionMessage]).To(Equal(""))
+		Expect(resPvc.GetAnnotations()[AnnRunningConditionReason]).To(Equal("Pod is running"))
+	})
+
+	It("Should create scratch PVC, if pod is pending and PVC is marked with scratch", func() {
+		scratchPvcName := &corev1.PersistentVolumeClaim{}
+		scratchPvcName.Name = "testPvc1-scratch"
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnRequiresScratch: "true"}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", scratchPvcName)
 		pod.Status = corev1.PodStatus{
 			Phase: corev1.PodPending,
 			ContainerStatuses: []v1.ContainerStatus{
@@ -545,6 +1122,167 @@ var _ = Describe("Update PVC from POD", func() {
 		Expect(resPvc.GetAnnotations()[AnnRunningConditionReason]).To(Equal("Explosion"))
 	})
 
+	It("Should count the failure of a restarting pod once", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceRegistry, AnnPodPhase: string(corev1.PodRunning), AnnPodRestarts: "0"}, nil)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					RestartCount: 1,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Unable to connect to registry data source: manifest unknown",
+						},
+					},
+				},
+			},
+		}
+		failures := func() float64 {
+			metric := &dto.Metric{}
+			err := importFailures.WithLabelValues("404", SourceRegistry).Write(metric)
+			Expect(err).ToNot(HaveOccurred())
+			return metric.GetCounter().GetValue()
+		}
+		before := failures()
+
+		reconciler = createImportReconciler(pvc, pod)
+		reconciler.recorder = record.NewFakeRecorder(2)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.updatePvcFromPod(resPvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures()).To(Equal(before + 1))
+	})
+
+	table.DescribeTable("Should classify the failures of the importer", func(message, reason string) {
+		Expect(importFailureReason(message)).To(Equal(reason))
+	},
+		table.Entry("untrusted certificate", "Unable to connect to http data source: HTTP request errored: Get \"https://example.com/disk.img\": x509: certificate signed by unknown authority", "cert_invalid"),
+		table.Entry("unauthorized", "Unable to connect to http data source: expected status code 200, got 401. Status: 401 Unauthorized", "auth"),
+		table.Entry("wrong S3 credentials", "Unable to connect to s3 data source: could not get s3 object: AccessDenied: Access Denied", "auth"),
+		table.Entry("missing file", "Unable to connect to http data source: expected status code 200, got 404. Status: 404 Not Found", "404"),
+		table.Entry("full disk", "Unable to process data: write /data/disk.img: no space left on device", "disk_full"),
+		table.Entry("image larger than the PVC", "Unable to process data: Virtual image size 10737418240 is larger than available size 1020054732 (PVC size 10737418240, reserved overhead 0.055000%). A larger PVC is required.", "disk_full"),
+		table.Entry("conversion failure", "Unable to process data: could not convert image to raw: qemu-img execution failed: exit status 1", "convert_error"),
+		table.Entry("anything else", "I went poof", "other"),
+	)
+
+	It("Should report the signature failure on the running condition while the pod restarts", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: "docker://registry.example.com/images/fedora", AnnSource: SourceRegistry, AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		message := "Unable to process data: " + common.ImageSignatureNotVerified + ", no signature found"
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					RestartCount: 1,
+					State: v1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason: "CrashLoopBackOff",
+						},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  message,
+							Reason:   "Error",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnRunningCondition]).To(Equal("false"))
+		Expect(resPvc.GetAnnotations()[AnnRunningConditionMessage]).To(Equal(message))
+		Expect(resPvc.GetAnnotations()[AnnRunningConditionReason]).To(Equal(signatureNotVerified))
+	})
+
+	It("Should record the source format and sizes on the PVC, if pod completed successfully", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+							Message: "Import Complete\n" + SourceFormatMessagePrefix + "qcow2\n" + SourceCompressionMessagePrefix + "xz\n" +
+								SourceVirtualSizeMessagePrefix + "10737418240\n" + SourceDownloadedSizeMessagePrefix + "524288000",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnSourceFormat]).To(Equal("qcow2"))
+		Expect(resPvc.GetAnnotations()[AnnSourceCompression]).To(Equal("xz"))
+		Expect(resPvc.GetAnnotations()[AnnSourceVirtualSize]).To(Equal("10737418240"))
+		Expect(resPvc.GetAnnotations()[AnnSourceDownloadedSize]).To(Equal("524288000"))
+	})
+
+	It("Should observe the phase durations once, if pod completed successfully", func() {
+		storageClass := "phase-durations"
+		pvc := createPvcInStorageClass("testPvc1", "default", &storageClass, map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+							Message:  "Import Complete\n" + PhaseDurationsMessagePrefix + "info=0.250,transfer=120.500",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		reconciler.recorder = record.NewFakeRecorder(2)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.updatePvcFromPod(resPvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+
+		metric := &dto.Metric{}
+		err = importPhaseDuration.WithLabelValues("transfer", SourceS3, storageClass).(prometheus.Histogram).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metric.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+		Expect(metric.GetHistogram().GetSampleSum()).To(Equal(120.5))
+		err = importPhaseDuration.WithLabelValues("info", SourceS3, storageClass).(prometheus.Histogram).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metric.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+	})
+
+	It("should pass the previous S3 object version to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnS3ObjectVersionID: "version2", AnnSourceETag: "\"v1\"", AnnSourceVersionID: "version1"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.s3ObjectVersionID).To(Equal("version2"))
+		Expect(podEnvVar.sourceETag).To(Equal("\"v1\""))
+		Expect(podEnvVar.sourceVersionID).To(Equal("version1"))
+	})
+
 	It("Should NOT update phase on PVC, if pod exited with error state that is scratchspace exit", func() {
 		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning)}, nil, corev1.ClaimBound)
 		scratchPvcName := &corev1.PersistentVolumeClaim{}
@@ -634,67 +1372,562 @@ var _ = Describe("Update PVC from POD", func() {
 		err := reconciler.createImporterPod(pvc)
 		Expect(err).ToNot(HaveOccurred())
 	})
-})
-
-var _ = Describe("Create Importer Pod", func() {
-	var scratchPvcName = "scratchPvc"
 
-	table.DescribeTable("should", func(pvc *corev1.PersistentVolumeClaim, scratchPvcName *string) {
+	It("Should mark PVC as waiting for the FileSourceHostPath feature gate, if not enabled", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: "file:///fedora.qcow2", AnnImportPod: "testpod", AnnSource: SourceFile, AnnFileHostPath: "/mnt/usb", AnnFileNodeName: "node01"}, nil, corev1.ClaimPending)
+		reconciler = createImportReconciler(pvc)
+		err := reconciler.createImporterPod(pvc)
+		By("Checking importer pod creation returned an error")
+		Expect(err).To(HaveOccurred())
+		By("Checking pvc annotations have been updated")
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnBoundCondition]).To(Equal("false"))
+		Expect(resPvc.GetAnnotations()[AnnBoundConditionMessage]).To(Equal("waiting for the FileSourceHostPath feature gate to import from a host path"))
+		Expect(resPvc.GetAnnotations()[AnnBoundConditionReason]).To(Equal(fileSourceHostPathDisabled))
+
+		By("Checking again after enabling the feature gate")
+		reconciler.featureGates = &FakeFeatureGates{fileSourceHostPathEnabled: true}
+		err = reconciler.createImporterPod(pvc)
+		Expect(err).To(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnBoundConditionReason]).To(Equal(fileSourceHostPathNotAllowed))
+
+		By("Checking again after allowing the host path")
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.FileSourceHostPathPrefixes = []string{"/mnt"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		err = reconciler.createImporterPod(resPvc)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should not allow a host path out of the prefixes of the CDIConfig", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: "file:///fedora.qcow2", AnnImportPod: "testpod", AnnSource: SourceFile, AnnFileHostPath: "/mnt/usb/../../etc", AnnFileNodeName: "node01"}, nil, corev1.ClaimPending)
+		reconciler = createImportReconciler(pvc)
+		reconciler.featureGates = &FakeFeatureGates{fileSourceHostPathEnabled: true}
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.FileSourceHostPathPrefixes = []string{"/mnt/usb"}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+		err := reconciler.createImporterPod(pvc)
+		Expect(err).To(HaveOccurred())
+		pods := &corev1.PodList{}
+		Expect(reconciler.client.List(context.TODO(), pods)).To(Succeed())
+		Expect(pods.Items).To(BeEmpty())
+	})
+
+	It("Should not require the FileSourceHostPath feature gate to import from a PVC", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: "file:///fedora.qcow2", AnnImportPod: "testpod", AnnSource: SourceFile, AnnFileClaimName: "images"}, nil, corev1.ClaimBound)
+		reconciler = createImportReconciler(pvc)
+		err := reconciler.createImporterPod(pvc)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Create Importer Pod", func() {
+	var scratchPvcName = "scratchPvc"
+
+	table.DescribeTable("should", func(pvc *corev1.PersistentVolumeClaim, scratchPvcName *string) {
+		reconciler := createImportReconciler(pvc)
+		podEnvVar := &importPodEnvVar{
+			ep:                 "",
+			secretName:         "",
+			source:             "",
+			contentType:        "",
+			imageSize:          "1G",
+			certConfigMap:      "",
+			diskID:             "",
+			filesystemOverhead: "0.055",
+			insecureTLS:        false,
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, scratchPvcName, nil)
+		Expect(err).ToNot(HaveOccurred())
+		By("Verifying PVC owns pod")
+		Expect(len(pod.GetOwnerReferences())).To(Equal(1))
+		Expect(pod.GetOwnerReferences()[0].UID).To(Equal(pvc.GetUID()))
+		By("Verifying volume mode is correct")
+		if getVolumeMode(pvc) == corev1.PersistentVolumeBlock {
+			Expect(pod.Spec.Containers[0].VolumeDevices[0].Name).To(Equal(DataVolName))
+			Expect(pod.Spec.Containers[0].VolumeDevices[0].DevicePath).To(Equal(common.WriteBlockPath))
+			Expect(*pod.Spec.SecurityContext.RunAsUser).To(Equal(common.QemuSubUID))
+			Expect(*pod.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+			Expect(*pod.Spec.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(pod.Spec.TerminationGracePeriodSeconds).To(Equal(&[]int64{importerBlockTerminationGracePeriod}[0]))
+			if scratchPvcName != nil {
+				By("Verifying scratch space is set if available")
+				Expect(len(pod.Spec.Containers[0].VolumeMounts)).To(Equal(1))
+				Expect(pod.Spec.Containers[0].VolumeMounts[0].Name).To(Equal(ScratchVolName))
+				Expect(pod.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal(common.ScratchDataDir))
+			}
+		} else {
+			Expect(pod.Spec.Containers[0].VolumeMounts[0].Name).To(Equal(DataVolName))
+			Expect(pod.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal(common.ImporterDataDir))
+			if scratchPvcName != nil {
+				By("Verifying scratch space is set if available")
+				Expect(len(pod.Spec.Containers[0].VolumeMounts)).To(Equal(2))
+				Expect(pod.Spec.Containers[0].VolumeMounts[1].Name).To(Equal(ScratchVolName))
+				Expect(pod.Spec.Containers[0].VolumeMounts[1].MountPath).To(Equal(common.ScratchDataDir))
+			}
+		}
+		By("Verifying container spec is correct")
+		Expect(pod.Spec.Containers[0].Image).To(Equal(testImage))
+		Expect(pod.Spec.Containers[0].ImagePullPolicy).To(BeEquivalentTo(testPullPolicy))
+		Expect(pod.Spec.Containers[0].Args[0]).To(Equal("-v=5"))
+	},
+		table.Entry("should create pod with file system volume mode", createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), nil),
+		table.Entry("should create pod with block volume mode", createBlockPvc("testBlockPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), nil),
+		table.Entry("should create pod with file system volume mode and scratchspace", createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), &scratchPvcName),
+		table.Entry("should create pod with block volume mode and scratchspace", createBlockPvc("testBlockPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), &scratchPvcName),
+	)
+
+	It("should mount the client certificate secret", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar := &importPodEnvVar{
+			ep:                 testEndPoint,
+			source:             SourceHTTP,
+			contentType:        string(cdiv1.DataVolumeKubeVirt),
+			imageSize:          "1G",
+			filesystemOverhead: "0.055",
+			clientCertSecret:   "client-cert",
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      ClientCertVolName,
+			MountPath: common.ImporterClientCertDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: ClientCertVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "client-cert",
+				},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  common.ImporterClientCertDirVar,
+			Value: common.ImporterClientCertDir,
+		}))
+	})
+
+	table.DescribeTable("should mount the directory of the NFS image file read-only", func(endpoint, server, dir string) {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: endpoint, AnnSource: SourceNFS, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeFalse())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      NFSVolName,
+			MountPath: common.ImporterNFSDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: NFSVolName,
+			VolumeSource: corev1.VolumeSource{
+				NFS: &corev1.NFSVolumeSource{
+					Server:   server,
+					Path:     dir,
+					ReadOnly: true,
+				},
+			},
+		}))
+	},
+		table.Entry("with a host name", "nfs://nfs.example.com/export/templates/fedora.qcow2", "nfs.example.com", "/export/templates"),
+		table.Entry("with a file at the root of the export", "nfs://192.168.1.10/fedora.qcow2", "192.168.1.10", "/"),
+		table.Entry("with an IPv6 address", "nfs://[fd00::10]/export/Fedora%20Templates/fedora.qcow2", "fd00::10", "/export/Fedora Templates"),
+	)
+
+	It("should mount the host path of the file source read-only on its node", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "file:///images/fedora.qcow2", AnnSource: SourceFile, AnnFileHostPath: "/mnt/usb", AnnFileNodeName: "node01", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeFalse())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      FileVolName,
+			MountPath: common.ImporterFileDir,
+			ReadOnly:  true,
+		}))
+		hostPathType := corev1.HostPathDirectory
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: FileVolName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/mnt/usb",
+					Type: &hostPathType,
+				},
+			},
+		}))
+		Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelHostname, "node01"))
+	})
+
+	It("should mount the PVC of the file source read-only", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "file:///fedora.qcow2", AnnSource: SourceFile, AnnFileClaimName: "images", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: FileVolName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "images",
+					ReadOnly:  true,
+				},
+			},
+		}))
+		Expect(pod.Spec.NodeSelector).ToNot(HaveKey(corev1.LabelHostname))
+	})
+
+	It("should pass the S3 segmented download settings to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnS3Segments: "4", AnnS3SegmentSize: "64Mi", AnnHTTPSegments: "2"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.httpSegments).To(Equal("4"))
+		Expect(podEnvVar.httpSegmentSize).To(Equal("67108864"))
+	})
+
+	It("should pass the number of VDDK connections to the pod as its segments", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceVDDK, AnnVDDKConnections: "4", AnnHTTPSegments: "2"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.httpSegments).To(Equal("4"))
+	})
+
+	It("should run the pod with the service account of the DataVolume", func() {
+		dv := newS3ImportDataVolume("testPvc1")
+		dv.Spec.Source.S3.ServiceAccountName = "s3-reader"
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnImportServiceAccount: "s3-reader", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		reconciler := createImportReconciler(dv, pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.ServiceAccountName).To(Equal("s3-reader"))
+	})
+
+	It("should ignore the service account annotation of a PVC not owned by a DataVolume", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnImportServiceAccount: "s3-reader", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.ServiceAccountName).To(BeEmpty())
+	})
+
+	It("should take the service account from the spec of the DataVolume rather than the annotation", func() {
+		dv := newS3ImportDataVolume("testPvc1")
+		dv.Spec.Source.S3.ServiceAccountName = "s3-reader"
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceS3, AnnImportServiceAccount: "cluster-admin", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		reconciler := createImportReconciler(dv, pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.serviceAccount).To(Equal("s3-reader"))
+	})
+
+	It("should pass the region and checkpoints of the AWS snapshot to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "snap-01", AnnSource: SourceAWSSnapshot, AnnAWSSnapshotRegion: "us-east-1", AnnCurrentCheckpoint: "snap-02", AnnPreviousCheckpoint: "snap-01"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.s3Region).To(Equal("us-east-1"))
+		Expect(podEnvVar.currentCheckpoint).To(Equal("snap-02"))
+		Expect(podEnvVar.previousCheckpoint).To(Equal("snap-01"))
+	})
+
+	It("should pass the service account key secret of the GCE image to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "projects/project/global/images/fedora", AnnSource: SourceGCEImage, AnnGCEImageSecret: "gce-key"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceGCEImage))
+		Expect(podEnvVar.gcsSecretName).To(Equal("gce-key"))
+	})
+
+	It("should pass the project, domain and region of the Glance image to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "https://keystone.example.com:5000/v3", AnnSource: SourceGlance, AnnSecret: "openstack", AnnDiskID: "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f", AnnGlanceProject: "admin", AnnGlanceDomain: "Default", AnnGlanceRegion: "RegionOne"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.diskID).To(Equal("0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f"))
+		Expect(podEnvVar.glanceProject).To(Equal("admin"))
+		Expect(podEnvVar.glanceDomain).To(Equal("Default"))
+		Expect(podEnvVar.glanceRegion).To(Equal("RegionOne"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeTrue())
+	})
+
+	It("should pass the node and the VM ID of the Proxmox disk to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "https://pve.example.com:8006", AnnSource: SourceProxmox, AnnSecret: "proxmox", AnnDiskID: "scsi0", AnnProxmoxNode: "pve1", AnnProxmoxVMID: "100"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceProxmox))
+		Expect(podEnvVar.diskID).To(Equal("scsi0"))
+		Expect(podEnvVar.proxmoxNode).To(Equal("pve1"))
+		Expect(podEnvVar.proxmoxVMID).To(Equal("100"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeTrue())
+	})
+
+	It("should pass the domain of the Hyper-V share user to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "smb://fileserver/vms/fedora.vhdx", AnnSource: SourceHyperV, AnnSecret: "smb", AnnSMBDomain: "CORP"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceHyperV))
+		Expect(podEnvVar.ep).To(Equal("smb://fileserver/vms/fedora.vhdx"))
+		Expect(podEnvVar.smbDomain).To(Equal("CORP"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeTrue())
+	})
+
+	It("should pass the ftp passive mode to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "ftp://ftp.example.com/images/appliance.qcow2", AnnSource: SourceHTTP, AnnSecret: "ftp", AnnFTPPassive: "false"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.ftpPassive).To(Equal("false"))
+		Expect(makeImportEnv(podEnvVar, "1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterFTPPassive, Value: "false"}))
+	})
+
+	It("should pass the registries of the CDIConfig to registry imports only", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "docker://quay.io/kubevirt/fedora-cloud-container-disk-demo", AnnSource: SourceRegistry}, nil)
+		httpPvc := createPvc("testPvc2", "default", map[string]string{AnnEndpoint: "http://www.example.com/fedora.qcow2", AnnSource: SourceHTTP}, nil)
+		reconciler := createImportReconciler(pvc, httpPvc)
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.Registries = []cdiv1.RegistryConfig{{Registry: "quay.io", Mirrors: []cdiv1.RegistryMirror{{Location: "mirror.example.com:5000"}}}}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.registriesConfig).To(Equal(`[{"registry":"quay.io","mirrors":[{"location":"mirror.example.com:5000"}]}]`))
+		Expect(makeImportEnv(podEnvVar, "1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterRegistriesConfig, Value: podEnvVar.registriesConfig}))
+		podEnvVar, err = reconciler.createImportEnvVar(httpPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.registriesConfig).To(BeEmpty())
+	})
+
+	It("should pass the registry platform to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "docker://quay.io/kubevirt/fedora-cloud-container-disk-demo", AnnSource: SourceRegistry, AnnRegistryPlatform: "linux/arm64/v8"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.registryPlatform).To(Equal("linux/arm64/v8"))
+		Expect(makeImportEnv(podEnvVar, "1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterRegistryPlatform, Value: "linux/arm64/v8"}))
+	})
+
+	It("should pass the enabled feature gates to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnSource: SourceHTTP}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.featureGates).To(BeEmpty())
+		for _, envVar := range makeImportEnv(podEnvVar, "1111") {
+			Expect(envVar.Name).ToNot(Equal(common.ImporterFeatureGates))
+		}
+
+		cdiConfig := &cdiv1.CDIConfig{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)).To(Succeed())
+		cdiConfig.Spec.FeatureGates = []string{"Experimental", featuregates.FileSourceHostPath}
+		Expect(reconciler.client.Update(context.TODO(), cdiConfig)).To(Succeed())
+
+		podEnvVar, err = reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(makeImportEnv(podEnvVar, "1111")).To(ContainElement(corev1.EnvVar{Name: common.ImporterFeatureGates, Value: "Experimental,FileSourceHostPath"}))
+	})
+
+	It("should mount the signature policy and pass the keyless identity to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "docker://registry.example.com/images/fedora", AnnSource: SourceRegistry, AnnImportPod: "podName",
+			AnnRegistrySignatureConfigMap: "sigstore-roots", AnnRegistrySignatureIdentity: "builder@example.com", AnnRegistrySignatureIssuer: "https://accounts.example.com"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.signatureConfigMap).To(Equal("sigstore-roots"))
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SignatureVolName,
+			MountPath: common.ImporterSignatureDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: SignatureVolName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "sigstore-roots",
+					},
+				},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSignatureDirVar, Value: common.ImporterSignatureDir}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSignatureIdentity, Value: "builder@example.com"}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSignatureIssuer, Value: "https://accounts.example.com"}))
+	})
+
+	It("should pass the rsync module and host key to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "ssh://backup.example.com:2222/templates/fedora.qcow2", AnnSource: SourceRsync, AnnSecret: "rsync", AnnRsyncModule: "images", AnnRsyncHostKey: "ssh-ed25519 AAAA"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceRsync))
+		Expect(podEnvVar.ep).To(Equal("ssh://backup.example.com:2222/templates/fedora.qcow2"))
+		Expect(podEnvVar.rsyncModule).To(Equal("images"))
+		Expect(podEnvVar.rsyncHostKey).To(Equal("ssh-ed25519 AAAA"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeTrue())
+	})
+
+	It("should pass the iSCSI initiator name to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "iscsi://san.example.com/iqn.2003-01.org.linux-iscsi.san:p2v/0", AnnSource: SourceISCSI, AnnSecret: "chap", AnnISCSIInitiator: "iqn.2021-01.io.kubevirt:importer"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceISCSI))
+		Expect(podEnvVar.ep).To(Equal("iscsi://san.example.com/iqn.2003-01.org.linux-iscsi.san:p2v/0"))
+		Expect(podEnvVar.iscsiInitiator).To(Equal("iqn.2021-01.io.kubevirt:importer"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeFalse())
+	})
+
+	It("should pass the Ceph monitors to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "rbd:///vms/disk-1", AnnSource: SourceRBD, AnnSecret: "ceph", AnnRBDMonitors: "10.0.0.1:6789,10.0.0.2"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceRBD))
+		Expect(podEnvVar.ep).To(Equal("rbd:///vms/disk-1"))
+		Expect(podEnvVar.rbdMonitors).To(Equal("10.0.0.1:6789,10.0.0.2"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeFalse())
+	})
+
+	It("should pass the domain of the SMB share user to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "smb://fileserver/images/fedora.qcow2", AnnSource: SourceSMB, AnnSecret: "smb", AnnSMBDomain: "CORP"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.source).To(Equal(SourceSMB))
+		Expect(podEnvVar.ep).To(Equal("smb://fileserver/images/fedora.qcow2"))
+		Expect(podEnvVar.smbDomain).To(Equal("CORP"))
+		Expect(reconciler.requiresScratchSpace(pvc)).To(BeFalse())
+	})
+
+	It("should pass the GCS service account key secret to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "gs://bucket/disk.img", AnnSource: SourceGCS, AnnGCSSecret: "gcs-key", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.gcsSecretName).To(Equal("gcs-key"))
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterGCSServiceAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "gcs-key"},
+					Key:                  common.KeyServiceAccountKey,
+				},
+			},
+		}))
+	})
+
+	It("should label the pod for Azure AD workload identity", func() {
+		dv := newImportDataVolume("testPvc1")
+		dv.Spec.Source = cdiv1.DataVolumeSource{AzureBlob: &cdiv1.DataVolumeSourceAzureBlob{URL: "https://account.blob.core.windows.net/images/disk.img", ServiceAccountName: "blob-reader"}}
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "https://account.blob.core.windows.net/images/disk.img", AnnSource: SourceAzureBlob, AnnImportServiceAccount: "blob-reader", AnnAzureBlobSegments: "4", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+		reconciler := createImportReconciler(dv, pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.httpSegments).To(Equal("4"))
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.ServiceAccountName).To(Equal("blob-reader"))
+		Expect(pod.Labels[azureWorkloadIdentityLabel]).To(Equal("true"))
+	})
+
+	It("should pass the optional Azure SAS token and account key to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "https://account.blob.core.windows.net/images/disk.img", AnnSource: SourceAzureBlob, AnnAzureBlobSecret: "azure-key", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Labels).ToNot(HaveKey(azureWorkloadIdentityLabel))
+		optional := true
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterAzureAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "azure-key"},
+					Key:                  common.KeyAccountKey,
+					Optional:             &optional,
+				},
+			},
+		}))
+	})
+
+	It("should pass the Azure service principal to the pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/snapshots/fedora", AnnSource: SourceAzureDisk, AnnAzureDiskSecret: "azure-sp", AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
 		reconciler := createImportReconciler(pvc)
-		podEnvVar := &importPodEnvVar{
-			ep:                 "",
-			secretName:         "",
-			source:             "",
-			contentType:        "",
-			imageSize:          "1G",
-			certConfigMap:      "",
-			diskID:             "",
-			filesystemOverhead: "0.055",
-			insecureTLS:        false,
-		}
-		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, scratchPvcName, nil)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
 		Expect(err).ToNot(HaveOccurred())
-		By("Verifying PVC owns pod")
-		Expect(len(pod.GetOwnerReferences())).To(Equal(1))
-		Expect(pod.GetOwnerReferences()[0].UID).To(Equal(pvc.GetUID()))
-		By("Verifying volume mode is correct")
-		if getVolumeMode(pvc) == corev1.PersistentVolumeBlock {
-			Expect(pod.Spec.Containers[0].VolumeDevices[0].Name).To(Equal(DataVolName))
-			Expect(pod.Spec.Containers[0].VolumeDevices[0].DevicePath).To(Equal(common.WriteBlockPath))
-			Expect(pod.Spec.SecurityContext.RunAsUser).To(Equal(&[]int64{0}[0]))
-			if scratchPvcName != nil {
-				By("Verifying scratch space is set if available")
-				Expect(len(pod.Spec.Containers[0].VolumeMounts)).To(Equal(1))
-				Expect(pod.Spec.Containers[0].VolumeMounts[0].Name).To(Equal(ScratchVolName))
-				Expect(pod.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal(common.ScratchDataDir))
-			}
-		} else {
-			Expect(pod.Spec.Containers[0].VolumeMounts[0].Name).To(Equal(DataVolName))
-			Expect(pod.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal(common.ImporterDataDir))
-			if scratchPvcName != nil {
-				By("Verifying scratch space is set if available")
-				Expect(len(pod.Spec.Containers[0].VolumeMounts)).To(Equal(2))
-				Expect(pod.Spec.Containers[0].VolumeMounts[1].Name).To(Equal(ScratchVolName))
-				Expect(pod.Spec.Containers[0].VolumeMounts[1].MountPath).To(Equal(common.ScratchDataDir))
-			}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: common.ImporterAzureClientSecret,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "azure-sp"},
+					Key:                  common.KeyClientSecret,
+				},
+			},
+		}))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal(common.ImporterAzureAccountKey))
 		}
-		By("Verifying container spec is correct")
-		Expect(pod.Spec.Containers[0].Image).To(Equal(testImage))
-		Expect(pod.Spec.Containers[0].ImagePullPolicy).To(BeEquivalentTo(testPullPolicy))
-		Expect(pod.Spec.Containers[0].Args[0]).To(Equal("-v=5"))
-	},
-		table.Entry("should create pod with file system volume mode", createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), nil),
-		table.Entry("should create pod with block volume mode", createBlockPvc("testBlockPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), nil),
-		table.Entry("should create pod with file system volume mode and scratchspace", createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), &scratchPvcName),
-		table.Entry("should create pod with block volume mode and scratchspace", createBlockPvc("testBlockPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil), &scratchPvcName),
-	)
+	})
+
+	It("should mount the trusted CA bundle", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc, createTrustedCAConfigMap(util.GetNamespace(), "bundle", nil))
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.trustedCAConfigMap).To(Equal(common.TrustedCAConfigMap))
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      TrustedCAVolName,
+			MountPath: common.ImporterTrustedCADir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		}))
+		cm := &corev1.ConfigMap{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+	})
 })
 
 var _ = Describe("Import test env", func() {
 	const mockUID = "1111-1111-1111-1111"
 
 	It("Should create import env", func() {
-		testEnvVar := &importPodEnvVar{"myendpoint", "mysecret", SourceHTTP, string(cdiv1.DataVolumeKubeVirt), "1G", "", "", "", "", "", "0.055", false, "", "", "", false}
+		testEnvVar := &importPodEnvVar{"myendpoint", "mysecret", SourceHTTP, string(cdiv1.DataVolumeKubeVirt), "1G", "", "", "", "", "", "0.055", false, "", "", "", false, "4", "1048576", "\"etag\"", "Wed, 01 Jan 2020 00:00:00 GMT", "mytoken", "https://sso.example.com/token", "myoauth2secret", "read write", "myclientcert", &cdiv1.TLSConfig{MinVersion: cdiv1.VersionTLS12, Ciphers: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}}, common.TrustedCAConfigMap, "s3-reader", "eu-west-1", "false", "mysseckey", "3HL4kqtJlcpXroDTDmJ", "3HL4kqtJlcpXroDTDmI", "gcs-key", "azure-key", "admin", "Default", "RegionOne", "pve1", "100", "CORP", "false", "images", "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl", "iqn.2021-01.io.kubevirt:importer", "10.0.0.1:6789,10.0.0.2", "/mnt/usb", "node01", "images", "vm01", "vda", "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl", `[{"registry":"quay.io","mirrors":[{"location":"mirror.example.com:5000"}]}]`, "linux/arm64", "cosign-key", "builder@example.com", "https://accounts.example.com", featuregates.FileSourceHostPath}
 		Expect(reflect.DeepEqual(makeImportEnv(testEnvVar, mockUID), createImportTestEnv(testEnvVar, mockUID))).To(BeTrue())
 	})
 })
@@ -807,6 +2040,32 @@ var _ = Describe("getContentType", func() {
 	)
 })
 
+var _ = Describe("Import source capabilities", func() {
+	table.DescribeTable("should support multi-stage imports", func(source cdiv1.DataVolumeSource, sourceType string, multiStage bool) {
+		Expect(GetImportSourceType(&source)).To(Equal(sourceType))
+		Expect(IsMultiStageImportSource(&source)).To(Equal(multiStage))
+	},
+		table.Entry("for VDDK", cdiv1.DataVolumeSource{VDDK: &cdiv1.DataVolumeSourceVDDK{}}, SourceVDDK, true),
+		table.Entry("for AWS snapshots", cdiv1.DataVolumeSource{AWSSnapshot: &cdiv1.DataVolumeSourceAWSSnapshot{}}, SourceAWSSnapshot, true),
+		table.Entry("for imageio", cdiv1.DataVolumeSource{Imageio: &cdiv1.DataVolumeSourceImageIO{}}, SourceImageio, true),
+		table.Entry("not for http", cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{}}, SourceHTTP, false),
+		table.Entry("not for clones", cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{}}, "", false),
+	)
+
+	table.DescribeTable("should only support the kubevirt content type", func(sourceType string, kubeVirtOnly bool) {
+		Expect(IsKubeVirtOnlySource(sourceType)).To(Equal(kubeVirtOnly))
+	},
+		table.Entry("for registry", SourceRegistry, true),
+		table.Entry("for imageio", SourceImageio, true),
+		table.Entry("for NFS", SourceNFS, true),
+		table.Entry("for Azure disks", SourceAzureDisk, true),
+		table.Entry("not for http", SourceHTTP, false),
+		table.Entry("not for S3", SourceS3, false),
+		table.Entry("not for blank", SourceNone, false),
+		table.Entry("not for VDDK", SourceVDDK, false),
+	)
+})
+
 var _ = Describe("getSource", func() {
 	pvcNoAnno := createPvc("testPVCNoAnno", "default", nil, nil)
 	pvcNoneAnno := createPvc("testPVCNoneAnno", "default", map[string]string{AnnSource: SourceNone}, nil)
@@ -850,6 +2109,27 @@ var _ = Describe("getEndpoint", func() {
 	)
 })
 
+var _ = Describe("importBackoff", func() {
+	table.DescribeTable("should double the backoff up to its maximum", func(policy *cdiv1.ImportRetryPolicy, attempts int, expected time.Duration) {
+		Expect(importBackoff(policy, attempts)).To(Equal(expected))
+	},
+		table.Entry("with the default initial backoff", &cdiv1.ImportRetryPolicy{}, 1, 10*time.Second),
+		table.Entry("with the doubled default backoff", &cdiv1.ImportRetryPolicy{}, 3, 40*time.Second),
+		table.Entry("with the default maximum backoff", &cdiv1.ImportRetryPolicy{}, 10, 5*time.Minute),
+		table.Entry("with the initial backoff of the policy", &cdiv1.ImportRetryPolicy{InitialBackoff: &metav1.Duration{Duration: time.Second}}, 2, 2*time.Second),
+		table.Entry("with the maximum backoff of the policy", &cdiv1.ImportRetryPolicy{MaxBackoff: &metav1.Duration{Duration: 15 * time.Second}}, 2, 15*time.Second),
+	)
+})
+
+func setMaxParallelImports(r *ImportReconciler, maxParallelImports int32) {
+	config := &cdiv1.CDIConfig{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config)
+	Expect(err).ToNot(HaveOccurred())
+	config.Spec.MaxParallelImports = &maxParallelImports
+	err = r.client.Update(context.TODO(), config)
+	Expect(err).ToNot(HaveOccurred())
+}
+
 func createImportReconciler(objects ...runtime.Object) *ImportReconciler {
 	objs := []runtime.Object{}
 	objs = append(objs, objects...)
@@ -952,6 +2232,22 @@ func createImportTestEnv(podEnvVar *importPodEnvVar, uid string) []corev1.EnvVar
 			Name:  common.Preallocation,
 			Value: strconv.FormatBool(podEnvVar.preallocation),
 		},
+		{
+			Name:  common.ImporterHTTPSegments,
+			Value: podEnvVar.httpSegments,
+		},
+		{
+			Name:  common.ImporterHTTPSegmentSize,
+			Value: podEnvVar.httpSegmentSize,
+		},
+		{
+			Name:  common.ImporterSourceETag,
+			Value: podEnvVar.sourceETag,
+		},
+		{
+			Name:  common.ImporterSourceLastModified,
+			Value: podEnvVar.sourceLastModified,
+		},
 	}
 
 	if podEnvVar.secretName != "" {
@@ -977,6 +2273,297 @@ func createImportTestEnv(podEnvVar *importPodEnvVar, uid string) []corev1.EnvVar
 			},
 		})
 	}
+	if podEnvVar.tokenSecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterBearerToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.tokenSecretName,
+					},
+					Key: common.KeyToken,
+				},
+			},
+		})
+	}
+	if podEnvVar.oauth2TokenURL != "" && podEnvVar.oauth2SecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterOAuth2TokenURL,
+			Value: podEnvVar.oauth2TokenURL,
+		}, corev1.EnvVar{
+			Name:  common.ImporterOAuth2Scopes,
+			Value: podEnvVar.oauth2Scopes,
+		}, corev1.EnvVar{
+			Name: common.ImporterOAuth2ClientID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.oauth2SecretName,
+					},
+					Key: common.KeyClientID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterOAuth2ClientSecret,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.oauth2SecretName,
+					},
+					Key: common.KeyClientSecret,
+				},
+			},
+		})
+	}
+	if podEnvVar.s3Region != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3Region,
+			Value: podEnvVar.s3Region,
+		})
+	}
+	if podEnvVar.s3ForcePathStyle != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3ForcePathStyle,
+			Value: podEnvVar.s3ForcePathStyle,
+		})
+	}
+	if podEnvVar.s3ObjectVersionID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterS3ObjectVersionID,
+			Value: podEnvVar.s3ObjectVersionID,
+		})
+	}
+	if podEnvVar.sourceVersionID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSourceVersionID,
+			Value: podEnvVar.sourceVersionID,
+		})
+	}
+	if podEnvVar.s3SSECustomerKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterS3SSECustomerKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.s3SSECustomerKey,
+					},
+					Key: common.KeySSECustomerKey,
+				},
+			},
+		})
+	}
+	if podEnvVar.glanceProject != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceProject,
+			Value: podEnvVar.glanceProject,
+		})
+	}
+	if podEnvVar.glanceDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceDomain,
+			Value: podEnvVar.glanceDomain,
+		})
+	}
+	if podEnvVar.glanceRegion != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterGlanceRegion,
+			Value: podEnvVar.glanceRegion,
+		})
+	}
+	if podEnvVar.proxmoxNode != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterProxmoxNode,
+			Value: podEnvVar.proxmoxNode,
+		})
+	}
+	if podEnvVar.proxmoxVMID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterProxmoxVMID,
+			Value: podEnvVar.proxmoxVMID,
+		})
+	}
+	if podEnvVar.smbDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSMBDomain,
+			Value: podEnvVar.smbDomain,
+		})
+	}
+	if podEnvVar.rsyncModule != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRsyncModule,
+			Value: podEnvVar.rsyncModule,
+		})
+	}
+	if podEnvVar.rsyncHostKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRsyncHostKey,
+			Value: podEnvVar.rsyncHostKey,
+		})
+	}
+	if podEnvVar.iscsiInitiator != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterISCSIInitiator,
+			Value: podEnvVar.iscsiInitiator,
+		})
+	}
+	if podEnvVar.rbdMonitors != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRBDMonitors,
+			Value: podEnvVar.rbdMonitors,
+		})
+	}
+	if podEnvVar.libvirtDomain != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtDomain,
+			Value: podEnvVar.libvirtDomain,
+		})
+	}
+	if podEnvVar.libvirtDisk != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtDisk,
+			Value: podEnvVar.libvirtDisk,
+		})
+	}
+	if podEnvVar.libvirtHostKey != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterLibvirtHostKey,
+			Value: podEnvVar.libvirtHostKey,
+		})
+	}
+	if podEnvVar.registriesConfig != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistriesConfig,
+			Value: podEnvVar.registriesConfig,
+		})
+	}
+	if podEnvVar.registryPlatform != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistryPlatform,
+			Value: podEnvVar.registryPlatform,
+		})
+	}
+	if podEnvVar.featureGates != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterFeatureGates,
+			Value: podEnvVar.featureGates,
+		})
+	}
+	if podEnvVar.signatureConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureDirVar,
+			Value: common.ImporterSignatureDir,
+		})
+	}
+	if podEnvVar.signatureIdentity != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureIdentity,
+			Value: podEnvVar.signatureIdentity,
+		})
+	}
+	if podEnvVar.signatureIssuer != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignatureIssuer,
+			Value: podEnvVar.signatureIssuer,
+		})
+	}
+	if podEnvVar.ftpPassive != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterFTPPassive,
+			Value: podEnvVar.ftpPassive,
+		})
+	}
+	if podEnvVar.gcsSecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterGCSServiceAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.gcsSecretName,
+					},
+					Key: common.KeyServiceAccountKey,
+				},
+			},
+		})
+	}
+	if podEnvVar.azureSecretName != "" && podEnvVar.source == SourceAzureDisk {
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterAzureTenantID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyTenantID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureClientID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyClientID,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureClientSecret,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key: common.KeyClientSecret,
+				},
+			},
+		})
+	} else if podEnvVar.azureSecretName != "" {
+		optional := true
+		env = append(env, corev1.EnvVar{
+			Name: common.ImporterAzureSASToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key:      common.KeySASToken,
+					Optional: &optional,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterAzureAccountKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podEnvVar.azureSecretName,
+					},
+					Key:      common.KeyAccountKey,
+					Optional: &optional,
+				},
+			},
+		})
+	}
+	if podEnvVar.clientCertSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterClientCertDirVar,
+			Value: common.ImporterClientCertDir,
+		})
+	}
+	if podEnvVar.trustedCAConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		})
+	}
+	if podEnvVar.tlsConfig != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.TLSMinVersionVar,
+			Value: string(podEnvVar.tlsConfig.MinVersion),
+		}, corev1.EnvVar{
+			Name:  common.TLSCiphersVar,
+			Value: strings.Join(podEnvVar.tlsConfig.Ciphers, ","),
+		})
+	}
 	return env
 }
 
@@ -1096,7 +2683,7 @@ func createImporterTestPod(pvc *corev1.PersistentVolumeClaim, dvname string, scr
 	if volumeMode == corev1.PersistentVolumeBlock {
 		pod.Spec.Containers[0].VolumeDevices = addVolumeDevices()
 		pod.Spec.SecurityContext = &corev1.PodSecurityContext{
-			RunAsUser: &[]int64{0}[0],
+			RunAsUser: &[]int64{common.QemuSubUID}[0],
 		}
 	} else {
 		pod.Spec.Containers[0].VolumeMounts = addImportVolumeMounts()
@@ -1114,8 +2701,34 @@ func createImporterTestPod(pvc *corev1.PersistentVolumeClaim, dvname string, scr
 
 type FakeFeatureGates struct {
 	honorWaitForFirstConsumerEnabled bool
+	fileSourceHostPathEnabled        bool
 }
 
 func (f *FakeFeatureGates) HonorWaitForFirstConsumerEnabled() (bool, error) {
 	return f.honorWaitForFirstConsumerEnabled, nil
 }
+
+func (f *FakeFeatureGates) FileSourceHostPathEnabled() (bool, error) {
+	return f.fileSourceHostPathEnabled, nil
+}
+
+func (f *FakeFeatureGates) Enabled(featureGate string) (bool, error) {
+	switch featureGate {
+	case featuregates.HonorWaitForFirstConsumer:
+		return f.honorWaitForFirstConsumerEnabled, nil
+	case featuregates.FileSourceHostPath:
+		return f.fileSourceHostPathEnabled, nil
+	}
+	return false, nil
+}
+
+func (f *FakeFeatureGates) EnabledFeatureGates() ([]string, error) {
+	var featureGates []string
+	if f.fileSourceHostPathEnabled {
+		featureGates = append(featureGates, featuregates.FileSourceHostPath)
+	}
+	if f.honorWaitForFirstConsumerEnabled {
+		featureGates = append(featureGates, featuregates.HonorWaitForFirstConsumer)
+	}
+	return featureGates, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// PhaseDurationsMessagePrefix is the prefix of the line in the importer's exit message containing the seconds spent in each phase
+	PhaseDurationsMessagePrefix = "Phase-Durations: "
+
+	// importFailureOther is the reason of the failures of the importer not matching any known reason
+	importFailureOther = "other"
+	// importFailureTimeout is the reason of the imports cancelled at their timeout
+	importFailureTimeout = "timeout"
+)
+
+// importFailureReasons classifies the exit messages of the failed importers, the first reason with a fragment found
+// in the lowercased message is reported
+var importFailureReasons = []struct {
+	reason    string
+	fragments []string
+}{
+	{"cert_invalid", []string{"x509:", "certificate"}},
+	{"auth", []string{"got 401", "got 403", "unauthorized", "forbidden", "access denied", "accessdenied", "authentication", "invalidaccesskeyid", "signaturedoesnotmatch", "incorrect user name or password", "oauth2"}},
+	{"404", []string{"got 404", "not found", "nosuchkey", "nosuchbucket", "manifest unknown", "no such file or directory"}},
+	{"disk_full", []string{"no space left on device", "disk quota exceeded", "a larger pvc is required"}},
+	{"convert_error", []string{"could not convert", "could not stream/convert", "invalid format", "has backing file", "invalid json for image"}},
+}
+
+// importFailures counts the failed importers by reason, served on the metrics endpoint of the controller
+var importFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cdi_import_failures_total",
+		Help: "Number of failed importers and timed out imports, by reason and source",
+	},
+	[]string{"reason", "source"},
+)
+
+// importPhaseDuration is the time the importers spent in each processing phase, served on the metrics endpoint of the controller
+var importPhaseDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "cdi_import_phase_duration_seconds",
+		Help: "Time spent by the successful imports in each processing phase",
+		// From one second to about nine hours
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	},
+	[]string{"phase", "source", "storage_class"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(importPhaseDuration, importFailures)
+}
+
+// importFailureReason returns the reason of the failure reported in the importer's exit message.
+func importFailureReason(message string) string {
+	message = strings.ToLower(message)
+	for _, r := range importFailureReasons {
+		for _, fragment := range r.fragments {
+			if strings.Contains(message, fragment) {
+				return r.reason
+			}
+		}
+	}
+	return importFailureOther
+}
+
+// countImportFailure counts a failure of the import for the given reason.
+func countImportFailure(pvc *corev1.PersistentVolumeClaim, reason string) {
+	importFailures.WithLabelValues(reason, getSource(pvc)).Inc()
+}
+
+// observePhaseDurationsFromMessage records the phase durations reported in the importer's exit message.
+func observePhaseDurationsFromMessage(pvc *corev1.PersistentVolumeClaim, message string) {
+	storageClass := ""
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+	source := getSource(pvc)
+
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, PhaseDurationsMessagePrefix) {
+			continue
+		}
+		for _, pair := range strings.Split(strings.TrimPrefix(line, PhaseDurationsMessagePrefix), ",") {
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			seconds, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil || seconds < 0 {
+				continue
+			}
+			importPhaseDuration.WithLabelValues(parts[0], source, storageClass).Observe(seconds)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// AnnImportPaused is a DataVolume or PVC annotation pausing the import while set to "true"
+	AnnImportPaused = AnnAPIGroup + "/storage.import.paused"
+
+	// ImportPausedByUser is reason for the running condition of an import paused with the AnnImportPaused annotation
+	ImportPausedByUser = "ImportPausedByUser"
+	// MessageImportPausedByUser is the message of the running condition of a paused import
+	MessageImportPausedByUser = "Import paused, remove the " + AnnImportPaused + " annotation to resume it"
+)
+
+// isImportPaused tells whether the import to the PVC is paused
+func isImportPaused(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.GetAnnotations()[AnnImportPaused] == "true"
+}
+
+// importPauseRequested tells whether the import is paused and still has to be stopped. An importer pod that already
+// succeeded completes the import instead.
+func importPauseRequested(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) bool {
+	if !isImportPaused(pvc) || isPVCComplete(pvc) || pvc.DeletionTimestamp != nil {
+		return false
+	}
+	return pod == nil || pod.Status.Phase != corev1.PodSucceeded
+}
+
+// pauseImport stops the importer pod of a paused import. The scratch space is handed over to the PVC so it outlives
+// the pod, the importer pod created on resume takes it back.
+func (r *ImportReconciler) pauseImport(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, log logr.Logger) error {
+	if pod != nil {
+		log.V(1).Info("Import paused, deleting POD", "pod.Name", pod.Name)
+		if err := r.keepScratchPvc(pvc, pod); err != nil {
+			return err
+		}
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	pvcCopy := pvc.DeepCopy()
+	anno := pvcCopy.GetAnnotations()
+	delete(anno, AnnImportQueued)
+	if _, ok := anno[AnnPodPhase]; ok {
+		anno[AnnPodPhase] = string(corev1.PodPending)
+	}
+	anno[AnnRunningCondition] = "false"
+	anno[AnnRunningConditionMessage] = MessageImportPausedByUser
+	anno[AnnRunningConditionReason] = ImportPausedByUser
+	if !reflect.DeepEqual(pvc, pvcCopy) {
+		return r.updatePVC(pvcCopy, log)
+	}
+	return nil
+}
+
+// keepScratchPvc makes the PVC the owner of the scratch space of the importer pod
+func (r *ImportReconciler) keepScratchPvc(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) error {
+	scratchPVCName, exists := getScratchNameFromPod(pod)
+	if !exists {
+		return nil
+	}
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: scratchPVCName}, scratchPvc)
+	if err != nil {
+		return IgnoreNotFound(err)
+	}
+	if metav1.IsControlledBy(scratchPvc, pvc) {
+		return nil
+	}
+	scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePVCOwnerReference(pvc)}
+	return r.client.Update(context.TODO(), scratchPvc)
+}
+
+// deleteKeptScratchPvc deletes the scratch space the PVC kept for the next importer pod, once there is none
+func (r *ImportReconciler) deleteKeptScratchPvc(pvc *corev1.PersistentVolumeClaim) error {
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: createScratchNameFromPvc(pvc)}, scratchPvc)
+	if err != nil {
+		return IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(scratchPvc, pvc) || scratchPvc.DeletionTimestamp != nil {
+		return nil
+	}
+	return IgnoreNotFound(r.client.Delete(context.TODO(), scratchPvc))
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// AnnImportPreflight is a DataVolume annotation checking the import source before provisioning the PVC when set to "true"
+	AnnImportPreflight = AnnAPIGroup + "/storage.import.preflight"
+
+	// ImportPreflightFailed provides a const to indicate the import source failed the preflight check
+	ImportPreflightFailed = "ImportPreflightFailed"
+	// MessageImportPreflightFailed provides a const to form the preflight failure message
+	MessageImportPreflightFailed = "The import source failed the preflight check: %v"
+
+	// importPreflightRetryInterval is how long to wait before checking a source that failed the preflight check again
+	importPreflightRetryInterval = time.Minute
+)
+
+// importPreflightRequired returns true if the DataVolume asks for its http source to be checked before the PVC is
+// provisioned. The probe pod checks the source like the importer reads it: the host must resolve, the certificate chain
+// must validate against the certConfigMap and the server must accept the credentials of the secretRef.
+func importPreflightRequired(dataVolume *cdiv1.DataVolume) bool {
+	return dataVolume.Spec.Source.HTTP != nil && dataVolume.GetAnnotations()[AnnImportPreflight] == "true"
+}
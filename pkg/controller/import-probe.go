@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// importProbePodPrefix is the prefix of the names of the probe pods, the importer pods are named after the PVCs
+	importProbePodPrefix = "cdi-probe"
+)
+
+// importProbeDeadlineSeconds is how long the probe pod may run, it only reads the header of most sources
+var importProbeDeadlineSeconds = int64(600)
+
+// importProbeResult is the information of the import source reported by the probe pod
+type importProbeResult struct {
+	// virtualSize is the virtual size of the disk image of the source, 0 if unknown
+	virtualSize int64
+	// err is the reason the probe of the source failed, nil if it succeeded
+	err error
+}
+
+// importProbeRequired returns true if the source of the DataVolume has to be probed before the PVC is provisioned
+func importProbeRequired(dataVolume *cdiv1.DataVolume) bool {
+	return importPreflightRequired(dataVolume) || importSizeDetectionRequired(dataVolume)
+}
+
+// importProbePodName returns the name of the probe pod of the DataVolume
+func importProbePodName(dataVolume *cdiv1.DataVolume) string {
+	return naming.GetResourceName(importProbePodPrefix, dataVolume.Name)
+}
+
+// probeImportSource returns the result of the probe pod of the DataVolume, nil while the pod runs. The pod is created
+// on the first call and deleted once its result is read, so the next call probes the source again. The probe pod runs
+// the importer in the namespace of the DataVolume, with the same credentials, certificates and network as the import,
+// and only reads the information of the source.
+func (r *DatavolumeReconciler) probeImportSource(dataVolume *cdiv1.DataVolume) (*importProbeResult, error) {
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataVolume.Namespace, Name: importProbePodName(dataVolume)}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, r.createImportProbePod(dataVolume)
+	}
+	if !metav1.IsControlledBy(pod, dataVolume) {
+		return nil, errors.Errorf("pod %s/%s is not the probe of the DataVolume", pod.Namespace, pod.Name)
+	}
+	result := importProbePodResult(pod)
+	if result == nil {
+		return nil, nil
+	}
+	if err := r.client.Delete(context.TODO(), pod); err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// createImportProbePod creates the probe pod of the DataVolume, the importer pod of its PVC without the PVC
+func (r *DatavolumeReconciler) createImportProbePod(dataVolume *cdiv1.DataVolume) error {
+	pod, err := newSourceReaderPod(r.client, r.uncachedClient, r.log, r.featureGates, dataVolume, r.importerImage, r.verbose, r.pullPolicy)
+	if err != nil {
+		return err
+	}
+	pod.Name = importProbePodName(dataVolume)
+	pod.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(dataVolume, cdiv1.SchemeGroupVersion.WithKind("DataVolume")),
+	}
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: common.ImporterProbe, Value: "true"})
+	if err := r.client.Create(context.TODO(), pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	r.log.V(1).Info("Created the probe pod of the import source", "pod.Namespace", pod.Namespace, "pod.Name", pod.Name)
+	return nil
+}
+
+// newSourceReaderPod returns the importer pod of the PVC of the DataVolume without the volume of the PVC, for the pods
+// which only read the information of the source, with the credentials, certificates and network of the import
+func newSourceReaderPod(c, uncachedClient client.Client, log logr.Logger, featureGates featuregates.FeatureGates, dataVolume *cdiv1.DataVolume, image, verbose, pullPolicy string) (*corev1.Pod, error) {
+	pvc, err := newPersistentVolumeClaim(c, dataVolume)
+	if err != nil {
+		return nil, err
+	}
+	// The pod populates no PVC, the source validators of the DataVolume are passed on for conditional requests
+	for _, ann := range []string{AnnSourceETag, AnnSourceLastModified, AnnSourceVersionID} {
+		if value, ok := dataVolume.Annotations[ann]; ok {
+			pvc.Annotations[ann] = value
+		}
+	}
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		// The pod writes nothing, the requested size is only passed to the importer
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("0")
+	}
+	importReconciler := &ImportReconciler{
+		client:         c,
+		uncachedClient: uncachedClient,
+		log:            log,
+		featureGates:   featureGates,
+	}
+	podEnvVar, err := importReconciler.createImportEnvVar(pvc)
+	if err != nil {
+		return nil, err
+	}
+	podResourceRequirements, err := GetPodResourceRequirements(c, pvc)
+	if err != nil {
+		return nil, err
+	}
+	workloadNodePlacement, err := GetPodNodePlacement(c, pvc)
+	if err != nil {
+		return nil, err
+	}
+	priorityClassName, err := GetPriorityClassName(c, pvc)
+	if err != nil {
+		return nil, err
+	}
+	podSecurity, err := GetPodSecurityConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	imagePullSecrets, err := GetImagePullSecrets(c)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := makeImporterPodSpec(dataVolume.Namespace, image, verbose, pullPolicy, podEnvVar, pvc, nil, podResourceRequirements, workloadNodePlacement, priorityClassName, corev1.RestartPolicyNever, nil)
+	removeImportTarget(pod)
+	SetRestrictedSecurityContext(pod, podSecurity)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
+	return pod, nil
+}
+
+// removeImportTarget removes the volume of the PVC from an importer pod which only reads the information of the
+// source, and bounds how long the pod may run
+func removeImportTarget(pod *corev1.Pod) {
+	// The pod does not report the progress of an import
+	delete(pod.Labels, common.PrometheusLabel)
+
+	volumes := []corev1.Volume{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name != DataVolName {
+			volumes = append(volumes, volume)
+		}
+	}
+	pod.Spec.Volumes = volumes
+	container := &pod.Spec.Containers[0]
+	mounts := []corev1.VolumeMount{}
+	for _, mount := range container.VolumeMounts {
+		if mount.Name != DataVolName {
+			mounts = append(mounts, mount)
+		}
+	}
+	container.VolumeMounts = mounts
+	container.VolumeDevices = nil
+	container.Ports = nil
+	pod.Spec.TerminationGracePeriodSeconds = nil
+	pod.Spec.ActiveDeadlineSeconds = &importProbeDeadlineSeconds
+}
+
+// importProbePodResult returns the result reported by the probe pod, nil while the pod runs
+func importProbePodResult(pod *corev1.Pod) *importProbeResult {
+	var terminated *corev1.ContainerStateTerminated
+	if len(pod.Status.ContainerStatuses) > 0 {
+		terminated = pod.Status.ContainerStatuses[0].State.Terminated
+	}
+	if terminated == nil {
+		if pod.Status.Phase == corev1.PodFailed {
+			// The pod failed before the importer ran, like when the deadline passes while the image is pulled
+			return &importProbeResult{err: errors.Errorf("the probe pod failed: %s %s", pod.Status.Reason, pod.Status.Message)}
+		}
+		return nil
+	}
+	message := strings.TrimSpace(terminated.Message)
+	if terminated.ExitCode != 0 {
+		if message == "" {
+			message = "the probe pod failed: " + terminated.Reason
+		}
+		return &importProbeResult{err: errors.New(message)}
+	}
+	result := &importProbeResult{}
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, SourceVirtualSizeMessagePrefix) {
+			result.virtualSize, _ = strconv.ParseInt(strings.TrimPrefix(line, SourceVirtualSizeMessagePrefix), 10, 64)
+		}
+	}
+	return result
+}
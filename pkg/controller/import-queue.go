@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// AnnMaxParallelImports is a namespace annotation limiting the number of importer pods running at the same time in the namespace
+	AnnMaxParallelImports = AnnAPIGroup + "/storage.import.maxParallelImports"
+
+	// MessageImportQueuedCluster provides a const to form the running condition message of an import queued by the limit of the cluster
+	MessageImportQueuedCluster = "Import queued at position %d, the cluster runs its maximum of %d parallel imports"
+	// MessageImportQueuedNamespace provides a const to form the running condition message of an import queued by the limit of the namespace
+	MessageImportQueuedNamespace = "Import queued at position %d, the namespace runs its maximum of %d parallel imports"
+)
+
+// admitImport tells whether the importer pod of the PVC can be created under the maximum number of parallel imports of
+// the CDIConfig and of the namespace. The imports over the maximum are queued, and admitted in the order of creation of
+// their PVCs as the running importer pods complete. The position of the queued imports is reported in the running
+// condition of the PVC.
+func (r *ImportReconciler) admitImport(pvc *corev1.PersistentVolumeClaim, log logr.Logger) (bool, error) {
+	maxParallelImports, err := r.getMaxParallelImports()
+	if err != nil {
+		return false, err
+	}
+	namespaceMaxParallelImports, err := r.getNamespaceMaxParallelImports(pvc.Namespace, log)
+	if err != nil {
+		return false, err
+	}
+	_, queued := pvc.Annotations[AnnImportQueued]
+	if maxParallelImports <= 0 && namespaceMaxParallelImports <= 0 && !queued {
+		return true, nil
+	}
+
+	message, err := r.getImportQueueMessage(pvc, maxParallelImports, log)
+	if err != nil {
+		return false, err
+	}
+	anno := pvc.GetAnnotations()
+	if message == "" {
+		if !queued {
+			return true, nil
+		}
+		log.V(1).Info("Import admitted under the maximum number of parallel imports", "pvc.Name", pvc.Name)
+		delete(anno, AnnImportQueued)
+		if anno[AnnRunningConditionReason] == ImportQueued {
+			delete(anno, AnnRunningCondition)
+			delete(anno, AnnRunningConditionMessage)
+			delete(anno, AnnRunningConditionReason)
+		}
+		return true, r.updatePVC(pvc, log)
+	}
+
+	if queued && anno[AnnRunningConditionMessage] == message {
+		return false, nil
+	}
+	if !queued {
+		log.V(1).Info("Import queued, the maximum number of parallel imports is reached", "pvc.Name", pvc.Name)
+		r.recorder.Event(pvc, corev1.EventTypeNormal, ImportQueued, message)
+	}
+	anno[AnnImportQueued] = "true"
+	anno[AnnRunningCondition] = "false"
+	anno[AnnRunningConditionMessage] = message
+	anno[AnnRunningConditionReason] = ImportQueued
+	return false, r.updatePVC(pvc, log)
+}
+
+// getImportQueueMessage admits the queued imports in the order of creation of their PVCs while the running imports
+// of the cluster and of their namespace are under the maximum, and returns why the import to the PVC waits along with
+// its position in the queue of the cluster or of its namespace, or an empty message if it is admitted. The imports of
+// a namespace at its maximum do not hold back the imports of the other namespaces.
+func (r *ImportReconciler) getImportQueueMessage(pvc *corev1.PersistentVolumeClaim, maxParallelImports int, log logr.Logger) (string, error) {
+	running, runningByNamespace, err := r.countRunningImports()
+	if err != nil {
+		return "", err
+	}
+	queue, err := r.listQueuedImports(pvc)
+	if err != nil {
+		return "", err
+	}
+
+	namespaceLimits := map[string]int{}
+	clusterWaiting, namespaceWaiting := 0, map[string]int{}
+	for _, queued := range queue {
+		namespace := queued.Namespace
+		limit, ok := namespaceLimits[namespace]
+		if !ok {
+			if limit, err = r.getNamespaceMaxParallelImports(namespace, log); err != nil {
+				return "", err
+			}
+			namespaceLimits[namespace] = limit
+		}
+		isPvc := queued.Namespace == pvc.Namespace && queued.Name == pvc.Name
+
+		switch {
+		case limit > 0 && runningByNamespace[namespace] >= limit:
+			namespaceWaiting[namespace]++
+			if isPvc {
+				return fmt.Sprintf(MessageImportQueuedNamespace, namespaceWaiting[namespace], limit), nil
+			}
+		case maxParallelImports > 0 && running >= maxParallelImports:
+			clusterWaiting++
+			if isPvc {
+				return fmt.Sprintf(MessageImportQueuedCluster, clusterWaiting, maxParallelImports), nil
+			}
+		default:
+			running++
+			runningByNamespace[namespace]++
+			if isPvc {
+				return "", nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (r *ImportReconciler) getMaxParallelImports() (int, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if cdiconfig.Spec.MaxParallelImports == nil {
+		return 0, nil
+	}
+	return int(*cdiconfig.Spec.MaxParallelImports), nil
+}
+
+func (r *ImportReconciler) getNamespaceMaxParallelImports(namespace string, log logr.Logger) (int, error) {
+	ns := &corev1.Namespace{}
+	if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	value, ok := ns.Annotations[AnnMaxParallelImports]
+	if !ok {
+		return 0, nil
+	}
+	maxParallelImports, err := strconv.Atoi(value)
+	if err != nil {
+		log.V(1).Info("Ignoring invalid namespace annotation", "namespace", namespace, "annotation", AnnMaxParallelImports, "value", value)
+		return 0, nil
+	}
+	return maxParallelImports, nil
+}
+
+// countRunningImports counts the importer pods of the cluster and of every namespace that are not completed. The pods
+// are listed from the API server, so the pods just created are counted.
+func (r *ImportReconciler) countRunningImports() (int, map[string]int, error) {
+	pods := &corev1.PodList{}
+	if err := r.uncachedClient.List(context.TODO(), pods, client.MatchingLabels{common.CDIComponentLabel: common.ImporterPodName}); err != nil {
+		return 0, nil, err
+	}
+	running, runningByNamespace := 0, map[string]int{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			running++
+			runningByNamespace[pod.Namespace]++
+		}
+	}
+	return running, runningByNamespace, nil
+}
+
+// listQueuedImports returns the PVCs of the queued imports along with the PVC, in the order of creation
+func (r *ImportReconciler) listQueuedImports(pvc *corev1.PersistentVolumeClaim) ([]*corev1.PersistentVolumeClaim, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.client.List(context.TODO(), pvcs); err != nil {
+		return nil, err
+	}
+	queue := []*corev1.PersistentVolumeClaim{pvc}
+	for i := range pvcs.Items {
+		other := &pvcs.Items[i]
+		if other.Namespace == pvc.Namespace && other.Name == pvc.Name {
+			continue
+		}
+		if _, ok := other.Annotations[AnnImportQueued]; ok && other.DeletionTimestamp == nil {
+			queue = append(queue, other)
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		return createdBefore(queue[i], queue[j])
+	})
+	return queue, nil
+}
+
+// createdBefore orders the PVCs by creation time, then by namespace and name
+func createdBefore(pvc, other *corev1.PersistentVolumeClaim) bool {
+	if !pvc.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return pvc.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	if pvc.Namespace != other.Namespace {
+		return pvc.Namespace < other.Namespace
+	}
+	return pvc.Name < other.Name
+}
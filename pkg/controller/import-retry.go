@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// AnnImportRetryPolicy is a PVC annotation holding the retry policy of the import, in JSON
+	AnnImportRetryPolicy = AnnAPIGroup + "/storage.import.retryPolicy"
+	// AnnImportAttempts is a PVC annotation holding the number of failed attempts of the import
+	AnnImportAttempts = AnnAPIGroup + "/storage.import.attempts"
+	// AnnImportRetryTime is a PVC annotation holding the time the next attempt of the import starts at
+	AnnImportRetryTime = AnnAPIGroup + "/storage.import.retryTime"
+
+	// ImportRetryBackoff is reason for the running condition of an import waiting to retry after a failed attempt
+	ImportRetryBackoff = "ImportRetryBackoff"
+	// ImportRetriesExhausted is reason for event and running condition of an import failed at its last attempt
+	ImportRetriesExhausted = "ImportRetriesExhausted"
+
+	defaultImportInitialBackoff = 10 * time.Second
+	defaultImportMaxBackoff     = 5 * time.Minute
+)
+
+// GetImportRetryPolicy returns the retry policy of the import to the PVC, the one requested for the PVC or else the
+// import retry policy of the CDIConfig. Without retry policy, the importer pods restart on failure.
+func GetImportRetryPolicy(c client.Client, pvc *corev1.PersistentVolumeClaim) (*cdiv1.ImportRetryPolicy, error) {
+	if value, ok := pvc.GetAnnotations()[AnnImportRetryPolicy]; ok {
+		policy := &cdiv1.ImportRetryPolicy{}
+		if err := json.Unmarshal([]byte(value), policy); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s annotation", AnnImportRetryPolicy)
+		}
+		return policy, nil
+	}
+
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cdiconfig.Spec.ImportRetryPolicy, nil
+}
+
+// importBackoff returns the delay before the next attempt of an import after its failed attempts
+func importBackoff(policy *cdiv1.ImportRetryPolicy, attempts int) time.Duration {
+	backoff, maxBackoff := defaultImportInitialBackoff, defaultImportMaxBackoff
+	if policy.InitialBackoff != nil {
+		backoff = policy.InitialBackoff.Duration
+	}
+	if policy.MaxBackoff != nil {
+		maxBackoff = policy.MaxBackoff.Duration
+	}
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func getImportAttempts(pvc *corev1.PersistentVolumeClaim) int {
+	attempts, err := strconv.Atoi(pvc.GetAnnotations()[AnnImportAttempts])
+	if err != nil {
+		return 0
+	}
+	return attempts
+}
+
+// importRetriesExhausted tells whether the import failed at the last attempt of its retry policy
+func importRetriesExhausted(pvc *corev1.PersistentVolumeClaim) bool {
+	_, ok := pvc.GetAnnotations()[AnnImportAttempts]
+	return ok && pvc.GetAnnotations()[AnnPodPhase] == string(corev1.PodFailed)
+}
+
+// importRetryWait returns how long the import waits before its next attempt
+func importRetryWait(pvc *corev1.PersistentVolumeClaim) time.Duration {
+	retryTime, err := time.Parse(time.RFC3339, pvc.GetAnnotations()[AnnImportRetryTime])
+	if err != nil {
+		return 0
+	}
+	return time.Until(retryTime)
+}
+
+// retryFailedImport counts the failed attempt of the importer pod, and schedules the next attempt after the backoff of
+// the retry policy, or fails the import for good at the last attempt. The error of the attempt is kept in the running
+// condition.
+func (r *ImportReconciler) retryFailedImport(pvc *corev1.PersistentVolumeClaim, log logr.Logger) error {
+	policy, err := GetImportRetryPolicy(r.client, pvc)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		// The retry policy was removed after the creation of the pod
+		policy = &cdiv1.ImportRetryPolicy{}
+	}
+
+	anno := pvc.GetAnnotations()
+	attempts := getImportAttempts(pvc) + 1
+	anno[AnnImportAttempts] = strconv.Itoa(attempts)
+	anno[AnnPodRestarts] = strconv.Itoa(attempts)
+	lastError := anno[AnnRunningConditionMessage]
+	if policy.MaxAttempts != nil && attempts >= int(*policy.MaxAttempts) {
+		log.V(1).Info("Import failed at its last attempt", "pvc.Name", pvc.Name, "attempts", attempts)
+		delete(anno, AnnImportRetryTime)
+		anno[AnnRunningConditionMessage] = fmt.Sprintf("Import failed after %d attempts: %s", attempts, lastError)
+		anno[AnnRunningConditionReason] = ImportRetriesExhausted
+		r.recorder.Event(pvc, corev1.EventTypeWarning, ImportRetriesExhausted, anno[AnnRunningConditionMessage])
+		return nil
+	}
+
+	backoff := importBackoff(policy, attempts)
+	log.V(1).Info("Import attempt failed, retrying after backoff", "pvc.Name", pvc.Name, "attempts", attempts, "backoff", backoff)
+	anno[AnnImportRetryTime] = time.Now().Add(backoff).Format(time.RFC3339)
+	anno[AnnPodPhase] = string(corev1.PodPending)
+	anno[AnnRunningConditionMessage] = fmt.Sprintf("Attempt %d failed: %s, retrying in %s", attempts, lastError, backoff)
+	anno[AnnRunningConditionReason] = ImportRetryBackoff
+	return nil
+}
+
+// podFailedTermination returns the termination of the failed importer container, the last one of a restarting
+// container or the final one of a failed pod
+func podFailedTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return nil
+	}
+	status := pod.Status.ContainerStatuses[0]
+	if status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode > 0 {
+		return status.LastTerminationState.Terminated
+	}
+	if pod.Status.Phase == corev1.PodFailed && status.State.Terminated != nil && status.State.Terminated.ExitCode > 0 {
+		return status.State.Terminated
+	}
+	return nil
+}
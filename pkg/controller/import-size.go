@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// ImportSizeDetectionFailed provides a const to indicate the size of the import source could not be detected
+	ImportSizeDetectionFailed = "ImportSizeDetectionFailed"
+	// MessageImportSizeDetectionFailed provides a const to form the size detection failure message
+	MessageImportSizeDetectionFailed = "Unable to detect the size of the import source, set the size of the PVC: %v"
+
+	// importSizeDetectionRetryInterval is how long to wait before probing a source whose size is unknown again
+	importSizeDetectionRetryInterval = time.Minute
+	// importSizeAlignment is the alignment of the detected PVC sizes
+	importSizeAlignment = 1024 * 1024
+)
+
+// importSizeDetectionRequired returns true if the DataVolume imports a disk image from http or from a registry without
+// requesting a PVC size, the size of the PVC is detected from the source then.
+func importSizeDetectionRequired(dataVolume *cdiv1.DataVolume) bool {
+	if dataVolume.Spec.PVC == nil || dataVolume.Spec.ContentType == cdiv1.DataVolumeArchive {
+		return false
+	}
+	if dataVolume.Spec.Source.HTTP == nil && dataVolume.Spec.Source.Registry == nil {
+		return false
+	}
+	_, ok := dataVolume.Spec.PVC.Resources.Requests[corev1.ResourceStorage]
+	return !ok
+}
+
+// detectImportSize returns the size of the PVC needed to import the source of the DataVolume, the virtual size of its
+// image reported by the probe plus the filesystem overhead.
+func (r *DatavolumeReconciler) detectImportSize(dataVolume *cdiv1.DataVolume, probe *importProbeResult) (*resource.Quantity, error) {
+	if probe.err != nil {
+		return nil, probe.err
+	}
+	if probe.virtualSize <= 0 {
+		return nil, errors.New("the probe did not report the virtual size of the source")
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: dataVolume.Namespace},
+		Spec:       *dataVolume.Spec.PVC,
+	}
+	if dataVolume.Spec.FilesystemOverhead != nil {
+		pvc.Annotations = map[string]string{AnnFilesystemOverhead: string(*dataVolume.Spec.FilesystemOverhead)}
+	}
+	overhead, err := GetFilesystemOverhead(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+	overheadValue, err := strconv.ParseFloat(string(overhead), 64)
+	if err != nil {
+		return nil, err
+	}
+	return importPvcSize(probe.virtualSize, overheadValue), nil
+}
+
+// importPvcSize returns the size of a PVC holding an image of the virtual size along with the filesystem overhead
+func importPvcSize(virtualSize int64, overhead float64) *resource.Quantity {
+	size := int64(math.Ceil(float64(virtualSize) / (1 - overhead)))
+	size = (size + importSizeAlignment - 1) / importSizeAlignment * importSizeAlignment
+	return resource.NewQuantity(size, resource.BinarySI)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// importSourceCapabilities are the capabilities of an import source type
+type importSourceCapabilities struct {
+	// multiStage is true if the importer data source implements the DeltaReader interface, to copy the changes
+	// between the checkpoints of a multi-stage import
+	multiStage bool
+	// kubeVirtOnly is true if the importer can only write disk images from the source, the archive content type
+	// is not supported
+	kubeVirtOnly bool
+}
+
+// importSources holds the capabilities of the import source types, the types missing from the map have none
+var importSources = map[string]importSourceCapabilities{
+	SourceRegistry:    {kubeVirtOnly: true},
+	SourceImageio:     {multiStage: true, kubeVirtOnly: true},
+	SourceGlance:      {kubeVirtOnly: true},
+	SourceProxmox:     {kubeVirtOnly: true},
+	SourceHyperV:      {kubeVirtOnly: true},
+	SourceNFS:         {kubeVirtOnly: true},
+	SourceSMB:         {kubeVirtOnly: true},
+	SourceRsync:       {kubeVirtOnly: true},
+	SourceISCSI:       {kubeVirtOnly: true},
+	SourceRBD:         {kubeVirtOnly: true},
+	SourceFile:        {kubeVirtOnly: true},
+	SourceLibvirt:     {kubeVirtOnly: true},
+	SourceAWSSnapshot: {multiStage: true, kubeVirtOnly: true},
+	SourceGCEImage:    {kubeVirtOnly: true},
+	SourceAzureDisk:   {kubeVirtOnly: true},
+	SourceVDDK:        {multiStage: true},
+}
+
+// GetImportSourceType returns the import source type of the DataVolume source, or "" if the DataVolume is not
+// populated by an importer.
+func GetImportSourceType(source *cdiv1.DataVolumeSource) string {
+	switch {
+	case source.HTTP != nil:
+		return SourceHTTP
+	case source.S3 != nil:
+		return SourceS3
+	case source.AWSSnapshot != nil:
+		return SourceAWSSnapshot
+	case source.GCS != nil:
+		return SourceGCS
+	case source.GCEImage != nil:
+		return SourceGCEImage
+	case source.AzureBlob != nil:
+		return SourceAzureBlob
+	case source.AzureDisk != nil:
+		return SourceAzureDisk
+	case source.Registry != nil:
+		return SourceRegistry
+	case source.Blank != nil:
+		return SourceNone
+	case source.Imageio != nil:
+		return SourceImageio
+	case source.Glance != nil:
+		return SourceGlance
+	case source.Proxmox != nil:
+		return SourceProxmox
+	case source.HyperV != nil:
+		return SourceHyperV
+	case source.NFS != nil:
+		return SourceNFS
+	case source.SMB != nil:
+		return SourceSMB
+	case source.Rsync != nil:
+		return SourceRsync
+	case source.ISCSI != nil:
+		return SourceISCSI
+	case source.RBD != nil:
+		return SourceRBD
+	case source.File != nil:
+		return SourceFile
+	case source.Libvirt != nil:
+		return SourceLibvirt
+	case source.VDDK != nil:
+		return SourceVDDK
+	}
+	return ""
+}
+
+// IsMultiStageImportSource returns true if the DataVolume source supports multi-stage imports.
+func IsMultiStageImportSource(source *cdiv1.DataVolumeSource) bool {
+	return importSources[GetImportSourceType(source)].multiStage
+}
+
+// IsKubeVirtOnlySource returns true if the importer only supports the kubevirt content type for the import source type.
+func IsKubeVirtOnlySource(sourceType string) bool {
+	return importSources[sourceType].kubeVirtOnly
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AnnImportTimeout is a PVC annotation holding the maximum duration of the import
+	AnnImportTimeout = AnnAPIGroup + "/storage.import.timeout"
+	// AnnImportStartTime is a PVC annotation holding the creation time of the first importer pod, the timeout of the import starts then
+	AnnImportStartTime = AnnAPIGroup + "/storage.import.startTime"
+
+	// ImportTimeout is reason for event and running condition of an import cancelled at its timeout
+	ImportTimeout = "Timeout"
+	// MessageImportTimeout is the message of an import cancelled at its timeout
+	MessageImportTimeout = "Import did not complete within %s"
+
+	// podDeadlineExceededReason is the reason of the pods failed at their active deadline
+	podDeadlineExceededReason = "DeadlineExceeded"
+)
+
+// getImportTimeout returns the timeout of the import, if any
+func getImportTimeout(pvc *corev1.PersistentVolumeClaim) (time.Duration, bool) {
+	value, ok := pvc.GetAnnotations()[AnnImportTimeout]
+	if !ok {
+		return 0, false
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, false
+	}
+	return timeout, true
+}
+
+// importDeadline returns the time the import is cancelled at, once its first importer pod is created
+func importDeadline(pvc *corev1.PersistentVolumeClaim) (time.Time, bool) {
+	timeout, ok := getImportTimeout(pvc)
+	if !ok {
+		return time.Time{}, false
+	}
+	startTime, err := time.Parse(time.RFC3339, pvc.GetAnnotations()[AnnImportStartTime])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return startTime.Add(timeout), true
+}
+
+// importDeadlineExceeded tells whether the import ran past its timeout
+func importDeadlineExceeded(pvc *corev1.PersistentVolumeClaim) bool {
+	deadline, ok := importDeadline(pvc)
+	return ok && !time.Now().Before(deadline)
+}
+
+// importActiveDeadlineSeconds returns the active deadline of an importer pod, the time left before the timeout of the
+// import. The kubelet kills the pod at its deadline, which cancels the transfer.
+func importActiveDeadlineSeconds(pvc *corev1.PersistentVolumeClaim) *int64 {
+	deadline, ok := importDeadline(pvc)
+	if !ok {
+		return nil
+	}
+	seconds := int64(math.Ceil(time.Until(deadline).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &seconds
+}
+
+// startImportTimeout starts the timeout of the import at the creation of its first importer pod, it returns whether
+// the PVC needs an update
+func startImportTimeout(pvc *corev1.PersistentVolumeClaim) bool {
+	if _, ok := getImportTimeout(pvc); !ok {
+		return false
+	}
+	if _, ok := pvc.GetAnnotations()[AnnImportStartTime]; ok {
+		return false
+	}
+	pvc.GetAnnotations()[AnnImportStartTime] = time.Now().Format(time.RFC3339)
+	return true
+}
+
+// isPodDeadlineExceeded tells whether the pod was killed at its active deadline
+func isPodDeadlineExceeded(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == podDeadlineExceededReason
+}
+
+// failTimedOutImport fails the import for good once it reached its timeout
+func (r *ImportReconciler) failTimedOutImport(pvc *corev1.PersistentVolumeClaim, log logr.Logger) {
+	timeout, _ := getImportTimeout(pvc)
+	log.V(1).Info("Import timed out", "pvc.Name", pvc.Name, "timeout", timeout)
+	anno := pvc.GetAnnotations()
+	if anno[AnnRunningConditionReason] != ImportTimeout {
+		countImportFailure(pvc, importFailureTimeout)
+	}
+	delete(anno, AnnImportRetryTime)
+	anno[AnnPodPhase] = string(corev1.PodFailed)
+	anno[AnnRunningCondition] = "false"
+	anno[AnnRunningConditionMessage] = fmt.Sprintf(MessageImportTimeout, timeout)
+	anno[AnnRunningConditionReason] = ImportTimeout
+	r.recorder.Event(pvc, corev1.EventTypeWarning, ImportTimeout, anno[AnnRunningConditionMessage])
+}
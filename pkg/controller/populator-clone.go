@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// populatorCloneRetryInterval is the interval the clone of a VolumeCloneSource is retried at while its source PVC
+	// is not ready to be cloned
+	populatorCloneRetryInterval = 10 * time.Second
+)
+
+// newClonePrimePvc returns the prime PVC cloning the source PVC of the VolumeCloneSource of the PVC, with the clone
+// strategy the DataVolumes would use, nil until the source PVC can be cloned
+func (r *PopulatorReconciler) newClonePrimePvc(log logr.Logger, pvc *corev1.PersistentVolumeClaim, dataSource *corev1.TypedLocalObjectReference) (*corev1.PersistentVolumeClaim, reconcile.Result, error) {
+	cloneSource := &cdiv1.VolumeCloneSource{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: dataSource.Name}, cloneSource); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, reconcile.Result{}, nil
+		}
+		return nil, reconcile.Result{}, err
+	}
+	source := cloneSource.Spec.Source
+	if source.Kind != "PersistentVolumeClaim" || (source.APIGroup != nil && *source.APIGroup != "") {
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, PopulateSourceInvalid, "VolumeCloneSource %s does not clone a PersistentVolumeClaim", cloneSource.Name)
+		return nil, reconcile.Result{}, nil
+	}
+
+	sourcePvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: source.Name}, sourcePvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.V(3).Info("Waiting for the source PVC to exist", "source.Name", source.Name)
+			return nil, reconcile.Result{RequeueAfter: populatorCloneRetryInterval}, nil
+		}
+		return nil, reconcile.Result{}, err
+	}
+	if populated, err := IsPopulated(sourcePvc, r.client); !populated || err != nil {
+		log.V(3).Info("Waiting for the source PVC to be populated", "source.Name", source.Name)
+		return nil, reconcile.Result{RequeueAfter: populatorCloneRetryInterval}, err
+	}
+
+	// The strategies are picked like for a DataVolume cloning the source PVC into the PVC
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+			UID:       pvc.UID,
+		},
+		Spec: cdiv1.DataVolumeSpec{
+			Source: cdiv1.DataVolumeSource{
+				PVC: &cdiv1.DataVolumeSourcePVC{Namespace: pvc.Namespace, Name: source.Name},
+			},
+			PVC:           pvc.Spec.DeepCopy(),
+			Preallocation: cloneSource.Spec.Preallocation,
+		},
+	}
+	dvr := &DatavolumeReconciler{
+		client:       r.client,
+		extClientSet: r.extClientSet,
+		recorder:     r.recorder,
+		log:          r.log,
+	}
+	cloneStrategy, err := dvr.getCloneStrategy(dataVolume)
+	if err != nil {
+		return nil, reconcile.Result{}, err
+	}
+	var snapshotClassName string
+	if cloneStrategy == cdiv1.CloneStrategyCsiClone {
+		err = dvr.validateCsiClone(dataVolume)
+	} else {
+		snapshotClassName, err = dvr.getSnapshotClassForSmartClone(dataVolume)
+	}
+	smartClone := err == nil && (cloneStrategy == cdiv1.CloneStrategySnapshot || cloneStrategy == cdiv1.CloneStrategyCsiClone)
+	if smartClone {
+		if inUse, err := r.cloneSourceInUse(pvc, source.Name); inUse || err != nil {
+			return nil, reconcile.Result{RequeueAfter: populatorCloneRetryInterval}, err
+		}
+	}
+
+	primePvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pvc.Namespace,
+			Labels: map[string]string{
+				common.CDILabelKey: common.CDILabelValue,
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: *pvc.Spec.DeepCopy(),
+	}
+	switch {
+	case smartClone && cloneStrategy == cdiv1.CloneStrategySnapshot:
+		snapshot, err := r.getCloneSnapshot(log, pvc, source.Name, snapshotClassName)
+		if snapshot == nil || err != nil {
+			return nil, reconcile.Result{RequeueAfter: populatorCloneRetryInterval}, err
+		}
+		apiGroup := snapshotv1.SchemeGroupVersion.Group
+		primePvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     "VolumeSnapshot",
+			Name:     snapshot.Name,
+		}
+	case smartClone:
+		log.V(1).Info("Cloning the source PVC with the CSI driver", "source.Name", source.Name)
+		primePvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: source.Name,
+		}
+	default:
+		primePvc.Spec.DataSource = nil
+		primePvc.Annotations[AnnCloneRequest] = fmt.Sprintf("%s/%s", pvc.Namespace, source.Name)
+		primePvc.Annotations[AnnPodRestarts] = "0"
+		primePvc.Annotations[AnnPreallocationRequested] = fmt.Sprintf("%t", GetPreallocation(r.client, dataVolume))
+		// Record why the clone is not a smart-clone when the cluster lacks the capability
+		var fallback *smartCloneFallbackError
+		if cloneStrategy != cdiv1.CloneStrategyHostAssisted && errors.As(err, &fallback) {
+			log.V(1).Info("Falling back to a host-assisted clone", "reason", fallback.reason, "message", fallback.message)
+			setSmartCloneFallback(primePvc, fallback)
+		}
+	}
+	return primePvc, reconcile.Result{}, nil
+}
+
+// cloneSourceInUse checks if pods use the source PVC, which cannot be smart-cloned then
+func (r *PopulatorReconciler) cloneSourceInUse(pvc *corev1.PersistentVolumeClaim, sourceName string) (bool, error) {
+	pods, err := getPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(sourceName), false)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, SmartCloneSourceInUse,
+			"pod %s/%s using PersistentVolumeClaim %s", pod.Namespace, pod.Name, sourceName)
+	}
+	return len(pods) > 0, nil
+}
+
+// getCloneSnapshot returns the snapshot of the source PVC the prime PVC is restored from once it is ready to use, and
+// creates the snapshot if it does not exist yet
+func (r *PopulatorReconciler) getCloneSnapshot(log logr.Logger, pvc *corev1.PersistentVolumeClaim, sourceName, snapshotClassName string) (*snapshotv1.VolumeSnapshot, error) {
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: GetPrimePvcName(pvc)}, snapshot)
+	if k8serrors.IsNotFound(err) {
+		snapshot = &snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GetPrimePvcName(pvc),
+				Namespace: pvc.Namespace,
+				Labels: map[string]string{
+					common.CDILabelKey:       common.CDILabelValue,
+					common.CDIComponentLabel: common.SmartClonerCDILabel,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(pvc, corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")),
+				},
+			},
+			Spec: snapshotv1.VolumeSnapshotSpec{
+				Source: snapshotv1.VolumeSnapshotSource{
+					PersistentVolumeClaimName: &sourceName,
+				},
+				VolumeSnapshotClassName: &snapshotClassName,
+			},
+		}
+		log.V(1).Info("Creating snapshot of the source PVC", "snapshot.Name", snapshot.Name)
+		if err := r.client.Create(context.TODO(), snapshot); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		log.V(3).Info("Waiting for the snapshot of the source PVC to be ready", "snapshot.Name", snapshot.Name)
+		return nil, nil
+	}
+	return snapshot, nil
+}
+
+// deleteCloneSnapshot deletes the snapshot the prime PVC of the PVC was restored from, if any
+func (r *PopulatorReconciler) deleteCloneSnapshot(log logr.Logger, pvc *corev1.PersistentVolumeClaim) error {
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: GetPrimePvcName(pvc)}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	log.V(1).Info("Deleting snapshot of the source PVC", "snapshot.Name", snapshot.Name)
+	return IgnoreNotFound(r.client.Delete(context.TODO(), snapshot))
+}
+
+// isPopulatorClone checks if the target PVC of a host-assisted clone is the prime PVC of a PVC populated by a
+// VolumeCloneSource, which only clones the PVCs of its own namespace and needs no clone token
+func isPopulatorClone(source, target *corev1.PersistentVolumeClaim) bool {
+	owner := metav1.GetControllerOf(target)
+	return owner != nil && owner.Kind == "PersistentVolumeClaim" &&
+		target.Name == fmt.Sprintf("%s%s", primePvcPrefix, owner.UID) &&
+		source.Namespace == target.Namespace
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	extclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	populatorControllerAgentName = "populator-controller"
+
+	// AnnSelectedNode is the annotation the scheduler sets on the PVCs of WaitForFirstConsumer storage classes with the
+	// node of their first consumer
+	AnnSelectedNode = "volume.kubernetes.io/selected-node"
+
+	// PopulateScheduled provides a const to indicate the prime PVC populating a PVC was created
+	PopulateScheduled = "PopulateScheduled"
+	// PopulateSucceeded provides a const to indicate the populated volume was bound to the PVC
+	PopulateSucceeded = "PopulateSucceeded"
+	// PopulateSourceInvalid provides a const to indicate the populator source of a PVC is not supported
+	PopulateSourceInvalid = "PopulateSourceInvalid"
+
+	// MessagePopulateScheduled provides a const to form the message of the PopulateScheduled event
+	MessagePopulateScheduled = "Populating PVC %s with %s %s"
+	// MessagePopulateSucceeded provides a const to form the message of the PopulateSucceeded event
+	MessagePopulateSucceeded = "PVC %s populated"
+
+	primePvcPrefix = "prime-"
+)
+
+// PopulatorReconciler members
+type PopulatorReconciler struct {
+	client       client.Client
+	extClientSet extclientset.Interface
+	recorder     record.EventRecorder
+	scheme       *runtime.Scheme
+	log          logr.Logger
+}
+
+// NewPopulatorController creates a new instance of the populator controller, populating the PVCs whose dataSource is
+// a CDI populator source.
+func NewPopulatorController(mgr manager.Manager, extClientSet extclientset.Interface, log logr.Logger) (controller.Controller, error) {
+	reconciler := &PopulatorReconciler{
+		client:       mgr.GetClient(),
+		extClientSet: extClientSet,
+		scheme:       mgr.GetScheme(),
+		log:          log.WithName(populatorControllerAgentName),
+		recorder:     mgr.GetEventRecorderFor(populatorControllerAgentName),
+	}
+	populatorController, err := controller.New(populatorControllerAgentName, mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addPopulatorControllerWatches(mgr, populatorController); err != nil {
+		return nil, err
+	}
+	return populatorController, nil
+}
+
+func addPopulatorControllerWatches(mgr manager.Manager, populatorController controller.Controller) error {
+	if err := populatorController.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	// The prime PVCs
+	if err := populatorController.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &corev1.PersistentVolumeClaim{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
+	// The PVCs waiting for their source
+	if err := populatorController.Watch(&source.Kind{Type: &cdiv1.VolumeImportSource{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return mapPopulatorSourceToPvcs(mgr.GetClient(), "VolumeImportSource", obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}); err != nil {
+		return err
+	}
+	if err := populatorController.Watch(&source.Kind{Type: &cdiv1.VolumeUploadSource{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return mapPopulatorSourceToPvcs(mgr.GetClient(), "VolumeUploadSource", obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}); err != nil {
+		return err
+	}
+	if err := populatorController.Watch(&source.Kind{Type: &cdiv1.VolumeCloneSource{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return mapPopulatorSourceToPvcs(mgr.GetClient(), "VolumeCloneSource", obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mapPopulatorSourceToPvcs returns the requests of the PVCs of the namespace whose dataSource is the populator source
+func mapPopulatorSourceToPvcs(c client.Client, kind, namespace, name string) []reconcile.Request {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := c.List(context.TODO(), pvcs, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, pvc := range pvcs.Items {
+		if dataSource := getPopulatorDataSource(&pvc); dataSource != nil && dataSource.Kind == kind && dataSource.Name == name {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}})
+		}
+	}
+	return reqs
+}
+
+// getPopulatorDataSource returns the dataSource of the PVC if it is a CDI populator source, nil otherwise
+func getPopulatorDataSource(pvc *corev1.PersistentVolumeClaim) *corev1.TypedLocalObjectReference {
+	dataSource := pvc.Spec.DataSource
+	if dataSource == nil || dataSource.APIGroup == nil || *dataSource.APIGroup != cdiv1.SchemeGroupVersion.Group {
+		return nil
+	}
+	switch dataSource.Kind {
+	case "VolumeImportSource", "VolumeUploadSource", "VolumeCloneSource":
+		return dataSource
+	}
+	return nil
+}
+
+// IsVolumeUploadSourceTarget checks if the PVC is populated by a VolumeUploadSource, the uploads to the PVC are
+// received by its prime PVC
+func IsVolumeUploadSourceTarget(pvc *corev1.PersistentVolumeClaim) bool {
+	dataSource := getPopulatorDataSource(pvc)
+	return dataSource != nil && dataSource.Kind == "VolumeUploadSource"
+}
+
+// Reconcile the reconcile loop for the PVCs populated by CDI.
+func (r *PopulatorReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("PVC", req.NamespacedName)
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	dataSource := getPopulatorDataSource(pvc)
+	if dataSource == nil || pvc.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	primePvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: GetPrimePvcName(pvc)}, primePvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		primePvc = nil
+	}
+
+	// The populated volume was bound to the PVC, the prime PVC left behind is no longer needed
+	if pvc.Status.Phase == corev1.ClaimBound {
+		if primePvc != nil {
+			log.V(1).Info("Deleting prime PVC", "prime.Name", primePvc.Name)
+			if err := r.client.Delete(context.TODO(), primePvc); IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if dataSource.Kind == "VolumeCloneSource" {
+			return reconcile.Result{}, r.deleteCloneSnapshot(log, pvc)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if primePvc == nil {
+		return r.createPrimePvc(log, pvc, dataSource)
+	}
+	if !isPrimePvcPopulated(primePvc) {
+		log.V(3).Info("Prime PVC not populated yet", "prime.Name", primePvc.Name)
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, r.rebindPopulatedVolume(log, pvc, primePvc)
+}
+
+// createPrimePvc creates the PVC populated in place of the target PVC, once the target PVC can be provisioned
+func (r *PopulatorReconciler) createPrimePvc(log logr.Logger, pvc *corev1.PersistentVolumeClaim, dataSource *corev1.TypedLocalObjectReference) (reconcile.Result, error) {
+	waitForFirstConsumer, err := r.isWaitForFirstConsumer(pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if waitForFirstConsumer && pvc.Annotations[AnnSelectedNode] == "" {
+		log.V(3).Info("Waiting for the first consumer of the PVC to be scheduled")
+		return reconcile.Result{}, nil
+	}
+
+	var primePvc *corev1.PersistentVolumeClaim
+	if dataSource.Kind == "VolumeCloneSource" {
+		var result reconcile.Result
+		if primePvc, result, err = r.newClonePrimePvc(log, pvc, dataSource); primePvc == nil || err != nil {
+			return result, err
+		}
+	} else {
+		dataVolume, err := r.getPopulatorDataVolume(pvc, dataSource)
+		if err != nil || dataVolume == nil {
+			return reconcile.Result{}, err
+		}
+		if primePvc, err = newPersistentVolumeClaim(r.client, dataVolume); err != nil {
+			return reconcile.Result{}, err
+		}
+		primePvc.Spec.DataSource = nil
+	}
+	primePvc.Name = GetPrimePvcName(pvc)
+	primePvc.Spec.VolumeName = ""
+	primePvc.Annotations[AnnImmediateBinding] = ""
+	if node := pvc.Annotations[AnnSelectedNode]; node != "" {
+		primePvc.Annotations[AnnSelectedNode] = node
+	}
+	primePvc.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(pvc, corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")),
+	}
+	log.V(1).Info("Creating prime PVC", "prime.Name", primePvc.Name)
+	if err := r.client.Create(context.TODO(), primePvc); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.recorder.Eventf(pvc, corev1.EventTypeNormal, PopulateScheduled, MessagePopulateScheduled, pvc.Name, dataSource.Kind, dataSource.Name)
+	return reconcile.Result{}, nil
+}
+
+// isPrimePvcPopulated checks if the prime PVC was populated, by a CDI pod or by the storage when the prime PVC restores
+// a snapshot or clones a PVC
+func isPrimePvcPopulated(primePvc *corev1.PersistentVolumeClaim) bool {
+	if primePvc.Spec.VolumeName == "" {
+		return false
+	}
+	if primePvc.Spec.DataSource != nil {
+		return primePvc.Status.Phase == corev1.ClaimBound
+	}
+	if _, ok := primePvc.Annotations[AnnCloneRequest]; ok {
+		return primePvc.Annotations[AnnCloneOf] == "true"
+	}
+	return primePvc.Annotations[AnnPodPhase] == string(corev1.PodSucceeded)
+}
+
+// getPopulatorDataVolume returns a DataVolume populating the PVC from its populator source, not to be created but to
+// form the prime PVC like the PVC of a DataVolume, nil if the source does not exist or is not supported
+func (r *PopulatorReconciler) getPopulatorDataVolume(pvc *corev1.PersistentVolumeClaim, dataSource *corev1.TypedLocalObjectReference) (*cdiv1.DataVolume, error) {
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+			UID:       pvc.UID,
+		},
+		Spec: cdiv1.DataVolumeSpec{
+			PVC: pvc.Spec.DeepCopy(),
+		},
+	}
+	switch dataSource.Kind {
+	case "VolumeImportSource":
+		importSource := &cdiv1.VolumeImportSource{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: dataSource.Name}, importSource); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		source := importSource.Spec.Source
+		if source.PVC != nil || source.PVCNetwork != nil || source.Snapshot != nil || source.Upload != nil {
+			r.recorder.Eventf(pvc, corev1.EventTypeWarning, PopulateSourceInvalid, "VolumeImportSource %s has no import source", importSource.Name)
+			return nil, nil
+		}
+		dataVolume.Spec.Source = source
+		dataVolume.Spec.ContentType = importSource.Spec.ContentType
+		dataVolume.Spec.Preallocation = importSource.Spec.Preallocation
+	case "VolumeUploadSource":
+		uploadSource := &cdiv1.VolumeUploadSource{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: dataSource.Name}, uploadSource); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		dataVolume.Spec.Source = cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{}}
+		dataVolume.Spec.Preallocation = uploadSource.Spec.Preallocation
+	default:
+		return nil, errors.Errorf("unknown populator source kind %s", dataSource.Kind)
+	}
+	return dataVolume, nil
+}
+
+// isWaitForFirstConsumer checks if the storage class of the PVC binds its volumes once their first consumer is scheduled
+func (r *PopulatorReconciler) isWaitForFirstConsumer(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	storageClass, err := GetStorageClassByName(r.client, pvc.Spec.StorageClassName)
+	if err != nil {
+		return false, err
+	}
+	return storageClass != nil && storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer, nil
+}
+
+// rebindPopulatedVolume binds the volume of the populated prime PVC to the target PVC
+func (r *PopulatorReconciler) rebindPopulatedVolume(log logr.Logger, pvc, primePvc *corev1.PersistentVolumeClaim) error {
+	pv := &corev1.PersistentVolume{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: primePvc.Spec.VolumeName}, pv); err != nil {
+		return err
+	}
+	if pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.UID == pvc.UID {
+		return nil
+	}
+	log.V(1).Info("Binding the populated volume to the PVC", "pv.Name", pv.Name)
+	pv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:            "PersistentVolumeClaim",
+		APIVersion:      "v1",
+		Namespace:       pvc.Namespace,
+		Name:            pvc.Name,
+		UID:             pvc.UID,
+		ResourceVersion: pvc.ResourceVersion,
+	}
+	if err := r.client.Update(context.TODO(), pv); err != nil {
+		return err
+	}
+	r.recorder.Eventf(pvc, corev1.EventTypeNormal, PopulateSucceeded, MessagePopulateSucceeded, pvc.Name)
+	return nil
+}
+
+// GetPrimePvcName returns the name of the prime PVC populated in place of a PVC whose dataSource is a CDI populator source
+func GetPrimePvcName(pvc *corev1.PersistentVolumeClaim) string {
+	return fmt.Sprintf("%s%s", primePvcPrefix, pvc.UID)
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	extfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var (
+	populatorLog = logf.Log.WithName("populator-controller-test")
+)
+
+var _ = Describe("Populator reconcile", func() {
+	It("Should ignore the PVCs without a CDI populator source", func() {
+		pvc := createPendingPvc("test-pvc", "default", nil, nil)
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{Kind: "VolumeSnapshot", Name: "test-snapshot"}
+		reconciler := createPopulatorReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+	})
+
+	It("Should wait for the VolumeImportSource to exist", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeImportSource", "test-source")
+		reconciler := createPopulatorReconciler(pvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+	})
+
+	It("Should create a prime PVC importing the VolumeImportSource", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeImportSource", "test-source")
+		reconciler := createPopulatorReconciler(pvc, createVolumeImportSource("test-source"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		primePvc := getPrimePvc(reconciler, pvc)
+		Expect(primePvc).ToNot(BeNil())
+		Expect(primePvc.OwnerReferences).To(HaveLen(1))
+		Expect(primePvc.OwnerReferences[0].UID).To(Equal(pvc.UID))
+		Expect(primePvc.Spec.DataSource).To(BeNil())
+		Expect(primePvc.Spec.Resources).To(Equal(pvc.Spec.Resources))
+		Expect(primePvc.Annotations[AnnSource]).To(Equal(SourceHTTP))
+		Expect(primePvc.Annotations[AnnEndpoint]).To(Equal("http://example.com/disk.img"))
+		Expect(primePvc.Annotations[AnnContentType]).To(Equal(string(cdiv1.DataVolumeArchive)))
+		Expect(primePvc.Annotations[AnnPreallocationRequested]).To(Equal("true"))
+		Expect(primePvc.Annotations).To(HaveKey(AnnImmediateBinding))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(PopulateScheduled))
+	})
+
+	It("Should not populate a PVC from a VolumeImportSource without an import source", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeImportSource", "test-source")
+		importSource := createVolumeImportSource("test-source")
+		importSource.Spec.Source = cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{}}
+		reconciler := createPopulatorReconciler(pvc, importSource)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(PopulateSourceInvalid))
+	})
+
+	It("Should create a prime PVC receiving the uploads of the VolumeUploadSource", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeUploadSource", "test-source")
+		uploadSource := &cdiv1.VolumeUploadSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-source",
+				Namespace: "default",
+			},
+		}
+		reconciler := createPopulatorReconciler(pvc, uploadSource)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(IsVolumeUploadSourceTarget(pvc)).To(BeTrue())
+		primePvc := getPrimePvc(reconciler, pvc)
+		Expect(primePvc).ToNot(BeNil())
+		Expect(primePvc.Annotations).To(HaveKey(AnnUploadRequest))
+		Expect(primePvc.Annotations).ToNot(HaveKey(AnnSource))
+		Expect(primePvc.Annotations[AnnPreallocationRequested]).To(Equal("false"))
+	})
+
+	It("Should create a prime PVC host-assisted cloning the source PVC of the VolumeCloneSource", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeCloneSource", "test-source")
+		sourcePvc := createPvc("source", "default", nil, nil)
+		reconciler := createPopulatorReconciler(pvc, sourcePvc, createVolumeCloneSource("test-source", "PersistentVolumeClaim", "source"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		primePvc := getPrimePvc(reconciler, pvc)
+		Expect(primePvc).ToNot(BeNil())
+		Expect(primePvc.Spec.DataSource).To(BeNil())
+		Expect(primePvc.Annotations[AnnCloneRequest]).To(Equal("default/source"))
+		Expect(primePvc.Annotations).ToNot(HaveKey(AnnCloneToken))
+		Expect(primePvc.Annotations[AnnSmartCloneFallbackReason]).To(Equal(SmartCloneNoSnapshotCRDs))
+		Expect(isPopulatorClone(sourcePvc, primePvc)).To(BeTrue())
+
+		By("Waiting for the clone to complete")
+		primePvc.Spec.VolumeName = "test-pv"
+		Expect(isPrimePvcPopulated(primePvc)).To(BeFalse())
+		primePvc.Annotations[AnnCloneOf] = "true"
+		Expect(isPrimePvcPopulated(primePvc)).To(BeTrue())
+	})
+
+	It("Should create a prime PVC cloning the source PVC of the VolumeCloneSource with the CSI driver", func() {
+		scName := "testsc"
+		sc := createStorageClassWithProvisioner(scName, map[string]string{AnnDefaultStorageClass: "true"}, "csi-plugin")
+		pvc := createPopulatedPvc("test-pvc", &scName, "VolumeCloneSource", "test-source")
+		sourcePvc := createPvcInStorageClass("source", "default", &scName, nil, nil, corev1.ClaimBound)
+		reconciler := createPopulatorReconciler(pvc, sc, sourcePvc, createVolumeCloneSource("test-source", "PersistentVolumeClaim", "source"))
+		cr := &cdiv1.CDI{}
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "cdi"}, cr)).To(Succeed())
+		cloneStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+		cr.Spec.CloneStrategyOverride = &cloneStrategy
+		Expect(reconciler.client.Update(context.TODO(), cr)).To(Succeed())
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		primePvc := getPrimePvc(reconciler, pvc)
+		Expect(primePvc).ToNot(BeNil())
+		Expect(primePvc.Spec.DataSource).To(Equal(&corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "source"}))
+		Expect(primePvc.Annotations).ToNot(HaveKey(AnnCloneRequest))
+
+		By("Waiting for the prime PVC to be bound")
+		primePvc.Spec.VolumeName = "test-pv"
+		Expect(isPrimePvcPopulated(primePvc)).To(BeFalse())
+		primePvc.Status.Phase = corev1.ClaimBound
+		Expect(isPrimePvcPopulated(primePvc)).To(BeTrue())
+	})
+
+	It("Should not populate a PVC from a VolumeCloneSource not cloning a PVC", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeCloneSource", "test-source")
+		reconciler := createPopulatorReconciler(pvc, createVolumeCloneSource("test-source", "VolumeSnapshot", "snapshot"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(PopulateSourceInvalid))
+	})
+
+	It("Should wait for the first consumer of a PVC of a WaitForFirstConsumer storage class", func() {
+		storageClass := createStorageClassWithBindingMode("wffc", nil, storagev1.VolumeBindingWaitForFirstConsumer)
+		pvc := createPopulatedPvc("test-pvc", &storageClass.Name, "VolumeImportSource", "test-source")
+		reconciler := createPopulatorReconciler(pvc, storageClass, createVolumeImportSource("test-source"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+
+		pvc.Annotations = map[string]string{AnnSelectedNode: "node01"}
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		primePvc := getPrimePvc(reconciler, pvc)
+		Expect(primePvc).ToNot(BeNil())
+		Expect(primePvc.Annotations[AnnSelectedNode]).To(Equal("node01"))
+	})
+
+	It("Should bind the volume of the populated prime PVC to the PVC", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeImportSource", "test-source")
+		primePvc := createPvc(GetPrimePvcName(pvc), "default", map[string]string{AnnPodPhase: string(corev1.PodSucceeded)}, nil)
+		primePvc.Spec.VolumeName = "test-pv"
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pv"},
+			Spec: corev1.PersistentVolumeSpec{
+				ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: primePvc.Name, UID: primePvc.UID},
+			},
+		}
+		reconciler := createPopulatorReconciler(pvc, primePvc, pv, createVolumeImportSource("test-source"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-pv"}, pv)).To(Succeed())
+		Expect(pv.Spec.ClaimRef.Name).To(Equal(pvc.Name))
+		Expect(pv.Spec.ClaimRef.UID).To(Equal(pvc.UID))
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(PopulateSucceeded))
+	})
+
+	It("Should delete the prime PVC once the PVC is bound", func() {
+		pvc := createPopulatedPvc("test-pvc", nil, "VolumeImportSource", "test-source")
+		pvc.Status.Phase = corev1.ClaimBound
+		primePvc := createPvc(GetPrimePvcName(pvc), "default", map[string]string{AnnPodPhase: string(corev1.PodSucceeded)}, nil)
+		primePvc.Status.Phase = corev1.ClaimLost
+		reconciler := createPopulatorReconciler(pvc, primePvc)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pvc", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPrimePvc(reconciler, pvc)).To(BeNil())
+	})
+})
+
+func createPopulatorReconciler(objects ...runtime.Object) *PopulatorReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+
+	objs = append(objs, MakeEmptyCDICR())
+	objs = append(objs, MakeEmptyCDIConfigSpec(common.ConfigName))
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	rec := record.NewFakeRecorder(1)
+	return &PopulatorReconciler{
+		client:       cl,
+		extClientSet: extfake.NewSimpleClientset(),
+		scheme:       s,
+		log:          populatorLog,
+		recorder:     rec,
+	}
+}
+
+func createPopulatedPvc(name string, storageClassName *string, kind, sourceName string) *corev1.PersistentVolumeClaim {
+	pvc := createPvcInStorageClass(name, "default", storageClassName, nil, nil, corev1.ClaimPending)
+	apiGroup := cdiv1.SchemeGroupVersion.Group
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     kind,
+		Name:     sourceName,
+	}
+	return pvc
+}
+
+func createVolumeImportSource(name string) *cdiv1.VolumeImportSource {
+	preallocation := true
+	return &cdiv1.VolumeImportSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: cdiv1.VolumeImportSourceSpec{
+			Source: cdiv1.DataVolumeSource{
+				HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "http://example.com/disk.img"},
+			},
+			ContentType:   cdiv1.DataVolumeArchive,
+			Preallocation: &preallocation,
+		},
+	}
+}
+
+func createVolumeCloneSource(name, kind, sourceName string) *cdiv1.VolumeCloneSource {
+	return &cdiv1.VolumeCloneSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: cdiv1.VolumeCloneSourceSpec{
+			Source: corev1.TypedLocalObjectReference{
+				Kind: kind,
+				Name: sourceName,
+			},
+		},
+	}
+}
+
+func getPrimePvc(reconciler *PopulatorReconciler, pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	primePvc := &corev1.PersistentVolumeClaim{}
+	if err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: GetPrimePvcName(pvc), Namespace: pvc.Namespace}, primePvc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		Expect(err).ToNot(HaveOccurred())
+	}
+	return primePvc
+}
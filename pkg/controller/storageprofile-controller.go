@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/operator"
+	"kubevirt.io/containerized-data-importer/pkg/storagecapabilities"
+)
+
+// StorageProfileReconciler members
+type StorageProfileReconciler struct {
+	client client.Client
+	// use this for getting any resources not in the install namespace or cluster scope
+	uncachedClient client.Client
+	scheme         *runtime.Scheme
+	log            logr.Logger
+}
+
+// Reconcile the reconciling loop for StorageProfile objects, one per StorageClass
+func (r *StorageProfileReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("StorageProfile", req.NamespacedName)
+	log.Info("reconciling StorageProfile")
+
+	storageClass := &storagev1.StorageClass{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, storageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, r.deleteStorageProfile(req.NamespacedName.Name, log)
+		}
+		return reconcile.Result{}, err
+	} else if storageClass.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, r.deleteStorageProfile(req.NamespacedName.Name, log)
+	}
+
+	return r.reconcileStorageProfile(storageClass, log)
+}
+
+func (r *StorageProfileReconciler) reconcileStorageProfile(sc *storagev1.StorageClass, log logr.Logger) (reconcile.Result, error) {
+	storageProfile, prevStorageProfile, err := r.getStorageProfile(sc)
+	if err != nil {
+		log.Error(err, "Unable to create StorageProfile")
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{}
+	probing, err := r.reconcileProbe(sc, storageProfile)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if probing {
+		result.RequeueAfter = storageProfileProbeInterval
+	}
+
+	// The capabilities of the spec come first, then the static ones of the known provisioners, then the probed ones,
+	// and the ones detected from the CSIDriver last
+	claimPropertySets := r.reconcilePropertySets(sc)
+	cloneStrategy := r.reconcileCloneStrategy(sc)
+	var snapshotClass *string
+	if probeResult := getProbeResult(storageProfile); probeResult != nil {
+		if len(claimPropertySets) == 0 {
+			claimPropertySets = probeResult.ClaimPropertySets
+		}
+		if cloneStrategy == nil {
+			cloneStrategy = probeResult.CloneStrategy
+		}
+	}
+	if len(claimPropertySets) == 0 || cloneStrategy == nil {
+		detectedPropertySets, detectedCloneStrategy, detectedSnapshotClass, err := r.detectCapabilities(sc)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(claimPropertySets) == 0 {
+			claimPropertySets = detectedPropertySets
+		}
+		if cloneStrategy == nil {
+			cloneStrategy = detectedCloneStrategy
+		}
+		snapshotClass = detectedSnapshotClass
+	}
+
+	storageProfile.Status.StorageClass = &sc.Name
+	storageProfile.Status.Provisioner = &sc.Provisioner
+	storageProfile.Status.CloneStrategy = storageProfile.Spec.CloneStrategy
+	if storageProfile.Status.CloneStrategy == nil {
+		storageProfile.Status.CloneStrategy = cloneStrategy
+	}
+	storageProfile.Status.SnapshotClass = storageProfile.Spec.SnapshotClass
+	if storageProfile.Status.SnapshotClass == nil {
+		storageProfile.Status.SnapshotClass = snapshotClass
+	}
+	if len(storageProfile.Spec.ClaimPropertySets) > 0 {
+		storageProfile.Status.ClaimPropertySets = storageProfile.Spec.ClaimPropertySets
+	} else {
+		storageProfile.Status.ClaimPropertySets = claimPropertySets
+	}
+
+	if prevStorageProfile == nil {
+		return result, r.client.Create(context.TODO(), storageProfile)
+	}
+	if !reflect.DeepEqual(prevStorageProfile, storageProfile) {
+		// Updates have happened, update StorageProfile.
+		log.Info("Updating StorageProfile", "StorageProfile.Name", storageProfile.Name, "storageProfile", storageProfile)
+		return result, r.client.Update(context.TODO(), storageProfile)
+	}
+	return result, nil
+}
+
+// getStorageProfile returns the StorageProfile of the StorageClass, a new one if it does not exist yet, along with a
+// copy of the existing one
+func (r *StorageProfileReconciler) getStorageProfile(sc *storagev1.StorageClass) (*cdiv1.StorageProfile, runtime.Object, error) {
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: sc.Name}, storageProfile); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		storageProfile = MakeEmptyStorageProfileSpec(sc.Name)
+		if err := operator.SetOwnerRuntime(r.uncachedClient, storageProfile); err != nil {
+			return nil, nil, err
+		}
+		return storageProfile, nil, nil
+	}
+	return storageProfile, storageProfile.DeepCopyObject(), nil
+}
+
+// reconcilePropertySets returns the claim property sets recommended for the provisioner of the StorageClass
+func (r *StorageProfileReconciler) reconcilePropertySets(sc *storagev1.StorageClass) []cdiv1.ClaimPropertySet {
+	var claimPropertySets []cdiv1.ClaimPropertySet
+	capabilities, found := storagecapabilities.Get(sc)
+	if found {
+		for i := range capabilities {
+			claimPropertySets = append(claimPropertySets, cdiv1.ClaimPropertySet{
+				AccessModes: []v1.PersistentVolumeAccessMode{capabilities[i].AccessMode},
+				VolumeMode:  &capabilities[i].VolumeMode,
+			})
+		}
+	}
+	return claimPropertySets
+}
+
+// reconcileCloneStrategy returns the clone strategy recommended for the provisioner
+func (r *StorageProfileReconciler) reconcileCloneStrategy(sc *storagev1.StorageClass) *cdiv1.CDICloneStrategy {
+	if strategy, found := storagecapabilities.GetCloneStrategy(sc); found {
+		return &strategy
+	}
+	return nil
+}
+
+func (r *StorageProfileReconciler) deleteStorageProfile(name string, log logr.Logger) error {
+	log.Info("Cleaning up StorageProfile that corresponds to deleted StorageClass", "StorageClass.Name", name)
+	storageProfile := &cdiv1.StorageProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if err := r.client.Delete(context.TODO(), storageProfile); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// MakeEmptyStorageProfileSpec creates StorageProfile manifest
+func MakeEmptyStorageProfileSpec(name string) *cdiv1.StorageProfile {
+	return &cdiv1.StorageProfile{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StorageProfile",
+			APIVersion: "cdi.kubevirt.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: "",
+			},
+		},
+	}
+}
+
+// NewStorageProfileController creates a new instance of the StorageProfile controller.
+func NewStorageProfileController(mgr manager.Manager, log logr.Logger) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reconciler := &StorageProfileReconciler{
+		client:         mgr.GetClient(),
+		uncachedClient: uncachedClient,
+		scheme:         mgr.GetScheme(),
+		log:            log.WithName("storageprofile-controller"),
+	}
+
+	storageProfileController, err := controller.New("storageprofile-controller", mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addStorageProfileControllerWatches(mgr, storageProfileController); err != nil {
+		return nil, err
+	}
+
+	log.Info("Initialized StorageProfile controller")
+	return storageProfileController, nil
+}
+
+// addStorageProfileControllerWatches sets up the watches used by the StorageProfile controller.
+func addStorageProfileControllerWatches(mgr manager.Manager, storageProfileController controller.Controller) error {
+	if err := cdiv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := storagev1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := storageProfileController.Watch(&source.Kind{Type: &storagev1.StorageClass{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := storageProfileController.Watch(&source.Kind{Type: &cdiv1.StorageProfile{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	// Watch the probe PVCs, owned by the StorageProfile of the StorageClass they test
+	return storageProfileController.Watch(&source.Kind{Type: &v1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &cdiv1.StorageProfile{},
+		IsController: true,
+	})
+}
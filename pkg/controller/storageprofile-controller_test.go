@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	storageProfileClassName   = "testSC"
+	storageProfileProvisioner = "rbd.csi.ceph.com"
+	storageProfileCSIDriver   = "csi.example.com"
+)
+
+var (
+	storageProfileLog = logf.Log.WithName("storageprofile-controller-test")
+)
+
+var _ = Describe("Storage profile controller reconcile loop", func() {
+	It("Should create a StorageProfile with the capabilities of a known provisioner", func() {
+		reconciler := createStorageProfileReconciler(createStorageClassWithProvisioner(storageProfileClassName, nil, storageProfileProvisioner))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		storageProfile := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*storageProfile.Status.StorageClass).To(Equal(storageProfileClassName))
+		Expect(*storageProfile.Status.Provisioner).To(Equal(storageProfileProvisioner))
+		Expect(*storageProfile.Status.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+		Expect(storageProfile.Status.ClaimPropertySets).To(HaveLen(3))
+		Expect(storageProfile.Status.ClaimPropertySets[0].AccessModes).To(ConsistOf(v1.ReadWriteMany))
+		Expect(*storageProfile.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+	})
+
+	It("Should create an empty StorageProfile for an unknown provisioner", func() {
+		reconciler := createStorageProfileReconciler(createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		storageProfile := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*storageProfile.Status.Provisioner).To(Equal("example.com/unknown"))
+		Expect(storageProfile.Status.CloneStrategy).To(BeNil())
+		Expect(storageProfile.Status.ClaimPropertySets).To(BeEmpty())
+	})
+
+	It("Should apply the overrides of the spec to the status", func() {
+		reconciler := createStorageProfileReconciler(createStorageClassWithProvisioner(storageProfileClassName, nil, storageProfileProvisioner))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		storageProfile := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+		cloneStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)
+		snapshotClass := "snapshot-class"
+		filesystemMode := v1.PersistentVolumeFilesystem
+		storageProfile.Spec = cdiv1.StorageProfileSpec{
+			CloneStrategy: &cloneStrategy,
+			SnapshotClass: &snapshotClass,
+			ClaimPropertySets: []cdiv1.ClaimPropertySet{
+				{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}, VolumeMode: &filesystemMode},
+			},
+		}
+		err = reconciler.client.Update(context.TODO(), storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*storageProfile.Status.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)))
+		Expect(*storageProfile.Status.SnapshotClass).To(Equal(snapshotClass))
+		Expect(storageProfile.Status.ClaimPropertySets).To(Equal(storageProfile.Spec.ClaimPropertySets))
+	})
+
+	It("Should delete the StorageProfile of a deleted StorageClass", func() {
+		reconciler := createStorageProfileReconciler(MakeEmptyStorageProfileSpec(storageProfileClassName))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		storageProfile := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should detect the capabilities of an unknown CSI driver without VolumeSnapshotClass", func() {
+		reconciler := createStorageProfileReconciler(
+			createStorageClassWithProvisioner(storageProfileClassName, nil, storageProfileCSIDriver),
+			&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: storageProfileCSIDriver}},
+		)
+		storageProfile := reconcileStorageProfile(reconciler)
+		Expect(*storageProfile.Status.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)))
+		Expect(storageProfile.Status.SnapshotClass).To(BeNil())
+		Expect(storageProfile.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(storageProfile.Status.ClaimPropertySets[0].AccessModes).To(ConsistOf(v1.ReadWriteOnce))
+		Expect(*storageProfile.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+	})
+
+	It("Should detect snapshot clones of an unknown CSI driver with the default VolumeSnapshotClass", func() {
+		reconciler := createStorageProfileReconciler(
+			createStorageClassWithProvisioner(storageProfileClassName, nil, storageProfileCSIDriver),
+			&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: storageProfileCSIDriver}},
+			&snapshotv1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "other-snapshots"}, Driver: "other.example.com"},
+			&snapshotv1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "a-snapshots"}, Driver: storageProfileCSIDriver},
+			&snapshotv1.VolumeSnapshotClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default-snapshots",
+					Annotations: map[string]string{annDefaultSnapshotClass: "true"},
+				},
+				Driver: storageProfileCSIDriver,
+			},
+		)
+		storageProfile := reconcileStorageProfile(reconciler)
+		Expect(*storageProfile.Status.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)))
+		Expect(*storageProfile.Status.SnapshotClass).To(Equal("default-snapshots"))
+		Expect(storageProfile.Status.ClaimPropertySets).To(HaveLen(1))
+	})
+
+	It("Should not detect the capabilities of an ephemeral only CSI driver", func() {
+		reconciler := createStorageProfileReconciler(
+			createStorageClassWithProvisioner(storageProfileClassName, nil, storageProfileCSIDriver),
+			&storagev1.CSIDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: storageProfileCSIDriver},
+				Spec: storagev1.CSIDriverSpec{
+					VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecycleEphemeral},
+				},
+			},
+		)
+		storageProfile := reconcileStorageProfile(reconciler)
+		Expect(storageProfile.Status.CloneStrategy).To(BeNil())
+		Expect(storageProfile.Status.ClaimPropertySets).To(BeEmpty())
+	})
+
+	It("Should create the probe PVCs when a probe is requested", func() {
+		reconciler := createStorageProfileReconciler(
+			createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"),
+			createProbedStorageProfile(),
+		)
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(storageProfileProbeInterval))
+
+		pvcs, err := reconciler.getProbePVCs(createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvcs).To(HaveLen(len(storageProfileProbeModes)))
+		for _, pvc := range pvcs {
+			Expect(pvc.Spec.StorageClassName).To(Equal(&[]string{storageProfileClassName}[0]))
+			Expect(pvc.Spec.Resources.Requests[v1.ResourceStorage]).To(Equal(storageProfileProbeSize))
+			Expect(pvc.OwnerReferences).To(HaveLen(1))
+			Expect(pvc.OwnerReferences[0].Kind).To(Equal("StorageProfile"))
+		}
+	})
+
+	It("Should record the capabilities found by the probe", func() {
+		reconciler := createStorageProfileReconciler(
+			createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"),
+			createProbedStorageProfile(),
+		)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		// RWX block and RWO filesystem get bound, the others stay pending past the timeout
+		setProbePVCs(reconciler, func(pvc *v1.PersistentVolumeClaim) {
+			pvc.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * storageProfileProbeTimeout))
+			mode := pvc.Annotations[annStorageProfileProbeMode]
+			if mode == "ReadWriteMany-Block" || mode == "ReadWriteOnce-Filesystem" {
+				pvc.Status.Phase = v1.ClaimBound
+			}
+		})
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(storageProfileProbeInterval))
+
+		// The CSI clone of the RWX block PVC gets bound
+		pvcs, err := reconciler.getProbePVCs(createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"))
+		Expect(err).ToNot(HaveOccurred())
+		clone, found := pvcs[storageProfileProbeClone]
+		Expect(found).To(BeTrue())
+		Expect(clone.Spec.DataSource.Name).To(Equal(pvcs["ReadWriteMany-Block"].Name))
+		setProbePVCs(reconciler, func(pvc *v1.PersistentVolumeClaim) {
+			if pvc.Annotations[annStorageProfileProbeMode] == storageProfileProbeClone {
+				pvc.Status.Phase = v1.ClaimBound
+			}
+		})
+
+		storageProfile := reconcileStorageProfile(reconciler)
+		Expect(storageProfile.Annotations[AnnStorageProfileProbe]).To(Equal(storageProfileProbeCompleted))
+		Expect(*storageProfile.Status.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+		Expect(storageProfile.Status.ClaimPropertySets).To(HaveLen(2))
+		Expect(storageProfile.Status.ClaimPropertySets[0].AccessModes).To(ConsistOf(v1.ReadWriteMany))
+		Expect(*storageProfile.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(storageProfile.Status.ClaimPropertySets[1].AccessModes).To(ConsistOf(v1.ReadWriteOnce))
+		Expect(*storageProfile.Status.ClaimPropertySets[1].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+
+		pvcs, err = reconciler.getProbePVCs(createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvcs).To(BeEmpty())
+	})
+
+	It("Should not probe a WaitForFirstConsumer StorageClass", func() {
+		sc := createStorageClassWithProvisioner(storageProfileClassName, nil, "example.com/unknown")
+		waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+		sc.VolumeBindingMode = &waitForFirstConsumer
+		reconciler := createStorageProfileReconciler(sc, createProbedStorageProfile())
+
+		storageProfile := reconcileStorageProfile(reconciler)
+		Expect(storageProfile.Annotations[AnnStorageProfileProbe]).To(Equal(storageProfileProbeCompleted))
+		Expect(storageProfile.Status.ClaimPropertySets).To(BeEmpty())
+		pvcs, err := reconciler.getProbePVCs(sc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvcs).To(BeEmpty())
+	})
+})
+
+func reconcileStorageProfile(reconciler *StorageProfileReconciler) *cdiv1.StorageProfile {
+	_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: storageProfileClassName}})
+	Expect(err).ToNot(HaveOccurred())
+
+	storageProfile := &cdiv1.StorageProfile{}
+	err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: storageProfileClassName}, storageProfile)
+	Expect(err).ToNot(HaveOccurred())
+	return storageProfile
+}
+
+func createProbedStorageProfile() *cdiv1.StorageProfile {
+	storageProfile := MakeEmptyStorageProfileSpec(storageProfileClassName)
+	storageProfile.UID = "storage-profile-uid"
+	storageProfile.Annotations = map[string]string{AnnStorageProfileProbe: storageProfileProbeRequested}
+	return storageProfile
+}
+
+func setProbePVCs(reconciler *StorageProfileReconciler, update func(pvc *v1.PersistentVolumeClaim)) {
+	pvcList := &v1.PersistentVolumeClaimList{}
+	Expect(reconciler.client.List(context.TODO(), pvcList, client.MatchingLabels{labelStorageProfileProbe: storageProfileClassName})).To(Succeed())
+	for i := range pvcList.Items {
+		update(&pvcList.Items[i])
+		Expect(reconciler.client.Update(context.TODO(), &pvcList.Items[i])).To(Succeed())
+	}
+}
+
+func createStorageProfileReconciler(objects ...runtime.Object) *StorageProfileReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	storagev1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	return &StorageProfileReconciler{
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            storageProfileLog,
+	}
+}
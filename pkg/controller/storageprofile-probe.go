@@ -0,0 +1,336 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// AnnStorageProfileProbe requests a probe of the capabilities of the StorageClass with test PVCs when set to "true"
+	// on the StorageProfile, the controller sets it to "completed" once the probe is done
+	AnnStorageProfileProbe = "cdi.kubevirt.io/storage.profile.probe"
+	// AnnStorageProfileProbeResult holds the capabilities found by the last probe of the StorageClass
+	AnnStorageProfileProbeResult = "cdi.kubevirt.io/storage.profile.probe.result"
+
+	storageProfileProbeRequested = "true"
+	storageProfileProbeCompleted = "completed"
+
+	// annStorageProfileProbeMode is the access and volume mode tested by a probe PVC, or clone for the csi-clone probe
+	annStorageProfileProbeMode = "cdi.kubevirt.io/storage.profile.probe.mode"
+	// labelStorageProfileProbe labels the probe PVCs with the StorageClass they test
+	labelStorageProfileProbe = "cdi.kubevirt.io/storage.profile.probe"
+
+	storageProfileProbeClone = "clone"
+
+	// annDefaultSnapshotClass is the annotation indicating that a VolumeSnapshotClass is the default one of its driver
+	annDefaultSnapshotClass = "snapshot.storage.kubernetes.io/is-default-class"
+)
+
+var (
+	// storageProfileProbeTimeout is how long a probe PVC may stay pending before its mode is deemed unsupported
+	storageProfileProbeTimeout = 2 * time.Minute
+	// storageProfileProbeInterval is how often the probe PVCs are checked
+	storageProfileProbeInterval = 10 * time.Second
+	// storageProfileProbeSize is the size requested by the probe PVCs, the provisioners round it up to their minimum
+	storageProfileProbeSize = resource.MustParse("1Mi")
+
+	// storageProfileProbeModes are the modes tested by the probe, in the order they are recommended
+	storageProfileProbeModes = []cdiv1.ClaimPropertySet{
+		newClaimPropertySet(corev1.ReadWriteMany, corev1.PersistentVolumeBlock),
+		newClaimPropertySet(corev1.ReadWriteMany, corev1.PersistentVolumeFilesystem),
+		newClaimPropertySet(corev1.ReadWriteOnce, corev1.PersistentVolumeBlock),
+		newClaimPropertySet(corev1.ReadWriteOnce, corev1.PersistentVolumeFilesystem),
+	}
+)
+
+// storageProfileProbeResult is the result of the probe of a StorageClass, kept in an annotation of its StorageProfile
+type storageProfileProbeResult struct {
+	ClaimPropertySets []cdiv1.ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	CloneStrategy     *cdiv1.CDICloneStrategy  `json:"cloneStrategy,omitempty"`
+}
+
+func newClaimPropertySet(accessMode corev1.PersistentVolumeAccessMode, volumeMode corev1.PersistentVolumeMode) cdiv1.ClaimPropertySet {
+	return cdiv1.ClaimPropertySet{
+		AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+		VolumeMode:  &volumeMode,
+	}
+}
+
+func claimPropertySetMode(claimPropertySet cdiv1.ClaimPropertySet) string {
+	return string(claimPropertySet.AccessModes[0]) + "-" + string(*claimPropertySet.VolumeMode)
+}
+
+// detectCapabilities guesses the capabilities of a provisioner missing from the static list from its CSIDriver and
+// VolumeSnapshotClasses. A CSI driver is expected to provide ReadWriteOnce filesystem volumes, and to clone them with
+// snapshots if it has a VolumeSnapshotClass. Nothing is detected for the other provisioners.
+func (r *StorageProfileReconciler) detectCapabilities(sc *storagev1.StorageClass) ([]cdiv1.ClaimPropertySet, *cdiv1.CDICloneStrategy, *string, error) {
+	csiDriver := &storagev1.CSIDriver{}
+	if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Name: sc.Provisioner}, csiDriver); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil, nil, nil
+		}
+		return nil, nil, nil, err
+	}
+	if !supportsPersistentVolumes(csiDriver) {
+		return nil, nil, nil, nil
+	}
+
+	claimPropertySets := []cdiv1.ClaimPropertySet{newClaimPropertySet(corev1.ReadWriteOnce, corev1.PersistentVolumeFilesystem)}
+	snapshotClass, err := r.findSnapshotClass(sc.Provisioner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cloneStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)
+	if snapshotClass != nil {
+		cloneStrategy = cdiv1.CloneStrategySnapshot
+	}
+	return claimPropertySets, &cloneStrategy, snapshotClass, nil
+}
+
+// supportsPersistentVolumes returns false if the CSI driver only provides ephemeral inline volumes
+func supportsPersistentVolumes(csiDriver *storagev1.CSIDriver) bool {
+	if len(csiDriver.Spec.VolumeLifecycleModes) == 0 {
+		return true
+	}
+	for _, mode := range csiDriver.Spec.VolumeLifecycleModes {
+		if mode == storagev1.VolumeLifecyclePersistent {
+			return true
+		}
+	}
+	return false
+}
+
+// findSnapshotClass returns the name of the VolumeSnapshotClass of the driver, the default one if there are several,
+// nil if there is none or the snapshot API is not installed
+func (r *StorageProfileReconciler) findSnapshotClass(driver string) (*string, error) {
+	snapshotClasses := &snapshotv1.VolumeSnapshotClassList{}
+	if err := r.uncachedClient.List(context.TODO(), snapshotClasses); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var found *string
+	for i := range snapshotClasses.Items {
+		snapshotClass := &snapshotClasses.Items[i]
+		if snapshotClass.Driver != driver {
+			continue
+		}
+		if snapshotClass.Annotations[annDefaultSnapshotClass] == "true" {
+			return &snapshotClass.Name, nil
+		}
+		if found == nil {
+			found = &snapshotClass.Name
+		}
+	}
+	return found, nil
+}
+
+// getProbeResult returns the result of the last probe of the StorageClass of the StorageProfile, nil if it was not probed
+func getProbeResult(storageProfile *cdiv1.StorageProfile) *storageProfileProbeResult {
+	value, ok := storageProfile.Annotations[AnnStorageProfileProbeResult]
+	if !ok {
+		return nil
+	}
+	result := &storageProfileProbeResult{}
+	if err := json.Unmarshal([]byte(value), result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// reconcileProbe runs the probe requested on the StorageProfile, and records its result in the annotations of the
+// StorageProfile once done. It returns true while the probe runs.
+func (r *StorageProfileReconciler) reconcileProbe(sc *storagev1.StorageClass, storageProfile *cdiv1.StorageProfile) (bool, error) {
+	if storageProfile.Annotations[AnnStorageProfileProbe] != storageProfileProbeRequested {
+		return false, nil
+	}
+
+	result := &storageProfileProbeResult{}
+	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		// The PVCs are not provisioned until a pod uses them, the probe would only time out
+		r.log.Info("Not probing the capabilities of a WaitForFirstConsumer StorageClass", "StorageClass.Name", sc.Name)
+	} else {
+		done, err := r.probeCapabilities(sc, storageProfile, result)
+		if err != nil || !done {
+			return !done, err
+		}
+	}
+
+	value, err := json.Marshal(result)
+	if err != nil {
+		return false, err
+	}
+	storageProfile.Annotations[AnnStorageProfileProbeResult] = string(value)
+	storageProfile.Annotations[AnnStorageProfileProbe] = storageProfileProbeCompleted
+	return false, nil
+}
+
+// probeCapabilities creates a small probe PVC in the CDI namespace for each mode, and a CSI clone of the first bound one.
+// A mode is supported once its PVC is bound, and deemed unsupported when the PVC is still pending after the timeout.
+// When all the probe PVCs are decided, they are deleted and the result is filled.
+func (r *StorageProfileReconciler) probeCapabilities(sc *storagev1.StorageClass, storageProfile *cdiv1.StorageProfile, result *storageProfileProbeResult) (bool, error) {
+	pvcs, err := r.getProbePVCs(sc)
+	if err != nil {
+		return false, err
+	}
+
+	done := true
+	var cloneSource *corev1.PersistentVolumeClaim
+	for _, mode := range storageProfileProbeModes {
+		pvc, found := pvcs[claimPropertySetMode(mode)]
+		if !found {
+			if err := r.createProbePVC(sc, storageProfile, mode, nil); err != nil {
+				return false, err
+			}
+			done = false
+			continue
+		}
+		supported, decided := probePVCResult(pvc)
+		if !decided {
+			done = false
+		} else if supported {
+			result.ClaimPropertySets = append(result.ClaimPropertySets, mode)
+			if cloneSource == nil {
+				cloneSource = pvc
+			}
+		}
+	}
+	if !done {
+		return false, nil
+	}
+
+	copyStrategy := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyHostAssisted)
+	result.CloneStrategy = &copyStrategy
+	if cloneSource != nil {
+		pvc, found := pvcs[storageProfileProbeClone]
+		if !found {
+			return false, r.createProbePVC(sc, storageProfile, newClaimPropertySet(cloneSource.Spec.AccessModes[0], *cloneSource.Spec.VolumeMode), cloneSource)
+		}
+		supported, decided := probePVCResult(pvc)
+		if !decided {
+			return false, nil
+		}
+		if supported {
+			csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+			result.CloneStrategy = &csiClone
+		}
+	}
+
+	for _, pvc := range pvcs {
+		if err := r.client.Delete(context.TODO(), pvc); err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	r.log.Info("Probed the capabilities of the StorageClass", "StorageClass.Name", sc.Name, "claimPropertySets", len(result.ClaimPropertySets), "cloneStrategy", *result.CloneStrategy)
+	return true, nil
+}
+
+// probePVCResult returns whether the probe PVC is bound, decided is false while it is pending before the timeout
+func probePVCResult(pvc *corev1.PersistentVolumeClaim) (supported, decided bool) {
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return true, true
+	}
+	return false, time.Since(pvc.CreationTimestamp.Time) > storageProfileProbeTimeout
+}
+
+// getProbePVCs returns the probe PVCs of the StorageClass by mode
+func (r *StorageProfileReconciler) getProbePVCs(sc *storagev1.StorageClass) (map[string]*corev1.PersistentVolumeClaim, error) {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := r.client.List(context.TODO(), pvcList, client.InNamespace(util.GetNamespace()), client.MatchingLabels{
+		labelStorageProfileProbe: naming.GetLabelNameFromResourceName(sc.Name),
+	}); err != nil {
+		return nil, err
+	}
+	pvcs := make(map[string]*corev1.PersistentVolumeClaim)
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != sc.Name {
+			continue
+		}
+		pvcs[pvc.Annotations[annStorageProfileProbeMode]] = pvc
+	}
+	return pvcs, nil
+}
+
+// createProbePVC creates a probe PVC of the mode, or a CSI clone of the source PVC if set, owned by the StorageProfile
+func (r *StorageProfileReconciler) createProbePVC(sc *storagev1.StorageClass, storageProfile *cdiv1.StorageProfile, mode cdiv1.ClaimPropertySet, source *corev1.PersistentVolumeClaim) error {
+	probeMode := claimPropertySetMode(mode)
+	size := storageProfileProbeSize
+	var dataSource *corev1.TypedLocalObjectReference
+	if source != nil {
+		probeMode = storageProfileProbeClone
+		size = source.Spec.Resources.Requests[corev1.ResourceStorage]
+		dataSource = &corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: source.Name,
+		}
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.GetResourceName("cdi-probe-"+sc.Name, strings.ToLower(probeMode)),
+			Namespace: util.GetNamespace(),
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: "",
+				labelStorageProfileProbe: naming.GetLabelNameFromResourceName(sc.Name),
+			},
+			Annotations: map[string]string{
+				annStorageProfileProbeMode: probeMode,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      mode.AccessModes,
+			VolumeMode:       mode.VolumeMode,
+			StorageClassName: &sc.Name,
+			DataSource:       dataSource,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(storageProfile, pvc, r.scheme); err != nil {
+		return err
+	}
+	r.log.V(1).Info("Creating probe PVC", "StorageClass.Name", sc.Name, "mode", probeMode)
+	if err := r.client.Create(context.TODO(), pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
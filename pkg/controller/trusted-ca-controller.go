@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// TrustedCAReconciler members
+type TrustedCAReconciler struct {
+	client       client.Client
+	log          logr.Logger
+	cdiNamespace string
+}
+
+// NewTrustedCAController creates a new instance of the trusted CA controller. The controller keeps the copies of the
+// cluster-wide trusted CA bundle in the namespaces of the CDI pods up to date.
+func NewTrustedCAController(mgr manager.Manager, log logr.Logger, cdiNamespace string) (controller.Controller, error) {
+	// ConfigMaps are not cached cluster-wide, the copies are read with an uncached client.
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reconciler := &TrustedCAReconciler{
+		client:       uncachedClient,
+		log:          log.WithName("trusted-ca-controller"),
+		cdiNamespace: cdiNamespace,
+	}
+	trustedCAController, err := controller.New("trusted-ca-controller", mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addTrustedCAControllerWatches(mgr, trustedCAController, cdiNamespace); err != nil {
+		return nil, err
+	}
+	return trustedCAController, nil
+}
+
+func addTrustedCAControllerWatches(mgr manager.Manager, trustedCAController controller.Controller, cdiNamespace string) error {
+	// Only watch the CDI namespace, to avoid caching the ConfigMaps of the whole cluster.
+	namespaceCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: cdiNamespace,
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(namespaceCache); err != nil {
+		return err
+	}
+	isTrustedCA := func(meta metav1.Object) bool {
+		return meta.GetName() == common.TrustedCAConfigMap
+	}
+	return trustedCAController.Watch(source.NewKindWithCache(&corev1.ConfigMap{}, namespaceCache), &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isTrustedCA(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isTrustedCA(e.MetaNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isTrustedCA(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) bool { return isTrustedCA(e.Meta) },
+	})
+}
+
+// Reconcile the reconcile loop for the trusted CA ConfigMap.
+func (r *TrustedCAReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("ConfigMap", req.NamespacedName)
+	log.V(1).Info("reconciling trusted CA copies")
+
+	trustedCA := &corev1.ConfigMap{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: r.cdiNamespace}, trustedCA); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		trustedCA = nil
+	}
+
+	// The controller is only allowed to list the ConfigMaps named like the bundle outside of the CDI namespace
+	copies := &corev1.ConfigMapList{}
+	if err := r.client.List(context.TODO(), copies, client.MatchingLabels(trustedCALabels()), client.MatchingFields{"metadata.name": common.TrustedCAConfigMap}); err != nil {
+		return reconcile.Result{}, err
+	}
+	for i := range copies.Items {
+		cm := &copies.Items[i]
+		if cm.Namespace == r.cdiNamespace || cm.Name != common.TrustedCAConfigMap {
+			continue
+		}
+		if trustedCA == nil {
+			log.V(1).Info("Deleting trusted CA copy", "namespace", cm.Namespace)
+			if err := r.client.Delete(context.TODO(), cm); err != nil && !k8serrors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			continue
+		}
+		if reflect.DeepEqual(cm.Data, trustedCA.Data) {
+			continue
+		}
+		log.V(1).Info("Updating trusted CA copy", "namespace", cm.Namespace)
+		cm.Data = trustedCA.Data
+		if err := r.client.Update(context.TODO(), cm); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func trustedCALabels() map[string]string {
+	return map[string]string{
+		common.CDILabelKey:       common.CDILabelValue,
+		common.CDIComponentLabel: common.TrustedCAConfigMap,
+	}
+}
+
+// SyncTrustedCA copies the cluster-wide trusted CA bundle to the namespace, so it can be mounted in the pods of the
+// namespace. Returns the name of the ConfigMap to mount, or "" if no trusted CA bundle is configured.
+func SyncTrustedCA(c client.Client, namespace string) (string, error) {
+	cdiNamespace := util.GetNamespace()
+	trustedCA := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: cdiNamespace}, trustedCA); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if namespace == cdiNamespace {
+		return trustedCA.Name, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: namespace}, cm)
+	if k8serrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.TrustedCAConfigMap,
+				Namespace: namespace,
+				Labels:    trustedCALabels(),
+			},
+			Data: trustedCA.Data,
+		}
+		if err := c.Create(context.TODO(), cm); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return "", err
+		}
+		return cm.Name, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	// Leave ConfigMaps that were not created by CDI alone
+	if cm.Labels[common.CDIComponentLabel] == common.TrustedCAConfigMap && !reflect.DeepEqual(cm.Data, trustedCA.Data) {
+		cm.Data = trustedCA.Data
+		if err := c.Update(context.TODO(), cm); err != nil {
+			return "", err
+		}
+	}
+	return cm.Name, nil
+}
+
+// addTrustedCAVolume mounts the copy of the trusted CA bundle in the first container of the pod, the container finds
+// it in the directory set in the ImporterTrustedCADirVar env var.
+func addTrustedCAVolume(pod *corev1.Pod, configMapName string) {
+	// The copy may be removed while the pod is running, and the kubelet refreshes the content when the bundle is rotated
+	optional := true
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      TrustedCAVolName,
+		MountPath: common.ImporterTrustedCADir,
+		ReadOnly:  true,
+	})
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: TrustedCAVolName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+				Optional: &optional,
+			},
+		},
+	})
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+var (
+	trustedCALog = logf.Log.WithName("trusted-ca-controller-test")
+)
+
+var _ = Describe("SyncTrustedCA", func() {
+	It("Should return nothing if there is no trusted CA bundle", func() {
+		name, err := SyncTrustedCA(createClient(), "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(BeEmpty())
+	})
+
+	It("Should copy the trusted CA bundle to the namespace", func() {
+		client := createClient(createTrustedCAConfigMap(util.GetNamespace(), "bundle", nil))
+		name, err := SyncTrustedCA(client, "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal(common.TrustedCAConfigMap))
+		cm := &corev1.ConfigMap{}
+		err = client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[common.TrustedCABundleKey]).To(Equal("bundle"))
+		Expect(cm.Labels[common.CDIComponentLabel]).To(Equal(common.TrustedCAConfigMap))
+	})
+
+	It("Should update an outdated copy", func() {
+		client := createClient(createTrustedCAConfigMap(util.GetNamespace(), "rotated", nil), createTrustedCAConfigMap("default", "bundle", trustedCALabels()))
+		_, err := SyncTrustedCA(client, "default")
+		Expect(err).ToNot(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		err = client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[common.TrustedCABundleKey]).To(Equal("rotated"))
+	})
+
+	It("Should not modify a ConfigMap with the same name that was not created by CDI", func() {
+		client := createClient(createTrustedCAConfigMap(util.GetNamespace(), "bundle", nil), createTrustedCAConfigMap("default", "user", nil))
+		_, err := SyncTrustedCA(client, "default")
+		Expect(err).ToNot(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		err = client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[common.TrustedCABundleKey]).To(Equal("user"))
+	})
+})
+
+var _ = Describe("Trusted CA reconcile loop", func() {
+	It("Should update the copies when the trusted CA bundle is rotated", func() {
+		reconciler := createTrustedCAReconciler(createTrustedCAConfigMap(util.GetNamespace(), "rotated", nil), createTrustedCAConfigMap("default", "bundle", trustedCALabels()))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: util.GetNamespace()}})
+		Expect(err).ToNot(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[common.TrustedCABundleKey]).To(Equal("rotated"))
+	})
+
+	It("Should delete the copies when the trusted CA bundle is removed", func() {
+		reconciler := createTrustedCAReconciler(createTrustedCAConfigMap("default", "bundle", trustedCALabels()))
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: util.GetNamespace()}})
+		Expect(err).ToNot(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.TrustedCAConfigMap, Namespace: "default"}, cm)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+func createTrustedCAReconciler(objects ...runtime.Object) *TrustedCAReconciler {
+	return &TrustedCAReconciler{
+		client:       createClient(objects...),
+		log:          trustedCALog,
+		cdiNamespace: util.GetNamespace(),
+	}
+}
+
+func createTrustedCAConfigMap(namespace, bundle string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.TrustedCAConfigMap,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string]string{
+			common.TrustedCABundleKey: bundle,
+		},
+	}
+}
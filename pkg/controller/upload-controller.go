@@ -18,7 +18,9 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -37,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -46,10 +49,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -62,22 +67,43 @@ const (
 	// AnnUploadPod name of the upload pod
 	AnnUploadPod = "cdi.kubevirt.io/storage.uploadPodName"
 
+	// AnnDownloadRequest marks that the disk of a PVC should be made available for download
+	AnnDownloadRequest = "cdi.kubevirt.io/storage.download.source"
+
+	// AnnDownloadPodReady tells whether the download pod of a PVC is ready to serve downloads
+	AnnDownloadPodReady = "cdi.kubevirt.io/storage.download.podReady"
+
+	// AnnUploadStatus is the json encoded progress of the upload to a PVC
+	AnnUploadStatus = "cdi.kubevirt.io/storage.upload.status"
+
 	annCreatedByUpload = "cdi.kubevirt.io/storage.createdByUploadController"
 
 	uploadServerClientName = "client.upload-server.cdi.kubevirt.io"
 
 	uploadServerCertDuration = 365 * 24 * time.Hour
 
+	// uploadServerStatusPort is the healthz port of upload servers, where they report the progress of uploads
+	uploadServerStatusPort = 8080
+
+	uploadStatusPollInterval = 2 * time.Second
+
 	// UploadSucceededPVC provides a const to indicate an import to the PVC failed
 	UploadSucceededPVC = "UploadSucceeded"
 
 	// UploadTargetInUse is reason for event created when an upload pvc is in use
 	UploadTargetInUse = "UploadTargetInUse"
+
+	// DownloadSourceInUse is reason for event created when a download pvc is in use by a pod writing to it
+	DownloadSourceInUse = "DownloadSourceInUse"
 )
 
+// may be overridden in tests
+var uploadStatusFunc = getUploadStatusFromPod
+
 // UploadReconciler members
 type UploadReconciler struct {
 	client                 client.Client
+	uncachedClient         client.Client
 	recorder               record.EventRecorder
 	scheme                 *runtime.Scheme
 	log                    logr.Logger
@@ -99,6 +125,8 @@ type UploadPodArgs struct {
 	FilesystemOverhead              string
 	ServerCert, ServerKey, ClientCA []byte
 	Preallocation                   string
+	TLSConfig                       *cdiv1.TLSConfig
+	TrustedCAConfigMap              string
 }
 
 // Reconcile the reconcile loop for the CDIConfig object.
@@ -137,7 +165,8 @@ func (r *UploadReconciler) Reconcile(req reconcile.Request) (reconcile.Result, e
 		if err := r.cleanup(pvc); err != nil {
 			return reconcile.Result{}, err
 		}
-		return reconcile.Result{}, nil
+		// the disk of the PVC can be downloaded once it is not being uploaded or cloned to
+		return r.reconcileDownload(log, pvc)
 	}
 
 	log.Info("Calling Upload reconcile PVC")
@@ -258,6 +287,13 @@ func (r *UploadReconciler) reconcilePVC(log logr.Logger, pvc *corev1.PersistentV
 	}
 	setConditionFromPodWithPrefix(anno, AnnRunningCondition, pod)
 
+	result := reconcile.Result{}
+	if !isCloneTarget && podPhase == corev1.PodRunning && isPodReady(pod) {
+		// Poll the progress of the upload until the pod completes
+		r.updateUploadStatus(anno, pod)
+		result.RequeueAfter = uploadStatusPollInterval
+	}
+
 	if !reflect.DeepEqual(pvc, pvcCopy) {
 		if err := r.updatePVC(pvcCopy); err != nil {
 			return reconcile.Result{}, err
@@ -268,9 +304,199 @@ func (r *UploadReconciler) reconcilePVC(log logr.Logger, pvc *corev1.PersistentV
 		}
 	}
 
+	return result, nil
+}
+
+func (r *UploadReconciler) reconcileDownload(log logr.Logger, pvc *corev1.PersistentVolumeClaim) (reconcile.Result, error) {
+	_, isDownload := pvc.Annotations[AnnDownloadRequest]
+	if !isDownload || pvc.DeletionTimestamp != nil || !isBound(pvc, log) {
+		if _, ok := pvc.Annotations[AnnDownloadPodReady]; ok {
+			return reconcile.Result{}, r.cleanupDownload(pvc)
+		}
+		return reconcile.Result{}, nil
+	}
+	populated, err := IsPopulated(pvc, r.client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !populated {
+		log.V(1).Info("PVC not populated yet, not serving downloads")
+		return reconcile.Result{}, nil
+	}
+
+	podName := createDownloadResourceName(pvc.Name)
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: pvc.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "error getting download pod %s/%s", pvc.Namespace, podName)
+		}
+		// Readers do not change the disk while it is downloaded
+		podsUsingPVC, err := getPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(podsUsingPVC) > 0 {
+			for _, pod := range podsUsingPVC {
+				r.log.V(1).Info("can't create download pod, pvc in use by other pod",
+					"namespace", pvc.Namespace, "name", pvc.Name, "pod", pod.Name)
+				r.recorder.Eventf(pvc, corev1.EventTypeWarning, DownloadSourceInUse,
+					"pod %s/%s using PersistentVolumeClaim %s", pod.Namespace, pod.Name, pvc.Name)
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if pod, err = r.createDownloadPodForPvc(pvc, podName); err != nil {
+			return reconcile.Result{}, err
+		}
+	} else if !metav1.IsControlledBy(pod, pvc) {
+		return reconcile.Result{}, errors.Errorf("%s pod not controlled by pvc %s", podName, pvc.Name)
+	}
+
+	if _, err := r.getOrCreateUploadService(pvc, naming.GetServiceNameFromResourceName(podName)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	ready := strconv.FormatBool(isPodReady(pod))
+	if pvc.Annotations[AnnDownloadPodReady] != ready {
+		pvcCopy := pvc.DeepCopy()
+		pvcCopy.Annotations[AnnDownloadPodReady] = ready
+		if err := r.updatePVC(pvcCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 	return reconcile.Result{}, nil
 }
 
+func (r *UploadReconciler) updateUploadStatus(anno map[string]string, pod *corev1.Pod) {
+	status, err := uploadStatusFunc(pod)
+	if err != nil {
+		r.log.V(1).Info("Unable to get the upload status", "pod", pod.Name, "error", err.Error())
+		return
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		r.log.Error(err, "Unable to encode the upload status")
+		return
+	}
+	anno[AnnUploadStatus] = string(data)
+}
+
+func getUploadStatusFromPod(pod *corev1.Pod) (*uploadv1.UploadStatus, error) {
+	if pod.Status.PodIP == "" {
+		return nil, errors.Errorf("pod %s has no IP", pod.Name)
+	}
+	url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, uploadServerStatusPort, common.UploadStatusPath)
+	resp, err := buildHTTPClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	status := &uploadv1.UploadStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetUploadStatus returns the progress of the upload to the PVC, as last reported by its upload server
+func GetUploadStatus(pvc *corev1.PersistentVolumeClaim) *uploadv1.UploadStatus {
+	status := &uploadv1.UploadStatus{}
+	if data, ok := pvc.Annotations[AnnUploadStatus]; ok {
+		if err := json.Unmarshal([]byte(data), status); err != nil {
+			klog.Errorf("Invalid upload status of PVC %s/%s: %v", pvc.Namespace, pvc.Name, err)
+		}
+	}
+	switch corev1.PodPhase(pvc.Annotations[AnnPodPhase]) {
+	case corev1.PodSucceeded:
+		status.Phase = uploadv1.UploadPhaseSucceeded
+		status.Progress = "100.00%"
+	case corev1.PodFailed:
+		status.Phase = uploadv1.UploadPhaseFailed
+	}
+	if status.Phase == "" {
+		status.Phase = uploadv1.UploadPhasePending
+	}
+	return status
+}
+
+func (r *UploadReconciler) cleanupDownload(pvc *corev1.PersistentVolumeClaim) error {
+	resourceName := createDownloadResourceName(pvc.Name)
+	if err := r.deleteService(pvc.Namespace, naming.GetServiceNameFromResourceName(resourceName)); err != nil {
+		return err
+	}
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: pvc.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+	} else if pod.DeletionTimestamp == nil {
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	pvcCopy := pvc.DeepCopy()
+	delete(pvcCopy.Annotations, AnnDownloadPodReady)
+	return r.updatePVC(pvcCopy)
+}
+
+func (r *UploadReconciler) createDownloadPodForPvc(pvc *corev1.PersistentVolumeClaim, podName string) (*corev1.Pod, error) {
+	serverCert, serverKey, err := r.serverCertGenerator.MakeServerCert(pvc.Namespace, naming.GetServiceNameFromResourceName(podName), uploadServerCertDuration)
+	if err != nil {
+		return nil, err
+	}
+	clientCA, err := r.clientCAFetcher.BundleBytes()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := GetTLSConfig(r.client)
+	if err != nil {
+		return nil, err
+	}
+	podResourceRequirements, err := GetPodResourceRequirements(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+	workloadNodePlacement, err := GetPodNodePlacement(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityClassName, err := GetPriorityClassName(r.client, pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	args := UploadPodArgs{
+		Name:       podName,
+		PVC:        pvc,
+		ClientName: uploadServerClientName,
+		ServerCert: serverCert,
+		ServerKey:  serverKey,
+		ClientCA:   clientCA,
+		TLSConfig:  tlsConfig,
+	}
+	podSecurity, err := GetPodSecurityConfig(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePullSecrets, err := GetImagePullSecrets(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := r.makeDownloadPodSpec(args, podResourceRequirements, workloadNodePlacement, priorityClassName)
+	SetRestrictedSecurityContext(pod, podSecurity)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
+	if err := r.client.Create(context.TODO(), pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, errors.Wrap(err, "download pod API create errored")
+	}
+	r.log.V(1).Info("download pod created\n", "Namespace", pod.Namespace, "Name", pod.Name, "Image name", r.image)
+	return pod, nil
+}
+
 func (r *UploadReconciler) updatePvcPodName(pvc *v1.PersistentVolumeClaim, podName string, log logr.Logger) error {
 	currentPvcCopy := pvc.DeepCopyObject()
 
@@ -383,11 +609,21 @@ func (r *UploadReconciler) createUploadPodForPvc(pvc *v1.PersistentVolumeClaim,
 		return nil, err
 	}
 
+	tlsConfig, err := GetTLSConfig(r.client)
+	if err != nil {
+		return nil, err
+	}
+
 	preallocationRequested := false
 	if preallocation, err := strconv.ParseBool(getValueFromAnnotation(pvc, AnnPreallocationRequested)); err == nil {
 		preallocationRequested = preallocation
 	}
 
+	trustedCAConfigMap, err := SyncTrustedCA(r.uncachedClient, pvc.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	args := UploadPodArgs{
 		Name:               podName,
 		PVC:                pvc,
@@ -398,6 +634,8 @@ func (r *UploadReconciler) createUploadPodForPvc(pvc *v1.PersistentVolumeClaim,
 		ServerKey:          serverKey,
 		ClientCA:           clientCA,
 		Preallocation:      strconv.FormatBool(preallocationRequested),
+		TLSConfig:          tlsConfig,
+		TrustedCAConfigMap: trustedCAConfigMap,
 	}
 
 	r.log.V(3).Info("Creating upload pod")
@@ -546,17 +784,41 @@ func (r *UploadReconciler) makeUploadServiceSpec(name string, pvc *v1.Persistent
 func (r *UploadReconciler) createUploadPod(args UploadPodArgs) (*v1.Pod, error) {
 	ns := args.PVC.Namespace
 
-	podResourceRequirements, err := GetDefaultPodResourceRequirements(r.client)
+	podResourceRequirements, err := GetPodResourceRequirements(r.client, args.PVC)
+	if err != nil {
+		return nil, err
+	}
+
+	workloadNodePlacement, err := GetPodNodePlacement(r.client, args.PVC)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityClassName, err := GetPriorityClassName(r.client, args.PVC)
 	if err != nil {
 		return nil, err
 	}
 
-	workloadNodePlacement, err := GetWorkloadNodePlacement(r.client)
+	podSecurity, err := GetPodSecurityConfig(r.client)
 	if err != nil {
 		return nil, err
 	}
 
-	pod := r.makeUploadPodSpec(args, podResourceRequirements, workloadNodePlacement)
+	imagePullSecrets, err := GetImagePullSecrets(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := r.makeUploadPodSpec(args, podResourceRequirements, workloadNodePlacement, priorityClassName)
+	if args.TrustedCAConfigMap != "" {
+		addTrustedCAVolume(pod, args.TrustedCAConfigMap)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, v1.EnvVar{
+			Name:  common.ImporterTrustedCADirVar,
+			Value: common.ImporterTrustedCADir,
+		})
+	}
+	SetRestrictedSecurityContext(pod, podSecurity)
+	pod.Spec.ImagePullSecrets = imagePullSecrets
 
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: args.Name, Namespace: ns}, pod); err != nil {
 		if !k8serrors.IsNotFound(err) {
@@ -573,9 +835,17 @@ func (r *UploadReconciler) createUploadPod(args UploadPodArgs) (*v1.Pod, error)
 
 // NewUploadController creates a new instance of the upload controller.
 func NewUploadController(mgr manager.Manager, log logr.Logger, uploadImage, pullPolicy, verbose string, serverCertGenerator generator.CertGenerator, clientCAFetcher fetcher.CertBundleFetcher) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
 	client := mgr.GetClient()
 	reconciler := &UploadReconciler{
 		client:              client,
+		uncachedClient:      uncachedClient,
 		scheme:              mgr.GetScheme(),
 		log:                 log.WithName("upload-controller"),
 		image:               uploadImage,
@@ -645,6 +915,11 @@ func createUploadResourceName(name string) string {
 	return naming.GetResourceName("cdi-upload", name)
 }
 
+// createDownloadResourceName returns the name given to download resources
+func createDownloadResourceName(name string) string {
+	return naming.GetResourceName("cdi-download", name)
+}
+
 // UploadPossibleForPVC is called by the api server to see whether to return an upload token
 func UploadPossibleForPVC(pvc *v1.PersistentVolumeClaim) error {
 	if _, ok := pvc.Annotations[AnnUploadRequest]; !ok {
@@ -653,6 +928,20 @@ func UploadPossibleForPVC(pvc *v1.PersistentVolumeClaim) error {
 	return nil
 }
 
+// DownloadPossibleForPVC is called by the upload proxy to see whether the disk of a PVC can be downloaded
+func DownloadPossibleForPVC(pvc *v1.PersistentVolumeClaim) error {
+	if _, ok := pvc.Annotations[AnnDownloadRequest]; !ok {
+		return errors.Errorf("PVC %s is not a download source", pvc.Name)
+	}
+	return nil
+}
+
+// GetDownloadServerURL returns the url the proxy should get the disk of a particular pvc from
+func GetDownloadServerURL(namespace, pvc, downloadPath string) string {
+	serviceName := naming.GetServiceNameFromResourceName(createDownloadResourceName(pvc))
+	return fmt.Sprintf("https://%s.%s.svc%s", serviceName, namespace, downloadPath)
+}
+
 // GetUploadServerURL returns the url the proxy should post to for a particular pvc
 func GetUploadServerURL(namespace, pvc, uploadPath string) string {
 	serviceName := createUploadServiceNameFromPvcName(pvc)
@@ -664,7 +953,7 @@ func createUploadServiceNameFromPvcName(pvc string) string {
 	return naming.GetServiceNameFromResourceName(createUploadResourceName(pvc))
 }
 
-func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequirements *v1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement) *v1.Pod {
+func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequirements *v1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement, priorityClassName string) *v1.Pod {
 	requestImageSize, _ := getRequestedImageSize(args.PVC)
 	serviceName := naming.GetServiceNameFromResourceName(args.Name)
 	fsGroup := common.QemuSubGid
@@ -686,9 +975,7 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 			},
 		},
 		Spec: v1.PodSpec{
-			SecurityContext: &v1.PodSecurityContext{
-				RunAsUser: &[]int64{0}[0],
-			},
+			SecurityContext: &v1.PodSecurityContext{},
 			Containers: []v1.Container{
 				{
 					Name:            common.UploadServerPodname,
@@ -752,9 +1039,10 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 					},
 				},
 			},
-			NodeSelector: workloadNodePlacement.NodeSelector,
-			Tolerations:  workloadNodePlacement.Tolerations,
-			Affinity:     workloadNodePlacement.Affinity,
+			NodeSelector:      workloadNodePlacement.NodeSelector,
+			Tolerations:       workloadNodePlacement.Tolerations,
+			Affinity:          workloadNodePlacement.Affinity,
+			PriorityClassName: priorityClassName,
 		},
 	}
 
@@ -766,6 +1054,8 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 		pod.Spec.Containers[0].Resources = *resourceRequirements
 	}
 
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, tlsconfig.EnvVars(args.TLSConfig)...)
+
 	if getVolumeMode(args.PVC) == v1.PersistentVolumeBlock {
 		pod.Spec.Containers[0].VolumeDevices = []v1.VolumeDevice{
 			{
@@ -805,3 +1095,118 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 	SetPodPvcAnnotations(pod, args.PVC)
 	return pod
 }
+
+func (r *UploadReconciler) makeDownloadPodSpec(args UploadPodArgs, resourceRequirements *v1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement, priorityClassName string) *v1.Pod {
+	serviceName := naming.GetServiceNameFromResourceName(args.Name)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      args.Name,
+			Namespace: args.PVC.Namespace,
+			Annotations: map[string]string{
+				annCreatedByUpload: "yes",
+			},
+			Labels: map[string]string{
+				common.CDILabelKey:              common.CDILabelValue,
+				common.CDIComponentLabel:        common.UploadServerCDILabel,
+				common.UploadServerServiceLabel: serviceName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				MakePVCOwnerReference(args.PVC),
+			},
+		},
+		Spec: v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{},
+			Containers: []v1.Container{
+				{
+					Name:            common.UploadServerPodname,
+					Image:           r.image,
+					ImagePullPolicy: v1.PullPolicy(r.pullPolicy),
+					Env: []v1.EnvVar{
+						{
+							Name:  "TLS_KEY",
+							Value: string(args.ServerKey),
+						},
+						{
+							Name:  "TLS_CERT",
+							Value: string(args.ServerCert),
+						},
+						{
+							Name:  "CLIENT_CERT",
+							Value: string(args.ClientCA),
+						},
+						{
+							Name:  "CLIENT_NAME",
+							Value: args.ClientName,
+						},
+					},
+					Args: []string{"-v=" + r.verbose},
+					ReadinessProbe: &v1.Probe{
+						Handler: v1.Handler{
+							HTTPGet: &v1.HTTPGetAction{
+								Path: "/healthz",
+								Port: intstr.IntOrString{
+									Type:   intstr.Int,
+									IntVal: 8080,
+								},
+							},
+						},
+						InitialDelaySeconds: 2,
+						PeriodSeconds:       5,
+					},
+					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:      ScratchVolName,
+							MountPath: common.ScratchDataDir,
+						},
+					},
+				},
+			},
+			RestartPolicy: v1.RestartPolicyOnFailure,
+			Volumes: []v1.Volume{
+				{
+					Name: DataVolName,
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+							ClaimName: args.PVC.Name,
+							ReadOnly:  true,
+						},
+					},
+				},
+				{
+					// Holds the qcow2 image converted for downloads
+					Name: ScratchVolName,
+					VolumeSource: v1.VolumeSource{
+						EmptyDir: &v1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+			NodeSelector:      workloadNodePlacement.NodeSelector,
+			Tolerations:       workloadNodePlacement.Tolerations,
+			Affinity:          workloadNodePlacement.Affinity,
+			PriorityClassName: priorityClassName,
+		},
+	}
+
+	if resourceRequirements != nil {
+		pod.Spec.Containers[0].Resources = *resourceRequirements
+	}
+
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, tlsconfig.EnvVars(args.TLSConfig)...)
+
+	source := common.ImporterWritePath
+	if getVolumeMode(args.PVC) == v1.PersistentVolumeBlock {
+		source = common.WriteBlockPath
+		pod.Spec.Containers[0].VolumeDevices = addVolumeDevices()
+	} else {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      DataVolName,
+			MountPath: common.UploadServerDataDir,
+			ReadOnly:  true,
+		})
+	}
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  common.DownloadSource,
+		Value: source,
+	})
+	return pod
+}
@@ -25,6 +25,7 @@ import (
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -40,7 +41,9 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
 )
@@ -423,6 +426,193 @@ var _ = Describe("reconcilePVC loop", func() {
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, scratchPvc)
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("Should pass the TLS config to the pod", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			cdiConfig := &cdiv1.CDIConfig{}
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)
+			Expect(err).ToNot(HaveOccurred())
+			cdiConfig.Spec.TLSConfig = &cdiv1.TLSConfig{MinVersion: cdiv1.VersionTLS12}
+			err = reconciler.client.Update(context.TODO(), cdiConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  common.TLSMinVersionVar,
+				Value: string(cdiv1.VersionTLS12),
+			}))
+		})
+
+		It("Should mount the trusted CA bundle in the pod", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName}, nil)
+			reconciler := createUploadReconciler(testPvc, createTrustedCAConfigMap(util.GetNamespace(), "bundle", nil))
+
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      TrustedCAVolName,
+				MountPath: common.ImporterTrustedCADir,
+				ReadOnly:  true,
+			}))
+			Expect(uploadPod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  common.ImporterTrustedCADirVar,
+				Value: common.ImporterTrustedCADir,
+			}))
+		})
+
+		It("Should poll the status of a running upload pod", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName}, nil)
+			pod := createUploadPod(testPvc)
+			pod.Name = uploadResourceName
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Ready: true}}
+			reconciler := createUploadReconciler(testPvc, pod)
+			origStatusFunc := uploadStatusFunc
+			defer func() {
+				uploadStatusFunc = origStatusFunc
+			}()
+			uploadStatusFunc = func(*corev1.Pod) (*uploadv1.UploadStatus, error) {
+				return &uploadv1.UploadStatus{Phase: uploadv1.UploadPhaseUploading, BytesReceived: 1024, TotalBytes: 4096}, nil
+			}
+
+			result, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(uploadStatusPollInterval))
+			resultPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testPvcName, Namespace: "default"}, resultPvc)
+			Expect(err).ToNot(HaveOccurred())
+			status := GetUploadStatus(resultPvc)
+			Expect(status.Phase).To(Equal(uploadv1.UploadPhaseUploading))
+			Expect(status.BytesReceived).To(Equal(int64(1024)))
+			Expect(status.TotalBytes).To(Equal(int64(4096)))
+		})
+	})
+})
+
+var _ = Describe("GetUploadStatus", func() {
+	table.DescribeTable("Should return the status of the upload to the PVC", func(annotations map[string]string, phase uploadv1.UploadPhase, bytesReceived int64) {
+		status := GetUploadStatus(createPvc("testPvc1", "default", annotations, nil))
+		Expect(status.Phase).To(Equal(phase))
+		Expect(status.BytesReceived).To(Equal(bytesReceived))
+	},
+		table.Entry("without status", map[string]string{}, uploadv1.UploadPhasePending, int64(0)),
+		table.Entry("while uploading", map[string]string{
+			AnnPodPhase:     string(corev1.PodRunning),
+			AnnUploadStatus: `{"phase":"Uploading","bytesReceived":10}`,
+		}, uploadv1.UploadPhaseUploading, int64(10)),
+		table.Entry("once the pod succeeded", map[string]string{
+			AnnPodPhase:     string(corev1.PodSucceeded),
+			AnnUploadStatus: `{"phase":"Processing","bytesReceived":10}`,
+		}, uploadv1.UploadPhaseSucceeded, int64(10)),
+		table.Entry("once the pod failed", map[string]string{
+			AnnPodPhase: string(corev1.PodFailed),
+		}, uploadv1.UploadPhaseFailed, int64(0)),
+	)
+})
+
+var _ = Describe("Upload controller download reconcile", func() {
+	downloadRequest := func() reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}}
+	}
+
+	It("Should create a read only download pod and service for a populated PVC", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnDownloadRequest: ""}, nil)
+		reconciler := createUploadReconciler(pvc)
+		_, err := reconciler.Reconcile(downloadRequest())
+		Expect(err).ToNot(HaveOccurred())
+
+		downloadResourceName := createDownloadResourceName("testPvc1")
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: downloadResourceName, Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  common.DownloadSource,
+			Value: common.ImporterWritePath,
+		}))
+		readOnly := false
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == "testPvc1" {
+				readOnly = volume.PersistentVolumeClaim.ReadOnly
+			}
+		}
+		Expect(readOnly).To(BeTrue())
+
+		service := &corev1.Service{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: naming.GetServiceNameFromResourceName(downloadResourceName), Namespace: "default"}, service)
+		Expect(err).ToNot(HaveOccurred())
+
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), downloadRequest().NamespacedName, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.Annotations[AnnDownloadPodReady]).To(Equal("false"))
+	})
+
+	It("Should requeue and not create a pod if the PVC is in use by a writer", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnDownloadRequest: ""}, nil)
+		reconciler := createUploadReconciler(pvc, podUsingPVC(pvc, false))
+		result, err := reconciler.Reconcile(downloadRequest())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		podList := &corev1.PodList{}
+		err = reconciler.client.List(context.TODO(), podList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podList.Items).To(HaveLen(1))
+		By("Checking events recorded")
+		close(reconciler.recorder.(*record.FakeRecorder).Events)
+		found := false
+		for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+			if strings.Contains(event, DownloadSourceInUse) {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("Should create the download pod if the PVC is only in use by readers", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnDownloadRequest: ""}, nil)
+		reconciler := createUploadReconciler(pvc, podUsingPVC(pvc, true))
+		result, err := reconciler.Reconcile(downloadRequest())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createDownloadResourceName("testPvc1"), Namespace: "default"}, pod)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should remove the download pod and service when the download annotation is removed", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnDownloadRequest: ""}, nil)
+		reconciler := createUploadReconciler(pvc)
+		_, err := reconciler.Reconcile(downloadRequest())
+		Expect(err).ToNot(HaveOccurred())
+
+		resultPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), downloadRequest().NamespacedName, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		delete(resultPvc.Annotations, AnnDownloadRequest)
+		Expect(reconciler.client.Update(context.TODO(), resultPvc)).To(Succeed())
+		_, err = reconciler.Reconcile(downloadRequest())
+		Expect(err).ToNot(HaveOccurred())
+
+		podList := &corev1.PodList{}
+		err = reconciler.client.List(context.TODO(), podList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podList.Items).To(BeEmpty())
+		serviceList := &corev1.ServiceList{}
+		err = reconciler.client.List(context.TODO(), serviceList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serviceList.Items).To(BeEmpty())
+		resultPvc = &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), downloadRequest().NamespacedName, resultPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resultPvc.Annotations).ToNot(HaveKey(AnnDownloadPodReady))
 	})
 })
 
@@ -534,6 +724,7 @@ func createUploadReconciler(objects ...runtime.Object) *UploadReconciler {
 	// Create a ReconcileMemcached object with the scheme and fake client.
 	r := &UploadReconciler{
 		client:              cl,
+		uncachedClient:      cl,
 		scheme:              s,
 		log:                 uploadLog,
 		serverCertGenerator: &fakeCertGenerator{},
@@ -588,7 +779,7 @@ func createUploadClonePod(pvc *corev1.PersistentVolumeClaim, clientName string)
 		},
 		Spec: corev1.PodSpec{
 			SecurityContext: &corev1.PodSecurityContext{
-				RunAsUser: &[]int64{0}[0],
+				RunAsUser: &[]int64{common.QemuSubUID}[0],
 			},
 			Containers: []corev1.Container{
 				{
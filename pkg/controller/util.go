@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"crypto/rsa"
+	"encoding/json"
 	"fmt"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	extclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -25,6 +27,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 )
 
@@ -35,6 +38,21 @@ const (
 	// CertVolName is the name of the volumecontaining certs
 	CertVolName = "cdi-cert-vol"
 
+	// ClientCertVolName is the name of the volume containing the client certificate
+	ClientCertVolName = "cdi-client-cert-vol"
+
+	// NFSVolName is the name of the volume containing the NFS export
+	NFSVolName = "cdi-nfs-vol"
+
+	// FileVolName is the name of the volume containing the image file of the file source
+	FileVolName = "cdi-file-vol"
+
+	// TrustedCAVolName is the name of the volume containing the cluster-wide trusted CA bundle
+	TrustedCAVolName = "cdi-trusted-ca-vol"
+
+	// SignatureVolName is the name of the volume containing the public key or the trusted roots verifying image signatures
+	SignatureVolName = "cdi-signature-vol"
+
 	// ScratchVolName provides a const to use for creating scratch pvc volumes in pod specs
 	ScratchVolName = "cdi-scratch-vol"
 
@@ -73,6 +91,18 @@ const (
 	AnnMultiStageImportDone = AnnAPIGroup + "/storage.checkpoint.done"
 	// AnnPreallocationRequested provides a const to indicate whether preallocation should be performed on the PV
 	AnnPreallocationRequested = AnnAPIGroup + "/storage.preallocacation.requested"
+	// AnnPodNodePlacement is a PVC annotation holding the node placement of the pods populating the PVC, in JSON
+	AnnPodNodePlacement = AnnAPIGroup + "/storage.pod.nodePlacement"
+	// AnnPriorityClassName is a PVC annotation holding the priority class of the pods populating the PVC
+	AnnPriorityClassName = AnnAPIGroup + "/storage.pod.priorityclassname"
+	// AnnPodResourceRequirements is a PVC annotation holding the resource requirements of the pods populating the PVC, in JSON
+	AnnPodResourceRequirements = AnnAPIGroup + "/storage.pod.resourceRequirements"
+	// AnnFilesystemOverhead is a PVC annotation holding the filesystem overhead of the PVC, overriding the CDIConfig
+	AnnFilesystemOverhead = AnnAPIGroup + "/storage.filesystemOverhead"
+	// AnnScratchStorageClass is a PVC annotation holding the storage class of its scratch space PVC, overriding the CDIConfig
+	AnnScratchStorageClass = AnnAPIGroup + "/storage.scratch.storageClass"
+	// AnnScratchSize is a PVC annotation holding the size of its scratch space PVC
+	AnnScratchSize = AnnAPIGroup + "/storage.scratch.size"
 
 	// AnnRunningCondition provides a const for the running condition
 	AnnRunningCondition = AnnAPIGroup + "/storage.condition.running"
@@ -236,6 +266,11 @@ func newScratchPersistentVolumeClaimSpec(pvc *v1.PersistentVolumeClaim, pod *v1.
 			Resources:   pvc.Spec.Resources,
 		},
 	}
+	if size, err := resource.ParseQuantity(pvc.GetAnnotations()[AnnScratchSize]); err == nil {
+		pvcDef.Spec.Resources = v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceStorage: size},
+		}
+	}
 	if storageClassName != "" {
 		pvcDef.Spec.StorageClassName = &storageClassName
 	}
@@ -274,6 +309,18 @@ func GetStorageClassByName(client client.Client, name *string) (*storagev1.Stora
 	return GetDefaultStorageClass(client)
 }
 
+// getStorageProfile returns the StorageProfile of the storage class, nil if it does not exist
+func getStorageProfile(c client.Client, storageClassName string) (*cdiv1.StorageProfile, error) {
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageProfile); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return storageProfile, nil
+}
+
 // GetDefaultStorageClass returns the default storage class or nil if none found
 func GetDefaultStorageClass(client client.Client) (*storagev1.StorageClass, error) {
 	storageClasses := &storagev1.StorageClassList{}
@@ -290,12 +337,16 @@ func GetDefaultStorageClass(client client.Client) (*storagev1.StorageClass, erro
 	return nil, nil
 }
 
-// GetFilesystemOverhead determines the filesystem overhead defined in CDIConfig for this PVC's volumeMode and storageClass.
+// GetFilesystemOverhead determines the filesystem overhead defined in CDIConfig for this PVC's volumeMode and storageClass,
+// unless the PVC overrides it with the AnnFilesystemOverhead annotation.
 func GetFilesystemOverhead(client client.Client, pvc *v1.PersistentVolumeClaim) (cdiv1.Percent, error) {
 	klog.V(1).Info("GetFilesystemOverhead with PVC", pvc)
 	if getVolumeMode(pvc) != v1.PersistentVolumeFilesystem {
 		return "0", nil
 	}
+	if overhead, ok := pvc.GetAnnotations()[AnnFilesystemOverhead]; ok {
+		return cdiv1.Percent(overhead), nil
+	}
 
 	cdiConfig := &cdiv1.CDIConfig{}
 	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
@@ -345,6 +396,9 @@ func GetFilesystemOverhead(client client.Client, pvc *v1.PersistentVolumeClaim)
 // 2. If 1 is not available, use the storage class name of the original pvc that will own the scratch pvc.
 // 3. If none of those are available, return blank.
 func GetScratchPvcStorageClass(client client.Client, pvc *v1.PersistentVolumeClaim) string {
+	if storageClassName := pvc.GetAnnotations()[AnnScratchStorageClass]; storageClassName != "" {
+		return storageClassName
+	}
 	config := &cdiv1.CDIConfig{}
 	if err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, config); err != nil {
 		return ""
@@ -375,6 +429,45 @@ func GetDefaultPodResourceRequirements(client client.Client) (*v1.ResourceRequir
 	return cdiconfig.Status.DefaultPodResourceRequirements, nil
 }
 
+// GetPodResourceRequirements returns the resource requirements of the pods populating the PVC, the default ones of the
+// CDIConfig with the resources requested for the PVC overriding them
+func GetPodResourceRequirements(c client.Client, pvc *v1.PersistentVolumeClaim) (*v1.ResourceRequirements, error) {
+	defaultRequirements, err := GetDefaultPodResourceRequirements(c)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := pvc.GetAnnotations()[AnnPodResourceRequirements]
+	if !ok {
+		return defaultRequirements, nil
+	}
+	pvcRequirements := &v1.ResourceRequirements{}
+	if err := json.Unmarshal([]byte(value), pvcRequirements); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", AnnPodResourceRequirements)
+	}
+
+	requirements := &v1.ResourceRequirements{}
+	if defaultRequirements != nil {
+		requirements = defaultRequirements.DeepCopy()
+	}
+	requirements.Limits = mergeResourceList(requirements.Limits, pvcRequirements.Limits)
+	requirements.Requests = mergeResourceList(requirements.Requests, pvcRequirements.Requests)
+	return requirements, nil
+}
+
+func mergeResourceList(resources, overrides v1.ResourceList) v1.ResourceList {
+	if len(overrides) == 0 {
+		return resources
+	}
+	if resources == nil {
+		resources = v1.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		resources[name] = quantity
+	}
+	return resources
+}
+
 // this is being called for pods using PV with block volume mode
 func addVolumeDevices() []v1.VolumeDevice {
 	volumeDevices := []v1.VolumeDevice{
@@ -503,7 +596,7 @@ func setConditionFromPodWithPrefix(anno map[string]string, prefix string, pod *v
 			anno[prefix+".message"] = ""
 			anno[prefix+".reason"] = podRunningReason
 		} else {
-			anno[AnnRunningCondition] = "false"
+			anno[prefix] = "false"
 			if pod.Status.ContainerStatuses[0].State.Waiting != nil {
 				anno[prefix+".message"] = pod.Status.ContainerStatuses[0].State.Waiting.Message
 				anno[prefix+".reason"] = pod.Status.ContainerStatuses[0].State.Waiting.Reason
@@ -590,6 +683,204 @@ func GetWorkloadNodePlacement(c client.Client) (*sdkapi.NodePlacement, error) {
 	return &cr.Spec.Workloads, nil
 }
 
+// GetImagePullSecrets returns the image pull secrets of the CDI CR, which the pods created by the controller reference
+// in their own namespace
+func GetImagePullSecrets(c client.Client) ([]v1.LocalObjectReference, error) {
+	cr, err := GetActiveCDI(c)
+	if err != nil {
+		return nil, err
+	}
+	if cr == nil {
+		return nil, nil
+	}
+	return cr.Spec.ImagePullSecrets, nil
+}
+
+// GetPodNodePlacement returns the workload node placement of the CDI CR merged with the node placement requested for
+// the pods of the PVC: the node selector and the tolerations of the PVC are added to the workload ones, and its
+// affinity replaces the workload one
+func GetPodNodePlacement(c client.Client, pvc *v1.PersistentVolumeClaim) (*sdkapi.NodePlacement, error) {
+	workloadNodePlacement, err := GetWorkloadNodePlacement(c)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := pvc.GetAnnotations()[AnnPodNodePlacement]
+	if !ok {
+		return workloadNodePlacement, nil
+	}
+	pvcNodePlacement := &sdkapi.NodePlacement{}
+	if err := json.Unmarshal([]byte(value), pvcNodePlacement); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", AnnPodNodePlacement)
+	}
+
+	return mergeNodePlacement(workloadNodePlacement, pvcNodePlacement), nil
+}
+
+// mergeNodePlacement returns the node placement with the node selector and the tolerations of the extra node placement
+// added, and its affinity replacing the one of the node placement
+func mergeNodePlacement(nodePlacement, extra *sdkapi.NodePlacement) *sdkapi.NodePlacement {
+	merged := nodePlacement.DeepCopy()
+	if len(extra.NodeSelector) > 0 {
+		if merged.NodeSelector == nil {
+			merged.NodeSelector = map[string]string{}
+		}
+		for k, v := range extra.NodeSelector {
+			merged.NodeSelector[k] = v
+		}
+	}
+	merged.Tolerations = append(merged.Tolerations, extra.Tolerations...)
+	if extra.Affinity != nil {
+		merged.Affinity = extra.Affinity
+	}
+	return merged
+}
+
+// GetConsumerNodePlacement returns the node placement making the pods populating a PVC of a WaitForFirstConsumer
+// storage class land where the consumer of the PVC runs, before the PVC is bound: the node the scheduler selected for
+// the consumer once it did, or else the scheduling constraints of the pods using the PVC. It returns nil when the PVC
+// is bound, when its storage class binds immediately or when it has no consumer yet.
+func GetConsumerNodePlacement(c client.Client, pvc *v1.PersistentVolumeClaim) (*sdkapi.NodePlacement, error) {
+	if pvc.Status.Phase == v1.ClaimBound {
+		return nil, nil
+	}
+	var storageClass *storagev1.StorageClass
+	if pvc.Spec.StorageClassName != nil {
+		// A missing storage class has no binding mode to honor
+		storageClass = &storagev1.StorageClass{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+			storageClass = nil
+		}
+	} else {
+		var err error
+		if storageClass, err = GetDefaultStorageClass(c); err != nil {
+			return nil, err
+		}
+	}
+	if storageClass == nil || storageClass.VolumeBindingMode == nil || *storageClass.VolumeBindingMode != storagev1.VolumeBindingWaitForFirstConsumer {
+		return nil, nil
+	}
+	if node := pvc.GetAnnotations()[AnnSelectedNode]; node != "" {
+		return &sdkapi.NodePlacement{NodeSelector: map[string]string{v1.LabelHostname: node}}, nil
+	}
+
+	pods, err := getPodsUsingPVCs(c, pvc.Namespace, sets.NewString(pvc.Name), false)
+	if err != nil {
+		return nil, err
+	}
+	var consumer *v1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		// The CDI pods are not consumers
+		if pod.Labels[common.CDILabelKey] == common.CDILabelValue {
+			continue
+		}
+		if consumer == nil || pod.CreationTimestamp.Before(&consumer.CreationTimestamp) {
+			consumer = pod
+		}
+	}
+	if consumer == nil {
+		return nil, nil
+	}
+	nodePlacement := &sdkapi.NodePlacement{
+		NodeSelector: consumer.Spec.NodeSelector,
+		Tolerations:  consumer.Spec.Tolerations,
+	}
+	// Only the node affinity applies, the pod affinities of the consumer are about its own workload
+	if consumer.Spec.Affinity != nil && consumer.Spec.Affinity.NodeAffinity != nil {
+		nodePlacement.Affinity = &v1.Affinity{NodeAffinity: consumer.Spec.Affinity.NodeAffinity}
+	}
+	return nodePlacement, nil
+}
+
+// GetPodSecurityConfig returns the security configuration of the pods populating the PVCs from the CDIConfig, an empty
+// one when the CDIConfig does not override it
+func GetPodSecurityConfig(c client.Client) (*cdiv1.PodSecurityConfig, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return &cdiv1.PodSecurityConfig{}, nil
+		}
+		return nil, err
+	}
+	if cdiconfig.Spec.PodSecurity == nil {
+		return &cdiv1.PodSecurityConfig{}, nil
+	}
+	return cdiconfig.Spec.PodSecurity, nil
+}
+
+// SetRestrictedSecurityContext makes the pod comply with the restricted Pod Security Standard, unless the security
+// configuration overrides it: its containers run as the qemu user without privilege escalation nor capabilities, with the
+// runtime default seccomp profile. The fsGroup is the only supplemental group, it lets the user write to the volumes, and
+// the container runtime gives the block devices to the user. The other security settings of the pod and its containers,
+// like the SELinux options of the clone source pods, are kept.
+func SetRestrictedSecurityContext(pod *v1.Pod, podSecurity *cdiv1.PodSecurityConfig) {
+	runAsUser := common.QemuSubUID
+	if podSecurity.RunAsUser != nil {
+		runAsUser = *podSecurity.RunAsUser
+	}
+	runAsNonRoot := runAsUser != 0
+	if pod.Spec.SecurityContext == nil {
+		pod.Spec.SecurityContext = &v1.PodSecurityContext{}
+	}
+	pod.Spec.SecurityContext.RunAsUser = &runAsUser
+	pod.Spec.SecurityContext.RunAsNonRoot = &runAsNonRoot
+	if pod.Spec.SecurityContext.FSGroup == nil {
+		fsGroup := common.QemuSubGid
+		pod.Spec.SecurityContext.FSGroup = &fsGroup
+	}
+	if podSecurity.SELinuxOptions != nil {
+		pod.Spec.SecurityContext.SELinuxOptions = podSecurity.SELinuxOptions
+	}
+
+	// The API server sets the seccompProfile field of the pod from the annotation
+	seccompProfile := v1.SeccompProfileRuntimeDefault
+	if podSecurity.SeccompProfile != nil {
+		seccompProfile = *podSecurity.SeccompProfile
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[v1.SeccompPodAnnotationKey] = seccompProfile
+
+	allowPrivilegeEscalation := false
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.SecurityContext == nil {
+			container.SecurityContext = &v1.SecurityContext{}
+		}
+		container.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+		if container.SecurityContext.Capabilities == nil {
+			container.SecurityContext.Capabilities = &v1.Capabilities{}
+		}
+		container.SecurityContext.Capabilities.Add = nil
+		container.SecurityContext.Capabilities.Drop = []v1.Capability{"ALL"}
+	}
+}
+
+// GetPriorityClassName returns the priority class of the pods populating the PVC, the one requested for the PVC or
+// else the default pod priority class of the CDIConfig
+func GetPriorityClassName(c client.Client, pvc *v1.PersistentVolumeClaim) (string, error) {
+	if priorityClassName, ok := pvc.GetAnnotations()[AnnPriorityClassName]; ok {
+		return priorityClassName, nil
+	}
+
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if cdiconfig.Spec.PodPriorityClassName == nil {
+		return "", nil
+	}
+	return *cdiconfig.Spec.PodPriorityClassName, nil
+}
+
 // GetActiveCDI returns the active CDI CR
 func GetActiveCDI(c client.Client) (*cdiv1.CDI, error) {
 	crList := &cdiv1.CDIList{}
@@ -654,6 +945,68 @@ func GetPreallocation(client client.Client, dataVolume *cdiv1.DataVolume) bool {
 	return cdiconfig.Status.Preallocation
 }
 
+// GetTLSConfig returns the TLS settings of the CDIConfig, nil if none are configured
+func GetTLSConfig(c client.Client) (*cdiv1.TLSConfig, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, err := tlsconfig.NewOptions(cdiconfig.Spec.TLSConfig); err != nil {
+		return nil, errors.Wrap(err, "invalid TLS config in CDIConfig")
+	}
+	return cdiconfig.Spec.TLSConfig, nil
+}
+
+// GetFileSourceHostPathPrefixes returns the directories of the nodes the host paths of File sources must be in
+func GetFileSourceHostPathPrefixes(c client.Client) ([]string, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cdiconfig.Spec.FileSourceHostPathPrefixes, nil
+}
+
+// GetRegistriesConfig returns the registries configuration of the CDIConfig encoded in JSON, empty if none is configured
+func GetRegistriesConfig(c client.Client) (string, error) {
+	cdiconfig := &cdiv1.CDIConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiconfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(cdiconfig.Spec.Registries) == 0 {
+		return "", nil
+	}
+	for _, registry := range cdiconfig.Spec.Registries {
+		if !isRegistryLocation(registry.Registry) {
+			return "", errors.Errorf("invalid registry %q in CDIConfig, expected host[:port][/namespace]", registry.Registry)
+		}
+		for _, mirror := range registry.Mirrors {
+			if !isRegistryLocation(mirror.Location) {
+				return "", errors.Errorf("invalid mirror %q of registry %s in CDIConfig, expected host[:port][/namespace]", mirror.Location, registry.Registry)
+			}
+		}
+	}
+	config, err := json.Marshal(cdiconfig.Spec.Registries)
+	if err != nil {
+		return "", err
+	}
+	return string(config), nil
+}
+
+// isRegistryLocation returns true for host[:port][/namespace] locations, without scheme, tag or digest
+func isRegistryLocation(location string) bool {
+	host := strings.SplitN(location, "/", 2)[0]
+	return host != "" && !strings.HasSuffix(location, "/") && !strings.ContainsAny(location, " \t\n\"'@\\") && !strings.Contains(location, "://")
+}
+
 // GetStorageClassNameForDV returns storage class to be used for the DV's PVC
 func GetStorageClassNameForDV(c client.Client, dv *cdiv1.DataVolume) string {
 	// If DV has a SC, return it
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -144,6 +145,29 @@ var _ = Describe("GetScratchPVCStorageClass", func() {
 		pvc := createPvcInStorageClass("test", "test", &storageClassName, nil, nil, v1.ClaimBound)
 		Expect(GetScratchPvcStorageClass(client, pvc)).To(Equal(""))
 	})
+
+	It("Should return the scratch storage class of the PVC over the one of the CDIConfig", func() {
+		client := createClient(createCDIConfigWithStorageClass(common.ConfigName, "test1"))
+		pvc := createPvc("test", "test", map[string]string{AnnScratchStorageClass: "local-nvme"}, nil)
+		Expect(GetScratchPvcStorageClass(client, pvc)).To(Equal("local-nvme"))
+	})
+})
+
+var _ = Describe("newScratchPersistentVolumeClaimSpec", func() {
+	It("Should request the size of the PVC", func() {
+		pvc := createPvc("test", "test", nil, nil)
+		scratch := newScratchPersistentVolumeClaimSpec(pvc, createImporterTestPod(pvc, "test", nil), "test-scratch", "local-nvme")
+		Expect(scratch.Spec.Resources).To(Equal(pvc.Spec.Resources))
+		Expect(*scratch.Spec.StorageClassName).To(Equal("local-nvme"))
+	})
+
+	It("Should request the scratch size of the PVC", func() {
+		pvc := createPvc("test", "test", map[string]string{AnnScratchSize: "20Gi"}, nil)
+		scratch := newScratchPersistentVolumeClaimSpec(pvc, createImporterTestPod(pvc, "test", nil), "test-scratch", "")
+		Expect(scratch.Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+		Expect(scratch.Spec.StorageClassName).To(BeNil())
+		Expect(scratch.Annotations).ToNot(HaveKey(AnnScratchSize))
+	})
 })
 
 var _ = Describe("GetWorkloadNodePlacement", func() {
@@ -171,6 +195,193 @@ var _ = Describe("GetWorkloadNodePlacement", func() {
 	})
 })
 
+var _ = Describe("GetPodResourceRequirements", func() {
+	It("Should return the default resource requirements without annotation", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Status.DefaultPodResourceRequirements = &v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		}
+		client := createClient(config)
+		pvc := createPvc("test", "test", nil, nil)
+		Expect(GetPodResourceRequirements(client, pvc)).To(Equal(config.Status.DefaultPodResourceRequirements))
+	})
+
+	It("Should override the default resource requirements with the ones of the PVC", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Status.DefaultPodResourceRequirements = &v1.ResourceRequirements{
+			Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("600M")},
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+		}
+		client := createClient(config)
+		pvc := createPvc("test", "test", map[string]string{
+			AnnPodResourceRequirements: `{"limits":{"cpu":"4","memory":"2Gi"},"requests":{"memory":"1Gi"}}`,
+		}, nil)
+		res, err := GetPodResourceRequirements(client, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Limits.Cpu().String()).To(Equal("4"))
+		Expect(res.Limits.Memory().String()).To(Equal("2Gi"))
+		Expect(res.Requests.Cpu().String()).To(Equal("100m"))
+		Expect(res.Requests.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("Should return an err with an invalid annotation", func() {
+		client := createClient(createCDIConfig(common.ConfigName))
+		pvc := createPvc("test", "test", map[string]string{AnnPodResourceRequirements: "invalid"}, nil)
+		res, err := GetPodResourceRequirements(client, pvc)
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+})
+
+var _ = Describe("GetPriorityClassName", func() {
+	It("Should return the priority class of the PVC over the default one", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Spec.PodPriorityClassName = &[]string{"default-class"}[0]
+		client := createClient(config)
+		pvc := createPvc("test", "test", map[string]string{AnnPriorityClassName: "pvc-class"}, nil)
+		Expect(GetPriorityClassName(client, pvc)).To(Equal("pvc-class"))
+	})
+
+	It("Should return the default priority class of the CDIConfig", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Spec.PodPriorityClassName = &[]string{"default-class"}[0]
+		client := createClient(config)
+		pvc := createPvc("test", "test", nil, nil)
+		Expect(GetPriorityClassName(client, pvc)).To(Equal("default-class"))
+	})
+
+	It("Should return blank without CDIConfig", func() {
+		client := createClient()
+		pvc := createPvc("test", "test", nil, nil)
+		Expect(GetPriorityClassName(client, pvc)).To(Equal(""))
+	})
+})
+
+var _ = Describe("GetFilesystemOverhead", func() {
+	It("Should return the overhead of the PVC over the one of the CDIConfig", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Status.FilesystemOverhead = &cdiv1.FilesystemOverhead{Global: "0.055"}
+		client := createClient(config)
+		pvc := createPvc("test", "test", map[string]string{AnnFilesystemOverhead: "0.2"}, nil)
+		Expect(GetFilesystemOverhead(client, pvc)).To(Equal(cdiv1.Percent("0.2")))
+		pvc = createPvc("test", "test", nil, nil)
+		Expect(GetFilesystemOverhead(client, pvc)).To(Equal(cdiv1.Percent("0.055")))
+	})
+
+	It("Should return no overhead for block volumes", func() {
+		client := createClient()
+		pvc := createBlockPvc("test", "test", map[string]string{AnnFilesystemOverhead: "0.2"}, nil)
+		Expect(GetFilesystemOverhead(client, pvc)).To(Equal(cdiv1.Percent("0")))
+	})
+})
+
+var _ = Describe("GetPodNodePlacement", func() {
+	It("Should return the workload node placement without annotation", func() {
+		cr := createCDIWithWorkload("cdi-test", "1111-1111")
+		cr.Spec.Workloads.NodeSelector = map[string]string{"kubernetes.io/arch": "amd64"}
+		client := createClient(cr)
+		pvc := createPvc("test", "test", nil, nil)
+		res, err := GetPodNodePlacement(client, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(&cr.Spec.Workloads))
+	})
+
+	It("Should merge the node placement of the PVC with the workload one", func() {
+		cr := createCDIWithWorkload("cdi-test", "1111-1111")
+		cr.Spec.Workloads.NodeSelector = map[string]string{"kubernetes.io/arch": "amd64", "zone": "a"}
+		cr.Spec.Workloads.Tolerations = []v1.Toleration{{Key: "workload", Value: "1"}}
+		client := createClient(cr)
+		pvc := createPvc("test", "test", map[string]string{
+			AnnPodNodePlacement: `{"nodeSelector":{"zone":"b"},"tolerations":[{"key":"dv","value":"2"}],` +
+				`"affinity":{"nodeAffinity":{"preferredDuringSchedulingIgnoredDuringExecution":[{"weight":1,"preference":{}}]}}}`,
+		}, nil)
+		res, err := GetPodNodePlacement(client, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.NodeSelector).To(Equal(map[string]string{"kubernetes.io/arch": "amd64", "zone": "b"}))
+		Expect(res.Tolerations).To(Equal([]v1.Toleration{{Key: "workload", Value: "1"}, {Key: "dv", Value: "2"}}))
+		Expect(res.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+
+		// The CDI CR keeps its own workload node placement
+		Expect(GetWorkloadNodePlacement(client)).To(Equal(&cr.Spec.Workloads))
+	})
+
+	It("Should return an err with an invalid annotation", func() {
+		client := createClient(createCDIWithWorkload("cdi-test", "1111-1111"))
+		pvc := createPvc("test", "test", map[string]string{AnnPodNodePlacement: "invalid"}, nil)
+		res, err := GetPodNodePlacement(client, pvc)
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+})
+
+var _ = Describe("GetConsumerNodePlacement", func() {
+	wffc := createStorageClassWithBindingMode("wffc", nil, storagev1.VolumeBindingWaitForFirstConsumer)
+
+	createConsumer := func(name string, labels map[string]string, created time.Time) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test",
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: v1.PodSpec{
+				NodeSelector: map[string]string{"zone": name},
+				Tolerations:  []v1.Toleration{{Key: name}},
+				Affinity: &v1.Affinity{
+					NodeAffinity:    &v1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{Weight: 1}}},
+					PodAntiAffinity: &v1.PodAntiAffinity{},
+				},
+				Volumes: []v1.Volume{{
+					Name: "disk",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "test"},
+					},
+				}},
+			},
+		}
+	}
+
+	It("Should return nil for a bound PVC or an immediate binding storage class", func() {
+		immediate := createStorageClassWithBindingMode("immediate", nil, storagev1.VolumeBindingImmediate)
+		bound := createPvcInStorageClass("test", "test", &wffc.Name, nil, nil, v1.ClaimBound)
+		pending := createPvcInStorageClass("test", "test", &immediate.Name, nil, nil, v1.ClaimPending)
+		client := createClient(wffc, immediate, createConsumer("consumer", nil, time.Now()))
+		Expect(GetConsumerNodePlacement(client, bound)).To(BeNil())
+		Expect(GetConsumerNodePlacement(client, pending)).To(BeNil())
+	})
+
+	It("Should return the node selected for the consumer", func() {
+		pvc := createPvcInStorageClass("test", "test", &wffc.Name, map[string]string{AnnSelectedNode: "node01"}, nil, v1.ClaimPending)
+		client := createClient(wffc, createConsumer("consumer", nil, time.Now()))
+		res, err := GetConsumerNodePlacement(client, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.NodeSelector).To(Equal(map[string]string{v1.LabelHostname: "node01"}))
+		Expect(res.Affinity).To(BeNil())
+	})
+
+	It("Should return the scheduling constraints of the oldest consumer, ignoring the CDI pods", func() {
+		pvc := createPvcInStorageClass("test", "test", &wffc.Name, nil, nil, v1.ClaimPending)
+		now := time.Now()
+		client := createClient(wffc,
+			createConsumer("importer", map[string]string{common.CDILabelKey: common.CDILabelValue}, now.Add(-time.Hour)),
+			createConsumer("first", nil, now.Add(-time.Minute)),
+			createConsumer("second", nil, now))
+		res, err := GetConsumerNodePlacement(client, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.NodeSelector).To(Equal(map[string]string{"zone": "first"}))
+		Expect(res.Tolerations).To(Equal([]v1.Toleration{{Key: "first"}}))
+		Expect(res.Affinity.NodeAffinity).ToNot(BeNil())
+		Expect(res.Affinity.PodAntiAffinity).To(BeNil())
+	})
+
+	It("Should return nil without consumer", func() {
+		pvc := createPvcInStorageClass("test", "test", &wffc.Name, nil, nil, v1.ClaimPending)
+		client := createClient(wffc)
+		Expect(GetConsumerNodePlacement(client, pvc)).To(BeNil())
+	})
+})
+
 func createClient(objs ...runtime.Object) client.Client {
 	// Register cdi types with the runtime scheme.
 	s := scheme.Scheme
@@ -179,6 +390,74 @@ func createClient(objs ...runtime.Object) client.Client {
 	return fake.NewFakeClientWithScheme(s, objs...)
 }
 
+var _ = Describe("SetRestrictedSecurityContext", func() {
+	createPod := func() *v1.Pod {
+		return &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "worker"}},
+			},
+		}
+	}
+
+	It("Should make the pod comply with the restricted Pod Security Standard by default", func() {
+		client := createClient(MakeEmptyCDIConfigSpec(common.ConfigName))
+		podSecurity, err := GetPodSecurityConfig(client)
+		Expect(err).ToNot(HaveOccurred())
+		pod := createPod()
+		SetRestrictedSecurityContext(pod, podSecurity)
+
+		Expect(*pod.Spec.SecurityContext.RunAsUser).To(Equal(common.QemuSubUID))
+		Expect(*pod.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(*pod.Spec.SecurityContext.FSGroup).To(Equal(common.QemuSubGid))
+		Expect(pod.Spec.SecurityContext.SupplementalGroups).To(BeEmpty())
+		Expect(pod.Spec.SecurityContext.SELinuxOptions).To(BeNil())
+		Expect(pod.Annotations[v1.SeccompPodAnnotationKey]).To(Equal(v1.SeccompProfileRuntimeDefault))
+		securityContext := pod.Spec.Containers[0].SecurityContext
+		Expect(*securityContext.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(securityContext.Capabilities.Drop).To(Equal([]v1.Capability{"ALL"}))
+		Expect(securityContext.Privileged).To(BeNil())
+	})
+
+	It("Should apply the security configuration of the CDIConfig", func() {
+		config := MakeEmptyCDIConfigSpec(common.ConfigName)
+		runAsUser := int64(1000650000)
+		seccompProfile := "localhost/cdi.json"
+		config.Spec.PodSecurity = &cdiv1.PodSecurityConfig{
+			RunAsUser:      &runAsUser,
+			SELinuxOptions: &v1.SELinuxOptions{Type: "spc_t"},
+			SeccompProfile: &seccompProfile,
+		}
+		podSecurity, err := GetPodSecurityConfig(createClient(config))
+		Expect(err).ToNot(HaveOccurred())
+		pod := createPod()
+		SetRestrictedSecurityContext(pod, podSecurity)
+
+		Expect(*pod.Spec.SecurityContext.RunAsUser).To(Equal(runAsUser))
+		Expect(pod.Spec.SecurityContext.SELinuxOptions.Type).To(Equal("spc_t"))
+		Expect(pod.Annotations[v1.SeccompPodAnnotationKey]).To(Equal(seccompProfile))
+	})
+
+	It("Should keep the other security settings of the pod and its containers", func() {
+		podSecurity, err := GetPodSecurityConfig(createClient(MakeEmptyCDIConfigSpec(common.ConfigName)))
+		Expect(err).ToNot(HaveOccurred())
+		readOnlyRootFilesystem := true
+		pod := createPod()
+		pod.Spec.SecurityContext = &v1.PodSecurityContext{SELinuxOptions: &v1.SELinuxOptions{Type: "spc_t"}}
+		pod.Spec.Containers[0].SecurityContext = &v1.SecurityContext{
+			ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+			Capabilities:           &v1.Capabilities{Add: []v1.Capability{"SYS_ADMIN"}},
+		}
+		SetRestrictedSecurityContext(pod, podSecurity)
+
+		Expect(pod.Spec.SecurityContext.SELinuxOptions.Type).To(Equal("spc_t"))
+		securityContext := pod.Spec.Containers[0].SecurityContext
+		Expect(*securityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		Expect(*securityContext.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(securityContext.Capabilities.Add).To(BeEmpty())
+		Expect(securityContext.Capabilities.Drop).To(Equal([]v1.Capability{"ALL"}))
+	})
+})
+
 var _ = Describe("DecodePublicKey", func() {
 	It("Should decode an encoded key", func() {
 		bytes, err := cert.EncodePublicKeyPEM(&getAPIServerKey().PublicKey)
@@ -292,6 +571,67 @@ var _ = Describe("GetPreallocation", func() {
 	})
 })
 
+var _ = Describe("GetTLSConfig", func() {
+	It("Should return nil if the CDIConfig does not exist or has no TLS config", func() {
+		tlsConfig, err := GetTLSConfig(createClient())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig).To(BeNil())
+
+		tlsConfig, err = GetTLSConfig(createClient(createCDIConfig(common.ConfigName)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig).To(BeNil())
+	})
+
+	It("Should return the TLS config of the CDIConfig", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Spec.TLSConfig = &cdiv1.TLSConfig{MinVersion: cdiv1.VersionTLS12}
+		tlsConfig, err := GetTLSConfig(createClient(config))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig).To(Equal(config.Spec.TLSConfig))
+	})
+
+	It("Should fail on an invalid TLS config", func() {
+		config := createCDIConfig(common.ConfigName)
+		config.Spec.TLSConfig = &cdiv1.TLSConfig{Ciphers: []string{"invalid"}}
+		_, err := GetTLSConfig(createClient(config))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unsupported cipher suite"))
+	})
+})
+
+var _ = Describe("GetRegistriesConfig", func() {
+	It("Should return an empty config if the CDIConfig does not exist or has no registries", func() {
+		config, err := GetRegistriesConfig(createClient())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(BeEmpty())
+
+		config, err = GetRegistriesConfig(createClient(createCDIConfig(common.ConfigName)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(BeEmpty())
+	})
+
+	It("Should return the registries of the CDIConfig in JSON", func() {
+		cdiConfig := createCDIConfig(common.ConfigName)
+		cdiConfig.Spec.Registries = []cdiv1.RegistryConfig{{Registry: "quay.io/kubevirt", Mirrors: []cdiv1.RegistryMirror{{Location: "mirror.example.com:5000/kubevirt", Insecure: true}}}}
+		config, err := GetRegistriesConfig(createClient(cdiConfig))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(Equal(`[{"registry":"quay.io/kubevirt","mirrors":[{"location":"mirror.example.com:5000/kubevirt","insecure":true}]}]`))
+	})
+
+	table.DescribeTable("Should fail on an invalid location", func(registry, mirror string) {
+		cdiConfig := createCDIConfig(common.ConfigName)
+		cdiConfig.Spec.Registries = []cdiv1.RegistryConfig{{Registry: registry, Mirrors: []cdiv1.RegistryMirror{{Location: mirror}}}}
+		_, err := GetRegistriesConfig(createClient(cdiConfig))
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("with an empty registry", "", "mirror.example.com"),
+		table.Entry("with a scheme", "https://quay.io", "mirror.example.com"),
+		table.Entry("with a digest", "quay.io", "mirror.example.com/kubevirt@sha256"),
+		table.Entry("with a trailing slash", "quay.io", "mirror.example.com/"),
+		table.Entry("with a space", "quay.io", "mirror.example.com kubevirt"),
+	)
+})
+
 var _ = Describe("GetDefaultStorageClass", func() {
 	It("Should return the default storage class name", func() {
 		client := createClient(
@@ -0,0 +1,201 @@
+package exporter
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var (
+	// cdiTargetRetries is the number of consecutive failed attempts after which the upload fails, the export pod
+	// restarts and resumes the upload again
+	cdiTargetRetries = 10
+	// cdiTargetBackoff is the wait before the first retry, doubled at each consecutive failure up to a minute
+	cdiTargetBackoff = 2 * time.Second
+)
+
+const cdiTargetMaxBackoff = time.Minute
+
+// CDITarget uploads the disk image to an upload DataVolume of another cluster through its upload proxy, with the tus
+// resumable upload protocol. Interrupted uploads resume from the offset the upload server already received.
+type CDITarget struct {
+	client    *http.Client
+	url       string
+	tokenFile string
+}
+
+// NewCDITarget creates a new instance of the CDITarget uploading to the upload proxy at endpoint. The upload token is
+// read from tokenFile before each request, so it can be renewed during the upload, and the certificates of certDir
+// are trusted in addition to the system ones.
+func NewCDITarget(endpoint, tokenFile, certDir string) (*CDITarget, error) {
+	ep, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	if ep.Scheme != "https" && ep.Scheme != "http" {
+		return nil, errors.Errorf("endpoint %q is not an http(s) url", endpoint)
+	}
+	client, err := createHTTPClient(certDir)
+	if err != nil {
+		return nil, err
+	}
+	return &CDITarget{
+		client:    client,
+		url:       strings.TrimSuffix(endpoint, "/"),
+		tokenFile: tokenFile,
+	}, nil
+}
+
+// Write uploads the disk image from its start, the reader cannot seek to resume the upload.
+func (t *CDITarget) Write(reader io.Reader, size int64) error {
+	return t.WriteResumable(reader, size, func(offset int64) error {
+		if offset != 0 {
+			return errors.Errorf("cannot resume the upload at offset %d", offset)
+		}
+		return nil
+	})
+}
+
+// WriteResumable creates the upload unless the upload server already has one of the same size, then sends the disk
+// image from the offset the server received. After a failure the offset is read again from the server and the upload
+// resumes from there, until cdiTargetRetries consecutive attempts failed.
+func (t *CDITarget) WriteResumable(reader io.Reader, size int64, seek func(offset int64) error) error {
+	failures := 0
+	backoff := cdiTargetBackoff
+	for {
+		offset, err := t.resume(size)
+		if err == nil {
+			if offset == size {
+				klog.V(1).Infof("Upload of %d bytes complete", size)
+				return nil
+			}
+			if err = seek(offset); err != nil {
+				return err
+			}
+			klog.V(1).Infof("Uploading from offset %d of %d", offset, size)
+			var received int64
+			received, err = t.patch(reader, offset, size)
+			if received > offset {
+				failures = 0
+				backoff = cdiTargetBackoff
+			}
+			if err == nil && received == size {
+				// The upload server processes the image now, it may not answer other requests
+				klog.V(1).Infof("Upload of %d bytes complete", size)
+				return nil
+			}
+			if err == nil {
+				continue
+			}
+		}
+
+		failures++
+		if failures >= cdiTargetRetries {
+			return errors.Wrapf(err, "upload failed after %d attempts", failures)
+		}
+		klog.Warningf("Upload interrupted, resuming in %s: %v", backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > cdiTargetMaxBackoff {
+			backoff = cdiTargetMaxBackoff
+		}
+	}
+}
+
+// resume returns the offset the upload server received, creating the upload if the server has none of the size
+func (t *CDITarget) resume(size int64) (int64, error) {
+	resp, err := t.do(http.MethodHead, t.url+common.UploadPathTus+"/"+common.TusUploadID, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		length, _ := strconv.ParseInt(resp.Header.Get(common.TusUploadLengthHeader), 10, 64)
+		offset, err := strconv.ParseInt(resp.Header.Get(common.TusUploadOffsetHeader), 10, 64)
+		if err == nil && length == size {
+			return offset, nil
+		}
+		klog.V(1).Infof("Replacing the upload of %d bytes on the server", length)
+	} else if resp.StatusCode != http.StatusNotFound {
+		return 0, statusError("could not get the upload offset", resp)
+	}
+
+	resp, err = t.do(http.MethodPost, t.url+common.UploadPathTus, nil, map[string]string{
+		common.TusUploadLengthHeader: strconv.FormatInt(size, 10),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return 0, statusError("could not create the upload", resp)
+	}
+	return 0, nil
+}
+
+// patch sends the disk image from the offset, it returns the offset the server received
+func (t *CDITarget) patch(reader io.Reader, offset, size int64) (int64, error) {
+	req, err := t.newRequest(http.MethodPatch, t.url+common.UploadPathTus+"/"+common.TusUploadID, ioutil.NopCloser(io.LimitReader(reader, size-offset)), map[string]string{
+		"Content-Type":               "application/offset+octet-stream",
+		common.TusUploadOffsetHeader: strconv.FormatInt(offset, 10),
+	})
+	if err != nil {
+		return offset, err
+	}
+	req.ContentLength = size - offset
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return offset, errors.Wrap(err, "could not send the disk image")
+	}
+	defer resp.Body.Close()
+	received, err := strconv.ParseInt(resp.Header.Get(common.TusUploadOffsetHeader), 10, 64)
+	if err != nil {
+		received = offset
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return received, statusError("could not send the disk image", resp)
+	}
+	return received, nil
+}
+
+func (t *CDITarget) do(method, location string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := t.newRequest(method, location, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not reach the upload proxy %s", t.url)
+	}
+	return resp, nil
+}
+
+func (t *CDITarget) newRequest(method, location string, body io.Reader, headers map[string]string) (*http.Request, error) {
+	token, err := ioutil.ReadFile(t.tokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the upload token")
+	}
+	req, err := http.NewRequest(method, location, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create %s request", method)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set(common.TusResumableHeader, common.TusVersion)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+func statusError(message string, resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.Errorf("%s: %s %s", message, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,168 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("CDI target", func() {
+	var (
+		tmpDir    string
+		tokenFile string
+		received  []byte
+		length    int64
+		creations int
+		interrupt int
+		tokens    []string
+		server    *httptest.Server
+	)
+
+	// handler is a minimal tus server like the upload server, keeping the data received before an interruption
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if r.Header.Get(common.TusResumableHeader) != common.TusVersion {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		switch r.Method {
+		case http.MethodHead:
+			if length < 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set(common.TusUploadOffsetHeader, strconv.Itoa(len(received)))
+			w.Header().Set(common.TusUploadLengthHeader, strconv.FormatInt(length, 10))
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPost:
+			length, _ = strconv.ParseInt(r.Header.Get(common.TusUploadLengthHeader), 10, 64)
+			received = nil
+			creations++
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPatch:
+			if r.Header.Get(common.TusUploadOffsetHeader) != strconv.Itoa(len(received)) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			var data []byte
+			if interrupt > 0 {
+				data, _ = ioutil.ReadAll(io.LimitReader(r.Body, int64(interrupt)))
+				interrupt = 0
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				data, _ = ioutil.ReadAll(r.Body)
+			}
+			received = append(received, data...)
+			w.Header().Set(common.TusUploadOffsetHeader, strconv.Itoa(len(received)))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cdi-target")
+		Expect(err).ToNot(HaveOccurred())
+		tokenFile = filepath.Join(tmpDir, "token")
+		Expect(ioutil.WriteFile(tokenFile, []byte("upload-token\n"), 0600)).To(Succeed())
+		received = nil
+		length = -1
+		creations = 0
+		interrupt = 0
+		tokens = nil
+		cdiTargetBackoff = time.Millisecond
+		server = httptest.NewServer(handler)
+	})
+
+	AfterEach(func() {
+		server.Close()
+		cdiTargetBackoff = 2 * time.Second
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Should create the upload and send the disk image with the upload token", func() {
+		target, err := NewCDITarget(server.URL+"/", tokenFile, "")
+		Expect(err).ToNot(HaveOccurred())
+		data := []byte("disk image content")
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+		Expect(received).To(Equal(data))
+		Expect(creations).To(Equal(1))
+		Expect(tokens).ToNot(BeEmpty())
+		for _, token := range tokens {
+			Expect(token).To(Equal("Bearer upload-token"))
+		}
+	})
+
+	It("Should resume an interrupted upload from the offset of the server", func() {
+		target, err := NewCDITarget(server.URL, tokenFile, "")
+		Expect(err).ToNot(HaveOccurred())
+		data := []byte("disk image content")
+		file := bytes.NewReader(data)
+		var seeks []int64
+		interrupt = 5
+		err = target.WriteResumable(file, int64(len(data)), func(offset int64) error {
+			seeks = append(seeks, offset)
+			_, err := file.Seek(offset, io.SeekStart)
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(received).To(Equal(data))
+		Expect(seeks).To(Equal([]int64{0, 5}))
+		Expect(creations).To(Equal(1))
+	})
+
+	It("Should resume the upload of a previous export pod", func() {
+		data := []byte("disk image content")
+		length = int64(len(data))
+		received = data[:8]
+		target, err := NewCDITarget(server.URL, tokenFile, "")
+		Expect(err).ToNot(HaveOccurred())
+		file := bytes.NewReader(data)
+		err = target.WriteResumable(file, int64(len(data)), func(offset int64) error {
+			Expect(offset).To(Equal(int64(8)))
+			_, err := file.Seek(offset, io.SeekStart)
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(received).To(Equal(data))
+		Expect(creations).To(BeZero())
+	})
+
+	It("Should replace an upload of another size", func() {
+		length = 100
+		received = []byte("other")
+		target, err := NewCDITarget(server.URL, tokenFile, "")
+		Expect(err).ToNot(HaveOccurred())
+		data := []byte("disk image content")
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+		Expect(received).To(Equal(data))
+		Expect(creations).To(Equal(1))
+	})
+
+	It("Should fail after too many failed attempts", func() {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		target, err := NewCDITarget(failing.URL, tokenFile, "")
+		Expect(err).ToNot(HaveOccurred())
+		err = target.Write(bytes.NewReader([]byte("disk")), 4)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("upload failed after 10 attempts"))
+	})
+
+	It("Should reject an endpoint which is not an http(s) url", func() {
+		_, err := NewCDITarget("ftp://cdi-uploadproxy.example.com", tokenFile, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
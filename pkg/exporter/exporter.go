@@ -0,0 +1,129 @@
+package exporter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+const qcow2ImageName = "disk.qcow2"
+
+// Target is the destination an exported disk image is written to.
+type Target interface {
+	// Write writes the size bytes of the disk image read from reader to the target.
+	Write(reader io.Reader, size int64) error
+}
+
+// ResumableTarget is a Target which resumes interrupted writes from the data it already received.
+type ResumableTarget interface {
+	Target
+	// WriteResumable writes the size bytes of the disk image read from reader to the target, calling seek to move the
+	// reader to the offset the target already received before each attempt.
+	WriteResumable(reader io.Reader, size int64, seek func(offset int64) error) error
+}
+
+var (
+	progress = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "export_progress",
+			Help: "The export progress in percentage",
+		},
+		[]string{"ownerUID"},
+	)
+
+	// may be overridden in tests
+	convertToQcow2 = image.ConvertToQcow2
+)
+
+func init() {
+	if err := prometheus.Register(progress); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			progress = are.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			klog.Errorf("Unable to create prometheus progress counter")
+		}
+	}
+}
+
+// Export writes the raw disk image file or block device source to the target. A qcow2 image is converted in
+// scratchDir first, qcow2 images cannot be written as a stream, and its clusters are compressed with zlib unless
+// another compression is set. The converted image is kept until it is written, so a restarted export pod resumes
+// writing the same image to a ResumableTarget. The progress of the write is reported to prometheus with the ownerUID
+// label.
+func Export(source string, format cdiv1.DataExportFormat, compression cdiv1.DataExportCompression, scratchDir string, target Target, ownerUID string) error {
+	exported := source
+	if format != cdiv1.DataExportFormatRaw {
+		var err error
+		if exported, err = convertSource(source, compression, scratchDir); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(exported)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", exported)
+	}
+	defer file.Close()
+	// Stat does not return the size of block devices
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrapf(err, "could not get the size of %s", exported)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "could not rewind %s", exported)
+	}
+
+	klog.V(1).Infof("Writing %d bytes of %s", size, exported)
+	reader := prometheusutil.NewProgressReader(file, uint64(size), progress, ownerUID)
+	reader.StartTimedUpdate()
+	if resumable, ok := target.(ResumableTarget); ok {
+		err = resumable.WriteResumable(reader, size, func(offset int64) error {
+			if _, err := file.Seek(offset, io.SeekStart); err != nil {
+				return errors.Wrapf(err, "could not seek %s to %d", exported, offset)
+			}
+			reader.Current = uint64(offset)
+			return nil
+		})
+	} else {
+		err = target.Write(reader, size)
+	}
+	if err != nil {
+		return err
+	}
+	if exported != source {
+		os.Remove(exported)
+	}
+	return nil
+}
+
+// convertSource converts the source to a qcow2 image in scratchDir, unless an earlier run of the export pod already did
+func convertSource(source string, compression cdiv1.DataExportCompression, scratchDir string) (string, error) {
+	if compression == "" {
+		compression = cdiv1.DataExportCompressionZlib
+	}
+	exported := filepath.Join(scratchDir, qcow2ImageName)
+	if _, err := os.Stat(exported); err == nil {
+		klog.V(1).Infof("Reusing %s converted by a previous run", exported)
+		return exported, nil
+	}
+
+	// The image is renamed once complete, an interrupted conversion is not reused
+	converting := exported + ".part"
+	klog.V(1).Infof("Converting %s to %s with %s compression", source, converting, compression)
+	if err := convertToQcow2(source, converting, string(compression)); err != nil {
+		os.Remove(converting)
+		return "", err
+	}
+	if err := os.Rename(converting, exported); err != nil {
+		return "", errors.Wrapf(err, "could not rename %s", converting)
+	}
+	return exported, nil
+}
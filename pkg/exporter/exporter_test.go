@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+type fakeTarget struct {
+	written []byte
+	size    int64
+}
+
+func (t *fakeTarget) Write(reader io.Reader, size int64) error {
+	var err error
+	t.size = size
+	t.written, err = ioutil.ReadAll(reader)
+	return err
+}
+
+type fakeResumableTarget struct {
+	fakeTarget
+	offset int64
+	err    error
+}
+
+func (t *fakeResumableTarget) WriteResumable(reader io.Reader, size int64, seek func(offset int64) error) error {
+	if err := seek(t.offset); err != nil {
+		return err
+	}
+	if t.err != nil {
+		return t.err
+	}
+	return t.Write(reader, size)
+}
+
+var _ = Describe("Export", func() {
+	var (
+		tmpDir string
+		source string
+		target *fakeTarget
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "export")
+		Expect(err).ToNot(HaveOccurred())
+		source = filepath.Join(tmpDir, "disk.img")
+		Expect(ioutil.WriteFile(source, bytes.Repeat([]byte("raw"), 1024), 0644)).To(Succeed())
+		target = &fakeTarget{}
+	})
+
+	AfterEach(func() {
+		convertToQcow2 = image.ConvertToQcow2
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should write a raw disk as is", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			Fail("a raw export should not be converted")
+			return nil
+		}
+		Expect(Export(source, cdiv1.DataExportFormatRaw, "", tmpDir, target, "uid")).To(Succeed())
+		Expect(target.size).To(Equal(int64(3072)))
+		Expect(target.written).To(Equal(bytes.Repeat([]byte("raw"), 1024)))
+	})
+
+	It("should convert the disk to qcow2 in the scratch space by default", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			Expect(src).To(Equal(source))
+			Expect(dest).To(Equal(filepath.Join(tmpDir, qcow2ImageName+".part")))
+			Expect(compression).To(Equal("zlib"))
+			return ioutil.WriteFile(dest, []byte("QFI\xfb"), 0644)
+		}
+		Expect(Export(source, "", "", tmpDir, target, "uid")).To(Succeed())
+		Expect(target.size).To(Equal(int64(4)))
+		Expect(target.written).To(Equal([]byte("QFI\xfb")))
+		Expect(filepath.Join(tmpDir, qcow2ImageName)).ToNot(BeAnExistingFile())
+	})
+
+	It("should convert the disk with the compression", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			Expect(compression).To(Equal("zstd"))
+			return ioutil.WriteFile(dest, []byte("QFI\xfb"), 0644)
+		}
+		Expect(Export(source, cdiv1.DataExportFormatQcow2, cdiv1.DataExportCompressionZstd, tmpDir, target, "uid")).To(Succeed())
+	})
+
+	It("should fail if the conversion fails", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			return errors.New("could not convert image to qcow2")
+		}
+		err := Export(source, cdiv1.DataExportFormatQcow2, "", tmpDir, target, "uid")
+		Expect(err).To(MatchError("could not convert image to qcow2"))
+		Expect(target.written).To(BeNil())
+	})
+
+	It("should reuse the disk converted by a previous run", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			Fail("a converted disk should not be converted again")
+			return nil
+		}
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, qcow2ImageName), []byte("QFI\xfb"), 0644)).To(Succeed())
+		Expect(Export(source, cdiv1.DataExportFormatQcow2, "", tmpDir, target, "uid")).To(Succeed())
+		Expect(target.written).To(Equal([]byte("QFI\xfb")))
+	})
+
+	It("should keep the converted disk when the write fails", func() {
+		convertToQcow2 = func(src, dest, compression string) error {
+			return ioutil.WriteFile(dest, []byte("QFI\xfb"), 0644)
+		}
+		resumable := &fakeResumableTarget{err: errors.New("upload interrupted")}
+		Expect(Export(source, cdiv1.DataExportFormatQcow2, "", tmpDir, resumable, "uid")).To(MatchError("upload interrupted"))
+		Expect(filepath.Join(tmpDir, qcow2ImageName)).To(BeAnExistingFile())
+	})
+
+	It("should seek to the offset of a resumable target", func() {
+		resumable := &fakeResumableTarget{offset: 3000}
+		Expect(Export(source, cdiv1.DataExportFormatRaw, "", tmpDir, resumable, "uid")).To(Succeed())
+		Expect(resumable.size).To(Equal(int64(3072)))
+		Expect(resumable.written).To(Equal(bytes.Repeat([]byte("raw"), 1024)[3000:]))
+	})
+
+	It("should fail if the source does not exist", func() {
+		err := Export(filepath.Join(tmpDir, "missing.img"), cdiv1.DataExportFormatRaw, "", tmpDir, target, "uid")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not open"))
+	})
+})
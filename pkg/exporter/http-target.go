@@ -0,0 +1,116 @@
+package exporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// HTTPTarget writes the disk image with an HTTP PUT, as WebDAV servers and artifact stores like Nexus or
+// Artifactory accept.
+type HTTPTarget struct {
+	client   *http.Client
+	url      string
+	user     string
+	password string
+}
+
+// NewHTTPTarget creates a new instance of the HTTPTarget writing to the endpoint. The user and password are sent
+// with basic authentication when set, and the certificates of certDir are trusted in addition to the system ones.
+func NewHTTPTarget(endpoint, user, password, certDir string) (*HTTPTarget, error) {
+	ep, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	if ep.Scheme != "http" && ep.Scheme != "https" {
+		return nil, errors.Errorf("endpoint %q is not an http(s) url", endpoint)
+	}
+	client, err := createHTTPClient(certDir)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPTarget{
+		client:   client,
+		url:      endpoint,
+		user:     user,
+		password: password,
+	}, nil
+}
+
+// Write sends the disk image as the body of a single PUT request.
+func (t *HTTPTarget) Write(reader io.Reader, size int64) error {
+	req, err := http.NewRequest(http.MethodPut, t.url, ioutil.NopCloser(reader))
+	if err != nil {
+		return errors.Wrap(err, "could not create PUT request")
+	}
+	// Servers reject chunked uploads of unknown length more often than fixed ones
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if t.user != "" || t.password != "" {
+		req.SetBasicAuth(t.user, t.password)
+	}
+
+	klog.V(1).Infof("Writing the disk image to %s", t.url)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "could not write the disk image to %s", t.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("could not write the disk image to %s: %s %s", t.url, resp.Status, string(body))
+	}
+	return nil
+}
+
+func createHTTPClient(certDir string) (*http.Client, error) {
+	client := &http.Client{}
+	if certDir == "" {
+		return client, nil
+	}
+
+	certPool, err := createCertPool(certDir)
+	if err != nil {
+		return nil, err
+	}
+	client.Transport = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			RootCAs: certPool,
+		},
+	}
+	return client, nil
+}
+
+func createCertPool(certDir string) (*x509.CertPool, error) {
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting system certs")
+	}
+
+	files, err := ioutil.ReadDir(certDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing files in %s", certDir)
+	}
+	for _, file := range files {
+		if file.IsDir() || file.Name()[0] == '.' {
+			continue
+		}
+		fp := filepath.Join(certDir, file.Name())
+		certs, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading file %s", fp)
+		}
+		if ok := certPool.AppendCertsFromPEM(certs); !ok {
+			klog.Warningf("No certs in %s", fp)
+		}
+	}
+	return certPool, nil
+}
@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP target", func() {
+	var (
+		received []byte
+		method   string
+		user     string
+		password string
+		status   int
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		user, password, _ = r.BasicAuth()
+		received, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	})
+
+	BeforeEach(func() {
+		received = nil
+		method = ""
+		user = ""
+		password = ""
+		status = http.StatusCreated
+	})
+
+	It("Should PUT the disk image with basic authentication", func() {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		target, err := NewHTTPTarget(server.URL+"/repository/disks/fedora.qcow2", "user", "pass", "")
+		Expect(err).ToNot(HaveOccurred())
+		data := []byte("disk image content")
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+		Expect(method).To(Equal(http.MethodPut))
+		Expect(received).To(Equal(data))
+		Expect(user).To(Equal("user"))
+		Expect(password).To(Equal("pass"))
+	})
+
+	It("Should fail when the server rejects the disk image", func() {
+		status = http.StatusForbidden
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		target, err := NewHTTPTarget(server.URL+"/fedora.qcow2", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		err = target.Write(bytes.NewReader([]byte("data")), 4)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("403"))
+	})
+
+	It("Should trust the certificate authorities of the cert dir", func() {
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+		certDir, err := ioutil.TempDir("", "certs")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(certDir)
+
+		target, err := NewHTTPTarget(server.URL+"/fedora.qcow2", "", "", certDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.Write(bytes.NewReader([]byte("data")), 4)).ToNot(Succeed())
+
+		certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		Expect(ioutil.WriteFile(filepath.Join(certDir, "ca.pem"), certPEM, 0644)).To(Succeed())
+		target, err = NewHTTPTarget(server.URL+"/fedora.qcow2", "", "", certDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.Write(bytes.NewReader([]byte("data")), 4)).To(Succeed())
+		Expect(received).To(Equal([]byte("data")))
+	})
+
+	It("Should reject endpoints that are not http urls", func() {
+		_, err := NewHTTPTarget("ftp://example.com/fedora.qcow2", "", "", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,166 @@
+package exporter
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// containerDiskDir is the directory KubeVirt reads the disk image of containerDisks from
+	containerDiskDir = "disk/"
+	// containerDiskOwner is the qemu user of the virt-launcher pods, which owns the disk image
+	containerDiskOwner = 107
+)
+
+// RegistryTarget pushes the disk image as a containerDisk image, whose single layer holds the disk image in the
+// /disk directory.
+type RegistryTarget struct {
+	ref      types.ImageReference
+	sys      *types.SystemContext
+	diskName string
+}
+
+// NewRegistryTarget creates a new instance of the RegistryTarget pushing to the image of the endpoint, of the form
+// docker://registry/repository:tag. The credentials of the registry are read from the authFile, a docker config
+// json file, and the certificates of certDir are trusted in addition to the system ones.
+func NewRegistryTarget(endpoint, authFile, certDir, diskName string) (*RegistryTarget, error) {
+	ref, err := parseImageName(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	sys := &types.SystemContext{
+		AuthFilePath: authFile,
+	}
+	if certDir != "" {
+		sys.DockerCertPath = certDir
+	}
+	return &RegistryTarget{
+		ref:      ref,
+		sys:      sys,
+		diskName: diskName,
+	}, nil
+}
+
+// Write pushes the layer holding the disk image, then the config and the manifest of the image.
+func (t *RegistryTarget) Write(reader io.Reader, size int64) error {
+	ctx := context.Background()
+	dest, err := t.ref.NewImageDestination(ctx, t.sys)
+	if err != nil {
+		return errors.Wrapf(err, "could not open image %s", t.ref.StringWithinTransport())
+	}
+	defer dest.Close()
+
+	klog.V(1).Infof("Pushing the disk image to %s", t.ref.StringWithinTransport())
+	layer, err := t.putLayer(ctx, dest, reader, size)
+	if err != nil {
+		return errors.Wrap(err, "could not push the layer of the disk image")
+	}
+
+	config, err := json.Marshal(imgspecv1.Image{
+		Architecture: runtime.GOARCH,
+		OS:           "linux",
+		RootFS: imgspecv1.RootFS{
+			Type: "layers",
+			// The layer is not compressed
+			DiffIDs: []digest.Digest{layer.Digest},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	configInfo, err := dest.PutBlob(ctx, bytes.NewReader(config), types.BlobInfo{Digest: digest.FromBytes(config), Size: int64(len(config))}, none.NoCache, true)
+	if err != nil {
+		return errors.Wrap(err, "could not push the config of the image")
+	}
+
+	manifest, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config: imgspecv1.Descriptor{
+			MediaType: imgspecv1.MediaTypeImageConfig,
+			Digest:    configInfo.Digest,
+			Size:      configInfo.Size,
+		},
+		Layers: []imgspecv1.Descriptor{
+			{
+				MediaType: imgspecv1.MediaTypeImageLayer,
+				Digest:    layer.Digest,
+				Size:      layer.Size,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := dest.PutManifest(ctx, manifest, nil); err != nil {
+		return errors.Wrap(err, "could not push the manifest of the image")
+	}
+	return dest.Commit(ctx, nil)
+}
+
+// putLayer streams the tar layer to the destination, the digest of the layer is computed while it is pushed.
+func (t *RegistryTarget) putLayer(ctx context.Context, dest types.ImageDestination, reader io.Reader, size int64) (types.BlobInfo, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeContainerDiskLayer(pw, reader, size, t.diskName))
+	}()
+	info, err := dest.PutBlob(ctx, pr, types.BlobInfo{Size: -1}, none.NoCache, false)
+	// Unblocks the writer when the push failed
+	pr.Close()
+	return info, err
+}
+
+func writeContainerDiskLayer(writer io.Writer, reader io.Reader, size int64, diskName string) error {
+	tw := tar.NewWriter(writer)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     containerDiskDir,
+		Mode:     0555,
+		Uid:      containerDiskOwner,
+		Gid:      containerDiskOwner,
+	}); err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     containerDiskDir + diskName,
+		Mode:     0440,
+		Uid:      containerDiskOwner,
+		Gid:      containerDiskOwner,
+		Size:     size,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, reader); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func parseImageName(img string) (types.ImageReference, error) {
+	parts := strings.SplitN(img, ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf(`Invalid image name "%s", expected colon-separated transport:reference`, img)
+	}
+	switch parts[0] {
+	case "docker":
+		return docker.ParseReference(parts[1])
+	case "oci":
+		return layout.ParseReference(parts[1])
+	}
+	return nil, errors.Errorf(`Invalid image name "%s", unknown transport`, img)
+}
@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = Describe("Registry target", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "registry-target")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	readBlob := func(d digest.Digest) []byte {
+		data, err := ioutil.ReadFile(filepath.Join(tmpDir, "blobs", d.Algorithm().String(), d.Hex()))
+		Expect(err).ToNot(HaveOccurred())
+		return data
+	}
+
+	It("Should push the disk image as a containerDisk", func() {
+		target, err := NewRegistryTarget("oci:"+tmpDir+":fedora", "", "", "disk.qcow2")
+		Expect(err).ToNot(HaveOccurred())
+		data := []byte("qcow2 disk image content")
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+
+		index := imgspecv1.Index{}
+		indexJSON, err := ioutil.ReadFile(filepath.Join(tmpDir, "index.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(indexJSON, &index)).To(Succeed())
+		Expect(index.Manifests).To(HaveLen(1))
+
+		manifest := imgspecv1.Manifest{}
+		Expect(json.Unmarshal(readBlob(index.Manifests[0].Digest), &manifest)).To(Succeed())
+		Expect(manifest.Config.MediaType).To(Equal(imgspecv1.MediaTypeImageConfig))
+		Expect(manifest.Layers).To(HaveLen(1))
+
+		config := imgspecv1.Image{}
+		Expect(json.Unmarshal(readBlob(manifest.Config.Digest), &config)).To(Succeed())
+		Expect(config.RootFS.DiffIDs).To(Equal([]digest.Digest{manifest.Layers[0].Digest}))
+
+		tr := tar.NewReader(bytes.NewReader(readBlob(manifest.Layers[0].Digest)))
+		header, err := tr.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(header.Name).To(Equal("disk/"))
+		header, err = tr.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(header.Name).To(Equal("disk/disk.qcow2"))
+		Expect(header.Uid).To(Equal(107))
+		Expect(header.Gid).To(Equal(107))
+		content, err := ioutil.ReadAll(tr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(data))
+		_, err = tr.Next()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("Should reject images of unknown transports", func() {
+		_, err := NewRegistryTarget("ftp://registry.example.com/fedora", "", "", "disk.img")
+		Expect(err).To(HaveOccurred())
+	})
+})
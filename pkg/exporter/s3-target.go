@@ -0,0 +1,180 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultS3PartSize is the size of the parts of the multipart upload when none is set
+	DefaultS3PartSize = 64 << 20
+	// the limits of S3 multipart uploads
+	minS3PartSize  = 5 << 20
+	maxS3PartCount = 10000
+)
+
+// S3Client is the interface to the S3 client used to write the disk image.
+type S3Client interface {
+	CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// may be overridden in tests
+var newS3ClientFunc = getS3Client
+
+// S3Target writes the disk image to an S3 object with a multipart upload.
+type S3Target struct {
+	client   S3Client
+	bucket   string
+	key      string
+	partSize int64
+}
+
+// NewS3Target creates a new instance of the S3Target writing to the object of the endpoint, an URL of the form
+// https://host/bucket/key. Without keys, the credentials of the environment of the pod are used.
+func NewS3Target(endpoint, accessKey, secKey, region string, partSize int64) (*S3Target, error) {
+	ep, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	bucket, key := extractBucketAndObject(strings.Trim(ep.Path, "/"))
+	if bucket == "" || key == "" {
+		return nil, errors.Errorf("endpoint %q does not contain a bucket and an object", endpoint)
+	}
+	if region == "" {
+		region = extractRegion(ep.Host)
+	}
+	client, err := newS3ClientFunc(ep.Scheme+"://"+ep.Host, accessKey, secKey, region)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
+	}
+	if partSize <= 0 {
+		partSize = DefaultS3PartSize
+	}
+	if partSize < minS3PartSize {
+		partSize = minS3PartSize
+	}
+	return &S3Target{
+		client:   client,
+		bucket:   bucket,
+		key:      key,
+		partSize: partSize,
+	}, nil
+}
+
+// Write uploads the disk image in parts, the upload is aborted if a part fails.
+func (t *S3Target) Write(reader io.Reader, size int64) error {
+	partSize := t.partSize
+	if size > partSize*maxS3PartCount {
+		// Round up to a multiple of 1MiB
+		partSize = ((size/maxS3PartCount)/(1<<20) + 1) << 20
+		klog.V(1).Infof("Increasing the part size to %d bytes to upload %d bytes", partSize, size)
+	}
+
+	upload, err := t.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String(t.bucket),
+		Key:    aws.String(t.key),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not create the upload of s3 object: \"%s/%s\"", t.bucket, t.key)
+	}
+
+	parts, err := t.uploadParts(reader, upload.UploadId, partSize)
+	if err != nil {
+		if _, abortErr := t.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(t.bucket),
+			Key:      aws.String(t.key),
+			UploadId: upload.UploadId,
+		}); abortErr != nil {
+			klog.Errorf("Could not abort the upload of s3 object \"%s/%s\": %v", t.bucket, t.key, abortErr)
+		}
+		return err
+	}
+
+	if _, err := t.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(t.bucket),
+		Key:             aws.String(t.key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}); err != nil {
+		return errors.Wrapf(err, "could not complete the upload of s3 object: \"%s/%s\"", t.bucket, t.key)
+	}
+	klog.V(1).Infof("Uploaded s3 object \"%s/%s\" in %d parts", t.bucket, t.key, len(parts))
+	return nil
+}
+
+func (t *S3Target) uploadParts(reader io.Reader, uploadID *string, partSize int64) ([]*s3.CompletedPart, error) {
+	var parts []*s3.CompletedPart
+	buf := make([]byte, partSize)
+	for partNumber := int64(1); ; partNumber++ {
+		n, err := io.ReadFull(reader, buf)
+		if err == io.EOF && partNumber > 1 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, errors.Wrap(err, "could not read the disk image")
+		}
+		output, uploadErr := t.client.UploadPart(&s3.UploadPartInput{
+			Bucket:     aws.String(t.bucket),
+			Key:        aws.String(t.key),
+			UploadId:   uploadID,
+			PartNumber: aws.Int64(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if uploadErr != nil {
+			return nil, errors.Wrapf(uploadErr, "could not upload part %d of s3 object: \"%s/%s\"", partNumber, t.bucket, t.key)
+		}
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       output.ETag,
+			PartNumber: aws.Int64(partNumber),
+		})
+		if err != nil {
+			// The last part was shorter than the part size
+			break
+		}
+	}
+	return parts, nil
+}
+
+func getS3Client(endpoint, accessKey, secKey, region string) (S3Client, error) {
+	config := &aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+	if accessKey != "" || secKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(accessKey, secKey, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return s3.New(sess), nil
+}
+
+func extractRegion(host string) string {
+	if matches := regexp.MustCompile("s3\\.(.+)\\.amazonaws\\.com").FindStringSubmatch(host); matches != nil {
+		return matches[1]
+	}
+	return strings.Split(host, ".")[0]
+}
+
+func extractBucketAndObject(path string) (string, string) {
+	pathSplit := strings.SplitN(path, "/", 2)
+	if len(pathSplit) < 2 {
+		return pathSplit[0], ""
+	}
+	return pathSplit[0], pathSplit[1]
+}
@@ -0,0 +1,131 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type fakeS3Client struct {
+	endpoint  string
+	region    string
+	accessKey string
+	parts     map[int64][]byte
+	completed *s3.CompleteMultipartUploadInput
+	aborted   bool
+	failPart  int64
+}
+
+func (c *fakeS3Client) CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+	Expect(aws.StringValue(input.Bucket)).To(Equal("bucket"))
+	Expect(aws.StringValue(input.Key)).To(Equal("images/fedora.qcow2"))
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+}
+
+func (c *fakeS3Client) UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	Expect(aws.StringValue(input.UploadId)).To(Equal("upload-1"))
+	partNumber := aws.Int64Value(input.PartNumber)
+	if partNumber == c.failPart {
+		return nil, errors.New("connection reset")
+	}
+	data, err := ioutil.ReadAll(input.Body)
+	Expect(err).ToNot(HaveOccurred())
+	c.parts[partNumber] = data
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", partNumber))}, nil
+}
+
+func (c *fakeS3Client) CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	c.completed = input
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (c *fakeS3Client) AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+	Expect(aws.StringValue(input.UploadId)).To(Equal("upload-1"))
+	c.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+var _ = Describe("S3 target", func() {
+	var client *fakeS3Client
+
+	BeforeEach(func() {
+		client = &fakeS3Client{parts: map[int64][]byte{}}
+		newS3ClientFunc = func(endpoint, accessKey, secKey, region string) (S3Client, error) {
+			client.endpoint = endpoint
+			client.region = region
+			client.accessKey = accessKey
+			return client, nil
+		}
+	})
+
+	AfterEach(func() {
+		newS3ClientFunc = getS3Client
+	})
+
+	It("should derive the bucket, the object and the region from the URL", func() {
+		target, err := NewS3Target("https://s3.us-east-2.amazonaws.com/bucket/images/fedora.qcow2", "access", "secret", "", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.bucket).To(Equal("bucket"))
+		Expect(target.key).To(Equal("images/fedora.qcow2"))
+		Expect(target.partSize).To(Equal(int64(DefaultS3PartSize)))
+		Expect(client.endpoint).To(Equal("https://s3.us-east-2.amazonaws.com"))
+		Expect(client.region).To(Equal("us-east-2"))
+		Expect(client.accessKey).To(Equal("access"))
+	})
+
+	It("should use the region and part size that are set", func() {
+		target, err := NewS3Target("http://minio.example.com:9000/bucket/images/fedora.qcow2", "", "", "eu-west-1", 128<<20)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.partSize).To(Equal(int64(128 << 20)))
+		Expect(client.endpoint).To(Equal("http://minio.example.com:9000"))
+		Expect(client.region).To(Equal("eu-west-1"))
+	})
+
+	It("should fail if the URL has no object", func() {
+		_, err := NewS3Target("https://s3.amazonaws.com/bucket", "", "", "", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should upload the disk in parts", func() {
+		target, err := NewS3Target("https://s3.amazonaws.com/bucket/images/fedora.qcow2", "", "", "", minS3PartSize)
+		Expect(err).ToNot(HaveOccurred())
+		data := bytes.Repeat([]byte("a"), 2*minS3PartSize+100)
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+		Expect(client.parts).To(HaveLen(3))
+		Expect(client.parts[1]).To(HaveLen(minS3PartSize))
+		Expect(client.parts[2]).To(HaveLen(minS3PartSize))
+		Expect(client.parts[3]).To(HaveLen(100))
+		Expect(client.completed).ToNot(BeNil())
+		Expect(client.completed.MultipartUpload.Parts).To(HaveLen(3))
+		Expect(aws.StringValue(client.completed.MultipartUpload.Parts[2].ETag)).To(Equal("etag-3"))
+		Expect(aws.Int64Value(client.completed.MultipartUpload.Parts[2].PartNumber)).To(Equal(int64(3)))
+		Expect(client.aborted).To(BeFalse())
+	})
+
+	It("should not upload an empty last part", func() {
+		target, err := NewS3Target("https://s3.amazonaws.com/bucket/images/fedora.qcow2", "", "", "", minS3PartSize)
+		Expect(err).ToNot(HaveOccurred())
+		data := bytes.Repeat([]byte("a"), 2*minS3PartSize)
+		Expect(target.Write(bytes.NewReader(data), int64(len(data)))).To(Succeed())
+		Expect(client.parts).To(HaveLen(2))
+		Expect(client.completed.MultipartUpload.Parts).To(HaveLen(2))
+	})
+
+	It("should abort the upload if a part fails", func() {
+		client.failPart = 2
+		target, err := NewS3Target("https://s3.amazonaws.com/bucket/images/fedora.qcow2", "", "", "", minS3PartSize)
+		Expect(err).ToNot(HaveOccurred())
+		data := bytes.Repeat([]byte("a"), 2*minS3PartSize+100)
+		err = target.Write(bytes.NewReader(data), int64(len(data)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not upload part 2"))
+		Expect(client.aborted).To(BeTrue())
+		Expect(client.completed).To(BeNil())
+	})
+})
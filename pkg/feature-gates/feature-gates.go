@@ -2,6 +2,8 @@ package featuregates
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -14,12 +16,20 @@ import (
 const (
 	// HonorWaitForFirstConsumer - if enabled will not schedule worker pods on a storage with WaitForFirstConsumer binding mode
 	HonorWaitForFirstConsumer = "HonorWaitForFirstConsumer"
+	// FileSourceHostPath - if enabled the file source of data volumes may mount directories of the nodes
+	FileSourceHostPath = "FileSourceHostPath"
 )
 
 // FeatureGates is a util for determining whether an optional feature is enabled or not.
 type FeatureGates interface {
 	// HonorWaitForFirstConsumerEnabled - see the HonorWaitForFirstConsumer const
 	HonorWaitForFirstConsumerEnabled() (bool, error)
+	// FileSourceHostPathEnabled - see the FileSourceHostPath const
+	FileSourceHostPathEnabled() (bool, error)
+	// Enabled checks if the feature gate is enabled, for the experimental features without a dedicated method
+	Enabled(featureGate string) (bool, error)
+	// EnabledFeatureGates returns the sorted feature gates that are enabled, to pass them to the CDI pods
+	EnabledFeatureGates() ([]string, error)
 }
 
 // CDIConfigFeatureGates is a util for determining whether an optional feature is enabled or not.
@@ -32,7 +42,8 @@ func NewFeatureGates(c client.Client) *CDIConfigFeatureGates {
 	return &CDIConfigFeatureGates{client: c}
 }
 
-func (f *CDIConfigFeatureGates) isFeatureGateEnabled(featureGate string) (bool, error) {
+// Enabled checks if the feature gate is enabled in the CDIConfig
+func (f *CDIConfigFeatureGates) Enabled(featureGate string) (bool, error) {
 	featureGates, err := f.getConfig()
 	if err != nil {
 		return false, errors.Wrap(err, "error getting CDIConfig")
@@ -55,7 +66,81 @@ func (f *CDIConfigFeatureGates) getConfig() ([]string, error) {
 	return config.Spec.FeatureGates, nil
 }
 
+// EnabledFeatureGates returns the sorted feature gates that are enabled in the CDIConfig
+func (f *CDIConfigFeatureGates) EnabledFeatureGates() ([]string, error) {
+	featureGates, err := f.getConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting CDIConfig")
+	}
+	return sortFeatureGates(featureGates), nil
+}
+
 // HonorWaitForFirstConsumerEnabled - see the HonorWaitForFirstConsumer const
 func (f *CDIConfigFeatureGates) HonorWaitForFirstConsumerEnabled() (bool, error) {
-	return f.isFeatureGateEnabled(HonorWaitForFirstConsumer)
+	return f.Enabled(HonorWaitForFirstConsumer)
+}
+
+// FileSourceHostPathEnabled - see the FileSourceHostPath const
+func (f *CDIConfigFeatureGates) FileSourceHostPathEnabled() (bool, error) {
+	return f.Enabled(FileSourceHostPath)
+}
+
+// StaticFeatureGates holds the feature gates passed to a CDI pod, like the importer, that does not read the CDIConfig
+type StaticFeatureGates struct {
+	featureGates []string
+}
+
+// NewStaticFeatureGates creates the feature gates of a comma-separated list, as formatted by FormatFeatureGates
+func NewStaticFeatureGates(value string) *StaticFeatureGates {
+	var featureGates []string
+	for _, fg := range strings.Split(value, ",") {
+		if fg = strings.TrimSpace(fg); fg != "" {
+			featureGates = append(featureGates, fg)
+		}
+	}
+	return &StaticFeatureGates{featureGates: sortFeatureGates(featureGates)}
+}
+
+// FormatFeatureGates formats the feature gates as a comma-separated list, to pass them in an environment variable
+func FormatFeatureGates(featureGates []string) string {
+	return strings.Join(featureGates, ",")
+}
+
+// Enabled checks if the feature gate is in the list
+func (f *StaticFeatureGates) Enabled(featureGate string) (bool, error) {
+	for _, fg := range f.featureGates {
+		if fg == featureGate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnabledFeatureGates returns the sorted feature gates of the list
+func (f *StaticFeatureGates) EnabledFeatureGates() ([]string, error) {
+	return f.featureGates, nil
+}
+
+// HonorWaitForFirstConsumerEnabled - see the HonorWaitForFirstConsumer const
+func (f *StaticFeatureGates) HonorWaitForFirstConsumerEnabled() (bool, error) {
+	return f.Enabled(HonorWaitForFirstConsumer)
+}
+
+// FileSourceHostPathEnabled - see the FileSourceHostPath const
+func (f *StaticFeatureGates) FileSourceHostPathEnabled() (bool, error) {
+	return f.Enabled(FileSourceHostPath)
+}
+
+// sortFeatureGates returns the sorted feature gates without duplicates
+func sortFeatureGates(featureGates []string) []string {
+	var sorted []string
+	seen := map[string]bool{}
+	for _, fg := range featureGates {
+		if !seen[fg] {
+			seen[fg] = true
+			sorted = append(sorted, fg)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
 }
@@ -37,6 +37,7 @@ var _ = Describe("Feature Gates", func() {
 	It("Should be false if not set", func() {
 		featureGates, _ := createFeatureGatesAndClient()
 		Expect(featureGates.HonorWaitForFirstConsumerEnabled()).To(BeFalse())
+		Expect(featureGates.FileSourceHostPathEnabled()).To(BeFalse())
 	})
 
 	It("Should reflect config changes", func() {
@@ -50,12 +51,44 @@ var _ = Describe("Feature Gates", func() {
 		err = client.Update(context.TODO(), cdiConfig)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(featureGates.HonorWaitForFirstConsumerEnabled()).To(BeTrue())
+		Expect(featureGates.FileSourceHostPathEnabled()).To(BeFalse())
+
+		cdiConfig.Spec.FeatureGates = []string{FileSourceHostPath}
+		err = client.Update(context.TODO(), cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(featureGates.FileSourceHostPathEnabled()).To(BeTrue())
 
 		cdiConfig.Spec.FeatureGates = nil
 		err = client.Update(context.TODO(), cdiConfig)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(featureGates.HonorWaitForFirstConsumerEnabled()).To(BeFalse())
 	})
+
+	It("Should return the sorted enabled feature gates", func() {
+		featureGates, client := createFeatureGatesAndClient()
+		cdiConfig := &cdiv1.CDIConfig{}
+		err := client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		cdiConfig.Spec.FeatureGates = []string{"Experimental", FileSourceHostPath, "Experimental"}
+		err = client.Update(context.TODO(), cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(featureGates.Enabled("Experimental")).To(BeTrue())
+		Expect(featureGates.Enabled("Unknown")).To(BeFalse())
+		Expect(featureGates.EnabledFeatureGates()).To(Equal([]string{"Experimental", FileSourceHostPath}))
+	})
+
+	It("Should pass the enabled feature gates to the static feature gates", func() {
+		value := FormatFeatureGates([]string{FileSourceHostPath, "Experimental"})
+		featureGates := NewStaticFeatureGates(value)
+		Expect(featureGates.FileSourceHostPathEnabled()).To(BeTrue())
+		Expect(featureGates.HonorWaitForFirstConsumerEnabled()).To(BeFalse())
+		Expect(featureGates.Enabled("Experimental")).To(BeTrue())
+		Expect(featureGates.EnabledFeatureGates()).To(Equal([]string{"Experimental", FileSourceHostPath}))
+
+		featureGates = NewStaticFeatureGates("")
+		Expect(featureGates.EnabledFeatureGates()).To(BeEmpty())
+	})
 })
 
 func createFeatureGatesAndClient(objects ...runtime.Object) (FeatureGates, client.Client) {
@@ -46,6 +46,39 @@ var knownHeaders = Headers{
 		SizeOff: 0,
 		SizeLen: 0,
 	},
+	"zst": Header{
+		Format:      "zst",
+		magicNumber: []byte{0x28, 0xB5, 0x2F, 0xFD},
+	},
+	"vmdk": Header{
+		Format:      "vmdk",
+		magicNumber: []byte("KDMV"),
+	},
+	"vhd": Header{
+		// Dynamic VHD images start with a copy of their footer, holding the current size
+		Format:      "vhd",
+		magicNumber: []byte("conectix"),
+		SizeOff:     48,
+		SizeLen:     8,
+	},
+	"vhdx": Header{
+		Format:      "vhdx",
+		magicNumber: []byte("vhdxfile"),
+	},
+	"vdi": Header{
+		Format:      "vdi",
+		magicNumber: []byte{0x7F, 0x10, 0xDA, 0xBE},
+		mgOffset:    0x40,
+	},
+}
+
+// convertFormats are the formats of disk images qemu-img converts to raw
+var convertFormats = map[string]bool{
+	"qcow2": true,
+	"vmdk":  true,
+	"vhd":   true,
+	"vhdx":  true,
+	"vdi":   true,
 }
 
 // Header represents our parameters for a file format header
@@ -66,6 +99,11 @@ func CopyKnownHdrs() Headers {
 	return m
 }
 
+// IsConvertFormat returns true if the header is the one of a disk image that needs to be converted to raw
+func (h Header) IsConvertFormat() bool {
+	return convertFormats[h.Format]
+}
+
 // Match performs a check to see if the provided byte slice matches the bytes in our header data
 func (h Header) Match(b []byte) bool {
 	return bytes.Equal(b[h.mgOffset:h.mgOffset+len(h.magicNumber)], h.magicNumber)
@@ -8,6 +8,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/system"
 	"net/url"
 	"strings"
+	"time"
 )
 
 var (
@@ -31,7 +32,9 @@ type NbdkitFilter string
 
 // Nbdkit plugins
 const (
-	NbdkitCurlPlugin NbdkitPlugin = "curl"
+	NbdkitCurlPlugin  NbdkitPlugin = "curl"
+	NbdkitIscsiPlugin NbdkitPlugin = "iscsi"
+	NbdkitSSHPlugin   NbdkitPlugin = "ssh"
 )
 
 // Nbdkit filters
@@ -75,6 +78,64 @@ func NewNbdkitCurl(nbdkitPidFile, certDir string) *Nbdkit {
 	}
 }
 
+// NewNbdkitIscsi creates a new Nbdkit instance with the iscsi plugin, the initiator name is generated by the plugin if
+// empty. The source is an iscsi://portal/target/lun URL.
+func NewNbdkitIscsi(nbdkitPidFile, initiator string) *Nbdkit {
+	var pluginArgs []string
+	if initiator != "" {
+		pluginArgs = append(pluginArgs, fmt.Sprintf("initiator=%s", initiator))
+	}
+
+	return &Nbdkit{
+		NbdPidFile: nbdkitPidFile,
+		plugin:     NbdkitIscsiPlugin,
+		pluginArgs: pluginArgs,
+	}
+}
+
+// NewNbdkitSSH creates a new Nbdkit instance with the ssh plugin, logging in with the private key in identity. The host
+// key is checked against knownHosts, or not at all if empty. The source is an ssh://user@host[:port]/path URL.
+func NewNbdkitSSH(nbdkitPidFile, identity, knownHosts string) *Nbdkit {
+	pluginArgs := []string{fmt.Sprintf("identity=%s", identity)}
+	if knownHosts != "" {
+		pluginArgs = append(pluginArgs, fmt.Sprintf("known-hosts=%s", knownHosts))
+	} else {
+		pluginArgs = append(pluginArgs, "verify-remote-host=false")
+	}
+
+	return &Nbdkit{
+		NbdPidFile: nbdkitPidFile,
+		plugin:     NbdkitSSHPlugin,
+		pluginArgs: pluginArgs,
+	}
+}
+
+// AddClientCertificate configures the curl plugin to authenticate with the client certificate and key in certDir
+func (n *Nbdkit) AddClientCertificate(certDir string) {
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("sslclientcert=%s/%s", certDir, "tls.crt"))
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("sslclientkey=%s/%s", certDir, "tls.key"))
+}
+
+// AddCredentials configures the curl or iscsi plugin to log in with the user name and the password read from passwordFile, so
+// the password does not show in the arguments of nbdkit
+func (n *Nbdkit) AddCredentials(user, passwordFile string) {
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("user=%s", user))
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("password=+%s", passwordFile))
+}
+
+// AddHeaderScript adds a script the curl plugin runs to get the http headers, the script is run again after the renew
+// interval so the headers can change during the transfer
+func (n *Nbdkit) AddHeaderScript(script string, renew time.Duration) {
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("header-script=%s", script))
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("header-script-renew=%d", int64(renew.Seconds())))
+}
+
+// AddTarFilter adds the tar filter, exposing the entry of the tar archive
+func (n *Nbdkit) AddTarFilter(entry string) {
+	n.AddFilter(NbdkitTarFilter)
+	n.pluginArgs = append(n.pluginArgs, fmt.Sprintf("tar-entry=%s", entry))
+}
+
 // AddFilter adds a nbdkit filter if it doesn't already exist
 func (n *Nbdkit) AddFilter(filter NbdkitFilter) {
 	for _, f := range n.filters {
@@ -99,13 +160,13 @@ func (n *nbdkitOperations) Info(url *url.URL) (*ImgInfo, error) {
 	return checkOutputQemuImgInfo(output, url.String())
 }
 
-// Validate validates the url
-func (n *nbdkitOperations) Validate(url *url.URL, availableSize int64, filesystemOverhead float64) error {
+// Validate validates the url and returns the information of its image
+func (n *nbdkitOperations) Validate(url *url.URL, availableSize int64, filesystemOverhead float64) (*ImgInfo, error) {
 	info, err := n.Info(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return checkIfURLIsValid(info, availableSize, filesystemOverhead, url.String())
+	return info, checkIfURLIsValid(info, availableSize, filesystemOverhead, url.String())
 }
 
 // ConvertToRawStream converts the content provided by the url to a raw disk in the dest
@@ -133,13 +194,25 @@ func (n *nbdkitOperations) Resize(image string, size resource.Quantity) error {
 	return Resize(image, size)
 }
 
-func (n *Nbdkit) getSource() string {
-	var source string
+func (n *Nbdkit) getSource() []string {
+	var source []string
 	switch n.plugin {
 	case NbdkitCurlPlugin:
-		source = fmt.Sprintf("url=%s", n.source.String())
-	default:
-		source = ""
+		source = []string{fmt.Sprintf("url=%s", n.source.String())}
+	case NbdkitIscsiPlugin:
+		target := strings.Split(strings.TrimPrefix(n.source.Path, "/"), "/")
+		source = []string{fmt.Sprintf("portal=%s", n.source.Host), fmt.Sprintf("target=%s", target[0])}
+		if len(target) > 1 && target[1] != "" {
+			source = append(source, fmt.Sprintf("lun=%s", target[1]))
+		}
+	case NbdkitSSHPlugin:
+		source = []string{fmt.Sprintf("host=%s", n.source.Hostname()), fmt.Sprintf("path=%s", n.source.Path)}
+		if n.source.Port() != "" {
+			source = append(source, fmt.Sprintf("port=%s", n.source.Port()))
+		}
+		if n.source.User != nil {
+			source = append(source, fmt.Sprintf("user=%s", n.source.User.Username()))
+		}
 	}
 	return source
 }
@@ -160,9 +233,30 @@ func (n *Nbdkit) startNbdkitWithQemuImg(qemuImgCmd string, qemuImgArgs []string)
 		argsNbdkit = append(argsNbdkit, a)
 	}
 	// append nbdkit plugin arguments
-	argsNbdkit = append(argsNbdkit, string(n.plugin), strings.Join(n.pluginArgs, " "), n.getSource())
+	argsNbdkit = append(argsNbdkit, string(n.plugin))
+	argsNbdkit = append(argsNbdkit, n.pluginArgs...)
+	argsNbdkit = append(argsNbdkit, n.getSource()...)
 	// append qemu-img command
 	argsNbdkit = append(argsNbdkit, "--run", fmt.Sprintf("qemu-img %s $nbd %v", qemuImgCmd, strings.Join(qemuImgArgs, " ")))
-	klog.V(3).Infof("Start nbdkit with: %v", argsNbdkit)
+	klog.V(3).Infof("Start nbdkit with: %v", redactNbdkitArgs(argsNbdkit))
 	return nbdkitExecFunction(nil, reportProgress, "nbdkit", argsNbdkit...)
 }
+
+// redactNbdkitArgs hides the values of the headers and the query of the url, they can contain credentials.
+func redactNbdkitArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, a := range args {
+		if strings.HasPrefix(a, "header=") {
+			if idx := strings.Index(a, ":"); idx > 0 {
+				a = a[:idx+1] + " <redacted>"
+			}
+		}
+		if strings.HasPrefix(a, "url=") {
+			if idx := strings.Index(a, "?"); idx > 0 {
+				a = a[:idx+1] + "<redacted>"
+			}
+		}
+		redacted[i] = a
+	}
+	return redacted
+}
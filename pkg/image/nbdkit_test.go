@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var (
@@ -33,6 +34,105 @@ var _ = Describe("Start nbdkit with qemu-img", func() {
 	})
 })
 
+var _ = Describe("Nbdkit curl headers", func() {
+	It("should pass the header script and its renew interval as plugin arguments", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitCurl(pidfile, "/certs")
+		n.AddHeaderScript("cat /tmp/nbdkit-headers", 5*time.Minute)
+		u := "http://someurl/somewhere/source.img"
+		n.source, _ = url.Parse(u)
+		args := append(defaultNbdkitArgs, "curl", "cainfo=/certs/tls.crt", "header-script=cat /tmp/nbdkit-headers", "header-script-renew=300", fmt.Sprintf("url=%s", u))
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should pass the client certificate and key as plugin arguments", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitCurl(pidfile, "")
+		n.AddClientCertificate("/client-certs")
+		u := "http://someurl/somewhere/source.img"
+		n.source, _ = url.Parse(u)
+		args := append(defaultNbdkitArgs, "curl", "sslclientcert=/client-certs/tls.crt", "sslclientkey=/client-certs/tls.key", fmt.Sprintf("url=%s", u))
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should pass the user and the password file as plugin arguments", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitCurl(pidfile, "")
+		n.AddCredentials("anonymous", "/tmp/ftp-password")
+		u := "ftp://someurl/somewhere/source.img"
+		n.source, _ = url.Parse(u)
+		args := append(defaultNbdkitArgs, "curl", "user=anonymous", "password=+/tmp/ftp-password", fmt.Sprintf("url=%s", u))
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should pass the portal, target and LUN of an iscsi source as plugin arguments", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitIscsi(pidfile, "iqn.2021-01.io.kubevirt:importer")
+		n.AddCredentials("chap", "/tmp/chap-password")
+		n.source, _ = url.Parse("iscsi://san.example.com:3260/iqn.2003-01.org.linux-iscsi.san:p2v/2")
+		args := append(defaultNbdkitArgs, "iscsi", "initiator=iqn.2021-01.io.kubevirt:importer", "user=chap", "password=+/tmp/chap-password", "portal=san.example.com:3260", "target=iqn.2003-01.org.linux-iscsi.san:p2v", "lun=2")
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should pass the host, path, port and user of an ssh source as plugin arguments", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitSSH(pidfile, "/tmp/ssh-key", "/tmp/known-hosts")
+		n.source, _ = url.Parse("ssh://root@kvm01.example.com:2222/var/lib/libvirt/images/fedora.qcow2")
+		args := append(defaultNbdkitArgs, "ssh", "identity=/tmp/ssh-key", "known-hosts=/tmp/known-hosts", "host=kvm01.example.com", "path=/var/lib/libvirt/images/fedora.qcow2", "port=2222", "user=root")
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should not verify the host key of an ssh source without known hosts", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitSSH(pidfile, "/tmp/ssh-key", "")
+		n.source, _ = url.Parse("ssh://root@kvm01.example.com/var/lib/libvirt/images/fedora.qcow2")
+		args := append(defaultNbdkitArgs, "ssh", "identity=/tmp/ssh-key", "verify-remote-host=false", "host=kvm01.example.com", "path=/var/lib/libvirt/images/fedora.qcow2", "user=root")
+		replaceNbdkitExecFunction(mockExecFunction("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should read the tar entry through the decompression filters", func() {
+		qemuArgs := []string{"-h"}
+		n := NewNbdkitCurl(pidfile, "")
+		n.AddTarFilter("disk.qcow2")
+		n.AddFilter(NbdkitGzipFilter)
+		u := "http://someurl/somewhere/source.tar.gz"
+		n.source, _ = url.Parse(u)
+		args := append(append([]string{}, defaultNbdkitArgs...), "--filter=tar", "--filter=gzip", "-r", "curl", "tar-entry=disk.qcow2", fmt.Sprintf("url=%s", u), "--run", "qemu-img convert $nbd -h")
+		replaceNbdkitExecFunction(mockExecFunctionStrict("", "", nil, args...), func() {
+			_, err := n.startNbdkitWithQemuImg("convert", qemuArgs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should redact the header values", func() {
+		args := redactNbdkitArgs([]string{"curl", "header=Authorization: Bearer token", "url=http://someurl"})
+		Expect(args).To(Equal([]string{"curl", "header=Authorization: <redacted>", "url=http://someurl"}))
+	})
+
+	It("should redact the query of the url", func() {
+		args := redactNbdkitArgs([]string{"curl", "url=https://account.blob.core.windows.net/c/disk.vhd?sv=2020-08-04&sig=secret"})
+		Expect(args).To(Equal([]string{"curl", "url=https://account.blob.core.windows.net/c/disk.vhd?<redacted>"}))
+	})
+})
+
 var _ = Describe("Convert to Raw", func() {
 	var (
 		u = "http://someurl/somewhere/source.img"
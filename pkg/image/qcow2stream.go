@@ -0,0 +1,429 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package image
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	qcow2Magic = 0x514649fb
+	// qcow2HeaderSize is the size of the fields of the version 2 header, version 3 adds the features
+	qcow2HeaderSize   = 72
+	qcow2V3HeaderSize = 104
+	// qcow2OffsetMask selects the offset of the L2 tables in L1 entries and of the clusters in L2 entries
+	qcow2OffsetMask     = 0x00fffffffffffe00
+	qcow2CompressedFlag = 1 << 62
+	qcow2ZeroFlag       = 1
+	// qcow2DirtyFeature is the only incompatible feature the image can be converted with
+	qcow2DirtyFeature = 1
+	// qcow2StreamMaxBuffered bounds the memory used to keep the clusters received before the tables referencing them
+	qcow2StreamMaxBuffered = 64 * 1024 * 1024
+)
+
+type qcow2ExtentKind int
+
+const (
+	qcow2L1Extent qcow2ExtentKind = iota
+	qcow2L2Extent
+	qcow2DataExtent
+	qcow2CompressedExtent
+)
+
+// qcow2Extent is a range of the image file, a table or the data of the guest at the guest offset
+type qcow2Extent struct {
+	kind   qcow2ExtentKind
+	offset int64
+	length int64
+	guest  int64
+}
+
+func (e *qcow2Extent) end() int64 {
+	return e.offset + e.length
+}
+
+// qcow2StreamConverter converts a qcow2 image to raw while reading it sequentially. The clusters are written to the
+// guest offsets found in the L1 and L2 tables, so the tables must come before the data they reference, as in the
+// images written by qemu-img. The clusters received before the tables referencing them are kept in memory, up to
+// qcow2StreamMaxBuffered bytes.
+type qcow2StreamConverter struct {
+	out         *os.File
+	block       bool
+	clusterBits uint
+	clusterSize int64
+	virtualSize int64
+	// received is the number of bytes of the image read so far
+	received int64
+	// clusters are the received clusters still needed, by offset in the image
+	clusters map[int64]*qcow2Cluster
+	buffered int64
+	// pending are the extents not received yet, sorted by offset
+	pending []*qcow2Extent
+	// tablesPending is the number of L1 and L2 tables not processed yet
+	tablesPending int
+	// written marks the guest clusters written, the others are zeroed on block devices
+	written []bool
+}
+
+type qcow2Cluster struct {
+	data    []byte
+	claimed bool
+}
+
+// ConvertQcow2Stream converts the qcow2 image read from the stream to a raw disk in dest, without storing the image.
+// Images with a backing file, encrypted images and images using features other than the dirty bit are rejected.
+func ConvertQcow2Stream(stream io.Reader, dest string) error {
+	info, err := os.Stat(dest)
+	c := &qcow2StreamConverter{
+		block:    err == nil && info.Mode()&os.ModeDevice != 0,
+		clusters: make(map[int64]*qcow2Cluster),
+	}
+	if c.block {
+		c.out, err = os.OpenFile(dest, os.O_WRONLY, os.ModePerm)
+	} else {
+		c.out, err = os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", dest)
+	}
+	defer c.out.Close()
+
+	klog.V(1).Infof("Converting qcow2 stream to raw...\n")
+	if err := c.convert(stream); err != nil {
+		if !c.block {
+			os.Remove(dest)
+		}
+		return errors.Wrap(err, "could not convert qcow2 stream to raw")
+	}
+	return c.out.Sync()
+}
+
+func (c *qcow2StreamConverter) convert(stream io.Reader) error {
+	header := make([]byte, qcow2V3HeaderSize)
+	n, err := io.ReadFull(stream, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	if err := c.parseHeader(header[:n]); err != nil {
+		return err
+	}
+	if !c.block {
+		if err := c.out.Truncate(c.virtualSize); err != nil {
+			return err
+		}
+	}
+	c.written = make([]bool, (c.virtualSize+c.clusterSize-1)/c.clusterSize)
+
+	// The rest of the first cluster follows the header
+	first := make([]byte, c.clusterSize)
+	copy(first, header[:n])
+	m, err := io.ReadFull(stream, first[n:])
+	if err := c.receive(first[:n+m]); err != nil {
+		return err
+	}
+	for err == nil {
+		data := make([]byte, c.clusterSize)
+		n, err = io.ReadFull(stream, data)
+		if n > 0 {
+			if receiveErr := c.receive(data[:n]); receiveErr != nil {
+				return receiveErr
+			}
+		}
+	}
+	if err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	return c.finish()
+}
+
+func (c *qcow2StreamConverter) parseHeader(header []byte) error {
+	if len(header) < qcow2HeaderSize || binary.BigEndian.Uint32(header[0:]) != qcow2Magic {
+		return errors.New("not a qcow2 image")
+	}
+	version := binary.BigEndian.Uint32(header[4:])
+	if version != 2 && version != 3 {
+		return errors.Errorf("unsupported qcow2 version %d", version)
+	}
+	if binary.BigEndian.Uint64(header[8:]) != 0 {
+		return errors.New("qcow2 images with a backing file are not supported")
+	}
+	c.clusterBits = uint(binary.BigEndian.Uint32(header[20:]))
+	if c.clusterBits < 9 || c.clusterBits > 21 {
+		return errors.Errorf("invalid qcow2 cluster bits %d", c.clusterBits)
+	}
+	c.clusterSize = int64(1) << c.clusterBits
+	c.virtualSize = int64(binary.BigEndian.Uint64(header[24:]))
+	if binary.BigEndian.Uint32(header[32:]) != 0 {
+		return errors.New("encrypted qcow2 images are not supported")
+	}
+	if version == 3 {
+		if len(header) < qcow2V3HeaderSize {
+			return errors.New("truncated qcow2 header")
+		}
+		if features := binary.BigEndian.Uint64(header[72:]) &^ qcow2DirtyFeature; features != 0 {
+			return errors.Errorf("unsupported qcow2 incompatible features %#x", features)
+		}
+	}
+	l1Size := int64(binary.BigEndian.Uint32(header[36:]))
+	l1Offset := int64(binary.BigEndian.Uint64(header[40:]))
+	klog.V(3).Infof("qcow2 image of %d bytes, cluster size %d, L1 table of %d entries at %d", c.virtualSize, c.clusterSize, l1Size, l1Offset)
+	if l1Size > 0 {
+		c.tablesPending++
+		return c.register(&qcow2Extent{kind: qcow2L1Extent, offset: l1Offset, length: l1Size * 8})
+	}
+	return nil
+}
+
+// receive processes the next cluster of the image
+func (c *qcow2StreamConverter) receive(data []byte) error {
+	c.clusters[c.received] = &qcow2Cluster{data: data}
+	c.buffered += int64(len(data))
+	c.received += int64(len(data))
+
+	for len(c.pending) > 0 && c.pending[0].offset < c.received {
+		e := c.pending[0]
+		if e.kind == qcow2DataExtent {
+			done, err := c.writeData(e)
+			if err != nil {
+				return err
+			}
+			if !done {
+				break
+			}
+			c.pending = c.pending[1:]
+			continue
+		}
+		if e.end() > c.received {
+			break
+		}
+		c.pending = c.pending[1:]
+		if err := c.process(e); err != nil {
+			return err
+		}
+	}
+
+	c.release()
+	if c.buffered > qcow2StreamMaxBuffered {
+		return errors.New("the qcow2 image stores its data before its tables, it can't be converted while streaming")
+	}
+	return nil
+}
+
+// register processes the extent if it was received, otherwise keeps it until it is
+func (c *qcow2StreamConverter) register(e *qcow2Extent) error {
+	if e.kind == qcow2DataExtent {
+		if done, err := c.writeData(e); err != nil || done {
+			return err
+		}
+	} else if e.end() <= c.received {
+		return c.process(e)
+	}
+
+	i := sort.Search(len(c.pending), func(i int) bool {
+		return c.pending[i].offset >= e.offset
+	})
+	// qemu-img writes the clusters of the guest in order, merge them to keep the pending extents few
+	if i > 0 {
+		prev := c.pending[i-1]
+		if e.kind == qcow2DataExtent && prev.kind == qcow2DataExtent && prev.end() == e.offset && prev.guest+prev.length == e.guest {
+			prev.length += e.length
+			return nil
+		}
+	}
+	c.pending = append(c.pending, nil)
+	copy(c.pending[i+1:], c.pending[i:])
+	c.pending[i] = e
+	return nil
+}
+
+// writeData writes the received part of the data extent, and returns true once it is all written
+func (c *qcow2StreamConverter) writeData(e *qcow2Extent) (bool, error) {
+	available := c.received - e.offset
+	if available <= 0 {
+		return false, nil
+	}
+	if available > e.length {
+		available = e.length
+	}
+	data, err := c.read(e.offset, available)
+	if err != nil {
+		return false, err
+	}
+	if err := c.write(data, e.guest); err != nil {
+		return false, err
+	}
+	e.offset += available
+	e.guest += available
+	e.length -= available
+	return e.length == 0, nil
+}
+
+// process handles the received L1 table, L2 table or compressed cluster
+func (c *qcow2StreamConverter) process(e *qcow2Extent) error {
+	data, err := c.read(e.offset, e.length)
+	if err != nil {
+		return err
+	}
+	switch e.kind {
+	case qcow2L1Extent:
+		c.tablesPending--
+		l2Coverage := c.clusterSize * (c.clusterSize / 8)
+		for i := int64(0); i < e.length/8; i++ {
+			offset := int64(binary.BigEndian.Uint64(data[i*8:]) & qcow2OffsetMask)
+			if offset == 0 || i*l2Coverage >= c.virtualSize {
+				continue
+			}
+			c.tablesPending++
+			if err := c.register(&qcow2Extent{kind: qcow2L2Extent, offset: offset, length: c.clusterSize, guest: i * l2Coverage}); err != nil {
+				return err
+			}
+		}
+	case qcow2L2Extent:
+		c.tablesPending--
+		for i := int64(0); i < e.length/8; i++ {
+			guest := e.guest + i*c.clusterSize
+			if guest >= c.virtualSize {
+				break
+			}
+			if err := c.registerCluster(binary.BigEndian.Uint64(data[i*8:]), guest); err != nil {
+				return err
+			}
+		}
+	case qcow2CompressedExtent:
+		cluster := make([]byte, c.clusterSize)
+		if _, err := io.ReadFull(flate.NewReader(bytes.NewReader(data)), cluster); err != nil {
+			return errors.Wrapf(err, "invalid compressed cluster at %d", e.offset)
+		}
+		return c.write(cluster, e.guest)
+	}
+	return nil
+}
+
+// registerCluster registers the data of the L2 entry of the guest cluster, unallocated and zero clusters are skipped
+func (c *qcow2StreamConverter) registerCluster(entry uint64, guest int64) error {
+	length := c.clusterSize
+	if guest+length > c.virtualSize {
+		length = c.virtualSize - guest
+	}
+	if entry&qcow2CompressedFlag != 0 {
+		// The offset and the number of additional 512 byte sectors of compressed clusters share the entry
+		offsetBits := 62 - (c.clusterBits - 8)
+		offset := int64(entry & (1<<offsetBits - 1))
+		sectors := int64((entry>>offsetBits)&(1<<(c.clusterBits-8)-1)) + 1
+		return c.register(&qcow2Extent{kind: qcow2CompressedExtent, offset: offset, length: sectors*512 - offset%512, guest: guest})
+	}
+	offset := int64(entry & qcow2OffsetMask)
+	if offset == 0 || entry&qcow2ZeroFlag != 0 {
+		return nil
+	}
+	return c.register(&qcow2Extent{kind: qcow2DataExtent, offset: offset, length: length, guest: guest})
+}
+
+// read returns the received bytes of the image from offset, and marks their clusters as used
+func (c *qcow2StreamConverter) read(offset, length int64) ([]byte, error) {
+	data := make([]byte, 0, length)
+	for pos := offset; pos < offset+length; {
+		start := pos &^ (c.clusterSize - 1)
+		cluster, ok := c.clusters[start]
+		if !ok {
+			return nil, errors.Errorf("the qcow2 image references data at %d before its tables, it can't be converted while streaming", pos)
+		}
+		cluster.claimed = true
+		from := pos - start
+		to := offset + length - start
+		if to > int64(len(cluster.data)) {
+			to = int64(len(cluster.data))
+		}
+		if from >= to {
+			return nil, errors.Errorf("truncated qcow2 image, missing data at %d", pos)
+		}
+		data = append(data, cluster.data[from:to]...)
+		pos = start + to
+	}
+	return data, nil
+}
+
+func (c *qcow2StreamConverter) write(data []byte, guest int64) error {
+	if guest+int64(len(data)) > c.virtualSize {
+		data = data[:c.virtualSize-guest]
+	}
+	if _, err := c.out.WriteAt(data, guest); err != nil {
+		return err
+	}
+	for i := guest / c.clusterSize; i*c.clusterSize < guest+int64(len(data)); i++ {
+		c.written[i] = true
+	}
+	return nil
+}
+
+// release drops the received clusters no pending extent needs. Until all the tables are known, the clusters nothing
+// used yet are kept, since a table received later may reference them.
+func (c *qcow2StreamConverter) release() {
+	keepFrom := c.received
+	if len(c.pending) > 0 && c.pending[0].offset < keepFrom {
+		keepFrom = c.pending[0].offset
+	}
+	for offset, cluster := range c.clusters {
+		if offset+c.clusterSize > keepFrom {
+			continue
+		}
+		if cluster.claimed || c.tablesPending == 0 {
+			c.buffered -= int64(len(cluster.data))
+			delete(c.clusters, offset)
+		}
+	}
+}
+
+// finish processes the compressed clusters at the end of the image, which may be shorter than their sectors, and
+// zeroes the clusters not written on block devices
+func (c *qcow2StreamConverter) finish() error {
+	for _, e := range c.pending {
+		if e.kind != qcow2CompressedExtent || e.offset >= c.received {
+			return errors.Errorf("truncated qcow2 image, missing data at %d", e.offset)
+		}
+		e.length = c.received - e.offset
+		if err := c.process(e); err != nil {
+			return err
+		}
+	}
+	c.pending = nil
+	if c.tablesPending > 0 {
+		return errors.New("truncated qcow2 image, missing tables")
+	}
+	if c.block {
+		zero := make([]byte, c.clusterSize)
+		for i, written := range c.written {
+			if !written {
+				if err := c.write(zero, int64(i)*c.clusterSize); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
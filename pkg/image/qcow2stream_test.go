@@ -0,0 +1,98 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testClusterBits = 9
+	testClusterSize = 1 << testClusterBits
+)
+
+// testQcow2Image builds a qcow2 image of clusters of 512 bytes with the given L1 and L2 offsets, the data cluster of
+// the first guest cluster is at dataOffset
+func testQcow2Image(l1Offset, l2Offset, dataOffset int64, data []byte) []byte {
+	img := make([]byte, 4*testClusterSize)
+	binary.BigEndian.PutUint32(img[0:], qcow2Magic)
+	binary.BigEndian.PutUint32(img[4:], 2)
+	binary.BigEndian.PutUint32(img[20:], testClusterBits)
+	binary.BigEndian.PutUint64(img[24:], 2*testClusterSize)
+	binary.BigEndian.PutUint32(img[36:], 1)
+	binary.BigEndian.PutUint64(img[40:], uint64(l1Offset))
+	binary.BigEndian.PutUint64(img[l1Offset:], uint64(l2Offset))
+	binary.BigEndian.PutUint64(img[l2Offset:], uint64(dataOffset))
+	copy(img[dataOffset:], data)
+	return img
+}
+
+var _ = Describe("Streaming qcow2 conversion", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "qcow2stream")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should convert images with", func(l1Offset, l2Offset, dataOffset int64) {
+		data := bytes.Repeat([]byte("qcow2"), testClusterSize/5)
+		dest := filepath.Join(tmpDir, "disk.img")
+		err := ConvertQcow2Stream(bytes.NewReader(testQcow2Image(l1Offset, l2Offset, dataOffset, data)), dest)
+		Expect(err).NotTo(HaveOccurred())
+		raw, err := ioutil.ReadFile(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(raw).To(HaveLen(2 * testClusterSize))
+		Expect(raw[:len(data)]).To(Equal(data))
+		Expect(raw[testClusterSize:]).To(Equal(make([]byte, testClusterSize)))
+	},
+		table.Entry("the tables before the data", int64(testClusterSize), int64(2*testClusterSize), int64(3*testClusterSize)),
+		table.Entry("the data before the tables", int64(2*testClusterSize), int64(3*testClusterSize), int64(testClusterSize)),
+	)
+
+	table.DescribeTable("should reject", func(modify func([]byte), message string) {
+		img := testQcow2Image(testClusterSize, 2*testClusterSize, 3*testClusterSize, nil)
+		modify(img)
+		dest := filepath.Join(tmpDir, "disk.img")
+		err := ConvertQcow2Stream(bytes.NewReader(img), dest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+		_, err = os.Stat(dest)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	},
+		table.Entry("images that are not qcow2", func(img []byte) { img[0] = 0 }, "not a qcow2 image"),
+		table.Entry("images with a backing file", func(img []byte) { binary.BigEndian.PutUint64(img[8:], 1024) }, "backing file"),
+		table.Entry("encrypted images", func(img []byte) { binary.BigEndian.PutUint32(img[32:], 1) }, "encrypted"),
+		table.Entry("truncated images", func(img []byte) { binary.BigEndian.PutUint64(img[2*testClusterSize:], 8*testClusterSize) }, "truncated"),
+	)
+})
@@ -23,6 +23,8 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -59,7 +61,7 @@ type QEMUOperations interface {
 	ConvertToRawStream(*url.URL, string, bool) error
 	Resize(string, resource.Quantity) error
 	Info(url *url.URL) (*ImgInfo, error)
-	Validate(*url.URL, int64, float64) error
+	Validate(*url.URL, int64, float64) (*ImgInfo, error)
 	CreateBlankImage(string, resource.Quantity, bool) error
 }
 
@@ -79,6 +81,10 @@ var (
 		[]string{"ownerUID"},
 	)
 	ownerUID string
+
+	// conversionProgress is the progress in percentage of the last qemu-img conversion
+	conversionProgress      float64
+	conversionProgressMutex sync.Mutex
 )
 
 func init() {
@@ -105,7 +111,7 @@ func convertToRaw(src, dest string, preallocate bool) error {
 		klog.V(1).Info("Added preallocation")
 		args = append(args, []string{"-o", "preallocation=falloc"}...)
 	}
-	_, err := qemuExecFunction(nil, nil, "qemu-img", args...)
+	_, err := qemuExecFunction(nil, reportProgress, "qemu-img", args...)
 	if err != nil {
 		os.Remove(dest)
 		return errors.Wrap(err, "could not convert image to raw")
@@ -120,9 +126,7 @@ func (o *qemuOperations) ConvertToRawStream(url *url.URL, dest string, prealloca
 		return convertToRaw(url.String(), dest, preallocate)
 	}
 
-	jsonArg := fmt.Sprintf("json: {\"file.driver\": \"%s\", \"file.url\": \"%s\", \"file.timeout\": %d}", url.Scheme, url, networkTimeoutSecs)
-
-	args := []string{"convert", "-t", "none", "-p", "-O", "raw", jsonArg, dest}
+	args := []string{"convert", "-t", "none", "-p", "-O", "raw", urlSourceArg(url), dest}
 	if preallocate {
 		klog.V(1).Info("Added preallocation")
 		args = append(args, []string{"-o", "preallocation=falloc"}...)
@@ -137,6 +141,61 @@ func (o *qemuOperations) ConvertToRawStream(url *url.URL, dest string, prealloca
 	return nil
 }
 
+// ConvertToQcow2 converts the raw disk image or block device src to a qcow2 image dest, whose clusters are compressed
+// with the compression type, zlib or zstd, or not compressed when compression is empty or none
+func ConvertToQcow2(src, dest, compression string) error {
+	args := []string{"convert", "-p", "-f", "raw", "-O", "qcow2"}
+	switch compression {
+	case "", "none":
+	case "zlib":
+		args = append(args, "-c")
+	default:
+		args = append(args, "-c", "-o", "compression_type="+compression)
+	}
+	args = append(args, src, dest)
+	_, err := qemuExecFunction(nil, nil, "qemu-img", args...)
+	if err != nil {
+		os.Remove(dest)
+		return errors.Wrap(err, "could not convert image to qcow2")
+	}
+
+	return nil
+}
+
+// urlSourceArg returns the qemu-img source argument of an image that is a URL.
+func urlSourceArg(url *url.URL) string {
+	if url.Scheme == "rbd" {
+		return rbdSourceArg(url)
+	}
+	// Make sure the timeout is long enough.
+	return fmt.Sprintf("json: {\"file.driver\": \"%s\", \"file.url\": \"%s\", \"file.timeout\": %d}", url.Scheme, url, networkTimeoutSecs)
+}
+
+// rbdSourceArg returns the qemu-img source argument of an rbd://user@/pool/image[@snapshot]?conf=file URL, the ceph
+// configuration file contains the monitors and the keyring of the user.
+func rbdSourceArg(url *url.URL) string {
+	options := map[string]string{
+		"file.driver": "rbd",
+		"file.conf":   url.Query().Get("conf"),
+	}
+	if url.User != nil {
+		options["file.user"] = url.User.Username()
+	}
+	image := strings.TrimPrefix(url.Path, "/")
+	if idx := strings.Index(image, "/"); idx >= 0 {
+		options["file.pool"] = image[:idx]
+		image = image[idx+1:]
+	}
+	if idx := strings.LastIndex(image, "@"); idx >= 0 {
+		options["file.snapshot"] = image[idx+1:]
+		image = image[:idx]
+	}
+	options["file.image"] = image
+	// The options are strings, marshaling them cannot fail.
+	jsonOptions, _ := json.Marshal(options)
+	return "json:" + string(jsonOptions)
+}
+
 // convertQuantityToQemuSize translates a quantity string into a Qemu compatible string.
 func convertQuantityToQemuSize(size resource.Quantity) string {
 	int64Size, asInt := size.AsInt64()
@@ -181,9 +240,7 @@ func (o *qemuOperations) Info(url *url.URL) (*ImgInfo, error) {
 	var err error
 
 	if len(url.Scheme) > 0 {
-		// Image is a URL, make sure the timeout is long enough.
-		jsonArg := fmt.Sprintf("json: {\"file.driver\": \"%s\", \"file.url\": \"%s\", \"file.timeout\": %d}", url.Scheme, url, networkTimeoutSecs)
-		output, err = qemuExecFunction(qemuInfoLimits, nil, "qemu-img", "info", "--output=json", jsonArg)
+		output, err = qemuExecFunction(qemuInfoLimits, nil, "qemu-img", "info", "--output=json", urlSourceArg(url))
 	} else {
 		output, err = qemuExecFunction(qemuInfoLimits, nil, "qemu-img", "info", "--output=json", url.String())
 	}
@@ -195,7 +252,7 @@ func (o *qemuOperations) Info(url *url.URL) (*ImgInfo, error) {
 
 func isSupportedFormat(value string) bool {
 	switch value {
-	case "raw", "qcow2":
+	case "raw", "qcow2", "vhdx", "vpc":
 		return true
 	default:
 		return false
@@ -217,12 +274,12 @@ func checkIfURLIsValid(info *ImgInfo, availableSize int64, filesystemOverhead fl
 	return nil
 }
 
-func (o *qemuOperations) Validate(url *url.URL, availableSize int64, filesystemOverhead float64) error {
+func (o *qemuOperations) Validate(url *url.URL, availableSize int64, filesystemOverhead float64) (*ImgInfo, error) {
 	info, err := o.Info(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return checkIfURLIsValid(info, availableSize, filesystemOverhead, url.String())
+	return info, checkIfURLIsValid(info, availableSize, filesystemOverhead, url.String())
 }
 
 // ConvertToRawStream converts an http accessible image to raw format without locally caching the image
@@ -230,18 +287,31 @@ func ConvertToRawStream(url *url.URL, dest string, preallocate bool) error {
 	return qemuIterface.ConvertToRawStream(url, dest, preallocate)
 }
 
-// Validate does basic validation of a qemu image
-func Validate(url *url.URL, availableSize int64, filesystemOverhead float64) error {
+// Validate does basic validation of a qemu image and returns its information
+func Validate(url *url.URL, availableSize int64, filesystemOverhead float64) (*ImgInfo, error) {
 	return qemuIterface.Validate(url, availableSize, filesystemOverhead)
 }
 
+// ConversionProgress returns the progress in percentage of the last qemu-img conversion
+func ConversionProgress() float64 {
+	conversionProgressMutex.Lock()
+	defer conversionProgressMutex.Unlock()
+	return conversionProgress
+}
+
 func reportProgress(line string) {
 	// (45.34/100%)
 	matches := re.FindStringSubmatch(line)
-	if len(matches) == 2 && ownerUID != "" {
+	if len(matches) != 2 {
+		return
+	}
+	// Don't need to check for an error, the regex made sure its a number we can parse.
+	v, _ := strconv.ParseFloat(matches[1], 64)
+	conversionProgressMutex.Lock()
+	conversionProgress = v
+	conversionProgressMutex.Unlock()
+	if ownerUID != "" {
 		klog.V(1).Info(matches[1])
-		// Don't need to check for an error, the regex made sure its a number we can parse.
-		v, _ := strconv.ParseFloat(matches[1], 64)
 		metric := &dto.Metric{}
 		err := progress.WithLabelValues(ownerUID).Write(metric)
 		if err == nil && v > 0 && v > *metric.Counter.Value {
@@ -54,6 +54,17 @@ const goodValidateJSON = `
 }
 `
 
+const vhdxValidateJSON = `
+{
+    "virtual-size": 4294967296,
+    "filename": "myimage.vhdx",
+    "cluster-size": 33554432,
+    "format": "vhdx",
+    "actual-size": 262152192,
+    "dirty-flag": false
+}
+`
+
 const hugeValidateJSON = `
 {
     "virtual-size": 52949672960,
@@ -173,6 +184,16 @@ var _ = Describe("Convert to Raw", func() {
 		})
 	})
 
+	It("should stream an rbd image to destination", func() {
+		ep, err := url.Parse("rbd://admin@/vms/disk-1@migration?conf=/tmp/ceph/ceph.conf")
+		Expect(err).NotTo(HaveOccurred())
+		jsonArg := `json:{"file.conf":"/tmp/ceph/ceph.conf","file.driver":"rbd","file.image":"disk-1","file.pool":"vms","file.snapshot":"migration","file.user":"admin"}`
+		replaceExecFunction(mockExecFunction("", "", nil, "convert", "-p", "-O", "raw", jsonArg, "dest"), func() {
+			err = ConvertToRawStream(ep, "dest", false)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	It("should add preallocation if requested", func() {
 		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "none", "-p", "-O", "raw", "/somefile/somewhere", "dest", "-o", "preallocation=falloc"), func() {
 			ep, err := url.Parse("/somefile/somewhere")
@@ -192,6 +213,37 @@ var _ = Describe("Convert to Raw", func() {
 	})
 })
 
+var _ = Describe("Convert to qcow2", func() {
+	It("should compress the raw image", func() {
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-p", "-f", "raw", "-O", "qcow2", "-c", "source", "dest"), func() {
+			err := ConvertToQcow2("source", "dest", "zlib")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should compress the raw image with zstd", func() {
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-p", "-f", "raw", "-O", "qcow2", "-c", "-o", "compression_type=zstd", "source", "dest"), func() {
+			err := ConvertToQcow2("source", "dest", "zstd")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should not compress the raw image without compression", func() {
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-p", "-f", "raw", "-O", "qcow2", "source", "dest"), func() {
+			err := ConvertToQcow2("source", "dest", "none")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should return conversion error if exec function returns error", func() {
+		replaceExecFunction(mockExecFunction("", "exit 1", nil, "convert", "-O", "qcow2", "source", "dest"), func() {
+			err := ConvertToQcow2("source", "dest", "zlib")
+			Expect(err).To(HaveOccurred())
+			Expect(strings.Contains(err.Error(), "could not convert image to qcow2")).To(BeTrue())
+		})
+	})
+})
+
 var _ = Describe("Resize", func() {
 	It("Should complete successfully if qemu-img resize succeeds", func() {
 		quantity, err := resource.ParseQuantity("10Gi")
@@ -224,10 +276,11 @@ var _ = Describe("Validate", func() {
 
 	table.DescribeTable("Validate should", func(execfunc execFunctionType, errString string, image *url.URL, overhead float64) {
 		replaceExecFunction(execfunc, func() {
-			err := Validate(image, 42949672960, overhead)
+			info, err := Validate(image, 42949672960, overhead)
 
 			if errString == "" {
 				Expect(err).NotTo(HaveOccurred())
+				Expect(info).ToNot(BeNil())
 			} else {
 				Expect(err).To(HaveOccurred())
 				rootErr := errors.Cause(err)
@@ -239,6 +292,7 @@ var _ = Describe("Validate", func() {
 	},
 		table.Entry("should return success", mockExecFunction(goodValidateJSON, "", expectedLimits, "info", "--output=json", imageName.String()), "", imageName, 0.0),
 		table.Entry("should return success for http url", mockExecFunction(goodValidateJSON, "", expectedLimits, "info", "--output=json", jsonArg), "", httpImage, 0.0),
+		table.Entry("should return success for a vhdx image", mockExecFunction(vhdxValidateJSON, "", expectedLimits, "info", "--output=json", imageName.String()), "", imageName, 0.0),
 		table.Entry("should return error", mockExecFunction("explosion", "exit 1", expectedLimits), "explosion, exit 1", imageName, 0.0),
 		table.Entry("should return error on bad json", mockExecFunction(badValidateJSON, "", expectedLimits), "unexpected end of JSON input", imageName, 0.0),
 		table.Entry("should return error on bad format", mockExecFunction(badFormatValidateJSON, "", expectedLimits), fmt.Sprintf("Invalid format raw2 for image %s", imageName), imageName, 0.0),
@@ -272,6 +326,7 @@ var _ = Describe("Report Progress", func() {
 		err = progress.WithLabelValues(ownerUID).Write(metric)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(*metric.Counter.Value).To(Equal(45.34))
+		Expect(ConversionProgress()).To(Equal(45.34))
 	})
 
 	It("Parse invalid progress line", func() {
@@ -0,0 +1,465 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"k8s.io/klog/v2"
+
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+const (
+	awsEC2APIVersion = "2016-11-15"
+	awsImagePrefix   = "ami-"
+	// number of concurrent GetSnapshotBlock requests
+	awsSnapshotConcurrency = 16
+	// attempts of a GetSnapshotBlock request, the EBS direct APIs throttle aggressively
+	awsSnapshotBlockAttempts = 5
+	awsSnapshotPageSize      = 10000
+)
+
+var (
+	// awsServiceEndpoint returns the endpoint of the service in the region, variable for testing
+	awsServiceEndpoint = func(service, region string) string {
+		return fmt.Sprintf("https://%s.%s.amazonaws.com", service, region)
+	}
+	awsSnapshotRetryDelay = time.Second
+)
+
+// AWSSnapshotDataSource is the data provider for EBS snapshots. The blocks of the snapshot are read with the EBS direct
+// APIs and written at their offset in the target, blocks that were never written are left sparse.
+//
+// With a previous checkpoint only the blocks that changed between the previous and the current snapshot are written
+// to the existing target, the snapshots must be of the same volume.
+type AWSSnapshotDataSource struct {
+	client           *http.Client
+	signer           *v4.Signer
+	region           string
+	snapshotID       string
+	previousSnapshot string
+	// blockSize and volumeSize are reported by the API when listing the blocks
+	blockSize  int64
+	volumeSize uint64
+	blocks     []awsSnapshotBlock
+}
+
+// awsSnapshotBlock is a block to write, the block is zeroed if it has no token.
+type awsSnapshotBlock struct {
+	index int64
+	token string
+}
+
+type awsListSnapshotBlocksOutput struct {
+	Blocks []struct {
+		BlockIndex int64
+		BlockToken string
+	}
+	ChangedBlocks []struct {
+		BlockIndex       int64
+		SecondBlockToken string
+	}
+	BlockSize  int64
+	VolumeSize int64
+	NextToken  string
+}
+
+type awsDescribeImagesOutput struct {
+	Images []struct {
+		RootDeviceName      string `xml:"rootDeviceName"`
+		BlockDeviceMappings []struct {
+			DeviceName string `xml:"deviceName"`
+			SnapshotID string `xml:"ebs>snapshotId"`
+		} `xml:"blockDeviceMapping>item"`
+	} `xml:"imagesSet>item"`
+}
+
+// NewAWSSnapshotDataSource lists the blocks to import. The snapshot of the current checkpoint is imported if set,
+// otherwise the passed in snapshot, or the root device snapshot of the passed in AMI.
+func NewAWSSnapshotDataSource(snapshotID, region, accessKey, secKey, currentCheckpoint, previousCheckpoint, certDir string) (*AWSSnapshotDataSource, error) {
+	if currentCheckpoint == "" && previousCheckpoint != "" {
+		return nil, errors.New("previous checkpoint set without a current checkpoint")
+	}
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	creds := credentials.NewStaticCredentials(accessKey, secKey, "")
+	if accessKey == "" && secKey == "" {
+		// No secret, use the credentials of the pod
+		if creds, err = newAmbientCredentials(region); err != nil {
+			return nil, err
+		}
+	}
+	sd := &AWSSnapshotDataSource{
+		client:           client,
+		signer:           v4.NewSigner(creds),
+		region:           region,
+		snapshotID:       snapshotID,
+		previousSnapshot: previousCheckpoint,
+	}
+	if currentCheckpoint != "" {
+		sd.snapshotID = currentCheckpoint
+	} else if strings.HasPrefix(snapshotID, awsImagePrefix) {
+		if sd.snapshotID, err = sd.rootDeviceSnapshot(snapshotID); err != nil {
+			return nil, err
+		}
+	}
+	if err := sd.listBlocks(); err != nil {
+		return nil, err
+	}
+	if sd.IsDeltaCopy() {
+		klog.V(1).Infof("Importing %d changed blocks between snapshot %s and snapshot %s", len(sd.blocks), sd.previousSnapshot, sd.snapshotID)
+	} else {
+		klog.V(1).Infof("Importing %d blocks of %d bytes from snapshot %s", len(sd.blocks), sd.blockSize, sd.snapshotID)
+	}
+	return sd, nil
+}
+
+// Info is called to get initial information about the data, the snapshot is raw so it is written directly to the target.
+func (sd *AWSSnapshotDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location, the snapshot never needs scratch space.
+func (sd *AWSSnapshotDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// IsDeltaCopy is called to determine if this is a full copy or one delta copy stage of a multi-stage import.
+func (sd *AWSSnapshotDataSource) IsDeltaCopy() bool {
+	return sd.previousSnapshot != ""
+}
+
+// GetCheckpoint returns the snapshot copied by this stage of a multi-stage import.
+func (sd *AWSSnapshotDataSource) GetCheckpoint() string {
+	return sd.snapshotID
+}
+
+// GetChangeID returns the snapshot the changed blocks are listed from, empty for the full copy.
+func (sd *AWSSnapshotDataSource) GetChangeID() string {
+	return sd.previousSnapshot
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (sd *AWSSnapshotDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if sd.IsDeltaCopy() && len(sd.blocks) == 0 {
+		klog.Infof("No changes reported between snapshot %s and snapshot %s, marking transfer complete.", sd.previousSnapshot, sd.snapshotID)
+		return ProcessingPhaseComplete, nil
+	}
+	var outFile *os.File
+	var isBlock bool
+	var err error
+	if sd.IsDeltaCopy() {
+		// Apply the changes to the disk imported by the previous checkpoints
+		if outFile, err = os.OpenFile(fileName, os.O_WRONLY, 0); err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "cannot apply the changes of snapshot %s", sd.snapshotID)
+		}
+	} else {
+		if outFile, isBlock, err = openSegmentTarget(fileName, sd.volumeSize, false); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	defer outFile.Close()
+	if isBlock {
+		// Unlike a new file the block device is not known to be zeroed, zero the blocks missing from the snapshot
+		if err := sd.zeroUnlistedBlocks(outFile); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	if err := sd.writeBlocks(outFile); err != nil {
+		if !isBlock && !sd.IsDeltaCopy() {
+			os.Remove(fileName)
+		}
+		return ProcessingPhaseError, err
+	}
+	if err := outFile.Sync(); err != nil {
+		return ProcessingPhaseError, errors.Wrap(err, "unable to sync the target")
+	}
+	if sd.IsDeltaCopy() {
+		return ProcessingPhasePreallocate, nil
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the url that the data processor can use when converting the data, the snapshot is never converted.
+func (sd *AWSSnapshotDataSource) GetURL() *url.URL {
+	return nil
+}
+
+// Close closes any readers or other open resources.
+func (sd *AWSSnapshotDataSource) Close() error {
+	return nil
+}
+
+func (sd *AWSSnapshotDataSource) request(service, path string, query url.Values) (*http.Response, error) {
+	u := awsServiceEndpoint(service, sd.region) + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	if _, err := sd.signer.Sign(req, nil, service, sd.region, time.Now()); err != nil {
+		return nil, errors.Wrap(err, "unable to sign request")
+	}
+	klog.V(3).Infof("Sending GET %q\n", u)
+	return sd.client.Do(req)
+}
+
+func awsError(resp *http.Response, action string) error {
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	return errors.Errorf("unable to %s, got %d: %s", action, resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// rootDeviceSnapshot returns the snapshot of the root device of the AMI.
+func (sd *AWSSnapshotDataSource) rootDeviceSnapshot(imageID string) (string, error) {
+	resp, err := sd.request("ec2", "/", url.Values{
+		"Action":    {"DescribeImages"},
+		"Version":   {awsEC2APIVersion},
+		"ImageId.1": {imageID},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to describe image %s", imageID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", awsError(resp, "describe image "+imageID)
+	}
+	out := &awsDescribeImagesOutput{}
+	if err := xml.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", errors.Wrapf(err, "unable to parse image %s", imageID)
+	}
+	if len(out.Images) != 1 {
+		return "", errors.Errorf("image %s not found", imageID)
+	}
+	image := out.Images[0]
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.DeviceName == image.RootDeviceName && mapping.SnapshotID != "" {
+			klog.V(1).Infof("Importing snapshot %s of the root device %s of image %s", mapping.SnapshotID, image.RootDeviceName, imageID)
+			return mapping.SnapshotID, nil
+		}
+	}
+	return "", errors.Errorf("image %s has no EBS snapshot for the root device %s", imageID, image.RootDeviceName)
+}
+
+// listBlocks lists the blocks of the snapshot, or the blocks changed since the previous snapshot.
+func (sd *AWSSnapshotDataSource) listBlocks() error {
+	path := "/snapshots/" + url.PathEscape(sd.snapshotID) + "/blocks"
+	query := url.Values{"maxResults": {strconv.Itoa(awsSnapshotPageSize)}}
+	if sd.IsDeltaCopy() {
+		path = "/snapshots/" + url.PathEscape(sd.snapshotID) + "/changedblocks"
+		query.Set("firstSnapshotId", sd.previousSnapshot)
+	}
+	for {
+		resp, err := sd.request("ebs", path, query)
+		if err != nil {
+			return errors.Wrapf(err, "unable to list the blocks of snapshot %s", sd.snapshotID)
+		}
+		out := &awsListSnapshotBlocksOutput{}
+		if resp.StatusCode != http.StatusOK {
+			err = awsError(resp, "list the blocks of snapshot "+sd.snapshotID)
+		} else if decodeErr := json.NewDecoder(resp.Body).Decode(out); decodeErr != nil {
+			err = errors.Wrapf(decodeErr, "unable to parse the blocks of snapshot %s", sd.snapshotID)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		for _, block := range out.Blocks {
+			sd.blocks = append(sd.blocks, awsSnapshotBlock{index: block.BlockIndex, token: block.BlockToken})
+		}
+		for _, block := range out.ChangedBlocks {
+			// Without a second token the block is no longer written in the current snapshot
+			sd.blocks = append(sd.blocks, awsSnapshotBlock{index: block.BlockIndex, token: block.SecondBlockToken})
+		}
+		if out.BlockSize > 0 {
+			sd.blockSize = out.BlockSize
+		}
+		if out.VolumeSize > 0 {
+			sd.volumeSize = uint64(out.VolumeSize) << 30
+		}
+		if out.NextToken == "" {
+			break
+		}
+		query.Set("pageToken", out.NextToken)
+	}
+	if sd.blockSize <= 0 || sd.volumeSize == 0 {
+		return errors.Errorf("no block or volume size returned for snapshot %s", sd.snapshotID)
+	}
+	return nil
+}
+
+// writeBlocks fetches the blocks concurrently and writes them at their offset.
+func (sd *AWSSnapshotDataSource) writeBlocks(outFile *os.File) error {
+	promReader := prometheusutil.NewProgressReader(nil, uint64(len(sd.blocks))*uint64(sd.blockSize), progress, ownerUID)
+	promReader.SetTransferMetrics(transferMetrics)
+	promReader.StartTimedUpdate()
+	defer func() {
+		promReader.Done = true
+	}()
+
+	work := make(chan awsSnapshotBlock, len(sd.blocks))
+	for _, block := range sd.blocks {
+		work <- block
+	}
+	close(work)
+	errs := make(chan error, awsSnapshotConcurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < awsSnapshotConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for block := range work {
+				var err error
+				if block.token == "" {
+					err = zeroFileRange(outFile, block.index*sd.blockSize, sd.blockSize)
+				} else {
+					err = sd.writeBlock(outFile, block)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				atomic.AddUint64(&promReader.Current, uint64(sd.blockSize))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return nil
+}
+
+// zeroUnlistedBlocks zeroes the blocks that are not part of the snapshot.
+func (sd *AWSSnapshotDataSource) zeroUnlistedBlocks(outFile *os.File) error {
+	listed := make(map[int64]bool, len(sd.blocks))
+	for _, block := range sd.blocks {
+		listed[block.index] = true
+	}
+	totalBlocks := int64(sd.volumeSize) / sd.blockSize
+	for start := int64(0); start < totalBlocks; {
+		if listed[start] {
+			start++
+			continue
+		}
+		end := start
+		for end < totalBlocks && !listed[end] {
+			end++
+		}
+		if err := zeroFileRange(outFile, start*sd.blockSize, (end-start)*sd.blockSize); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (sd *AWSSnapshotDataSource) writeBlock(outFile *os.File, block awsSnapshotBlock) error {
+	var err error
+	for attempt := 1; attempt <= awsSnapshotBlockAttempts; attempt++ {
+		var data []byte
+		var retry bool
+		if data, retry, err = sd.getBlock(block); err == nil {
+			if _, err := outFile.WriteAt(data, block.index*sd.blockSize); err != nil {
+				return errors.Wrap(err, "unable to write to file")
+			}
+			return nil
+		}
+		if !retry {
+			return err
+		}
+		klog.V(1).Infof("Retrying block %d of snapshot %s: %v", block.index, sd.snapshotID, err)
+		time.Sleep(time.Duration(attempt) * awsSnapshotRetryDelay)
+	}
+	return err
+}
+
+// getBlock returns the verified data of the block, and whether a failure can be retried.
+func (sd *AWSSnapshotDataSource) getBlock(block awsSnapshotBlock) ([]byte, bool, error) {
+	path := fmt.Sprintf("/snapshots/%s/blocks/%d", url.PathEscape(sd.snapshotID), block.index)
+	resp, err := sd.request("ebs", path, url.Values{"blockToken": {block.token}})
+	if err != nil {
+		return nil, true, errors.Wrapf(err, "unable to get block %d of snapshot %s", block.index, sd.snapshotID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, awsError(resp, fmt.Sprintf("get block %d of snapshot %s", block.index, sd.snapshotID))
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, sd.blockSize+1))
+	if err != nil {
+		return nil, true, errors.Wrapf(err, "unable to read block %d of snapshot %s", block.index, sd.snapshotID)
+	}
+	if int64(len(data)) != sd.blockSize {
+		return nil, true, errors.Errorf("block %d of snapshot %s is %d bytes, expected %d", block.index, sd.snapshotID, len(data), sd.blockSize)
+	}
+	if algorithm := resp.Header.Get("x-amz-Checksum-Algorithm"); algorithm != "" && algorithm != "SHA256" {
+		return nil, false, errors.Errorf("unsupported checksum algorithm %s", algorithm)
+	}
+	if checksum := resp.Header.Get("x-amz-Checksum"); checksum != "" {
+		sum := sha256.Sum256(data)
+		if base64.StdEncoding.EncodeToString(sum[:]) != checksum {
+			return nil, true, errors.Errorf("checksum mismatch for block %d of snapshot %s", block.index, sd.snapshotID)
+		}
+	}
+	return data, false, nil
+}
+
+// zeroFileRange punches a hole in the file or block device, falling back to writing zeroes.
+func zeroFileRange(outFile *os.File, offset, length int64) error {
+	flags := uint32(unix.FALLOC_FL_PUNCH_HOLE | unix.FALLOC_FL_KEEP_SIZE)
+	if err := syscall.Fallocate(int(outFile.Fd()), flags, offset, length); err == nil {
+		return nil
+	}
+	zeroes := make([]byte, 1024*1024)
+	for written := int64(0); written < length; {
+		n := int64(len(zeroes))
+		if length-written < n {
+			n = length - written
+		}
+		if _, err := outFile.WriteAt(zeroes[:n], offset+written); err != nil {
+			return errors.Wrapf(err, "unable to zero range %d-%d", offset, offset+length)
+		}
+		written += n
+	}
+	return nil
+}
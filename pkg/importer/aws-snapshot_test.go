@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS snapshot data source", func() {
+	const blockSize = 4096
+
+	var (
+		ts            *httptest.Server
+		tmpDir        string
+		blocks        map[string]map[int64][]byte
+		changed       []map[string]interface{}
+		corruptBlocks int
+		lock          sync.Mutex
+		savedEndpoint func(string, string) string
+		savedDelay    time.Duration
+	)
+
+	blockData := func(b byte) []byte {
+		return bytes.Repeat([]byte{b}, blockSize)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "aws-snapshot")
+		Expect(err).ToNot(HaveOccurred())
+		blocks = map[string]map[int64][]byte{
+			"snap-01": {0: blockData(1), 2: blockData(2), 5: blockData(5)},
+		}
+		changed = nil
+		corruptBlocks = 0
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=access/"))
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+			switch {
+			case r.URL.Query().Get("Action") == "DescribeImages":
+				Expect(r.Header.Get("Authorization")).To(ContainSubstring("/us-east-1/ec2/aws4_request"))
+				Expect(r.URL.Query().Get("ImageId.1")).To(Equal("ami-01"))
+				fmt.Fprint(w, `<DescribeImagesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><imagesSet><item>`+
+					`<imageId>ami-01</imageId><rootDeviceName>/dev/xvda</rootDeviceName><blockDeviceMapping>`+
+					`<item><deviceName>/dev/xvdb</deviceName><ebs><snapshotId>snap-data</snapshotId></ebs></item>`+
+					`<item><deviceName>/dev/xvda</deviceName><ebs><snapshotId>snap-01</snapshotId></ebs></item>`+
+					`</blockDeviceMapping></item></imagesSet></DescribeImagesResponse>`)
+			case len(parts) == 3 && parts[2] == "blocks":
+				Expect(r.Header.Get("Authorization")).To(ContainSubstring("/us-east-1/ebs/aws4_request"))
+				var indexes []int64
+				for index := range blocks[parts[1]] {
+					indexes = append(indexes, index)
+				}
+				// The pages have to be stable between requests
+				sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+				var list []map[string]interface{}
+				for _, index := range indexes {
+					list = append(list, map[string]interface{}{"BlockIndex": index, "BlockToken": fmt.Sprintf("token-%d", index)})
+				}
+				// Return the blocks in two pages
+				out := map[string]interface{}{"BlockSize": blockSize, "VolumeSize": 1}
+				if r.URL.Query().Get("pageToken") == "" {
+					out["Blocks"] = list[:1]
+					out["NextToken"] = "page-2"
+				} else {
+					out["Blocks"] = list[1:]
+				}
+				json.NewEncoder(w).Encode(out)
+			case len(parts) == 3 && parts[2] == "changedblocks":
+				Expect(r.URL.Query().Get("firstSnapshotId")).To(Equal("snap-01"))
+				json.NewEncoder(w).Encode(map[string]interface{}{"BlockSize": blockSize, "VolumeSize": 1, "ChangedBlocks": changed})
+			case len(parts) == 4 && parts[2] == "blocks":
+				index, _ := strconv.ParseInt(parts[3], 10, 64)
+				Expect(r.URL.Query().Get("blockToken")).To(Equal(fmt.Sprintf("token-%d", index)))
+				data := blocks[parts[1]][index]
+				sum := sha256.Sum256(data)
+				w.Header().Set("x-amz-Checksum", base64.StdEncoding.EncodeToString(sum[:]))
+				w.Header().Set("x-amz-Checksum-Algorithm", "SHA256")
+				lock.Lock()
+				if corruptBlocks > 0 {
+					corruptBlocks--
+					data = blockData(0xff)
+				}
+				lock.Unlock()
+				w.Write(data)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		savedEndpoint, savedDelay = awsServiceEndpoint, awsSnapshotRetryDelay
+		awsServiceEndpoint = func(service, region string) string {
+			return ts.URL
+		}
+		awsSnapshotRetryDelay = time.Millisecond
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		awsServiceEndpoint, awsSnapshotRetryDelay = savedEndpoint, savedDelay
+		os.RemoveAll(tmpDir)
+	})
+
+	readBlock := func(fileName string, index int64) []byte {
+		f, err := os.Open(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+		data := make([]byte, blockSize)
+		_, err = f.ReadAt(data, index*blockSize)
+		Expect(err).ToNot(HaveOccurred())
+		return data
+	}
+
+	It("should write the blocks of the snapshot at their offset", func() {
+		sd, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sd.IsDeltaCopy()).To(BeFalse())
+		Expect(sd.blocks).To(HaveLen(3))
+		phase, err := sd.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = sd.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		info, err := os.Stat(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(Equal(int64(1 << 30)))
+		Expect(readBlock(target, 0)).To(Equal(blockData(1)))
+		Expect(readBlock(target, 1)).To(Equal(blockData(0)))
+		Expect(readBlock(target, 2)).To(Equal(blockData(2)))
+		Expect(readBlock(target, 5)).To(Equal(blockData(5)))
+	})
+
+	It("should import the root device snapshot of an AMI", func() {
+		sd, err := NewAWSSnapshotDataSource("ami-01", "us-east-1", "access", "secret", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sd.snapshotID).To(Equal("snap-01"))
+	})
+
+	It("should retry blocks with an invalid checksum", func() {
+		corruptBlocks = 2
+		sd, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		target := filepath.Join(tmpDir, "disk.img")
+		_, err = sd.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readBlock(target, 0)).To(Equal(blockData(1)))
+		Expect(readBlock(target, 2)).To(Equal(blockData(2)))
+		Expect(readBlock(target, 5)).To(Equal(blockData(5)))
+	})
+
+	It("should apply the changed blocks of the current checkpoint to the existing disk", func() {
+		target := filepath.Join(tmpDir, "disk.img")
+		sd, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "snap-01", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sd.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+
+		blocks["snap-02"] = map[int64][]byte{2: blockData(3), 7: blockData(7)}
+		changed = []map[string]interface{}{
+			{"BlockIndex": 2, "FirstBlockToken": "first-2", "SecondBlockToken": "token-2"},
+			{"BlockIndex": 5, "FirstBlockToken": "first-5"},
+			{"BlockIndex": 7, "SecondBlockToken": "token-7"},
+		}
+		sd, err = NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "snap-02", "snap-01", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sd.IsDeltaCopy()).To(BeTrue())
+		Expect(sd.GetCheckpoint()).To(Equal("snap-02"))
+		Expect(sd.GetChangeID()).To(Equal("snap-01"))
+		phase, err := sd.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhasePreallocate))
+		Expect(readBlock(target, 0)).To(Equal(blockData(1)))
+		Expect(readBlock(target, 2)).To(Equal(blockData(3)))
+		Expect(readBlock(target, 5)).To(Equal(blockData(0)))
+		Expect(readBlock(target, 7)).To(Equal(blockData(7)))
+	})
+
+	It("should complete a delta copy without changes", func() {
+		sd, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "snap-02", "snap-01", "")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := sd.TransferFile(filepath.Join(tmpDir, "missing.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseComplete))
+	})
+
+	It("should fail to apply changes without the disk of the previous checkpoint", func() {
+		changed = []map[string]interface{}{{"BlockIndex": 5, "FirstBlockToken": "first-5"}}
+		sd, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "snap-02", "snap-01", "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sd.TransferFile(filepath.Join(tmpDir, "missing.img"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a previous checkpoint without a current checkpoint", func() {
+		_, err := NewAWSSnapshotDataSource("snap-01", "us-east-1", "access", "secret", "", "snap-00", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,328 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// version of the blob service used for the SAS and the delegation key requests
+	azureStorageVersion = "2020-02-10"
+	azureTimeFormat     = "2006-01-02T15:04:05Z"
+	// lifetime of the generated SAS, requests have to be started before it expires
+	azureSASLifetime = 24 * time.Hour
+	// allow for clock skew between the node and the storage account
+	azureClockSkew     = 5 * time.Minute
+	azureStorageScope  = "https://storage.azure.com/.default"
+	azureAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	// Environment variables set by the Azure AD workload identity webhook
+	azureClientIDVar           = "AZURE_CLIENT_ID"
+	azureTenantIDVar           = "AZURE_TENANT_ID"
+	azureFederatedTokenFileVar = "AZURE_FEDERATED_TOKEN_FILE"
+	azureAuthorityHostVar      = "AZURE_AUTHORITY_HOST"
+	defaultAzureAuthorityHost  = "https://login.microsoftonline.com/"
+)
+
+// AzureBlobCredentials are the credentials used to read a blob. If neither is set the Azure AD workload identity of
+// the pod is used if available, otherwise the blob is read anonymously.
+type AzureBlobCredentials struct {
+	// SASToken is a shared access signature granting read access to the blob
+	SASToken string
+	// AccountKey is the base64 encoded key of the storage account
+	AccountKey string
+}
+
+// AzureADCredentials are the credentials of a service principal. Without a client secret the Azure AD workload
+// identity of the pod is used.
+type AzureADCredentials struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+}
+
+type azureBlob struct {
+	url       *url.URL
+	account   string
+	container string
+	blob      string
+}
+
+type userDelegationKey struct {
+	SignedOid     string `xml:"SignedOid"`
+	SignedTid     string `xml:"SignedTid"`
+	SignedStart   string `xml:"SignedStart"`
+	SignedExpiry  string `xml:"SignedExpiry"`
+	SignedService string `xml:"SignedService"`
+	SignedVersion string `xml:"SignedVersion"`
+	Value         string `xml:"Value"`
+}
+
+// AzureBlobEndpoint returns the url of the blob including a read only shared access signature, so the blob can be
+// read with plain (ranged) http requests. A SAS is generated from the account key, or from a user delegation key
+// requested with the Azure AD workload identity of the pod. The SAS replaces the query of the passed in url.
+func AzureBlobEndpoint(blobURL string, credentials AzureBlobCredentials, certDir string) (string, error) {
+	blob, err := parseAzureBlobURL(blobURL)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	switch {
+	case credentials.SASToken != "":
+		klog.V(1).Infof("Using the SAS token for azure blob %s", blob.blob)
+		blob.url.RawQuery = strings.TrimPrefix(strings.TrimSpace(credentials.SASToken), "?")
+	case credentials.AccountKey != "":
+		klog.V(1).Infof("Using the key of storage account %s for azure blob %s", blob.account, blob.blob)
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(credentials.AccountKey))
+		if err != nil {
+			return "", errors.Wrap(err, "account key is not base64 encoded")
+		}
+		blob.url.RawQuery = blob.serviceSAS(key, now.Add(azureSASLifetime)).Encode()
+	case os.Getenv(azureFederatedTokenFileVar) != "":
+		klog.V(1).Infof("Using workload identity %s for azure blob %s", os.Getenv(azureClientIDVar), blob.blob)
+		client, err := createHTTPClient(certDir, "")
+		if err != nil {
+			return "", errors.Wrap(err, "Error creating http client")
+		}
+		client.Timeout = time.Minute
+		token, err := azureADToken(client, azureStorageScope, AzureADCredentials{})
+		if err != nil {
+			return "", err
+		}
+		udk, err := blob.userDelegationKey(client, token, now)
+		if err != nil {
+			return "", err
+		}
+		values, err := blob.userDelegationSAS(udk, now.Add(azureSASLifetime))
+		if err != nil {
+			return "", err
+		}
+		blob.url.RawQuery = values.Encode()
+	default:
+		// public containers, or a SAS already included in the url
+		klog.V(1).Infof("No credentials for azure blob %s, using the url as is", blob.blob)
+	}
+	return blob.url.String(), nil
+}
+
+// parseAzureBlobURL accepts https://account.blob.core.windows.net/container/blob urls, and the
+// http://host:port/account/container/blob urls of the storage emulator.
+func parseAzureBlobURL(blobURL string) (*azureBlob, error) {
+	u, err := url.Parse(blobURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse azure blob url %q", blobURL)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, errors.Errorf("invalid azure blob url %q", blobURL)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 3)
+	blob := &azureBlob{url: u}
+	if net.ParseIP(u.Hostname()) != nil || u.Hostname() == "localhost" {
+		if len(parts) == 3 {
+			blob.account, blob.container, blob.blob = parts[0], parts[1], parts[2]
+		}
+	} else if len(parts) >= 2 {
+		blob.account = strings.SplitN(u.Hostname(), ".", 2)[0]
+		blob.container, blob.blob = parts[0], strings.Join(parts[1:], "/")
+	}
+	if blob.account == "" || blob.container == "" || blob.blob == "" {
+		return nil, errors.Errorf("invalid azure blob url %q, expected https://account.blob.core.windows.net/container/blob", blobURL)
+	}
+	return blob, nil
+}
+
+func (b *azureBlob) canonicalizedResource() string {
+	return "/blob/" + b.account + "/" + b.container + "/" + b.blob
+}
+
+// serviceURL returns the url of the blob service of the storage account.
+func (b *azureBlob) serviceURL() *url.URL {
+	u := *b.url
+	u.Path = strings.TrimSuffix(u.Path, "/"+b.container+"/"+b.blob) + "/"
+	u.RawPath = ""
+	return &u
+}
+
+// serviceSAS returns the query parameters of a read only service SAS for the blob, signed with the account key.
+func (b *azureBlob) serviceSAS(key []byte, expiry time.Time) url.Values {
+	se := expiry.Format(azureTimeFormat)
+	stringToSign := strings.Join([]string{
+		"r",                       // signedPermissions
+		"",                        // signedStart
+		se,                        // signedExpiry
+		b.canonicalizedResource(), // canonicalizedResource
+		"",                        // signedIdentifier
+		"",                        // signedIP
+		"",                        // signedProtocol
+		azureStorageVersion,       // signedVersion
+		"b",                       // signedResource
+		"",                        // signedSnapshotTime
+		"", "", "", "", "",        // response headers
+	}, "\n")
+	return url.Values{
+		"sv":  {azureStorageVersion},
+		"sr":  {"b"},
+		"sp":  {"r"},
+		"se":  {se},
+		"sig": {signSAS(key, stringToSign)},
+	}
+}
+
+// userDelegationSAS returns the query parameters of a read only user delegation SAS for the blob.
+func (b *azureBlob) userDelegationSAS(udk *userDelegationKey, expiry time.Time) (url.Values, error) {
+	key, err := base64.StdEncoding.DecodeString(udk.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "user delegation key is not base64 encoded")
+	}
+	se := expiry.Format(azureTimeFormat)
+	stringToSign := strings.Join([]string{
+		"r",                       // signedPermissions
+		"",                        // signedStart
+		se,                        // signedExpiry
+		b.canonicalizedResource(), // canonicalizedResource
+		udk.SignedOid,
+		udk.SignedTid,
+		udk.SignedStart,
+		udk.SignedExpiry,
+		udk.SignedService,
+		udk.SignedVersion,
+		"",                  // signedAuthorizedUserObjectId
+		"",                  // signedUnauthorizedUserObjectId
+		"",                  // signedCorrelationId
+		"",                  // signedIP
+		"",                  // signedProtocol
+		azureStorageVersion, // signedVersion
+		"b",                 // signedResource
+		"",                  // signedSnapshotTime
+		"", "", "", "", "",  // response headers
+	}, "\n")
+	return url.Values{
+		"sv":    {azureStorageVersion},
+		"sr":    {"b"},
+		"sp":    {"r"},
+		"se":    {se},
+		"skoid": {udk.SignedOid},
+		"sktid": {udk.SignedTid},
+		"skt":   {udk.SignedStart},
+		"ske":   {udk.SignedExpiry},
+		"sks":   {udk.SignedService},
+		"skv":   {udk.SignedVersion},
+		"sig":   {signSAS(key, stringToSign)},
+	}, nil
+}
+
+func signSAS(key []byte, stringToSign string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// userDelegationKey requests a key to sign the SAS with, valid as long as the SAS.
+func (b *azureBlob) userDelegationKey(client *http.Client, token string, now time.Time) (*userDelegationKey, error) {
+	body := "<?xml version=\"1.0\" encoding=\"utf-8\"?><KeyInfo><Start>" + now.Add(-azureClockSkew).Format(azureTimeFormat) +
+		"</Start><Expiry>" + now.Add(azureSASLifetime).Format(azureTimeFormat) + "</Expiry></KeyInfo>"
+	u := b.serviceURL()
+	u.RawQuery = "restype=service&comp=userdelegationkey"
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create user delegation key request")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	req.Header.Set("Content-Type", "application/xml")
+	klog.V(2).Infof("Requesting user delegation key from %q\n", u.String())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "user delegation key request errored")
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read user delegation key response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("expected status code 200 from the user delegation key request, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	udk := &userDelegationKey{}
+	if err := xml.NewDecoder(bytes.NewReader(content)).Decode(udk); err != nil {
+		return nil, errors.Wrap(err, "unable to parse user delegation key response")
+	}
+	return udk, nil
+}
+
+// azureADToken requests an Azure AD token of the scope, with the client secret of a service principal, or by
+// exchanging the projected service account token of the workload identity of the pod.
+func azureADToken(client *http.Client, scope string, credentials AzureADCredentials) (string, error) {
+	tenantID, clientID := credentials.TenantID, credentials.ClientID
+	if tenantID == "" {
+		tenantID = os.Getenv(azureTenantIDVar)
+	}
+	if clientID == "" {
+		clientID = os.Getenv(azureClientIDVar)
+	}
+	v := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {clientID},
+		"scope":      {scope},
+	}
+	if credentials.ClientSecret != "" {
+		v.Set("client_secret", credentials.ClientSecret)
+	} else {
+		tokenFile := os.Getenv(azureFederatedTokenFileVar)
+		if tokenFile == "" {
+			return "", errors.New("no client secret and no workload identity available for azure")
+		}
+		assertion, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read federated token %s", tokenFile)
+		}
+		v.Set("client_assertion_type", azureAssertionType)
+		v.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	}
+	authorityHost := os.Getenv(azureAuthorityHostVar)
+	if authorityHost == "" {
+		authorityHost = defaultAzureAuthorityHost
+	}
+	tokenURL := strings.TrimSuffix(authorityHost, "/") + "/" + tenantID + "/oauth2/v2.0/token"
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	klog.V(2).Infof("Retrieving azure token from %q\n", tokenURL)
+	token, err := requestToken(client, req)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
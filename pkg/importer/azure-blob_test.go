@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Azure blob endpoint", func() {
+	accountKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+	expectedSignature := func(key []byte, stringToSign string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(stringToSign))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	table.DescribeTable("should parse the blob url", func(blobURL, account, container, blob string, wantErr bool) {
+		b, err := parseAzureBlobURL(blobURL)
+		if wantErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.account).To(Equal(account))
+		Expect(b.container).To(Equal(container))
+		Expect(b.blob).To(Equal(blob))
+	},
+		table.Entry("azure url", "https://account.blob.core.windows.net/images/disk.img", "account", "images", "disk.img", false),
+		table.Entry("azure url with folders", "https://account.blob.core.windows.net/images/fedora/disk.img", "account", "images", "fedora/disk.img", false),
+		table.Entry("emulator url", "http://127.0.0.1:10000/devstoreaccount1/images/disk.img", "devstoreaccount1", "images", "disk.img", false),
+		table.Entry("missing blob", "https://account.blob.core.windows.net/images", "", "", "", true),
+		table.Entry("invalid scheme", "gs://account/images/disk.img", "", "", "", true),
+	)
+
+	It("should use the SAS token", func() {
+		endpoint, err := AzureBlobEndpoint("https://account.blob.core.windows.net/images/disk.img", AzureBlobCredentials{SASToken: "?sv=2020-02-10&sr=b&sp=r&sig=abc"}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint).To(Equal("https://account.blob.core.windows.net/images/disk.img?sv=2020-02-10&sr=b&sp=r&sig=abc"))
+	})
+
+	It("should keep the url without credentials", func() {
+		endpoint, err := AzureBlobEndpoint("https://account.blob.core.windows.net/images/disk.img?sig=abc", AzureBlobCredentials{}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint).To(Equal("https://account.blob.core.windows.net/images/disk.img?sig=abc"))
+	})
+
+	It("should sign a read only SAS with the account key", func() {
+		endpoint, err := AzureBlobEndpoint("https://account.blob.core.windows.net/images/disk.img", AzureBlobCredentials{AccountKey: accountKey}, "")
+		Expect(err).ToNot(HaveOccurred())
+		u, err := url.Parse(endpoint)
+		Expect(err).ToNot(HaveOccurred())
+		q := u.Query()
+		Expect(q.Get("sp")).To(Equal("r"))
+		Expect(q.Get("sr")).To(Equal("b"))
+		Expect(q.Get("sv")).To(Equal(azureStorageVersion))
+		key, _ := base64.StdEncoding.DecodeString(accountKey)
+		stringToSign := "r\n\n" + q.Get("se") + "\n/blob/account/images/disk.img\n\n\n\n" + azureStorageVersion + "\nb\n\n\n\n\n\n"
+		Expect(q.Get("sig")).To(Equal(expectedSignature(key, stringToSign)))
+	})
+
+	It("should fail with an invalid account key", func() {
+		_, err := AzureBlobEndpoint("https://account.blob.core.windows.net/images/disk.img", AzureBlobCredentials{AccountKey: "not base64!"}, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with workload identity", func() {
+		var (
+			ts        *httptest.Server
+			tokenDir  string
+			udkStatus int
+		)
+
+		BeforeEach(func() {
+			var err error
+			udkStatus = http.StatusOK
+			tokenDir, err = ioutil.TempDir("", "azure-token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(tokenDir, "token"), []byte("federated-token\n"), 0600)).To(Succeed())
+			ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				switch {
+				case r.URL.Path == "/tenant/oauth2/v2.0/token":
+					Expect(r.FormValue("client_assertion")).To(Equal("federated-token"))
+					Expect(r.FormValue("client_assertion_type")).To(Equal(azureAssertionType))
+					Expect(r.FormValue("client_id")).To(Equal("client"))
+					Expect(r.FormValue("scope")).To(Equal(azureStorageScope))
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"access_token": "aad-token", "token_type": "Bearer", "expires_in": 3600}`)
+				case r.URL.Path == "/devstoreaccount1/" && r.URL.Query().Get("comp") == "userdelegationkey":
+					Expect(r.Method).To(Equal("POST"))
+					Expect(r.Header.Get("Authorization")).To(Equal("Bearer aad-token"))
+					Expect(r.Header.Get("x-ms-version")).To(Equal(azureStorageVersion))
+					w.WriteHeader(udkStatus)
+					fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><UserDelegationKey><SignedOid>oid</SignedOid><SignedTid>tid</SignedTid><SignedStart>2021-01-01T00:00:00Z</SignedStart><SignedExpiry>2021-01-02T00:00:00Z</SignedExpiry><SignedService>b</SignedService><SignedVersion>%s</SignedVersion><Value>%s</Value></UserDelegationKey>`, azureStorageVersion, accountKey)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			os.Setenv(azureFederatedTokenFileVar, filepath.Join(tokenDir, "token"))
+			os.Setenv(azureClientIDVar, "client")
+			os.Setenv(azureTenantIDVar, "tenant")
+			os.Setenv(azureAuthorityHostVar, ts.URL+"/")
+		})
+
+		AfterEach(func() {
+			ts.Close()
+			os.RemoveAll(tokenDir)
+			os.Unsetenv(azureFederatedTokenFileVar)
+			os.Unsetenv(azureClientIDVar)
+			os.Unsetenv(azureTenantIDVar)
+			os.Unsetenv(azureAuthorityHostVar)
+		})
+
+		It("should sign a user delegation SAS", func() {
+			endpoint, err := AzureBlobEndpoint(ts.URL+"/devstoreaccount1/images/disk.img", AzureBlobCredentials{}, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoint).To(HavePrefix(ts.URL + "/devstoreaccount1/images/disk.img?"))
+			u, err := url.Parse(endpoint)
+			Expect(err).ToNot(HaveOccurred())
+			q := u.Query()
+			Expect(q.Get("skoid")).To(Equal("oid"))
+			Expect(q.Get("sktid")).To(Equal("tid"))
+			key, _ := base64.StdEncoding.DecodeString(accountKey)
+			stringToSign := strings.Join([]string{"r", "", q.Get("se"), "/blob/devstoreaccount1/images/disk.img",
+				"oid", "tid", "2021-01-01T00:00:00Z", "2021-01-02T00:00:00Z", "b", azureStorageVersion,
+				"", "", "", "", "", azureStorageVersion, "b", "", "", "", "", "", ""}, "\n")
+			Expect(q.Get("sig")).To(Equal(expectedSignature(key, stringToSign)))
+		})
+
+		It("should fail if the user delegation key is denied", func() {
+			udkStatus = http.StatusForbidden
+			_, err := AzureBlobEndpoint(ts.URL+"/devstoreaccount1/images/disk.img", AzureBlobCredentials{}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("got 403"))
+		})
+	})
+})
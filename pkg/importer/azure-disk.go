@@ -0,0 +1,276 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	azureManagementScope = "https://management.azure.com/.default"
+	azureComputeVersion  = "2022-03-02"
+	// lifetime of the SAS of the disk, access is revoked when the import completes
+	azureDiskAccessDuration = 24 * time.Hour
+	azureGrantAccessTimeout = 10 * time.Minute
+	// the managed disk is exported as a fixed VHD, the raw disk data followed by a 512 byte footer
+	vhdFooterSize  = 512
+	vhdCookie      = "conectix"
+	vhdDiskTypeFix = 2
+	// number of concurrent ranged requests used to download the disk
+	azureDiskSegments = 4
+)
+
+var (
+	// endpoint of the Azure resource manager, variable for testing
+	azureManagementEndpoint = "https://management.azure.com"
+	azureGrantAccessPoll    = 2 * time.Second
+
+	azureDiskResourceID = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/(disks|snapshots)/[^/]+$`)
+)
+
+// AzureDiskDataSource is the data provider for Azure managed disks and snapshots. Read access to the disk is granted,
+// which returns a SAS of the fixed VHD of the disk. The VHD footer is verified and the raw data preceding it is
+// written directly to the target.
+type AzureDiskDataSource struct {
+	client     *http.Client
+	resourceID string
+	token      string
+	// sasURL is the url of the VHD of the disk
+	sasURL string
+	// diskSize is the size of the raw data of the disk, without the VHD footer
+	diskSize uint64
+	url      *url.URL
+}
+
+type azureAccessURI struct {
+	AccessSAS string `json:"accessSAS"`
+}
+
+// NewAzureDiskDataSource grants read access to the managed disk or snapshot and verifies it can be imported.
+func NewAzureDiskDataSource(resourceID string, credentials AzureADCredentials, certDir string) (*AzureDiskDataSource, error) {
+	if !azureDiskResourceID.MatchString(resourceID) {
+		return nil, errors.Errorf("invalid resource ID %q, expected the ID of a managed disk or snapshot", resourceID)
+	}
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	token, err := azureADToken(&http.Client{Transport: client.Transport, Timeout: time.Minute}, azureManagementScope, credentials)
+	if err != nil {
+		return nil, err
+	}
+	ads := &AzureDiskDataSource{
+		client:     client,
+		resourceID: resourceID,
+		token:      token,
+	}
+	if ads.sasURL, err = ads.grantAccess(); err != nil {
+		return nil, err
+	}
+	if ads.diskSize, err = ads.readVHDFooter(); err != nil {
+		ads.Close()
+		return nil, err
+	}
+	klog.V(1).Infof("Importing %d bytes from %s", ads.diskSize, resourceID)
+	return ads, nil
+}
+
+// Info is called to get initial information about the data, the disk is raw so it is written directly to the target.
+func (ads *AzureDiskDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (ads *AzureDiskDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	if err := downloadSegments(file, ads.diskSize, azureDiskSegments, 0, "", ads.fetchSegment, nil); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ads.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (ads *AzureDiskDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if err := downloadSegments(fileName, ads.diskSize, azureDiskSegments, 0, "", ads.fetchSegment, nil); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (ads *AzureDiskDataSource) GetURL() *url.URL {
+	return ads.url
+}
+
+// Close revokes the access to the disk.
+func (ads *AzureDiskDataSource) Close() error {
+	if ads.sasURL == "" {
+		return nil
+	}
+	resp, err := ads.managementRequest("POST", ads.resourceID+"/endGetAccess", nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to revoke access to the disk")
+	}
+	resp.Body.Close()
+	ads.sasURL = ""
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("unable to revoke access to the disk, got %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (ads *AzureDiskDataSource) managementRequest(method, path string, body []byte) (*http.Response, error) {
+	u := path
+	if !strings.HasPrefix(path, "http") {
+		u = azureManagementEndpoint + path + "?api-version=" + azureComputeVersion
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Authorization", "Bearer "+ads.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	klog.V(2).Infof("Sending %s %q\n", method, u)
+	return ads.client.Do(req)
+}
+
+// grantAccess requests read access to the disk, and waits for the long running operation to return the SAS.
+func (ads *AzureDiskDataSource) grantAccess() (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"access":            "Read",
+		"durationInSeconds": int(azureDiskAccessDuration.Seconds()),
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := ads.managementRequest("POST", ads.resourceID+"/beginGetAccess", body)
+	deadline := time.Now().Add(azureGrantAccessTimeout)
+	for {
+		if err != nil {
+			return "", errors.Wrap(err, "unable to grant access to the disk")
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			access := &azureAccessURI{}
+			err = json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(access)
+			resp.Body.Close()
+			if err != nil {
+				return "", errors.Wrap(err, "unable to parse the access of the disk")
+			}
+			if access.AccessSAS == "" {
+				return "", errors.New("no SAS returned for the disk")
+			}
+			return access.AccessSAS, nil
+		case http.StatusAccepted:
+			location := resp.Header.Get("Location")
+			wait := azureGrantAccessPoll
+			if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && retryAfter > 0 {
+				wait = time.Duration(retryAfter) * time.Second
+			}
+			resp.Body.Close()
+			if location == "" {
+				return "", errors.New("no location returned for the access operation of the disk")
+			}
+			if time.Now().Add(wait).After(deadline) {
+				return "", errors.New("timed out waiting for access to the disk")
+			}
+			time.Sleep(wait)
+			resp, err = ads.managementRequest("GET", location, nil)
+		default:
+			message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			return "", errors.Errorf("unable to grant access to the disk, got %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+	}
+}
+
+// readVHDFooter verifies the disk is a fixed VHD, and returns the size of the raw data.
+func (ads *AzureDiskDataSource) readVHDFooter() (uint64, error) {
+	ep, err := url.Parse(ads.sasURL)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid SAS of the disk")
+	}
+	total, err := getContentLength(ads.client, ep, "", "", "")
+	if err != nil {
+		return 0, err
+	}
+	if total < vhdFooterSize {
+		return 0, errors.Errorf("the disk is too small to be a VHD, %d bytes", total)
+	}
+	body, err := ads.fetchSegment(byteRange{start: int64(total - vhdFooterSize), end: int64(total - 1)})
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	footer := make([]byte, vhdFooterSize)
+	if _, err := io.ReadFull(body, footer); err != nil {
+		return 0, errors.Wrap(err, "unable to read the VHD footer")
+	}
+	if string(footer[0:8]) != vhdCookie {
+		return 0, errors.New("the disk is not a VHD")
+	}
+	if diskType := binary.BigEndian.Uint32(footer[60:64]); diskType != vhdDiskTypeFix {
+		return 0, errors.Errorf("unsupported VHD disk type %d, only fixed VHDs are supported", diskType)
+	}
+	currentSize := binary.BigEndian.Uint64(footer[48:56])
+	if currentSize+vhdFooterSize != total {
+		return 0, errors.Errorf("VHD size %d does not match the size of the disk %d", currentSize, total-vhdFooterSize)
+	}
+	return currentSize, nil
+}
+
+func (ads *AzureDiskDataSource) fetchSegment(r byteRange) (io.ReadCloser, error) {
+	// http.NewRequest can only return error on invalid METHOD, or invalid url, neither can happen here.
+	req, _ := http.NewRequest("GET", ads.sasURL, nil)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.start, r.end))
+	resp, err := ads.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get range %d-%d of the disk", r.start, r.end)
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, errors.Errorf("expected status code 206 for range %d-%d of the disk, got %d", r.start, r.end, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
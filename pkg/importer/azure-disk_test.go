@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Azure disk data source", func() {
+	const resourceID = "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/snapshots/fedora"
+
+	var (
+		ts          *httptest.Server
+		tmpDir      string
+		diskData    []byte
+		diskType    uint32
+		polls       int
+		revoked     int
+		savedPoll   time.Duration
+		savedEP     string
+		grantStatus int
+	)
+
+	vhd := func() []byte {
+		footer := make([]byte, vhdFooterSize)
+		copy(footer, vhdCookie)
+		binary.BigEndian.PutUint64(footer[48:56], uint64(len(diskData)))
+		binary.BigEndian.PutUint32(footer[60:64], diskType)
+		return append(append([]byte{}, diskData...), footer...)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "azure-disk")
+		Expect(err).ToNot(HaveOccurred())
+		diskData = make([]byte, 1024*1024+512)
+		rand.Read(diskData)
+		diskType = vhdDiskTypeFix
+		polls = 0
+		revoked = 0
+		grantStatus = http.StatusAccepted
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			switch r.URL.Path {
+			case "/tenant/oauth2/v2.0/token":
+				Expect(r.FormValue("client_id")).To(Equal("client"))
+				Expect(r.FormValue("client_secret")).To(Equal("secret"))
+				Expect(r.FormValue("scope")).To(Equal(azureManagementScope))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"access_token": "arm-token", "token_type": "Bearer", "expires_in": 3600}`)
+			case resourceID + "/beginGetAccess":
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer arm-token"))
+				Expect(r.URL.Query().Get("api-version")).To(Equal(azureComputeVersion))
+				w.Header().Set("Location", "http://"+r.Host+"/operations/1")
+				w.WriteHeader(grantStatus)
+			case "/operations/1":
+				polls++
+				if polls < 2 {
+					w.Header().Set("Location", "http://"+r.Host+"/operations/1")
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				fmt.Fprintf(w, `{"accessSAS": "http://%s/disk/abcd?sv=2018-03-28&sig=x"}`, r.Host)
+			case "/disk/abcd":
+				http.ServeContent(w, r, "abcd", time.Time{}, bytes.NewReader(vhd()))
+			case resourceID + "/endGetAccess":
+				revoked++
+				w.WriteHeader(http.StatusAccepted)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		savedEP, savedPoll = azureManagementEndpoint, azureGrantAccessPoll
+		azureManagementEndpoint = ts.URL
+		azureGrantAccessPoll = 10 * time.Millisecond
+		os.Setenv(azureAuthorityHostVar, ts.URL)
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		azureManagementEndpoint, azureGrantAccessPoll = savedEP, savedPoll
+		os.Unsetenv(azureAuthorityHostVar)
+		os.RemoveAll(tmpDir)
+	})
+
+	credentials := AzureADCredentials{TenantID: "tenant", ClientID: "client", ClientSecret: "secret"}
+
+	It("should write the raw disk without the VHD footer and revoke the access", func() {
+		ads, err := NewAzureDiskDataSource(resourceID, credentials, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(polls).To(Equal(2))
+		Expect(ads.diskSize).To(Equal(uint64(len(diskData))))
+		phase, err := ads.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = ads.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(diskData))
+		Expect(ads.Close()).To(Succeed())
+		Expect(revoked).To(Equal(1))
+	})
+
+	It("should reject dynamic VHDs and revoke the access", func() {
+		diskType = 3
+		_, err := NewAzureDiskDataSource(resourceID, credentials, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("only fixed VHDs are supported"))
+		Expect(revoked).To(Equal(1))
+	})
+
+	It("should fail if the access is not granted", func() {
+		grantStatus = http.StatusForbidden
+		_, err := NewAzureDiskDataSource(resourceID, credentials, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("got 403"))
+	})
+
+	It("should reject resource IDs that are not disks or snapshots", func() {
+		_, err := NewAzureDiskDataSource("/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/images/fedora", credentials, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,425 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// cosignSignatureMediaType is the media type of the layers of cosign signature images, holding the signed payload
+	cosignSignatureMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// cosignPayloadType is the type of the payloads signing container images
+	cosignPayloadType = "cosign container image signature"
+	// cosignSignatureAnnotation is the layer annotation with the base64 signature of the payload
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// cosignCertificateAnnotation is the layer annotation with the PEM certificate of keyless signatures
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	// cosignChainAnnotation is the layer annotation with the PEM intermediate certificates of keyless signatures
+	cosignChainAnnotation = "dev.sigstore.cosign/chain"
+	// cosignBundleAnnotation is the layer annotation with the proof of the Rekor transparency log entry of the signature
+	cosignBundleAnnotation = "dev.sigstore.cosign/bundle"
+
+	// cosignPublicKeyFile is the file of the PEM public key verifying signatures
+	cosignPublicKeyFile = "cosign.pub"
+	// fulcioRootsFile is the file of the PEM root certificates of Fulcio
+	fulcioRootsFile = "fulcio.crt.pem"
+	// rekorPublicKeyFile is the file of the PEM public key of Rekor
+	rekorPublicKeyFile = "rekor.pub"
+
+	// maxSignaturePayloadSize limits the size of the signed payloads read from the registry
+	maxSignaturePayloadSize = 1 << 20
+)
+
+var (
+	// fulcioIssuerOID is the extension of the Fulcio certificates with the OIDC issuer as a raw string
+	fulcioIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	// fulcioIssuerV2OID is the extension of the Fulcio certificates with the OIDC issuer as a DER encoded string
+	fulcioIssuerV2OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// signaturePolicy verifies the signatures of the registry images, nil if they are not verified
+var signaturePolicy *SignaturePolicy
+
+// SignaturePolicy verifies the cosign signatures of registry images, with a public key, or keyless with the identity
+// and the issuer of the certificate Fulcio issued to the signer and the proof the signature is in the Rekor log.
+type SignaturePolicy struct {
+	publicKey      crypto.PublicKey
+	identity       string
+	issuer         string
+	fulcioRoots    *x509.CertPool
+	rekorPublicKey crypto.PublicKey
+}
+
+// cosignPayload is the simple signing payload of cosign, binding the signature to the manifest digest of the image
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the entry of a signature in the Rekor transparency log, signed by Rekor
+type rekorBundle struct {
+	SignedEntryTimestamp []byte             `json:"SignedEntryTimestamp"`
+	Payload              rekorBundlePayload `json:"Payload"`
+}
+
+// rekorBundlePayload is the signed part of the bundle, the fields are in the order of its canonical JSON
+type rekorBundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// rekorHashedRekord is the body of a Rekor entry of a signature
+type rekorHashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Signature struct {
+			Content string `json:"content"`
+		} `json:"signature"`
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+	} `json:"spec"`
+}
+
+// SetSignaturePolicy loads the public key, or for keyless signatures the Fulcio roots and the Rekor public key, from the
+// directory. The registry images are only imported if one of their cosign signatures verifies from then on.
+func SetSignaturePolicy(dir, identity, issuer string) error {
+	policy, err := loadSignaturePolicy(dir, identity, issuer)
+	if err != nil {
+		return err
+	}
+	signaturePolicy = policy
+	return nil
+}
+
+// loadSignaturePolicy creates a signature policy from the files of the directory, verifying keyless signatures if the
+// identity is set.
+func loadSignaturePolicy(dir, identity, issuer string) (*SignaturePolicy, error) {
+	if identity == "" {
+		key, err := readPublicKey(filepath.Join(dir, cosignPublicKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		return &SignaturePolicy{publicKey: key}, nil
+	}
+	if issuer == "" {
+		return nil, errors.New("the issuer of keyless signatures is required")
+	}
+	roots, err := ioutil.ReadFile(filepath.Join(dir, fulcioRootsFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the Fulcio root certificates")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(roots) {
+		return nil, errors.Errorf("no certificate in %s", fulcioRootsFile)
+	}
+	rekorKey, err := readPublicKey(filepath.Join(dir, rekorPublicKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return &SignaturePolicy{
+		identity:       identity,
+		issuer:         issuer,
+		fulcioRoots:    pool,
+		rekorPublicKey: rekorKey,
+	}, nil
+}
+
+// readPublicKey reads a PEM PKIX public key
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the public key %s", filepath.Base(path))
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.Errorf("no PEM public key in %s", filepath.Base(path))
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the public key %s", filepath.Base(path))
+	}
+	return key, nil
+}
+
+// verifySignature verifies the signature of the SHA-256 digest of the data with the public key
+func verifySignature(key crypto.PublicKey, data, signature []byte) error {
+	digest := sha256.Sum256(data)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var sig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(signature, &sig); err != nil || len(rest) != 0 {
+			return errors.New("invalid ECDSA signature")
+		}
+		if !ecdsa.Verify(k, digest[:], sig.R, sig.S) {
+			return errors.New("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "invalid RSA signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, signature) {
+			return errors.New("invalid Ed25519 signature")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// verify verifies the signature of the payload from the annotations of its layer
+func (p *SignaturePolicy) verify(payload []byte, annotations map[string]string) error {
+	signature, err := base64.StdEncoding.DecodeString(annotations[cosignSignatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return errors.New("missing signature")
+	}
+	if p.publicKey != nil {
+		return verifySignature(p.publicKey, payload, signature)
+	}
+
+	cert, err := parseCertificate(annotations[cosignCertificateAnnotation])
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(annotations[cosignChainAnnotation]))
+	bundle, err := p.verifyBundle(annotations[cosignBundleAnnotation], payload, signature)
+	if err != nil {
+		return err
+	}
+	// Fulcio certificates are only valid for a few minutes, they must have been valid when the signature was logged
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         p.fulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Unix(bundle.Payload.IntegratedTime, 0),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return errors.Wrap(err, "certificate not issued by Fulcio")
+	}
+	if !certificateHasIdentity(cert, p.identity) {
+		return errors.Errorf("certificate not issued to %s", p.identity)
+	}
+	if issuer := certificateIssuer(cert); issuer != p.issuer {
+		return errors.Errorf("certificate issued to an identity of %q instead of %q", issuer, p.issuer)
+	}
+	return verifySignature(cert.PublicKey, payload, signature)
+}
+
+// verifyBundle verifies that Rekor signed the log entry of the signature of the payload
+func (p *SignaturePolicy) verifyBundle(content string, payload, signature []byte) (*rekorBundle, error) {
+	if content == "" {
+		return nil, errors.New("missing Rekor bundle")
+	}
+	bundle := &rekorBundle{}
+	if err := json.Unmarshal([]byte(content), bundle); err != nil {
+		return nil, errors.Wrap(err, "invalid Rekor bundle")
+	}
+	signed, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(p.rekorPublicKey, signed, bundle.SignedEntryTimestamp); err != nil {
+		return nil, errors.Wrap(err, "Rekor bundle not signed by Rekor")
+	}
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Rekor entry")
+	}
+	entry := &rekorHashedRekord{}
+	if err := json.Unmarshal(body, entry); err != nil {
+		return nil, errors.Wrap(err, "invalid Rekor entry")
+	}
+	digest := sha256.Sum256(payload)
+	if entry.Kind != "hashedrekord" || entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(digest[:]) ||
+		entry.Spec.Signature.Content != base64.StdEncoding.EncodeToString(signature) {
+		return nil, errors.New("Rekor entry of another signature")
+	}
+	return bundle, nil
+}
+
+func parseCertificate(content string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(content))
+	if block == nil {
+		return nil, errors.New("missing certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate")
+	}
+	return cert, nil
+}
+
+// certificateHasIdentity returns true if the email or URI of the certificate is the identity
+func certificateHasIdentity(cert *x509.Certificate, identity string) bool {
+	for _, email := range cert.EmailAddresses {
+		if email == identity {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateIssuer returns the OIDC issuer that authenticated the identity of a Fulcio certificate
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(fulcioIssuerV2OID) {
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(fulcioIssuerOID) {
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// cosignSignatureTag returns the tag of the signatures of the manifest digest, sha256-<hex>.sig
+func cosignSignatureTag(manifestDigest string) string {
+	return strings.Replace(manifestDigest, ":", "-", 1) + ".sig"
+}
+
+// verifyImageSignature verifies that one of the cosign signatures of the image verifies with the policy. The signatures
+// are stored in the sha256-<digest>.sig tag of the repository of the image.
+func verifyImageSignature(ctx context.Context, sys *types.SystemContext, src types.ImageSource, policy *SignaturePolicy) error {
+	ref := src.Reference().DockerReference()
+	if ref == nil {
+		return errors.Errorf("%s: the signatures of %s cannot be located", common.ImageSignatureNotVerified, src.Reference().StringWithinTransport())
+	}
+	rawManifest, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "Could not read the image manifest")
+	}
+	manifestDigest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return errors.Wrap(err, "Could not compute the image manifest digest")
+	}
+	signatures := "docker://" + reference.TrimNamed(ref).String() + ":" + cosignSignatureTag(manifestDigest.String())
+	return verifySignatureImage(ctx, sys, signatures, manifestDigest.String(), policy)
+}
+
+// verifySignatureImage verifies that one of the signatures of the signature image verifies with the policy and signs
+// the manifest digest.
+func verifySignatureImage(ctx context.Context, sys *types.SystemContext, signatures, manifestDigest string, policy *SignaturePolicy) error {
+	klog.Infof("Verifying the signatures %s of image %s", signatures, manifestDigest)
+	src, err := readImageSource(ctx, sys, signatures)
+	if err != nil {
+		return errors.Wrapf(err, "%s, unable to read the signatures", common.ImageSignatureNotVerified)
+	}
+	defer closeImage(src)
+	img, err := image.FromSource(ctx, sys, src)
+	if err != nil {
+		return errors.Wrapf(err, "%s, unable to read the signatures", common.ImageSignatureNotVerified)
+	}
+	defer img.Close()
+
+	cache := blobinfocache.DefaultCache(sys)
+	var failures []string
+	for _, layer := range img.LayerInfos() {
+		if layer.MediaType != cosignSignatureMediaType {
+			continue
+		}
+		if err := verifyCosignSignature(ctx, src, layer, manifestDigest, policy, cache); err != nil {
+			klog.V(1).Infof("Signature %s does not verify: %v", layer.Digest, err)
+			failures = append(failures, err.Error())
+			continue
+		}
+		klog.Infof("Signature %s verified", layer.Digest)
+		return nil
+	}
+	if len(failures) == 0 {
+		return errors.Errorf("%s, no signature found", common.ImageSignatureNotVerified)
+	}
+	return errors.Errorf("%s: %s", common.ImageSignatureNotVerified, strings.Join(failures, "; "))
+}
+
+// verifyCosignSignature verifies the signature of the payload of the layer, and that the payload signs the manifest digest
+func verifyCosignSignature(ctx context.Context, src types.ImageSource, layer types.BlobInfo, manifestDigest string, policy *SignaturePolicy, cache types.BlobInfoCache) error {
+	reader, _, err := src.GetBlob(ctx, layer, cache)
+	if err != nil {
+		return errors.Wrap(err, "Could not read signature")
+	}
+	defer reader.Close()
+	var payload bytes.Buffer
+	if _, err := io.Copy(&payload, io.LimitReader(reader, maxSignaturePayloadSize)); err != nil {
+		return errors.Wrap(err, "Could not read signature")
+	}
+	if err := policy.verify(payload.Bytes(), layer.Annotations); err != nil {
+		return err
+	}
+	signed := &cosignPayload{}
+	if err := json.Unmarshal(payload.Bytes(), signed); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if signed.Critical.Type != cosignPayloadType {
+		return errors.Errorf("signature of type %q", signed.Critical.Type)
+	}
+	if signed.Critical.Image.DockerManifestDigest != manifestDigest {
+		return errors.Errorf("signature of image %s", signed.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
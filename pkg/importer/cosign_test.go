@@ -0,0 +1,307 @@
+package importer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/image/v5/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const testManifestDigest = "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+// cosignSignature is a signature layer of a cosign signature image
+type cosignSignature struct {
+	payload     []byte
+	annotations map[string]string
+}
+
+// createSignatureArchive writes an oci-archive of a cosign signature image with a layer per signature
+func createSignatureArchive(path string, signatures ...cosignSignature) {
+	writeOCIArchive(path, func(w *ociArchiveWriter) map[string]interface{} {
+		var layers []map[string]interface{}
+		for _, signature := range signatures {
+			layers = append(layers, w.addBlob(cosignSignatureMediaType, signature.payload, signature.annotations))
+		}
+		return w.addJSONBlob("application/vnd.oci.image.manifest.v1+json", map[string]interface{}{
+			"schemaVersion": 2,
+			"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+			"config":        w.addBlob("application/vnd.oci.image.config.v1+json", []byte("{}"), nil),
+			"layers":        layers,
+		})
+	})
+}
+
+func cosignTestPayload(manifestDigest string) []byte {
+	return []byte(`{"critical":{"identity":{"docker-reference":"registry.example.com/images/fedora"},"image":{"docker-manifest-digest":"` +
+		manifestDigest + `"},"type":"cosign container image signature"},"optional":null}`)
+}
+
+func generateTestKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	return key
+}
+
+func signTestData(key *ecdsa.PrivateKey, data []byte) []byte {
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	Expect(err).ToNot(HaveOccurred())
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	Expect(err).ToNot(HaveOccurred())
+	return signature
+}
+
+func writeTestPublicKey(path string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)).To(Succeed())
+}
+
+var _ = Describe("Cosign signature verification", func() {
+	var (
+		tmpDir  string
+		archive string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cosign")
+		Expect(err).ToNot(HaveOccurred())
+		archive = filepath.Join(tmpDir, "signatures.tar")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	verify := func(policy *SignaturePolicy) error {
+		return verifySignatureImage(context.Background(), &types.SystemContext{}, "oci-archive:"+archive, testManifestDigest, policy)
+	}
+
+	It("should name the signature tag after the manifest digest", func() {
+		Expect(cosignSignatureTag(testManifestDigest)).To(Equal("sha256-2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae.sig"))
+	})
+
+	It("should fail to locate the signatures of an image out of a registry", func() {
+		createArtifactArchive(archive, artifactFile{"application/x-qcow2", "cirros.qcow2", cirrosData})
+		src, err := readImageSource(context.Background(), &types.SystemContext{}, "oci-archive:"+archive)
+		Expect(err).ToNot(HaveOccurred())
+		defer closeImage(src)
+		err = verifyImageSignature(context.Background(), &types.SystemContext{}, src, &SignaturePolicy{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(common.ImageSignatureNotVerified))
+	})
+
+	Context("with a public key", func() {
+		var (
+			key    *ecdsa.PrivateKey
+			policy *SignaturePolicy
+		)
+
+		BeforeEach(func() {
+			key = generateTestKey()
+			writeTestPublicKey(filepath.Join(tmpDir, cosignPublicKeyFile), key)
+			var err error
+			policy, err = loadSignaturePolicy(tmpDir, "", "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		sign := func(key *ecdsa.PrivateKey, payload []byte) cosignSignature {
+			return cosignSignature{payload, map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signTestData(key, payload)),
+			}}
+		}
+
+		It("should verify a signature of the image", func() {
+			payload := cosignTestPayload(testManifestDigest)
+			createSignatureArchive(archive, sign(generateTestKey(), payload), sign(key, payload))
+			Expect(verify(policy)).To(Succeed())
+		})
+
+		It("should fail on a signature of another key", func() {
+			createSignatureArchive(archive, sign(generateTestKey(), cosignTestPayload(testManifestDigest)))
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(common.ImageSignatureNotVerified))
+			Expect(err.Error()).To(ContainSubstring("invalid ECDSA signature"))
+		})
+
+		It("should fail on a signature of another image", func() {
+			createSignatureArchive(archive, sign(key, cosignTestPayload("sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9")))
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("signature of image sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"))
+		})
+
+		It("should fail without signature", func() {
+			createSignatureArchive(archive)
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no signature found"))
+		})
+
+		It("should fail to load a missing public key", func() {
+			_, err := loadSignaturePolicy(filepath.Join(tmpDir, "invalid"), "", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("keyless", func() {
+		const (
+			identity = "builder@example.com"
+			issuer   = "https://accounts.example.com"
+		)
+		var (
+			rootKey   *ecdsa.PrivateKey
+			rootCert  *x509.Certificate
+			rekorKey  *ecdsa.PrivateKey
+			policy    *SignaturePolicy
+			issuedAt  time.Time
+			signerKey *ecdsa.PrivateKey
+		)
+
+		issue := func(email, oidcIssuer string) string {
+			issuerValue, err := asn1.Marshal(oidcIssuer)
+			Expect(err).ToNot(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber:    big.NewInt(2),
+				NotBefore:       issuedAt.Add(-time.Minute),
+				NotAfter:        issuedAt.Add(10 * time.Minute),
+				KeyUsage:        x509.KeyUsageDigitalSignature,
+				ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+				EmailAddresses:  []string{email},
+				ExtraExtensions: []pkix.Extension{{Id: fulcioIssuerV2OID, Value: issuerValue}},
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, rootCert, &signerKey.PublicKey, rootKey)
+			Expect(err).ToNot(HaveOccurred())
+			return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		}
+
+		bundle := func(payload, signature []byte, integratedTime time.Time) string {
+			digest := sha256.Sum256(payload)
+			body, err := json.Marshal(map[string]interface{}{
+				"apiVersion": "0.0.1",
+				"kind":       "hashedrekord",
+				"spec": map[string]interface{}{
+					"signature": map[string]interface{}{"content": base64.StdEncoding.EncodeToString(signature)},
+					"data":      map[string]interface{}{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(digest[:])}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			entry := rekorBundlePayload{
+				Body:           base64.StdEncoding.EncodeToString(body),
+				IntegratedTime: integratedTime.Unix(),
+				LogID:          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+				LogIndex:       42,
+			}
+			signed, err := json.Marshal(entry)
+			Expect(err).ToNot(HaveOccurred())
+			content, err := json.Marshal(rekorBundle{SignedEntryTimestamp: signTestData(rekorKey, signed), Payload: entry})
+			Expect(err).ToNot(HaveOccurred())
+			return string(content)
+		}
+
+		sign := func(cert string, integratedTime time.Time) cosignSignature {
+			payload := cosignTestPayload(testManifestDigest)
+			signature := signTestData(signerKey, payload)
+			return cosignSignature{payload, map[string]string{
+				cosignSignatureAnnotation:   base64.StdEncoding.EncodeToString(signature),
+				cosignCertificateAnnotation: cert,
+				cosignBundleAnnotation:      bundle(payload, signature, integratedTime),
+			}}
+		}
+
+		BeforeEach(func() {
+			issuedAt = time.Now().Add(-24 * time.Hour)
+			rootKey = generateTestKey()
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "fulcio"},
+				NotBefore:             issuedAt.Add(-time.Hour),
+				NotAfter:              issuedAt.Add(365 * 24 * time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &rootKey.PublicKey, rootKey)
+			Expect(err).ToNot(HaveOccurred())
+			rootCert, err = x509.ParseCertificate(der)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, fulcioRootsFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)).To(Succeed())
+			rekorKey = generateTestKey()
+			writeTestPublicKey(filepath.Join(tmpDir, rekorPublicKeyFile), rekorKey)
+			signerKey = generateTestKey()
+			policy, err = loadSignaturePolicy(tmpDir, identity, issuer)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should verify a signature of the identity logged while the certificate was valid", func() {
+			createSignatureArchive(archive, sign(issue(identity, issuer), issuedAt))
+			Expect(verify(policy)).To(Succeed())
+		})
+
+		It("should fail on a signature of another identity", func() {
+			createSignatureArchive(archive, sign(issue("someone@example.com", issuer), issuedAt))
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("certificate not issued to " + identity))
+		})
+
+		It("should fail on a signature of an identity of another issuer", func() {
+			createSignatureArchive(archive, sign(issue(identity, "https://other.example.com"), issuedAt))
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("instead of"))
+		})
+
+		It("should fail on a signature logged once the certificate expired", func() {
+			createSignatureArchive(archive, sign(issue(identity, issuer), issuedAt.Add(time.Hour)))
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("certificate not issued by Fulcio"))
+		})
+
+		It("should fail on a bundle not signed by Rekor", func() {
+			signature := sign(issue(identity, issuer), issuedAt)
+			rekorKey = generateTestKey()
+			signature.annotations[cosignBundleAnnotation] = bundle(signature.payload, signTestData(signerKey, signature.payload), issuedAt)
+			createSignatureArchive(archive, signature)
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Rekor bundle not signed by Rekor"))
+		})
+
+		It("should fail without bundle", func() {
+			signature := sign(issue(identity, issuer), issuedAt)
+			delete(signature.annotations, cosignBundleAnnotation)
+			createSignatureArchive(archive, signature)
+			err := verify(policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing Rekor bundle"))
+		})
+
+		It("should require the issuer", func() {
+			_, err := loadSignaturePolicy(tmpDir, identity, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
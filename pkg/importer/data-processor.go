@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -99,6 +102,32 @@ type ResumableDataSource interface {
 	GetResumePhase() ProcessingPhase
 }
 
+// SourceInfoReader is implemented by the data sources that know the compression and the size of their source.
+type SourceInfoReader interface {
+	// GetSourceInfo returns the compression of the source, empty if not compressed, and its size as downloaded,
+	// 0 if unknown
+	GetSourceInfo() (string, int64)
+}
+
+// VirtualSizeProber is implemented by the data sources finding the virtual size of their source without transferring
+// it, for the sources qemu-img does not read from their url.
+type VirtualSizeProber interface {
+	// ProbeVirtualSize returns the virtual size of the disk image of the source, Info is called first
+	ProbeVirtualSize() (int64, error)
+}
+
+// SourceInfo describes the source of the import, as detected during the processing.
+type SourceInfo struct {
+	// Format is the disk image format of the source, empty if unknown
+	Format string
+	// Compression is the compression of the source, empty if not compressed
+	Compression string
+	// VirtualSize is the size of the disk in the source image, 0 if unknown
+	VirtualSize int64
+	// DownloadedSize is the size of the source as downloaded, 0 if unknown
+	DownloadedSize int64
+}
+
 // DataProcessor holds the fields needed to process data from a data provider.
 type DataProcessor struct {
 	// currentPhase is the phase the processing is in currently.
@@ -125,14 +154,32 @@ type DataProcessor struct {
 	// "skipped" is used to indicate that preallocation would have been perfomed but there was not enough space, so the
 	// preallocation whould have failed.
 	preallocationApplied common.PreallocationStatus
+	// sourceInfo is the information of the source image, nil until the image is validated
+	sourceInfo *image.ImgInfo
+	// transferredRaw is true if the source was written to the target without conversion
+	transferredRaw bool
+	// phaseDurations is the time spent in the info, transfer, convert, resize and preallocate phases
+	phaseDurations map[string]time.Duration
+}
+
+// DeltaReader is implemented by the data sources of multi-stage imports. Every stage copies one checkpoint, the first
+// one in full as the base of the import, and the later ones by applying the changes since the previous checkpoint,
+// identified by its change ID, to the existing data.
+type DeltaReader interface {
+	// IsDeltaCopy returns true if the stage applies the changes since the previous checkpoint to the existing data
+	IsDeltaCopy() bool
+	// GetCheckpoint returns the identifier of the checkpoint copied by the stage
+	GetCheckpoint() string
+	// GetChangeID returns the identifier of the checkpoint the changes are copied from, empty for the base copy
+	GetChangeID() string
 }
 
 // NewDataProcessor create a new instance of a data processor using the passed in data provider.
 func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratchDataDir, requestImageSize string, filesystemOverhead float64, preallocation bool) *DataProcessor {
 	needsDataCleanup := true
-	vddkSource, isVddk := dataSource.(*VDDKDataSource)
-	if isVddk {
-		needsDataCleanup = !vddkSource.IsDeltaCopy()
+	if deltaReader, ok := dataSource.(DeltaReader); ok && deltaReader.IsDeltaCopy() {
+		klog.Infof("Applying the changes between checkpoint %s and checkpoint %s", deltaReader.GetChangeID(), deltaReader.GetCheckpoint())
+		needsDataCleanup = false
 	}
 	dp := &DataProcessor{
 		currentPhase:       ProcessingPhaseInfo,
@@ -144,6 +191,7 @@ func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratch
 		filesystemOverhead: filesystemOverhead,
 		needsDataCleanup:   needsDataCleanup,
 		preallocation:      preallocation,
+		phaseDurations:     make(map[string]time.Duration),
 	}
 	// Calculate available space before doing anything.
 	dp.availableSpace = dp.calculateTargetSize()
@@ -151,14 +199,21 @@ func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratch
 }
 
 // ProcessData is the main synchronous processing loop
-func (dp *DataProcessor) ProcessData() error {
+func (dp *DataProcessor) ProcessData() (err error) {
 	if size, _ := util.GetAvailableSpace(dp.scratchDataDir); size > int64(0) {
-		// Clean up before trying to write, in case a previous attempt left a mess. Note the deferred cleanup is intentional.
-		if err := CleanDir(dp.scratchDataDir); err != nil {
+		// Clean up before trying to write, in case a previous attempt left a mess. An interrupted download is kept
+		// so it resumes. Note the deferred cleanup is intentional.
+		if err := cleanScratchDir(dp.scratchDataDir); err != nil {
 			return errors.Wrap(err, "Failure cleaning up temporary scratch space")
 		}
-		// Attempt to be a good citizen and clean up my mess at the end.
-		defer CleanDir(dp.scratchDataDir)
+		// Attempt to be a good citizen and clean up my mess at the end, the scratch space is kept across retries.
+		defer func() {
+			if err != nil {
+				cleanScratchDir(dp.scratchDataDir)
+			} else {
+				CleanDir(dp.scratchDataDir)
+			}
+		}()
 	}
 
 	if size, _ := util.GetAvailableSpace(dp.dataDir); size > int64(0) && dp.needsDataCleanup {
@@ -185,6 +240,7 @@ func (dp *DataProcessor) ProcessDataResume() error {
 func (dp *DataProcessor) ProcessDataWithPause() error {
 	var err error
 	for dp.currentPhase != ProcessingPhaseComplete && dp.currentPhase != ProcessingPhasePause {
+		phase, started := dp.currentPhase, time.Now()
 		switch dp.currentPhase {
 		case ProcessingPhaseInfo:
 			dp.currentPhase, err = dp.source.Info()
@@ -209,6 +265,7 @@ func (dp *DataProcessor) ProcessDataWithPause() error {
 			if err != nil {
 				err = errors.Wrap(err, "Unable to transfer source data to target file")
 			}
+			dp.transferredRaw = true
 		case ProcessingPhaseValidatePause:
 			validateErr := dp.validate(dp.source.GetURL())
 			if validateErr != nil {
@@ -234,6 +291,9 @@ func (dp *DataProcessor) ProcessDataWithPause() error {
 		default:
 			return errors.Errorf("Unknown processing phase %s", dp.currentPhase)
 		}
+		if name := phaseDurationName(phase); name != "" {
+			dp.phaseDurations[name] += time.Since(started)
+		}
 		if err != nil {
 			klog.Errorf("%+v", err)
 			return err
@@ -245,13 +305,97 @@ func (dp *DataProcessor) ProcessDataWithPause() error {
 
 func (dp *DataProcessor) validate(url *url.URL) error {
 	klog.V(1).Infoln("Validating image")
-	err := qemuOperations.Validate(url, dp.availableSpace, dp.filesystemOverhead)
+	info, err := qemuOperations.Validate(url, dp.availableSpace, dp.filesystemOverhead)
+	if info != nil {
+		dp.sourceInfo = info
+	}
 	if err != nil {
 		return ValidationSizeError{err: err}
 	}
 	return nil
 }
 
+// SourceInfo returns the format, compression and sizes of the source detected while processing it.
+func (dp *DataProcessor) SourceInfo() SourceInfo {
+	info := SourceInfo{}
+	if dp.sourceInfo != nil {
+		info.Format = dp.sourceInfo.Format
+		info.VirtualSize = dp.sourceInfo.VirtualSize
+	} else if dp.transferredRaw {
+		info.Format = "raw"
+	}
+	if reader, ok := dp.source.(SourceInfoReader); ok {
+		info.Compression, info.DownloadedSize = reader.GetSourceInfo()
+	}
+	return info
+}
+
+// ProbeData returns the format, compression and virtual size of the source without transferring it to the target. The
+// disk images read from their url are inspected with qemu-img info, through the same nbdkit filters as the transfer,
+// and the other sources probe the header or the metadata of their image.
+func (dp *DataProcessor) ProbeData() (SourceInfo, error) {
+	phase, err := dp.source.Info()
+	if err != nil {
+		return SourceInfo{}, errors.Wrap(err, "Unable to obtain information about data source")
+	}
+	if phase == ProcessingPhaseConvert && dp.source.GetURL() != nil {
+		info, err := qemuOperations.Info(dp.source.GetURL())
+		if err != nil {
+			return SourceInfo{}, errors.Wrap(err, "Unable to obtain information about the disk image")
+		}
+		dp.sourceInfo = info
+		return dp.SourceInfo(), nil
+	}
+	prober, ok := dp.source.(VirtualSizeProber)
+	if !ok || phase == ProcessingPhaseTransferDataDir {
+		return SourceInfo{}, errors.New("The size of the source is unknown until it is transferred")
+	}
+	virtualSize, err := prober.ProbeVirtualSize()
+	if err != nil {
+		return SourceInfo{}, errors.Wrap(err, "Unable to obtain the virtual size of the disk image")
+	}
+	info := dp.SourceInfo()
+	info.VirtualSize = virtualSize
+	return info, nil
+}
+
+// PhaseDurations returns the time spent in the info, transfer, convert, resize and preallocate phases of the
+// processing, the phases that did not run are missing.
+func (dp *DataProcessor) PhaseDurations() map[string]time.Duration {
+	return dp.phaseDurations
+}
+
+// phaseDurationName returns the name the duration of the phase is reported under, the transfers to the scratch
+// space, the target directory and the target file are reported together. It is empty for the phases not reported.
+func phaseDurationName(phase ProcessingPhase) string {
+	switch phase {
+	case ProcessingPhaseInfo:
+		return "info"
+	case ProcessingPhaseTransferScratch, ProcessingPhaseTransferDataDir, ProcessingPhaseTransferDataFile:
+		return "transfer"
+	case ProcessingPhaseConvert:
+		return "convert"
+	case ProcessingPhaseResize:
+		return "resize"
+	case ProcessingPhasePreallocate:
+		return "preallocate"
+	}
+	return ""
+}
+
+// FormatPhaseDurations formats the phase durations as a comma separated list of phase=seconds, sorted by phase.
+func FormatPhaseDurations(durations map[string]time.Duration) string {
+	phases := make([]string, 0, len(durations))
+	for phase := range durations {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for i, phase := range phases {
+		phases[i] = fmt.Sprintf("%s=%.3f", phase, durations[phase].Seconds())
+	}
+	return strings.Join(phases, ",")
+}
+
 // convert is called when convert the image from the url to a RAW disk image. Source formats include RAW/QCOW2 (Raw to raw conversion is a copy)
 func (dp *DataProcessor) convert(url *url.URL) (ProcessingPhase, error) {
 	err := dp.validate(url)
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -95,6 +96,27 @@ func (m *MockDataProvider) Close() error {
 	return nil
 }
 
+type MockSourceInfoDataProvider struct {
+	MockDataProvider
+	compression string
+	size        int64
+}
+
+// GetSourceInfo returns the compression and the downloaded size of the source
+func (m *MockSourceInfoDataProvider) GetSourceInfo() (string, int64) {
+	return m.compression, m.size
+}
+
+type MockVirtualSizeDataProvider struct {
+	MockDataProvider
+	virtualSize int64
+}
+
+// ProbeVirtualSize returns the virtual size of the source
+func (m *MockVirtualSizeDataProvider) ProbeVirtualSize() (int64, error) {
+	return m.virtualSize, nil
+}
+
 type MockAsyncDataProvider struct {
 	MockDataProvider
 	ResumePhase ProcessingPhase
@@ -130,7 +152,37 @@ func (madp *MockAsyncDataProvider) GetResumePhase() ProcessingPhase {
 	return madp.ResumePhase
 }
 
+type MockDeltaDataProvider struct {
+	MockDataProvider
+	checkpoint string
+	changeID   string
+}
+
+// IsDeltaCopy returns true if the changes since the previous checkpoint are copied.
+func (mddp *MockDeltaDataProvider) IsDeltaCopy() bool {
+	return mddp.changeID != ""
+}
+
+// GetCheckpoint returns the copied checkpoint.
+func (mddp *MockDeltaDataProvider) GetCheckpoint() string {
+	return mddp.checkpoint
+}
+
+// GetChangeID returns the checkpoint the changes are copied from.
+func (mddp *MockDeltaDataProvider) GetChangeID() string {
+	return mddp.changeID
+}
+
 var _ = Describe("Data Processor", func() {
+	It("should keep the data of the previous checkpoints when applying the changes of a delta reader", func() {
+		mddp := &MockDeltaDataProvider{checkpoint: "checkpoint-2", changeID: "checkpoint-1"}
+		dp := NewDataProcessor(mddp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		Expect(dp.needsDataCleanup).To(BeFalse())
+		mddp = &MockDeltaDataProvider{checkpoint: "checkpoint-1"}
+		dp = NewDataProcessor(mddp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		Expect(dp.needsDataCleanup).To(BeTrue())
+	})
+
 	It("should call the right phases based on the responses from the provider, Transfer should pass the scratch data dir as a path", func() {
 		mdp := &MockDataProvider{
 			infoResponse:     ProcessingPhaseTransferScratch,
@@ -303,6 +355,111 @@ var _ = Describe("Convert", func() {
 	})
 })
 
+var _ = Describe("Source info", func() {
+	It("Should report the format and the virtual size of a converted source", func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &MockSourceInfoDataProvider{
+			MockDataProvider: MockDataProvider{
+				url: url,
+			},
+			compression: "gz",
+			size:        1024,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		qcow2Info := image.ImgInfo{Format: "qcow2", VirtualSize: SmallVirtualSize, ActualSize: SmallActualSize}
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&qcow2Info, nil}, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			_, err := dp.convert(mdp.GetURL())
+			Expect(err).ToNot(HaveOccurred())
+		})
+		Expect(dp.SourceInfo()).To(Equal(SourceInfo{
+			Format:         "qcow2",
+			Compression:    "gz",
+			VirtualSize:    SmallVirtualSize,
+			DownloadedSize: 1024,
+		}))
+	})
+
+	It("Should report a raw source written without conversion", func() {
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferDataFile,
+			transferResponse: ProcessingPhaseComplete,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		err := dp.ProcessData()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dp.SourceInfo()).To(Equal(SourceInfo{Format: "raw"}))
+	})
+})
+
+var _ = Describe("Phase durations", func() {
+	It("Should probe the virtual size of a source converted from its url", func() {
+		url, err := url.Parse("http://fakeurl-notreal.fake")
+		Expect(err).ToNot(HaveOccurred())
+		mdp := &MockDataProvider{
+			infoResponse: ProcessingPhaseConvert,
+			url:          url,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		qcow2Info := image.ImgInfo{Format: "qcow2", VirtualSize: SmallVirtualSize, ActualSize: SmallActualSize}
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&qcow2Info, nil}, nil, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			info, err := dp.ProbeData()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Format).To(Equal("qcow2"))
+			Expect(info.VirtualSize).To(Equal(int64(SmallVirtualSize)))
+		})
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("Should probe the virtual size of a source streamed to the target", func() {
+		mdp := &MockVirtualSizeDataProvider{
+			MockDataProvider: MockDataProvider{
+				infoResponse: ProcessingPhaseTransferDataFile,
+			},
+			virtualSize: 1024,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		info, err := dp.ProbeData()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.VirtualSize).To(Equal(int64(1024)))
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("Should not probe the sources that do not know their virtual size", func() {
+		mdp := &MockDataProvider{
+			infoResponse: ProcessingPhaseTransferScratch,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		_, err := dp.ProbeData()
+		Expect(err).To(HaveOccurred())
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("Should report the durations of the phases that ran", func() {
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferDataDir,
+			transferResponse: ProcessingPhaseComplete,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.055, false)
+		err := dp.ProcessData()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dp.PhaseDurations()).To(HaveLen(2))
+		Expect(dp.PhaseDurations()).To(HaveKey("info"))
+		Expect(dp.PhaseDurations()).To(HaveKey("transfer"))
+	})
+
+	It("Should format the durations sorted by phase", func() {
+		durations := map[string]time.Duration{
+			"transfer": 90 * time.Second,
+			"convert":  1500 * time.Millisecond,
+			"info":     20 * time.Millisecond,
+		}
+		Expect(FormatPhaseDurations(durations)).To(Equal("convert=1.500,info=0.020,transfer=90.000"))
+	})
+})
+
 var _ = Describe("Resize", func() {
 	It("Should not resize and return complete, when requestedSize is blank", func() {
 		url, err := url.Parse("http://fakeurl-notreal.fake")
@@ -452,8 +609,8 @@ func (o *fakeQEMUOperations) ConvertToRawStream(*url.URL, string, bool) error {
 	return o.e2
 }
 
-func (o *fakeQEMUOperations) Validate(*url.URL, int64, float64) error {
-	return o.e5
+func (o *fakeQEMUOperations) Validate(*url.URL, int64, float64) (*image.ImgInfo, error) {
+	return o.ret4.imgInfo, o.e5
 }
 
 func (o *fakeQEMUOperations) Resize(dest string, size resource.Quantity) error {
@@ -17,6 +17,7 @@ limitations under the License.
 package importer
 
 import (
+	"archive/tar"
 	"bytes"
 	"compress/gzip"
 	"encoding/hex"
@@ -24,6 +25,7 @@ import (
 	"io/ioutil"
 	"strconv"
 
+	"github.com/containers/image/v5/pkg/compression"
 	"github.com/pkg/errors"
 	"github.com/ulikunitz/xz"
 
@@ -45,7 +47,9 @@ var (
 		},
 		[]string{"ownerUID"},
 	)
-	ownerUID string
+	// transferMetrics are the bytes transferred, throughput and estimated remaining time of the import
+	transferMetrics = prometheusutil.NewTransferMetrics("import")
+	ownerUID        string
 )
 
 func init() {
@@ -68,12 +72,19 @@ type reader struct {
 
 // FormatReaders contains the stack of readers needed to get information from the input stream (io.ReadCloser)
 type FormatReaders struct {
-	readers        []reader
-	buf            []byte // holds file headers
-	Convert        bool
-	Archived       bool
-	ArchiveXz      bool
-	ArchiveGz      bool
+	readers     []reader
+	buf         []byte // holds file headers
+	Convert     bool
+	Archived    bool
+	ArchiveXz   bool
+	ArchiveGz   bool
+	ArchiveZstd bool
+	// ArchiveTar is true if the stream is a tar archive, ExtractTarEntry replaces it by the file it holds
+	ArchiveTar bool
+	// TarEntry is the name of the file extracted from the tar archive
+	TarEntry string
+	// VirtualSize is the virtual size in the header of the disk image to convert, 0 if the header does not hold it
+	VirtualSize    int64
 	progressReader *prometheusutil.ProgressReader
 }
 
@@ -82,12 +93,15 @@ const (
 	rdrMulti
 	rdrXz
 	rdrStream
+	rdrZstd
+	rdrTar
 )
 
 // map scheme and format to rdrType
 var rdrTypM = map[string]int{
 	"gz":     rdrGz,
 	"xz":     rdrXz,
+	"zst":    rdrZstd,
 	"stream": rdrStream,
 }
 
@@ -99,6 +113,7 @@ func NewFormatReaders(stream io.ReadCloser, total uint64) (*FormatReaders, error
 	}
 	if total > uint64(0) {
 		readers.progressReader = prometheusutil.NewProgressReader(stream, total, progress, ownerUID)
+		readers.progressReader.SetTransferMetrics(transferMetrics)
 		err = readers.constructReaders(readers.progressReader)
 	} else {
 		err = readers.constructReaders(stream)
@@ -108,7 +123,11 @@ func NewFormatReaders(stream io.ReadCloser, total uint64) (*FormatReaders, error
 
 func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 	fr.appendReader(rdrTypM["stream"], r)
-	knownHdrs := image.CopyKnownHdrs() // need local copy since keys are removed
+	return fr.detectFormats(image.CopyKnownHdrs())
+}
+
+// detectFormats appends the readers of the compression formats of the top reader, until the header of the image
+func (fr *FormatReaders) detectFormats(knownHdrs image.Headers) error {
 	klog.V(3).Infof("constructReaders: checking compression and archive formats\n")
 	for {
 		hdr, err := fr.matchHeader(&knownHdrs)
@@ -121,8 +140,8 @@ func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 		klog.V(2).Infof("found header of type %q\n", hdr.Format)
 		// create format-specific reader and append it to dataStream readers stack
 		fr.fileFormatSelector(hdr)
-		// exit loop if hdr is qcow2
-		if hdr.Format == "qcow2" {
+		// exit loop if hdr is a disk image to convert
+		if hdr.IsConvertFormat() {
 			break
 		}
 	}
@@ -130,6 +149,28 @@ func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 	return nil
 }
 
+// ExtractTarEntry replaces the tar archive at the top of the reader stack by the first file it holds, and detects the
+// format of that file. The sources of disk images use it for the archives of a single image.
+func (fr *FormatReaders) ExtractTarEntry() error {
+	tr := tar.NewReader(fr.TopReader())
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return errors.Wrap(err, "could not find a file in the tar archive")
+		}
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA {
+			fr.TarEntry = hdr.Name
+			break
+		}
+	}
+	klog.V(2).Infof("tar: extracting %q\n", fr.TarEntry)
+	fr.Archived = true
+	fr.appendReader(rdrTar, tr)
+	knownHdrs := image.CopyKnownHdrs()
+	delete(knownHdrs, "tar")
+	return fr.detectFormats(knownHdrs)
+}
+
 // Append to the receiver's reader stack the passed in reader. If the reader type is multi-reader
 // then wrap a multi-reader around the passed in reader. If the reader is not a Closer then wrap a
 // nop closer.
@@ -178,6 +219,21 @@ func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
 			fr.Archived = true
 			fr.ArchiveXz = true
 		}
+	case "zst":
+		r, err = compression.ZstdDecompressor(fr.TopReader())
+		if err == nil {
+			fr.Archived = true
+			fr.ArchiveZstd = true
+		} else {
+			err = errors.Wrap(err, "could not create zstd reader")
+		}
+	case "tar":
+		fr.ArchiveTar = true
+	default:
+		fr.Convert = hdr.IsConvertFormat()
+		if fr.Convert {
+			fr.VirtualSize, err = hdr.Size(fr.buf)
+		}
 	}
 	if err == nil && r != nil {
 		fr.appendReader(rdrTypM[fFmt], r)
@@ -203,10 +259,11 @@ func (fr *FormatReaders) gzReader() (io.ReadCloser, error) {
 // Note: size is stored at offset 24 in the qcow2 header.
 func (fr *FormatReaders) qcow2NopReader(h *image.Header) (io.Reader, error) {
 	s := hex.EncodeToString(fr.buf[h.SizeOff : h.SizeOff+h.SizeLen])
-	_, err := strconv.ParseInt(s, 16, 64)
+	size, err := strconv.ParseInt(s, 16, 64)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to determine original qcow2 file size from %+v", s)
 	}
+	fr.VirtualSize = size
 	return nil, nil
 }
 
@@ -265,6 +322,59 @@ func (fr *FormatReaders) Close() (rtnerr error) {
 	return rtnerr
 }
 
+// compression returns the compression detected in the headers of the stream, empty if not compressed.
+func (fr *FormatReaders) compression() string {
+	switch {
+	case fr == nil:
+		return ""
+	case fr.ArchiveGz:
+		return "gz"
+	case fr.ArchiveXz:
+		return "xz"
+	case fr.ArchiveZstd:
+		return "zst"
+	}
+	return ""
+}
+
+// ProbeVirtualSize returns the virtual size of the disk image streamed by the readers, size is the length of the
+// stream or 0 if unknown. The size of a disk image to convert is read from its header, and the size of a raw disk
+// image is the length of the stream unless it is compressed or archived, the stream is read to its end then.
+func (fr *FormatReaders) ProbeVirtualSize(size int64) (int64, error) {
+	if fr.Convert {
+		if fr.VirtualSize <= 0 {
+			return 0, errors.New("the header of the disk image does not hold its virtual size")
+		}
+		return fr.VirtualSize, nil
+	}
+	if !fr.Archived && size > 0 {
+		return size, nil
+	}
+	read, err := io.Copy(ioutil.Discard, fr.TopReader())
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to read the disk image")
+	}
+	return read, nil
+}
+
+// addNbdkitFilters adds the nbdkit filters extracting and decompressing the image of the source, there is no nbdkit
+// filter for zstd
+func addNbdkitFilters(n *image.Nbdkit, readers *FormatReaders) {
+	// The tar filter comes first, it reads the archive through the decompression filters
+	if readers.ArchiveTar {
+		n.AddTarFilter(readers.TarEntry)
+		klog.V(2).Infof("Added nbdkit tar filter")
+	}
+	if readers.ArchiveGz {
+		n.AddFilter(image.NbdkitGzipFilter)
+		klog.V(2).Infof("Added nbdkit gzip filter")
+	}
+	if readers.ArchiveXz {
+		n.AddFilter(image.NbdkitXzFilter)
+		klog.V(2).Infof("Added nbdkit xz filter")
+	}
+}
+
 // StartProgressUpdate starts the go routine to automatically update the progress on a set interval.
 func (fr *FormatReaders) StartProgressUpdate() {
 	if fr.progressReader != nil {
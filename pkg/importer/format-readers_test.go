@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,6 +13,8 @@ import (
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"github.com/containers/image/v5/pkg/compression"
+
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/tests/utils"
 )
@@ -24,6 +27,7 @@ var (
 	tinyCoreXzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtXz)
 	tinyCoreGzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtGz)
 	tinyCoreTarFilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtTar)
+	tinyCoreZstFilePath, _    = zstdTestData(tinyCoreFilePath, os.TempDir())
 	archiveFilePath, _        = utils.ArchiveFiles(archiveFileNameWithoutExt, os.TempDir(), tinyCoreFilePath, cirrosFilePath)
 	archiveFileNameWithoutExt = strings.TrimSuffix(archiveFileName, filepath.Ext(archiveFileName))
 	cirrosFilePath            = filepath.Join(imageDir, cirrosFileName)
@@ -62,6 +66,7 @@ var _ = Describe("Format Readers", func() {
 	},
 		table.Entry("successfully construct a xz reader", tinyCoreXzFilePath, 4, false, true, false),              // [stream, multi-r, xz, multi-r] convert = false
 		table.Entry("successfully construct a gz reader", tinyCoreGzFilePath, 4, false, true, false),              // [stream, multi-r, gz, multi-r] convert = false
+		table.Entry("successfully construct a zstd reader", tinyCoreZstFilePath, 4, false, true, false),           // [stream, multi-r, zstd, multi-r] convert = false
 		table.Entry("successfully return the base reader when archived", archiveFilePath, 3, false, false, false), // [stream, multi-r, multi-r] convert = false
 		table.Entry("successfully construct qcow2 reader", cirrosFilePath, 2, false, false, true),                 // [stream, multi-r] convert = true
 		table.Entry("successfully construct .iso reader", tinyCoreFilePath, 2, false, false, false),               // [stream, multi-r] convert = false
@@ -91,6 +96,67 @@ var _ = Describe("Format Readers", func() {
 		table.Entry("should append io.Multireader", rdrMulti, stringRdr, 3, false),
 	)
 
+	It("should extract the image of a tar archive", func() {
+		f, err := os.Open(filepath.Join(imageDir, "cirros.qcow2.tar"))
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+		fr, err = NewFormatReaders(f, uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.ArchiveTar).To(BeTrue())
+		Expect(fr.Convert).To(BeFalse())
+		err = fr.ExtractTarEntry()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.TarEntry).To(Equal("cirros.qcow2"))
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.Convert).To(BeTrue())
+		header := make([]byte, 4)
+		_, err = io.ReadFull(fr.TopReader(), header)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(header).To(Equal([]byte{'Q', 'F', 'I', 0xfb}))
+	})
+
+	table.DescribeTable("should convert the disk images", func(offset int, magic []byte) {
+		header := make([]byte, image.MaxExpectedHdrSize)
+		copy(header[offset:], magic)
+		var err error
+		fr, err = NewFormatReaders(ioutil.NopCloser(bytes.NewReader(header)), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Convert).To(BeTrue())
+		Expect(fr.Archived).To(BeFalse())
+	},
+		table.Entry("vmdk", 0, []byte("KDMV")),
+		table.Entry("vhd", 0, []byte("conectix")),
+		table.Entry("vhdx", 0, []byte("vhdxfile")),
+		table.Entry("vdi", 0x40, []byte{0x7F, 0x10, 0xDA, 0xBE}),
+	)
+
+	table.DescribeTable("can probe the virtual size", func(filename string, size, expected int64) {
+		f, err := os.Open(filename)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		fr, err = NewFormatReaders(f, uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		virtualSize, err := fr.ProbeVirtualSize(size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(virtualSize).To(Equal(expected))
+	},
+		table.Entry("of a qcow2 image from its header", cirrosFilePath, int64(0), int64(46137344)),
+		table.Entry("of a raw image from the size of the source", tinyCoreFilePath, int64(18874368), int64(18874368)),
+		table.Entry("of a raw image of unknown size by reading it", tinyCoreFilePath, int64(0), int64(18874368)),
+		table.Entry("of a gz compressed raw image by decompressing it", tinyCoreGzFilePath, int64(1024), int64(18874368)),
+		table.Entry("of a xz compressed raw image by decompressing it", tinyCoreXzFilePath, int64(1024), int64(18874368)),
+	)
+
+	It("should not probe the virtual size of an image without it in its header", func() {
+		header := make([]byte, image.MaxExpectedHdrSize)
+		copy(header, []byte("KDMV"))
+		fr, err := NewFormatReaders(ioutil.NopCloser(bytes.NewReader(header)), uint64(0))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fr.ProbeVirtualSize(int64(len(header)))
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should not crash on no progress reader", func() {
 		stringReader := ioutil.NopCloser(strings.NewReader("This is a test string"))
 		testReader, err := NewFormatReaders(stringReader, uint64(0))
@@ -101,3 +167,26 @@ var _ = Describe("Format Readers", func() {
 		testReader.StartProgressUpdate()
 	})
 })
+
+// zstdTestData writes the zstd compressed file to the target directory, and returns its path
+func zstdTestData(srcFile, targetDir string) (string, error) {
+	src, err := os.Open(srcFile)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	targetFile := filepath.Join(targetDir, filepath.Base(srcFile)+".zst")
+	dst, err := os.Create(targetFile)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+	w, err := compression.CompressStream(dst, compression.Zstd, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return "", err
+	}
+	return targetFile, w.Close()
+}
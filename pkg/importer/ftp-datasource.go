@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// curlPath is the curl binary used to read files from ftp servers, a variable for testing.
+var curlPath = "curl"
+
+// IsFTPEndpoint returns true if the endpoint of a http source is an ftp:// or ftps:// URL.
+func IsFTPEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && (u.Scheme == "ftp" || u.Scheme == "ftps")
+}
+
+// FTPDataSource is the data provider for ftp and ftps endpoints. The file is streamed with curl to detect its format,
+// qemu-img then reads it through the nbdkit curl plugin if it can, otherwise it is copied to scratch space.
+// Sequence of phases:
+// 1a. Info -> Convert if the file needs conversion and passive mode is used
+// 1b. Info -> TransferScratch if the file needs conversion and active mode is used, the server has to connect back
+// to the importer for each transfer, so the file is only transferred once
+// 1c. Info -> TransferDataFile if the file is a raw image, compressed or not
+// 2. Transfer -> Convert
+type FTPDataSource struct {
+	// endpoint the ftp endpoint to retrieve the data from, without credentials.
+	endpoint *url.URL
+	user     string
+	password string
+	// path to the custom CA. Empty if not used
+	customCA string
+	passive  bool
+	// reader of the file on the server
+	ftpReader *ftpReader
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, the endpoint or a file in scratch space.
+	url *url.URL
+	// file containing the password nbdkit logs in with
+	passwordFile string
+}
+
+// NewFTPDataSource creates a new instance of the ftp data provider, a passive mode data connection is used unless
+// passive is false.
+func NewFTPDataSource(endpoint, user, password, certDir string, passive bool) (*FTPDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	if ep.Scheme != "ftp" && ep.Scheme != "ftps" {
+		return nil, errors.Errorf("%s is not an ftp:// or ftps:// URL", endpoint)
+	}
+	reader, err := newFTPReader(ep, user, password, certDir, passive)
+	if err != nil {
+		return nil, err
+	}
+	return &FTPDataSource{
+		endpoint:  ep,
+		user:      user,
+		password:  password,
+		customCA:  certDir,
+		passive:   passive,
+		ftpReader: reader,
+	}, nil
+}
+
+// Info is called to get initial information about the data.
+func (fs *FTPDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	fs.readers, err = NewFormatReaders(fs.ftpReader, uint64(0))
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if fs.readers.ArchiveTar {
+		if err := fs.readers.ExtractTarEntry(); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	if !fs.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	if !fs.passive || fs.readers.ArchiveZstd {
+		return ProcessingPhaseTransferScratch, nil
+	}
+	// Many servers limit the connections per user, stop reading before nbdkit connects.
+	fs.ftpReader.Close()
+	n := image.NewNbdkitCurl("/var/run/nbdkit.pid", fs.customCA)
+	if fs.user != "" {
+		if err := fs.writePasswordFile(); err != nil {
+			return ProcessingPhaseError, err
+		}
+		n.AddCredentials(fs.user, fs.passwordFile)
+	}
+	addNbdkitFilters(n, fs.readers)
+	qemuOperations = image.NewNbdkitOperations(n)
+	fs.url = fs.endpoint
+	return ProcessingPhaseConvert, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (fs *FTPDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(fs.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	fs.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (fs *FTPDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	fs.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(fs.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (fs *FTPDataSource) GetURL() *url.URL {
+	return fs.url
+}
+
+// Close all readers and removes the password file.
+func (fs *FTPDataSource) Close() error {
+	var err error
+	if fs.readers != nil {
+		err = fs.readers.Close()
+	}
+	if closeErr := fs.ftpReader.Close(); err == nil {
+		err = closeErr
+	}
+	if fs.passwordFile != "" {
+		os.Remove(fs.passwordFile)
+	}
+	return err
+}
+
+func (fs *FTPDataSource) writePasswordFile() error {
+	f, err := ioutil.TempFile("", "ftp-password")
+	if err != nil {
+		return errors.Wrap(err, "unable to create the password file")
+	}
+	fs.passwordFile = f.Name()
+	_, err = f.WriteString(fs.password)
+	f.Close()
+	return errors.Wrap(err, "unable to write the password file")
+}
+
+// ftpReader streams a file from an ftp server with curl, the credentials are passed in a config file so they do not
+// show in the arguments of the process.
+type ftpReader struct {
+	cmd        *exec.Cmd
+	stdout     io.ReadCloser
+	stderr     bytes.Buffer
+	configFile string
+	done       bool
+	waitErr    error
+}
+
+// newFTPReader starts reading the file, curl logs in anonymously without user.
+func newFTPReader(ep *url.URL, user, password, certDir string, passive bool) (*ftpReader, error) {
+	r := &ftpReader{}
+	args := []string{"--silent", "--show-error"}
+	if user != "" {
+		config, err := ioutil.TempFile("", "curl-config")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create the curl config file")
+		}
+		r.configFile = config.Name()
+		_, err = fmt.Fprintf(config, "user = \"%s\"\n", escapeCurlConfig(user+":"+password))
+		config.Close()
+		if err != nil {
+			r.Close()
+			return nil, errors.Wrap(err, "unable to write the curl config file")
+		}
+		args = append(args, "--config", r.configFile)
+	}
+	if passive {
+		args = append(args, "--ftp-pasv")
+	} else {
+		// The server connects back to the address of the control connection
+		args = append(args, "--ftp-port", "-")
+	}
+	if certDir != "" {
+		args = append(args, "--cacert", filepath.Join(certDir, "tls.crt"))
+	}
+	args = append(args, ep.String())
+	r.cmd = exec.Command(curlPath, args...)
+	r.cmd.Stderr = &r.stderr
+	var err error
+	if r.stdout, err = r.cmd.StdoutPipe(); err != nil {
+		r.Close()
+		return nil, errors.Wrap(err, "unable to read the output of curl")
+	}
+	if err := r.cmd.Start(); err != nil {
+		r.Close()
+		return nil, errors.Wrap(err, "unable to start curl")
+	}
+	klog.V(1).Infof("Reading %s", ep)
+	return r, nil
+}
+
+// escapeCurlConfig escapes the value of a quoted parameter of a curl config file.
+func escapeCurlConfig(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
+
+// Read reads the data of the file, the exit status of curl is checked at the end of the data.
+func (r *ftpReader) Read(p []byte) (int, error) {
+	n, err := r.stdout.Read(p)
+	if err == io.EOF {
+		if waitErr := r.wait(); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *ftpReader) wait() error {
+	if r.done {
+		return r.waitErr
+	}
+	r.done = true
+	if err := r.cmd.Wait(); err != nil {
+		r.waitErr = errors.Errorf("curl failed: %v, %s", err, strings.TrimSpace(r.stderr.String()))
+	}
+	return r.waitErr
+}
+
+// Close stops curl if the file was not read completely and removes the config file.
+func (r *ftpReader) Close() error {
+	if r.cmd != nil && r.cmd.Process != nil && !r.done {
+		r.cmd.Process.Kill()
+		r.wait()
+	}
+	if r.configFile != "" {
+		os.Remove(r.configFile)
+		r.configFile = ""
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("FTP data source", func() {
+	var (
+		tmpDir      string
+		scratchDir  string
+		origCurl    string
+		origQemuOps image.QEMUOperations
+		fs          *FTPDataSource
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "ftp")
+		Expect(err).ToNot(HaveOccurred())
+		scratchDir = filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0755)).To(Succeed())
+		// The fake curl records its arguments and config file, and writes the file to stdout
+		script := fmt.Sprintf(`#!/bin/sh
+printf "%%s\n" "$*" > %[1]s/args
+while [ $# -gt 0 ]; do
+	if [ "$1" = "--config" ]; then
+		cp "$2" %[1]s/config
+	fi
+	shift
+done
+if [ -f %[1]s/fail ]; then
+	echo "curl: (67) Access denied: 530" >&2
+	exit 67
+fi
+exec cat %[1]s/file
+`, tmpDir)
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "curl"), []byte(script), 0755)).To(Succeed())
+		origCurl = curlPath
+		curlPath = filepath.Join(tmpDir, "curl")
+		origQemuOps = qemuOperations
+		fs = nil
+	})
+
+	AfterEach(func() {
+		if fs != nil {
+			fs.Close()
+		}
+		curlPath = origCurl
+		qemuOperations = origQemuOps
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should convert a qcow2 image through nbdkit in passive mode", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), cirrosData, 0644)).To(Succeed())
+		var err error
+		fs, err = NewFTPDataSource("ftp://ftp.example.com/images/cirros.qcow2", "admin", "se\"cret", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := fs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(fs.GetURL().String()).To(Equal("ftp://ftp.example.com/images/cirros.qcow2"))
+		password, err := ioutil.ReadFile(fs.passwordFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(password)).To(Equal("se\"cret"))
+
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(HaveSuffix("--ftp-pasv ftp://ftp.example.com/images/cirros.qcow2\n"))
+		Expect(string(args)).ToNot(ContainSubstring("cret"))
+		config, err := ioutil.ReadFile(filepath.Join(tmpDir, "config"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(Equal("user = \"admin:se\\\"cret\"\n"))
+
+		passwordFile := fs.passwordFile
+		Expect(fs.Close()).To(Succeed())
+		_, err = os.Stat(passwordFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should stream a qcow2 image to scratch space in active mode", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), cirrosData, 0644)).To(Succeed())
+		var err error
+		fs, err = NewFTPDataSource("ftps://ftp.example.com/images/cirros.qcow2", "", "", "/certs", false)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := fs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = fs.Transfer(scratchDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(fs.GetURL().String()).To(Equal(filepath.Join(scratchDir, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(scratchDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(Equal("--silent --show-error --ftp-port - --cacert /certs/tls.crt ftps://ftp.example.com/images/cirros.qcow2\n"))
+	})
+
+	It("should write a raw image to the target", func() {
+		expected, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), expected, 0644)).To(Succeed())
+		fs, err = NewFTPDataSource("ftp://ftp.example.com/images/tinyCore.iso", "", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := fs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = fs.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(expected))
+	})
+
+	It("should fail the transfer with the error of curl", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "fail"), nil, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), nil, 0644)).To(Succeed())
+		var err error
+		fs, err = NewFTPDataSource("ftp://ftp.example.com/images/cirros.qcow2", "admin", "wrong", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fs.Info()
+		if err == nil {
+			_, err = fs.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		}
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Access denied"))
+	})
+
+	table.DescribeTable("should detect ftp endpoints", func(endpoint string, expected bool) {
+		Expect(IsFTPEndpoint(endpoint)).To(Equal(expected))
+	},
+		table.Entry("ftp", "ftp://ftp.example.com/images/cirros.qcow2", true),
+		table.Entry("ftps", "ftps://ftp.example.com/images/cirros.qcow2", true),
+		table.Entry("https", "https://www.example.com/images/cirros.qcow2", false),
+	)
+})
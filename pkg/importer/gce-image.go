@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+const (
+	gceComputeReadScope = "https://www.googleapis.com/auth/compute.readonly"
+	gceImageReady       = "READY"
+	// the images exported by gcloud and the raw disks of images are a tar.gz of a sparse disk.raw
+	gceDiskFile = "disk.raw"
+	// chunks of zeroes are skipped when writing to a file, which keeps the holes of the sparse disk
+	gceCopyChunkSize = 1024 * 1024
+)
+
+var (
+	// endpoint of the Compute Engine API, variable for testing
+	gceComputeEndpoint = "https://compute.googleapis.com/compute/v1/"
+)
+
+// GCEImageDataSource is the data provider for Google Compute Engine images. The tar.gz the image was created from, or
+// the tar.gz the image was exported to, is streamed and its disk.raw is written directly to the target.
+type GCEImageDataSource struct {
+	cancel context.CancelFunc
+	// reader of the tar.gz
+	httpReader    io.ReadCloser
+	contentLength uint64
+	// archiveURL is the url of the tar.gz
+	archiveURL string
+}
+
+type gceImage struct {
+	Name    string
+	Status  string
+	RawDisk struct {
+		Source string
+	}
+}
+
+// NewGCEImageDataSource opens the tar.gz of the image. The endpoint is either the path of the image,
+// projects/<project>/global/images/<name>, or the gs:// url the image was exported to.
+func NewGCEImageDataSource(endpoint, key, certDir string) (*GCEImageDataSource, error) {
+	tokenSource, err := newGoogleTokenSource(key, certDir, gceComputeReadScope, gcsReadScope)
+	if err != nil {
+		return nil, err
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve Google access token")
+	}
+	archiveURL := endpoint
+	if !strings.HasPrefix(endpoint, gcsScheme+"://") {
+		if archiveURL, err = gceImageRawDisk(endpoint, token.AccessToken, certDir); err != nil {
+			return nil, err
+		}
+	}
+	if strings.HasPrefix(archiveURL, gcsScheme+"://") {
+		if archiveURL, err = GCSEndpoint(archiveURL); err != nil {
+			return nil, err
+		}
+	}
+	ep, err := ParseEndpoint(archiveURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", archiveURL)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	httpReader, contentLength, _, err := createHTTPReader(ctx, ep, "", "", token.AccessToken, certDir, "")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	klog.V(1).Infof("Importing image archive %s", archiveURL)
+	return &GCEImageDataSource{
+		cancel:        cancel,
+		httpReader:    httpReader,
+		contentLength: contentLength,
+		archiveURL:    archiveURL,
+	}, nil
+}
+
+// gceImageRawDisk returns the url of the tar.gz the image was created from.
+func gceImageRawDisk(image, token, certDir string) (string, error) {
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating http client")
+	}
+	client.Timeout = time.Minute
+	req, err := http.NewRequest("GET", gceComputeEndpoint+strings.TrimPrefix(image, "/"), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create request")
+	}
+	setBearerToken(req, token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get image %s", image)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", errors.Errorf("unable to get image %s, got %d: %s", image, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	gi := &gceImage{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(gi); err != nil {
+		return "", errors.Wrapf(err, "unable to parse image %s", image)
+	}
+	if gi.Status != gceImageReady {
+		return "", errors.Errorf("image %s is %s, not %s", image, gi.Status, gceImageReady)
+	}
+	if gi.RawDisk.Source == "" {
+		return "", errors.Errorf("image %s was not created from a tar.gz in GCS, export it with gcloud compute images export and set the exportUrl", image)
+	}
+	return gi.RawDisk.Source, nil
+}
+
+// Info is called to get initial information about the data, disk.raw is written directly to the target.
+func (gd *GCEImageDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location, the image never needs scratch space.
+func (gd *GCEImageDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (gd *GCEImageDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	promReader := prometheusutil.NewProgressReader(gd.httpReader, gd.contentLength, progress, ownerUID)
+	promReader.SetTransferMetrics(transferMetrics)
+	promReader.StartTimedUpdate()
+	defer func() {
+		promReader.Done = true
+	}()
+	gz, err := gzip.NewReader(promReader)
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "%s is not a tar.gz", gd.archiveURL)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return ProcessingPhaseError, errors.Errorf("no %s in %s", gceDiskFile, gd.archiveURL)
+		}
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "unable to read %s", gd.archiveURL)
+		}
+		if strings.TrimPrefix(hdr.Name, "./") == gceDiskFile {
+			if err := writeSparse(tr, fileName, hdr.Size); err != nil {
+				return ProcessingPhaseError, err
+			}
+			return ProcessingPhaseResize, nil
+		}
+		klog.V(3).Infof("Skipping %s in %s", hdr.Name, gd.archiveURL)
+	}
+}
+
+// GetURL returns the url that the data processor can use when converting the data, the image is never converted.
+func (gd *GCEImageDataSource) GetURL() *url.URL {
+	return nil
+}
+
+// Close closes the stream of the tar.gz.
+func (gd *GCEImageDataSource) Close() error {
+	gd.cancel()
+	return gd.httpReader.Close()
+}
+
+// writeSparse writes size bytes to the passed in file or block device. Chunks of zeroes are skipped when writing to a
+// file, the tar reader expands the holes of sparse files to zeroes.
+func writeSparse(r io.Reader, fileName string, size int64) error {
+	outFile, isBlock, err := openSegmentTarget(fileName, uint64(size), false)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+	zeroes := make([]byte, gceCopyChunkSize)
+	buf := make([]byte, gceCopyChunkSize)
+	var offset int64
+	for offset < size {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if isBlock || !bytes.Equal(buf[:n], zeroes[:n]) {
+				if _, werr := outFile.WriteAt(buf[:n], offset); werr != nil {
+					return errors.Wrap(werr, "unable to write to file")
+				}
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			if !isBlock {
+				os.Remove(fileName)
+			}
+			return errors.Wrap(err, "unable to read disk data")
+		}
+	}
+	if offset != size {
+		if !isBlock {
+			os.Remove(fileName)
+		}
+		return errors.Errorf("incomplete disk data, got %d bytes, expected %d", offset, size)
+	}
+	return outFile.Sync()
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GCE image data source", func() {
+	const image = "projects/project/global/images/fedora"
+
+	var (
+		ts        *httptest.Server
+		tmpDir    string
+		diskData  []byte
+		imageJSON string
+		savedEP   string
+	)
+
+	archive := func(name string, data []byte) []byte {
+		buf := &bytes.Buffer{}
+		gz := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gz)
+		Expect(tw.WriteHeader(&tar.Header{Name: "manifest", Mode: 0644, Size: 2})).To(Succeed())
+		_, err := tw.Write([]byte("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))})).To(Succeed())
+		_, err = tw.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "gce-image")
+		Expect(err).ToNot(HaveOccurred())
+		// Random data with a hole in the middle
+		diskData = make([]byte, 3*gceCopyChunkSize+512)
+		rand.Read(diskData[:gceCopyChunkSize])
+		rand.Read(diskData[2*gceCopyChunkSize:])
+		imageJSON = `{"name": "fedora", "status": "READY", "rawDisk": {"source": "%s/bucket/fedora.tar.gz", "containerType": "TAR"}}`
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			switch r.URL.Path {
+			case gcsMetadataTokenURI:
+				Expect(r.Header.Get("Metadata-Flavor")).To(Equal("Google"))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"access_token": "gce-token", "token_type": "Bearer", "expires_in": 3600}`)
+			case "/compute/v1/" + image:
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer gce-token"))
+				fmt.Fprintf(w, imageJSON, "http://"+r.Host)
+			case "/bucket/fedora.tar.gz":
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer gce-token"))
+				w.Write(archive(gceDiskFile, diskData))
+			case "/bucket/other.tar.gz":
+				w.Write(archive("other.raw", diskData))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		savedEP = gceComputeEndpoint
+		gceComputeEndpoint = ts.URL + "/compute/v1/"
+		os.Setenv(gcsMetadataHostVar, strings.TrimPrefix(ts.URL, "http://"))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		gceComputeEndpoint = savedEP
+		os.Unsetenv(gcsMetadataHostVar)
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should write the disk.raw of the tar.gz the image was created from", func() {
+		gd, err := NewGCEImageDataSource(image, "", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gd.Close()
+		phase, err := gd.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = gd.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(diskData))
+	})
+
+	It("should fail if the archive has no disk.raw", func() {
+		imageJSON = strings.Replace(imageJSON, "fedora.tar.gz", "other.tar.gz", 1)
+		gd, err := NewGCEImageDataSource(image, "", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gd.Close()
+		_, err = gd.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no disk.raw"))
+	})
+
+	It("should require an export of images not created from a tar.gz", func() {
+		imageJSON = `{"name": "fedora", "status": "READY", "sourceDisk": "%s/disks/fedora"}`
+		_, err := NewGCEImageDataSource(image, "", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("gcloud compute images export"))
+	})
+
+	It("should fail if the image is not ready", func() {
+		imageJSON = `{"name": "fedora", "status": "PENDING", "rawDisk": {"source": "%s/bucket/fedora.tar.gz"}}`
+		_, err := NewGCEImageDataSource(image, "", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("PENDING"))
+	})
+
+	It("should fail if the image does not exist", func() {
+		_, err := NewGCEImageDataSource("projects/project/global/images/missing", "", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("got 404"))
+	})
+})
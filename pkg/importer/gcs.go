@@ -0,0 +1,221 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	gcsScheme = "gs"
+	// the XML API accepts bearer tokens, supports ranges and reports ETags, so the http data source can be used as is
+	gcsHost      = "storage.googleapis.com"
+	gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+	// lifetime of the assertions signed with the service account key, the maximum accepted by Google
+	gcsAssertionLifetime = time.Hour
+	jwtBearerGrantType   = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	defaultGCSTokenURL   = "https://oauth2.googleapis.com/token"
+	// GKE workload identity serves the tokens of the bound Google service account on the metadata server
+	gcsMetadataHostVar  = "GCE_METADATA_HOST"
+	gcsMetadataHost     = "metadata.google.internal"
+	gcsMetadataTokenURI = "/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCSEndpoint translates a gs://bucket/object url to the https url of the object.
+func GCSEndpoint(gsURL string) (string, error) {
+	u, err := url.Parse(gsURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse gcs url %q", gsURL)
+	}
+	object := strings.TrimPrefix(u.Path, "/")
+	if u.Scheme != gcsScheme || u.Host == "" || object == "" {
+		return "", errors.Errorf("invalid gcs url %q, expected gs://bucket/object", gsURL)
+	}
+	endpoint := &url.URL{
+		Scheme: "https",
+		Host:   gcsHost,
+		Path:   "/" + u.Host + "/" + object,
+	}
+	return endpoint.String(), nil
+}
+
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// serviceAccountTokenSource exchanges assertions signed with the key of a Google service account for access tokens.
+type serviceAccountTokenSource struct {
+	client *http.Client
+	key    *serviceAccountKey
+	signer *rsa.PrivateKey
+	scopes []string
+}
+
+// metadataTokenSource retrieves the access tokens of the service account of the pod from the metadata server.
+type metadataTokenSource struct {
+	client *http.Client
+	url    string
+}
+
+// NewGCSTokenSource returns a token source for reading GCS objects. With a service account key (JSON) the tokens are
+// retrieved with the key, otherwise the tokens of the pod are retrieved from the metadata server, which is how GKE
+// workload identity provides the credentials of the bound service account.
+func NewGCSTokenSource(key, certDir string) (oauth2.TokenSource, error) {
+	return newGoogleTokenSource(key, certDir, gcsReadScope)
+}
+
+// newGoogleTokenSource returns a token source for the passed in scopes, the scopes of the tokens of the metadata server
+// are those granted to the service account of the pod.
+func newGoogleTokenSource(key, certDir string, scopes ...string) (oauth2.TokenSource, error) {
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	client.Timeout = time.Minute
+	if key == "" {
+		host := os.Getenv(gcsMetadataHostVar)
+		if host == "" {
+			host = gcsMetadataHost
+		}
+		klog.V(1).Infof("Using the metadata server %s for Google credentials", host)
+		return oauth2.ReuseTokenSource(nil, &metadataTokenSource{
+			// The metadata server is only reachable over http, and does not need the custom CAs
+			client: &http.Client{Timeout: time.Minute},
+			url:    "http://" + host + gcsMetadataTokenURI,
+		}), nil
+	}
+	sak := &serviceAccountKey{}
+	if err := json.Unmarshal([]byte(key), sak); err != nil {
+		return nil, errors.Wrap(err, "unable to parse service account key")
+	}
+	if sak.Type != "service_account" || sak.ClientEmail == "" {
+		return nil, errors.New("service account key is not a service_account key")
+	}
+	signer, err := parseRSAPrivateKey(sak.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if sak.TokenURI == "" {
+		sak.TokenURI = defaultGCSTokenURL
+	}
+	klog.V(1).Infof("Using service account %s for Google credentials", sak.ClientEmail)
+	return oauth2.ReuseTokenSource(nil, &serviceAccountTokenSource{
+		client: client,
+		key:    sak,
+		signer: signer,
+		scopes: scopes,
+	}), nil
+}
+
+func parseRSAPrivateKey(key string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM encoded")
+	}
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("service account private key is not a RSA key")
+		}
+		return rsaKey, nil
+	}
+	rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse service account private key")
+	}
+	return rsaKey, nil
+}
+
+// Token exchanges a new signed assertion for an access token.
+func (s *serviceAccountTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{
+		"grant_type": {jwtBearerGrantType},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequest("POST", s.key.TokenURI, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	klog.V(2).Infof("Retrieving gcs token from %q\n", s.key.TokenURI)
+	return requestToken(s.client, req)
+}
+
+// assertion returns a JWT signed with the service account key, requesting the scopes of the token source.
+func (s *serviceAccountTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.key.PrivateKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.key.ClientEmail,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(gcsAssertionLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.signer, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", errors.Wrap(err, "unable to sign assertion")
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token retrieves the current access token from the metadata server.
+func (m *metadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest("GET", m.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	klog.V(2).Infof("Retrieving gcs token from %q\n", m.url)
+	return requestToken(m.client, req)
+}
@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GCS endpoint", func() {
+	table.DescribeTable("should translate the gs url", func(gsURL, expected string, wantErr bool) {
+		endpoint, err := GCSEndpoint(gsURL)
+		if wantErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint).To(Equal(expected))
+	},
+		table.Entry("object in the bucket root", "gs://bucket/disk.img", "https://storage.googleapis.com/bucket/disk.img", false),
+		table.Entry("object in a folder", "gs://bucket/images/disk.qcow2", "https://storage.googleapis.com/bucket/images/disk.qcow2", false),
+		table.Entry("https url", "https://storage.googleapis.com/bucket/disk.img", "", true),
+		table.Entry("missing object", "gs://bucket/", "", true),
+		table.Entry("missing bucket", "gs:///disk.img", "", true),
+	)
+})
+
+var _ = Describe("GCS token source", func() {
+	var (
+		ts       *httptest.Server
+		requests int
+		rsaKey   *rsa.PrivateKey
+	)
+
+	serviceAccountKeyJSON := func(tokenURI string) string {
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := json.Marshal(map[string]string{
+			"type":           "service_account",
+			"client_email":   "importer@project.iam.gserviceaccount.com",
+			"private_key_id": "keyid",
+			"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})),
+			"token_uri":      tokenURI,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return string(key)
+	}
+
+	BeforeEach(func() {
+		var err error
+		requests = 0
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			requests++
+			switch r.URL.Path {
+			case "/token":
+				Expect(r.FormValue("grant_type")).To(Equal(jwtBearerGrantType))
+				parts := strings.Split(r.FormValue("assertion"), ".")
+				Expect(parts).To(HaveLen(3))
+				signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+				Expect(err).ToNot(HaveOccurred())
+				hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+				Expect(rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, hash[:], signature)).To(Succeed())
+				claimBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+				Expect(err).ToNot(HaveOccurred())
+				claims := map[string]interface{}{}
+				Expect(json.Unmarshal(claimBytes, &claims)).To(Succeed())
+				Expect(claims["iss"]).To(Equal("importer@project.iam.gserviceaccount.com"))
+				Expect(claims["scope"]).To(Equal(gcsReadScope))
+				Expect(claims["aud"]).To(Equal(ts.URL + "/token"))
+			case gcsMetadataTokenURI:
+				if r.Header.Get("Metadata-Flavor") != "Google" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, requests)
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		os.Unsetenv(gcsMetadataHostVar)
+	})
+
+	It("should exchange an assertion signed with the service account key for a token", func() {
+		tokenSource, err := NewGCSTokenSource(serviceAccountKeyJSON(ts.URL+"/token"), "")
+		Expect(err).ToNot(HaveOccurred())
+		token, err := tokenSource.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token-1"))
+		token, err = tokenSource.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token-1"))
+		Expect(requests).To(Equal(1))
+	})
+
+	It("should retrieve the token from the metadata server without a key", func() {
+		os.Setenv(gcsMetadataHostVar, strings.TrimPrefix(ts.URL, "http://"))
+		tokenSource, err := NewGCSTokenSource("", "")
+		Expect(err).ToNot(HaveOccurred())
+		token, err := tokenSource.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token-1"))
+	})
+
+	table.DescribeTable("should reject invalid service account keys", func(key string) {
+		_, err := NewGCSTokenSource(key, "")
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("not json", "not a key"),
+		table.Entry("not a service account", `{"type": "authorized_user", "client_email": "user@example.com"}`),
+		table.Entry("no private key", `{"type": "service_account", "client_email": "importer@project.iam.gserviceaccount.com", "private_key": "invalid"}`),
+	)
+})
@@ -0,0 +1,380 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	glanceDefaultDomain = "Default"
+	glanceImageActive   = "active"
+	glanceTokenHeader   = "X-Auth-Token"
+	// maximum size of the Keystone and Glance responses, the service catalog of large clouds can be big
+	maxGlanceResponseSize = 4 * 1024 * 1024
+)
+
+// GlanceCredentials are the credentials used to get a project scoped token from Keystone
+type GlanceCredentials struct {
+	Username string
+	Password string
+	Project  string
+	// Domain of the user and the project, defaults to Default
+	Domain string
+}
+
+// GlanceDataSource is the data provider for OpenStack Glance images.
+// Sequence of phases:
+// 1a. Info -> TransferDataFile if the image is raw
+// 1b. Info -> TransferScratch if the image is qcow2
+// 2. Transfer -> Convert
+type GlanceDataSource struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// reader of the image data
+	glanceReader io.ReadCloser
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, a file in scratch space.
+	url *url.URL
+	// the disk format of the image in Glance
+	diskFormat string
+	// the size of the image in Glance
+	contentLength uint64
+	// hash of the data read so far and the expected hash of the image, nil if Glance has no hash of the image
+	hash         hash.Hash
+	expectedHash string
+}
+
+type glanceImage struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	DiskFormat      string `json:"disk_format"`
+	ContainerFormat string `json:"container_format"`
+	Size            uint64 `json:"size"`
+	Checksum        string `json:"checksum"`
+	HashAlgo        string `json:"os_hash_algo"`
+	HashValue       string `json:"os_hash_value"`
+}
+
+type keystoneToken struct {
+	Token struct {
+		Catalog []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				RegionID  string `json:"region_id"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// NewGlanceDataSource creates a new instance of the Glance data provider. The endpoint is the URL of Keystone, the
+// image endpoint is looked up in the service catalog of the project scoped token.
+func NewGlanceDataSource(endpoint string, credentials GlanceCredentials, region, imageID, certDir string) (*GlanceDataSource, error) {
+	apiClient, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	apiClient.Timeout = time.Minute
+	token, imageEndpoint, err := keystoneAuthenticate(apiClient, endpoint, credentials, region)
+	if err != nil {
+		return nil, err
+	}
+	imageURL := strings.TrimSuffix(strings.TrimSuffix(imageEndpoint, "/"), "/v2") + "/v2/images/" + imageID
+	image, err := getGlanceImage(apiClient, imageURL, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateGlanceImage(image); err != nil {
+		return nil, err
+	}
+
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest("GET", imageURL+"/file", nil)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(glanceTokenHeader, token)
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrapf(err, "unable to download image %s", imageID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, errors.Errorf("unable to download image %s, got %d", imageID, resp.StatusCode)
+	}
+	gs := &GlanceDataSource{
+		ctx:           ctx,
+		cancel:        cancel,
+		diskFormat:    image.DiskFormat,
+		contentLength: image.Size,
+	}
+	gs.hash, gs.expectedHash = glanceImageHash(image)
+	if gs.hash != nil {
+		gs.glanceReader = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(resp.Body, gs.hash), resp.Body}
+	} else {
+		klog.Warningf("Image %s has no checksum, the downloaded data is not verified", imageID)
+		gs.glanceReader = resp.Body
+	}
+	klog.V(1).Infof("Importing %s image %s of %d bytes", image.DiskFormat, imageID, image.Size)
+	return gs, nil
+}
+
+// keystoneAuthenticate requests a project scoped token with the password of the user, and returns the token and the
+// public image endpoint of the service catalog.
+func keystoneAuthenticate(client *http.Client, authURL string, credentials GlanceCredentials, region string) (string, string, error) {
+	domain := credentials.Domain
+	if domain == "" {
+		domain = glanceDefaultDomain
+	}
+	auth := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     credentials.Username,
+						"password": credentials.Password,
+						"domain":   map[string]string{"name": domain},
+					},
+				},
+			},
+			"scope": map[string]interface{}{
+				"project": map[string]interface{}{
+					"name":   credentials.Project,
+					"domain": map[string]string{"name": domain},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(auth)
+	if err != nil {
+		return "", "", errors.Wrap(err, "unable to encode authentication request")
+	}
+	authURL = strings.TrimSuffix(authURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+	resp, err := client.Post(authURL+"/auth/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", "", errors.Wrap(err, "unable to authenticate to keystone")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", "", errors.Errorf("unable to authenticate to keystone, got %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	token := resp.Header.Get("X-Subject-Token")
+	if token == "" {
+		return "", "", errors.New("keystone did not return a token")
+	}
+	kt := &keystoneToken{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxGlanceResponseSize)).Decode(kt); err != nil {
+		return "", "", errors.Wrap(err, "unable to parse keystone token")
+	}
+	for _, service := range kt.Token.Catalog {
+		if service.Type != "image" {
+			continue
+		}
+		for _, ep := range service.Endpoints {
+			if ep.Interface == "public" && (region == "" || ep.RegionID == region || ep.Region == region) {
+				return token, ep.URL, nil
+			}
+		}
+	}
+	if region != "" {
+		return "", "", errors.Errorf("no public image endpoint in region %s", region)
+	}
+	return "", "", errors.New("no public image endpoint in the service catalog")
+}
+
+func getGlanceImage(client *http.Client, imageURL, token string) (*glanceImage, error) {
+	req, err := http.NewRequest("GET", imageURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set(glanceTokenHeader, token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get image")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unable to get image %s, got %d", imageURL, resp.StatusCode)
+	}
+	image := &glanceImage{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxGlanceResponseSize)).Decode(image); err != nil {
+		return nil, errors.Wrap(err, "unable to parse image")
+	}
+	return image, nil
+}
+
+// validateGlanceImage checks the image can be imported, only bare disks in formats qemu-img validates are supported.
+func validateGlanceImage(image *glanceImage) error {
+	if image.Status != glanceImageActive {
+		return errors.Errorf("image %s is %s, not %s", image.ID, image.Status, glanceImageActive)
+	}
+	switch image.ContainerFormat {
+	case "bare", "compressed":
+	default:
+		return errors.Errorf("container format %s of image %s is not supported", image.ContainerFormat, image.ID)
+	}
+	switch image.DiskFormat {
+	case "raw", "qcow2", "iso":
+	default:
+		return errors.Errorf("disk format %s of image %s is not supported, convert the image to raw or qcow2", image.DiskFormat, image.ID)
+	}
+	return nil
+}
+
+// glanceImageHash returns the hash function and the expected hash of the image, the multihash of Glance is preferred
+// over the md5 checksum.
+func glanceImageHash(image *glanceImage) (hash.Hash, string) {
+	switch {
+	case image.HashAlgo == "sha512" && image.HashValue != "":
+		return sha512.New(), image.HashValue
+	case image.HashAlgo == "sha384" && image.HashValue != "":
+		return sha512.New384(), image.HashValue
+	case image.HashAlgo == "sha256" && image.HashValue != "":
+		return sha256.New(), image.HashValue
+	case image.Checksum != "":
+		return md5.New(), image.Checksum
+	}
+	return nil, ""
+}
+
+// Info is called to get initial information about the data. The detected format has to match the disk format of the
+// image, so a raw image is never interpreted as qcow2.
+func (gs *GlanceDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	gs.readers, err = NewFormatReaders(gs.glanceReader, gs.contentLength)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if gs.readers.Convert != (gs.diskFormat == "qcow2") {
+		return ProcessingPhaseError, errors.Errorf("the data of the image does not match its disk format %s", gs.diskFormat)
+	}
+	if !gs.readers.Convert {
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (gs *GlanceDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(gs.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := gs.verifyHash(); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	gs.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (gs *GlanceDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	gs.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(gs.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := gs.verifyHash(); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// verifyHash compares the hash of the downloaded data with the hash of the image in Glance. The rest of the data is
+// read first, the decompressing readers may stop before the end of the stream.
+func (gs *GlanceDataSource) verifyHash() error {
+	if gs.hash == nil {
+		return nil
+	}
+	if _, err := io.Copy(ioutil.Discard, gs.glanceReader); err != nil {
+		return errors.Wrap(err, "unable to read image")
+	}
+	if actual := hex.EncodeToString(gs.hash.Sum(nil)); actual != gs.expectedHash {
+		return errors.Errorf("checksum mismatch, expected %s, got %s", gs.expectedHash, actual)
+	}
+	return nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (gs *GlanceDataSource) GetURL() *url.URL {
+	return gs.url
+}
+
+// Close all readers.
+func (gs *GlanceDataSource) Close() error {
+	var err error
+	if gs.readers != nil {
+		err = gs.readers.Close()
+	}
+	gs.cancel()
+	if closeErr := gs.glanceReader.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GetSourceInfo returns the compression of the source and its size as downloaded
+func (gs *GlanceDataSource) GetSourceInfo() (string, int64) {
+	return gs.readers.compression(), int64(gs.contentLength)
+}
@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"crypto/md5"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Glance data source", func() {
+	const imageID = "0f2e1c38-5b2a-4d1e-9f3c-6a7b8c9d0e1f"
+
+	var (
+		ts        *httptest.Server
+		tmpDir    string
+		imageData []byte
+		image     map[string]interface{}
+		authBody  map[string]interface{}
+	)
+
+	credentials := GlanceCredentials{Username: "user", Password: "secret", Project: "admin"}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "glance")
+		Expect(err).ToNot(HaveOccurred())
+		imageData = cirrosData
+		sum := sha512.Sum512(imageData)
+		image = map[string]interface{}{
+			"id":               imageID,
+			"status":           "active",
+			"disk_format":      "qcow2",
+			"container_format": "bare",
+			"size":             len(imageData),
+			"os_hash_algo":     "sha512",
+			"os_hash_value":    hex.EncodeToString(sum[:]),
+		}
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			switch r.URL.Path {
+			case "/identity/v3/auth/tokens":
+				Expect(r.Method).To(Equal("POST"))
+				authBody = map[string]interface{}{}
+				Expect(json.NewDecoder(r.Body).Decode(&authBody)).To(Succeed())
+				w.Header().Set("X-Subject-Token", "keystone-token")
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token": {"catalog": [`+
+					`{"type": "compute", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "http://%[1]s/compute"}]},`+
+					`{"type": "image", "endpoints": [`+
+					`{"interface": "internal", "region_id": "RegionOne", "url": "http://%[1]s/internal"},`+
+					`{"interface": "public", "region_id": "RegionOne", "url": "http://%[1]s/image"},`+
+					`{"interface": "public", "region_id": "RegionTwo", "url": "http://%[1]s/image-two/"}]}]}}`, r.Host)
+			case "/image/v2/images/" + imageID, "/image-two/v2/images/" + imageID:
+				Expect(r.Header.Get("X-Auth-Token")).To(Equal("keystone-token"))
+				json.NewEncoder(w).Encode(image)
+			case "/image/v2/images/" + imageID + "/file", "/image-two/v2/images/" + imageID + "/file":
+				Expect(r.Header.Get("X-Auth-Token")).To(Equal("keystone-token"))
+				w.Write(imageData)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should download a qcow2 image to scratch space and verify its hash", func() {
+		gs, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gs.Close()
+		auth := authBody["auth"].(map[string]interface{})
+		Expect(auth["scope"]).To(Equal(map[string]interface{}{
+			"project": map[string]interface{}{"name": "admin", "domain": map[string]interface{}{"name": "Default"}},
+		}))
+		phase, err := gs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = gs.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(gs.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(imageData))
+	})
+
+	It("should write a raw image directly to the target and verify its md5 checksum", func() {
+		imageData, _ = ioutil.ReadFile(tinyCoreFilePath)
+		sum := md5.Sum(imageData)
+		image["disk_format"] = "iso"
+		image["size"] = len(imageData)
+		image["os_hash_algo"] = nil
+		image["os_hash_value"] = nil
+		image["checksum"] = hex.EncodeToString(sum[:])
+		gs, err := NewGlanceDataSource(ts.URL+"/identity/v3/", credentials, "RegionTwo", imageID, "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gs.Close()
+		phase, err := gs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = gs.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(imageData))
+	})
+
+	It("should fail if the hash does not match", func() {
+		image["os_hash_value"] = "00"
+		gs, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gs.Close()
+		_, err = gs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = gs.Transfer(tmpDir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("checksum mismatch"))
+	})
+
+	It("should fail if the data does not match the disk format", func() {
+		image["disk_format"] = "raw"
+		gs, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).ToNot(HaveOccurred())
+		defer gs.Close()
+		_, err = gs.Info()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match its disk format raw"))
+	})
+
+	It("should reject unsupported disk and container formats", func() {
+		image["disk_format"] = "vmdk"
+		_, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("disk format vmdk"))
+		image["disk_format"] = "qcow2"
+		image["container_format"] = "ova"
+		_, err = NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("container format ova"))
+	})
+
+	It("should fail if the image is not active", func() {
+		image["status"] = "queued"
+		_, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "", imageID, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("queued"))
+	})
+
+	It("should fail if there is no image endpoint in the region", func() {
+		_, err := NewGlanceDataSource(ts.URL+"/identity", credentials, "RegionThree", imageID, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no public image endpoint in region RegionThree"))
+	})
+})
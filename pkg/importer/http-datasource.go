@@ -25,30 +25,43 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
 	tempFile = "tmpimage"
+	// interval at which nbdkit reloads the headers when the bearer token is refreshed.
+	headerRenewInterval = 60 * time.Second
+	// timeout of the requests notifying the webhooks of the DataImportCrons
+	notificationTimeout = 10 * time.Second
 )
 
+// TLS settings of the CDIConfig, applied to the http clients.
+var tlsOptions *tlsconfig.Options
+
 // HTTPDataSource is the data provider for http(s) endpoints.
 // Sequence of phases:
 // 1a. Info -> Convert (In Info phase the format readers are configured), if the source Reader image is not archived, and no custom CA is used, and can be converted by QEMU-IMG (RAW/QCOW2)
 // 1b. Info -> TransferArchive if the content type is archive
-// 1c. Info -> Transfer in all other cases.
+// 1c. Info -> Transfer in all other cases, or if a segmented download is configured and the server supports ranges, or TLS settings are configured for an https endpoint, or the url is signed.
 // 2a. Transfer -> Convert if content type is kube virt
 // 2b. Transfer -> Complete if content type is archive (Transfer is called with the target instead of the scratch space). Non block PVCs only.
 type HTTPDataSource struct {
@@ -66,22 +79,34 @@ type HTTPDataSource struct {
 	url *url.URL
 	// path to the custom CA. Empty if not used
 	customCA string
+	// path to the client certificate and key used to authenticate to the endpoint. Empty if not used
+	clientCertDir string
+	// bearer token used to authenticate to the endpoint. Empty if not used
+	token string
+	// source of refreshed bearer tokens, nil if the token does not expire
+	tokenSource oauth2.TokenSource
+	// file containing the headers nbdkit sends, rewritten when the token is refreshed
+	headerFile string
 	// true if we know `qemu-img` will fail to download this
 	brokenForQemuImg bool
 	// the content length reported by the http server.
 	contentLength uint64
+	// number of concurrent ranged requests used to download to scratch space, 0 or 1 disables segmented download.
+	segments int
+	// size of each ranged request, if 0 the content is split evenly between the segments.
+	segmentSize int64
 
 	n *image.Nbdkit
 }
 
 // NewHTTPDataSource creates a new instance of the http data provider.
-func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType) (*HTTPDataSource, error) {
+func NewHTTPDataSource(endpoint, accessKey, secKey, token, certDir, clientCertDir string, contentType cdiv1.DataVolumeContentType) (*HTTPDataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
 	}
 	ctx, cancel := context.WithCancel(context.Background())
-	httpReader, contentLength, brokenForQemuImg, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir)
+	httpReader, contentLength, brokenForQemuImg, err := createHTTPReader(ctx, ep, accessKey, secKey, token, certDir, clientCertDir)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -97,6 +122,8 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 		contentType:      contentType,
 		endpoint:         ep,
 		customCA:         certDir,
+		clientCertDir:    clientCertDir,
+		token:            token,
 		brokenForQemuImg: brokenForQemuImg,
 		contentLength:    contentLength,
 	}
@@ -106,6 +133,31 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 	return httpSource, nil
 }
 
+// SetSegmentedDownload configures the data source to download the data into scratch space using the passed in number
+// of concurrent ranged requests before conversion. If segmentSize is 0, the content is split evenly over the segments.
+func (hs *HTTPDataSource) SetSegmentedDownload(segments int, segmentSize int64) {
+	hs.segments = segments
+	hs.segmentSize = segmentSize
+}
+
+// SetTokenSource configures the data source to refresh the bearer token using the passed in token source during the
+// transfer. The token passed to NewHTTPDataSource is used for the initial request.
+func (hs *HTTPDataSource) SetTokenSource(tokenSource oauth2.TokenSource) {
+	hs.tokenSource = tokenSource
+}
+
+// currentToken returns the bearer token to use for a new request.
+func (hs *HTTPDataSource) currentToken() string {
+	if hs.tokenSource != nil {
+		token, err := hs.tokenSource.Token()
+		if err == nil {
+			return token.AccessToken
+		}
+		klog.Warningf("Unable to refresh bearer token, using previous token: %v", err)
+	}
+	return hs.token
+}
+
 // Info is called to get initial information about the data.
 func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 	var err error
@@ -117,27 +169,52 @@ func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
-	if hs.brokenForQemuImg {
+	if hs.readers.ArchiveTar {
+		if err := hs.readers.ExtractTarEntry(); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	if hs.useSegmentedDownload() {
+		return ProcessingPhaseTransferScratch, nil
+	}
+	// nbdkit and qemu-img do not honor the configured TLS settings, nbdkit has no zstd filter, and the signature of a
+	// signed url would show in their arguments, download through the http client instead.
+	if hs.brokenForQemuImg || hs.readers.ArchiveZstd || (tlsOptions != nil && hs.endpoint.Scheme == "https") || isSignedURL(hs.endpoint) {
+		if !hs.readers.Convert {
+			// Raw images are extracted and decompressed while they are written to the target, without scratch space
+			return ProcessingPhaseTransferDataFile, nil
+		}
 		return ProcessingPhaseTransferScratch, nil
 	}
 	hs.url = hs.endpoint
-	if !hs.readers.Archived && hs.customCA == "" && hs.readers.Convert {
+	if !hs.readers.Archived && hs.customCA == "" && hs.clientCertDir == "" && hs.token == "" && hs.readers.Convert {
 		// We can pass straight to conversion from the endpoint
 		return ProcessingPhaseConvert, nil
 	}
 	hs.n = image.NewNbdkitCurl("/var/run/nbdkit.pid", hs.customCA)
-	if hs.readers.ArchiveGz {
-		hs.n.AddFilter(image.NbdkitGzipFilter)
-		klog.V(2).Infof("Added nbdkit gzip filter")
+	if hs.clientCertDir != "" {
+		hs.n.AddClientCertificate(hs.clientCertDir)
 	}
-	if hs.readers.ArchiveXz {
-		hs.n.AddFilter(image.NbdkitXzFilter)
-		klog.V(2).Infof("Added nbdkit xz filter")
+	if hs.tokenSource != nil || hs.token != "" {
+		// The token is read from a file so it does not show in the arguments of nbdkit
+		if err := hs.startHeaderRefresh(); err != nil {
+			return ProcessingPhaseError, err
+		}
+		hs.n.AddHeaderScript(fmt.Sprintf("cat %s", hs.headerFile), headerRenewInterval)
 	}
+	addNbdkitFilters(hs.n, hs.readers)
 	qemuOperations = image.NewNbdkitOperations(hs.GetNbdkit())
 	return ProcessingPhaseConvert, nil
 }
 
+// ProbeVirtualSize returns the virtual size of the disk image streamed from the endpoint.
+func (hs *HTTPDataSource) ProbeVirtualSize() (int64, error) {
+	if hs.readers == nil {
+		return 0, errors.New("Info must be called before ProbeVirtualSize")
+	}
+	return hs.readers.ProbeVirtualSize(int64(hs.contentLength))
+}
+
 // Transfer is called to transfer the data from the source to a scratch location.
 func (hs *HTTPDataSource) Transfer(path string) (ProcessingPhase, error) {
 	if hs.contentType == cdiv1.DataVolumeKubeVirt {
@@ -147,7 +224,11 @@ func (hs *HTTPDataSource) Transfer(path string) (ProcessingPhase, error) {
 			return ProcessingPhaseError, ErrInvalidPath
 		}
 		file := filepath.Join(path, tempFile)
-		err = util.StreamDataToFile(hs.readers.TopReader(), file)
+		if hs.useSegmentedDownload() {
+			err = hs.downloadSegments(file)
+		} else {
+			err = util.StreamDataToFile(hs.readers.TopReader(), file)
+		}
 		if err != nil {
 			return ProcessingPhaseError, err
 		}
@@ -196,18 +277,167 @@ func (hs *HTTPDataSource) Close() error {
 		hs.cancel = nil
 	}
 	hs.cancelLock.Unlock()
+	if hs.headerFile != "" {
+		os.Remove(hs.headerFile)
+	}
 	return err
 }
 
-func createHTTPClient(certDir string) (*http.Client, error) {
+// GetSourceInfo returns the compression of the source and its size as downloaded
+func (hs *HTTPDataSource) GetSourceInfo() (string, int64) {
+	return hs.readers.compression(), int64(hs.contentLength)
+}
+
+// startHeaderRefresh writes the authorization header to a file nbdkit reads, and keeps the file up to date until the
+// transfer completes if the token is refreshed.
+func (hs *HTTPDataSource) startHeaderRefresh() error {
+	f, err := ioutil.TempFile("", "nbdkit-headers")
+	if err != nil {
+		return errors.Wrap(err, "unable to create header file")
+	}
+	f.Close()
+	hs.headerFile = f.Name()
+	if err := hs.writeHeaderFile(); err != nil {
+		return err
+	}
+	if hs.tokenSource == nil {
+		return nil
+	}
+	go func() {
+		for {
+			select {
+			case <-time.After(headerRenewInterval / 2):
+				if err := hs.writeHeaderFile(); err != nil {
+					klog.Warningf("Unable to refresh headers: %v", err)
+				}
+			case <-hs.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (hs *HTTPDataSource) writeHeaderFile() error {
+	tmpFile := hs.headerFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, []byte(bearerTokenHeader(hs.currentToken())+"\n"), 0600); err != nil {
+		return errors.Wrap(err, "unable to write header file")
+	}
+	return os.Rename(tmpFile, hs.headerFile)
+}
+
+// useSegmentedDownload returns true if the data can be downloaded with concurrent ranged requests. This requires a
+// server that supports ranges and reports the content length, and the data should not need to be decompressed.
+func (hs *HTTPDataSource) useSegmentedDownload() bool {
+	return hs.segments > 1 && !hs.brokenForQemuImg && hs.contentLength > 0 &&
+		hs.contentType == cdiv1.DataVolumeKubeVirt && hs.readers != nil && !hs.readers.Archived
+}
+
+// downloadSegments downloads the content of the endpoint into the passed in file using concurrent ranged requests.
+func (hs *HTTPDataSource) downloadSegments(fileName string) error {
+	// The initial stream is no longer needed, the ranged requests retrieve all the data.
+	if err := hs.readers.Close(); err != nil {
+		klog.V(3).Infof("Unable to close initial reader: %v", err)
+	}
+	client, err := createHTTPClient(hs.customCA, hs.clientCertDir)
+	if err != nil {
+		return errors.Wrap(err, "Error creating http client")
+	}
+	// Keep the idle timeout of the initial reader going while the segments are being downloaded.
+	countingReader := hs.httpReader.(*util.CountingReader)
+	onRead := func(n int) {
+		atomic.AddUint64(&countingReader.Current, uint64(n))
+	}
+	fetch := func(r byteRange) (io.ReadCloser, error) {
+		return hs.fetchSegment(client, r)
+	}
+	return downloadSegments(fileName, hs.contentLength, hs.segments, hs.segmentSize, hs.sourceValidator(), fetch, onRead)
+}
+
+// sourceValidator returns the ETag, or else the last modification time, of the content of the endpoint. It identifies
+// the content of an interrupted download that can be resumed, empty if the server reports neither.
+func (hs *HTTPDataSource) sourceValidator() string {
+	accessKey, secKey := "", ""
+	endpoint := *hs.endpoint
+	if endpoint.User != nil {
+		accessKey = endpoint.User.Username()
+		secKey, _ = endpoint.User.Password()
+		endpoint.User = nil
+	}
+	_, validators, err := CheckHTTPSourceModified(endpoint.String(), accessKey, secKey, hs.currentToken(), hs.customCA, hs.clientCertDir, HTTPSourceValidators{})
+	if err != nil {
+		klog.V(1).Infof("Unable to get the validators of the source, the download will not resume if interrupted: %v", err)
+		return ""
+	}
+	if validators.ETag != "" {
+		return validators.ETag
+	}
+	return validators.LastModified
+}
+
+func (hs *HTTPDataSource) fetchSegment(client *http.Client, r byteRange) (io.ReadCloser, error) {
+	// http.NewRequest can only return error on invalid METHOD, or invalid url, neither can happen here.
+	req, _ := http.NewRequest("GET", hs.endpoint.String(), nil)
+	req = req.WithContext(hs.ctx)
+	if hs.endpoint.User != nil {
+		if secKey, ok := hs.endpoint.User.Password(); ok {
+			req.SetBasicAuth(hs.endpoint.User.Username(), secKey)
+		}
+	}
+	setBearerToken(req, hs.currentToken())
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.start, r.end))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, errors.Errorf("expected status code 206, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// SetTLSOptions sets the TLS settings used by the http clients of the importer
+func SetTLSOptions(options *tlsconfig.Options) {
+	tlsOptions = options
+}
+
+func createHTTPClient(certDir, clientCertDir string) (*http.Client, error) {
 	client := &http.Client{
 		// Don't set timeout here, since that will be an absolute timeout, we need a relative to last progress timeout.
 	}
 
-	if certDir == "" {
+	if certDir == "" && clientCertDir == "" && tlsOptions == nil {
 		return client, nil
 	}
 
+	tlsConfig := &tls.Config{}
+	if certDir != "" {
+		certPool, err := createCertPool(certDir)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if clientCertDir != "" {
+		klog.Infof("Loading client certificate from %s", clientCertDir)
+		clientCert, err := tls.LoadX509KeyPair(filepath.Join(clientCertDir, v1.TLSCertKey), filepath.Join(clientCertDir, v1.TLSPrivateKeyKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error loading client certificate from %s", clientCertDir)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	tlsOptions.Apply(tlsConfig)
+
+	client.Transport = &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+
+	return client, nil
+}
+
+// createCertPool returns the system certs together with the certs found in certDir.
+func createCertPool(certDir string) (*x509.CertPool, error) {
 	// let's get system certs as well
 	certPool, err := x509.SystemCertPool()
 	if err != nil {
@@ -238,18 +468,24 @@ func createHTTPClient(certDir string) (*http.Client, error) {
 		}
 	}
 
-	client.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs: certPool,
-		},
-	}
+	return certPool, nil
+}
 
-	return client, nil
+// bearerTokenHeader returns the authorization header for the passed in bearer token.
+func bearerTokenHeader(token string) string {
+	return "Authorization: Bearer " + token
+}
+
+// setBearerToken sets the authorization header of the request if a bearer token is used.
+func setBearerToken(r *http.Request, token string) {
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
 }
 
-func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string) (io.ReadCloser, uint64, bool, error) {
+func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, token, certDir, clientCertDir string) (io.ReadCloser, uint64, bool, error) {
 	var brokenForQemuImg bool
-	client, err := createHTTPClient(certDir)
+	client, err := createHTTPClient(certDir, clientCertDir)
 	if err != nil {
 		return nil, uint64(0), false, errors.Wrap(err, "Error creating http client")
 	}
@@ -258,10 +494,11 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 		if len(accessKey) > 0 && len(secKey) > 0 {
 			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
 		}
+		setBearerToken(r, token)
 		return nil
 	}
 
-	total, err := getContentLength(client, ep, accessKey, secKey)
+	total, err := getContentLength(client, ep, accessKey, secKey, token)
 	if err != nil {
 		brokenForQemuImg = true
 	}
@@ -272,10 +509,11 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 	if len(accessKey) > 0 && len(secKey) > 0 {
 		req.SetBasicAuth(accessKey, secKey)
 	}
-	klog.V(2).Infof("Attempting to get object %q via http client\n", ep.String())
+	setBearerToken(req, token)
+	klog.V(2).Infof("Attempting to get object %q via http client\n", redactURL(ep))
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, uint64(0), true, errors.Wrap(err, "HTTP request errored")
+		return nil, uint64(0), true, requestError(err)
 	}
 	if resp.StatusCode != 200 {
 		klog.Errorf("http: expected status code 200, got %d", resp.StatusCode)
@@ -326,7 +564,7 @@ func (hs *HTTPDataSource) pollProgress(reader *util.CountingReader, idleTime, po
 	}
 }
 
-func getContentLength(client *http.Client, ep *url.URL, accessKey, secKey string) (uint64, error) {
+func getContentLength(client *http.Client, ep *url.URL, accessKey, secKey, token string) (uint64, error) {
 	req, err := http.NewRequest("HEAD", ep.String(), nil)
 	if err != nil {
 		return uint64(0), errors.Wrap(err, "could not create HTTP request")
@@ -334,11 +572,12 @@ func getContentLength(client *http.Client, ep *url.URL, accessKey, secKey string
 	if len(accessKey) > 0 && len(secKey) > 0 {
 		req.SetBasicAuth(accessKey, secKey)
 	}
+	setBearerToken(req, token)
 
-	klog.V(2).Infof("Attempting to HEAD %q via http client\n", ep.String())
+	klog.V(2).Infof("Attempting to HEAD %q via http client\n", redactURL(ep))
 	resp, err := client.Do(req)
 	if err != nil {
-		return uint64(0), errors.Wrap(err, "HTTP request errored")
+		return uint64(0), requestError(err)
 	}
 
 	if resp.StatusCode != 200 {
@@ -359,6 +598,131 @@ func getContentLength(client *http.Client, ep *url.URL, accessKey, secKey string
 	return total, nil
 }
 
+// HTTPSourceValidators contains the validators reported by a http server for the content of an endpoint.
+type HTTPSourceValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// CheckHTTPSourceModified issues a conditional HEAD request to the endpoint using the validators of a previous import.
+// It returns false if the server reports that the content did not change, together with the current validators.
+func CheckHTTPSourceModified(endpoint, accessKey, secKey, token, certDir, clientCertDir string, previous HTTPSourceValidators) (bool, HTTPSourceValidators, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return true, HTTPSourceValidators{}, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
+	}
+	client, err := createHTTPClient(certDir, clientCertDir)
+	if err != nil {
+		return true, HTTPSourceValidators{}, errors.Wrap(err, "Error creating http client")
+	}
+	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
+		}
+		setBearerToken(r, token)
+		return nil
+	}
+	req, err := http.NewRequest("HEAD", ep.String(), nil)
+	if err != nil {
+		return true, HTTPSourceValidators{}, errors.Wrap(err, "could not create HTTP request")
+	}
+	if len(accessKey) > 0 && len(secKey) > 0 {
+		req.SetBasicAuth(accessKey, secKey)
+	}
+	setBearerToken(req, token)
+	if previous.ETag != "" {
+		req.Header.Set("If-None-Match", previous.ETag)
+	}
+	if previous.LastModified != "" {
+		req.Header.Set("If-Modified-Since", previous.LastModified)
+	}
+	klog.V(2).Infof("Attempting conditional HEAD %q via http client\n", redactURL(ep))
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, HTTPSourceValidators{}, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	current := HTTPSourceValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		// A 304 response is not required to repeat all the validators.
+		if current.ETag == "" {
+			current.ETag = previous.ETag
+		}
+		if current.LastModified == "" {
+			current.LastModified = previous.LastModified
+		}
+		// Only trust a 304 if the request was actually conditional.
+		return previous.ETag == "" && previous.LastModified == "", current, nil
+	case http.StatusOK:
+		return true, current, nil
+	}
+	return true, HTTPSourceValidators{}, errors.Errorf("expected status code 200 or 304, got %d. Status: %s", resp.StatusCode, resp.Status)
+}
+
+// PostNotification posts the JSON body to the webhook of a DataImportCron, verifying its certificate with the CA bundle
+// of certDir when it is set.
+func PostNotification(endpoint, body, certDir string) error {
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return errors.Wrap(err, "Error creating http client")
+	}
+	client.Timeout = notificationTimeout
+	resp, err := client.Post(endpoint, "application/json", strings.NewReader(body))
+	if err != nil {
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("the webhook replied %s", resp.Status)
+	}
+	return nil
+}
+
+// signedURLParams are the query parameters carrying the signature of the signed urls of Azure, S3 and GCS.
+var signedURLParams = []string{"sig", "signature", "x-amz-signature", "x-goog-signature"}
+
+// isSignedURL returns true if the query of the url contains a signature granting access to the object.
+func isSignedURL(u *url.URL) bool {
+	for key := range u.Query() {
+		for _, param := range signedURLParams {
+			if strings.EqualFold(key, param) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactURL returns the url without its query and password, so it can be logged.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		if _, ok := redacted.User.Password(); ok {
+			redacted.User = url.UserPassword(redacted.User.Username(), "redacted")
+		}
+	}
+	if redacted.RawQuery != "" {
+		redacted.RawQuery = "redacted"
+	}
+	return redacted.String()
+}
+
+// requestError wraps the error of a request, the url in the error is redacted since the error is reported in the
+// termination message of the importer.
+func requestError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = redactURL(u)
+		}
+	}
+	return errors.Wrap(err, "HTTP request errored")
+}
+
 func parseHTTPHeader(resp *http.Response) uint64 {
 	var err error
 	total := uint64(0)
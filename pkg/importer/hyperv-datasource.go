@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// HyperVDataSource is the data provider for the VHDX and VHD disks of Hyper-V virtual machines stored on SMB shares.
+// The disk is streamed from the share to scratch space with smbclient and converted to raw by qemu-img.
+// Sequence of phases:
+// 1. Info -> TransferScratch
+// 2. Transfer -> Convert
+type HyperVDataSource struct {
+	location *smbLocation
+	// reader of the file on the share
+	smbReader *smbReader
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, a file in scratch space.
+	url *url.URL
+}
+
+// NewHyperVDataSource creates a new instance of the Hyper-V data provider, the endpoint is an smb://server/share/path URL.
+func NewHyperVDataSource(endpoint, user, password, domain string) (*HyperVDataSource, error) {
+	location, err := parseSMBURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(location.path)) {
+	case ".vhdx", ".vhd":
+	case ".avhdx", ".avhd":
+		return nil, errors.Errorf("%s is a differencing disk of a checkpoint, merge the checkpoints of the VM before importing its disk", location)
+	default:
+		return nil, errors.Errorf("%s is not a VHDX or VHD disk", location)
+	}
+	reader, err := newSMBReader(location, user, password, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &HyperVDataSource{
+		location:  location,
+		smbReader: reader,
+	}, nil
+}
+
+// Info is called to get initial information about the data. The disk always needs conversion, qemu-img detects
+// whether it is a VHDX or VHD disk.
+func (hs *HyperVDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	hs.readers, err = NewFormatReaders(hs.smbReader, uint64(0))
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (hs *HyperVDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(hs.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	hs.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is not supported, VHDX and VHD disks have to be converted.
+func (hs *HyperVDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.Errorf("%s has to be converted through scratch space", hs.location)
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (hs *HyperVDataSource) GetURL() *url.URL {
+	return hs.url
+}
+
+// Close all readers.
+func (hs *HyperVDataSource) Close() error {
+	var err error
+	if hs.readers != nil {
+		err = hs.readers.Close()
+	}
+	if closeErr := hs.smbReader.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
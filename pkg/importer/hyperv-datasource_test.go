@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hyper-V data source", func() {
+	var (
+		tmpDir        string
+		scratchDir    string
+		origSmbclient string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "hyperv")
+		Expect(err).ToNot(HaveOccurred())
+		scratchDir = filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "disk"), cirrosData, 0644)).To(Succeed())
+		// The fake smbclient records its arguments and authentication file, and writes the disk to stdout
+		script := fmt.Sprintf(`#!/bin/sh
+printf "%%s\n" "$*" > %[1]s/args
+for arg; do
+	case "$arg" in
+	--authentication-file=*) cp "${arg#--authentication-file=}" %[1]s/auth;;
+	esac
+done
+if [ -f %[1]s/fail ]; then
+	echo "NT_STATUS_OBJECT_NAME_NOT_FOUND opening remote file" >&2
+	exit 1
+fi
+cat %[1]s/disk
+`, tmpDir)
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "smbclient"), []byte(script), 0755)).To(Succeed())
+		origSmbclient = smbclientPath
+		smbclientPath = filepath.Join(tmpDir, "smbclient")
+	})
+
+	AfterEach(func() {
+		smbclientPath = origSmbclient
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should stream the disk to scratch space for conversion", func() {
+		hs, err := NewHyperVDataSource("smb://fileserver/vms/fedora/Virtual Hard Disks/fedora.vhdx", "admin", "secret", "CORP")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := hs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = hs.Transfer(scratchDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(hs.GetURL().String()).To(Equal(filepath.Join(scratchDir, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(scratchDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(HavePrefix("//fileserver/vms --authentication-file="))
+		Expect(string(args)).To(HaveSuffix(" -E -c get \"fedora\\Virtual Hard Disks\\fedora.vhdx\" /dev/stdout\n"))
+		Expect(string(args)).ToNot(ContainSubstring("secret"))
+		auth, err := ioutil.ReadFile(filepath.Join(tmpDir, "auth"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(auth)).To(Equal("username = admin\npassword = secret\ndomain = CORP\n"))
+
+		authFile := hs.smbReader.authFile
+		Expect(hs.Close()).To(Succeed())
+		_, err = os.Stat(authFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should use a guest session without credentials", func() {
+		hs, err := NewHyperVDataSource("smb://fileserver/vms/fedora.vhd", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer hs.Close()
+		_, err = hs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = hs.Transfer(scratchDir)
+		Expect(err).ToNot(HaveOccurred())
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(HavePrefix("//fileserver/vms --no-pass -E"))
+	})
+
+	It("should fail the transfer with the error of smbclient", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "fail"), nil, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "disk"), nil, 0644)).To(Succeed())
+		hs, err := NewHyperVDataSource("smb://fileserver/vms/missing.vhdx", "admin", "secret", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer hs.Close()
+		_, err = hs.Info()
+		if err == nil {
+			_, err = hs.Transfer(scratchDir)
+		}
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("NT_STATUS_OBJECT_NAME_NOT_FOUND"))
+	})
+
+	It("should not support transferring to the target directly", func() {
+		hs, err := NewHyperVDataSource("smb://fileserver/vms/fedora.vhdx", "admin", "secret", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer hs.Close()
+		_, err = hs.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	table.DescribeTable("should reject", func(endpoint, message string) {
+		_, err := NewHyperVDataSource(endpoint, "admin", "secret", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+	},
+		table.Entry("differencing disks", "smb://fileserver/vms/fedora_1234.avhdx", "differencing disk"),
+		table.Entry("other files", "smb://fileserver/vms/fedora.vmdk", "is not a VHDX or VHD disk"),
+		table.Entry("URLs without a file", "smb://fileserver/vms", "has no share and file path"),
+		table.Entry("other URLs", "http://fileserver/vms/fedora.vhdx", "is not an smb:// URL"),
+		table.Entry("quotes in the path", "smb://fileserver/vms/%22fedora.vhdx", "invalid file path"),
+	)
+})
@@ -19,10 +19,13 @@ package importer
 import (
 	"context"
 	"crypto/x509"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -34,6 +37,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 // ImageioDataSource is the data provider for ovirt-imageio.
@@ -52,12 +56,34 @@ type ImageioDataSource struct {
 	imageTransfer *ovirtsdk4.ImageTransfer
 	// connection is connection to the oVirt system
 	connection ConnectionInterface
+	// currentCheckpoint is the oVirt VM backup the disk is read from in multi-stage imports
+	currentCheckpoint string
+	// previousCheckpoint is set for the delta copies of multi-stage imports, only the dirty extents are transferred
+	previousCheckpoint string
+	client             *http.Client
+	transferURL        string
+	extents            []imageioExtent
 }
 
-// NewImageioDataSource creates a new instance of the ovirt-imageio data provider.
-func NewImageioDataSource(endpoint string, accessKey string, secKey string, certDir string, diskID string) (*ImageioDataSource, error) {
+// imageioExtent is an extent of the disk as reported by the imageio extents API.
+type imageioExtent struct {
+	Start  int64 `json:"start"`
+	Length int64 `json:"length"`
+	Dirty  bool  `json:"dirty"`
+}
+
+// NewImageioDataSource creates a new instance of the ovirt-imageio data provider. With a current checkpoint the disk is
+// read from the oVirt VM backup of that ID, and with a previous checkpoint only the extents that changed since the
+// previous checkpoint are transferred, as reported by the changed block tracking of the backup.
+func NewImageioDataSource(endpoint string, accessKey string, secKey string, certDir string, diskID string, currentCheckpoint string, previousCheckpoint string) (*ImageioDataSource, error) {
+	if currentCheckpoint == "" && previousCheckpoint != "" {
+		return nil, errors.New("previous checkpoint set without a current checkpoint")
+	}
 	ctx, cancel := context.WithCancel(context.Background())
-	imageioReader, contentLength, it, conn, err := createImageioReader(ctx, endpoint, accessKey, secKey, certDir, diskID)
+	if previousCheckpoint != "" {
+		return createImageioDeltaSource(ctx, cancel, endpoint, accessKey, secKey, certDir, diskID, currentCheckpoint, previousCheckpoint)
+	}
+	imageioReader, contentLength, it, conn, err := createImageioReader(ctx, endpoint, accessKey, secKey, certDir, diskID, currentCheckpoint)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -69,6 +95,8 @@ func NewImageioDataSource(endpoint string, accessKey string, secKey string, cert
 		contentLength: contentLength,
 		imageTransfer: it,
 		connection:    conn,
+		// The base copy of a multi-stage import
+		currentCheckpoint: currentCheckpoint,
 	}
 	// We know this is a counting reader, so no need to check.
 	countingReader := imageioReader.(*util.CountingReader)
@@ -78,6 +106,10 @@ func NewImageioDataSource(endpoint string, accessKey string, secKey string, cert
 
 // Info is called to get initial information about the data.
 func (is *ImageioDataSource) Info() (ProcessingPhase, error) {
+	if is.IsDeltaCopy() {
+		// The changes are applied to the raw disk of the previous checkpoints
+		return ProcessingPhaseTransferDataFile, nil
+	}
 	var err error
 	is.readers, err = NewFormatReaders(is.imageioReader, is.contentLength)
 	if err != nil {
@@ -93,6 +125,9 @@ func (is *ImageioDataSource) Info() (ProcessingPhase, error) {
 
 // Transfer is called to transfer the data from the source to a scratch location.
 func (is *ImageioDataSource) Transfer(path string) (ProcessingPhase, error) {
+	if is.IsDeltaCopy() {
+		return ProcessingPhaseTransferDataFile, nil
+	}
 	// we know that there won't be archives
 	size, _ := util.GetAvailableSpace(path)
 	if size <= int64(0) {
@@ -111,6 +146,9 @@ func (is *ImageioDataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (is *ImageioDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if is.IsDeltaCopy() {
+		return is.transferDirtyExtents(fileName)
+	}
 	is.readers.StartProgressUpdate()
 	err := util.StreamDataToFile(is.readers.TopReader(), fileName)
 	if err != nil {
@@ -119,6 +157,21 @@ func (is *ImageioDataSource) TransferFile(fileName string) (ProcessingPhase, err
 	return ProcessingPhaseResize, nil
 }
 
+// IsDeltaCopy is called to determine if this is a full copy or one delta copy stage of a multi-stage import.
+func (is *ImageioDataSource) IsDeltaCopy() bool {
+	return is.previousCheckpoint != ""
+}
+
+// GetCheckpoint returns the oVirt VM backup copied by this stage of a multi-stage import.
+func (is *ImageioDataSource) GetCheckpoint() string {
+	return is.currentCheckpoint
+}
+
+// GetChangeID returns the checkpoint the dirty extents are reported from, empty for the full copy.
+func (is *ImageioDataSource) GetChangeID() string {
+	return is.previousCheckpoint
+}
+
 // GetURL returns the URI that the data processor can use when converting the data.
 func (is *ImageioDataSource) GetURL() *url.URL {
 	return is.url
@@ -175,19 +228,19 @@ func (is *ImageioDataSource) pollProgress(reader *util.CountingReader, idleTime,
 	}
 }
 
-func createImageioReader(ctx context.Context, ep string, accessKey string, secKey string, certDir string, diskID string) (io.ReadCloser, uint64, *ovirtsdk4.ImageTransfer, ConnectionInterface, error) {
+func createImageioReader(ctx context.Context, ep string, accessKey string, secKey string, certDir string, diskID string, backupID string) (io.ReadCloser, uint64, *ovirtsdk4.ImageTransfer, ConnectionInterface, error) {
 	conn, err := newOvirtClientFunc(ep, accessKey, secKey)
 	if err != nil {
 		return nil, uint64(0), nil, conn, errors.Wrap(err, "Error creating connection")
 	}
 
-	it, total, err := getTransfer(conn, diskID)
+	it, total, err := getTransfer(conn, diskID, backupID)
 	if err != nil {
 		return nil, uint64(0), it, conn, err
 	}
 
 	// Use the create client from http source.
-	client, err := createHTTPClient(certDir)
+	client, err := createHTTPClient(certDir, "")
 	if err != nil {
 		return nil, uint64(0), it, conn, err
 	}
@@ -218,7 +271,125 @@ func createImageioReader(ctx context.Context, ep string, accessKey string, secKe
 	return countingReader, total, it, conn, nil
 }
 
-func getTransfer(conn ConnectionInterface, diskID string) (*ovirtsdk4.ImageTransfer, uint64, error) {
+// createImageioDeltaSource starts the transfer of the disk from the backup of the current checkpoint, and lists the
+// extents that changed since the previous checkpoint.
+func createImageioDeltaSource(ctx context.Context, cancel context.CancelFunc, ep string, accessKey string, secKey string, certDir string, diskID string, backupID string, previousCheckpoint string) (*ImageioDataSource, error) {
+	is := &ImageioDataSource{
+		ctx:                ctx,
+		cancel:             cancel,
+		currentCheckpoint:  backupID,
+		previousCheckpoint: previousCheckpoint,
+	}
+	conn, err := newOvirtClientFunc(ep, accessKey, secKey)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "Error creating connection")
+	}
+	is.connection = conn
+	is.imageTransfer, is.contentLength, err = getTransfer(conn, diskID, backupID)
+	if err == nil {
+		err = is.listDirtyExtents(certDir)
+	}
+	if err != nil {
+		is.Close()
+		return nil, err
+	}
+	klog.V(1).Infof("Importing %d extents of disk %s changed between checkpoint %s and backup %s", len(is.extents), diskID, previousCheckpoint, backupID)
+	return is, nil
+}
+
+// listDirtyExtents lists the extents of the disk that changed since the previous checkpoint.
+func (is *ImageioDataSource) listDirtyExtents(certDir string) error {
+	var err error
+	if is.client, err = createHTTPClient(certDir, ""); err != nil {
+		return err
+	}
+	transferURL, available := is.imageTransfer.TransferUrl()
+	if !available {
+		return errors.New("Error transfer url not available")
+	}
+	is.transferURL = transferURL
+	req, err := http.NewRequest("GET", strings.TrimSuffix(transferURL, "/")+"/extents?context=dirty", nil)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	resp, err := is.client.Do(req.WithContext(is.ctx))
+	if err != nil {
+		return errors.Wrap(err, "Sending request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unable to list the dirty extents, bad status: %s", resp.Status)
+	}
+	var extents []imageioExtent
+	if err := json.NewDecoder(resp.Body).Decode(&extents); err != nil {
+		return errors.Wrap(err, "unable to parse the dirty extents")
+	}
+	for _, extent := range extents {
+		if extent.Dirty && extent.Length > 0 {
+			is.extents = append(is.extents, extent)
+		}
+	}
+	return nil
+}
+
+// transferDirtyExtents writes the dirty extents at their offset in the disk imported by the previous checkpoints.
+func (is *ImageioDataSource) transferDirtyExtents(fileName string) (ProcessingPhase, error) {
+	if len(is.extents) == 0 {
+		klog.Infof("No changes reported since checkpoint %s, marking transfer complete.", is.previousCheckpoint)
+		return ProcessingPhaseComplete, nil
+	}
+	outFile, err := os.OpenFile(fileName, os.O_WRONLY, 0)
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "cannot apply the changes since checkpoint %s", is.previousCheckpoint)
+	}
+	defer outFile.Close()
+	var total uint64
+	for _, extent := range is.extents {
+		total += uint64(extent.Length)
+	}
+	promReader := prometheusutil.NewProgressReader(nil, total, progress, ownerUID)
+	promReader.SetTransferMetrics(transferMetrics)
+	promReader.StartTimedUpdate()
+	defer func() {
+		promReader.Done = true
+	}()
+	for _, extent := range is.extents {
+		if err := is.transferExtent(outFile, extent); err != nil {
+			return ProcessingPhaseError, err
+		}
+		promReader.Current += uint64(extent.Length)
+	}
+	if err := outFile.Sync(); err != nil {
+		return ProcessingPhaseError, errors.Wrap(err, "unable to sync the target")
+	}
+	return ProcessingPhasePreallocate, nil
+}
+
+func (is *ImageioDataSource) transferExtent(outFile *os.File, extent imageioExtent) error {
+	req, err := http.NewRequest("GET", is.transferURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", extent.Start, extent.Start+extent.Length-1))
+	resp, err := is.client.Do(req.WithContext(is.ctx))
+	if err != nil {
+		return errors.Wrapf(err, "unable to read the extent at %d", extent.Start)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return errors.Errorf("unable to read the extent at %d, bad status: %s", extent.Start, resp.Status)
+	}
+	if _, err := outFile.Seek(extent.Start, io.SeekStart); err != nil {
+		return errors.Wrap(err, "unable to seek in the target")
+	}
+	if _, err := io.CopyN(outFile, resp.Body, extent.Length); err != nil {
+		return errors.Wrapf(err, "unable to write the extent at %d", extent.Start)
+	}
+	return nil
+}
+
+func getTransfer(conn ConnectionInterface, diskID string, backupID string) (*ovirtsdk4.ImageTransfer, uint64, error) {
 	disksService := conn.SystemService().DisksService()
 	diskService := disksService.DiskService(diskID)
 	diskRequest := diskService.Get()
@@ -241,20 +412,29 @@ func getTransfer(conn ConnectionInterface, diskID string) (*ovirtsdk4.ImageTrans
 		return nil, uint64(0), errors.New("Error disk id not available")
 	}
 
-	image, err := ovirtsdk4.NewImageBuilder().Id(id).Build()
-	if err != nil {
-		return nil, uint64(0), errors.Wrap(err, "Error building image object")
+	transferBuilder := ovirtsdk4.NewImageTransferBuilder().Direction(
+		ovirtsdk4.IMAGETRANSFERDIRECTION_DOWNLOAD,
+	).Format(
+		ovirtsdk4.DISKFORMAT_RAW,
+	)
+	if backupID != "" {
+		// Backups are transferred per disk, the backup selects the checkpoint to read
+		backup, err := ovirtsdk4.NewBackupBuilder().Id(backupID).Build()
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(err, "Error building backup object")
+		}
+		transferBuilder.Disk(disk).Backup(backup)
+	} else {
+		image, err := ovirtsdk4.NewImageBuilder().Id(id).Build()
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(err, "Error building image object")
+		}
+		transferBuilder.Image(image)
 	}
 
 	transfersService := conn.SystemService().ImageTransfersService()
 	transfer := transfersService.Add()
-	imageTransfer, err := ovirtsdk4.NewImageTransferBuilder().Image(
-		image,
-	).Direction(
-		ovirtsdk4.IMAGETRANSFERDIRECTION_DOWNLOAD,
-	).Format(
-		ovirtsdk4.DISKFORMAT_RAW,
-	).Build()
+	imageTransfer, err := transferBuilder.Build()
 	if err != nil {
 		return nil, uint64(0), errors.Wrap(err, "Error preparing transfer object")
 	}
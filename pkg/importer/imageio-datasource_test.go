@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/pem"
 	"io/ioutil"
+	"math/rand"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
@@ -25,6 +27,7 @@ var it = &ovirtsdk4.ImageTransfer{}
 var disk = &ovirtsdk4.Disk{}
 var diskAvailable = true
 var diskCreateError error
+var lastImageTransfer *ovirtsdk4.ImageTransfer
 
 var _ = Describe("Imageio reader", func() {
 	var (
@@ -54,13 +57,13 @@ var _ = Describe("Imageio reader", func() {
 
 	It("should fail creating client", func() {
 		newOvirtClientFunc = failMockOvirtClient
-		_, total, _, _, err := createImageioReader(context.Background(), "invalid/", "", "", "", "")
+		_, total, _, _, err := createImageioReader(context.Background(), "invalid/", "", "", "", "", "")
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 	})
 
 	It("should create reader", func() {
-		reader, total, _, _, err := createImageioReader(context.Background(), "", "", "", tempDir, "")
+		reader, total, _, _, err := createImageioReader(context.Background(), "", "", "", tempDir, "", "")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(1024)).To(Equal(total))
 		err = reader.Close()
@@ -97,26 +100,26 @@ var _ = Describe("Imageio data source", func() {
 
 	It("NewImageioDataSource should fail when called with an invalid endpoint", func() {
 		newOvirtClientFunc = getOvirtClient
-		_, err = NewImageioDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", "")
+		_, err = NewImageioDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewImageioDataSource info should not fail when called with valid endpoint", func() {
-		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		_, err = dp.Info()
 		Expect(err).ToNot(HaveOccurred())
 	})
 
 	It("NewImageioDataSource tranfer should fail if invalid path", func() {
-		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		_, err = dp.Transfer("")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewImageioDataSource tranferfile should fail when invalid path", func() {
-		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		_, err = dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -126,14 +129,14 @@ var _ = Describe("Imageio data source", func() {
 	})
 
 	It("NewImageioDataSource url should be nil if not set", func() {
-		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		url := dp.GetURL()
 		Expect(url).To(BeNil())
 	})
 
 	It("NewImageioDataSource close should succeed if valid url", func() {
-		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		err = dp.Close()
 		Expect(err).ToNot(HaveOccurred())
@@ -141,24 +144,125 @@ var _ = Describe("Imageio data source", func() {
 
 	It("NewImageioDataSource should fail if transfer in unknown state", func() {
 		it.SetPhase(ovirtsdk4.IMAGETRANSFERPHASE_UNKNOWN)
-		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewImageioDataSource should fail if disk creation fails", func() {
 		diskCreateError = errors.New("this is error message")
-		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewImageioDataSource should fail if disk does not exists", func() {
 		diskAvailable = false
-		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "")
+		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 })
 
+var _ = Describe("Imageio multi-stage import", func() {
+	var (
+		ts       *httptest.Server
+		tempDir  string
+		diskData []byte
+		extents  string
+	)
+
+	BeforeEach(func() {
+		newOvirtClientFunc = createMockOvirtClient
+		tempDir = createCert()
+		diskData = make([]byte, 3*4096)
+		rand.Read(diskData)
+		extents = `[{"start": 0, "length": 4096, "dirty": false}, {"start": 4096, "length": 4096, "dirty": true}, {"start": 8192, "length": 4096, "dirty": false}]`
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/disk/extents":
+				Expect(r.URL.Query().Get("context")).To(Equal("dirty"))
+				w.Write([]byte(extents))
+			case "/disk":
+				http.ServeContent(w, r, "disk", time.Time{}, bytes.NewReader(diskData))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		disk.SetTotalSize(int64(len(diskData)))
+		disk.SetId("123")
+		it.SetPhase(ovirtsdk4.IMAGETRANSFERPHASE_TRANSFERRING)
+		it.SetTransferUrl(ts.URL + "/disk")
+		diskAvailable = true
+		diskCreateError = nil
+		lastImageTransfer = nil
+	})
+
+	AfterEach(func() {
+		newOvirtClientFunc = getOvirtClient
+		os.RemoveAll(tempDir)
+		ts.Close()
+	})
+
+	It("should read the disk from the backup of the current checkpoint", func() {
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "123", "backup-1", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer dp.Close()
+		Expect(dp.IsDeltaCopy()).To(BeFalse())
+		Expect(dp.GetCheckpoint()).To(Equal("backup-1"))
+		Expect(dp.GetChangeID()).To(BeEmpty())
+		backup, ok := lastImageTransfer.Backup()
+		Expect(ok).To(BeTrue())
+		Expect(backup.MustId()).To(Equal("backup-1"))
+		_, ok = lastImageTransfer.Image()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should only write the dirty extents since the previous checkpoint", func() {
+		target := path.Join(tempDir, "disk.img")
+		Expect(ioutil.WriteFile(target, make([]byte, len(diskData)), 0644)).To(Succeed())
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "123", "backup-2", "checkpoint-1")
+		Expect(err).ToNot(HaveOccurred())
+		defer dp.Close()
+		Expect(dp.IsDeltaCopy()).To(BeTrue())
+		Expect(dp.GetCheckpoint()).To(Equal("backup-2"))
+		Expect(dp.GetChangeID()).To(Equal("checkpoint-1"))
+		Expect(lastImageTransfer.MustBackup().MustId()).To(Equal("backup-2"))
+		phase, err := dp.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		phase, err = dp.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhasePreallocate))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content[:4096]).To(Equal(make([]byte, 4096)))
+		Expect(content[4096:8192]).To(Equal(diskData[4096:8192]))
+		Expect(content[8192:]).To(Equal(make([]byte, 4096)))
+	})
+
+	It("should complete a delta copy without dirty extents", func() {
+		extents = `[{"start": 0, "length": 12288, "dirty": false}]`
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "123", "backup-2", "checkpoint-1")
+		Expect(err).ToNot(HaveOccurred())
+		defer dp.Close()
+		phase, err := dp.TransferFile(path.Join(tempDir, "missing.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseComplete))
+	})
+
+	It("should fail to apply changes without the disk of the previous checkpoint", func() {
+		dp, err := NewImageioDataSource(ts.URL, "", "", tempDir, "123", "backup-2", "checkpoint-1")
+		Expect(err).ToNot(HaveOccurred())
+		defer dp.Close()
+		_, err = dp.TransferFile(path.Join(tempDir, "missing.img"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a previous checkpoint without a current checkpoint", func() {
+		_, err := NewImageioDataSource(ts.URL, "", "", tempDir, "123", "", "checkpoint-1")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("Imageio client preparation", func() {
 	var tempDir string
 
@@ -284,6 +388,7 @@ func (conn *MockOvirtClient) Add() ImageTransferServiceAddInterface {
 	}
 }
 func (conn *MockAddService) ImageTransfer(imageTransfer *ovirtsdk4.ImageTransfer) *ovirtsdk4.ImageTransfersServiceAddRequest {
+	lastImageTransfer = imageTransfer
 	return &ovirtsdk4.ImageTransfersServiceAddRequest{}
 }
 
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+// ISCSIDataSource is the data provider for LUNs of iSCSI targets, for instance the disk of a physical machine exposed
+// for a P2V migration. qemu-img reads the LUN through the nbdkit iscsi plugin and converts it to raw, copying it if it
+// is raw already.
+// Sequence of phases:
+// 1. Info -> Convert
+type ISCSIDataSource struct {
+	// endpoint the iscsi://portal/target/lun URL of the LUN.
+	endpoint *url.URL
+	user     string
+	password string
+	// initiator name to log in with, generated by nbdkit if empty
+	initiator string
+	// file containing the CHAP password nbdkit logs in with
+	passwordFile string
+}
+
+// NewISCSIDataSource creates a new instance of the iSCSI data provider, CHAP authentication is used if user is set.
+func NewISCSIDataSource(endpoint, user, password, initiator string) (*ISCSIDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	target := strings.Split(strings.Trim(ep.Path, "/"), "/")
+	if ep.Scheme != "iscsi" || ep.Host == "" || len(target) != 2 || target[0] == "" {
+		return nil, errors.Errorf("%s is not an iscsi://portal/target/lun URL", endpoint)
+	}
+	return &ISCSIDataSource{
+		endpoint:  ep,
+		user:      user,
+		password:  password,
+		initiator: initiator,
+	}, nil
+}
+
+// Info is called to get initial information about the data, the LUN is read by nbdkit during the conversion.
+func (is *ISCSIDataSource) Info() (ProcessingPhase, error) {
+	n := image.NewNbdkitIscsi("/var/run/nbdkit.pid", is.initiator)
+	if is.user != "" {
+		var err error
+		if is.passwordFile, err = writeTempFile("chap-password", is.password); err != nil {
+			return ProcessingPhaseError, errors.Wrap(err, "unable to write the password file")
+		}
+		n.AddCredentials(is.user, is.passwordFile)
+	}
+	qemuOperations = image.NewNbdkitOperations(n)
+	return ProcessingPhaseConvert, nil
+}
+
+// Transfer is not used, the LUN is converted directly.
+func (is *ISCSIDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an iSCSI LUN to scratch space is not supported")
+}
+
+// TransferFile is not used, the LUN is converted directly.
+func (is *ISCSIDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an iSCSI LUN to a file is not supported")
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (is *ISCSIDataSource) GetURL() *url.URL {
+	return is.endpoint
+}
+
+// Close removes the password file.
+func (is *ISCSIDataSource) Close() error {
+	if is.passwordFile != "" {
+		os.Remove(is.passwordFile)
+	}
+	return nil
+}
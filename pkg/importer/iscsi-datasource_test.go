@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("iSCSI data source", func() {
+	var (
+		origQemuOps image.QEMUOperations
+		is          *ISCSIDataSource
+	)
+
+	BeforeEach(func() {
+		origQemuOps = qemuOperations
+		is = nil
+	})
+
+	AfterEach(func() {
+		if is != nil {
+			is.Close()
+		}
+		qemuOperations = origQemuOps
+	})
+
+	It("should convert the LUN through nbdkit with a CHAP password file", func() {
+		var err error
+		is, err = NewISCSIDataSource("iscsi://san.example.com:3260/iqn.2003-01.org.linux-iscsi.san:p2v/1", "chap", "secret", "iqn.2021-01.io.kubevirt:importer")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := is.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(qemuOperations).ToNot(Equal(origQemuOps))
+		Expect(is.GetURL().String()).To(Equal("iscsi://san.example.com:3260/iqn.2003-01.org.linux-iscsi.san:p2v/1"))
+		password, err := ioutil.ReadFile(is.passwordFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(password)).To(Equal("secret"))
+
+		passwordFile := is.passwordFile
+		Expect(is.Close()).To(Succeed())
+		_, err = os.Stat(passwordFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should not write a password file without CHAP user", func() {
+		var err error
+		is, err = NewISCSIDataSource("iscsi://san.example.com/iqn.2003-01.org.linux-iscsi.san:p2v/0", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := is.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(is.passwordFile).To(BeEmpty())
+	})
+
+	table.DescribeTable("should reject invalid endpoints", func(endpoint string) {
+		_, err := NewISCSIDataSource(endpoint, "", "", "")
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("without LUN", "iscsi://san.example.com/iqn.2003-01.org.linux-iscsi.san:p2v"),
+		table.Entry("without portal", "iscsi:///iqn.2003-01.org.linux-iscsi.san:p2v/0"),
+		table.Entry("with another scheme", "http://san.example.com/iqn.2003-01.org.linux-iscsi.san:p2v/0"),
+	)
+})
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+const (
+	// libvirtOverlaySuffix is appended to the path of the disk to name the overlay receiving the writes of a running
+	// domain during the import
+	libvirtOverlaySuffix = ".cdi-overlay"
+	// libvirtSnapshotName is the name of the disk-only snapshot creating the overlay, it is not kept as metadata
+	libvirtSnapshotName = "cdi-import"
+)
+
+var (
+	// virshPath is the virsh binary, a variable for testing.
+	virshPath = "virsh"
+)
+
+// LibvirtDataSource is the data provider for disks of domains of remote libvirt hosts, for instance standalone KVM
+// hosts. If the domain is not shut off, an external disk-only snapshot redirects its writes to an overlay, so the disk
+// does not change while it is read. qemu-img reads the disk through the nbdkit ssh plugin and converts it to raw, and
+// the overlay is committed back into the disk once done.
+// Sequence of phases:
+// 1. Info -> Convert
+type LibvirtDataSource struct {
+	// uri the connection URI of virsh, with the SSH key and host key parameters
+	uri    string
+	domain string
+	// disk the target device of the disk in the domain
+	disk string
+	// host the ssh://user@host[:port] URL of the host, the path of the disk is read from the domain
+	host *url.URL
+	// file containing the SSH private key
+	keyFile string
+	// file containing the host key of the server, empty if the host key is not verified
+	knownHostsFile string
+	// overlay created by the snapshot of a running domain, empty if the domain is shut off
+	overlay string
+	// url the ssh:// url of the disk that the data processor converts
+	url *url.URL
+}
+
+// NewLibvirtDataSource creates a new instance of the libvirt data provider. The endpoint is a qemu+ssh:// URI, user and
+// privateKey are the SSH credentials and hostKey is the public key of the host in known_hosts format.
+func NewLibvirtDataSource(endpoint, user, privateKey, domain, disk, hostKey string) (*LibvirtDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	if ep.Scheme != "qemu+ssh" || ep.Hostname() == "" {
+		return nil, errors.Errorf("%s is not a qemu+ssh:// URI", endpoint)
+	}
+	if user == "" || privateKey == "" {
+		return nil, errors.New("a user name and an SSH private key are required")
+	}
+	if domain == "" || disk == "" {
+		return nil, errors.New("a domain and a disk are required")
+	}
+	ls := &LibvirtDataSource{
+		domain: domain,
+		disk:   disk,
+		host: &url.URL{
+			Scheme: "ssh",
+			User:   url.User(user),
+			Host:   ep.Host,
+		},
+	}
+	// ssh refuses keys that do not end with a new line
+	if !strings.HasSuffix(privateKey, "\n") {
+		privateKey += "\n"
+	}
+	if ls.keyFile, err = writeTempFile("ssh-key", privateKey); err != nil {
+		ls.Close()
+		return nil, errors.Wrap(err, "unable to write the SSH private key")
+	}
+	// The libssh transport of libvirt takes the key and the known hosts as parameters, unlike the ssh one
+	params := url.Values{
+		"keyfile":   []string{ls.keyFile},
+		"sshauth":   []string{"privkey"},
+		"no_tty":    []string{"1"},
+		"no_verify": []string{"1"},
+	}
+	if hostKey != "" {
+		knownHost := ep.Hostname()
+		if ep.Port() != "" {
+			knownHost = "[" + knownHost + "]:" + ep.Port()
+		}
+		if ls.knownHostsFile, err = writeTempFile("known-hosts", knownHost+" "+strings.TrimSpace(hostKey)+"\n"); err != nil {
+			ls.Close()
+			return nil, errors.Wrap(err, "unable to write the SSH host key")
+		}
+		params.Del("no_verify")
+		params.Set("known_hosts", ls.knownHostsFile)
+		params.Set("known_hosts_verify", "normal")
+	} else {
+		klog.Warningf("No host key for %s, the identity of the host is not verified", ep.Host)
+	}
+	uri := url.URL{
+		Scheme:   "qemu+libssh",
+		User:     url.User(user),
+		Host:     ep.Host,
+		Path:     ep.Path,
+		RawQuery: params.Encode(),
+	}
+	ls.uri = uri.String()
+	return ls, nil
+}
+
+// Info is called to get initial information about the data. It snapshots the disk of a running domain, the disk is
+// read by nbdkit during the conversion.
+func (ls *LibvirtDataSource) Info() (ProcessingPhase, error) {
+	source, err := ls.diskSource()
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	if strings.HasSuffix(source, libvirtOverlaySuffix) {
+		// An interrupted import left its overlay, merge it back before taking a new snapshot
+		klog.Warningf("Committing the overlay %s left by a previous import", source)
+		if err := ls.commit(); err != nil {
+			return ProcessingPhaseError, err
+		}
+		if source, err = ls.diskSource(); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	state, err := ls.virsh("domstate", "--domain", ls.domain)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	if strings.TrimSpace(state) != "shut off" {
+		if err := ls.snapshot(source); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	n := image.NewNbdkitSSH("/var/run/nbdkit.pid", ls.keyFile, ls.knownHostsFile)
+	qemuOperations = image.NewNbdkitOperations(n)
+	u := *ls.host
+	u.Path = source
+	ls.url = &u
+	return ProcessingPhaseConvert, nil
+}
+
+// Transfer is not used, the disk is converted directly.
+func (ls *LibvirtDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring a libvirt disk to scratch space is not supported")
+}
+
+// TransferFile is not used, the disk is converted directly.
+func (ls *LibvirtDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring a libvirt disk to a file is not supported")
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (ls *LibvirtDataSource) GetURL() *url.URL {
+	return ls.url
+}
+
+// Close commits the overlay of a running domain back into its disk, and removes the SSH key and host key files.
+func (ls *LibvirtDataSource) Close() error {
+	var err error
+	if ls.overlay != "" {
+		if err = ls.commit(); err == nil {
+			ls.overlay = ""
+		}
+	}
+	if ls.keyFile != "" {
+		os.Remove(ls.keyFile)
+	}
+	if ls.knownHostsFile != "" {
+		os.Remove(ls.knownHostsFile)
+	}
+	return err
+}
+
+// diskSource returns the path of the file or block device of the disk on the host.
+func (ls *LibvirtDataSource) diskSource() (string, error) {
+	output, err := ls.virsh("domblklist", "--domain", ls.domain, "--details")
+	if err != nil {
+		return "", err
+	}
+	// Each disk is listed as: type device target source
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != ls.disk {
+			continue
+		}
+		source := strings.Join(fields[3:], " ")
+		if (fields[0] != "file" && fields[0] != "block") || source == "-" {
+			return "", errors.Errorf("disk %s of domain %s is not a file or a block device", ls.disk, ls.domain)
+		}
+		return source, nil
+	}
+	return "", errors.Errorf("domain %s has no disk %s", ls.domain, ls.disk)
+}
+
+// snapshot creates an external disk-only snapshot of the disk, the domain writes to the overlay from then on. The other
+// disks of the domain are left alone.
+func (ls *LibvirtDataSource) snapshot(source string) error {
+	output, err := ls.virsh("domblklist", "--domain", ls.domain)
+	if err != nil {
+		return err
+	}
+	overlay := source + libvirtOverlaySuffix
+	// Commas are doubled in the values of --diskspec
+	args := []string{"snapshot-create-as", "--domain", ls.domain, "--name", libvirtSnapshotName, "--disk-only", "--atomic", "--no-metadata",
+		"--diskspec", fmt.Sprintf("%s,snapshot=external,file=%s", ls.disk, strings.ReplaceAll(overlay, ",", ",,"))}
+	for _, line := range strings.Split(output, "\n") {
+		// Each disk is listed as: target source
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] == ls.disk || fields[0] == "Target" || strings.HasPrefix(fields[0], "-") {
+			continue
+		}
+		args = append(args, "--diskspec", fmt.Sprintf("%s,snapshot=no", fields[0]))
+	}
+	klog.V(1).Infof("Redirecting the writes of domain %s to %s", ls.domain, overlay)
+	if _, err := ls.virsh(args...); err != nil {
+		return err
+	}
+	ls.overlay = overlay
+	return nil
+}
+
+// commit merges the overlay back into the disk, switches the domain back to the disk and deletes the overlay.
+func (ls *LibvirtDataSource) commit() error {
+	klog.V(1).Infof("Committing the writes of domain %s back into disk %s", ls.domain, ls.disk)
+	_, err := ls.virsh("blockcommit", "--domain", ls.domain, "--path", ls.disk, "--active", "--pivot", "--delete")
+	return err
+}
+
+// virsh runs a virsh command on the host and returns its output.
+func (ls *LibvirtDataSource) virsh(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(virshPath, append([]string{"--connect", ls.uri}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Errorf("virsh %s failed: %v, %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
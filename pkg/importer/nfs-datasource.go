@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// NFSDataSource is the data provider for image files on an NFS export, host path or PVC, which the importer pod mounts
+// read-only.
+// Sequence of phases:
+// 1a. Info -> Convert if the file is a qcow2 image, qemu-img reads the file on the export directly
+// 1b. Info -> TransferScratch if the file is a compressed qcow2 image
+// 1c. Info -> TransferDataFile if the file is a raw image, compressed or not
+// 2. Transfer -> Convert
+type NFSDataSource struct {
+	// path of the image file on the mounted export
+	path string
+	// the open image file
+	file *os.File
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, the file on the export or a file in scratch space.
+	url *url.URL
+}
+
+// NewNFSDataSource creates a new instance of the NFS data provider for the file at the given path on the mounted export,
+// host path or PVC.
+func NewNFSDataSource(path string) (*NFSDataSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open %s", path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "unable to stat %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, errors.Errorf("%s is not a regular file", path)
+	}
+	return &NFSDataSource{
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Info is called to get initial information about the data.
+func (ns *NFSDataSource) Info() (ProcessingPhase, error) {
+	info, err := ns.file.Stat()
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "unable to stat %s", ns.path)
+	}
+	ns.readers, err = NewFormatReaders(ns.file, uint64(info.Size()))
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if !ns.readers.Convert {
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	if !ns.readers.Archived {
+		// The file is random accessible, qemu-img can convert it in place
+		ns.url, _ = url.Parse(ns.path)
+		return ProcessingPhaseConvert, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (ns *NFSDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(ns.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ns.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (ns *NFSDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	ns.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(ns.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (ns *NFSDataSource) GetURL() *url.URL {
+	return ns.url
+}
+
+// Close all readers, which closes the file.
+func (ns *NFSDataSource) Close() error {
+	if ns.readers != nil {
+		return ns.readers.Close()
+	}
+	return ns.file.Close()
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NFS data source", func() {
+	var (
+		tmpDir string
+		ns     *NFSDataSource
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "nfs")
+		Expect(err).ToNot(HaveOccurred())
+		ns = nil
+	})
+
+	AfterEach(func() {
+		if ns != nil {
+			ns.Close()
+		}
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should convert a qcow2 image directly from the export", func() {
+		var err error
+		ns, err = NewNFSDataSource(cirrosFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ns.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ns.GetURL().String()).To(Equal(cirrosFilePath))
+	})
+
+	It("should write a raw image to the target", func() {
+		var err error
+		ns, err = NewNFSDataSource(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ns.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = ns.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		expected, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(expected))
+	})
+
+	It("should decompress a compressed raw image to the target", func() {
+		var err error
+		ns, err = NewNFSDataSource(tinyCoreXzFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ns.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		_, err = ns.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		expected, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(expected))
+	})
+
+	It("should decompress a compressed qcow2 image to scratch space", func() {
+		compressed := filepath.Join(tmpDir, "cirros.qcow2.gz")
+		file, err := os.Create(compressed)
+		Expect(err).ToNot(HaveOccurred())
+		gz := gzip.NewWriter(file)
+		_, err = gz.Write(cirrosData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gz.Close()).To(Succeed())
+		Expect(file.Close()).To(Succeed())
+
+		ns, err = NewNFSDataSource(compressed)
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ns.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		scratch := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratch, 0755)).To(Succeed())
+		phase, err = ns.Transfer(scratch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ns.GetURL().String()).To(Equal(filepath.Join(scratch, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(scratch, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+	})
+
+	It("should fail if the file does not exist", func() {
+		_, err := NewNFSDataSource(filepath.Join(tmpDir, "missing.qcow2"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to open"))
+	})
+
+	It("should fail if the path is a directory", func() {
+		_, err := NewNFSDataSource(tmpDir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is not a regular file"))
+	})
+})
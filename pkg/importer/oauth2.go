@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maximum size of the token endpoint response we are willing to read.
+	maxTokenResponseSize = 1 << 20
+)
+
+// clientCredentialsTokenSource retrieves tokens from an OAuth2 token endpoint using the client credentials grant.
+type clientCredentialsTokenSource struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewClientCredentialsTokenSource creates a token source that retrieves bearer tokens from the token endpoint using the
+// OAuth2 client credentials flow. Tokens are cached and a new token is retrieved once the current one expires.
+func NewClientCredentialsTokenSource(tokenURL, clientID, clientSecret string, scopes []string, certDir string) (oauth2.TokenSource, error) {
+	if _, err := url.Parse(tokenURL); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse token url %q", tokenURL)
+	}
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	client.Timeout = time.Minute
+	return oauth2.ReuseTokenSource(nil, &clientCredentialsTokenSource{
+		client:       client,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+	}), nil
+}
+
+// Token retrieves a new token from the token endpoint.
+func (c *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	v := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+	if len(c.scopes) > 0 {
+		v.Set("scope", strings.Join(c.scopes, " "))
+	}
+	req, err := http.NewRequest("POST", c.tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	klog.V(2).Infof("Retrieving OAuth2 token from %q\n", c.tokenURL)
+	return requestToken(c.client, req)
+}
+
+// requestToken sends the token request and parses the OAuth2 token response.
+func requestToken(client *http.Client, req *http.Request) (*oauth2.Token, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "token request errored")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read token response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("expected status code 200 from token endpoint, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+	tr := &tokenResponse{}
+	if err := json.Unmarshal(body, tr); err != nil {
+		return nil, errors.Wrap(err, "unable to parse token response")
+	}
+	if tr.AccessToken == "" {
+		return nil, errors.New("token endpoint did not return an access token")
+	}
+	token := &oauth2.Token{
+		AccessToken: tr.AccessToken,
+		TokenType:   tr.TokenType,
+	}
+	if tr.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
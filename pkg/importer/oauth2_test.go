@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("OAuth2 client credentials token source", func() {
+	var (
+		ts       *httptest.Server
+		requests int
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			requests++
+			id, secret, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(r.FormValue("grant_type")).To(Equal("client_credentials"))
+			if id != "client" || secret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600, "scope": "%s"}`, requests, r.FormValue("scope"))
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	It("should retrieve a token and reuse it until it expires", func() {
+		tokenSource, err := NewClientCredentialsTokenSource(ts.URL, "client", "secret", []string{"read", "write"}, "")
+		Expect(err).ToNot(HaveOccurred())
+		token, err := tokenSource.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token-1"))
+		Expect(token.Valid()).To(BeTrue())
+		token, err = tokenSource.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token-1"))
+		Expect(requests).To(Equal(1))
+	})
+
+	It("should fail if the token endpoint rejects the credentials", func() {
+		tokenSource, err := NewClientCredentialsTokenSource(ts.URL, "client", "wrong", nil, "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = tokenSource.Token()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("got 401"))
+	})
+
+	It("should fail to create the token source with an invalid cert dir", func() {
+		_, err := NewClientCredentialsTokenSource(ts.URL, "client", "secret", nil, "/invaliddir")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+type countingTokenSource struct {
+	lock  sync.Mutex
+	count int
+}
+
+func (c *countingTokenSource) Token() (*oauth2.Token, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.count++
+	return &oauth2.Token{AccessToken: fmt.Sprintf("refreshed-%d", c.count)}, nil
+}
+
+var _ = Describe("Http data source with refreshed tokens", func() {
+	var (
+		ts *httptest.Server
+		dp *HTTPDataSource
+	)
+
+	AfterEach(func() {
+		if dp != nil {
+			dp.Close()
+		}
+		ts.Close()
+	})
+
+	It("should write the refreshed token to the nbdkit header file", func() {
+		ts = createTestServer(imageDir)
+		var err error
+		dp, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "initial", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		dp.SetTokenSource(&countingTokenSource{})
+		phase, err := dp.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		content, err := ioutil.ReadFile(dp.headerFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("Authorization: Bearer refreshed-1\n"))
+	})
+
+	It("should use the refreshed token for segmented downloads", func() {
+		fileServer := http.FileServer(http.Dir(imageDir))
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			if auth != "Bearer initial" && !strings.HasPrefix(auth, "Bearer refreshed-") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fileServer.ServeHTTP(w, r)
+		}))
+		tmpDir, err := ioutil.TempDir("", "scratch")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+cirrosFileName, "", "", "initial", "", "", cdiv1.DataVolumeKubeVirt)
+		Expect(err).ToNot(HaveOccurred())
+		tokenSource := &countingTokenSource{}
+		dp.SetTokenSource(tokenSource)
+		dp.SetSegmentedDownload(2, 0)
+		phase, err := dp.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = dp.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		// The request of the validators of the source, then one per segment
+		Expect(tokenSource.count).To(Equal(3))
+	})
+})
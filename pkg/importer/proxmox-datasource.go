@@ -0,0 +1,297 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	proxmoxAPIPath = "/api2/json"
+	// maximum size of the Proxmox API responses
+	maxProxmoxResponseSize = 1024 * 1024
+)
+
+// ProxmoxDataSource is the data provider for the disks of Proxmox VE virtual machines. The disk is read from the export
+// stream of its volume, the stream pvesm export produces, a little endian 64 bit size followed by the data.
+// Sequence of phases:
+// 1a. Info -> TransferDataFile if the volume is raw, which includes LVM, Ceph and ZFS volumes
+// 1b. Info -> TransferScratch if the volume is qcow2
+// 2. Transfer -> Convert
+type ProxmoxDataSource struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// reader of the export stream
+	exportReader io.ReadCloser
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, a file in scratch space.
+	url *url.URL
+	// the format of the volume, raw or qcow2
+	format string
+	// the size of the exported data
+	contentLength uint64
+}
+
+type proxmoxResource struct {
+	VMID int64  `json:"vmid"`
+	Node string `json:"node"`
+	Type string `json:"type"`
+}
+
+type proxmoxVolume struct {
+	Format string `json:"format"`
+	Size   uint64 `json:"size"`
+}
+
+type proxmoxVMStatus struct {
+	Status string `json:"status"`
+}
+
+// proxmoxClient calls the Proxmox VE API with an API token.
+type proxmoxClient struct {
+	client        *http.Client
+	endpoint      string
+	authorization string
+}
+
+// NewProxmoxDataSource creates a new instance of the Proxmox VE data provider. The endpoint is the URL of the API of a
+// node of the cluster, the node running the VM is looked up in the cluster resources if not set. The disk is the key of
+// the disk in the configuration of the VM, for instance scsi0.
+func NewProxmoxDataSource(endpoint, tokenID, tokenSecret, node string, vmID int64, disk, certDir string) (*ProxmoxDataSource, error) {
+	apiClient, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	apiClient.Timeout = time.Minute
+	pc := &proxmoxClient{
+		client:        apiClient,
+		endpoint:      strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), proxmoxAPIPath),
+		authorization: fmt.Sprintf("PVEAPIToken=%s=%s", tokenID, tokenSecret),
+	}
+	if node == "" {
+		if node, err = pc.findNode(vmID); err != nil {
+			return nil, err
+		}
+	}
+	status := &proxmoxVMStatus{}
+	if err := pc.get(fmt.Sprintf("/nodes/%s/qemu/%d/status/current", node, vmID), status); err != nil {
+		return nil, err
+	}
+	if status.Status != "stopped" {
+		return nil, errors.Errorf("VM %d is %s, stop it to import its disks", vmID, status.Status)
+	}
+	config := map[string]interface{}{}
+	if err := pc.get(fmt.Sprintf("/nodes/%s/qemu/%d/config", node, vmID), &config); err != nil {
+		return nil, err
+	}
+	volumeID, err := proxmoxDiskVolume(config, disk)
+	if err != nil {
+		return nil, errors.Wrapf(err, "VM %d", vmID)
+	}
+	storage := strings.SplitN(volumeID, ":", 2)[0]
+	contentPath := fmt.Sprintf("/nodes/%s/storage/%s/content/%s", node, storage, url.PathEscape(volumeID))
+	volume := &proxmoxVolume{}
+	if err := pc.get(contentPath, volume); err != nil {
+		return nil, err
+	}
+	if volume.Format != "raw" && volume.Format != "qcow2" {
+		return nil, errors.Errorf("format %s of volume %s is not supported", volume.Format, volumeID)
+	}
+
+	client, err := createHTTPClient(certDir, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest("GET", pc.endpoint+proxmoxAPIPath+contentPath+"/export?format="+url.QueryEscape(volume.Format+"+size"), nil)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", pc.authorization)
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrapf(err, "unable to export volume %s", volumeID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, errors.Errorf("unable to export volume %s, got %d", volumeID, resp.StatusCode)
+	}
+	var size uint64
+	if err := binary.Read(resp.Body, binary.LittleEndian, &size); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, errors.Wrapf(err, "unable to read the size of the export of volume %s", volumeID)
+	}
+	ps := &ProxmoxDataSource{
+		ctx:    ctx,
+		cancel: cancel,
+		exportReader: struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(resp.Body, int64(size)), resp.Body},
+		format:        volume.Format,
+		contentLength: size,
+	}
+	klog.V(1).Infof("Importing %s volume %s of VM %d on node %s, %d bytes", volume.Format, volumeID, vmID, node, size)
+	return ps, nil
+}
+
+// get calls the API and decodes the data of the response into out.
+func (pc *proxmoxClient) get(path string, out interface{}) error {
+	req, err := http.NewRequest("GET", pc.endpoint+proxmoxAPIPath+path, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Authorization", pc.authorization)
+	resp, err := pc.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s", path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unable to get %s, got %d", path, resp.StatusCode)
+	}
+	response := struct {
+		Data interface{} `json:"data"`
+	}{out}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxProxmoxResponseSize)).Decode(&response); err != nil {
+		return errors.Wrapf(err, "unable to parse %s", path)
+	}
+	return nil
+}
+
+// findNode returns the node of the cluster running the VM.
+func (pc *proxmoxClient) findNode(vmID int64) (string, error) {
+	var resources []proxmoxResource
+	if err := pc.get("/cluster/resources?type=vm", &resources); err != nil {
+		return "", err
+	}
+	for _, resource := range resources {
+		if resource.VMID == vmID && resource.Type == "qemu" {
+			return resource.Node, nil
+		}
+	}
+	return "", errors.Errorf("VM %d not found in the cluster", vmID)
+}
+
+// proxmoxDiskVolume returns the volume ID of a disk in the configuration of a VM, like local-lvm:vm-100-disk-0 in
+// local-lvm:vm-100-disk-0,size=32G.
+func proxmoxDiskVolume(config map[string]interface{}, disk string) (string, error) {
+	value, ok := config[disk].(string)
+	if !ok {
+		return "", errors.Errorf("disk %s not found", disk)
+	}
+	options := strings.Split(value, ",")
+	for _, option := range options[1:] {
+		if option == "media=cdrom" {
+			return "", errors.Errorf("disk %s is a CD-ROM drive", disk)
+		}
+	}
+	volumeID := options[0]
+	if !strings.Contains(volumeID, ":") {
+		// none or a path to a device of the host
+		return "", errors.Errorf("disk %s has no volume", disk)
+	}
+	return volumeID, nil
+}
+
+// Info is called to get initial information about the data. The detected format has to match the format of the
+// volume, so a raw volume is never interpreted as qcow2.
+func (ps *ProxmoxDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	ps.readers, err = NewFormatReaders(ps.exportReader, ps.contentLength)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if ps.readers.Convert != (ps.format == "qcow2") {
+		return ProcessingPhaseError, errors.Errorf("the data of the volume does not match its format %s", ps.format)
+	}
+	if !ps.readers.Convert {
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (ps *ProxmoxDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(ps.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ps.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (ps *ProxmoxDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	ps.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(ps.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (ps *ProxmoxDataSource) GetURL() *url.URL {
+	return ps.url
+}
+
+// Close all readers.
+func (ps *ProxmoxDataSource) Close() error {
+	var err error
+	if ps.readers != nil {
+		err = ps.readers.Close()
+	}
+	ps.cancel()
+	if closeErr := ps.exportReader.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GetSourceInfo returns the compression of the source and its size as downloaded
+func (ps *ProxmoxDataSource) GetSourceInfo() (string, int64) {
+	return ps.readers.compression(), int64(ps.contentLength)
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Proxmox data source", func() {
+	const volumeID = "local-lvm:vm-100-disk-0"
+
+	var (
+		ts           *httptest.Server
+		tmpDir       string
+		diskData     []byte
+		volumeFormat string
+		vmStatus     string
+		config       map[string]interface{}
+		exportFormat string
+	)
+
+	respond := func(w http.ResponseWriter, data interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "proxmox")
+		Expect(err).ToNot(HaveOccurred())
+		diskData = cirrosData
+		volumeFormat = "qcow2"
+		vmStatus = "stopped"
+		config = map[string]interface{}{
+			"name":  "fedora",
+			"scsi0": volumeID + ",size=32G",
+			"ide2":  "local:iso/fedora.iso,media=cdrom",
+			"sata1": "none",
+		}
+		exportFormat = ""
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Authorization")).To(Equal("PVEAPIToken=root@pam!cdi=secret"))
+			switch r.URL.EscapedPath() {
+			case "/api2/json/cluster/resources":
+				Expect(r.URL.Query().Get("type")).To(Equal("vm"))
+				respond(w, []map[string]interface{}{
+					{"vmid": 100, "node": "pve2", "type": "lxc"},
+					{"vmid": 100, "node": "pve1", "type": "qemu"},
+				})
+			case "/api2/json/nodes/pve1/qemu/100/status/current":
+				respond(w, map[string]interface{}{"status": vmStatus})
+			case "/api2/json/nodes/pve1/qemu/100/config":
+				respond(w, config)
+			case "/api2/json/nodes/pve1/storage/local-lvm/content/local-lvm:vm-100-disk-0":
+				respond(w, map[string]interface{}{"format": volumeFormat, "size": 34359738368})
+			case "/api2/json/nodes/pve1/storage/local-lvm/content/local-lvm:vm-100-disk-0/export":
+				exportFormat = r.URL.Query().Get("format")
+				binary.Write(w, binary.LittleEndian, uint64(len(diskData)))
+				w.Write(diskData)
+				// Trailing data after the size announced by the stream is not part of the disk
+				w.Write(bytes.Repeat([]byte{0xff}, 512))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should download the export of a qcow2 volume to scratch space", func() {
+		ps, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "", 100, "scsi0", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer ps.Close()
+		Expect(exportFormat).To(Equal("qcow2+size"))
+		phase, err := ps.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ps.Transfer(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ps.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(diskData))
+	})
+
+	It("should write the export of a raw volume directly to the target", func() {
+		diskData, _ = ioutil.ReadFile(tinyCoreFilePath)
+		volumeFormat = "raw"
+		ps, err := NewProxmoxDataSource(ts.URL+"/api2/json/", "root@pam!cdi", "secret", "pve1", 100, "scsi0", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer ps.Close()
+		Expect(exportFormat).To(Equal("raw+size"))
+		phase, err := ps.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = ps.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(diskData))
+	})
+
+	It("should fail if the data does not match the format of the volume", func() {
+		volumeFormat = "raw"
+		ps, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "pve1", 100, "scsi0", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer ps.Close()
+		_, err = ps.Info()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match its format raw"))
+	})
+
+	It("should fail if the VM is running", func() {
+		vmStatus = "running"
+		_, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "pve1", 100, "scsi0", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("VM 100 is running"))
+	})
+
+	It("should fail if the VM is not in the cluster", func() {
+		_, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "", 101, "scsi0", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("VM 101 not found"))
+	})
+
+	table.DescribeTable("should reject disks without a volume", func(disk, message string) {
+		_, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "pve1", 100, disk, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+	},
+		table.Entry("a missing disk", "virtio0", "disk virtio0 not found"),
+		table.Entry("a CD-ROM drive", "ide2", "disk ide2 is a CD-ROM drive"),
+		table.Entry("an empty drive", "sata1", "disk sata1 has no volume"),
+	)
+
+	It("should reject unsupported volume formats", func() {
+		volumeFormat = "vmdk"
+		_, err := NewProxmoxDataSource(ts.URL, "root@pam!cdi", "secret", "pve1", 100, "scsi0", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("format vmdk"))
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// RBDDataSource is the data provider for RBD images of external Ceph clusters. qemu-img reads the image with its rbd
+// driver and converts it to raw, so the image does not have to be exported first.
+// Sequence of phases:
+// 1. Info -> Convert
+type RBDDataSource struct {
+	// configDir contains the ceph configuration file and the keyring of the user
+	configDir string
+	// url the rbd://user@/pool/image[@snapshot]?conf=file url qemu-img reads the image from.
+	url *url.URL
+}
+
+// NewRBDDataSource creates a new instance of the Ceph RBD data provider. The endpoint is an rbd:///pool/image[@snapshot]
+// URL, monitors is the comma separated list of the monitors of the cluster, and key is the key of the user.
+func NewRBDDataSource(endpoint, user, key, monitors string) (*RBDDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	image := strings.Split(strings.TrimPrefix(ep.Path, "/"), "/")
+	if ep.Scheme != "rbd" || len(image) != 2 || image[0] == "" || image[1] == "" {
+		return nil, errors.Errorf("%s is not an rbd:///pool/image URL", endpoint)
+	}
+	if monitors == "" {
+		return nil, errors.New("the monitors of the cluster are required")
+	}
+	if user == "" || key == "" {
+		return nil, errors.New("a user id and a key are required")
+	}
+	user = strings.TrimPrefix(user, "client.")
+	rs := &RBDDataSource{}
+	if rs.configDir, err = ioutil.TempDir("", "ceph"); err != nil {
+		return nil, errors.Wrap(err, "unable to create the ceph configuration directory")
+	}
+	keyring := filepath.Join(rs.configDir, "keyring")
+	conf := filepath.Join(rs.configDir, "ceph.conf")
+	err = ioutil.WriteFile(keyring, []byte(fmt.Sprintf("[client.%s]\n\tkey = %s\n", user, strings.TrimSpace(key))), 0600)
+	if err == nil {
+		err = ioutil.WriteFile(conf, []byte(fmt.Sprintf("[global]\n\tmon_host = %s\n\tkeyring = %s\n", monitors, keyring)), 0600)
+	}
+	if err != nil {
+		rs.Close()
+		return nil, errors.Wrap(err, "unable to write the ceph configuration")
+	}
+	rs.url = &url.URL{
+		Scheme:   "rbd",
+		User:     url.User(user),
+		Path:     ep.Path,
+		RawQuery: url.Values{"conf": []string{conf}}.Encode(),
+	}
+	klog.V(1).Infof("Reading %s from monitors %s", ep.Path, monitors)
+	return rs, nil
+}
+
+// Info is called to get initial information about the data, the image is read by qemu-img during the conversion.
+func (rs *RBDDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseConvert, nil
+}
+
+// Transfer is not used, the image is converted directly.
+func (rs *RBDDataSource) Transfer(path string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an RBD image to scratch space is not supported")
+}
+
+// TransferFile is not used, the image is converted directly.
+func (rs *RBDDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("transferring an RBD image to a file is not supported")
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (rs *RBDDataSource) GetURL() *url.URL {
+	return rs.url
+}
+
+// Close removes the ceph configuration and keyring.
+func (rs *RBDDataSource) Close() error {
+	if rs.configDir != "" {
+		return os.RemoveAll(rs.configDir)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RBD data source", func() {
+	var rs *RBDDataSource
+
+	BeforeEach(func() {
+		rs = nil
+	})
+
+	AfterEach(func() {
+		if rs != nil {
+			rs.Close()
+		}
+	})
+
+	It("should convert the image with a ceph configuration and keyring", func() {
+		var err error
+		rs, err = NewRBDDataSource("rbd:///vms/disk-1@migration", "client.importer", "AQBpXHRhAAAAABAAkzNc1Jbf8dgGgGHqP0lmhQ==\n", "10.0.0.1:6789,10.0.0.2")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := rs.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		conf := filepath.Join(rs.configDir, "ceph.conf")
+		Expect(rs.GetURL().Scheme).To(Equal("rbd"))
+		Expect(rs.GetURL().User.Username()).To(Equal("importer"))
+		Expect(rs.GetURL().Path).To(Equal("/vms/disk-1@migration"))
+		Expect(rs.GetURL().Query().Get("conf")).To(Equal(conf))
+
+		content, err := ioutil.ReadFile(conf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("[global]\n\tmon_host = 10.0.0.1:6789,10.0.0.2\n\tkeyring = " + filepath.Join(rs.configDir, "keyring") + "\n"))
+		content, err = ioutil.ReadFile(filepath.Join(rs.configDir, "keyring"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("[client.importer]\n\tkey = AQBpXHRhAAAAABAAkzNc1Jbf8dgGgGHqP0lmhQ==\n"))
+
+		configDir := rs.configDir
+		Expect(rs.Close()).To(Succeed())
+		_, err = os.Stat(configDir)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	table.DescribeTable("should reject invalid parameters", func(endpoint, user, key, monitors string) {
+		_, err := NewRBDDataSource(endpoint, user, key, monitors)
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("without pool", "rbd:///disk-1", "admin", "key", "10.0.0.1"),
+		table.Entry("with another scheme", "http:///vms/disk-1", "admin", "key", "10.0.0.1"),
+		table.Entry("without monitors", "rbd:///vms/disk-1", "admin", "key", ""),
+		table.Entry("without key", "rbd:///vms/disk-1", "admin", "", "10.0.0.1"),
+	)
+})
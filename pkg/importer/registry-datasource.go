@@ -17,7 +17,6 @@ limitations under the License.
 package importer
 
 import (
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -36,38 +35,69 @@ const (
 	containerDiskImageDir = "disk"
 )
 
-// RegistryDataSource is the struct containing the information needed to import from a registry data source.
+// RegistryDataSource is the struct containing the information needed to import from a registry data source. The disk
+// image is streamed out of its layer as the layer downloads, a raw disk image is written directly to the target and
+// only a disk image that needs conversion is written to scratch space.
 // Sequence of phases:
-// 1. Info -> Transfer
-// 2. Transfer -> Convert
+// 1a. Info -> TransferDataFile (raw image)
+// 1b. Info -> TransferScratch (image that needs conversion)
+// 2a. TransferDataFile -> Resize
+// 2b. TransferScratch -> Convert
 type RegistryDataSource struct {
 	endpoint    string
 	accessKey   string
 	secKey      string
 	certDir     string
+	platform    string
 	insecureTLS bool
 	imageDir    string
+	// fileName the name of the disk image in the image
+	fileName string
+	// fileSize the size of the disk image recorded in its layer, 0 if unknown
+	fileSize int64
+	// readers the format readers of the disk image streamed out of its layer
+	readers *FormatReaders
 	//The discovered image file in scratch space.
 	url *url.URL
 }
 
-// NewRegistryDataSource creates a new instance of the Registry Data Source.
-func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecureTLS bool) *RegistryDataSource {
+// NewRegistryDataSource creates a new instance of the Registry Data Source. The platform selects the image of a
+// manifest list, the platform of the importer if empty.
+func NewRegistryDataSource(endpoint, accessKey, secKey, certDir, platform string, insecureTLS bool) *RegistryDataSource {
 	return &RegistryDataSource{
 		endpoint:    endpoint,
 		accessKey:   accessKey,
 		secKey:      secKey,
 		certDir:     certDir,
+		platform:    platform,
 		insecureTLS: insecureTLS,
 	}
 }
 
-// Info is called to get initial information about the data. No information available for registry currently.
+// Info is called to get initial information about the data. It starts streaming the disk image out of its layer to
+// find its format.
 func (rd *RegistryDataSource) Info() (ProcessingPhase, error) {
+	reader, fileName, err := OpenRegistryImageFile(rd.endpoint, containerDiskImageDir, rd.accessKey, rd.secKey, rd.certDir, rd.platform, rd.insecureTLS)
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
+	}
+	rd.fileName = fileName
+	if fileReader, ok := reader.(*registryFileReader); ok {
+		rd.fileSize = fileReader.size
+	}
+	rd.readers, err = NewFormatReaders(reader, 0)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if !rd.readers.Convert {
+		// Streaming a raw disk image, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
 	return ProcessingPhaseTransferScratch, nil
 }
 
-// Transfer is called to transfer the data from the source registry to a temporary location.
+// Transfer is called to transfer the disk image streamed from the source registry to a temporary location.
 func (rd *RegistryDataSource) Transfer(path string) (ProcessingPhase, error) {
 	size, err := util.GetAvailableSpace(path)
 	if err != nil {
@@ -77,28 +107,46 @@ func (rd *RegistryDataSource) Transfer(path string) (ProcessingPhase, error) {
 		//Path provided is invalid.
 		return ProcessingPhaseError, ErrInvalidPath
 	}
+	if rd.readers == nil {
+		return ProcessingPhaseError, errors.New("Info must be called before Transfer")
+	}
 	rd.imageDir = filepath.Join(path, containerDiskImageDir)
+	if err := os.MkdirAll(rd.imageDir, os.ModePerm); err != nil {
+		return ProcessingPhaseError, errors.Wrap(err, "Error creating the image directory")
+	}
+	// The format readers decompress the file
+	fileName := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(rd.fileName), ".gz"), ".xz")
+	file := filepath.Join(rd.imageDir, fileName)
 
 	klog.V(1).Infof("Copying registry image to scratch space.")
-	err = CopyRegistryImage(rd.endpoint, path, containerDiskImageDir, rd.accessKey, rd.secKey, rd.certDir, rd.insecureTLS)
-	if err != nil {
+	if err := util.StreamDataToFile(rd.readers.TopReader(), file); err != nil {
 		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
 	}
 
-	imageFile, err := getImageFileName(rd.imageDir)
-	if err != nil {
-		return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
-	}
-
-	// imageFile and rd.imageDir are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
-	rd.url, _ = url.Parse(filepath.Join(rd.imageDir, imageFile))
-	klog.V(3).Infof("Successfully found file. VM disk image filename is %s", rd.url.String())
+	// rd.imageDir and fileName are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
+	rd.url, _ = url.Parse(file)
+	klog.V(3).Infof("Successfully copied file. VM disk image filename is %s", rd.url.String())
 	return ProcessingPhaseConvert, nil
 }
 
-// TransferFile is called to transfer the data from the source to the passed in file.
+// TransferFile is called to transfer the raw disk image streamed from the source registry to the passed in file.
 func (rd *RegistryDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	return ProcessingPhaseError, errors.New("Transferfile should not be called")
+	if rd.readers == nil {
+		return ProcessingPhaseError, errors.New("Info must be called before TransferFile")
+	}
+	if err := util.StreamDataToFile(rd.readers.TopReader(), fileName); err != nil {
+		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// ProbeVirtualSize returns the virtual size of the disk image of the registry image. The size of a raw disk image is
+// the size recorded in its layer, so only the layer holding the disk image is read up to its header.
+func (rd *RegistryDataSource) ProbeVirtualSize() (int64, error) {
+	if rd.readers == nil {
+		return 0, errors.New("Info must be called before ProbeVirtualSize")
+	}
+	return rd.readers.ProbeVirtualSize(rd.fileSize)
 }
 
 // GetURL returns the url that the data processor can use when converting the data.
@@ -106,48 +154,12 @@ func (rd *RegistryDataSource) GetURL() *url.URL {
 	return rd.url
 }
 
-// Close closes any readers or other open resources.
+// Close closes the readers of the disk image, and so the image.
 func (rd *RegistryDataSource) Close() error {
-	// No-op, no open readers
-	return nil
-}
-
-func getImageFileName(dir string) (string, error) {
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		klog.Errorf("image directory does not exist")
-		return "", errors.Errorf("image directory does not exist")
-	}
-
-	entries, err := ioutil.ReadDir(dir)
-	if err != nil {
-		klog.Errorf("Error reading directory")
-		return "", errors.Wrapf(err, "image file does not exist in image directory")
-	}
-
-	if len(entries) == 0 {
-		klog.Errorf("image file does not exist in image directory - directory is empty ")
-		return "", errors.New("image file does not exist in image directory - directory is empty")
+	var err error
+	if rd.readers != nil {
+		err = rd.readers.Close()
+		rd.readers = nil
 	}
-
-	if len(entries) > 1 {
-		klog.Errorf("image directory contains more than one file")
-		return "", errors.New("image directory contains more than one file")
-	}
-
-	fileinfo := entries[0]
-	if fileinfo.IsDir() {
-		klog.Errorf("image file does not exist in image directory contains another directory ")
-		return "", errors.New("image directory contains another directory")
-	}
-
-	filename := fileinfo.Name()
-
-	if len(strings.TrimSpace(filename)) == 0 {
-		klog.Errorf("image file does not exist in image directory - file has no name ")
-		return "", errors.New("image file does has no name")
-	}
-
-	klog.V(1).Infof("VM disk image filename is %s", filename)
-
-	return filename, nil
+	return err
 }
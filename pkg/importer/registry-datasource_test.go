@@ -1,14 +1,20 @@
 package importer
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
 )
 
 var (
@@ -35,18 +41,28 @@ var _ = Describe("Registry data source", func() {
 		}
 	})
 
-	It("should return transfer after info is called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
+	It("should return transfer scratch after info is called on an image that needs conversion", func() {
+		ds = NewRegistryDataSource("oci-archive:"+imageFile, "", "", "", "", true)
 		result, err := ds.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		Expect(ds.fileName).To(Equal("disk/cirros-0.3.4-x86_64-disk.img"))
+	})
+
+	It("should return an error on info if the image cannot be read", func() {
+		ds = NewRegistryDataSource("invalid", "", "", "", "", true)
+		result, err := ds.Info()
+		Expect(err).To(HaveOccurred())
+		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
 	table.DescribeTable("Transfer should ", func(ep, accKey, secKey, certDir, scratchPath string, insecureRegistry bool, wantErr bool) {
 		if scratchPath == "" {
 			scratchPath = tmpDir
 		}
-		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, insecureRegistry)
+		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, "", insecureRegistry)
+		_, err := ds.Info()
+		Expect(err).NotTo(HaveOccurred())
 
 		// Need to pass in a real path if we don't want scratch space needed error.
 		result, err := ds.Transfer(scratchPath)
@@ -54,6 +70,7 @@ var _ = Describe("Registry data source", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(ProcessingPhaseConvert).To(Equal(result))
 			Expect(filepath.Join(scratchPath, containerDiskImageDir)).To(Equal(ds.imageDir))
+			Expect(ds.GetURL().Path).To(Equal(filepath.Join(scratchPath, containerDiskImageDir, "cirros-0.3.4-x86_64-disk.img")))
 		} else {
 			Expect(err).To(HaveOccurred())
 			Expect(ProcessingPhaseError).To(Equal(result))
@@ -62,67 +79,235 @@ var _ = Describe("Registry data source", func() {
 		table.Entry("successfully return Convert on valid scratch space and empty user parameters", "oci-archive:"+imageFile, "", "", "", "", true, false),
 		table.Entry("successfully return Convert on valid scratch space and parameters", "oci-archive:"+imageFile, "username", "password", "/path/to/cert", "", true, false),
 		table.Entry("return Error on invalid scratch space", "oci-archive:"+imageFile, "", "", "", "/invalid", true, true),
-		table.Entry("return Error on valid scratch space, but CopyImage failed", "invalid", "", "", "", "", true, true),
 	)
 
-	It("TransferFile should not be called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
-		result, err := ds.TransferFile("file")
+	It("Transfer should fail if info was not called", func() {
+		ds = NewRegistryDataSource("oci-archive:"+imageFile, "", "", "", "", true)
+		result, err := ds.Transfer(tmpDir)
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
-	It("getImageFileName should return an error with non-existing image directory", func() {
-		_, err := getImageFileName("/invalid")
-		Expect(err).To(HaveOccurred())
-		Expect("image directory does not exist").To(Equal(err.Error()))
+	It("should stream a raw image to the target file without scratch space", func() {
+		raw, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		archive := filepath.Join(tmpDir, "raw.tar")
+		createArtifactArchive(archive, artifactFile{"application/octet-stream", "tinyCore.iso", raw})
+		ds = NewRegistryDataSource("oci-archive:"+archive, "", "", "", "", false)
+		result, err := ds.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		target := filepath.Join(tmpDir, "disk.img")
+		result, err = ds.TransferFile(target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal(raw))
 	})
 
-	It("getImageFileName should return an error with invalid image directory", func() {
-		file, err := os.Create(filepath.Join(tmpDir, "test"))
-		Expect(err).NotTo(HaveOccurred())
-		_, err = getImageFileName(file.Name())
+	It("TransferFile should fail if info was not called", func() {
+		ds = NewRegistryDataSource("oci-archive:"+imageFile, "", "", "", "", true)
+		result, err := ds.TransferFile("file")
 		Expect(err).To(HaveOccurred())
-		Expect(strings.Contains(err.Error(), "image file does not exist in image directory")).To(BeTrue())
+		Expect(ProcessingPhaseError).To(Equal(result))
 	})
+})
 
-	It("getImageFileName should return an error with empty image directory", func() {
-		err := os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir), os.ModeDir)
-		Expect(err).NotTo(HaveOccurred())
-		_, err = getImageFileName(filepath.Join(tmpDir, containerDiskImageDir))
-		Expect(err).To(HaveOccurred())
-		Expect("image file does not exist in image directory - directory is empty").To(Equal(err.Error()))
+type artifactFile struct {
+	mediaType string
+	title     string
+	content   []byte
+}
+
+// ociArchiveWriter writes the blobs and the files of an oci-archive
+type ociArchiveWriter struct {
+	tw *tar.Writer
+}
+
+func (w *ociArchiveWriter) addFile(name string, content []byte) {
+	Expect(w.tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+	_, err := w.tw.Write(content)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (w *ociArchiveWriter) addBlob(mediaType string, content []byte, annotations map[string]string) map[string]interface{} {
+	d := digest.FromBytes(content)
+	w.addFile("blobs/sha256/"+d.Hex(), content)
+	desc := map[string]interface{}{"mediaType": mediaType, "digest": d.String(), "size": len(content)}
+	if annotations != nil {
+		desc["annotations"] = annotations
+	}
+	return desc
+}
+
+// addJSONBlob adds the object as a blob and returns its descriptor
+func (w *ociArchiveWriter) addJSONBlob(mediaType string, object interface{}) map[string]interface{} {
+	content, err := json.Marshal(object)
+	Expect(err).ToNot(HaveOccurred())
+	return w.addBlob(mediaType, content, nil)
+}
+
+// addArtifact adds the manifest of an OCI artifact with a layer per file and returns its descriptor
+func (w *ociArchiveWriter) addArtifact(configMediaType string, files ...artifactFile) map[string]interface{} {
+	var layers []map[string]interface{}
+	for _, file := range files {
+		var annotations map[string]string
+		if file.title != "" {
+			annotations = map[string]string{"org.opencontainers.image.title": file.title}
+		}
+		layers = append(layers, w.addBlob(file.mediaType, file.content, annotations))
+	}
+	return w.addJSONBlob("application/vnd.oci.image.manifest.v1+json", map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"config":        w.addBlob(configMediaType, []byte("{}"), nil),
+		"layers":        layers,
 	})
+}
 
-	It("getImageFileName should return an error with image directory containing another directory", func() {
-		err := os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir), os.ModeDir)
-		Expect(err).NotTo(HaveOccurred())
-		err = os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir, "anotherdir"), os.ModeDir)
-		Expect(err).NotTo(HaveOccurred())
-		_, err = getImageFileName(filepath.Join(tmpDir, containerDiskImageDir))
-		Expect(err).To(HaveOccurred())
-		Expect("image directory contains another directory").To(Equal(err.Error()))
+// writeOCIArchive writes an oci-archive whose index.json refers to the manifest added by the add function
+func writeOCIArchive(path string, add func(w *ociArchiveWriter) map[string]interface{}) {
+	archive, err := os.Create(path)
+	Expect(err).ToNot(HaveOccurred())
+	defer archive.Close()
+	w := &ociArchiveWriter{tw: tar.NewWriter(archive)}
+	defer w.tw.Close()
+	w.addFile("oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`))
+	index, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"manifests":     []interface{}{add(w)},
 	})
+	Expect(err).ToNot(HaveOccurred())
+	w.addFile("index.json", index)
+}
 
-	It("getImageFileName should return an error with zero length filename", func() {
-		err := os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir), os.ModeDir)
-		Expect(err).NotTo(HaveOccurred())
-		_, err = os.Create(filepath.Join(tmpDir, containerDiskImageDir, " "))
+// createArtifactArchive writes an oci-archive of an OCI artifact with a layer per file, as pushed by ORAS
+func createArtifactArchive(path string, files ...artifactFile) {
+	writeOCIArchive(path, func(w *ociArchiveWriter) map[string]interface{} {
+		return w.addArtifact("application/vnd.oras.config.v1+json", files...)
+	})
+}
+
+// createMultiArchArchive writes an oci-archive of an image index with an artifact per os/arch[/variant] platform
+func createMultiArchArchive(path string, platforms map[string]artifactFile) {
+	writeOCIArchive(path, func(w *ociArchiveWriter) map[string]interface{} {
+		var manifests []map[string]interface{}
+		for platform, file := range platforms {
+			parts := strings.Split(platform, "/")
+			// The type of the manifests of a nested index is guessed from their config in an oci layout
+			desc := w.addArtifact("application/vnd.oci.image.config.v1+json", file)
+			desc["platform"] = map[string]string{"os": parts[0], "architecture": parts[1]}
+			if len(parts) == 3 {
+				desc["platform"].(map[string]string)["variant"] = parts[2]
+			}
+			manifests = append(manifests, desc)
+		}
+		return w.addJSONBlob("application/vnd.oci.image.index.v1+json", map[string]interface{}{
+			"schemaVersion": 2,
+			"manifests":     manifests,
+		})
+	})
+}
+
+var _ = Describe("Registry data source with OCI artifacts", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "artifact")
 		Expect(err).NotTo(HaveOccurred())
-		_, err = getImageFileName(filepath.Join(tmpDir, containerDiskImageDir))
-		Expect(err).To(HaveOccurred())
-		Expect("image file does has no name").To(Equal(err.Error()))
 	})
 
-	It("getImageFileName should return an error with multiple files in the image directory", func() {
-		err := os.Mkdir(filepath.Join(tmpDir, containerDiskImageDir), os.ModeDir)
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	// Writing to a buffer does not fail, the table entries are built before the assertions can be used
+	compress := func(data []byte) []byte {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		gw.Write(data)
+		gw.Close()
+		return buf.Bytes()
+	}
+
+	table.DescribeTable("should copy the disk image of the artifact", func(fileName string, files ...artifactFile) {
+		archive := filepath.Join(tmpDir, "artifact.tar")
+		createArtifactArchive(archive, files...)
+		scratch := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratch, 0755)).To(Succeed())
+		ds := NewRegistryDataSource("oci-archive:"+archive, "", "", "", "", false)
+		defer ds.Close()
+		result, err := ds.Info()
 		Expect(err).NotTo(HaveOccurred())
-		_, err = os.Create(filepath.Join(tmpDir, containerDiskImageDir, "extra-file"))
+		Expect(result).To(Equal(ProcessingPhaseTransferScratch))
+		result, err = ds.Transfer(scratch)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = os.Create(filepath.Join(tmpDir, containerDiskImageDir, "disk.img"))
+		Expect(result).To(Equal(ProcessingPhaseConvert))
+		Expect(ds.GetURL().Path).To(Equal(filepath.Join(scratch, containerDiskImageDir, fileName)))
+		content, err := ioutil.ReadFile(ds.GetURL().Path)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = getImageFileName(filepath.Join(tmpDir, containerDiskImageDir))
+		Expect(content).To(Equal(cirrosData))
+	},
+		table.Entry("with a single file", "cirros.qcow2", artifactFile{"application/x-qcow2", "cirros.qcow2", cirrosData}),
+		table.Entry("without title", artifactFileName, artifactFile{"application/octet-stream", "", cirrosData}),
+		table.Entry("with a compressed file", "cirros.qcow2", artifactFile{"application/gzip", "cirros.qcow2.gz", compress(cirrosData)}),
+		table.Entry("with several files", "cirros.img",
+			artifactFile{"text/markdown", "README.md", []byte("# Cirros")},
+			artifactFile{"application/octet-stream", "cirros.img", cirrosData},
+			artifactFile{"text/plain", "SHA256SUMS", []byte("sums")}),
+	)
+
+	It("should fail on an artifact with several disk images", func() {
+		archive := filepath.Join(tmpDir, "artifact.tar")
+		createArtifactArchive(archive,
+			artifactFile{"application/x-qcow2", "cirros.qcow2", cirrosData},
+			artifactFile{"application/x-qcow2", "fedora.qcow2", cirrosData})
+		err := CopyRegistryImage("oci-archive:"+archive, tmpDir, containerDiskImageDir, "", "", "", "", false)
 		Expect(err).To(HaveOccurred())
-		Expect("image directory contains more than one file").To(Equal(err.Error()))
+		Expect(err.Error()).To(ContainSubstring("Found 2 disk images"))
+	})
+
+	Context("with a manifest list", func() {
+		var archive string
+
+		// The content is long enough for the header of the format readers
+		content := func(arch string) []byte {
+			return bytes.Repeat([]byte(arch), 1024)
+		}
+
+		BeforeEach(func() {
+			archive = filepath.Join(tmpDir, "multiarch.tar")
+			createMultiArchArchive(archive, map[string]artifactFile{
+				"linux/amd64":    {"application/x-qcow2", "amd64.qcow2", content("amd64")},
+				"linux/arm64/v8": {"application/x-qcow2", "arm64.qcow2", content("arm64")},
+				"linux/s390x":    {"application/x-qcow2", "s390x.qcow2", content("s390x")},
+			})
+		})
+
+		table.DescribeTable("should copy the image of the platform", func(platform, fileName string) {
+			Expect(CopyRegistryImage("oci-archive:"+archive, tmpDir, containerDiskImageDir, "", "", "", platform, false)).To(Succeed())
+			data, err := ioutil.ReadFile(filepath.Join(tmpDir, containerDiskImageDir, fileName))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(content(strings.TrimSuffix(fileName, ".qcow2"))))
+		},
+			table.Entry("with os and architecture", "linux/s390x", "s390x.qcow2"),
+			table.Entry("with variant", "linux/arm64/v8", "arm64.qcow2"),
+			table.Entry("with the default variant", "linux/arm64", "arm64.qcow2"),
+			table.Entry("of the node by default", "", runtime.GOARCH+".qcow2"),
+		)
+
+		It("should fail if the platform is not in the manifest list", func() {
+			err := CopyRegistryImage("oci-archive:"+archive, tmpDir, containerDiskImageDir, "", "", "", "linux/ppc64le", false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no image found in image index for architecture ppc64le"))
+		})
+
+		It("should fail on an invalid platform", func() {
+			err := CopyRegistryImage("oci-archive:"+archive, tmpDir, containerDiskImageDir, "", "", "", "arm64", false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid platform"))
+		})
 	})
 })
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// rsyncAttempts is the number of times rsync is started before giving up on a transfer
+	rsyncAttempts = 5
+	// rsyncTimeout is the number of seconds without data after which rsync gives up on the connection
+	rsyncTimeout = "300"
+)
+
+var (
+	// rsyncPath is the rsync binary, a variable for testing.
+	rsyncPath = "rsync"
+	// rsyncRetryDelay is the time to wait before resuming an interrupted transfer, a variable for testing.
+	rsyncRetryDelay = 10 * time.Second
+	// rsyncRetryExitCodes are the exit codes of rsync for connection errors, see the EXIT VALUES of rsync(1)
+	rsyncRetryExitCodes = map[int]bool{
+		10: true, // error in socket I/O
+		12: true, // error in rsync protocol data stream
+		30: true, // timeout in data send/receive
+		35: true, // timeout waiting for daemon connection
+	}
+)
+
+// RsyncDataSource is the data provider for image files pulled with rsync over SSH. The file is copied to scratch
+// space with --partial, so a transfer interrupted by a poor link resumes from the data already received instead of
+// starting over.
+// Sequence of phases:
+// 1. Info -> TransferScratch
+// 2a. Transfer -> TransferDataFile if the file is a raw image, compressed or not
+// 2b. Transfer -> Convert
+type RsyncDataSource struct {
+	// remote is the source argument of rsync, user@host:path or user@host::module/path
+	remote string
+	// port is the SSH port, empty for the default one
+	port string
+	// file containing the SSH private key
+	keyFile string
+	// file containing the host key of the server, empty if the host key is not verified
+	knownHostsFile string
+	// stack of readers of the file in scratch space
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, the file in scratch space.
+	url *url.URL
+}
+
+// NewRsyncDataSource creates a new instance of the rsync data provider. The endpoint is an ssh:// URL with the path of
+// the file, relative to the module if one is passed, hostKey is the public key of the server in known_hosts format.
+func NewRsyncDataSource(endpoint, user, privateKey, module, hostKey string) (*RsyncDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	if ep.Scheme != "ssh" || ep.Hostname() == "" || ep.Path == "" {
+		return nil, errors.Errorf("%s is not an ssh:// URL of a file", endpoint)
+	}
+	if user == "" || privateKey == "" {
+		return nil, errors.New("a user name and an SSH private key are required")
+	}
+	host := ep.Hostname()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	rs := &RsyncDataSource{
+		port: ep.Port(),
+	}
+	if module != "" {
+		rs.remote = fmt.Sprintf("%s@%s::%s/%s", user, host, module, strings.TrimPrefix(ep.Path, "/"))
+	} else {
+		rs.remote = fmt.Sprintf("%s@%s:%s", user, host, ep.Path)
+	}
+	// ssh refuses keys that do not end with a new line
+	if !strings.HasSuffix(privateKey, "\n") {
+		privateKey += "\n"
+	}
+	if rs.keyFile, err = writeTempFile("ssh-key", privateKey); err != nil {
+		rs.Close()
+		return nil, errors.Wrap(err, "unable to write the SSH private key")
+	}
+	if hostKey != "" {
+		knownHost := ep.Hostname()
+		if rs.port != "" {
+			knownHost = "[" + knownHost + "]:" + rs.port
+		}
+		if rs.knownHostsFile, err = writeTempFile("known-hosts", knownHost+" "+strings.TrimSpace(hostKey)+"\n"); err != nil {
+			rs.Close()
+			return nil, errors.Wrap(err, "unable to write the SSH host key")
+		}
+	} else {
+		klog.Warningf("No host key for %s, the identity of the server is not verified", ep.Host)
+	}
+	return rs, nil
+}
+
+// Info is called to get initial information about the data, rsync needs a file to resume transfers into.
+func (rs *RsyncDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (rs *RsyncDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	if err := rs.rsync(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return ProcessingPhaseError, errors.Wrap(err, "unable to open the transferred file")
+	}
+	rs.readers, err = NewFormatReaders(f, uint64(0))
+	if err != nil {
+		f.Close()
+		return ProcessingPhaseError, err
+	}
+	if !rs.readers.Convert {
+		// A raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	if rs.readers.Archived {
+		// qemu-img cannot read compressed files, decompress the image next to the transferred file.
+		decompressed := file + ".img"
+		err = util.StreamDataToFile(rs.readers.TopReader(), decompressed)
+		rs.readers.Close()
+		rs.readers = nil
+		if err != nil {
+			return ProcessingPhaseError, err
+		}
+		os.Remove(file)
+		file = decompressed
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	rs.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (rs *RsyncDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	if rs.readers == nil {
+		return ProcessingPhaseError, errors.New("the file was not transferred to scratch space")
+	}
+	rs.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(rs.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (rs *RsyncDataSource) GetURL() *url.URL {
+	return rs.url
+}
+
+// Close all readers and removes the SSH key and host key files.
+func (rs *RsyncDataSource) Close() error {
+	var err error
+	if rs.readers != nil {
+		err = rs.readers.Close()
+	}
+	if rs.keyFile != "" {
+		os.Remove(rs.keyFile)
+	}
+	if rs.knownHostsFile != "" {
+		os.Remove(rs.knownHostsFile)
+	}
+	return err
+}
+
+// rsync pulls the file, restarting rsync on connection errors. The data received before an error is kept by
+// --partial and serves as the basis of the next attempt, so only the missing blocks are transferred again.
+func (rs *RsyncDataSource) rsync(file string) error {
+	args := []string{"--partial", "--compress", "--protect-args", "--timeout", rsyncTimeout, "--rsh", rs.sshCommand(), rs.remote, file}
+	var err error
+	for attempt := 1; attempt <= rsyncAttempts; attempt++ {
+		if attempt > 1 {
+			klog.Warningf("Transfer interrupted, resuming in %v: %v", rsyncRetryDelay, err)
+			time.Sleep(rsyncRetryDelay)
+		}
+		var stderr bytes.Buffer
+		cmd := exec.Command(rsyncPath, args...)
+		cmd.Stderr = &stderr
+		klog.V(1).Infof("Pulling %s, attempt %d", rs.remote, attempt)
+		runErr := cmd.Run()
+		if runErr == nil {
+			return nil
+		}
+		err = errors.Errorf("rsync failed: %v, %s", runErr, strings.TrimSpace(stderr.String()))
+		exitErr, ok := runErr.(*exec.ExitError)
+		if !ok || !rsyncRetryExitCodes[exitErr.ExitCode()] {
+			return err
+		}
+	}
+	return err
+}
+
+// sshCommand returns the remote shell rsync uses to connect to the server.
+func (rs *RsyncDataSource) sshCommand() string {
+	ssh := []string{"ssh", "-i", rs.keyFile, "-o", "BatchMode=yes", "-o", "ServerAliveInterval=30"}
+	if rs.port != "" {
+		ssh = append(ssh, "-p", rs.port)
+	}
+	if rs.knownHostsFile != "" {
+		ssh = append(ssh, "-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile="+rs.knownHostsFile)
+	} else {
+		ssh = append(ssh, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null")
+	}
+	return strings.Join(ssh, " ")
+}
+
+// writeTempFile writes content to a new file only readable by the importer, and returns its name.
+func writeTempFile(pattern, content string) (string, error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Environment variables set by the EKS pod identity webhook for IAM roles for service accounts
+	awsRoleARNVar              = "AWS_ROLE_ARN"
+	awsWebIdentityTokenFileVar = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	awsRoleSessionNameVar      = "AWS_ROLE_SESSION_NAME"
+	awsRegionVar               = "AWS_REGION"
+	// Environment variables set by the EKS Pod Identity agent
+	awsContainerCredentialsURIVar    = "AWS_CONTAINER_CREDENTIALS_FULL_URI"
+	awsContainerAuthTokenFileVar     = "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"
+	awsContainerAuthTokenVar         = "AWS_CONTAINER_AUTHORIZATION_TOKEN"
+	webIdentityProviderName          = "WebIdentityProvider"
+	containerCredentialsProviderName = "ContainerCredentialsProvider"
+	// refresh the credentials a bit before they expire
+	credentialsExpiryWindow = 5 * time.Minute
+)
+
+type stsClient interface {
+	AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+// webIdentityProvider exchanges the projected service account token for temporary credentials of the IAM role.
+type webIdentityProvider struct {
+	credentials.Expiry
+	client          stsClient
+	roleARN         string
+	roleSessionName string
+	tokenFile       string
+}
+
+// Retrieve reads the token again on every call, since the kubelet rotates the projected token.
+func (p *webIdentityProvider) Retrieve() (credentials.Value, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, errors.Wrapf(err, "unable to read web identity token %s", p.tokenFile)
+	}
+	out, err := p.client.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.roleSessionName),
+		WebIdentityToken: aws.String(strings.TrimSpace(string(token))),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, errors.Wrapf(err, "unable to assume role %s", p.roleARN)
+	}
+	p.SetExpiration(aws.TimeValue(out.Credentials.Expiration), credentialsExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(out.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(out.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(out.Credentials.SessionToken),
+		ProviderName:    webIdentityProviderName,
+	}, nil
+}
+
+type containerCredentialsResponse struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      *time.Time
+}
+
+// containerCredentialsProvider retrieves the credentials from the container credentials endpoint of the pod identity agent.
+type containerCredentialsProvider struct {
+	credentials.Expiry
+	client    *http.Client
+	url       string
+	tokenFile string
+	token     string
+}
+
+// Retrieve reads the authorization token again on every call, since the kubelet rotates the projected token.
+func (p *containerCredentialsProvider) Retrieve() (credentials.Value, error) {
+	token := p.token
+	if p.tokenFile != "" {
+		content, err := ioutil.ReadFile(p.tokenFile)
+		if err != nil {
+			return credentials.Value{ProviderName: containerCredentialsProviderName}, errors.Wrapf(err, "unable to read authorization token %s", p.tokenFile)
+		}
+		token = strings.TrimSpace(string(content))
+	}
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return credentials.Value{ProviderName: containerCredentialsProviderName}, errors.Wrap(err, "could not create credentials request")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return credentials.Value{ProviderName: containerCredentialsProviderName}, errors.Wrap(err, "credentials request errored")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return credentials.Value{ProviderName: containerCredentialsProviderName}, errors.Errorf("expected status code 200 from credentials endpoint, got %d", resp.StatusCode)
+	}
+	out := &containerCredentialsResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(out); err != nil {
+		return credentials.Value{ProviderName: containerCredentialsProviderName}, errors.Wrap(err, "unable to parse credentials response")
+	}
+	if out.Expiration != nil {
+		p.SetExpiration(*out.Expiration, credentialsExpiryWindow)
+	}
+	return credentials.Value{
+		AccessKeyID:     out.AccessKeyID,
+		SecretAccessKey: out.SecretAccessKey,
+		SessionToken:    out.Token,
+		ProviderName:    containerCredentialsProviderName,
+	}, nil
+}
+
+// newAmbientCredentials returns the credentials available to the importer pod without a secret: the IAM role of the
+// service account (IRSA), EKS Pod Identity, the AWS environment variables, and finally the instance role of the node.
+func newAmbientCredentials(region string) (*credentials.Credentials, error) {
+	var providers []credentials.Provider
+	if roleARN, tokenFile := os.Getenv(awsRoleARNVar), os.Getenv(awsWebIdentityTokenFileVar); roleARN != "" && tokenFile != "" {
+		klog.V(1).Infof("Using web identity credentials for role %s", roleARN)
+		// The S3 endpoint may not be AWS, prefer the region of the cluster for STS
+		stsRegion := os.Getenv(awsRegionVar)
+		if stsRegion == "" {
+			stsRegion = region
+		}
+		sess, err := session.NewSession(&aws.Config{
+			Region:      aws.String(stsRegion),
+			Credentials: credentials.AnonymousCredentials,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sessionName := os.Getenv(awsRoleSessionNameVar)
+		if sessionName == "" {
+			sessionName = "cdi-importer-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+		}
+		providers = append(providers, &webIdentityProvider{
+			client:          sts.New(sess),
+			roleARN:         roleARN,
+			roleSessionName: sessionName,
+			tokenFile:       tokenFile,
+		})
+	}
+	if uri := os.Getenv(awsContainerCredentialsURIVar); uri != "" {
+		klog.V(1).Infof("Using container credentials from %s", uri)
+		providers = append(providers, &containerCredentialsProvider{
+			client:    &http.Client{Timeout: time.Minute},
+			url:       uri,
+			tokenFile: os.Getenv(awsContainerAuthTokenFileVar),
+			token:     os.Getenv(awsContainerAuthTokenVar),
+		})
+	}
+	providers = append(providers, defaults.CredProviders(defaults.Config(), defaults.Handlers())...)
+	return credentials.NewCredentials(&credentials.ChainProvider{
+		Providers:     providers,
+		VerboseErrors: true,
+	}), nil
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type fakeSTSClient struct {
+	tokens []string
+	err    error
+}
+
+func (f *fakeSTSClient) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.tokens = append(f.tokens, aws.StringValue(input.WebIdentityToken))
+	return &sts.AssumeRoleWithWebIdentityOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("access"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("session"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+var _ = Describe("S3 ambient credentials", func() {
+	var (
+		tmpDir    string
+		tokenFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "s3-credentials")
+		Expect(err).ToNot(HaveOccurred())
+		tokenFile = filepath.Join(tmpDir, "token")
+		Expect(ioutil.WriteFile(tokenFile, []byte("token1\n"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("web identity provider should exchange the current token", func() {
+		client := &fakeSTSClient{}
+		provider := &webIdentityProvider{client: client, roleARN: "arn:aws:iam::123456789012:role/importer", roleSessionName: "test", tokenFile: tokenFile}
+		value, err := provider.Retrieve()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("access"))
+		Expect(value.SecretAccessKey).To(Equal("secret"))
+		Expect(value.SessionToken).To(Equal("session"))
+		Expect(provider.IsExpired()).To(BeFalse())
+
+		Expect(ioutil.WriteFile(tokenFile, []byte("token2"), 0600)).To(Succeed())
+		_, err = provider.Retrieve()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.tokens).To(Equal([]string{"token1", "token2"}))
+	})
+
+	It("web identity provider should fail when the role cannot be assumed", func() {
+		provider := &webIdentityProvider{client: &fakeSTSClient{err: errors.New("denied")}, roleARN: "role", tokenFile: tokenFile}
+		_, err := provider.Retrieve()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to assume role"))
+	})
+
+	It("web identity provider should fail when the token is missing", func() {
+		provider := &webIdentityProvider{client: &fakeSTSClient{}, roleARN: "role", tokenFile: filepath.Join(tmpDir, "missing")}
+		_, err := provider.Retrieve()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("container credentials provider should pass the authorization token", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "token1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"AccessKeyId":"access","SecretAccessKey":"secret","Token":"session","Expiration":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		}))
+		defer server.Close()
+		provider := &containerCredentialsProvider{client: server.Client(), url: server.URL, tokenFile: tokenFile}
+		value, err := provider.Retrieve()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("access"))
+		Expect(value.SessionToken).To(Equal("session"))
+		Expect(provider.IsExpired()).To(BeFalse())
+
+		provider.tokenFile = ""
+		provider.token = "wrong"
+		_, err = provider.Retrieve()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("ambient credentials should use the web identity when configured", func() {
+		os.Setenv(awsRoleARNVar, "arn:aws:iam::123456789012:role/importer")
+		os.Setenv(awsWebIdentityTokenFileVar, tokenFile)
+		defer os.Unsetenv(awsRoleARNVar)
+		defer os.Unsetenv(awsWebIdentityTokenFileVar)
+		creds, err := newAmbientCredentials("us-east-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(creds).ToNot(BeNil())
+	})
+})
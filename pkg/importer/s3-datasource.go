@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"encoding/base64"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -19,11 +20,39 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
-const s3FolderSep = "/"
+const (
+	s3FolderSep = "/"
+	// the only algorithm supported by SSE-C
+	sseCustomerAlgorithm = "AES256"
+	sseCustomerKeySize   = 32
+)
 
 // S3Client is the interface to the used S3 client.
 type S3Client interface {
 	GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error)
+}
+
+// S3Options are the optional settings of the S3 data source
+type S3Options struct {
+	// Region of the bucket, derived from the endpoint when empty
+	Region string
+	// ForcePathStyle addresses the bucket in the path instead of the host name
+	ForcePathStyle bool
+	// SSECustomerKey is the base64 encoded key the object is encrypted with (SSE-C)
+	SSECustomerKey string
+	// Segments is the number of concurrent ranged requests used to download the object
+	Segments int
+	// SegmentSize is the size of each ranged request, if 0 the object is split evenly over the segments
+	SegmentSize int64
+	// VersionID of the object in a versioned bucket, the latest version when empty
+	VersionID string
+}
+
+// S3SourceValidators identify the content of an S3 object.
+type S3SourceValidators struct {
+	ETag      string
+	VersionID string
 }
 
 // may be overridden in tests
@@ -42,6 +71,16 @@ type S3DataSource struct {
 	secKey string
 	// Reader
 	s3Reader io.ReadCloser
+	// Optional settings
+	options S3Options
+	// S3 client, used for the ranged requests
+	client S3Client
+	// Request of the object
+	objInput *s3.GetObjectInput
+	// Size of the object, 0 if unknown
+	contentLength uint64
+	// ETag of the object, so all segments are read from the same version
+	etag string
 	// stack of readers
 	readers *FormatReaders
 	// The image file in scratch space.
@@ -49,21 +88,21 @@ type S3DataSource struct {
 }
 
 // NewS3DataSource creates a new instance of the S3DataSource
-func NewS3DataSource(endpoint, accessKey, secKey string) (*S3DataSource, error) {
+func NewS3DataSource(endpoint, accessKey, secKey string, options S3Options) (*S3DataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
 	}
-	s3Reader, err := createS3Reader(ep, accessKey, secKey)
-	if err != nil {
-		return nil, err
-	}
-	return &S3DataSource{
+	sd := &S3DataSource{
 		ep:        ep,
 		accessKey: accessKey,
 		secKey:    secKey,
-		s3Reader:  s3Reader,
-	}, nil
+		options:   options,
+	}
+	if err := sd.createS3Reader(); err != nil {
+		return nil, err
+	}
+	return sd, nil
 }
 
 // Info is called to get initial information about the data.
@@ -90,7 +129,8 @@ func (sd *S3DataSource) Transfer(path string) (ProcessingPhase, error) {
 		return ProcessingPhaseError, ErrInvalidPath
 	}
 	file := filepath.Join(path, tempFile)
-	err = util.StreamDataToFile(sd.readers.TopReader(), file)
+	// The download into scratch space resumes when the import is retried
+	err = sd.transferToFile(file, sd.etag)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -101,7 +141,7 @@ func (sd *S3DataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (sd *S3DataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	err := util.StreamDataToFile(sd.readers.TopReader(), fileName)
+	err := sd.transferToFile(fileName, "")
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -122,41 +162,159 @@ func (sd *S3DataSource) Close() error {
 	return err
 }
 
-func createS3Reader(ep *url.URL, accessKey, secKey string) (io.ReadCloser, error) {
+// GetSourceInfo returns the compression of the source and its size as downloaded
+func (sd *S3DataSource) GetSourceInfo() (string, int64) {
+	return sd.readers.compression(), int64(sd.contentLength)
+}
+
+// transferToFile writes the object to the file, a segmented download records its progress when the validator is set
+func (sd *S3DataSource) transferToFile(fileName, validator string) error {
+	if sd.useSegmentedDownload() {
+		// The initial stream is no longer needed, the ranged requests retrieve all the data.
+		if err := sd.readers.Close(); err != nil {
+			klog.V(3).Infof("Unable to close initial reader: %v", err)
+		}
+		return downloadSegments(fileName, sd.contentLength, sd.options.Segments, sd.options.SegmentSize, validator, sd.fetchSegment, nil)
+	}
+	return util.StreamDataToFile(sd.readers.TopReader(), fileName)
+}
+
+// useSegmentedDownload returns true if the object can be downloaded with concurrent ranged requests, the size has
+// to be known and the data should not need to be decompressed.
+func (sd *S3DataSource) useSegmentedDownload() bool {
+	return sd.options.Segments > 1 && sd.contentLength > 0 && sd.readers != nil && !sd.readers.Archived
+}
+
+func (sd *S3DataSource) fetchSegment(r byteRange) (io.ReadCloser, error) {
+	input := *sd.objInput
+	input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", r.start, r.end))
+	if sd.etag != "" {
+		input.IfMatch = aws.String(sd.etag)
+	}
+	objOutput, err := sd.client.GetObject(&input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get range %d-%d of s3 object: \"%s/%s\"", r.start, r.end, aws.StringValue(input.Bucket), aws.StringValue(input.Key))
+	}
+	return objOutput.Body, nil
+}
+
+func (sd *S3DataSource) createS3Reader() error {
 	klog.V(3).Infoln("Using S3 client to get data")
 
-	endpoint := ep.Host
+	endpoint := sd.ep.Host
 	klog.Infof("Endpoint %s", endpoint)
-	path := strings.Trim(ep.Path, "/")
+	path := strings.Trim(sd.ep.Path, "/")
 	bucket, object := extractBucketAndObject(path)
 
 	klog.V(1).Infof("bucket %s", bucket)
 	klog.V(1).Infof("object %s", object)
-	svc, err := newClientFunc(endpoint, accessKey, secKey)
+	svc, err := newClientFunc(endpoint, sd.accessKey, sd.secKey, sd.options)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
+		return errors.Wrapf(err, "could not build s3 client for %q", sd.ep.Host)
 	}
 
 	objInput := &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(object),
 	}
+	if sd.options.VersionID != "" {
+		objInput.VersionId = aws.String(sd.options.VersionID)
+	}
+	if sd.options.SSECustomerKey != "" {
+		key, err := decodeSSECustomerKey(sd.options.SSECustomerKey)
+		if err != nil {
+			return err
+		}
+		// The client encodes the key and adds its MD5 to the request
+		objInput.SSECustomerAlgorithm = aws.String(sseCustomerAlgorithm)
+		objInput.SSECustomerKey = aws.String(key)
+	}
 	objOutput, err := svc.GetObject(objInput)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
+		return errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
 	}
-	objectReader := objOutput.Body
-	return objectReader, nil
+	sd.client = svc
+	sd.objInput = objInput
+	sd.s3Reader = objOutput.Body
+	if length := aws.Int64Value(objOutput.ContentLength); length > 0 {
+		sd.contentLength = uint64(length)
+	}
+	sd.etag = aws.StringValue(objOutput.ETag)
+	return nil
+}
+
+// decodeSSECustomerKey returns the raw customer key needed to read an object encrypted with SSE-C
+func decodeSSECustomerKey(encodedKey string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return "", errors.Wrap(err, "SSE-C customer key is not base64 encoded")
+	}
+	if len(key) != sseCustomerKeySize {
+		return "", errors.Errorf("SSE-C customer key must be %d bytes, got %d", sseCustomerKeySize, len(key))
+	}
+	return string(key), nil
+}
+
+// CheckS3SourceModified compares the ETag and version of the object with the validators of a previous import. It
+// returns false if the object did not change, together with the current validators.
+func CheckS3SourceModified(endpoint, accessKey, secKey string, options S3Options, previous S3SourceValidators) (bool, S3SourceValidators, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return true, S3SourceValidators{}, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
+	}
+	bucket, object := extractBucketAndObject(strings.Trim(ep.Path, "/"))
+	svc, err := newClientFunc(ep.Host, accessKey, secKey, options)
+	if err != nil {
+		return true, S3SourceValidators{}, errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
+	}
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	}
+	if options.VersionID != "" {
+		headInput.VersionId = aws.String(options.VersionID)
+	}
+	if options.SSECustomerKey != "" {
+		key, err := decodeSSECustomerKey(options.SSECustomerKey)
+		if err != nil {
+			return true, S3SourceValidators{}, err
+		}
+		headInput.SSECustomerAlgorithm = aws.String(sseCustomerAlgorithm)
+		headInput.SSECustomerKey = aws.String(key)
+	}
+	klog.V(2).Infof("Checking s3 object \"%s/%s\" for changes\n", bucket, object)
+	headOutput, err := svc.HeadObject(headInput)
+	if err != nil {
+		return true, S3SourceValidators{}, errors.Wrapf(err, "could not get s3 object metadata: \"%s/%s\"", bucket, object)
+	}
+	current := S3SourceValidators{
+		ETag:      aws.StringValue(headOutput.ETag),
+		VersionID: aws.StringValue(headOutput.VersionId),
+	}
+	if previous.ETag == "" || current.ETag != previous.ETag || current.VersionID != previous.VersionID {
+		return true, current, nil
+	}
+	return false, current, nil
 }
 
-func getS3Client(endpoint, accessKey, secKey string) (S3Client, error) {
+func getS3Client(endpoint, accessKey, secKey string, options S3Options) (S3Client, error) {
+	region := options.Region
+	if region == "" {
+		region = extractRegion(endpoint)
+	}
 	creds := credentials.NewStaticCredentials(accessKey, secKey, "")
-	region := extractRegion(endpoint)
+	if accessKey == "" && secKey == "" {
+		// No secret, use the credentials of the pod
+		var err error
+		if creds, err = newAmbientCredentials(region); err != nil {
+			return nil, err
+		}
+	}
 	sess, err := session.NewSession(&aws.Config{
 		Region:           aws.String(region),
 		Endpoint:         aws.String(endpoint),
 		Credentials:      creds,
-		S3ForcePathStyle: aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(options.ForcePathStyle),
 	},
 	)
 	if err != nil {
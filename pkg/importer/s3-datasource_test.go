@@ -1,11 +1,16 @@
 package importer
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -37,19 +42,19 @@ var _ = Describe("S3 data source", func() {
 	})
 
 	It("NewS3DataSource should Error, when passed in an invalid endpoint", func() {
-		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "")
+		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "", S3Options{})
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to create S3 client", func() {
 		newClientFunc = failMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", S3Options{})
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to get object", func() {
 		newClientFunc = createErrMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", S3Options{})
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -59,7 +64,7 @@ var _ = Describe("S3 data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -72,7 +77,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -85,7 +90,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -101,7 +106,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -135,7 +140,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -153,7 +158,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -169,7 +174,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -182,10 +187,137 @@ var _ = Describe("S3 data source", func() {
 	})
 
 	It("GetS3Client should return a real client", func() {
-		_, err := getS3Client("", "", "")
+		_, err := getS3Client("", "", "", S3Options{})
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("NewS3DataSource should pass the SSE-C customer key", func() {
+		var client *MockS3Client
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			client = &MockS3Client{options: options}
+			return client, nil
+		}
+		key := bytes.Repeat([]byte{1}, 32)
+		options := S3Options{Region: "eu-west-1", ForcePathStyle: true, SSECustomerKey: base64.StdEncoding.EncodeToString(key)}
+		sd, err = NewS3DataSource("https://minio.example.com/bucket-1/object-1", "", "", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.options).To(Equal(options))
+		Expect(aws.StringValue(client.input.SSECustomerAlgorithm)).To(Equal("AES256"))
+		Expect(aws.StringValue(client.input.SSECustomerKey)).To(Equal(string(key)))
+	})
+
+	table.DescribeTable("NewS3DataSource should reject an invalid SSE-C customer key", func(key string) {
+		sd, err = NewS3DataSource("https://minio.example.com/bucket-1/object-1", "", "", S3Options{SSECustomerKey: key})
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("not base64", "not a key!"),
+		table.Entry("too short", base64.StdEncoding.EncodeToString([]byte("short"))),
+	)
+
+	It("GetS3Client should honor the region and addressing style", func() {
+		client, err := getS3Client("minio.example.com", "", "", S3Options{Region: "eu-west-1", ForcePathStyle: false})
+		Expect(err).NotTo(HaveOccurred())
+		config := client.(*s3.S3).Client.Config
+		Expect(aws.StringValue(config.Region)).To(Equal("eu-west-1"))
+		Expect(aws.BoolValue(config.S3ForcePathStyle)).To(BeFalse())
+	})
+
+	It("Transfer should download the object in segments", func() {
+		content, err := ioutil.ReadFile(cirrosFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		client := &rangeMockS3Client{content: content, etag: "\"v1\""}
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			return client, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{Segments: 3, SegmentSize: 1024 * 1024})
+		Expect(err).NotTo(HaveOccurred())
+		result, err := sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		result, err = sd.Transfer(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseConvert).To(Equal(result))
+		data, err := ioutil.ReadFile(filepath.Join(tmpDir, tempFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(data, content)).To(BeTrue())
+		Expect(client.ranges).To(HaveLen(len(segmentRanges(uint64(len(content)), 3, 1024*1024))))
+	})
+
+	It("TransferFile should download a raw object in segments", func() {
+		content, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		client := &rangeMockS3Client{content: content, etag: "\"v1\""}
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			return client, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{Segments: 4})
+		Expect(err).NotTo(HaveOccurred())
+		result, err := sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		fileName := filepath.Join(tmpDir, "file")
+		result, err = sd.TransferFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		data, err := ioutil.ReadFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(data, content)).To(BeTrue())
+		Expect(client.ranges).To(HaveLen(4))
+	})
+
+	It("TransferFile should fail when the object changes during a segmented download", func() {
+		content, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		client := &rangeMockS3Client{content: content, etag: "\"v1\""}
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			return client, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{Segments: 2})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		client.etag = "\"v2\""
+		fileName := filepath.Join(tmpDir, "file")
+		result, err := sd.TransferFile(fileName)
+		Expect(err).To(HaveOccurred())
+		Expect(ProcessingPhaseError).To(Equal(result))
+		_, err = os.Stat(fileName)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("NewS3DataSource should request the object version", func() {
+		client := &rangeMockS3Client{content: []byte("data"), etag: "\"v1\"", versionID: "3HL4kqtJlcpXroDTDmJ"}
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			return client, nil
+		}
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", S3Options{VersionID: "3HL4kqtJlcpXroDTDmJ"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.versions).To(Equal([]string{"3HL4kqtJlcpXroDTDmJ"}))
+	})
+
+	table.DescribeTable("CheckS3SourceModified should", func(previous S3SourceValidators, options S3Options, expectedModified, expectErr bool) {
+		client := &rangeMockS3Client{content: []byte("data"), etag: "\"v2\"", versionID: "version2"}
+		newClientFunc = func(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
+			return client, nil
+		}
+		modified, current, err := CheckS3SourceModified("http://region.amazon.com/bucket-1/object-1", "", "", options, previous)
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(Equal(expectedModified))
+		Expect(current).To(Equal(S3SourceValidators{ETag: "\"v2\"", VersionID: "version2"}))
+	},
+		table.Entry("report modified without previous import", S3SourceValidators{}, S3Options{}, true, false),
+		table.Entry("report not modified with the same ETag and version", S3SourceValidators{ETag: "\"v2\"", VersionID: "version2"}, S3Options{}, false, false),
+		table.Entry("report modified with a different ETag", S3SourceValidators{ETag: "\"v1\"", VersionID: "version2"}, S3Options{}, true, false),
+		table.Entry("report modified with a different version", S3SourceValidators{ETag: "\"v2\"", VersionID: "version1"}, S3Options{}, true, false),
+		table.Entry("report not modified for the pinned version", S3SourceValidators{ETag: "\"v2\"", VersionID: "version2"}, S3Options{VersionID: "version2"}, false, false),
+		table.Entry("fail for a missing version", S3SourceValidators{ETag: "\"v2\"", VersionID: "version2"}, S3Options{VersionID: "version1"}, true, true),
+	)
+
 	It("Should Extract Bucket and Object form the S3 URL", func() {
 		bucket, object := extractBucketAndObject("Bucket1/Object.tmp")
 		Expect(bucket).Should(Equal("Bucket1"))
@@ -202,30 +334,87 @@ type MockS3Client struct {
 	endpoint string
 	accKey   string
 	secKey   string
+	options  S3Options
 	doErr    bool
+	input    *s3.GetObjectInput
 }
 
-func failMockS3Client(endpoint, accKey, secKey string) (S3Client, error) {
+func failMockS3Client(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
 	return nil, errors.New("Failed to create client")
 }
 
-func createMockS3Client(endpoint, accKey, secKey string) (S3Client, error) {
+func createMockS3Client(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
 	return &MockS3Client{
-		accKey: accKey,
-		secKey: secKey,
-		doErr:  false,
+		accKey:  accKey,
+		secKey:  secKey,
+		options: options,
+		doErr:   false,
 	}, nil
 }
 
-func createErrMockS3Client(endpoint, accKey, secKey string) (S3Client, error) {
+func createErrMockS3Client(endpoint, accKey, secKey string, options S3Options) (S3Client, error) {
 	return &MockS3Client{
 		doErr: true,
 	}, nil
 }
 
+func (mc *MockS3Client) HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	if !mc.doErr {
+		return &s3.HeadObjectOutput{}, nil
+	}
+	return nil, errors.New("Failed to get object metadata")
+}
+
 func (mc *MockS3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	mc.input = input
 	if !mc.doErr {
 		return &s3.GetObjectOutput{}, nil
 	}
 	return nil, errors.New("Failed to get object")
 }
+
+// rangeMockS3Client serves the content, supporting ranged requests
+type rangeMockS3Client struct {
+	content   []byte
+	etag      string
+	versionID string
+	mutex     sync.Mutex
+	ranges    []string
+	versions  []string
+}
+
+func (mc *rangeMockS3Client) HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if input.VersionId != nil && aws.StringValue(input.VersionId) != mc.versionID {
+		return nil, errors.New("NoSuchVersion")
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(mc.content))),
+		ETag:          aws.String(mc.etag),
+		VersionId:     aws.String(mc.versionID),
+	}, nil
+}
+
+func (mc *rangeMockS3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if input.IfMatch != nil && aws.StringValue(input.IfMatch) != mc.etag {
+		return nil, errors.New("PreconditionFailed")
+	}
+	mc.versions = append(mc.versions, aws.StringValue(input.VersionId))
+	content := mc.content
+	if input.Range != nil {
+		var start, end int
+		if _, err := fmt.Sscanf(aws.StringValue(input.Range), "bytes=%d-%d", &start, &end); err != nil {
+			return nil, err
+		}
+		content = content[start : end+1]
+		mc.ranges = append(mc.ranges, aws.StringValue(input.Range))
+	}
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(bytes.NewReader(content)),
+		ContentLength: aws.Int64(int64(len(content))),
+		ETag:          aws.String(mc.etag),
+	}, nil
+}
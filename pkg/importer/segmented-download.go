@@ -0,0 +1,283 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+// segmentProgressSuffix is the suffix of the progress marker written next to a resumable segmented download
+const segmentProgressSuffix = ".progress"
+
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+// segmentFetcher returns a reader of the passed in range of the source.
+type segmentFetcher func(r byteRange) (io.ReadCloser, error)
+
+// segmentRanges splits the content length into ranges, the end of each range is inclusive.
+func segmentRanges(contentLength uint64, segments int, segmentSize int64) []byteRange {
+	total := int64(contentLength)
+	if segmentSize <= 0 {
+		segmentSize = (total + int64(segments) - 1) / int64(segments)
+	}
+	var ranges []byteRange
+	for start := int64(0); start < total; start += segmentSize {
+		end := start + segmentSize - 1
+		if end >= total {
+			end = total - 1
+		}
+		ranges = append(ranges, byteRange{start: start, end: end})
+	}
+	return ranges
+}
+
+// segmentProgress is the progress marker of a segmented download into a file, it lists the ranges written so far so
+// an interrupted download only fetches the missing ones when retried, as long as the source did not change.
+type segmentProgress struct {
+	ContentLength uint64 `json:"contentLength"`
+	SegmentSize   int64  `json:"segmentSize"`
+	Validator     string `json:"validator"`
+	// Done are the starts of the ranges written to the file
+	Done []int64 `json:"done"`
+
+	fileName string
+	mutex    sync.Mutex
+}
+
+// loadSegmentProgress returns the progress of a previous download of the same source into the file. A download of
+// another source, or one that did not leave a valid marker, is removed so it starts over. That includes a download
+// interrupted before its first segment was recorded, which leaves the file without marker.
+func loadSegmentProgress(fileName string, contentLength uint64, ranges []byteRange, validator string) *segmentProgress {
+	progress := &segmentProgress{
+		ContentLength: contentLength,
+		SegmentSize:   ranges[0].end - ranges[0].start + 1,
+		Validator:     validator,
+		fileName:      fileName,
+	}
+	data, err := ioutil.ReadFile(fileName + segmentProgressSuffix)
+	if os.IsNotExist(err) {
+		// Block devices are never resumed, only leftover files are removed
+		if info, statErr := os.Stat(fileName); statErr == nil && info.Mode().IsRegular() {
+			klog.V(1).Infof("Discarding the previous download into %s, no segment was recorded", fileName)
+			os.Remove(fileName)
+		}
+		return progress
+	}
+	previous := &segmentProgress{}
+	if err == nil {
+		err = json.Unmarshal(data, previous)
+	}
+	info, statErr := os.Stat(fileName)
+	if err != nil || statErr != nil || info.Size() != int64(contentLength) || previous.ContentLength != contentLength ||
+		previous.SegmentSize != progress.SegmentSize || previous.Validator != validator {
+		klog.V(1).Infof("Discarding the previous download into %s, the source or the segments changed", fileName)
+		os.Remove(fileName)
+		os.Remove(fileName + segmentProgressSuffix)
+		return progress
+	}
+	progress.Done = previous.Done
+	return progress
+}
+
+func (p *segmentProgress) done() map[int64]bool {
+	done := make(map[int64]bool, len(p.Done))
+	for _, start := range p.Done {
+		done[start] = true
+	}
+	return done
+}
+
+// segmentDone records the range as written, once its data is synced to the file
+func (p *segmentProgress) segmentDone(outFile *os.File, r byteRange) error {
+	if err := outFile.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync file")
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.Done = append(p.Done, r.start)
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	markerFile := p.fileName + segmentProgressSuffix
+	if err := ioutil.WriteFile(markerFile+".tmp", data, 0600); err != nil {
+		return errors.Wrap(err, "unable to write the download progress")
+	}
+	return os.Rename(markerFile+".tmp", markerFile)
+}
+
+// downloadSegments downloads contentLength bytes into the passed in file or block device, fetching the ranges with
+// the passed in number of concurrent requests. onRead is called with the number of bytes written, it may be nil.
+// When the validator identifying the content of the source is not empty, the progress of a download into a file is
+// recorded next to it, and an interrupted download of the same content resumes from the ranges already written.
+func downloadSegments(fileName string, contentLength uint64, segments int, segmentSize int64, validator string, fetch segmentFetcher, onRead func(int)) error {
+	ranges := segmentRanges(contentLength, segments, segmentSize)
+	var marker *segmentProgress
+	done := map[int64]bool{}
+	if validator != "" && len(ranges) > 0 {
+		marker = loadSegmentProgress(fileName, contentLength, ranges, validator)
+		done = marker.done()
+	}
+	outFile, isBlock, err := openSegmentTarget(fileName, contentLength, len(done) > 0)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+	if isBlock {
+		marker = nil
+	}
+
+	klog.V(1).Infof("Downloading %d bytes in %d segments using %d connections, %d segments already downloaded\n", contentLength, len(ranges), segments, len(done))
+	promReader := prometheusutil.NewProgressReader(nil, contentLength, progress, ownerUID)
+	promReader.SetTransferMetrics(transferMetrics)
+	promReader.StartTimedUpdate()
+	defer func() {
+		promReader.Done = true
+	}()
+	countBytes := func(n int) {
+		atomic.AddUint64(&promReader.Current, uint64(n))
+		if onRead != nil {
+			onRead(n)
+		}
+	}
+
+	work := make(chan byteRange, len(ranges))
+	for _, r := range ranges {
+		if done[r.start] {
+			atomic.AddUint64(&promReader.Current, uint64(r.end-r.start+1))
+			continue
+		}
+		work <- r
+	}
+	close(work)
+	errs := make(chan error, segments)
+	var wg sync.WaitGroup
+	for i := 0; i < segments; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range work {
+				err := downloadSegment(fetch, outFile, r, countBytes)
+				if err == nil && marker != nil {
+					err = marker.segmentDone(outFile, r)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		// Keep the ranges written so far when the progress is recorded, a retry resumes the download
+		if !isBlock && marker == nil {
+			os.Remove(outFile.Name())
+		}
+		return err
+	}
+	if err := outFile.Sync(); err != nil {
+		return err
+	}
+	if marker != nil {
+		os.Remove(fileName + segmentProgressSuffix)
+	}
+	return nil
+}
+
+// openSegmentTarget opens the block device, or creates the file with the final size so the segments can be written
+// at their offset. The file of a resumed download is opened as is.
+func openSegmentTarget(fileName string, contentLength uint64, resume bool) (*os.File, bool, error) {
+	if resume {
+		outFile, err := os.OpenFile(fileName, os.O_WRONLY, os.ModePerm)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "could not open file %q", fileName)
+		}
+		return outFile, false, nil
+	}
+	blockSize, err := util.GetAvailableSpaceBlock(fileName)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "error determining if block device exists")
+	}
+	if blockSize >= 0 {
+		if uint64(blockSize) < contentLength {
+			return nil, true, errors.Errorf("block device %q is too small, %d bytes available, need %d", fileName, blockSize, contentLength)
+		}
+		outFile, err := os.OpenFile(fileName, os.O_EXCL|os.O_WRONLY, os.ModePerm)
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "could not open file %q", fileName)
+		}
+		return outFile, true, nil
+	}
+	outFile, err := os.OpenFile(fileName, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	if err := outFile.Truncate(int64(contentLength)); err != nil {
+		outFile.Close()
+		return nil, false, errors.Wrapf(err, "unable to size file %q", fileName)
+	}
+	return outFile, false, nil
+}
+
+func downloadSegment(fetch segmentFetcher, outFile *os.File, r byteRange, onRead func(int)) error {
+	body, err := fetch(r)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	buf := make([]byte, 32*1024)
+	offset := r.start
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if offset+int64(n) > r.end+1 {
+				return errors.Errorf("server returned more data than requested for range %d-%d", r.start, r.end)
+			}
+			if _, werr := outFile.WriteAt(buf[:n], offset); werr != nil {
+				return errors.Wrap(werr, "unable to write to file")
+			}
+			offset += int64(n)
+			onRead(n)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "unable to read segment")
+		}
+	}
+	if offset != r.end+1 {
+		return errors.Errorf("incomplete segment, got %d bytes, expected %d", offset-r.start, r.end-r.start+1)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// SMBDataSource is the data provider for image files on SMB/CIFS shares, for instance ISO or template libraries on
+// Windows file servers. The file is streamed from the share with smbclient.
+// Sequence of phases:
+// 1a. Info -> TransferScratch if the file needs conversion
+// 1b. Info -> TransferDataFile if the file is a raw image, compressed or not
+// 2. Transfer -> Convert
+type SMBDataSource struct {
+	location *smbLocation
+	// reader of the file on the share
+	smbReader *smbReader
+	// stack of readers
+	readers *FormatReaders
+	// url the url to report to the caller of getURL, a file in scratch space.
+	url *url.URL
+}
+
+// NewSMBDataSource creates a new instance of the SMB data provider, the endpoint is an smb://server/share/path URL.
+func NewSMBDataSource(endpoint, user, password, domain string) (*SMBDataSource, error) {
+	location, err := parseSMBURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := newSMBReader(location, user, password, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &SMBDataSource{
+		location:  location,
+		smbReader: reader,
+	}, nil
+}
+
+// Info is called to get initial information about the data.
+func (ss *SMBDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	ss.readers, err = NewFormatReaders(ss.smbReader, uint64(0))
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if !ss.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a scratch location.
+func (ss *SMBDataSource) Transfer(path string) (ProcessingPhase, error) {
+	size, _ := util.GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	file := filepath.Join(path, tempFile)
+	err := util.StreamDataToFile(ss.readers.TopReader(), file)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ss.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (ss *SMBDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
+	ss.readers.StartProgressUpdate()
+	err := util.StreamDataToFile(ss.readers.TopReader(), fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns the URI that the data processor can use when converting the data.
+func (ss *SMBDataSource) GetURL() *url.URL {
+	return ss.url
+}
+
+// Close all readers.
+func (ss *SMBDataSource) Close() error {
+	var err error
+	if ss.readers != nil {
+		err = ss.readers.Close()
+	}
+	if closeErr := ss.smbReader.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SMB data source", func() {
+	var (
+		tmpDir        string
+		scratchDir    string
+		origSmbclient string
+		ss            *SMBDataSource
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "smb")
+		Expect(err).ToNot(HaveOccurred())
+		scratchDir = filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0755)).To(Succeed())
+		// The fake smbclient records its arguments and authentication file, and writes the file to stdout
+		script := fmt.Sprintf(`#!/bin/sh
+printf "%%s\n" "$*" > %[1]s/args
+for arg; do
+	case "$arg" in
+	--authentication-file=*) cp "${arg#--authentication-file=}" %[1]s/auth;;
+	esac
+done
+if [ -f %[1]s/fail ]; then
+	echo "NT_STATUS_ACCESS_DENIED opening remote file" >&2
+	exit 1
+fi
+cat %[1]s/file
+`, tmpDir)
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "smbclient"), []byte(script), 0755)).To(Succeed())
+		origSmbclient = smbclientPath
+		smbclientPath = filepath.Join(tmpDir, "smbclient")
+		ss = nil
+	})
+
+	AfterEach(func() {
+		if ss != nil {
+			ss.Close()
+		}
+		smbclientPath = origSmbclient
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should stream a qcow2 image to scratch space for conversion", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), cirrosData, 0644)).To(Succeed())
+		var err error
+		ss, err = NewSMBDataSource("smb://fileserver/images/templates/cirros.qcow2", "admin", "secret", "CORP")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ss.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ss.Transfer(scratchDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ss.GetURL().String()).To(Equal(filepath.Join(scratchDir, tempFile)))
+		content, err := ioutil.ReadFile(filepath.Join(scratchDir, tempFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(cirrosData))
+
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(HavePrefix("//fileserver/images --authentication-file="))
+		Expect(string(args)).To(HaveSuffix(" -E -c get \"templates\\cirros.qcow2\" /dev/stdout\n"))
+		auth, err := ioutil.ReadFile(filepath.Join(tmpDir, "auth"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(auth)).To(Equal("username = admin\npassword = secret\ndomain = CORP\n"))
+	})
+
+	It("should write a raw image to the target", func() {
+		expected, err := ioutil.ReadFile(tinyCoreFilePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), expected, 0644)).To(Succeed())
+		ss, err = NewSMBDataSource("smb://fileserver/images/ISO/tinyCore.iso", "", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		phase, err := ss.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = ss.TransferFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		content, err := ioutil.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(expected))
+		args, err := ioutil.ReadFile(filepath.Join(tmpDir, "args"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(args)).To(HavePrefix("//fileserver/images --no-pass -E"))
+	})
+
+	It("should fail the transfer with the error of smbclient", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "fail"), nil, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "file"), nil, 0644)).To(Succeed())
+		var err error
+		ss, err = NewSMBDataSource("smb://fileserver/images/denied.qcow2", "admin", "secret", "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = ss.Info()
+		if err == nil {
+			_, err = ss.TransferFile(filepath.Join(tmpDir, "disk.img"))
+		}
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("NT_STATUS_ACCESS_DENIED"))
+	})
+
+	table.DescribeTable("should reject", func(endpoint, message string) {
+		_, err := NewSMBDataSource(endpoint, "admin", "secret", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+	},
+		table.Entry("URLs without a file", "smb://fileserver/images", "has no share and file path"),
+		table.Entry("other URLs", "nfs://fileserver/images/fedora.qcow2", "is not an smb:// URL"),
+	)
+})
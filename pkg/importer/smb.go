@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// smbclientPath is the smbclient binary used to read files from SMB shares, a variable for testing.
+var smbclientPath = "smbclient"
+
+// smbLocation is a file on an SMB share.
+type smbLocation struct {
+	server string
+	share  string
+	// path of the file in the share, with backslash separators
+	path string
+}
+
+// parseSMBURL parses an smb://server/share/path URL.
+func parseSMBURL(endpoint string) (*smbLocation, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", endpoint)
+	}
+	if u.Scheme != "smb" || u.Host == "" {
+		return nil, errors.Errorf("%s is not an smb:// URL", endpoint)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("%s has no share and file path", endpoint)
+	}
+	if strings.ContainsAny(parts[1], "\"\n") {
+		return nil, errors.Errorf("invalid file path %s", parts[1])
+	}
+	return &smbLocation{
+		server: u.Host,
+		share:  parts[0],
+		path:   strings.ReplaceAll(parts[1], "/", "\\"),
+	}, nil
+}
+
+func (l *smbLocation) String() string {
+	return fmt.Sprintf("//%s/%s/%s", l.server, l.share, strings.ReplaceAll(l.path, "\\", "/"))
+}
+
+// smbReader streams a file from an SMB share with smbclient, the credentials are passed in an authentication file so
+// they do not show in the arguments of the process.
+type smbReader struct {
+	cmd      *exec.Cmd
+	stdout   io.ReadCloser
+	stderr   bytes.Buffer
+	authFile string
+	done     bool
+	waitErr  error
+}
+
+// newSMBReader starts reading the file, a guest session is used without user.
+func newSMBReader(location *smbLocation, user, password, domain string) (*smbReader, error) {
+	r := &smbReader{}
+	args := []string{"//" + location.server + "/" + location.share}
+	if user != "" {
+		auth, err := ioutil.TempFile("", "smb-auth")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create the smbclient authentication file")
+		}
+		r.authFile = auth.Name()
+		content := fmt.Sprintf("username = %s\npassword = %s\n", user, password)
+		if domain != "" {
+			content += fmt.Sprintf("domain = %s\n", domain)
+		}
+		_, err = auth.WriteString(content)
+		auth.Close()
+		if err != nil {
+			r.Close()
+			return nil, errors.Wrap(err, "unable to write the smbclient authentication file")
+		}
+		args = append(args, "--authentication-file="+r.authFile)
+	} else {
+		args = append(args, "--no-pass")
+	}
+	// Messages go to stderr, stdout only has the data of the file
+	args = append(args, "-E", "-c", fmt.Sprintf("get \"%s\" /dev/stdout", location.path))
+	r.cmd = exec.Command(smbclientPath, args...)
+	r.cmd.Stderr = &r.stderr
+	var err error
+	if r.stdout, err = r.cmd.StdoutPipe(); err != nil {
+		r.Close()
+		return nil, errors.Wrap(err, "unable to read the output of smbclient")
+	}
+	if err := r.cmd.Start(); err != nil {
+		r.Close()
+		return nil, errors.Wrap(err, "unable to start smbclient")
+	}
+	klog.V(1).Infof("Reading %s", location)
+	return r, nil
+}
+
+// Read reads the data of the file, the exit status of smbclient is checked at the end of the data.
+func (r *smbReader) Read(p []byte) (int, error) {
+	n, err := r.stdout.Read(p)
+	if err == io.EOF {
+		if waitErr := r.wait(); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *smbReader) wait() error {
+	if r.done {
+		return r.waitErr
+	}
+	r.done = true
+	if err := r.cmd.Wait(); err != nil {
+		r.waitErr = errors.Errorf("smbclient failed: %v, %s", err, strings.TrimSpace(r.stderr.String()))
+	}
+	return r.waitErr
+}
+
+// Close stops smbclient if the file was not read completely and removes the authentication file.
+func (r *smbReader) Close() error {
+	if r.cmd != nil && r.cmd.Process != nil && !r.done {
+		r.cmd.Process.Kill()
+		r.wait()
+	}
+	if r.authFile != "" {
+		os.Remove(r.authFile)
+	}
+	return nil
+}
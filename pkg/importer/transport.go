@@ -19,25 +19,58 @@ package importer
 import (
 	"archive/tar"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/containers/image/v5/docker"
 	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
 	"github.com/containers/image/v5/oci/archive"
 	"github.com/containers/image/v5/pkg/blobinfocache"
 	"github.com/containers/image/v5/types"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 const (
 	whFilePrefix = ".wh."
+	// artifactFileName is the name of the disk image of an OCI artifact without title
+	artifactFileName = "disk.img"
+	// artifactTitleAnnotation is the annotation of the layers of OCI artifacts with the name of their file
+	artifactTitleAnnotation = "org.opencontainers.image.title"
 )
 
+// artifactDiskExtensions are the extensions of the disk images, to find the disk image of OCI artifacts with several files
+var artifactDiskExtensions = []string{".qcow2", ".img", ".raw", ".iso", ".vmdk", ".vhd", ".vhdx"}
+
+// registriesConfPath is the registries.conf file with the mirrors of the CDIConfig, empty to use the system one
+var registriesConfPath string
+
+// SetRegistriesConfig writes the mirrors and insecure registries of the CDIConfig to a registries.conf file consulted
+// by the registry imports.
+func SetRegistriesConfig(registries []cdiv1.RegistryConfig) error {
+	var conf strings.Builder
+	for _, registry := range registries {
+		fmt.Fprintf(&conf, "[[registry]]\nprefix = %s\nlocation = %s\ninsecure = %t\n\n", strconv.Quote(registry.Registry), strconv.Quote(registry.Registry), registry.Insecure)
+		for _, mirror := range registry.Mirrors {
+			fmt.Fprintf(&conf, "[[registry.mirror]]\nlocation = %s\ninsecure = %t\n\n", strconv.Quote(mirror.Location), mirror.Insecure)
+		}
+	}
+	path, err := writeTempFile("registries.conf", conf.String())
+	if err != nil {
+		return errors.Wrap(err, "unable to write the registries configuration")
+	}
+	registriesConfPath = path
+	return nil
+}
+
 func commandTimeoutContext() (context.Context, context.CancelFunc) {
 	return context.WithCancel(context.Background())
 }
@@ -55,6 +88,10 @@ func buildSourceContext(accessKey, secKey, certDir string, insecureRegistry bool
 		ctx.DockerDaemonCertPath = certDir
 	}
 
+	if registriesConfPath != "" {
+		ctx.SystemRegistriesConfPath = registriesConfPath
+	}
+
 	if insecureRegistry {
 		ctx.DockerDaemonInsecureSkipTLSVerify = true
 		ctx.DockerInsecureSkipTLSVerify = types.NewOptionalBool(true)
@@ -63,6 +100,21 @@ func buildSourceContext(accessKey, secKey, certDir string, insecureRegistry bool
 	return ctx
 }
 
+// setPlatform sets the os/arch[/variant] platform selecting the image of a manifest list, by default the image of the
+// platform of the importer, and so of its node, is selected.
+func setPlatform(ctx *types.SystemContext, platform string) error {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("Invalid platform %q, expected os/arch[/variant]", platform)
+	}
+	ctx.OSChoice = parts[0]
+	ctx.ArchitectureChoice = parts[1]
+	if len(parts) == 3 {
+		ctx.VariantChoice = parts[2]
+	}
+	return nil
+}
+
 func readImageSource(ctx context.Context, sys *types.SystemContext, img string) (types.ImageSource, error) {
 	ref, err := parseImageName(img)
 	if err != nil {
@@ -169,12 +221,117 @@ func processLayer(ctx context.Context,
 	return found, nil
 }
 
-func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry, stopAtFirst bool) error {
+// isTarLayer returns true for the layers of container images, the layers of OCI artifacts, for instance pushed with
+// ORAS, have the media types of their files.
+func isTarLayer(layer types.BlobInfo) bool {
+	return layer.MediaType == "" || strings.Contains(layer.MediaType, ".tar")
+}
+
+// artifactTitle returns the file name of a layer of an OCI artifact
+func artifactTitle(layer types.BlobInfo) string {
+	title := filepath.Base(layer.Annotations[artifactTitleAnnotation])
+	if title == "." || title == "/" || title == ".." {
+		return ""
+	}
+	return title
+}
+
+// findArtifactLayer returns the layer holding the disk image if the image is an OCI artifact, nil if it is a container
+// image. The disk image is the only file of the artifact, or its only file with the extension of a disk image.
+func findArtifactLayer(layers []types.BlobInfo) (*types.BlobInfo, error) {
+	var disks []types.BlobInfo
+	for _, layer := range layers {
+		if isTarLayer(layer) {
+			return nil, nil
+		}
+		ext := filepath.Ext(strings.TrimSuffix(strings.TrimSuffix(artifactTitle(layer), ".gz"), ".xz"))
+		for _, diskExt := range artifactDiskExtensions {
+			if strings.EqualFold(ext, diskExt) {
+				disks = append(disks, layer)
+				break
+			}
+		}
+	}
+	if len(layers) == 1 {
+		return &layers[0], nil
+	}
+	if len(disks) != 1 {
+		return nil, errors.Errorf("Found %d disk images in the %d files of the OCI artifact, expected one", len(disks), len(layers))
+	}
+	return &disks[0], nil
+}
+
+// artifactName returns the name of the disk image of an OCI artifact
+func artifactName(layer types.BlobInfo) string {
+	if name := artifactTitle(layer); name != "" {
+		return name
+	}
+	return artifactFileName
+}
+
+// copyArtifactLayer copies the disk image of an OCI artifact to the destination directory, decompressing it if needed
+func copyArtifactLayer(ctx context.Context, src types.ImageSource, layer types.BlobInfo, destDir string, cache types.BlobInfoCache) error {
+	name := artifactName(layer)
+	klog.Infof("Copying the file '%s' of media type '%s' of the OCI artifact", name, layer.MediaType)
+	reader, _, err := src.GetBlob(ctx, layer, cache)
+	if err != nil {
+		klog.Errorf("Could not read artifact: %v", err)
+		return errors.Wrap(err, "Could not read artifact")
+	}
+	fr, err := NewFormatReaders(reader, 0)
+	if err != nil {
+		return errors.Wrap(err, "Could not read artifact")
+	}
+	defer fr.Close()
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		klog.Errorf("Error creating output file's directory: %v", err)
+		return errors.Wrap(err, "Error creating output file's directory")
+	}
+	// The format readers decompress the file
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".xz")
+	if err := util.StreamDataToFile(fr.TopReader(), filepath.Join(destDir, name)); err != nil {
+		klog.Errorf("Error copying file: %v", err)
+		return errors.Wrap(err, "Error copying file")
+	}
+	return nil
+}
+
+// GetRegistryImageDigest returns the digest of the manifest the registry image points to, the digest of the manifest
+// list of multi-platform images, so an import of the image by digest still selects the image of its platform.
+func GetRegistryImageDigest(url, accessKey, secKey, certDir string, insecureRegistry bool) (string, error) {
+	ctx, cancel := commandTimeoutContext()
+	defer cancel()
+	srcCtx := buildSourceContext(accessKey, secKey, certDir, insecureRegistry)
+
+	src, err := readImageSource(ctx, srcCtx, url)
+	if err != nil {
+		return "", err
+	}
+	defer closeImage(src)
+
+	rawManifest, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		klog.Errorf("Could not read the image manifest: %v", err)
+		return "", errors.Wrap(err, "Could not read the image manifest")
+	}
+	digest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute the digest of the image manifest")
+	}
+	return digest.String(), nil
+}
+
+func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, platform string, insecureRegistry, stopAtFirst bool) error {
 	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", url, pathPrefix, destDir)
 
 	ctx, cancel := commandTimeoutContext()
 	defer cancel()
 	srcCtx := buildSourceContext(accessKey, secKey, certDir, insecureRegistry)
+	if platform != "" {
+		if err := setPlatform(srcCtx, platform); err != nil {
+			return err
+		}
+	}
 
 	src, err := readImageSource(ctx, srcCtx, url)
 	if err != nil {
@@ -189,10 +346,24 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 	}
 	defer imgCloser.Close()
 
+	if signaturePolicy != nil {
+		if err := verifyImageSignature(ctx, srcCtx, src, signaturePolicy); err != nil {
+			return err
+		}
+	}
+
 	cache := blobinfocache.DefaultCache(srcCtx)
 	found := false
 	layers := imgCloser.LayerInfos()
 
+	artifact, err := findArtifactLayer(layers)
+	if err != nil {
+		return err
+	}
+	if artifact != nil {
+		return copyArtifactLayer(ctx, src, *artifact, filepath.Join(destDir, pathPrefix), cache)
+	}
+
 	for _, layer := range layers {
 		klog.Infof("Processing layer %+v", layer)
 
@@ -215,6 +386,150 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 	return nil
 }
 
+// registryFileReader streams a file out of a layer of a registry image, closing it closes the layer and the image.
+type registryFileReader struct {
+	io.Reader
+	closers []io.Closer
+	cancel  context.CancelFunc
+	// size is the size of the file recorded in the layer, or the size of the artifact
+	size int64
+}
+
+// Close closes the layer and the image in reverse order of opening.
+func (r *registryFileReader) Close() error {
+	var err error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if closeErr := r.closers[i].Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	r.closers = nil
+	r.cancel()
+	return err
+}
+
+// findLayerFile positions the tar reader of the layer at its first file under the pathPrefix and returns the tar header
+// of the file, it returns a nil reader if the layer has no such file.
+func findLayerFile(ctx context.Context, src types.ImageSource, layer types.BlobInfo, pathPrefix string, cache types.BlobInfoCache) (io.Reader, io.Closer, *tar.Header, error) {
+	reader, _, err := src.GetBlob(ctx, layer, cache)
+	if err != nil {
+		klog.Errorf("Could not read layer: %v", err)
+		return nil, nil, nil, errors.Wrap(err, "Could not read layer")
+	}
+	fr, err := NewFormatReaders(reader, 0)
+	if err != nil {
+		fr.Close()
+		return nil, nil, nil, errors.Wrap(err, "Could not read layer")
+	}
+	tarReader := tar.NewReader(fr.TopReader())
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			fr.Close()
+			return nil, nil, nil, nil
+		}
+		if err != nil {
+			klog.Errorf("Error reading layer: %v", err)
+			fr.Close()
+			return nil, nil, nil, errors.Wrap(err, "Error reading layer")
+		}
+		if hasPrefix(hdr.Name, pathPrefix) && !isWhiteout(hdr.Name) && !isDir(hdr.Name) {
+			klog.Infof("File '%v' found in the layer", hdr.Name)
+			return tarReader, fr, hdr, nil
+		}
+	}
+}
+
+// OpenRegistryImageFile streams the first file under the pathPrefix out of the layers of a registry image, or the disk
+// image of an OCI artifact, as the layers download. Nothing is written to disk, the file is read only once.
+// It returns the reader of the file and its name, the reader must be closed to release the image.
+// url: source registry url.
+// pathPrefix: path to stream the file from.
+// accessKey: accessKey for the registry described in url.
+// secKey: secretKey for the registry described in url.
+// certDir: directory public CA keys are stored for registry identity verification
+// platform: os/arch[/variant] selecting the image of a manifest list, the platform of the importer if empty.
+// insecureRegistry: boolean if true will allow insecure registries.
+func OpenRegistryImageFile(url, pathPrefix, accessKey, secKey, certDir, platform string, insecureRegistry bool) (io.ReadCloser, string, error) {
+	klog.Infof("Streaming image from '%v', reading file from '%v'", url, pathPrefix)
+
+	ctx, cancel := commandTimeoutContext()
+	r := &registryFileReader{cancel: cancel}
+	srcCtx := buildSourceContext(accessKey, secKey, certDir, insecureRegistry)
+	if platform != "" {
+		if err := setPlatform(srcCtx, platform); err != nil {
+			r.Close()
+			return nil, "", err
+		}
+	}
+
+	src, err := readImageSource(ctx, srcCtx, url)
+	if err != nil {
+		r.Close()
+		return nil, "", err
+	}
+	r.closers = append(r.closers, src)
+
+	imgCloser, err := image.FromSource(ctx, srcCtx, src)
+	if err != nil {
+		klog.Errorf("Error retrieving image: %v", err)
+		r.Close()
+		return nil, "", errors.Wrap(err, "Error retrieving image")
+	}
+	r.closers = append(r.closers, imgCloser)
+
+	if signaturePolicy != nil {
+		if err := verifyImageSignature(ctx, srcCtx, src, signaturePolicy); err != nil {
+			r.Close()
+			return nil, "", err
+		}
+	}
+
+	cache := blobinfocache.DefaultCache(srcCtx)
+	layers := imgCloser.LayerInfos()
+
+	artifact, err := findArtifactLayer(layers)
+	if err != nil {
+		r.Close()
+		return nil, "", err
+	}
+	if artifact != nil {
+		name := artifactName(*artifact)
+		klog.Infof("Streaming the file '%s' of media type '%s' of the OCI artifact", name, artifact.MediaType)
+		reader, _, err := src.GetBlob(ctx, *artifact, cache)
+		if err != nil {
+			klog.Errorf("Could not read artifact: %v", err)
+			r.Close()
+			return nil, "", errors.Wrap(err, "Could not read artifact")
+		}
+		r.Reader = reader
+		r.closers = append(r.closers, reader)
+		r.size = artifact.Size
+		return r, name, nil
+	}
+
+	for _, layer := range layers {
+		klog.Infof("Processing layer %+v", layer)
+
+		reader, closer, hdr, err := findLayerFile(ctx, src, layer, pathPrefix, cache)
+		if err != nil {
+			// Skipping layer and trying the next one.
+			// Error already logged in findLayerFile
+			continue
+		}
+		if reader != nil {
+			r.Reader = reader
+			r.closers = append(r.closers, closer)
+			r.size = hdr.Size
+			return r, hdr.Name, nil
+		}
+	}
+
+	r.Close()
+	klog.Errorf("Failed to find VM disk image file in the container image")
+	return nil, "", errors.New("Failed to find VM disk image file in the container image")
+}
+
 // CopyRegistryImage download image from registry with docker image API. It will extract first file under the pathPrefix
 // url: source registry url.
 // destDir: the scratch space destination.
@@ -222,9 +537,10 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 // accessKey: accessKey for the registry described in url.
 // secKey: secretKey for the registry described in url.
 // certDir: directory public CA keys are stored for registry identity verification
+// platform: os/arch[/variant] selecting the image of a manifest list, the platform of the importer if empty.
 // insecureRegistry: boolean if true will allow insecure registries.
-func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry bool) error {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, true)
+func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, platform string, insecureRegistry bool) error {
+	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, platform, insecureRegistry, true)
 }
 
 // CopyRegistryImageAll download image from registry with docker image API. It will extract all files under the pathPrefix
@@ -234,7 +550,8 @@ func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 // accessKey: accessKey for the registry described in url.
 // secKey: secretKey for the registry described in url.
 // certDir: directory public CA keys are stored for registry identity verification
+// platform: os/arch[/variant] selecting the image of a manifest list, the platform of the importer if empty.
 // insecureRegistry: boolean if true will allow insecure registries.
-func CopyRegistryImageAll(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry bool) error {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, false)
+func CopyRegistryImageAll(url, destDir, pathPrefix, accessKey, secKey, certDir, platform string, insecureRegistry bool) error {
+	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, platform, insecureRegistry, false)
 }
@@ -20,8 +20,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/containers/image/v5/pkg/sysregistriesv2"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 )
 
 var _ = Describe("Registry Importer", func() {
@@ -40,14 +43,14 @@ var _ = Describe("Registry Importer", func() {
 	})
 
 	It("Should extract a single file", func() {
-		err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", false)
+		err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", "", false)
 		Expect(err).ToNot(HaveOccurred())
 
 		file := filepath.Join(tmpDir, "disk/cirros-0.3.4-x86_64-disk.img")
 		Expect(file).To(BeARegularFile())
 	})
 	It("Should extract files prefixed by path", func() {
-		err := CopyRegistryImageAll(source, tmpDir, "etc/", "", "", "", false)
+		err := CopyRegistryImageAll(source, tmpDir, "etc/", "", "", "", "", false)
 		Expect(err).ToNot(HaveOccurred())
 
 		file := filepath.Join(tmpDir, "etc/hosts")
@@ -57,7 +60,7 @@ var _ = Describe("Registry Importer", func() {
 		Expect(file).To(BeARegularFile())
 	})
 	It("Should return an error if a single file is not found", func() {
-		err := CopyRegistryImage(source, tmpDir, "disk/invalid.img", "", "", "", false)
+		err := CopyRegistryImage(source, tmpDir, "disk/invalid.img", "", "", "", "", false)
 		Expect(err).To(HaveOccurred())
 
 		file := filepath.Join(tmpDir, "disk/cirros-0.3.4-x86_64-disk.img")
@@ -65,7 +68,62 @@ var _ = Describe("Registry Importer", func() {
 		Expect(err).To(HaveOccurred())
 	})
 	It("Should return an error if no files matches a prefix", func() {
-		err := CopyRegistryImageAll(source, tmpDir, "invalid/", "", "", "", false)
+		err := CopyRegistryImageAll(source, tmpDir, "invalid/", "", "", "", "", false)
 		Expect(err).To(HaveOccurred())
 	})
+	It("Should return the digest of the image manifest", func() {
+		digest, err := GetRegistryImageDigest(source, "", "", "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(MatchRegexp("^sha256:[0-9a-f]{64}$"))
+
+		again, err := GetRegistryImageDigest(source, "", "", "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again).To(Equal(digest))
+	})
+})
+
+var _ = Describe("Registries config", func() {
+	AfterEach(func() {
+		if registriesConfPath != "" {
+			os.Remove(registriesConfPath)
+			registriesConfPath = ""
+		}
+	})
+
+	It("Should consult the mirrors of the CDIConfig", func() {
+		err := SetRegistriesConfig([]cdiv1.RegistryConfig{
+			{
+				Registry: "quay.io/kubevirt",
+				Mirrors: []cdiv1.RegistryMirror{
+					{Location: "mirror.example.com:5000/kubevirt", Insecure: true},
+					{Location: "backup.example.com/quay/kubevirt"},
+				},
+			},
+			{Registry: "registry.example.com:5000", Insecure: true},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		ctx := buildSourceContext("", "", "", false)
+		Expect(ctx.SystemRegistriesConfPath).To(Equal(registriesConfPath))
+
+		registry, err := sysregistriesv2.FindRegistry(ctx, "quay.io/kubevirt/fedora-cloud-container-disk-demo:latest")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry).ToNot(BeNil())
+		Expect(registry.Location).To(Equal("quay.io/kubevirt"))
+		Expect(registry.Mirrors).To(Equal([]sysregistriesv2.Endpoint{
+			{Location: "mirror.example.com:5000/kubevirt", Insecure: true},
+			{Location: "backup.example.com/quay/kubevirt"},
+		}))
+		registry, err = sysregistriesv2.FindRegistry(ctx, "registry.example.com:5000/disks/cirros:latest")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry).ToNot(BeNil())
+		Expect(registry.Insecure).To(BeTrue())
+		Expect(registry.Mirrors).To(BeEmpty())
+		registry, err = sysregistriesv2.FindRegistry(ctx, "docker.io/library/fedora:latest")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry).To(BeNil())
+	})
+
+	It("Should use the system configuration by default", func() {
+		Expect(buildSourceContext("", "", "", false).SystemRegistriesConfPath).To(BeEmpty())
+	})
 })
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// interval at which the merged certificates are refreshed from the cert dirs.
+var certReloadInterval = 30 * time.Second
+
+// MergeCertDirs merges the certificates found in the cert dirs into a single tls.crt in a new directory, so the
+// directory can be used everywhere a cert dir is expected. The merged certificates are refreshed when the content of
+// the cert dirs changes, until stopCh is closed. Returns the merged directory.
+func MergeCertDirs(stopCh <-chan struct{}, certDirs ...string) (string, error) {
+	content, err := readCertDirs(certDirs)
+	if err != nil {
+		return "", err
+	}
+	mergedDir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to create merged cert dir")
+	}
+	if err := writeMergedCerts(mergedDir, content); err != nil {
+		return "", err
+	}
+
+	go wait.Until(func() {
+		newContent, err := readCertDirs(certDirs)
+		if err != nil {
+			klog.Warningf("Unable to refresh certificates: %v", err)
+			return
+		}
+		if bytes.Equal(newContent, content) {
+			return
+		}
+		klog.Infof("Certificates changed, updating %s", mergedDir)
+		if err := writeMergedCerts(mergedDir, newContent); err != nil {
+			klog.Warningf("Unable to refresh certificates: %v", err)
+			return
+		}
+		content = newContent
+	}, certReloadInterval, stopCh)
+
+	return mergedDir, nil
+}
+
+func readCertDirs(certDirs []string) ([]byte, error) {
+	var content bytes.Buffer
+	for _, certDir := range certDirs {
+		if certDir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(certDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing files in %s", certDir)
+		}
+		for _, file := range files {
+			if file.IsDir() || file.Name()[0] == '.' {
+				continue
+			}
+			fp := filepath.Join(certDir, file.Name())
+			certs, err := ioutil.ReadFile(fp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error reading file %s", fp)
+			}
+			content.Write(certs)
+			if len(certs) > 0 && certs[len(certs)-1] != '\n' {
+				content.WriteByte('\n')
+			}
+		}
+	}
+	return content.Bytes(), nil
+}
+
+// writeMergedCerts replaces the merged certificates atomically, so readers never see a partial file.
+func writeMergedCerts(mergedDir string, content []byte) error {
+	tmpFile := filepath.Join(mergedDir, "."+v1.TLSCertKey)
+	if err := ioutil.WriteFile(tmpFile, content, 0644); err != nil {
+		return errors.Wrapf(err, "unable to write %s", tmpFile)
+	}
+	if err := os.Rename(tmpFile, filepath.Join(mergedDir, v1.TLSCertKey)); err != nil {
+		return errors.Wrapf(err, "unable to update merged certificates in %s", mergedDir)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge cert dirs", func() {
+	var (
+		certDir      string
+		trustedCADir string
+		stopCh       chan struct{}
+	)
+
+	BeforeEach(func() {
+		var err error
+		certDir, err = ioutil.TempDir("", "cert-test")
+		Expect(err).ToNot(HaveOccurred())
+		trustedCADir, err = ioutil.TempDir("", "trusted-ca-test")
+		Expect(err).ToNot(HaveOccurred())
+		stopCh = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+		os.RemoveAll(certDir)
+		os.RemoveAll(trustedCADir)
+	})
+
+	It("should merge the certificates of all dirs", func() {
+		Expect(ioutil.WriteFile(filepath.Join(certDir, "tls.crt"), []byte("cert"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(trustedCADir, "ca-bundle.crt"), []byte("bundle\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(trustedCADir, ".hidden"), []byte("hidden"), 0644)).To(Succeed())
+		mergedDir, err := MergeCertDirs(stopCh, certDir, "", trustedCADir)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(mergedDir)
+		content, err := ioutil.ReadFile(filepath.Join(mergedDir, "tls.crt"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("cert\nbundle\n"))
+	})
+
+	It("should refresh the merged certificates when the content changes", func() {
+		defer func(interval time.Duration) {
+			certReloadInterval = interval
+		}(certReloadInterval)
+		certReloadInterval = 10 * time.Millisecond
+		Expect(ioutil.WriteFile(filepath.Join(trustedCADir, "ca-bundle.crt"), []byte("bundle\n"), 0644)).To(Succeed())
+		mergedDir, err := MergeCertDirs(stopCh, trustedCADir)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(mergedDir)
+		Expect(ioutil.WriteFile(filepath.Join(trustedCADir, "ca-bundle.crt"), []byte("rotated\n"), 0644)).To(Succeed())
+		Eventually(func() string {
+			content, _ := ioutil.ReadFile(filepath.Join(mergedDir, "tls.crt"))
+			return string(content)
+		}, 5*time.Second, 10*time.Millisecond).Should(Equal("rotated\n"))
+	})
+
+	It("should fail if a cert dir does not exist", func() {
+		_, err := MergeCertDirs(stopCh, "/invalid")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -7,15 +7,15 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 // UploadDataSource contains all the information need to upload data into a data volume.
 // Sequence of phases:
-// 1a. ProcessingPhaseInfo -> ProcessingPhaseTransferScratch (In Info phase the format readers are configured) In case the readers don't contain a raw file.
-// 1b. ProcessingPhaseInfo -> ProcessingPhaseTransferDataFile, in the case the readers contain a raw file.
-// 2a. ProcessingPhaseTransferScratch -> ProcessingPhaseConvert
-// 2b. ProcessingPhaseTransferDataFile -> ProcessingPhaseResize
+// 1. ProcessingPhaseInfo -> ProcessingPhaseTransferDataFile (In Info phase the format readers are configured)
+// 2. ProcessingPhaseTransferDataFile -> ProcessingPhaseResize
+// qcow2 images are converted while they are streamed to the target file, so no scratch space is needed.
 type UploadDataSource struct {
 	// Data strean
 	stream io.ReadCloser
@@ -41,11 +41,8 @@ func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
-	if !ud.readers.Convert {
-		// Uploading a raw file, we can write that directly to the target.
-		return ProcessingPhaseTransferDataFile, nil
-	}
-	return ProcessingPhaseTransferScratch, nil
+	// Uploading a raw file we can write directly to the target, and qcow2 files are converted on the fly.
+	return ProcessingPhaseTransferDataFile, nil
 }
 
 // Transfer is called to transfer the data from the source to the passed in path.
@@ -70,7 +67,12 @@ func (ud *UploadDataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (ud *UploadDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	err := util.StreamDataToFile(ud.readers.TopReader(), fileName)
+	var err error
+	if ud.readers.Convert {
+		err = image.ConvertQcow2Stream(ud.readers.TopReader(), fileName)
+	} else {
+		err = util.StreamDataToFile(ud.readers.TopReader(), fileName)
+	}
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -111,8 +113,13 @@ func NewAsyncUploadDataSource(stream io.ReadCloser) *AsyncUploadDataSource {
 }
 
 // Info is called to get initial information about the data.
+// Async uploads are validated before they are converted, so qcow2 files are still written to the scratch space.
 func (aud *AsyncUploadDataSource) Info() (ProcessingPhase, error) {
-	return aud.uploadDataSource.Info()
+	phase, err := aud.uploadDataSource.Info()
+	if err == nil && aud.uploadDataSource.readers.Convert {
+		return ProcessingPhaseTransferScratch, nil
+	}
+	return phase, err
 }
 
 // Transfer is called to transfer the data from the source to the passed in path.
@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -43,14 +44,14 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
-	It("Info should return Transfer, when passed in a valid image", func() {
+	It("Info should return TransferData, when passed in a valid qcow2 image", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 		ud = NewUploadDataSource(file)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
 	})
 
 	It("Info should return TransferData, when passed in a valid raw image", func() {
@@ -73,7 +74,7 @@ var _ = Describe("Upload data source", func() {
 		ud = NewUploadDataSource(sourceFile)
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		result, err := ud.Transfer(scratchPath)
 		if !wantErr {
 			Expect(err).NotTo(HaveOccurred())
@@ -103,7 +104,7 @@ var _ = Describe("Upload data source", func() {
 		ud = NewUploadDataSource(sourceFile)
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		err = sourceFile.Close()
 		Expect(err).NotTo(HaveOccurred())
 		result, err := ud.Transfer(tmpDir)
@@ -124,6 +125,24 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseResize).To(Equal(result))
 	})
 
+	It("TransferFile should convert a qcow2 image while streaming it", func() {
+		// Don't need to defer close, since ud.Close will close the reader
+		sourceFile, err := os.Open(cirrosFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(sourceFile)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = ud.TransferFile(filepath.Join(tmpDir, "file"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		want, err := ioutil.ReadFile(filepath.Join(imageDir, "cirros.raw"))
+		Expect(err).NotTo(HaveOccurred())
+		got, err := ioutil.ReadFile(filepath.Join(tmpDir, "file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(got, want)).To(BeTrue())
+	})
+
 	It("TransferFile should fail on streaming error", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
@@ -10,9 +10,18 @@ import (
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
+// featureGates are the feature gates of the CDIConfig passed to the importer, consulted by the experimental data sources
+var featureGates featuregates.FeatureGates = featuregates.NewStaticFeatureGates("")
+
+// SetFeatureGates sets the feature gates consulted by the importer
+func SetFeatureGates(gates featuregates.FeatureGates) {
+	featureGates = gates
+}
+
 // ParseEndpoint parses the required endpoint and return the url struct.
 func ParseEndpoint(endpt string) (*url.URL, error) {
 	if endpt == "" {
@@ -28,12 +37,30 @@ func ParseEndpoint(endpt string) (*url.URL, error) {
 // CleanDir cleans the contents of a directory including its sub directories, but does NOT remove the
 // directory itself.
 func CleanDir(dest string) error {
+	return cleanDirExcept(dest, nil)
+}
+
+// cleanScratchDir cleans the contents of the scratch space, except an interrupted download that recorded its
+// progress, which the next attempt resumes.
+func cleanScratchDir(dest string) error {
+	var keep map[string]bool
+	if _, err := os.Stat(filepath.Join(dest, tempFile+segmentProgressSuffix)); err == nil {
+		klog.V(1).Infof("Keeping the interrupted download in %s", dest)
+		keep = map[string]bool{tempFile: true, tempFile + segmentProgressSuffix: true}
+	}
+	return cleanDirExcept(dest, keep)
+}
+
+func cleanDirExcept(dest string, keep map[string]bool) error {
 	dir, err := ioutil.ReadDir(dest)
 	if err != nil {
 		klog.Errorf("Unable read directory to clean: %s, %v", dest, err)
 		return err
 	}
 	for _, d := range dir {
+		if keep[d.Name()] {
+			continue
+		}
 		klog.V(1).Infoln("deleting file: " + filepath.Join(dest, d.Name()))
 		err = os.RemoveAll(filepath.Join(dest, d.Name()))
 		if err != nil {
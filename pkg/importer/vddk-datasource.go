@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
@@ -66,6 +67,8 @@ type NbdKitWrapper struct {
 	Command *exec.Cmd
 	Socket  *url.URL
 	Handle  NbdOperations
+	// Connections are the additional connections to nbdkit used to copy the disk concurrently
+	Connections []NbdOperations
 }
 
 // getVddkPluginPath checks for the existence of a fake VDDK plugin for tests
@@ -154,8 +157,10 @@ func validatePlugins() error {
 	return nil
 }
 
-// createNbdKitWrapper starts nbdkit and returns a process handle for further management
-func createNbdKitWrapper(vmware *VMwareClient, diskFileName string) (*NbdKitWrapper, error) {
+// createNbdKitWrapper starts nbdkit and returns a process handle for further management, with the given number of
+// connections to nbdkit. The VDDK plugin serializes the requests of each connection, so every connection opens its own
+// VDDK session on the host.
+func createNbdKitWrapper(vmware *VMwareClient, diskFileName string, connections int) (*NbdKitWrapper, error) {
 	err := validatePlugins()
 	if err != nil {
 		klog.Errorf("Error validating nbdkit plugins: %v", err)
@@ -214,10 +219,37 @@ func createNbdKitWrapper(vmware *VMwareClient, diskFileName string) (*NbdKitWrap
 		return nil, err
 	}
 
+	socket, _ := url.Parse("nbd://" + nbdUnixSocket)
+	handle, err := connectNbd()
+	if err != nil {
+		nbdkit.Process.Kill()
+		return nil, err
+	}
+
+	source := &NbdKitWrapper{
+		Command: nbdkit,
+		Socket:  socket,
+		Handle:  handle,
+	}
+	for i := 1; i < connections; i++ {
+		connection, err := connectNbd()
+		if err != nil {
+			source.Close()
+			return nil, err
+		}
+		source.Connections = append(source.Connections, connection)
+	}
+	if connections > 1 {
+		klog.Infof("Opened %d connections to nbdkit", connections)
+	}
+	return source, nil
+}
+
+// connectNbd opens a libnbd connection to the nbdkit socket.
+func connectNbd() (*libnbd.Libnbd, error) {
 	handle, err := libnbd.Create()
 	if err != nil {
 		klog.Errorf("Unable to create libnbd handle: %v", err)
-		nbdkit.Process.Kill()
 		return nil, err
 	}
 
@@ -226,20 +258,22 @@ func createNbdKitWrapper(vmware *VMwareClient, diskFileName string) (*NbdKitWrap
 		klog.Errorf("Error adding base:allocation context to libnbd handle: %v", err)
 	}
 
-	socket, _ := url.Parse("nbd://" + nbdUnixSocket)
 	err = handle.ConnectUri("nbd+unix://?socket=" + nbdUnixSocket)
 	if err != nil {
-		klog.Errorf("Unable to connect to socket %s: %v", socket, err)
-		nbdkit.Process.Kill()
+		klog.Errorf("Unable to connect to socket %s: %v", nbdUnixSocket, err)
+		handle.Close()
 		return nil, err
 	}
+	return handle, nil
+}
 
-	source := &NbdKitWrapper{
-		Command: nbdkit,
-		Socket:  socket,
-		Handle:  handle,
+// Close closes the connections to nbdkit and stops it.
+func (nbdkit *NbdKitWrapper) Close() error {
+	nbdkit.Handle.Close()
+	for _, connection := range nbdkit.Connections {
+		connection.Close()
 	}
-	return source, nil
+	return nbdkit.Command.Process.Kill()
 }
 
 /* Section: VMware API manipulations */
@@ -465,6 +499,10 @@ const MaxBlockStatusLength = (2 << 30)
 // MaxPreadLength limits individual data block transfers to 23MB, larger block sizes fail
 const MaxPreadLength = (23 << 20)
 
+// connectionChunkSize is the size of the data ranges copied by each NBD connection when copying concurrently,
+// variable for testing
+var connectionChunkSize = uint32(64 << 20)
+
 // NbdOperations provides a mockable interface for the things needed from libnbd.
 type NbdOperations interface {
 	GetSize() (uint64, error)
@@ -758,13 +796,17 @@ func init() {
 	ownerUID, _ = util.ParseEnvVar(common.OwnerUID, false)
 }
 
-// NewVDDKDataSource creates a new instance of the vddk data provider.
-func NewVDDKDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode) (*VDDKDataSource, error) {
-	return newVddkDataSource(endpoint, accessKey, secKey, thumbprint, uuid, backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, volumeMode)
+// NewVDDKDataSource creates a new instance of the vddk data provider. The disk is copied over the given number of NBD
+// connections, a single connection is used if not positive.
+func NewVDDKDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode, connections int) (*VDDKDataSource, error) {
+	if connections < 1 {
+		connections = 1
+	}
+	return newVddkDataSource(endpoint, accessKey, secKey, thumbprint, uuid, backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, volumeMode, connections)
 }
 
-func createVddkDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode) (*VDDKDataSource, error) {
-	klog.Infof("Creating VDDK data source: backingFile [%s], currentCheckpoint [%s], previousCheckpoint [%s], finalCheckpoint [%s]", backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint)
+func createVddkDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode, connections int) (*VDDKDataSource, error) {
+	klog.Infof("Creating VDDK data source: backingFile [%s], currentCheckpoint [%s], previousCheckpoint [%s], finalCheckpoint [%s], connections [%d]", backingFile, currentCheckpoint, previousCheckpoint, finalCheckpoint, connections)
 	if currentCheckpoint == "" && previousCheckpoint != "" {
 		// Not sure what to do with just previous set by itself, return error
 		return nil, errors.New("previous checkpoint set without current")
@@ -828,7 +870,7 @@ func createVddkDataSource(endpoint string, accessKey string, secKey string, thum
 		}
 		klog.Infof("Set disk file name from current snapshot: %s", diskFileName)
 	}
-	nbdkit, err := newNbdKitWrapper(vmware, diskFileName)
+	nbdkit, err := newNbdKitWrapper(vmware, diskFileName, connections)
 	if err != nil {
 		klog.Errorf("Unable to start nbdkit: %v", err)
 		return nil, err
@@ -868,8 +910,7 @@ func (vs *VDDKDataSource) Info() (ProcessingPhase, error) {
 
 // Close closes any readers or other open resources.
 func (vs *VDDKDataSource) Close() error {
-	vs.NbdKit.Handle.Close()
-	return vs.NbdKit.Command.Process.Kill()
+	return vs.NbdKit.Close()
 }
 
 // GetURL returns the url that the data processor can use when converting the data.
@@ -889,6 +930,19 @@ func (vs *VDDKDataSource) IsDeltaCopy() bool {
 	return result
 }
 
+// GetCheckpoint returns the snapshot copied by this stage of a warm migration.
+func (vs *VDDKDataSource) GetCheckpoint() string {
+	return vs.CurrentSnapshot
+}
+
+// GetChangeID returns the snapshot the changed blocks are queried from, empty for the full copy.
+func (vs *VDDKDataSource) GetChangeID() string {
+	if !vs.IsDeltaCopy() {
+		return ""
+	}
+	return vs.PreviousSnapshot
+}
+
 // TransferFile is called to transfer the data from the source to the file passed in.
 func (vs *VDDKDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
 	if vs.ChangedBlocks != nil { // Warm migration pre-checks
@@ -951,17 +1005,9 @@ func (vs *VDDKDataSource) TransferFile(fileName string) (ProcessingPhase, error)
 		}
 	}
 
+	var extents []types.DiskChangeExtent
 	if vs.ChangedBlocks != nil { // Warm migration delta copy
-		for _, extent := range vs.ChangedBlocks.ChangedArea {
-			blocks := GetBlockStatus(vs.NbdKit.Handle, extent)
-			for _, block := range blocks {
-				err := CopyRange(vs.NbdKit.Handle, sink, block, updateProgress)
-				if err != nil {
-					klog.Errorf("Unable to copy block at offset %d: %v", block.Offset, err)
-					return ProcessingPhaseError, err
-				}
-			}
-		}
+		extents = vs.ChangedBlocks.ChangedArea
 	} else { // Cold migration full copy
 		start := uint64(0)
 		blocksize := uint64(MaxBlockStatusLength)
@@ -970,21 +1016,88 @@ func (vs *VDDKDataSource) TransferFile(fileName string) (ProcessingPhase, error)
 				blocksize = vs.Size - i
 			}
 
-			extent := types.DiskChangeExtent{
+			extents = append(extents, types.DiskChangeExtent{
 				Length: int64(blocksize),
 				Start:  int64(i),
+			})
+		}
+	}
+
+	if len(vs.NbdKit.Connections) > 0 {
+		if err := vs.copyConcurrently(sink, extents, updateProgress); err != nil {
+			return ProcessingPhaseError, err
+		}
+		return ProcessingPhasePreallocate, nil
+	}
+	for _, extent := range extents {
+		blocks := GetBlockStatus(vs.NbdKit.Handle, extent)
+		for _, block := range blocks {
+			err := CopyRange(vs.NbdKit.Handle, sink, block, updateProgress)
+			if err != nil {
+				klog.Errorf("Unable to copy block at offset %d: %v", block.Offset, err)
+				return ProcessingPhaseError, err
 			}
+		}
+	}
 
-			blocks := GetBlockStatus(vs.NbdKit.Handle, extent)
-			for _, block := range blocks {
-				err := CopyRange(vs.NbdKit.Handle, sink, block, updateProgress)
-				if err != nil {
-					klog.Errorf("Unable to copy block at offset %d: %v", block.Offset, err)
-					return ProcessingPhaseError, err
+	return ProcessingPhasePreallocate, nil
+}
+
+// copyConcurrently copies the data blocks of the extents over all the connections to nbdkit. Holes and zero blocks are
+// zeroed first from a single connection, so that zeroing never truncates the destination while data is written to it.
+func (vs *VDDKDataSource) copyConcurrently(sink VDDKDataSink, extents []types.DiskChangeExtent, updateProgress func(int)) error {
+	var dataBlocks []*BlockStatusData
+	for _, extent := range extents {
+		for _, block := range GetBlockStatus(vs.NbdKit.Handle, extent) {
+			if (block.Flags & (libnbd.STATE_ZERO | libnbd.STATE_HOLE)) != 0 {
+				if err := CopyRange(vs.NbdKit.Handle, sink, block, updateProgress); err != nil {
+					klog.Errorf("Unable to zero block at offset %d: %v", block.Offset, err)
+					return err
+				}
+				continue
+			}
+			// Split the data blocks so that all the connections get some of the work
+			for count := uint32(0); count < block.Length; count += connectionChunkSize {
+				length := block.Length - count
+				if length > connectionChunkSize {
+					length = connectionChunkSize
 				}
+				dataBlocks = append(dataBlocks, &BlockStatusData{Offset: block.Offset + uint64(count), Length: length, Flags: block.Flags})
 			}
 		}
 	}
 
-	return ProcessingPhasePreallocate, nil
+	work := make(chan *BlockStatusData, len(dataBlocks))
+	for _, block := range dataBlocks {
+		work <- block
+	}
+	close(work)
+	var progressLock sync.Mutex
+	lockedProgress := func(written int) {
+		progressLock.Lock()
+		defer progressLock.Unlock()
+		updateProgress(written)
+	}
+	handles := append([]NbdOperations{vs.NbdKit.Handle}, vs.NbdKit.Connections...)
+	errs := make(chan error, len(handles))
+	var wg sync.WaitGroup
+	for _, handle := range handles {
+		wg.Add(1)
+		go func(handle NbdOperations) {
+			defer wg.Done()
+			for block := range work {
+				if err := CopyRange(handle, sink, block, lockedProgress); err != nil {
+					klog.Errorf("Unable to copy block at offset %d: %v", block.Offset, err)
+					errs <- err
+					return
+				}
+			}
+		}(handle)
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return nil
 }
@@ -5,8 +5,11 @@ import (
 	"context"
 	"crypto/md5"
 	"errors"
+	"math/rand"
 	"net/url"
 	"os/exec"
+	"sync/atomic"
+	"time"
 
 	libnbd "github.com/mrnold/go-libnbd"
 	. "github.com/onsi/ginkgo"
@@ -57,12 +60,12 @@ var _ = Describe("VDDK data source", func() {
 	It("NewVDDKDataSource should fail when called with an invalid endpoint", func() {
 		newVddkDataSource = createVddkDataSource
 		newVMwareClient = createVMwareClient
-		_, err := NewVDDKDataSource("httpx://-------", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		_, err := NewVDDKDataSource("httpx://-------", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("VDDK data source GetURL should pass through NBD socket information", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		socket := dp.GetURL()
 		path := socket.String()
@@ -70,7 +73,7 @@ var _ = Describe("VDDK data source", func() {
 	})
 
 	It("VDDK data source should move to transfer data phase after Info", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		phase, err := dp.Info()
 		Expect(err).ToNot(HaveOccurred())
@@ -86,7 +89,7 @@ var _ = Describe("VDDK data source", func() {
 			return bytes.Repeat([]byte{0x55}, 512), nil
 		}
 		currentExport = replaceExport
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		phase, err := dp.Info()
 		Expect(err).ToNot(HaveOccurred())
@@ -98,7 +101,7 @@ var _ = Describe("VDDK data source", func() {
 
 	It("VDDK data source should fail if TransferFile fails", func() {
 		newVddkDataSink = createVddkDataSink
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		phase, err := dp.Info()
 		Expect(err).ToNot(HaveOccurred())
@@ -109,19 +112,22 @@ var _ = Describe("VDDK data source", func() {
 	})
 
 	It("VDDK data source should know if it is a delta copy", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(dp.IsDeltaCopy()).To(Equal(true))
+		Expect(dp.GetCheckpoint()).To(Equal("checkpoint-1"))
+		Expect(dp.GetChangeID()).To(Equal("checkpoint-2"))
 	})
 
 	It("VDDK data source should know if it is not a delta copy", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(dp.IsDeltaCopy()).To(Equal(false))
+		Expect(dp.GetChangeID()).To(BeEmpty())
 	})
 
 	It("VDDK delta copy should return immediately if there are no changed blocks", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem, 1)
 		dp.ChangedBlocks = &types.DiskChangeInfo{
 			StartOffset: 0,
 			Length:      0,
@@ -133,7 +139,7 @@ var _ = Describe("VDDK data source", func() {
 	})
 
 	It("VDDK full copy should successfully copy the same bytes passed in", func() {
-		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem)
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 1)
 		dp.Size = 40 << 20
 		sourceBytes := bytes.Repeat([]byte{0x55}, int(dp.Size))
 		replaceExport := currentExport
@@ -159,7 +165,7 @@ var _ = Describe("VDDK data source", func() {
 	It("VDDK delta copy should sucessfully apply a delta to a base disk image", func() {
 
 		// Copy base disk ("snapshot 1")
-		snap1, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "", "", v1.PersistentVolumeFilesystem)
+		snap1, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "", "", v1.PersistentVolumeFilesystem, 1)
 		snap1.Size = 40 << 20
 		sourceBytes := bytes.Repeat([]byte{0x55}, int(snap1.Size))
 		replaceExport := currentExport
@@ -182,7 +188,7 @@ var _ = Describe("VDDK data source", func() {
 		Expect(sourceSum).To(Equal(destSum))
 
 		// Write some data to the first snapshot, then copy the delta from difference between the two snapshots
-		snap2, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem)
+		snap2, err := NewVDDKDataSource("", "", "", "", "", "", "checkpoint-1", "checkpoint-2", "", v1.PersistentVolumeFilesystem, 1)
 		snap2.Size = 40 << 20
 		copy(sourceBytes[1024:2048], bytes.Repeat([]byte{0xAA}, 1024))
 		snap2.ChangedBlocks = &types.DiskChangeInfo{
@@ -203,13 +209,61 @@ var _ = Describe("VDDK data source", func() {
 		Expect(changedSourceSum).To(Equal(deltaSum))
 	})
 
+	It("VDDK full copy should copy the disk over all the connections", func() {
+		savedChunkSize := connectionChunkSize
+		connectionChunkSize = 1 << 20
+		defer func() {
+			connectionChunkSize = savedChunkSize
+		}()
+		dp, err := NewVDDKDataSource("", "", "", "", "", "", "", "", "", v1.PersistentVolumeFilesystem, 3)
+		Expect(err).ToNot(HaveOccurred())
+		handles := []*countingNbdOperations{{}, {}, {}}
+		dp.NbdKit.Handle = handles[0]
+		dp.NbdKit.Connections = []NbdOperations{handles[1], handles[2]}
+		dp.Size = 6 << 20
+		sourceBytes := make([]byte, dp.Size)
+		rand.Read(sourceBytes)
+		replaceExport := currentExport
+		replaceExport.Read = func(uint64) ([]byte, error) {
+			return sourceBytes, nil
+		}
+		currentExport = replaceExport
+		mockSinkBuffer = bytes.Repeat([]byte{0x00}, int(dp.Size))
+
+		phase, err := dp.TransferFile(".")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhasePreallocate))
+		Expect(md5.Sum(mockSinkBuffer)).To(Equal(md5.Sum(sourceBytes)))
+		for _, handle := range handles {
+			Expect(handle.preads).To(BeNumerically(">", 0))
+		}
+		Expect(handles[0].preads + handles[1].preads + handles[2].preads).To(BeEquivalentTo(6))
+	})
+
+	It("should open the requested number of connections to nbdkit", func() {
+		newVddkDataSource = createVddkDataSource
+		diskName := "[teststore] testvm/testfile.vmdk"
+		currentVMwareFunctions.Properties = func(ctx context.Context, ref types.ManagedObjectReference, property []string, result interface{}) error {
+			if out, ok := result.(*mo.VirtualMachine); ok && property[0] == "config.hardware.device" {
+				out.Config = createVirtualDiskConfig(diskName, 12345)
+			}
+			return nil
+		}
+		dp, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "", "", "", v1.PersistentVolumeFilesystem, 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dp.NbdKit.Connections).To(HaveLen(3))
+		dp, err = NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "", "", "", v1.PersistentVolumeFilesystem, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dp.NbdKit.Connections).To(BeEmpty())
+	})
+
 	DescribeTable("disk name lookup", func(targetDiskName string, diskName string, snapshotDiskName string, expectedSuccess bool) {
 		var returnedDiskName string
 
 		newVddkDataSource = createVddkDataSource
-		newNbdKitWrapper = func(vmware *VMwareClient, fileName string) (*NbdKitWrapper, error) {
+		newNbdKitWrapper = func(vmware *VMwareClient, fileName string, connections int) (*NbdKitWrapper, error) {
 			returnedDiskName = fileName
-			return createMockNbdKitWrapper(vmware, fileName)
+			return createMockNbdKitWrapper(vmware, fileName, connections)
 		}
 		currentVMwareFunctions.Properties = func(ctx context.Context, ref types.ManagedObjectReference, property []string, result interface{}) error {
 			switch out := result.(type) {
@@ -225,7 +279,7 @@ var _ = Describe("VDDK data source", func() {
 			return nil
 		}
 
-		_, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", targetDiskName, "", "", "", v1.PersistentVolumeFilesystem)
+		_, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", targetDiskName, "", "", "", v1.PersistentVolumeFilesystem, 1)
 		if expectedSuccess {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(returnedDiskName).To(Equal(targetDiskName))
@@ -291,7 +345,7 @@ var _ = Describe("VDDK data source", func() {
 		}
 
 		// Expect source.ChangedBlocks to equal local changed blocks
-		source, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "snapshot-1", "snapshot-2", "false", v1.PersistentVolumeFilesystem)
+		source, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "snapshot-1", "snapshot-2", "false", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(changedBlockList.StartOffset).To(Equal(source.ChangedBlocks.StartOffset))
 		Expect(changedBlockList.Length).To(Equal(source.ChangedBlocks.Length))
@@ -316,7 +370,7 @@ var _ = Describe("VDDK data source", func() {
 			return nil
 		}
 
-		_, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "", "", "false", v1.PersistentVolumeFilesystem)
+		_, err := NewVDDKDataSource("http://vcenter.test", "user", "pass", "aa:bb:cc:dd", "1-2-3-4", diskName, "", "", "false", v1.PersistentVolumeFilesystem, 1)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal("disk 'testdisk.vmdk' is not present in VM hardware config or snapshot list"))
 	})
@@ -335,6 +389,20 @@ func (handle *mockNbdOperations) Pread(buf []byte, offset uint64, optargs *libnb
 	return err
 }
 
+// countingNbdOperations counts the reads of one of the connections to nbdkit
+type countingNbdOperations struct {
+	mockNbdOperations
+	preads int32
+}
+
+func (handle *countingNbdOperations) Pread(buf []byte, offset uint64, optargs *libnbd.PreadOptargs) error {
+	if atomic.AddInt32(&handle.preads, 1) == 1 {
+		// Give the other connections a chance to pick up some of the work
+		time.Sleep(10 * time.Millisecond)
+	}
+	return handle.mockNbdOperations.Pread(buf, offset, optargs)
+}
+
 func (handle *mockNbdOperations) Close() *libnbd.LibnbdError {
 	return nil
 }
@@ -345,7 +413,7 @@ func (handle *mockNbdOperations) BlockStatus(length uint64, offset uint64, callb
 	return nil
 }
 
-func createMockVddkDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode) (*VDDKDataSource, error) {
+func createMockVddkDataSource(endpoint string, accessKey string, secKey string, thumbprint string, uuid string, backingFile string, currentCheckpoint string, previousCheckpoint string, finalCheckpoint string, volumeMode v1.PersistentVolumeMode, connections int) (*VDDKDataSource, error) {
 	socketURL, err := url.Parse(socketPath)
 	if err != nil {
 		return nil, err
@@ -469,13 +537,17 @@ func createMockVMwareClient(endpoint string, accessKey string, secKey string, th
 	}, nil
 }
 
-func createMockNbdKitWrapper(vmware *VMwareClient, diskFileName string) (*NbdKitWrapper, error) {
+func createMockNbdKitWrapper(vmware *VMwareClient, diskFileName string, connections int) (*NbdKitWrapper, error) {
 	u, _ := url.Parse("http://vcenter.test")
-	return &NbdKitWrapper{
+	nbdkit := &NbdKitWrapper{
 		Command: &exec.Cmd{},
 		Socket:  u,
 		Handle:  &mockNbdOperations{},
-	}, nil
+	}
+	for i := 1; i < connections; i++ {
+		nbdkit.Connections = append(nbdkit.Connections, &mockNbdOperations{})
+	}
+	return nbdkit, nil
 }
 
 func createVirtualDiskConfig(fileName string, key int32) *types.VirtualMachineConfigInfo {
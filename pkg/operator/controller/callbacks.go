@@ -110,12 +110,22 @@ func reconcileCreateRoute(args *callbacks.ReconcileCallbackArgs) error {
 	}
 
 	cr := args.Resource.(runtime.Object)
-	if err := ensureUploadProxyRouteExists(args.Logger, args.Client, args.Scheme, deployment); err != nil {
+	exposure := args.Resource.(*cdiv1.CDI).Spec.UploadProxyExposure
+	if err := ensureUploadProxyRouteExists(args.Logger, args.Client, args.Scheme, deployment, exposure); err != nil {
 		args.Recorder.Event(cr, corev1.EventTypeWarning, createResourceFailed, fmt.Sprintf("Failed to ensure upload proxy route exists, %v", err))
 		return err
 	}
 	args.Recorder.Event(cr, corev1.EventTypeNormal, createResourceSuccess, "Successfully ensured upload proxy route exists")
 
+	exposed, err := ensureUploadProxyIngress(args.Client, args.Scheme, deployment, exposure)
+	if err != nil {
+		args.Recorder.Event(cr, corev1.EventTypeWarning, createResourceFailed, fmt.Sprintf("Failed to ensure upload proxy ingress exists, %v", err))
+		return err
+	}
+	if exposed {
+		args.Recorder.Event(cr, corev1.EventTypeNormal, createResourceSuccess, "Successfully ensured upload proxy ingress exists")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdicerts "kubevirt.io/containerized-data-importer/pkg/operator/resources/cert"
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+const (
+	certManagerGroup       = "cert-manager.io"
+	certManagerIssuerKind  = "Issuer"
+	certManagerCAKey       = "ca.crt"
+	certBundleConfigMapKey = "ca-bundle.crt"
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Certificate"}
+
+// syncCertificates rotates the certificates of CDI, except for the serving certificates delegated to cert-manager,
+// whose cert-manager Certificates are ensured instead. The Certificates are removed if cert-manager is not configured.
+func (r *ReconcileCDI) syncCertificates(cdi *cdiv1.CDI, defs []cdicerts.CertificateDefinition) error {
+	if cdi.Spec.CertConfig == nil || cdi.Spec.CertConfig.CertManager == nil {
+		if err := r.certManager.Sync(defs); err != nil {
+			return err
+		}
+
+		for _, def := range defs {
+			if isDelegable(def) {
+				if err := r.deleteCertManagerCertificate(cdi, def); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	var internal, delegated []cdicerts.CertificateDefinition
+	for _, def := range defs {
+		if isDelegable(def) {
+			delegated = append(delegated, def)
+		} else {
+			internal = append(internal, def)
+		}
+	}
+
+	if err := r.certManager.Sync(internal); err != nil {
+		return err
+	}
+
+	issuerRef := cdi.Spec.CertConfig.CertManager.IssuerRef
+	for _, def := range delegated {
+		if err := r.ensureCertManagerCertificate(cdi, def, issuerRef); err != nil {
+			return err
+		}
+
+		if err := r.ensureCertManagerCABundle(def); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isDelegable returns true for the serving certificates configurable by the user
+func isDelegable(def cdicerts.CertificateDefinition) bool {
+	return def.Configurable && def.TargetService != nil && def.TargetSecret != nil && def.CertBundleConfigmap != nil
+}
+
+func (r *ReconcileCDI) ensureCertManagerCertificate(cdi *cdiv1.CDI, def cdicerts.CertificateDefinition, issuerRef cdiv1.CertManagerIssuerReference) error {
+	desired := newCertManagerCertificate(def, issuerRef)
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(certManagerCertificateGVK)
+	key := client.ObjectKey{Namespace: desired.GetNamespace(), Name: desired.GetName()}
+	err := r.uncachedClient.Get(context.TODO(), key, current)
+	if err == nil {
+		if !reflect.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+			current.Object["spec"] = desired.Object["spec"]
+			return r.uncachedClient.Update(context.TODO(), current)
+		}
+		return nil
+	}
+
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cert-manager is configured but its Certificate kind is not installed")
+	}
+
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	if err = controllerutil.SetControllerReference(cdi, desired, r.scheme); err != nil {
+		return err
+	}
+
+	return r.uncachedClient.Create(context.TODO(), desired)
+}
+
+func (r *ReconcileCDI) deleteCertManagerCertificate(cdi *cdiv1.CDI, def cdicerts.CertificateDefinition) error {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	key := client.ObjectKey{Namespace: def.TargetSecret.Namespace, Name: def.TargetSecret.Name}
+	if err := r.uncachedClient.Get(context.TODO(), key, certificate); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Certificates created by hand are left alone
+	if !metav1.IsControlledBy(certificate, cdi) {
+		return nil
+	}
+
+	if err := r.uncachedClient.Delete(context.TODO(), certificate); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// ensureCertManagerCABundle copies the CA of the certificate issued by cert-manager to the cert bundle read by
+// the clients of the service
+func (r *ReconcileCDI) ensureCertManagerCABundle(def cdicerts.CertificateDefinition) error {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: def.TargetSecret.Namespace, Name: def.TargetSecret.Name}
+	if err := r.client.Get(context.TODO(), key, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	ca, ok := secret.Data[certManagerCAKey]
+	if !ok || len(ca) == 0 {
+		log.V(3).Info("certificate not issued yet", "secret", secret.Name)
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key = client.ObjectKey{Namespace: def.CertBundleConfigmap.Namespace, Name: def.CertBundleConfigmap.Name}
+	if err := r.client.Get(context.TODO(), key, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if configMap.Data[certBundleConfigMapKey] == string(ca) {
+		return nil
+	}
+
+	configMapCpy := configMap.DeepCopy()
+	if configMapCpy.Data == nil {
+		configMapCpy.Data = make(map[string]string)
+	}
+	configMapCpy.Data[certBundleConfigMapKey] = string(ca)
+
+	return r.client.Update(context.TODO(), configMapCpy)
+}
+
+func newCertManagerCertificate(def cdicerts.CertificateDefinition, issuerRef cdiv1.CertManagerIssuerReference) *unstructured.Unstructured {
+	namespace := def.TargetSecret.Namespace
+	service := *def.TargetService
+
+	kind := issuerRef.Kind
+	if kind == "" {
+		kind = certManagerIssuerKind
+	}
+	group := issuerRef.Group
+	if group == "" {
+		group = certManagerGroup
+	}
+
+	spec := map[string]interface{}{
+		"secretName": def.TargetSecret.Name,
+		"dnsNames": []interface{}{
+			service,
+			fmt.Sprintf("%s.%s", service, namespace),
+			fmt.Sprintf("%s.%s.svc", service, namespace),
+		},
+		"duration": def.TargetConfig.Lifetime.String(),
+		"issuerRef": map[string]interface{}{
+			"name":  issuerRef.Name,
+			"kind":  kind,
+			"group": group,
+		},
+	}
+	// CDI refreshes the certificate once it is older than the refresh period, cert-manager renews it the given
+	// period before its expiration
+	if renewBefore := def.TargetConfig.Lifetime - def.TargetConfig.Refresh; renewBefore > 0 {
+		spec["renewBefore"] = renewBefore.String()
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	certificate.SetNamespace(namespace)
+	certificate.SetName(def.TargetSecret.Name)
+	certificate.SetLabels(utils.ResourcesBuiler.WithCommonLabels(nil))
+	certificate.Object["spec"] = spec
+
+	return certificate
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("cert-manager certificates", func() {
+	var cdi *cdiv1.CDI
+
+	BeforeEach(func() {
+		cdi = createCDI("cdi", "good uid")
+		cdi.Spec.CertConfig = &cdiv1.CDICertConfig{
+			Server: &cdiv1.CertConfig{
+				Duration:    &metav1.Duration{Duration: 48 * time.Hour},
+				RenewBefore: &metav1.Duration{Duration: 36 * time.Hour},
+			},
+			CertManager: &cdiv1.CertManagerConfig{
+				IssuerRef: cdiv1.CertManagerIssuerReference{
+					Name: "cdi-issuer",
+					Kind: "ClusterIssuer",
+				},
+			},
+		}
+	})
+
+	createBundle := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: cdiNamespace, Name: name}}
+	}
+
+	getCertificate := func(c client.Client, name string) (*unstructured.Unstructured, error) {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certManagerCertificateGVK)
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: cdiNamespace, Name: name}, certificate)
+		return certificate, err
+	}
+
+	It("should create the Certificates of the serving certificates", func() {
+		c := createClient(createBundle("cdi-uploadproxy-signer-bundle"))
+		r := createReconciler(c)
+
+		err := r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		certificate, err := getCertificate(c, "cdi-apiserver-server-cert")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(certificate, cdi)).To(BeTrue())
+		Expect(certificate.Object["spec"]).To(Equal(map[string]interface{}{
+			"secretName": "cdi-apiserver-server-cert",
+			"dnsNames":   []interface{}{"cdi-api", "cdi-api.cdi", "cdi-api.cdi.svc"},
+			"duration":   "48h0m0s",
+			// CDI refreshes after 36 hours, cert-manager 12 hours before the expiration
+			"renewBefore": "12h0m0s",
+			"issuerRef": map[string]interface{}{
+				"name":  "cdi-issuer",
+				"kind":  "ClusterIssuer",
+				"group": "cert-manager.io",
+			},
+		}))
+
+		certificate, err = getCertificate(c, "cdi-uploadproxy-server-cert")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Object["spec"]).To(HaveKeyWithValue("secretName", "cdi-uploadproxy-server-cert"))
+	})
+
+	It("should update the Certificates when the issuer changes", func() {
+		c := createClient(createBundle("cdi-uploadproxy-signer-bundle"))
+		r := createReconciler(c)
+
+		err := r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		cdi.Spec.CertConfig.CertManager.IssuerRef = cdiv1.CertManagerIssuerReference{Name: "other-issuer"}
+		err = r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		certificate, err := getCertificate(c, "cdi-apiserver-server-cert")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Object["spec"]).To(HaveKeyWithValue("issuerRef", map[string]interface{}{
+			"name":  "other-issuer",
+			"kind":  "Issuer",
+			"group": "cert-manager.io",
+		}))
+	})
+
+	It("should publish the CA of the issued certificate in the cert bundle", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cdiNamespace, Name: "cdi-apiserver-server-cert"},
+			Data: map[string][]byte{
+				"ca.crt":  []byte("issuer ca"),
+				"tls.crt": []byte("cert"),
+				"tls.key": []byte("key"),
+			},
+		}
+		c := createClient(secret, createBundle("cdi-apiserver-signer-bundle"), createBundle("cdi-uploadproxy-signer-bundle"))
+		r := createReconciler(c)
+
+		err := r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		err = c.Get(context.TODO(), client.ObjectKey{Namespace: cdiNamespace, Name: "cdi-apiserver-signer-bundle"}, cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue("ca-bundle.crt", "issuer ca"))
+	})
+
+	It("should remove the Certificates when cert-manager is not configured", func() {
+		c := createClient(createBundle("cdi-uploadproxy-signer-bundle"))
+		r := createReconciler(c)
+
+		err := r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		cdi.Spec.CertConfig.CertManager = nil
+		err = r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = getCertificate(c, "cdi-apiserver-server-cert")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		_, err = getCertificate(c, "cdi-uploadproxy-server-cert")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should leave the Certificates created by hand alone", func() {
+		c := createClient(createBundle("cdi-uploadproxy-signer-bundle"))
+		r := createReconciler(c)
+
+		manual := newCertManagerCertificate(r.getCertificateDefinitions(cdi)[0], cdi.Spec.CertConfig.CertManager.IssuerRef)
+		Expect(c.Create(context.TODO(), manual)).To(Succeed())
+
+		cdi.Spec.CertConfig.CertManager = nil
+		err := r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = getCertificate(c, "cdi-apiserver-server-cert")
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
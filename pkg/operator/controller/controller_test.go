@@ -38,8 +38,10 @@ import (
 	conditions "github.com/openshift/custom-resource-status/conditions/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -316,6 +318,108 @@ var _ = Describe("Controller", func() {
 				validateEvents(args.reconciler, createReadyEventValidationMap())
 			})
 
+			It("should create the upload proxy ingress of the exposure", func() {
+				args := createArgs()
+				ingressClass := "nginx"
+				args.cdi.Spec.UploadProxyExposure = &cdiv1.UploadProxyExposure{
+					Type:             cdiv1.UploadProxyExposureIngress,
+					Hostname:         "cdi-uploadproxy.example.com",
+					TLSSecretName:    "cdi-uploadproxy-tls",
+					IngressClassName: &ingressClass,
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+					},
+				}
+				err := args.client.Update(context.TODO(), args.cdi)
+				Expect(err).ToNot(HaveOccurred())
+				doReconcile(args)
+				Expect(setDeploymentsReady(args)).To(BeTrue())
+
+				ingress := &extensionsv1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      uploadProxyIngressName,
+						Namespace: cdiNamespace,
+					},
+				}
+				obj, err := getObject(args.client, ingress)
+				Expect(err).ToNot(HaveOccurred())
+				ingress = obj.(*extensionsv1beta1.Ingress)
+				Expect(ingress.Spec.IngressClassName).To(Equal(&ingressClass))
+				Expect(ingress.Spec.TLS[0].Hosts).To(ConsistOf("cdi-uploadproxy.example.com"))
+				Expect(ingress.Spec.TLS[0].SecretName).To(Equal("cdi-uploadproxy-tls"))
+				Expect(ingress.Spec.Rules[0].Host).To(Equal("cdi-uploadproxy.example.com"))
+				Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName).To(Equal(uploadProxyServiceName))
+				Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/backend-protocol", "HTTPS"))
+
+				args.cdi.Spec.UploadProxyExposure = nil
+				err = args.client.Update(context.TODO(), args.cdi)
+				Expect(err).ToNot(HaveOccurred())
+				doReconcile(args)
+
+				_, err = getObject(args.client, ingress)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should not delete an upload proxy ingress created by hand", func() {
+				args := createArgs()
+				ingress := &extensionsv1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      uploadProxyIngressName,
+						Namespace: cdiNamespace,
+					},
+				}
+				err := args.client.Create(context.TODO(), ingress)
+				Expect(err).ToNot(HaveOccurred())
+				doReconcile(args)
+				Expect(setDeploymentsReady(args)).To(BeTrue())
+
+				_, err = getObject(args.client, ingress)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should set the host name and certificate of the route exposure", func() {
+				args := createArgs()
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cdi-uploadproxy-tls",
+						Namespace: cdiNamespace,
+					},
+					Data: map[string][]byte{
+						corev1.TLSCertKey:       []byte("cert"),
+						corev1.TLSPrivateKeyKey: []byte("key"),
+					},
+				}
+				err := args.client.Create(context.TODO(), secret)
+				Expect(err).ToNot(HaveOccurred())
+				args.cdi.Spec.UploadProxyExposure = &cdiv1.UploadProxyExposure{
+					Type:          cdiv1.UploadProxyExposureRoute,
+					Hostname:      "cdi-uploadproxy.example.com",
+					TLSSecretName: "cdi-uploadproxy-tls",
+					Annotations: map[string]string{
+						"haproxy.router.openshift.io/timeout": "2h",
+					},
+				}
+				err = args.client.Update(context.TODO(), args.cdi)
+				Expect(err).ToNot(HaveOccurred())
+				doReconcile(args)
+				Expect(setDeploymentsReady(args)).To(BeTrue())
+
+				route := &routev1.Route{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      uploadProxyRouteName,
+						Namespace: cdiNamespace,
+					},
+				}
+				obj, err := getObject(args.client, route)
+				Expect(err).ToNot(HaveOccurred())
+				route = obj.(*routev1.Route)
+				Expect(route.Spec.Host).To(Equal("cdi-uploadproxy.example.com"))
+				Expect(route.Spec.TLS.Certificate).To(Equal("cert"))
+				Expect(route.Spec.TLS.Key).To(Equal("key"))
+				Expect(route.Spec.TLS.DestinationCACertificate).Should(Equal(testCertData))
+				Expect(route.Annotations).To(HaveKeyWithValue("haproxy.router.openshift.io/timeout", "2h"))
+			})
+
 			It("should set config authority", func() {
 				args := createArgs()
 				doReconcile(args)
@@ -381,7 +485,10 @@ var _ = Describe("Controller", func() {
 					dd, err := getDeployment(args.client, d)
 					Expect(err).ToNot(HaveOccurred())
 
-					dd.Status.Replicas = *dd.Spec.Replicas
+					dd.Status.Replicas = 1
+					if dd.Spec.Replicas != nil {
+						dd.Status.Replicas = *dd.Spec.Replicas
+					}
 					dd.Status.ReadyReplicas = dd.Status.Replicas
 
 					err = args.client.Update(context.TODO(), dd)
@@ -549,6 +656,14 @@ var _ = Describe("Controller", func() {
 		validateEvents(args.reconciler, createNotReadyEventValidationMap())
 	},
 		Entry("Pull override", &pullOverride{corev1.PullNever}),
+		Entry("Image override", &imageOverride{cdiv1.CDIImageOverrides{Importer: "mirror.example.com/cdi-importer@sha256:1234"}}),
+		Entry("Pull secrets override", &pullSecretsOverride{[]corev1.LocalObjectReference{{Name: "mirror-pull-secret"}}}),
+		Entry("Resources override", &resourcesOverride{corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}}),
+		Entry("Default priority class", &priorityClassOverride{""}),
+		Entry("Priority class override", &priorityClassOverride{"cdi-custom-priority"}),
 	)
 
 	Describe("Upgrading CDI", func() {
@@ -1339,6 +1454,72 @@ func (o *pullOverride) Check(d *appsv1.Deployment) {
 	Expect(pp).Should(Equal(o.value))
 }
 
+type imageOverride struct {
+	value cdiv1.CDIImageOverrides
+}
+
+func (o *imageOverride) Set(cr *cdiv1.CDI) {
+	cr.Spec.ImageOverrides = o.value.DeepCopy()
+}
+
+func (o *imageOverride) Check(d *appsv1.Deployment) {
+	container := d.Spec.Template.Spec.Containers[0]
+	if container.Name != "cdi-controller" {
+		return
+	}
+	Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "IMPORTER_IMAGE", Value: o.value.Importer}))
+	Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "CLONER_IMAGE", Value: "cdi-cloner"}))
+}
+
+type pullSecretsOverride struct {
+	value []corev1.LocalObjectReference
+}
+
+func (o *pullSecretsOverride) Set(cr *cdiv1.CDI) {
+	cr.Spec.ImagePullSecrets = o.value
+}
+
+func (o *pullSecretsOverride) Check(d *appsv1.Deployment) {
+	Expect(d.Spec.Template.Spec.ImagePullSecrets).Should(Equal(o.value))
+}
+
+type resourcesOverride struct {
+	value corev1.ResourceRequirements
+}
+
+func (o *resourcesOverride) Set(cr *cdiv1.CDI) {
+	cr.Spec.ComponentResources = &cdiv1.CDIComponentResources{
+		Controller:  o.value.DeepCopy(),
+		APIServer:   o.value.DeepCopy(),
+		UploadProxy: o.value.DeepCopy(),
+	}
+}
+
+func (o *resourcesOverride) Check(d *appsv1.Deployment) {
+	resources := d.Spec.Template.Spec.Containers[0].Resources
+	Expect(resources.Requests.Memory().Cmp(*o.value.Requests.Memory())).To(Equal(0))
+	Expect(resources.Limits.Memory().Cmp(*o.value.Limits.Memory())).To(Equal(0))
+	Expect(resources.Requests.Cpu().IsZero()).To(BeTrue())
+}
+
+type priorityClassOverride struct {
+	value cdiv1.CDIPriorityClass
+}
+
+func (o *priorityClassOverride) Set(cr *cdiv1.CDI) {
+	if o.value != "" {
+		cr.Spec.PriorityClass = &o.value
+	}
+}
+
+func (o *priorityClassOverride) Check(d *appsv1.Deployment) {
+	expected := clusterResources.PriorityClassName
+	if o.value != "" {
+		expected = string(o.value)
+	}
+	Expect(d.Spec.Template.Spec.PriorityClassName).To(Equal(expected))
+}
+
 func getCDI(client realClient.Client, cdi *cdiv1.CDI) (*cdiv1.CDI, error) {
 	result, err := getObject(client, cdi)
 	if err != nil {
@@ -1370,12 +1551,14 @@ func setDeploymentsReady(args *args) bool {
 
 		d, err := getDeployment(args.client, d)
 		Expect(err).ToNot(HaveOccurred())
+		// Deployments without replicas run one pod
+		d.Status.Replicas = 1
 		if d.Spec.Replicas != nil {
 			d.Status.Replicas = *d.Spec.Replicas
-			d.Status.ReadyReplicas = d.Status.Replicas
-			err = args.client.Update(context.TODO(), d)
-			Expect(err).ToNot(HaveOccurred())
 		}
+		d.Status.ReadyReplicas = d.Status.Replicas
+		err = args.client.Update(context.TODO(), d)
+		Expect(err).ToNot(HaveOccurred())
 
 		doReconcile(args)
 
@@ -1402,12 +1585,10 @@ func setDeploymentsDegraded(args *args) {
 
 		d, err := getDeployment(args.client, d)
 		Expect(err).ToNot(HaveOccurred())
-		if d.Spec.Replicas != nil {
-			d.Status.Replicas = int32(0)
-			d.Status.ReadyReplicas = d.Status.Replicas
-			err = args.client.Update(context.TODO(), d)
-			Expect(err).ToNot(HaveOccurred())
-		}
+		d.Status.Replicas = int32(0)
+		d.Status.ReadyReplicas = d.Status.Replicas
+		err = args.client.Update(context.TODO(), d)
+		Expect(err).ToNot(HaveOccurred())
 
 	}
 	doReconcile(args)
@@ -1630,6 +1811,14 @@ func createNotReadyEventValidationMap() map[string]bool {
 	match[normalCreateSuccess+" *v1.ClusterRoleBinding cdi-sa"] = false
 	match[normalCreateSuccess+" *v1.CustomResourceDefinition datavolumes.cdi.kubevirt.io"] = false
 	match[normalCreateSuccess+" *v1.CustomResourceDefinition cdiconfigs.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition dataexports.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition clonegrants.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition volumeimportsources.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition volumeuploadsources.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition volumeclonesources.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition dataimportcrons.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition datasources.cdi.kubevirt.io"] = false
+	match[normalCreateSuccess+" *v1.CustomResourceDefinition storageprofiles.cdi.kubevirt.io"] = false
 	match[normalCreateSuccess+" *v1.ClusterRole cdi-uploadproxy"] = false
 	match[normalCreateSuccess+" *v1.ClusterRoleBinding cdi-uploadproxy"] = false
 	match[normalCreateSuccess+" *v1.ClusterRole cdi.kubevirt.io:admin"] = false
@@ -1637,6 +1826,7 @@ func createNotReadyEventValidationMap() map[string]bool {
 	match[normalCreateSuccess+" *v1.ClusterRole cdi.kubevirt.io:view"] = false
 	match[normalCreateSuccess+" *v1.ClusterRole cdi.kubevirt.io:config-reader"] = false
 	match[normalCreateSuccess+" *v1.ClusterRoleBinding cdi.kubevirt.io:config-reader"] = false
+	match[normalCreateSuccess+" *v1.PriorityClass cdi-cluster-critical"] = false
 	match[normalCreateSuccess+" *v1.ServiceAccount cdi-apiserver"] = false
 	match[normalCreateSuccess+" *v1.RoleBinding cdi-apiserver"] = false
 	match[normalCreateSuccess+" *v1.Role cdi-apiserver"] = false
@@ -1657,6 +1847,9 @@ func createNotReadyEventValidationMap() map[string]bool {
 	match[normalCreateSuccess+" *v1beta1.ValidatingWebhookConfiguration cdi-api-datavolume-validate"] = false
 	match[normalCreateSuccess+" *v1beta1.MutatingWebhookConfiguration cdi-api-datavolume-mutate"] = false
 	match[normalCreateSuccess+" *v1beta1.ValidatingWebhookConfiguration cdi-api-validate"] = false
+	match[normalCreateSuccess+" *v1beta1.ValidatingWebhookConfiguration cdi-api-storageprofile-validate"] = false
+	match[normalCreateSuccess+" *v1beta1.ValidatingWebhookConfiguration cdi-api-dataimportcron-validate"] = false
+	match[normalCreateSuccess+" *v1beta1.ValidatingWebhookConfiguration cdi-api-volumeimportsource-validate"] = false
 	match[normalCreateSuccess+" *v1.Secret cdi-apiserver-signer"] = false
 	match[normalCreateSuccess+" *v1.ConfigMap cdi-apiserver-signer-bundle"] = false
 	match[normalCreateSuccess+" *v1.Secret cdi-apiserver-server-cert"] = false
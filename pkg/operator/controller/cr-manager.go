@@ -26,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiregistrationv1beta1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
 
@@ -56,6 +57,7 @@ func (r *ReconcileCDI) GetDependantResourcesListObjects() []runtime.Object {
 		&rbacv1.RoleBindingList{},
 		&rbacv1.RoleList{},
 		&corev1.ServiceAccountList{},
+		&schedulingv1.PriorityClassList{},
 		&apiregistrationv1beta1.APIServiceList{},
 		&admissionregistrationv1beta1.ValidatingWebhookConfigurationList{},
 		&admissionregistrationv1beta1.MutatingWebhookConfigurationList{},
@@ -79,6 +81,28 @@ func (r *ReconcileCDI) getNamespacedArgs(cr *cdiv1.CDI) *cdinamespaced.FactoryAr
 			result.PullPolicy = string(cr.Spec.ImagePullPolicy)
 		}
 		result.InfraNodePlacement = &cr.Spec.Infra
+		if resources := cr.Spec.ComponentResources; resources != nil {
+			result.ControllerResources = resources.Controller
+			result.APIServerResources = resources.APIServer
+			result.UploadProxyResources = resources.UploadProxy
+		}
+		result.ImagePullSecrets = cr.Spec.ImagePullSecrets
+		if cr.Spec.PriorityClass != nil && *cr.Spec.PriorityClass != "" {
+			result.PriorityClassName = string(*cr.Spec.PriorityClass)
+		} else if sdk.DeployClusterResources() {
+			result.PriorityClassName = cdicluster.PriorityClassName
+		}
+		if images := cr.Spec.ImageOverrides; images != nil {
+			if images.Importer != "" {
+				result.ImporterImage = images.Importer
+			}
+			if images.Cloner != "" {
+				result.ClonerImage = images.Cloner
+			}
+			if images.UploadServer != "" {
+				result.UploadServerImage = images.UploadServer
+			}
+		}
 	}
 
 	return &result
@@ -26,6 +26,10 @@ func (r *ReconcileCDI) watch() error {
 		return err
 	}
 
+	if err := r.watchIngresses(); err != nil {
+		return err
+	}
+
 	if err := r.watchSecurityContextConstraints(); err != nil {
 		return err
 	}
@@ -68,7 +72,7 @@ func (r *ReconcileCDI) checkSanity(cr controllerutil.Object, reqLogger logr.Logg
 // sync syncs certificates used by CDU
 func (r *ReconcileCDI) sync(cr controllerutil.Object, logger logr.Logger) error {
 	cdi := cr.(*cdiv1.CDI)
-	return r.certManager.Sync(r.getCertificateDefinitions(cdi))
+	return r.syncCertificates(cdi, r.getCertificateDefinitions(cdi))
 }
 
 func (r *ReconcileCDI) configMapOwnerDeleted(cm *corev1.ConfigMap) (bool, error) {
@@ -19,26 +19,34 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 )
 
 const (
 	uploadProxyServiceName = "cdi-uploadproxy"
 	uploadProxyRouteName   = uploadProxyServiceName
+	uploadProxyIngressName = uploadProxyServiceName
 	uploadProxyCABundle    = "cdi-uploadproxy-signer-bundle"
 )
 
-func ensureUploadProxyRouteExists(logger logr.Logger, c client.Client, scheme *runtime.Scheme, owner metav1.Object) error {
+// ensureUploadProxyRouteExists creates the Route of the upload proxy on OpenShift, with the host name, certificate
+// and annotations of the exposure if it is of the Route type
+func ensureUploadProxyRouteExists(logger logr.Logger, c client.Client, scheme *runtime.Scheme, owner metav1.Object, exposure *cdiv1.UploadProxyExposure) error {
 	namespace := owner.GetNamespace()
 	if namespace == "" {
 		return fmt.Errorf("cluster scoped owner not supported")
@@ -83,16 +91,45 @@ func ensureUploadProxyRouteExists(logger logr.Logger, c client.Client, scheme *r
 		},
 	}
 
+	if exposure != nil && exposure.Type == cdiv1.UploadProxyExposureRoute {
+		desiredRoute.Spec.Host = exposure.Hostname
+		for k, v := range exposure.Annotations {
+			desiredRoute.Annotations[k] = v
+		}
+		if exposure.TLSSecretName != "" {
+			crt, key, err := getTLSSecretData(c, namespace, exposure.TLSSecretName)
+			if err != nil {
+				return err
+			}
+			desiredRoute.Spec.TLS.Certificate = crt
+			desiredRoute.Spec.TLS.Key = key
+		}
+	}
+
 	currentRoute := &routev1.Route{}
 	key = client.ObjectKey{Namespace: namespace, Name: uploadProxyRouteName}
 	err := c.Get(context.TODO(), key, currentRoute)
 	if err == nil {
 		if currentRoute.Spec.To.Kind != desiredRoute.Spec.To.Kind ||
 			currentRoute.Spec.To.Name != desiredRoute.Spec.To.Name ||
+			// The router generates the host name if it is not set
+			(desiredRoute.Spec.Host != "" && currentRoute.Spec.Host != desiredRoute.Spec.Host) ||
 			currentRoute.Spec.TLS == nil ||
 			currentRoute.Spec.TLS.Termination != desiredRoute.Spec.TLS.Termination ||
-			currentRoute.Spec.TLS.DestinationCACertificate != desiredRoute.Spec.TLS.DestinationCACertificate {
+			currentRoute.Spec.TLS.DestinationCACertificate != desiredRoute.Spec.TLS.DestinationCACertificate ||
+			currentRoute.Spec.TLS.Certificate != desiredRoute.Spec.TLS.Certificate ||
+			currentRoute.Spec.TLS.Key != desiredRoute.Spec.TLS.Key ||
+			!hasAnnotations(currentRoute, desiredRoute.Annotations) {
+			if desiredRoute.Spec.Host == "" {
+				desiredRoute.Spec.Host = currentRoute.Spec.Host
+			}
 			currentRoute.Spec = desiredRoute.Spec
+			if currentRoute.Annotations == nil {
+				currentRoute.Annotations = map[string]string{}
+			}
+			for k, v := range desiredRoute.Annotations {
+				currentRoute.Annotations[k] = v
+			}
 			return c.Update(context.TODO(), currentRoute)
 		}
 
@@ -116,6 +153,119 @@ func ensureUploadProxyRouteExists(logger logr.Logger, c client.Client, scheme *r
 	return c.Create(context.TODO(), desiredRoute)
 }
 
+// ensureUploadProxyIngress creates the Ingress of the upload proxy if the exposure is of the Ingress type, otherwise
+// it removes the Ingress created by the operator
+func ensureUploadProxyIngress(c client.Client, scheme *runtime.Scheme, owner metav1.Object, exposure *cdiv1.UploadProxyExposure) (bool, error) {
+	namespace := owner.GetNamespace()
+	if namespace == "" {
+		return false, fmt.Errorf("cluster scoped owner not supported")
+	}
+
+	currentIngress := &extensionsv1beta1.Ingress{}
+	key := client.ObjectKey{Namespace: namespace, Name: uploadProxyIngressName}
+	err := c.Get(context.TODO(), key, currentIngress)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	exists := err == nil
+
+	if exposure == nil || exposure.Type != cdiv1.UploadProxyExposureIngress {
+		// Ingresses created by hand are left alone
+		if exists && metav1.IsControlledBy(currentIngress, owner) {
+			return false, c.Delete(context.TODO(), currentIngress)
+		}
+		return false, nil
+	}
+
+	pathTypePrefix := extensionsv1beta1.PathTypePrefix
+	desiredIngress := &extensionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uploadProxyIngressName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"cdi.kubevirt.io": "",
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			IngressClassName: exposure.IngressClassName,
+			TLS: []extensionsv1beta1.IngressTLS{
+				{
+					Hosts:      []string{exposure.Hostname},
+					SecretName: exposure.TLSSecretName,
+				},
+			},
+			Rules: []extensionsv1beta1.IngressRule{
+				{
+					Host: exposure.Hostname,
+					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extensionsv1beta1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathTypePrefix,
+									Backend: extensionsv1beta1.IngressBackend{
+										ServiceName: uploadProxyServiceName,
+										ServicePort: intstr.FromInt(443),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for k, v := range exposure.Annotations {
+		desiredIngress.Annotations[k] = v
+	}
+
+	if exists {
+		if !reflect.DeepEqual(currentIngress.Spec, desiredIngress.Spec) || !hasAnnotations(currentIngress, desiredIngress.Annotations) {
+			currentIngress.Spec = desiredIngress.Spec
+			if currentIngress.Annotations == nil {
+				currentIngress.Annotations = map[string]string{}
+			}
+			for k, v := range desiredIngress.Annotations {
+				currentIngress.Annotations[k] = v
+			}
+			return true, c.Update(context.TODO(), currentIngress)
+		}
+		return true, nil
+	}
+
+	if err := controllerutil.SetControllerReference(owner, desiredIngress, scheme); err != nil {
+		return false, err
+	}
+
+	return true, c.Create(context.TODO(), desiredIngress)
+}
+
+func getTLSSecretData(c client.Client, namespace, name string) (string, string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return "", "", err
+	}
+	crt, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		return "", "", fmt.Errorf("secret %s has no %s", name, corev1.TLSCertKey)
+	}
+	key, ok := secret.Data[corev1.TLSPrivateKeyKey]
+	if !ok {
+		return "", "", fmt.Errorf("secret %s has no %s", name, corev1.TLSPrivateKeyKey)
+	}
+	return string(crt), string(key), nil
+}
+
+func hasAnnotations(obj metav1.Object, annotations map[string]string) bool {
+	for k, v := range annotations {
+		if current, ok := obj.GetAnnotations()[k]; !ok || current != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *ReconcileCDI) watchRoutes() error {
 	err := r.controller.Watch(
 		&source.Kind{Type: &routev1.Route{}},
@@ -132,3 +282,10 @@ func (r *ReconcileCDI) watchRoutes() error {
 
 	return nil
 }
+
+func (r *ReconcileCDI) watchIngresses() error {
+	return r.controller.Watch(
+		&source.Kind{Type: &extensionsv1beta1.Ingress{}},
+		enqueueCDI(r.client),
+	)
+}
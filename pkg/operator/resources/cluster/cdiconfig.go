@@ -241,6 +241,20 @@ func createCDIConfigCRD() *extv1.CustomResourceDefinition {
 									Description: "CDIConfigSpec defines specification for user configuration",
 									Type:        "object",
 									Properties: map[string]extv1.JSONSchemaProps{
+										"dataVolumeTTLSeconds": {
+											Description: "DataVolumeTTLSeconds is the time in seconds after the completion of a DataVolume before it is deleted, its PVC is kept. DataVolumes whose PVC is used by a pod or which are referenced by a VirtualMachine are kept until they are not anymore. The DataVolumes are kept if not set",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"fileSourceHostPathPrefixes": {
+											Description: "FileSourceHostPathPrefixes are the directories of the nodes the host paths of File sources must be in, host paths are rejected if it is empty",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+											Type: "array",
+										},
 										"featureGates": {
 											Description: "FeatureGates are a list of specific enabled feature gates",
 											Items: &extv1.JSONSchemaPropsOrArray{
@@ -254,10 +268,88 @@ func createCDIConfigCRD() *extv1.CustomResourceDefinition {
 											Description: "Override the URL used when uploading to a DataVolume",
 											Type:        "string",
 										},
+										"uploadProxyClientCAConfigMap": {
+											Description: "UploadProxyClientCAConfigMap is the name of a ConfigMap in the CDI namespace holding, in its ca-bundle.crt key, the CA bundle of the client certificates the upload proxy requires in addition to the upload token. If not set client certificates are not required",
+											Type:        "string",
+										},
+										"uploadProxyRateLimits": {
+											Description: "UploadProxyRateLimits limits the concurrent uploads and the bandwidth of the uploads through the upload proxy, per namespace and per PVC",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"maxBandwidthPerNamespace": {
+													Description: "MaxBandwidthPerNamespace is the maximum number of bytes per second uploaded to the PVCs of a namespace",
+													AnyOf: []extv1.JSONSchemaProps{
+														{
+															Type: "integer",
+														},
+														{
+															Type: "string",
+														},
+													},
+													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+													XIntOrString: true,
+												},
+												"maxBandwidthPerPVC": {
+													Description: "MaxBandwidthPerPVC is the maximum number of bytes per second uploaded to a PVC",
+													AnyOf: []extv1.JSONSchemaProps{
+														{
+															Type: "integer",
+														},
+														{
+															Type: "string",
+														},
+													},
+													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+													XIntOrString: true,
+												},
+												"maxConcurrentUploadsPerNamespace": {
+													Description: "MaxConcurrentUploadsPerNamespace is the maximum number of uploads proxied at the same time to the PVCs of a namespace",
+													Type:        "integer",
+													Format:      "int32",
+												},
+												"maxConcurrentUploadsPerPVC": {
+													Description: "MaxConcurrentUploadsPerPVC is the maximum number of uploads proxied at the same time to a PVC, with any of its upload tokens",
+													Type:        "integer",
+													Format:      "int32",
+												},
+											},
+											Type: "object",
+										},
+										"uploadTokenTTL": {
+											Description: "UploadTokenTTL is the lifetime of the upload and download tokens issued by the CDI API server, 5 minutes if not set. Tokens can be renewed before they expire",
+											Type:        "string",
+										},
 										"scratchSpaceStorageClass": {
 											Description: "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
 											Type:        "string",
 										},
+										"importRetryPolicy": {
+											Description: "ImportRetryPolicy is the default retry policy of the failed imports, the importer pods restart with the backoff of the kubelet without it",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"initialBackoff": {
+													Description: "InitialBackoff is the delay before the first retry, 10s when unset",
+													Type:        "string",
+												},
+												"maxAttempts": {
+													Description: "MaxAttempts is the number of attempts of the import before the DataVolume fails for good, unlimited when unset",
+													Type:        "integer",
+													Format:      "int32",
+												},
+												"maxBackoff": {
+													Description: "MaxBackoff caps the delay between two attempts, 5m when unset",
+													Type:        "string",
+												},
+											},
+										},
+										"maxParallelImports": {
+											Description: "MaxParallelImports is the maximum number of importer pods running at the same time in the cluster, the other imports wait in the Pending phase",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"podPriorityClassName": {
+											Description: "PodPriorityClassName is the default priority class of the importer, upload and clone pods",
+											Type:        "string",
+										},
 										"podResourceRequirements": {
 											Description: "ResourceRequirements describes the compute resource requirements.",
 											Type:        "object",
@@ -322,10 +414,112 @@ func createCDIConfigCRD() *extv1.CustomResourceDefinition {
 												},
 											},
 										},
+										"podSecurity": {
+											Description: "PodSecurity overrides the security context of the importer, upload server and clone source pods, which comply with the restricted Pod Security Standard by default",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"runAsUser": {
+													Description: "RunAsUser is the user the pods run as, 107 (qemu) if not set",
+													Format:      "int64",
+													Type:        "integer",
+												},
+												"seLinuxOptions": {
+													Description: "SELinuxOptions are the SELinux options of the pods, those of the container runtime if not set",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"level": {
+															Description: "Level is SELinux level label that applies to the container.",
+															Type:        "string",
+														},
+														"role": {
+															Description: "Role is a SELinux role label that applies to the container.",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type is a SELinux type label that applies to the container.",
+															Type:        "string",
+														},
+														"user": {
+															Description: "User is a SELinux user label that applies to the container.",
+															Type:        "string",
+														},
+													},
+													Type: "object",
+												},
+												"seccompProfile": {
+													Description: "SeccompProfile is the seccomp profile of the pods, runtime/default if not set",
+													Type:        "string",
+												},
+											},
+											Type: "object",
+										},
 										"preallocation": {
 											Description: "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
 											Type:        "boolean",
 										},
+										"registries": {
+											Description: "Registries configures the mirrors and the insecure registries consulted by registry imports",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "RegistryConfig defines the mirrors of a registry, or of a namespace of a registry, tried before the registry itself",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"insecure": {
+															Description: "Insecure allows plain HTTP and TLS connections without certificate verification to the registry",
+															Type:        "boolean",
+														},
+														"mirrors": {
+															Description: "Mirrors are tried in order before the registry, the images are pulled from the registry if none of them has the image",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Description: "RegistryMirror defines a mirror of a registry",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"insecure": {
+																			Description: "Insecure allows plain HTTP and TLS connections without certificate verification to the mirror",
+																			Type:        "boolean",
+																		},
+																		"location": {
+																			Description: "Location is the host[:port] of the mirror, optionally followed by a namespace replacing the one of the registry, for instance mirror.example.com:5000/quay",
+																			Type:        "string",
+																		},
+																	},
+																	Required: []string{
+																		"location",
+																	},
+																	Type: "object",
+																},
+															},
+															Type: "array",
+														},
+														"registry": {
+															Description: "Registry is the host[:port] of the registry, optionally followed by a namespace, for instance quay.io/kubevirt. The configuration applies to the images under it",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"registry",
+													},
+													Type: "object",
+												},
+											},
+											Type: "array",
+										},
+										"tlsConfig": {
+											Description: "TLSConfig controls the minimum TLS version and the cipher suites used by the CDI servers and the http clients of the importer",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"ciphers": {
+													Description: "Ciphers is the list of allowed cipher suites using the IANA names, for instance TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The cipher suites of TLS 1.3 are not configurable. If not set the default of the component is used",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+													Type: "array",
+												},
+												"minVersion": {
+													Description: "MinVersion is the minimum TLS version, one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13. If not set the default of the component is used",
+													Type:        "string",
+												},
+											},
+											Type: "object",
+										},
 									},
 								},
 								"status": {
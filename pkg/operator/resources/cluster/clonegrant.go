@@ -0,0 +1,191 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewCloneGrantCrd - provides CloneGrant CRD
+func NewCloneGrantCrd() *extv1.CustomResourceDefinition {
+	return createCloneGrantCRD()
+}
+
+// createCloneGrantCRD creates the CloneGrant schema
+func createCloneGrantCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "clonegrants.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "CloneGrant",
+				Plural: "clonegrants",
+				ShortNames: []string{
+					"cg",
+					"cgs",
+				},
+				ListKind: "CloneGrantList",
+				Singular: "clonegrant",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:         "v1beta1",
+					Served:       true,
+					Storage:      true,
+					Subresources: &extv1.CustomResourceSubresources{},
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "CloneGrant allows the DataVolumes of a target namespace to clone the PVCs of the namespace of the grant, in place of the short-lived clone token of the DataVolumes",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "CloneGrantSpec defines the CloneGrant type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"expirationTime": {
+											Description: "ExpirationTime is the time after which the grant no longer allows clones, the grant does not expire when unset",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"selector": {
+											Description: "Selector selects the PVCs that may be cloned, all the PVCs of the namespace may be cloned when unset",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"matchExpressions": {
+													Description: "matchExpressions is a list of label selector requirements. The requirements are ANDed.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Description: "A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"key": {
+																	Description: "key is the label key that the selector applies to.",
+																	Type:        "string",
+																},
+																"operator": {
+																	Description: "operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.",
+																	Type:        "string",
+																},
+																"values": {
+																	Description: "values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type: "string",
+																		},
+																	},
+																},
+															},
+															Required: []string{
+																"key",
+																"operator",
+															},
+														},
+													},
+												},
+												"matchLabels": {
+													Description: "matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is \"key\", the operator is \"In\", and the values array contains only \"value\". The requirements are ANDed.",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+											},
+										},
+										"targetNamespace": {
+											Description: "TargetNamespace is the namespace whose DataVolumes may clone the PVCs of the namespace of the grant",
+											Type:        "string",
+										},
+									},
+									Required: []string{
+										"targetNamespace",
+									},
+								},
+								"status": {
+									Description: "CloneGrantStatus is the status of a CloneGrant, recording the clones it allowed",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"cloneCount": {
+											Description: "CloneCount is the number of clones the grant allowed",
+											Type:        "integer",
+											Format:      "int64",
+										},
+										"lastCloneTime": {
+											Description: "LastCloneTime is the time the grant last allowed a clone",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"lastSource": {
+											Description: "LastSource is the name of the source PVC of the last clone the grant allowed",
+											Type:        "string",
+										},
+										"lastTarget": {
+											Description: "LastTarget is the namespace/name of the target PVC of the last clone the grant allowed",
+											Type:        "string",
+										},
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Target",
+							Type:        "string",
+							Description: "The namespace allowed to clone",
+							JSONPath:    ".spec.targetNamespace",
+						},
+						{
+							Name:        "Expiration",
+							Type:        "string",
+							Description: "The time the grant expires",
+							JSONPath:    ".spec.expirationTime",
+						},
+						{
+							Name:        "Clones",
+							Type:        "integer",
+							Description: "The number of clones the grant allowed",
+							JSONPath:    ".status.cloneCount",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
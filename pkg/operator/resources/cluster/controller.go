@@ -82,6 +82,20 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"persistentvolumes",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
@@ -98,6 +112,23 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 				"delete",
 			},
 		},
+		{
+			// The CronJobs polling the sources of the DataImportCrons
+			APIGroups: []string{
+				"batch",
+			},
+			Resources: []string{
+				"cronjobs",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"create",
+				"update",
+				"delete",
+			},
+		},
 		{
 			APIGroups: []string{
 				"extensions",
@@ -120,6 +151,24 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 			},
 			Verbs: []string{
 				"get",
+				"create",
+			},
+		},
+		{
+			// The copies of the trusted CA bundle in the namespaces of the CDI pods
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"configmaps",
+			},
+			ResourceNames: []string{
+				"cdi-trusted-ca",
+			},
+			Verbs: []string{
+				"list",
+				"update",
+				"delete",
 			},
 		},
 		{
@@ -135,6 +184,28 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 				"watch",
 			},
 		},
+		{
+			APIGroups: []string{
+				"storage.k8s.io",
+			},
+			Resources: []string{
+				"csidrivers",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"namespaces",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
 		{
 			APIGroups: []string{
 				"route.openshift.io",
@@ -170,6 +241,17 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"kubevirt.io",
+			},
+			Resources: []string{
+				"virtualmachines",
+			},
+			Verbs: []string{
+				"list",
+			},
+		},
 		{
 			APIGroups: []string{
 				"apiextensions.k8s.io",
@@ -0,0 +1,330 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewDataExportCrd - provides DataExport CRD
+func NewDataExportCrd() *extv1.CustomResourceDefinition {
+	return createDataExportCRD()
+}
+
+// createDataExportCRD creates the DataExport schema
+func createDataExportCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "dataexports.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "DataExport",
+				Plural: "dataexports",
+				ShortNames: []string{
+					"dx",
+					"dxs",
+				},
+				ListKind: "DataExportList",
+				Singular: "dataexport",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:         "v1beta1",
+					Served:       true,
+					Storage:      true,
+					Subresources: &extv1.CustomResourceSubresources{},
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "DataExport writes the disk of a PVC or DataVolume to a target outside of the cluster",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "DataExportSpec defines the DataExport type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"compression": {
+											Description: "Compression is the compression of the clusters of qcow2 images, zlib, zstd or none, defaults to zlib",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"zlib"`),
+												},
+												{
+													Raw: []byte(`"zstd"`),
+												},
+												{
+													Raw: []byte(`"none"`),
+												},
+											},
+										},
+										"format": {
+											Description: "Format is the format of the exported disk image, qcow2 or raw, defaults to qcow2",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"qcow2"`),
+												},
+												{
+													Raw: []byte(`"raw"`),
+												},
+											},
+										},
+										"source": {
+											Description: "Source is the PVC, DataVolume or VolumeSnapshot whose disk is exported",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"dataVolume": {
+													Description: "DataVolume is the name of the exported DataVolume, the export starts once the DataVolume succeeded",
+													Type:        "string",
+												},
+												"pvc": {
+													Description: "PVC is the name of the exported PVC",
+													Type:        "string",
+												},
+												"volumeSnapshot": {
+													Description: "VolumeSnapshot is the name of the exported VolumeSnapshot, the disk is read from a temporary PVC restored from the snapshot",
+													Type:        "string",
+												},
+											},
+										},
+										"target": {
+											Description: "Target is where the disk is written to",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"cdi": {
+													Description: "CDI uploads the disk to a DataVolume of another cluster, resuming after network interruptions",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"certConfigMap": {
+															Description: "CertConfigMap is a configmap containing the Certificate Authorities of the upload proxy",
+															Type:        "string",
+														},
+														"tokenSecretRef": {
+															Description: "TokenSecretRef is the secret containing the upload token of the target DataVolume in its token key. The token is read again before each request, so it can be renewed in the secret during long exports",
+															Type:        "string",
+														},
+														"url": {
+															Description: "URL is the url of the upload proxy of the other cluster, for instance https://cdi-uploadproxy.dr.example.com",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"tokenSecretRef",
+														"url",
+													},
+												},
+												"http": {
+													Description: "HTTP writes the disk with an HTTP PUT, to a WebDAV server or an artifact store",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"certConfigMap": {
+															Description: "CertConfigMap is a configmap containing the Certificate Authorities of the server",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing the accessKeyId (user name) and secretKey (password) of the basic authentication of the server",
+															Type:        "string",
+														},
+														"url": {
+															Description: "URL is the url the disk is written to, for instance https://nexus.example.com/repository/disks/fedora.qcow2",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"registry": {
+													Description: "Registry pushes the disk as a containerDisk image to a container registry",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"certConfigMap": {
+															Description: "CertConfigMap is a configmap containing the Certificate Authorities of the registry",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is a secret of type kubernetes.io/dockerconfigjson with the credentials of the registry",
+															Type:        "string",
+														},
+														"url": {
+															Description: "URL is the image the disk is pushed to, for instance docker://quay.io/kubevirt/fedora:35",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"s3": {
+													Description: "S3 writes the disk to an object of an S3 bucket",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"partSize": {
+															Description: "PartSize is the size of the parts of the multipart upload, defaults to 64Mi",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+														"region": {
+															Description: "Region is the region of the bucket, by default the region is derived from the host of the URL",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing the accessKeyId and secretKey used to write the object. Without a secretRef the credentials of the pod are used",
+															Type:        "string",
+														},
+														"url": {
+															Description: "URL is the url of the S3 object, for instance https://s3.us-east-1.amazonaws.com/bucket/fedora.qcow2",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+											},
+										},
+										"ttlSecondsAfterFinished": {
+											Description: "TTLSecondsAfterFinished is the time the DataExport is kept once it succeeded before it is deleted, it is kept until deleted when unset",
+											Type:        "integer",
+											Format:      "int32",
+										},
+									},
+									Required: []string{
+										"source",
+										"target",
+									},
+								},
+								"status": {
+									Description: "DataExportStatus is the status of a DataExport",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"completionTime": {
+											Description: "CompletionTime is the time the export completed",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"conditions": {
+											Description: "Conditions are the SourceReady, Running and Ready conditions of the export",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "DataVolumeCondition represents the state of a data volume condition.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"lastHeartbeatTime": {
+															Type:   "string",
+															Format: "date-time",
+														},
+														"lastTransitionTime": {
+															Type:   "string",
+															Format: "date-time",
+														},
+														"message": {
+															Type: "string",
+														},
+														"reason": {
+															Type: "string",
+														},
+														"status": {
+															Type: "string",
+														},
+														"type": {
+															Description: "DataVolumeConditionType is the string representation of known condition types",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"status",
+														"type",
+													},
+												},
+											},
+											Type: "array",
+										},
+										"message": {
+											Description: "Message explains why the export is pending, or the last failure of the export pod",
+											Type:        "string",
+										},
+										"phase": {
+											Description: "Phase is the current phase of the export",
+											Type:        "string",
+										},
+										"progress": {
+											Description: "Progress is the percentage of the disk written to the target",
+											Type:        "string",
+										},
+										"restartCount": {
+											Description: "RestartCount is the number of times the export pod has restarted",
+											Type:        "integer",
+											Format:      "int32",
+										},
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Phase",
+							Type:        "string",
+							Description: "The phase the export is in",
+							JSONPath:    ".status.phase",
+						},
+						{
+							Name:        "Progress",
+							Type:        "string",
+							Description: "Export progress in percentage if known, N/A otherwise",
+							JSONPath:    ".status.progress",
+						},
+						{
+							Name:        "Restarts",
+							Type:        "integer",
+							Description: "The number of times the export has been restarted.",
+							JSONPath:    ".status.restartCount",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
@@ -0,0 +1,229 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewDataImportCronCrd - provides DataImportCron CRD
+func NewDataImportCronCrd() *extv1.CustomResourceDefinition {
+	return createDataImportCronCRD()
+}
+
+// dataVolumeSchema returns the schema of the v1beta1 DataVolumes, for the DataVolume templates
+func dataVolumeSchema(description string) extv1.JSONSchemaProps {
+	for _, version := range createDataVolumeCRD().Spec.Versions {
+		if version.Name == "v1beta1" {
+			schema := *version.Schema.OpenAPIV3Schema.DeepCopy()
+			schema.Description = description
+			return schema
+		}
+	}
+	panic("no v1beta1 DataVolume schema")
+}
+
+// createDataImportCronCRD creates the DataImportCron schema
+func createDataImportCronCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "dataimportcrons.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "DataImportCron",
+				Plural: "dataimportcrons",
+				ShortNames: []string{
+					"dic",
+					"dics",
+				},
+				ListKind: "DataImportCronList",
+				Singular: "dataimportcron",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:         "v1beta1",
+					Served:       true,
+					Storage:      true,
+					Subresources: &extv1.CustomResourceSubresources{},
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "DataImportCron polls the registry, http or s3 source of a DataVolume template on a schedule and imports each new image as a golden image, keeping the latest imports and pointing a DataSource at the PVC of the latest one",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "DataImportCronSpec defines the DataImportCron type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"garbageCollect": {
+											Description: "GarbageCollect is Outdated, the default, to delete the imports which are not retained anymore, or Never to keep all the imports",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"Outdated"`),
+												},
+												{
+													Raw: []byte(`"Never"`),
+												},
+											},
+										},
+										"importsToKeep": {
+											Description: "ImportsToKeep is the number of the latest imports retained, 3 by default",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"managedDataSource": {
+											Description: "ManagedDataSource is the name of the DataSource pointing at the PVC of the latest import, in the namespace of the DataImportCron",
+											Type:        "string",
+										},
+										"notification": {
+											Description: "Notification is the webhook notified when the DataSource points at a new golden image",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"certConfigMap": {
+													Description: "CertConfigMap is a ConfigMap, in the namespace of the DataImportCron, with the CA bundle verifying the certificate of the endpoint",
+													Type:        "string",
+												},
+												"url": {
+													Description: "URL is the http(s) endpoint the new golden images are posted to",
+													Type:        "string",
+												},
+											},
+											Required: []string{
+												"url",
+											},
+										},
+										"pinnedDigest": {
+											Description: "PinnedDigest pins the DataSource to the import of the digest, which is imported if it is not retained. New images are still imported, the DataSource points at the latest one again once the pin is removed",
+											Type:        "string",
+										},
+										"retentionPeriod": {
+											Description: "RetentionPeriod retains the imports newer than the period as well, whatever their number",
+											Type:        "string",
+										},
+										"schedule": {
+											Description: "Schedule is when the source is polled, in the cron format of CronJobs",
+											Type:        "string",
+										},
+										"template": dataVolumeSchema("Template is the DataVolume of the imports, its source is polled for new images"),
+									},
+									Required: []string{
+										"managedDataSource",
+										"schedule",
+										"template",
+									},
+								},
+								"status": {
+									Description: "DataImportCronStatus is the status of a DataImportCron",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"currentDigest": {
+											Description: "CurrentDigest is the digest of the import the DataSource points at",
+											Type:        "string",
+										},
+										"imports": {
+											Description: "Imports are the imports of the DataImportCron which are not garbage collected yet, the latest first",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "DataImportCronImport is an import of a new image of the source of a DataImportCron",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"dataVolumeName": {
+															Description: "DataVolumeName is the name of the DataVolume of the import, and of its PVC",
+															Type:        "string",
+														},
+														"digest": {
+															Description: "Digest is the digest of the imported image, or of the validators of the imported http or s3 object",
+															Type:        "string",
+														},
+														"timestamp": {
+															Description: "Timestamp is the time the import started, the retention period of the import starts then",
+															Type:        "string",
+															Format:      "date-time",
+														},
+													},
+													Required: []string{
+														"dataVolumeName",
+														"digest",
+														"timestamp",
+													},
+												},
+											},
+											Type: "array",
+										},
+										"lastExecutionTimestamp": {
+											Description: "LastExecutionTimestamp is the time the source was last polled",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"lastImportTimestamp": {
+											Description: "LastImportTimestamp is the time the latest import succeeded",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"notifiedDigest": {
+											Description: "NotifiedDigest is the digest of the last golden image the DataImportCron notified of",
+											Type:        "string",
+										},
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Schedule",
+							Type:        "string",
+							Description: "The schedule of the polls of the source",
+							JSONPath:    ".spec.schedule",
+						},
+						{
+							Name:        "DataSource",
+							Type:        "string",
+							Description: "The DataSource pointing at the latest import",
+							JSONPath:    ".spec.managedDataSource",
+						},
+						{
+							Name:        "Last Import",
+							Type:        "date",
+							Description: "The time of the last import",
+							JSONPath:    ".status.lastImportTimestamp",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
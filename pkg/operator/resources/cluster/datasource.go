@@ -0,0 +1,229 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewDataSourceCrd - provides DataSource CRD
+func NewDataSourceCrd() *extv1.CustomResourceDefinition {
+	return createDataSourceCRD()
+}
+
+// createDataSourceCRD creates the DataSource schema
+func createDataSourceCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "datasources.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "DataSource",
+				Plural: "datasources",
+				ShortNames: []string{
+					"das",
+				},
+				ListKind: "DataSourceList",
+				Singular: "datasource",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1beta1",
+					Served:  true,
+					Storage: true,
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "DataSource references the PVC of a golden image, so its consumers keep the same reference when the PVC is replaced by a newer import",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "DataSourceSpec defines the DataSource type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"source": {
+											Description: "Source is the source of the data referenced by the DataSource",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"pvc": {
+													Description: "PVC is the PVC of the golden image",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Description: "The name of the source PVC",
+															Type:        "string",
+														},
+														"namespace": {
+															Description: "The namespace of the source PVC",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"name",
+														"namespace",
+													},
+												},
+											},
+										},
+										"fallbacks": {
+											Description: "Fallbacks are the sources used, in their order, while the source is not ready, like when its golden image is missing or being replaced",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "DataSourceSource is the source of the data referenced by a DataSource",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"pvc": {
+															Description: "PVC is the PVC of the golden image",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {
+																	Description: "The name of the source PVC",
+																	Type:        "string",
+																},
+																"namespace": {
+																	Description: "The namespace of the source PVC",
+																	Type:        "string",
+																},
+															},
+															Required: []string{
+																"name",
+																"namespace",
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+									Required: []string{
+										"source",
+									},
+								},
+								"status": {
+									Description: "DataSourceStatus is the status of a DataSource",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"source": {
+											Description: "Source is the first ready source among the source and the fallbacks, the one its consumers clone",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"pvc": {
+													Description: "PVC is the PVC of the golden image",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Description: "The name of the source PVC",
+															Type:        "string",
+														},
+														"namespace": {
+															Description: "The namespace of the source PVC",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"name",
+														"namespace",
+													},
+												},
+											},
+										},
+										"conditions": {
+											Description: "Conditions holds the Ready condition of the DataSource, its reason tells whether the source or a fallback is used",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "DataVolumeCondition represents the state of a data volume condition.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"lastHeartbeatTime": {
+															Type:   "string",
+															Format: "date-time",
+														},
+														"lastTransitionTime": {
+															Type:   "string",
+															Format: "date-time",
+														},
+														"message": {
+															Type: "string",
+														},
+														"reason": {
+															Type: "string",
+														},
+														"status": {
+															Type: "string",
+														},
+														"type": {
+															Description: "DataVolumeConditionType is the string representation of known condition types",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"status",
+														"type",
+													},
+												},
+											},
+											Type: "array",
+										},
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:        "PVC",
+							Type:        "string",
+							Description: "The PVC of the ready source",
+							JSONPath:    ".status.source.pvc.name",
+						},
+						{
+							Name:        "Ready",
+							Type:        "string",
+							Description: "Whether a source is ready",
+							JSONPath:    ".status.conditions[?(@.type=='Ready')].status",
+						},
+						{
+							Name:        "Reason",
+							Type:        "string",
+							Description: "Why the source is ready or not",
+							JSONPath:    ".status.conditions[?(@.type=='Ready')].reason",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
@@ -541,100 +541,701 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 											Type:        "object",
 											Properties: map[string]extv1.JSONSchemaProps{
 												"http": {
-													Description: "DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
+													Description: "DataVolumeSourceHTTP can be either an http, https, ftp or ftps endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
 													Type:        "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"url": {
-															Description: "URL is the URL of the http(s) endpoint",
+															Description: "URL is the URL of the http(s) or ftp(s) endpoint",
 															Type:        "string",
 														},
 														"secretRef": {
 															Description: "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded",
 															Type:        "string",
 														},
-														"certConfigMap": {
-															Description: "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate",
+														"ftpPassive": {
+															Description: "FTPPassive selects passive mode for ftp and ftps URLs, the default, set it to false to use active mode where the server connects back to the importer pod",
+															Type:        "boolean",
+														},
+														"tokenSecretRef": {
+															Description: "TokenSecretRef A Secret reference, the secret should contain a bearer token (token) base64 encoded, that is sent in the Authorization header",
+															Type:        "string",
+														},
+														"oauth2": {
+															Description: "OAuth2 provides the parameters to retrieve bearer tokens using the OAuth2 client credentials flow",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"tokenURL": {
+																	Description: "TokenURL is the URL of the OAuth2 token endpoint",
+																	Type:        "string",
+																},
+																"secretRef": {
+																	Description: "SecretRef A Secret reference, the secret should contain clientId and clientSecret base64 encoded",
+																	Type:        "string",
+																},
+																"scopes": {
+																	Description: "Scopes is the list of scopes to request",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type: "string",
+																		},
+																	},
+																},
+															},
+															Required: []string{
+																"secretRef",
+																"tokenURL",
+															},
+														},
+														"certConfigMap": {
+															Description: "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate",
+															Type:        "string",
+														},
+														"clientCertSecretRef": {
+															Description: "ClientCertSecretRef A Secret reference of type kubernetes.io/tls, the client certificate (tls.crt) and key (tls.key) are presented to the endpoint",
+															Type:        "string",
+														},
+														"segments": {
+															Description: "Segments is the number of concurrent ranged requests used to download the image into scratch space before conversion, requires the server to support ranges",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"segmentSize": {
+															Description: "SegmentSize is the size of each ranged request, if not set the image is split evenly between the segments",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"hyperV": {
+													Description: "DataVolumeSourceHyperV provides the parameters to create a Data Volume from a VHDX or VHD disk of a Hyper-V virtual machine on an SMB share",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"server": {
+															Description: "Server is the host name or address of the SMB server",
+															Type:        "string",
+														},
+														"share": {
+															Description: "Share is the name of the SMB share",
+															Type:        "string",
+														},
+														"path": {
+															Description: "Path is the path of the disk in the share, for instance VMs/fedora/Virtual Hard Disks/fedora.vhdx",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set",
+															Type:        "string",
+														},
+														"domain": {
+															Description: "Domain is the domain or workgroup of the user",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"path",
+														"server",
+														"share",
+													},
+												},
+												"imageio": {
+													Description: "DataVolumeSourceImageIO provides the parameters to create a Data Volume from an imageio source",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the URL of the ovirt-engine",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the ovirt-engine",
+															Type:        "string",
+														},
+														"certConfigMap": {
+															Description: "CertConfigMap provides a reference to the CA cert",
+															Type:        "string",
+														},
+														"diskId": {
+															Description: "DiskID provides id of a disk to be imported",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"diskId",
+														"url",
+													},
+												},
+												"awsSnapshot": {
+													Description: "DataVolumeSourceAWSSnapshot provides the parameters to create a Data Volume from an EBS snapshot or AMI, using the EBS direct APIs",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"region": {
+															Description: "Region is the region of the snapshot",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret containing the accessKeyId and secretKey. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with",
+															Type:        "string",
+														},
+														"snapshotId": {
+															Description: "SnapshotID is the ID of the EBS snapshot, or the ID of an AMI whose root device snapshot is imported. With checkpoints the snapshot of the current checkpoint is imported instead",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"region",
+														"snapshotId",
+													},
+												},
+												"azureBlob": {
+													Description: "DataVolumeSourceAzureBlob provides the parameters to create a Data Volume from an Azure Blob Storage blob",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the url of the blob, https://account.blob.core.windows.net/container/blob",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing either a SAS token in the sasToken key or the storage account key in the accountKey key. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity",
+															Type:        "string",
+														},
+														"segments": {
+															Description: "Segments is the number of concurrent ranged requests used to download the blob",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"segmentSize": {
+															Description: "SegmentSize is the size of each ranged request, if not set the blob is split evenly between the segments",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"azureDisk": {
+													Description: "DataVolumeSourceAzureDisk provides the parameters to create a Data Volume from an Azure managed disk or snapshot",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"resourceId": {
+															Description: "ResourceID is the resource ID of the managed disk or snapshot, /subscriptions/<subscription>/resourceGroups/<group>/providers/Microsoft.Compute/snapshots/<name>",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing the tenantId, clientId and clientSecret of a service principal. Without a secretRef the credentials of the pod are used, for instance Azure AD workload identity",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with, used with Azure AD workload identity",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"resourceId",
+													},
+												},
+												"gceImage": {
+													Description: "DataVolumeSourceGCEImage provides the parameters to create a Data Volume from a Google Compute Engine image",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"exportUrl": {
+															Description: "ExportURL is the gs:// url of the image exported with gcloud compute images export, required if the image was not created from a tar.gz in GCS",
+															Type:        "string",
+														},
+														"image": {
+															Description: "Image is the image to import, projects/<project>/global/images/<name> or projects/<project>/global/images/family/<family>",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"image",
+													},
+												},
+												"gcs": {
+													Description: "DataVolumeSourceGCS provides the parameters to create a Data Volume from a Google Cloud Storage object",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the url of the GCS object, gs://bucket/object",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef is the secret containing the JSON key of a Google service account in the serviceAccountKey key. Without a secretRef the credentials of the pod are used, for instance GKE workload identity",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with, used with GKE workload identity",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"glance": {
+													Description: "DataVolumeSourceGlance provides the parameters to create a Data Volume from an OpenStack Glance image",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"authUrl": {
+															Description: "AuthURL is the URL of the Keystone v3 identity service, for instance https://keystone.example.com:5000/v3",
+															Type:        "string",
+														},
+														"project": {
+															Description: "Project is the name of the project the image is visible to",
+															Type:        "string",
+														},
+														"domain": {
+															Description: "Domain is the name of the domain of the user and the project, defaults to Default",
+															Type:        "string",
+														},
+														"region": {
+															Description: "Region is the region of the image service in the service catalog, if not set the first public image endpoint is used",
+															Type:        "string",
+														},
+														"imageId": {
+															Description: "ImageID is the UUID of the image",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access Keystone, the secret should contain accessKeyId (user name) and secretKey (password)",
+															Type:        "string",
+														},
+														"certConfigMap": {
+															Description: "CertConfigMap provides a reference to the CA cert of Keystone and Glance",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"authUrl",
+														"imageId",
+														"project",
+														"secretRef",
+													},
+												},
+												"proxmox": {
+													Description: "DataVolumeSourceProxmox provides the parameters to create a Data Volume from the disk of a Proxmox VE virtual machine",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the URL of the API of a node of the Proxmox VE cluster, for instance https://pve.example.com:8006",
+															Type:        "string",
+														},
+														"node": {
+															Description: "Node is the name of the node of the VM, if not set the node is looked up in the cluster resources",
+															Type:        "string",
+														},
+														"vmId": {
+															Description: "VMID is the ID of the VM, the VM must be stopped",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"disk": {
+															Description: "Disk is the key of the disk in the configuration of the VM, for instance scsi0 or virtio1",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the API, the secret should contain accessKeyId (API token ID, user@realm!token) and secretKey (API token secret)",
+															Type:        "string",
+														},
+														"certConfigMap": {
+															Description: "CertConfigMap provides a reference to the CA cert of the API",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"disk",
+														"secretRef",
+														"url",
+														"vmId",
+													},
+												},
+												"s3": {
+													Description: "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the url of the S3 source",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the S3 source",
+															Type:        "string",
+														},
+														"serviceAccountName": {
+															Description: "ServiceAccountName is the service account the importer pod runs with. Without a secretRef the credentials of the pod are used, for instance the IAM role of the service account",
+															Type:        "string",
+														},
+														"region": {
+															Description: "Region is the region of the bucket, by default the region is derived from the host of the URL",
+															Type:        "string",
+														},
+														"forcePathStyle": {
+															Description: "ForcePathStyle addresses the bucket in the path of the request instead of the host name, defaults to true",
+															Type:        "boolean",
+														},
+														"sseCustomerKeySecretRef": {
+															Description: "SSECustomerKeySecretRef is the secret containing the base64 encoded 256-bit AES key in the sseCustomerKey key, used to read objects encrypted with SSE-C",
+															Type:        "string",
+														},
+														"segments": {
+															Description: "Segments is the number of concurrent ranged requests used to download the object",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"segmentSize": {
+															Description: "SegmentSize is the size of each ranged request, if not set the object is split evenly between the segments",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+														"objectVersionId": {
+															Description: "ObjectVersionID is the version of the object to import from a versioned bucket, by default the latest version is imported",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"registry": {
+													Description: "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"url": {
+															Description: "URL is the url of the Docker registry source",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the Registry source",
+															Type:        "string",
+														},
+														"certConfigMap": {
+															Description: "CertConfigMap provides a reference to the Registry certs",
+															Type:        "string",
+														},
+														"platform": {
+															Description: "Platform selects the image of a manifest list as os/arch[/variant], for instance linux/arm64. If not set the image of the platform of the node running the import is selected",
+															Type:        "string",
+														},
+														"signature": {
+															Description: "Signature verifies the cosign signature of the image before it is imported, the import fails if no signature verifies",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"configMap": {
+																	Description: "ConfigMap is the name of a ConfigMap with the PEM public key verifying the signatures in its cosign.pub key, or for keyless signatures with the PEM Fulcio root certificates in its fulcio.crt.pem key and the PEM Rekor public key in its rekor.pub key",
+																	Type:        "string",
+																},
+																"identity": {
+																	Description: "Identity is the email or URI of the signer of keyless signatures",
+																	Type:        "string",
+																},
+																"issuer": {
+																	Description: "Issuer is the URL of the OIDC issuer that authenticated the signer of keyless signatures",
+																	Type:        "string",
+																},
+															},
+															Required: []string{
+																"configMap",
+															},
+															Type: "object",
+														},
+													},
+													Required: []string{
+														"url",
+													},
+												},
+												"nfs": {
+													Description: "DataVolumeSourceNFS provides the parameters to create a Data Volume from an image file on an NFS export",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"server": {
+															Description: "Server is the host name or address of the NFS server",
+															Type:        "string",
+														},
+														"path": {
+															Description: "Path is the absolute path of the image file on the server, for instance /export/templates/fedora.qcow2, the importer mounts its directory read-only",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"path",
+														"server",
+													},
+												},
+												"smb": {
+													Description: "DataVolumeSourceSMB provides the parameters to create a Data Volume from an image file on an SMB/CIFS share",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"server": {
+															Description: "Server is the host name or address of the SMB server",
+															Type:        "string",
+														},
+														"share": {
+															Description: "Share is the name of the SMB share",
+															Type:        "string",
+														},
+														"path": {
+															Description: "Path is the path of the image file in the share, for instance Images/fedora.qcow2",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the share, the secret should contain accessKeyId (user name) and secretKey (password), a guest session is used if not set",
+															Type:        "string",
+														},
+														"domain": {
+															Description: "Domain is the domain or workgroup of the user",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"path",
+														"server",
+														"share",
+													},
+												},
+												"rsync": {
+													Description: "DataVolumeSourceRsync provides the parameters to create a Data Volume from an image file pulled with rsync over SSH",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"host": {
+															Description: "Host is the host name or address of the SSH server, optionally followed by :port",
+															Type:        "string",
+														},
+														"module": {
+															Description: "Module is the rsync module containing the image, read through a single-use rsync daemon started over SSH. The path is absolute on the server if not set",
+															Type:        "string",
+														},
+														"path": {
+															Description: "Path is the path of the image file, relative to the module if one is set, for instance templates/fedora.qcow2",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to log in, the secret should contain accessKeyId (user name) and secretKey (SSH private key)",
+															Type:        "string",
+														},
+														"hostKey": {
+															Description: "HostKey is the public key of the SSH server in known_hosts format, for instance \"ssh-ed25519 AAAAC3...\", the host key is not verified if not set",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"host",
+														"path",
+														"secretRef",
+													},
+												},
+												"iscsi": {
+													Description: "DataVolumeSourceISCSI provides the parameters to create a Data Volume from a LUN of an iSCSI target",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"portal": {
+															Description: "Portal is the address of the iSCSI portal, optionally followed by :port, the default port is 3260",
+															Type:        "string",
+														},
+														"iqn": {
+															Description: "IQN is the qualified name of the iSCSI target, for instance iqn.2003-01.org.linux-iscsi.san:p2v",
+															Type:        "string",
+														},
+														"lun": {
+															Description: "LUN is the number of the logical unit to import, 0 by default",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed for CHAP authentication, the secret should contain accessKeyId (user name) and secretKey (password), no authentication is used if not set",
+															Type:        "string",
+														},
+														"initiatorName": {
+															Description: "InitiatorName is the qualified name of the initiator the importer logs in with, for targets restricting access by initiator",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"iqn",
+														"portal",
+													},
+												},
+												"rbd": {
+													Description: "DataVolumeSourceRBD provides the parameters to create a Data Volume from an RBD image of an external Ceph cluster",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"monitors": {
+															Description: "Monitors are the addresses of the Ceph monitors, optionally followed by :port",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+														"pool": {
+															Description: "Pool is the name of the pool containing the image",
+															Type:        "string",
+														},
+														"image": {
+															Description: "Image is the name of the RBD image",
+															Type:        "string",
+														},
+														"snapshot": {
+															Description: "Snapshot is the name of a snapshot of the image to import instead of the image itself, recommended if the image is in use",
+															Type:        "string",
+														},
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to access the cluster, the secret should contain accessKeyId (Ceph user id, for instance admin) and secretKey (key of the user from its keyring)",
+															Type:        "string",
+														},
+													},
+													Required: []string{
+														"image",
+														"monitors",
+														"pool",
+														"secretRef",
+													},
+												},
+												"file": {
+													Description: "DataVolumeSourceFile provides the parameters to create a Data Volume from an image file on a directory of a node or on an existing PVC, which the importer pod mounts read-only",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"hostPath": {
+															Description: "HostPath is the absolute path of a directory of the node containing the image file, it requires the FileSourceHostPath feature gate",
+															Type:        "string",
+														},
+														"nodeName": {
+															Description: "NodeName is the name of the node with the host path, the importer pod runs on this node",
+															Type:        "string",
+														},
+														"claimName": {
+															Description: "ClaimName is the name of an existing PVC in the namespace of the Data Volume containing the image file",
+															Type:        "string",
+														},
+														"path": {
+															Description: "Path is the path of the image file relative to the host path or to the root of the PVC, for instance images/fedora.qcow2",
 															Type:        "string",
 														},
 													},
 													Required: []string{
-														"url",
+														"path",
 													},
 												},
-												"imageio": {
-													Description: "DataVolumeSourceImageIO provides the parameters to create a Data Volume from an imageio source",
+												"libvirt": {
+													Description: "DataVolumeSourceLibvirt provides the parameters to create a Data Volume from a disk of a domain of a remote libvirt host",
 													Type:        "object",
 													Properties: map[string]extv1.JSONSchemaProps{
-														"url": {
-															Description: "URL is the URL of the ovirt-engine",
+														"uri": {
+															Description: "URI is the libvirt connection URI of the host, for instance qemu+ssh://kvm01.example.com/system",
 															Type:        "string",
 														},
-														"secretRef": {
-															Description: "SecretRef provides the secret reference needed to access the ovirt-engine",
+														"domain": {
+															Description: "Domain is the name of the domain",
 															Type:        "string",
 														},
-														"certConfigMap": {
-															Description: "CertConfigMap provides a reference to the CA cert",
+														"disk": {
+															Description: "Disk is the target device of the disk in the domain, for instance vda",
 															Type:        "string",
 														},
-														"diskId": {
-															Description: "DiskID provides id of a disk to be imported",
+														"secretRef": {
+															Description: "SecretRef provides the secret reference needed to log in to the host, the secret should contain accessKeyId (user name) and secretKey (SSH private key)",
+															Type:        "string",
+														},
+														"hostKey": {
+															Description: "HostKey is the public key of the host in known_hosts format, for instance ssh-ed25519 AAAA..., the host key is not verified if empty",
 															Type:        "string",
 														},
 													},
 													Required: []string{
-														"diskId",
-														"url",
+														"disk",
+														"domain",
+														"secretRef",
+														"uri",
 													},
 												},
-												"s3": {
-													Description: "DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source",
+												"pvc": {
+													Description: "DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC",
 													Type:        "object",
 													Properties: map[string]extv1.JSONSchemaProps{
-														"url": {
-															Description: "URL is the url of the S3 source",
+														"namespace": {
+															Description: "The namespace of the source PVC",
 															Type:        "string",
 														},
-														"secretRef": {
-															Description: "SecretRef provides the secret reference needed to access the S3 source",
+														"name": {
+															Description: "The name of the source PVC",
 															Type:        "string",
 														},
 													},
 													Required: []string{
-														"url",
+														"name",
+														"namespace",
 													},
 												},
-												"registry": {
-													Description: "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
+												"pvcNetwork": {
+													Description: "DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC",
 													Type:        "object",
 													Properties: map[string]extv1.JSONSchemaProps{
-														"url": {
-															Description: "URL is the url of the Docker registry source",
-															Type:        "string",
-														},
-														"secretRef": {
-															Description: "SecretRef provides the secret reference needed to access the Registry source",
+														"namespace": {
+															Description: "The namespace of the source PVC",
 															Type:        "string",
 														},
-														"certConfigMap": {
-															Description: "CertConfigMap provides a reference to the Registry certs",
+														"name": {
+															Description: "The name of the source PVC",
 															Type:        "string",
 														},
 													},
 													Required: []string{
-														"url",
+														"name",
+														"namespace",
 													},
 												},
-												"pvc": {
-													Description: "DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC",
+												"snapshot": {
+													Description: "DataVolumeSourceSnapshot provides the parameters to create a Data Volume from a VolumeSnapshot",
 													Type:        "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"namespace": {
-															Description: "The namespace of the source PVC",
+															Description: "The namespace of the source VolumeSnapshot",
 															Type:        "string",
 														},
 														"name": {
-															Description: "The name of the source PVC",
+															Description: "The name of the source VolumeSnapshot",
 															Type:        "string",
 														},
 													},
@@ -655,6 +1256,11 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 															Description: "BackingFile is the path to the virtual hard disk to migrate from vCenter/ESXi",
 															Type:        "string",
 														},
+														"connections": {
+															Description: "Connections is the number of concurrent NBD connections used to copy the disk, each connection opens a VDDK session on the host",
+															Type:        "integer",
+															Format:      "int32",
+														},
 														"secretRef": {
 															Description: "SecretRef provides a reference to a secret containing the username and password needed to access the vCenter or ESXi host",
 															Type:        "string",
@@ -679,6 +1285,28 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 												},
 											},
 										},
+										"sourceRef": {
+											Description: "SourceRef is a reference to a DataSource, resolved into the source of the DataVolume when it is created",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"kind": {
+													Description: "Kind of the source, only DataSource is supported",
+													Type:        "string",
+												},
+												"namespace": {
+													Description: "Namespace of the source, the namespace of the DataVolume when unset",
+													Type:        "string",
+												},
+												"name": {
+													Description: "Name of the source",
+													Type:        "string",
+												},
+											},
+											Required: []string{
+												"kind",
+												"name",
+											},
+										},
 										"pvc": {
 											Description: "PVC is the PVC specification",
 											Type:        "object",
@@ -842,6 +1470,11 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 											Type:        "array",
 											Description: "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
 										},
+										"filesystemOverhead": {
+											Description: "FilesystemOverhead of the PVC of the DataVolume when using a Filesystem volume, a value between 0 and 1 overriding the filesystemOverhead of the CDIConfig",
+											Type:        "string",
+											Pattern:     `^(0(?:\.\d{1,3})?|1)$`,
+										},
 										"finalCheckpoint": {
 											Description: "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
 											Type:        "boolean",
@@ -850,10 +1483,687 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 											Description: "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
 											Type:        "boolean",
 										},
+										"podResourceRequirements": {
+											Description: "PodResourceRequirements of the pods populating the DataVolume, overrides the resources of the podResourceRequirements of the CDIConfig",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"limits": {
+													Description: "Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+												},
+												"requests": {
+													Description: "Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+												},
+											},
+										},
+										"importTimeout": {
+											Description: "ImportTimeout is the maximum duration of the import from the start of its first importer pod, after which the import is cancelled and the DataVolume fails",
+											Type:        "string",
+										},
+										"retryPolicy": {
+											Description: "RetryPolicy of the failed imports of the DataVolume, overrides the importRetryPolicy of the CDIConfig",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"initialBackoff": {
+													Description: "InitialBackoff is the delay before the first retry, 10s when unset",
+													Type:        "string",
+												},
+												"maxAttempts": {
+													Description: "MaxAttempts is the number of attempts of the import before the DataVolume fails for good, unlimited when unset",
+													Type:        "integer",
+													Format:      "int32",
+												},
+												"maxBackoff": {
+													Description: "MaxBackoff caps the delay between two attempts, 5m when unset",
+													Type:        "string",
+												},
+											},
+										},
+										"scratchSpace": {
+											Description: "ScratchSpace of the pods populating the DataVolume, overrides the scratchSpaceStorageClass of the CDIConfig",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"size": {
+													Description: "Size of the scratch space PVC, the size of the PVC of the DataVolume when unset",
+													AnyOf: []extv1.JSONSchemaProps{
+														{
+															Type: "integer",
+														},
+														{
+															Type: "string",
+														},
+													},
+													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+													XIntOrString: true,
+												},
+												"storageClassName": {
+													Description: "StorageClassName of the scratch space PVC, the scratchSpaceStorageClass of the CDIConfig when unset",
+													Type:        "string",
+												},
+											},
+										},
+										"priorityClassName": {
+											Description: "PriorityClassName of the pods populating the DataVolume, overrides the default pod priority class of the CDIConfig",
+											Type:        "string",
+										},
+										"nodePlacement": {
+											Description: "NodePlacement restricts on which nodes the pods populating the DataVolume are scheduled, in addition to the workload placement of the CDI resource",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"affinity": {
+													Description: "affinity enables pod affinity/anti-affinity placement expanding the types of constraints that can be expressed with nodeSelector. affinity is going to be applied to the relevant kind of pods in parallel with nodeSelector See https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"nodeAffinity": {
+															Description: "Describes node affinity scheduling rules for the pod.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"preferredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "The scheduler will prefer to schedule pods to nodes that satisfy the affinity expressions specified by this field, but it may choose a node that violates one or more of the expressions. The node that is most preferred is the one with the greatest sum of weights, i.e. for each node that meets all of the scheduling requirements (resource request, requiredDuringScheduling affinity expressions, etc.), compute a sum by iterating through the elements of this field and adding \"weight\" to the sum if the node matches the corresponding matchExpressions; the node(s) with the highest sum are the most preferred.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "An empty preferred scheduling term matches all objects with implicit weight 0 (i.e. it's a no-op). A null preferred scheduling term matches no objects (i.e. is also a no-op).",
+																			Type:        "object",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"preference": {
+																					Description: "A node selector term, associated with the corresponding weight.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"matchExpressions": {
+																							Description: "A list of node selector requirements by node's labels.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A node selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "The label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "Represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "An array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. If the operator is Gt or Lt, the values array must have a single element, which will be interpreted as an integer. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																						"matchFields": {
+																							Description: "A list of node selector requirements by node's fields.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A node selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "The label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "Represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "An array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. If the operator is Gt or Lt, the values array must have a single element, which will be interpreted as an integer. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																					},
+																				},
+																				"weight": {
+																					Description: "Weight associated with matching the corresponding nodeSelectorTerm, in the range 1-100.",
+																					Format:      "int32",
+																					Type:        "integer",
+																				},
+																			},
+																			Required: []string{
+																				"preference",
+																				"weight",
+																			},
+																		},
+																	},
+																},
+																"requiredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "If the affinity requirements specified by this field are not met at scheduling time, the pod will not be scheduled onto the node. If the affinity requirements specified by this field cease to be met at some point during pod execution (e.g. due to an update), the system may or may not try to eventually evict the pod from its node.",
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"nodeSelectorTerms": {
+																			Description: "Required. A list of node selector terms. The terms are ORed.",
+																			Type:        "array",
+																			Items: &extv1.JSONSchemaPropsOrArray{
+																				Schema: &extv1.JSONSchemaProps{
+																					Description: "A null or empty node selector term matches no objects. The requirements of them are ANDed. The TopologySelectorTerm type implements a subset of the NodeSelectorTerm.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"matchExpressions": {
+																							Description: "A list of node selector requirements by node's labels.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A node selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "The label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "Represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "An array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. If the operator is Gt or Lt, the values array must have a single element, which will be interpreted as an integer. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																						"matchFields": {
+																							Description: "A list of node selector requirements by node's fields.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A node selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "The label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "Represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "An array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. If the operator is Gt or Lt, the values array must have a single element, which will be interpreted as an integer. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																					},
+																				},
+																			},
+																		},
+																	},
+																	Required: []string{
+																		"nodeSelectorTerms",
+																	},
+																},
+															},
+														},
+														"podAffinity": {
+															Description: "Describes pod affinity scheduling rules (e.g. co-locate this pod in the same node, zone, etc. as some other pod(s)).",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"preferredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "The scheduler will prefer to schedule pods to nodes that satisfy the affinity expressions specified by this field, but it may choose a node that violates one or more of the expressions. The node that is most preferred is the one with the greatest sum of weights, i.e. for each node that meets all of the scheduling requirements (resource request, requiredDuringScheduling affinity expressions, etc.), compute a sum by iterating through the elements of this field and adding \"weight\" to the sum if the node has pods which matches the corresponding podAffinityTerm; the node(s) with the highest sum are the most preferred.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "The weights of all of the matched WeightedPodAffinityTerm fields are added per-node to find the most preferred node(s)",
+																			Type:        "object",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"podAffinityTerm": {
+																					Description: "Required. A pod affinity term, associated with the corresponding weight.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"labelSelector": {
+																							Description: "A label query over a set of resources, in this case pods.",
+																							Type:        "object",
+																							Properties: map[string]extv1.JSONSchemaProps{
+																								"matchExpressions": {
+																									Description: "matchExpressions is a list of label selector requirements. The requirements are ANDed.",
+																									Type:        "array",
+																									Items: &extv1.JSONSchemaPropsOrArray{
+																										Schema: &extv1.JSONSchemaProps{
+																											Description: "A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																											Type:        "object",
+																											Properties: map[string]extv1.JSONSchemaProps{
+																												"key": {
+																													Description: "key is the label key that the selector applies to.",
+																													Type:        "string",
+																												},
+																												"operator": {
+																													Description: "operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.",
+																													Type:        "string",
+																												},
+																												"values": {
+																													Description: "values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																													Type:        "array",
+																													Items: &extv1.JSONSchemaPropsOrArray{
+																														Schema: &extv1.JSONSchemaProps{
+																															Type: "string",
+																														},
+																													},
+																												},
+																											},
+																											Required: []string{
+																												"key",
+																												"operator",
+																											},
+																										},
+																									},
+																								},
+																								"matchLabels": {
+																									Description: "matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is \"key\", the operator is \"In\", and the values array contains only \"value\". The requirements are ANDed.",
+																									Type:        "object",
+																									AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																										Schema: &extv1.JSONSchemaProps{
+																											Type: "string",
+																										},
+																									},
+																								},
+																							},
+																						},
+																						"namespaces": {
+																							Description: "namespaces specifies which namespaces the labelSelector applies to (matches against); null or empty list means \"this pod's namespace\"",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Type: "string",
+																								},
+																							},
+																						},
+																						"topologyKey": {
+																							Description: "This pod should be co-located (affinity) or not co-located (anti-affinity) with the pods matching the labelSelector in the specified namespaces, where co-located is defined as running on a node whose value of the label with key topologyKey matches that of any node on which any of the selected pods is running. Empty topologyKey is not allowed.",
+																							Type:        "string",
+																						},
+																					},
+																					Required: []string{
+																						"topologyKey",
+																					},
+																				},
+																				"weight": {
+																					Description: "weight associated with matching the corresponding podAffinityTerm, in the range 1-100.",
+																					Type:        "integer",
+																					Format:      "int32",
+																				},
+																			},
+																			Required: []string{
+																				"podAffinityTerm",
+																				"weight",
+																			},
+																		},
+																	},
+																},
+																"requiredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "If the affinity requirements specified by this field are not met at scheduling time, the pod will not be scheduled onto the node. If the affinity requirements specified by this field cease to be met at some point during pod execution (e.g. due to a pod label update), the system may or may not try to eventually evict the pod from its node. When there are multiple elements, the lists of nodes corresponding to each podAffinityTerm are intersected, i.e. all terms must be satisfied.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "Defines a set of pods (namely those matching the labelSelector relative to the given namespace(s)) that this pod should be co-located (affinity) or not co-located (anti-affinity) with, where co-located is defined as running on a node whose value of the label with key <topologyKey> matches that of any node on which a pod of the set of pods is running",
+																			Type:        "object",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"labelSelector": {
+																					Description: "A label query over a set of resources, in this case pods.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"matchExpressions": {
+																							Description: "matchExpressions is a list of label selector requirements. The requirements are ANDed.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "key is the label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																						"matchLabels": {
+																							Description: "matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is \"key\", the operator is \"In\", and the values array contains only \"value\". The requirements are ANDed.",
+																							Type:        "object",
+																							AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																								Schema: &extv1.JSONSchemaProps{
+																									Type: "string",
+																								},
+																							},
+																						},
+																					},
+																				},
+																				"namespaces": {
+																					Description: "namespaces specifies which namespaces the labelSelector applies to (matches against); null or empty list means \"this pod's namespace\"",
+																					Type:        "array",
+																					Items: &extv1.JSONSchemaPropsOrArray{
+																						Schema: &extv1.JSONSchemaProps{
+																							Type: "string",
+																						},
+																					},
+																				},
+																				"topologyKey": {
+																					Description: "This pod should be co-located (affinity) or not co-located (anti-affinity) with the pods matching the labelSelector in the specified namespaces, where co-located is defined as running on a node whose value of the label with key topologyKey matches that of any node on which any of the selected pods is running. Empty topologyKey is not allowed.",
+																					Type:        "string",
+																				},
+																			},
+																			Required: []string{
+																				"topologyKey",
+																			},
+																		},
+																	},
+																},
+															},
+														},
+														"podAntiAffinity": {
+															Description: "Describes pod anti-affinity scheduling rules (e.g. avoid putting this pod in the same node, zone, etc. as some other pod(s)).",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"preferredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "The scheduler will prefer to schedule pods to nodes that satisfy the anti-affinity expressions specified by this field, but it may choose a node that violates one or more of the expressions. The node that is most preferred is the one with the greatest sum of weights, i.e. for each node that meets all of the scheduling requirements (resource request, requiredDuringScheduling anti-affinity expressions, etc.), compute a sum by iterating through the elements of this field and adding \"weight\" to the sum if the node has pods which matches the corresponding podAffinityTerm; the node(s) with the highest sum are the most preferred.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "The weights of all of the matched WeightedPodAffinityTerm fields are added per-node to find the most preferred node(s)",
+																			Type:        "object",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"podAffinityTerm": {
+																					Description: "Required. A pod affinity term, associated with the corresponding weight.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"labelSelector": {
+																							Description: "A label query over a set of resources, in this case pods.",
+																							Type:        "object",
+																							Properties: map[string]extv1.JSONSchemaProps{
+																								"matchExpressions": {
+																									Description: "matchExpressions is a list of label selector requirements. The requirements are ANDed.",
+																									Type:        "array",
+																									Items: &extv1.JSONSchemaPropsOrArray{
+																										Schema: &extv1.JSONSchemaProps{
+																											Description: "A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																											Type:        "object",
+																											Properties: map[string]extv1.JSONSchemaProps{
+																												"key": {
+																													Description: "key is the label key that the selector applies to.",
+																													Type:        "string",
+																												},
+																												"operator": {
+																													Description: "operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.",
+																													Type:        "string",
+																												},
+																												"values": {
+																													Description: "values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																													Type:        "array",
+																													Items: &extv1.JSONSchemaPropsOrArray{
+																														Schema: &extv1.JSONSchemaProps{
+																															Type: "string",
+																														},
+																													},
+																												},
+																											},
+																											Required: []string{
+																												"key",
+																												"operator",
+																											},
+																										},
+																									},
+																								},
+																								"matchLabels": {
+																									Description: "matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is \"key\", the operator is \"In\", and the values array contains only \"value\". The requirements are ANDed.",
+																									Type:        "object",
+																									AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																										Schema: &extv1.JSONSchemaProps{
+																											Type: "string",
+																										},
+																									},
+																								},
+																							},
+																						},
+																						"namespaces": {
+																							Description: "namespaces specifies which namespaces the labelSelector applies to (matches against); null or empty list means \"this pod's namespace\"",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Type: "string",
+																								},
+																							},
+																						},
+																						"topologyKey": {
+																							Description: "This pod should be co-located (affinity) or not co-located (anti-affinity) with the pods matching the labelSelector in the specified namespaces, where co-located is defined as running on a node whose value of the label with key topologyKey matches that of any node on which any of the selected pods is running. Empty topologyKey is not allowed.",
+																							Type:        "string",
+																						},
+																					},
+																					Required: []string{
+																						"topologyKey",
+																					},
+																				},
+																				"weight": {
+																					Description: "weight associated with matching the corresponding podAffinityTerm, in the range 1-100.",
+																					Type:        "integer",
+																					Format:      "int32",
+																				},
+																			},
+																			Required: []string{
+																				"podAffinityTerm",
+																				"weight",
+																			},
+																		},
+																	},
+																},
+																"requiredDuringSchedulingIgnoredDuringExecution": {
+																	Description: "If the anti-affinity requirements specified by this field are not met at scheduling time, the pod will not be scheduled onto the node. If the anti-affinity requirements specified by this field cease to be met at some point during pod execution (e.g. due to a pod label update), the system may or may not try to eventually evict the pod from its node. When there are multiple elements, the lists of nodes corresponding to each podAffinityTerm are intersected, i.e. all terms must be satisfied.",
+																	Type:        "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "Defines a set of pods (namely those matching the labelSelector relative to the given namespace(s)) that this pod should be co-located (affinity) or not co-located (anti-affinity) with, where co-located is defined as running on a node whose value of the label with key <topologyKey> matches that of any node on which a pod of the set of pods is running",
+																			Type:        "object",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"labelSelector": {
+																					Description: "A label query over a set of resources, in this case pods.",
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"matchExpressions": {
+																							Description: "matchExpressions is a list of label selector requirements. The requirements are ANDed.",
+																							Type:        "array",
+																							Items: &extv1.JSONSchemaPropsOrArray{
+																								Schema: &extv1.JSONSchemaProps{
+																									Description: "A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.",
+																									Type:        "object",
+																									Properties: map[string]extv1.JSONSchemaProps{
+																										"key": {
+																											Description: "key is the label key that the selector applies to.",
+																											Type:        "string",
+																										},
+																										"operator": {
+																											Description: "operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.",
+																											Type:        "string",
+																										},
+																										"values": {
+																											Description: "values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																											Type:        "array",
+																											Items: &extv1.JSONSchemaPropsOrArray{
+																												Schema: &extv1.JSONSchemaProps{
+																													Type: "string",
+																												},
+																											},
+																										},
+																									},
+																									Required: []string{
+																										"key",
+																										"operator",
+																									},
+																								},
+																							},
+																						},
+																						"matchLabels": {
+																							Description: "matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is \"key\", the operator is \"In\", and the values array contains only \"value\". The requirements are ANDed.",
+																							Type:        "object",
+																							AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																								Schema: &extv1.JSONSchemaProps{
+																									Type: "string",
+																								},
+																							},
+																						},
+																					},
+																				},
+																				"namespaces": {
+																					Description: "namespaces specifies which namespaces the labelSelector applies to (matches against); null or empty list means \"this pod's namespace\"",
+																					Type:        "array",
+																					Items: &extv1.JSONSchemaPropsOrArray{
+																						Schema: &extv1.JSONSchemaProps{
+																							Type: "string",
+																						},
+																					},
+																				},
+																				"topologyKey": {
+																					Description: "This pod should be co-located (affinity) or not co-located (anti-affinity) with the pods matching the labelSelector in the specified namespaces, where co-located is defined as running on a node whose value of the label with key topologyKey matches that of any node on which any of the selected pods is running. Empty topologyKey is not allowed.",
+																					Type:        "string",
+																				},
+																			},
+																			Required: []string{
+																				"topologyKey",
+																			},
+																		},
+																	},
+																},
+															},
+														},
+													},
+												},
+												"nodeSelector": {
+													Description: "nodeSelector is the node selector applied to the relevant kind of pods It specifies a map of key-value pairs: for the pod to be eligible to run on a node, the node must have each of the indicated key-value pairs as labels (it can have additional labels as well). See https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#nodeselector",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"tolerations": {
+													Description: "tolerations is a list of tolerations applied to the relevant kind of pods See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/ for more info. These are additional tolerations other than default ones.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Description: "The pod this Toleration is attached to tolerates any taint that matches the triple <key,value,effect> using the matching operator <operator>.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"effect": {
+																	Description: "Effect indicates the taint effect to match. Empty means match all taint effects. When specified, allowed values are NoSchedule, PreferNoSchedule and NoExecute.",
+																	Type:        "string",
+																},
+																"key": {
+																	Description: "Key is the taint key that the toleration applies to. Empty means match all taint keys. If the key is empty, operator must be Exists; this combination means to match all values and all keys.",
+																	Type:        "string",
+																},
+																"operator": {
+																	Description: "Operator represents a key's relationship to the value. Valid operators are Exists and Equal. Defaults to Equal. Exists is equivalent to wildcard for value, so that a pod can tolerate all taints of a particular category.",
+																	Type:        "string",
+																},
+																"tolerationSeconds": {
+																	Description: "TolerationSeconds represents the period of time the toleration (which must be of effect NoExecute, otherwise this field is ignored) tolerates the taint. By default, it is not set, which means tolerate the taint forever (do not evict). Zero and negative values will be treated as 0 (evict immediately) by the system.",
+																	Type:        "integer",
+																	Format:      "int64",
+																},
+																"value": {
+																	Description: "Value is the taint value the toleration matches to. If the operator is Exists, the value should be empty, otherwise just a regular string.",
+																	Type:        "string",
+																},
+															},
+														},
+													},
+												},
+											},
+										},
 									},
 									Required: []string{
 										"pvc",
-										"source",
 									},
 								},
 								"status": {
@@ -868,6 +2178,71 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 											Description: "DataVolumeProgress is the current progress of the DataVolume transfer operation. Value between 0 and 100 inclusive, N/A if not available",
 											Type:        "string",
 										},
+										"transfer": {
+											Description: "Transfer is the amount transferred, the throughput and the estimated completion of the import, when the importer reports them",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"bytesPerSecond": {
+													Description: "BytesPerSecond is the current transfer rate, 0 when the transfer is stalled.",
+													Type:        "integer",
+													Format:      "int64",
+												},
+												"bytesTransferred": {
+													Description: "BytesTransferred is the number of bytes read from the source so far.",
+													Type:        "integer",
+													Format:      "int64",
+												},
+												"estimatedCompletionTime": {
+													Description: "EstimatedCompletionTime is the time the transfer is expected to complete at the current rate, unset when unknown.",
+													Type:        "string",
+													Format:      "date-time",
+												},
+											},
+											Required: []string{
+												"bytesPerSecond",
+												"bytesTransferred",
+											},
+										},
+										"sourceInfo": {
+											Description: "SourceInfo is the format, compression and sizes of the imported source, as detected by the importer",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"compression": {
+													Description: "Compression is the compression of the source, gz or xz, empty if not compressed.",
+													Type:        "string",
+												},
+												"downloadedSize": {
+													Description: "DownloadedSize is the size of the source as downloaded.",
+													AnyOf: []extv1.JSONSchemaProps{
+														{
+															Type: "integer",
+														},
+														{
+															Type: "string",
+														},
+													},
+													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+													XIntOrString: true,
+												},
+												"format": {
+													Description: "Format is the disk image format of the source, like raw, qcow2, vmdk or vhdx.",
+													Type:        "string",
+												},
+												"virtualSize": {
+													Description: "VirtualSize is the size of the disk in the source image.",
+													AnyOf: []extv1.JSONSchemaProps{
+														{
+															Type: "integer",
+														},
+														{
+															Type: "string",
+														},
+													},
+													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+													XIntOrString: true,
+												},
+											},
+										},
 										"restartCount": {
 											Description: "RestartCount is the number of times the pod populating the DataVolume has restarted",
 											Type:        "integer",
@@ -909,6 +2284,47 @@ func createDataVolumeCRD() *extv1.CustomResourceDefinition {
 											},
 											Type: "array",
 										},
+										"checkpoints": {
+											Description: "Checkpoints is the status of the copy of each checkpoint of a multi-stage import",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "DataVolumeCheckpointStatus is the status of the copy of one checkpoint of a multi-stage import.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"completionTime": {
+															Description: "CompletionTime is the time the copy of the checkpoint completed.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"current": {
+															Description: "Current is the identifier of the copied checkpoint.",
+															Type:        "string",
+														},
+														"phase": {
+															Description: "Phase is the phase of the copy of the checkpoint, Pending, ImportInProgress or Succeeded.",
+															Type:        "string",
+														},
+														"previous": {
+															Description: "Previous is the identifier of the checkpoint the changes are copied from, empty for the base copy.",
+															Type:        "string",
+														},
+														"progress": {
+															Description: "Progress is the progress of the copy of the checkpoint.",
+															Type:        "string",
+														},
+														"startTime": {
+															Description: "StartTime is the time the copy of the checkpoint started.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+													},
+													Required: []string{
+														"current",
+													},
+												},
+											},
+											Type: "array",
+										},
 									},
 								},
 							},
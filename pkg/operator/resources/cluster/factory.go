@@ -44,6 +44,7 @@ var staticFactoryFunctions = factoryFuncMap{
 	"crd-resources":    createCRDResources,
 	"uploadproxy-rbac": createUploadProxyResources,
 	"aggregate-roles":  createAggregateClusterRoles,
+	"priorityclass":    createPriorityClassResources,
 }
 
 var dynamicFactoryFunctions = factoryFuncMap{
@@ -54,6 +55,14 @@ func createCRDResources(args *FactoryArgs) []runtime.Object {
 	return []runtime.Object{
 		createDataVolumeCRD(),
 		createCDIConfigCRD(),
+		createDataExportCRD(),
+		createCloneGrantCRD(),
+		createVolumeImportSourceCRD(),
+		createVolumeUploadSourceCRD(),
+		createVolumeCloneSourceCRD(),
+		createDataImportCronCRD(),
+		createDataSourceCRD(),
+		createStorageProfileCRD(),
 	}
 }
 
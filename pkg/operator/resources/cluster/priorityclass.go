@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+const (
+	// PriorityClassName is the name of the default priority class of the CDI control plane
+	PriorityClassName = "cdi-cluster-critical"
+
+	// The highest value allowed for classes that are not built into kubernetes
+	priorityClassValue = 1000000000
+)
+
+func createPriorityClassResources(args *FactoryArgs) []runtime.Object {
+	return []runtime.Object{
+		createPriorityClass(),
+	}
+}
+
+func createPriorityClass() *schedulingv1.PriorityClass {
+	return &schedulingv1.PriorityClass{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "scheduling.k8s.io/v1",
+			Kind:       "PriorityClass",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   PriorityClassName,
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Value:       priorityClassValue,
+		Description: "Priority of the CDI control plane, which finishes the data transfers in progress",
+	}
+}
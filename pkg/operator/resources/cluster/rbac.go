@@ -35,6 +35,23 @@ func createAggregateClusterRoles(args *FactoryArgs) []runtime.Object {
 }
 
 func getAdminPolicyRules() []rbacv1.PolicyRule {
+	// CloneGrants allow other namespaces to clone the PVCs of the namespace, granting them is left to the admin
+	return append(getEditPolicyRules(), rbacv1.PolicyRule{
+		APIGroups: []string{
+			"cdi.kubevirt.io",
+		},
+		Resources: []string{
+			"clonegrants",
+		},
+		Verbs: []string{
+			"*",
+		},
+	})
+}
+
+func getEditPolicyRules() []rbacv1.PolicyRule {
+	// diff between admin and edit ClusterRoles is minimal and limited to RBAC
+	// both can CRUD pods/PVCs/etc
 	return []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -42,6 +59,12 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"datavolumes",
+				"dataexports",
+				"volumeimportsources",
+				"volumeuploadsources",
+				"volumeclonesources",
+				"dataimportcrons",
+				"datasources",
 			},
 			Verbs: []string{
 				"*",
@@ -79,6 +102,7 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"uploadtokenrequests",
+				"uploadtokenrenewals",
 			},
 			Verbs: []string{
 				"*",
@@ -87,12 +111,6 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 	}
 }
 
-func getEditPolicyRules() []rbacv1.PolicyRule {
-	// diff between admin and edit ClusterRoles is minimal and limited to RBAC
-	// both can CRUD pods/PVCs/etc
-	return getAdminPolicyRules()
-}
-
 func getViewPolicyRules() []rbacv1.PolicyRule {
 	return []rbacv1.PolicyRule{
 		{
@@ -101,6 +119,13 @@ func getViewPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"datavolumes",
+				"dataexports",
+				"volumeimportsources",
+				"volumeuploadsources",
+				"volumeclonesources",
+				"dataimportcrons",
+				"datasources",
+				"clonegrants",
 			},
 			Verbs: []string{
 				"get",
@@ -125,6 +150,7 @@ func getViewPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"storageprofiles",
 			},
 			Verbs: []string{
 				"get",
@@ -143,6 +169,7 @@ func createConfigReaderClusterRole(name string) *rbacv1.ClusterRole {
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"storageprofiles",
 			},
 			Verbs: []string{
 				"get",
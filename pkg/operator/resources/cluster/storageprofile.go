@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewStorageProfileCrd - provides StorageProfile CRD
+func NewStorageProfileCrd() *extv1.CustomResourceDefinition {
+	return createStorageProfileCRD()
+}
+
+// createStorageProfileCRD creates the StorageProfile schema
+func createStorageProfileCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "storageprofiles.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:     "StorageProfile",
+				Plural:   "storageprofiles",
+				ListKind: "StorageProfileList",
+				Singular: "storageprofile",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:         "v1beta1",
+					Served:       true,
+					Storage:      true,
+					Subresources: &extv1.CustomResourceSubresources{},
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "StorageProfile provides a CDI specific recommendation for storage parameters, one per StorageClass",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "StorageProfileSpec defines specification for StorageProfile, the settings overriding the recommendations for the provisioner",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"claimPropertySets": claimPropertySetsSchema("ClaimPropertySets is a provided set of properties applicable to PVC"),
+										"cloneStrategy": {
+											Description: "CloneStrategy defines the preferred method for performing a CDI clone",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"copy"`),
+												},
+												{
+													Raw: []byte(`"snapshot"`),
+												},
+												{
+													Raw: []byte(`"csi-clone"`),
+												},
+											},
+										},
+										"snapshotClass": {
+											Description: "SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass",
+											Type:        "string",
+										},
+									},
+								},
+								"status": {
+									Description: "StorageProfileStatus provides the most recently observed status of the StorageProfile",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"claimPropertySets": claimPropertySetsSchema("ClaimPropertySets computed from the spec and detected in the system"),
+										"cloneStrategy": {
+											Description: "CloneStrategy defines the preferred method for performing a CDI clone",
+											Type:        "string",
+										},
+										"provisioner": {
+											Description: "The Storage class provisioner plugin name",
+											Type:        "string",
+										},
+										"snapshotClass": {
+											Description: "SnapshotClass is the VolumeSnapshotClass of the snapshots taken to clone the PVCs of the StorageClass",
+											Type:        "string",
+										},
+										"storageClass": {
+											Description: "The StorageClass name for which capabilities are defined",
+											Type:        "string",
+										},
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Provisioner",
+							Type:        "string",
+							Description: "The provisioner of the StorageClass",
+							JSONPath:    ".status.provisioner",
+						},
+						{
+							Name:        "Clone Strategy",
+							Type:        "string",
+							Description: "The clone strategy of the StorageClass",
+							JSONPath:    ".status.cloneStrategy",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Cluster",
+		},
+	}
+}
+
+// claimPropertySetsSchema returns the schema of the claim property sets of the spec and the status
+func claimPropertySetsSchema(description string) extv1.JSONSchemaProps {
+	return extv1.JSONSchemaProps{
+		Description: description,
+		Type:        "array",
+		Items: &extv1.JSONSchemaPropsOrArray{
+			Schema: &extv1.JSONSchemaProps{
+				Description: "ClaimPropertySet is a set of properties applicable to PVC",
+				Type:        "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"accessModes": {
+						Description: "AccessModes contains the desired access modes the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1",
+						Type:        "array",
+						Items: &extv1.JSONSchemaPropsOrArray{
+							Schema: &extv1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					"volumeMode": {
+						Description: "VolumeMode defines what type of volume is required by the claim. Value of Filesystem is implied when not included in claim spec.",
+						Type:        "string",
+					},
+				},
+			},
+		},
+	}
+}
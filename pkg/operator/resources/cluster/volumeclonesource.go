@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewVolumeCloneSourceCrd - provides VolumeCloneSource CRD
+func NewVolumeCloneSourceCrd() *extv1.CustomResourceDefinition {
+	return createVolumeCloneSourceCRD()
+}
+
+// createVolumeCloneSourceCRD creates the VolumeCloneSource schema
+func createVolumeCloneSourceCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "volumeclonesources.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "VolumeCloneSource",
+				Plural: "volumeclonesources",
+				ShortNames: []string{
+					"vcs",
+				},
+				ListKind: "VolumeCloneSourceList",
+				Singular: "volumeclonesource",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1beta1",
+					Served:  true,
+					Storage: true,
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "VolumeCloneSource populates the PVCs referencing it in their dataSource with a clone, without a DataVolume",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "VolumeCloneSourceSpec defines the VolumeCloneSource type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"preallocation": {
+											Description: "Preallocation preallocates the space of the PVCs of host-assisted clones when true",
+											Type:        "boolean",
+										},
+										"source": {
+											Description: "Source is the PVC cloned into the PVCs, in their namespace",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"apiGroup": {
+													Description: "APIGroup is the group for the resource being referenced. If APIGroup is not specified, the specified Kind must be in the core API group. For any other third-party types, APIGroup is required.",
+													Type:        "string",
+												},
+												"kind": {
+													Description: "Kind is the type of resource being referenced",
+													Type:        "string",
+												},
+												"name": {
+													Description: "Name is the name of resource being referenced",
+													Type:        "string",
+												},
+											},
+											Required: []string{
+												"kind",
+												"name",
+											},
+										},
+									},
+									Required: []string{
+										"source",
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:     "Source",
+							Type:     "string",
+							JSONPath: ".spec.source.name",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
+)
+
+// NewVolumeImportSourceCrd - provides VolumeImportSource CRD
+func NewVolumeImportSourceCrd() *extv1.CustomResourceDefinition {
+	return createVolumeImportSourceCRD()
+}
+
+// dataVolumeSourceSchema returns the schema of the sources of the v1beta1 DataVolumes, with the given description
+func dataVolumeSourceSchema(description string) extv1.JSONSchemaProps {
+	for _, version := range createDataVolumeCRD().Spec.Versions {
+		if version.Name == "v1beta1" {
+			source := version.Schema.OpenAPIV3Schema.Properties["spec"].Properties["source"]
+			source.Description = description
+			return source
+		}
+	}
+	panic("no v1beta1 DataVolume schema")
+}
+
+// createVolumeImportSourceCRD creates the VolumeImportSource schema
+func createVolumeImportSourceCRD() *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "volumeimportsources.cdi.kubevirt.io",
+			Labels: utils.ResourcesBuiler.WithCommonLabels(nil),
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "cdi.kubevirt.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   "VolumeImportSource",
+				Plural: "volumeimportsources",
+				ShortNames: []string{
+					"vis",
+				},
+				ListKind: "VolumeImportSourceList",
+				Singular: "volumeimportsource",
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1beta1",
+					Served:  true,
+					Storage: true,
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "VolumeImportSource populates the PVCs referencing it in their dataSource with an import, without a DataVolume",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								// We are aware apiVersion, kind, and metadata are technically not needed, but to make comparision with
+								// kubebuilder easier, we add it here.
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "VolumeImportSourceSpec defines the VolumeImportSource type specification",
+									Type:        "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"contentType": {
+											Description: "ContentType is the content type of the imported data, kubevirt or archive, defaults to kubevirt",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"kubevirt"`),
+												},
+												{
+													Raw: []byte(`"archive"`),
+												},
+											},
+										},
+										"preallocation": {
+											Description: "Preallocation preallocates the space of the PVCs when true",
+											Type:        "boolean",
+										},
+										"source": dataVolumeSourceSchema("Source is the source of the data imported into the PVCs, any DataVolume source but pvc, pvcNetwork, snapshot and upload"),
+									},
+									Required: []string{
+										"source",
+									},
+								},
+							},
+							Required: []string{
+								"spec",
+							},
+						},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+			Conversion: &extv1.CustomResourceConversion{
+				Strategy: extv1.NoneConverter,
+			},
+			Scope: "Namespaced",
+		},
+	}
+}
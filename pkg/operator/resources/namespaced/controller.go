@@ -47,7 +47,8 @@ func createControllerResources(args *FactoryArgs) []runtime.Object {
 			args.UploadServerImage,
 			args.Verbosity,
 			args.PullPolicy,
-			args.InfraNodePlacement),
+			args.InfraNodePlacement,
+			args.ControllerResources),
 		createInsecureRegConfigMap(),
 		createPrometheusService(),
 	}
@@ -91,10 +92,13 @@ func createControllerServiceAccount() *corev1.ServiceAccount {
 	return utils.ResourcesBuiler.CreateServiceAccount(common.ControllerServiceAccountName)
 }
 
-func createControllerDeployment(controllerImage, importerImage, clonerImage, uploadServerImage, verbosity, pullPolicy string, infraNodePlacement *sdkapi.NodePlacement) *appsv1.Deployment {
+func createControllerDeployment(controllerImage, importerImage, clonerImage, uploadServerImage, verbosity, pullPolicy string, infraNodePlacement *sdkapi.NodePlacement, resources *corev1.ResourceRequirements) *appsv1.Deployment {
 	defaultMode := corev1.ConfigMapVolumeSourceDefaultMode
 	deployment := utils.CreateDeployment(controllerResourceName, "app", "containerized-data-importer", common.ControllerServiceAccountName, int32(1), infraNodePlacement)
 	container := utils.CreateContainer("cdi-controller", controllerImage, verbosity, pullPolicy)
+	if resources != nil {
+		container.Resources = *resources
+	}
 	container.Env = []corev1.EnvVar{
 		{
 			Name:  "IMPORTER_IMAGE",
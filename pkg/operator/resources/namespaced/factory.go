@@ -21,6 +21,8 @@ import (
 
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	utils "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/resources"
@@ -40,6 +42,11 @@ type FactoryArgs struct {
 	PullPolicy             string `required:"true" split_words:"true"`
 	Namespace              string
 	InfraNodePlacement     *sdkapi.NodePlacement
+	ControllerResources    *corev1.ResourceRequirements
+	APIServerResources     *corev1.ResourceRequirements
+	UploadProxyResources   *corev1.ResourceRequirements
+	ImagePullSecrets       []corev1.LocalObjectReference
+	PriorityClassName      string
 }
 
 type factoryFunc func(*FactoryArgs) []runtime.Object
@@ -78,6 +85,12 @@ func CreateResourceGroup(group string, args *FactoryArgs) ([]runtime.Object, err
 	utils.ValidateGVKs(resources)
 	for _, resource := range resources {
 		assignNamspaceIfMissing(resource, args.Namespace)
+		if deployment, ok := resource.(*appsv1.Deployment); ok {
+			if len(args.ImagePullSecrets) > 0 {
+				deployment.Spec.Template.Spec.ImagePullSecrets = args.ImagePullSecrets
+			}
+			deployment.Spec.Template.Spec.PriorityClassName = args.PriorityClassName
+		}
 	}
 	return resources, nil
 }
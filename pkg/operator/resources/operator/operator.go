@@ -131,6 +131,17 @@ func getClusterPolicyRules() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"scheduling.k8s.io",
+			},
+			Resources: []string{
+				"priorityclasses",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
 	}
 	rules = append(rules, cluster.GetClusterRolePolicyRules()...)
 	return rules
@@ -204,6 +215,29 @@ func getNamespacedPolicyRules() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"extensions",
+				"networking.k8s.io",
+			},
+			Resources: []string{
+				"ingresses",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"cert-manager.io",
+			},
+			Resources: []string{
+				"certificates",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
 	}
 	return rules
 }
@@ -2884,12 +2918,29 @@ func createCDIListCRD() *extv1.CustomResourceDefinition {
 												{
 													Raw: []byte(`"snapshot"`),
 												},
+												{
+													Raw: []byte(`"csi-clone"`),
+												},
 											},
 										},
 										"config": {
 											Description: "CDIConfig at CDI level",
 											Type:        "object",
 											Properties: map[string]extv1.JSONSchemaProps{
+												"dataVolumeTTLSeconds": {
+													Description: "DataVolumeTTLSeconds is the time in seconds after the completion of a DataVolume before it is deleted, its PVC is kept. DataVolumes whose PVC is used by a pod or which are referenced by a VirtualMachine are kept until they are not anymore. The DataVolumes are kept if not set",
+													Type:        "integer",
+													Format:      "int32",
+												},
+												"fileSourceHostPathPrefixes": {
+													Description: "FileSourceHostPathPrefixes are the directories of the nodes the host paths of File sources must be in, host paths are rejected if it is empty",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+													Type: "array",
+												},
 												"featureGates": {
 													Description: "FeatureGates are a list of specific enabled feature gates",
 													Items: &extv1.JSONSchemaPropsOrArray{
@@ -2903,10 +2954,88 @@ func createCDIListCRD() *extv1.CustomResourceDefinition {
 													Description: "Override the URL used when uploading to a DataVolume",
 													Type:        "string",
 												},
+												"uploadProxyClientCAConfigMap": {
+													Description: "UploadProxyClientCAConfigMap is the name of a ConfigMap in the CDI namespace holding, in its ca-bundle.crt key, the CA bundle of the client certificates the upload proxy requires in addition to the upload token. If not set client certificates are not required",
+													Type:        "string",
+												},
+												"uploadProxyRateLimits": {
+													Description: "UploadProxyRateLimits limits the concurrent uploads and the bandwidth of the uploads through the upload proxy, per namespace and per PVC",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"maxBandwidthPerNamespace": {
+															Description: "MaxBandwidthPerNamespace is the maximum number of bytes per second uploaded to the PVCs of a namespace",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+														"maxBandwidthPerPVC": {
+															Description: "MaxBandwidthPerPVC is the maximum number of bytes per second uploaded to a PVC",
+															AnyOf: []extv1.JSONSchemaProps{
+																{
+																	Type: "integer",
+																},
+																{
+																	Type: "string",
+																},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+														"maxConcurrentUploadsPerNamespace": {
+															Description: "MaxConcurrentUploadsPerNamespace is the maximum number of uploads proxied at the same time to the PVCs of a namespace",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"maxConcurrentUploadsPerPVC": {
+															Description: "MaxConcurrentUploadsPerPVC is the maximum number of uploads proxied at the same time to a PVC, with any of its upload tokens",
+															Type:        "integer",
+															Format:      "int32",
+														},
+													},
+													Type: "object",
+												},
+												"uploadTokenTTL": {
+													Description: "UploadTokenTTL is the lifetime of the upload and download tokens issued by the CDI API server, 5 minutes if not set. Tokens can be renewed before they expire",
+													Type:        "string",
+												},
 												"scratchSpaceStorageClass": {
 													Description: "Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn't exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space",
 													Type:        "string",
 												},
+												"importRetryPolicy": {
+													Description: "ImportRetryPolicy is the default retry policy of the failed imports, the importer pods restart with the backoff of the kubelet without it",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"initialBackoff": {
+															Description: "InitialBackoff is the delay before the first retry, 10s when unset",
+															Type:        "string",
+														},
+														"maxAttempts": {
+															Description: "MaxAttempts is the number of attempts of the import before the DataVolume fails for good, unlimited when unset",
+															Type:        "integer",
+															Format:      "int32",
+														},
+														"maxBackoff": {
+															Description: "MaxBackoff caps the delay between two attempts, 5m when unset",
+															Type:        "string",
+														},
+													},
+												},
+												"maxParallelImports": {
+													Description: "MaxParallelImports is the maximum number of importer pods running at the same time in the cluster, the other imports wait in the Pending phase",
+													Type:        "integer",
+													Format:      "int32",
+												},
+												"podPriorityClassName": {
+													Description: "PodPriorityClassName is the default priority class of the importer, upload and clone pods",
+													Type:        "string",
+												},
 												"podResourceRequirements": {
 													Description: "ResourceRequirements describes the compute resource requirements.",
 													Type:        "object",
@@ -2971,10 +3100,112 @@ func createCDIListCRD() *extv1.CustomResourceDefinition {
 														},
 													},
 												},
+												"podSecurity": {
+													Description: "PodSecurity overrides the security context of the importer, upload server and clone source pods, which comply with the restricted Pod Security Standard by default",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"runAsUser": {
+															Description: "RunAsUser is the user the pods run as, 107 (qemu) if not set",
+															Format:      "int64",
+															Type:        "integer",
+														},
+														"seLinuxOptions": {
+															Description: "SELinuxOptions are the SELinux options of the pods, those of the container runtime if not set",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"level": {
+																	Description: "Level is SELinux level label that applies to the container.",
+																	Type:        "string",
+																},
+																"role": {
+																	Description: "Role is a SELinux role label that applies to the container.",
+																	Type:        "string",
+																},
+																"type": {
+																	Description: "Type is a SELinux type label that applies to the container.",
+																	Type:        "string",
+																},
+																"user": {
+																	Description: "User is a SELinux user label that applies to the container.",
+																	Type:        "string",
+																},
+															},
+															Type: "object",
+														},
+														"seccompProfile": {
+															Description: "SeccompProfile is the seccomp profile of the pods, runtime/default if not set",
+															Type:        "string",
+														},
+													},
+													Type: "object",
+												},
 												"preallocation": {
 													Description: "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
 													Type:        "boolean",
 												},
+												"registries": {
+													Description: "Registries configures the mirrors and the insecure registries consulted by registry imports",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Description: "RegistryConfig defines the mirrors of a registry, or of a namespace of a registry, tried before the registry itself",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"insecure": {
+																	Description: "Insecure allows plain HTTP and TLS connections without certificate verification to the registry",
+																	Type:        "boolean",
+																},
+																"mirrors": {
+																	Description: "Mirrors are tried in order before the registry, the images are pulled from the registry if none of them has the image",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Description: "RegistryMirror defines a mirror of a registry",
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"insecure": {
+																					Description: "Insecure allows plain HTTP and TLS connections without certificate verification to the mirror",
+																					Type:        "boolean",
+																				},
+																				"location": {
+																					Description: "Location is the host[:port] of the mirror, optionally followed by a namespace replacing the one of the registry, for instance mirror.example.com:5000/quay",
+																					Type:        "string",
+																				},
+																			},
+																			Required: []string{
+																				"location",
+																			},
+																			Type: "object",
+																		},
+																	},
+																	Type: "array",
+																},
+																"registry": {
+																	Description: "Registry is the host[:port] of the registry, optionally followed by a namespace, for instance quay.io/kubevirt. The configuration applies to the images under it",
+																	Type:        "string",
+																},
+															},
+															Required: []string{
+																"registry",
+															},
+															Type: "object",
+														},
+													},
+													Type: "array",
+												},
+												"tlsConfig": {
+													Description: "TLSConfig controls the minimum TLS version and the cipher suites used by the CDI servers and the http clients of the importer",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ciphers": {
+															Description: "Ciphers is the list of allowed cipher suites using the IANA names, for instance TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The cipher suites of TLS 1.3 are not configurable. If not set the default of the component is used",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+															Type: "array",
+														},
+														"minVersion": {
+															Description: "MinVersion is the minimum TLS version, one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13. If not set the default of the component is used",
+															Type:        "string",
+														},
+													},
+													Type: "object",
+												},
 											},
 										},
 										"certConfig": {
@@ -3009,8 +3240,259 @@ func createCDIListCRD() *extv1.CustomResourceDefinition {
 														},
 													},
 												},
+												"certManager": {
+													Type:        "object",
+													Description: "CertManager delegates the serving certificates of the apiserver and of the upload proxy to cert-manager, which then rotates them and their CA instead of CDI",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"issuerRef": {
+															Type:        "object",
+															Description: "IssuerRef is the cert-manager Issuer of the CDI namespace or the ClusterIssuer issuing the certificates. The issuer must provide the CA of the certificates, like the CA and Vault issuers",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {
+																	Description: "Name is the name of the issuer",
+																	Type:        "string",
+																},
+																"kind": {
+																	Description: "Kind is the kind of the issuer, Issuer if not set",
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"Issuer"`),
+																		},
+																		{
+																			Raw: []byte(`"ClusterIssuer"`),
+																		},
+																	},
+																},
+																"group": {
+																	Description: "Group is the API group of the issuer, cert-manager.io if not set",
+																	Type:        "string",
+																},
+															},
+															Required: []string{
+																"name",
+															},
+														},
+													},
+													Required: []string{
+														"issuerRef",
+													},
+												},
+											},
+										},
+										"uploadProxyExposure": {
+											Type:        "object",
+											Description: "UploadProxyExposure makes the operator expose the upload proxy outside of the cluster",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"type": {
+													Description: "Type is the kind of object exposing the upload proxy",
+													Type:        "string",
+													Enum: []extv1.JSON{
+														{
+															Raw: []byte(`"Ingress"`),
+														},
+														{
+															Raw: []byte(`"Route"`),
+														},
+													},
+												},
+												"hostname": {
+													Description: "Hostname is the external host name of the upload proxy",
+													Type:        "string",
+												},
+												"tlsSecretName": {
+													Description: "TLSSecretName is the name of a kubernetes.io/tls Secret of the CDI namespace, holding the certificate of the host name. The default certificate of the ingress controller or of the router is used if not set",
+													Type:        "string",
+												},
+												"ingressClassName": {
+													Description: "IngressClassName is the class of the Ingress, the default class is used if not set",
+													Type:        "string",
+												},
+												"annotations": {
+													Description: "Annotations are added to the Ingress or to the Route, for the settings of the ingress controller or of the router",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+											},
+											Required: []string{
+												"type",
+												"hostname",
+											},
+										},
+										"componentResources": {
+											Description: "ComponentResources overrides the resource requirements of the CDI control-plane components",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"apiServer": {
+													Description: "APIServer is the resource requirements of cdi-apiserver",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"limits": {
+															Description: "Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+														"requests": {
+															Description: "Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+												"controller": {
+													Description: "Controller is the resource requirements of cdi-deployment",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"limits": {
+															Description: "Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+														"requests": {
+															Description: "Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+												"uploadProxy": {
+													Description: "UploadProxy is the resource requirements of cdi-uploadproxy",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"limits": {
+															Description: "Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+														"requests": {
+															Description: "Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	AnyOf: []extv1.JSONSchemaProps{
+																		{
+																			Type: "integer",
+																		},
+																		{
+																			Type: "string",
+																		},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										"imagePullSecrets": {
+											Description: "ImagePullSecrets are the secrets used to pull the images of the CDI pods. The control-plane pods use the secrets of the CDI namespace, the importer, upload and clone pods use the secrets of the same names in their own namespace",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Description: "LocalObjectReference contains enough information to let you locate the referenced object inside the same namespace.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Description: "Name of the referent. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names TODO: Add other useful fields. apiVersion, kind, uid?",
+															Type:        "string",
+														},
+													},
+												},
 											},
 										},
+										"imageOverrides": {
+											Description: "ImageOverrides replaces the images of the importer, upload and clone pods, for instance with mirrored digests",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"cloner": {
+													Description: "Cloner is the image of the clone source pods",
+													Type:        "string",
+												},
+												"importer": {
+													Description: "Importer is the image of the importer and export pods",
+													Type:        "string",
+												},
+												"uploadServer": {
+													Description: "UploadServer is the image of the upload, download and clone target pods",
+													Type:        "string",
+												},
+											},
+										},
+										"priorityClass": {
+											Description: "PriorityClass is the priority class of the control-plane pods, which keeps them from being evicted before the transfer pods. The cdi-cluster-critical priority class created by the operator is used if not set",
+											Type:        "string",
+										},
 									},
 									Type:        "object",
 									Description: "CDISpec defines our specification for the CDI installation",
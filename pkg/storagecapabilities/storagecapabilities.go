@@ -0,0 +1,116 @@
+package storagecapabilities
+
+import (
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// StorageCapabilities is a simple holder of storage capabilities (accessMode etc.)
+type StorageCapabilities struct {
+	AccessMode v1.PersistentVolumeAccessMode
+	VolumeMode v1.PersistentVolumeMode
+}
+
+const (
+	rwo = v1.ReadWriteOnce
+	rox = v1.ReadOnlyMany
+	rwx = v1.ReadWriteMany
+
+	block = v1.PersistentVolumeBlock
+	file  = v1.PersistentVolumeFilesystem
+)
+
+// CapabilitiesByProvisionerKey defines the capabilities of the known provisioners, the first one is the recommended one
+var CapabilitiesByProvisionerKey = map[string][]StorageCapabilities{
+	// ceph-rbd
+	"kubernetes.io/rbd":                  createRbdCapabilities(),
+	"rbd.csi.ceph.com":                   createRbdCapabilities(),
+	"openshift-storage.rbd.csi.ceph.com": createRbdCapabilities(),
+	// ceph-fs
+	"cephfs.csi.ceph.com":                   {{rwx, file}, {rox, file}, {rwo, file}},
+	"openshift-storage.cephfs.csi.ceph.com": {{rwx, file}, {rox, file}, {rwo, file}},
+	// LINSTOR
+	"linstor.csi.linbit.com": createRbdCapabilities(),
+	// hostpath-provisioner
+	"kubevirt.io.hostpath-provisioner": {{rwo, file}},
+	"kubevirt.io/hostpath-provisioner": {{rwo, file}},
+	"k8s.io/minikube-hostpath":         {{rwo, file}},
+	"rancher.io/local-path":            {{rwo, file}},
+	// local volumes
+	"kubernetes.io/no-provisioner": {{rwo, file}, {rwo, block}},
+	"topolvm.cybozu.com":           {{rwo, block}, {rwo, file}},
+	// nfs
+	"nfs.csi.k8s.io": {{rwx, file}, {rox, file}, {rwo, file}},
+	"k8s-sigs.io/nfs-subdir-external-provisioner": {{rwx, file}, {rox, file}, {rwo, file}},
+	// AWS EBS
+	"kubernetes.io/aws-ebs": {{rwo, block}, {rwo, file}},
+	"ebs.csi.aws.com":       {{rwo, block}, {rwo, file}},
+	// GCE Persistent Disk
+	"kubernetes.io/gce-pd":  {{rwo, block}, {rwo, file}},
+	"pd.csi.storage.gke.io": {{rwo, block}, {rwo, file}},
+	// Azure Disk and Azure File
+	"kubernetes.io/azure-disk": {{rwo, block}, {rwo, file}},
+	"disk.csi.azure.com":       {{rwo, block}, {rwo, file}},
+	"kubernetes.io/azure-file": {{rwx, file}, {rox, file}, {rwo, file}},
+	"file.csi.azure.com":       {{rwx, file}, {rox, file}, {rwo, file}},
+	// OpenStack Cinder
+	"kubernetes.io/cinder":     {{rwo, block}, {rwo, file}},
+	"cinder.csi.openstack.org": {{rwo, block}, {rwo, file}},
+	// vSphere
+	"kubernetes.io/vsphere-volume": {{rwo, file}},
+	"csi.vsphere.vmware.com":       {{rwo, block}, {rwo, file}},
+	// Portworx
+	"kubernetes.io/portworx-volume": {{rwx, file}, {rwo, block}, {rwo, file}},
+	"pxd.portworx.com":              {{rwx, file}, {rwo, block}, {rwo, file}},
+	// NetApp Trident
+	"csi.trident.netapp.io": {{rwx, file}, {rwo, block}, {rwo, file}},
+}
+
+// CloneStrategyByProvisionerKey defines the clone strategy of the known provisioners which can clone their volumes
+// better than with the default snapshot strategy
+var CloneStrategyByProvisionerKey = map[string]cdiv1.CDICloneStrategy{
+	"rbd.csi.ceph.com":                      cdiv1.CloneStrategyCsiClone,
+	"openshift-storage.rbd.csi.ceph.com":    cdiv1.CloneStrategyCsiClone,
+	"cephfs.csi.ceph.com":                   cdiv1.CloneStrategyCsiClone,
+	"openshift-storage.cephfs.csi.ceph.com": cdiv1.CloneStrategyCsiClone,
+	"kubevirt.io.hostpath-provisioner":      cdiv1.CloneStrategyHostAssisted,
+	"kubevirt.io/hostpath-provisioner":      cdiv1.CloneStrategyHostAssisted,
+	"kubernetes.io/no-provisioner":          cdiv1.CloneStrategyHostAssisted,
+}
+
+func createRbdCapabilities() []StorageCapabilities {
+	return []StorageCapabilities{
+		{rwx, block},
+		{rwo, block},
+		{rwo, file},
+	}
+}
+
+// Get finds and returns the predefined StorageCapabilities of the provisioner of the StorageClass
+func Get(sc *storagev1.StorageClass) ([]StorageCapabilities, bool) {
+	capabilities, found := CapabilitiesByProvisionerKey[sc.Provisioner]
+	return capabilities, found
+}
+
+// GetCloneStrategy returns the predefined clone strategy of the provisioner of the StorageClass
+func GetCloneStrategy(sc *storagev1.StorageClass) (cdiv1.CDICloneStrategy, bool) {
+	strategy, found := CloneStrategyByProvisionerKey[sc.Provisioner]
+	return strategy, found
+}
+
+// Supports returns true if the provisioner supports the access mode in the volume mode, known is false when the
+// capabilities of the provisioner are not predefined
+func Supports(provisioner string, accessMode v1.PersistentVolumeAccessMode, volumeMode v1.PersistentVolumeMode) (supported, known bool) {
+	capabilities, found := CapabilitiesByProvisionerKey[provisioner]
+	if !found {
+		return false, false
+	}
+	for _, capability := range capabilities {
+		if capability.AccessMode == accessMode && capability.VolumeMode == volumeMode {
+			return true, true
+		}
+	}
+	return false, true
+}
@@ -37,6 +37,9 @@ const (
 
 	// OperationUpload is the type of token for uploading to a PVC
 	OperationUpload Operation = "Upload"
+
+	// OperationDownload is the type of token for downloading the disk of a PVC
+	OperationDownload Operation = "Download"
 )
 
 // Operation is the type of the token
@@ -0,0 +1,180 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadproxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// uploadLeaseNamespaceLabel is the label of the upload leases holding the namespace of the PVC
+	uploadLeaseNamespaceLabel = "cdi.kubevirt.io/storage.upload.namespace"
+	// uploadLeasePVCAnnotation is the annotation of the upload leases holding the name of the PVC, which can be too
+	// long for a label
+	uploadLeasePVCAnnotation = "cdi.kubevirt.io/storage.upload.pvc"
+
+	uploadLeasePrefix = "cdi-upload-"
+
+	// uploadLeaseDuration is how long the lease of an upload outlives a replica that stopped renewing it
+	uploadLeaseDuration = 60 * time.Second
+
+	uploadLeaseRenewInterval = 20 * time.Second
+)
+
+// uploadLeases shares the uploads in progress between the replicas of the upload proxy. Every rate limited upload
+// holds a Lease in the CDI namespace while it runs, so the limits apply to the uploads of every replica.
+type uploadLeases struct {
+	client    kubernetes.Interface
+	namespace string
+	holder    string
+}
+
+// uploadLease is the lease of an upload in progress, it is renewed until it is released
+type uploadLease struct {
+	leases *uploadLeases
+	name   string
+	stopCh chan struct{}
+}
+
+// acquire creates the lease of an upload to the PVC, and returns the number of uploads to the namespace and to the
+// PVC that were started before it by any replica
+func (l *uploadLeases) acquire(namespace, pvc string) (*uploadLease, int32, int32, error) {
+	now := metav1.NowMicro()
+	duration := int32(uploadLeaseDuration.Seconds())
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: uploadLeasePrefix + strings.ToLower(util.RandAlphaNum(12)),
+			Labels: map[string]string{
+				common.CDILabelKey:        common.CDILabelValue,
+				uploadLeaseNamespaceLabel: namespace,
+			},
+			Annotations: map[string]string{
+				uploadLeasePVCAnnotation: pvc,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &l.holder,
+			LeaseDurationSeconds: &duration,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+	lease, err := l.client.CoordinationV1().Leases(l.namespace).Create(context.TODO(), lease, metav1.CreateOptions{})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	acquired := &uploadLease{leases: l, name: lease.Name, stopCh: make(chan struct{})}
+
+	// Listed after the creation, so two replicas admitting uploads at the same time see each other's lease
+	leases, err := l.client.CoordinationV1().Leases(l.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: uploadLeaseNamespaceLabel + "=" + namespace,
+	})
+	if err != nil {
+		acquired.delete()
+		return nil, 0, 0, err
+	}
+	namespaceUploads, pvcUploads := int32(0), int32(0)
+	for i := range leases.Items {
+		other := &leases.Items[i]
+		if other.Name == lease.Name {
+			continue
+		}
+		if leaseExpired(other) {
+			l.deleteExpired(other)
+			continue
+		}
+		if leaseBefore(other, lease) {
+			namespaceUploads++
+			if other.Annotations[uploadLeasePVCAnnotation] == pvc {
+				pvcUploads++
+			}
+		}
+	}
+
+	go acquired.renew()
+	return acquired, namespaceUploads, pvcUploads, nil
+}
+
+func leaseExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(time.Now())
+}
+
+// leaseBefore orders the leases by acquire time then by name, which every replica sees the same
+func leaseBefore(lease, other *coordinationv1.Lease) bool {
+	if lease.Spec.AcquireTime == nil || other.Spec.AcquireTime == nil || lease.Spec.AcquireTime.Equal(other.Spec.AcquireTime) {
+		return lease.Name < other.Name
+	}
+	return lease.Spec.AcquireTime.Before(other.Spec.AcquireTime)
+}
+
+// deleteExpired removes the leases of the replicas that stopped without releasing them
+func (l *uploadLeases) deleteExpired(lease *coordinationv1.Lease) {
+	err := l.client.CoordinationV1().Leases(l.namespace).Delete(context.TODO(), lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if err != nil {
+		klog.V(3).Infof("Unable to delete expired upload lease %s: %v", lease.Name, err)
+	}
+}
+
+func (lease *uploadLease) renew() {
+	ticker := time.NewTicker(uploadLeaseRenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lease.stopCh:
+			return
+		case <-ticker.C:
+			patch := fmt.Sprintf(`{"spec":{"renewTime":%q}}`, metav1.NowMicro().Format(metav1.RFC3339Micro))
+			_, err := lease.leases.client.CoordinationV1().Leases(lease.leases.namespace).Patch(context.TODO(),
+				lease.name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+			if err != nil {
+				klog.Errorf("Unable to renew upload lease %s: %v", lease.name, err)
+			}
+		}
+	}
+}
+
+func (lease *uploadLease) release() {
+	close(lease.stopCh)
+	lease.delete()
+}
+
+func (lease *uploadLease) delete() {
+	err := lease.leases.client.CoordinationV1().Leases(lease.leases.namespace).Delete(context.TODO(), lease.name, metav1.DeleteOptions{})
+	if err != nil {
+		klog.Errorf("Unable to delete upload lease %s: %v", lease.name, err)
+	}
+}
@@ -0,0 +1,285 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadproxy
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/client/informers/externalversions"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// RateLimitWatcher is the interface of the watcher of the upload rate limits of the CDIConfig
+type RateLimitWatcher interface {
+	GetRateLimits() *cdiv1.UploadProxyRateLimits
+}
+
+type rateLimitWatcher struct {
+	limits *cdiv1.UploadProxyRateLimits
+	mutex  sync.RWMutex
+}
+
+// NewRateLimitWatcher creates a new RateLimitWatcher, that keeps track of the upload rate limits of the CDIConfig
+func NewRateLimitWatcher(client cdiclient.Interface, stopCh <-chan struct{}) RateLimitWatcher {
+	informerFactory := externalversions.NewFilteredSharedInformerFactory(client,
+		common.DefaultResyncPeriod,
+		metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = "metadata.name=" + common.ConfigName
+		},
+	)
+
+	configInformer := informerFactory.Cdi().V1beta1().CDIConfigs().Informer()
+
+	rw := &rateLimitWatcher{}
+
+	configInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			rw.updateLimits(obj.(*cdiv1.CDIConfig))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			rw.updateLimits(obj.(*cdiv1.CDIConfig))
+		},
+	})
+
+	go informerFactory.Start(stopCh)
+
+	cache.WaitForCacheSync(stopCh, configInformer.HasSynced)
+
+	return rw
+}
+
+// GetRateLimits returns the current upload rate limits, nil if uploads are not limited
+func (rw *rateLimitWatcher) GetRateLimits() *cdiv1.UploadProxyRateLimits {
+	rw.mutex.RLock()
+	defer rw.mutex.RUnlock()
+	return rw.limits
+}
+
+func (rw *rateLimitWatcher) updateLimits(config *cdiv1.CDIConfig) {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	rw.limits = config.Spec.UploadProxyRateLimits
+	klog.V(1).Infof("Updated upload rate limits %+v", rw.limits)
+}
+
+// uploadRateLimiter tracks the uploads in progress of every namespace and every PVC, along with their shared
+// bandwidth limiters. The entries are removed once their last upload is done. When the uploads are limited, the
+// leases count the uploads of the other replicas of the upload proxy too.
+type uploadRateLimiter struct {
+	mutex      sync.Mutex
+	namespaces map[string]*rateLimitEntry
+	pvcs       map[string]*rateLimitEntry
+	leases     *uploadLeases
+}
+
+type rateLimitEntry struct {
+	uploads int32
+	// otherUploads are the uploads through the other replicas when the last upload was admitted
+	otherUploads int32
+	bandwidth    int64
+	limiter      *rate.Limiter
+}
+
+// rateLimitedUpload is an upload admitted by the rate limiter, it must be released once done
+type rateLimitedUpload struct {
+	rateLimiter *uploadRateLimiter
+	namespace   string
+	pvcKey      string
+	limiters    []*rate.Limiter
+	lease       *uploadLease
+}
+
+func newUploadRateLimiter() *uploadRateLimiter {
+	return &uploadRateLimiter{
+		namespaces: make(map[string]*rateLimitEntry),
+		pvcs:       make(map[string]*rateLimitEntry),
+	}
+}
+
+// admit returns the upload to the PVC, or nil if the namespace or the PVC already have their maximum number of
+// concurrent uploads
+func (l *uploadRateLimiter) admit(limits *cdiv1.UploadProxyRateLimits, namespace, pvc string) *rateLimitedUpload {
+	if limits == nil {
+		limits = &cdiv1.UploadProxyRateLimits{}
+	}
+	pvcKey := namespace + "/" + pvc
+
+	var lease *uploadLease
+	namespaceUploads, pvcUploads := int32(0), int32(0)
+	if l.leases != nil && uploadsLimited(limits) {
+		var err error
+		lease, namespaceUploads, pvcUploads, err = l.leases.acquire(namespace, pvc)
+		if err != nil {
+			klog.Errorf("Unable to count the uploads of the other replicas, limiting the local uploads only: %v", err)
+		}
+	}
+
+	upload := l.countUpload(limits, namespace, pvcKey, lease, namespaceUploads, pvcUploads)
+	if upload == nil && lease != nil {
+		lease.release()
+	}
+	return upload
+}
+
+// countUpload counts the upload unless it is over the concurrent limits. Without lease, the uploads in progress are
+// the local ones, otherwise they are the uploads of every replica started before the lease, local ones included.
+func (l *uploadRateLimiter) countUpload(limits *cdiv1.UploadProxyRateLimits, namespace, pvcKey string, lease *uploadLease, namespaceUploads, pvcUploads int32) *rateLimitedUpload {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	namespaceEntry, pvcEntry := l.namespaces[namespace], l.pvcs[pvcKey]
+	if lease == nil {
+		namespaceUploads, pvcUploads = entryUploads(namespaceEntry), entryUploads(pvcEntry)
+	}
+	if limitReached(namespaceUploads, limits.MaxConcurrentUploadsPerNamespace) ||
+		limitReached(pvcUploads, limits.MaxConcurrentUploadsPerPVC) {
+		return nil
+	}
+	return &rateLimitedUpload{
+		rateLimiter: l,
+		namespace:   namespace,
+		pvcKey:      pvcKey,
+		limiters: []*rate.Limiter{
+			addUpload(l.namespaces, namespace, limits.MaxBandwidthPerNamespace, namespaceUploads-entryUploads(namespaceEntry)),
+			addUpload(l.pvcs, pvcKey, limits.MaxBandwidthPerPVC, pvcUploads-entryUploads(pvcEntry)),
+		},
+		lease: lease,
+	}
+}
+
+func uploadsLimited(limits *cdiv1.UploadProxyRateLimits) bool {
+	return limits.MaxConcurrentUploadsPerNamespace != nil || limits.MaxConcurrentUploadsPerPVC != nil ||
+		limits.MaxBandwidthPerNamespace != nil || limits.MaxBandwidthPerPVC != nil
+}
+
+func entryUploads(entry *rateLimitEntry) int32 {
+	if entry == nil {
+		return 0
+	}
+	return entry.uploads
+}
+
+func limitReached(uploads int32, maxUploads *int32) bool {
+	return maxUploads != nil && *maxUploads > 0 && uploads >= *maxUploads
+}
+
+// addUpload counts the upload in the entry of the key and returns its limiter, updated with the current bandwidth
+func addUpload(entries map[string]*rateLimitEntry, key string, bandwidth *resource.Quantity, otherUploads int32) *rate.Limiter {
+	entry, ok := entries[key]
+	if !ok {
+		entry = &rateLimitEntry{limiter: rate.NewLimiter(rate.Inf, 0)}
+		entries[key] = entry
+	}
+	entry.uploads++
+	entry.otherUploads = otherUploads
+	if entry.otherUploads < 0 {
+		entry.otherUploads = 0
+	}
+	entry.bandwidth = 0
+	if bandwidth != nil {
+		entry.bandwidth = bandwidth.Value()
+	}
+	entry.updateLimit()
+	return entry.limiter
+}
+
+// updateLimit shares the bandwidth between the replicas in proportion to their uploads
+func (entry *rateLimitEntry) updateLimit() {
+	limit, burst := rate.Inf, 0
+	if entry.bandwidth > 0 {
+		share := entry.bandwidth * int64(entry.uploads) / int64(entry.uploads+entry.otherUploads)
+		if share < 1 {
+			share = 1
+		}
+		// Allow bursts of a second of data
+		limit, burst = rate.Limit(share), int(share)
+	}
+	if entry.limiter.Limit() != limit || entry.limiter.Burst() != burst {
+		entry.limiter.SetLimit(limit)
+		entry.limiter.SetBurst(burst)
+	}
+}
+
+func removeUpload(entries map[string]*rateLimitEntry, key string) {
+	if entry, ok := entries[key]; ok {
+		entry.uploads--
+		if entry.uploads <= 0 {
+			delete(entries, key)
+		} else {
+			entry.updateLimit()
+		}
+	}
+}
+
+func (u *rateLimitedUpload) release() {
+	u.rateLimiter.mutex.Lock()
+	removeUpload(u.rateLimiter.namespaces, u.namespace)
+	removeUpload(u.rateLimiter.pvcs, u.pvcKey)
+	u.rateLimiter.mutex.Unlock()
+	if u.lease != nil {
+		u.lease.release()
+	}
+}
+
+// throttle returns a reader of the upload data limited to the bandwidth of the namespace and of the PVC
+func (u *rateLimitedUpload) throttle(ctx context.Context, reader io.ReadCloser) io.ReadCloser {
+	return &throttledReader{ReadCloser: reader, ctx: ctx, limiters: u.limiters}
+}
+
+type throttledReader struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	for _, limiter := range r.limiters {
+		if waitErr := waitN(r.ctx, limiter, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// waitN waits for n bytes in steps of at most the burst of the limiter
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	for n > 0 {
+		step := n
+		if burst := limiter.Burst(); limiter.Limit() != rate.Inf && step > burst {
+			step = burst
+		}
+		if err := limiter.WaitN(ctx, step); err != nil {
+			return err
+		}
+		n -= step
+	}
+	return nil
+}
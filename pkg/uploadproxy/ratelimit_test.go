@@ -0,0 +1,209 @@
+package uploadproxy
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+type fakeRateLimitWatcher struct {
+	limits *cdiv1.UploadProxyRateLimits
+}
+
+func (w *fakeRateLimitWatcher) GetRateLimits() *cdiv1.UploadProxyRateLimits {
+	return w.limits
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+var _ = Describe("Upload rate limiter", func() {
+	It("Should limit the concurrent uploads of a namespace and of a PVC", func() {
+		limiter := newUploadRateLimiter()
+		limits := &cdiv1.UploadProxyRateLimits{
+			MaxConcurrentUploadsPerNamespace: int32Ptr(2),
+			MaxConcurrentUploadsPerPVC:       int32Ptr(1),
+		}
+
+		first := limiter.admit(limits, "default", "pvc1")
+		Expect(first).ToNot(BeNil())
+		Expect(limiter.admit(limits, "default", "pvc1")).To(BeNil())
+		second := limiter.admit(limits, "default", "pvc2")
+		Expect(second).ToNot(BeNil())
+		Expect(limiter.admit(limits, "default", "pvc3")).To(BeNil())
+		Expect(limiter.admit(limits, "other", "pvc1")).ToNot(BeNil())
+
+		first.release()
+		Expect(limiter.admit(limits, "default", "pvc1")).ToNot(BeNil())
+	})
+
+	It("Should not limit uploads without limits", func() {
+		limiter := newUploadRateLimiter()
+		for i := 0; i < 10; i++ {
+			Expect(limiter.admit(nil, "default", "pvc1")).ToNot(BeNil())
+		}
+	})
+
+	It("Should forget the namespaces and PVCs without uploads", func() {
+		limiter := newUploadRateLimiter()
+		upload := limiter.admit(nil, "default", "pvc1")
+		Expect(limiter.namespaces).To(HaveLen(1))
+		Expect(limiter.pvcs).To(HaveLen(1))
+		upload.release()
+		Expect(limiter.namespaces).To(BeEmpty())
+		Expect(limiter.pvcs).To(BeEmpty())
+	})
+
+	It("Should limit the bandwidth of the uploads", func() {
+		limiter := newUploadRateLimiter()
+		bandwidth := resource.MustParse("1000")
+		upload := limiter.admit(&cdiv1.UploadProxyRateLimits{MaxBandwidthPerPVC: &bandwidth}, "default", "pvc1")
+		Expect(upload).ToNot(BeNil())
+		defer upload.release()
+
+		start := time.Now()
+		data, err := ioutil.ReadAll(upload.throttle(context.TODO(), ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 1500)))))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(HaveLen(1500))
+		// The first second of data is the burst of the limiter
+		Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+	})
+
+	It("Should limit the concurrent uploads of every replica", func() {
+		client := k8sfake.NewSimpleClientset()
+		replica1 := newUploadRateLimiter()
+		replica1.leases = &uploadLeases{client: client, namespace: "cdi", holder: "replica1"}
+		replica2 := newUploadRateLimiter()
+		replica2.leases = &uploadLeases{client: client, namespace: "cdi", holder: "replica2"}
+		limits := &cdiv1.UploadProxyRateLimits{
+			MaxConcurrentUploadsPerNamespace: int32Ptr(2),
+			MaxConcurrentUploadsPerPVC:       int32Ptr(1),
+		}
+
+		first := replica1.admit(limits, "default", "pvc1")
+		Expect(first).ToNot(BeNil())
+		Expect(replica2.admit(limits, "default", "pvc1")).To(BeNil())
+		second := replica2.admit(limits, "default", "pvc2")
+		Expect(second).ToNot(BeNil())
+		Expect(replica1.admit(limits, "default", "pvc3")).To(BeNil())
+		Expect(replica1.admit(limits, "other", "pvc1")).ToNot(BeNil())
+
+		first.release()
+		Expect(replica2.admit(limits, "default", "pvc1")).ToNot(BeNil())
+
+		leases, err := client.CoordinationV1().Leases("cdi").List(context.TODO(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leases.Items).To(HaveLen(3))
+	})
+
+	It("Should not count the expired leases", func() {
+		client := k8sfake.NewSimpleClientset()
+		limiter := newUploadRateLimiter()
+		limiter.leases = &uploadLeases{client: client, namespace: "cdi", holder: "replica1"}
+		limits := &cdiv1.UploadProxyRateLimits{MaxConcurrentUploadsPerPVC: int32Ptr(1)}
+
+		upload := limiter.admit(limits, "default", "pvc1")
+		Expect(upload).ToNot(BeNil())
+		leases, err := client.CoordinationV1().Leases("cdi").List(context.TODO(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leases.Items).To(HaveLen(1))
+		expired := leases.Items[0]
+		renewTime := metav1.NewMicroTime(time.Now().Add(-2 * uploadLeaseDuration))
+		expired.Spec.RenewTime = &renewTime
+		_, err = client.CoordinationV1().Leases("cdi").Update(context.TODO(), &expired, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		// As if the replica of the first upload stopped renewing its lease
+		other := newUploadRateLimiter()
+		other.leases = limiter.leases
+		Expect(other.admit(limits, "default", "pvc1")).ToNot(BeNil())
+		_, err = client.CoordinationV1().Leases("cdi").Get(context.TODO(), expired.Name, metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should share the bandwidth between the replicas", func() {
+		client := k8sfake.NewSimpleClientset()
+		replica1 := newUploadRateLimiter()
+		replica1.leases = &uploadLeases{client: client, namespace: "cdi", holder: "replica1"}
+		replica2 := newUploadRateLimiter()
+		replica2.leases = &uploadLeases{client: client, namespace: "cdi", holder: "replica2"}
+		bandwidth := resource.MustParse("1000")
+		limits := &cdiv1.UploadProxyRateLimits{MaxBandwidthPerPVC: &bandwidth}
+
+		first := replica1.admit(limits, "default", "pvc1")
+		Expect(first).ToNot(BeNil())
+		Expect(first.limiters[1].Limit()).To(BeEquivalentTo(1000))
+		second := replica2.admit(limits, "default", "pvc1")
+		Expect(second).ToNot(BeNil())
+		Expect(second.limiters[1].Limit()).To(BeEquivalentTo(500))
+		third := replica2.admit(limits, "default", "pvc1")
+		Expect(third).ToNot(BeNil())
+		Expect(third.limiters[1].Limit()).To(BeEquivalentTo(666))
+
+		third.release()
+		Expect(second.limiters[1].Limit()).To(BeEquivalentTo(500))
+	})
+
+	It("Should follow the rate limits of the CDIConfig", func() {
+		ch := make(chan struct{})
+		defer close(ch)
+		config := &cdiv1.CDIConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.ConfigName,
+			},
+		}
+		client := cdiclientfake.NewSimpleClientset(config)
+		watcher := NewRateLimitWatcher(client, ch)
+		Expect(watcher.GetRateLimits()).To(BeNil())
+
+		config.Spec.UploadProxyRateLimits = &cdiv1.UploadProxyRateLimits{MaxConcurrentUploadsPerPVC: int32Ptr(1)}
+		_, err := client.CdiV1beta1().CDIConfigs().Update(context.TODO(), config, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(watcher.GetRateLimits, 10*time.Second, 100*time.Millisecond).Should(Equal(config.Spec.UploadProxyRateLimits))
+	})
+})
+
+var _ = Describe("Rate limited upload request", func() {
+	It("Should reject the uploads over the concurrent limit", func() {
+		received := make(chan struct{}, 2)
+		done := make(chan struct{})
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- struct{}{}
+			<-done
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.rateLimitWatcher = &fakeRateLimitWatcher{limits: &cdiv1.UploadProxyRateLimits{MaxConcurrentUploadsPerPVC: int32Ptr(1)}}
+
+		firstResult := make(chan int)
+		go func() {
+			defer GinkgoRecover()
+			rr := httptest.NewRecorder()
+			app.ServeHTTP(rr, newProxyRequest(common.UploadPathAsync, "Bearer valid"))
+			firstResult <- rr.Code
+		}()
+		Eventually(received, 10*time.Second).Should(Receive())
+
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathAsync, "Bearer valid"), http.StatusTooManyRequests, app)
+
+		close(done)
+		Eventually(firstResult, 10*time.Second).Should(Receive(Equal(http.StatusOK)))
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathAsync, "Bearer valid"), http.StatusOK, app)
+	})
+})
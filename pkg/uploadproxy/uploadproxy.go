@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"os"
 	"regexp"
 	"strconv"
 	"time"
@@ -22,10 +24,14 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
 	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -62,9 +68,16 @@ type uploadProxyApp struct {
 	bindPort    uint
 
 	client kubernetes.Interface
+	// namespace is the namespace of CDI, holding the ConfigMap of the client CA
+	namespace string
 
 	certWatcher CertWatcher
 
+	tlsWatcher tlsconfig.Watcher
+
+	rateLimitWatcher RateLimitWatcher
+	rateLimiter      *uploadRateLimiter
+
 	clientCreator ClientCreator
 
 	tokenValidator token.Validator
@@ -72,13 +85,16 @@ type uploadProxyApp struct {
 	handler http.Handler
 
 	// test hooks
-	urlResolver    urlLookupFunc
-	uploadPossible uploadPossibleFunc
+	urlResolver         urlLookupFunc
+	uploadPossible      uploadPossibleFunc
+	downloadURLResolver urlLookupFunc
+	downloadPossible    uploadPossibleFunc
 }
 
 type clientCreator struct {
 	certFetcher   fetcher.CertFetcher
 	bundleFetcher fetcher.CertBundleFetcher
+	tlsWatcher    tlsconfig.Watcher
 }
 
 var authHeaderMatcher = regexp.MustCompile(`(?i)^Bearer\s+([A-Za-z0-9\-\._~\+\/]+)$`)
@@ -90,16 +106,24 @@ func NewUploadProxy(bindAddress string,
 	certWatcher CertWatcher,
 	clientCertFetcher fetcher.CertFetcher,
 	serverCAFetcher fetcher.CertBundleFetcher,
-	client kubernetes.Interface) (Server, error) {
+	client kubernetes.Interface,
+	tlsWatcher tlsconfig.Watcher,
+	rateLimitWatcher RateLimitWatcher) (Server, error) {
 	var err error
 	app := &uploadProxyApp{
-		bindAddress:    bindAddress,
-		bindPort:       bindPort,
-		certWatcher:    certWatcher,
-		clientCreator:  &clientCreator{certFetcher: clientCertFetcher, bundleFetcher: serverCAFetcher},
-		client:         client,
-		urlResolver:    controller.GetUploadServerURL,
-		uploadPossible: controller.UploadPossibleForPVC,
+		bindAddress:      bindAddress,
+		bindPort:         bindPort,
+		certWatcher:      certWatcher,
+		tlsWatcher:       tlsWatcher,
+		rateLimitWatcher: rateLimitWatcher,
+		clientCreator:    &clientCreator{certFetcher: clientCertFetcher, bundleFetcher: serverCAFetcher, tlsWatcher: tlsWatcher},
+		client:           client,
+		namespace:        util.GetNamespace(),
+		urlResolver:      controller.GetUploadServerURL,
+		uploadPossible:   controller.UploadPossibleForPVC,
+
+		downloadURLResolver: controller.GetDownloadServerURL,
+		downloadPossible:    controller.DownloadPossibleForPVC,
 	}
 	// retrieve RSA key used by apiserver to sign tokens
 	err = app.getSigningKey(apiServerPublicKey)
@@ -108,6 +132,11 @@ func NewUploadProxy(bindAddress string,
 	}
 
 	app.initHandler()
+	holder, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get the hostname")
+	}
+	app.rateLimiter.leases = &uploadLeases{client: client, namespace: app.namespace, holder: holder}
 
 	return app, nil
 }
@@ -142,6 +171,9 @@ func (c *clientCreator) CreateClient() (*http.Client, error) {
 		Certificates: []tls.Certificate{clientCert},
 		RootCAs:      caCertPool,
 	}
+	if c.tlsWatcher != nil {
+		c.tlsWatcher.GetOptions().Apply(tlsConfig)
+	}
 	tlsConfig.BuildNameToCertificate()
 
 	transport := &http.Transport{TLSClientConfig: tlsConfig}
@@ -149,53 +181,100 @@ func (c *clientCreator) CreateClient() (*http.Client, error) {
 }
 
 func (app *uploadProxyApp) initHandler() {
+	app.rateLimiter = newUploadRateLimiter()
 	mux := http.NewServeMux()
 	mux.HandleFunc(healthzPath, app.handleHealthzRequest)
 	for _, path := range common.ProxyPaths {
 		mux.HandleFunc(path, app.handleUploadRequest)
 	}
-	app.handler = cors.AllowAll().Handler(mux)
+	mux.HandleFunc(common.DownloadPath, app.handleDownloadRequest)
+	mux.HandleFunc(common.UploadStatusPath, app.handleUploadStatusRequest)
+	mux.HandleFunc(common.UploadPathWebSocket, app.handleWebSocketUploadRequest)
+	app.handler = cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		// Browser clients of tus uploads read the state of the upload from the response headers
+		ExposedHeaders: []string{
+			"Location",
+			common.TusResumableHeader,
+			common.TusUploadOffsetHeader,
+			common.TusUploadLengthHeader,
+		},
+	}).Handler(mux)
 }
 
 func (app *uploadProxyApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The readiness probe of the proxy has no client certificate
+	if r.URL.Path != healthzPath && !app.clientCertVerified(r) {
+		klog.Errorf("Rejected request from %s without a valid client certificate", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("client certificate required"))
+		return
+	}
 	app.handler.ServeHTTP(w, r)
 }
 
+// clientCertVerified checks the client certificate of the request when the CDIConfig requires them
+func (app *uploadProxyApp) clientCertVerified(r *http.Request) bool {
+	if app.tlsWatcher == nil || app.tlsWatcher.GetUploadProxyClientCAConfigMap() == "" {
+		return true
+	}
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
 func (app *uploadProxyApp) handleHealthzRequest(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "OK")
 }
 
-func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Request) {
+// validateToken returns the payload of the token of the request if it is valid for the operation, otherwise it
+// writes the error status of the request and returns nil
+func (app *uploadProxyApp) validateToken(w http.ResponseWriter, r *http.Request, operation token.Operation) *token.Payload {
 	tokenHeader := r.Header.Get("Authorization")
 	if tokenHeader == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil
 	}
 
 	match := authHeaderMatcher.FindStringSubmatch(tokenHeader)
 	if len(match) != 2 {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil
 	}
 
 	tokenData, err := app.tokenValidator.Validate(match[1])
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
-		return
+		return nil
 	}
 
-	if tokenData.Operation != token.OperationUpload ||
+	if tokenData.Operation != operation ||
 		tokenData.Name == "" ||
 		tokenData.Namespace == "" ||
 		tokenData.Resource.Resource != "persistentvolumeclaims" {
 		klog.Errorf("Bad token %+v", tokenData)
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil
 	}
 
 	klog.V(1).Infof("Received valid token: pvc: %s, namespace: %s", tokenData.Name, tokenData.Namespace)
+	return tokenData
+}
+
+func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Request) {
+	tokenData := app.validateToken(w, r, token.OperationUpload)
+	if tokenData == nil {
+		return
+	}
 
-	err = app.uploadReady(tokenData.Name, tokenData.Namespace)
+	uploadPVCName, err := app.uploadReady(tokenData.Name, tokenData.Namespace)
 	if err != nil {
 		klog.Error(err)
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -204,11 +283,118 @@ func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	app.proxyUploadRequest(tokenData.Namespace, tokenData.Name, w, r)
+	if uploadsData(r) {
+		upload := app.admitUpload(w, tokenData.Namespace, tokenData.Name)
+		if upload == nil {
+			return
+		}
+		defer upload.release()
+		r.Body = upload.throttle(r.Context(), r.Body)
+	}
+
+	app.proxyUploadRequest(tokenData.Namespace, uploadPVCName, w, r)
 }
 
-func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) error {
-	return wait.PollImmediate(waitReadyImterval, waitReadyTime, func() (bool, error) {
+// uploadsData checks if the request sends data to the upload server, the other requests are not rate limited
+func uploadsData(r *http.Request) bool {
+	return r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch
+}
+
+// admitUpload applies the rate limits of the CDIConfig to an upload, it writes the error status of the request and
+// returns nil if the namespace or the PVC already have their maximum number of concurrent uploads
+func (app *uploadProxyApp) admitUpload(w http.ResponseWriter, namespace, pvc string) *rateLimitedUpload {
+	var limits *cdiv1.UploadProxyRateLimits
+	if app.rateLimitWatcher != nil {
+		limits = app.rateLimitWatcher.GetRateLimits()
+	}
+	upload := app.rateLimiter.admit(limits, namespace, pvc)
+	if upload == nil {
+		klog.Warningf("Rejecting upload to PVC %s/%s, too many concurrent uploads", namespace, pvc)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("too many concurrent uploads"))
+	}
+	return upload
+}
+
+func (app *uploadProxyApp) handleDownloadRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	tokenData := app.validateToken(w, r, token.OperationDownload)
+	if tokenData == nil {
+		return
+	}
+
+	err := app.downloadReady(tokenData.Name, tokenData.Namespace)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// Return the error to the caller in the body.
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	app.proxyRequest(app.downloadURLResolver(tokenData.Namespace, tokenData.Name, r.URL.Path), w, r)
+}
+
+// handleUploadStatusRequest proxies status requests to the upload server while it runs, otherwise it returns the
+// last status recorded on the PVC by the upload controller
+func (app *uploadProxyApp) handleUploadStatusRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	tokenData := app.validateToken(w, r, token.OperationUpload)
+	if tokenData == nil {
+		return
+	}
+
+	pvc, err := app.client.CoreV1().PersistentVolumeClaims(tokenData.Namespace).Get(context.TODO(), tokenData.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		if k8serrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	uploadPVC, err := app.getUploadPVC(pvc)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	var status *uploadv1.UploadStatus
+	if uploadPVC == nil {
+		// The prime PVC does not exist before the upload can start, nor once its volume was bound to the PVC
+		status = &uploadv1.UploadStatus{Phase: uploadv1.UploadPhasePending}
+		if pvc.Status.Phase == v1.ClaimBound {
+			status = &uploadv1.UploadStatus{Phase: uploadv1.UploadPhaseSucceeded, Progress: "100.00%"}
+		}
+	} else {
+		status = controller.GetUploadStatus(uploadPVC)
+		ready, _ := strconv.ParseBool(uploadPVC.Annotations[controller.AnnPodReady])
+		if ready && status.Phase != uploadv1.UploadPhaseSucceeded && status.Phase != uploadv1.UploadPhaseFailed {
+			app.proxyUploadRequest(tokenData.Namespace, uploadPVC.Name, w, r)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// uploadReady waits for the upload server of the PVC to be ready, and returns the name of the PVC it writes to
+func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) (string, error) {
+	uploadPVCName := pvcName
+	err := wait.PollImmediate(waitReadyImterval, waitReadyTime, func() (bool, error) {
 		pvc, err := app.client.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(context.TODO(), pvcName, metav1.GetOptions{})
 		if err != nil {
 			if k8serrors.IsNotFound(err) {
@@ -217,22 +403,72 @@ func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) error {
 
 			return false, err
 		}
+		if controller.IsVolumeUploadSourceTarget(pvc) && pvc.Status.Phase == v1.ClaimBound {
+			return false, fmt.Errorf("rejecting Upload Request for PVC %s that already finished uploading", pvcName)
+		}
 
-		err = app.uploadPossible(pvc)
+		uploadPVC, err := app.getUploadPVC(pvc)
+		if err != nil || uploadPVC == nil {
+			return false, err
+		}
+		err = app.uploadPossible(uploadPVC)
 		if err != nil {
 			return false, err
 		}
-		phase := v1.PodPhase(pvc.Annotations[controller.AnnPodPhase])
+		phase := v1.PodPhase(uploadPVC.Annotations[controller.AnnPodPhase])
 		if phase == v1.PodSucceeded {
 			return false, fmt.Errorf("rejecting Upload Request for PVC %s that already finished uploading", pvcName)
 		}
 
-		ready, _ := strconv.ParseBool(pvc.Annotations[controller.AnnPodReady])
+		uploadPVCName = uploadPVC.Name
+		ready, _ := strconv.ParseBool(uploadPVC.Annotations[controller.AnnPodReady])
+		return ready, nil
+	})
+	return uploadPVCName, err
+}
+
+// getUploadPVC returns the PVC written by the uploads to the PVC, which is the prime PVC of a PVC populated by a
+// VolumeUploadSource, nil while that prime PVC does not exist
+func (app *uploadProxyApp) getUploadPVC(pvc *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
+	if !controller.IsVolumeUploadSourceTarget(pvc) {
+		return pvc, nil
+	}
+	primePVC, err := app.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(context.TODO(), controller.GetPrimePvcName(pvc), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return primePVC, nil
+}
+
+func (app *uploadProxyApp) downloadReady(pvcName, pvcNamespace string) error {
+	return wait.PollImmediate(waitReadyImterval, waitReadyTime, func() (bool, error) {
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(context.TODO(), pvcName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, fmt.Errorf("rejecting Download Request for PVC %s that doesn't exist", pvcName)
+			}
+
+			return false, err
+		}
+
+		err = app.downloadPossible(pvc)
+		if err != nil {
+			return false, err
+		}
+
+		ready, _ := strconv.ParseBool(pvc.Annotations[controller.AnnDownloadPodReady])
 		return ready, nil
 	})
 }
 
 func (app *uploadProxyApp) proxyUploadRequest(namespace, pvc string, w http.ResponseWriter, r *http.Request) {
+	app.proxyRequest(app.urlResolver(namespace, pvc, r.URL.Path), w, r)
+}
+
+func (app *uploadProxyApp) proxyRequest(serverURL string, w http.ResponseWriter, r *http.Request) {
 	client, err := app.clientCreator.CreateClient()
 	if err != nil {
 		klog.Error("Error creating http client")
@@ -242,7 +478,8 @@ func (app *uploadProxyApp) proxyUploadRequest(namespace, pvc string, w http.Resp
 
 	p := &httputil.ReverseProxy{
 		Director: func(req *http.Request) {
-			req.URL, _ = url.Parse(app.urlResolver(namespace, pvc, r.URL.Path))
+			req.URL, _ = url.Parse(serverURL)
+			req.URL.RawQuery = r.URL.RawQuery
 			if _, ok := req.Header["User-Agent"]; !ok {
 				// explicitly disable User-Agent so it's not set to default value
 				req.Header.Set("User-Agent", "")
@@ -268,6 +505,42 @@ func (app *uploadProxyApp) Start() error {
 	return app.startTLS()
 }
 
+// getTLSConfig returns the TLS config of the server with the current TLS options of the CDIConfig
+func (app *uploadProxyApp) getTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		GetCertificate: app.certWatcher.GetCertificate,
+	}
+	if app.tlsWatcher != nil {
+		app.tlsWatcher.GetOptions().Apply(tlsConfig)
+		if configMapName := app.tlsWatcher.GetUploadProxyClientCAConfigMap(); configMapName != "" {
+			if err := app.verifyClientCerts(tlsConfig, configMapName); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tlsConfig, nil
+}
+
+// verifyClientCerts verifies the client certificates against the CA bundle of the ConfigMap. They are not required
+// during the handshake so the readiness probe still works, ServeHTTP rejects the requests without one.
+func (app *uploadProxyApp) verifyClientCerts(tlsConfig *tls.Config, configMapName string) error {
+	bundleFetcher := &fetcher.ConfigMapCertBundleFetcher{
+		Name:   configMapName,
+		Client: app.client.CoreV1().ConfigMaps(app.namespace),
+	}
+	bundle, err := bundleFetcher.BundleBytes()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the client CA bundle of ConfigMap %s", configMapName)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(bundle) {
+		return errors.Errorf("invalid client CA bundle in ConfigMap %s", configMapName)
+	}
+	tlsConfig.ClientCAs = caCertPool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return nil
+}
+
 func (app *uploadProxyApp) startTLS() error {
 	var serveFunc func() error
 	bindAddr := fmt.Sprintf("%s:%d", app.bindAddress, app.bindPort)
@@ -281,6 +554,14 @@ func (app *uploadProxyApp) startTLS() error {
 		server.TLSConfig = &tls.Config{
 			GetCertificate: app.certWatcher.GetCertificate,
 		}
+		server.TLSConfig.GetConfigForClient = func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			klog.V(3).Info("Getting TLS config")
+			tlsConfig, err := app.getTLSConfig()
+			if err != nil {
+				klog.Errorf("Unable to get TLS config: %v", err)
+			}
+			return tlsConfig, err
+		}
 
 		serveFunc = func() error {
 			return server.ListenAndServeTLS("", "")
@@ -1,8 +1,12 @@
 package uploadproxy
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -18,11 +22,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 type httpClientConfig struct {
@@ -48,6 +54,14 @@ func (*validateSuccess) Validate(string) (*token.Payload, error) {
 	}, nil
 }
 
+type validateDownload struct{}
+
+func (*validateDownload) Validate(string) (*token.Payload, error) {
+	payload, _ := (&validateSuccess{}).Validate("")
+	payload.Operation = token.OperationDownload
+	return payload, nil
+}
+
 func (*validateFailure) Validate(string) (*token.Payload, error) {
 	return nil, fmt.Errorf("Bad token")
 }
@@ -142,8 +156,86 @@ var _ = Describe("Certificate functions", func() {
 		_, err := cc.CreateClient()
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("Get upload server client with TLS options", func() {
+		certs := getHTTPClientConfig()
+		certFetcher := &fetcher.MemCertFetcher{Cert: certs.cert, Key: certs.key}
+		bundleFetcher := &fetcher.MemCertBundleFetcher{Bundle: certs.caCert}
+		tlsWatcher := &fakeTLSWatcher{options: &tlsconfig.Options{MinVersion: tls.VersionTLS12}}
+
+		cc := &clientCreator{certFetcher: certFetcher, bundleFetcher: bundleFetcher, tlsWatcher: tlsWatcher}
+		client, err := cc.CreateClient()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Transport.(*http.Transport).TLSClientConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+	})
+
+	It("Get server TLS config with TLS options", func() {
+		app := createApp()
+		app.certWatcher = &fakeCertWatcher{}
+		app.tlsWatcher = &fakeTLSWatcher{options: &tlsconfig.Options{
+			MinVersion:   tls.VersionTLS13,
+			CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		}}
+
+		tlsConfig, err := app.getTLSConfig()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(tlsConfig.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}))
+		Expect(tlsConfig.GetCertificate).ToNot(BeNil())
+		Expect(tlsConfig.ClientAuth).To(Equal(tls.NoClientCert))
+	})
+
+	It("Get server TLS config verifying client certificates", func() {
+		certs := getHTTPClientConfig()
+		app := createApp()
+		app.namespace = "cdi"
+		app.certWatcher = &fakeCertWatcher{}
+		app.tlsWatcher = &fakeTLSWatcher{clientCAConfigMap: "client-ca"}
+		app.client = k8sfake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "client-ca",
+				Namespace: "cdi",
+			},
+			Data: map[string]string{"ca-bundle.crt": string(certs.caCert)},
+		})
+
+		tlsConfig, err := app.getTLSConfig()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.ClientAuth).To(Equal(tls.VerifyClientCertIfGiven))
+		Expect(tlsConfig.ClientCAs).ToNot(BeNil())
+	})
+
+	It("Fail to get server TLS config if the client CA ConfigMap is missing", func() {
+		app := createApp()
+		app.namespace = "cdi"
+		app.certWatcher = &fakeCertWatcher{}
+		app.tlsWatcher = &fakeTLSWatcher{clientCAConfigMap: "client-ca"}
+		app.client = k8sfake.NewSimpleClientset()
+
+		_, err := app.getTLSConfig()
+		Expect(err).To(HaveOccurred())
+	})
 })
 
+type fakeTLSWatcher struct {
+	options           *tlsconfig.Options
+	clientCAConfigMap string
+}
+
+func (w *fakeTLSWatcher) GetOptions() *tlsconfig.Options {
+	return w.options
+}
+
+func (w *fakeTLSWatcher) GetUploadProxyClientCAConfigMap() string {
+	return w.clientCAConfigMap
+}
+
+type fakeCertWatcher struct{}
+
+func (w *fakeCertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return &tls.Certificate{}, nil
+}
+
 type fakeClientCreator struct {
 	client *http.Client
 }
@@ -164,8 +256,9 @@ func setupProxyTests(handler http.HandlerFunc) *uploadProxyApp {
 			Name:      "testpvc",
 			Namespace: "default",
 			Annotations: map[string]string{
-				"cdi.kubevirt.io/storage.pod.phase": "Running",
-				"cdi.kubevirt.io/storage.pod.ready": "true",
+				"cdi.kubevirt.io/storage.pod.phase":         "Running",
+				"cdi.kubevirt.io/storage.pod.ready":         "true",
+				"cdi.kubevirt.io/storage.download.podReady": "true",
 			},
 		},
 	}
@@ -176,6 +269,9 @@ func setupProxyTests(handler http.HandlerFunc) *uploadProxyApp {
 	app.client = k8sfake.NewSimpleClientset(objects...)
 	app.tokenValidator = &validateSuccess{}
 	app.urlResolver = urlResolver
+	app.downloadURLResolver = func(_, _, path string) string {
+		return server.URL + path
+	}
 	app.clientCreator = &fakeClientCreator{client: server.Client()}
 
 	return app
@@ -199,6 +295,9 @@ var _ = Describe("submit request and check status", func() {
 		table.Entry("Test Form Sync error", common.UploadFormSync, http.StatusInternalServerError),
 		table.Entry("Test Form Async OK", common.UploadFormAsync, http.StatusOK),
 		table.Entry("Test Form Async error", common.UploadFormAsync, http.StatusInternalServerError),
+		table.Entry("Test Chunked OK", common.UploadPathChunked, http.StatusCreated),
+		table.Entry("Test Chunked chunk OK", common.UploadPathChunked+"/0", http.StatusNoContent),
+		table.Entry("Test Chunked error", common.UploadPathChunked+"/"+common.ChunkedUploadComplete, http.StatusConflict),
 	)
 	table.DescribeTable("Test proxy status code with CORS", func(path string, statusCode int) {
 		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -270,4 +369,236 @@ var _ = Describe("submit request and check status", func() {
 		Expect(err).ToNot(HaveOccurred())
 		submitRequestAndCheckStatus(req, http.StatusOK, nil)
 	})
+
+	table.DescribeTable("Test proxy client certificate", func(connectionState *tls.ConnectionState, statusCode int) {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.tlsWatcher = &fakeTLSWatcher{clientCAConfigMap: "client-ca"}
+
+		req := newProxyRequest(common.UploadPathSync, "Bearer valid")
+		req.TLS = connectionState
+		submitRequestAndCheckStatus(req, statusCode, app)
+	},
+		table.Entry("No TLS connection", nil, http.StatusUnauthorized),
+		table.Entry("No client certificate", &tls.ConnectionState{}, http.StatusUnauthorized),
+		table.Entry("Verified client certificate", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}, http.StatusOK),
+	)
+
+	It("Test healthz without client certificate", func() {
+		app := createApp()
+		app.tlsWatcher = &fakeTLSWatcher{clientCAConfigMap: "client-ca"}
+		req, err := http.NewRequest("GET", healthzPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		submitRequestAndCheckStatus(req, http.StatusOK, app)
+	})
+})
+
+var _ = Describe("Download request", func() {
+	var query string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte("disk content"))
+	})
+
+	It("Should proxy the download to the download server of the PVC", func() {
+		app := setupProxyTests(handler)
+		app.tokenValidator = &validateDownload{}
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req, err := http.NewRequest("GET", common.DownloadPath+"?format=qcow2", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(Equal("disk content"))
+		Expect(query).To(Equal("format=qcow2"))
+	})
+
+	It("Should reject upload tokens", func() {
+		app := setupProxyTests(handler)
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req, err := http.NewRequest("GET", common.DownloadPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusBadRequest, app)
+	})
+
+	It("Should reject PVCs that are not download sources", func() {
+		app := setupProxyTests(handler)
+		app.tokenValidator = &validateDownload{}
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return fmt.Errorf("NOPE") }
+
+		req, err := http.NewRequest("GET", common.DownloadPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusServiceUnavailable, app)
+	})
+
+	It("Should reject uploads to the download path", func() {
+		app := setupProxyTests(handler)
+		app.tokenValidator = &validateDownload{}
+
+		req := newProxyRequest(common.DownloadPath, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusMethodNotAllowed, app)
+	})
+})
+
+var _ = Describe("Tus upload request", func() {
+	It("Should proxy requests to the upload resource and expose the tus headers", func() {
+		var method, path string
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			w.Header().Set(common.TusUploadOffsetHeader, "4")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		resolver := app.urlResolver
+		app.urlResolver = func(namespace, pvc, path string) string {
+			return resolver(namespace, pvc, path) + path
+		}
+
+		uploadURL := common.UploadPathTus + "/" + common.TusUploadID
+		req, err := http.NewRequest(http.MethodPatch, uploadURL, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		req.Header.Set("Origin", "foo.bar.com")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(method).To(Equal(http.MethodPatch))
+		Expect(path).To(Equal(uploadURL))
+		Expect(rr.Header().Get(common.TusUploadOffsetHeader)).To(Equal("4"))
+		Expect(rr.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring(common.TusUploadOffsetHeader))
+	})
+})
+
+var _ = Describe("Upload status request", func() {
+	var proxied bool
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		w.Write([]byte(`{"phase":"Uploading","bytesReceived":4}`))
+	})
+
+	BeforeEach(func() {
+		proxied = false
+	})
+
+	getStatus := func(app *uploadProxyApp) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, common.UploadStatusPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		return rr
+	}
+
+	It("Should proxy the request to the running upload server", func() {
+		app := setupProxyTests(handler)
+		rr := getStatus(app)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(proxied).To(BeTrue())
+		Expect(rr.Body.String()).To(ContainSubstring("Uploading"))
+	})
+
+	It("Should return the status recorded on the PVC once the upload finished", func() {
+		app := setupProxyTests(handler)
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		pvc.Annotations["cdi.kubevirt.io/storage.pod.phase"] = string(v1.PodSucceeded)
+		pvc.Annotations["cdi.kubevirt.io/storage.upload.status"] = `{"phase":"Processing","bytesReceived":4,"totalBytes":4}`
+		_, err = app.client.CoreV1().PersistentVolumeClaims("default").Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := getStatus(app)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(proxied).To(BeFalse())
+		status := &uploadv1.UploadStatus{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+		Expect(status.Phase).To(Equal(uploadv1.UploadPhaseSucceeded))
+		Expect(status.BytesReceived).To(Equal(int64(4)))
+	})
+
+	It("Should return not found for missing PVCs", func() {
+		app := setupProxyTests(handler)
+		app.client = k8sfake.NewSimpleClientset()
+		Expect(getStatus(app).Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("Upload to a PVC populated by a VolumeUploadSource", func() {
+	var uploadPVCName string
+
+	setupPopulatorTests := func(targetPhase v1.PersistentVolumeClaimPhase, objects ...runtime.Object) *uploadProxyApp {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		resolver := app.urlResolver
+		app.urlResolver = func(namespace, pvc, path string) string {
+			uploadPVCName = pvc
+			return resolver(namespace, pvc, path)
+		}
+		apiGroup := "cdi.kubevirt.io"
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testpvc",
+				Namespace: "default",
+				UID:       "testpvc-uid",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				DataSource: &corev1.TypedLocalObjectReference{
+					APIGroup: &apiGroup,
+					Kind:     "VolumeUploadSource",
+					Name:     "upload-source",
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase: targetPhase,
+			},
+		}
+		app.client = k8sfake.NewSimpleClientset(append(objects, pvc)...)
+		return app
+	}
+
+	It("Should proxy the upload to the prime PVC", func() {
+		primePVC := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "prime-testpvc-uid",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"cdi.kubevirt.io/storage.upload.target": "",
+					"cdi.kubevirt.io/storage.pod.phase":     "Running",
+					"cdi.kubevirt.io/storage.pod.ready":     "true",
+				},
+			},
+		}
+		app := setupPopulatorTests(corev1.ClaimPending, primePVC)
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathSync, "Bearer valid"), http.StatusOK, app)
+		Expect(uploadPVCName).To(Equal("prime-testpvc-uid"))
+	})
+
+	It("Should reject the uploads once the PVC is populated", func() {
+		app := setupPopulatorTests(corev1.ClaimBound)
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathSync, "Bearer valid"), http.StatusServiceUnavailable, app)
+	})
+
+	It("Should return a succeeded status once the PVC is populated", func() {
+		app := setupPopulatorTests(corev1.ClaimBound)
+		req, err := http.NewRequest(http.MethodGet, common.UploadStatusPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		status := &uploadv1.UploadStatus{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+		Expect(status.Phase).To(Equal(uploadv1.UploadPhaseSucceeded))
+	})
 })
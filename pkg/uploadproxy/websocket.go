@@ -0,0 +1,141 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadproxy
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+)
+
+// webSocketTokenParameter is the query parameter of the upload token, browsers can't set the headers of websockets
+const webSocketTokenParameter = "token"
+
+// webSocketUploadResult is the last message sent to websocket upload clients, the response of the upload server
+type webSocketUploadResult struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message,omitempty"`
+}
+
+// webSocketMessage is a message received from websocket upload clients
+type webSocketMessage struct {
+	payloadType byte
+	data        []byte
+}
+
+// webSocketMessageCodec receives messages along with their type, binary messages carry the image and a text
+// message ends the upload
+var webSocketMessageCodec = websocket.Codec{
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		message := v.(*webSocketMessage)
+		message.payloadType = payloadType
+		message.data = data
+		return nil
+	},
+}
+
+// handleWebSocketUploadRequest accepts images streamed in the binary messages of a websocket, for clients behind
+// proxies that buffer or cut long lived HTTP requests. The messages are forwarded as the body of an async upload to
+// the upload server, and the response of the upload server is sent back once a text message ends the upload.
+func (app *uploadProxyApp) handleWebSocketUploadRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" && r.URL.Query().Get(webSocketTokenParameter) != "" {
+		r.Header.Set("Authorization", "Bearer "+r.URL.Query().Get(webSocketTokenParameter))
+	}
+	tokenData := app.validateToken(w, r, token.OperationUpload)
+	if tokenData == nil {
+		return
+	}
+
+	uploadPVCName, err := app.uploadReady(tokenData.Name, tokenData.Namespace)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// Return the error to the caller in the body.
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	upload := app.admitUpload(w, tokenData.Namespace, tokenData.Name)
+	if upload == nil {
+		return
+	}
+	defer upload.release()
+
+	serverURL := app.urlResolver(tokenData.Namespace, uploadPVCName, common.UploadPathAsync)
+	websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			result := app.forwardWebSocketUpload(ws, serverURL, upload)
+			if err := websocket.JSON.Send(ws, result); err != nil {
+				klog.Errorf("Sending the result of the websocket upload failed: %v", err)
+			}
+		},
+	}.ServeHTTP(w, r)
+}
+
+func (app *uploadProxyApp) forwardWebSocketUpload(ws *websocket.Conn, serverURL string, upload *rateLimitedUpload) *webSocketUploadResult {
+	client, err := app.clientCreator.CreateClient()
+	if err != nil {
+		klog.Error("Error creating http client")
+		return &webSocketUploadResult{StatusCode: http.StatusInternalServerError}
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(receiveWebSocketUpload(ws, writer))
+	}()
+	resp, err := client.Post(serverURL, "application/octet-stream", upload.throttle(ws.Request().Context(), reader))
+	// Unblocks the receiving of the upload if the upload server did not read it all
+	reader.Close()
+	if err != nil {
+		klog.Errorf("Forwarding the websocket upload failed: %v", err)
+		return &webSocketUploadResult{StatusCode: http.StatusBadGateway, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		klog.Errorf("Reading the upload server response failed: %v", err)
+	}
+	return &webSocketUploadResult{StatusCode: resp.StatusCode, Message: string(body)}
+}
+
+// receiveWebSocketUpload writes the binary messages of the websocket until a text message ends the upload
+func receiveWebSocketUpload(ws *websocket.Conn, writer io.Writer) error {
+	for {
+		message := &webSocketMessage{}
+		if err := webSocketMessageCodec.Receive(ws, message); err != nil {
+			return errors.Wrap(err, "websocket upload interrupted")
+		}
+		if message.payloadType != websocket.BinaryFrame {
+			return nil
+		}
+		if _, err := writer.Write(message.data); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package uploadproxy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/websocket"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("WebSocket upload request", func() {
+	var path, received string
+	var proxy *httptest.Server
+
+	setupWebSocketProxy := func(statusCode int) *uploadProxyApp {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			data, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			received = string(data)
+			w.WriteHeader(statusCode)
+			w.Write([]byte("upload server response"))
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		resolver := app.urlResolver
+		app.urlResolver = func(namespace, pvc, path string) string {
+			return resolver(namespace, pvc, path) + path
+		}
+		proxy = httptest.NewServer(app)
+		return app
+	}
+
+	dial := func(query string) *websocket.Conn {
+		wsURL := "ws" + strings.TrimPrefix(proxy.URL, "http") + common.UploadPathWebSocket + query
+		ws, err := websocket.Dial(wsURL, "", proxy.URL)
+		Expect(err).ToNot(HaveOccurred())
+		return ws
+	}
+
+	upload := func(ws *websocket.Conn) *webSocketUploadResult {
+		defer ws.Close()
+		Expect(websocket.Message.Send(ws, []byte("da"))).To(Succeed())
+		Expect(websocket.Message.Send(ws, []byte("ta"))).To(Succeed())
+		Expect(websocket.Message.Send(ws, "done")).To(Succeed())
+		result := &webSocketUploadResult{}
+		Expect(websocket.JSON.Receive(ws, result)).To(Succeed())
+		return result
+	}
+
+	BeforeEach(func() {
+		path = ""
+		received = ""
+	})
+
+	AfterEach(func() {
+		proxy.Close()
+	})
+
+	It("Should forward the binary messages as an async upload", func() {
+		setupWebSocketProxy(http.StatusOK)
+		result := upload(dial("?token=valid"))
+		Expect(result.StatusCode).To(Equal(http.StatusOK))
+		Expect(result.Message).To(Equal("upload server response"))
+		Expect(path).To(Equal(common.UploadPathAsync))
+		Expect(received).To(Equal("data"))
+	})
+
+	It("Should return the errors of the upload server", func() {
+		setupWebSocketProxy(http.StatusConflict)
+		result := upload(dial("?token=valid"))
+		Expect(result.StatusCode).To(Equal(http.StatusConflict))
+	})
+
+	It("Should reject invalid tokens before upgrading the connection", func() {
+		app := setupWebSocketProxy(http.StatusOK)
+		app.tokenValidator = &validateFailure{}
+		_, err := websocket.Dial("ws"+strings.TrimPrefix(proxy.URL, "http")+common.UploadPathWebSocket+"?token=invalid", "", proxy.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(path).To(BeEmpty())
+	})
+})
@@ -0,0 +1,309 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	chunkedDataFile  = "chunked-upload.img"
+	chunkedStateFile = "chunked-upload.json"
+	// chunkedChunksDir holds an empty file per received chunk, named after its index
+	chunkedChunksDir = "chunked-upload.chunks"
+	// maxChunkSize bounds the memory used to verify chunks, which are read whole before they are written
+	maxChunkSize = 64 * 1024 * 1024
+)
+
+// chunkedUploadStatus is the state of a chunked upload, returned by GET requests of the chunked upload path
+type chunkedUploadStatus struct {
+	// Length is the size of the whole image
+	Length int64 `json:"length"`
+	// ChunkSize is the size of every chunk but the last one
+	ChunkSize int64 `json:"chunkSize"`
+	// Chunks is the number of chunks of the image
+	Chunks int64 `json:"chunks"`
+	// Received are the indexes of the chunks stored by the server
+	Received []int64 `json:"received"`
+}
+
+func (s *chunkedUploadStatus) chunkLength(index int64) int64 {
+	if index == s.Chunks-1 {
+		return s.Length - index*s.ChunkSize
+	}
+	return s.ChunkSize
+}
+
+func (s *chunkedUploadStatus) receivedBytes() int64 {
+	received := int64(0)
+	for _, index := range s.Received {
+		received += s.chunkLength(index)
+	}
+	return received
+}
+
+// chunkedHandler handles uploads of images split in fixed size chunks, which can be sent in any order and in
+// parallel. Every chunk is verified against its sha256 checksum and written at its offset of a file of the scratch
+// space, a chunk that failed is simply sent again. Once every chunk is received, completing the upload processes
+// the image in the background like async uploads.
+func (app *uploadServerApp) chunkedHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.clientAuthorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodPost && path == common.UploadPathChunked:
+		app.chunkedCreate(w, r)
+	case r.Method == http.MethodGet && path == common.UploadPathChunked:
+		app.chunkedStatus(w)
+	case r.Method == http.MethodPost && path == common.UploadPathChunked+"/"+common.ChunkedUploadComplete:
+		app.chunkedComplete(w)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, common.UploadPathChunked+"/"):
+		index, err := strconv.ParseInt(strings.TrimPrefix(path, common.UploadPathChunked+"/"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		app.chunkedPut(w, r, index)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (app *uploadServerApp) chunkedCreate(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get(common.UploadLengthHeader), 10, 64)
+	if err != nil || length <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header", common.UploadLengthHeader)))
+		return
+	}
+	chunkSize, err := strconv.ParseInt(r.Header.Get(common.UploadChunkSizeHeader), 10, 64)
+	if err != nil || chunkSize <= 0 || chunkSize > maxChunkSize {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header, chunks are at most %d bytes", common.UploadChunkSizeHeader, maxChunkSize)))
+		return
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if !app.acceptsUploads(w) {
+		return
+	}
+	if app.chunkUploads > 0 {
+		klog.Warning("Got upload creation request while chunks are uploaded")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	status := &chunkedUploadStatus{
+		Length:    length,
+		ChunkSize: chunkSize,
+		Chunks:    (length + chunkSize - 1) / chunkSize,
+	}
+	if err := createChunkedUpload(status); err != nil {
+		klog.Errorf("Creating the upload failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	klog.Infof("Created chunked upload of %d bytes in %d chunks", length, status.Chunks)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (app *uploadServerApp) chunkedStatus(w http.ResponseWriter) {
+	status, err := chunkedUploadState()
+	if err != nil {
+		klog.Errorf("Reading the upload state failed: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (app *uploadServerApp) chunkedPut(w http.ResponseWriter, r *http.Request, index int64) {
+	checksum, err := hex.DecodeString(r.Header.Get(common.UploadChunkChecksumHeader))
+	if err != nil || len(checksum) != sha256.Size {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header", common.UploadChunkChecksumHeader)))
+		return
+	}
+
+	app.mutex.Lock()
+	if !app.acceptsUploads(w) {
+		app.mutex.Unlock()
+		return
+	}
+	status, err := chunkedUploadState()
+	if err != nil {
+		app.mutex.Unlock()
+		klog.Errorf("Reading the upload state failed: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if index < 0 || index >= status.Chunks {
+		app.mutex.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Chunk index %d out of range, the upload has %d chunks", index, status.Chunks)))
+		return
+	}
+	// Chunks are written concurrently, each at its own offset
+	app.chunkUploads++
+	app.mutex.Unlock()
+
+	err = writeChunk(r.Body, status, index, checksum)
+
+	app.mutex.Lock()
+	app.chunkUploads--
+	app.mutex.Unlock()
+
+	if err != nil {
+		klog.Warningf("Chunk %d rejected: %v", index, err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Chunk %d rejected: %s", index, err.Error())))
+		return
+	}
+	klog.V(1).Infof("Received chunk %d", index)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *uploadServerApp) chunkedComplete(w http.ResponseWriter) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if !app.acceptsUploads(w) {
+		return
+	}
+	if app.chunkUploads > 0 {
+		klog.Warning("Got upload completion request while chunks are uploaded")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	status, err := chunkedUploadState()
+	if err != nil {
+		klog.Errorf("Reading the upload state failed: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if missing := status.Chunks - int64(len(status.Received)); missing > 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(fmt.Sprintf("%d chunks are missing", missing)))
+		return
+	}
+	if err := app.processReceivedImage(filepath.Join(resumableUploadDir, chunkedDataFile)); err != nil {
+		klog.Errorf("Processing the upload failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createChunkedUpload discards the data of any previous upload and allocates the file of the image
+func createChunkedUpload(status *chunkedUploadStatus) error {
+	chunksDir := filepath.Join(resumableUploadDir, chunkedChunksDir)
+	if err := os.RemoveAll(chunksDir); err != nil {
+		return err
+	}
+	if err := os.Mkdir(chunksDir, 0700); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(resumableUploadDir, chunkedDataFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := file.Truncate(status.Length); err != nil {
+		return err
+	}
+	state, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(resumableUploadDir, chunkedStateFile), state, 0600)
+}
+
+func chunkedUploadState() (*chunkedUploadStatus, error) {
+	data, err := ioutil.ReadFile(filepath.Join(resumableUploadDir, chunkedStateFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "no upload created")
+	}
+	status := &chunkedUploadStatus{}
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, errors.Wrap(err, "invalid upload state")
+	}
+	chunks, err := ioutil.ReadDir(filepath.Join(resumableUploadDir, chunkedChunksDir))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid upload state")
+	}
+	status.Received = []int64{}
+	for _, chunk := range chunks {
+		if index, err := strconv.ParseInt(chunk.Name(), 10, 64); err == nil {
+			status.Received = append(status.Received, index)
+		}
+	}
+	return status, nil
+}
+
+// writeChunk verifies the chunk and writes it at its offset, the chunk is recorded as received once it is synced
+func writeChunk(reader io.Reader, status *chunkedUploadStatus, index int64, checksum []byte) error {
+	length := status.chunkLength(index)
+	data, err := ioutil.ReadAll(io.LimitReader(reader, length+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != length {
+		return errors.Errorf("expected %d bytes, got %d", length, len(data))
+	}
+	if sum := sha256.Sum256(data); !bytes.Equal(sum[:], checksum) {
+		return errors.New("checksum mismatch")
+	}
+
+	file, err := os.OpenFile(filepath.Join(resumableUploadDir, chunkedDataFile), os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteAt(data, index*status.ChunkSize)
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(resumableUploadDir, chunkedChunksDir, strconv.FormatInt(index, 10)), nil, 0600)
+}
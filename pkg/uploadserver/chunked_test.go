@@ -0,0 +1,151 @@
+package uploadserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Chunked upload server", func() {
+	var (
+		tmpDir    string
+		origDir   = resumableUploadDir
+		origFunc  = uploadProcessorFunc
+		processed chan string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "chunked-upload")
+		Expect(err).ToNot(HaveOccurred())
+		resumableUploadDir = tmpDir
+		processed = make(chan string, 1)
+		uploadProcessorFunc = func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (common.PreallocationStatus, error) {
+			data, err := ioutil.ReadAll(stream)
+			Expect(err).ToNot(HaveOccurred())
+			processed <- string(data)
+			return common.PreallocationNotApplied, nil
+		}
+	})
+
+	AfterEach(func() {
+		resumableUploadDir = origDir
+		uploadProcessorFunc = origFunc
+		os.RemoveAll(tmpDir)
+	})
+
+	request := func(server *uploadServerApp, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	createUpload := func(server *uploadServerApp, length, chunkSize string) {
+		rr := request(server, http.MethodPost, common.UploadPathChunked, "", map[string]string{
+			common.UploadLengthHeader:    length,
+			common.UploadChunkSizeHeader: chunkSize,
+		})
+		Expect(rr.Code).To(Equal(http.StatusCreated))
+	}
+
+	putChunk := func(server *uploadServerApp, index int, data string) *httptest.ResponseRecorder {
+		sum := sha256.Sum256([]byte(data))
+		return request(server, http.MethodPut, common.UploadPathChunked+"/"+strconv.Itoa(index), data, map[string]string{
+			common.UploadChunkChecksumHeader: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	complete := func(server *uploadServerApp) *httptest.ResponseRecorder {
+		return request(server, http.MethodPost, common.UploadPathChunked+"/"+common.ChunkedUploadComplete, "", nil)
+	}
+
+	It("Should assemble chunks uploaded in parallel and in any order", func() {
+		server := newServer()
+		createUpload(server, "12", "4")
+		chunks := []string{"disk", " con", "tent"}
+		wg := sync.WaitGroup{}
+		for i := len(chunks) - 1; i >= 0; i-- {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(putChunk(server, i, chunks[i]).Code).To(Equal(http.StatusNoContent))
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(complete(server).Code).To(Equal(http.StatusNoContent))
+		Eventually(processed).Should(Receive(Equal("disk content")))
+		Eventually(server.doneChan, 5*time.Second).Should(BeClosed())
+	})
+
+	It("Should report the received chunks and refuse to complete with missing chunks", func() {
+		server := newServer()
+		createUpload(server, "10", "4")
+		Expect(putChunk(server, 2, "nt").Code).To(Equal(http.StatusNoContent))
+
+		By("Reading the status from a new server, as if the pod restarted")
+		server = newServer()
+		rr := request(server, http.MethodGet, common.UploadPathChunked, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		status := &chunkedUploadStatus{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+		Expect(status.Chunks).To(Equal(int64(3)))
+		Expect(status.Received).To(Equal([]int64{2}))
+
+		rr = complete(server)
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Body.String()).To(ContainSubstring("2 chunks are missing"))
+	})
+
+	It("Should reject chunks with a bad checksum so they can be sent again", func() {
+		server := newServer()
+		createUpload(server, "8", "4")
+		sum := sha256.Sum256([]byte("disk"))
+		rr := request(server, http.MethodPut, common.UploadPathChunked+"/0", "dusk", map[string]string{
+			common.UploadChunkChecksumHeader: hex.EncodeToString(sum[:]),
+		})
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(ContainSubstring("checksum mismatch"))
+		Expect(putChunk(server, 0, "disk").Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("Should reject chunks of the wrong size", func() {
+		server := newServer()
+		createUpload(server, "8", "4")
+		Expect(putChunk(server, 1, "disk content").Code).To(Equal(http.StatusBadRequest))
+		Expect(putChunk(server, 1, "di").Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should reject chunk indexes out of range", func() {
+		server := newServer()
+		createUpload(server, "8", "4")
+		Expect(putChunk(server, 2, "disk").Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should reject chunks larger than the maximum chunk size", func() {
+		rr := request(newServer(), http.MethodPost, common.UploadPathChunked, "", map[string]string{
+			common.UploadLengthHeader:    strconv.Itoa(2 * maxChunkSize),
+			common.UploadChunkSizeHeader: strconv.Itoa(maxChunkSize + 1),
+		})
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+})
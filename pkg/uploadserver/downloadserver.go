@@ -0,0 +1,125 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadserver
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
+)
+
+const (
+	downloadFormatRaw   = "raw"
+	downloadFormatQcow2 = "qcow2"
+)
+
+// may be overridden in tests
+var convertToQcow2Func = image.ConvertToQcow2
+
+// NewDownloadServer returns a new instance of uploadServerApp serving the disk image of the source with GET requests
+// of the download path. The server runs until it is stopped, any number of downloads can be served.
+func NewDownloadServer(bindAddress string, bindPort int, source, tlsKey, tlsCert, clientCert, clientName string, tlsOptions *tlsconfig.Options) UploadServer {
+	server := &uploadServerApp{
+		bindAddress: bindAddress,
+		bindPort:    bindPort,
+		source:      source,
+		tlsKey:      tlsKey,
+		tlsCert:     tlsCert,
+		clientCert:  clientCert,
+		clientName:  clientName,
+		tlsOptions:  tlsOptions,
+		mux:         http.NewServeMux(),
+		doneChan:    make(chan struct{}),
+		errChan:     make(chan error),
+	}
+	server.mux.HandleFunc(common.DownloadPath, server.downloadHandler)
+	return server
+}
+
+func (app *uploadServerApp) downloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !app.clientAuthorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = downloadFormatRaw
+	}
+	if format != downloadFormatRaw && format != downloadFormatQcow2 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Unsupported format %q, expected raw or qcow2", format)))
+		return
+	}
+
+	path, err := app.getDownloadImage(format)
+	if err != nil {
+		klog.Errorf("Preparing the disk image failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("Preparing the disk image failed: %s", err.Error())))
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		klog.Errorf("Opening the disk image failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	klog.Infof("Serving %s as %s", app.source, format)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"disk.%s\"", format))
+	// Range requests let clients resume interrupted downloads
+	http.ServeContent(w, r, "", time.Time{}, file)
+}
+
+// getDownloadImage returns the path of the disk image in the format, qcow2 images are converted in the scratch space
+// by the first download and reused by the next ones.
+func (app *uploadServerApp) getDownloadImage(format string) (string, error) {
+	if format == downloadFormatRaw {
+		return app.source, nil
+	}
+
+	app.downloadMutex.Lock()
+	defer app.downloadMutex.Unlock()
+	if app.qcow2Image == "" {
+		dest := filepath.Join(common.ScratchDataDir, "disk.qcow2")
+		klog.Infof("Converting %s to qcow2", app.source)
+		if err := convertToQcow2Func(app.source, dest, ""); err != nil {
+			return "", errors.Wrap(err, "could not convert the disk image to qcow2")
+		}
+		app.qcow2Image = dest
+	}
+	return app.qcow2Image, nil
+}
@@ -0,0 +1,91 @@
+package uploadserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Download server", func() {
+	var (
+		tmpDir         string
+		source         string
+		origConversion = convertToQcow2Func
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "download-server")
+		Expect(err).ToNot(HaveOccurred())
+		source = filepath.Join(tmpDir, "disk.img")
+		Expect(ioutil.WriteFile(source, []byte("raw disk content"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		convertToQcow2Func = origConversion
+		os.RemoveAll(tmpDir)
+	})
+
+	newDownloadServer := func() *uploadServerApp {
+		return NewDownloadServer("127.0.0.1", 0, source, "", "", "", "", nil).(*uploadServerApp)
+	}
+
+	It("Should serve the raw disk image by default", func() {
+		req := httptest.NewRequest(http.MethodGet, common.DownloadPath, nil)
+		rr := httptest.NewRecorder()
+		newDownloadServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(Equal("raw disk content"))
+		Expect(rr.Header().Get("Content-Disposition")).To(ContainSubstring("disk.raw"))
+	})
+
+	It("Should serve ranges of the disk image to resume downloads", func() {
+		req := httptest.NewRequest(http.MethodGet, common.DownloadPath, nil)
+		req.Header.Set("Range", "bytes=4-")
+		rr := httptest.NewRecorder()
+		newDownloadServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusPartialContent))
+		Expect(rr.Body.String()).To(Equal("disk content"))
+	})
+
+	It("Should convert the disk image to qcow2 once", func() {
+		conversions := 0
+		converted := filepath.Join(tmpDir, "disk.qcow2")
+		convertToQcow2Func = func(src, dest, compression string) error {
+			conversions++
+			Expect(src).To(Equal(source))
+			Expect(dest).To(Equal(filepath.Join(common.ScratchDataDir, "disk.qcow2")))
+			return ioutil.WriteFile(converted, []byte("qcow2 content"), 0644)
+		}
+		server := newDownloadServer()
+		for i := 0; i < 2; i++ {
+			path, err := server.getDownloadImage(downloadFormatQcow2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join(common.ScratchDataDir, "disk.qcow2")))
+		}
+		Expect(conversions).To(Equal(1))
+	})
+
+	It("Should reject unknown formats", func() {
+		req := httptest.NewRequest(http.MethodGet, common.DownloadPath+"?format=vmdk", nil)
+		rr := httptest.NewRecorder()
+		newDownloadServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should reject uploads", func() {
+		req := httptest.NewRequest(http.MethodPost, common.DownloadPath, nil)
+		rr := httptest.NewRecorder()
+		newDownloadServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+})
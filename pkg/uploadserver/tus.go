@@ -0,0 +1,210 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package uploadserver
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	tusDataFile   = "tus-upload.img"
+	tusLengthFile = "tus-upload.length"
+)
+
+// tusUploadURL is the url of the single upload resource of the upload server, returned by creation requests
+var tusUploadURL = common.UploadPathTus + "/" + common.TusUploadID
+
+// tusHandler implements the core protocol and the creation extension of tus resumable uploads. The received data is
+// appended to a file of the scratch space, so uploads resume from the last offset even if the server restarted. Once
+// the whole image is received it is processed in the background like async uploads.
+func (app *uploadServerApp) tusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.TusResumableHeader, common.TusVersion)
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Tus-Version", common.TusVersion)
+		w.Header().Set("Tus-Extension", "creation")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Header.Get(common.TusResumableHeader) != common.TusVersion {
+		w.Header().Set("Tus-Version", common.TusVersion)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if !app.clientAuthorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && strings.TrimSuffix(r.URL.Path, "/") == common.UploadPathTus:
+		app.tusCreate(w, r)
+	case r.Method == http.MethodHead && r.URL.Path == tusUploadURL:
+		app.tusOffset(w)
+	case r.Method == http.MethodPatch && r.URL.Path == tusUploadURL:
+		app.tusPatch(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (app *uploadServerApp) tusCreate(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get(common.TusUploadLengthHeader), 10, 64)
+	if err != nil || length <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid Upload-Length header"))
+		return
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if !app.acceptsUploads(w) {
+		return
+	}
+
+	// Creating an upload discards the data of any previous one
+	if err := ioutil.WriteFile(filepath.Join(resumableUploadDir, tusDataFile), nil, 0600); err != nil {
+		klog.Errorf("Creating the upload failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(resumableUploadDir, tusLengthFile), []byte(strconv.FormatInt(length, 10)), 0600); err != nil {
+		klog.Errorf("Creating the upload failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	klog.Infof("Created resumable upload of %d bytes", length)
+	w.Header().Set("Location", tusUploadURL)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (app *uploadServerApp) tusOffset(w http.ResponseWriter) {
+	offset, length, err := tusUploadState()
+	if err != nil {
+		klog.Errorf("Reading the upload state failed: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(common.TusUploadOffsetHeader, strconv.FormatInt(offset, 10))
+	w.Header().Set(common.TusUploadLengthHeader, strconv.FormatInt(length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (app *uploadServerApp) tusPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	requestOffset, err := strconv.ParseInt(r.Header.Get(common.TusUploadOffsetHeader), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid Upload-Offset header"))
+		return
+	}
+
+	app.mutex.Lock()
+	if !app.acceptsUploads(w) {
+		app.mutex.Unlock()
+		return
+	}
+	offset, length, err := tusUploadState()
+	if err != nil {
+		app.mutex.Unlock()
+		klog.Errorf("Reading the upload state failed: %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if requestOffset != offset {
+		app.mutex.Unlock()
+		w.Header().Set(common.TusUploadOffsetHeader, strconv.FormatInt(offset, 10))
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	app.uploading = true
+	app.mutex.Unlock()
+
+	written, err := appendTusData(r.Body, length-offset)
+	offset += written
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.uploading = false
+	w.Header().Set(common.TusUploadOffsetHeader, strconv.FormatInt(offset, 10))
+	if err != nil {
+		// The received data is kept, the client resumes from the new offset
+		klog.Warningf("Upload interrupted at offset %d: %v", offset, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if offset == length {
+		if err := app.processReceivedImage(filepath.Join(resumableUploadDir, tusDataFile)); err != nil {
+			klog.Errorf("Processing the upload failed: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func tusUploadState() (int64, int64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(resumableUploadDir, tusLengthFile))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "no upload created")
+	}
+	length, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid upload length")
+	}
+	info, err := os.Stat(filepath.Join(resumableUploadDir, tusDataFile))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "no upload created")
+	}
+	return info.Size(), length, nil
+}
+
+func appendTusData(reader io.Reader, remaining int64) (int64, error) {
+	file, err := os.OpenFile(filepath.Join(resumableUploadDir, tusDataFile), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(file, io.LimitReader(reader, remaining))
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, fmt.Errorf("could not store the upload data: %v", err)
+	}
+	return written, nil
+}
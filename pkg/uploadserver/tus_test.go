@@ -0,0 +1,136 @@
+package uploadserver
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Tus upload server", func() {
+	var (
+		tmpDir    string
+		origDir   = resumableUploadDir
+		origFunc  = uploadProcessorFunc
+		processed chan string
+		uploadURL = common.UploadPathTus + "/" + common.TusUploadID
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "tus-upload")
+		Expect(err).ToNot(HaveOccurred())
+		resumableUploadDir = tmpDir
+		processed = make(chan string, 1)
+		uploadProcessorFunc = func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (common.PreallocationStatus, error) {
+			data, err := ioutil.ReadAll(stream)
+			Expect(err).ToNot(HaveOccurred())
+			processed <- string(data)
+			return common.PreallocationNotApplied, nil
+		}
+	})
+
+	AfterEach(func() {
+		resumableUploadDir = origDir
+		uploadProcessorFunc = origFunc
+		os.RemoveAll(tmpDir)
+	})
+
+	tusRequest := func(server *uploadServerApp, method, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(common.TusResumableHeader, common.TusVersion)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	createUpload := func(server *uploadServerApp, length string) {
+		rr := tusRequest(server, http.MethodPost, common.UploadPathTus, "", map[string]string{common.TusUploadLengthHeader: length})
+		Expect(rr.Code).To(Equal(http.StatusCreated))
+		Expect(rr.Header().Get("Location")).To(Equal(uploadURL))
+	}
+
+	patch := func(server *uploadServerApp, offset, data string) *httptest.ResponseRecorder {
+		return tusRequest(server, http.MethodPatch, uploadURL, data, map[string]string{
+			"Content-Type":               "application/offset+octet-stream",
+			common.TusUploadOffsetHeader: offset,
+		})
+	}
+
+	It("Should advertise the protocol with OPTIONS", func() {
+		req := httptest.NewRequest(http.MethodOptions, common.UploadPathTus, nil)
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(rr.Header().Get("Tus-Version")).To(Equal(common.TusVersion))
+		Expect(rr.Header().Get("Tus-Extension")).To(Equal("creation"))
+	})
+
+	It("Should reject requests of other protocol versions", func() {
+		req := httptest.NewRequest(http.MethodPost, common.UploadPathTus, nil)
+		req.Header.Set(common.TusResumableHeader, "0.2.2")
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusPreconditionFailed))
+	})
+
+	It("Should resume the upload from the stored offset and process the whole image", func() {
+		server := newServer()
+		createUpload(server, "12")
+		rr := patch(server, "0", "disk")
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(rr.Header().Get(common.TusUploadOffsetHeader)).To(Equal("4"))
+
+		By("Resuming with a new server, as if the pod restarted")
+		server = newServer()
+		rr = tusRequest(server, http.MethodHead, uploadURL, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Header().Get(common.TusUploadOffsetHeader)).To(Equal("4"))
+		Expect(rr.Header().Get(common.TusUploadLengthHeader)).To(Equal("12"))
+		status := server.uploadStatus()
+		Expect(status.Phase).To(Equal(uploadv1.UploadPhaseUploading))
+		Expect(status.BytesReceived).To(Equal(int64(4)))
+		Expect(status.TotalBytes).To(Equal(int64(12)))
+
+		rr = patch(server, "4", " content")
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(rr.Header().Get(common.TusUploadOffsetHeader)).To(Equal("12"))
+		Eventually(processed).Should(Receive(Equal("disk content")))
+		Eventually(server.doneChan, 5*time.Second).Should(BeClosed())
+
+		rr = patch(server, "12", "more")
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+	})
+
+	It("Should reject patches at the wrong offset", func() {
+		server := newServer()
+		createUpload(server, "12")
+		rr := patch(server, "4", "content")
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Header().Get(common.TusUploadOffsetHeader)).To(Equal("0"))
+	})
+
+	It("Should reject patches that are not offset octet streams", func() {
+		server := newServer()
+		createUpload(server, "12")
+		rr := tusRequest(server, http.MethodPatch, uploadURL, "disk", map[string]string{common.TusUploadOffsetHeader: "0"})
+		Expect(rr.Code).To(Equal(http.StatusUnsupportedMediaType))
+	})
+
+	It("Should not find uploads that were not created", func() {
+		rr := tusRequest(newServer(), http.MethodHead, uploadURL, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+})
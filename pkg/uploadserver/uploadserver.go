@@ -22,9 +22,13 @@ package uploadserver
 import (
 	"archive/tar"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
@@ -34,14 +38,19 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/containers/image/v5/pkg/compression"
 	"github.com/golang/snappy"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 const (
@@ -68,14 +77,24 @@ type uploadServerApp struct {
 	imageSize            string
 	filesystemOverhead   float64
 	preallocation        bool
+	tlsOptions           *tlsconfig.Options
 	mux                  *http.ServeMux
 	uploading            bool
 	processing           bool
 	done                 bool
 	preallocationApplied common.PreallocationStatus
-	doneChan             chan struct{}
-	errChan              chan error
-	mutex                sync.Mutex
+	// chunkUploads is the number of chunks of chunked uploads being written
+	chunkUploads int
+	// bytesReceived and totalBytes are the progress of uploads streamed in a single request
+	bytesReceived int64
+	totalBytes    int64
+	doneChan      chan struct{}
+	errChan       chan error
+	mutex         sync.Mutex
+	// source is the disk image served by download servers
+	source        string
+	qcow2Image    string
+	downloadMutex sync.Mutex
 }
 
 type imageReadCloser func(*http.Request) (io.ReadCloser, error)
@@ -84,6 +103,9 @@ type imageReadCloser func(*http.Request) (io.ReadCloser, error)
 var uploadProcessorFunc = newUploadStreamProcessor
 var uploadProcessorFuncAsync = newAsyncUploadStreamProcessor
 
+// resumableUploadDir stores the data received by resumable uploads until the whole image is received
+var resumableUploadDir = common.ScratchDataDir
+
 func bodyReadCloser(r *http.Request) (io.ReadCloser, error) {
 	return r.Body, nil
 }
@@ -113,7 +135,7 @@ func formReadCloser(r *http.Request) (io.ReadCloser, error) {
 }
 
 // NewUploadServer returns a new instance of uploadServerApp
-func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool) UploadServer {
+func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool, tlsOptions *tlsconfig.Options) UploadServer {
 	server := &uploadServerApp{
 		bindAddress:        bindAddress,
 		bindPort:           bindPort,
@@ -124,6 +146,7 @@ func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsC
 		clientName:         clientName,
 		filesystemOverhead: filesystemOverhead,
 		preallocation:      preallocation,
+		tlsOptions:         tlsOptions,
 		imageSize:          imageSize,
 		mux:                http.NewServeMux(),
 		uploading:          false,
@@ -144,6 +167,13 @@ func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsC
 	for _, path := range common.AsyncUploadFormPaths {
 		server.mux.HandleFunc(path, server.uploadHandlerAsync(formReadCloser))
 	}
+	for _, path := range common.TusUploadPaths {
+		server.mux.HandleFunc(path, server.tusHandler)
+	}
+	for _, path := range common.ChunkedUploadPaths {
+		server.mux.HandleFunc(path, server.chunkedHandler)
+	}
+	server.mux.HandleFunc(common.UploadStatusPath, server.statusHandler)
 
 	return server
 }
@@ -239,12 +269,23 @@ func (app *uploadServerApp) createUploadServer() (*http.Server, error) {
 		}
 	}
 
+	if app.tlsOptions != nil {
+		if server.TLSConfig == nil {
+			server.TLSConfig = &tls.Config{}
+		}
+		app.tlsOptions.Apply(server.TLSConfig)
+	}
+
 	return server, nil
 }
 
 func (app *uploadServerApp) createHealthzServer() (*http.Server, error) {
 	mux := http.NewServeMux()
 	mux.HandleFunc(healthzPath, app.healthzHandler)
+	if app.destination != "" {
+		// The upload controller polls the status of upload servers on the healthz port
+		mux.HandleFunc(common.UploadStatusPath, app.statusHandler)
+	}
 	return &http.Server{Handler: mux}, nil
 }
 
@@ -256,28 +297,96 @@ func (app *uploadServerApp) healthzHandler(w http.ResponseWriter, r *http.Reques
 	io.WriteString(w, "OK")
 }
 
-func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r *http.Request) bool {
-	if r.Method != "POST" {
+func (app *uploadServerApp) statusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusNotFound)
-		return false
+		return
 	}
+	if !app.clientAuthorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(app.uploadStatus())
+}
 
-	if r.TLS != nil {
-		found := false
-
-		for _, cert := range r.TLS.PeerCertificates {
-			if cert.Subject.CommonName == app.clientName {
-				found = true
-				break
-			}
+// uploadStatus returns the progress of the upload, resumable uploads keep it in the scratch space
+func (app *uploadServerApp) uploadStatus() *uploadv1.UploadStatus {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	status := &uploadv1.UploadStatus{
+		Phase:         uploadv1.UploadPhasePending,
+		BytesReceived: atomic.LoadInt64(&app.bytesReceived),
+		TotalBytes:    app.totalBytes,
+	}
+	if status.BytesReceived == 0 {
+		if offset, length, err := tusUploadState(); err == nil {
+			status.BytesReceived, status.TotalBytes = offset, length
+		} else if chunked, err := chunkedUploadState(); err == nil {
+			status.BytesReceived, status.TotalBytes = chunked.receivedBytes(), chunked.Length
 		}
+	}
 
-		if !found {
-			w.WriteHeader(http.StatusUnauthorized)
-			return false
-		}
-	} else {
+	received := status.TotalBytes > 0 && status.BytesReceived >= status.TotalBytes
+	switch {
+	case app.done:
+		status.Phase = uploadv1.UploadPhaseSucceeded
+		status.Progress = "100.00%"
+	case app.processing || (app.uploading && received):
+		status.Phase = uploadv1.UploadPhaseProcessing
+		status.Progress = fmt.Sprintf("%.2f%%", image.ConversionProgress())
+	case app.uploading || app.chunkUploads > 0 || status.BytesReceived > 0:
+		status.Phase = uploadv1.UploadPhaseUploading
+	}
+	return status
+}
+
+// trackProgress counts the bytes of the image read from the stream of the request
+func (app *uploadServerApp) trackProgress(stream io.ReadCloser, r *http.Request) io.ReadCloser {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	atomic.StoreInt64(&app.bytesReceived, 0)
+	app.totalBytes = 0
+	if r.ContentLength > 0 {
+		app.totalBytes = r.ContentLength
+	}
+	return &countingReadCloser{ReadCloser: stream, count: &app.bytesReceived}
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	count *int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+// clientAuthorized checks the request comes from the expected client, the upload proxy or the clone source
+func (app *uploadServerApp) clientAuthorized(r *http.Request) bool {
+	if r.TLS == nil {
 		klog.V(3).Infof("Handling HTTP connection")
+		return true
+	}
+	for _, cert := range r.TLS.PeerCertificates {
+		if cert.Subject.CommonName == app.clientName {
+			return true
+		}
+	}
+	return false
+}
+
+func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	}
+
+	if !app.clientAuthorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
 	}
 
 	app.mutex.Lock()
@@ -319,6 +428,16 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 		}
+		readCloser = app.trackProgress(readCloser, r)
+		readCloser, err = newCloneStreamReader(readCloser, cdiContentType, r.Header.Get(common.UploadContentEncodingHeader))
+		if err != nil {
+			klog.Errorf("Decoding stream failed: %s", err)
+			w.WriteHeader(http.StatusBadRequest)
+			app.mutex.Lock()
+			app.uploading = false
+			app.mutex.Unlock()
+			return
+		}
 
 		processor, err := uploadProcessorFuncAsync(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType)
 
@@ -374,8 +493,27 @@ func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 		}
+		readCloser = app.trackProgress(readCloser, r)
+		readCloser, err = newCloneStreamReader(readCloser, cdiContentType, r.Header.Get(common.UploadContentEncodingHeader))
+		if err != nil {
+			klog.Errorf("Decoding stream failed: %s", err)
+			w.WriteHeader(http.StatusBadRequest)
+			app.mutex.Lock()
+			app.uploading = false
+			app.mutex.Unlock()
+			return
+		}
+		var checksum *checksumReader
+		if isCloneContentType(cdiContentType) {
+			checksum = newChecksumReader(readCloser)
+			defer checksum.ReadCloser.Close()
+			readCloser = checksum
+		}
 
 		app.preallocationApplied, err = uploadProcessorFunc(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType)
+		if err == nil && checksum != nil {
+			err = checksum.verify(r)
+		}
 
 		app.mutex.Lock()
 		defer app.mutex.Unlock()
@@ -383,6 +521,7 @@ func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc
 		if err != nil {
 			klog.Errorf("Saving stream failed: %s", err)
 			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("Saving stream failed: %s", err.Error())))
 			app.uploading = false
 			return
 		}
@@ -396,6 +535,49 @@ func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc
 	}
 }
 
+// acceptsUploads checks no other upload is in progress for resumable uploads, it must be called holding the mutex
+func (app *uploadServerApp) acceptsUploads(w http.ResponseWriter) bool {
+	if app.uploading || app.processing {
+		klog.Warning("Got concurrent upload request")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+	if app.done {
+		klog.Warning("Got upload request after already done")
+		w.WriteHeader(http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// processReceivedImage starts processing the image received by resumable uploads in the background, it must be
+// called holding the mutex
+func (app *uploadServerApp) processReceivedImage(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	app.processing = true
+	go func() {
+		// The processor cleans the scratch space, the open file stays readable until it is closed
+		preallocationApplied, err := uploadProcessorFunc(file, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, "")
+		file.Close()
+		if err != nil {
+			klog.Errorf("Saving stream failed: %s", err)
+			app.errChan <- err
+			return
+		}
+		app.mutex.Lock()
+		defer app.mutex.Unlock()
+		app.processing = false
+		app.done = true
+		app.preallocationApplied = preallocationApplied
+		close(app.doneChan)
+		klog.Infof("Wrote data to %s", app.destination)
+	}()
+	return nil
+}
+
 func (app *uploadServerApp) PreallocationApplied() common.PreallocationStatus {
 	return app.preallocationApplied
 }
@@ -405,7 +587,7 @@ func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string,
 		return nil, fmt.Errorf("async filesystem clone not supported")
 	}
 
-	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType))
+	uds := importer.NewAsyncUploadDataSource(stream)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
 	return processor, processor.ProcessDataWithPause()
 }
@@ -416,7 +598,7 @@ func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, file
 	}
 
 	// Clone block device to block device or file system
-	uds := importer.NewUploadDataSource(newContentReader(stream, sourceContentType))
+	uds := importer.NewUploadDataSource(stream)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
 	err := processor.ProcessData()
 	return processor.PreallocationApplied(), err
@@ -426,7 +608,7 @@ func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, file
 func filesystemCloneProcessor(stream io.ReadCloser, dest string) error {
 	// Clone to block device
 	if dest == common.WriteBlockPath {
-		if err := untarToBlockdev(newSnappyReadCloser(stream), dest); err != nil {
+		if err := untarToBlockdev(stream, dest); err != nil {
 			return errors.Wrapf(err, "error unarchiving to %s", dest)
 		}
 		return nil
@@ -437,25 +619,28 @@ func filesystemCloneProcessor(stream io.ReadCloser, dest string) error {
 	if err := importer.CleanDir(destDir); err != nil {
 		return errors.Wrapf(err, "error removing contents of %s", destDir)
 	}
-	if err := util.UnArchiveTar(newSnappyReadCloser(stream), destDir); err != nil {
+	if err := util.UnArchiveTar(stream, destDir); err != nil {
 		return errors.Wrapf(err, "error unarchiving to %s", destDir)
 	}
 	return nil
 }
 
+// untarToBlockdev writes the disk image of the archived file system to the block device, the image is archived sparse
+// unless it has no holes
 func untarToBlockdev(stream io.Reader, dest string) error {
 	tr := tar.NewReader(stream)
 	for {
 		header, err := tr.Next()
 		switch {
 		case err == io.EOF:
-			return nil
+			return errors.Errorf("no %s in the source file system", common.DiskImageName)
 		case err != nil:
 			return err
 		case header == nil:
 			continue
 		}
-		if header.Typeflag == tar.TypeGNUSparse && strings.Contains(header.Name, common.DiskImageName) {
+		isFile := header.Typeflag == tar.TypeGNUSparse || header.Typeflag == tar.TypeReg
+		if isFile && strings.Contains(header.Name, common.DiskImageName) {
 			klog.Infof("Untaring %d bytes to %s", header.Size, dest)
 			f, err := os.OpenFile(dest, os.O_APPEND|os.O_WRONLY, os.ModeDevice|os.ModePerm)
 			if err != nil {
@@ -472,12 +657,66 @@ func untarToBlockdev(stream io.Reader, dest string) error {
 	}
 }
 
-func newContentReader(stream io.ReadCloser, contentType string) io.ReadCloser {
-	if contentType == common.BlockdeviceClone {
-		return newSnappyReadCloser(stream)
+// newCloneStreamReader decodes the stream of a clone source, compressed with snappy unless the source set another
+// content encoding. Other streams are returned as is.
+func newCloneStreamReader(stream io.ReadCloser, contentType, contentEncoding string) (io.ReadCloser, error) {
+	if !isCloneContentType(contentType) {
+		return stream, nil
+	}
+	switch contentEncoding {
+	case "", common.CloneCompressionSnappy:
+		return newSnappyReadCloser(stream), nil
+	case common.CloneCompressionZstd:
+		return compression.ZstdDecompressor(stream)
 	}
+	return nil, errors.Errorf("unsupported content encoding %q", contentEncoding)
+}
+
+func isCloneContentType(contentType string) bool {
+	return contentType == common.BlockdeviceClone || contentType == common.FilesystemCloneContentType
+}
+
+// checksumReader computes the sha256 checksum of the decoded clone stream. The processors may close the stream before
+// reading it to the end, so closing it is left to the handler.
+type checksumReader struct {
+	io.ReadCloser
+	hash hash.Hash
+}
 
-	return stream
+func newChecksumReader(stream io.ReadCloser) *checksumReader {
+	return &checksumReader{ReadCloser: stream, hash: sha256.New()}
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+func (r *checksumReader) Close() error {
+	return nil
+}
+
+// verify reads the rest of the stream, like the end of the archives, and compares its checksum to the one the clone
+// source sent in the trailer of the request. The trailer is only read once the body is read to the end.
+func (r *checksumReader) verify(req *http.Request) error {
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+	if _, err := io.Copy(ioutil.Discard, req.Body); err != nil {
+		return err
+	}
+	expected := req.Trailer.Get(common.UploadContentSHA256Trailer)
+	if expected == "" {
+		klog.Warning("The clone source sent no checksum, skipping the verification")
+		return nil
+	}
+	actual := hex.EncodeToString(r.hash.Sum(nil))
+	if actual != expected {
+		return errors.Errorf("%s, source sha256 %s, target sha256 %s", common.CloneChecksumMismatchMessage, expected, actual)
+	}
+	klog.Infof("Verified the clone stream checksum %s", actual)
+	return nil
 }
 
 func newSnappyReadCloser(stream io.ReadCloser) io.ReadCloser {
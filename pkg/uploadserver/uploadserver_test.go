@@ -20,30 +20,40 @@
 package uploadserver
 
 import (
+	"archive/tar"
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/containers/image/v5/pkg/compression"
+	"github.com/golang/snappy"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	uploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/tlsconfig"
 )
 
 func newServer() *uploadServerApp {
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false)
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, nil)
 	return server.(*uploadServerApp)
 }
 
@@ -61,7 +71,7 @@ func newTLSServer(clientCertName, expectedName string) (*uploadServerApp, *tripl
 	tlsCert := string(cert.EncodeCertPEM(serverKeyPair.Cert))
 	clientCert := string(cert.EncodeCertPEM(clientCA.Cert))
 
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false).(*uploadServerApp)
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false, nil).(*uploadServerApp)
 
 	clientKeyPair, err := triple.NewClientKeyPair(clientCA, clientCertName, []string{})
 	Expect(err).ToNot(HaveOccurred())
@@ -371,6 +381,19 @@ var _ = Describe("Upload server tests", func() {
 		table.Entry("Valid data", "client", "client", 200),
 		table.Entry("Invalid data", "foo", "bar", 401),
 	)
+
+	It("should apply the TLS options to the server", func() {
+		app, _, _ := newTLSServer("client", "client")
+		app.tlsOptions = &tlsconfig.Options{
+			MinVersion:   tls.VersionTLS12,
+			CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		}
+		server, err := app.createUploadServer()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.TLSConfig.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+		Expect(server.TLSConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(server.TLSConfig.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}))
+	})
 })
 
 func newFormRequest(path string) *http.Request {
@@ -393,3 +416,178 @@ func newFormRequest(path string) *http.Request {
 
 	return req
 }
+
+var _ = Describe("Upload status", func() {
+	getStatus := func(server *uploadServerApp) *uploadv1.UploadStatus {
+		req := httptest.NewRequest(http.MethodGet, common.UploadStatusPath, nil)
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		status := &uploadv1.UploadStatus{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+		return status
+	}
+
+	It("Should be pending before the upload starts", func() {
+		status := getStatus(newServer())
+		Expect(status.Phase).To(Equal(uploadv1.UploadPhasePending))
+		Expect(status.BytesReceived).To(BeZero())
+	})
+
+	It("Should report the bytes received and the processing of the image", func() {
+		received := make(chan struct{})
+		release := make(chan struct{})
+		processor := func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (common.PreallocationStatus, error) {
+			_, err := ioutil.ReadAll(stream)
+			Expect(err).ToNot(HaveOccurred())
+			close(received)
+			<-release
+			return common.PreallocationNotApplied, nil
+		}
+		replaceProcessorFunc(processor, func() {
+			server := newServer()
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				req := httptest.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("disk content"))
+				rr := httptest.NewRecorder()
+				server.ServeHTTP(rr, req)
+				Expect(rr.Code).To(Equal(http.StatusOK))
+			}()
+			<-received
+			status := getStatus(server)
+			Expect(status.Phase).To(Equal(uploadv1.UploadPhaseProcessing))
+			Expect(status.BytesReceived).To(Equal(int64(12)))
+			Expect(status.TotalBytes).To(Equal(int64(12)))
+			close(release)
+			<-done
+			status = getStatus(server)
+			Expect(status.Phase).To(Equal(uploadv1.UploadPhaseSucceeded))
+			Expect(status.Progress).To(Equal("100.00%"))
+		})
+	})
+})
+
+var _ = Describe("Filesystem clone to block device", func() {
+	newArchive := func(files map[string]string) io.Reader {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		return buf
+	}
+
+	var dest string
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "untar")
+		Expect(err).ToNot(HaveOccurred())
+		dest = filepath.Join(dir, "blockdev")
+		Expect(ioutil.WriteFile(dest, nil, 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(dest))
+	})
+
+	It("Should write a disk image archived without holes", func() {
+		err := untarToBlockdev(newArchive(map[string]string{"./disk.img": "disk content"}), dest)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("disk content"))
+	})
+
+	It("Should fail without disk image", func() {
+		err := untarToBlockdev(newArchive(map[string]string{"./other.img": "other content"}), dest)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Clone stream decoding", func() {
+	compress := func(encoding, content string) io.ReadCloser {
+		buf := &bytes.Buffer{}
+		var w io.WriteCloser
+		switch encoding {
+		case common.CloneCompressionZstd:
+			var err error
+			w, err = compression.CompressStream(buf, compression.Zstd, nil)
+			Expect(err).ToNot(HaveOccurred())
+		case "", common.CloneCompressionSnappy:
+			w = snappy.NewBufferedWriter(buf)
+		}
+		_, err := w.Write([]byte(content))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		return ioutil.NopCloser(buf)
+	}
+
+	table.DescribeTable("should decode the stream of", func(contentType, contentEncoding string) {
+		stream, err := newCloneStreamReader(compress(contentEncoding, "disk content"), contentType, contentEncoding)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadAll(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("disk content"))
+	},
+		table.Entry("a block device clone with the default compression", common.BlockdeviceClone, ""),
+		table.Entry("a block device clone with snappy", common.BlockdeviceClone, common.CloneCompressionSnappy),
+		table.Entry("a block device clone with zstd", common.BlockdeviceClone, common.CloneCompressionZstd),
+		table.Entry("a filesystem clone with zstd", common.FilesystemCloneContentType, common.CloneCompressionZstd),
+	)
+
+	It("should not decode uploads", func() {
+		stream, err := newCloneStreamReader(ioutil.NopCloser(strings.NewReader("disk content")), "", "")
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadAll(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("disk content"))
+	})
+
+	It("should reject an unsupported encoding", func() {
+		_, err := newCloneStreamReader(ioutil.NopCloser(strings.NewReader("disk content")), common.BlockdeviceClone, "lz4")
+		Expect(err).To(HaveOccurred())
+	})
+
+	table.DescribeTable("should verify the checksum of a clone", func(checksum string, expectedStatus int) {
+		processor := func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (common.PreallocationStatus, error) {
+			// Only read the beginning of the stream, like the processors stopping at the end of archives
+			_, err := io.ReadFull(stream, make([]byte, 4))
+			Expect(err).ToNot(HaveOccurred())
+			return common.PreallocationNotApplied, stream.Close()
+		}
+		replaceProcessorFunc(processor, func() {
+			server := newServer()
+			req := httptest.NewRequest(http.MethodPost, common.UploadPathSync, compress("", "disk content"))
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+			if checksum != "" {
+				req.Trailer = http.Header{}
+				req.Trailer.Set(common.UploadContentSHA256Trailer, checksum)
+			}
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+			Expect(rr.Code).To(Equal(expectedStatus))
+			if expectedStatus != http.StatusOK {
+				Expect(rr.Body.String()).To(ContainSubstring(common.CloneChecksumMismatchMessage))
+			}
+		})
+	},
+		table.Entry("accepting a matching checksum", fmt.Sprintf("%x", sha256.Sum256([]byte("disk content"))), http.StatusOK),
+		table.Entry("rejecting a mismatching checksum", fmt.Sprintf("%x", sha256.Sum256([]byte("disk contents"))), http.StatusInternalServerError),
+		table.Entry("accepting a clone source not sending it", "", http.StatusOK),
+	)
+
+	It("should reject a clone with an unsupported encoding", func() {
+		server := newServer()
+		req := httptest.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("disk content"))
+		req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+		req.Header.Set(common.UploadContentEncodingHeader, "lz4")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -17,12 +17,57 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
+// throughputSmoothing is the weight of the last second in the reported throughput, smoothing out short bursts
+const throughputSmoothing = 0.3
+
 // ProgressReader is a counting reader that reports progress to prometheus.
 type ProgressReader struct {
 	util.CountingReader
 	total    uint64
 	progress *prometheus.CounterVec
 	ownerUID string
+
+	transfer    *TransferMetrics
+	started     time.Time
+	lastCurrent uint64
+	lastUpdate  time.Time
+	throughput  float64
+}
+
+// TransferMetrics are the gauges of the bytes transferred, the throughput in bytes per second, the estimated
+// remaining seconds and the duration so far of a transfer.
+type TransferMetrics struct {
+	BytesTransferred *prometheus.GaugeVec
+	Throughput       *prometheus.GaugeVec
+	RemainingSeconds *prometheus.GaugeVec
+	DurationSeconds  *prometheus.GaugeVec
+}
+
+// NewTransferMetrics creates and registers the transfer gauges, named after the prefix, labelled with the owner UID.
+func NewTransferMetrics(prefix string) *TransferMetrics {
+	return &TransferMetrics{
+		BytesTransferred: registerGaugeVec(prefix+"_bytes_transferred", "The number of bytes transferred"),
+		Throughput:       registerGaugeVec(prefix+"_throughput_bytes", "The transfer rate in bytes per second"),
+		RemainingSeconds: registerGaugeVec(prefix+"_remaining_seconds", "The estimated seconds until the transfer completes"),
+		DurationSeconds:  registerGaugeVec(prefix+"_duration_seconds", "The seconds elapsed since the transfer started"),
+	}
+}
+
+func registerGaugeVec(name, help string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		[]string{"ownerUID"},
+	)
+	if err := prometheus.Register(gauge); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.GaugeVec)
+		}
+		klog.Errorf("Unable to create prometheus gauge %s", name)
+	}
+	return gauge
 }
 
 // NewProgressReader creates a new instance of a prometheus updating progress reader.
@@ -40,6 +85,12 @@ func NewProgressReader(r io.ReadCloser, total uint64, progress *prometheus.Count
 	return promReader
 }
 
+// SetTransferMetrics makes the reader report its bytes transferred, throughput, estimated remaining time and duration
+// along with its progress.
+func (r *ProgressReader) SetTransferMetrics(transfer *TransferMetrics) {
+	r.transfer = transfer
+}
+
 // StartTimedUpdate starts the update timer to automatically update every second.
 func (r *ProgressReader) StartTimedUpdate() {
 	// Start the progress update thread.
@@ -67,11 +118,42 @@ func (r *ProgressReader) updateProgress() bool {
 			r.progress.WithLabelValues(r.ownerUID).Add(currentProgress - *metric.Counter.Value)
 		}
 		klog.V(1).Infoln(fmt.Sprintf("%.2f", currentProgress))
+		r.updateTransfer(time.Now())
 		return !r.Done
 	}
 	return false
 }
 
+func (r *ProgressReader) updateTransfer(now time.Time) {
+	if r.transfer == nil {
+		return
+	}
+	if r.started.IsZero() {
+		r.started = now
+	}
+	if !r.lastUpdate.IsZero() && now.After(r.lastUpdate) && r.Current >= r.lastCurrent {
+		rate := float64(r.Current-r.lastCurrent) / now.Sub(r.lastUpdate).Seconds()
+		if r.throughput == 0 {
+			r.throughput = rate
+		} else {
+			r.throughput = throughputSmoothing*rate + (1-throughputSmoothing)*r.throughput
+		}
+	}
+	r.lastCurrent, r.lastUpdate = r.Current, now
+
+	remaining := 0.0
+	if !r.Done && r.Current < r.total {
+		remaining = -1
+		if r.throughput > 0 {
+			remaining = float64(r.total-r.Current) / r.throughput
+		}
+	}
+	r.transfer.BytesTransferred.WithLabelValues(r.ownerUID).Set(float64(r.Current))
+	r.transfer.Throughput.WithLabelValues(r.ownerUID).Set(r.throughput)
+	r.transfer.RemainingSeconds.WithLabelValues(r.ownerUID).Set(remaining)
+	r.transfer.DurationSeconds.WithLabelValues(r.ownerUID).Set(now.Sub(r.started).Seconds())
+}
+
 // StartPrometheusEndpoint starts an http server providing a prometheus endpoint using the passed
 // in directory to store the self signed certificates that will be generated before starting the
 // http server.
@@ -3,6 +3,7 @@ package prometheus
 import (
 	"bytes"
 	"io/ioutil"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -107,4 +108,49 @@ var _ = Describe("Update Progress", func() {
 		Expect(*metric.Counter.Value).To(Equal(float64(100)))
 	})
 
+
+	It("Should report the transfer rate and the remaining time", func() {
+		transfer := &TransferMetrics{
+			BytesTransferred: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_bytes_transferred"}, []string{"ownerUID"}),
+			Throughput:       prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_throughput_bytes"}, []string{"ownerUID"}),
+			RemainingSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_remaining_seconds"}, []string{"ownerUID"}),
+			DurationSeconds:  prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_duration_seconds"}, []string{"ownerUID"}),
+		}
+		gaugeValue := func(gauge *prometheus.GaugeVec) float64 {
+			metric := &dto.Metric{}
+			gauge.WithLabelValues(ownerUID).Write(metric)
+			return *metric.Gauge.Value
+		}
+		promReader := &ProgressReader{
+			total:    uint64(1000),
+			progress: progress,
+			ownerUID: ownerUID,
+		}
+		promReader.SetTransferMetrics(transfer)
+		start := time.Now()
+
+		By("Not estimating the remaining time before knowing the rate")
+		promReader.updateTransfer(start)
+		Expect(gaugeValue(transfer.RemainingSeconds)).To(Equal(float64(-1)))
+		Expect(gaugeValue(transfer.DurationSeconds)).To(Equal(float64(0)))
+
+		By("Estimating the remaining time from the rate")
+		promReader.Current = 100
+		promReader.updateTransfer(start.Add(time.Second))
+		Expect(gaugeValue(transfer.BytesTransferred)).To(Equal(float64(100)))
+		Expect(gaugeValue(transfer.Throughput)).To(Equal(float64(100)))
+		Expect(gaugeValue(transfer.RemainingSeconds)).To(Equal(float64(9)))
+
+		By("Smoothing the rate")
+		promReader.Current = 300
+		promReader.updateTransfer(start.Add(2 * time.Second))
+		Expect(gaugeValue(transfer.Throughput)).To(BeNumerically("~", 130, 0.001))
+
+		By("Reporting no remaining time once done")
+		promReader.Current = 1000
+		promReader.Done = true
+		promReader.updateTransfer(start.Add(3 * time.Second))
+		Expect(gaugeValue(transfer.RemainingSeconds)).To(Equal(float64(0)))
+		Expect(gaugeValue(transfer.DurationSeconds)).To(Equal(float64(3)))
+	})
 })
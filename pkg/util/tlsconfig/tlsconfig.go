@@ -0,0 +1,127 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var versions = map[cdiv1.TLSProtocolVersion]uint16{
+	cdiv1.VersionTLS10: tls.VersionTLS10,
+	cdiv1.VersionTLS11: tls.VersionTLS11,
+	cdiv1.VersionTLS12: tls.VersionTLS12,
+	cdiv1.VersionTLS13: tls.VersionTLS13,
+}
+
+// Options are the TLS settings applied to the servers and clients of the CDI components
+type Options struct {
+	MinVersion   uint16
+	CipherSuites []uint16
+}
+
+// NewOptions validates the TLS config and returns the matching options. A nil config returns nil options
+func NewOptions(config *cdiv1.TLSConfig) (*Options, error) {
+	if config == nil {
+		return nil, nil
+	}
+	options := &Options{}
+	if config.MinVersion != "" {
+		version, ok := versions[config.MinVersion]
+		if !ok {
+			return nil, errors.Errorf("unsupported TLS version %q", config.MinVersion)
+		}
+		options.MinVersion = version
+	}
+	for _, name := range config.Ciphers {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return nil, errors.Errorf("unsupported cipher suite %q", name)
+		}
+		options.CipherSuites = append(options.CipherSuites, id)
+	}
+	return options, nil
+}
+
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// Apply sets the minimum version and the cipher suites on the TLS config, nil options leave the config unchanged
+func (o *Options) Apply(config *tls.Config) {
+	if o == nil {
+		return
+	}
+	if o.MinVersion != 0 {
+		config.MinVersion = o.MinVersion
+	}
+	if len(o.CipherSuites) > 0 {
+		config.CipherSuites = o.CipherSuites
+	}
+}
+
+// EnvVars returns the environment variables passing the TLS config to a pod
+func EnvVars(config *cdiv1.TLSConfig) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if config == nil {
+		return env
+	}
+	if config.MinVersion != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.TLSMinVersionVar,
+			Value: string(config.MinVersion),
+		})
+	}
+	if len(config.Ciphers) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.TLSCiphersVar,
+			Value: strings.Join(config.Ciphers, ","),
+		})
+	}
+	return env
+}
+
+// FromEnv returns the options passed to the pod in the environment variables, nil if none are set
+func FromEnv() (*Options, error) {
+	minVersion := os.Getenv(common.TLSMinVersionVar)
+	ciphers := os.Getenv(common.TLSCiphersVar)
+	if minVersion == "" && ciphers == "" {
+		return nil, nil
+	}
+	config := &cdiv1.TLSConfig{
+		MinVersion: cdiv1.TLSProtocolVersion(minVersion),
+	}
+	if ciphers != "" {
+		config.Ciphers = strings.Split(ciphers, ",")
+	}
+	return NewOptions(config)
+}
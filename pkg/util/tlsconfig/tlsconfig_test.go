@@ -0,0 +1,153 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("TLS options", func() {
+	It("should return nil options for a nil config", func() {
+		options, err := NewOptions(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(BeNil())
+	})
+
+	It("should parse the minimum version and cipher suites", func() {
+		options, err := NewOptions(&cdiv1.TLSConfig{
+			MinVersion: cdiv1.VersionTLS12,
+			Ciphers:    []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(options.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}))
+	})
+
+	table.DescribeTable("should reject", func(config *cdiv1.TLSConfig) {
+		_, err := NewOptions(config)
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("an unknown version", &cdiv1.TLSConfig{MinVersion: "VersionTLS14"}),
+		table.Entry("an unknown cipher suite", &cdiv1.TLSConfig{Ciphers: []string{"TLS_INVALID"}}),
+	)
+
+	It("should apply the options to a TLS config", func() {
+		config := &tls.Config{}
+		options := &Options{MinVersion: tls.VersionTLS13, CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}}
+		options.Apply(config)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(config.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}))
+	})
+
+	It("should leave the TLS config unchanged with nil options", func() {
+		config := &tls.Config{MinVersion: tls.VersionTLS11}
+		var options *Options
+		options.Apply(config)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS11)))
+		Expect(config.CipherSuites).To(BeNil())
+	})
+})
+
+var _ = Describe("TLS environment variables", func() {
+	AfterEach(func() {
+		os.Unsetenv(common.TLSMinVersionVar)
+		os.Unsetenv(common.TLSCiphersVar)
+	})
+
+	It("should not create environment variables for a nil config", func() {
+		Expect(EnvVars(nil)).To(BeEmpty())
+	})
+
+	It("should pass the config through environment variables", func() {
+		config := &cdiv1.TLSConfig{
+			MinVersion: cdiv1.VersionTLS12,
+			Ciphers:    []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		}
+		env := EnvVars(config)
+		Expect(env).To(Equal([]corev1.EnvVar{
+			{Name: common.TLSMinVersionVar, Value: "VersionTLS12"},
+			{Name: common.TLSCiphersVar, Value: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		}))
+		for _, e := range env {
+			os.Setenv(e.Name, e.Value)
+		}
+		options, err := FromEnv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(options.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}))
+	})
+
+	It("should return nil options if the environment variables are not set", func() {
+		options, err := FromEnv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(BeNil())
+	})
+
+	It("should fail on an invalid environment variable", func() {
+		os.Setenv(common.TLSMinVersionVar, "invalid")
+		_, err := FromEnv()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("TLS config watcher", func() {
+	It("should follow the TLS settings of the CDIConfig", func() {
+		ch := make(chan struct{})
+		defer close(ch)
+		config := &cdiv1.CDIConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.ConfigName,
+			},
+		}
+		client := cdiclientfake.NewSimpleClientset(config)
+		watcher := NewWatcher(client, ch).(*configWatcher)
+		Expect(watcher.GetOptions()).To(BeNil())
+
+		config.Spec.TLSConfig = &cdiv1.TLSConfig{MinVersion: cdiv1.VersionTLS12}
+		config, err := client.CdiV1beta1().CDIConfigs().Update(context.TODO(), config, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() *Options {
+			return watcher.GetOptions()
+		}, 10*time.Second, 100*time.Millisecond).Should(Equal(&Options{MinVersion: tls.VersionTLS12}))
+
+		By("Keeping the previous settings if the config is invalid")
+		config.Spec.TLSConfig = &cdiv1.TLSConfig{MinVersion: "invalid"}
+		_, err = client.CdiV1beta1().CDIConfigs().Update(context.TODO(), config, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		time.Sleep(100 * time.Millisecond)
+		cache.WaitForCacheSync(ch, watcher.informer.HasSynced)
+		Expect(watcher.GetOptions()).To(Equal(&Options{MinVersion: tls.VersionTLS12}))
+	})
+
+	It("should follow the client CA ConfigMap of the upload proxy", func() {
+		ch := make(chan struct{})
+		defer close(ch)
+		config := &cdiv1.CDIConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.ConfigName,
+			},
+		}
+		client := cdiclientfake.NewSimpleClientset(config)
+		watcher := NewWatcher(client, ch)
+		Expect(watcher.GetUploadProxyClientCAConfigMap()).To(BeEmpty())
+
+		configMapName := "upload-client-ca"
+		config.Spec.UploadProxyClientCAConfigMap = &configMapName
+		_, err := client.CdiV1beta1().CDIConfigs().Update(context.TODO(), config, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(watcher.GetUploadProxyClientCAConfigMap, 10*time.Second, 100*time.Millisecond).Should(Equal(configMapName))
+	})
+})
@@ -0,0 +1,122 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package tlsconfig
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/client/informers/externalversions"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// Watcher is the interface of configWatcher
+type Watcher interface {
+	GetOptions() *Options
+	GetUploadProxyClientCAConfigMap() string
+}
+
+type configWatcher struct {
+	// keep this around for tests
+	informer cache.SharedIndexInformer
+
+	options *Options
+	// uploadProxyClientCAConfigMap is the ConfigMap of the CA of the client certificates required by the upload proxy
+	uploadProxyClientCAConfigMap string
+	mutex                        sync.RWMutex
+}
+
+// NewWatcher creates a new configWatcher, that keeps track of the TLS settings of the CDIConfig
+func NewWatcher(client cdiclient.Interface, stopCh <-chan struct{}) Watcher {
+	informerFactory := externalversions.NewFilteredSharedInformerFactory(client,
+		common.DefaultResyncPeriod,
+		metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = "metadata.name=" + common.ConfigName
+		},
+	)
+
+	configInformer := informerFactory.Cdi().V1beta1().CDIConfigs().Informer()
+
+	cw := &configWatcher{
+		informer: configInformer,
+	}
+
+	configInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			klog.V(3).Infof("cdiConfigInformer add callback: %+v", obj)
+			cw.updateOptions(obj.(*cdiv1.CDIConfig))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			klog.V(3).Infof("cdiConfigInformer update callback: %+v", obj)
+			cw.updateOptions(obj.(*cdiv1.CDIConfig))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if config, ok := obj.(*cdiv1.CDIConfig); ok {
+				klog.Errorf("CDIConfig %s deleted", config.Name)
+			}
+		},
+	})
+
+	go informerFactory.Start(stopCh)
+
+	klog.V(3).Infoln("Waiting for cache sync")
+	cache.WaitForCacheSync(stopCh, configInformer.HasSynced)
+	klog.V(3).Infoln("Cache sync complete")
+
+	return cw
+}
+
+// GetOptions returns the current TLS options, nil if the CDIConfig does not contain TLS settings
+func (cw *configWatcher) GetOptions() *Options {
+	cw.mutex.RLock()
+	defer cw.mutex.RUnlock()
+	return cw.options
+}
+
+// GetUploadProxyClientCAConfigMap returns the name of the ConfigMap of the CA of the client certificates required by the
+// upload proxy, empty if client certificates are not required
+func (cw *configWatcher) GetUploadProxyClientCAConfigMap() string {
+	cw.mutex.RLock()
+	defer cw.mutex.RUnlock()
+	return cw.uploadProxyClientCAConfigMap
+}
+
+func (cw *configWatcher) updateOptions(config *cdiv1.CDIConfig) {
+	options, err := NewOptions(config.Spec.TLSConfig)
+	if err != nil {
+		klog.Errorf("Invalid TLS config in CDIConfig %s, keeping the previous settings: %v", config.Name, err)
+		return
+	}
+
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+	cw.options = options
+	cw.uploadProxyClientCAConfigMap = ""
+	if config.Spec.UploadProxyClientCAConfigMap != nil {
+		cw.uploadProxyClientCAConfigMap = *config.Spec.UploadProxyClientCAConfigMap
+	}
+	klog.V(1).Infof("Updated TLS options %+v", options)
+}
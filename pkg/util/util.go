@@ -15,6 +15,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	"unsafe"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
@@ -26,6 +27,9 @@ import (
 
 const (
 	blockdevFileName = "/usr/sbin/blockdev"
+
+	// blkZeroOut is the BLKZEROOUT ioctl, zeroing a range of a block device
+	blkZeroOut = 0x127f
 )
 
 // CountingReader is a reader that keeps track of how much has been read
@@ -132,6 +136,40 @@ func GetAvailableSpaceBlock(deviceName string) (int64, error) {
 	return i, nil
 }
 
+// WipeBlockDevice zeroes the whole block device, offloaded to the storage with the BLKZEROOUT ioctl when possible
+func WipeBlockDevice(deviceName string) error {
+	f, err := os.OpenFile(deviceName, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", deviceName)
+	}
+	defer f.Close()
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrapf(err, "could not get the size of %s", deviceName)
+	}
+	zeroRange := [2]uint64{0, uint64(size)}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), blkZeroOut, uintptr(unsafe.Pointer(&zeroRange)))
+	if errno == 0 {
+		return nil
+	}
+	klog.V(3).Infof("Unable to zero %s with BLKZEROOUT, writing zeroes: %v", deviceName, errno)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	zeroes := make([]byte, 1024*1024)
+	for written := int64(0); written < size; {
+		n := int64(len(zeroes))
+		if size-written < n {
+			n = size - written
+		}
+		if _, err := f.Write(zeroes[:n]); err != nil {
+			return errors.Wrapf(err, "could not zero %s", deviceName)
+		}
+		written += n
+	}
+	return f.Sync()
+}
+
 // MinQuantity calculates the minimum of two quantities.
 func MinQuantity(availableSpace, imageSize *resource.Quantity) resource.Quantity {
 	if imageSize.Cmp(*availableSpace) == 1 {
@@ -274,6 +312,23 @@ func CopyDir(source string, dest string) (err error) {
 	return
 }
 
+// HostPathAllowed returns true if the host path is a clean absolute path equal to or under one of the absolute prefixes
+func HostPathAllowed(hostPath string, prefixes []string) bool {
+	if !filepath.IsAbs(hostPath) || filepath.Clean(hostPath) != hostPath {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if !filepath.IsAbs(prefix) {
+			continue
+		}
+		prefix = filepath.Clean(prefix)
+		if hostPath == prefix || strings.HasPrefix(hostPath, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // RoundDown returns the number rounded down to the nearest multiple.
 func RoundDown(number, multiple int64) int64 {
 	return number / multiple * multiple
@@ -137,6 +137,32 @@ var _ = Describe("Copy files", func() {
 	})
 })
 
+var _ = Describe("Wipe block device", func() {
+	It("Should zero the whole device", func() {
+		device, err := ioutil.TempFile("", "device")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(device.Name())
+		data := make([]byte, 3*1024*1024+512)
+		for i := range data {
+			data[i] = 0xff
+		}
+		_, err = device.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(device.Close()).To(Succeed())
+
+		err = WipeBlockDevice(device.Name())
+		Expect(err).ToNot(HaveOccurred())
+		wiped, err := ioutil.ReadFile(device.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wiped).To(Equal(make([]byte, len(data))))
+	})
+
+	It("Should fail on a missing device", func() {
+		err := WipeBlockDevice("/invalidpath/device")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 func md5sum(filePath string) (string, error) {
 	var returnMD5String string
 
@@ -157,3 +183,18 @@ func md5sum(filePath string) (string, error) {
 
 	return returnMD5String, nil
 }
+
+var _ = Describe("Host path prefixes", func() {
+	table.DescribeTable("Should only allow the host paths under the prefixes", func(hostPath string, prefixes []string, allowed bool) {
+		Expect(HostPathAllowed(hostPath, prefixes)).To(Equal(allowed))
+	},
+		table.Entry("the prefix itself", "/mnt/usb", []string{"/mnt/usb"}, true),
+		table.Entry("a directory under the prefix", "/mnt/usb/images", []string{"/srv", "/mnt/usb/"}, true),
+		table.Entry("any directory under the root", "/etc", []string{"/"}, true),
+		table.Entry("no prefix", "/mnt/usb", nil, false),
+		table.Entry("a directory sharing the name of the prefix", "/mnt/usb2", []string{"/mnt/usb"}, false),
+		table.Entry("a directory out of the prefix", "/mnt/usb/../../etc", []string{"/mnt/usb"}, false),
+		table.Entry("a relative path", "mnt/usb", []string{"/mnt/usb"}, false),
+		table.Entry("a relative prefix", "/mnt/usb", []string{"mnt/usb"}, false),
+	)
+})
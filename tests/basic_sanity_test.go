@@ -131,6 +131,14 @@ var _ = Describe("[rfe_id:1347][crit:high][vendor:cnv-qe@redhat.com][level:compo
 			table.Entry("[test_id:5056]CDIConfigs", "cdiconfigs.cdi.kubevirt.io"),
 			table.Entry("[test_id:5057]CDIs", "cdis.cdi.kubevirt.io"),
 			table.Entry("[test_id:5056]Datavolumes", "datavolumes.cdi.kubevirt.io"),
+			table.Entry("DataExports", "dataexports.cdi.kubevirt.io"),
+			table.Entry("CloneGrants", "clonegrants.cdi.kubevirt.io"),
+			table.Entry("VolumeImportSources", "volumeimportsources.cdi.kubevirt.io"),
+			table.Entry("VolumeUploadSources", "volumeuploadsources.cdi.kubevirt.io"),
+			table.Entry("VolumeCloneSources", "volumeclonesources.cdi.kubevirt.io"),
+			table.Entry("DataImportCrons", "dataimportcrons.cdi.kubevirt.io"),
+			table.Entry("DataSources", "datasources.cdi.kubevirt.io"),
+			table.Entry("StorageProfiles", "storageprofiles.cdi.kubevirt.io"),
 		)
 	})
 })
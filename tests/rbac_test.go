@@ -182,13 +182,19 @@ var _ = Describe("Aggregated role in-action tests", func() {
 })
 
 var _ = Describe("Aggregated role definition tests", func() {
-	var adminRules = []rbacv1.PolicyRule{
+	var editRules = []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
 				"cdi.kubevirt.io",
 			},
 			Resources: []string{
 				"datavolumes",
+				"dataexports",
+				"volumeimportsources",
+				"volumeuploadsources",
+				"volumeclonesources",
+				"dataimportcrons",
+				"datasources",
 			},
 			Verbs: []string{
 				"*",
@@ -233,7 +239,17 @@ var _ = Describe("Aggregated role definition tests", func() {
 		},
 	}
 
-	var editRules = adminRules
+	var adminRules = append(editRules, rbacv1.PolicyRule{
+		APIGroups: []string{
+			"cdi.kubevirt.io",
+		},
+		Resources: []string{
+			"clonegrants",
+		},
+		Verbs: []string{
+			"*",
+		},
+	})
 
 	var viewRules = []rbacv1.PolicyRule{
 		{
@@ -242,6 +258,13 @@ var _ = Describe("Aggregated role definition tests", func() {
 			},
 			Resources: []string{
 				"datavolumes",
+				"dataexports",
+				"volumeimportsources",
+				"volumeuploadsources",
+				"volumeclonesources",
+				"dataimportcrons",
+				"datasources",
+				"clonegrants",
 			},
 			Verbs: []string{
 				"get",
@@ -266,6 +289,7 @@ var _ = Describe("Aggregated role definition tests", func() {
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"storageprofiles",
 			},
 			Verbs: []string{
 				"get",
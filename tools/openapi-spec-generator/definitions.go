@@ -65,6 +65,54 @@ func CoreAPI() []*restful.WebService {
 		Resource: "cdiconfigs",
 	}
 
+	dataExportGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "dataexports",
+	}
+
+	cloneGrantGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "clonegrants",
+	}
+
+	volumeImportSourceGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "volumeimportsources",
+	}
+
+	volumeUploadSourceGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "volumeuploadsources",
+	}
+
+	volumeCloneSourceGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "volumeclonesources",
+	}
+
+	dataImportCronGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "dataimportcrons",
+	}
+
+	dataSourceGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "datasources",
+	}
+
+	storageProfileGVR := schema.GroupVersionResource{
+		Group:    cdiv1.SchemeGroupVersion.Group,
+		Version:  cdiv1.SchemeGroupVersion.Version,
+		Resource: "storageprofiles",
+	}
+
 	ws, err := groupVersionProxyBase(cdiv1.SchemeGroupVersion)
 	if err != nil {
 		panic(err)
@@ -85,6 +133,46 @@ func CoreAPI() []*restful.WebService {
 		panic(err)
 	}
 
+	ws, err = genericResourceProxy(ws, dataExportGVR, &cdiv1.DataExport{}, "DataExport", &cdiv1.DataExportList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, cloneGrantGVR, &cdiv1.CloneGrant{}, "CloneGrant", &cdiv1.CloneGrantList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, volumeImportSourceGVR, &cdiv1.VolumeImportSource{}, "VolumeImportSource", &cdiv1.VolumeImportSourceList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, volumeUploadSourceGVR, &cdiv1.VolumeUploadSource{}, "VolumeUploadSource", &cdiv1.VolumeUploadSourceList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, volumeCloneSourceGVR, &cdiv1.VolumeCloneSource{}, "VolumeCloneSource", &cdiv1.VolumeCloneSourceList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, dataImportCronGVR, &cdiv1.DataImportCron{}, "DataImportCron", &cdiv1.DataImportCronList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, dataSourceGVR, &cdiv1.DataSource{}, "DataSource", &cdiv1.DataSourceList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = genericResourceProxy(ws, storageProfileGVR, &cdiv1.StorageProfile{}, "StorageProfile", &cdiv1.StorageProfileList{})
+	if err != nil {
+		panic(err)
+	}
+
 	ws1, err := resourceProxyAutodiscovery(dvGVR)
 	if err != nil {
 		panic(err)
@@ -45,6 +45,14 @@ func main() {
 	crds = append(crds, cdioperator.NewCdiCrd())
 	crds = append(crds, cluster.NewCdiConfigCrd())
 	crds = append(crds, cluster.NewDataVolumeCrd())
+	crds = append(crds, cluster.NewDataExportCrd())
+	crds = append(crds, cluster.NewCloneGrantCrd())
+	crds = append(crds, cluster.NewVolumeImportSourceCrd())
+	crds = append(crds, cluster.NewVolumeUploadSourceCrd())
+	crds = append(crds, cluster.NewVolumeCloneSourceCrd())
+	crds = append(crds, cluster.NewDataImportCronCrd())
+	crds = append(crds, cluster.NewDataSourceCrd())
+	crds = append(crds, cluster.NewStorageProfileCrd())
 
 	for _, crd := range crds {
 		crdPath := filepath.Join(*exportPath, crd.GetObjectMeta().GetName())